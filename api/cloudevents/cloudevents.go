@@ -0,0 +1,125 @@
+// Package cloudevents 提供接收 CloudEvents webhook 的 HTTP 端点，把入站事件投递到事件总线。
+//
+// 路由：
+//   - `POST {Path}`：接收二进制模式、结构化模式或批量模式的 CloudEvents，转换为 eventing.Event 后发布，返回 202；
+//   - `OPTIONS {Path}`：CloudEvents webhook 规范的滥用保护握手（WebHook-Request-Origin）。
+//
+// 事件转换语义见 gochen/eventing/cloudevents。入站事件来自外部系统，挂载时应配合认证中间件：
+// 事件经 ScopeInbound 处理，tenant_id/operator 取自认证上下文，外部声明的关联 ID 被丢弃，事件 ID 按 source 命名空间派生。
+package cloudevents
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/bus"
+	ce "gochen/eventing/cloudevents"
+	"gochen/eventing/registry"
+	"gochen/httpx"
+)
+
+// DefaultPath 是 webhook 端点的默认路径。
+const DefaultPath = "/cloudevents"
+
+// DefaultMaxBatchEvents 是单次请求允许的默认事件数上限。
+const DefaultMaxBatchEvents = 100
+
+// Config 定义 webhook 端点配置。
+type Config struct {
+	// Path 是路由路径；为空时使用 DefaultPath。
+	Path string
+	// Registry 可选：注册了事件类型时把 data 反序列化为强类型载荷。
+	Registry *registry.Registry
+	// AllowedOrigins 是滥用保护握手允许的来源；为空时允许任意来源。
+	AllowedOrigins []string
+	// MaxBatchEvents 是单次请求允许的事件数上限；<=0 时使用 DefaultMaxBatchEvents。
+	MaxBatchEvents int
+}
+
+// Registrar 把 CloudEvents webhook 投递到事件总线，实现 host 模块的路由注册器约定。
+//
+// ID 为入站事件的聚合 ID 类型，由 CloudEvent 的 subject 解析。
+type Registrar[ID comparable] struct {
+	eventBus bus.IEventBus
+	config   Config
+}
+
+// NewRegistrar 创建 webhook 路由注册器。
+func NewRegistrar[ID comparable](eventBus bus.IEventBus, cfg *Config) *Registrar[ID] {
+	config := Config{}
+	if cfg != nil {
+		config = *cfg
+	}
+	config.Path = strings.TrimRight(strings.TrimSpace(config.Path), "/")
+	if config.Path == "" {
+		config.Path = DefaultPath
+	}
+	if config.MaxBatchEvents <= 0 {
+		config.MaxBatchEvents = DefaultMaxBatchEvents
+	}
+	return &Registrar[ID]{eventBus: eventBus, config: config}
+}
+
+// RegisterRoutes 注册 webhook 端点。
+func (r *Registrar[ID]) RegisterRoutes(group httpx.IRouteGroup) error {
+	if group == nil {
+		return errors.NewCode(errors.InvalidInput, "route group cannot be nil")
+	}
+	if r.eventBus == nil {
+		return errors.NewCode(errors.InvalidInput, "event bus cannot be nil")
+	}
+	group.POST(r.config.Path, r.handleReceive)
+	group.OPTIONS(r.config.Path, r.handleValidate)
+	return nil
+}
+
+func (r *Registrar[ID]) handleReceive(c httpx.IContext) error {
+	body, err := c.Body()
+	if err != nil {
+		return err
+	}
+	received, err := ce.ReadHTTPBatch(c.Request().Header, body)
+	if err != nil {
+		return err
+	}
+	if len(received) > r.config.MaxBatchEvents {
+		return errors.NewCode(errors.PayloadTooLarge, "too many cloudevents in batch").
+			WithContext("events", len(received)).
+			WithContext("max_events", r.config.MaxBatchEvents)
+	}
+	ctx := c.RequestContext()
+	events := make([]eventing.IEvent, 0, len(received))
+	ids := make([]string, 0, len(received))
+	for _, in := range received {
+		evt, err := ce.ToEvent[ID](in, r.config.Registry)
+		if err != nil {
+			return err
+		}
+		ce.ScopeInbound(ctx, evt, in.Source)
+		events = append(events, evt)
+		ids = append(ids, in.ID)
+	}
+	if len(events) > 0 {
+		if err := r.eventBus.PublishEvents(ctx, events); err != nil {
+			return err
+		}
+	}
+	return c.JSON(http.StatusAccepted, httpx.JSONValue(map[string]any{"accepted": ids}))
+}
+
+func (r *Registrar[ID]) handleValidate(c httpx.IContext) error {
+	origin := strings.TrimSpace(c.Header("WebHook-Request-Origin"))
+	if origin == "" {
+		return errors.NewCode(errors.InvalidInput, "missing WebHook-Request-Origin header")
+	}
+	if len(r.config.AllowedOrigins) > 0 && !slices.Contains(r.config.AllowedOrigins, origin) {
+		return errors.NewCode(errors.Forbidden, "webhook origin not allowed").
+			WithContext("origin", origin)
+	}
+	c.SetHeader("WebHook-Allowed-Origin", origin)
+	c.SetHeader("Allow", http.MethodPost)
+	return c.Data(http.StatusOK, "", nil)
+}
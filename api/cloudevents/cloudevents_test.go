@@ -0,0 +1,160 @@
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gochen/contextx"
+	"gochen/eventing"
+	"gochen/eventing/bus"
+	ce "gochen/eventing/cloudevents"
+	"gochen/httpx/httpxtest"
+	"gochen/messaging"
+	"gochen/messaging/transport/direct"
+)
+
+func TestRegistrar_PublishesWebhookEvents(t *testing.T) {
+	ctx := context.Background()
+	transport := direct.NewSyncTransport()
+	if err := transport.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() { _ = transport.Stop(ctx) }()
+	eventBus := bus.NewEventBus(messaging.NewMessageBus(transport))
+
+	var received []*eventing.Event[int64]
+	_, err := eventBus.SubscribeEvent(ctx, "*", bus.EventHandlerFunc(func(_ context.Context, evt eventing.IEvent) error {
+		received = append(received, evt.(*eventing.Event[int64]))
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("SubscribeEvent: %v", err)
+	}
+
+	group := httpxtest.NewMockRouteGroup()
+	if err := NewRegistrar[int64](eventBus, &Config{Path: "/hooks/ce/", AllowedOrigins: []string{"broker.example"}}).RegisterRoutes(group); err != nil {
+		t.Fatalf("RegisterRoutes: %v", err)
+	}
+	receive := group.Handlers["POST /hooks/ce"]
+
+	req := httptest.NewRequest(http.MethodPost, "/hooks/ce", bytes.NewBufferString(`{"order_id":"o-1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Ce-Specversion", "1.0")
+	req.Header.Set("Ce-Id", "evt-1")
+	req.Header.Set("Ce-Source", "/orders")
+	req.Header.Set("Ce-Type", "OrderPlaced")
+	req.Header.Set("Ce-Subject", "42")
+	req.Header.Set("Ce-Aggregatetype", "Order")
+	req.Header.Set("Ce-Traceid", "t-1")
+	if w := httpxtest.Serve(t, receive, req, nil); w.Code != http.StatusAccepted {
+		t.Fatalf("binary status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/hooks/ce", bytes.NewBufferString(`[
+		{"specversion":"1.0","id":"evt-2","source":"/orders","type":"OrderShipped","subject":"42"},
+		{"specversion":"1.0","id":"evt-3","source":"/orders","type":"OrderClosed","subject":"42"}
+	]`))
+	req.Header.Set("Content-Type", "application/cloudevents-batch+json")
+	if w := httpxtest.Serve(t, receive, req, nil); w.Code != http.StatusAccepted {
+		t.Fatalf("batch status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	if len(received) != 3 {
+		t.Fatalf("received %d events", len(received))
+	}
+	first := received[0]
+	if first.ID != ce.InboundEventID("/orders", "evt-1") || first.AggregateID != 42 || first.AggregateType != "Order" {
+		t.Fatalf("unexpected event: %+v", first)
+	}
+	if id, _ := first.GetMetadata().Get(ce.MetadataSourceIDKey); id != "evt-1" {
+		t.Fatalf("cloudevent_id = %q", id)
+	}
+	if trace, _ := first.GetMetadata().Get("trace_id"); trace != "t-1" {
+		t.Fatalf("trace_id = %q", trace)
+	}
+	if received[2].GetType() != "OrderClosed" {
+		t.Fatalf("unexpected batch order: %s", received[2].GetType())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/hooks/ce", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+	if w := httpxtest.Serve(t, receive, req, nil); w.Code != http.StatusBadRequest {
+		t.Fatalf("invalid status = %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodOptions, "/hooks/ce", nil)
+	req.Header.Set("WebHook-Request-Origin", "broker.example")
+	w := httpxtest.Serve(t, group.Handlers["OPTIONS /hooks/ce"], req, nil)
+	if w.Code != http.StatusOK || w.Header().Get("WebHook-Allowed-Origin") != "broker.example" {
+		t.Fatalf("handshake status = %d, headers = %v", w.Code, w.Header())
+	}
+	req.Header.Set("WebHook-Request-Origin", "evil.example")
+	if w := httpxtest.Serve(t, group.Handlers["OPTIONS /hooks/ce"], req, nil); w.Code != http.StatusForbidden {
+		t.Fatalf("disallowed origin status = %d", w.Code)
+	}
+}
+
+// TestRegistrar_ScopesInboundEvents 验证入站事件的租户/操作人取自认证上下文、外部关联 ID 被丢弃且批量条数受限。
+func TestRegistrar_ScopesInboundEvents(t *testing.T) {
+	ctx := context.Background()
+	transport := direct.NewSyncTransport()
+	if err := transport.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() { _ = transport.Stop(ctx) }()
+	eventBus := bus.NewEventBus(messaging.NewMessageBus(transport))
+
+	var received []eventing.IEvent
+	if _, err := eventBus.SubscribeEvent(ctx, "*", bus.EventHandlerFunc(func(_ context.Context, evt eventing.IEvent) error {
+		received = append(received, evt)
+		return nil
+	})); err != nil {
+		t.Fatalf("SubscribeEvent: %v", err)
+	}
+
+	group := httpxtest.NewMockRouteGroup()
+	if err := NewRegistrar[int64](eventBus, &Config{MaxBatchEvents: 1}).RegisterRoutes(group); err != nil {
+		t.Fatalf("RegisterRoutes: %v", err)
+	}
+	receive := group.Handlers["POST "+DefaultPath]
+
+	reqCtx, err := contextx.WithTenantID(ctx, "tenant-a")
+	if err != nil {
+		t.Fatalf("WithTenantID: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, DefaultPath, bytes.NewBufferString(
+		`{"specversion":"1.0","id":"evt-1","source":"/orders","type":"OrderPlaced","tenantid":"tenant-b","operator":"admin","causationid":"internal-1"}`,
+	)).WithContext(reqCtx)
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+	if w := httpxtest.Serve(t, receive, req, nil); w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if len(received) != 1 {
+		t.Fatalf("received %d events", len(received))
+	}
+	md := received[0].GetMetadata()
+	if tenant, _ := md.Get(contextx.MetadataTenantKey); tenant != "tenant-a" {
+		t.Fatalf("tenant_id = %q", tenant)
+	}
+	if operator, ok := md.Get(contextx.MetadataOperatorKey); ok {
+		t.Fatalf("operator = %q, want dropped", operator)
+	}
+	if causation, ok := md.Get(contextx.MetadataCausationKey); ok {
+		t.Fatalf("causation_id = %q, want dropped", causation)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, DefaultPath, bytes.NewBufferString(`[
+		{"specversion":"1.0","id":"evt-2","source":"/orders","type":"OrderShipped"},
+		{"specversion":"1.0","id":"evt-3","source":"/orders","type":"OrderClosed"}
+	]`))
+	req.Header.Set("Content-Type", "application/cloudevents-batch+json")
+	if w := httpxtest.Serve(t, receive, req, nil); w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("oversized batch status = %d", w.Code)
+	}
+	if len(received) != 1 {
+		t.Fatalf("oversized batch published %d events", len(received)-1)
+	}
+}
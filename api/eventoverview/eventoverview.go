@@ -0,0 +1,206 @@
+// Package eventoverview 提供事件链路内部状态的只读概览端点，供运维看板一次拉取。
+//
+// 路由：
+//   - `GET {Path}`：汇总投影运行状态、事件存储缓存统计、Outbox 积压、DLQ 大小、Saga 状态分布与传输层队列深度。
+//
+// 各来源均可选，未配置的段不输出；单个来源采集失败时该段缺省并在 errors 中记录原因，其余段照常返回（始终 200）。
+// 健康判定请使用 api/eventstatus。端点暴露内部运行信息，挂载时应限制在内网或配合认证中间件。
+package eventoverview
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"gochen/errors"
+	"gochen/eventing/monitoring"
+	"gochen/eventing/outbox"
+	"gochen/eventing/projection"
+	"gochen/eventing/store/cached"
+	"gochen/httpx"
+	"gochen/messaging"
+	"gochen/process/saga"
+)
+
+// DefaultPath 是概览端点的默认路径。
+const DefaultPath = "/internal/eventing/overview"
+
+// 概览段名称（Overview.Errors 的键）。
+const (
+	SectionOutbox = "outbox"
+	SectionDLQ    = "dlq"
+	SectionSagas  = "sagas"
+)
+
+// IProjectionStatusSource 提供全部投影的运行状态（projection.ProjectionManager 已实现）。
+type IProjectionStatusSource interface {
+	ProjectionStatuses() map[string]*projection.ProjectionStatus
+}
+
+// IDLQCounter 提供 DLQ 当前记录数（outbox.IDLQRepository 已实现）。
+type IDLQCounter interface {
+	GetDLQCount(ctx context.Context) (int64, error)
+}
+
+// ITransportStatsSource 提供传输层运行统计（messaging.ITransport 已实现）。
+type ITransportStatsSource interface {
+	Stats() messaging.TransportStats
+}
+
+// Sources 定义概览的数据来源；为 nil/空的来源对应段不输出。
+type Sources struct {
+	Projections IProjectionStatusSource
+	// Caches 按名称（通常为聚合类型或存储名）登记缓存统计来源，如 cached.CachedEventStore。
+	Caches map[string]monitoring.ICacheStatsProvider
+	// Outbox 提供 Outbox 积压（eventing/outbox/monitoring.NewBacklogProvider）。
+	Outbox monitoring.IOutboxBacklogProvider
+	DLQ    IDLQCounter
+	// Sagas 的状态分布通过 List 全量统计，Saga 实例很多时应定期归档已结束的实例。
+	Sagas saga.ISagaStateStore
+	// Transports 按名称登记传输层统计来源。
+	Transports map[string]ITransportStatsSource
+}
+
+// Config 定义概览端点配置。
+type Config struct {
+	// Path 是路由路径；为空时使用 DefaultPath。
+	Path string
+}
+
+// DLQOverview 是 DLQ 概览。
+type DLQOverview struct {
+	Size int64 `json:"size"`
+}
+
+// SagaOverview 是 Saga 状态分布。
+type SagaOverview struct {
+	Total    int            `json:"total"`
+	ByStatus map[string]int `json:"by_status"`
+}
+
+// Overview 是概览端点的响应。
+type Overview struct {
+	Timestamp time.Time `json:"timestamp"`
+
+	// Projections 按名称排序。
+	Projections []projection.ProjectionStatus       `json:"projections,omitempty"`
+	Caches      map[string]monitoring.CacheStats    `json:"caches,omitempty"`
+	Outbox      *monitoring.OutboxBacklog           `json:"outbox,omitempty"`
+	DLQ         *DLQOverview                        `json:"dlq,omitempty"`
+	Sagas       *SagaOverview                       `json:"sagas,omitempty"`
+	Transports  map[string]messaging.TransportStats `json:"transports,omitempty"`
+
+	// Errors 记录采集失败的段（键为 Section* 常量）及原因。
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// Registrar 把事件链路概览暴露为只读端点，实现 host 模块的路由注册器约定。
+type Registrar struct {
+	sources Sources
+	config  Config
+}
+
+// NewRegistrar 创建概览端点路由注册器。
+func NewRegistrar(sources Sources, cfg *Config) *Registrar {
+	config := Config{}
+	if cfg != nil {
+		config = *cfg
+	}
+	config.Path = strings.TrimRight(strings.TrimSpace(config.Path), "/")
+	if config.Path == "" {
+		config.Path = DefaultPath
+	}
+	return &Registrar{sources: sources, config: config}
+}
+
+// RegisterRoutes 注册概览端点。
+func (r *Registrar) RegisterRoutes(group httpx.IRouteGroup) error {
+	if group == nil {
+		return errors.NewCode(errors.InvalidInput, "route group cannot be nil")
+	}
+	group.GET(r.config.Path, r.handleOverview)
+	return nil
+}
+
+// Overview 采集一次概览（不经 HTTP，便于嵌入其他端点或定时上报）。
+func (r *Registrar) Overview(ctx context.Context) Overview {
+	o := Overview{Timestamp: monitoring.Now()}
+	fail := func(section string, err error) {
+		if o.Errors == nil {
+			o.Errors = make(map[string]string)
+		}
+		o.Errors[section] = err.Error()
+	}
+
+	if r.sources.Projections != nil {
+		statuses := r.sources.Projections.ProjectionStatuses()
+		o.Projections = make([]projection.ProjectionStatus, 0, len(statuses))
+		for _, status := range statuses {
+			if status != nil {
+				o.Projections = append(o.Projections, *status)
+			}
+		}
+		sort.Slice(o.Projections, func(i, j int) bool { return o.Projections[i].Name < o.Projections[j].Name })
+	}
+
+	for name, provider := range r.sources.Caches {
+		if provider == nil {
+			continue
+		}
+		if o.Caches == nil {
+			o.Caches = make(map[string]monitoring.CacheStats, len(r.sources.Caches))
+		}
+		o.Caches[name] = provider.CacheStats()
+	}
+
+	if r.sources.Outbox != nil {
+		if backlog, err := r.sources.Outbox.OutboxBacklog(ctx); err != nil {
+			fail(SectionOutbox, err)
+		} else {
+			o.Outbox = &backlog
+		}
+	}
+
+	if r.sources.DLQ != nil {
+		if size, err := r.sources.DLQ.GetDLQCount(ctx); err != nil {
+			fail(SectionDLQ, err)
+		} else {
+			o.DLQ = &DLQOverview{Size: size}
+		}
+	}
+
+	if r.sources.Sagas != nil {
+		if states, err := r.sources.Sagas.List(ctx, ""); err != nil {
+			fail(SectionSagas, err)
+		} else {
+			o.Sagas = &SagaOverview{Total: len(states), ByStatus: make(map[string]int)}
+			for _, state := range states {
+				o.Sagas.ByStatus[string(state.Status)]++
+			}
+		}
+	}
+
+	for name, source := range r.sources.Transports {
+		if source == nil {
+			continue
+		}
+		if o.Transports == nil {
+			o.Transports = make(map[string]messaging.TransportStats, len(r.sources.Transports))
+		}
+		o.Transports[name] = source.Stats()
+	}
+	return o
+}
+
+func (r *Registrar) handleOverview(c httpx.IContext) error {
+	return c.JSON(http.StatusOK, httpx.JSONValue(r.Overview(c.RequestContext())))
+}
+
+var (
+	_ IProjectionStatusSource        = (*projection.ProjectionManager[int64])(nil)
+	_ monitoring.ICacheStatsProvider = (*cached.CachedEventStore[int64])(nil)
+	_ IDLQCounter                    = (outbox.IDLQRepository[int64])(nil)
+	_ ITransportStatsSource          = (messaging.ITransport)(nil)
+)
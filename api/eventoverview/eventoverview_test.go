@@ -0,0 +1,123 @@
+package eventoverview
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gochen/errors"
+	"gochen/eventing/monitoring"
+	"gochen/eventing/projection"
+	"gochen/httpx/httpxtest"
+	"gochen/messaging"
+	"gochen/process/saga"
+)
+
+type projectionStatuses map[string]*projection.ProjectionStatus
+
+func (p projectionStatuses) ProjectionStatuses() map[string]*projection.ProjectionStatus { return p }
+
+type cacheStats struct{}
+
+func (cacheStats) CacheStats() monitoring.CacheStats {
+	return monitoring.CacheStats{Hits: 9, Misses: 1, HitRatePercent: 90}
+}
+
+type backlog struct{}
+
+func (backlog) OutboxBacklog(context.Context) (monitoring.OutboxBacklog, error) {
+	return monitoring.OutboxBacklog{PendingCount: 3, OldestPendingAge: time.Second}, nil
+}
+
+type dlqCounter struct{ err error }
+
+func (d dlqCounter) GetDLQCount(context.Context) (int64, error) { return 4, d.err }
+
+type transportStats struct{}
+
+func (transportStats) Stats() messaging.TransportStats {
+	return messaging.TransportStats{Running: true, QueueSize: 100, QueueDepth: 7}
+}
+
+func TestRegistrar_Overview(t *testing.T) {
+	ctx := context.Background()
+	sagas := saga.NewMemorySagaStateStore()
+	for i, status := range []saga.SagaStatus{saga.SagaStatusRunning, saga.SagaStatusRunning, saga.SagaStatusFailed} {
+		if err := sagas.Save(ctx, &saga.SagaState{SagaID: string(rune('a' + i)), Status: status}); err != nil {
+			t.Fatalf("save saga: %v", err)
+		}
+	}
+
+	group := httpxtest.NewMockRouteGroup()
+	registrar := NewRegistrar(Sources{
+		Projections: projectionStatuses{
+			"orders":   {Name: "orders", Status: "running", ProcessedEvents: 10},
+			"accounts": {Name: "accounts", Status: "error", LastError: "boom"},
+		},
+		Caches:     map[string]monitoring.ICacheStatsProvider{"Order": cacheStats{}},
+		Outbox:     backlog{},
+		DLQ:        dlqCounter{},
+		Sagas:      sagas,
+		Transports: map[string]ITransportStatsSource{"commands": transportStats{}},
+	}, nil)
+	if err := registrar.RegisterRoutes(group); err != nil {
+		t.Fatalf("RegisterRoutes: %v", err)
+	}
+	h := group.Handler(t, http.MethodGet, DefaultPath)
+	w := httpxtest.Serve(t, h, httptest.NewRequest(http.MethodGet, DefaultPath, nil), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status code = %d, body = %s", w.Code, w.Body.String())
+	}
+	var overview Overview
+	if err := json.Unmarshal(w.Body.Bytes(), &overview); err != nil {
+		t.Fatalf("decode overview: %v", err)
+	}
+
+	if len(overview.Projections) != 2 || overview.Projections[0].Name != "accounts" || overview.Projections[1].ProcessedEvents != 10 {
+		t.Fatalf("unexpected projections: %+v", overview.Projections)
+	}
+	if overview.Caches["Order"].Hits != 9 {
+		t.Fatalf("unexpected caches: %+v", overview.Caches)
+	}
+	if overview.Outbox == nil || overview.Outbox.PendingCount != 3 {
+		t.Fatalf("unexpected outbox: %+v", overview.Outbox)
+	}
+	if overview.DLQ == nil || overview.DLQ.Size != 4 {
+		t.Fatalf("unexpected dlq: %+v", overview.DLQ)
+	}
+	if overview.Sagas == nil || overview.Sagas.Total != 3 || overview.Sagas.ByStatus["running"] != 2 {
+		t.Fatalf("unexpected sagas: %+v", overview.Sagas)
+	}
+	if overview.Transports["commands"].QueueDepth != 7 {
+		t.Fatalf("unexpected transports: %+v", overview.Transports)
+	}
+	if len(overview.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", overview.Errors)
+	}
+}
+
+func TestRegistrar_Overview_PartialFailure(t *testing.T) {
+	registrar := NewRegistrar(Sources{
+		Outbox: backlog{},
+		DLQ:    dlqCounter{err: errors.NewCode(errors.Database, "dlq table missing")},
+	}, &Config{Path: "/ops/overview/"})
+
+	overview := registrar.Overview(context.Background())
+	if overview.DLQ != nil || overview.Errors[SectionDLQ] == "" {
+		t.Fatalf("dlq failure should be reported in errors: %+v", overview)
+	}
+	if overview.Outbox == nil || overview.Sagas != nil || overview.Projections != nil {
+		t.Fatalf("other sections should be unaffected: %+v", overview)
+	}
+
+	group := httpxtest.NewMockRouteGroup()
+	if err := registrar.RegisterRoutes(group); err != nil {
+		t.Fatalf("RegisterRoutes: %v", err)
+	}
+	if _, ok := group.Handlers["GET /ops/overview"]; !ok {
+		t.Fatalf("custom path not registered: %v", group.Handlers)
+	}
+}
@@ -0,0 +1,76 @@
+// Package eventstatus 提供事件链路运维状态端点，基于 eventing/monitoring.StatusReporter。
+//
+// 路由：
+//   - `GET {Path}`：JSON 状态（Outbox 积压、发布错误率、投影检查点延迟与健康报告），unhealthy 时返回 503；
+//   - `GET {Path}/metrics`：同一状态的 Prometheus 文本格式 gauge。
+//
+// 端点暴露内部运行信息，挂载时应限制在内网或配合认证中间件。
+package eventstatus
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"gochen/errors"
+	"gochen/eventing/monitoring"
+	"gochen/httpx"
+)
+
+// DefaultPath 是状态端点的默认路径。
+const DefaultPath = "/internal/eventing/status"
+
+// Config 定义状态端点配置。
+type Config struct {
+	// Path 是路由路径；为空时使用 DefaultPath。
+	Path string
+}
+
+// Registrar 把 StatusReporter 暴露为运维端点，实现 host 模块的路由注册器约定。
+type Registrar struct {
+	reporter *monitoring.StatusReporter
+	config   Config
+}
+
+// NewRegistrar 创建状态端点路由注册器。
+func NewRegistrar(reporter *monitoring.StatusReporter, cfg *Config) *Registrar {
+	config := Config{}
+	if cfg != nil {
+		config = *cfg
+	}
+	config.Path = strings.TrimRight(strings.TrimSpace(config.Path), "/")
+	if config.Path == "" {
+		config.Path = DefaultPath
+	}
+	return &Registrar{reporter: reporter, config: config}
+}
+
+// RegisterRoutes 注册状态端点。
+func (r *Registrar) RegisterRoutes(group httpx.IRouteGroup) error {
+	if group == nil {
+		return errors.NewCode(errors.InvalidInput, "route group cannot be nil")
+	}
+	if r.reporter == nil {
+		return errors.NewCode(errors.InvalidInput, "status reporter cannot be nil")
+	}
+	group.GET(r.config.Path, r.handleStatus)
+	group.GET(r.config.Path+"/metrics", r.handleMetrics)
+	return nil
+}
+
+func (r *Registrar) handleStatus(c httpx.IContext) error {
+	status := r.reporter.Status(c.RequestContext())
+	code := http.StatusOK
+	if status.Health.Status == monitoring.HealthStatusUnhealthy {
+		code = http.StatusServiceUnavailable
+	}
+	return c.JSON(code, httpx.JSONValue(status))
+}
+
+func (r *Registrar) handleMetrics(c httpx.IContext) error {
+	var buf bytes.Buffer
+	if err := monitoring.WritePrometheus(&buf, r.reporter.Status(c.RequestContext())); err != nil {
+		return errors.Wrap(err, errors.Internal, "write prometheus metrics failed")
+	}
+	return c.Data(http.StatusOK, monitoring.PrometheusContentType, buf.Bytes())
+}
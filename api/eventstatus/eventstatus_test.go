@@ -0,0 +1,70 @@
+package eventstatus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gochen/eventing/monitoring"
+	"gochen/httpx/httpxtest"
+)
+
+type backlog struct{ err error }
+
+func (b backlog) OutboxBacklog(context.Context) (monitoring.OutboxBacklog, error) {
+	return monitoring.OutboxBacklog{PendingCount: 2, OldestPendingAge: time.Second}, b.err
+}
+
+func TestRegistrar_EventingStatus(t *testing.T) {
+	reporter, err := monitoring.NewStatusReporter(monitoring.NewMetrics(), backlog{}, nil, monitoring.DefaultStatusConfig())
+	if err != nil {
+		t.Fatalf("NewStatusReporter: %v", err)
+	}
+	group := httpxtest.NewMockRouteGroup()
+	if err := NewRegistrar(reporter, nil).RegisterRoutes(group); err != nil {
+		t.Fatalf("RegisterRoutes: %v", err)
+	}
+
+	w := httpxtest.Serve(t, group.Handlers["GET "+DefaultPath], httptest.NewRequest(http.MethodGet, DefaultPath, nil), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status code = %d, body = %s", w.Code, w.Body.String())
+	}
+	var status monitoring.EventingStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	if status.Outbox == nil || status.Outbox.PendingCount != 2 || status.Health.Status != monitoring.HealthStatusHealthy {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+
+	w = httpxtest.Serve(t, group.Handlers["GET "+DefaultPath+"/metrics"], httptest.NewRequest(http.MethodGet, DefaultPath, nil), nil)
+	if got := w.Header().Get("Content-Type"); got != monitoring.PrometheusContentType {
+		t.Fatalf("content type = %q", got)
+	}
+	if !strings.Contains(w.Body.String(), "gochen_outbox_pending_entries 2\n") {
+		t.Fatalf("unexpected metrics body: %s", w.Body.String())
+	}
+}
+
+func TestRegistrar_UnhealthyReturns503(t *testing.T) {
+	reporter, _ := monitoring.NewStatusReporter(monitoring.NewMetrics(), backlog{err: context.DeadlineExceeded}, nil, monitoring.DefaultStatusConfig())
+	group := httpxtest.NewMockRouteGroup()
+	if err := NewRegistrar(reporter, &Config{Path: "/ops/status/"}).RegisterRoutes(group); err != nil {
+		t.Fatalf("RegisterRoutes: %v", err)
+	}
+	h, ok := group.Handlers["GET /ops/status"]
+	if !ok {
+		t.Fatalf("custom path not registered: %v", group.Handlers)
+	}
+	if w := httpxtest.Serve(t, h, httptest.NewRequest(http.MethodGet, DefaultPath, nil), nil); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status code = %d", w.Code)
+	}
+
+	if err := NewRegistrar(nil, nil).RegisterRoutes(group); err == nil {
+		t.Fatalf("expected error for nil reporter")
+	}
+}
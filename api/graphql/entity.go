@@ -0,0 +1,501 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gochen/api/rest"
+	appcrud "gochen/app/crud"
+	"gochen/codec/idcodec"
+	"gochen/db/query"
+	"gochen/di"
+	"gochen/domain"
+	"gochen/errors"
+	"gochen/ident"
+	"gochen/messaging/command"
+	mquery "gochen/messaging/query"
+)
+
+// EntityConfig 定义实体映射配置。
+type EntityConfig struct {
+	// Name GraphQL 类型名（默认为实体 Go 类型名）。
+	Name string
+	// Single 详情查询字段名（默认为首字母小写的 Name，如 "order"）。
+	Single string
+	// Plural 列表查询字段名（默认为 Single+"s"）。
+	Plural string
+	// ReadOnly 为 true 时不生成变更字段。
+	ReadOnly bool
+	// MaxPageSize 分页查询允许的最大 size（默认 100）。
+	MaxPageSize int
+	// Authorization 可选：与 REST 路由相同的角色与权限配置；解析器经 rest.EntityOperations
+	// 执行同一套授权、写约束与软删过滤。
+	Authorization *rest.AuthorizationConfig
+	// InputFields 可选：create/update 输入允许的字段（json 名）；为空时为实体全部字段。
+	// 无论是否配置，ProtectedInputFields 中的字段都不会出现在输入类型中。
+	InputFields []string
+}
+
+// ProtectedInputFields 是由框架维护、不允许通过 GraphQL 输入写入的字段（json 名）。
+var ProtectedInputFields = []string{
+	"id",
+	"version",
+	"created_at",
+	"created_by",
+	"updated_at",
+	"updated_by",
+	"deleted_at",
+	"deleted_by",
+}
+
+// AddEntity 按服务实现的能力为实体生成查询与变更字段（能力面与 api/rest 一致）：
+//   - Get：<single>(id: ID!): Name
+//   - ListPage：<plural>(page, size, sorts, filters): NamePage!；否则 ListByQuery：<plural>(sorts, filters): [Name]
+//   - Create / Update / Delete：create<Name>(input) / update<Name>(id, input) / delete<Name>(id)
+//
+// 解析器与 REST 路由共用 rest.EntityOperations：角色、权限码、写约束与软删过滤保持一致；
+// create/update 的 input 只接受白名单字段，update 在当前实体上合并输入后保存。
+//
+// 服务一项能力都没有实现时返回 errors.InvalidInput。
+func AddEntity[T domain.IEntity[ID], ID comparable](s *Schema, svc any, cfg EntityConfig) error {
+	if s == nil {
+		return errors.NewCode(errors.InvalidInput, "graphql schema is nil")
+	}
+	if svc == nil {
+		return errors.NewCode(errors.InvalidInput, "entity service is nil")
+	}
+	entityType := indirectType(reflect.TypeFor[T]())
+	if entityType.Kind() != reflect.Struct {
+		return errors.NewCode(errors.InvalidInput, "graphql entity must be a struct").
+			WithContext("go_type", entityType.String())
+	}
+	if cfg.Name == "" {
+		cfg.Name = typeName(entityType)
+	}
+	if cfg.Single == "" {
+		cfg.Single = lowerFirst(cfg.Name)
+	}
+	if cfg.Plural == "" {
+		cfg.Plural = cfg.Single + "s"
+	}
+	if cfg.MaxPageSize <= 0 {
+		cfg.MaxPageSize = 100
+	}
+	idCodec, err := idcodec.NewDefault[ID]()
+	if err != nil {
+		return err
+	}
+	if err := s.NameType(entityType, cfg.Name); err != nil {
+		return err
+	}
+	if err := s.NameType(reflect.TypeFor[query.PagedResult[T]](), cfg.Name+"Page"); err != nil {
+		return err
+	}
+	var inputFields map[string]bool
+	if !cfg.ReadOnly {
+		if inputFields, err = entityInputFields(entityType, cfg.InputFields); err != nil {
+			return err
+		}
+		if err := s.restrictInput(entityType, inputFields); err != nil {
+			return err
+		}
+	}
+
+	routeCfg := rest.DefaultRouteConfig[ID]()
+	routeCfg.Authorization = cfg.Authorization
+	if cfg.ReadOnly {
+		routeCfg.Routing.EnableCreate = false
+		routeCfg.Routing.EnableUpdate = false
+		routeCfg.Routing.EnableDelete = false
+	}
+	ops, err := rest.NewEntityOperations[T, ID](svc, routeCfg)
+	if err != nil {
+		return err
+	}
+
+	b := &entityBinding[T, ID]{schema: s, cfg: cfg, ops: ops, inputFields: inputFields, decodeID: idCodec.Decode}
+	registered := 0
+	if _, ok := svc.(rest.IGetService[T, ID]); ok {
+		if err := b.addGet(); err != nil {
+			return err
+		}
+		registered++
+	}
+	if _, ok := svc.(rest.IPagedListService[T, ID]); ok {
+		if err := b.addListPage(); err != nil {
+			return err
+		}
+		registered++
+	} else if _, ok := svc.(rest.IListService[T, ID]); ok {
+		if err := b.addList(); err != nil {
+			return err
+		}
+		registered++
+	}
+	if !cfg.ReadOnly {
+		if _, ok := svc.(rest.ICreateService[T, ID]); ok {
+			if err := b.addCreate(); err != nil {
+				return err
+			}
+			registered++
+		}
+		_, canGet := svc.(rest.IGetService[T, ID])
+		if _, ok := svc.(rest.IUpdateService[T, ID]); ok && canGet {
+			if err := b.addUpdate(); err != nil {
+				return err
+			}
+			registered++
+		}
+		if _, ok := svc.(rest.IDeleteService[ID]); ok {
+			if err := b.addDelete(); err != nil {
+				return err
+			}
+			registered++
+		}
+	}
+	if registered == 0 {
+		return errors.NewCode(errors.InvalidInput, "entity service exposes no graphql capability").
+			WithContext("entity", cfg.Name).
+			WithContext("service", fmt.Sprintf("%T", svc))
+	}
+	return nil
+}
+
+// AddEntityFromDI 从 DI 容器解析 appcrud.IApplication[T, ID] 并调用 AddEntity。
+func AddEntityFromDI[T domain.IEntity[ID], ID comparable](s *Schema, resolver di.IResolver, cfg EntityConfig) error {
+	if resolver == nil {
+		return errors.NewCode(errors.InvalidInput, "di resolver is nil")
+	}
+	svc, err := di.Resolve[appcrud.IApplication[T, ID]](resolver)
+	if err != nil {
+		return err
+	}
+	return AddEntity[T, ID](s, svc, cfg)
+}
+
+type entityBinding[T domain.IEntity[ID], ID comparable] struct {
+	schema      *Schema
+	cfg         EntityConfig
+	ops         *rest.EntityOperations[T, ID]
+	inputFields map[string]bool
+	decodeID    func(raw any) (ID, error)
+}
+
+var (
+	sortsType   = reflect.TypeFor[[]query.Sort]()
+	filtersType = reflect.TypeFor[query.QueryFilters]()
+	intType     = reflect.TypeFor[int]()
+)
+
+func (b *entityBinding[T, ID]) idArg() Argument {
+	return Argument{Name: "id", TypeName: scalarID, Required: true}
+}
+
+func (b *entityBinding[T, ID]) id(args map[string]any) (ID, error) {
+	id, err := b.decodeID(args["id"])
+	if err != nil {
+		return id, errors.Wrap(err, errors.InvalidInput, "invalid id")
+	}
+	return id, nil
+}
+
+// decodeInput 把 input 参数解码到 target；出现白名单以外的字段时返回 InvalidInput。
+func (b *entityBinding[T, ID]) decodeInput(args map[string]any, target *T) error {
+	input, ok := args["input"].(map[string]any)
+	if !ok {
+		return errors.NewCode(errors.InvalidInput, "input must be an object").
+			WithContext("entity", b.cfg.Name)
+	}
+	for key := range input {
+		if !b.inputFields[key] {
+			return errors.NewCode(errors.InvalidInput, "field is not allowed in input").
+				WithContext("entity", b.cfg.Name).
+				WithContext("field", key)
+		}
+	}
+	return Decode(input, target)
+}
+
+func (b *entityBinding[T, ID]) addGet() error {
+	return b.schema.AddQuery(Field{
+		Name:        b.cfg.Single,
+		Description: "按 ID 查询 " + b.cfg.Name,
+		Args:        []Argument{b.idArg()},
+		Type:        reflect.PointerTo(indirectType(reflect.TypeFor[T]())),
+		Resolve: func(ctx context.Context, args map[string]any) (any, error) {
+			id, err := b.id(args)
+			if err != nil {
+				return nil, err
+			}
+			return b.ops.Get(ctx, id)
+		},
+	})
+}
+
+func (b *entityBinding[T, ID]) addListPage() error {
+	return b.schema.AddQuery(Field{
+		Name:        b.cfg.Plural,
+		Description: "分页查询 " + b.cfg.Name,
+		Args: []Argument{
+			{Name: "page", Type: intType},
+			{Name: "size", Type: intType},
+			{Name: "sorts", Type: sortsType},
+			{Name: "filters", Type: filtersType},
+		},
+		Type: reflect.TypeFor[*query.PagedResult[T]](),
+		Resolve: func(ctx context.Context, args map[string]any) (any, error) {
+			request := &query.PageRequest{}
+			if err := Decode(args, request); err != nil {
+				return nil, err
+			}
+			if err := request.Validate(b.cfg.MaxPageSize); err != nil {
+				return nil, err
+			}
+			return b.ops.ListPage(ctx, request)
+		},
+	})
+}
+
+func (b *entityBinding[T, ID]) addList() error {
+	return b.schema.AddQuery(Field{
+		Name:        b.cfg.Plural,
+		Description: "查询 " + b.cfg.Name + " 列表",
+		Args: []Argument{
+			{Name: "sorts", Type: sortsType},
+			{Name: "filters", Type: filtersType},
+		},
+		Type: reflect.TypeFor[[]T](),
+		Resolve: func(ctx context.Context, args map[string]any) (any, error) {
+			request := &query.QueryRequest{}
+			if err := Decode(args, request); err != nil {
+				return nil, err
+			}
+			return b.ops.ListByQuery(ctx, request)
+		},
+	})
+}
+
+func (b *entityBinding[T, ID]) addCreate() error {
+	return b.schema.AddMutation(Field{
+		Name:        "create" + b.cfg.Name,
+		Description: "创建 " + b.cfg.Name,
+		Args:        []Argument{{Name: "input", Type: reflect.TypeFor[T](), Required: true}},
+		Type:        reflect.TypeFor[T](),
+		Resolve: func(ctx context.Context, args map[string]any) (any, error) {
+			entity := newEntity[T]()
+			if err := b.decodeInput(args, &entity); err != nil {
+				return nil, err
+			}
+			if err := b.ops.Create(ctx, entity); err != nil {
+				return nil, err
+			}
+			return entity, nil
+		},
+	})
+}
+
+func (b *entityBinding[T, ID]) addUpdate() error {
+	return b.schema.AddMutation(Field{
+		Name:        "update" + b.cfg.Name,
+		Description: "更新 " + b.cfg.Name,
+		Args:        []Argument{b.idArg(), {Name: "input", Type: reflect.TypeFor[T](), Required: true}},
+		Type:        reflect.TypeFor[T](),
+		Resolve: func(ctx context.Context, args map[string]any) (any, error) {
+			id, err := b.id(args)
+			if err != nil {
+				return nil, err
+			}
+			// 与 REST PUT 一致：在当前实体上合并输入，ID、版本与审计字段保持服务端的值。
+			return b.ops.Update(ctx, id, func(entity *T) error {
+				return b.decodeInput(args, entity)
+			})
+		},
+	})
+}
+
+func (b *entityBinding[T, ID]) addDelete() error {
+	return b.schema.AddMutation(Field{
+		Name:        "delete" + b.cfg.Name,
+		Description: "删除 " + b.cfg.Name,
+		Args:        []Argument{b.idArg()},
+		Type:        reflect.TypeFor[bool](),
+		Resolve: func(ctx context.Context, args map[string]any) (any, error) {
+			id, err := b.id(args)
+			if err != nil {
+				return nil, err
+			}
+			if err := b.ops.Delete(ctx, id); err != nil {
+				return nil, err
+			}
+			return true, nil
+		},
+	})
+}
+
+// entityInputFields 计算实体输入白名单：explicit 为空时取实体全部字段，并始终排除 ProtectedInputFields。
+func entityInputFields(entityType reflect.Type, explicit []string) (map[string]bool, error) {
+	known := make(map[string]bool)
+	for _, f := range reflect.VisibleFields(entityType) {
+		if !f.IsExported() || (f.Anonymous && f.Tag.Get("json") == "" && indirectType(f.Type).Kind() == reflect.Struct) {
+			continue
+		}
+		if name := jsonName(f); name != "-" && nameRE.MatchString(name) {
+			known[name] = true
+		}
+	}
+	allowed := known
+	if len(explicit) > 0 {
+		allowed = make(map[string]bool, len(explicit))
+		for _, name := range explicit {
+			if !known[name] {
+				return nil, errors.NewCode(errors.InvalidInput, "graphql input field does not exist on entity").
+					WithContext("go_type", entityType.String()).
+					WithContext("field", name)
+			}
+			allowed[name] = true
+		}
+	}
+	for _, name := range ProtectedInputFields {
+		delete(allowed, name)
+	}
+	if len(allowed) == 0 {
+		return nil, errors.NewCode(errors.InvalidInput, "graphql entity has no writable input fields").
+			WithContext("go_type", entityType.String())
+	}
+	return allowed, nil
+}
+
+// AddQuery 把查询总线上的查询类型 Q 映射为 Query 根字段：Q 的导出字段即参数，结果为 R。
+func AddQuery[Q any, R any](s *Schema, name string, dispatcher mquery.IQueryDispatcher) error {
+	if s == nil {
+		return errors.NewCode(errors.InvalidInput, "graphql schema is nil")
+	}
+	if dispatcher == nil {
+		return errors.NewCode(errors.InvalidInput, "query dispatcher is nil")
+	}
+	args, err := structArguments(reflect.TypeFor[Q]())
+	if err != nil {
+		return err
+	}
+	return s.AddQuery(Field{
+		Name: name,
+		Args: args,
+		Type: reflect.TypeFor[R](),
+		Resolve: func(ctx context.Context, args map[string]any) (any, error) {
+			var q Q
+			if err := Decode(args, &q); err != nil {
+				return nil, err
+			}
+			return mquery.Ask[R](ctx, dispatcher, q)
+		},
+	})
+}
+
+// CommandConfig 定义命令变更映射配置。
+type CommandConfig[P any] struct {
+	// Name 变更字段名。
+	Name string
+	// CommandType 命令类型。
+	CommandType string
+	// AggregateType 可选：聚合类型。
+	AggregateType string
+	// AggregateID 可选：从载荷中取聚合 ID。
+	AggregateID func(payload P) string
+}
+
+// AddCommand 把命令映射为 Mutation 根字段：<name>(input: PInput!): ID!，返回命令 ID。
+//
+// 命令通过 executor 同步执行，处理结果（含错误）即变更结果。
+func AddCommand[P any](s *Schema, executor command.ICommandExecutor, cfg CommandConfig[P]) error {
+	if s == nil {
+		return errors.NewCode(errors.InvalidInput, "graphql schema is nil")
+	}
+	if executor == nil {
+		return errors.NewCode(errors.InvalidInput, "command executor is nil")
+	}
+	if cfg.CommandType == "" {
+		return errors.NewCode(errors.InvalidInput, "command type is required")
+	}
+	ids := ident.DefaultStringGenerator()
+	return s.AddMutation(Field{
+		Name:        cfg.Name,
+		Description: "执行命令 " + cfg.CommandType,
+		Args:        []Argument{{Name: "input", Type: reflect.TypeFor[P](), Required: true}},
+		Type:        reflect.TypeFor[string](),
+		Resolve: func(ctx context.Context, args map[string]any) (any, error) {
+			var payload P
+			if err := Decode(args["input"], &payload); err != nil {
+				return nil, err
+			}
+			id, err := ids.Next()
+			if err != nil {
+				return nil, errors.Wrap(err, errors.Internal, "generate command id")
+			}
+			aggregateID := ""
+			if cfg.AggregateID != nil {
+				aggregateID = cfg.AggregateID(payload)
+			}
+			cmd := command.NewCommand(id, cfg.CommandType, aggregateID, cfg.AggregateType, payload)
+			if err := executor.Execute(ctx, cmd); err != nil {
+				return nil, err
+			}
+			return id, nil
+		},
+	})
+}
+
+// structArguments 把结构体的导出字段转换为参数（json tag 决定参数名，均为可选）。
+func structArguments(t reflect.Type) ([]Argument, error) {
+	t = indirectType(t)
+	if t.Kind() != reflect.Struct {
+		return nil, errors.NewCode(errors.InvalidInput, "graphql query must be a struct").
+			WithContext("go_type", t.String())
+	}
+	var args []Argument
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		name := jsonName(f)
+		if name == "-" || !nameRE.MatchString(name) {
+			continue
+		}
+		args = append(args, Argument{Name: name, Type: f.Type})
+	}
+	return args, nil
+}
+
+// Decode 把参数值（JSON 兼容值）解码到 target，通常用于自定义 ResolveFunc。
+func Decode(value any, target any) error {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrap(err, errors.InvalidInput, "invalid graphql argument")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(target); err != nil {
+		return errors.Wrap(err, errors.InvalidInput, "invalid graphql argument")
+	}
+	return nil
+}
+
+// newEntity 创建 T 的零值；T 为指针类型时分配底层结构体。
+func newEntity[T any]() T {
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Pointer {
+		return reflect.New(t.Elem()).Interface().(T)
+	}
+	var zero T
+	return zero
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,525 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+// Request 是 GraphQL 请求（POST JSON body 或 GET 查询参数）。
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Response 是 GraphQL 响应；请求在执行前失败（语法/校验错误）时不含 data。
+type Response struct {
+	Data   any      `json:"data,omitempty"`
+	Errors []*Error `json:"errors,omitempty"`
+}
+
+// Error 是 GraphQL 错误；extensions.code 为 errors.ErrorCode。
+type Error struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// errorFormatter 把错误转换为对外的 code 与 message。
+type errorFormatter func(err error) (code, message string)
+
+// defaultErrorFormatter 复用 HTTP 错误编码语义：5xx 消息做安全兜底。
+func defaultErrorFormatter(err error) (string, string) {
+	_, payload := httpx.EncodeErrorResponse(nil, err)
+	return payload.Code, payload.Message
+}
+
+// Execute 执行请求；解析、校验与字段错误都记录在 Response.Errors 中。
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	return s.execute(ctx, req, defaultErrorFormatter, false)
+}
+
+// execute 执行请求；queryOnly 为 true 时拒绝 mutation（GET 请求）。
+func (s *Schema) execute(ctx context.Context, req Request, format errorFormatter, queryOnly bool) *Response {
+	e := &execution{schema: s, format: format, limits: s.currentLimits()}
+	if ctx == nil {
+		e.addError(errors.NewCode(errors.InvalidInput, "ctx is nil"), nil)
+		return e.response(nil)
+	}
+	doc, err := parseDocument(req.Query, e.limits)
+	if err != nil {
+		e.addError(err, nil)
+		return e.response(nil)
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		e.addError(err, nil)
+		return e.response(nil)
+	}
+	if queryOnly && op.kind == "mutation" {
+		e.addError(errors.NewCode(errors.InvalidInput, "mutations are not allowed over GET"), nil)
+		return e.response(nil)
+	}
+	e.doc = doc
+	if e.variables, err = coerceVariables(op, req.Variables); err != nil {
+		e.addError(err, nil)
+		return e.response(nil)
+	}
+
+	rootName := "Query"
+	if op.kind == "mutation" {
+		rootName = "Mutation"
+	}
+	fields, err := e.collect(op.selections, rootName)
+	if err != nil {
+		e.addError(err, nil)
+		return e.response(nil)
+	}
+	roots := make([]*rootField, len(fields))
+	for i, cf := range fields {
+		if cf.name() == "__typename" {
+			continue
+		}
+		root, ok := s.lookupRoot(op.kind, cf.name())
+		if !ok {
+			e.addError(errors.NewCode(errors.InvalidInput, fmt.Sprintf("cannot query field %q on type %q", cf.name(), rootName)), nil)
+			return e.response(nil)
+		}
+		if err := e.validateArgs(root, cf.first()); err != nil {
+			e.addError(err, nil)
+			return e.response(nil)
+		}
+		if err := e.validateSelections(root.typ, cf, 1); err != nil {
+			e.addError(err, nil)
+			return e.response(nil)
+		}
+		roots[i] = root
+	}
+
+	// 根字段按文档顺序串行执行（mutation 语义要求串行，query 同样串行以保持简单可预期）。
+	data := &orderedMap{}
+	for i, cf := range fields {
+		if cf.name() == "__typename" {
+			data.set(cf.key, rootName)
+			continue
+		}
+		value, ok := e.resolveRoot(ctx, roots[i], cf)
+		if !ok {
+			return e.response(nil)
+		}
+		data.set(cf.key, value)
+	}
+	return e.response(data)
+}
+
+func selectOperation(doc *document, name string) (*operation, error) {
+	if name == "" {
+		if len(doc.operations) != 1 {
+			return nil, errors.NewCode(errors.InvalidInput, "operationName is required when document has multiple operations")
+		}
+		return doc.operations[0], nil
+	}
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, errors.NewCode(errors.InvalidInput, "unknown operation").WithContext("operation", name)
+}
+
+func coerceVariables(op *operation, provided map[string]any) (map[string]any, error) {
+	values := make(map[string]any, len(op.variables))
+	for _, def := range op.variables {
+		value, ok := provided[def.name]
+		if !ok && def.hasDefault {
+			value, ok = def.defaultValue, true
+		}
+		if def.nonNull && (!ok || value == nil) {
+			return nil, errors.NewCode(errors.InvalidInput, fmt.Sprintf("variable $%s is required", def.name))
+		}
+		if ok {
+			values[def.name] = value
+		}
+	}
+	return values, nil
+}
+
+type execution struct {
+	schema    *Schema
+	doc       *document
+	variables map[string]any
+	format    errorFormatter
+	limits    Limits
+	errors    []*Error
+}
+
+func (e *execution) response(data *orderedMap) *Response {
+	resp := &Response{Errors: e.errors}
+	if data != nil {
+		resp.Data = data
+	}
+	return resp
+}
+
+func (e *execution) addError(err error, path []any) {
+	code, message := e.format(err)
+	e.errors = append(e.errors, &Error{
+		Message:    message,
+		Path:       append([]any(nil), path...),
+		Extensions: map[string]any{"code": code},
+	})
+}
+
+// collectedField 是同一响应键下合并的字段选择。
+type collectedField struct {
+	key    string
+	fields []*selection
+}
+
+func (cf *collectedField) name() string      { return cf.fields[0].name }
+func (cf *collectedField) first() *selection { return cf.fields[0] }
+func (cf *collectedField) subSelections() []*selection {
+	var subs []*selection
+	for _, f := range cf.fields {
+		subs = append(subs, f.selections...)
+	}
+	return subs
+}
+
+// collect 展开 fragment 并按响应键合并字段，保持文档顺序。
+func (e *execution) collect(selections []*selection, typeName string) ([]*collectedField, error) {
+	var result []*collectedField
+	index := make(map[string]*collectedField)
+	visited := make(map[string]bool)
+	var walk func([]*selection) error
+	walk = func(selections []*selection) error {
+		for _, sel := range selections {
+			include, err := e.shouldInclude(sel.directives)
+			if err != nil {
+				return err
+			}
+			if !include {
+				continue
+			}
+			switch {
+			case sel.spread != "":
+				if visited[sel.spread] {
+					continue
+				}
+				visited[sel.spread] = true
+				frag, ok := e.doc.fragments[sel.spread]
+				if !ok {
+					return errors.NewCode(errors.InvalidInput, fmt.Sprintf("unknown fragment %q", sel.spread))
+				}
+				if frag.typeCondition != typeName {
+					return errors.NewCode(errors.InvalidInput, fmt.Sprintf("fragment %q cannot be spread on type %q", frag.name, typeName))
+				}
+				if err := walk(frag.selections); err != nil {
+					return err
+				}
+			case sel.inline:
+				if sel.typeCondition != "" && sel.typeCondition != typeName {
+					return errors.NewCode(errors.InvalidInput, fmt.Sprintf("inline fragment on %q cannot be spread on type %q", sel.typeCondition, typeName))
+				}
+				if err := walk(sel.selections); err != nil {
+					return err
+				}
+			default:
+				key := sel.responseKey()
+				if existing, ok := index[key]; ok {
+					if existing.name() != sel.name {
+						return errors.NewCode(errors.InvalidInput, fmt.Sprintf("fields %q conflict: %s and %s are different fields", key, existing.name(), sel.name))
+					}
+					existing.fields = append(existing.fields, sel)
+					continue
+				}
+				cf := &collectedField{key: key, fields: []*selection{sel}}
+				index[key] = cf
+				result = append(result, cf)
+			}
+		}
+		return nil
+	}
+	if err := walk(selections); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (e *execution) shouldInclude(directives []directive) (bool, error) {
+	for _, d := range directives {
+		if d.name != "skip" && d.name != "include" {
+			return false, errors.NewCode(errors.InvalidInput, fmt.Sprintf("unknown directive @%s", d.name))
+		}
+		value, err := e.resolveValue(d.args["if"])
+		if err != nil {
+			return false, err
+		}
+		cond, ok := value.(bool)
+		if !ok {
+			return false, errors.NewCode(errors.InvalidInput, fmt.Sprintf("directive @%s requires boolean argument \"if\"", d.name))
+		}
+		if (d.name == "skip" && cond) || (d.name == "include" && !cond) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// resolveValue 替换变量引用并把枚举字面量转换为字符串。
+func (e *execution) resolveValue(value any) (any, error) {
+	switch v := value.(type) {
+	case variableRef:
+		return e.variables[string(v)], nil
+	case enumValue:
+		return string(v), nil
+	case []any:
+		list := make([]any, len(v))
+		for i, item := range v {
+			resolved, err := e.resolveValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = resolved
+		}
+		return list, nil
+	case map[string]any:
+		object := make(map[string]any, len(v))
+		for key, item := range v {
+			resolved, err := e.resolveValue(item)
+			if err != nil {
+				return nil, err
+			}
+			object[key] = resolved
+		}
+		return object, nil
+	}
+	return value, nil
+}
+
+func (e *execution) validateArgs(root *rootField, sel *selection) error {
+	for name := range sel.args {
+		known := false
+		for _, arg := range root.args {
+			if arg.name == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return errors.NewCode(errors.InvalidInput, fmt.Sprintf("unknown argument %q on field %q", name, root.Name))
+		}
+	}
+	for _, arg := range root.args {
+		if !arg.required {
+			continue
+		}
+		value, err := e.resolveValue(sel.args[arg.name])
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			return errors.NewCode(errors.InvalidInput, fmt.Sprintf("argument %q of field %q is required", arg.name, root.Name))
+		}
+	}
+	return nil
+}
+
+// validateSelections 按静态类型校验子字段选择：对象必须选择子字段，标量不能选择子字段。
+//
+// depth 为 fragment 展开后的字段深度，超过 MaxDepth 时拒绝（自引用类型可借 fragment 绕过文档的字面深度）。
+func (e *execution) validateSelections(typ *typeRef, cf *collectedField, depth int) error {
+	if depth > e.limits.MaxDepth {
+		return depthExceeded(e.limits.MaxDepth)
+	}
+	for typ.elem != nil {
+		typ = typ.elem
+	}
+	subs := cf.subSelections()
+	if typ.object == nil {
+		if len(subs) > 0 {
+			return errors.NewCode(errors.InvalidInput, fmt.Sprintf("field %q of type %q must not have a selection of subfields", cf.name(), typ.String()))
+		}
+		return nil
+	}
+	if len(subs) == 0 {
+		return errors.NewCode(errors.InvalidInput, fmt.Sprintf("field %q of type %q must have a selection of subfields", cf.name(), typ.name))
+	}
+	children, err := e.collect(subs, typ.object.name)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if len(child.first().args) > 0 {
+			return errors.NewCode(errors.InvalidInput, fmt.Sprintf("field %q on type %q does not accept arguments", child.name(), typ.object.name))
+		}
+		if child.name() == "__typename" {
+			continue
+		}
+		field, ok := typ.object.byName[child.name()]
+		if !ok {
+			return errors.NewCode(errors.InvalidInput, fmt.Sprintf("cannot query field %q on type %q", child.name(), typ.object.name))
+		}
+		if err := e.validateSelections(field.typ, child, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveRoot 执行根字段；返回 false 表示非空字段为 null，整个 data 置为 null。
+func (e *execution) resolveRoot(ctx context.Context, root *rootField, cf *collectedField) (any, bool) {
+	path := []any{cf.key}
+	args := make(map[string]any, len(cf.first().args))
+	for name, raw := range cf.first().args {
+		value, err := e.resolveValue(raw)
+		if err != nil {
+			e.addError(err, path)
+			return nil, !root.typ.nonNull
+		}
+		args[name] = value
+	}
+
+	result, err := invokeResolver(ctx, root, args)
+	if err != nil {
+		e.addError(err, path)
+		return nil, !root.typ.nonNull
+	}
+	return e.complete(root.typ, reflect.ValueOf(result), cf, path)
+}
+
+func invokeResolver(ctx context.Context, root *rootField, args map[string]any) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = errors.NewCode(errors.Internal, "graphql resolver panicked").
+				WithContext("field", root.Name).
+				WithContext("panic", fmt.Sprint(r))
+		}
+	}()
+	return root.Resolve(ctx, args)
+}
+
+// complete 把 Go 值按静态类型投影为响应值；返回 false 表示非空位置出现 null，需要向上传播。
+func (e *execution) complete(typ *typeRef, value reflect.Value, cf *collectedField, path []any) (any, bool) {
+	for value.IsValid() && (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			value = reflect.Value{}
+			break
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() || (typ.elem != nil && value.Kind() == reflect.Slice && value.IsNil()) {
+		if typ.nonNull {
+			e.addError(errors.NewCode(errors.Internal, "cannot return null for non-nullable field"), path)
+			return nil, false
+		}
+		return nil, true
+	}
+
+	switch {
+	case typ.elem != nil:
+		if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+			return e.unexpectedType(typ, value, path)
+		}
+		list := make([]any, value.Len())
+		for i := range list {
+			item, ok := e.complete(typ.elem, value.Index(i), cf, append(path, i))
+			if !ok {
+				return nil, !typ.nonNull
+			}
+			list[i] = item
+		}
+		return list, true
+	case typ.object != nil:
+		if value.Type() != typ.object.goType {
+			return e.unexpectedType(typ, value, path)
+		}
+		return e.completeObject(typ, value, cf, path)
+	case typ.name == scalarTime:
+		t, ok := value.Interface().(time.Time)
+		if !ok {
+			return e.unexpectedType(typ, value, path)
+		}
+		return t.Format(time.RFC3339Nano), true
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8 && typ.name == scalarString:
+		return string(value.Bytes()), true
+	}
+	return value.Interface(), true
+}
+
+func (e *execution) completeObject(typ *typeRef, value reflect.Value, cf *collectedField, path []any) (any, bool) {
+	children, err := e.collect(cf.subSelections(), typ.object.name)
+	if err != nil {
+		e.addError(err, path)
+		return nil, !typ.nonNull
+	}
+	object := &orderedMap{}
+	for _, child := range children {
+		if child.name() == "__typename" {
+			object.set(child.key, typ.object.name)
+			continue
+		}
+		field := typ.object.byName[child.name()]
+		fieldValue, err := value.FieldByIndexErr(field.index)
+		if err != nil {
+			// 嵌入的 nil 指针：按 null 处理。
+			fieldValue = reflect.Value{}
+		}
+		completed, ok := e.complete(field.typ, fieldValue, child, append(append([]any(nil), path...), child.key))
+		if !ok {
+			return nil, !typ.nonNull
+		}
+		object.set(child.key, completed)
+	}
+	return object, true
+}
+
+func (e *execution) unexpectedType(typ *typeRef, value reflect.Value, path []any) (any, bool) {
+	e.addError(errors.NewCode(errors.Internal, "graphql resolver returned unexpected type").
+		WithContext("expected", typ.String()).
+		WithContext("actual", value.Type().String()), path)
+	return nil, !typ.nonNull
+}
+
+// orderedMap 按插入顺序序列化为 JSON 对象，保证响应字段顺序与查询一致。
+type orderedMap struct {
+	keys   []string
+	values []any
+}
+
+func (m *orderedMap) set(key string, value any) {
+	m.keys = append(m.keys, key)
+	m.values = append(m.values, value)
+}
+
+// MarshalJSON 实现 json.Marshaler。
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		encodedValue, err := json.Marshal(m.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encodedValue)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,586 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"gochen/api/rest"
+	appcrud "gochen/app/crud"
+	"gochen/auth"
+	"gochen/db/query"
+	"gochen/di"
+	dibasic "gochen/di/basic"
+	"gochen/errors"
+	"gochen/httpx/httpxtest"
+	"gochen/messaging/command"
+	mquery "gochen/messaging/query"
+)
+
+type customer struct {
+	Name string `json:"name"`
+}
+
+type order struct {
+	ID        int64     `json:"id"`
+	Title     string    `json:"title"`
+	Amount    float64   `json:"amount"`
+	Tags      []string  `json:"tags"`
+	Customer  *customer `json:"customer"`
+	CreatedAt time.Time `json:"created_at"`
+	internal  string
+}
+
+func (o *order) GetID() int64       { return o.ID }
+func (o *order) GetVersion() uint64 { return 0 }
+func (o *order) SetID(id int64)     { o.ID = id }
+
+// orderService 实现 api/rest 的 Get/ListPage/Create/Update/Delete 能力。
+type orderService struct {
+	orders   map[int64]*order
+	lastPage *query.PageRequest
+}
+
+func newOrderService() *orderService {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &orderService{orders: map[int64]*order{
+		1: {ID: 1, Title: "first", Amount: 9.5, Tags: []string{"a"}, Customer: &customer{Name: "alice"}, CreatedAt: created},
+		2: {ID: 2, Title: "second", CreatedAt: created},
+	}}
+}
+
+func (s *orderService) Get(_ context.Context, id int64) (*order, error) {
+	o, ok := s.orders[id]
+	if !ok {
+		return nil, errors.NewCode(errors.NotFound, "order not found")
+	}
+	return o, nil
+}
+
+func (s *orderService) ListPage(_ context.Context, request *query.PageRequest) (*query.PagedResult[*order], error) {
+	s.lastPage = request
+	return &query.PagedResult[*order]{
+		Data:  []*order{s.orders[1], s.orders[2]},
+		Total: 2,
+		Page:  request.Page,
+		Size:  request.Size,
+	}, nil
+}
+
+func (s *orderService) Create(_ context.Context, o *order) error {
+	o.ID = int64(len(s.orders) + 1)
+	s.orders[o.ID] = o
+	return nil
+}
+
+func (s *orderService) Update(_ context.Context, o *order) error {
+	if _, ok := s.orders[o.ID]; !ok {
+		return errors.NewCode(errors.NotFound, "order not found")
+	}
+	s.orders[o.ID] = o
+	return nil
+}
+
+func (s *orderService) Delete(_ context.Context, id int64) error {
+	delete(s.orders, id)
+	return nil
+}
+
+func newOrderSchema(t *testing.T) (*Schema, *orderService) {
+	t.Helper()
+	svc := newOrderService()
+	schema := NewSchema()
+	if err := AddEntity[*order, int64](schema, svc, EntityConfig{Name: "Order"}); err != nil {
+		t.Fatalf("AddEntity returned error: %v", err)
+	}
+	return schema, svc
+}
+
+func execute(t *testing.T, schema *Schema, query string, variables map[string]any) map[string]any {
+	t.Helper()
+	resp := schema.Execute(context.Background(), Request{Query: query, Variables: variables})
+	payload, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("marshal response: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	return decoded
+}
+
+func executeCtx(t *testing.T, schema *Schema, ctx context.Context, query string) map[string]any {
+	t.Helper()
+	payload, err := json.Marshal(schema.Execute(ctx, Request{Query: query}))
+	if err != nil {
+		t.Fatalf("marshal response: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	return decoded
+}
+
+func executeCode(t *testing.T, schema *Schema, ctx context.Context, query string) string {
+	t.Helper()
+	return errorCode(t, executeCtx(t, schema, ctx, query))
+}
+
+func errorCode(t *testing.T, resp map[string]any) string {
+	t.Helper()
+	errs, _ := resp["errors"].([]any)
+	if len(errs) == 0 {
+		t.Fatalf("expected errors, got %v", resp)
+	}
+	ext, _ := errs[0].(map[string]any)["extensions"].(map[string]any)
+	code, _ := ext["code"].(string)
+	return code
+}
+
+func TestParseDocument_SyntaxError(t *testing.T) {
+	if _, err := parseDocument(`{ order(id: 1) { title }`, Limits{}); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected InvalidInput, got %v", err)
+	}
+	if _, err := parseDocument(`subscription { orders }`, Limits{}); err == nil {
+		t.Fatalf("expected subscription to be rejected")
+	}
+}
+
+func TestExecute_EntityGetWithAliasFragmentAndVariables(t *testing.T) {
+	schema, _ := newOrderSchema(t)
+	resp := execute(t, schema, `
+		query Get($id: ID!, $withTags: Boolean = false) {
+			first: order(id: $id) { ...orderFields customer { name } tags @include(if: $withTags) }
+			missing: order(id: 99) { id }
+		}
+		fragment orderFields on Order { id title created_at __typename }
+	`, map[string]any{"id": "1"})
+
+	data := resp["data"].(map[string]any)
+	first := data["first"].(map[string]any)
+	if first["title"] != "first" || first["id"] != float64(1) || first["__typename"] != "Order" {
+		t.Fatalf("unexpected order: %v", first)
+	}
+	if first["created_at"] != "2026-01-02T03:04:05Z" {
+		t.Fatalf("unexpected created_at: %v", first["created_at"])
+	}
+	if first["customer"].(map[string]any)["name"] != "alice" {
+		t.Fatalf("unexpected customer: %v", first["customer"])
+	}
+	if _, ok := first["tags"]; ok {
+		t.Fatalf("expected tags to be skipped: %v", first)
+	}
+	if data["missing"] != nil {
+		t.Fatalf("expected missing order to be null, got %v", data["missing"])
+	}
+	if code := errorCode(t, resp); code != string(errors.NotFound) {
+		t.Fatalf("expected NOT_FOUND error, got %s", code)
+	}
+	path := resp["errors"].([]any)[0].(map[string]any)["path"].([]any)
+	if len(path) != 1 || path[0] != "missing" {
+		t.Fatalf("unexpected error path: %v", path)
+	}
+}
+
+func TestExecute_ResponseKeepsSelectionOrder(t *testing.T) {
+	schema, _ := newOrderSchema(t)
+	resp := schema.Execute(context.Background(), Request{Query: `{ order(id: 1) { title id } }`})
+	payload, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if got := string(payload); got != `{"data":{"order":{"title":"first","id":1}}}` {
+		t.Fatalf("unexpected payload: %s", got)
+	}
+}
+
+func TestExecute_ValidationErrors(t *testing.T) {
+	schema, _ := newOrderSchema(t)
+	cases := map[string]string{
+		"unknown field":     `{ order(id: 1) { nope } }`,
+		"unknown root":      `{ nope }`,
+		"missing argument":  `{ order { id } }`,
+		"unknown argument":  `{ order(id: 1, x: 2) { id } }`,
+		"missing selection": `{ order(id: 1) }`,
+		"leaf selection":    `{ order(id: 1) { title { x } } }`,
+		"wrong fragment":    `{ order(id: 1) { ...f } } fragment f on Customer { name }`,
+		"missing variable":  `query($id: ID!) { order(id: $id) { id } }`,
+		"unknown directive": `{ order(id: 1) { id @defer } }`,
+	}
+	for name, q := range cases {
+		resp := execute(t, schema, q, nil)
+		if _, ok := resp["data"]; ok {
+			t.Fatalf("%s: expected no data, got %v", name, resp)
+		}
+		if code := errorCode(t, resp); code != string(errors.InvalidInput) {
+			t.Fatalf("%s: expected INVALID_INPUT, got %s", name, code)
+		}
+	}
+}
+
+func TestExecute_PagedListAndMutations(t *testing.T) {
+	schema, svc := newOrderSchema(t)
+
+	resp := execute(t, schema, `{ orders(page: 2, size: 500, sorts: [{field: "title", direction: "desc"}]) { total page data { id } } }`, nil)
+	page := resp["data"].(map[string]any)["orders"].(map[string]any)
+	if page["total"] != float64(2) || len(page["data"].([]any)) != 2 {
+		t.Fatalf("unexpected page: %v", page)
+	}
+	if svc.lastPage.Page != 2 || svc.lastPage.Size != 100 {
+		t.Fatalf("expected page clamped to max size, got %+v", svc.lastPage)
+	}
+	if len(svc.lastPage.Sorts) != 1 || svc.lastPage.Sorts[0].Direction != query.DESC {
+		t.Fatalf("unexpected sorts: %+v", svc.lastPage.Sorts)
+	}
+
+	resp = execute(t, schema, `mutation($input: OrderInput!) { createOrder(input: $input) { id title } }`,
+		map[string]any{"input": map[string]any{"title": "third", "amount": 3}})
+	created := resp["data"].(map[string]any)["createOrder"].(map[string]any)
+	if created["id"] != float64(3) || created["title"] != "third" {
+		t.Fatalf("unexpected created order: %v", resp)
+	}
+
+	resp = execute(t, schema, `mutation { updateOrder(id: 3, input: {title: "renamed"}) { id title } }`, nil)
+	if svc.orders[3].Title != "renamed" {
+		t.Fatalf("expected order renamed, got %v", resp)
+	}
+
+	resp = execute(t, schema, `mutation { updateOrder(id: 3, input: {id: 1, title: "x"}) { id } }`, nil)
+	if code := errorCode(t, resp); code != string(errors.InvalidInput) {
+		t.Fatalf("expected INVALID_INPUT for protected id field, got %s", code)
+	}
+
+	resp = execute(t, schema, `mutation { deleteOrder(id: 3) }`, nil)
+	if resp["data"].(map[string]any)["deleteOrder"] != true {
+		t.Fatalf("unexpected delete response: %v", resp)
+	}
+	if _, ok := svc.orders[3]; ok {
+		t.Fatalf("expected order deleted")
+	}
+}
+
+func TestAddEntity_InputAllowlist(t *testing.T) {
+	schema, svc := newOrderSchema(t)
+
+	resp := execute(t, schema, `mutation { createOrder(input: {title: "x", created_at: "2020-01-01T00:00:00Z"}) { id } }`, nil)
+	if code := errorCode(t, resp); code != string(errors.InvalidInput) {
+		t.Fatalf("expected INVALID_INPUT for protected field, got %s", code)
+	}
+	if len(svc.orders) != 2 {
+		t.Fatalf("rejected input must not create an order")
+	}
+
+	resp = execute(t, schema, `mutation { updateOrder(id: 1, input: {amount: 1}) { id title amount } }`, nil)
+	updated := resp["data"].(map[string]any)["updateOrder"].(map[string]any)
+	if updated["title"] != "first" || updated["amount"] != float64(1) {
+		t.Fatalf("update should merge input into current entity, got %v", resp)
+	}
+
+	sdl := schema.SDL()
+	input := sdl[strings.Index(sdl, "input OrderInput {"):]
+	input = input[:strings.Index(input, "}")]
+	if strings.Contains(input, "id:") || strings.Contains(input, "created_at") {
+		t.Fatalf("OrderInput should omit protected fields:\n%s", input)
+	}
+
+	restricted := NewSchema()
+	if err := AddEntity[*order, int64](restricted, newOrderService(), EntityConfig{Name: "Order", InputFields: []string{"title"}}); err != nil {
+		t.Fatalf("AddEntity returned error: %v", err)
+	}
+	resp = execute(t, restricted, `mutation { createOrder(input: {title: "x", amount: 3}) { id } }`, nil)
+	if code := errorCode(t, resp); code != string(errors.InvalidInput) {
+		t.Fatalf("expected INVALID_INPUT for field outside allowlist, got %s", code)
+	}
+	if err := AddEntity[*order, int64](NewSchema(), newOrderService(), EntityConfig{InputFields: []string{"missing"}}); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected InvalidInput for unknown input field, got %v", err)
+	}
+}
+
+func TestAddEntity_AuthorizationMatchesRest(t *testing.T) {
+	var permissions []string
+	authorizer, err := auth.NewAuthorizer(
+		auth.EvaluatorFunc(func(_ context.Context, _ auth.Principal, permission string, resources []auth.Resource) (auth.AuthzDecision, error) {
+			permissions = append(permissions, permission)
+			if permission == "order:delete" {
+				return auth.DenyDecision("denied", resources...), nil
+			}
+			return auth.AllowDecision(resources...), nil
+		}),
+		auth.TypedResourceResolver(func(target *order) (auth.Resource, bool) {
+			return auth.Resource{Kind: "order", ID: strconv.FormatInt(target.ID, 10)}, true
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewAuthorizer returned error: %v", err)
+	}
+	svc := newOrderService()
+	schema := NewSchema()
+	err = AddEntity[*order, int64](schema, svc, EntityConfig{
+		Name: "Order",
+		Authorization: &rest.AuthorizationConfig{
+			Authorizer:  authorizer,
+			Permissions: rest.CRUDPermissions{Get: "order:get", Delete: "order:delete"},
+			Roles:       rest.CRUDRoles{Get: []string{"reader"}},
+		},
+	})
+	if !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("write permissions without a constrained writer should be rejected, got %v", err)
+	}
+
+	err = AddEntity[*order, int64](schema, svc, EntityConfig{
+		Name:     "Order",
+		ReadOnly: true,
+		Authorization: &rest.AuthorizationConfig{
+			Authorizer:  authorizer,
+			Permissions: rest.CRUDPermissions{Get: "order:get"},
+			Roles:       rest.CRUDRoles{Get: []string{"reader"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("AddEntity returned error: %v", err)
+	}
+
+	resp := execute(t, schema, `{ order(id: 1) { id } }`, nil)
+	if code := errorCode(t, resp); code != string(errors.Unauthorized) {
+		t.Fatalf("expected UNAUTHORIZED without principal, got %s", code)
+	}
+
+	ctx, err := auth.WithPrincipal(context.Background(), auth.Principal{SubjectID: 7, Roles: []string{"writer"}})
+	if err != nil {
+		t.Fatalf("WithPrincipal returned error: %v", err)
+	}
+	if code := executeCode(t, schema, ctx, `{ order(id: 1) { id } }`); code != string(errors.Forbidden) {
+		t.Fatalf("expected FORBIDDEN for missing role, got %s", code)
+	}
+
+	ctx, _ = auth.WithPrincipal(context.Background(), auth.Principal{SubjectID: 7, Roles: []string{"reader"}})
+	resp = executeCtx(t, schema, ctx, `{ order(id: 1) { id } }`)
+	if resp["errors"] != nil || len(permissions) != 1 || permissions[0] != "order:get" {
+		t.Fatalf("expected get authorized via order:get, got %v (permissions %v)", resp, permissions)
+	}
+}
+
+func TestExecute_DocumentLimits(t *testing.T) {
+	schema, _ := newOrderSchema(t)
+	schema.SetLimits(Limits{MaxQueryBytes: 200, MaxDepth: 3, MaxAliases: 2})
+
+	resp := execute(t, schema, `{ order(id: 1) { id `+strings.Repeat(" ", 200)+`} }`, nil)
+	if code := errorCode(t, resp); code != string(errors.InvalidInput) {
+		t.Fatalf("expected INVALID_INPUT for oversized document, got %s", code)
+	}
+
+	resp = execute(t, schema, `{ a: order(id: 1) { id } b: order(id: 1) { id } c: order(id: 2) { id } }`, nil)
+	if code := errorCode(t, resp); code != string(errors.InvalidInput) {
+		t.Fatalf("expected INVALID_INPUT for too many aliases, got %s", code)
+	}
+
+	resp = execute(t, schema, `{ orders(filters: {a: {b: {c: 1}}}) { total } }`, nil)
+	if code := errorCode(t, resp); code != string(errors.InvalidInput) {
+		t.Fatalf("expected INVALID_INPUT for deep argument value, got %s", code)
+	}
+
+	resp = execute(t, schema, `{ orders { data { ...f } } } fragment f on Order { customer { name } }`, nil)
+	if code := errorCode(t, resp); code != string(errors.InvalidInput) {
+		t.Fatalf("expected INVALID_INPUT when fragments exceed depth, got %s", code)
+	}
+
+	resp = execute(t, schema, `{ orders { data { id } } }`, nil)
+	if resp["errors"] != nil {
+		t.Fatalf("query within limits should succeed, got %v", resp)
+	}
+}
+
+func TestAddEntity_ReadOnlyAndNoCapability(t *testing.T) {
+	schema := NewSchema()
+	if err := AddEntity[*order, int64](schema, newOrderService(), EntityConfig{ReadOnly: true}); err != nil {
+		t.Fatalf("AddEntity returned error: %v", err)
+	}
+	if _, ok := schema.lookupRoot("mutation", "createOrder"); ok {
+		t.Fatalf("read-only entity should not register mutations")
+	}
+	if err := AddEntity[*order, int64](NewSchema(), struct{}{}, EntityConfig{}); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected InvalidInput, got %v", err)
+	}
+	if err := AddEntity[*order, int64](schema, newOrderService(), EntityConfig{ReadOnly: true}); !errors.Is(err, errors.Conflict) {
+		t.Fatalf("expected Conflict for duplicate fields, got %v", err)
+	}
+}
+
+// diApplication 只实现 Get，其余方法由嵌入的 nil 接口占位。
+type diApplication struct {
+	appcrud.IApplication[*order, int64]
+	svc *orderService
+}
+
+func (a *diApplication) Get(ctx context.Context, id int64) (*order, error) { return a.svc.Get(ctx, id) }
+
+func TestAddEntityFromDI(t *testing.T) {
+	container := dibasic.New()
+	app := &diApplication{svc: newOrderService()}
+	if err := di.RegisterInstance[appcrud.IApplication[*order, int64]](container, app); err != nil {
+		t.Fatalf("RegisterInstance returned error: %v", err)
+	}
+	schema := NewSchema()
+	if err := AddEntityFromDI[*order, int64](schema, container, EntityConfig{Name: "Order", ReadOnly: true}); err != nil {
+		t.Fatalf("AddEntityFromDI returned error: %v", err)
+	}
+	resp := execute(t, schema, `{ order(id: 2) { title } }`, nil)
+	if resp["data"].(map[string]any)["order"].(map[string]any)["title"] != "second" {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+	if err := AddEntityFromDI[*order, int64](NewSchema(), dibasic.New(), EntityConfig{}); err == nil {
+		t.Fatalf("expected error for unregistered application")
+	}
+}
+
+type searchOrders struct {
+	Keyword string `json:"keyword"`
+	Limit   int    `json:"limit"`
+}
+
+type orderSummary struct {
+	Title string `json:"title"`
+}
+
+func TestAddQuery_DispatchesToQueryBus(t *testing.T) {
+	bus := mquery.NewQueryBus()
+	var got searchOrders
+	if err := mquery.RegisterFunc(bus, func(_ context.Context, q searchOrders) ([]orderSummary, error) {
+		got = q
+		return []orderSummary{{Title: q.Keyword}}, nil
+	}); err != nil {
+		t.Fatalf("RegisterFunc returned error: %v", err)
+	}
+	schema := NewSchema()
+	if err := AddQuery[searchOrders, []orderSummary](schema, "searchOrders", bus); err != nil {
+		t.Fatalf("AddQuery returned error: %v", err)
+	}
+	resp := execute(t, schema, `{ searchOrders(keyword: "foo", limit: 5) { title } }`, nil)
+	items := resp["data"].(map[string]any)["searchOrders"].([]any)
+	if len(items) != 1 || items[0].(map[string]any)["title"] != "foo" {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+	if got.Limit != 5 {
+		t.Fatalf("expected limit decoded, got %+v", got)
+	}
+}
+
+type placeOrder struct {
+	OrderID string  `json:"order_id"`
+	Amount  float64 `json:"amount"`
+}
+
+type recordingExecutor struct {
+	commands []*command.Command
+	err      error
+}
+
+func (e *recordingExecutor) Execute(_ context.Context, cmd *command.Command) error {
+	e.commands = append(e.commands, cmd)
+	return e.err
+}
+
+func TestAddCommand_ExecutesCommand(t *testing.T) {
+	executor := &recordingExecutor{}
+	schema := NewSchema()
+	if err := AddCommand(schema, executor, CommandConfig[placeOrder]{
+		Name:          "placeOrder",
+		CommandType:   "PlaceOrder",
+		AggregateType: "Order",
+		AggregateID:   func(p placeOrder) string { return p.OrderID },
+	}); err != nil {
+		t.Fatalf("AddCommand returned error: %v", err)
+	}
+	resp := execute(t, schema, `mutation { placeOrder(input: {order_id: "o-1", amount: 12.5}) }`, nil)
+	id, _ := resp["data"].(map[string]any)["placeOrder"].(string)
+	if id == "" || len(executor.commands) != 1 {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+	cmd := executor.commands[0]
+	var payload placeOrder
+	if err := cmd.Payload.DecodeTo(&payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if cmd.ID != id || cmd.Type != "PlaceOrder" || cmd.AggregateID != "o-1" || payload.Amount != 12.5 {
+		t.Fatalf("unexpected command: %+v", cmd)
+	}
+
+	executor.err = errors.NewCode(errors.Conflict, "order exists")
+	resp = execute(t, schema, `mutation { placeOrder(input: {order_id: "o-1"}) }`, nil)
+	if resp["data"] != nil {
+		t.Fatalf("expected data null for failed non-null mutation, got %v", resp["data"])
+	}
+	if code := errorCode(t, resp); code != string(errors.Conflict) {
+		t.Fatalf("expected CONFLICT, got %s", code)
+	}
+}
+
+func TestSchema_SDL(t *testing.T) {
+	schema, _ := newOrderSchema(t)
+	sdl := schema.SDL()
+	for _, want := range []string{
+		"scalar Time",
+		"order(id: ID!): Order\n",
+		"orders(page: Int, size: Int, sorts: [SortInput], filters: JSON): OrderPage\n",
+		"createOrder(input: OrderInput!): Order\n",
+		"deleteOrder(id: ID!): Boolean!\n",
+		"type Order {\n  id: Int!\n  title: String!\n  amount: Float!\n  tags: [String!]\n  customer: Customer\n  created_at: Time!\n}",
+		"input OrderInput {",
+		"data: [Order]",
+	} {
+		if !strings.Contains(sdl, want) {
+			t.Fatalf("SDL missing %q:\n%s", want, sdl)
+		}
+	}
+	if strings.Contains(sdl, "internal") {
+		t.Fatalf("SDL should not expose unexported fields:\n%s", sdl)
+	}
+}
+
+func TestHandler_RegisterRoutes(t *testing.T) {
+	schema, _ := newOrderSchema(t)
+	handler, err := NewHandler(schema, HandlerConfig{})
+	if err != nil {
+		t.Fatalf("NewHandler returned error: %v", err)
+	}
+	group := httpxtest.NewMockRouteGroup()
+	if err := handler.RegisterRoutes(group); err != nil {
+		t.Fatalf("RegisterRoutes returned error: %v", err)
+	}
+
+	body := `{"query":"query($id: ID!) { order(id: $id) { title } }","variables":{"id":2}}`
+	rec := httpxtest.Serve(t, group.Handler(t, http.MethodPost, "/graphql"), httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body)), nil)
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"data":{"order":{"title":"second"}}}` {
+		t.Fatalf("unexpected POST response: %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httpxtest.Serve(t, group.Handler(t, http.MethodPost, "/graphql"), httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{`)), nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for malformed body, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	params := url.Values{"query": {`{ order(id: 1) { title } }`}}
+	rec = httpxtest.Serve(t, group.Handler(t, http.MethodGet, "/graphql"), httptest.NewRequest(http.MethodGet, "/graphql?"+params.Encode(), nil), nil)
+	if rec.Body.String() != `{"data":{"order":{"title":"first"}}}` {
+		t.Fatalf("unexpected GET response: %s", rec.Body.String())
+	}
+
+	params = url.Values{"query": {`mutation { deleteOrder(id: 1) }`}}
+	rec = httpxtest.Serve(t, group.Handler(t, http.MethodGet, "/graphql"), httptest.NewRequest(http.MethodGet, "/graphql?"+params.Encode(), nil), nil)
+	if !strings.Contains(rec.Body.String(), "mutations are not allowed over GET") {
+		t.Fatalf("expected mutation over GET to be rejected: %s", rec.Body.String())
+	}
+
+	rec = httpxtest.Serve(t, group.Handler(t, http.MethodGet, "/graphql/schema"), httptest.NewRequest(http.MethodGet, "/graphql/schema", nil), nil)
+	if !strings.Contains(rec.Body.String(), "type Query {") || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("unexpected schema response: %s", rec.Body.String())
+	}
+}
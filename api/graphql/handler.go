@@ -0,0 +1,115 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+// DefaultPath 是 GraphQL 端点的默认挂载路径。
+const DefaultPath = "/graphql"
+
+// HandlerConfig 定义 GraphQL 路由配置。
+type HandlerConfig struct {
+	// Path 端点路径；为空时使用 DefaultPath。
+	Path string
+
+	// SchemaPath 输出 SDL 的路径；为空时为 Path+"/schema"，设为 "-" 表示不挂载。
+	SchemaPath string
+}
+
+// Handler 是 GraphQL HTTP 端点，实现 assembly.IRouteRegistrar。
+//
+// 约定：
+//   - POST 接收 JSON 请求体 {"query", "operationName", "variables"}；
+//   - GET 通过 query/operationName/variables 查询参数传递，只允许 query 操作；
+//   - 执行结果（包括字段错误）统一以 200 返回，请求体无法解析时返回 400。
+type Handler struct {
+	schema *Schema
+	config HandlerConfig
+}
+
+// NewHandler 创建 GraphQL 端点。
+func NewHandler(schema *Schema, cfg HandlerConfig) (*Handler, error) {
+	if schema == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "graphql schema is nil")
+	}
+	if strings.TrimSpace(cfg.Path) == "" {
+		cfg.Path = DefaultPath
+	}
+	if strings.TrimSpace(cfg.SchemaPath) == "" {
+		cfg.SchemaPath = strings.TrimRight(cfg.Path, "/") + "/schema"
+	}
+	return &Handler{schema: schema, config: cfg}, nil
+}
+
+// RegisterRoutes 把端点注册到路由组。
+func (h *Handler) RegisterRoutes(group httpx.IRouteGroup) error {
+	if group == nil {
+		return errors.NewCode(errors.InvalidInput, "route group cannot be nil")
+	}
+	group.POST(h.config.Path, h.handlePost)
+	group.GET(h.config.Path, h.handleGet)
+	if h.config.SchemaPath != "-" {
+		group.GET(h.config.SchemaPath, h.handleSchema)
+	}
+	return nil
+}
+
+func (h *Handler) handlePost(c httpx.IContext) error {
+	body, err := c.Body()
+	if err != nil {
+		return h.writeRequestError(c, errors.Wrap(err, errors.InvalidInput, "failed to read graphql request body"))
+	}
+	var req Request
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&req); err != nil {
+		return h.writeRequestError(c, errors.Wrap(err, errors.InvalidInput, "invalid graphql request body"))
+	}
+	return h.execute(c, req, false)
+}
+
+func (h *Handler) handleGet(c httpx.IContext) error {
+	req := Request{Query: c.Query("query"), OperationName: c.Query("operationName")}
+	if raw := c.Query("variables"); raw != "" {
+		decoder := json.NewDecoder(strings.NewReader(raw))
+		decoder.UseNumber()
+		if err := decoder.Decode(&req.Variables); err != nil {
+			return h.writeRequestError(c, errors.Wrap(err, errors.InvalidInput, "invalid graphql variables"))
+		}
+	}
+	return h.execute(c, req, true)
+}
+
+func (h *Handler) handleSchema(c httpx.IContext) error {
+	return c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(h.schema.SDL()))
+}
+
+func (h *Handler) execute(c httpx.IContext, req Request, queryOnly bool) error {
+	format := func(err error) (string, string) {
+		_, payload := httpx.EncodeErrorResponse(c, err)
+		return payload.Code, payload.Message
+	}
+	return h.write(c, http.StatusOK, h.schema.execute(c.RequestContext(), req, format, queryOnly))
+}
+
+func (h *Handler) writeRequestError(c httpx.IContext, err error) error {
+	_, payload := httpx.EncodeErrorResponse(c, err)
+	return h.write(c, http.StatusBadRequest, &Response{Errors: []*Error{{
+		Message:    payload.Message,
+		Extensions: map[string]any{"code": payload.Code},
+	}}})
+}
+
+func (h *Handler) write(c httpx.IContext, status int, resp *Response) error {
+	payload, err := json.Marshal(resp)
+	if err != nil {
+		return errors.Wrap(err, errors.Internal, "failed to encode graphql response")
+	}
+	return c.Data(status, "application/json; charset=utf-8", payload)
+}
@@ -0,0 +1,702 @@
+package graphql
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gochen/errors"
+)
+
+// document 是解析后的可执行文档（operation 与 fragment 定义）。
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+type operation struct {
+	kind       string // query / mutation
+	name       string
+	variables  []variableDef
+	selections []*selection
+}
+
+type variableDef struct {
+	name         string
+	nonNull      bool
+	defaultValue any
+	hasDefault   bool
+}
+
+type fragment struct {
+	name          string
+	typeCondition string
+	selections    []*selection
+}
+
+// selection 是字段、fragment 展开或内联 fragment 之一。
+type selection struct {
+	// 字段
+	alias      string
+	name       string
+	args       map[string]any
+	selections []*selection
+
+	// fragment 展开（spread 非空）或内联 fragment（inline 为 true）
+	spread        string
+	inline        bool
+	typeCondition string
+
+	directives []directive
+}
+
+type directive struct {
+	name string
+	args map[string]any
+}
+
+// responseKey 返回字段在响应中的键（别名优先）。
+func (s *selection) responseKey() string {
+	if s.alias != "" {
+		return s.alias
+	}
+	return s.name
+}
+
+// variableRef 是参数中对变量的引用，执行前替换为变量值。
+type variableRef string
+
+// enumValue 是枚举字面量，执行时按字符串处理。
+type enumValue string
+
+const (
+	tokenEOF = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  int
+	value string
+	pos   int
+}
+
+type parser struct {
+	src string
+	pos int
+	tok token
+
+	limits  Limits
+	depth   int
+	aliases int
+}
+
+// parseDocument 解析 GraphQL 可执行文档；不支持 schema 定义语法。
+//
+// 文档大小、嵌套深度与别名数量超过 limits 时返回 InvalidInput。
+func parseDocument(src string, limits Limits) (*document, error) {
+	limits = limits.withDefaults()
+	if len(src) > limits.MaxQueryBytes {
+		return nil, errors.NewCode(errors.InvalidInput, "graphql document is too large").
+			WithContext("max_bytes", limits.MaxQueryBytes)
+	}
+	p := &parser{src: src, limits: limits}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	doc := &document{fragments: make(map[string]*fragment)}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.isPunct("{"):
+			selections, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &operation{kind: "query", selections: selections})
+		case p.tok.kind == tokenName && (p.tok.value == "query" || p.tok.value == "mutation"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case p.tok.kind == tokenName && p.tok.value == "fragment":
+			frag, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := doc.fragments[frag.name]; exists {
+				return nil, syntaxError(p.tok.pos, "duplicate fragment "+frag.name)
+			}
+			doc.fragments[frag.name] = frag
+		case p.tok.kind == tokenName && p.tok.value == "subscription":
+			return nil, errors.NewCode(errors.Unsupported, "graphql subscriptions are not supported")
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	if len(doc.operations) == 0 {
+		return nil, errors.NewCode(errors.InvalidInput, "graphql document has no operation")
+	}
+	return doc, nil
+}
+
+func (p *parser) parseOperation() (*operation, error) {
+	op := &operation{kind: p.tok.value}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenName {
+		op.name = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if p.isPunct("(") {
+		defs, err := p.parseVariableDefinitions()
+		if err != nil {
+			return nil, err
+		}
+		op.variables = defs
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selections = selections
+	return op, nil
+}
+
+func (p *parser) parseVariableDefinitions() ([]variableDef, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	var defs []variableDef
+	for !p.isPunct(")") {
+		if err := p.expectPunct("$"); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		nonNull, err := p.parseTypeRef()
+		if err != nil {
+			return nil, err
+		}
+		def := variableDef{name: name, nonNull: nonNull}
+		if p.isPunct("=") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			value, err := p.parseValue(true)
+			if err != nil {
+				return nil, err
+			}
+			def.defaultValue, def.hasDefault = value, true
+		}
+		defs = append(defs, def)
+	}
+	return defs, p.advance()
+}
+
+// parseTypeRef 解析变量类型（Name、[Type]、Type!），返回最外层是否非空；具体类型不做校验。
+func (p *parser) parseTypeRef() (bool, error) {
+	if p.isPunct("[") {
+		if err := p.advance(); err != nil {
+			return false, err
+		}
+		if _, err := p.parseTypeRef(); err != nil {
+			return false, err
+		}
+		if err := p.expectPunct("]"); err != nil {
+			return false, err
+		}
+	} else if _, err := p.expectName(); err != nil {
+		return false, err
+	}
+	if p.isPunct("!") {
+		return true, p.advance()
+	}
+	return false, nil
+}
+
+func (p *parser) parseFragment() (*fragment, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if name == "on" {
+		return nil, syntaxError(p.tok.pos, "fragment name cannot be \"on\"")
+	}
+	if p.tok.kind != tokenName || p.tok.value != "on" {
+		return nil, p.unexpected()
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	typeCondition, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	return &fragment{name: name, typeCondition: typeCondition, selections: selections}, nil
+}
+
+func (p *parser) parseSelectionSet() ([]*selection, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+	var selections []*selection
+	for !p.isPunct("}") {
+		if p.tok.kind == tokenEOF {
+			return nil, p.unexpected()
+		}
+		sel, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, sel)
+	}
+	if len(selections) == 0 {
+		return nil, syntaxError(p.tok.pos, "selection set cannot be empty")
+	}
+	return selections, p.advance()
+}
+
+func (p *parser) parseSelection() (*selection, error) {
+	if p.isPunct("...") {
+		return p.parseFragmentSelection()
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	sel := &selection{name: name}
+	if p.isPunct(":") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if sel.name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+		sel.alias = name
+		if p.aliases++; p.aliases > p.limits.MaxAliases {
+			return nil, errors.NewCode(errors.InvalidInput, "graphql document has too many aliases").
+				WithContext("max_aliases", p.limits.MaxAliases)
+		}
+	}
+	if p.isPunct("(") {
+		if sel.args, err = p.parseArguments(); err != nil {
+			return nil, err
+		}
+	}
+	if sel.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if p.isPunct("{") {
+		if sel.selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return sel, nil
+}
+
+func (p *parser) parseFragmentSelection() (*selection, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	sel := &selection{}
+	var err error
+	switch {
+	case p.tok.kind == tokenName && p.tok.value != "on":
+		sel.spread = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		sel.directives, err = p.parseDirectives()
+		return sel, err
+	case p.tok.kind == tokenName:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if sel.typeCondition, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+	sel.inline = true
+	if sel.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if sel.selections, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+	return sel, nil
+}
+
+func (p *parser) parseArguments() (map[string]any, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	args := make(map[string]any)
+	for !p.isPunct(")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue(false)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := args[name]; exists {
+			return nil, syntaxError(p.tok.pos, "duplicate argument "+name)
+		}
+		args[name] = value
+	}
+	return args, p.advance()
+}
+
+func (p *parser) parseDirectives() ([]directive, error) {
+	var directives []directive
+	for p.isPunct("@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		d := directive{name: name}
+		if p.isPunct("(") {
+			if d.args, err = p.parseArguments(); err != nil {
+				return nil, err
+			}
+		}
+		directives = append(directives, d)
+	}
+	return directives, nil
+}
+
+// parseValue 解析值字面量；constant 为 true 时不允许变量（用于变量默认值）。
+func (p *parser) parseValue(constant bool) (any, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokenPunct:
+		switch tok.value {
+		case "$":
+			if constant {
+				return nil, p.unexpected()
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.expectName()
+			return variableRef(name), err
+		case "[":
+			if err := p.enter(); err != nil {
+				return nil, err
+			}
+			defer p.leave()
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			list := []any{}
+			for !p.isPunct("]") {
+				item, err := p.parseValue(constant)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, item)
+			}
+			return list, p.advance()
+		case "{":
+			if err := p.enter(); err != nil {
+				return nil, err
+			}
+			defer p.leave()
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			object := map[string]any{}
+			for !p.isPunct("}") {
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expectPunct(":"); err != nil {
+					return nil, err
+				}
+				if object[name], err = p.parseValue(constant); err != nil {
+					return nil, err
+				}
+			}
+			return object, p.advance()
+		}
+	case tokenInt:
+		value, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			return nil, syntaxError(tok.pos, "invalid int "+tok.value)
+		}
+		return value, p.advance()
+	case tokenFloat:
+		value, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, syntaxError(tok.pos, "invalid float "+tok.value)
+		}
+		return value, p.advance()
+	case tokenString:
+		return tok.value, p.advance()
+	case tokenName:
+		var value any
+		switch tok.value {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		default:
+			value = enumValue(tok.value)
+		}
+		return value, p.advance()
+	}
+	return nil, p.unexpected()
+}
+
+// enter 进入一层选择集或复合值，超过 MaxDepth 时返回 InvalidInput。
+func (p *parser) enter() error {
+	if p.depth++; p.depth > p.limits.MaxDepth {
+		return depthExceeded(p.limits.MaxDepth)
+	}
+	return nil
+}
+
+func (p *parser) leave() { p.depth-- }
+
+func depthExceeded(max int) error {
+	return errors.NewCode(errors.InvalidInput, "graphql document exceeds max depth").
+		WithContext("max_depth", max)
+}
+
+func (p *parser) isPunct(value string) bool {
+	return p.tok.kind == tokenPunct && p.tok.value == value
+}
+
+func (p *parser) expectPunct(value string) error {
+	if !p.isPunct(value) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.unexpected()
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokenEOF {
+		return syntaxError(p.tok.pos, "unexpected end of document")
+	}
+	return syntaxError(p.tok.pos, "unexpected "+strconv.Quote(p.tok.value))
+}
+
+func syntaxError(pos int, message string) error {
+	return errors.NewCode(errors.InvalidInput, "graphql syntax error: "+message).WithContext("position", pos)
+}
+
+// advance 读取下一个 token，跳过空白、逗号与注释。
+func (p *parser) advance() error {
+	src := p.src
+	for p.pos < len(src) {
+		c := src[p.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			p.pos++
+			continue
+		}
+		if c == '#' {
+			for p.pos < len(src) && src[p.pos] != '\n' && src[p.pos] != '\r' {
+				p.pos++
+			}
+			continue
+		}
+		break
+	}
+	start := p.pos
+	if p.pos >= len(src) {
+		p.tok = token{kind: tokenEOF, pos: start}
+		return nil
+	}
+	c := src[p.pos]
+	switch {
+	case strings.IndexByte("!$()&:=@[]{}|", c) >= 0:
+		p.pos++
+		p.tok = token{kind: tokenPunct, value: string(c), pos: start}
+	case c == '.':
+		if !strings.HasPrefix(src[p.pos:], "...") {
+			return syntaxError(start, "unexpected \".\"")
+		}
+		p.pos += 3
+		p.tok = token{kind: tokenPunct, value: "...", pos: start}
+	case c == '_' || isLetter(c):
+		for p.pos < len(src) && (src[p.pos] == '_' || isLetter(src[p.pos]) || isDigit(src[p.pos])) {
+			p.pos++
+		}
+		p.tok = token{kind: tokenName, value: src[start:p.pos], pos: start}
+	case c == '-' || isDigit(c):
+		return p.lexNumber(start)
+	case c == '"':
+		if strings.HasPrefix(src[p.pos:], `"""`) {
+			return p.lexBlockString(start)
+		}
+		return p.lexString(start)
+	default:
+		r, _ := utf8.DecodeRuneInString(src[p.pos:])
+		return syntaxError(start, "unexpected character "+strconv.QuoteRune(r))
+	}
+	return nil
+}
+
+func (p *parser) lexNumber(start int) error {
+	src := p.src
+	if src[p.pos] == '-' {
+		p.pos++
+	}
+	digits := func() int {
+		n := 0
+		for p.pos < len(src) && isDigit(src[p.pos]) {
+			p.pos++
+			n++
+		}
+		return n
+	}
+	if digits() == 0 {
+		return syntaxError(start, "invalid number")
+	}
+	kind := tokenInt
+	if p.pos < len(src) && src[p.pos] == '.' {
+		p.pos++
+		kind = tokenFloat
+		if digits() == 0 {
+			return syntaxError(start, "invalid number")
+		}
+	}
+	if p.pos < len(src) && (src[p.pos] == 'e' || src[p.pos] == 'E') {
+		p.pos++
+		kind = tokenFloat
+		if p.pos < len(src) && (src[p.pos] == '+' || src[p.pos] == '-') {
+			p.pos++
+		}
+		if digits() == 0 {
+			return syntaxError(start, "invalid number")
+		}
+	}
+	p.tok = token{kind: kind, value: p.src[start:p.pos], pos: start}
+	return nil
+}
+
+func (p *parser) lexString(start int) error {
+	src := p.src
+	p.pos++
+	var sb strings.Builder
+	for p.pos < len(src) {
+		c := src[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			p.tok = token{kind: tokenString, value: sb.String(), pos: start}
+			return nil
+		case c == '\n' || c == '\r':
+			return syntaxError(start, "unterminated string")
+		case c == '\\':
+			if p.pos+1 >= len(src) {
+				return syntaxError(start, "unterminated string")
+			}
+			escaped := src[p.pos+1]
+			p.pos += 2
+			switch escaped {
+			case '"', '\\', '/':
+				sb.WriteByte(escaped)
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if p.pos+4 > len(src) {
+					return syntaxError(start, "invalid unicode escape")
+				}
+				code, err := strconv.ParseUint(src[p.pos:p.pos+4], 16, 32)
+				if err != nil {
+					return syntaxError(start, "invalid unicode escape")
+				}
+				sb.WriteRune(rune(code))
+				p.pos += 4
+			default:
+				return syntaxError(start, "invalid escape sequence")
+			}
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+	return syntaxError(start, "unterminated string")
+}
+
+// lexBlockString 读取 """ 块字符串（保留原文，仅处理 \""" 转义，不做缩进规整）。
+func (p *parser) lexBlockString(start int) error {
+	p.pos += 3
+	var sb strings.Builder
+	for p.pos < len(p.src) {
+		rest := p.src[p.pos:]
+		switch {
+		case strings.HasPrefix(rest, `\"""`):
+			sb.WriteString(`"""`)
+			p.pos += 4
+		case strings.HasPrefix(rest, `"""`):
+			p.pos += 3
+			p.tok = token{kind: tokenString, value: strings.TrimSpace(sb.String()), pos: start}
+			return nil
+		default:
+			sb.WriteByte(p.src[p.pos])
+			p.pos++
+		}
+	}
+	return syntaxError(start, "unterminated block string")
+}
+
+func isLetter(c byte) bool { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
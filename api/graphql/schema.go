@@ -0,0 +1,560 @@
+// Package graphql 提供由已注册实体与查询生成的 GraphQL 端点，面向管理后台与 BFF。
+//
+// 设计要点：
+//   - 类型由 Go 结构体元数据推导（json tag 决定字段名），不需要手写 SDL；
+//   - AddEntity / AddEntityFromDI 把 CRUD 服务映射为 get/list 查询与 create/update/delete 变更；
+//   - AddQuery 把 messaging/query 的查询映射为查询字段，AddCommand 把变更映射为命令；
+//   - Handler 实现路由注册器（RegisterRoutes），可直接挂到 httpx.IRouteGroup。
+//
+// 执行器是内置的最小实现：支持 query/mutation、变量、别名、fragment 与 @skip/@include；
+// 不支持 subscription 与内省查询（__schema/__type），schema 以 SDL 文本对外提供（Schema.SDL）。
+package graphql
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gochen/errors"
+)
+
+// ResolveFunc 解析字段值；args 为已替换变量的参数（JSON 兼容值），可用 Decode 解码为结构体。
+type ResolveFunc func(ctx context.Context, args map[string]any) (any, error)
+
+// Argument 描述字段参数。
+type Argument struct {
+	// Name 参数名。
+	Name string
+	// Type 参数的 Go 类型，用于推导 GraphQL 类型。
+	Type reflect.Type
+	// TypeName 可选：覆盖推导出的 GraphQL 类型名（如 "ID"）。
+	TypeName string
+	// Required 为 true 时参数非空（"!"），缺失时请求校验失败。
+	Required bool
+}
+
+// Field 描述 Query 或 Mutation 上的根字段。
+type Field struct {
+	// Name 字段名。
+	Name string
+	// Description 可选：字段说明，输出到 SDL。
+	Description string
+	// Args 参数列表。
+	Args []Argument
+	// Type 返回值的 Go 类型，用于推导 GraphQL 类型与结果投影；Resolve 应返回该类型的值。
+	Type reflect.Type
+	// Resolve 解析函数。
+	Resolve ResolveFunc
+}
+
+// Schema 是 GraphQL schema：根字段与由 Go 类型推导的对象类型，并发安全。
+type Schema struct {
+	mu        sync.RWMutex
+	queries   []*rootField
+	mutations []*rootField
+
+	objects map[reflect.Type]*objectType
+	inputs  map[reflect.Type]*objectType
+	// names 记录已占用的类型名，避免不同 Go 类型推导出同名 GraphQL 类型。
+	names   map[string]reflect.Type
+	scalars map[string]bool
+	// aliases 为 Go 类型预设的 GraphQL 类型名。
+	aliases map[reflect.Type]string
+	// inputAllow 限定结构体作为输入类型时允许的字段（json 名）。
+	inputAllow map[reflect.Type]map[string]bool
+	limits     Limits
+}
+
+// Limits 限制单个请求文档的规模，防止深层嵌套、别名放大与超大文档耗尽资源。
+type Limits struct {
+	// MaxQueryBytes 查询文档的最大字节数（默认 DefaultMaxQueryBytes）。
+	MaxQueryBytes int
+	// MaxDepth 选择集与参数值的最大嵌套深度（默认 DefaultMaxDepth），fragment 展开后同样计入。
+	MaxDepth int
+	// MaxAliases 文档中别名的最大数量（默认 DefaultMaxAliases）。
+	MaxAliases int
+}
+
+const (
+	// DefaultMaxQueryBytes 是默认的查询文档大小上限。
+	DefaultMaxQueryBytes = 64 << 10
+	// DefaultMaxDepth 是默认的嵌套深度上限。
+	DefaultMaxDepth = 12
+	// DefaultMaxAliases 是默认的别名数量上限。
+	DefaultMaxAliases = 32
+)
+
+func (l Limits) withDefaults() Limits {
+	if l.MaxQueryBytes <= 0 {
+		l.MaxQueryBytes = DefaultMaxQueryBytes
+	}
+	if l.MaxDepth <= 0 {
+		l.MaxDepth = DefaultMaxDepth
+	}
+	if l.MaxAliases <= 0 {
+		l.MaxAliases = DefaultMaxAliases
+	}
+	return l
+}
+
+type rootField struct {
+	Field
+	typ  *typeRef
+	args []argumentDef
+}
+
+type argumentDef struct {
+	name     string
+	typ      *typeRef
+	required bool
+}
+
+// typeRef 是 GraphQL 类型引用：具名类型或列表，可带非空修饰。
+type typeRef struct {
+	name    string
+	elem    *typeRef
+	nonNull bool
+	// object 非空表示对象类型（需要子字段选择）。
+	object *objectType
+}
+
+func (t *typeRef) String() string {
+	s := t.name
+	if t.elem != nil {
+		s = "[" + t.elem.String() + "]"
+	}
+	if t.nonNull {
+		s += "!"
+	}
+	return s
+}
+
+type objectType struct {
+	name   string
+	goType reflect.Type
+	fields []*objectField
+	byName map[string]*objectField
+}
+
+type objectField struct {
+	name  string
+	index []int
+	typ   *typeRef
+}
+
+const (
+	scalarInt     = "Int"
+	scalarFloat   = "Float"
+	scalarString  = "String"
+	scalarBoolean = "Boolean"
+	scalarID      = "ID"
+	// scalarTime 以 RFC 3339 字符串表示 time.Time。
+	scalarTime = "Time"
+	// scalarJSON 表示任意 JSON 值（map、interface 以及自定义 JSON 编码的类型）。
+	scalarJSON = "JSON"
+)
+
+var (
+	nameRE          = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+	timeType        = reflect.TypeFor[time.Time]()
+	marshalerType   = reflect.TypeFor[json.Marshaler]()
+	textMarshalType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// NewSchema 创建空 schema。
+func NewSchema() *Schema {
+	return &Schema{
+		objects: make(map[reflect.Type]*objectType),
+		inputs:  make(map[reflect.Type]*objectType),
+		names:   make(map[string]reflect.Type),
+		scalars: make(map[string]bool),
+		aliases: make(map[reflect.Type]string),
+
+		inputAllow: make(map[reflect.Type]map[string]bool),
+		limits:     Limits{}.withDefaults(),
+	}
+}
+
+// SetLimits 设置请求文档限制；未设置（<=0）的项使用默认值。
+func (s *Schema) SetLimits(limits Limits) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits = limits.withDefaults()
+}
+
+func (s *Schema) currentLimits() Limits {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.limits
+}
+
+// restrictInput 限定结构体作为输入类型时只暴露 allowed 中的字段，须在该输入类型首次被引用前调用。
+func (s *Schema) restrictInput(goType reflect.Type, allowed map[string]bool) error {
+	goType = indirectType(goType)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.inputs[goType]; ok {
+		return errors.NewCode(errors.Conflict, "graphql input type already registered").
+			WithContext("go_type", goType.String())
+	}
+	s.inputAllow[goType] = allowed
+	return nil
+}
+
+// NameType 为结构体类型预设 GraphQL 类型名（输入类型为 name+"Input"），须在该类型首次被引用前调用。
+func (s *Schema) NameType(goType reflect.Type, name string) error {
+	if goType == nil {
+		return errors.NewCode(errors.InvalidInput, "graphql type cannot be nil")
+	}
+	for goType.Kind() == reflect.Pointer {
+		goType = goType.Elem()
+	}
+	if !nameRE.MatchString(name) {
+		return errors.NewCode(errors.InvalidInput, "invalid graphql type name").WithContext("name", name)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.aliases[goType]; ok && existing != name {
+		return errors.NewCode(errors.Conflict, "graphql type already named").
+			WithContext("go_type", goType.String()).
+			WithContext("name", existing)
+	}
+	if _, ok := s.objects[goType]; ok && s.aliases[goType] != name {
+		return errors.NewCode(errors.Conflict, "graphql type already registered").
+			WithContext("go_type", goType.String())
+	}
+	s.aliases[goType] = name
+	return nil
+}
+
+// AddQuery 注册 Query 根字段。
+func (s *Schema) AddQuery(field Field) error {
+	return s.addField(&s.queries, "Query", field)
+}
+
+// AddMutation 注册 Mutation 根字段。
+func (s *Schema) AddMutation(field Field) error {
+	return s.addField(&s.mutations, "Mutation", field)
+}
+
+func (s *Schema) addField(target *[]*rootField, parent string, field Field) error {
+	if !nameRE.MatchString(field.Name) || strings.HasPrefix(field.Name, "__") {
+		return errors.NewCode(errors.InvalidInput, "invalid graphql field name").WithContext("field", field.Name)
+	}
+	if field.Type == nil {
+		return errors.NewCode(errors.InvalidInput, "graphql field type cannot be nil").WithContext("field", field.Name)
+	}
+	if field.Resolve == nil {
+		return errors.NewCode(errors.InvalidInput, "graphql resolver cannot be nil").WithContext("field", field.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range *target {
+		if existing.Name == field.Name {
+			return errors.NewCode(errors.Conflict, "graphql field already registered").
+				WithContext("type", parent).
+				WithContext("field", field.Name)
+		}
+	}
+	typ, err := s.outputType(field.Type)
+	if err != nil {
+		return errors.Wrap(err, errors.InvalidInput, "unsupported graphql field type").WithContext("field", field.Name)
+	}
+	root := &rootField{Field: field, typ: typ}
+	seen := make(map[string]bool, len(field.Args))
+	for _, arg := range field.Args {
+		if !nameRE.MatchString(arg.Name) || seen[arg.Name] {
+			return errors.NewCode(errors.InvalidInput, "invalid graphql argument").
+				WithContext("field", field.Name).
+				WithContext("argument", arg.Name)
+		}
+		seen[arg.Name] = true
+		var argType *typeRef
+		if arg.TypeName != "" {
+			argType = &typeRef{name: arg.TypeName}
+		} else if arg.Type == nil {
+			return errors.NewCode(errors.InvalidInput, "graphql argument type cannot be nil").
+				WithContext("field", field.Name).
+				WithContext("argument", arg.Name)
+		} else if argType, err = s.inputType(arg.Type); err != nil {
+			return errors.Wrap(err, errors.InvalidInput, "unsupported graphql argument type").
+				WithContext("field", field.Name).
+				WithContext("argument", arg.Name)
+		}
+		argType = &typeRef{name: argType.name, elem: argType.elem, nonNull: arg.Required}
+		root.args = append(root.args, argumentDef{name: arg.Name, typ: argType, required: arg.Required})
+	}
+	*target = append(*target, root)
+	return nil
+}
+
+// outputType 推导输出类型；非指针、非切片、非 map 的值视为非空。调用方需持写锁。
+func (s *Schema) outputType(t reflect.Type) (*typeRef, error) {
+	switch {
+	case t.Kind() == reflect.Pointer:
+		inner, err := s.outputType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		nullable := *inner
+		nullable.nonNull = false
+		return &nullable, nil
+	case t == timeType:
+		s.scalars[scalarTime] = true
+		return &typeRef{name: scalarTime, nonNull: true}, nil
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return &typeRef{name: scalarString, nonNull: true}, nil
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		elem, err := s.outputType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &typeRef{elem: elem, nonNull: t.Kind() == reflect.Array}, nil
+	case t.Kind() == reflect.Map || t.Kind() == reflect.Interface || implementsMarshaler(t):
+		s.scalars[scalarJSON] = true
+		return &typeRef{name: scalarJSON}, nil
+	case t.Kind() == reflect.Struct:
+		obj, err := s.objectFor(t, s.objects, "")
+		if err != nil {
+			return nil, err
+		}
+		return &typeRef{name: obj.name, nonNull: true, object: obj}, nil
+	}
+	name, err := scalarName(t)
+	if err != nil {
+		return nil, err
+	}
+	return &typeRef{name: name, nonNull: true}, nil
+}
+
+// inputType 推导输入类型；输入对象字段一律可空（部分字段缺省时按零值解码）。调用方需持写锁。
+func (s *Schema) inputType(t reflect.Type) (*typeRef, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		s.scalars[scalarTime] = true
+		return &typeRef{name: scalarTime}, nil
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return &typeRef{name: scalarString}, nil
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		elem, err := s.inputType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &typeRef{elem: elem}, nil
+	case t.Kind() == reflect.Map || t.Kind() == reflect.Interface || implementsMarshaler(t):
+		s.scalars[scalarJSON] = true
+		return &typeRef{name: scalarJSON}, nil
+	case t.Kind() == reflect.Struct:
+		obj, err := s.objectFor(t, s.inputs, "Input")
+		if err != nil {
+			return nil, err
+		}
+		return &typeRef{name: obj.name}, nil
+	}
+	name, err := scalarName(t)
+	if err != nil {
+		return nil, err
+	}
+	return &typeRef{name: name}, nil
+}
+
+func (s *Schema) objectFor(t reflect.Type, registry map[reflect.Type]*objectType, suffix string) (*objectType, error) {
+	if obj, ok := registry[t]; ok {
+		return obj, nil
+	}
+	name := s.aliases[t]
+	if name == "" {
+		name = typeName(t)
+	}
+	name += suffix
+	if !nameRE.MatchString(name) {
+		return nil, errors.NewCode(errors.InvalidInput, "cannot derive graphql type name").
+			WithContext("go_type", t.String())
+	}
+	if existing, ok := s.names[name]; ok && existing != t {
+		return nil, errors.NewCode(errors.Conflict, "graphql type name already used").
+			WithContext("name", name).
+			WithContext("go_type", t.String()).
+			WithContext("registered_type", existing.String())
+	}
+
+	obj := &objectType{name: name, goType: t, byName: make(map[string]*objectField)}
+	// 先登记再推导字段，支持自引用类型。
+	registry[t] = obj
+	s.names[name] = t
+	input := suffix != ""
+	allow := s.inputAllow[t]
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || (f.Anonymous && f.Tag.Get("json") == "" && indirectType(f.Type).Kind() == reflect.Struct) {
+			continue
+		}
+		fieldName := jsonName(f)
+		if fieldName == "-" || !nameRE.MatchString(fieldName) || obj.byName[fieldName] != nil {
+			continue
+		}
+		if input && allow != nil && !allow[fieldName] {
+			continue
+		}
+		var (
+			typ *typeRef
+			err error
+		)
+		if input {
+			typ, err = s.inputType(f.Type)
+		} else {
+			typ, err = s.outputType(f.Type)
+		}
+		if err != nil {
+			delete(registry, t)
+			delete(s.names, name)
+			return nil, errors.Wrap(err, errors.InvalidInput, "unsupported graphql field type").
+				WithContext("go_type", t.String()).
+				WithContext("field", f.Name)
+		}
+		field := &objectField{name: fieldName, index: f.Index, typ: typ}
+		obj.fields = append(obj.fields, field)
+		obj.byName[fieldName] = field
+	}
+	if len(obj.fields) == 0 {
+		delete(registry, t)
+		delete(s.names, name)
+		return nil, errors.NewCode(errors.InvalidInput, "graphql object type has no fields").
+			WithContext("go_type", t.String())
+	}
+	return obj, nil
+}
+
+func scalarName(t reflect.Type) (string, error) {
+	switch t.Kind() {
+	case reflect.Bool:
+		return scalarBoolean, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return scalarInt, nil
+	case reflect.Float32, reflect.Float64:
+		return scalarFloat, nil
+	case reflect.String:
+		return scalarString, nil
+	}
+	return "", errors.NewCode(errors.Unsupported, "unsupported go type for graphql").WithContext("go_type", t.String())
+}
+
+func implementsMarshaler(t reflect.Type) bool {
+	if t == timeType {
+		return false
+	}
+	return t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType) ||
+		t.Implements(textMarshalType) || reflect.PointerTo(t).Implements(textMarshalType)
+}
+
+// typeName 返回首字母大写的 Go 类型名；泛型实例化类型去掉类型参数部分。
+func typeName(t reflect.Type) string {
+	name := t.Name()
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// lookupRoot 返回 operation 对应的根字段。
+func (s *Schema) lookupRoot(kind, name string) (*rootField, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fields := s.queries
+	if kind == "mutation" {
+		fields = s.mutations
+	}
+	for _, f := range fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// SDL 以 GraphQL SDL 文本输出 schema（类型按名称排序）。
+func (s *Schema) SDL() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var sb strings.Builder
+	for _, scalar := range []string{scalarJSON, scalarTime} {
+		if s.scalars[scalar] {
+			sb.WriteString("scalar " + scalar + "\n\n")
+		}
+	}
+	writeRoot := func(name string, fields []*rootField) {
+		if len(fields) == 0 {
+			return
+		}
+		sb.WriteString("type " + name + " {\n")
+		for _, f := range fields {
+			if f.Description != "" {
+				sb.WriteString("  " + quoteDescription(f.Description) + "\n")
+			}
+			sb.WriteString("  " + f.Name)
+			if len(f.args) > 0 {
+				parts := make([]string, 0, len(f.args))
+				for _, arg := range f.args {
+					parts = append(parts, arg.name+": "+arg.typ.String())
+				}
+				sb.WriteString("(" + strings.Join(parts, ", ") + ")")
+			}
+			sb.WriteString(": " + f.typ.String() + "\n")
+		}
+		sb.WriteString("}\n\n")
+	}
+	writeRoot("Query", s.queries)
+	writeRoot("Mutation", s.mutations)
+
+	writeObjects := func(keyword string, registry map[reflect.Type]*objectType) {
+		objects := make([]*objectType, 0, len(registry))
+		for _, obj := range registry {
+			objects = append(objects, obj)
+		}
+		sort.Slice(objects, func(i, j int) bool { return objects[i].name < objects[j].name })
+		for _, obj := range objects {
+			sb.WriteString(keyword + " " + obj.name + " {\n")
+			for _, f := range obj.fields {
+				sb.WriteString("  " + f.name + ": " + f.typ.String() + "\n")
+			}
+			sb.WriteString("}\n\n")
+		}
+	}
+	writeObjects("type", s.objects)
+	writeObjects("input", s.inputs)
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+func quoteDescription(description string) string {
+	encoded, _ := json.Marshal(description)
+	return string(encoded)
+}
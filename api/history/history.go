@@ -0,0 +1,167 @@
+// Package history 提供聚合事件时间线的只读 HTTP 端点，面向支持/排障工具。
+//
+// 路由：
+//   - `GET {BasePath}/:type/:id/history`：返回聚合时间线一页（app/eventsourced.AggregateHistory）。
+//
+// 查询参数：
+//   - `after_version`：从该版本之后开始（分页游标，取上一页的 next_version）；
+//   - `limit`：每页事件数；
+//   - `diff=true`：附带相邻版本的状态差异（需要为聚合类型注册 AggregateStateLoader；
+//     单页最多 eventsourced.MaxHistoryDiffPageSize 条，其余通过 next_version 翻页）；
+//   - `from_version` + `to_version`：只返回两个版本之间的状态差异（app/eventsourced.StateDiff）。
+//
+// 端点会暴露事件载荷与元数据，挂载时应配合认证/授权中间件，并通过 Config.AggregateTypes 限定可查询的聚合类型。
+package history
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gochen/app/eventsourced"
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+const (
+	// DefaultBasePath 是聚合历史端点的默认路径前缀。
+	DefaultBasePath = "/aggregates"
+
+	// QueryAfterVersion 是分页游标查询参数名。
+	QueryAfterVersion = "after_version"
+	// QueryLimit 是每页事件数查询参数名。
+	QueryLimit = "limit"
+	// QueryDiff 是附带状态差异的查询参数名。
+	QueryDiff = "diff"
+	// QueryFromVersion / QueryToVersion 是版本区间差异的查询参数名。
+	QueryFromVersion = "from_version"
+	QueryToVersion   = "to_version"
+)
+
+// Config 定义聚合历史端点配置。
+type Config struct {
+	// BasePath 是路由前缀；为空时使用 DefaultBasePath。
+	BasePath string
+
+	// AggregateTypes 是可查询的聚合类型白名单；为空表示不限制。
+	AggregateTypes []string
+}
+
+// Registrar 把 AggregateHistoryService 暴露为 HTTP 端点，实现 host 模块的路由注册器约定。
+type Registrar struct {
+	service *eventsourced.AggregateHistoryService
+	config  Config
+}
+
+// NewRegistrar 创建聚合历史路由注册器；cfg 为 nil 时使用默认配置。
+func NewRegistrar(service *eventsourced.AggregateHistoryService, cfg *Config) *Registrar {
+	config := Config{}
+	if cfg != nil {
+		config = *cfg
+	}
+	config.BasePath = strings.TrimRight(strings.TrimSpace(config.BasePath), "/")
+	if config.BasePath == "" {
+		config.BasePath = DefaultBasePath
+	}
+	return &Registrar{service: service, config: config}
+}
+
+// RegisterRoutes 注册聚合历史端点。
+func (r *Registrar) RegisterRoutes(group httpx.IRouteGroup) error {
+	if group == nil {
+		return errors.NewCode(errors.InvalidInput, "route group cannot be nil")
+	}
+	if r.service == nil {
+		return errors.NewCode(errors.InvalidInput, "aggregate history service cannot be nil")
+	}
+	group.GET(r.config.BasePath+"/:type/:id/history", r.Handler())
+	return nil
+}
+
+// Handler 返回聚合历史请求处理器。
+func (r *Registrar) Handler() httpx.Handler {
+	return func(c httpx.IContext) error {
+		aggregateType := c.Param("type")
+		if aggregateType == "" {
+			return errors.NewCode(errors.InvalidInput, "parameter type cannot be empty")
+		}
+		if len(r.config.AggregateTypes) > 0 && !slices.Contains(r.config.AggregateTypes, aggregateType) {
+			return errors.NewCode(errors.NotFound, "aggregate type not found").
+				WithContext("aggregate_type", aggregateType)
+		}
+		id, err := httpx.ParseInt64Param(c, "id")
+		if err != nil {
+			return err
+		}
+		ctx := c.RequestContext()
+
+		fromVersion, hasFrom, err := uintQuery(c, QueryFromVersion)
+		if err != nil {
+			return err
+		}
+		toVersion, hasTo, err := uintQuery(c, QueryToVersion)
+		if err != nil {
+			return err
+		}
+		if hasFrom || hasTo {
+			if !hasTo {
+				return errors.NewCode(errors.InvalidInput, "to_version is required with from_version")
+			}
+			diff, err := r.service.Diff(ctx, aggregateType, id, fromVersion, toVersion)
+			if err != nil {
+				return err
+			}
+			return c.JSON(http.StatusOK, httpx.JSONValue(diff))
+		}
+
+		afterVersion, _, err := uintQuery(c, QueryAfterVersion)
+		if err != nil {
+			return err
+		}
+		limit, _, err := uintQuery(c, QueryLimit)
+		if err != nil {
+			return err
+		}
+		includeDiffs, err := boolQuery(c, QueryDiff)
+		if err != nil {
+			return err
+		}
+		history, err := r.service.Timeline(ctx, aggregateType, id, eventsourced.HistoryQuery{
+			AfterVersion: afterVersion,
+			Limit:        int(min(limit, eventsourced.MaxHistoryPageSize)),
+			IncludeDiffs: includeDiffs,
+		})
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, httpx.JSONValue(history))
+	}
+}
+
+// uintQuery 解析非负整数查询参数；参数缺失时返回 (0, false, nil)。
+func uintQuery(c httpx.IContext, name string) (uint64, bool, error) {
+	raw := strings.TrimSpace(c.Query(name))
+	if raw == "" {
+		return 0, false, nil
+	}
+	value, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, false, errors.Wrap(err, errors.InvalidInput, "query parameter must be a non-negative integer").
+			WithContext("param", name)
+	}
+	return value, true, nil
+}
+
+func boolQuery(c httpx.IContext, name string) (bool, error) {
+	raw := strings.TrimSpace(c.Query(name))
+	if raw == "" {
+		return false, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, errors.Wrap(err, errors.InvalidInput, "query parameter must be a boolean").
+			WithContext("param", name)
+	}
+	return value, nil
+}
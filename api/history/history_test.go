@@ -0,0 +1,60 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gochen/app/eventsourced"
+	"gochen/eventing"
+	"gochen/eventing/store"
+	"gochen/httpx/httpxtest"
+)
+
+func TestRegistrar_History(t *testing.T) {
+	ctx := context.Background()
+	es := store.NewMemoryEventStore()
+	for v := uint64(1); v <= 3; v++ {
+		evt := eventing.NewEvent[int64](7, "Order", "OrderChanged", v, map[string]any{"step": v})
+		if err := es.AppendEvents(ctx, 7, []eventing.IStorableEvent[int64]{evt}, v-1); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	svc, err := eventsourced.NewAggregateHistoryService(es, nil)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	group := httpxtest.NewMockRouteGroup()
+	if err := NewRegistrar(svc, &Config{AggregateTypes: []string{"Order"}}).RegisterRoutes(group); err != nil {
+		t.Fatalf("register routes: %v", err)
+	}
+	h := group.Handlers["GET /aggregates/:type/:id/history"]
+	if h == nil {
+		t.Fatalf("history route not registered: %v", group.Handlers)
+	}
+
+	w := httpxtest.Serve(t, h, httptest.NewRequest(http.MethodGet, "/aggregates/Order/7/history?after_version=1&limit=1", nil), map[string]string{"type": "Order", "id": "7"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var history eventsourced.AggregateHistory
+	if err := json.Unmarshal(w.Body.Bytes(), &history); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if history.HeadVersion != 3 || len(history.Entries) != 1 || history.Entries[0].Version != 2 || !history.HasMore {
+		t.Fatalf("unexpected history page: %+v", history)
+	}
+
+	if w := httpxtest.Serve(t, h, httptest.NewRequest(http.MethodGet, "/aggregates/Payment/7/history", nil), map[string]string{"type": "Payment", "id": "7"}); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for non-whitelisted type, got %d", w.Code)
+	}
+	if w := httpxtest.Serve(t, h, httptest.NewRequest(http.MethodGet, "/aggregates/Order/7/history?limit=x", nil), map[string]string{"type": "Order", "id": "7"}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid limit, got %d", w.Code)
+	}
+	if w := httpxtest.Serve(t, h, httptest.NewRequest(http.MethodGet, "/aggregates/Order/7/history?from_version=1&to_version=2", nil), map[string]string{"type": "Order", "id": "7"}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for diff without state loader, got %d", w.Code)
+	}
+}
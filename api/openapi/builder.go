@@ -0,0 +1,183 @@
+package openapi
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"gochen/errors"
+)
+
+// ISpecProvider 表示可以产出 OpenAPI 文档的组件。
+type ISpecProvider interface {
+	// Document 返回当前时刻的 OpenAPI 文档快照。
+	Document() *Document
+}
+
+// Builder 负责在装配阶段收集路由操作与 schema，并产出 OpenAPI 文档。
+//
+// 说明：
+//   - 路径中的 `:param` 会统一转换为 OpenAPI 的 `{param}` 形式；
+//   - Builder 可被多个资源注册过程共享，并发安全；
+//   - Document 每次返回新的文档快照，调用方修改返回值不会影响 Builder 内部状态。
+type Builder struct {
+	mu      sync.RWMutex
+	info    Info
+	servers []Server
+	tags    map[string]Tag
+	paths   map[string]*PathItem
+	schemas *SchemaRegistry
+}
+
+// NewBuilder 创建 OpenAPI 文档构建器。
+func NewBuilder(info Info) *Builder {
+	if info.Title == "" {
+		info.Title = "API"
+	}
+	if info.Version == "" {
+		info.Version = "1.0.0"
+	}
+	return &Builder{
+		info:    info,
+		tags:    make(map[string]Tag),
+		paths:   make(map[string]*PathItem),
+		schemas: NewSchemaRegistry(),
+	}
+}
+
+// AddServer 追加一个服务地址声明。
+func (b *Builder) AddServer(server Server) *Builder {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.servers = append(b.servers, server)
+	return b
+}
+
+// AddTag 声明一个操作分组标签。
+func (b *Builder) AddTag(tag Tag) *Builder {
+	if tag.Name == "" {
+		return b
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tags[tag.Name] = tag
+	return b
+}
+
+// SchemaFor 推导 Go 类型的 schema，并把具名 struct 注册到 components.schemas。
+func (b *Builder) SchemaFor(typ reflect.Type) *Schema {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.schemas.SchemaFor(typ)
+}
+
+// PutSchema 以指定名称注册手写组件 schema。
+func (b *Builder) PutSchema(name string, schema *Schema) *Builder {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.schemas.Put(name, schema)
+	return b
+}
+
+// AddOperation 在指定方法与路径下注册一个操作。
+//
+// 说明：同一 method+path 重复注册会返回 Conflict，避免多个资源静默覆盖彼此的文档。
+func (b *Builder) AddOperation(method, path string, op *Operation) error {
+	if op == nil {
+		return errors.NewCode(errors.InvalidInput, "openapi operation cannot be nil")
+	}
+	method = strings.ToUpper(strings.TrimSpace(method))
+	path = NormalizePath(path)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	item, ok := b.paths[path]
+	if !ok {
+		item = &PathItem{}
+		b.paths[path] = item
+	}
+	slot := item.operation(method)
+	if slot == nil {
+		return errors.NewCode(errors.InvalidInput, "unknown http method").
+			WithContext("method", method).
+			WithContext("path", path)
+	}
+	if *slot != nil {
+		return errors.NewCode(errors.Conflict, "openapi operation already registered").
+			WithContext("method", method).
+			WithContext("path", path)
+	}
+	if op.Responses == nil {
+		op.Responses = map[string]*Response{"200": {Description: "OK"}}
+	}
+	for _, tag := range op.Tags {
+		if _, exists := b.tags[tag]; !exists {
+			b.tags[tag] = Tag{Name: tag}
+		}
+	}
+	*slot = op
+	return nil
+}
+
+// Document 返回当前已收集内容对应的 OpenAPI 文档。
+func (b *Builder) Document() *Document {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    b.info,
+		Servers: append([]Server(nil), b.servers...),
+		Paths:   make(map[string]*PathItem, len(b.paths)),
+	}
+	for path, item := range b.paths {
+		copied := *item
+		doc.Paths[path] = &copied
+	}
+	if schemas := b.schemas.Schemas(); len(schemas) > 0 {
+		doc.Components = &Components{Schemas: schemas}
+	}
+	if len(b.tags) > 0 {
+		names := make([]string, 0, len(b.tags))
+		for name := range b.tags {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			doc.Tags = append(doc.Tags, b.tags[name])
+		}
+	}
+	return doc
+}
+
+var colonParamPattern = regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_]*)`)
+
+// NormalizePath 把 `:param` 风格路径转换为 OpenAPI 的 `{param}` 风格，并补齐前导斜杠。
+func NormalizePath(path string) string {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return colonParamPattern.ReplaceAllString(path, "{$1}")
+}
+
+// JoinPath 拼接路由前缀与相对路径。
+func JoinPath(prefix, path string) string {
+	prefix = strings.TrimRight(strings.TrimSpace(prefix), "/")
+	path = strings.TrimSpace(path)
+	if path == "" {
+		if prefix == "" {
+			return "/"
+		}
+		return prefix
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return prefix + path
+}
+
+var _ ISpecProvider = (*Builder)(nil)
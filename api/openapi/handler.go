@@ -0,0 +1,111 @@
+package openapi
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+// DefaultSpecPath 是 OpenAPI JSON 文档的默认挂载路径。
+const DefaultSpecPath = "/openapi.json"
+
+// DefaultSwaggerUIAssetsURL 是 Swagger UI 静态资源的默认 CDN 前缀。
+const DefaultSwaggerUIAssetsURL = "https://unpkg.com/swagger-ui-dist@5"
+
+// MountConfig 定义 OpenAPI 文档路由的挂载配置。
+type MountConfig struct {
+	// SpecPath 是 JSON 文档路径；为空时使用 DefaultSpecPath。
+	SpecPath string
+
+	// UIPath 是 Swagger UI 页面路径；为空表示不挂载 UI。
+	UIPath string
+
+	// UIAssetsURL 是 Swagger UI 静态资源前缀；为空时使用 DefaultSwaggerUIAssetsURL。
+	//
+	// 说明：内网/离线环境可指向自托管的 swagger-ui-dist 目录。
+	UIAssetsURL string
+
+	// UISpecURL 是 UI 页面加载文档使用的 URL；为空时回退到 SpecPath。
+	//
+	// 说明：当路由组带前缀（如 `/api`）时，需要显式设置为浏览器可访问的完整路径。
+	UISpecURL string
+}
+
+// Mount 把 OpenAPI 文档（以及可选的 Swagger UI）注册到路由组。
+func Mount(group httpx.IRouteGroup, provider ISpecProvider, cfg *MountConfig) error {
+	if group == nil {
+		return errors.NewCode(errors.InvalidInput, "route group cannot be nil")
+	}
+	if provider == nil {
+		return errors.NewCode(errors.InvalidInput, "openapi spec provider cannot be nil")
+	}
+	config := MountConfig{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if strings.TrimSpace(config.SpecPath) == "" {
+		config.SpecPath = DefaultSpecPath
+	}
+
+	group.GET(config.SpecPath, SpecHandler(provider))
+	if strings.TrimSpace(config.UIPath) != "" {
+		specURL := config.UISpecURL
+		if specURL == "" {
+			specURL = config.SpecPath
+		}
+		group.GET(config.UIPath, SwaggerUIHandler(specURL, config.UIAssetsURL))
+	}
+	return nil
+}
+
+// SpecHandler 返回输出 OpenAPI JSON 文档的 handler。
+func SpecHandler(provider ISpecProvider) httpx.Handler {
+	return func(c httpx.IContext) error {
+		payload, err := json.Marshal(provider.Document())
+		if err != nil {
+			return errors.Wrap(err, errors.Internal, "failed to encode openapi document")
+		}
+		return c.Data(http.StatusOK, "application/json; charset=utf-8", payload)
+	}
+}
+
+var swaggerUITemplate = template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>API Docs</title>
+<link rel="stylesheet" href="{{.Assets}}/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="{{.Assets}}/swagger-ui-bundle.js"></script>
+<script>
+window.onload = function () {
+  window.ui = SwaggerUIBundle({ url: {{.SpecURL}}, dom_id: "#swagger-ui" });
+};
+</script>
+</body>
+</html>
+`))
+
+// SwaggerUIHandler 返回渲染 Swagger UI 页面的 handler。
+func SwaggerUIHandler(specURL, assetsURL string) httpx.Handler {
+	if strings.TrimSpace(assetsURL) == "" {
+		assetsURL = DefaultSwaggerUIAssetsURL
+	}
+	assetsURL = strings.TrimRight(assetsURL, "/")
+	return func(c httpx.IContext) error {
+		var sb strings.Builder
+		if err := swaggerUITemplate.Execute(&sb, struct {
+			Assets  string
+			SpecURL string
+		}{Assets: assetsURL, SpecURL: specURL}); err != nil {
+			return errors.Wrap(err, errors.Internal, "failed to render swagger ui")
+		}
+		return c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(sb.String()))
+	}
+}
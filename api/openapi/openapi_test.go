@@ -0,0 +1,153 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gochen/errors"
+	"gochen/httpx/httpxtest"
+)
+
+type address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type base struct {
+	ID int64 `json:"id" openapi:"readonly"`
+}
+
+type user struct {
+	base
+	Name      string            `json:"name" validate:"required,min=2,max=32" doc:"display name"`
+	Role      string            `json:"role" validate:"oneof=admin user"`
+	Age       *int              `json:"age,omitempty" validate:"min=0"`
+	Tags      []string          `json:"tags"`
+	Labels    map[string]string `json:"labels"`
+	CreatedAt time.Time         `json:"created_at"`
+	Address   address           `json:"address"`
+	Parent    *user             `json:"parent,omitempty"`
+	Secret    string            `json:"-"`
+	Internal  string            `json:"internal" openapi:"-"`
+	hidden    string
+}
+
+// TestSchemaRegistry_InfersStructTags 验证 schema 推导遵循 json/validate/doc/openapi tag。
+func TestSchemaRegistry_InfersStructTags(t *testing.T) {
+	reg := NewSchemaRegistry()
+	ref := reg.SchemaFor(reflect.TypeFor[*user]())
+	if ref.Ref != "#/components/schemas/user" {
+		t.Fatalf("expected $ref to user, got %+v", ref)
+	}
+
+	schema := reg.Schemas()["user"]
+	if schema == nil {
+		t.Fatalf("expected user component")
+	}
+	for _, name := range []string{"id", "name", "role", "age", "tags", "labels", "created_at", "address", "parent"} {
+		if schema.Properties[name] == nil {
+			t.Fatalf("expected property %q, got %v", name, schema.Properties)
+		}
+	}
+	for _, name := range []string{"Secret", "internal", "hidden", "base"} {
+		if schema.Properties[name] != nil {
+			t.Fatalf("unexpected property %q", name)
+		}
+	}
+	if !schema.Properties["id"].ReadOnly {
+		t.Fatalf("expected id readOnly")
+	}
+	name := schema.Properties["name"]
+	if name.Description != "display name" || *name.MinLength != 2 || *name.MaxLength != 32 {
+		t.Fatalf("unexpected name schema %+v", name)
+	}
+	if len(schema.Properties["role"].Enum) != 2 {
+		t.Fatalf("expected role enum, got %+v", schema.Properties["role"])
+	}
+	if age := schema.Properties["age"]; !age.Nullable || age.Type != "integer" || *age.Minimum != 0 {
+		t.Fatalf("unexpected age schema %+v", age)
+	}
+	if ts := schema.Properties["created_at"]; ts.Type != "string" || ts.Format != "date-time" {
+		t.Fatalf("unexpected time schema %+v", ts)
+	}
+	if schema.Properties["parent"].Ref != "#/components/schemas/user" {
+		t.Fatalf("expected self reference, got %+v", schema.Properties["parent"])
+	}
+	if len(schema.Required) != 1 || schema.Required[0] != "name" {
+		t.Fatalf("unexpected required %v", schema.Required)
+	}
+	if reg.Schemas()["address"] == nil {
+		t.Fatalf("expected nested address component")
+	}
+}
+
+// TestBuilder_AddOperationNormalizesPathAndRejectsDuplicates 验证路径规范化与重复注册冲突。
+func TestBuilder_AddOperationNormalizesPathAndRejectsDuplicates(t *testing.T) {
+	b := NewBuilder(Info{Title: "demo"})
+	if err := b.AddOperation("get", "users/:id", &Operation{Tags: []string{"users"}}); err != nil {
+		t.Fatalf("AddOperation returned error: %v", err)
+	}
+	err := b.AddOperation("GET", "/users/:id", &Operation{})
+	if !errors.Is(err, errors.Conflict) {
+		t.Fatalf("expected conflict, got %v", err)
+	}
+	if err := b.AddOperation("TRACE", "/users", &Operation{}); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected invalid input for unknown method, got %v", err)
+	}
+
+	doc := b.Document()
+	item := doc.Paths["/users/{id}"]
+	if item == nil || item.Get == nil {
+		t.Fatalf("expected normalized path, got %v", doc.Paths)
+	}
+	if item.Get.Responses["200"] == nil {
+		t.Fatalf("expected default 200 response")
+	}
+	if doc.OpenAPI != Version || doc.Info.Version == "" {
+		t.Fatalf("unexpected document header %+v", doc)
+	}
+	if len(doc.Tags) != 1 || doc.Tags[0].Name != "users" {
+		t.Fatalf("expected users tag, got %+v", doc.Tags)
+	}
+}
+
+// TestMount_ServesSpecAndSwaggerUI 验证文档与 Swagger UI 路由挂载。
+func TestMount_ServesSpecAndSwaggerUI(t *testing.T) {
+	b := NewBuilder(Info{Title: "demo", Version: "2"})
+	if err := b.AddOperation("GET", "/ping", &Operation{Summary: "ping"}); err != nil {
+		t.Fatalf("AddOperation returned error: %v", err)
+	}
+
+	group := httpxtest.NewMockRouteGroup()
+	if err := Mount(group, b, &MountConfig{UIPath: "/docs"}); err != nil {
+		t.Fatalf("Mount returned error: %v", err)
+	}
+
+	rec := httpxtest.Serve(t, group.Handler(t, http.MethodGet, "/openapi.json"), httptest.NewRequest(http.MethodGet, "/openapi.json", nil), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var doc Document
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshal spec: %v", err)
+	}
+	if doc.Info.Version != "2" || doc.Paths["/ping"] == nil {
+		t.Fatalf("unexpected spec %+v", doc)
+	}
+
+	rec = httpxtest.Serve(t, group.Handler(t, http.MethodGet, "/docs"), httptest.NewRequest(http.MethodGet, "/docs", nil), nil)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "SwaggerUIBundle") {
+		t.Fatalf("expected swagger ui page, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"/openapi.json"`) {
+		t.Fatalf("expected spec url in page, got %s", rec.Body.String())
+	}
+
+	if err := Mount(nil, b, nil); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected invalid input for nil group, got %v", err)
+	}
+}
@@ -0,0 +1,332 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	rawMessageType    = reflect.TypeOf(json.RawMessage(nil))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// SchemaRegistry 负责把 Go 类型推导为 schema，并把具名 struct 收敛到 components.schemas。
+//
+// 说明：
+//   - 字段名遵循 `json` tag（`json:"-"` 跳过）；
+//   - `validate` tag 中的 required/min/max/len/oneof 会映射为 required/范围/枚举约束；
+//   - `doc` tag 作为字段描述；`openapi` tag 支持 `-`、`readonly`、`format=...`、`enum=a|b`；
+//   - 同名类型冲突时后注册的类型会追加包名前缀，避免互相覆盖。
+type SchemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+// NewSchemaRegistry 创建空的 schema 注册表。
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// SchemaFor 推导类型对应的 schema；具名 struct 返回 `$ref` 引用。
+func (r *SchemaRegistry) SchemaFor(typ reflect.Type) *Schema {
+	return r.schemaFor(typ)
+}
+
+// Schemas 返回已注册的组件 schema（浅拷贝）。
+func (r *SchemaRegistry) Schemas() map[string]*Schema {
+	out := make(map[string]*Schema, len(r.schemas))
+	for name, schema := range r.schemas {
+		out[name] = schema
+	}
+	return out
+}
+
+// Put 以指定名称注册一个手写 schema。
+func (r *SchemaRegistry) Put(name string, schema *Schema) {
+	if name == "" || schema == nil {
+		return
+	}
+	r.schemas[name] = schema
+}
+
+func (r *SchemaRegistry) schemaFor(typ reflect.Type) *Schema {
+	if typ == nil {
+		return &Schema{}
+	}
+	nullable := false
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+		nullable = true
+	}
+
+	schema := r.baseSchema(typ)
+	if nullable && schema.Ref == "" {
+		schema.Nullable = true
+	}
+	return schema
+}
+
+func (r *SchemaRegistry) baseSchema(typ reflect.Type) *Schema {
+	switch typ {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Format: "int64"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schemaFor(typ.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaFor(typ.Elem())}
+	case reflect.Struct:
+		return r.structSchema(typ)
+	default:
+		// interface/any 等无法静态推导的类型保持开放 schema。
+		return &Schema{}
+	}
+}
+
+func (r *SchemaRegistry) structSchema(typ reflect.Type) *Schema {
+	// 自定义 JSON 编码的类型无法从字段推导真实结构，按开放 schema 处理。
+	if typ.Implements(jsonMarshalerType) || reflect.PointerTo(typ).Implements(jsonMarshalerType) {
+		return &Schema{}
+	}
+	if typ.Name() == "" {
+		return r.buildObject(typ)
+	}
+	if name, ok := r.names[typ]; ok {
+		return RefSchema(name)
+	}
+
+	name := r.componentName(typ)
+	r.names[typ] = name
+	// 先占位再展开字段，保证自引用类型可以终止递归。
+	r.schemas[name] = &Schema{Type: "object"}
+	r.schemas[name] = r.buildObject(typ)
+	return RefSchema(name)
+}
+
+func (r *SchemaRegistry) componentName(typ reflect.Type) string {
+	name := sanitizeComponentName(typ.Name())
+	if _, exists := r.schemas[name]; !exists {
+		return name
+	}
+	pkg := typ.PkgPath()
+	if idx := strings.LastIndex(pkg, "/"); idx >= 0 {
+		pkg = pkg[idx+1:]
+	}
+	qualified := sanitizeComponentName(pkg + "." + typ.Name())
+	candidate := qualified
+	for i := 2; ; i++ {
+		if _, exists := r.schemas[candidate]; !exists {
+			return candidate
+		}
+		candidate = qualified + strconv.Itoa(i)
+	}
+}
+
+// sanitizeComponentName 把泛型实例化名称等转换为合法的组件名。
+func sanitizeComponentName(name string) string {
+	var sb strings.Builder
+	for _, ch := range name {
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9', ch == '.', ch == '_', ch == '-':
+			sb.WriteRune(ch)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+	return strings.Trim(sb.String(), "_")
+}
+
+func (r *SchemaRegistry) buildObject(typ reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	r.collectFields(typ, schema)
+	if len(schema.Properties) == 0 {
+		schema.Properties = nil
+	}
+	return schema
+}
+
+func (r *SchemaRegistry) collectFields(typ reflect.Type, schema *Schema) {
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		name, skip := jsonFieldName(sf)
+		if skip {
+			continue
+		}
+		hints := parseFieldHints(sf)
+		if hints.skip {
+			continue
+		}
+
+		fieldType := sf.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if sf.Anonymous && !hasExplicitJSONName(sf) && fieldType.Kind() == reflect.Struct && fieldType != timeType {
+			r.collectFields(fieldType, schema)
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		prop := r.schemaFor(sf.Type)
+		applyFieldHints(prop, hints)
+		schema.Properties[name] = prop
+		if hints.required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func jsonFieldName(sf reflect.StructField) (name string, skip bool) {
+	raw, ok := sf.Tag.Lookup("json")
+	if !ok {
+		return sf.Name, false
+	}
+	parts := strings.Split(raw, ",")
+	name = strings.TrimSpace(parts[0])
+	if name == "-" && len(parts) == 1 {
+		return "", true
+	}
+	if name == "" {
+		name = sf.Name
+	}
+	return name, false
+}
+
+func hasExplicitJSONName(sf reflect.StructField) bool {
+	raw, ok := sf.Tag.Lookup("json")
+	if !ok {
+		return false
+	}
+	return strings.TrimSpace(strings.Split(raw, ",")[0]) != ""
+}
+
+type fieldHints struct {
+	skip        bool
+	required    bool
+	readOnly    bool
+	description string
+	format      string
+	enum        []any
+	min         *float64
+	max         *float64
+}
+
+func parseFieldHints(sf reflect.StructField) fieldHints {
+	hints := fieldHints{description: strings.TrimSpace(sf.Tag.Get("doc"))}
+
+	for _, rule := range strings.Split(sf.Tag.Get("validate"), ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch key {
+		case "required":
+			hints.required = true
+		case "min", "gte":
+			hints.min = parseFloat(value)
+		case "max", "lte":
+			hints.max = parseFloat(value)
+		case "len":
+			hints.min = parseFloat(value)
+			hints.max = parseFloat(value)
+		case "oneof":
+			for _, item := range strings.Fields(value) {
+				hints.enum = append(hints.enum, item)
+			}
+		}
+	}
+
+	raw := strings.TrimSpace(sf.Tag.Get("openapi"))
+	if raw == "-" {
+		hints.skip = true
+		return hints
+	}
+	for _, opt := range strings.Split(raw, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(opt), "=")
+		switch key {
+		case "readonly":
+			hints.readOnly = true
+		case "required":
+			hints.required = true
+		case "format":
+			hints.format = value
+		case "enum":
+			hints.enum = hints.enum[:0]
+			for _, item := range strings.Split(value, "|") {
+				if item = strings.TrimSpace(item); item != "" {
+					hints.enum = append(hints.enum, item)
+				}
+			}
+		}
+	}
+	return hints
+}
+
+func parseFloat(raw string) *float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
+
+func applyFieldHints(prop *Schema, hints fieldHints) {
+	// OpenAPI 3.0 中 $ref 的兄弟属性会被忽略，引用字段不再附加约束。
+	if prop.Ref != "" {
+		return
+	}
+	if hints.description != "" {
+		prop.Description = hints.description
+	}
+	if hints.readOnly {
+		prop.ReadOnly = true
+	}
+	if hints.format != "" {
+		prop.Format = hints.format
+	}
+	if len(hints.enum) > 0 {
+		prop.Enum = append([]any(nil), hints.enum...)
+	}
+	switch prop.Type {
+	case "string":
+		if hints.min != nil {
+			n := int(*hints.min)
+			prop.MinLength = &n
+		}
+		if hints.max != nil {
+			n := int(*hints.max)
+			prop.MaxLength = &n
+		}
+	case "integer", "number":
+		prop.Minimum = hints.min
+		prop.Maximum = hints.max
+	}
+}
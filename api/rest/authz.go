@@ -8,6 +8,7 @@ import (
 	appcrud "gochen/app/crud"
 	auth "gochen/auth"
 	"gochen/domain/access"
+	"gochen/domain/crud"
 	"gochen/errors"
 	"gochen/httpx"
 )
@@ -87,7 +88,7 @@ func (rb *RouteBuilder[T, ID]) validateAuthorizationConfig() error {
 	if cfg == nil {
 		return nil
 	}
-	if cfg.Authorizer == nil {
+	if cfg.Authorizer == nil && hasAnyPermission(cfg.Permissions) {
 		return errors.NewCode(errors.InvalidInput, "RouteConfig.Authorization.Authorizer is required")
 	}
 	if rb.hasWriteAuthorization() {
@@ -116,6 +117,81 @@ func (rb *RouteBuilder[T, ID]) validateAuthorizationConfig() error {
 	return nil
 }
 
+// hasAnyPermission 判断是否配置了任一 CRUD 权限码。
+func hasAnyPermission(perms CRUDPermissions) bool {
+	for _, permission := range []string{perms.List, perms.Get, perms.Create, perms.Update, perms.Delete} {
+		if strings.TrimSpace(permission) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredRoles 返回路由类型对应的角色要求；批量与审计路由复用对应 CRUD 操作的角色。
+func (rb *RouteBuilder[T, ID]) requiredRoles(kind RouteKind) []string {
+	cfg := rb.authzConfig()
+	if cfg == nil {
+		return nil
+	}
+	switch kind {
+	case RouteKindList, RouteKindListDeleted, RouteKindTrash, RouteKindExport:
+		return cfg.Roles.List
+	case RouteKindGet, RouteKindAuditTrail, RouteKindChangeHistory:
+		return cfg.Roles.Get
+	case RouteKindCreate, RouteKindBatchCreate, RouteKindImport:
+		return cfg.Roles.Create
+	case RouteKindUpdate, RouteKindPatch, RouteKindBatchUpdate, RouteKindRestore:
+		return cfg.Roles.Update
+	case RouteKindDelete, RouteKindBatchDelete, RouteKindPurge:
+		return cfg.Roles.Delete
+	}
+	return nil
+}
+
+// requireRoles 在 handler 前校验角色要求；未配置角色的路由原样返回 handler。
+//
+// 校验放在路由中间件链内侧执行，确保认证中间件（如 authhttp.BearerAuthMiddleware）已绑定主体。
+func (rb *RouteBuilder[T, ID]) requireRoles(kind RouteKind, handler func(httpx.IContext) error) func(httpx.IContext) error {
+	if len(rb.trimmedRoles(kind)) == 0 {
+		return handler
+	}
+	return func(c httpx.IContext) error {
+		if err := rb.checkRoles(c.RequestContext(), kind); err != nil {
+			return err
+		}
+		return handler(c)
+	}
+}
+
+// trimmedRoles 返回去除空白项后的角色要求。
+func (rb *RouteBuilder[T, ID]) trimmedRoles(kind RouteKind) []string {
+	roles := make([]string, 0, len(rb.requiredRoles(kind)))
+	for _, role := range rb.requiredRoles(kind) {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// checkRoles 基于 ctx 中的主体校验角色要求：缺少主体返回 Unauthorized，未命中任一角色返回 Forbidden。
+func (rb *RouteBuilder[T, ID]) checkRoles(ctx context.Context, kind RouteKind) error {
+	roles := rb.trimmedRoles(kind)
+	if len(roles) == 0 {
+		return nil
+	}
+	principal, err := auth.RequirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	if !principal.IsSystem && !principal.HasAnyRole(roles...) {
+		return errors.NewCode(errors.Forbidden, "required role missing").
+			WithContext("route_kind", string(kind)).
+			WithContext("roles", roles)
+	}
+	return nil
+}
+
 // authorize 在调用 Authorizer 之前补齐请求上下文，并强制要求决策结果为 allow。
 func (rb *RouteBuilder[T, ID]) authorize(
 	c httpx.IContext,
@@ -259,7 +335,7 @@ func (rb *RouteBuilder[T, ID]) resourceBoundaryRepository() (access.IResourceBou
 	if !ok {
 		return nil, false
 	}
-	repo, ok := provider.Repository().(access.IResourceBoundaryRepository[T, ID])
+	repo, ok := crud.RepositoryAs[access.IResourceBoundaryRepository[T, ID]](provider.Repository())
 	return repo, ok
 }
 
@@ -7,12 +7,12 @@ import (
 	"strings"
 	"testing"
 
-	"gochen/api/rest/internal/testutil"
 	appcrud "gochen/app/crud"
 	auth "gochen/auth"
 	"gochen/contextx"
 	"gochen/domain/access"
 	"gochen/errors"
+	"gochen/httpx/httpxtest"
 	"gochen/httpx/nethttp"
 )
 
@@ -273,7 +273,7 @@ func TestRouteBuilder_Create_AuthorizesAndUsesWriteConstraint(t *testing.T) {
 		cfg.Routing.BasePath = "/items"
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -326,7 +326,7 @@ func TestRouteBuilder_Update_AuthorizesAndUsesWriteConstraint(t *testing.T) {
 		cfg.Routing.BasePath = "/items"
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -377,7 +377,7 @@ func TestRouteBuilder_Delete_AuthorizesAndUsesWriteConstraint(t *testing.T) {
 		cfg.Routing.BasePath = "/items"
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -421,7 +421,7 @@ func TestRouteBuilder_Get_AuthorizationDenyStopsResponse(t *testing.T) {
 		cfg.Routing.BasePath = "/items"
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -444,6 +444,71 @@ func TestRouteBuilder_Get_AuthorizationDenyStopsResponse(t *testing.T) {
 	}
 }
 
+func TestRouteBuilder_Roles_EnforcedPerOperation(t *testing.T) {
+	svc := newStubAppService(nil)
+	svc.loadedEntity = &fakeEntity{ID: 9, Version: 1, Name: "demo"}
+
+	// 只声明角色、不声明权限码时无需 Authorizer。
+	builder, err := NewApiBuilder[*fakeEntity, int64](svc, WithRoles[*fakeEntity, int64](CRUDRoles{Get: []string{"viewer"}}))
+	if err != nil {
+		t.Fatalf("NewApiBuilder returned error: %v", err)
+	}
+	builder.Route(func(cfg *RouteConfig[int64]) {
+		cfg.Routing.BasePath = "/items"
+	})
+
+	group := httpxtest.NewMockRouteGroup()
+	if err := builder.Build(group); err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	handler := group.Handlers["GET /items/:id"]
+	cases := []struct {
+		name      string
+		principal *auth.Principal
+		status    int
+	}{
+		{name: "anonymous", status: http.StatusUnauthorized},
+		{name: "missing role", principal: &auth.Principal{SubjectID: 99, Roles: []string{"editor"}}, status: http.StatusForbidden},
+		{name: "matching role", principal: &auth.Principal{SubjectID: 99, Roles: []string{"Viewer"}}, status: http.StatusOK},
+		{name: "system", principal: &auth.Principal{SubjectID: 1, IsSystem: true}, status: http.StatusOK},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		ctx, err := nethttp.NewBaseContext(w, httptest.NewRequest(http.MethodGet, "/items/9", nil))
+		if err != nil {
+			t.Fatalf("NewBaseContext returned error: %v", err)
+		}
+		ctx.SetParam("id", "9")
+		if tc.principal != nil {
+			derived, err := auth.WithPrincipal(ctx.RequestContext(), *tc.principal)
+			if err != nil {
+				t.Fatalf("WithPrincipal: %v", err)
+			}
+			ctx.SetContext(ctx.RequestContext().WithContext(derived))
+		}
+		if err := handler(ctx); err != nil {
+			t.Fatalf("%s: handler returned error: %v", tc.name, err)
+		}
+		if w.Code != tc.status {
+			t.Fatalf("%s: expected status %d, got %d", tc.name, tc.status, w.Code)
+		}
+	}
+
+	// 未声明角色的操作不受影响。
+	w := httptest.NewRecorder()
+	ctx, err := nethttp.NewBaseContext(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+	if err != nil {
+		t.Fatalf("NewBaseContext returned error: %v", err)
+	}
+	if err := group.Handlers["GET /items"](ctx); err != nil {
+		t.Fatalf("list handler returned error: %v", err)
+	}
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected list status 200, got %d", w.Code)
+	}
+}
+
 func TestRouteBuilder_Build_FailsFastWhenRepoLacksWriteConstraintSupport(t *testing.T) {
 	svc := newStubAppService(nil)
 	svc.repository = &writeConstraintValidatingRepo{err: errors.NewCode(errors.Unsupported, "missing result support")}
@@ -459,7 +524,7 @@ func TestRouteBuilder_Build_FailsFastWhenRepoLacksWriteConstraintSupport(t *test
 		cfg.Routing.BasePath = "/items"
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	err = builder.Build(group)
 	if err == nil {
 		t.Fatalf("expected build to fail")
@@ -486,7 +551,7 @@ func TestRouteBuilder_BatchRoutes_AuthorizeAndUseWriteConstraints(t *testing.T)
 		cfg.Routing.BasePath = "/items"
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -605,7 +670,7 @@ func TestRouteBuilder_BatchRoutes_AllowConstrainedOnlyService(t *testing.T) {
 		cfg.Routing.BasePath = "/items"
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -654,7 +719,7 @@ func TestRouteBuilder_Get_UsesResourceBoundaryFirstHop(t *testing.T) {
 		t.Fatalf("NewApiBuilder returned error: %v", err)
 	}
 	builder.Route(func(cfg *RouteConfig[int64]) { cfg.Routing.BasePath = "/items" })
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -701,7 +766,7 @@ func TestRouteBuilder_Update_UsesResourceBoundaryFirstHop(t *testing.T) {
 		t.Fatalf("NewApiBuilder returned error: %v", err)
 	}
 	builder.Route(func(cfg *RouteConfig[int64]) { cfg.Routing.BasePath = "/items" })
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -752,7 +817,7 @@ func TestRouteBuilder_Delete_UsesResolvedResourceForAuthorization(t *testing.T)
 		t.Fatalf("NewApiBuilder returned error: %v", err)
 	}
 	builder.Route(func(cfg *RouteConfig[int64]) { cfg.Routing.BasePath = "/items" })
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -810,7 +875,7 @@ func TestRouteBuilder_DeleteBatch_UsesResolvedResourcesAndScopedWrites(t *testin
 		cfg.Routing.BasePath = "/items"
 		cfg.Routing.EnableBatch = true
 	})
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -862,7 +927,7 @@ func TestRouteBuilder_Create_AuthorizationConfigPropagatesConsistency(t *testing
 		cfg.Authorization.Consistency = auth.ConsistencyModeBoundedStaleness
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -971,7 +1036,7 @@ func TestRouteBuilder_Get_PinsSnapshotBeforeInitialRead(t *testing.T) {
 		cfg.Routing.BasePath = "/items"
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -1026,7 +1091,7 @@ func TestRouteBuilder_Get_FailsClosedWhenInitialSnapshotBindingFails(t *testing.
 		cfg.Routing.BasePath = "/items"
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -1077,7 +1142,7 @@ func TestRouteBuilder_Create_HighRiskBoundedStalenessFailsClosed(t *testing.T) {
 		cfg.Authorization.HighRisk = true
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
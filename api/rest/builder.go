@@ -2,6 +2,7 @@
 package rest
 
 import (
+	"gochen/api/openapi"
 	appcrud "gochen/app/crud"
 	auth "gochen/auth"
 	"gochen/db/query"
@@ -49,6 +50,9 @@ type ApiBuilder[T domain.IEntity[ID], ID comparable] struct {
 	service       any
 	validator     validate.IValidator
 	hooks         *appcrud.Hooks[T, ID]
+
+	openapi        *openapi.Builder
+	openapiOptions *OpenAPIOptions
 }
 
 // NewApiBuilder 创建一个可继续配置的 CRUD API 构建器。
@@ -157,7 +161,10 @@ func (rb *ApiBuilder[T, ID]) Build(group httpx.IRouteGroup) error {
 	}
 
 	// 注册到路由组
-	return routeBuilder.Register(group)
+	if err := routeBuilder.Register(group); err != nil {
+		return err
+	}
+	return DescribeOpenAPI[T, ID](rb.openapi, routeBuilder.Routes(), rb.routeConfig, rb.openapiOptions)
 }
 
 // Register 是一层便捷封装：创建 builder 后立刻完成路由注册。
@@ -187,6 +194,30 @@ func WithBatchOperations[T domain.IEntity[ID], ID comparable](maxSize int) Optio
 	}
 }
 
+// WithImportExport 启用 CSV/JSONL 导入与导出路由；maxRows > 0 时同时作为导入与导出的行数上限。
+func WithImportExport[T domain.IEntity[ID], ID comparable](maxRows int) Option[T, ID] {
+	return func(rb *ApiBuilder[T, ID]) {
+		rb.Route(func(config *RouteConfig[ID]) {
+			config.Routing.EnableImport = true
+			config.Routing.EnableExport = true
+			if maxRows > 0 {
+				config.Transfer.MaxImportRows = maxRows
+				config.Transfer.MaxExportRows = maxRows
+			}
+		})
+	}
+}
+
+// WithPatch 启用局部更新路由（PATCH /:id）；fields 非空时作为可修改字段白名单。
+func WithPatch[T domain.IEntity[ID], ID comparable](fields ...string) Option[T, ID] {
+	return func(rb *ApiBuilder[T, ID]) {
+		rb.Route(func(config *RouteConfig[ID]) {
+			config.Routing.EnablePatch = true
+			config.Patch.AllowedFields = fields
+		})
+	}
+}
+
 // WithPagination 启用分页列表路由，并配置默认/最大 page size。
 //
 // 参数：
@@ -271,10 +302,25 @@ func WithAuthorization[T domain.IEntity[ID], ID comparable](
 ) Option[T, ID] {
 	return func(rb *ApiBuilder[T, ID]) {
 		rb.Route(func(cfg *RouteConfig[ID]) {
-			cfg.Authorization = &AuthorizationConfig{
-				Authorizer:  authorizer,
-				Permissions: permissions,
+			if cfg.Authorization == nil {
+				cfg.Authorization = &AuthorizationConfig{}
+			}
+			cfg.Authorization.Authorizer = authorizer
+			cfg.Authorization.Permissions = permissions
+		})
+	}
+}
+
+// WithRoles 为标准 CRUD 路由声明按操作的角色要求。
+//
+// 可与 WithAuthorization 组合使用（顺序无关）：两者都配置时，先校验角色，再执行权限授权。
+func WithRoles[T domain.IEntity[ID], ID comparable](roles CRUDRoles) Option[T, ID] {
+	return func(rb *ApiBuilder[T, ID]) {
+		rb.Route(func(cfg *RouteConfig[ID]) {
+			if cfg.Authorization == nil {
+				cfg.Authorization = &AuthorizationConfig{}
 			}
+			cfg.Authorization.Roles = roles
 		})
 	}
 }
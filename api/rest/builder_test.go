@@ -11,7 +11,6 @@ import (
 	"testing"
 	"time"
 
-	"gochen/api/rest/internal/testutil"
 	appaudited "gochen/app/audited"
 	appcrud "gochen/app/crud"
 	auth "gochen/auth"
@@ -21,6 +20,7 @@ import (
 	"gochen/domain/audited"
 	"gochen/domain/crud"
 	"gochen/httpx"
+	"gochen/httpx/httpxtest"
 	"gochen/httpx/nethttp"
 	"gochen/validate"
 )
@@ -158,7 +158,7 @@ func TestRestfulBuilderAppliesServiceConfig(t *testing.T) {
 		cfg.MaxBatchSize = 77
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -188,7 +188,7 @@ func TestApiBuilder_AutoInfersQuerySchema(t *testing.T) {
 		cfg.Query.EnablePagination = false
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -248,7 +248,7 @@ func TestApiBuilder_ExplicitQuerySchemaWinsOverAutoInfer(t *testing.T) {
 		cfg.Query.QuerySchema = manual
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -285,7 +285,7 @@ func TestApiBuilder_QuerySchemaInferOptionsForceInference(t *testing.T) {
 		}
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -321,7 +321,7 @@ func TestApiBuilder_AllowsListOnlyService(t *testing.T) {
 		cfg.Routing.EnableBatch = false
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -548,7 +548,7 @@ func TestApiBuilder_FailsFastWhenEntityIsAuditedButServiceNotAudited(t *testing.
 	if err != nil {
 		t.Fatalf("NewApiBuilder returned error: %v", err)
 	}
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 
 	buildErr := builder.Build(group)
 	if buildErr == nil {
@@ -572,7 +572,7 @@ func TestApiBuilder_RegistersAuditedRoutesWhenEntityIsAudited(t *testing.T) {
 		cfg.Audit.OperatorExtractor = func(httpx.IContext) (string, bool) { return "tester", true }
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -671,6 +671,12 @@ func (s *stubAppService) Update(ctx context.Context, _ *fakeEntity) error {
 	return nil
 }
 
+// Patch 局部更新数据。
+func (s *stubAppService) Patch(ctx context.Context, _ *fakeEntity, _ []string) error {
+	s.lastCtx = ctx
+	return nil
+}
+
 // Delete 删除数据。
 //
 // 参数：
@@ -971,7 +977,7 @@ func TestRouteBuilderMiddlewareChain(t *testing.T) {
 		})
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -1016,7 +1022,7 @@ func TestRouteBuilder_OperatorExtractorInjectsOperatorIntoContext(t *testing.T)
 		}
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -1068,7 +1074,7 @@ func TestRestfulBuilderAppliesHooks(t *testing.T) {
 		h.BeforeCreate = func(ctx context.Context, entity *fakeEntity) error { return nil }
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -1101,7 +1107,7 @@ func TestServiceConfigOverride(t *testing.T) {
 		cfg.MaxBatchSize = 99
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
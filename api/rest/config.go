@@ -19,10 +19,28 @@ type CRUDPermissions struct {
 	Delete string
 }
 
+// CRUDRoles 定义标准 CRUD 路由要求的角色（命中任一即可，大小写不敏感）。
+//
+// 批量与审计路由复用对应操作的角色：batch create/update/delete 分别对应 Create/Update/Delete，
+// list_deleted 对应 List，audit_trail 对应 Get，restore 对应 Update，purge 对应 Delete；
+// import 对应 Create，export 对应 List，patch 对应 Update；软删回收站的 trash 对应 List。
+type CRUDRoles struct {
+	List   []string
+	Get    []string
+	Create []string
+	Update []string
+	Delete []string
+}
+
 // AuthorizationConfig 定义标准 CRUD 路由的自动授权配置。
 type AuthorizationConfig struct {
+	// Authorizer 执行权限码授权；仅在 Permissions 配置了任一权限码时必需。
 	Authorizer  auth.IAuthorizer
 	Permissions CRUDPermissions
+
+	// Roles 是按操作声明的角色要求，在 handler 执行前基于请求上下文中的主体校验：
+	// 缺少主体返回 Unauthorized，未命中任一角色返回 Forbidden；IsSystem 主体直接放行。
+	Roles       CRUDRoles
 	Consistency auth.ConsistencyMode
 	HighRisk    bool
 }
@@ -57,6 +75,20 @@ type RoutingOptions[ID comparable] struct {
 
 	// EnableBatch 控制是否启用批量操作路由。
 	EnableBatch bool
+
+	// EnableImport 控制是否启用导入路由（POST {BasePath}/import，CSV/JSONL），默认关闭。
+	EnableImport bool
+
+	// EnableExport 控制是否启用导出路由（GET {BasePath}/export，CSV/JSONL），默认关闭。
+	EnableExport bool
+
+	// EnablePatch 控制是否启用局部更新路由（PATCH {BasePath}/:id，JSON Merge Patch/JSON Patch），默认关闭。
+	EnablePatch bool
+
+	// EnableTrash 控制是否为软删实体启用回收站路由（GET {BasePath}/trash、POST /:id/restore、DELETE /:id/purge），默认关闭。
+	//
+	// 说明：回收站路由不下推写约束，配置了 List/Update/Delete 权限码时 Register 直接拒绝，仅支持 CRUDRoles 角色校验。
+	EnableTrash bool
 }
 
 // QueryOptions 定义列表查询、分页和查询 schema 配置。
@@ -107,6 +139,11 @@ type QueryOptions struct {
 	// - 若已手工设置 Allowed* 但仍希望启用自动推导，可显式设置该配置来强制启用；
 	// - 可通过该配置覆盖字段名映射等推导细节。
 	QuerySchemaInferOptions *query.SchemaInferOptions
+
+	// SoftDeleteField 是软删实体列表查询下推 `IS NULL` 过滤的字段名，默认 deleted_at。
+	//
+	// 说明：过滤随查询一起交给仓储，保证分页与总数只统计未删除记录；为空时不追加过滤（由仓储自行排除软删记录）。
+	SoftDeleteField string
 }
 
 // BodyOptions 定义请求体绑定与校验配置。
@@ -140,6 +177,34 @@ type ResponseOptions struct {
 	UseHTTP201ForCreate bool
 }
 
+// TransferOptions 定义导入/导出路由配置；零值字段使用默认值。
+type TransferOptions struct {
+	// MaxImportSize 是导入文件大小上限（bytes，默认 64MB）；导入路由不受 Body.MaxBodySize 限制。
+	MaxImportSize int64
+
+	// MaxImportRows 是单次导入的最大行数（默认 10000）；超出时整体拒绝，不写入任何数据。
+	MaxImportRows int
+
+	// ImportBatchSize 是每次批量写入的行数（默认 500），不应超过 ServiceConfig.MaxBatchSize。
+	ImportBatchSize int
+
+	// MaxExportRows 是单次导出的最大行数（默认 10000）；超出部分被截断，并设置 X-Export-Truncated 响应头。
+	MaxExportRows int
+
+	// ExportPageSize 是导出时分页读取的每页大小（默认 500）。
+	ExportPageSize int
+}
+
+// PatchOptions 定义局部更新路由配置。
+type PatchOptions struct {
+	// AllowedFields 允许通过 PATCH 修改的顶层 JSON 字段白名单。
+	//
+	// 说明：
+	// - 为空表示允许实体的全部字段（服务端维护的 id/version/审计/软删字段始终被拒绝）；
+	// - 非空时，补丁触及白名单以外的字段会返回 400（fail-fast，不静默忽略）。
+	AllowedFields []string
+}
+
 // AuditOptions 定义审计和操作人提取配置。
 type AuditOptions struct {
 	// OperatorExtractor 从请求中提取操作人（用于审计/软删等场景）。
@@ -171,6 +236,12 @@ type RouteConfig[ID comparable] struct {
 	// Audit 配置审计操作人提取。
 	Audit AuditOptions
 
+	// Transfer 配置导入/导出路由。
+	Transfer TransferOptions
+
+	// Patch 配置局部更新路由。
+	Patch PatchOptions
+
 	// Authorization 控制标准 CRUD 路由的自动资源绑定与预授权。
 	//
 	// 说明：
@@ -180,7 +251,12 @@ type RouteConfig[ID comparable] struct {
 	Authorization *AuthorizationConfig
 }
 
-const defaultMaxPageSize = 1000
+const (
+	defaultMaxPageSize = 1000
+
+	// defaultSoftDeleteField 与默认 ORM 仓储的软删列名保持一致。
+	defaultSoftDeleteField = "deleted_at"
+)
 
 // CORSConfig CORS 配置。
 type CORSConfig struct {
@@ -207,6 +283,7 @@ func DefaultRouteConfig[ID comparable]() *RouteConfig[ID] {
 			EnablePagination: true,
 			MaxPageSize:      defaultMaxPageSize,
 			DefaultPageSize:  10,
+			SoftDeleteField:  defaultSoftDeleteField,
 		},
 		Body: BodyOptions{
 			MaxBodySize: 10 << 20, // 10MB
@@ -216,7 +293,8 @@ func DefaultRouteConfig[ID comparable]() *RouteConfig[ID] {
 				// 默认仅允许同源与常见跨域场景，调用方可在组合根显式放宽
 				AllowOrigins:     []string{""},
 				AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"},
-				AllowHeaders:     []string{"Content-Type", "Authorization"},
+				AllowHeaders:     []string{"Content-Type", "Authorization", "If-Match"},
+				ExposeHeaders:    []string{"ETag"},
 				AllowCredentials: false,
 				MaxAge:           86400,
 			},
@@ -22,6 +22,7 @@ func TestDefaultErrorHandler_MapsErrorCodesToHTTPStatus(t *testing.T) {
 		{name: "conflict", err: errors.NewCode(errors.Conflict, "conflict"), wantStatus: http.StatusConflict},
 		{name: "duplicate", err: errors.NewCode(errors.Duplicate, "duplicate"), wantStatus: http.StatusConflict},
 		{name: "concurrency", err: errors.NewCode(errors.Concurrency, "concurrency"), wantStatus: http.StatusConflict},
+		{name: "precondition_failed", err: errors.NewCode(errors.PreconditionFailed, "stale"), wantStatus: http.StatusPreconditionFailed},
 		{name: "timeout", err: errors.NewCode(errors.Timeout, "timeout"), wantStatus: http.StatusRequestTimeout},
 		{name: "service_unavailable", err: errors.NewCode(errors.ServiceUnavailable, "unavailable"), wantStatus: http.StatusServiceUnavailable},
 	}
@@ -38,3 +38,25 @@ func ensureEntityIDMatchesPath[T domain.IEntity[ID], ID comparable](entity *T, p
 		WithContext("path_id", pathID).
 		WithContext("body_id", current)
 }
+
+// restoreEntityVersion 把绑定请求体后的实体版本还原为已校验的版本，避免请求体中的 version 绕过 If-Match 与乐观锁。
+//
+// 实体不支持 SetVersion 且版本已被改写时返回 InvalidInput。
+func restoreEntityVersion[T domain.IEntity[ID], ID comparable](entity *T, version uint64) error {
+	if entity == nil {
+		return errors.NewCode(errors.InvalidInput, "entity cannot be nil")
+	}
+	if (*entity).GetVersion() == version {
+		return nil
+	}
+	if settable, ok := any(*entity).(domain.ISettableVersion); ok {
+		settable.SetVersion(version)
+	} else if settable, ok := any(entity).(domain.ISettableVersion); ok {
+		settable.SetVersion(version)
+	}
+	if (*entity).GetVersion() != version {
+		return errors.NewCode(errors.InvalidInput, "entity version cannot be changed by request body").
+			WithContext("expected_version", version)
+	}
+	return nil
+}
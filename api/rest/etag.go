@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+const (
+	headerETag    = "ETag"
+	headerIfMatch = "If-Match"
+)
+
+// entityETag 以实体版本号生成 ETag（如 `"7"`）。
+func entityETag(version uint64) string {
+	return `"` + strconv.FormatUint(version, 10) + `"`
+}
+
+// setETag 把实体当前版本写入 ETag 响应头；实体为 nil 时不设置。
+func (rb *RouteBuilder[T, ID]) setETag(c httpx.IContext, entity T) {
+	if v := reflect.ValueOf(entity); !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return
+	}
+	c.SetHeader(headerETag, entityETag(entity.GetVersion()))
+}
+
+// checkIfMatch 校验 If-Match 前置条件；未携带时放行，与当前版本不符时返回 PreconditionFailed（412）。
+//
+// 说明：
+//   - 支持 `*` 与逗号分隔的多个 ETag；
+//   - 弱 ETag（`W/"7"`）按版本号比较，兼容会弱化 ETag 的反向代理。
+func checkIfMatch(c httpx.IContext, id any, current uint64) error {
+	header := strings.TrimSpace(c.Header(headerIfMatch))
+	if header == "" || ifMatchSatisfied(header, current) {
+		return nil
+	}
+	return errors.NewCode(errors.PreconditionFailed, "If-Match does not match current version").
+		WithContext("aggregate_id", id).
+		WithContext("if_match", header).
+		WithContext("actual_version", current)
+}
+
+func ifMatchSatisfied(header string, current uint64) bool {
+	want := entityETag(current)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == want {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchConflict 把携带 If-Match 的更新在写入时遇到的并发冲突映射为 PreconditionFailed（412）。
+//
+// If-Match 校验与写入之间实体可能被并发修改，由仓储按版本条件更新兜底；未携带 If-Match 时原样返回。
+func ifMatchConflict(c httpx.IContext, id any, err error) error {
+	if c == nil || strings.TrimSpace(c.Header(headerIfMatch)) == "" || !errors.Is(err, errors.Concurrency) {
+		return err
+	}
+	return errors.Wrap(err, errors.PreconditionFailed, "If-Match does not match current version").
+		WithContext("aggregate_id", id)
+}
@@ -4,7 +4,6 @@ import (
 	"net/http"
 	"strings"
 
-	auth "gochen/auth"
 	"gochen/errors"
 	"gochen/httpx"
 )
@@ -18,19 +17,11 @@ func (rb *RouteBuilder[T, ID]) handleList(c httpx.IContext) error {
 	if err != nil {
 		return err
 	}
-	if ctx, _, err = rb.authorize(c, ctx, rb.listPermission()); err != nil {
-		return err
-	}
-
 	query, err := rb.parseQueryParams(c)
 	if err != nil {
 		return err
 	}
-	listSvc, ok := rb.listService()
-	if !ok {
-		return errors.NewCode(errors.InvalidInput, "list route requires service to implement ListByQuery")
-	}
-	result, err := listSvc.ListByQuery(ctx, query)
+	result, err := rb.listEntities(c, ctx, query)
 	if err != nil {
 		return err
 	}
@@ -44,19 +35,11 @@ func (rb *RouteBuilder[T, ID]) handlePagedList(c httpx.IContext) error {
 	if err != nil {
 		return err
 	}
-	if ctx, _, err = rb.authorize(c, ctx, rb.listPermission()); err != nil {
-		return err
-	}
-
 	options, err := rb.parsePaginationOptions(c)
 	if err != nil {
 		return err
 	}
-	listSvc, ok := rb.pagedListService()
-	if !ok {
-		return errors.NewCode(errors.InvalidInput, "list route with pagination requires service to implement ListPage")
-	}
-	result, err := listSvc.ListPage(ctx, options.ToPageRequest())
+	result, err := rb.listEntityPage(c, ctx, options.ToPageRequest())
 	if err != nil {
 		return err
 	}
@@ -74,42 +57,12 @@ func (rb *RouteBuilder[T, ID]) handleGet(c httpx.IContext) error {
 	if err != nil {
 		return err
 	}
-	if permission := rb.getPermission(); permission != "" {
-		scopedCtx, resource, ok, err := rb.contextForResourceID(ctx, id)
-		if err != nil {
-			return err
-		}
-		if ok {
-			ctx = rb.syncRequestContext(c, scopedCtx)
-			if ctx, _, err = rb.authorize(c, ctx, permission, auth.AuthResourceFromBoundary(resource)); err != nil {
-				return err
-			}
-			getSvc, ok := rb.getService()
-			if !ok {
-				return errors.NewCode(errors.InvalidInput, "get route requires service to implement Get")
-			}
-			entity, err := getSvc.Get(ctx, id)
-			if err != nil {
-				return err
-			}
-
-			wrappedData := rb.config.Response.ResponseWrapper(entity)
-			return c.JSON(http.StatusOK, httpx.JSONValue(wrappedData))
-		}
-	}
-
-	getSvc, ok := rb.getService()
-	if !ok {
-		return errors.NewCode(errors.InvalidInput, "get route requires service to implement Get")
-	}
-	entity, err := getSvc.Get(ctx, id)
+	_, entity, err := rb.getEntity(c, ctx, id)
 	if err != nil {
 		return err
 	}
-	if ctx, _, err = rb.authorize(c, ctx, rb.getPermission(), entity); err != nil {
-		return err
-	}
 
+	rb.setETag(c, entity)
 	wrappedData := rb.config.Response.ResponseWrapper(entity)
 	return c.JSON(http.StatusOK, httpx.JSONValue(wrappedData))
 }
@@ -124,10 +77,8 @@ func (rb *RouteBuilder[T, ID]) handleCreate(c httpx.IContext) error {
 	}
 
 	// 执行自定义验证
-	if rb.config.Body.Validator != nil {
-		if err := rb.config.Body.Validator(entity); err != nil {
-			return err
-		}
+	if err := rb.validateBody(entity); err != nil {
+		return err
 	}
 
 	ctx, err := rb.serviceContext(c)
@@ -141,24 +92,8 @@ func (rb *RouteBuilder[T, ID]) handleCreate(c httpx.IContext) error {
 		}
 		ctx = auditCtx
 	}
-	if permission := rb.createPermission(); permission != "" {
-		ctx, decision, err := rb.authorize(c, ctx, permission, entity)
-		if err != nil {
-			return err
-		}
-		ctx = auth.BindConstraintMetadata(ctx, decision)
-		writer, _ := rb.writeConstraintWriter()
-		if err := writer.CreateWithConstraint(ctx, entity, auth.WriteConstraintFromDecision(decision)); err != nil {
-			return err
-		}
-	} else {
-		createSvc, ok := rb.createService()
-		if !ok {
-			return errors.NewCode(errors.InvalidInput, "create route requires service to implement Create")
-		}
-		if err := createSvc.Create(ctx, entity); err != nil {
-			return err
-		}
+	if err := rb.createEntity(c, ctx, entity); err != nil {
+		return err
 	}
 
 	result := map[string]any{
@@ -174,7 +109,10 @@ func (rb *RouteBuilder[T, ID]) handleCreate(c httpx.IContext) error {
 	return c.JSON(statusCode, httpx.JSONValue(wrappedData))
 }
 
-// handleUpdate 更新实体；audited 场景要求显式 version，并禁止通过更新篡改审计/软删字段。
+// handleUpdate 更新实体；携带 If-Match 时先校验当前版本（不符返回 412），响应携带新版本的 ETag。
+//
+// 请求体中的 version 被还原为读取时的版本，仓储按该版本条件更新；写入时的并发冲突在携带 If-Match 时同样返回 412。
+// audited 场景要求显式 version，并禁止通过更新篡改审计/软删字段。
 func (rb *RouteBuilder[T, ID]) handleUpdate(c httpx.IContext) error {
 	ctx, err := rb.serviceContext(c)
 	if err != nil {
@@ -192,25 +130,14 @@ func (rb *RouteBuilder[T, ID]) handleUpdate(c httpx.IContext) error {
 	if err != nil {
 		return err
 	}
-	if permission := rb.updatePermission(); permission != "" {
-		scopedCtx, _, ok, err := rb.contextForResourceID(ctx, id)
-		if err != nil {
-			return err
-		}
-		if ok {
-			ctx = rb.syncRequestContext(c, scopedCtx)
-		}
-	}
-
-	// 首先获取现有实体
-	getSvc, ok := rb.getService()
-	if !ok {
-		return errors.NewCode(errors.InvalidInput, "update route requires service to implement Get")
-	}
-	entity, err := getSvc.Get(ctx, id)
+	ctx, entity, err := rb.loadForUpdate(c, ctx, id)
 	if err != nil {
 		return err
 	}
+	loadedVersion := entity.GetVersion()
+	if err := checkIfMatch(c, id, loadedVersion); err != nil {
+		return err
+	}
 
 	// audited 更新：显式要求 version，并禁止通过更新接口篡改审计/软删字段。
 	if rb.auditedEnabled {
@@ -243,34 +170,21 @@ func (rb *RouteBuilder[T, ID]) handleUpdate(c httpx.IContext) error {
 	if err := ensureEntityIDMatchesPath(&entity, id); err != nil {
 		return err
 	}
+	// 版本以读取时（已通过 If-Match 校验）的值为准，由仓储作为更新条件。
+	if err := restoreEntityVersion(&entity, loadedVersion); err != nil {
+		return err
+	}
 
 	// 执行自定义验证
-	if rb.config.Body.Validator != nil {
-		if err := rb.config.Body.Validator(entity); err != nil {
-			return err
-		}
+	if err := rb.validateBody(entity); err != nil {
+		return err
 	}
 
-	if permission := rb.updatePermission(); permission != "" {
-		ctx, decision, err := rb.authorize(c, ctx, permission, entity)
-		if err != nil {
-			return err
-		}
-		ctx = auth.BindConstraintMetadata(ctx, decision)
-		writer, _ := rb.writeConstraintWriter()
-		if err := writer.UpdateWithConstraint(ctx, entity, auth.WriteConstraintFromDecision(decision)); err != nil {
-			return err
-		}
-	} else {
-		updateSvc, ok := rb.updateService()
-		if !ok {
-			return errors.NewCode(errors.InvalidInput, "update route requires service to implement Update")
-		}
-		if err := updateSvc.Update(ctx, entity); err != nil {
-			return err
-		}
+	if err := rb.updateEntity(c, ctx, entity); err != nil {
+		return ifMatchConflict(c, id, err)
 	}
 
+	rb.setETag(c, entity)
 	wrappedData := rb.config.Response.ResponseWrapper(entity)
 	return c.JSON(http.StatusOK, httpx.JSONValue(wrappedData))
 }
@@ -299,54 +213,8 @@ func (rb *RouteBuilder[T, ID]) handleDelete(c httpx.IContext) error {
 		ctx = auditCtx
 	}
 
-	if permission := rb.deletePermission(); permission != "" {
-		scopedCtx, resource, ok, err := rb.contextForResourceID(ctx, id)
-		if err != nil {
-			return err
-		}
-		if ok {
-			ctx = rb.syncRequestContext(c, scopedCtx)
-			ctx, decision, err := rb.authorize(c, ctx, permission, auth.AuthResourceFromBoundary(resource))
-			if err != nil {
-				return err
-			}
-			ctx = auth.BindConstraintMetadata(ctx, decision)
-			writer, _ := rb.writeConstraintWriter()
-			if err := writer.DeleteWithConstraint(ctx, id, auth.WriteConstraintFromDecision(decision)); err != nil {
-				return err
-			}
-			wrappedData := rb.config.Response.ResponseWrapper(nil)
-			return c.JSON(http.StatusOK, httpx.JSONValue(wrappedData))
-		}
-		getSvc, ok := rb.getService()
-		if !ok {
-			return errors.NewCode(errors.InvalidInput, "authz-enabled delete route requires service to implement Get")
-		}
-		entity, err := getSvc.Get(ctx, id)
-		if err != nil {
-			return err
-		}
-		ctx, decision, err := rb.authorize(c, ctx, permission, entity)
-		if err != nil {
-			return err
-		}
-		ctx = auth.BindConstraintMetadata(ctx, decision)
-		writer, _ := rb.writeConstraintWriter()
-		if err := writer.DeleteWithConstraint(ctx, id, auth.WriteConstraintFromDecision(decision)); err != nil {
-			return err
-		}
-	} else if rb.auditedEnabled {
-		if err := rb.auditedService.Delete(ctx, id); err != nil {
-			return err
-		}
-	} else {
-		deleteSvc, ok := rb.deleteService()
-		if !ok {
-			return errors.NewCode(errors.InvalidInput, "delete route requires service to implement Delete")
-		}
-		if err := deleteSvc.Delete(ctx, id); err != nil {
-			return err
-		}
+	if err := rb.deleteEntity(c, ctx, id); err != nil {
+		return err
 	}
 
 	wrappedData := rb.config.Response.ResponseWrapper(nil)
@@ -374,3 +242,24 @@ func (rb *RouteBuilder[T, ID]) handleAuditTrail(c httpx.IContext) error {
 	}
 	return c.JSON(http.StatusOK, httpx.JSONValue(rb.config.Response.ResponseWrapper(recs)))
 }
+
+// handleChangeHistory 查询 audited 实体的字段级变更历史（谁、何时、改了什么）。
+func (rb *RouteBuilder[T, ID]) handleChangeHistory(c httpx.IContext) error {
+	historySvc, ok := rb.service.(IChangeHistoryService[ID])
+	if !rb.auditedEnabled || !ok {
+		return errors.NewCode(errors.NotFound, "route not enabled")
+	}
+	id, err := rb.parseID(c)
+	if err != nil {
+		return err
+	}
+	opts, err := rb.parsePaginationOptions(c)
+	if err != nil {
+		return err
+	}
+	entries, err := historySvc.ChangeHistory(c.RequestContext(), id, opts.Offset(), opts.Size)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, httpx.JSONValue(rb.config.Response.ResponseWrapper(entries)))
+}
@@ -0,0 +1,223 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"mime"
+	"net/http"
+	"reflect"
+	"slices"
+
+	"gochen/codec/jsonpatch"
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+const (
+	contentTypeMergePatch = "application/merge-patch+json"
+	contentTypeJSONPatch  = "application/json-patch+json"
+)
+
+// patchManagedFields 是服务端维护、不允许通过 PATCH 修改的字段。
+var patchManagedFields = map[string]struct{}{
+	"id":         {},
+	"version":    {},
+	"created_at": {},
+	"created_by": {},
+	"updated_at": {},
+	"updated_by": {},
+	"deleted_at": {},
+	"deleted_by": {},
+}
+
+// handlePatch 按 JSON Merge Patch（RFC 7386）或 JSON Patch（RFC 6902）局部更新实体。
+//
+// 说明：
+//   - 按 Content-Type 选择语义：application/json-patch+json 为 JSON Patch，其余 JSON 类型按 Merge Patch 处理；
+//   - 补丁作用于实体当前的 JSON 表示，仅把触及的顶层字段交给仓储写入；
+//   - audited 实体必须携带 If-Match，以代替 PUT 请求体中的 version。
+func (rb *RouteBuilder[T, ID]) handlePatch(c httpx.IContext) error {
+	ctx, err := rb.serviceContext(c)
+	if err != nil {
+		return err
+	}
+	if rb.auditedEnabled {
+		auditCtx, _, err := rb.mustAuditedContext(c)
+		if err != nil {
+			return err
+		}
+		ctx = auditCtx
+		if c.Header(headerIfMatch) == "" {
+			return errors.NewCode(errors.InvalidInput, "If-Match header is required for audited patch")
+		}
+	}
+
+	id, err := rb.parseID(c)
+	if err != nil {
+		return err
+	}
+	getSvc, ok := rb.getService()
+	if !ok {
+		return errors.NewCode(errors.InvalidInput, "patch route requires service to implement Get")
+	}
+	current, err := getSvc.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := checkIfMatch(c, id, current.GetVersion()); err != nil {
+		return err
+	}
+
+	body, err := c.Body()
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return errors.NewCode(errors.InvalidInput, "empty request body")
+	}
+	raw, err := json.Marshal(current)
+	if err != nil {
+		return errors.Wrap(err, errors.Internal, "failed to encode entity")
+	}
+	doc, err := jsonpatch.Decode(raw)
+	if err != nil {
+		return err
+	}
+	patched, fields, err := applyEntityPatch(c.Header("Content-Type"), doc, body)
+	if err != nil {
+		return err
+	}
+	if err := rb.checkPatchFields(fields); err != nil {
+		return err
+	}
+
+	entity, err := decodePatchedEntity[T](patched)
+	if err != nil {
+		return err
+	}
+	if err := ensureEntityIDMatchesPath(&entity, id); err != nil {
+		return err
+	}
+	if err := rb.validateBody(entity); err != nil {
+		return err
+	}
+
+	patchSvc, ok := rb.patchService()
+	if !ok {
+		return errors.NewCode(errors.InvalidInput, "patch route requires service to implement Patch")
+	}
+	if err := patchSvc.Patch(ctx, entity, fields); err != nil {
+		return err
+	}
+
+	rb.setETag(c, entity)
+	wrappedData := rb.config.Response.ResponseWrapper(entity)
+	return c.JSON(http.StatusOK, httpx.JSONValue(wrappedData))
+}
+
+// applyEntityPatch 按 Content-Type 应用补丁，返回补丁后的文档与触及的顶层字段。
+func applyEntityPatch(contentType string, doc any, body []byte) (any, []string, error) {
+	mediaType := ""
+	if contentType != "" {
+		parsed, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return nil, nil, errors.NewCode(errors.InvalidInput, "invalid Content-Type").WithContext("content_type", contentType)
+		}
+		mediaType = parsed
+	}
+
+	switch mediaType {
+	case contentTypeJSONPatch:
+		patch, err := jsonpatch.DecodePatch(body)
+		if err != nil {
+			return nil, nil, err
+		}
+		var fields []string
+		for _, op := range patch {
+			if op.Op == "test" {
+				continue
+			}
+			pointers := []string{op.Path}
+			if op.Op == "move" {
+				pointers = append(pointers, op.From)
+			}
+			for _, pointer := range pointers {
+				tokens, err := jsonpatch.ParsePointer(pointer)
+				if err != nil {
+					return nil, nil, err
+				}
+				if len(tokens) == 0 {
+					return nil, nil, errors.NewCode(errors.InvalidInput, "JSON Patch cannot replace the whole entity")
+				}
+				fields = appendUniqueField(fields, tokens[0])
+			}
+		}
+		patched, err := patch.Apply(doc)
+		if err != nil {
+			return nil, nil, err
+		}
+		return patched, fields, nil
+	case contentTypeMergePatch, "application/json", "":
+		patchDoc, err := jsonpatch.Decode(body)
+		if err != nil {
+			return nil, nil, err
+		}
+		obj, ok := patchDoc.(map[string]any)
+		if !ok {
+			return nil, nil, errors.NewCode(errors.InvalidInput, "merge patch must be a JSON object")
+		}
+		fields := slices.Sorted(maps.Keys(obj))
+		return jsonpatch.Merge(doc, obj), fields, nil
+	default:
+		return nil, nil, errors.NewCode(errors.InvalidInput, "unsupported patch Content-Type").
+			WithContext("content_type", mediaType)
+	}
+}
+
+func appendUniqueField(fields []string, field string) []string {
+	for _, existing := range fields {
+		if existing == field {
+			return fields
+		}
+	}
+	return append(fields, field)
+}
+
+// checkPatchFields 拒绝未知字段、服务端维护字段以及白名单以外的字段。
+func (rb *RouteBuilder[T, ID]) checkPatchFields(fields []string) error {
+	known := make(map[string]struct{})
+	for _, column := range transferColumns(reflect.TypeFor[T]()) {
+		known[column.name] = struct{}{}
+	}
+	for _, field := range fields {
+		if _, ok := patchManagedFields[field]; ok {
+			return errors.NewCode(errors.InvalidInput, "cannot patch server-managed field").WithContext("field", field)
+		}
+		if _, ok := known[field]; !ok {
+			return errors.NewCode(errors.InvalidInput, "unknown field in patch").WithContext("field", field)
+		}
+		if len(rb.config.Patch.AllowedFields) > 0 && !slices.Contains(rb.config.Patch.AllowedFields, field) {
+			return errors.NewCode(errors.InvalidInput, "field is not allowed in patch").WithContext("field", field)
+		}
+	}
+	return nil
+}
+
+// decodePatchedEntity 把补丁后的文档按严格 JSON 语义解码为新实体。
+func decodePatchedEntity[T any](doc any) (T, error) {
+	var entity T
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return entity, errors.Wrap(err, errors.InvalidInput, "invalid patched entity")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&entity); err != nil {
+		return entity, errors.Wrap(err, errors.InvalidInput, "invalid patched entity")
+	}
+	if v := reflect.ValueOf(entity); v.Kind() == reflect.Pointer && v.IsNil() {
+		return entity, errors.NewCode(errors.InvalidInput, "entity cannot be nil")
+	}
+	return entity, nil
+}
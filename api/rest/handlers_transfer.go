@@ -0,0 +1,766 @@
+package rest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+
+	auth "gochen/auth"
+	"gochen/db/query"
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+const (
+	transferFormatCSV   = "csv"
+	transferFormatJSONL = "jsonl"
+
+	defaultMaxImportSize   int64 = 64 << 20 // 64MB
+	defaultMaxImportRows         = 10000
+	defaultImportBatchSize       = 500
+	defaultMaxExportRows         = 10000
+	defaultExportPageSize        = 500
+
+	// importFileField 是 multipart 上传时的文件字段名。
+	importFileField = "file"
+)
+
+// ImportResult 是导入路由的响应数据。
+type ImportResult struct {
+	// Total 是解析到的数据行数。
+	Total int `json:"total"`
+	// Created 是成功写入的行数。
+	Created int `json:"created"`
+	// Failed 是失败的行数（解析、校验或写入失败）。
+	Failed int `json:"failed"`
+	// Errors 按行列出失败原因。
+	Errors []ImportRowError `json:"errors,omitempty"`
+}
+
+// ImportRowError 描述单行导入失败的原因。
+type ImportRowError struct {
+	// Row 是从 1 开始的数据行序号（CSV 不含表头，JSONL 不含空行）。
+	Row     int    `json:"row"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type importRow[T any] struct {
+	row    int
+	entity T
+}
+
+// transferColumn 描述实体的一个顶层 JSON 字段。
+type transferColumn struct {
+	name string
+	// quoted 表示该字段的 JSON 表示为字符串：CSV 单元格需要加引号后再解码。
+	quoted bool
+}
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+func (rb *RouteBuilder[T, ID]) transferOptions() TransferOptions {
+	opts := TransferOptions{}
+	if rb.config != nil {
+		opts = rb.config.Transfer
+	}
+	if opts.MaxImportSize <= 0 {
+		opts.MaxImportSize = defaultMaxImportSize
+	}
+	if opts.MaxImportRows <= 0 {
+		opts.MaxImportRows = defaultMaxImportRows
+	}
+	if opts.ImportBatchSize <= 0 {
+		opts.ImportBatchSize = defaultImportBatchSize
+	}
+	if opts.MaxExportRows <= 0 {
+		opts.MaxExportRows = defaultMaxExportRows
+	}
+	if opts.ExportPageSize <= 0 {
+		opts.ExportPageSize = defaultExportPageSize
+	}
+	return opts
+}
+
+// handleImport 流式解析 CSV/JSONL 上传并分批写入；行级失败汇总到响应中，不中断其余行。
+//
+// 说明：
+//   - 格式优先取 `format` 查询参数，其次是 Content-Type 或上传文件扩展名；
+//   - 超过 MaxImportRows 时整体拒绝，不写入任何数据；
+//   - 单批写入失败时该批所有行记为失败，已写入的其他批次不回滚。
+func (rb *RouteBuilder[T, ID]) handleImport(c httpx.IContext) error {
+	opts := rb.transferOptions()
+	source, format, err := openImportSource(c, opts.MaxImportSize)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = source.Close() }()
+
+	result := &ImportResult{}
+	var rows []importRow[T]
+	switch format {
+	case transferFormatCSV:
+		rows, err = rb.parseCSVImport(c, source, opts.MaxImportRows, result)
+	default:
+		rows, err = rb.parseJSONLImport(c, source, opts.MaxImportRows, result)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(rows) > 0 {
+		if err := rb.writeImportRows(c, rows, opts.ImportBatchSize, result); err != nil {
+			return err
+		}
+	}
+
+	wrappedData := rb.config.Response.ResponseWrapper(result)
+	return c.JSON(http.StatusOK, httpx.JSONValue(wrappedData))
+}
+
+func (rb *RouteBuilder[T, ID]) writeImportRows(c httpx.IContext, rows []importRow[T], batchSize int, result *ImportResult) error {
+	ctx, err := rb.serviceContext(c)
+	if err != nil {
+		return err
+	}
+	if rb.auditedEnabled {
+		auditCtx, _, err := rb.mustAuditedContext(c)
+		if err != nil {
+			return err
+		}
+		ctx = auditCtx
+	}
+
+	entities := make([]T, 0, len(rows))
+	for _, row := range rows {
+		entities = append(entities, row.entity)
+	}
+
+	var write func(ctx context.Context, entities []T) error
+	if permission := rb.createPermission(); permission != "" {
+		// 授权覆盖全部待写入行：拒绝时整个导入失败，不做部分写入。
+		authzCtx, decision, err := rb.authorize(c, ctx, permission, batchTargets(entities)...)
+		if err != nil {
+			return err
+		}
+		ctx = auth.BindConstraintMetadata(authzCtx, decision)
+		constraint := auth.WriteConstraintFromDecision(decision)
+		writer, ok := rb.batchWriteConstraintWriter()
+		if !ok {
+			return errors.NewCode(errors.InvalidInput, "authz-enabled import route requires a write-constraint batch writer")
+		}
+		write = func(ctx context.Context, entities []T) error {
+			return writer.CreateAllWithConstraint(ctx, entities, constraint)
+		}
+	} else {
+		writer, ok := rb.batchWriter()
+		if !ok {
+			return errors.NewCode(errors.InvalidInput, "import route requires a built-in batch writer or service-specific batch writer")
+		}
+		write = writer.CreateAll
+	}
+
+	for start := 0; start < len(rows); start += batchSize {
+		end := min(start+batchSize, len(rows))
+		if err := write(ctx, entities[start:end]); err != nil {
+			for _, row := range rows[start:end] {
+				result.addError(c, row.row, err)
+			}
+			continue
+		}
+		result.Created += end - start
+	}
+	return nil
+}
+
+func (rb *RouteBuilder[T, ID]) parseCSVImport(c httpx.IContext, source io.Reader, maxRows int, result *ImportResult) ([]importRow[T], error) {
+	reader := csv.NewReader(source)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, importReadError(err)
+	}
+
+	known := make(map[string]transferColumn)
+	for _, column := range transferColumns(reflect.TypeFor[T]()) {
+		known[column.name] = column
+	}
+	columns := make([]transferColumn, len(header))
+	seen := make(map[string]struct{}, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.TrimSpace(name)
+		column, ok := known[name]
+		if !ok {
+			return nil, errors.NewCode(errors.InvalidInput, "unknown import column").WithContext("column", name)
+		}
+		if _, dup := seen[name]; dup {
+			return nil, errors.NewCode(errors.InvalidInput, "duplicate import column").WithContext("column", name)
+		}
+		seen[name] = struct{}{}
+		columns[i] = column
+	}
+
+	var rows []importRow[T]
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if result.Total = row; row > maxRows {
+			return nil, importRowsExceeded(maxRows)
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				result.addError(c, row, errors.Wrap(err, errors.InvalidInput, "invalid csv row"))
+				continue
+			}
+			return nil, importReadError(err)
+		}
+		if len(record) != len(columns) {
+			result.addError(c, row, errors.NewCode(errors.InvalidInput, "csv row column count does not match header"))
+			continue
+		}
+		payload, err := csvRecordJSON(columns, record)
+		if err != nil {
+			result.addError(c, row, err)
+			continue
+		}
+		entity, err := rb.decodeImportEntity(payload)
+		if err != nil {
+			result.addError(c, row, err)
+			continue
+		}
+		rows = append(rows, importRow[T]{row: row, entity: entity})
+	}
+}
+
+func (rb *RouteBuilder[T, ID]) parseJSONLImport(c httpx.IContext, source io.Reader, maxRows int, result *ImportResult) ([]importRow[T], error) {
+	reader := bufio.NewReader(source)
+	var rows []importRow[T]
+	row := 0
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return nil, importReadError(readErr)
+		}
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			row++
+			if result.Total = row; row > maxRows {
+				return nil, importRowsExceeded(maxRows)
+			}
+			entity, err := rb.decodeImportEntity(line)
+			if err != nil {
+				result.addError(c, row, err)
+			} else {
+				rows = append(rows, importRow[T]{row: row, entity: entity})
+			}
+		}
+		if readErr == io.EOF {
+			return rows, nil
+		}
+	}
+}
+
+// decodeImportEntity 按创建路由相同的严格 JSON 语义解码单行，并执行 API 层校验。
+func (rb *RouteBuilder[T, ID]) decodeImportEntity(payload []byte) (T, error) {
+	var entity T
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&entity); err != nil {
+		return entity, errors.Wrap(err, errors.InvalidInput, "invalid row data")
+	}
+	if decoder.More() {
+		return entity, errors.NewCode(errors.InvalidInput, "invalid row data: trailing data")
+	}
+	if v := reflect.ValueOf(entity); v.Kind() == reflect.Pointer && v.IsNil() {
+		return entity, errors.NewCode(errors.InvalidInput, "entity cannot be nil")
+	}
+	if err := rb.validateBody(entity); err != nil {
+		return entity, err
+	}
+	return entity, nil
+}
+
+func (r *ImportResult) addError(c httpx.IContext, row int, err error) {
+	_, payload := httpx.EncodeErrorResponse(c, err)
+	r.Failed++
+	r.Errors = append(r.Errors, ImportRowError{Row: row, Code: payload.Code, Message: payload.Message})
+}
+
+// csvRecordJSON 把一行 CSV 转为 JSON 对象：字符串字段加引号，其余字段按 JSON 字面量解析，空单元格省略。
+func csvRecordJSON(columns []transferColumn, record []string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	first := true
+	for i, cell := range record {
+		if cell == "" {
+			continue
+		}
+		column := columns[i]
+		var value []byte
+		if column.quoted {
+			value, _ = json.Marshal(cell)
+		} else {
+			value = []byte(strings.TrimSpace(cell))
+			if !json.Valid(value) {
+				return nil, errors.NewCode(errors.InvalidInput, "invalid value for import column").
+					WithContext("column", column.name)
+			}
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		name, _ := json.Marshal(column.name)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// openImportSource 返回导入数据流与格式；multipart 上传时读取 file 字段，不落盘。
+func openImportSource(c httpx.IContext, maxSize int64) (io.ReadCloser, string, error) {
+	req := c.Request()
+	if req == nil || req.Body == nil {
+		return nil, "", errors.NewCode(errors.InvalidInput, "import requires a request body")
+	}
+	body := &sizeLimitedReader{reader: req.Body, remaining: maxSize}
+	format := strings.ToLower(strings.TrimSpace(c.Query("format")))
+	mediaType, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+
+	if mediaType == "multipart/form-data" {
+		parts := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := parts.NextPart()
+			if err == io.EOF {
+				return nil, "", errors.NewCode(errors.InvalidInput, "import file field is missing").
+					WithContext("field", importFileField)
+			}
+			if err != nil {
+				return nil, "", importReadError(err)
+			}
+			if part.FormName() != importFileField {
+				_ = part.Close()
+				continue
+			}
+			if format == "" {
+				format = formatFromFilename(part.FileName())
+			}
+			if format == "" {
+				partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+				format = formatFromMediaType(partType)
+			}
+			if err := validateTransferFormat(format); err != nil {
+				_ = part.Close()
+				return nil, "", err
+			}
+			return part, format, nil
+		}
+	}
+
+	if format == "" {
+		format = formatFromMediaType(mediaType)
+	}
+	if err := validateTransferFormat(format); err != nil {
+		return nil, "", err
+	}
+	return io.NopCloser(body), format, nil
+}
+
+func formatFromMediaType(mediaType string) string {
+	switch strings.ToLower(mediaType) {
+	case "text/csv", "application/csv":
+		return transferFormatCSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines":
+		return transferFormatJSONL
+	}
+	return ""
+}
+
+func formatFromFilename(filename string) string {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return transferFormatCSV
+	case ".jsonl", ".ndjson":
+		return transferFormatJSONL
+	}
+	return ""
+}
+
+func validateTransferFormat(format string) error {
+	if format == transferFormatCSV || format == transferFormatJSONL {
+		return nil
+	}
+	return errors.NewCode(errors.InvalidInput, "unsupported transfer format, expected csv or jsonl").
+		WithContext("format", format)
+}
+
+func importRowsExceeded(maxRows int) error {
+	return errors.NewCode(errors.PayloadTooLarge, "import exceeds maximum rows").WithContext("max_rows", maxRows)
+}
+
+func importReadError(err error) error {
+	if errors.Is(err, errors.PayloadTooLarge) {
+		return err
+	}
+	return errors.Wrap(err, errors.InvalidInput, "failed to read import data")
+}
+
+// sizeLimitedReader 在读取超过上限时返回 PayloadTooLarge。
+type sizeLimitedReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (r *sizeLimitedReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, errors.NewCode(errors.PayloadTooLarge, "import data too large")
+	}
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return 0, errors.NewCode(errors.PayloadTooLarge, "import data too large")
+	}
+	return n, err
+}
+
+// handleExport 按列表路由相同的 filter/fields 语义导出实体为 CSV 或 JSONL。
+//
+// 说明：
+//   - 格式取 `format` 查询参数（csv/jsonl，默认 csv）；
+//   - 通过 ListPage 按主键 keyset 分页读取（每页 ExportPageSize 条），逐页编码，不在内存中保留实体；
+//     导出固定按主键升序输出，`sorts` 参数不参与排序；
+//   - 与列表路由一致，软删实体被排除；
+//   - 超过 MaxExportRows 的部分被截断，并设置 `X-Export-Truncated: true` 响应头。
+func (rb *RouteBuilder[T, ID]) handleExport(c httpx.IContext) error {
+	ctx, err := rb.serviceContext(c)
+	if err != nil {
+		return err
+	}
+	if ctx, _, err = rb.authorize(c, ctx, rb.listPermission()); err != nil {
+		return err
+	}
+
+	format := strings.ToLower(strings.TrimSpace(c.Query("format")))
+	if format == "" {
+		format = transferFormatCSV
+	}
+	if err := validateTransferFormat(format); err != nil {
+		return err
+	}
+	request, err := rb.parseQueryParams(c)
+	if err != nil {
+		return err
+	}
+
+	var encoder exportEncoder[T]
+	contentType := "text/csv; charset=utf-8"
+	if format == transferFormatCSV {
+		columns := request.Fields
+		if len(columns) == 0 {
+			for _, column := range transferColumns(reflect.TypeFor[T]()) {
+				columns = append(columns, column.name)
+			}
+		}
+		encoder, err = newCSVExportEncoder[T](columns)
+	} else {
+		contentType = "application/x-ndjson; charset=utf-8"
+		encoder = &jsonlExportEncoder[T]{fields: request.Fields}
+	}
+	if err != nil {
+		return err
+	}
+	truncated, err := rb.exportEntities(ctx, request, rb.transferOptions(), encoder.write)
+	if err != nil {
+		return err
+	}
+	payload, err := encoder.bytes()
+	if err != nil {
+		return err
+	}
+
+	c.SetHeader("Content-Disposition", fmt.Sprintf("attachment; filename=%q", resourceTag(rb.config.Routing.BasePath)+"."+format))
+	if truncated {
+		c.SetHeader("X-Export-Truncated", "true")
+	}
+	return c.Data(http.StatusOK, contentType, payload)
+}
+
+// exportEntities 按主键 keyset 分页读取待导出实体，并把每页交给 emit；返回是否因 MaxExportRows 截断。
+//
+// 每页都请求第 1 页并以 `id > 上一页最后主键` 作为游标条件，避免深分页的 OFFSET 扫描，
+// 也不会因导出期间的写入产生重复或遗漏。
+func (rb *RouteBuilder[T, ID]) exportEntities(ctx context.Context, request *query.QueryRequest, opts TransferOptions, emit func([]T) error) (bool, error) {
+	pager, ok := rb.pagedListService()
+	if !ok {
+		return false, errors.NewCode(errors.InvalidInput, "export route requires service to implement ListPage")
+	}
+
+	filters := rb.withoutDeleted(request.Filters)
+	fields := request.Fields
+	if len(fields) > 0 && !slices.Contains(fields, exportKeysetField) {
+		fields = append(slices.Clone(fields), exportKeysetField)
+	}
+	sorts := []query.Sort{{Field: exportKeysetField, Direction: query.ASC}}
+
+	exported := 0
+	var cursor *query.QueryValue
+	for {
+		pageFilters := filters
+		if cursor != nil {
+			pageFilters = filters.Clone().Merge(query.QueryFilters{
+				exportKeysetField: {{Op: query.FilterOpGt, Value: *cursor}},
+			})
+		}
+		result, err := pager.ListPage(ctx, &query.PageRequest{
+			Page:    1,
+			Size:    opts.ExportPageSize,
+			Filters: pageFilters,
+			Sorts:   sorts,
+			Fields:  fields,
+		})
+		if err != nil {
+			return false, err
+		}
+		if result == nil || len(result.Data) == 0 {
+			return false, nil
+		}
+		batch := result.Data
+		if exported+len(batch) > opts.MaxExportRows {
+			return true, emit(batch[:opts.MaxExportRows-exported])
+		}
+		if err := emit(batch); err != nil {
+			return false, err
+		}
+		exported += len(batch)
+		if !result.HasNext || len(batch) < opts.ExportPageSize {
+			return false, nil
+		}
+		next, ok := exportKeysetValue(batch[len(batch)-1].GetID())
+		if !ok {
+			return false, errors.NewCode(errors.InvalidInput, "export requires an integer or string entity ID")
+		}
+		cursor = &next
+	}
+}
+
+// exportKeysetField 是导出 keyset 分页使用的主键字段。
+const exportKeysetField = "id"
+
+// exportKeysetValue 把主键转换为 keyset 游标值；仅支持整数与字符串主键。
+func exportKeysetValue(id any) (query.QueryValue, bool) {
+	value := reflect.ValueOf(id)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return query.IntValue(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value.Uint() > math.MaxInt64 {
+			return query.QueryValue{}, false
+		}
+		return query.IntValue(int64(value.Uint())), true
+	case reflect.String:
+		return query.StringValue(value.String()), true
+	default:
+		return query.QueryValue{}, false
+	}
+}
+
+// supportsExportKeyset 判断主键类型能否作为导出 keyset 游标。
+func supportsExportKeyset[ID comparable]() bool {
+	switch reflect.TypeFor[ID]().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.String:
+		return true
+	default:
+		return false
+	}
+}
+
+// exportEncoder 逐页编码导出实体。
+type exportEncoder[T any] interface {
+	write(entities []T) error
+	bytes() ([]byte, error)
+}
+
+type csvExportEncoder[T any] struct {
+	buf     bytes.Buffer
+	writer  *csv.Writer
+	columns []string
+	record  []string
+}
+
+func newCSVExportEncoder[T any](columns []string) (*csvExportEncoder[T], error) {
+	e := &csvExportEncoder[T]{columns: columns, record: make([]string, len(columns))}
+	e.writer = csv.NewWriter(&e.buf)
+	if err := e.writer.Write(columns); err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "failed to encode export")
+	}
+	return e, nil
+}
+
+func (e *csvExportEncoder[T]) write(entities []T) error {
+	for _, entity := range entities {
+		fields, err := entityJSONFields(entity)
+		if err != nil {
+			return err
+		}
+		for i, column := range e.columns {
+			e.record[i] = csvCell(fields[column])
+		}
+		if err := e.writer.Write(e.record); err != nil {
+			return errors.Wrap(err, errors.Internal, "failed to encode export")
+		}
+	}
+	return nil
+}
+
+func (e *csvExportEncoder[T]) bytes() ([]byte, error) {
+	e.writer.Flush()
+	if err := e.writer.Error(); err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "failed to encode export")
+	}
+	return e.buf.Bytes(), nil
+}
+
+// jsonlExportEncoder 每行输出一个实体；指定 fields 时只保留这些字段（按 fields 顺序）。
+type jsonlExportEncoder[T any] struct {
+	buf    bytes.Buffer
+	fields []string
+}
+
+func (e *jsonlExportEncoder[T]) write(entities []T) error {
+	for _, entity := range entities {
+		if len(e.fields) == 0 {
+			line, err := json.Marshal(entity)
+			if err != nil {
+				return errors.Wrap(err, errors.Internal, "failed to encode export")
+			}
+			e.buf.Write(line)
+			e.buf.WriteByte('\n')
+			continue
+		}
+		values, err := entityJSONFields(entity)
+		if err != nil {
+			return err
+		}
+		e.buf.WriteByte('{')
+		first := true
+		for _, field := range e.fields {
+			value, ok := values[field]
+			if !ok {
+				continue
+			}
+			if !first {
+				e.buf.WriteByte(',')
+			}
+			first = false
+			name, _ := json.Marshal(field)
+			e.buf.Write(name)
+			e.buf.WriteByte(':')
+			e.buf.Write(value)
+		}
+		e.buf.WriteString("}\n")
+	}
+	return nil
+}
+
+func (e *jsonlExportEncoder[T]) bytes() ([]byte, error) {
+	return e.buf.Bytes(), nil
+}
+
+func entityJSONFields(entity any) (map[string]json.RawMessage, error) {
+	payload, err := json.Marshal(entity)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "failed to encode export")
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "export entity must encode as a JSON object")
+	}
+	return fields, nil
+}
+
+// csvCell 把 JSON 值转为 CSV 单元格：字符串去引号，null 为空，其余保留 JSON 文本（与导入规则对称）。
+func csvCell(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			return s
+		}
+	}
+	return string(raw)
+}
+
+// transferColumns 返回实体的顶层 JSON 字段（按结构体声明顺序，含嵌入结构体提升的字段）。
+func transferColumns(t reflect.Type) []transferColumn {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var columns []transferColumn
+	seen := make(map[string]struct{})
+	for _, field := range reflect.VisibleFields(t) {
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && indirectKind(field.Type) == reflect.Struct {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if _, dup := seen[name]; dup {
+			continue
+		}
+		seen[name] = struct{}{}
+		columns = append(columns, transferColumn{name: name, quoted: isJSONStringType(field.Type)})
+	}
+	return columns
+}
+
+func isJSONStringType(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.String || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func indirectKind(t reflect.Type) reflect.Kind {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind()
+}
@@ -0,0 +1,300 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"gochen/db/query"
+	"gochen/errors"
+	"gochen/httpx"
+	"gochen/httpx/httpxtest"
+	"gochen/httpx/nethttp"
+)
+
+type transferAppService struct {
+	*stubAppService
+	created  [][]*fakeEntity
+	failOn   string
+	items    []*fakeEntity
+	pageReqs []*query.PageRequest
+}
+
+func (s *transferAppService) CreateAll(_ context.Context, items []*fakeEntity) error {
+	for _, item := range items {
+		if s.failOn != "" && item.Name == s.failOn {
+			return errors.NewCode(errors.Conflict, "duplicate name")
+		}
+	}
+	s.created = append(s.created, items)
+	return nil
+}
+
+// ListPage 按 `id > 游标` 过滤后返回前 Size 条，模拟 keyset 分页。
+func (s *transferAppService) ListPage(_ context.Context, opts *query.PageRequest) (*query.PagedResult[*fakeEntity], error) {
+	s.pageReqs = append(s.pageReqs, opts)
+	var after int64
+	for _, expr := range opts.Filters["id"] {
+		if expr.Op == query.FilterOpGt {
+			after = expr.Value.Int
+		}
+	}
+	var remaining []*fakeEntity
+	for _, item := range s.items {
+		if item.ID > after {
+			remaining = append(remaining, item)
+		}
+	}
+	page := remaining
+	if len(page) > opts.Size {
+		page = page[:opts.Size]
+	}
+	return &query.PagedResult[*fakeEntity]{
+		Data:    page,
+		Page:    opts.Page,
+		Size:    opts.Size,
+		HasNext: len(remaining) > len(page),
+	}, nil
+}
+
+func newTransferRoutes(t *testing.T, svc *transferAppService, mutate func(*RouteConfig[int64])) *httpxtest.MockRouteGroup {
+	t.Helper()
+	builder, err := NewApiBuilder[*fakeEntity, int64](svc, WithImportExport[*fakeEntity, int64](0))
+	if err != nil {
+		t.Fatalf("NewApiBuilder returned error: %v", err)
+	}
+	builder.Route(func(cfg *RouteConfig[int64]) {
+		cfg.Routing.BasePath = "/items"
+		if mutate != nil {
+			mutate(cfg)
+		}
+	})
+	group := httpxtest.NewMockRouteGroup()
+	if err := builder.Build(group); err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	return group
+}
+
+func serveTransfer(t *testing.T, group *httpxtest.MockRouteGroup, route string, r *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	handler, ok := group.Handlers[route]
+	if !ok {
+		t.Fatalf("route %s not registered", route)
+	}
+	w := httptest.NewRecorder()
+	ctx, err := nethttp.NewBaseContext(w, r)
+	if err != nil {
+		t.Fatalf("NewBaseContext returned error: %v", err)
+	}
+	if err := handler(ctx); err != nil {
+		status, _ := httpx.EncodeErrorResponse(ctx, err)
+		w.Code = status
+	}
+	return w
+}
+
+func decodeImportResult(t *testing.T, w *httptest.ResponseRecorder) ImportResult {
+	t.Helper()
+	var envelope struct {
+		Data ImportResult `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response failed: %v (%s)", err, w.Body.String())
+	}
+	return envelope.Data
+}
+
+func TestRouteBuilder_ImportCSVReportsRowErrors(t *testing.T) {
+	svc := &transferAppService{stubAppService: newStubAppService(nil)}
+	group := newTransferRoutes(t, svc, nil)
+
+	body := "\ufeffname,active\nalpha,true\nbeta,maybe\ngamma,\n"
+	r := httptest.NewRequest(http.MethodPost, "/items/import", strings.NewReader(body))
+	r.Header.Set("Content-Type", "text/csv")
+	w := serveTransfer(t, group, "POST /items/import", r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	result := decodeImportResult(t, w)
+	if result.Total != 3 || result.Created != 2 || result.Failed != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Row != 2 || result.Errors[0].Code != string(errors.InvalidInput) {
+		t.Fatalf("unexpected row errors: %+v", result.Errors)
+	}
+	if len(svc.created) != 1 || len(svc.created[0]) != 2 {
+		t.Fatalf("expected one batch of 2 entities, got %+v", svc.created)
+	}
+	if got := svc.created[0][0]; got.Name != "alpha" || !got.Active {
+		t.Fatalf("unexpected first entity: %+v", got)
+	}
+	if got := svc.created[0][1]; got.Name != "gamma" || got.Active {
+		t.Fatalf("unexpected second entity: %+v", got)
+	}
+}
+
+func TestRouteBuilder_ImportCSVRejectsUnknownColumn(t *testing.T) {
+	svc := &transferAppService{stubAppService: newStubAppService(nil)}
+	group := newTransferRoutes(t, svc, nil)
+
+	r := httptest.NewRequest(http.MethodPost, "/items/import?format=csv", strings.NewReader("name,unknown\na,b\n"))
+	w := serveTransfer(t, group, "POST /items/import", r)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+	if len(svc.created) != 0 {
+		t.Fatalf("expected no writes, got %+v", svc.created)
+	}
+}
+
+func TestRouteBuilder_ImportJSONLMultipartWritesInBatches(t *testing.T) {
+	svc := &transferAppService{stubAppService: newStubAppService(nil), failOn: "dup"}
+	group := newTransferRoutes(t, svc, func(cfg *RouteConfig[int64]) {
+		cfg.Transfer.ImportBatchSize = 2
+	})
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", "items.jsonl")
+	if err != nil {
+		t.Fatalf("CreateFormFile returned error: %v", err)
+	}
+	_, _ = part.Write([]byte("{\"name\":\"a\"}\n\n{\"name\":\"b\"}\n{\"name\":\"dup\"}\n{\"bogus\":1}\n{\"name\":\"c\"}"))
+	if err := mw.Close(); err != nil {
+		t.Fatalf("close multipart writer: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/items/import", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	w := serveTransfer(t, group, "POST /items/import", r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	result := decodeImportResult(t, w)
+	if result.Total != 5 || result.Created != 2 || result.Failed != 3 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	rows := make([]int, 0, len(result.Errors))
+	for _, rowErr := range result.Errors {
+		rows = append(rows, rowErr.Row)
+	}
+	// 第 4 行解码失败；第 3、5 行与失败的 dup 同批写入。
+	if len(rows) != 3 || rows[0] != 4 || rows[1] != 3 || rows[2] != 5 {
+		t.Fatalf("unexpected failed rows: %v (%+v)", rows, result.Errors)
+	}
+	if len(svc.created) != 1 || svc.created[0][0].Name != "a" || svc.created[0][1].Name != "b" {
+		t.Fatalf("unexpected created batches: %+v", svc.created)
+	}
+}
+
+func TestRouteBuilder_ImportRejectsTooManyRows(t *testing.T) {
+	svc := &transferAppService{stubAppService: newStubAppService(nil)}
+	group := newTransferRoutes(t, svc, func(cfg *RouteConfig[int64]) {
+		cfg.Transfer.MaxImportRows = 1
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/items/import", strings.NewReader("{\"name\":\"a\"}\n{\"name\":\"b\"}\n"))
+	r.Header.Set("Content-Type", "application/x-ndjson")
+	w := serveTransfer(t, group, "POST /items/import", r)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected status 413, got %d", w.Code)
+	}
+	if len(svc.created) != 0 {
+		t.Fatalf("expected no writes, got %+v", svc.created)
+	}
+}
+
+func TestRouteBuilder_ExportCSVPagesAndTruncates(t *testing.T) {
+	svc := &transferAppService{
+		stubAppService: newStubAppService(nil),
+		items:          []*fakeEntity{{ID: 1, Name: "a,b", Active: true}, {ID: 2, Name: "c"}, {ID: 3, Name: "d"}},
+	}
+	group := newTransferRoutes(t, svc, func(cfg *RouteConfig[int64]) {
+		cfg.Query.EnablePagination = true
+		cfg.Transfer.ExportPageSize = 2
+		cfg.Transfer.MaxExportRows = 2
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/items/export?fields=id,name,active&filter=active:eq:true", nil)
+	w := serveTransfer(t, group, "GET /items/export", r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Body.String(); got != "id,name,active\n1,\"a,b\",true\n2,c,false\n" {
+		t.Fatalf("unexpected csv: %q", got)
+	}
+	if w.Header().Get("X-Export-Truncated") != "true" {
+		t.Fatalf("expected truncated header, got %v", w.Header())
+	}
+	if !strings.Contains(w.Header().Get("Content-Disposition"), `filename="items.csv"`) {
+		t.Fatalf("unexpected content disposition: %q", w.Header().Get("Content-Disposition"))
+	}
+	if len(svc.pageReqs) != 2 || svc.pageReqs[0].Size != 2 || len(svc.pageReqs[0].Filters) != 1 {
+		t.Fatalf("unexpected page requests: %+v", svc.pageReqs)
+	}
+	next := svc.pageReqs[1]
+	if next.Page != 1 || len(next.Filters["id"]) != 1 || next.Filters["id"][0].Op != query.FilterOpGt || next.Filters["id"][0].Value.Int != 2 {
+		t.Fatalf("expected keyset cursor id > 2, got %+v", next)
+	}
+	if len(svc.pageReqs[0].Filters["id"]) != 0 {
+		t.Fatalf("expected first page without cursor, got %+v", svc.pageReqs[0].Filters)
+	}
+}
+
+func TestRouteBuilder_ExportFetchesKeysetFieldWhenProjecting(t *testing.T) {
+	svc := &transferAppService{
+		stubAppService: newStubAppService(nil),
+		items:          []*fakeEntity{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c"}},
+	}
+	group := newTransferRoutes(t, svc, func(cfg *RouteConfig[int64]) {
+		cfg.Transfer.ExportPageSize = 2
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/items/export?fields=name", nil)
+	w := serveTransfer(t, group, "GET /items/export", r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Body.String(); got != "name\na\nb\nc\n" {
+		t.Fatalf("unexpected csv: %q", got)
+	}
+	if len(svc.pageReqs) != 2 {
+		t.Fatalf("expected 2 keyset pages, got %d", len(svc.pageReqs))
+	}
+	first := svc.pageReqs[0]
+	if !slices.Equal(first.Fields, []string{"name", "id"}) {
+		t.Fatalf("expected id fetched for keyset cursor, got %v", first.Fields)
+	}
+	if len(first.Sorts) != 1 || first.Sorts[0].Field != "id" || first.Sorts[0].Direction != query.ASC {
+		t.Fatalf("expected export ordered by id, got %+v", first.Sorts)
+	}
+}
+
+func TestRouteBuilder_ExportJSONLProjectsFields(t *testing.T) {
+	svc := &transferAppService{
+		stubAppService: newStubAppService(nil),
+		items:          []*fakeEntity{{ID: 1, Name: "a"}},
+	}
+	group := newTransferRoutes(t, svc, nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/items/export?format=jsonl&fields=name,id", nil)
+	w := serveTransfer(t, group, "GET /items/export", r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Body.String(); got != "{\"name\":\"a\",\"id\":1}\n" {
+		t.Fatalf("unexpected jsonl: %q", got)
+	}
+	if w.Header().Get("X-Export-Truncated") != "" {
+		t.Fatalf("unexpected truncated header")
+	}
+}
@@ -0,0 +1,82 @@
+package rest
+
+import (
+	"net/http"
+	"strings"
+
+	"gochen/db/query"
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+func (rb *RouteBuilder[T, ID]) handleTrash(c httpx.IContext) error {
+	trash, ok := rb.trash()
+	if !ok {
+		return errors.NewCode(errors.NotFound, "route not enabled")
+	}
+	ctx, err := rb.serviceContext(c)
+	if err != nil {
+		return err
+	}
+	opts, err := rb.parsePaginationOptions(c)
+	if err != nil {
+		return err
+	}
+	data, err := trash.ListDeleted(ctx, opts.Offset(), opts.Size)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, httpx.JSONValue(rb.config.Response.ResponseWrapper(data)))
+}
+
+func (rb *RouteBuilder[T, ID]) handleTrashRestore(c httpx.IContext) error {
+	trash, ok := rb.trash()
+	if !ok {
+		return errors.NewCode(errors.NotFound, "route not enabled")
+	}
+	ctx, err := rb.serviceContext(c)
+	if err != nil {
+		return err
+	}
+	id, err := rb.parseID(c)
+	if err != nil {
+		return err
+	}
+	if err := trash.Restore(ctx, id); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, httpx.JSONValue(rb.config.Response.ResponseWrapper(nil)))
+}
+
+func (rb *RouteBuilder[T, ID]) handleTrashPurge(c httpx.IContext) error {
+	trash, ok := rb.trash()
+	if !ok {
+		return errors.NewCode(errors.NotFound, "route not enabled")
+	}
+	ctx, err := rb.serviceContext(c)
+	if err != nil {
+		return err
+	}
+	id, err := rb.parseID(c)
+	if err != nil {
+		return err
+	}
+	if err := trash.Purge(ctx, id); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, httpx.JSONValue(rb.config.Response.ResponseWrapper(nil)))
+}
+
+// withoutDeleted 为软删实体的列表查询追加 `<SoftDeleteField> IS NULL` 过滤。
+//
+// 过滤随查询下推到仓储，避免在分页之后再剔除记录导致页内条数不足、总数失真。
+func (rb *RouteBuilder[T, ID]) withoutDeleted(filters query.QueryFilters) query.QueryFilters {
+	if !isSoftDeletableEntityType[T]() {
+		return filters
+	}
+	field := strings.TrimSpace(rb.config.Query.SoftDeleteField)
+	if field == "" {
+		return filters
+	}
+	return filters.Merge(query.QueryFilters{field: {{Op: query.FilterOpIsNull}}})
+}
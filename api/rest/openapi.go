@@ -0,0 +1,437 @@
+package rest
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gochen/api/openapi"
+	"gochen/domain"
+	"gochen/domain/audited"
+	"gochen/httpx"
+)
+
+// RouteKind 标识 CRUD 路由在标准模板中的角色。
+type RouteKind string
+
+const (
+	// RouteKindList 表示列表路由。
+	RouteKindList RouteKind = "list"
+	// RouteKindGet 表示详情路由。
+	RouteKindGet RouteKind = "get"
+	// RouteKindCreate 表示创建路由。
+	RouteKindCreate RouteKind = "create"
+	// RouteKindUpdate 表示更新路由。
+	RouteKindUpdate RouteKind = "update"
+	// RouteKindPatch 表示局部更新路由。
+	RouteKindPatch RouteKind = "patch"
+	// RouteKindDelete 表示删除路由。
+	RouteKindDelete RouteKind = "delete"
+	// RouteKindBatchCreate 表示批量创建路由。
+	RouteKindBatchCreate RouteKind = "batch_create"
+	// RouteKindBatchUpdate 表示批量更新路由。
+	RouteKindBatchUpdate RouteKind = "batch_update"
+	// RouteKindBatchDelete 表示批量删除路由。
+	RouteKindBatchDelete RouteKind = "batch_delete"
+	// RouteKindListDeleted 表示 audited 已删除列表路由。
+	RouteKindListDeleted RouteKind = "list_deleted"
+	// RouteKindAuditTrail 表示 audited 审计轨迹路由。
+	RouteKindAuditTrail RouteKind = "audit_trail"
+	// RouteKindChangeHistory 表示 audited 字段级变更历史路由。
+	RouteKindChangeHistory RouteKind = "change_history"
+	// RouteKindRestore 表示 audited 恢复路由。
+	RouteKindRestore RouteKind = "restore"
+	// RouteKindPurge 表示 audited 物理删除路由。
+	RouteKindPurge RouteKind = "purge"
+	// RouteKindTrash 表示软删实体的回收站列表路由。
+	RouteKindTrash RouteKind = "trash"
+	// RouteKindImport 表示批量导入路由。
+	RouteKindImport RouteKind = "import"
+	// RouteKindExport 表示导出路由。
+	RouteKindExport RouteKind = "export"
+)
+
+// RouteInfo 描述一条已注册的 CRUD 路由。
+type RouteInfo struct {
+	// Method 是 HTTP 方法。
+	Method string
+	// Path 是相对路由组的路径（`:id` 风格参数）。
+	Path string
+	// Kind 是路由在 CRUD 模板中的角色。
+	Kind RouteKind
+}
+
+// OpenAPIOptions 定义 CRUD 路由写入 OpenAPI 文档时的可选配置。
+type OpenAPIOptions struct {
+	// PathPrefix 是路由组在服务器上的前缀（如 `/api/v1`）。
+	//
+	// 说明：httpx.IRouteGroup 不暴露自身前缀，文档中的完整路径需要由组合根显式声明。
+	PathPrefix string
+
+	// Tag 是该资源操作的分组标签；为空时取 BasePath 的最后一段。
+	Tag string
+
+	// OperationIDPrefix 是 operationId 前缀；为空时取 Tag。
+	OperationIDPrefix string
+}
+
+// WithOpenAPI 在路由注册成功后把 CRUD(+audited) 端点写入 OpenAPI 文档构建器。
+func WithOpenAPI[T domain.IEntity[ID], ID comparable](doc *openapi.Builder, opts *OpenAPIOptions) Option[T, ID] {
+	return func(rb *ApiBuilder[T, ID]) {
+		rb.openapi = doc
+		rb.openapiOptions = opts
+	}
+}
+
+// DescribeOpenAPI 把一组 CRUD 路由描述写入 OpenAPI 文档构建器。
+//
+// 说明：
+//   - schema 从实体类型 T 的 json/validate/doc tag 推导；
+//   - 成功响应按 DefaultResponseWrapper 的统一信封（code/message/data）描述；
+//   - 列表路由会按 RouteConfig 声明分页参数与 filter/sorts/fields 白名单。
+func DescribeOpenAPI[T domain.IEntity[ID], ID comparable](
+	doc *openapi.Builder,
+	routes []RouteInfo,
+	cfg *RouteConfig[ID],
+	opts *OpenAPIOptions,
+) error {
+	if doc == nil {
+		return nil
+	}
+	if cfg == nil {
+		cfg = DefaultRouteConfig[ID]()
+	}
+	options := OpenAPIOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.Tag == "" {
+		options.Tag = resourceTag(cfg.Routing.BasePath)
+	}
+	if options.OperationIDPrefix == "" {
+		options.OperationIDPrefix = options.Tag
+	}
+
+	d := &openAPIDescriber[T, ID]{
+		doc:       doc,
+		cfg:       cfg,
+		opts:      options,
+		entity:    doc.SchemaFor(reflect.TypeFor[T]()),
+		idSchema:  doc.SchemaFor(reflect.TypeFor[ID]()),
+		errSchema: doc.SchemaFor(reflect.TypeFor[httpx.ResponseMessage]()),
+	}
+	for _, route := range routes {
+		op := d.operation(route)
+		if op == nil {
+			continue
+		}
+		if err := doc.AddOperation(route.Method, openapi.JoinPath(options.PathPrefix, route.Path), op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type openAPIDescriber[T domain.IEntity[ID], ID comparable] struct {
+	doc       *openapi.Builder
+	cfg       *RouteConfig[ID]
+	opts      OpenAPIOptions
+	entity    *openapi.Schema
+	idSchema  *openapi.Schema
+	errSchema *openapi.Schema
+}
+
+func (d *openAPIDescriber[T, ID]) operation(route RouteInfo) *openapi.Operation {
+	op := &openapi.Operation{
+		OperationID: d.opts.OperationIDPrefix + "_" + string(route.Kind),
+		Tags:        []string{d.opts.Tag},
+		Responses:   d.errorResponses(route),
+	}
+	idParam := openapi.Parameter{Name: "id", In: "path", Required: true, Schema: d.idSchema}
+
+	switch route.Kind {
+	case RouteKindList:
+		op.Summary = "List " + d.opts.Tag
+		op.Parameters = d.listParameters(d.cfg.Query.EnablePagination)
+		if d.cfg.Query.EnablePagination {
+			op.Responses["200"] = d.success("OK", pagedSchema(d.entity))
+		} else {
+			op.Responses["200"] = d.success("OK", &openapi.Schema{Type: "array", Items: d.entity})
+		}
+	case RouteKindGet:
+		op.Summary = "Get " + d.opts.Tag + " by id"
+		op.Parameters = []openapi.Parameter{idParam}
+		op.Responses["200"] = d.success("OK", d.entity)
+	case RouteKindCreate:
+		op.Summary = "Create " + d.opts.Tag
+		op.RequestBody = jsonBody(d.entity)
+		status := http.StatusOK
+		if d.cfg.Response.UseHTTP201ForCreate {
+			status = http.StatusCreated
+		}
+		op.Responses[strconv.Itoa(status)] = d.success("Created", d.entity)
+	case RouteKindUpdate:
+		op.Summary = "Update " + d.opts.Tag
+		op.Parameters = []openapi.Parameter{idParam, {
+			Name:        "If-Match",
+			In:          "header",
+			Description: "ETag returned by get/update; rejects the update with 412 when the entity version changed.",
+			Schema:      &openapi.Schema{Type: "string"},
+		}}
+		op.RequestBody = jsonBody(d.entity)
+		op.Responses["200"] = d.success("OK", d.entity)
+	case RouteKindPatch:
+		op.Summary = "Partially update " + d.opts.Tag
+		op.Parameters = []openapi.Parameter{idParam, {
+			Name:        "If-Match",
+			In:          "header",
+			Description: "ETag returned by get/update; rejects the patch with 412 when the entity version changed.",
+			Schema:      &openapi.Schema{Type: "string"},
+		}}
+		operations := &openapi.Schema{Type: "array", Items: &openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"op":    {Type: "string", Enum: []any{"add", "remove", "replace", "move", "copy", "test"}},
+				"path":  {Type: "string"},
+				"from":  {Type: "string"},
+				"value": {},
+			},
+			Required: []string{"op", "path"},
+		}}
+		op.RequestBody = &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{
+			contentTypeMergePatch: {Schema: &openapi.Schema{Type: "object"}},
+			contentTypeJSONPatch:  {Schema: operations},
+		}}
+		op.Responses["200"] = d.success("OK", d.entity)
+	case RouteKindDelete:
+		op.Summary = "Delete " + d.opts.Tag
+		op.Parameters = []openapi.Parameter{idParam}
+		op.Responses["200"] = d.success("OK", nil)
+	case RouteKindBatchCreate:
+		op.Summary = "Batch create " + d.opts.Tag
+		op.RequestBody = jsonBody(&openapi.Schema{Type: "array", Items: d.entity})
+		status := http.StatusOK
+		if d.cfg.Response.UseHTTP201ForCreate {
+			status = http.StatusCreated
+		}
+		op.Responses[strconv.Itoa(status)] = d.success("Created", batchResultSchema(d.idSchema))
+	case RouteKindBatchUpdate:
+		op.Summary = "Batch update " + d.opts.Tag
+		op.RequestBody = jsonBody(&openapi.Schema{Type: "array", Items: d.entity})
+		op.Responses["200"] = d.success("OK", batchResultSchema(nil))
+	case RouteKindBatchDelete:
+		op.Summary = "Batch delete " + d.opts.Tag
+		op.RequestBody = jsonBody(&openapi.Schema{
+			Type:       "object",
+			Properties: map[string]*openapi.Schema{"ids": {Type: "array", Items: d.idSchema}},
+			Required:   []string{"ids"},
+		})
+		op.Responses["200"] = d.success("OK", batchResultSchema(nil))
+	case RouteKindListDeleted:
+		op.Summary = "List deleted " + d.opts.Tag
+		op.Parameters = pageParameters()
+		op.Responses["200"] = d.success("OK", &openapi.Schema{Type: "array", Items: d.entity})
+	case RouteKindTrash:
+		op.Summary = "List " + d.opts.Tag + " in trash"
+		op.Parameters = pageParameters()
+		op.Responses["200"] = d.success("OK", &openapi.Schema{Type: "array", Items: d.entity})
+	case RouteKindAuditTrail:
+		op.Summary = "Get audit trail of " + d.opts.Tag
+		op.Parameters = append([]openapi.Parameter{idParam}, pageParameters()...)
+		record := d.doc.SchemaFor(reflect.TypeFor[audited.AuditRecord]())
+		op.Responses["200"] = d.success("OK", &openapi.Schema{Type: "array", Items: record})
+	case RouteKindChangeHistory:
+		op.Summary = "Get change history of " + d.opts.Tag
+		op.Parameters = append([]openapi.Parameter{idParam}, pageParameters()...)
+		entry := d.doc.SchemaFor(reflect.TypeFor[audited.ChangeEntry]())
+		op.Responses["200"] = d.success("OK", &openapi.Schema{Type: "array", Items: entry})
+	case RouteKindRestore:
+		op.Summary = "Restore deleted " + d.opts.Tag
+		op.Parameters = []openapi.Parameter{idParam}
+		op.Responses["200"] = d.success("OK", nil)
+	case RouteKindPurge:
+		op.Summary = "Purge " + d.opts.Tag
+		op.Parameters = []openapi.Parameter{idParam}
+		op.Responses["200"] = d.success("OK", nil)
+	case RouteKindImport:
+		op.Summary = "Import " + d.opts.Tag + " from CSV or JSONL"
+		op.Parameters = []openapi.Parameter{formatParameter("Upload format; inferred from Content-Type or file extension when omitted.")}
+		text := &openapi.Schema{Type: "string"}
+		op.RequestBody = &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{
+			"text/csv":             {Schema: text},
+			"application/x-ndjson": {Schema: text},
+			"multipart/form-data": {Schema: &openapi.Schema{
+				Type:       "object",
+				Properties: map[string]*openapi.Schema{importFileField: {Type: "string", Format: "binary"}},
+				Required:   []string{importFileField},
+			}},
+		}}
+		op.Responses["200"] = d.success("OK", d.doc.SchemaFor(reflect.TypeFor[ImportResult]()))
+	case RouteKindExport:
+		op.Summary = "Export " + d.opts.Tag + " as CSV or JSONL"
+		op.Parameters = []openapi.Parameter{formatParameter("Export format, defaults to csv.")}
+		for _, param := range d.listParameters(false) {
+			// 导出按主键 keyset 分页，固定按 id 升序输出，不接受 sorts。
+			if param.Name != "sorts" {
+				op.Parameters = append(op.Parameters, param)
+			}
+		}
+		text := &openapi.Schema{Type: "string"}
+		op.Responses["200"] = &openapi.Response{Description: "OK", Content: map[string]openapi.MediaType{
+			"text/csv":             {Schema: text},
+			"application/x-ndjson": {Schema: text},
+		}}
+	default:
+		return nil
+	}
+	return op
+}
+
+func (d *openAPIDescriber[T, ID]) success(description string, data *openapi.Schema) *openapi.Response {
+	envelope := &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"code":    {Type: "string"},
+			"message": {Type: "string"},
+		},
+		Required: []string{"code", "message"},
+	}
+	if data != nil {
+		envelope.Properties["data"] = data
+	}
+	return &openapi.Response{Description: description, Content: openapi.JSONContent(envelope)}
+}
+
+func (d *openAPIDescriber[T, ID]) errorResponses(route RouteInfo) map[string]*openapi.Response {
+	errResp := func(description string) *openapi.Response {
+		return &openapi.Response{Description: description, Content: openapi.JSONContent(d.errSchema)}
+	}
+	responses := map[string]*openapi.Response{
+		"400": errResp("Invalid request"),
+		"500": errResp("Internal error"),
+	}
+	if strings.Contains(route.Path, ":id") {
+		responses["404"] = errResp("Not found")
+	}
+	switch route.Kind {
+	case RouteKindCreate, RouteKindUpdate, RouteKindPatch, RouteKindBatchCreate, RouteKindBatchUpdate:
+		responses["409"] = errResp("Conflict")
+		responses["413"] = errResp("Payload too large")
+	case RouteKindImport:
+		responses["413"] = errResp("Payload too large")
+	}
+	if route.Kind == RouteKindUpdate || route.Kind == RouteKindPatch {
+		responses["412"] = errResp("Precondition failed")
+	}
+	if d.cfg.Authorization != nil {
+		responses["401"] = errResp("Unauthorized")
+		responses["403"] = errResp("Forbidden")
+	}
+	return responses
+}
+
+func (d *openAPIDescriber[T, ID]) listParameters(paged bool) []openapi.Parameter {
+	params := make([]openapi.Parameter, 0, 5)
+	if paged {
+		params = append(params, pageParameters()...)
+	}
+	explode := true
+	params = append(params,
+		openapi.Parameter{
+			Name:        "filter",
+			In:          "query",
+			Description: allowListDescription("Repeatable filter expression `<field>:<op>:<value>`.", d.cfg.Query.AllowedFilterFields),
+			Explode:     &explode,
+			Schema:      &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}},
+		},
+		openapi.Parameter{
+			Name:        "sorts",
+			In:          "query",
+			Description: allowListDescription("Sort spec `field[:asc|desc][,field2[:asc|desc]]`.", d.cfg.Query.AllowedSortFields),
+			Schema:      &openapi.Schema{Type: "string"},
+		},
+		openapi.Parameter{
+			Name:        "fields",
+			In:          "query",
+			Description: allowListDescription("Comma separated field selection.", d.cfg.Query.AllowedFields),
+			Schema:      &openapi.Schema{Type: "string"},
+		},
+	)
+	if paged {
+		if d.cfg.Query.DefaultPageSize > 0 {
+			params[1].Schema.Default = d.cfg.Query.DefaultPageSize
+		}
+		if d.cfg.Query.MaxPageSize > 0 {
+			maxSize := float64(d.cfg.Query.MaxPageSize)
+			params[1].Schema.Maximum = &maxSize
+		}
+	}
+	return params
+}
+
+func formatParameter(description string) openapi.Parameter {
+	return openapi.Parameter{
+		Name:        "format",
+		In:          "query",
+		Description: description,
+		Schema:      &openapi.Schema{Type: "string", Enum: []any{transferFormatCSV, transferFormatJSONL}},
+	}
+}
+
+func pageParameters() []openapi.Parameter {
+	minimum := float64(1)
+	return []openapi.Parameter{
+		{Name: "page", In: "query", Schema: &openapi.Schema{Type: "integer", Minimum: &minimum, Default: 1}},
+		{Name: "size", In: "query", Schema: &openapi.Schema{Type: "integer", Minimum: &minimum}},
+	}
+}
+
+func allowListDescription(base string, allowed []string) string {
+	if len(allowed) == 0 {
+		return base
+	}
+	return base + " Allowed fields: " + strings.Join(allowed, ", ") + "."
+}
+
+func pagedSchema(item *openapi.Schema) *openapi.Schema {
+	return &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"data":        {Type: "array", Items: item},
+			"total":       {Type: "integer", Format: "int64"},
+			"page":        {Type: "integer"},
+			"size":        {Type: "integer"},
+			"total_pages": {Type: "integer"},
+			"has_next":    {Type: "boolean"},
+			"has_prev":    {Type: "boolean"},
+		},
+	}
+}
+
+// batchResultSchema 描述批量写响应；id 为 nil 时只包含 count。
+func batchResultSchema(id *openapi.Schema) *openapi.Schema {
+	schema := &openapi.Schema{
+		Type:       "object",
+		Properties: map[string]*openapi.Schema{"count": {Type: "integer"}},
+	}
+	if id != nil {
+		schema.Properties["ids"] = &openapi.Schema{Type: "array", Items: id}
+	}
+	return schema
+}
+
+func jsonBody(schema *openapi.Schema) *openapi.RequestBody {
+	return &openapi.RequestBody{Required: true, Content: openapi.JSONContent(schema)}
+}
+
+func resourceTag(basePath string) string {
+	trimmed := strings.Trim(strings.TrimSpace(basePath), "/")
+	if idx := strings.LastIndex(trimmed, "/"); idx >= 0 {
+		trimmed = trimmed[idx+1:]
+	}
+	if trimmed == "" {
+		return "resource"
+	}
+	return trimmed
+}
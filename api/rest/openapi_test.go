@@ -0,0 +1,145 @@
+package rest
+
+import (
+	"testing"
+
+	"gochen/api/openapi"
+	appaudited "gochen/app/audited"
+	"gochen/httpx"
+	"gochen/httpx/httpxtest"
+)
+
+// TestApiBuilder_WithOpenAPIDescribesRegisteredRoutes 验证 WithOpenAPI 只描述实际注册的路由。
+func TestApiBuilder_WithOpenAPIDescribesRegisteredRoutes(t *testing.T) {
+	doc := openapi.NewBuilder(openapi.Info{Title: "test", Version: "1"})
+	svc := newStubAppService(nil)
+
+	err := Register[*fakeEntity, int64](
+		httpxtest.NewMockRouteGroup(),
+		svc,
+		WithOpenAPI[*fakeEntity, int64](doc, &OpenAPIOptions{PathPrefix: "/api"}),
+		func(b *ApiBuilder[*fakeEntity, int64]) {
+			b.Route(func(cfg *RouteConfig[int64]) {
+				cfg.Routing.BasePath = "/users"
+				cfg.Routing.EnableBatch = false
+				cfg.Routing.EnableDelete = false
+			})
+		},
+	)
+	if err != nil {
+		t.Fatalf("Register returned error: %v", err)
+	}
+
+	spec := doc.Document()
+	list := spec.Paths["/api/users"]
+	if list == nil || list.Get == nil || list.Post == nil {
+		t.Fatalf("expected list/create operations, got %+v", list)
+	}
+	if list.Get.OperationID != "users_list" {
+		t.Fatalf("unexpected operationId %q", list.Get.OperationID)
+	}
+	names := make(map[string]bool)
+	for _, p := range list.Get.Parameters {
+		names[p.Name] = true
+	}
+	for _, want := range []string{"page", "size", "filter", "sorts", "fields"} {
+		if !names[want] {
+			t.Fatalf("expected list parameter %q, got %+v", want, list.Get.Parameters)
+		}
+	}
+
+	item := spec.Paths["/api/users/{id}"]
+	if item == nil || item.Get == nil || item.Put == nil {
+		t.Fatalf("expected get/update operations, got %+v", item)
+	}
+	if item.Delete != nil {
+		t.Fatalf("delete route disabled but documented")
+	}
+	if _, ok := spec.Paths["/api/users/batch"]; ok {
+		t.Fatalf("batch routes disabled but documented")
+	}
+	if item.Get.Parameters[0].Schema.Type != "integer" {
+		t.Fatalf("expected integer id parameter, got %+v", item.Get.Parameters[0].Schema)
+	}
+	if spec.Components == nil || spec.Components.Schemas["fakeEntity"] == nil {
+		t.Fatalf("expected fakeEntity component schema")
+	}
+}
+
+// TestApiBuilder_WithOpenAPIDescribesAuditedRoutes 验证 audited 扩展端点同样写入文档。
+func TestApiBuilder_WithOpenAPIDescribesAuditedRoutes(t *testing.T) {
+	repo := auditedNoopRepo{}
+	auditedApp, err := appaudited.NewApplication(&repo, nil, nil, stubAuditStore{})
+	if err != nil {
+		t.Fatalf("new audited app: %v", err)
+	}
+	doc := openapi.NewBuilder(openapi.Info{})
+	builder, err := NewApiBuilder[*auditedFakeEntity, int64](auditedApp, WithOpenAPI[*auditedFakeEntity, int64](doc, nil))
+	if err != nil {
+		t.Fatalf("NewApiBuilder returned error: %v", err)
+	}
+	builder.Route(func(cfg *RouteConfig[int64]) {
+		cfg.Routing.BasePath = "/items"
+		cfg.Audit.OperatorExtractor = func(httpx.IContext) (string, bool) { return "tester", true }
+	})
+	if err := builder.Build(httpxtest.NewMockRouteGroup()); err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	spec := doc.Document()
+	if op := spec.Paths["/items/{id}/audit"]; op == nil || op.Get == nil {
+		t.Fatalf("expected audit trail operation")
+	}
+	if op := spec.Paths["/items/{id}/restore"]; op == nil || op.Post == nil {
+		t.Fatalf("expected restore operation")
+	}
+	if op := spec.Paths["/items/deleted"]; op == nil || op.Get == nil {
+		t.Fatalf("expected deleted list operation")
+	}
+}
+
+func TestApiBuilder_WithOpenAPIDescribesTransferAndPatchRoutes(t *testing.T) {
+	doc := openapi.NewBuilder(openapi.Info{})
+	err := Register[*fakeEntity, int64](
+		httpxtest.NewMockRouteGroup(),
+		newStubAppService(nil),
+		WithOpenAPI[*fakeEntity, int64](doc, nil),
+		WithImportExport[*fakeEntity, int64](0),
+		WithPatch[*fakeEntity, int64](),
+		func(b *ApiBuilder[*fakeEntity, int64]) {
+			b.Route(func(cfg *RouteConfig[int64]) { cfg.Routing.BasePath = "/items" })
+		},
+	)
+	if err != nil {
+		t.Fatalf("Register returned error: %v", err)
+	}
+
+	spec := doc.Document()
+	importOp := spec.Paths["/items/import"]
+	if importOp == nil || importOp.Post == nil || importOp.Post.RequestBody == nil {
+		t.Fatalf("expected import operation, got %+v", importOp)
+	}
+	if _, ok := importOp.Post.RequestBody.Content["multipart/form-data"]; !ok {
+		t.Fatalf("expected multipart request body, got %+v", importOp.Post.RequestBody.Content)
+	}
+	if _, ok := importOp.Post.Responses["413"]; !ok {
+		t.Fatalf("expected 413 response on import")
+	}
+	exportOp := spec.Paths["/items/export"]
+	if exportOp == nil || exportOp.Get == nil {
+		t.Fatalf("expected export operation, got %+v", exportOp)
+	}
+	if _, ok := exportOp.Get.Responses["200"].Content["text/csv"]; !ok {
+		t.Fatalf("expected csv export response, got %+v", exportOp.Get.Responses["200"])
+	}
+	patchOp := spec.Paths["/items/{id}"]
+	if patchOp == nil || patchOp.Patch == nil || patchOp.Patch.RequestBody == nil {
+		t.Fatalf("expected patch operation, got %+v", patchOp)
+	}
+	if _, ok := patchOp.Patch.RequestBody.Content["application/json-patch+json"]; !ok {
+		t.Fatalf("expected json-patch request body, got %+v", patchOp.Patch.RequestBody.Content)
+	}
+	if _, ok := patchOp.Patch.Responses["412"]; !ok {
+		t.Fatalf("expected 412 response on patch")
+	}
+}
@@ -0,0 +1,307 @@
+package rest
+
+import (
+	"context"
+
+	auth "gochen/auth"
+	"gochen/db/query"
+	"gochen/domain"
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+// EntityOperations 以与 REST 路由相同的角色、授权、写约束与软删过滤执行实体读写。
+//
+// 供 GraphQL 等不经过 HTTP 路由注册的入口复用，避免在另一套适配层里重新实现授权链路。
+// 调用方负责在 ctx 中绑定主体（以及 audited 实体写操作所需的 operator）。
+type EntityOperations[T domain.IEntity[ID], ID comparable] struct {
+	rb *RouteBuilder[T, ID]
+}
+
+// NewEntityOperations 创建实体操作入口；config 为 nil 时使用 DefaultRouteConfig。
+//
+// 与 Register 一样在创建时校验授权配置与约束写能力，配置不闭环时返回 InvalidInput。
+func NewEntityOperations[T domain.IEntity[ID], ID comparable](svc any, config *RouteConfig[ID]) (*EntityOperations[T, ID], error) {
+	if isNilService(svc) {
+		return nil, errors.NewCode(errors.InvalidInput, "service cannot be nil")
+	}
+	rb := NewRouteBuilder[T, ID](svc).WithConfig(config).(*RouteBuilder[T, ID])
+	if err := rb.validateAuthorizationConfig(); err != nil {
+		return nil, err
+	}
+	if rb.auditedEnabled && rb.auditedService == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "audited entity requires service to implement audited operations")
+	}
+	return &EntityOperations[T, ID]{rb: rb}, nil
+}
+
+// Get 按 ID 读取实体，执行 Get 角色与权限校验。
+func (o *EntityOperations[T, ID]) Get(ctx context.Context, id ID) (T, error) {
+	if err := o.rb.checkRoles(ctx, RouteKindGet); err != nil {
+		var zero T
+		return zero, err
+	}
+	_, entity, err := o.rb.getEntity(nil, ctx, id)
+	return entity, err
+}
+
+// ListByQuery 查询实体列表，执行 List 角色与权限校验并排除软删记录。
+func (o *EntityOperations[T, ID]) ListByQuery(ctx context.Context, req *query.QueryRequest) ([]T, error) {
+	if err := o.rb.checkRoles(ctx, RouteKindList); err != nil {
+		return nil, err
+	}
+	return o.rb.listEntities(nil, ctx, req)
+}
+
+// ListPage 分页查询实体，执行 List 角色与权限校验并排除软删记录。
+func (o *EntityOperations[T, ID]) ListPage(ctx context.Context, req *query.PageRequest) (*query.PagedResult[T], error) {
+	if err := o.rb.checkRoles(ctx, RouteKindList); err != nil {
+		return nil, err
+	}
+	return o.rb.listEntityPage(nil, ctx, req)
+}
+
+// Create 创建实体；配置了 Create 权限时按授权决策的写约束落库。
+func (o *EntityOperations[T, ID]) Create(ctx context.Context, entity T) error {
+	if err := o.rb.checkRoles(ctx, RouteKindCreate); err != nil {
+		return err
+	}
+	ctx, err := o.rb.writeContext(ctx)
+	if err != nil {
+		return err
+	}
+	return o.rb.createEntity(nil, ctx, entity)
+}
+
+// Update 读取当前实体，经 apply 修改后按 Update 权限与写约束保存，返回保存后的实体。
+//
+// 与 PUT 路由一致：修改后的 ID 必须与参数 id 一致（零值时回填），版本还原为读取时的版本。
+func (o *EntityOperations[T, ID]) Update(ctx context.Context, id ID, apply func(entity *T) error) (T, error) {
+	var zero T
+	if err := o.rb.checkRoles(ctx, RouteKindUpdate); err != nil {
+		return zero, err
+	}
+	if apply == nil {
+		return zero, errors.NewCode(errors.InvalidInput, "update apply func cannot be nil")
+	}
+	ctx, err := o.rb.writeContext(ctx)
+	if err != nil {
+		return zero, err
+	}
+	ctx, entity, err := o.rb.loadForUpdate(nil, ctx, id)
+	if err != nil {
+		return zero, err
+	}
+	loadedVersion := entity.GetVersion()
+	if err := apply(&entity); err != nil {
+		return zero, err
+	}
+	if err := ensureEntityIDMatchesPath(&entity, id); err != nil {
+		return zero, err
+	}
+	if err := restoreEntityVersion(&entity, loadedVersion); err != nil {
+		return zero, err
+	}
+	if err := o.rb.validateBody(entity); err != nil {
+		return zero, err
+	}
+	if err := o.rb.updateEntity(nil, ctx, entity); err != nil {
+		return zero, err
+	}
+	return entity, nil
+}
+
+// Delete 删除实体；audited 实体走软删，配置了 Delete 权限时按写约束删除。
+func (o *EntityOperations[T, ID]) Delete(ctx context.Context, id ID) error {
+	if err := o.rb.checkRoles(ctx, RouteKindDelete); err != nil {
+		return err
+	}
+	ctx, err := o.rb.writeContext(ctx)
+	if err != nil {
+		return err
+	}
+	return o.rb.deleteEntity(nil, ctx, id)
+}
+
+// writeContext 为非 HTTP 写入口补齐 audited 约束：与 REST 一致，audited 实体写操作要求 operator。
+func (rb *RouteBuilder[T, ID]) writeContext(ctx context.Context) (context.Context, error) {
+	if rb.auditedEnabled && auth.Operator(ctx) == "" {
+		return nil, errors.NewCode(errors.Validation, "missing operator")
+	}
+	return ctx, nil
+}
+
+// validateBody 执行 RouteConfig.Body.Validator（未配置时放行）。
+func (rb *RouteBuilder[T, ID]) validateBody(entity T) error {
+	if rb.config.Body.Validator == nil {
+		return nil
+	}
+	return rb.config.Body.Validator(entity)
+}
+
+// 以下方法是 REST handler 与 EntityOperations 共享的授权与读写链路；c 为 nil 表示非 HTTP 调用方。
+
+// listEntities 校验 List 权限后查询列表，并下推软删过滤。
+func (rb *RouteBuilder[T, ID]) listEntities(c httpx.IContext, ctx context.Context, req *query.QueryRequest) ([]T, error) {
+	ctx, _, err := rb.authorize(c, ctx, rb.listPermission())
+	if err != nil {
+		return nil, err
+	}
+	listSvc, ok := rb.listService()
+	if !ok {
+		return nil, errors.NewCode(errors.InvalidInput, "list route requires service to implement ListByQuery")
+	}
+	if req == nil {
+		req = &query.QueryRequest{}
+	}
+	req.Filters = rb.withoutDeleted(req.Filters)
+	return listSvc.ListByQuery(ctx, req)
+}
+
+// listEntityPage 校验 List 权限后分页查询，并下推软删过滤。
+func (rb *RouteBuilder[T, ID]) listEntityPage(c httpx.IContext, ctx context.Context, req *query.PageRequest) (*query.PagedResult[T], error) {
+	ctx, _, err := rb.authorize(c, ctx, rb.listPermission())
+	if err != nil {
+		return nil, err
+	}
+	listSvc, ok := rb.pagedListService()
+	if !ok {
+		return nil, errors.NewCode(errors.InvalidInput, "list route with pagination requires service to implement ListPage")
+	}
+	if req == nil {
+		req = &query.PageRequest{Page: 1, Size: 10}
+	}
+	req.Filters = rb.withoutDeleted(req.Filters)
+	return listSvc.ListPage(ctx, req)
+}
+
+// getEntity 按 Get 权限读取实体：仓储支持资源边界时先按边界授权再读取，否则读取后按实体授权。
+func (rb *RouteBuilder[T, ID]) getEntity(c httpx.IContext, ctx context.Context, id ID) (context.Context, T, error) {
+	var zero T
+	getSvc, ok := rb.getService()
+	if !ok {
+		return ctx, zero, errors.NewCode(errors.InvalidInput, "get route requires service to implement Get")
+	}
+	if permission := rb.getPermission(); permission != "" {
+		scopedCtx, resource, ok, err := rb.contextForResourceID(ctx, id)
+		if err != nil {
+			return ctx, zero, err
+		}
+		if ok {
+			ctx = rb.syncRequestContext(c, scopedCtx)
+			if ctx, _, err = rb.authorize(c, ctx, permission, auth.AuthResourceFromBoundary(resource)); err != nil {
+				return ctx, zero, err
+			}
+			entity, err := getSvc.Get(ctx, id)
+			return ctx, entity, err
+		}
+	}
+	entity, err := getSvc.Get(ctx, id)
+	if err != nil {
+		return ctx, zero, err
+	}
+	if ctx, _, err = rb.authorize(c, ctx, rb.getPermission(), entity); err != nil {
+		return ctx, zero, err
+	}
+	return ctx, entity, nil
+}
+
+// createEntity 创建实体；配置了 Create 权限时先授权，再按决策的写约束写入。
+func (rb *RouteBuilder[T, ID]) createEntity(c httpx.IContext, ctx context.Context, entity T) error {
+	if permission := rb.createPermission(); permission != "" {
+		ctx, decision, err := rb.authorize(c, ctx, permission, entity)
+		if err != nil {
+			return err
+		}
+		ctx = auth.BindConstraintMetadata(ctx, decision)
+		writer, _ := rb.writeConstraintWriter()
+		return writer.CreateWithConstraint(ctx, entity, auth.WriteConstraintFromDecision(decision))
+	}
+	createSvc, ok := rb.createService()
+	if !ok {
+		return errors.NewCode(errors.InvalidInput, "create route requires service to implement Create")
+	}
+	return createSvc.Create(ctx, entity)
+}
+
+// loadForUpdate 读取待更新实体；配置了 Update 权限时先把资源边界放进上下文。
+func (rb *RouteBuilder[T, ID]) loadForUpdate(c httpx.IContext, ctx context.Context, id ID) (context.Context, T, error) {
+	var zero T
+	if permission := rb.updatePermission(); permission != "" {
+		scopedCtx, _, ok, err := rb.contextForResourceID(ctx, id)
+		if err != nil {
+			return ctx, zero, err
+		}
+		if ok {
+			ctx = rb.syncRequestContext(c, scopedCtx)
+		}
+	}
+	getSvc, ok := rb.getService()
+	if !ok {
+		return ctx, zero, errors.NewCode(errors.InvalidInput, "update route requires service to implement Get")
+	}
+	entity, err := getSvc.Get(ctx, id)
+	if err != nil {
+		return ctx, zero, err
+	}
+	return ctx, entity, nil
+}
+
+// updateEntity 保存已修改的实体；配置了 Update 权限时先授权，再按决策的写约束写入。
+func (rb *RouteBuilder[T, ID]) updateEntity(c httpx.IContext, ctx context.Context, entity T) error {
+	if permission := rb.updatePermission(); permission != "" {
+		ctx, decision, err := rb.authorize(c, ctx, permission, entity)
+		if err != nil {
+			return err
+		}
+		ctx = auth.BindConstraintMetadata(ctx, decision)
+		writer, _ := rb.writeConstraintWriter()
+		return writer.UpdateWithConstraint(ctx, entity, auth.WriteConstraintFromDecision(decision))
+	}
+	updateSvc, ok := rb.updateService()
+	if !ok {
+		return errors.NewCode(errors.InvalidInput, "update route requires service to implement Update")
+	}
+	return updateSvc.Update(ctx, entity)
+}
+
+// deleteEntity 删除实体：配置了 Delete 权限时按资源边界（或读取实体）授权并走约束删除；
+// 否则 audited 实体走软删，普通实体直接删除。
+func (rb *RouteBuilder[T, ID]) deleteEntity(c httpx.IContext, ctx context.Context, id ID) error {
+	if permission := rb.deletePermission(); permission != "" {
+		var target any
+		scopedCtx, resource, ok, err := rb.contextForResourceID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if ok {
+			ctx = rb.syncRequestContext(c, scopedCtx)
+			target = auth.AuthResourceFromBoundary(resource)
+		} else {
+			getSvc, ok := rb.getService()
+			if !ok {
+				return errors.NewCode(errors.InvalidInput, "authz-enabled delete route requires service to implement Get")
+			}
+			entity, err := getSvc.Get(ctx, id)
+			if err != nil {
+				return err
+			}
+			target = entity
+		}
+		ctx, decision, err := rb.authorize(c, ctx, permission, target)
+		if err != nil {
+			return err
+		}
+		ctx = auth.BindConstraintMetadata(ctx, decision)
+		writer, _ := rb.writeConstraintWriter()
+		return writer.DeleteWithConstraint(ctx, id, auth.WriteConstraintFromDecision(decision))
+	}
+	if rb.auditedEnabled {
+		return rb.auditedService.Delete(ctx, id)
+	}
+	deleteSvc, ok := rb.deleteService()
+	if !ok {
+		return errors.NewCode(errors.InvalidInput, "delete route requires service to implement Delete")
+	}
+	return deleteSvc.Delete(ctx, id)
+}
@@ -21,6 +21,9 @@ type IRouteBuilder[T domain.IEntity[ID], ID comparable] interface {
 
 	// 注册到路由组
 	Register(group httpx.IRouteGroup) error
+
+	// Routes 返回最近一次 Register 实际注册的路由描述（用于文档生成）。
+	Routes() []RouteInfo
 }
 
 // RouteBuilder 路由构建器实现。
@@ -33,6 +36,9 @@ type RouteBuilder[T domain.IEntity[ID], ID comparable] struct {
 	// 注意：audited 能力闭环仍需通过 service 能力校验（见 Register）。
 	auditedEnabled bool
 	auditedService IAuditedService[T, ID]
+
+	// routes 记录 Register 实际注册的路由，供 OpenAPI 等文档生成复用。
+	routes []RouteInfo
 }
 
 // NewRouteBuilder 创建路由构建器。
@@ -92,6 +98,7 @@ func (rb *RouteBuilder[T, ID]) Register(group httpx.IRouteGroup) error {
 	}
 
 	// 注册路由
+	rb.routes = rb.routes[:0]
 	if rb.config.Routing.EnableList || rb.auditedEnabled {
 		rb.registerListRoutes(group)
 	}
@@ -100,10 +107,14 @@ func (rb *RouteBuilder[T, ID]) Register(group httpx.IRouteGroup) error {
 	if rb.hasEnabledBatchRoutes() {
 		rb.registerBatchRoutes(group)
 	}
+	rb.registerTransferRoutes(group)
 
 	if rb.auditedEnabled {
 		rb.registerAuditedRoutes(group)
 	}
+	if rb.config.Routing.EnableTrash {
+		rb.registerTrashRoutes(group)
+	}
 
 	return nil
 }
@@ -136,6 +147,18 @@ func (rb *RouteBuilder[T, ID]) validateServiceCapabilities() error {
 			return errors.NewCode(errors.InvalidInput, "update route requires service to implement Update")
 		}
 	}
+	if rb.config.Routing.EnablePatch {
+		if _, ok := rb.getService(); !ok {
+			return errors.NewCode(errors.InvalidInput, "patch route requires service to implement Get")
+		}
+		if _, ok := rb.patchService(); !ok {
+			return errors.NewCode(errors.InvalidInput, "patch route requires service to implement Patch")
+		}
+		// 局部更新尚无写入约束变体，授权场景下无法把决策下推到仓储，因此直接拒绝。
+		if rb.updatePermission() != "" {
+			return errors.NewCode(errors.InvalidInput, "patch route does not support permission-based authorization; use PUT")
+		}
+	}
 	if rb.config.Routing.EnableDelete {
 		if _, ok := rb.deleteService(); !ok {
 			return errors.NewCode(errors.InvalidInput, "delete route requires service to implement Delete")
@@ -153,6 +176,36 @@ func (rb *RouteBuilder[T, ID]) validateServiceCapabilities() error {
 			return errors.NewCode(errors.InvalidInput, "batch routes require a built-in batch writer or service-specific batch writer")
 		}
 	}
+	if rb.config.Routing.EnableImport {
+		if rb.createPermission() != "" {
+			if _, ok := rb.batchWriteConstraintWriter(); !ok {
+				return errors.NewCode(errors.InvalidInput, "authz-enabled import route requires a write-constraint batch writer")
+			}
+		} else if _, ok := rb.batchWriter(); !ok {
+			return errors.NewCode(errors.InvalidInput, "import route requires a built-in batch writer or service-specific batch writer")
+		}
+	}
+	if rb.config.Routing.EnableTrash {
+		// audited 实体有独立的 deleted/restore/purge 路由（额外记录审计），不复用回收站。
+		if rb.auditedEnabled || !isSoftDeletableEntityType[T]() {
+			return errors.NewCode(errors.InvalidInput, "trash routes require a soft-deletable, non-audited entity")
+		}
+		if _, ok := rb.trash(); !ok {
+			return errors.NewCode(errors.InvalidInput, "trash routes require service to implement appcrud.ITrash or a repository with deleted query and restore support")
+		}
+		// 回收站的恢复/物理删除尚无写入约束变体，授权场景下无法把决策下推到仓储，因此直接拒绝。
+		if rb.listPermission() != "" || rb.updatePermission() != "" || rb.deletePermission() != "" {
+			return errors.NewCode(errors.InvalidInput, "trash routes do not support permission-based authorization")
+		}
+	}
+	if rb.config.Routing.EnableExport {
+		if _, paged := rb.pagedListService(); !paged {
+			return errors.NewCode(errors.InvalidInput, "export route requires service to implement ListPage")
+		}
+		if !supportsExportKeyset[ID]() {
+			return errors.NewCode(errors.InvalidInput, "export route requires an integer or string entity ID")
+		}
+	}
 	return nil
 }
 
@@ -186,12 +239,12 @@ func (rb *RouteBuilder[T, ID]) registerListRoutes(group httpx.IRouteGroup) {
 
 	if rb.config.Routing.EnableList {
 		// GET /resource - 获取列表
-		group.GET(basePath, rb.wrapHandler(rb.handleList))
+		rb.handle(group, "GET", basePath, RouteKindList, rb.handleList)
 	}
 
 	// audited 扩展：GET /resource/deleted - 获取已删除列表
 	if rb.auditedEnabled {
-		group.GET(fmt.Sprintf("%s/deleted", basePath), rb.wrapHandler(rb.handleListDeleted))
+		rb.handle(group, "GET", fmt.Sprintf("%s/deleted", basePath), RouteKindListDeleted, rb.handleListDeleted)
 	}
 }
 
@@ -203,19 +256,23 @@ func (rb *RouteBuilder[T, ID]) registerEntityRoutes(group httpx.IRouteGroup) {
 	}
 
 	if rb.config.Routing.EnableGet {
-		group.GET(fmt.Sprintf("%s/:id", basePath), rb.wrapHandler(rb.handleGet))
+		rb.handle(group, "GET", fmt.Sprintf("%s/:id", basePath), RouteKindGet, rb.handleGet)
 	}
 
 	if rb.config.Routing.EnableCreate {
-		group.POST(basePath, rb.wrapHandler(rb.handleCreate))
+		rb.handle(group, "POST", basePath, RouteKindCreate, rb.handleCreate)
 	}
 
 	if rb.config.Routing.EnableUpdate {
-		group.PUT(fmt.Sprintf("%s/:id", basePath), rb.wrapHandler(rb.handleUpdate))
+		rb.handle(group, "PUT", fmt.Sprintf("%s/:id", basePath), RouteKindUpdate, rb.handleUpdate)
+	}
+
+	if rb.config.Routing.EnablePatch {
+		rb.handle(group, "PATCH", fmt.Sprintf("%s/:id", basePath), RouteKindPatch, rb.handlePatch)
 	}
 
 	if rb.config.Routing.EnableDelete {
-		group.DELETE(fmt.Sprintf("%s/:id", basePath), rb.wrapHandler(rb.handleDelete))
+		rb.handle(group, "DELETE", fmt.Sprintf("%s/:id", basePath), RouteKindDelete, rb.handleDelete)
 	}
 }
 
@@ -232,21 +289,49 @@ func (rb *RouteBuilder[T, ID]) registerAuditedRoutes(group httpx.IRouteGroup) {
 	if rb.config != nil {
 		basePath = rb.config.Routing.BasePath
 	}
-	group.GET(fmt.Sprintf("%s/:id/audit", basePath), rb.wrapHandler(rb.handleAuditTrail))
-	group.POST(fmt.Sprintf("%s/:id/restore", basePath), rb.wrapHandler(rb.handleRestore))
+	rb.handle(group, "GET", fmt.Sprintf("%s/:id/audit", basePath), RouteKindAuditTrail, rb.handleAuditTrail)
+	if _, ok := rb.service.(IChangeHistoryService[ID]); ok {
+		rb.handle(group, "GET", fmt.Sprintf("%s/:id/changes", basePath), RouteKindChangeHistory, rb.handleChangeHistory)
+	}
+	rb.handle(group, "POST", fmt.Sprintf("%s/:id/restore", basePath), RouteKindRestore, rb.handleRestore)
 
 	// 物理删除（purge）为"危险操作"，仅在仓储或自定义 service 明确支持时才注册端点。
 	if rb.auditedService != nil {
 		if provider, ok := rb.repositoryProvider(); ok {
-			if _, ok := provider.Repository().(crud.IPurgeRepository[T, ID]); ok {
-				group.DELETE(fmt.Sprintf("%s/:id/purge", basePath), rb.wrapHandler(rb.handlePurge))
+			if _, ok := crud.RepositoryAs[crud.IPurgeRepository[T, ID]](provider.Repository()); ok {
+				rb.handle(group, "DELETE", fmt.Sprintf("%s/:id/purge", basePath), RouteKindPurge, rb.handlePurge)
 			}
 			return
 		}
-		group.DELETE(fmt.Sprintf("%s/:id/purge", basePath), rb.wrapHandler(rb.handlePurge))
+		rb.handle(group, "DELETE", fmt.Sprintf("%s/:id/purge", basePath), RouteKindPurge, rb.handlePurge)
 	}
 }
 
+// isSoftDeletableEntityType 判断实体类型是否具备软删能力。
+func isSoftDeletableEntityType[T any]() bool {
+	var zero T
+	_, ok := any(zero).(domain.ISoftDeletable)
+	return ok
+}
+
+// registerTrashRoutes 注册软删实体的回收站路由（能力已在 validateServiceCapabilities 中校验）。
+func (rb *RouteBuilder[T, ID]) registerTrashRoutes(group httpx.IRouteGroup) {
+	basePath := ""
+	if rb.config != nil {
+		basePath = rb.config.Routing.BasePath
+	}
+	rb.handle(group, "GET", fmt.Sprintf("%s/trash", basePath), RouteKindTrash, rb.handleTrash)
+	rb.handle(group, "POST", fmt.Sprintf("%s/:id/restore", basePath), RouteKindRestore, rb.handleTrashRestore)
+
+	// 与 audited 一致：标准 app 仅在仓储支持物理删除时注册 purge。
+	if provider, ok := rb.repositoryProvider(); ok {
+		if _, ok := crud.RepositoryAs[crud.IPurgeRepository[T, ID]](provider.Repository()); !ok {
+			return
+		}
+	}
+	rb.handle(group, "DELETE", fmt.Sprintf("%s/:id/purge", basePath), RouteKindPurge, rb.handleTrashPurge)
+}
+
 // registerBatchRoutes 注册批量操作路由。
 func (rb *RouteBuilder[T, ID]) registerBatchRoutes(group httpx.IRouteGroup) {
 	basePath := ""
@@ -255,16 +340,55 @@ func (rb *RouteBuilder[T, ID]) registerBatchRoutes(group httpx.IRouteGroup) {
 	}
 
 	if rb.config.Routing.EnableCreate {
-		group.POST(fmt.Sprintf("%s/batch", basePath), rb.wrapHandler(rb.handleCreateAll))
+		rb.handle(group, "POST", fmt.Sprintf("%s/batch", basePath), RouteKindBatchCreate, rb.handleCreateAll)
 	}
 
 	if rb.config.Routing.EnableUpdate {
-		group.PUT(fmt.Sprintf("%s/batch", basePath), rb.wrapHandler(rb.handleUpdateBatch))
+		rb.handle(group, "PUT", fmt.Sprintf("%s/batch", basePath), RouteKindBatchUpdate, rb.handleUpdateBatch)
 	}
 
 	if rb.config.Routing.EnableDelete {
-		group.DELETE(fmt.Sprintf("%s/batch", basePath), rb.wrapHandler(rb.handleDeleteBatch))
+		rb.handle(group, "DELETE", fmt.Sprintf("%s/batch", basePath), RouteKindBatchDelete, rb.handleDeleteBatch)
+	}
+}
+
+// registerTransferRoutes 注册导入/导出路由。
+func (rb *RouteBuilder[T, ID]) registerTransferRoutes(group httpx.IRouteGroup) {
+	basePath := ""
+	if rb.config != nil {
+		basePath = rb.config.Routing.BasePath
+	}
+
+	if rb.config.Routing.EnableImport {
+		rb.handle(group, "POST", fmt.Sprintf("%s/import", basePath), RouteKindImport, rb.handleImport)
 	}
+
+	if rb.config.Routing.EnableExport {
+		rb.handle(group, "GET", fmt.Sprintf("%s/export", basePath), RouteKindExport, rb.handleExport)
+	}
+}
+
+// handle 注册单条路由并记录其描述。
+func (rb *RouteBuilder[T, ID]) handle(group httpx.IRouteGroup, method, path string, kind RouteKind, handler func(httpx.IContext) error) {
+	wrapped := rb.wrapHandler(rb.requireRoles(kind, handler))
+	switch method {
+	case "GET":
+		group.GET(path, wrapped)
+	case "POST":
+		group.POST(path, wrapped)
+	case "PUT":
+		group.PUT(path, wrapped)
+	case "PATCH":
+		group.PATCH(path, wrapped)
+	case "DELETE":
+		group.DELETE(path, wrapped)
+	}
+	rb.routes = append(rb.routes, RouteInfo{Method: method, Path: path, Kind: kind})
+}
+
+// Routes 返回最近一次 Register 实际注册的路由描述。
+func (rb *RouteBuilder[T, ID]) Routes() []RouteInfo {
+	return append([]RouteInfo(nil), rb.routes...)
 }
 
 // wrapHandler 包装处理器，应用中间件和错误处理。
@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	appaudited "gochen/app/audited"
+	"gochen/domain/audited"
+	"gochen/httpx"
+	"gochen/httpx/httpxtest"
+	"gochen/httpx/nethttp"
+)
+
+// fixedAuditStore 按实体返回预置的审计记录。
+type fixedAuditStore struct {
+	noopAuditStore
+	records []audited.AuditRecord
+}
+
+func (s fixedAuditStore) ListAuditRecordsByEntity(_ context.Context, entityID string, _, _ int) ([]audited.AuditRecord, error) {
+	var out []audited.AuditRecord
+	for _, rec := range s.records {
+		if rec.EntityID == entityID {
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
+
+// TestRouteBuilder_Audited_ChangeHistory 验证 audited 实体注册 /:id/changes 并返回字段级变更历史。
+func TestRouteBuilder_Audited_ChangeHistory(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	store := fixedAuditStore{records: []audited.AuditRecord{
+		{ID: 1, EntityID: "1", Operation: audited.AuditOpCreate, Operator: "alice", Timestamp: at,
+			Changes: json.RawMessage(`{"id":1,"version":0,"name":"draft"}`)},
+		{ID: 2, EntityID: "1", Operation: audited.AuditOpUpdate, Operator: "bob", Timestamp: at.Add(time.Hour),
+			Changes: json.RawMessage(`[{"field":"name","old":"draft","new":"final"}]`)},
+		{ID: 3, EntityID: "1", Operation: audited.AuditOpDelete, Operator: "bob", Timestamp: at.Add(2 * time.Hour)},
+	}}
+	app, err := appaudited.NewApplication[*auditedUpdateEntity, int64](&auditedUpdateRepo{}, nil, nil, store)
+	if err != nil {
+		t.Fatalf("new audited app: %v", err)
+	}
+	builder, err := NewApiBuilder[*auditedUpdateEntity, int64](app, nil)
+	if err != nil {
+		t.Fatalf("NewApiBuilder returned error: %v", err)
+	}
+	builder.Route(func(cfg *RouteConfig[int64]) {
+		cfg.Routing.BasePath = "/items"
+		cfg.Audit.OperatorExtractor = func(httpx.IContext) (string, bool) { return "tester", true }
+	})
+	group := httpxtest.NewMockRouteGroup()
+	if err := builder.Build(group); err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	handler := group.Handlers["GET /items/:id/changes"]
+	if handler == nil {
+		t.Fatalf("expected change history route to be registered")
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/items/1/changes", nil)
+	ctx, err := nethttp.NewBaseContext(w, r)
+	if err != nil {
+		t.Fatalf("NewBaseContext returned error: %v", err)
+	}
+	ctx.SetParam("id", "1")
+	if err := handler(ctx); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var body struct {
+		Data []audited.ChangeEntry `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(body.Data) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", body.Data)
+	}
+	created := body.Data[0]
+	if created.ChangedBy != "alice" || !created.ChangedAt.Equal(at) || len(created.Changes) != 1 ||
+		created.Changes[0].Field != "name" || created.Changes[0].Old != nil || created.Changes[0].New != "draft" {
+		t.Fatalf("unexpected create entry: %+v", created)
+	}
+	updated := body.Data[1]
+	if updated.ChangedBy != "bob" || len(updated.Changes) != 1 ||
+		updated.Changes[0].Old != "draft" || updated.Changes[0].New != "final" {
+		t.Fatalf("unexpected update entry: %+v", updated)
+	}
+	if deleted := body.Data[2]; deleted.Operation != audited.AuditOpDelete || len(deleted.Changes) != 0 {
+		t.Fatalf("unexpected delete entry: %+v", deleted)
+	}
+}
@@ -0,0 +1,149 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	appcrud "gochen/app/crud"
+	"gochen/errors"
+	"gochen/httpx"
+	"gochen/httpx/httpxtest"
+	"gochen/httpx/nethttp"
+)
+
+type patchCapturingRepo struct {
+	updateCapturingRepo
+	gotPatch  *updateTestEntity
+	gotFields []string
+}
+
+// UpdateFields 记录局部更新的实体与字段。
+func (r *patchCapturingRepo) UpdateFields(_ context.Context, e *updateTestEntity, fields []string) error {
+	r.gotPatch = e
+	r.gotFields = fields
+	return nil
+}
+
+func newPatchTestGroup(t *testing.T, repo *patchCapturingRepo, allowed ...string) *httpxtest.MockRouteGroup {
+	t.Helper()
+	svc, err := appcrud.NewApplication[*updateTestEntity, int64](repo, nil, nil)
+	if err != nil {
+		t.Fatalf("new application: %v", err)
+	}
+	builder, err := NewApiBuilder[*updateTestEntity, int64](svc, nil, WithPatch[*updateTestEntity, int64](allowed...))
+	if err != nil {
+		t.Fatalf("NewApiBuilder returned error: %v", err)
+	}
+	builder.Route(func(cfg *RouteConfig[int64]) {
+		cfg.Routing.BasePath = "/items"
+	})
+	group := httpxtest.NewMockRouteGroup()
+	if err := builder.Build(group); err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	return group
+}
+
+func servePatch(t *testing.T, handler httpx.Handler, contentType, ifMatch, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPatch, "/items/1", strings.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	if ifMatch != "" {
+		r.Header.Set("If-Match", ifMatch)
+	}
+	ctx, err := nethttp.NewBaseContext(w, r)
+	if err != nil {
+		t.Fatalf("NewBaseContext returned error: %v", err)
+	}
+	ctx.SetParam("id", "1")
+	if err := handler(ctx); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	return w
+}
+
+// TestRouteBuilder_Patch_MergeAndJSONPatch 验证 Merge Patch 与 JSON Patch 只把触及的字段交给仓储。
+func TestRouteBuilder_Patch_MergeAndJSONPatch(t *testing.T) {
+	repo := &patchCapturingRepo{}
+	group := newPatchTestGroup(t, repo)
+	handler := group.Handlers["PATCH /items/:id"]
+	if handler == nil {
+		t.Fatalf("expected PATCH route to be registered")
+	}
+
+	w := servePatch(t, handler, "application/merge-patch+json", `"0"`, `{"name":"merged"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if repo.gotPatch == nil || repo.gotPatch.ID != 1 || repo.gotPatch.Name != "merged" {
+		t.Fatalf("unexpected patched entity: %+v", repo.gotPatch)
+	}
+	if len(repo.gotFields) != 1 || repo.gotFields[0] != "name" {
+		t.Fatalf("expected fields [name], got %v", repo.gotFields)
+	}
+	if got := w.Header().Get("ETag"); got != `"0"` {
+		t.Fatalf("expected ETag %q, got %q", `"0"`, got)
+	}
+	if repo.updateCalls != 0 {
+		t.Fatalf("expected full Update not called, got %d", repo.updateCalls)
+	}
+
+	w = servePatch(t, handler, "application/json-patch+json", "",
+		`[{"op":"test","path":"/name","value":"existing"},{"op":"replace","path":"/name","value":"replaced"}]`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if repo.gotPatch.Name != "replaced" {
+		t.Fatalf("expected name replaced, got %+v", repo.gotPatch)
+	}
+
+	w = servePatch(t, handler, "application/json-patch+json", "", `[{"op":"test","path":"/name","value":"other"}]`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409 on failed test op, got %d", w.Code)
+	}
+}
+
+// TestRouteBuilder_Patch_RejectsProtectedFields 验证 PATCH 拒绝服务端维护字段、未知字段与白名单外字段。
+func TestRouteBuilder_Patch_RejectsProtectedFields(t *testing.T) {
+	repo := &patchCapturingRepo{}
+	group := newPatchTestGroup(t, repo)
+	handler := group.Handlers["PATCH /items/:id"]
+
+	cases := map[string]struct{ contentType, body string }{
+		"managed version":  {"application/merge-patch+json", `{"version":9}`},
+		"managed id":       {"application/json-patch+json", `[{"op":"replace","path":"/id","value":2}]`},
+		"unknown field":    {"application/merge-patch+json", `{"color":"red"}`},
+		"whole document":   {"application/json-patch+json", `[{"op":"replace","path":"","value":{}}]`},
+		"non-object merge": {"application/merge-patch+json", `["name"]`},
+		"unsupported type": {"text/plain", `{"name":"x"}`},
+	}
+	for name, tc := range cases {
+		w := servePatch(t, handler, tc.contentType, "", tc.body)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d: %s", name, w.Code, w.Body.String())
+		}
+	}
+	if repo.gotPatch != nil {
+		t.Fatalf("expected no write for rejected patches, got %+v", repo.gotPatch)
+	}
+
+	w := servePatch(t, handler, "application/merge-patch+json", `"3"`, `{"name":"stale"}`)
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected status 412, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), string(errors.PreconditionFailed)) {
+		t.Fatalf("expected precondition error body, got %s", w.Body.String())
+	}
+
+	restricted := newPatchTestGroup(t, repo, "id")
+	w = servePatch(t, restricted.Handlers["PATCH /items/:id"], "application/merge-patch+json", "", `{"name":"x"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for field outside whitelist, got %d", w.Code)
+	}
+}
@@ -7,11 +7,11 @@ import (
 	"strings"
 	"testing"
 
-	"gochen/api/rest/internal/testutil"
 	appaudited "gochen/app/audited"
 	"gochen/db/query"
 	"gochen/domain/audited"
 	"gochen/httpx"
+	"gochen/httpx/httpxtest"
 	"gochen/httpx/nethttp"
 )
 
@@ -65,7 +65,7 @@ func TestRouteBuilder_MaxBodySizeEnforced(t *testing.T) {
 		cfg.Body.MaxBodySize = 8
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -107,7 +107,7 @@ func TestRouteBuilder_QueryAllowlistApplied(t *testing.T) {
 		cfg.Query.AllowedFields = []string{"id", "name"}
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -172,7 +172,7 @@ func TestRouteBuilder_QueryMultiSortOrderPreserved(t *testing.T) {
 		cfg.Query.AllowedSortFields = []string{"created_at", "id"}
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -227,7 +227,7 @@ func TestRouteBuilder_AuditTrailPaginationApplied(t *testing.T) {
 		cfg.Audit.OperatorExtractor = func(httpx.IContext) (string, bool) { return "tester", true }
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -269,7 +269,7 @@ func TestRouteBuilder_Delete_RejectsPurgeQueryParam(t *testing.T) {
 		cfg.Routing.BasePath = "/items"
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -313,7 +313,7 @@ func TestRouteBuilder_QueryDisallowedFilterReturnsInvalidInput(t *testing.T) {
 		cfg.Query.AllowedFilterFields = []string{"name", "status"}
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -358,7 +358,7 @@ func TestRouteBuilder_PaginationOptionsAllowlistApplied(t *testing.T) {
 		cfg.Query.AllowedFields = []string{"id"}
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -420,7 +420,7 @@ func TestRouteBuilder_QueryDisallowedSortReturnsInvalidInput(t *testing.T) {
 		cfg.Query.AllowedSortFields = []string{"created_at"}
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -463,7 +463,7 @@ func TestRouteBuilder_QueryDisallowedFieldsReturnsInvalidInput(t *testing.T) {
 		cfg.Query.AllowedFields = []string{"id"}
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -506,7 +506,7 @@ func TestRouteBuilder_QueryInvalidSortDirectionReturnsInvalidInput(t *testing.T)
 		cfg.Query.AllowedSortFields = []string{"created_at"}
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -553,7 +553,7 @@ func TestRouteBuilder_QuerySchemaNormalizesTypedFilters(t *testing.T) {
 		)
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -614,7 +614,7 @@ func TestRouteBuilder_PaginationQuerySchemaDecodesTypedFilters(t *testing.T) {
 		)
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -662,7 +662,7 @@ func TestRouteBuilder_QuerySchemaRejectsInvalidTypedFilter(t *testing.T) {
 		)
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -0,0 +1,377 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	appcrud "gochen/app/crud"
+	auth "gochen/auth"
+	"gochen/db/query"
+	"gochen/errors"
+	"gochen/httpx"
+	"gochen/httpx/httpxtest"
+	"gochen/httpx/nethttp"
+)
+
+type trashTestEntity struct {
+	ID        int64      `json:"id"`
+	Version   uint64     `json:"version"`
+	Name      string     `json:"name"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+func (e *trashTestEntity) GetID() int64             { return e.ID }
+func (e *trashTestEntity) GetVersion() uint64       { return e.Version }
+func (e *trashTestEntity) GetDeletedAt() *time.Time { return e.DeletedAt }
+func (e *trashTestEntity) IsDeleted() bool          { return e.DeletedAt != nil }
+func (e *trashTestEntity) SoftDelete(at time.Time) error {
+	if e.IsDeleted() {
+		return errors.NewCode(errors.Conflict, "entity already deleted")
+	}
+	e.DeletedAt = &at
+	return nil
+}
+func (e *trashTestEntity) Restore() error {
+	if !e.IsDeleted() {
+		return errors.NewCode(errors.Conflict, "entity not deleted")
+	}
+	e.DeletedAt = nil
+	return nil
+}
+
+// trashMemoryRepo 是回收站测试用的内存仓储（Get/ListDeleted 按软删语义过滤）。
+type trashMemoryRepo struct {
+	items       map[int64]*trashTestEntity
+	purged      []int64
+	lastFilters query.QueryFilters
+}
+
+func (r *trashMemoryRepo) Create(_ context.Context, e *trashTestEntity) error {
+	r.items[e.ID] = e
+	return nil
+}
+
+func (r *trashMemoryRepo) Update(_ context.Context, e *trashTestEntity) error {
+	r.items[e.ID] = e
+	return nil
+}
+
+func (r *trashMemoryRepo) Delete(_ context.Context, id int64) error {
+	if e, ok := r.items[id]; ok && !e.IsDeleted() {
+		return e.SoftDelete(time.Now())
+	}
+	return nil
+}
+
+func (r *trashMemoryRepo) Get(_ context.Context, id int64) (*trashTestEntity, error) {
+	if e, ok := r.items[id]; ok && !e.IsDeleted() {
+		return e, nil
+	}
+	return nil, errors.NewCode(errors.NotFound, "record not found")
+}
+
+func (r *trashMemoryRepo) GetWithDeleted(_ context.Context, id int64) (*trashTestEntity, error) {
+	if e, ok := r.items[id]; ok {
+		return e, nil
+	}
+	return nil, errors.NewCode(errors.NotFound, "record not found")
+}
+
+func (r *trashMemoryRepo) ListDeleted(_ context.Context, _, _ int) ([]*trashTestEntity, error) {
+	var out []*trashTestEntity
+	for _, e := range r.items {
+		if e.IsDeleted() {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// List 故意不过滤软删记录，用于验证列表路由把软删过滤下推到查询。
+func (r *trashMemoryRepo) List(_ context.Context, _, _ int) ([]*trashTestEntity, error) {
+	out := make([]*trashTestEntity, 0, len(r.items))
+	for _, e := range r.items {
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// Query 仅识别 deleted_at IS NULL 过滤，其余条件忽略。
+func (r *trashMemoryRepo) Query(_ context.Context, opts query.QueryOptions) ([]*trashTestEntity, error) {
+	r.lastFilters = opts.Filters
+	_, aliveOnly := opts.Filters["deleted_at"]
+	out := make([]*trashTestEntity, 0, len(r.items))
+	for _, e := range r.items {
+		if aliveOnly && e.IsDeleted() {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func (r *trashMemoryRepo) QueryOne(ctx context.Context, opts query.QueryOptions) (*trashTestEntity, error) {
+	items, _ := r.Query(ctx, opts)
+	if len(items) == 0 {
+		return nil, errors.NewCode(errors.NotFound, "record not found")
+	}
+	return items[0], nil
+}
+
+func (r *trashMemoryRepo) QueryCount(ctx context.Context, opts query.QueryOptions) (int64, error) {
+	items, _ := r.Query(ctx, opts)
+	return int64(len(items)), nil
+}
+
+func (r *trashMemoryRepo) Count(context.Context) (int64, error) { return int64(len(r.items)), nil }
+
+func (r *trashMemoryRepo) Exists(_ context.Context, id int64) (bool, error) {
+	_, ok := r.items[id]
+	return ok, nil
+}
+
+func (r *trashMemoryRepo) Purge(_ context.Context, id int64) error {
+	delete(r.items, id)
+	r.purged = append(r.purged, id)
+	return nil
+}
+
+// TestRouteBuilder_SoftDeletableEntity_ExportExcludesDeleted 验证导出与列表一致地下推软删过滤。
+func TestRouteBuilder_SoftDeletableEntity_ExportExcludesDeleted(t *testing.T) {
+	deletedAt := time.Now()
+	repo := &trashMemoryRepo{items: map[int64]*trashTestEntity{
+		1: {ID: 1, Name: "alive"},
+		2: {ID: 2, Name: "trashed", DeletedAt: &deletedAt},
+	}}
+	svc, err := appcrud.NewApplication[*trashTestEntity, int64](repo, nil, nil)
+	if err != nil {
+		t.Fatalf("new application: %v", err)
+	}
+	builder, err := NewApiBuilder[*trashTestEntity, int64](svc, WithImportExport[*trashTestEntity, int64](0))
+	if err != nil {
+		t.Fatalf("NewApiBuilder returned error: %v", err)
+	}
+	builder.Route(func(cfg *RouteConfig[int64]) {
+		cfg.Routing.BasePath = "/items"
+	})
+	group := httpxtest.NewMockRouteGroup()
+	if err := builder.Build(group); err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	w := serveTrash(t, group.Handlers["GET /items/export"], http.MethodGet, "/items/export?format=jsonl&fields=id,name", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Body.String(); got != "{\"id\":1,\"name\":\"alive\"}\n" {
+		t.Fatalf("expected export to exclude deleted rows, got %q", got)
+	}
+	if _, ok := repo.lastFilters["deleted_at"]; !ok {
+		t.Fatalf("expected soft-delete filter pushed down, got %+v", repo.lastFilters)
+	}
+}
+
+func serveTrash(t *testing.T, handler httpx.Handler, method, path, id string) *httptest.ResponseRecorder {
+	t.Helper()
+	if handler == nil {
+		t.Fatalf("route %s %s not registered", method, path)
+	}
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(method, path, nil)
+	ctx, err := nethttp.NewBaseContext(w, r)
+	if err != nil {
+		t.Fatalf("NewBaseContext returned error: %v", err)
+	}
+	if id != "" {
+		ctx.SetParam("id", id)
+	}
+	if err := handler(ctx); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	return w
+}
+
+// TestRouteBuilder_SoftDeletableEntity_TrashRestorePurge 验证显式启用后注册回收站路由，且默认列表排除已删除记录。
+func TestRouteBuilder_SoftDeletableEntity_TrashRestorePurge(t *testing.T) {
+	deletedAt := time.Now()
+	repo := &trashMemoryRepo{items: map[int64]*trashTestEntity{
+		1: {ID: 1, Name: "alive"},
+		2: {ID: 2, Name: "trashed", DeletedAt: &deletedAt},
+	}}
+	svc, err := appcrud.NewApplication[*trashTestEntity, int64](repo, nil, nil)
+	if err != nil {
+		t.Fatalf("new application: %v", err)
+	}
+	builder, err := NewApiBuilder[*trashTestEntity, int64](svc, nil)
+	if err != nil {
+		t.Fatalf("NewApiBuilder returned error: %v", err)
+	}
+	builder.Route(func(cfg *RouteConfig[int64]) {
+		cfg.Routing.BasePath = "/items"
+		cfg.Routing.EnableTrash = true
+		cfg.Query.EnablePagination = false
+	})
+	group := httpxtest.NewMockRouteGroup()
+	if err := builder.Build(group); err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	var listed struct {
+		Data []trashTestEntity `json:"data"`
+	}
+	w := serveTrash(t, group.Handlers["GET /items"], http.MethodGet, "/items", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(listed.Data) != 1 || listed.Data[0].ID != 1 {
+		t.Fatalf("expected list to exclude deleted rows, got %+v", listed.Data)
+	}
+
+	w = serveTrash(t, group.Handlers["GET /items/trash"], http.MethodGet, "/items/trash", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("decode trash: %v", err)
+	}
+	if len(listed.Data) != 1 || listed.Data[0].ID != 2 {
+		t.Fatalf("expected trash to list deleted rows, got %+v", listed.Data)
+	}
+
+	w = serveTrash(t, group.Handlers["POST /items/:id/restore"], http.MethodPost, "/items/2/restore", "2")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected restore 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if repo.items[2].IsDeleted() {
+		t.Fatalf("expected entity 2 restored")
+	}
+	w = serveTrash(t, group.Handlers["POST /items/:id/restore"], http.MethodPost, "/items/2/restore", "2")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected restoring a live entity to return 409, got %d", w.Code)
+	}
+
+	w = serveTrash(t, group.Handlers["DELETE /items/:id/purge"], http.MethodDelete, "/items/1/purge", "1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected purge 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(repo.purged) != 1 || repo.purged[0] != 1 {
+		t.Fatalf("expected entity 1 purged, got %v", repo.purged)
+	}
+}
+
+// TestRouteBuilder_PlainEntity_NoTrashRoutes 验证非软删实体不注册回收站路由。
+func TestRouteBuilder_PlainEntity_NoTrashRoutes(t *testing.T) {
+	svc, err := appcrud.NewApplication[*updateTestEntity, int64](&updateCapturingRepo{}, nil, nil)
+	if err != nil {
+		t.Fatalf("new application: %v", err)
+	}
+	builder, err := NewApiBuilder[*updateTestEntity, int64](svc, nil)
+	if err != nil {
+		t.Fatalf("NewApiBuilder returned error: %v", err)
+	}
+	builder.Route(func(cfg *RouteConfig[int64]) { cfg.Routing.BasePath = "/items" })
+	group := httpxtest.NewMockRouteGroup()
+	if err := builder.Build(group); err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if _, ok := group.Handlers["GET /items/trash"]; ok {
+		t.Fatalf("expected no trash route for non soft-deletable entity")
+	}
+}
+
+func newTrashTestRepo() *trashMemoryRepo {
+	deletedAt := time.Now()
+	return &trashMemoryRepo{items: map[int64]*trashTestEntity{
+		1: {ID: 1, Name: "alive"},
+		2: {ID: 2, Name: "trashed", DeletedAt: &deletedAt},
+	}}
+}
+
+// TestRouteBuilder_SoftDeletableEntity_TrashOptIn 验证回收站路由默认不注册。
+func TestRouteBuilder_SoftDeletableEntity_TrashOptIn(t *testing.T) {
+	svc, err := appcrud.NewApplication[*trashTestEntity, int64](newTrashTestRepo(), nil, nil)
+	if err != nil {
+		t.Fatalf("new application: %v", err)
+	}
+	builder, err := NewApiBuilder[*trashTestEntity, int64](svc, nil)
+	if err != nil {
+		t.Fatalf("NewApiBuilder returned error: %v", err)
+	}
+	builder.Route(func(cfg *RouteConfig[int64]) { cfg.Routing.BasePath = "/items" })
+	group := httpxtest.NewMockRouteGroup()
+	if err := builder.Build(group); err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	for _, key := range []string{"GET /items/trash", "POST /items/:id/restore", "DELETE /items/:id/purge"} {
+		if _, ok := group.Handlers[key]; ok {
+			t.Fatalf("expected %s not registered without EnableTrash", key)
+		}
+	}
+}
+
+// TestRouteBuilder_Trash_RejectsPermissionAuthz 验证配置权限码时拒绝注册回收站路由。
+func TestRouteBuilder_Trash_RejectsPermissionAuthz(t *testing.T) {
+	svc, err := appcrud.NewApplication[*trashTestEntity, int64](newTrashTestRepo(), nil, nil)
+	if err != nil {
+		t.Fatalf("new application: %v", err)
+	}
+	builder, err := NewApiBuilder[*trashTestEntity, int64](svc, nil)
+	if err != nil {
+		t.Fatalf("NewApiBuilder returned error: %v", err)
+	}
+	builder.Route(func(cfg *RouteConfig[int64]) {
+		cfg.Routing.BasePath = "/items"
+		cfg.Routing.EnableTrash = true
+		cfg.Routing.EnableCreate = false
+		cfg.Routing.EnableUpdate = false
+		cfg.Routing.EnableDelete = false
+		cfg.Routing.EnableBatch = false
+		cfg.Authorization = &AuthorizationConfig{
+			Authorizer: newFakeEntityAuthorizer(t, func(context.Context, auth.Principal, string, []auth.Resource) (auth.AuthzDecision, error) {
+				return auth.AllowDecision(), nil
+			}),
+			Permissions: CRUDPermissions{List: "items:list"},
+		}
+	})
+	err = builder.Build(httpxtest.NewMockRouteGroup())
+	if !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected InvalidInput, got %v", err)
+	}
+}
+
+// TestRouteBuilder_SoftDeletableEntity_PagedListPushesFilter 验证分页列表把软删过滤下推到查询，总数不含已删除记录。
+func TestRouteBuilder_SoftDeletableEntity_PagedListPushesFilter(t *testing.T) {
+	repo := newTrashTestRepo()
+	svc, err := appcrud.NewApplication[*trashTestEntity, int64](repo, nil, nil)
+	if err != nil {
+		t.Fatalf("new application: %v", err)
+	}
+	builder, err := NewApiBuilder[*trashTestEntity, int64](svc, nil)
+	if err != nil {
+		t.Fatalf("NewApiBuilder returned error: %v", err)
+	}
+	builder.Route(func(cfg *RouteConfig[int64]) { cfg.Routing.BasePath = "/items" })
+	group := httpxtest.NewMockRouteGroup()
+	if err := builder.Build(group); err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	w := serveTrash(t, group.Handlers["GET /items"], http.MethodGet, "/items", "")
+	var paged struct {
+		Data struct {
+			Data  []trashTestEntity `json:"data"`
+			Total int64             `json:"total"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &paged); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if paged.Data.Total != 1 || len(paged.Data.Data) != 1 {
+		t.Fatalf("expected 1 live row and total 1, got %+v (%s)", paged.Data, w.Body.String())
+	}
+	exprs := repo.lastFilters["deleted_at"]
+	if len(exprs) != 1 || exprs[0].Op != query.FilterOpIsNull {
+		t.Fatalf("expected deleted_at IS NULL pushed to query, got %+v", repo.lastFilters)
+	}
+}
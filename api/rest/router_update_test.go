@@ -7,7 +7,6 @@ import (
 	"strings"
 	"testing"
 
-	"gochen/api/rest/internal/testutil"
 	appaudited "gochen/app/audited"
 	appcrud "gochen/app/crud"
 	"gochen/contextx"
@@ -15,6 +14,7 @@ import (
 	"gochen/domain/crud"
 	"gochen/errors"
 	"gochen/httpx"
+	"gochen/httpx/httpxtest"
 	"gochen/httpx/nethttp"
 )
 
@@ -40,6 +40,7 @@ type updateCapturingRepo struct {
 	gotUpdate   *updateTestEntity
 	updateCalls int
 	getCalls    int
+	updateErr   error
 }
 
 var _ crud.IRepository[*updateTestEntity, int64] = (*updateCapturingRepo)(nil)
@@ -64,7 +65,7 @@ func (r *updateCapturingRepo) Create(_ context.Context, _ *updateTestEntity) err
 func (r *updateCapturingRepo) Update(_ context.Context, e *updateTestEntity) error {
 	r.updateCalls++
 	r.gotUpdate = e
-	return nil
+	return r.updateErr
 }
 
 // Delete 删除实体并同步到存储。
@@ -139,7 +140,7 @@ func TestRouteBuilder_Update_RejectsMismatchedBodyID(t *testing.T) {
 		cfg.Routing.BasePath = "/items"
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -164,6 +165,121 @@ func TestRouteBuilder_Update_RejectsMismatchedBodyID(t *testing.T) {
 	}
 }
 
+// TestRouteBuilder_Update_IfMatchPrecondition 验证 If-Match 与当前版本不符时返回 412，匹配时更新并返回 ETag。
+func TestRouteBuilder_Update_IfMatchPrecondition(t *testing.T) {
+	repo := &updateCapturingRepo{}
+	svc, err := appcrud.NewApplication[*updateTestEntity, int64](repo, nil, nil)
+	if err != nil {
+		t.Fatalf("new application: %v", err)
+	}
+
+	builder, err := NewApiBuilder[*updateTestEntity, int64](svc, nil)
+	if err != nil {
+		t.Fatalf("NewApiBuilder returned error: %v", err)
+	}
+	builder.Route(func(cfg *RouteConfig[int64]) {
+		cfg.Routing.BasePath = "/items"
+	})
+
+	group := httpxtest.NewMockRouteGroup()
+	if err := builder.Build(group); err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	serve := func(method, ifMatch string, handler httpx.Handler) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, "/items/1", strings.NewReader(`{"name":"ok"}`))
+		if ifMatch != "" {
+			r.Header.Set("If-Match", ifMatch)
+		}
+		ctx, err := nethttp.NewBaseContext(w, r)
+		if err != nil {
+			t.Fatalf("NewBaseContext returned error: %v", err)
+		}
+		ctx.SetParam("id", "1")
+		if err := handler(ctx); err != nil {
+			t.Fatalf("handler returned error: %v", err)
+		}
+		return w
+	}
+
+	w := serve(http.MethodGet, "", group.Handlers["GET /items/:id"])
+	if got := w.Header().Get("ETag"); got != `"0"` {
+		t.Fatalf("expected get ETag %q, got %q", `"0"`, got)
+	}
+
+	w = serve(http.MethodPut, `"3"`, group.Handlers["PUT /items/:id"])
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected status 412, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), string(errors.PreconditionFailed)) {
+		t.Fatalf("expected precondition error body, got %s", w.Body.String())
+	}
+	if repo.updateCalls != 0 {
+		t.Fatalf("expected Update not called on stale If-Match, got %d", repo.updateCalls)
+	}
+
+	w = serve(http.MethodPut, `"9", W/"0"`, group.Handlers["PUT /items/:id"])
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if repo.updateCalls != 1 {
+		t.Fatalf("expected Update called once, got %d", repo.updateCalls)
+	}
+	if got := w.Header().Get("ETag"); got != `"0"` {
+		t.Fatalf("expected update ETag %q, got %q", `"0"`, got)
+	}
+}
+
+// TestRouteBuilder_Update_VersionComesFromLoadedEntity 验证请求体不能改写 version，
+// 且 If-Match 校验后写入时的并发冲突同样返回 412。
+func TestRouteBuilder_Update_VersionComesFromLoadedEntity(t *testing.T) {
+	repo := &updateCapturingRepo{}
+	svc, err := appcrud.NewApplication[*updateTestEntity, int64](repo, nil, nil)
+	if err != nil {
+		t.Fatalf("new application: %v", err)
+	}
+	builder, err := NewApiBuilder[*updateTestEntity, int64](svc, nil)
+	if err != nil {
+		t.Fatalf("NewApiBuilder returned error: %v", err)
+	}
+	builder.Route(func(cfg *RouteConfig[int64]) {
+		cfg.Routing.BasePath = "/items"
+	})
+	group := httpxtest.NewMockRouteGroup()
+	if err := builder.Build(group); err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	put := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPut, "/items/1", strings.NewReader(body))
+		r.Header.Set("If-Match", `"0"`)
+		ctx, err := nethttp.NewBaseContext(w, r)
+		if err != nil {
+			t.Fatalf("NewBaseContext returned error: %v", err)
+		}
+		ctx.SetParam("id", "1")
+		if err := group.Handlers["PUT /items/:id"](ctx); err != nil {
+			t.Fatalf("handler returned error: %v", err)
+		}
+		return w
+	}
+
+	if w := put(`{"name":"ok","version":7}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for body version override, got %d: %s", w.Code, w.Body.String())
+	}
+	if repo.updateCalls != 0 {
+		t.Fatalf("expected Update not called on body version override, got %d", repo.updateCalls)
+	}
+
+	repo.updateErr = errors.NewCode(errors.Concurrency, "concurrent modification detected")
+	w := put(`{"name":"ok"}`)
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected status 412 on write conflict, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
 // TestRouteBuilder_Update_KeepsPathIDWhenBodyOmitsID 验证 RouteBuilder Update KeepsPathIDWhenBodyOmitsID。
 func TestRouteBuilder_Update_KeepsPathIDWhenBodyOmitsID(t *testing.T) {
 	repo := &updateCapturingRepo{}
@@ -180,7 +296,7 @@ func TestRouteBuilder_Update_KeepsPathIDWhenBodyOmitsID(t *testing.T) {
 		cfg.Routing.BasePath = "/items"
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -445,7 +561,7 @@ func TestRouteBuilder_AuditedUpdate_RequiresVersion(t *testing.T) {
 		cfg.Audit.OperatorExtractor = func(httpx.IContext) (string, bool) { return "tester", true }
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -487,7 +603,7 @@ func TestRouteBuilder_AuditedUpdate_RejectsMismatchedVersion(t *testing.T) {
 		cfg.Audit.OperatorExtractor = func(httpx.IContext) (string, bool) { return "tester", true }
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -529,7 +645,7 @@ func TestRouteBuilder_AuditedUpdate_RejectsAuditFields(t *testing.T) {
 		cfg.Audit.OperatorExtractor = func(httpx.IContext) (string, bool) { return "tester", true }
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
@@ -571,7 +687,7 @@ func TestRouteBuilder_AuditedUpdate_SucceedsWithMatchingVersion(t *testing.T) {
 		cfg.Audit.OperatorExtractor = func(httpx.IContext) (string, bool) { return "tester", true }
 	})
 
-	group := testutil.NewMockRouteGroup()
+	group := httpxtest.NewMockRouteGroup()
 	if err := builder.Build(group); err != nil {
 		t.Fatalf("build failed: %v", err)
 	}
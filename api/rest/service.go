@@ -8,6 +8,7 @@ import (
 	"gochen/db/query"
 	"gochen/domain"
 	"gochen/domain/audited"
+	"gochen/domain/crud"
 )
 
 // IListService 表示列表查询路由需要的最小能力。
@@ -35,6 +36,11 @@ type IUpdateService[T domain.IEntity[ID], ID comparable] interface {
 	Update(ctx context.Context, entity T) error
 }
 
+// IPatchService 表示局部更新路由需要的最小能力。
+type IPatchService[T domain.IEntity[ID], ID comparable] interface {
+	Patch(ctx context.Context, entity T, fields []string) error
+}
+
 // IDeleteService 表示删除路由需要的最小能力。
 type IDeleteService[ID comparable] interface {
 	Delete(ctx context.Context, id ID) error
@@ -56,6 +62,11 @@ type IAuditedService[T any, ID comparable] interface {
 	AuditStore() audited.IAuditStore
 }
 
+// IChangeHistoryService 表示变更历史路由需要的最小能力（app/audited.Application 已实现）。
+type IChangeHistoryService[ID comparable] interface {
+	ChangeHistory(ctx context.Context, id ID, offset, limit int) ([]audited.ChangeEntry, error)
+}
+
 func isNilService(svc any) bool {
 	if svc == nil {
 		return true
@@ -94,11 +105,31 @@ func (rb *RouteBuilder[T, ID]) updateService() (IUpdateService[T, ID], bool) {
 	return svc, ok
 }
 
+func (rb *RouteBuilder[T, ID]) patchService() (IPatchService[T, ID], bool) {
+	svc, ok := rb.service.(IPatchService[T, ID])
+	return svc, ok
+}
+
 func (rb *RouteBuilder[T, ID]) deleteService() (IDeleteService[ID], bool) {
 	svc, ok := rb.service.(IDeleteService[ID])
 	return svc, ok
 }
 
+// trash 返回回收站能力：标准 app 在仓储支持已删除查询与恢复时自动装配，自定义 service 需实现 appcrud.ITrash。
+func (rb *RouteBuilder[T, ID]) trash() (appcrud.ITrash[T, ID], bool) {
+	if app, ok := rb.service.(*appcrud.Application[T, ID]); ok {
+		if _, ok := crud.RepositoryAs[audited.IDeletedQueryRepository[T, ID]](app.Repository()); !ok {
+			return nil, false
+		}
+		if _, ok := crud.RepositoryAs[audited.IRestoreRepository[T, ID]](app.Repository()); !ok {
+			return nil, false
+		}
+		return appcrud.NewTrash(app), true
+	}
+	svc, ok := rb.service.(appcrud.ITrash[T, ID])
+	return svc, ok
+}
+
 func (rb *RouteBuilder[T, ID]) repositoryProvider() (appcrud.IRepositoryProvider[T, ID], bool) {
 	svc, ok := rb.service.(appcrud.IRepositoryProvider[T, ID])
 	return svc, ok
@@ -0,0 +1,283 @@
+// Package sagaadmin 提供 Saga 运维/排障 HTTP 端点，基于 process/saga.ISagaStateStore。
+//
+// 路由：
+//   - `GET {BasePath}`：列出 Saga（查询参数 status、type、updated_before、limit、offset）；
+//   - `GET {BasePath}/:id`：查看 Saga 状态与逐步骤进度；
+//   - `POST {BasePath}/:id/resume`：从当前步骤恢复执行（SagaOrchestrator.Resume）；
+//   - `POST {BasePath}/:id/compensate`：人工触发补偿（SagaOrchestrator.Compensate）。
+//
+// resume/compensate 需要根据持久化状态重建 ISaga 定义，通过 Config.Factories 按 SagaType 注册；
+// 两者在请求内同步执行。端点可修改业务状态，挂载时应配合认证/授权中间件。
+package sagaadmin
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gochen/errors"
+	"gochen/httpx"
+	"gochen/process/saga"
+)
+
+const (
+	// DefaultBasePath 是 Saga 运维端点的默认路径前缀。
+	DefaultBasePath = "/sagas"
+	// DefaultPageSize 是列表默认每页条数。
+	DefaultPageSize = 50
+	// MaxPageSize 是列表单页条数上限。
+	MaxPageSize = 500
+
+	// QueryStatus 是状态过滤参数名。
+	QueryStatus = "status"
+	// QueryType 是 Saga 类型过滤参数名。
+	QueryType = "type"
+	// QueryUpdatedBefore 是更新时间过滤参数名（RFC3339），用于查找长时间未推进的 Saga。
+	QueryUpdatedBefore = "updated_before"
+	// QueryLimit / QueryOffset 是分页参数名。
+	QueryLimit  = "limit"
+	QueryOffset = "offset"
+)
+
+// 步骤进度状态。
+const (
+	StepStatusPending   = "pending"
+	StepStatusCompleted = "completed"
+	StepStatusSkipped   = "skipped"
+	StepStatusFailed    = "failed"
+)
+
+// SagaFactory 根据持久化状态重建 Saga 定义（通常从 state.SagaID / state.Data 还原业务参数）。
+type SagaFactory func(ctx context.Context, state *saga.SagaState) (saga.ISaga, error)
+
+// Config 定义 Saga 运维端点配置。
+type Config struct {
+	// BasePath 是路由前缀；为空时使用 DefaultBasePath。
+	BasePath string
+
+	// Factories 按 SagaType（即 saga.TypeName 的结果）注册 Saga 定义工厂；
+	// 未注册的类型只能查看，不能 resume/compensate，详情中也不展示逐步骤进度。
+	Factories map[string]SagaFactory
+}
+
+// StepView 是单个步骤（或并行分支）的进度。
+type StepView struct {
+	Name     string     `json:"name"`
+	Status   string     `json:"status"`
+	Branches []StepView `json:"branches,omitempty"`
+}
+
+// SagaView 是 Saga 详情：持久化状态 + 按定义展开的步骤进度。
+type SagaView struct {
+	State *saga.SagaState `json:"state"`
+	Steps []StepView      `json:"steps,omitempty"`
+}
+
+// SagaList 是 Saga 列表的一页（按更新时间倒序）。
+type SagaList struct {
+	Items  []*saga.SagaState `json:"items"`
+	Total  int               `json:"total"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
+
+// Registrar 把 Saga 状态存储与编排器暴露为运维端点，实现 host 模块的路由注册器约定。
+type Registrar struct {
+	store        saga.ISagaStateStore
+	orchestrator *saga.SagaOrchestrator
+	config       Config
+}
+
+// NewRegistrar 创建 Saga 运维路由注册器；orchestrator 为 nil 时只注册只读端点。
+func NewRegistrar(store saga.ISagaStateStore, orchestrator *saga.SagaOrchestrator, cfg *Config) *Registrar {
+	config := Config{}
+	if cfg != nil {
+		config = *cfg
+	}
+	config.BasePath = strings.TrimRight(strings.TrimSpace(config.BasePath), "/")
+	if config.BasePath == "" {
+		config.BasePath = DefaultBasePath
+	}
+	return &Registrar{store: store, orchestrator: orchestrator, config: config}
+}
+
+// RegisterRoutes 注册 Saga 运维端点。
+func (r *Registrar) RegisterRoutes(group httpx.IRouteGroup) error {
+	if group == nil {
+		return errors.NewCode(errors.InvalidInput, "route group cannot be nil")
+	}
+	if r.store == nil {
+		return errors.NewCode(errors.InvalidInput, "saga state store cannot be nil")
+	}
+	group.GET(r.config.BasePath, r.handleList)
+	group.GET(r.config.BasePath+"/:id", r.handleGet)
+	if r.orchestrator != nil {
+		group.POST(r.config.BasePath+"/:id/resume", r.handleResume)
+		group.POST(r.config.BasePath+"/:id/compensate", r.handleCompensate)
+	}
+	return nil
+}
+
+func (r *Registrar) handleList(c httpx.IContext) error {
+	status := saga.SagaStatus(strings.TrimSpace(c.Query(QueryStatus)))
+	sagaType := strings.TrimSpace(c.Query(QueryType))
+	var updatedBefore time.Time
+	if raw := strings.TrimSpace(c.Query(QueryUpdatedBefore)); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return errors.Wrap(err, errors.InvalidInput, "query parameter must be an RFC3339 time").
+				WithContext("param", QueryUpdatedBefore)
+		}
+		updatedBefore = parsed
+	}
+	limit, err := intQuery(c, QueryLimit, DefaultPageSize)
+	if err != nil {
+		return err
+	}
+	limit = min(max(limit, 1), MaxPageSize)
+	offset, err := intQuery(c, QueryOffset, 0)
+	if err != nil {
+		return err
+	}
+
+	states, err := r.store.List(c.RequestContext(), status)
+	if err != nil {
+		return err
+	}
+	filtered := make([]*saga.SagaState, 0, len(states))
+	for _, state := range states {
+		if state == nil {
+			continue
+		}
+		if sagaType != "" && state.SagaType != sagaType {
+			continue
+		}
+		if !updatedBefore.IsZero() && !state.UpdatedAt.Before(updatedBefore) {
+			continue
+		}
+		filtered = append(filtered, state)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		if !filtered[i].UpdatedAt.Equal(filtered[j].UpdatedAt) {
+			return filtered[i].UpdatedAt.After(filtered[j].UpdatedAt)
+		}
+		return filtered[i].SagaID < filtered[j].SagaID
+	})
+
+	page := SagaList{Items: []*saga.SagaState{}, Total: len(filtered), Limit: limit, Offset: offset}
+	if offset < len(filtered) {
+		page.Items = filtered[offset:min(offset+limit, len(filtered))]
+	}
+	return c.JSON(http.StatusOK, httpx.JSONValue(page))
+}
+
+func (r *Registrar) handleGet(c httpx.IContext) error {
+	ctx := c.RequestContext()
+	state, err := r.loadState(ctx, c)
+	if err != nil {
+		return err
+	}
+	view := SagaView{State: state}
+	if factory, ok := r.config.Factories[state.SagaType]; ok {
+		definition, err := factory(ctx, state.Clone())
+		if err != nil {
+			return err
+		}
+		view.Steps = stepViews(state, definition.Steps())
+	}
+	return c.JSON(http.StatusOK, httpx.JSONValue(view))
+}
+
+func (r *Registrar) handleResume(c httpx.IContext) error {
+	return r.runAction(c, r.orchestrator.Resume)
+}
+
+func (r *Registrar) handleCompensate(c httpx.IContext) error {
+	return r.runAction(c, r.orchestrator.Compensate)
+}
+
+// runAction 重建 Saga 定义后执行运维动作，返回动作结束后的最新状态。
+func (r *Registrar) runAction(c httpx.IContext, action func(context.Context, saga.ISaga, *saga.SagaState) error) error {
+	ctx := c.RequestContext()
+	state, err := r.loadState(ctx, c)
+	if err != nil {
+		return err
+	}
+	factory, ok := r.config.Factories[state.SagaType]
+	if !ok {
+		return errors.NewCode(errors.Unsupported, "saga type has no registered factory").
+			WithContext("saga_type", state.SagaType)
+	}
+	definition, err := factory(ctx, state.Clone())
+	if err != nil {
+		return err
+	}
+	if err := action(ctx, definition, state); err != nil {
+		return err
+	}
+	latest, err := r.store.Load(ctx, state.SagaID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, httpx.JSONValue(latest))
+}
+
+func (r *Registrar) loadState(ctx context.Context, c httpx.IContext) (*saga.SagaState, error) {
+	sagaID := strings.TrimSpace(c.Param("id"))
+	if sagaID == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "parameter id cannot be empty")
+	}
+	return r.store.Load(ctx, sagaID)
+}
+
+// stepViews 按 Saga 定义展开步骤进度。
+func stepViews(state *saga.SagaState, steps []*saga.SagaStep) []StepView {
+	views := make([]StepView, 0, len(steps))
+	for i, step := range steps {
+		if step == nil {
+			continue
+		}
+		view := StepView{Name: step.Name, Status: stepStatus(state, step.Name, i)}
+		for _, branch := range step.Parallel {
+			if branch == nil {
+				continue
+			}
+			branchStatus := view.Status
+			if state.IsStepSkipped(branch.Name) {
+				branchStatus = StepStatusSkipped
+			}
+			view.Branches = append(view.Branches, StepView{Name: branch.Name, Status: branchStatus})
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+func stepStatus(state *saga.SagaState, name string, index int) string {
+	switch {
+	case state.IsStepSkipped(name):
+		return StepStatusSkipped
+	case index < state.CurrentStep:
+		return StepStatusCompleted
+	case state.FailedStep == name:
+		return StepStatusFailed
+	default:
+		return StepStatusPending
+	}
+}
+
+func intQuery(c httpx.IContext, name string, def int) (int, error) {
+	raw := strings.TrimSpace(c.Query(name))
+	if raw == "" {
+		return def, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, errors.NewCode(errors.InvalidInput, "query parameter must be a non-negative integer").
+			WithContext("param", name)
+	}
+	return value, nil
+}
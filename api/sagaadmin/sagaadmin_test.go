@@ -0,0 +1,122 @@
+package sagaadmin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gochen/httpx/httpxtest"
+	"gochen/messaging/command"
+	"gochen/process/saga"
+)
+
+type orderSaga struct {
+	saga.BaseSaga
+	id string
+}
+
+func (s *orderSaga) ID() string { return s.id }
+
+func (s *orderSaga) Steps() []*saga.SagaStep {
+	step := func(name string) *saga.SagaStep {
+		return saga.NewSagaStep(name, func(ctx context.Context) (*command.Command, error) {
+			return command.NewCommand(name+"-"+s.id, name, s.id, "Order", nil), nil
+		}).WithCompensation(func(ctx context.Context) (*command.Command, error) {
+			return command.NewCommand("undo-"+name+"-"+s.id, "Undo"+name, s.id, "Order", nil), nil
+		})
+	}
+	return []*saga.SagaStep{step("Reserve"), step("Charge")}
+}
+
+func TestRegistrar_SagaAdmin(t *testing.T) {
+	ctx := context.Background()
+	store := saga.NewMemorySagaStateStore()
+	sagaType := saga.TypeName(&orderSaga{})
+
+	// 两个卡在第二步的 Saga 与一个已完成的 Saga
+	for _, id := range []string{"o-1", "o-2"} {
+		state := saga.NewSagaState(id, sagaType)
+		state.Status = saga.SagaStatusRunning
+		state.MarkStepCompleted("Reserve")
+		if err := store.Save(ctx, state); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+	done := saga.NewSagaState("o-3", sagaType)
+	done.MarkCompleted()
+	if err := store.Save(ctx, done); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	executed := map[string]int{}
+	executor := command.NewCommandExecutor()
+	for _, cmdType := range []string{"Reserve", "Charge", "UndoReserve", "UndoCharge"} {
+		if err := executor.RegisterHandler(cmdType, func(ctx context.Context, cmd *command.Command) error {
+			executed[cmd.GetType()]++
+			return nil
+		}); err != nil {
+			t.Fatalf("register handler: %v", err)
+		}
+	}
+	orchestrator := saga.NewSagaOrchestrator(executor, nil, store)
+
+	group := httpxtest.NewMockRouteGroup()
+	registrar := NewRegistrar(store, orchestrator, &Config{Factories: map[string]SagaFactory{
+		sagaType: func(ctx context.Context, state *saga.SagaState) (saga.ISaga, error) {
+			return &orderSaga{id: state.SagaID}, nil
+		},
+	}})
+	if err := registrar.RegisterRoutes(group); err != nil {
+		t.Fatalf("register routes: %v", err)
+	}
+
+	w := httpxtest.Serve(t, group.Handlers["GET /sagas"], httptest.NewRequest(http.MethodGet, "/sagas?status=running&limit=1", nil), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var list SagaList
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if list.Total != 2 || len(list.Items) != 1 {
+		t.Fatalf("unexpected list page: total=%d items=%d", list.Total, len(list.Items))
+	}
+
+	w = httpxtest.Serve(t, group.Handlers["GET /sagas/:id"], httptest.NewRequest(http.MethodGet, "/sagas/o-1", nil), map[string]string{"id": "o-1"})
+	var view SagaView
+	if err := json.Unmarshal(w.Body.Bytes(), &view); err != nil {
+		t.Fatalf("decode view: %v", err)
+	}
+	if len(view.Steps) != 2 || view.Steps[0].Status != StepStatusCompleted || view.Steps[1].Status != StepStatusPending {
+		t.Fatalf("unexpected steps: %+v", view.Steps)
+	}
+
+	w = httpxtest.Serve(t, group.Handlers["POST /sagas/:id/resume"], httptest.NewRequest(http.MethodPost, "/sagas/o-1/resume", nil), map[string]string{"id": "o-1"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("resume: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	resumed, _ := store.Load(ctx, "o-1")
+	if resumed.Status != saga.SagaStatusCompleted || executed["Charge"] != 1 {
+		t.Fatalf("resume did not complete saga: status=%s executed=%v", resumed.Status, executed)
+	}
+
+	w = httpxtest.Serve(t, group.Handlers["POST /sagas/:id/compensate"], httptest.NewRequest(http.MethodPost, "/sagas/o-2/compensate", nil), map[string]string{"id": "o-2"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("compensate: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	compensated, _ := store.Load(ctx, "o-2")
+	if compensated.Status != saga.SagaStatusCompensated || executed["UndoReserve"] != 1 || executed["UndoCharge"] != 0 {
+		t.Fatalf("compensate: status=%s executed=%v", compensated.Status, executed)
+	}
+
+	w = httpxtest.Serve(t, group.Handlers["POST /sagas/:id/compensate"], httptest.NewRequest(http.MethodPost, "/sagas/o-3/compensate", nil), map[string]string{"id": "o-3"})
+	if w.Code != http.StatusConflict {
+		t.Fatalf("compensate completed saga: expected 409, got %d", w.Code)
+	}
+	w = httpxtest.Serve(t, group.Handlers["GET /sagas/:id"], httptest.NewRequest(http.MethodGet, "/sagas/missing", nil), map[string]string{"id": "missing"})
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("missing saga: expected 404, got %d", w.Code)
+	}
+}
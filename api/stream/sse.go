@@ -0,0 +1,93 @@
+package stream
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+// SSEHandler 返回 Server-Sent Events 处理器。
+//
+// 每个事件以 `id: <事件ID>`、`event: <事件类型>`、`data: <事件 JSON>` 推送；
+// 空闲时按 HeartbeatInterval 发送注释行心跳。客户端断开或消费过慢时返回 nil 结束请求。
+func (r *Registrar) SSEHandler() httpx.Handler {
+	return func(ctx httpx.IContext) error {
+		w, err := responseWriter(ctx)
+		if err != nil {
+			return err
+		}
+		f, err := r.newFilter(ctx)
+		if err != nil {
+			return err
+		}
+		if !canFlush(w) {
+			return errors.NewCode(errors.Unsupported, "response writer does not support flushing")
+		}
+		rc := http.NewResponseController(w)
+
+		reqCtx := ctx.Request().Context()
+		sub, err := r.subscribe(reqCtx, f)
+		if err != nil {
+			return err
+		}
+		defer sub.close()
+
+		// http.Server.WriteTimeout 针对整个响应，长连接改为每次写出前续期。
+		_ = rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+		header := w.Header()
+		header.Set("Content-Type", "text/event-stream")
+		header.Set("Cache-Control", "no-cache")
+		header.Set("Connection", "keep-alive")
+		header.Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		if err := rc.Flush(); err != nil {
+			return nil
+		}
+
+		heartbeat := time.NewTicker(r.config.HeartbeatInterval)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-reqCtx.Done():
+				return nil
+			case <-sub.overflow:
+				return nil
+			case <-heartbeat.C:
+				_ = rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+				if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+					return nil
+				}
+			case evt := <-sub.events:
+				data, err := encodeEvent(evt)
+				if err != nil {
+					continue
+				}
+				_ = rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+				if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", evt.GetID(), evt.GetType(), data); err != nil {
+					return nil
+				}
+			}
+			if err := rc.Flush(); err != nil {
+				return nil
+			}
+		}
+	}
+}
+
+// canFlush 沿 Unwrap 链检查 ResponseWriter 是否支持 http.Flusher（与 http.ResponseController 的探测方式一致）。
+func canFlush(w http.ResponseWriter) bool {
+	for w != nil {
+		if _, ok := w.(http.Flusher); ok {
+			return true
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return false
+		}
+		w = unwrapper.Unwrap()
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+// Package stream 提供把事件总线上的事件实时推送给 HTTP 客户端的路由注册器。
+//
+// 支持两种传输：
+//   - Server-Sent Events（默认，`GET {Path}`）：浏览器原生 EventSource 即可消费；
+//   - WebSocket（可选，`GET {WebSocketPath}`）：只做服务端到客户端的单向推送，客户端消息被忽略。
+//
+// 客户端通过查询参数过滤事件：`aggregate_type`、`event_type`（可重复或逗号分隔）。
+// 每个连接独立订阅事件总线，连接断开时自动取消订阅；推送是 best-effort 的实时通知，
+// 不提供历史回放，需要完整事件历史的消费方应使用投影或事件存储。
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/bus"
+	"gochen/httpx"
+	"gochen/httpx/nethttp"
+	"gochen/messaging"
+)
+
+const (
+	// DefaultPath 是 SSE 端点的默认路径。
+	DefaultPath = "/events"
+	// DefaultBufferSize 是单个连接的默认事件缓冲区大小。
+	DefaultBufferSize = 64
+	// DefaultHeartbeatInterval 是默认心跳间隔，用于穿透代理的空闲超时。
+	DefaultHeartbeatInterval = 15 * time.Second
+
+	// writeTimeout 是单次推送的写出超时；长连接不受 http.Server.WriteTimeout 约束，改为逐次设置。
+	writeTimeout = 10 * time.Second
+
+	// QueryAggregateType 是按聚合类型过滤的查询参数名。
+	QueryAggregateType = "aggregate_type"
+	// QueryEventType 是按事件类型过滤的查询参数名。
+	QueryEventType = "event_type"
+)
+
+// Config 定义事件流端点配置。
+type Config struct {
+	// Path 是 SSE 端点路径；为空时使用 DefaultPath。
+	Path string
+
+	// WebSocketPath 是 WebSocket 端点路径；为空表示不挂载 WebSocket。
+	WebSocketPath string
+
+	// BufferSize 是单个连接的事件缓冲区大小；<=0 时使用 DefaultBufferSize。
+	//
+	// 说明：缓冲区写满说明客户端消费过慢，连接会被主动断开，而不是静默丢弃事件。
+	BufferSize int
+
+	// HeartbeatInterval 是心跳间隔；<=0 时使用 DefaultHeartbeatInterval。
+	HeartbeatInterval time.Duration
+
+	// AggregateTypes / EventTypes 是服务端白名单；为空表示不限制。
+	//
+	// 客户端查询参数只能在白名单内进一步收窄，不能越过白名单。
+	AggregateTypes []string
+	EventTypes     []string
+
+	// Filter 是可选的逐连接过滤器（如按租户、按权限裁剪事件）；返回 false 的事件不会推送。
+	Filter func(ctx httpx.IContext, evt eventing.IEvent) bool
+}
+
+// Registrar 把事件总线暴露为 SSE / WebSocket 端点，实现 host 模块的路由注册器约定。
+type Registrar struct {
+	bus    bus.IEventBus
+	config Config
+}
+
+// NewRegistrar 创建事件流路由注册器；cfg 为 nil 时使用默认配置。
+func NewRegistrar(eventBus bus.IEventBus, cfg *Config) *Registrar {
+	config := Config{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if strings.TrimSpace(config.Path) == "" {
+		config.Path = DefaultPath
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = DefaultBufferSize
+	}
+	if config.HeartbeatInterval <= 0 {
+		config.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+	return &Registrar{bus: eventBus, config: config}
+}
+
+// RegisterRoutes 注册 SSE 端点，以及配置了 WebSocketPath 时的 WebSocket 端点。
+func (r *Registrar) RegisterRoutes(group httpx.IRouteGroup) error {
+	if group == nil {
+		return errors.NewCode(errors.InvalidInput, "route group cannot be nil")
+	}
+	if r.bus == nil {
+		return errors.NewCode(errors.InvalidInput, "event bus cannot be nil")
+	}
+	group.GET(r.config.Path, r.SSEHandler())
+	if strings.TrimSpace(r.config.WebSocketPath) != "" {
+		group.GET(r.config.WebSocketPath, r.WebSocketHandler())
+	}
+	return nil
+}
+
+// filter 是单个连接生效的事件过滤条件。
+type filter struct {
+	aggregateTypes []string
+	eventTypes     []string
+	custom         func(evt eventing.IEvent) bool
+}
+
+// newFilter 合并服务端白名单与客户端查询参数。
+func (r *Registrar) newFilter(ctx httpx.IContext) (*filter, error) {
+	query := ctx.QueryParams()
+	aggregateTypes, err := narrow(r.config.AggregateTypes, splitValues(query[QueryAggregateType]), QueryAggregateType)
+	if err != nil {
+		return nil, err
+	}
+	eventTypes, err := narrow(r.config.EventTypes, splitValues(query[QueryEventType]), QueryEventType)
+	if err != nil {
+		return nil, err
+	}
+	f := &filter{aggregateTypes: aggregateTypes, eventTypes: eventTypes}
+	if r.config.Filter != nil {
+		custom := r.config.Filter
+		f.custom = func(evt eventing.IEvent) bool { return custom(ctx, evt) }
+	}
+	return f, nil
+}
+
+// match 判断事件是否应推送给当前连接。
+func (f *filter) match(evt eventing.IEvent) bool {
+	if len(f.aggregateTypes) > 0 && !slices.Contains(f.aggregateTypes, evt.GetAggregateType()) {
+		return false
+	}
+	if len(f.eventTypes) > 0 && !slices.Contains(f.eventTypes, evt.GetType()) {
+		return false
+	}
+	return f.custom == nil || f.custom(evt)
+}
+
+// narrow 返回客户端请求值；请求值超出服务端白名单时报错，未请求时回退到白名单。
+func narrow(allowed, requested []string, param string) ([]string, error) {
+	if len(requested) == 0 {
+		return allowed, nil
+	}
+	if len(allowed) == 0 {
+		return requested, nil
+	}
+	for _, value := range requested {
+		if !slices.Contains(allowed, value) {
+			return nil, errors.NewCode(errors.Forbidden, "stream filter value is not allowed").
+				WithContext("param", param).
+				WithContext("value", value)
+		}
+	}
+	return requested, nil
+}
+
+// splitValues 展开重复参数与逗号分隔值，并去掉空白项。
+func splitValues(values []string) []string {
+	var out []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+// subscription 是单个连接的事件订阅。
+type subscription struct {
+	events      chan eventing.IEvent
+	overflow    chan struct{}
+	unsubscribe messaging.UnsubscribeFunc
+}
+
+// subscribe 为当前连接订阅事件总线。
+//
+// 总线处理器只做非阻塞投递，避免慢客户端拖慢发布方；缓冲区满时通过 overflow 通知连接断开。
+func (r *Registrar) subscribe(ctx context.Context, f *filter) (*subscription, error) {
+	sub := &subscription{
+		events:   make(chan eventing.IEvent, r.config.BufferSize),
+		overflow: make(chan struct{}),
+	}
+	var overflowOnce sync.Once
+	handler := bus.EventHandlerFunc(func(_ context.Context, evt eventing.IEvent) error {
+		if evt == nil || !f.match(evt) {
+			return nil
+		}
+		select {
+		case sub.events <- evt:
+		default:
+			overflowOnce.Do(func() { close(sub.overflow) })
+		}
+		return nil
+	})
+	unsubscribe, err := r.bus.SubscribeEvent(ctx, "*", handler)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "subscribe event stream failed")
+	}
+	sub.unsubscribe = unsubscribe
+	return sub, nil
+}
+
+// close 取消订阅；使用独立上下文，确保请求取消后仍能完成清理。
+func (s *subscription) close() {
+	if s.unsubscribe != nil {
+		_ = s.unsubscribe(context.Background())
+	}
+}
+
+// encodeEvent 把事件编码为推送给客户端的 JSON。
+func encodeEvent(evt eventing.IEvent) ([]byte, error) {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "encode stream event failed").
+			WithContext("event_type", evt.GetType())
+	}
+	return data, nil
+}
+
+// responseWriter 提取底层 net/http ResponseWriter；不支持的适配器返回 Unsupported。
+func responseWriter(ctx httpx.IContext) (http.ResponseWriter, error) {
+	w, ok := nethttp.ResponseWriterOf(ctx)
+	if !ok {
+		return nil, errors.NewCode(errors.Unsupported, "event stream requires a net/http response writer")
+	}
+	return w, nil
+}
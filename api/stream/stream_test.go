@@ -0,0 +1,226 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gochen/eventing"
+	"gochen/eventing/bus"
+	"gochen/httpx"
+	"gochen/httpx/nethttp"
+	"gochen/messaging"
+	"gochen/messaging/transport/direct"
+)
+
+// muxGroup 把 GET 路由直接挂到 http.ServeMux，便于用真实连接测试流式响应。
+type muxGroup struct {
+	mux *http.ServeMux
+}
+
+func (g *muxGroup) GET(path string, h httpx.Handler) httpx.IRouteGroup {
+	g.mux.HandleFunc("GET "+path, func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := nethttp.NewBaseContext(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := h(ctx); err != nil {
+			_ = nethttp.WriteErrorResponse(ctx, err)
+		}
+	})
+	return g
+}
+func (g *muxGroup) POST(string, httpx.Handler) httpx.IRouteGroup    { return g }
+func (g *muxGroup) PUT(string, httpx.Handler) httpx.IRouteGroup     { return g }
+func (g *muxGroup) DELETE(string, httpx.Handler) httpx.IRouteGroup  { return g }
+func (g *muxGroup) PATCH(string, httpx.Handler) httpx.IRouteGroup   { return g }
+func (g *muxGroup) HEAD(string, httpx.Handler) httpx.IRouteGroup    { return g }
+func (g *muxGroup) OPTIONS(string, httpx.Handler) httpx.IRouteGroup { return g }
+func (g *muxGroup) Group(string) httpx.IRouteGroup                  { return g }
+func (g *muxGroup) Use(...httpx.Middleware) httpx.IRouteGroup       { return g }
+
+// newStreamServer 启动挂载了事件流端点的测试服务器。
+func newStreamServer(t *testing.T, cfg *Config) (*httptest.Server, *bus.EventBus) {
+	t.Helper()
+	tpt := direct.NewSyncTransport()
+	if err := tpt.Start(context.Background()); err != nil {
+		t.Fatalf("start transport: %v", err)
+	}
+	t.Cleanup(func() { _ = tpt.Stop(context.Background()) })
+	eventBus := bus.NewEventBus(messaging.NewMessageBus(tpt))
+
+	group := &muxGroup{mux: http.NewServeMux()}
+	if err := NewRegistrar(eventBus, cfg).RegisterRoutes(group); err != nil {
+		t.Fatalf("register routes: %v", err)
+	}
+	ts := httptest.NewServer(group.mux)
+	t.Cleanup(ts.Close)
+	return ts, eventBus
+}
+
+// publishUntil 持续发布事件直到 done 关闭，规避订阅建立前的竞态。
+func publishUntil(t *testing.T, eventBus *bus.EventBus, done <-chan struct{}, events ...*eventing.Event[int64]) {
+	t.Helper()
+	go func() {
+		for {
+			for _, evt := range events {
+				_ = eventBus.PublishEvent(context.Background(), evt)
+			}
+			select {
+			case <-done:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}()
+}
+
+// TestSSEHandler_StreamsFilteredEvents 验证 SSE 只推送满足查询过滤条件的事件。
+func TestSSEHandler_StreamsFilteredEvents(t *testing.T) {
+	ts, eventBus := newStreamServer(t, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/events?aggregate_type=order", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	publishUntil(t, eventBus, done,
+		eventing.NewEvent[int64](1, "stock", "StockAdded", 1, map[string]int{"quantity": 1}),
+		eventing.NewEvent[int64](2, "order", "OrderPlaced", 1, map[string]int{"quantity": 2}),
+	)
+
+	reader := bufio.NewReader(resp.Body)
+	var eventLine, dataLine string
+	for dataLine == "" {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read stream: %v", err)
+		}
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			eventLine = strings.TrimSpace(strings.TrimPrefix(line, "event: "))
+		case strings.HasPrefix(line, "data: "):
+			dataLine = strings.TrimSpace(strings.TrimPrefix(line, "data: "))
+		}
+	}
+	if eventLine != "OrderPlaced" {
+		t.Fatalf("expected OrderPlaced, got %q", eventLine)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(dataLine), &payload); err != nil {
+		t.Fatalf("decode data: %v", err)
+	}
+	if payload["aggregate_type"] != "order" {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+}
+
+// TestSSEHandler_RejectsFilterOutsideAllowlist 验证客户端过滤条件不能越过服务端白名单。
+func TestSSEHandler_RejectsFilterOutsideAllowlist(t *testing.T) {
+	ts, _ := newStreamServer(t, &Config{AggregateTypes: []string{"order"}})
+
+	resp, err := http.Get(ts.URL + "/events?aggregate_type=order,payment")
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", resp.StatusCode)
+	}
+}
+
+// TestWebSocketHandler_PushesEvents 验证 WebSocket 握手与事件文本帧推送。
+func TestWebSocketHandler_PushesEvents(t *testing.T) {
+	ts, eventBus := newStreamServer(t, &Config{WebSocketPath: "/ws"})
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(ts.URL, "http://"))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	_, _ = io.WriteString(conn, "GET /ws?event_type=OrderPlaced HTTP/1.1\r\n"+
+		"Host: example.com\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: keep-alive, Upgrade\r\n"+
+		"Sec-WebSocket-Version: 13\r\n"+
+		"Sec-WebSocket-Key: "+key+"\r\n\r\n")
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("read handshake: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected 101, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("unexpected accept %q", got)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	publishUntil(t, eventBus, done,
+		eventing.NewEvent[int64](1, "order", "OrderCancelled", 2, nil),
+		eventing.NewEvent[int64](1, "order", "OrderPlaced", 1, nil),
+	)
+
+	var head [2]byte
+	if _, err := io.ReadFull(reader, head[:]); err != nil {
+		t.Fatalf("read frame: %v", err)
+	}
+	if head[0] != 0x80|opText {
+		t.Fatalf("expected final text frame, got %#x", head[0])
+	}
+	length := int(head[1] & 0x7F)
+	if length == 126 {
+		var ext [2]byte
+		_, _ = io.ReadFull(reader, ext[:])
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		t.Fatalf("read payload: %v", err)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload["type"] != "OrderPlaced" {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+}
+
+// TestWebSocketHandler_RequiresUpgrade 验证普通 GET 请求被拒绝。
+func TestWebSocketHandler_RequiresUpgrade(t *testing.T) {
+	ts, _ := newStreamServer(t, &Config{WebSocketPath: "/ws"})
+
+	resp, err := http.Get(ts.URL + "/ws")
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+}
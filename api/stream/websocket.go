@@ -0,0 +1,266 @@
+package stream
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	stdErrors "errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+// websocketGUID 是 RFC 6455 握手计算 Sec-WebSocket-Accept 使用的固定 GUID。
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket 帧操作码（RFC 6455 §5.2）。
+const (
+	opText  byte = 0x1
+	opClose byte = 0x8
+	opPing  byte = 0x9
+	opPong  byte = 0xA
+)
+
+// WebSocket 关闭码（RFC 6455 §7.4.1）。
+const (
+	closeNormal         = 1000
+	closePolicyViolated = 1008
+	closeTooLarge       = 1009
+)
+
+// maxClientFrameSize 是客户端帧载荷上限；本端点只推送，不期望客户端发送大消息。
+const maxClientFrameSize = 64 << 10
+
+// WebSocketHandler 返回 WebSocket 处理器。
+//
+// 说明：
+//   - 内置最小 RFC 6455 服务端实现：握手、文本帧推送、ping/pong 与关闭握手，不支持扩展与子协议；
+//   - 每个事件以一个文本帧（事件 JSON）推送；心跳使用 ping 帧；
+//   - 客户端发送的数据帧会被读取并丢弃；握手成功后连接已被接管，处理器不再返回错误。
+func (r *Registrar) WebSocketHandler() httpx.Handler {
+	return func(ctx httpx.IContext) error {
+		w, err := responseWriter(ctx)
+		if err != nil {
+			return err
+		}
+		key, err := websocketKey(ctx.Request())
+		if err != nil {
+			return err
+		}
+		f, err := r.newFilter(ctx)
+		if err != nil {
+			return err
+		}
+
+		conn, rw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			if stdErrors.Is(err, http.ErrNotSupported) {
+				return errors.Wrap(err, errors.Unsupported, "response writer does not support hijacking")
+			}
+			return errors.Wrap(err, errors.Internal, "hijack websocket connection failed")
+		}
+		defer conn.Close()
+		// 接管后的连接仍带着 http.Server 设置的读写超时，长连接需要清除读超时，写超时按帧设置。
+		_ = conn.SetReadDeadline(time.Time{})
+
+		ws := &wsConn{conn: conn, rw: rw}
+		if err := ws.handshake(key); err != nil {
+			return nil
+		}
+
+		sub, err := r.subscribe(ctx.Request().Context(), f)
+		if err != nil {
+			_ = ws.writeClose(closePolicyViolated)
+			return nil
+		}
+		defer sub.close()
+
+		pings := make(chan []byte, 1)
+		closed := make(chan int, 1)
+		go ws.readLoop(pings, closed)
+
+		heartbeat := time.NewTicker(r.config.HeartbeatInterval)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case code := <-closed:
+				_ = ws.writeClose(code)
+				return nil
+			case <-sub.overflow:
+				_ = ws.writeClose(closePolicyViolated)
+				return nil
+			case payload := <-pings:
+				if ws.writeFrame(opPong, payload) != nil {
+					return nil
+				}
+			case <-heartbeat.C:
+				if ws.writeFrame(opPing, nil) != nil {
+					return nil
+				}
+			case evt := <-sub.events:
+				data, err := encodeEvent(evt)
+				if err != nil {
+					continue
+				}
+				if ws.writeFrame(opText, data) != nil {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// websocketKey 校验升级请求并返回 Sec-WebSocket-Key。
+func websocketKey(req *http.Request) (string, error) {
+	if !headerContainsToken(req.Header, "Connection", "upgrade") ||
+		!strings.EqualFold(strings.TrimSpace(req.Header.Get("Upgrade")), "websocket") {
+		return "", errors.NewCode(errors.InvalidInput, "websocket upgrade required")
+	}
+	if req.Header.Get("Sec-WebSocket-Version") != "13" {
+		return "", errors.NewCode(errors.InvalidInput, "unsupported websocket version").
+			WithContext("version", req.Header.Get("Sec-WebSocket-Version"))
+	}
+	key := strings.TrimSpace(req.Header.Get("Sec-WebSocket-Key"))
+	if key == "" {
+		return "", errors.NewCode(errors.InvalidInput, "missing Sec-WebSocket-Key")
+	}
+	return key, nil
+}
+
+// headerContainsToken 判断逗号分隔的头部值中是否包含指定 token（大小写不敏感）。
+func headerContainsToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// websocketAccept 计算握手响应的 Sec-WebSocket-Accept。
+func websocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// wsConn 是被接管的 WebSocket 连接；写操作只在处理器 goroutine 中进行。
+type wsConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+}
+
+// handshake 写出 101 握手响应。
+func (c *wsConn) handshake(key string) error {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, _ = c.rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + websocketAccept(key) + "\r\n\r\n")
+	return c.rw.Flush()
+}
+
+// writeFrame 写出一个未分片、未掩码的服务端帧。
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	_ = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if _, err := c.rw.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+// writeClose 发送关闭帧。
+func (c *wsConn) writeClose(code int) error {
+	return c.writeFrame(opClose, binary.BigEndian.AppendUint16(nil, uint16(code)))
+}
+
+// readLoop 读取客户端帧：ping 交给处理器回 pong，close/读错误/超限帧通知处理器结束连接。
+func (c *wsConn) readLoop(pings chan<- []byte, closed chan<- int) {
+	for {
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			code := closeNormal
+			if errors.Is(err, errors.InvalidInput) {
+				code = closeTooLarge
+			}
+			closed <- code
+			return
+		}
+		switch opcode {
+		case opClose:
+			closed <- closeNormal
+			return
+		case opPing:
+			select {
+			case pings <- payload:
+			default:
+			}
+		}
+	}
+}
+
+// readFrame 读取一个客户端帧并解除掩码；载荷超过上限时返回 InvalidInput。
+func (c *wsConn) readFrame() (byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.rw, head[:]); err != nil {
+		return 0, nil, err
+	}
+	opcode := head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxClientFrameSize {
+		return 0, nil, errors.NewCode(errors.InvalidInput, "websocket frame too large").
+			WithContext("length", length)
+	}
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.rw, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return opcode, payload, nil
+}
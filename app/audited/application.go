@@ -3,6 +3,7 @@ package audited
 
 import (
 	"context"
+	"reflect"
 
 	appcrud "gochen/app/crud"
 	"gochen/domain"
@@ -32,6 +33,9 @@ type Application[T domain.IEntity[ID], ID comparable] struct {
 	auditStore  audited.IAuditStore
 	restoreRepo audited.IRestoreRepository[T, ID]
 	deletedRepo audited.IDeletedQueryRepository[T, ID]
+
+	// maskedFields 是审计记录中需要脱敏的字段（JSON 字段名），见 SetMaskedFields。
+	maskedFields map[string]struct{}
 }
 
 // isAuditedEntityType 判断AuditedEntity类型。
@@ -75,11 +79,11 @@ func NewApplication[T domain.IEntity[ID], ID comparable](
 	if !ok {
 		return nil, errors.NewCode(errors.InvalidInput, "repository must implement appcrud.ITransactional for audited writes")
 	}
-	restoreRepo, ok := any(repo).(audited.IRestoreRepository[T, ID])
+	restoreRepo, ok := crud.RepositoryAs[audited.IRestoreRepository[T, ID]](repo)
 	if !ok {
 		return nil, errors.NewCode(errors.InvalidInput, "repository must implement audited.IRestoreRepository for audited restore")
 	}
-	deletedRepo, ok := any(repo).(audited.IDeletedQueryRepository[T, ID])
+	deletedRepo, ok := crud.RepositoryAs[audited.IDeletedQueryRepository[T, ID]](repo)
 	if !ok {
 		return nil, errors.NewCode(errors.InvalidInput, "repository must implement audited.IDeletedQueryRepository for audited deleted list")
 	}
@@ -94,6 +98,8 @@ func NewApplication[T domain.IEntity[ID], ID comparable](
 		auditStore:  auditStore,
 		restoreRepo: restoreRepo,
 		deletedRepo: deletedRepo,
+
+		maskedFields: taggedMaskedFields(reflect.TypeFor[T]()),
 	}, nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -903,6 +904,48 @@ func TestAuditedApplication_Update_RecordsFieldDiff(t *testing.T) {
 	}
 }
 
+// TestAuditedApplication_ChangeHistory_ProjectsAuditTrail 验证 ChangeHistory 把创建快照与更新 diff 投影为字段变更。
+func TestAuditedApplication_ChangeHistory_ProjectsAuditTrail(t *testing.T) {
+	repo := newMemAuditedRepo()
+	store := &recordingAuditStore{}
+	app, err := NewApplication(repo, nil, nil, store)
+	if err != nil {
+		t.Fatalf("new audited app: %v", err)
+	}
+	ctx, err := auth.WithOperator(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("WithOperator returned error: %v", err)
+	}
+	e := &auditedTestEntity{AuditedEntity: &audited.AuditedEntity[int64]{Entity: crud.Entity[int64]{ID: 1}}, Name: "draft"}
+	if err := app.Create(ctx, e); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	updated := &auditedTestEntity{
+		AuditedEntity: &audited.AuditedEntity[int64]{Entity: crud.Entity[int64]{ID: 1, Version: repo.items[1].GetVersion()}},
+		Name:          "final",
+	}
+	if err := app.Update(ctx, updated); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	entries, err := app.ChangeHistory(context.Background(), 1, 0, 10)
+	if err != nil {
+		t.Fatalf("change history: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	for _, change := range entries[0].Changes {
+		if isAuditIgnoredField(change.Field) || change.Old != nil {
+			t.Fatalf("unexpected create change: %+v", change)
+		}
+	}
+	if entries[1].Operation != audited.AuditOpUpdate || entries[1].ChangedBy != "alice" ||
+		len(entries[1].Changes) != 1 || entries[1].Changes[0].Old != "draft" || entries[1].Changes[0].New != "final" {
+		t.Fatalf("unexpected update entry: %+v", entries[1])
+	}
+}
+
 // TestAuditedApplication_UpdateAll_UsesBatchAuditStoreWhenAvailable 验证 AuditedApplication UpdateAll UsesBatchAuditStoreWhenAvailable。
 func TestAuditedApplication_UpdateAll_UsesBatchAuditStoreWhenAvailable(t *testing.T) {
 	repo := newMemAuditedRepo()
@@ -1363,12 +1406,47 @@ func TestComputeEntityDiff_ReturnsNilWhenNoChanges(t *testing.T) {
 		Name:          "same",
 		Status:        "same",
 	}
-	diff := computeEntityDiff(e, e)
+	diff := computeEntityDiff(e, e, nil)
 	if diff != nil {
 		t.Fatalf("expected nil diff for identical entities, got %s", string(diff))
 	}
 }
 
+type maskedAuditEntity struct {
+	*audited.AuditedEntity[int64]
+	Name     string `json:"name"`
+	Password string `json:"password" audit:"mask"`
+	Phone    string `json:"phone"`
+}
+
+// TestAuditedApplication_MasksSensitiveFields 验证 `audit:"mask"` 与 SetMaskedFields 标记的字段在审计中只保留"已变更"。
+func TestAuditedApplication_MasksSensitiveFields(t *testing.T) {
+	store := &recordingAuditStore{}
+	app := &Application[*maskedAuditEntity, int64]{auditStore: store, maskedFields: taggedMaskedFields(reflect.TypeFor[*maskedAuditEntity]())}
+	app.SetMaskedFields("phone")
+
+	before := &maskedAuditEntity{AuditedEntity: &audited.AuditedEntity[int64]{}, Name: "a", Password: "old-secret", Phone: "111"}
+	after := &maskedAuditEntity{AuditedEntity: &audited.AuditedEntity[int64]{}, Name: "b", Password: "new-secret", Phone: "222"}
+
+	diff := string(app.auditDiff(before, after))
+	if strings.Contains(diff, "secret") || strings.Contains(diff, "111") || strings.Contains(diff, "222") {
+		t.Fatalf("expected sensitive values masked, got %s", diff)
+	}
+	var changes []audited.FieldChange
+	if err := json.Unmarshal([]byte(diff), &changes); err != nil {
+		t.Fatalf("decode diff: %v", err)
+	}
+	if len(changes) != 3 || changes[0].Field != "name" || changes[0].New != "b" ||
+		changes[1].Field != "password" || changes[1].New != auditMaskedValue {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+
+	snapshot := string(app.auditSnapshot(after))
+	if strings.Contains(snapshot, "new-secret") || !strings.Contains(snapshot, `"name":"b"`) {
+		t.Fatalf("expected masked snapshot, got %s", snapshot)
+	}
+}
+
 // TestComputeEntityDiff_SortedFieldOrder 验证 ComputeEntityDiff SortedFieldOrder。
 func TestComputeEntityDiff_SortedFieldOrder(t *testing.T) {
 	type testEntity struct {
@@ -1383,7 +1461,7 @@ func TestComputeEntityDiff_SortedFieldOrder(t *testing.T) {
 
 	// 多次运行确保顺序稳定
 	for i := 0; i < 10; i++ {
-		diff := computeEntityDiff(before, after)
+		diff := computeEntityDiff(before, after, nil)
 		if diff == nil {
 			t.Fatalf("expected diff, got nil")
 		}
@@ -1415,7 +1493,7 @@ func TestComputeEntityDiff_PreservesNumberType(t *testing.T) {
 	before := &testEntity{Count: 100}
 	after := &testEntity{Count: 200}
 
-	diff := computeEntityDiff(before, after)
+	diff := computeEntityDiff(before, after, nil)
 	if diff == nil {
 		t.Fatalf("expected diff, got nil")
 	}
@@ -1453,7 +1531,7 @@ func TestComputeEntityDiff_FiltersFrameworkFields(t *testing.T) {
 		Status:        "pending",
 	}
 
-	diff := computeEntityDiff(before, after)
+	diff := computeEntityDiff(before, after, nil)
 	if diff == nil {
 		t.Fatalf("expected diff, got nil")
 	}
@@ -0,0 +1,116 @@
+package audited
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"gochen/codec/jsoncodec"
+	"gochen/domain/audited"
+)
+
+// auditMaskTag 是标记敏感字段的 struct tag：字段声明 `audit:"mask"` 后，审计记录只保留"是否变更"，不落明文。
+const auditMaskTag = "audit"
+
+// auditMaskedValue 是敏感字段在审计记录中的占位值。
+const auditMaskedValue = "***"
+
+// SetMaskedFields 追加需要在审计记录中脱敏的字段（按 JSON 字段名），与实体上的 `audit:"mask"` 标记合并生效。
+//
+// 脱敏字段在 diff 中仍会出现（便于合规审查确认"改过"），但旧值/新值均替换为占位值；nil 保持为 nil。
+func (s *Application[T, ID]) SetMaskedFields(fields ...string) {
+	masked := make(map[string]struct{}, len(s.maskedFields)+len(fields))
+	for field := range s.maskedFields {
+		masked[field] = struct{}{}
+	}
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			masked[field] = struct{}{}
+		}
+	}
+	s.maskedFields = masked
+}
+
+// auditDiff 计算 Update 的字段级 diff，并对敏感字段脱敏。
+func (s *Application[T, ID]) auditDiff(before, after T) json.RawMessage {
+	return computeEntityDiff(before, after, s.maskedFields)
+}
+
+// auditSnapshot 序列化 Create 的实体快照，并对敏感字段脱敏。
+func (s *Application[T, ID]) auditSnapshot(entity T) json.RawMessage {
+	snapshot := marshalAuditSnapshot(entity)
+	if len(s.maskedFields) == 0 || len(snapshot) == 0 {
+		return snapshot
+	}
+	fields, err := unmarshalWithNumber(snapshot)
+	if err != nil {
+		return snapshot
+	}
+	for field, value := range fields {
+		if _, ok := s.maskedFields[field]; ok && value != nil {
+			fields[field] = auditMaskedValue
+		}
+	}
+	masked, err := jsoncodec.MarshalPreserveNumber(fields)
+	if err != nil {
+		return nil
+	}
+	return masked
+}
+
+// maskFieldChanges 原地把敏感字段的旧值/新值替换为占位值。
+func maskFieldChanges(changes []audited.FieldChange, masked map[string]struct{}) {
+	if len(masked) == 0 {
+		return
+	}
+	for i := range changes {
+		if _, ok := masked[changes[i].Field]; !ok {
+			continue
+		}
+		if changes[i].Old != nil {
+			changes[i].Old = auditMaskedValue
+		}
+		if changes[i].New != nil {
+			changes[i].New = auditMaskedValue
+		}
+	}
+}
+
+// taggedMaskedFields 收集实体类型上标记了 `audit:"mask"` 的字段（JSON 字段名，含匿名嵌入结构体）。
+func taggedMaskedFields(t reflect.Type) map[string]struct{} {
+	masked := make(map[string]struct{})
+	collectMaskedFields(t, masked, 0)
+	return masked
+}
+
+func collectMaskedFields(t reflect.Type, masked map[string]struct{}, depth int) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || depth > 8 {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(jsonTag, ",")
+		if field.Anonymous && name == "" {
+			collectMaskedFields(field.Type, masked, depth+1)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		for _, opt := range strings.Split(field.Tag.Get(auditMaskTag), ",") {
+			if strings.TrimSpace(opt) == "mask" {
+				masked[name] = struct{}{}
+			}
+		}
+	}
+}
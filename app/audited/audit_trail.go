@@ -1,6 +1,7 @@
 package audited
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -29,6 +30,69 @@ func (s *Application[T, ID]) AuditTrail(ctx context.Context, id ID, offset, limi
 	return s.auditStore.ListAuditRecordsByEntity(ctx, fmt.Sprint(id), offset, limit)
 }
 
+// ChangeHistory 返回指定实体的字段级变更历史（按审计记录顺序），支持分页。
+//
+// 基于 AuditTrail 投影：更新记录的 diff 原样展开，创建记录的快照展开为"从无到有"的字段变更。
+func (s *Application[T, ID]) ChangeHistory(ctx context.Context, id ID, offset, limit int) ([]audited.ChangeEntry, error) {
+	records, err := s.AuditTrail(ctx, id, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]audited.ChangeEntry, 0, len(records))
+	for i := range records {
+		changes, err := decodeFieldChanges(records[i].Changes)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.Internal, "failed to decode audit record changes").
+				WithContext("audit_id", records[i].ID)
+		}
+		out = append(out, audited.ChangeEntry{
+			ID:        records[i].ID,
+			Operation: records[i].Operation,
+			ChangedBy: records[i].Operator,
+			ChangedAt: records[i].Timestamp,
+			Changes:   changes,
+		})
+	}
+	return out, nil
+}
+
+// decodeFieldChanges 把审计记录的 Changes 解码为字段变更列表。
+//
+// Changes 可能是 diff 数组（Update）或实体快照对象（Create）；快照按字段名排序并过滤框架元数据字段。
+func decodeFieldChanges(raw json.RawMessage) ([]audited.FieldChange, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return []audited.FieldChange{}, nil
+	}
+	if trimmed[0] == '[' {
+		changes, err := jsoncodec.Decode[[]audited.FieldChange](trimmed)
+		if err != nil {
+			return nil, err
+		}
+		for i := range changes {
+			changes[i].Old = jsoncodec.NormalizeNumbers(changes[i].Old)
+			changes[i].New = jsoncodec.NormalizeNumbers(changes[i].New)
+		}
+		return changes, nil
+	}
+	snapshot, err := unmarshalWithNumber(trimmed)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(snapshot))
+	for k := range snapshot {
+		if !isAuditIgnoredField(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	changes := make([]audited.FieldChange, 0, len(keys))
+	for _, k := range keys {
+		changes = append(changes, audited.FieldChange{Field: k, New: jsoncodec.NormalizeNumbers(snapshot[k])})
+	}
+	return changes, nil
+}
+
 // saveAuditRecords 保存审计Records。
 func (s *Application[T, ID]) saveAuditRecords(ctx context.Context, records []audited.AuditRecord) error {
 	if len(records) == 0 {
@@ -68,8 +132,8 @@ func (s *Application[T, ID]) saveAudit(ctx context.Context, id ID, op audited.Au
 }
 
 // computeEntityDiff 计算两个实体之间的字段级差异，返回变化字段的 diff 列表。
-// 使用 JSON 序列化比较，过滤框架元数据字段，仅保留业务字段变更。
-func computeEntityDiff[T any](before, after T) json.RawMessage {
+// 使用 JSON 序列化比较，过滤框架元数据字段，仅保留业务字段变更；masked 中的字段旧值/新值替换为脱敏占位值。
+func computeEntityDiff[T any](before, after T, masked map[string]struct{}) json.RawMessage {
 	beforeJSON, err := json.Marshal(before)
 	if err != nil {
 		return nil
@@ -131,6 +195,7 @@ func computeEntityDiff[T any](before, after T) json.RawMessage {
 	if len(diffs) == 0 {
 		return nil
 	}
+	maskFieldChanges(diffs, masked)
 
 	result, err := json.Marshal(diffs)
 	if err != nil {
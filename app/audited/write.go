@@ -174,7 +174,7 @@ func (s *Application[T, ID]) Create(ctx context.Context, entity T) error {
 			if err := s.Application.RunAfterCreate(writeCtx, entity); err != nil {
 				return err
 			}
-			return s.saveAudit(writeCtx, entity.GetID(), domaudited.AuditOpCreate, marshalAuditSnapshot(entity))
+			return s.saveAudit(writeCtx, entity.GetID(), domaudited.AuditOpCreate, s.auditSnapshot(entity))
 		},
 		PostCommits:     postCommitCallbacks(s.Application.PostCommitCreateCallback(entity)),
 		CallbackContext: ctx,
@@ -207,7 +207,48 @@ func (s *Application[T, ID]) Update(ctx context.Context, entity T) error {
 			if err := s.Application.RunAfterUpdate(writeCtx, entity); err != nil {
 				return err
 			}
-			return s.saveAudit(writeCtx, entity.GetID(), domaudited.AuditOpUpdate, computeEntityDiff(before, entity))
+			return s.saveAudit(writeCtx, entity.GetID(), domaudited.AuditOpUpdate, s.auditDiff(before, entity))
+		},
+		PostCommits:     postCommitCallbacks(s.Application.PostCommitUpdateCallback(entity)),
+		CallbackContext: ctx,
+	})
+}
+
+// Patch 按字段局部更新记录，并以变更前后的差异写入审计 AuditOpUpdate。
+//
+// 约束：
+// - ctx 中必须包含 operator，否则返回 InvalidInput；
+// - repo 必须实现 crud.IPatchRepository，否则返回 Unsupported。
+func (s *Application[T, ID]) Patch(ctx context.Context, entity T, fields []string) error {
+	if err := requireAuditOperator(ctx); err != nil {
+		return err
+	}
+	repo, ok := crud.RepositoryAs[crud.IPatchRepository[T, ID]](s.Repository())
+	if !ok {
+		return errors.NewCode(errors.Unsupported, "patch requires repository to implement crud.IPatchRepository")
+	}
+
+	var before T
+	return s.runWriteFlow(ctx, writeflow.Plan{
+		Before: func(writeCtx context.Context) error {
+			return s.Application.RunBeforeUpdate(writeCtx, entity)
+		},
+		Validate: func(context.Context) error {
+			return s.Application.Validate(entity)
+		},
+		Write: func(writeCtx context.Context) error {
+			var err error
+			before, err = s.Repository().Get(writeCtx, entity.GetID())
+			if err != nil {
+				return err
+			}
+			return repo.UpdateFields(writeCtx, entity, fields)
+		},
+		After: func(writeCtx context.Context) error {
+			if err := s.Application.RunAfterUpdate(writeCtx, entity); err != nil {
+				return err
+			}
+			return s.saveAudit(writeCtx, entity.GetID(), domaudited.AuditOpUpdate, s.auditDiff(before, entity))
 		},
 		PostCommits:     postCommitCallbacks(s.Application.PostCommitUpdateCallback(entity)),
 		CallbackContext: ctx,
@@ -359,7 +400,7 @@ func (w *BatchWriter[T, ID]) CreateAll(ctx context.Context, entities []T) error
 			now := time.Now()
 			records := make([]domaudited.AuditRecord, 0, len(entities))
 			for _, entity := range entities {
-				records = append(records, s.buildAuditRecord(by, now, entity.GetID(), domaudited.AuditOpCreate, marshalAuditSnapshot(entity)))
+				records = append(records, s.buildAuditRecord(by, now, entity.GetID(), domaudited.AuditOpCreate, s.auditSnapshot(entity)))
 			}
 			return s.saveAuditRecords(writeCtx, records)
 		},
@@ -422,7 +463,7 @@ func (w *BatchWriter[T, ID]) UpdateAll(ctx context.Context, entities []T) error
 			now := time.Now()
 			records := make([]domaudited.AuditRecord, 0, len(entities))
 			for _, entity := range entities {
-				records = append(records, s.buildAuditRecord(by, now, entity.GetID(), domaudited.AuditOpUpdate, computeEntityDiff(beforeByID[entity.GetID()], entity)))
+				records = append(records, s.buildAuditRecord(by, now, entity.GetID(), domaudited.AuditOpUpdate, s.auditDiff(beforeByID[entity.GetID()], entity)))
 			}
 			return s.saveAuditRecords(writeCtx, records)
 		},
@@ -61,7 +61,7 @@ func (w *WriteConstraintWriter[T, ID]) CreateWithConstraint(ctx context.Context,
 			if err := s.Application.RunAfterCreate(writeCtx, entity); err != nil {
 				return err
 			}
-			return s.saveAudit(writeCtx, entity.GetID(), domaudited.AuditOpCreate, marshalAuditSnapshot(entity))
+			return s.saveAudit(writeCtx, entity.GetID(), domaudited.AuditOpCreate, s.auditSnapshot(entity))
 		},
 		PostCommits:     postCommitCallbacks(s.Application.PostCommitCreateCallback(entity)),
 		CallbackContext: ctx,
@@ -103,7 +103,7 @@ func (w *WriteConstraintWriter[T, ID]) UpdateWithConstraint(ctx context.Context,
 			if err := s.Application.RunAfterUpdate(writeCtx, entity); err != nil {
 				return err
 			}
-			return s.saveAudit(writeCtx, entity.GetID(), domaudited.AuditOpUpdate, computeEntityDiff(before, entity))
+			return s.saveAudit(writeCtx, entity.GetID(), domaudited.AuditOpUpdate, s.auditDiff(before, entity))
 		},
 		PostCommits:     postCommitCallbacks(s.Application.PostCommitUpdateCallback(entity)),
 		CallbackContext: ctx,
@@ -66,7 +66,7 @@ func (w *WriteConstraintWriter[T, ID]) CreateAllWithConstraint(ctx context.Conte
 			now := time.Now()
 			records := make([]domaudited.AuditRecord, 0, len(entities))
 			for _, entity := range entities {
-				records = append(records, s.buildAuditRecord(by.value, now, entity.GetID(), domaudited.AuditOpCreate, marshalAuditSnapshot(entity)))
+				records = append(records, s.buildAuditRecord(by.value, now, entity.GetID(), domaudited.AuditOpCreate, s.auditSnapshot(entity)))
 			}
 			return s.saveAuditRecords(writeCtx, records)
 		},
@@ -135,7 +135,7 @@ func (w *WriteConstraintWriter[T, ID]) UpdateAllWithConstraint(ctx context.Conte
 			now := time.Now()
 			records := make([]domaudited.AuditRecord, 0, len(entities))
 			for _, entity := range entities {
-				records = append(records, s.buildAuditRecord(by.value, now, entity.GetID(), domaudited.AuditOpUpdate, computeEntityDiff(beforeByID[entity.GetID()], entity)))
+				records = append(records, s.buildAuditRecord(by.value, now, entity.GetID(), domaudited.AuditOpUpdate, s.auditDiff(beforeByID[entity.GetID()], entity)))
 			}
 			return s.saveAuditRecords(writeCtx, records)
 		},
@@ -36,6 +36,11 @@ type IBatchWriter[T domain.IEntity[ID], ID comparable] interface {
 	DeleteAll(ctx context.Context, ids []ID) error
 }
 
+// IPatcher 表示按字段局部更新的写能力。
+type IPatcher[T domain.IEntity[ID], ID comparable] interface {
+	Patch(ctx context.Context, e T, fields []string) error
+}
+
 // IRepositoryProvider 暴露底层仓储。
 type IRepositoryProvider[T domain.IEntity[ID], ID comparable] interface {
 	Repository() crud.IRepository[T, ID]
@@ -76,6 +81,9 @@ type ServiceConfig struct {
 
 	// 最大单页大小（分页查询）
 	MaxPageSize int
+
+	// 仓储装饰（重试/缓存/指标），仅在 NewApplication 时生效；nil 表示不包装
+	Decorators *RepositoryDecoratorConfig
 }
 
 const (
@@ -144,6 +152,10 @@ func NewApplication[T domain.IEntity[ID], ID comparable](
 	if config == nil {
 		config = DefaultServiceConfig()
 	}
+	repository, err := DecorateRepositoryWithConfig(repository, config.Decorators)
+	if err != nil {
+		return nil, err
+	}
 
 	return &Application[T, ID]{
 		repository: repository,
@@ -281,7 +293,7 @@ func (s *Application[T, ID]) postCommitDeleteCallbacks(ids []ID) []func(context.
 }
 
 func (s *Application[T, ID]) transactionalRepository() (ITransactional, bool) {
-	txRepo, ok := s.repository.(ITransactional)
+	txRepo, ok := crud.RepositoryAs[ITransactional](s.repository)
 	return txRepo, ok
 }
 
@@ -325,6 +337,34 @@ func (s *Application[T, ID]) Update(ctx context.Context, entity T) error {
 	})
 }
 
+// Patch 按字段局部更新实体，执行与 Update 相同的生命周期钩子。
+//
+// 说明：
+//   - entity 为合并补丁后的完整实体（用于钩子与校验），仓储只写入 fields 对应的列；
+//   - 仓储需实现 crud.IPatchRepository，否则返回 errors.Unsupported。
+func (s *Application[T, ID]) Patch(ctx context.Context, entity T, fields []string) error {
+	repo, ok := crud.RepositoryAs[crud.IPatchRepository[T, ID]](s.repository)
+	if !ok {
+		return errors.NewCode(errors.Unsupported, "patch requires repository to implement crud.IPatchRepository")
+	}
+	return s.runWriteFlow(ctx, writeflow.Plan{
+		Before: func(writeCtx context.Context) error {
+			return s.runBeforeUpdate(writeCtx, entity)
+		},
+		Validate: func(context.Context) error {
+			return s.Validate(entity)
+		},
+		Write: func(writeCtx context.Context) error {
+			return repo.UpdateFields(writeCtx, entity, fields)
+		},
+		After: func(writeCtx context.Context) error {
+			return s.runAfterUpdate(writeCtx, entity)
+		},
+		PostCommits:     callbacksToPostCommits([]func(context.Context) error{s.postCommitUpdate(entity)}),
+		CallbackContext: ctx,
+	})
+}
+
 // Delete 删除实体，执行完整的生命周期钩子。
 func (s *Application[T, ID]) Delete(ctx context.Context, id ID) error {
 	return s.runWriteFlow(ctx, writeflow.Plan{
@@ -377,7 +417,7 @@ func (s *Application[T, ID]) Repository() crud.IRepository[T, ID] { return s.rep
 
 // QueryRepository 返回底层查询仓储扩展（若支持）。
 func (s *Application[T, ID]) QueryRepository() (crud.IQueryRepository[T, ID], bool) {
-	repo, ok := s.repository.(crud.IQueryRepository[T, ID])
+	repo, ok := crud.RepositoryAs[crud.IQueryRepository[T, ID]](s.repository)
 	return repo, ok
 }
 
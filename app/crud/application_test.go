@@ -579,6 +579,41 @@ func TestApplication_Update_ValidatesBeforePersist(t *testing.T) {
 	}
 }
 
+type patchRepo struct {
+	recordingRepo
+	fields []string
+}
+
+func (r *patchRepo) UpdateFields(ctx context.Context, e testEntity, fields []string) error {
+	_ = ctx
+	_ = e
+	r.fields = fields
+	return nil
+}
+
+// TestApplication_Patch_WritesFieldsThroughPatchRepository 验证 Application Patch 经由 IPatchRepository 写入字段。
+func TestApplication_Patch_WritesFieldsThroughPatchRepository(t *testing.T) {
+	repo := &patchRepo{}
+	app, err := NewApplication[testEntity, int64](repo, nil, nil)
+	if err != nil {
+		t.Fatalf("new application: %v", err)
+	}
+	if err := app.Patch(context.Background(), testEntity{id: 1}, []string{"name"}); err != nil {
+		t.Fatalf("patch: %v", err)
+	}
+	if len(repo.fields) != 1 || repo.fields[0] != "name" {
+		t.Fatalf("expected fields [name], got %v", repo.fields)
+	}
+
+	plain, err := NewApplication[testEntity, int64](&recordingRepo{}, nil, nil)
+	if err != nil {
+		t.Fatalf("new application: %v", err)
+	}
+	if err := plain.Patch(context.Background(), testEntity{id: 1}, []string{"name"}); !errors.Is(err, errors.Unsupported) {
+		t.Fatalf("expected Unsupported, got %v", err)
+	}
+}
+
 // TestApplication_ListByQuery_PassesFieldsToRequest 验证 Application ListByQuery PassesFieldsToRequest。
 func TestApplication_ListByQuery_PassesFieldsToRequest(t *testing.T) {
 	repo := &capturingQueryRepo{recordingRepo: recordingRepo{items: []testEntity{{id: 1}}}}
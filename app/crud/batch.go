@@ -47,7 +47,7 @@ func (w *BatchWriter[T, ID]) CreateAll(ctx context.Context, entities []T) error
 			return s.Validate(entity)
 		}),
 		Write: func(writeCtx context.Context) error {
-			if batchRepo, ok := repo.(domaincrud.IBatchOperations[T, ID]); ok {
+			if batchRepo, ok := domaincrud.RepositoryAs[domaincrud.IBatchOperations[T, ID]](repo); ok {
 				return batchRepo.CreateAll(writeCtx, entities)
 			}
 			if _, ok := s.transactionalRepository(); !ok {
@@ -87,7 +87,7 @@ func (w *BatchWriter[T, ID]) UpdateAll(ctx context.Context, entities []T) error
 			return s.Validate(entity)
 		}),
 		Write: func(writeCtx context.Context) error {
-			if batchRepo, ok := repo.(domaincrud.IBatchOperations[T, ID]); ok {
+			if batchRepo, ok := domaincrud.RepositoryAs[domaincrud.IBatchOperations[T, ID]](repo); ok {
 				return batchRepo.UpdateAll(writeCtx, entities)
 			}
 			if _, ok := s.transactionalRepository(); !ok {
@@ -124,7 +124,7 @@ func (w *BatchWriter[T, ID]) DeleteAll(ctx context.Context, ids []ID) error {
 	return s.runWriteFlow(ctx, writeflow.Plan{
 		Before: writeflow.ForEach(ids, s.runBeforeDelete),
 		Write: func(writeCtx context.Context) error {
-			if batchRepo, ok := repo.(domaincrud.IBatchOperations[T, ID]); ok {
+			if batchRepo, ok := domaincrud.RepositoryAs[domaincrud.IBatchOperations[T, ID]](repo); ok {
 				return batchRepo.DeleteAll(writeCtx, ids)
 			}
 			if _, ok := s.transactionalRepository(); !ok {
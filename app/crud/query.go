@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"gochen/db/query"
+	domaincrud "gochen/domain/crud"
 	"gochen/errors"
 )
 
@@ -53,7 +54,7 @@ func (s *Application[T, ID]) ListByQuery(ctx context.Context, quer *query.QueryR
 		Filters: normalized.Filters,
 	}
 
-	if queryableRepo, ok := s.Repository().(query.IQueryableRepository[T, ID]); ok {
+	if queryableRepo, ok := domaincrud.RepositoryAs[query.IQueryableRepository[T, ID]](s.Repository()); ok {
 		return queryableRepo.Query(ctx, options)
 	}
 
@@ -100,7 +101,7 @@ func (s *Application[T, ID]) ListPage(ctx context.Context, request *query.PageRe
 	var total int64
 	var err error
 
-	if queryableRepo, ok := s.Repository().(query.IQueryableRepository[T, ID]); ok {
+	if queryableRepo, ok := domaincrud.RepositoryAs[query.IQueryableRepository[T, ID]](s.Repository()); ok {
 		data, err = queryableRepo.Query(ctx, queryOpts)
 		if err != nil {
 			return nil, err
@@ -161,7 +162,7 @@ func (s *Application[T, ID]) CountByQuery(ctx context.Context, quer *query.Query
 		Sorts:   normalized.Sorts,
 		Filters: normalized.Filters,
 	}
-	if queryableRepo, ok := s.Repository().(query.IQueryableRepository[T, ID]); ok {
+	if queryableRepo, ok := domaincrud.RepositoryAs[query.IQueryableRepository[T, ID]](s.Repository()); ok {
 		return queryableRepo.QueryCount(ctx, options)
 	}
 
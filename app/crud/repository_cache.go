@@ -0,0 +1,215 @@
+package crud
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gochen/cache"
+	"gochen/clock"
+	"gochen/domain"
+	"gochen/domain/access"
+	domaincrud "gochen/domain/crud"
+)
+
+const (
+	defaultRepositoryCacheSize = 1000
+	defaultRepositoryCacheTTL  = 5 * time.Minute
+)
+
+// RepositoryCacheConfig 仓储 cache-aside 装饰配置。
+type RepositoryCacheConfig struct {
+	// Name 缓存名称（用于统计与日志）。
+	Name string
+
+	// MaxSize 最大缓存实体数（默认 1000）。
+	MaxSize int
+
+	// TTL 缓存过期时间（默认 5 分钟）。
+	TTL time.Duration
+
+	// Clock 可选时间源，便于测试推进 TTL。
+	Clock clock.IClock
+}
+
+// CachedRepository 以 cache-aside 方式缓存 Get 结果的仓储装饰器。
+//
+// 说明：
+//   - 仅缓存按 ID 读取的实体；列表/查询类读操作直接透传；
+//   - Update/UpdateFields/Delete/Purge（含批量与写约束变体）无论成功与否都会失效对应条目；
+//   - 事务内（经由 WithinTx）的 Get 绕过缓存，事务结束后再次失效事务内写过的条目，避免缓存未提交数据；
+//   - 缓存返回共享实例，调用方修改实体后应写回仓储，否则会污染缓存。
+type CachedRepository[T domain.IEntity[ID], ID comparable] struct {
+	*InterceptedRepository[T, ID]
+	cache *cache.Cache[ID, T]
+}
+
+// NewCachedRepository 创建 cache-aside 仓储装饰器。
+func NewCachedRepository[T domain.IEntity[ID], ID comparable](
+	inner domaincrud.IRepository[T, ID],
+	cfg RepositoryCacheConfig,
+) (*CachedRepository[T, ID], error) {
+	base, err := NewInterceptedRepository(inner, nil)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Name == "" {
+		cfg.Name = "repository"
+	}
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = defaultRepositoryCacheSize
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultRepositoryCacheTTL
+	}
+	return &CachedRepository[T, ID]{
+		InterceptedRepository: base,
+		cache: cache.New[ID, T](cache.Config{
+			Name:    cfg.Name,
+			MaxSize: cfg.MaxSize,
+			TTL:     cfg.TTL,
+			Clock:   cfg.Clock,
+		}),
+	}, nil
+}
+
+// WithRepositoryCache 返回 cache-aside 装饰器。
+func WithRepositoryCache[T domain.IEntity[ID], ID comparable](cfg RepositoryCacheConfig) RepositoryDecorator[T, ID] {
+	return func(repo domaincrud.IRepository[T, ID]) (domaincrud.IRepository[T, ID], error) {
+		return NewCachedRepository(repo, cfg)
+	}
+}
+
+// Invalidate 失效指定 ID 的缓存条目。
+func (r *CachedRepository[T, ID]) Invalidate(id ID) {
+	r.cache.Delete(id)
+}
+
+// CacheStats 返回缓存统计信息。
+func (r *CachedRepository[T, ID]) CacheStats() cache.CacheStats {
+	return r.cache.Stats()
+}
+
+// Get 优先从缓存读取实体，未命中时回源并写入缓存。
+func (r *CachedRepository[T, ID]) Get(ctx context.Context, id ID) (T, error) {
+	if withinRepositoryTx(ctx) {
+		return r.InterceptedRepository.Get(ctx, id)
+	}
+	if entity, ok := r.cache.Get(id); ok {
+		return entity, nil
+	}
+	entity, err := r.InterceptedRepository.Get(ctx, id)
+	if err != nil {
+		return entity, err
+	}
+	r.cache.Set(id, entity)
+	return entity, nil
+}
+
+// Update 更新实体并失效缓存。
+func (r *CachedRepository[T, ID]) Update(ctx context.Context, e T) error {
+	defer r.invalidate(ctx, e.GetID())
+	return r.InterceptedRepository.Update(ctx, e)
+}
+
+// UpdateFields 按字段局部更新实体并失效缓存。
+func (r *CachedRepository[T, ID]) UpdateFields(ctx context.Context, e T, fields []string) error {
+	defer r.invalidate(ctx, e.GetID())
+	return r.InterceptedRepository.UpdateFields(ctx, e, fields)
+}
+
+// Delete 删除实体并失效缓存。
+func (r *CachedRepository[T, ID]) Delete(ctx context.Context, id ID) error {
+	defer r.invalidate(ctx, id)
+	return r.InterceptedRepository.Delete(ctx, id)
+}
+
+// Purge 永久删除实体并失效缓存。
+func (r *CachedRepository[T, ID]) Purge(ctx context.Context, id ID) error {
+	defer r.invalidate(ctx, id)
+	return r.InterceptedRepository.Purge(ctx, id)
+}
+
+// UpdateAll 批量更新实体并失效缓存。
+func (r *CachedRepository[T, ID]) UpdateAll(ctx context.Context, entities []T) error {
+	defer r.invalidateEntities(ctx, entities)
+	return r.InterceptedRepository.UpdateAll(ctx, entities)
+}
+
+// DeleteAll 批量删除实体并失效缓存。
+func (r *CachedRepository[T, ID]) DeleteAll(ctx context.Context, ids []ID) error {
+	defer r.invalidate(ctx, ids...)
+	return r.InterceptedRepository.DeleteAll(ctx, ids)
+}
+
+// UpdateWithConstraint 在写入约束下更新实体并失效缓存。
+func (r *CachedRepository[T, ID]) UpdateWithConstraint(ctx context.Context, e T, constraint access.WriteConstraint) error {
+	defer r.invalidate(ctx, e.GetID())
+	return r.InterceptedRepository.UpdateWithConstraint(ctx, e, constraint)
+}
+
+// DeleteWithConstraint 在写入约束下删除实体并失效缓存。
+func (r *CachedRepository[T, ID]) DeleteWithConstraint(ctx context.Context, id ID, constraint access.WriteConstraint) error {
+	defer r.invalidate(ctx, id)
+	return r.InterceptedRepository.DeleteWithConstraint(ctx, id, constraint)
+}
+
+// UpdateAllWithConstraint 在写入约束下批量更新实体并失效缓存。
+func (r *CachedRepository[T, ID]) UpdateAllWithConstraint(ctx context.Context, entities []T, constraint access.WriteConstraint) error {
+	defer r.invalidateEntities(ctx, entities)
+	return r.InterceptedRepository.UpdateAllWithConstraint(ctx, entities, constraint)
+}
+
+// DeleteAllWithConstraint 在写入约束下批量删除实体并失效缓存。
+func (r *CachedRepository[T, ID]) DeleteAllWithConstraint(ctx context.Context, ids []ID, constraint access.WriteConstraint) error {
+	defer r.invalidate(ctx, ids...)
+	return r.InterceptedRepository.DeleteAllWithConstraint(ctx, ids, constraint)
+}
+
+// cachedRepositoryTxKey 以装饰器实例区分事务内待失效集合，避免多层缓存互相干扰。
+type cachedRepositoryTxKey struct {
+	owner any
+}
+
+// cachedRepositoryTxWrites 记录事务内写过的实体 ID。
+type cachedRepositoryTxWrites[ID comparable] struct {
+	mu  sync.Mutex
+	ids []ID
+}
+
+// WithinTx 在事务中执行 fn，并在事务结束后失效事务内写过的条目。
+func (r *CachedRepository[T, ID]) WithinTx(ctx context.Context, fn func(txCtx context.Context) error) error {
+	writes := &cachedRepositoryTxWrites[ID]{}
+	defer func() {
+		writes.mu.Lock()
+		defer writes.mu.Unlock()
+		for _, id := range writes.ids {
+			r.cache.Delete(id)
+		}
+	}()
+	return r.InterceptedRepository.WithinTx(ctx, func(txCtx context.Context) error {
+		return fn(context.WithValue(txCtx, cachedRepositoryTxKey{owner: r}, writes))
+	})
+}
+
+func (r *CachedRepository[T, ID]) invalidate(ctx context.Context, ids ...ID) {
+	for _, id := range ids {
+		r.cache.Delete(id)
+	}
+	if ctx == nil {
+		return
+	}
+	if writes, ok := ctx.Value(cachedRepositoryTxKey{owner: r}).(*cachedRepositoryTxWrites[ID]); ok {
+		writes.mu.Lock()
+		writes.ids = append(writes.ids, ids...)
+		writes.mu.Unlock()
+	}
+}
+
+func (r *CachedRepository[T, ID]) invalidateEntities(ctx context.Context, entities []T) {
+	ids := make([]ID, 0, len(entities))
+	for _, entity := range entities {
+		ids = append(ids, entity.GetID())
+	}
+	r.invalidate(ctx, ids...)
+}
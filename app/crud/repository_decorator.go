@@ -0,0 +1,496 @@
+package crud
+
+import (
+	"context"
+
+	"gochen/db/query"
+	"gochen/domain"
+	"gochen/domain/access"
+	"gochen/domain/audited"
+	domaincrud "gochen/domain/crud"
+	"gochen/errors"
+)
+
+// RepositoryOperation 标识一次仓储调用的操作名，用于指标标签与重试判定。
+type RepositoryOperation string
+
+const (
+	RepositoryOpCreate                  RepositoryOperation = "create"
+	RepositoryOpUpdate                  RepositoryOperation = "update"
+	RepositoryOpDelete                  RepositoryOperation = "delete"
+	RepositoryOpGet                     RepositoryOperation = "get"
+	RepositoryOpList                    RepositoryOperation = "list"
+	RepositoryOpCount                   RepositoryOperation = "count"
+	RepositoryOpExists                  RepositoryOperation = "exists"
+	RepositoryOpQuery                   RepositoryOperation = "query"
+	RepositoryOpQueryOne                RepositoryOperation = "query_one"
+	RepositoryOpQueryCount              RepositoryOperation = "query_count"
+	RepositoryOpCreateAll               RepositoryOperation = "create_all"
+	RepositoryOpUpdateAll               RepositoryOperation = "update_all"
+	RepositoryOpDeleteAll               RepositoryOperation = "delete_all"
+	RepositoryOpPurge                   RepositoryOperation = "purge"
+	RepositoryOpUpdateFields            RepositoryOperation = "update_fields"
+	RepositoryOpGetWithDeleted          RepositoryOperation = "get_with_deleted"
+	RepositoryOpListDeleted             RepositoryOperation = "list_deleted"
+	RepositoryOpCreateWithConstraint    RepositoryOperation = "create_with_constraint"
+	RepositoryOpUpdateWithConstraint    RepositoryOperation = "update_with_constraint"
+	RepositoryOpDeleteWithConstraint    RepositoryOperation = "delete_with_constraint"
+	RepositoryOpCreateAllWithConstraint RepositoryOperation = "create_all_with_constraint"
+	RepositoryOpUpdateAllWithConstraint RepositoryOperation = "update_all_with_constraint"
+	RepositoryOpDeleteAllWithConstraint RepositoryOperation = "delete_all_with_constraint"
+	RepositoryOpResolveResource         RepositoryOperation = "resolve_resource"
+)
+
+// IsRead 判断操作是否为只读操作。
+func (op RepositoryOperation) IsRead() bool {
+	switch op {
+	case RepositoryOpGet, RepositoryOpList, RepositoryOpCount, RepositoryOpExists,
+		RepositoryOpQuery, RepositoryOpQueryOne, RepositoryOpQueryCount, RepositoryOpResolveResource,
+		RepositoryOpGetWithDeleted, RepositoryOpListDeleted:
+		return true
+	default:
+		return false
+	}
+}
+
+// RepositoryInterceptor 拦截一次仓储调用；实现必须调用 call 才会真正访问底层仓储。
+type RepositoryInterceptor func(ctx context.Context, op RepositoryOperation, call func(ctx context.Context) error) error
+
+// RepositoryDecorator 把仓储包装为带额外能力的仓储。
+type RepositoryDecorator[T domain.IEntity[ID], ID comparable] func(domaincrud.IRepository[T, ID]) (domaincrud.IRepository[T, ID], error)
+
+// DecorateRepository 按顺序组合仓储装饰器。
+//
+// 说明：
+//   - decorators 依次包装，最后一个位于最外层（例如 retry, cache, metrics 表示 metrics(cache(retry(repo)))）；
+//   - nil 装饰器会被忽略。
+func DecorateRepository[T domain.IEntity[ID], ID comparable](
+	repo domaincrud.IRepository[T, ID],
+	decorators ...RepositoryDecorator[T, ID],
+) (domaincrud.IRepository[T, ID], error) {
+	if repo == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "repository cannot be nil")
+	}
+	current := repo
+	for _, decorate := range decorators {
+		if decorate == nil {
+			continue
+		}
+		next, err := decorate(current)
+		if err != nil {
+			return nil, err
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// WithRepositoryInterceptor 返回以拦截器包装仓储的装饰器。
+func WithRepositoryInterceptor[T domain.IEntity[ID], ID comparable](interceptor RepositoryInterceptor) RepositoryDecorator[T, ID] {
+	return func(repo domaincrud.IRepository[T, ID]) (domaincrud.IRepository[T, ID], error) {
+		return NewInterceptedRepository(repo, interceptor)
+	}
+}
+
+// RepositoryDecoratorConfig 按实体声明仓储装饰（重试、缓存、指标）。
+//
+// 说明：由内向外的包装顺序为 Retry → Cache → Metrics，因此指标也覆盖缓存命中的调用。
+type RepositoryDecoratorConfig struct {
+	// Retry 瞬时错误重试配置；nil 表示不启用。
+	Retry *RepositoryRetryConfig
+
+	// Cache cache-aside 缓存配置；nil 表示不启用。
+	Cache *RepositoryCacheConfig
+
+	// Metrics 指标配置；nil 表示不启用。
+	Metrics *RepositoryMetricsConfig
+}
+
+// DecorateRepositoryWithConfig 按配置组合仓储装饰器；cfg 为 nil 时原样返回。
+func DecorateRepositoryWithConfig[T domain.IEntity[ID], ID comparable](
+	repo domaincrud.IRepository[T, ID],
+	cfg *RepositoryDecoratorConfig,
+) (domaincrud.IRepository[T, ID], error) {
+	if cfg == nil {
+		return DecorateRepository(repo)
+	}
+	var decorators []RepositoryDecorator[T, ID]
+	if cfg.Retry != nil {
+		decorators = append(decorators, WithRepositoryRetry[T, ID](*cfg.Retry))
+	}
+	if cfg.Cache != nil {
+		decorators = append(decorators, WithRepositoryCache[T, ID](*cfg.Cache))
+	}
+	if cfg.Metrics != nil {
+		decorators = append(decorators, WithRepositoryMetrics[T, ID](*cfg.Metrics))
+	}
+	return DecorateRepository(repo, decorators...)
+}
+
+// repositoryTxKey 标记当前 ctx 位于装饰器透传的事务之内。
+type repositoryTxKey struct{}
+
+// withinRepositoryTx 判断 ctx 是否处于装饰仓储开启的事务中。
+func withinRepositoryTx(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	inTx, _ := ctx.Value(repositoryTxKey{}).(bool)
+	return inTx
+}
+
+// InterceptedRepository 把拦截器应用到仓储的全部调用上，并透传可选扩展能力。
+//
+// 说明：
+//   - 为了透传能力，InterceptedRepository 静态实现了全部可选扩展接口；底层不支持时返回 errors.Unsupported；
+//   - 探测能力请使用 domain/crud.RepositoryAs，它会沿 Unwrap 链确认底层仓储确实具备该能力；
+//   - WithinTx 不经过拦截器，事务内的每次仓储调用仍会单独经过拦截器。
+type InterceptedRepository[T domain.IEntity[ID], ID comparable] struct {
+	inner       domaincrud.IRepository[T, ID]
+	interceptor RepositoryInterceptor
+}
+
+// NewInterceptedRepository 创建拦截器仓储；interceptor 为 nil 时直接透传。
+func NewInterceptedRepository[T domain.IEntity[ID], ID comparable](
+	inner domaincrud.IRepository[T, ID],
+	interceptor RepositoryInterceptor,
+) (*InterceptedRepository[T, ID], error) {
+	if inner == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "inner repository cannot be nil")
+	}
+	return &InterceptedRepository[T, ID]{inner: inner, interceptor: interceptor}, nil
+}
+
+// Unwrap 返回被包装的仓储。
+func (r *InterceptedRepository[T, ID]) Unwrap() domaincrud.IRepository[T, ID] {
+	return r.inner
+}
+
+func (r *InterceptedRepository[T, ID]) invoke(ctx context.Context, op RepositoryOperation, call func(ctx context.Context) error) error {
+	if r.interceptor == nil {
+		return call(ctx)
+	}
+	return r.interceptor(ctx, op, call)
+}
+
+func unsupportedRepositoryCapability(op RepositoryOperation, required string) error {
+	return errors.NewCode(errors.Unsupported, "decorated repository does not support operation").
+		WithContext("operation", string(op)).
+		WithContext("required_interface", required)
+}
+
+// Create 创建实体。
+func (r *InterceptedRepository[T, ID]) Create(ctx context.Context, e T) error {
+	return r.invoke(ctx, RepositoryOpCreate, func(ctx context.Context) error {
+		return r.inner.Create(ctx, e)
+	})
+}
+
+// Update 更新实体。
+func (r *InterceptedRepository[T, ID]) Update(ctx context.Context, e T) error {
+	return r.invoke(ctx, RepositoryOpUpdate, func(ctx context.Context) error {
+		return r.inner.Update(ctx, e)
+	})
+}
+
+// Delete 删除实体。
+func (r *InterceptedRepository[T, ID]) Delete(ctx context.Context, id ID) error {
+	return r.invoke(ctx, RepositoryOpDelete, func(ctx context.Context) error {
+		return r.inner.Delete(ctx, id)
+	})
+}
+
+// Get 通过 ID 获取实体。
+func (r *InterceptedRepository[T, ID]) Get(ctx context.Context, id ID) (T, error) {
+	var result T
+	err := r.invoke(ctx, RepositoryOpGet, func(ctx context.Context) error {
+		var err error
+		result, err = r.inner.Get(ctx, id)
+		return err
+	})
+	return result, err
+}
+
+// GetWithDeleted 通过 ID 获取实体（包含已软删记录）。
+func (r *InterceptedRepository[T, ID]) GetWithDeleted(ctx context.Context, id ID) (T, error) {
+	var result T
+	repo, ok := r.inner.(audited.IRestoreRepository[T, ID])
+	if !ok {
+		return result, unsupportedRepositoryCapability(RepositoryOpGetWithDeleted, "domain/audited.IRestoreRepository")
+	}
+	err := r.invoke(ctx, RepositoryOpGetWithDeleted, func(ctx context.Context) error {
+		var err error
+		result, err = repo.GetWithDeleted(ctx, id)
+		return err
+	})
+	return result, err
+}
+
+// ListDeleted 分页查询已软删的实体。
+func (r *InterceptedRepository[T, ID]) ListDeleted(ctx context.Context, offset, limit int) ([]T, error) {
+	repo, ok := r.inner.(audited.IDeletedQueryRepository[T, ID])
+	if !ok {
+		return nil, unsupportedRepositoryCapability(RepositoryOpListDeleted, "domain/audited.IDeletedQueryRepository")
+	}
+	var result []T
+	err := r.invoke(ctx, RepositoryOpListDeleted, func(ctx context.Context) error {
+		var err error
+		result, err = repo.ListDeleted(ctx, offset, limit)
+		return err
+	})
+	return result, err
+}
+
+// List 分页查询实体。
+func (r *InterceptedRepository[T, ID]) List(ctx context.Context, offset, limit int) ([]T, error) {
+	repo, ok := r.inner.(domaincrud.IQueryRepository[T, ID])
+	if !ok {
+		return nil, unsupportedRepositoryCapability(RepositoryOpList, "domain/crud.IQueryRepository")
+	}
+	var result []T
+	err := r.invoke(ctx, RepositoryOpList, func(ctx context.Context) error {
+		var err error
+		result, err = repo.List(ctx, offset, limit)
+		return err
+	})
+	return result, err
+}
+
+// Count 统计实体总数。
+func (r *InterceptedRepository[T, ID]) Count(ctx context.Context) (int64, error) {
+	repo, ok := r.inner.(domaincrud.IQueryRepository[T, ID])
+	if !ok {
+		return 0, unsupportedRepositoryCapability(RepositoryOpCount, "domain/crud.IQueryRepository")
+	}
+	var result int64
+	err := r.invoke(ctx, RepositoryOpCount, func(ctx context.Context) error {
+		var err error
+		result, err = repo.Count(ctx)
+		return err
+	})
+	return result, err
+}
+
+// Exists 检查实体是否存在。
+func (r *InterceptedRepository[T, ID]) Exists(ctx context.Context, id ID) (bool, error) {
+	repo, ok := r.inner.(domaincrud.IQueryRepository[T, ID])
+	if !ok {
+		return false, unsupportedRepositoryCapability(RepositoryOpExists, "domain/crud.IQueryRepository")
+	}
+	var result bool
+	err := r.invoke(ctx, RepositoryOpExists, func(ctx context.Context) error {
+		var err error
+		result, err = repo.Exists(ctx, id)
+		return err
+	})
+	return result, err
+}
+
+// Query 执行统一查询。
+func (r *InterceptedRepository[T, ID]) Query(ctx context.Context, opts query.QueryOptions) ([]T, error) {
+	repo, ok := r.inner.(query.IQueryableRepository[T, ID])
+	if !ok {
+		return nil, unsupportedRepositoryCapability(RepositoryOpQuery, "db/query.IQueryableRepository")
+	}
+	var result []T
+	err := r.invoke(ctx, RepositoryOpQuery, func(ctx context.Context) error {
+		var err error
+		result, err = repo.Query(ctx, opts)
+		return err
+	})
+	return result, err
+}
+
+// QueryOne 查询单条记录。
+func (r *InterceptedRepository[T, ID]) QueryOne(ctx context.Context, opts query.QueryOptions) (T, error) {
+	var result T
+	repo, ok := r.inner.(query.IQueryableRepository[T, ID])
+	if !ok {
+		return result, unsupportedRepositoryCapability(RepositoryOpQueryOne, "db/query.IQueryableRepository")
+	}
+	err := r.invoke(ctx, RepositoryOpQueryOne, func(ctx context.Context) error {
+		var err error
+		result, err = repo.QueryOne(ctx, opts)
+		return err
+	})
+	return result, err
+}
+
+// QueryCount 统计统一查询结果数量。
+func (r *InterceptedRepository[T, ID]) QueryCount(ctx context.Context, opts query.QueryOptions) (int64, error) {
+	repo, ok := r.inner.(query.IQueryableRepository[T, ID])
+	if !ok {
+		return 0, unsupportedRepositoryCapability(RepositoryOpQueryCount, "db/query.IQueryableRepository")
+	}
+	var result int64
+	err := r.invoke(ctx, RepositoryOpQueryCount, func(ctx context.Context) error {
+		var err error
+		result, err = repo.QueryCount(ctx, opts)
+		return err
+	})
+	return result, err
+}
+
+// CreateAll 批量创建实体。
+func (r *InterceptedRepository[T, ID]) CreateAll(ctx context.Context, entities []T) error {
+	repo, ok := r.inner.(domaincrud.IBatchOperations[T, ID])
+	if !ok {
+		return unsupportedRepositoryCapability(RepositoryOpCreateAll, "domain/crud.IBatchOperations")
+	}
+	return r.invoke(ctx, RepositoryOpCreateAll, func(ctx context.Context) error {
+		return repo.CreateAll(ctx, entities)
+	})
+}
+
+// UpdateAll 批量更新实体。
+func (r *InterceptedRepository[T, ID]) UpdateAll(ctx context.Context, entities []T) error {
+	repo, ok := r.inner.(domaincrud.IBatchOperations[T, ID])
+	if !ok {
+		return unsupportedRepositoryCapability(RepositoryOpUpdateAll, "domain/crud.IBatchOperations")
+	}
+	return r.invoke(ctx, RepositoryOpUpdateAll, func(ctx context.Context) error {
+		return repo.UpdateAll(ctx, entities)
+	})
+}
+
+// DeleteAll 批量删除实体。
+func (r *InterceptedRepository[T, ID]) DeleteAll(ctx context.Context, ids []ID) error {
+	repo, ok := r.inner.(domaincrud.IBatchOperations[T, ID])
+	if !ok {
+		return unsupportedRepositoryCapability(RepositoryOpDeleteAll, "domain/crud.IBatchOperations")
+	}
+	return r.invoke(ctx, RepositoryOpDeleteAll, func(ctx context.Context) error {
+		return repo.DeleteAll(ctx, ids)
+	})
+}
+
+// Purge 永久删除实体。
+func (r *InterceptedRepository[T, ID]) Purge(ctx context.Context, id ID) error {
+	repo, ok := r.inner.(domaincrud.IPurgeRepository[T, ID])
+	if !ok {
+		return unsupportedRepositoryCapability(RepositoryOpPurge, "domain/crud.IPurgeRepository")
+	}
+	return r.invoke(ctx, RepositoryOpPurge, func(ctx context.Context) error {
+		return repo.Purge(ctx, id)
+	})
+}
+
+// UpdateFields 按字段局部更新实体。
+func (r *InterceptedRepository[T, ID]) UpdateFields(ctx context.Context, e T, fields []string) error {
+	repo, ok := r.inner.(domaincrud.IPatchRepository[T, ID])
+	if !ok {
+		return unsupportedRepositoryCapability(RepositoryOpUpdateFields, "domain/crud.IPatchRepository")
+	}
+	return r.invoke(ctx, RepositoryOpUpdateFields, func(ctx context.Context) error {
+		return repo.UpdateFields(ctx, e, fields)
+	})
+}
+
+// CreateWithConstraint 在写入约束下创建实体。
+func (r *InterceptedRepository[T, ID]) CreateWithConstraint(ctx context.Context, e T, constraint access.WriteConstraint) error {
+	repo, ok := r.inner.(access.IWriteConstraintRepository[T, ID])
+	if !ok {
+		return unsupportedRepositoryCapability(RepositoryOpCreateWithConstraint, "domain/access.IWriteConstraintRepository")
+	}
+	return r.invoke(ctx, RepositoryOpCreateWithConstraint, func(ctx context.Context) error {
+		return repo.CreateWithConstraint(ctx, e, constraint)
+	})
+}
+
+// UpdateWithConstraint 在写入约束下更新实体。
+func (r *InterceptedRepository[T, ID]) UpdateWithConstraint(ctx context.Context, e T, constraint access.WriteConstraint) error {
+	repo, ok := r.inner.(access.IWriteConstraintRepository[T, ID])
+	if !ok {
+		return unsupportedRepositoryCapability(RepositoryOpUpdateWithConstraint, "domain/access.IWriteConstraintRepository")
+	}
+	return r.invoke(ctx, RepositoryOpUpdateWithConstraint, func(ctx context.Context) error {
+		return repo.UpdateWithConstraint(ctx, e, constraint)
+	})
+}
+
+// DeleteWithConstraint 在写入约束下删除实体。
+func (r *InterceptedRepository[T, ID]) DeleteWithConstraint(ctx context.Context, id ID, constraint access.WriteConstraint) error {
+	repo, ok := r.inner.(access.IWriteConstraintRepository[T, ID])
+	if !ok {
+		return unsupportedRepositoryCapability(RepositoryOpDeleteWithConstraint, "domain/access.IWriteConstraintRepository")
+	}
+	return r.invoke(ctx, RepositoryOpDeleteWithConstraint, func(ctx context.Context) error {
+		return repo.DeleteWithConstraint(ctx, id, constraint)
+	})
+}
+
+// CreateAllWithConstraint 在写入约束下批量创建实体。
+func (r *InterceptedRepository[T, ID]) CreateAllWithConstraint(ctx context.Context, entities []T, constraint access.WriteConstraint) error {
+	repo, ok := r.inner.(access.IWriteConstraintBatchRepository[T, ID])
+	if !ok {
+		return unsupportedRepositoryCapability(RepositoryOpCreateAllWithConstraint, "domain/access.IWriteConstraintBatchRepository")
+	}
+	return r.invoke(ctx, RepositoryOpCreateAllWithConstraint, func(ctx context.Context) error {
+		return repo.CreateAllWithConstraint(ctx, entities, constraint)
+	})
+}
+
+// UpdateAllWithConstraint 在写入约束下批量更新实体。
+func (r *InterceptedRepository[T, ID]) UpdateAllWithConstraint(ctx context.Context, entities []T, constraint access.WriteConstraint) error {
+	repo, ok := r.inner.(access.IWriteConstraintBatchRepository[T, ID])
+	if !ok {
+		return unsupportedRepositoryCapability(RepositoryOpUpdateAllWithConstraint, "domain/access.IWriteConstraintBatchRepository")
+	}
+	return r.invoke(ctx, RepositoryOpUpdateAllWithConstraint, func(ctx context.Context) error {
+		return repo.UpdateAllWithConstraint(ctx, entities, constraint)
+	})
+}
+
+// DeleteAllWithConstraint 在写入约束下批量删除实体。
+func (r *InterceptedRepository[T, ID]) DeleteAllWithConstraint(ctx context.Context, ids []ID, constraint access.WriteConstraint) error {
+	repo, ok := r.inner.(access.IWriteConstraintBatchRepository[T, ID])
+	if !ok {
+		return unsupportedRepositoryCapability(RepositoryOpDeleteAllWithConstraint, "domain/access.IWriteConstraintBatchRepository")
+	}
+	return r.invoke(ctx, RepositoryOpDeleteAllWithConstraint, func(ctx context.Context) error {
+		return repo.DeleteAllWithConstraint(ctx, ids, constraint)
+	})
+}
+
+// ResolveResourceByID 按资源 ID 解析访问边界。
+func (r *InterceptedRepository[T, ID]) ResolveResourceByID(ctx context.Context, id ID) (access.ResourceBoundary, error) {
+	var result access.ResourceBoundary
+	repo, ok := r.inner.(access.IResourceBoundaryRepository[T, ID])
+	if !ok {
+		return result, unsupportedRepositoryCapability(RepositoryOpResolveResource, "domain/access.IResourceBoundaryRepository")
+	}
+	err := r.invoke(ctx, RepositoryOpResolveResource, func(ctx context.Context) error {
+		var err error
+		result, err = repo.ResolveResourceByID(ctx, id)
+		return err
+	})
+	return result, err
+}
+
+// WithinTx 在底层仓储的事务中执行 fn。
+func (r *InterceptedRepository[T, ID]) WithinTx(ctx context.Context, fn func(txCtx context.Context) error) error {
+	txRepo, ok := r.inner.(ITransactional)
+	if !ok {
+		return errors.NewCode(errors.Unsupported, "decorated repository inner does not implement transactions")
+	}
+	return txRepo.WithinTx(ctx, func(txCtx context.Context) error {
+		return fn(context.WithValue(txCtx, repositoryTxKey{}, true))
+	})
+}
+
+// 接口断言。
+var (
+	_ domaincrud.IRepository[*domaincrud.Entity[int64], int64]                 = (*InterceptedRepository[*domaincrud.Entity[int64], int64])(nil)
+	_ domaincrud.IQueryRepository[*domaincrud.Entity[int64], int64]            = (*InterceptedRepository[*domaincrud.Entity[int64], int64])(nil)
+	_ domaincrud.IBatchOperations[*domaincrud.Entity[int64], int64]            = (*InterceptedRepository[*domaincrud.Entity[int64], int64])(nil)
+	_ domaincrud.IPurgeRepository[*domaincrud.Entity[int64], int64]            = (*InterceptedRepository[*domaincrud.Entity[int64], int64])(nil)
+	_ domaincrud.IPatchRepository[*domaincrud.Entity[int64], int64]            = (*InterceptedRepository[*domaincrud.Entity[int64], int64])(nil)
+	_ audited.IRestoreRepository[*domaincrud.Entity[int64], int64]             = (*InterceptedRepository[*domaincrud.Entity[int64], int64])(nil)
+	_ audited.IDeletedQueryRepository[*domaincrud.Entity[int64], int64]        = (*InterceptedRepository[*domaincrud.Entity[int64], int64])(nil)
+	_ domaincrud.IRepositoryUnwrapper[*domaincrud.Entity[int64], int64]        = (*InterceptedRepository[*domaincrud.Entity[int64], int64])(nil)
+	_ query.IQueryableRepository[*domaincrud.Entity[int64], int64]             = (*InterceptedRepository[*domaincrud.Entity[int64], int64])(nil)
+	_ access.IWriteConstraintRepository[*domaincrud.Entity[int64], int64]      = (*InterceptedRepository[*domaincrud.Entity[int64], int64])(nil)
+	_ access.IWriteConstraintBatchRepository[*domaincrud.Entity[int64], int64] = (*InterceptedRepository[*domaincrud.Entity[int64], int64])(nil)
+	_ access.IResourceBoundaryRepository[*domaincrud.Entity[int64], int64]     = (*InterceptedRepository[*domaincrud.Entity[int64], int64])(nil)
+	_ ITransactional                                                           = (*InterceptedRepository[*domaincrud.Entity[int64], int64])(nil)
+)
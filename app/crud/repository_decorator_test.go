@@ -0,0 +1,189 @@
+package crud
+
+import (
+	"context"
+	"testing"
+
+	"gochen/db/query"
+	domaincrud "gochen/domain/crud"
+	"gochen/errors"
+	"gochen/observe"
+	"gochen/policy/retry"
+)
+
+// countingGetRepo 统计 Get 调用次数，并可按次数注入错误。
+type countingGetRepo struct {
+	*batchTestRepo
+	gets    int
+	failFor int
+	failErr error
+}
+
+func (r *countingGetRepo) Get(ctx context.Context, id int64) (testEntity, error) {
+	r.gets++
+	if r.gets <= r.failFor {
+		return testEntity{}, r.failErr
+	}
+	return r.batchTestRepo.Get(ctx, id)
+}
+
+func newCountingGetRepo(ids ...int64) *countingGetRepo {
+	repo := &countingGetRepo{batchTestRepo: newBatchTestRepo()}
+	for _, id := range ids {
+		repo.existingIDs[id] = true
+	}
+	return repo
+}
+
+// TestDecorateRepository_ProbesUnderlyingCapabilities 验证装饰后的仓储只暴露底层真实具备的能力。
+func TestDecorateRepository_ProbesUnderlyingCapabilities(t *testing.T) {
+	decorated, err := DecorateRepository[testEntity, int64](&recordingRepo{},
+		WithRepositoryRetry[testEntity, int64](RepositoryRetryConfig{}),
+		WithRepositoryMetrics[testEntity, int64](RepositoryMetricsConfig{Entity: "test"}),
+	)
+	if err != nil {
+		t.Fatalf("decorate repository: %v", err)
+	}
+	if _, ok := domaincrud.RepositoryAs[domaincrud.IQueryRepository[testEntity, int64]](decorated); !ok {
+		t.Fatalf("expected IQueryRepository capability to be forwarded")
+	}
+	if _, ok := domaincrud.RepositoryAs[query.IQueryableRepository[testEntity, int64]](decorated); ok {
+		t.Fatalf("expected IQueryableRepository capability to be hidden")
+	}
+	if _, ok := domaincrud.RepositoryAs[ITransactional](decorated); ok {
+		t.Fatalf("expected ITransactional capability to be hidden")
+	}
+
+	app, err := NewApplication[testEntity, int64](decorated, nil, nil)
+	if err != nil {
+		t.Fatalf("new application: %v", err)
+	}
+	if _, err := app.ListByQuery(context.Background(), nil); err != nil {
+		t.Fatalf("expected list fallback through decorators, got %v", err)
+	}
+}
+
+// TestRepositoryMetrics_RecordsOperations 验证指标装饰器按 entity/operation/status 记录调用。
+func TestRepositoryMetrics_RecordsOperations(t *testing.T) {
+	metrics := observe.NewInMemoryMetrics()
+	decorated, err := DecorateRepository[testEntity, int64](newCountingGetRepo(1),
+		WithRepositoryMetrics[testEntity, int64](RepositoryMetricsConfig{Metrics: metrics, Namespace: "app", Entity: "test"}),
+	)
+	if err != nil {
+		t.Fatalf("decorate repository: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := decorated.Get(ctx, 1); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if _, err := decorated.Get(ctx, 2); !errors.Is(err, errors.NotFound) {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+
+	ok := metrics.CounterValue("app_repository_operations_total", map[string]string{"entity": "test", "operation": "get", "status": "ok"})
+	notFound := metrics.CounterValue("app_repository_operations_total", map[string]string{"entity": "test", "operation": "get", "status": string(errors.NotFound)})
+	if ok != 1 || notFound != 1 {
+		t.Fatalf("unexpected counters: ok=%d not_found=%d", ok, notFound)
+	}
+}
+
+// TestRepositoryRetry_RetriesTransientReads 验证重试装饰器仅对瞬时读错误重试。
+func TestRepositoryRetry_RetriesTransientReads(t *testing.T) {
+	repo := newCountingGetRepo(1)
+	repo.failFor = 2
+	repo.failErr = errors.NewCode(errors.ServiceUnavailable, "db unavailable")
+
+	decorated, err := DecorateRepository[testEntity, int64](repo,
+		WithRepositoryRetry[testEntity, int64](RepositoryRetryConfig{Policy: retry.Config{MaxAttempts: 3}}),
+	)
+	if err != nil {
+		t.Fatalf("decorate repository: %v", err)
+	}
+	if _, err := decorated.Get(context.Background(), 1); err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if repo.gets != 3 {
+		t.Fatalf("expected 3 attempts, got %d", repo.gets)
+	}
+
+	repo.gets = 0
+	repo.failFor = 5
+	repo.failErr = errors.NewCode(errors.NotFound, "missing")
+	if _, err := decorated.Get(context.Background(), 1); !errors.Is(err, errors.NotFound) {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+	if repo.gets != 1 {
+		t.Fatalf("expected non-transient error not to be retried, got %d attempts", repo.gets)
+	}
+}
+
+// TestCachedRepository_GetAndInvalidate 验证缓存命中与写操作失效。
+func TestCachedRepository_GetAndInvalidate(t *testing.T) {
+	repo := newCountingGetRepo(1)
+	decorated, err := DecorateRepository[testEntity, int64](repo,
+		WithRepositoryCache[testEntity, int64](RepositoryCacheConfig{Name: "test"}),
+	)
+	if err != nil {
+		t.Fatalf("decorate repository: %v", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := decorated.Get(ctx, 1); err != nil {
+			t.Fatalf("get: %v", err)
+		}
+	}
+	if repo.gets != 1 {
+		t.Fatalf("expected cached reads, got %d inner gets", repo.gets)
+	}
+
+	if err := decorated.Update(ctx, testEntity{id: 1}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if _, err := decorated.Get(ctx, 1); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if repo.gets != 2 {
+		t.Fatalf("expected update to invalidate cache, got %d inner gets", repo.gets)
+	}
+}
+
+// TestCachedRepository_BypassesCacheInsideTx 验证事务内读取绕过缓存且不会回填未提交数据。
+func TestCachedRepository_BypassesCacheInsideTx(t *testing.T) {
+	repo := newCountingGetRepo(1)
+	app, err := NewApplication[testEntity, int64](repo, nil, &ServiceConfig{
+		MaxBatchSize: 10,
+		MaxPageSize:  10,
+		Decorators: &RepositoryDecoratorConfig{
+			Cache: &RepositoryCacheConfig{Name: "test"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("new application: %v", err)
+	}
+	txRepo, ok := app.transactionalRepository()
+	if !ok {
+		t.Fatalf("expected decorated repository to forward transactions")
+	}
+
+	ctx := context.Background()
+	if _, err := app.Get(ctx, 1); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	err = txRepo.WithinTx(ctx, func(txCtx context.Context) error {
+		if _, err := app.Get(txCtx, 1); err != nil {
+			return err
+		}
+		return app.Repository().Delete(txCtx, 1)
+	})
+	if err != nil {
+		t.Fatalf("within tx: %v", err)
+	}
+	if repo.gets != 2 {
+		t.Fatalf("expected tx read to bypass cache, got %d inner gets", repo.gets)
+	}
+	if _, err := app.Get(ctx, 1); !errors.Is(err, errors.NotFound) {
+		t.Fatalf("expected deleted entity to be evicted after commit, got %v", err)
+	}
+}
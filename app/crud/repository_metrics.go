@@ -0,0 +1,67 @@
+package crud
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gochen/domain"
+	"gochen/errors"
+	"gochen/observe"
+)
+
+// RepositoryMetricsConfig 仓储指标装饰配置。
+type RepositoryMetricsConfig struct {
+	// Metrics 指标收集器；默认使用无操作实现。
+	Metrics observe.IMetrics
+
+	// Namespace 指标命名空间前缀（可选）。
+	Namespace string
+
+	// Entity 实体名称，作为 entity 标签区分不同仓储。
+	Entity string
+}
+
+// NewRepositoryMetricsInterceptor 创建记录仓储调用次数与耗时的拦截器。
+//
+// 指标名称：
+// - {namespace}_repository_operations_total：调用次数计数器
+// - {namespace}_repository_operation_duration_ms：调用耗时直方图（毫秒）
+//
+// 标签：entity、operation、status（成功为 ok，失败为错误码）。
+func NewRepositoryMetricsInterceptor(cfg RepositoryMetricsConfig) RepositoryInterceptor {
+	metrics := cfg.Metrics
+	if metrics == nil {
+		metrics = &observe.DefaultMetrics{}
+	}
+	namespace := cfg.Namespace
+	if namespace != "" && !strings.HasSuffix(namespace, "_") {
+		namespace = namespace + "_"
+	}
+	operationsTotal := namespace + "repository_operations_total"
+	operationDuration := namespace + "repository_operation_duration_ms"
+
+	return func(ctx context.Context, op RepositoryOperation, call func(ctx context.Context) error) error {
+		start := time.Now()
+		err := call(ctx)
+		elapsed := time.Since(start)
+
+		status := "ok"
+		if err != nil {
+			status = string(errors.Code(err))
+		}
+		labels := observe.MetricLabels{
+			"entity":    cfg.Entity,
+			"operation": string(op),
+			"status":    status,
+		}
+		metrics.Counter(operationsTotal, 1, labels)
+		metrics.Histogram(operationDuration, float64(elapsed.Milliseconds()), labels)
+		return err
+	}
+}
+
+// WithRepositoryMetrics 返回指标装饰器。
+func WithRepositoryMetrics[T domain.IEntity[ID], ID comparable](cfg RepositoryMetricsConfig) RepositoryDecorator[T, ID] {
+	return WithRepositoryInterceptor[T, ID](NewRepositoryMetricsInterceptor(cfg))
+}
@@ -0,0 +1,80 @@
+package crud
+
+import (
+	"context"
+	"net"
+
+	"gochen/domain"
+	"gochen/errors"
+	"gochen/policy/retry"
+)
+
+// RepositoryRetryConfig 仓储重试装饰配置。
+type RepositoryRetryConfig struct {
+	// Policy 重试策略；MaxAttempts <= 0 时使用 retry.DefaultConfig()。
+	//
+	// 说明：Policy.RetryIf 为空时使用 IsTransientRepositoryError。
+	Policy retry.Config
+
+	// RetryWrites 是否对写操作重试（默认仅重试读操作）。
+	//
+	// 说明：写操作超时后可能已在数据库侧生效，仅在写入幂等时开启。
+	RetryWrites bool
+}
+
+// IsTransientRepositoryError 判断仓储错误是否为可重试的瞬时错误。
+//
+// 说明：
+// - Timeout/ServiceUnavailable/Network/TooManyRequests 视为瞬时错误；
+// - 实现 retry.IRetryableError 的错误遵循其 IsRetryable()；
+// - 超时类 net.Error 视为瞬时错误；
+// - NotFound/Conflict/Concurrency/Validation 等业务语义错误不重试。
+func IsTransientRepositoryError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var re retry.IRetryableError
+	if errors.As(err, &re) {
+		return re.IsRetryable()
+	}
+	var ne net.Error
+	if errors.As(err, &ne) {
+		return ne.Timeout()
+	}
+	switch errors.Code(err) {
+	case errors.Timeout, errors.ServiceUnavailable, errors.Network, errors.TooManyRequests:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewRepositoryRetryInterceptor 创建对瞬时错误重试的拦截器。
+//
+// 说明：事务内（经由装饰仓储的 WithinTx 开启）的调用不重试，避免在已中止的事务上反复执行。
+func NewRepositoryRetryInterceptor(cfg RepositoryRetryConfig) RepositoryInterceptor {
+	policy := cfg.Policy
+	if policy.MaxAttempts <= 0 {
+		retryIf := policy.RetryIf
+		policy = retry.DefaultConfig()
+		policy.RetryIf = retryIf
+	}
+	if policy.RetryIf == nil {
+		policy.RetryIf = IsTransientRepositoryError
+	}
+
+	return func(ctx context.Context, op RepositoryOperation, call func(ctx context.Context) error) error {
+		if withinRepositoryTx(ctx) || (!op.IsRead() && !cfg.RetryWrites) {
+			return call(ctx)
+		}
+		return retry.Do(ctx, retry.Operation(call), policy)
+	}
+}
+
+// WithRepositoryRetry 返回重试装饰器。
+func WithRepositoryRetry[T domain.IEntity[ID], ID comparable](cfg RepositoryRetryConfig) RepositoryDecorator[T, ID] {
+	return WithRepositoryInterceptor[T, ID](NewRepositoryRetryInterceptor(cfg))
+}
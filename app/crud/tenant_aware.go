@@ -31,7 +31,7 @@ func WithTenantResolver[T domaincrud.ITenantEntity[ID], ID comparable](resolver
 // NewTenantAwareWrapper 创建租户感知仓储包装器。
 func NewTenantAwareWrapper[T domaincrud.ITenantEntity[ID], ID comparable](inner domaincrud.IRepository[T, ID], opts ...TenantAwareOption[T, ID]) *TenantAwareWrapper[T, ID] {
 	wrapper := &TenantAwareWrapper[T, ID]{inner: inner}
-	if queryable, ok := inner.(query.IQueryableRepository[T, ID]); ok && queryable != nil {
+	if queryable, ok := domaincrud.RepositoryAs[query.IQueryableRepository[T, ID]](inner); ok && queryable != nil {
 		wrapper.queryable = queryable
 	}
 	for _, opt := range opts {
@@ -219,7 +219,7 @@ func (w *TenantAwareWrapper[T, ID]) QueryCount(ctx context.Context, opts query.Q
 
 // WithinTx 在事务中执行 fn。
 func (w *TenantAwareWrapper[T, ID]) WithinTx(ctx context.Context, fn func(txCtx context.Context) error) error {
-	txRepo, ok := w.inner.(ITransactional)
+	txRepo, ok := domaincrud.RepositoryAs[ITransactional](w.inner)
 	if !ok || txRepo == nil {
 		return errors.NewCode(errors.Unsupported, "tenant-aware repository inner does not implement transactions")
 	}
@@ -0,0 +1,108 @@
+package crud
+
+import (
+	"context"
+
+	"gochen/app/internal/writeflow"
+	"gochen/domain"
+	"gochen/domain/audited"
+	domaincrud "gochen/domain/crud"
+	"gochen/errors"
+)
+
+// ITrash 表示软删实体的回收站能力：查看已删除、恢复与永久删除。
+type ITrash[T domain.IEntity[ID], ID comparable] interface {
+	ListDeleted(ctx context.Context, offset, limit int) ([]T, error)
+	Restore(ctx context.Context, id ID) error
+	Purge(ctx context.Context, id ID) error
+}
+
+// Trash 以外部包装器形式承载软删实体的回收站能力，避免污染 Application 主接口。
+//
+// 说明：
+//   - 实体需实现 domain.ISoftDeletable，恢复经由实体状态机（Restore）后写回仓储；
+//   - 仓储能力按需探测：ListDeleted 需要 audited.IDeletedQueryRepository，Restore 需要 audited.IRestoreRepository，
+//     Purge 需要 domain/crud.IPurgeRepository；缺失时返回 errors.Unsupported；
+//   - audited 实体请使用 app/audited.Application，它在相同操作上额外记录审计。
+type Trash[T domain.IEntity[ID], ID comparable] struct {
+	service *Application[T, ID]
+}
+
+// NewTrash 创建回收站包装器。
+func NewTrash[T domain.IEntity[ID], ID comparable](service *Application[T, ID]) *Trash[T, ID] {
+	return &Trash[T, ID]{service: service}
+}
+
+func (t *Trash[T, ID]) serviceOrErr() (*Application[T, ID], error) {
+	if t == nil || t.service == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "trash service is nil")
+	}
+	return t.service, nil
+}
+
+// ListDeleted 返回已软删的实体列表。
+func (t *Trash[T, ID]) ListDeleted(ctx context.Context, offset, limit int) ([]T, error) {
+	s, err := t.serviceOrErr()
+	if err != nil {
+		return nil, err
+	}
+	repo, ok := domaincrud.RepositoryAs[audited.IDeletedQueryRepository[T, ID]](s.repository)
+	if !ok {
+		return nil, errors.NewCode(errors.Unsupported, "list deleted requires repository to implement audited.IDeletedQueryRepository")
+	}
+	return repo.ListDeleted(ctx, offset, limit)
+}
+
+// Restore 恢复已软删的实体；实体未删除时返回 errors.Conflict。
+func (t *Trash[T, ID]) Restore(ctx context.Context, id ID) error {
+	s, err := t.serviceOrErr()
+	if err != nil {
+		return err
+	}
+	repo, ok := domaincrud.RepositoryAs[audited.IRestoreRepository[T, ID]](s.repository)
+	if !ok {
+		return errors.NewCode(errors.Unsupported, "restore requires repository to implement audited.IRestoreRepository")
+	}
+
+	return s.runWriteFlow(ctx, writeflow.Plan{
+		Write: func(writeCtx context.Context) error {
+			entity, err := repo.GetWithDeleted(writeCtx, id)
+			if err != nil {
+				return err
+			}
+			deletable, ok := any(entity).(domain.ISoftDeletable)
+			if !ok {
+				return errors.NewCode(errors.Unsupported, "entity does not implement domain.ISoftDeletable")
+			}
+			if err := deletable.Restore(); err != nil {
+				return err
+			}
+			return s.repository.Update(writeCtx, entity)
+		},
+	})
+}
+
+// Purge 永久删除实体（物理删除，不可恢复），执行 Delete 的生命周期钩子。
+func (t *Trash[T, ID]) Purge(ctx context.Context, id ID) error {
+	s, err := t.serviceOrErr()
+	if err != nil {
+		return err
+	}
+	repo, ok := domaincrud.RepositoryAs[domaincrud.IPurgeRepository[T, ID]](s.repository)
+	if !ok {
+		return errors.NewCode(errors.Unsupported, "purge requires repository to implement crud.IPurgeRepository")
+	}
+	return s.runWriteFlow(ctx, writeflow.Plan{
+		Before: func(writeCtx context.Context) error {
+			return s.runBeforeDelete(writeCtx, id)
+		},
+		Write: func(writeCtx context.Context) error {
+			return repo.Purge(writeCtx, id)
+		},
+		After: func(writeCtx context.Context) error {
+			return s.runAfterDelete(writeCtx, id)
+		},
+		PostCommits:     callbacksToPostCommits([]func(context.Context) error{s.postCommitDelete(id)}),
+		CallbackContext: ctx,
+	})
+}
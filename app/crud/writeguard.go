@@ -6,6 +6,7 @@ import (
 	"gochen/app/internal/writeflow"
 	"gochen/domain"
 	"gochen/domain/access"
+	domaincrud "gochen/domain/crud"
 	"gochen/errors"
 )
 
@@ -39,7 +40,7 @@ func (w *WriteConstraintWriter[T, ID]) CreateWithConstraint(ctx context.Context,
 	if err != nil {
 		return err
 	}
-	repo, ok := s.repository.(access.IWriteConstraintRepository[T, ID])
+	repo, ok := domaincrud.RepositoryAs[access.IWriteConstraintRepository[T, ID]](s.repository)
 	if !ok {
 		return errors.NewCode(errors.Unsupported, "repository does not support write constraint")
 	}
@@ -67,7 +68,7 @@ func (w *WriteConstraintWriter[T, ID]) UpdateWithConstraint(ctx context.Context,
 	if err != nil {
 		return err
 	}
-	repo, ok := s.repository.(access.IWriteConstraintRepository[T, ID])
+	repo, ok := domaincrud.RepositoryAs[access.IWriteConstraintRepository[T, ID]](s.repository)
 	if !ok {
 		return errors.NewCode(errors.Unsupported, "repository does not support write constraint")
 	}
@@ -95,7 +96,7 @@ func (w *WriteConstraintWriter[T, ID]) DeleteWithConstraint(ctx context.Context,
 	if err != nil {
 		return err
 	}
-	repo, ok := s.repository.(access.IWriteConstraintRepository[T, ID])
+	repo, ok := domaincrud.RepositoryAs[access.IWriteConstraintRepository[T, ID]](s.repository)
 	if !ok {
 		return errors.NewCode(errors.Unsupported, "repository does not support write constraint")
 	}
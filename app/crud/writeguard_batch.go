@@ -8,6 +8,7 @@ import (
 	"gochen/app/internal/writeflow"
 	"gochen/domain"
 	"gochen/domain/access"
+	domaincrud "gochen/domain/crud"
 	"gochen/errors"
 )
 
@@ -31,8 +32,8 @@ func (w *WriteConstraintWriter[T, ID]) CreateAllWithConstraint(ctx context.Conte
 		return errors.NewCode(errors.Validation, fmt.Sprintf("batch size exceeds maximum limit of %d", s.config.MaxBatchSize))
 	}
 	repo := s.Repository()
-	batchRepo, hasBatchRepo := repo.(access.IWriteConstraintBatchRepository[T, ID])
-	constraintRepo, hasConstraintRepo := repo.(access.IWriteConstraintRepository[T, ID])
+	batchRepo, hasBatchRepo := domaincrud.RepositoryAs[access.IWriteConstraintBatchRepository[T, ID]](repo)
+	constraintRepo, hasConstraintRepo := domaincrud.RepositoryAs[access.IWriteConstraintRepository[T, ID]](repo)
 	_, hasTxRepo := s.transactionalRepository()
 	return s.runWriteFlow(ctx, writeflow.Plan{
 		Before: writeflow.ForEach(entities, s.runBeforeCreate),
@@ -84,8 +85,8 @@ func (w *WriteConstraintWriter[T, ID]) UpdateAllWithConstraint(ctx context.Conte
 		return errors.NewCode(errors.Validation, fmt.Sprintf("batch size exceeds maximum limit of %d", s.config.MaxBatchSize))
 	}
 	repo := s.Repository()
-	batchRepo, hasBatchRepo := repo.(access.IWriteConstraintBatchRepository[T, ID])
-	constraintRepo, hasConstraintRepo := repo.(access.IWriteConstraintRepository[T, ID])
+	batchRepo, hasBatchRepo := domaincrud.RepositoryAs[access.IWriteConstraintBatchRepository[T, ID]](repo)
+	constraintRepo, hasConstraintRepo := domaincrud.RepositoryAs[access.IWriteConstraintRepository[T, ID]](repo)
 	_, hasTxRepo := s.transactionalRepository()
 	return s.runWriteFlow(ctx, writeflow.Plan{
 		Before: writeflow.ForEach(entities, s.runBeforeUpdate),
@@ -137,8 +138,8 @@ func (w *WriteConstraintWriter[T, ID]) DeleteAllWithConstraint(ctx context.Conte
 		return errors.NewCode(errors.Validation, fmt.Sprintf("batch size exceeds maximum limit of %d", s.config.MaxBatchSize))
 	}
 	repo := s.Repository()
-	batchRepo, hasBatchRepo := repo.(access.IWriteConstraintBatchRepository[T, ID])
-	constraintRepo, hasConstraintRepo := repo.(access.IWriteConstraintRepository[T, ID])
+	batchRepo, hasBatchRepo := domaincrud.RepositoryAs[access.IWriteConstraintBatchRepository[T, ID]](repo)
+	constraintRepo, hasConstraintRepo := domaincrud.RepositoryAs[access.IWriteConstraintRepository[T, ID]](repo)
 	_, hasTxRepo := s.transactionalRepository()
 	return s.runWriteFlow(ctx, writeflow.Plan{
 		Before: writeflow.ForEach(ids, s.runBeforeDelete),
@@ -0,0 +1,64 @@
+package eventsourced
+
+import (
+	"context"
+
+	auth "gochen/auth"
+	deventsourced "gochen/domain/eventsourced"
+	"gochen/errors"
+)
+
+// IAuthorizer 命令授权接口。
+//
+// EventSourcedService 在加载聚合之后、BeforeExecute 钩子与 handler 之前调用 Authorize，
+// 用于集中实现归属校验、角色校验等规则，而不必在每个 handler 闭包里重复。
+//
+// 注意：
+// - 仓储使用 GetOrCreate 加载聚合，首次创建的聚合版本为 0，实现需要区分“新建”与“已有”两种情况；
+// - 启用并发重试时，每次尝试都会基于重新加载的聚合再次授权；
+// - 返回 *errors.AppError 时原样透传，其他错误统一包装为 Forbidden。
+type IAuthorizer[T deventsourced.IEventSourcedAggregate[ID], ID comparable] interface {
+	Authorize(ctx context.Context, cmd IEventSourcedCommand[ID], agg T) error
+}
+
+// AuthorizerFunc 是 IAuthorizer 的函数适配器。
+type AuthorizerFunc[T deventsourced.IEventSourcedAggregate[ID], ID comparable] func(ctx context.Context, cmd IEventSourcedCommand[ID], agg T) error
+
+// Authorize 调用函数本身。
+func (f AuthorizerFunc[T, ID]) Authorize(ctx context.Context, cmd IEventSourcedCommand[ID], agg T) error {
+	return f(ctx, cmd, agg)
+}
+
+// RequireAnyRole 返回要求上下文主体命中任一角色的授权器。
+//
+// 缺少主体返回 Unauthorized，未命中角色返回 Forbidden；IsSystem 主体直接放行。
+func RequireAnyRole[T deventsourced.IEventSourcedAggregate[ID], ID comparable](roles ...string) AuthorizerFunc[T, ID] {
+	return func(ctx context.Context, cmd IEventSourcedCommand[ID], agg T) error {
+		principal, err := auth.RequirePrincipal(ctx)
+		if err != nil {
+			return err
+		}
+		if principal.IsSystem || principal.HasAnyRole(roles...) {
+			return nil
+		}
+		return errors.NewCode(errors.Forbidden, "required role missing").
+			WithContext("aggregate_id", cmd.AggregateID()).
+			WithContext("roles", roles)
+	}
+}
+
+// authorize 执行已配置的授权器。
+func (s *EventSourcedService[T, ID]) authorize(ctx context.Context, cmd IEventSourcedCommand[ID], aggregate T) error {
+	if s.authorizer == nil {
+		return nil
+	}
+	if err := s.authorizer.Authorize(ctx, cmd, aggregate); err != nil {
+		var appErr *errors.AppError
+		if errors.As(err, &appErr) && appErr != nil {
+			return appErr
+		}
+		return errors.Wrap(err, errors.Forbidden, "command authorization failed").
+			WithContext("aggregate_id", cmd.AggregateID())
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package eventsourced
+
+import (
+	"context"
+	stdErrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	auth "gochen/auth"
+	"gochen/errors"
+)
+
+// TestEventSourcedService_Authorizer_RunsBeforeHooksAndHandler 验证授权器拒绝时 hook、handler 与保存都不会执行。
+func TestEventSourcedService_Authorizer_RunsBeforeHooksAndHandler(t *testing.T) {
+	repo := &okRepo{}
+	hook := &recordingHook{}
+	var authorizedAgg *retryTestAggregate
+	svc, err := NewEventSourcedService[*retryTestAggregate, int64](repo, &EventSourcedServiceOptions[*retryTestAggregate, int64]{
+		CommandHooks: []IEventSourcedCommandHook[*retryTestAggregate, int64]{hook},
+		Authorizer: AuthorizerFunc[*retryTestAggregate, int64](func(ctx context.Context, cmd IEventSourcedCommand[int64], agg *retryTestAggregate) error {
+			authorizedAgg = agg
+			if cmd.AggregateID() == 2 {
+				return stdErrors.New("not the owner")
+			}
+			return nil
+		}),
+	})
+	require.NoError(t, err)
+
+	handlerCalls := 0
+	require.NoError(t, svc.RegisterCommandHandler(&retryTestCommand{}, func(ctx context.Context, cmd IEventSourcedCommand[int64], aggregate *retryTestAggregate) error {
+		handlerCalls++
+		return nil
+	}))
+
+	require.NoError(t, svc.ExecuteCommand(context.Background(), &retryTestCommand{id: 1}))
+	require.NotNil(t, authorizedAgg)
+	require.Equal(t, int64(1), authorizedAgg.GetID())
+	require.Equal(t, 1, handlerCalls)
+	require.Equal(t, 1, repo.saveCalls)
+
+	execErr := svc.ExecuteCommand(context.Background(), &retryTestCommand{id: 2})
+	require.True(t, errors.Is(execErr, errors.Forbidden), "plain errors are wrapped as forbidden: %v", execErr)
+	require.Equal(t, 1, handlerCalls)
+	require.Equal(t, 1, repo.saveCalls)
+	require.Equal(t, 1, hook.beforeCalls, "BeforeExecute hooks do not run for denied commands")
+	require.Equal(t, 2, hook.afterCalls)
+	require.True(t, errors.Is(hook.lastErr, errors.Forbidden))
+}
+
+// TestRequireAnyRole 验证基于上下文主体的角色授权器。
+func TestRequireAnyRole(t *testing.T) {
+	authorizer := RequireAnyRole[*retryTestAggregate, int64]("operator")
+	cmd := &retryTestCommand{id: 1}
+	agg := newRetryTestAggregate(1)
+
+	err := authorizer.Authorize(context.Background(), cmd, agg)
+	require.True(t, errors.Is(err, errors.Unauthorized))
+
+	viewerCtx, err := auth.WithPrincipal(context.Background(), auth.Principal{SubjectID: 7, Roles: []string{"viewer"}})
+	require.NoError(t, err)
+	require.True(t, errors.Is(authorizer.Authorize(viewerCtx, cmd, agg), errors.Forbidden))
+
+	operatorCtx, err := auth.WithPrincipal(context.Background(), auth.Principal{SubjectID: 7, Roles: []string{"Operator"}})
+	require.NoError(t, err)
+	require.NoError(t, authorizer.Authorize(operatorCtx, cmd, agg))
+
+	systemCtx, err := auth.WithPrincipal(context.Background(), auth.Principal{SubjectID: 1, IsSystem: true})
+	require.NoError(t, err)
+	require.NoError(t, authorizer.Authorize(systemCtx, cmd, agg))
+}
@@ -3,6 +3,7 @@ package eventsourced
 import (
 	"context"
 
+	"gochen/contextx"
 	deventsourced "gochen/domain/eventsourced"
 	"gochen/messaging"
 	cmd "gochen/messaging/command"
@@ -32,6 +33,11 @@ func (h *commandMessageHandler[T, ID]) Handle(ctx context.Context, message messa
 	if err != nil {
 		return err
 	}
+	// 命令执行期间持久化的事件以该命令为直接原因。
+	ctx, err = contextx.WithCausationID(ctx, c.GetID())
+	if err != nil {
+		return err
+	}
 	return h.service.ExecuteCommand(ctx, domainCmd)
 }
 
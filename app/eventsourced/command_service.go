@@ -10,6 +10,7 @@ import (
 	"gochen/errors"
 	"gochen/logging"
 	"gochen/policy/retry"
+	"gochen/validate"
 )
 
 // IEventSourcedCommand 事件溯源命令接口（应用层）。
@@ -32,6 +33,13 @@ type EventSourcedServiceOptions[T deventsourced.IEventSourcedAggregate[ID], ID c
 	CommandHooks  []IEventSourcedCommandHook[T, ID]
 	CommandTracer ICommandTracer
 
+	// Authorizer 在 handler 之前对命令做集中授权（可选）；拒绝时 handler 与保存都不会执行。
+	Authorizer IAuthorizer[T, ID]
+
+	// Validator 在加载聚合之前校验命令（可选，为 nil 时不校验）；传入 validate.TagValidator{}
+	// 按 `validate` struct tag 校验。校验失败返回 errors.Validation，不会加载聚合、运行钩子或重试。
+	Validator validate.IValidator
+
 	// ConcurrencyRetry 配置“保存阶段遇到并发冲突（errors.Concurrency）”时的自动重试（可选）。
 	//
 	// 语义：
@@ -73,6 +81,14 @@ type IEventSourcedCommandFinalizeHook[T deventsourced.IEventSourcedAggregate[ID]
 	AfterFinalize(ctx context.Context, cmd IEventSourcedCommand[ID], agg T, err error, attempts int) error
 }
 
+// IEventSourcedCommandStartHook 命令开始钩子接口（可选）。
+//
+// 每次 ExecuteCommand 在加载聚合之前调用一次；返回的 ctx 会贯穿本次执行的所有尝试与钩子，
+// 便于审计、计时等逻辑在 BeforeStart 与 AfterFinalize 之间传递状态。返回 nil 时沿用原 ctx。
+type IEventSourcedCommandStartHook[ID comparable] interface {
+	BeforeStart(ctx context.Context, cmd IEventSourcedCommand[ID]) context.Context
+}
+
 // ICommandTracer 提供命令执行过程的耗时与错误追踪。
 type ICommandTracer interface {
 	Trace(ctx context.Context, commandName string, elapsed time.Duration, err error)
@@ -82,7 +98,7 @@ type ICommandTracer interface {
 //
 // 该服务基于领域层的事件溯源仓储与命令处理器，封装了：
 //   - 加载聚合；
-//   - 执行命令（含前后钩子与追踪）；
+//   - 执行命令（含授权、前后钩子与追踪）；
 //   - 保存聚合（由 IEventSourcedRepository 实现具体持久化策略）。
 //
 // 类型参数：
@@ -94,6 +110,8 @@ type EventSourcedService[T deventsourced.IEventSourcedAggregate[ID], ID comparab
 	logger     logging.ILogger
 	hooks      []IEventSourcedCommandHook[T, ID]
 	tracer     ICommandTracer
+	authorizer IAuthorizer[T, ID]
+	validator  validate.IValidator
 
 	retryConfig        *RetryConfig
 	isConcurrencyError IsConcurrencyError
@@ -117,6 +135,8 @@ func NewEventSourcedService[T deventsourced.IEventSourcedAggregate[ID], ID compa
 	if opts != nil {
 		service.hooks = opts.CommandHooks
 		service.tracer = opts.CommandTracer
+		service.authorizer = opts.Authorizer
+		service.validator = opts.Validator
 		service.logger = opts.Logger
 		service.retryConfig = normalizeRetryConfig(opts.ConcurrencyRetry)
 		if opts.IsConcurrencyError != nil {
@@ -126,6 +146,9 @@ func NewEventSourcedService[T deventsourced.IEventSourcedAggregate[ID], ID compa
 	if service.logger == nil {
 		service.logger = logging.ComponentLogger("app.eventsourced.command_service")
 	}
+	if service.validator == nil {
+		service.validator = validate.Noop{}
+	}
 	return service, nil
 }
 
@@ -158,8 +181,14 @@ func (s *EventSourcedService[T, ID]) ExecuteCommand(ctx context.Context, cmd IEv
 			WithContext("command_type", cmdType.String())
 	}
 
+	if err := s.validator.Validate(cmd); err != nil {
+		return validationError(err, cmdType.String())
+	}
+
 	aggregateID := cmd.AggregateID()
 	commandName := cmdType.String()
+	ctx = withAggregateContext(ctx, aggregateID)
+	ctx = s.runBeforeStartHooks(ctx, cmd)
 	_, err := commandflow.Run(ctx, commandflow.Plan[T]{
 		Attempt: func(opCtx context.Context, attempt int) (T, error) {
 			_ = attempt
@@ -201,7 +230,7 @@ func (s *EventSourcedService[T, ID]) ExecuteCommand(ctx context.Context, cmd IEv
 	return err
 }
 
-// executeAttempt 执行一次真实尝试，包括加载聚合、运行 hook、执行 handler 和保存。
+// executeAttempt 执行一次真实尝试，包括加载聚合、授权、运行 hook、执行 handler 和保存。
 func (s *EventSourcedService[T, ID]) executeAttempt(
 	ctx context.Context,
 	cmd IEventSourcedCommand[ID],
@@ -213,6 +242,10 @@ func (s *EventSourcedService[T, ID]) executeAttempt(
 		return aggregate, s.wrapAggregateError(err, aggregateID)
 	}
 
+	if err := s.authorize(ctx, cmd, aggregate); err != nil {
+		return aggregate, err
+	}
+
 	if err := s.runBeforeExecuteHooks(ctx, cmd, aggregate); err != nil {
 		return aggregate, err
 	}
@@ -252,6 +285,20 @@ func (s *EventSourcedService[T, ID]) runAfterFinalizeHooks(
 	}
 }
 
+// runBeforeStartHooks 依次执行实现了 IEventSourcedCommandStartHook 的钩子。
+func (s *EventSourcedService[T, ID]) runBeforeStartHooks(ctx context.Context, cmd IEventSourcedCommand[ID]) context.Context {
+	for _, hook := range s.hooks {
+		startHook, ok := hook.(IEventSourcedCommandStartHook[ID])
+		if !ok {
+			continue
+		}
+		if derived := startHook.BeforeStart(ctx, cmd); derived != nil {
+			ctx = derived
+		}
+	}
+	return ctx
+}
+
 // runBeforeExecuteHooks 依次执行所有 BeforeExecute 钩子。
 func (s *EventSourcedService[T, ID]) runBeforeExecuteHooks(ctx context.Context, cmd IEventSourcedCommand[ID], aggregate T) error {
 	for _, hook := range s.hooks {
@@ -279,6 +326,20 @@ func (s *EventSourcedService[T, ID]) runAfterExecuteHooks(ctx context.Context, c
 	}
 }
 
+// validationError 为校验错误补充命令类型；非 AppError 统一包装为 errors.Validation 并保留逐字段错误。
+func validationError(err error, commandType string) error {
+	var appErr *errors.AppError
+	if errors.As(err, &appErr) && appErr != nil {
+		return appErr.WithContext("command_type", commandType)
+	}
+	wrapped := errors.Wrap(err, errors.Validation, "command validation failed").
+		WithContext("command_type", commandType)
+	if fields, ok := validate.FieldErrorsOf(err); ok {
+		wrapped = wrapped.WithContext("fields", []validate.FieldError(fields))
+	}
+	return wrapped
+}
+
 // wrapAggregateError 为加载聚合失败的错误补充聚合 ID 上下文。
 func (s *EventSourcedService[T, ID]) wrapAggregateError(err error, aggregateID ID) error {
 	var appErr *errors.AppError
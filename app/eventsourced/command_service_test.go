@@ -10,10 +10,12 @@ import (
 
 	"gochen/app/operation"
 	deventsourced "gochen/domain/eventsourced"
+	"gochen/errors"
 	"gochen/eventing/store"
 	"gochen/messaging"
 	cmd "gochen/messaging/command"
 	mtransport "gochen/messaging/transport/memory"
+	"gochen/validate"
 )
 
 // 测试用领域事件与聚合
@@ -193,3 +195,53 @@ func TestEventSourcedService_ExecuteCommandWrappedByOperationRunner(t *testing.T
 	require.Equal(t, "service-aggregate", result.Resource.Type)
 	require.Equal(t, []string{"aggregates:list"}, result.AffectedScopes)
 }
+
+type depositCommand struct {
+	ID       int64  `json:"id"`
+	Amount   int    `json:"amount" validate:"min=1"`
+	Currency string `json:"currency" validate:"required,oneof=CNY USD"`
+}
+
+func (c *depositCommand) AggregateID() int64 { return c.ID }
+
+// TestEventSourcedService_ExecuteCommand_ValidatesTaggedCommand 验证带 validate tag 的命令在加载聚合前被拒绝。
+func TestEventSourcedService_ExecuteCommand_ValidatesTaggedCommand(t *testing.T) {
+	ctx := context.Background()
+
+	eventStore := store.NewMemoryEventStore()
+	reg, upgraders := newTestRegistryAndUpgraders()
+	require.NoError(t, reg.Register("Set", func() any { return &setEvent{} }))
+	adapter, err := NewDomainEventStore(DomainEventStoreOptions[*serviceAggregate, int64]{
+		AggregateType:    "ServiceAggregate",
+		EventStore:       eventStore,
+		EventRegistry:    reg,
+		UpgraderRegistry: upgraders,
+	})
+	require.NoError(t, err)
+
+	repo, err := newTestEventSourcedRepository[*serviceAggregate, int64]("ServiceAggregate", &serviceAggregate{}, AdaptAggregateFactory(newServiceAggregate), adapter)
+	require.NoError(t, err)
+
+	service, err := NewEventSourcedService[*serviceAggregate, int64](repo, &EventSourcedServiceOptions[*serviceAggregate, int64]{
+		Validator: validate.TagValidator{},
+	})
+	require.NoError(t, err)
+
+	handled := 0
+	require.NoError(t, service.RegisterCommandHandler(&depositCommand{}, func(ctx context.Context, cmd IEventSourcedCommand[int64], agg *serviceAggregate) error {
+		handled++
+		return agg.ApplyAndRecord(&setEvent{V: cmd.(*depositCommand).Amount})
+	}))
+
+	err = service.ExecuteCommand(ctx, &depositCommand{ID: 1, Amount: 0, Currency: "EUR"})
+	require.True(t, errors.Is(err, errors.Validation))
+	fields, ok := validate.FieldErrorsOf(err)
+	require.True(t, ok)
+	require.Len(t, fields, 2)
+	require.Equal(t, "amount", fields[0].Field)
+	require.Equal(t, "currency", fields[1].Field)
+	require.Equal(t, 0, handled)
+
+	require.NoError(t, service.ExecuteCommand(ctx, &depositCommand{ID: 1, Amount: 5, Currency: "CNY"}))
+	require.Equal(t, 1, handled)
+}
@@ -3,7 +3,9 @@ package eventsourced
 import (
 	"context"
 	"fmt"
+	"gochen/clock"
 	"gochen/contextx"
+	"gochen/db"
 	"gochen/domain"
 	deventsourced "gochen/domain/eventsourced"
 	"gochen/errors"
@@ -35,6 +37,13 @@ type DomainEventStoreOptions[T deventsourced.IEventSourcedAggregate[ID], ID comp
 	UpgraderRegistry *upcast.UpgraderRegistry
 
 	Logger logging.ILogger
+
+	// Clock 可选：写入事件时间戳的时间来源；为 nil 时使用真实时钟。
+	Clock clock.IClock
+
+	// StreamLinks 可选（实验性）：启用事件流切换（SplitStream），记录聚合当前所在的事件流。
+	// 启用后 StreamMigrated/StreamStarted 事件类型会自动登记到 EventRegistry；不能与 OutboxRepo 同时配置。
+	StreamLinks IStreamLinkStore[ID]
 }
 
 // DomainEventStore 定义Domain事件存储。
@@ -46,9 +55,11 @@ type DomainEventStore[T deventsourced.IEventSourcedAggregate[ID], ID comparable]
 	outboxRepo      outbox.IOutboxRepository[ID]
 	publishEvents   bool
 	logger          logging.ILogger
+	clock           clock.IClock
 
 	eventRegistry *registry.Registry
 	upgraders     *upcast.UpgraderRegistry
+	streamLinks   IStreamLinkStore[ID]
 }
 
 const restoreAggregateBatchLimit = 1000
@@ -72,19 +83,34 @@ func NewDomainEventStore[T deventsourced.IEventSourcedAggregate[ID], ID comparab
 		outboxRepo:      opts.OutboxRepo,
 		publishEvents:   opts.PublishEvents,
 		logger:          opts.Logger,
+		clock:           opts.Clock,
 		eventRegistry:   opts.EventRegistry,
 		upgraders:       opts.UpgraderRegistry,
+		streamLinks:     opts.StreamLinks,
 	}
 	if adapter.logger == nil {
 		adapter.logger = logging.ComponentLogger("app.eventsourced.domain_event_store").
 			WithField("aggregate_type", opts.AggregateType)
 	}
+	if adapter.clock == nil {
+		adapter.clock = clock.NewRealClock()
+	}
 	if adapter.eventRegistry == nil {
 		return nil, errors.NewCode(errors.InvalidInput, "event registry cannot be nil")
 	}
 	if adapter.upgraders == nil {
 		return nil, errors.NewCode(errors.InvalidInput, "event upgrader registry cannot be nil")
 	}
+	if adapter.streamLinks != nil {
+		// Outbox 仓储以同一批事件写入事件流与 Outbox，无法区分物理流（存储）与逻辑聚合（发布）两种视图。
+		if adapter.outboxRepo != nil {
+			return nil, errors.NewCode(errors.InvalidInput, "stream links cannot be combined with OutboxRepo; use bus.WithTxPublishing").
+				WithContext("aggregate_type", opts.AggregateType)
+		}
+		if err := registerStreamEvents[ID](adapter.eventRegistry); err != nil {
+			return nil, err
+		}
+	}
 
 	// 配置提示：启用 PublishEvents 但未配置 OutboxRepo 时，会退化为“直接发布”模式，
 	// 该模式不具备“事件持久化 + 发布”的原子性；生产环境建议启用 Outbox。
@@ -110,12 +136,39 @@ func NewDomainEventStore[T deventsourced.IEventSourcedAggregate[ID], ID comparab
 // - - 下游系统不会感知到这批事件；
 // - - 需要额外补偿机制或通过重放/扫描来补发事件。
 // - 在生产环境强烈建议使用 Outbox 模式，以保证“最终一致”的语义。
+// - ctx 经 bus.WithTxPublishing 绑定了调用方事务时，优先于上述两种模式：
+// - - 事件通过 store.ITxEventAppender 在该事务内追加，并经绑定的暂存器写入 Outbox；
+// - - 事务提交前不会发布任何事件，回滚时事件与 Outbox 记录一并丢弃；
+// - - 事件存储不支持事务内追加时返回 errors.Unsupported。
+// - 启用流切换（StreamLinks）时，expectedVersion 为聚合逻辑版本；事件以流内物理版本写入聚合当前所在的流。
+// - 事件本身保持聚合 ID 与逻辑版本（发布即此视图），物理流与流内版本记录在 Metadata（见 MetadataStreamIDKey）。
+// - 无论是否发布，每个事件的 Metadata 都会记录 ctx 中的 correlation_id 与 causation_id（见 stampCausality）。
 func (a *DomainEventStore[T, ID]) AppendEvents(ctx context.Context, aggregateID ID, events []domain.IDomainEvent, expectedVersion uint64) error {
 	if len(events) == 0 {
 		return nil
 	}
 
+	ref, err := a.resolveStream(ctx, aggregateID)
+	if err != nil {
+		return err
+	}
+	if ref.base > 0 && expectedVersion <= ref.base {
+		return errors.NewCode(errors.Concurrency, "expected version precedes the current stream").
+			WithContext("aggregate_type", a.aggregateType).
+			WithContext("aggregate_id", aggregateID).
+			WithContext("expected_version", expectedVersion).
+			WithContext("stream_base_version", ref.base)
+	}
+	streamID := ref.id
+	expectedVersion -= ref.base
+
+	ctx, err = contextx.EnsureCorrelationID(ctx, nil, contextx.CausationID(ctx))
+	if err != nil {
+		return err
+	}
+
 	currentVersion := expectedVersion
+	now := a.clock.Now()
 	storableEvents := make([]eventing.Event[ID], 0, len(events))
 
 	// 仅在需要直接通过 EventBus 发布且未启用 Outbox 时才构建发布用切片，
@@ -145,22 +198,36 @@ func (a *DomainEventStore[T, ID]) AppendEvents(ctx context.Context, aggregateID
 		}
 		schemaVersion := a.eventRegistry.EventSchemaVersion(eventType)
 		version := currentVersion + uint64(i) + 1
-		evt := eventing.NewEvent(aggregateID, a.aggregateType, eventType, version, de, schemaVersion)
+		// 事件始终携带聚合逻辑 ID 与逻辑版本；切换过的流另在 Metadata 记录物理流与流内版本。
+		// 载荷经 registry 封装：注册了 codec 的类型（如 protobuf）按同一编码写入存储与 Outbox。
+		evt := eventing.NewEvent(aggregateID, a.aggregateType, eventType, ref.logical(version), a.eventRegistry.NewPayload(eventType, de), schemaVersion)
+		evt.Timestamp = now
+		if err := stampCausality(ctx, evt.GetMetadata()); err != nil {
+			return err
+		}
+		if ref.split(aggregateID) {
+			stampStreamLocation(evt.GetMetadata(), streamID, version)
+		}
 		storableEvents = append(storableEvents, *evt)
 		if needDirectPublish {
 			publishedEvents = append(publishedEvents, evt)
 		}
 	}
 
-	// Outbox 模式：通过 OutboxRepo 原子保存事件与 Outbox。
+	// 事务发布模式：事件随调用方事务持久化与暂存，不在提交前直接发布。
+	if tx, stager, ok := bus.TxPublishingFromContext(ctx); ok {
+		return a.appendInTx(ctx, tx, stager, ref, storableEvents, expectedVersion)
+	}
+
+	// Outbox 模式：通过 OutboxRepo 原子保存事件与 Outbox（构造时已拒绝与流切换同时启用）。
 	if a.outboxRepo != nil {
-		if err := a.outboxRepo.SaveWithEvents(ctx, aggregateID, storableEvents); err != nil {
+		if err := a.outboxRepo.SaveWithEvents(ctx, streamID, storableEvents); err != nil {
 			return err
 		}
 	} else {
 		// 直接写入 EventStore。
-		storable := store.ToStorable(storableEvents)
-		if err := a.eventStore.AppendEvents(ctx, aggregateID, storable, expectedVersion); err != nil {
+		storable := store.ToStorable(ref.physical(storableEvents))
+		if err := a.eventStore.AppendEvents(ctx, streamID, storable, expectedVersion); err != nil {
 			return err
 		}
 	}
@@ -176,6 +243,46 @@ func (a *DomainEventStore[T, ID]) AppendEvents(ctx context.Context, aggregateID
 	return nil
 }
 
+// appendInTx 在调用方事务内追加事件；启用发布（PublishEvents 或 OutboxRepo）时同时暂存到 Outbox。
+func (a *DomainEventStore[T, ID]) appendInTx(
+	ctx context.Context,
+	tx db.ITransaction,
+	stager bus.ITxEventStager,
+	ref streamRef[ID],
+	events []eventing.Event[ID],
+	expectedVersion uint64,
+) error {
+	appender, ok := a.eventStore.(store.ITxEventAppender[ID])
+	if !ok {
+		return errors.NewCode(errors.Unsupported, "event store cannot append events within a transaction").
+			WithContext("aggregate_type", a.aggregateType).
+			WithContext("event_store", fmt.Sprintf("%T", a.eventStore))
+	}
+	if err := appender.AppendEventsWithDB(ctx, tx, ref.id, store.ToStorable(ref.physical(events)), expectedVersion); err != nil {
+		return err
+	}
+	if !a.publishEvents && a.outboxRepo == nil {
+		return nil
+	}
+
+	staged := make([]eventing.IEvent, len(events))
+	for i := range events {
+		staged[i] = &events[i]
+	}
+	return bus.PublishInTx(ctx, tx, stager, staged...)
+}
+
+// stampCausality 把 ctx 中的 correlation_id/causation_id 写入事件 Metadata（已设置的字段保持不变）。
+//
+// 命令执行时 ctx 的 causationID 是命令 ID；未携带上游 correlation_id 时 AppendEvents 以 causationID 兜底，
+// 即该命令为流程起点。这样即使未启用事件发布，持久化的事件也能按流程与命令追溯。
+func stampCausality(ctx context.Context, md contextx.IMetadata) error {
+	if err := contextx.InjectCorrelationID(ctx, md); err != nil {
+		return err
+	}
+	return contextx.InjectCausationID(ctx, md)
+}
+
 func validateAggregateSample(sample any, aggregateType string) error {
 	if sample == nil {
 		return errors.NewCode(errors.InvalidInput, "aggregate sample cannot be nil").
@@ -200,6 +307,18 @@ func validateAggregateSample(sample any, aggregateType string) error {
 // 参数：
 // - aggregate：聚合实例。
 func (a *DomainEventStore[T, ID]) RestoreAggregate(ctx context.Context, aggregate deventsourced.IEventSourcedAggregate[ID]) (*deventsourced.RestoreResult, error) {
+	return a.restore(ctx, aggregate, nil)
+}
+
+// RestoreAggregateAt 把聚合恢复到历史时点（不使用快照，从版本 1 开始重放）。
+//
+// 事件按版本顺序重放，遇到第一个超出 point 的事件即停止。
+func (a *DomainEventStore[T, ID]) RestoreAggregateAt(ctx context.Context, aggregate deventsourced.IEventSourcedAggregate[ID], point deventsourced.RestorePoint) (*deventsourced.RestoreResult, error) {
+	return a.restore(ctx, aggregate, &point)
+}
+
+// restore 恢复聚合；point 为 nil 时恢复到最新状态并允许使用快照。
+func (a *DomainEventStore[T, ID]) restore(ctx context.Context, aggregate deventsourced.IEventSourcedAggregate[ID], point *deventsourced.RestorePoint) (*deventsourced.RestoreResult, error) {
 	if aggregate == nil {
 		return nil, errors.NewCode(errors.InvalidInput, "aggregate cannot be nil")
 	}
@@ -210,7 +329,8 @@ func (a *DomainEventStore[T, ID]) RestoreAggregate(ctx context.Context, aggregat
 	// 若配置了 SnapshotManager，优先尝试通过快照 + 增量事件恢复：
 	// 1) 通过 LoadSnapshot 将聚合状态恢复到快照版本；
 	// 2) 记录快照版本，从该版本之后重放增量领域事件。
-	if a.snapshotManager != nil {
+	// 历史时点恢复不使用快照：最新快照可能晚于目标时点。
+	if a.snapshotManager != nil && point == nil {
 		snap, err := a.snapshotManager.LoadSnapshot(ctx, aggregate.GetID(), aggregate)
 		if err == nil && snap != nil {
 			fromVersion = snap.Version
@@ -254,76 +374,143 @@ func (a *DomainEventStore[T, ID]) RestoreAggregate(ctx context.Context, aggregat
 		return nil
 	}
 
-	// 使用流式接口分批读取，避免一次性加载大聚合事件流导致内存峰值/GC 压力。
-	var lastVersion = fromVersion
-	afterVersion := fromVersion
-	for {
-		batch, err := a.eventStore.StreamAggregate(ctx, &store.AggregateStreamOptions[ID]{
-			AggregateType: a.aggregateType,
-			AggregateID:   aggregate.GetID(),
-			AfterVersion:  afterVersion,
-			Limit:         restoreAggregateBatchLimit,
-		})
+	// 定位起始事件流：最新状态从链接指向的当前流开始（流首的 StreamStarted 检查点提供切换前的状态）；
+	// 历史时点恢复从原始流开始，沿 StreamMigrated 标记依次追溯。
+	ref := streamRef[ID]{id: aggregate.GetID()}
+	if point == nil {
+		var err error
+		if ref, err = a.resolveStream(ctx, aggregate.GetID()); err != nil {
+			return nil, err
+		}
+	}
+	var after uint64
+	if fromVersion > ref.base {
+		after = fromVersion - ref.base
+	}
+
+	lastVersion := fromVersion
+	for first := true; ; first = false {
+		marker, err := a.replayStream(ctx, aggregate, ref, after, point, applyOne, result, &lastVersion)
 		if err != nil {
-			if errors.Is(err, errors.NotFound) {
+			if first && errors.Is(err, errors.NotFound) {
 				return result, nil
 			}
 			return nil, err
 		}
-		if batch == nil || len(batch.Events) == 0 {
+		if marker == nil {
 			break
 		}
-		for i := range batch.Events {
-			evt := &batch.Events[i]
-			if err := applyOne(evt); err != nil {
+		// 旧流已关闭：继续重放新流（跳过其首个 StreamStarted 检查点，状态已由旧流事件重放得到）。
+		ref = a.followStream(ctx, aggregate.GetID(), ref, marker)
+		after = 1
+	}
+
+	aggregate.MarkEventsAsCommitted()
+	result.Version = lastVersion
+	result.Exists = result.Version > 0
+	return result, nil
+}
+
+// replayStream 重放单个物理事件流；遇到 StreamMigrated 标记时返回该标记，由调用方切换到新流。
+//
+// 使用迭代器按页拉取并逐条重放，避免一次性加载大聚合事件流导致内存峰值/GC 压力。
+// 迭代器基于 StreamAggregate 分页，以保持按 aggregateType 限定事件流的语义。
+func (a *DomainEventStore[T, ID]) replayStream(
+	ctx context.Context,
+	aggregate deventsourced.IEventSourcedAggregate[ID],
+	ref streamRef[ID],
+	after uint64,
+	point *deventsourced.RestorePoint,
+	applyOne func(evt *eventing.Event[ID]) error,
+	result *deventsourced.RestoreResult,
+	lastVersion *uint64,
+) (*StreamMigrated[ID], error) {
+	it := store.NewAggregateStreamIterator(ctx, a.eventStore.StreamAggregate, store.AggregateStreamOptions[ID]{
+		AggregateType: a.aggregateType,
+		AggregateID:   ref.id,
+		AfterVersion:  after,
+		Limit:         restoreAggregateBatchLimit,
+	})
+	defer func() { _ = it.Close() }()
+
+	for it.Next() {
+		evt := it.Event()
+		switch evt.GetType() {
+		case StreamMigratedEventType:
+			payload, err := asDomainEvent(ctx, a.eventRegistry, a.upgraders, evt)
+			if err != nil {
+				return nil, err
+			}
+			marker, ok := payload.(*StreamMigrated[ID])
+			if !ok {
+				return nil, errors.NewCode(errors.Internal, "unexpected stream migrated payload").
+					WithContext("payload_type", fmtType(payload))
+			}
+			return marker, nil
+		case StreamStartedEventType:
+			payload, err := asDomainEvent(ctx, a.eventRegistry, a.upgraders, evt)
+			if err != nil {
+				return nil, err
+			}
+			started, ok := payload.(*StreamStarted[ID])
+			if !ok {
+				return nil, errors.NewCode(errors.Internal, "unexpected stream started payload").
+					WithContext("payload_type", fmtType(payload))
+			}
+			if err := restoreStreamCheckpoint(aggregate, started); err != nil {
 				return nil, err
 			}
-			result.EventCount++
-			lastVersion = evt.Version
+			*lastVersion = started.Version
+			continue
 		}
-		if !batch.HasMore {
-			break
+		version := ref.logical(evt.Version)
+		if point != nil && beyondRestorePoint(evt, version, point) {
+			return nil, nil
 		}
-		// 使用 NextVersion 推进游标；若实现未提供 NextVersion，则退化为最后一条事件版本号。
-		if batch.NextVersion > afterVersion {
-			afterVersion = batch.NextVersion
-		} else {
-			afterVersion = lastVersion
+		if err := applyOne(evt); err != nil {
+			return nil, err
 		}
+		result.EventCount++
+		*lastVersion = version
 	}
+	return nil, it.Err()
+}
 
-	aggregate.MarkEventsAsCommitted()
-	result.Version = lastVersion
-	result.Exists = result.Version > 0
-	return result, nil
+// beyondRestorePoint 判断事件是否超出历史恢复时点。
+func beyondRestorePoint[ID comparable](evt *eventing.Event[ID], version uint64, point *deventsourced.RestorePoint) bool {
+	if point.Version > 0 && version > point.Version {
+		return true
+	}
+	return !point.AsOf.IsZero() && evt.Timestamp.After(point.AsOf)
 }
 
 // Exists 检查聚合是否存在。
 func (a *DomainEventStore[T, ID]) Exists(ctx context.Context, aggregateID ID) (bool, error) {
-	events, err := a.eventStore.LoadEventsByType(ctx, a.aggregateType, aggregateID, 0)
+	version, err := a.GetAggregateVersion(ctx, aggregateID)
 	if err != nil {
-		if errors.Is(err, errors.NotFound) {
-			return false, nil
-		}
 		return false, err
 	}
-	return len(events) > 0, nil
+	return version > 0, nil
 }
 
 // GetAggregateVersion 从存储中查询对象。
 //
 // 说明：
-// - GetAggregateVersion 获取聚合当前版本。
+// - GetAggregateVersion 获取聚合当前版本（经 HeadVersion 查询，不加载事件体）。
+// - 启用流切换时返回逻辑版本（当前流的基准版本 + 流内版本）。
 func (a *DomainEventStore[T, ID]) GetAggregateVersion(ctx context.Context, aggregateID ID) (uint64, error) {
-	events, err := a.eventStore.LoadEventsByType(ctx, a.aggregateType, aggregateID, 0)
+	ref, err := a.resolveStream(ctx, aggregateID)
+	if err != nil {
+		return 0, err
+	}
+	version, err := a.eventStore.HeadVersion(ctx, a.aggregateType, ref.id)
 	if err != nil {
 		if errors.Is(err, errors.NotFound) {
 			return 0, nil
 		}
 		return 0, err
 	}
-	if len(events) == 0 {
-		return 0, nil
-	}
-	return events[len(events)-1].Version, nil
+	return ref.logical(version), nil
 }
+
+var _ deventsourced.IPointInTimeRestorer[int64] = (*DomainEventStore[deventsourced.IEventSourcedAggregate[int64], int64])(nil)
@@ -7,12 +7,17 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"gochen/clock"
+	"gochen/contextx"
+	"gochen/db"
 	"gochen/domain"
 	deventsourced "gochen/domain/eventsourced"
+	"gochen/errors"
 	"gochen/eventing"
 	"gochen/eventing/bus"
 	"gochen/eventing/store"
 	"gochen/messaging"
+	synctransport "gochen/messaging/transport/direct"
 	memorytransport "gochen/messaging/transport/memory"
 )
 
@@ -135,6 +140,80 @@ func TestDomainEventStore_AppendEvents_SetsSchemaVersionFromRegistry(t *testing.
 	require.Equal(t, 3, loaded[0].EventSchemaVersion())
 }
 
+// TestDomainEventStore_AppendEvents_TimestampsFromClock 验证事件时间戳取自注入的时钟。
+func TestDomainEventStore_AppendEvents_TimestampsFromClock(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	eventStore := store.NewMemoryEventStore()
+	reg, upgraders := newTestRegistryAndUpgraders()
+	require.NoError(t, reg.Register("ValueSet", func() any { return &valueSetEvent{} }))
+	clk := clock.NewManualClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	storeAdapter, err := NewDomainEventStore(DomainEventStoreOptions[*testAggregate, int64]{
+		AggregateType:    "TestAggregate",
+		EventStore:       eventStore,
+		EventRegistry:    reg,
+		UpgraderRegistry: upgraders,
+		Clock:            clk,
+	})
+	require.NoError(t, err)
+
+	agg := newTestAggregate(1)
+	require.NoError(t, agg.ApplyAndRecord(&valueSetEvent{V: 42}))
+	require.NoError(t, storeAdapter.AppendEvents(ctx, agg.GetID(), agg.GetUncommittedEvents(), 0))
+
+	loaded, err := eventStore.LoadEvents(ctx, agg.GetID(), 0)
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	require.Equal(t, clk.Now(), loaded[0].GetTimestamp())
+}
+
+// TestDomainEventStore_AppendEvents_StampsCorrelationAndCausation 验证未启用发布时事件仍记录关联与因果 ID。
+func TestDomainEventStore_AppendEvents_StampsCorrelationAndCausation(t *testing.T) {
+	t.Parallel()
+
+	eventStore := store.NewMemoryEventStore()
+	reg, upgraders := newTestRegistryAndUpgraders()
+	require.NoError(t, reg.Register("ValueSet", func() any { return &valueSetEvent{} }))
+
+	storeAdapter, err := NewDomainEventStore(DomainEventStoreOptions[*testAggregate, int64]{
+		AggregateType:    "TestAggregate",
+		EventStore:       eventStore,
+		EventRegistry:    reg,
+		UpgraderRegistry: upgraders,
+	})
+	require.NoError(t, err)
+
+	// 根命令：只有 causation，correlation 以命令 ID 兜底。
+	rootCtx, err := contextx.WithCausationID(context.Background(), "cmd-1")
+	require.NoError(t, err)
+	agg := newTestAggregate(1)
+	require.NoError(t, agg.ApplyAndRecord(&valueSetEvent{V: 1}))
+	require.NoError(t, storeAdapter.AppendEvents(rootCtx, agg.GetID(), agg.GetUncommittedEvents(), 0))
+
+	// 下游命令：沿用流程 correlation。
+	childCtx, err := contextx.WithCorrelationID(context.Background(), "cmd-1")
+	require.NoError(t, err)
+	childCtx, err = contextx.WithCausationID(childCtx, "cmd-2")
+	require.NoError(t, err)
+	other := newTestAggregate(2)
+	require.NoError(t, other.ApplyAndRecord(&valueSetEvent{V: 2}))
+	require.NoError(t, storeAdapter.AppendEvents(childCtx, other.GetID(), other.GetUncommittedEvents(), 0))
+
+	ctx := context.Background()
+	flow, err := eventStore.StreamEvents(ctx, &store.StreamOptions{CorrelationID: "cmd-1"})
+	require.NoError(t, err)
+	require.Len(t, flow.Events, 2)
+
+	caused, err := eventStore.StreamEvents(ctx, &store.StreamOptions{CausationID: "cmd-2"})
+	require.NoError(t, err)
+	require.Len(t, caused.Events, 1)
+	require.Equal(t, int64(2), caused.Events[0].AggregateID)
+	correlationID, _ := caused.Events[0].GetMetadata().Get(contextx.MetadataCorrelationKey)
+	require.Equal(t, "cmd-1", correlationID)
+}
+
 // 用于验证 RestoreAggregate 在回放阶段对未命中 handler 直接 fail-fast。
 type autoAgg struct {
 	*deventsourced.EventSourcedAggregate[int64]
@@ -228,3 +307,85 @@ func TestDomainEventStore_ExistsAndVersion_AreScopedByAggregateType(t *testing.T
 	require.NoError(t, err)
 	require.Equal(t, uint64(1), version)
 }
+
+// txAppendingEventStore 记录事务内追加的事件，模拟支持 AppendEventsWithDB 的 SQL 事件存储。
+type txAppendingEventStore struct {
+	*store.MemoryEventStore
+	txEvents []eventing.IStorableEvent[int64]
+	txDB     db.IDatabase
+}
+
+func (s *txAppendingEventStore) AppendEventsWithDB(_ context.Context, database db.IDatabase, _ int64, events []eventing.IStorableEvent[int64], _ uint64) error {
+	s.txDB = database
+	s.txEvents = append(s.txEvents, events...)
+	return nil
+}
+
+type recordingStager struct {
+	tx     db.ITransaction
+	events []eventing.IEvent
+}
+
+func (s *recordingStager) StageEvents(_ context.Context, tx db.ITransaction, events []eventing.IEvent) error {
+	s.tx = tx
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func TestDomainEventStore_AppendEvents_TxPublishingDefersPublish(t *testing.T) {
+	t.Parallel()
+
+	reg, upgraders := newTestRegistryAndUpgraders()
+	require.NoError(t, reg.Register("ValueSet", func() any { return &valueSetEvent{} }))
+
+	transport := synctransport.NewSyncTransport()
+	require.NoError(t, transport.Start(context.Background()))
+	t.Cleanup(func() { _ = transport.Stop(context.Background()) })
+	eventBus := bus.NewEventBus(messaging.NewMessageBus(transport))
+	published := 0
+	_, err := eventBus.SubscribeEvent(context.Background(), "*", bus.EventHandlerFunc(func(ctx context.Context, evt eventing.IEvent) error {
+		published++
+		return nil
+	}))
+	require.NoError(t, err)
+
+	eventStore := &txAppendingEventStore{MemoryEventStore: store.NewMemoryEventStore()}
+	storeAdapter, err := NewDomainEventStore(DomainEventStoreOptions[*testAggregate, int64]{
+		AggregateType:    "TestAggregate",
+		EventStore:       eventStore,
+		EventBus:         eventBus,
+		PublishEvents:    true,
+		EventRegistry:    reg,
+		UpgraderRegistry: upgraders,
+	})
+	require.NoError(t, err)
+
+	tx := struct{ db.ITransaction }{}
+	stager := &recordingStager{}
+	ctx, err := bus.WithTxPublishing(context.Background(), tx, stager)
+	require.NoError(t, err)
+
+	agg := newTestAggregate(1)
+	require.NoError(t, agg.ApplyAndRecord(&valueSetEvent{V: 42}))
+	require.NoError(t, storeAdapter.AppendEvents(ctx, agg.GetID(), agg.GetUncommittedEvents(), 0))
+
+	require.Zero(t, published, "events must not be published before the transaction commits")
+	require.Equal(t, tx, eventStore.txDB)
+	require.Len(t, eventStore.txEvents, 1)
+	require.Equal(t, tx, stager.tx)
+	require.Len(t, stager.events, 1)
+	require.Equal(t, eventStore.txEvents[0].GetID(), stager.events[0].GetID())
+
+	// 不支持事务内追加的事件存储直接拒绝，避免事件与事务脱节。
+	plainAdapter, err := NewDomainEventStore(DomainEventStoreOptions[*testAggregate, int64]{
+		AggregateType:    "TestAggregate",
+		EventStore:       store.NewMemoryEventStore(),
+		EventBus:         eventBus,
+		PublishEvents:    true,
+		EventRegistry:    reg,
+		UpgraderRegistry: upgraders,
+	})
+	require.NoError(t, err)
+	err = plainAdapter.AppendEvents(ctx, agg.GetID(), agg.GetUncommittedEvents(), 0)
+	require.True(t, errors.Is(err, errors.Unsupported), "expected Unsupported, got: %v", err)
+}
@@ -0,0 +1,325 @@
+package eventsourced
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	deventsourced "gochen/domain/eventsourced"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/store"
+	"gochen/messaging"
+)
+
+const (
+	// DefaultHistoryPageSize 是聚合时间线默认每页事件数。
+	DefaultHistoryPageSize = 50
+	// MaxHistoryPageSize 是聚合时间线单页事件数上限。
+	MaxHistoryPageSize = 500
+	// MaxHistoryDiffPageSize 是附带状态差异时的单页事件数上限：每条事件都需要一次从版本 1 开始的历史重建。
+	MaxHistoryDiffPageSize = 20
+)
+
+// AggregateTimelineEntry 是聚合时间线中的单个事件。
+type AggregateTimelineEntry struct {
+	EventID       string            `json:"event_id"`
+	EventType     string            `json:"event_type"`
+	Version       uint64            `json:"version"`
+	SchemaVersion int               `json:"schema_version"`
+	Timestamp     time.Time         `json:"timestamp"`
+	Summary       any               `json:"summary,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// StateChange 是两个版本之间单个字段的变化；Path 以 "." 分隔嵌套字段。
+//
+// Before/After 缺失分别表示字段新增/删除。
+type StateChange struct {
+	Path   string `json:"path"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+// StateDiff 是聚合在两个版本之间的状态差异（基于聚合 JSON 表示逐字段比较）。
+type StateDiff struct {
+	FromVersion uint64        `json:"from_version"`
+	ToVersion   uint64        `json:"to_version"`
+	Changes     []StateChange `json:"changes"`
+}
+
+// AggregateHistory 是聚合时间线的一页。
+type AggregateHistory struct {
+	AggregateType string                   `json:"aggregate_type"`
+	AggregateID   int64                    `json:"aggregate_id"`
+	HeadVersion   uint64                   `json:"head_version"`
+	Entries       []AggregateTimelineEntry `json:"entries"`
+	// Diffs 仅在 HistoryQuery.IncludeDiffs 时返回，与 Entries 一一对应（版本 v-1 → v）。
+	Diffs       []StateDiff `json:"diffs,omitempty"`
+	HasMore     bool        `json:"has_more"`
+	NextVersion uint64      `json:"next_version,omitempty"`
+}
+
+// HistoryQuery 定义时间线查询条件。
+type HistoryQuery struct {
+	AfterVersion uint64 // 从该版本之后开始
+	Limit        int    // 每页事件数（默认 DefaultHistoryPageSize，上限 MaxHistoryPageSize）
+	IncludeDiffs bool   // 是否计算相邻版本的状态差异（需要注册 AggregateStateLoader；单页上限 MaxHistoryDiffPageSize）
+}
+
+// AggregateStateLoader 重建聚合在指定版本的状态；version=0 时应返回 (nil, nil)。
+//
+// 返回值会被 JSON 序列化后比较，因此只有导出字段参与差异计算。
+type AggregateStateLoader func(ctx context.Context, id int64, version uint64) (any, error)
+
+// PayloadSummarizer 把事件载荷映射为时间线中展示的摘要；返回 nil 表示不展示载荷。
+type PayloadSummarizer func(evt *eventing.Event[int64]) any
+
+// AggregateHistoryOptions 定义聚合历史服务选项。
+type AggregateHistoryOptions struct {
+	// Summarizer 为空时直接展示原始载荷。
+	Summarizer PayloadSummarizer
+}
+
+// AggregateHistoryService 提供聚合事件时间线与版本间状态差异，面向支持/排障工具。
+//
+// 与 LoadEventHistoryPage 的区别：时间线保留事件元数据与 schema 版本，
+// 并可通过已注册的 AggregateStateLoader 计算状态差异。
+type AggregateHistoryService struct {
+	eventStore IEventHistoryStore
+	summarizer PayloadSummarizer
+
+	mu      sync.RWMutex
+	loaders map[string]AggregateStateLoader
+}
+
+// NewAggregateHistoryService 创建聚合历史服务。
+func NewAggregateHistoryService(eventStore IEventHistoryStore, opts *AggregateHistoryOptions) (*AggregateHistoryService, error) {
+	if eventStore == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "event store cannot be nil")
+	}
+	svc := &AggregateHistoryService{
+		eventStore: eventStore,
+		summarizer: defaultPayloadSummarizer,
+		loaders:    make(map[string]AggregateStateLoader),
+	}
+	if opts != nil && opts.Summarizer != nil {
+		svc.summarizer = opts.Summarizer
+	}
+	return svc, nil
+}
+
+// RegisterStateLoader 为聚合类型注册状态重建函数（用于 Diff 与 IncludeDiffs）。
+func (s *AggregateHistoryService) RegisterStateLoader(aggregateType string, loader AggregateStateLoader) error {
+	if aggregateType == "" {
+		return errors.NewCode(errors.InvalidInput, "aggregate type cannot be empty")
+	}
+	if loader == nil {
+		return errors.NewCode(errors.InvalidInput, "state loader cannot be nil").
+			WithContext("aggregate_type", aggregateType)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loaders[aggregateType] = loader
+	return nil
+}
+
+// TemporalStateLoader 基于支持历史时点读取的仓储构造 AggregateStateLoader。
+func TemporalStateLoader[T deventsourced.IEventSourcedAggregate[int64]](repo deventsourced.ITemporalRepository[T, int64]) AggregateStateLoader {
+	return func(ctx context.Context, id int64, version uint64) (any, error) {
+		if version == 0 {
+			return nil, nil
+		}
+		return repo.GetByIDAtVersion(ctx, id, version)
+	}
+}
+
+// Timeline 返回聚合事件时间线的一页；聚合不存在时返回 errors.NotFound。
+func (s *AggregateHistoryService) Timeline(ctx context.Context, aggregateType string, id int64, query HistoryQuery) (*AggregateHistory, error) {
+	if aggregateType == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "aggregate type cannot be empty")
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultHistoryPageSize
+	}
+	limit = min(limit, MaxHistoryPageSize)
+
+	var loader AggregateStateLoader
+	if query.IncludeDiffs {
+		var err error
+		if loader, err = s.stateLoader(aggregateType); err != nil {
+			return nil, err
+		}
+		limit = min(limit, MaxHistoryDiffPageSize)
+	}
+
+	head, err := s.eventStore.HeadVersion(ctx, aggregateType, id)
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return nil, err
+	}
+	if head == 0 {
+		return nil, errors.NewCode(errors.NotFound, "aggregate not found").
+			WithContext("aggregate_type", aggregateType).
+			WithContext("aggregate_id", id)
+	}
+
+	res, err := s.eventStore.StreamAggregate(ctx, &store.AggregateStreamOptions[int64]{
+		AggregateType: aggregateType,
+		AggregateID:   id,
+		AfterVersion:  query.AfterVersion,
+		Limit:         limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	history := &AggregateHistory{
+		AggregateType: aggregateType,
+		AggregateID:   id,
+		HeadVersion:   head,
+		Entries:       make([]AggregateTimelineEntry, 0, len(res.Events)),
+		HasMore:       res.HasMore,
+	}
+	if res.HasMore {
+		history.NextVersion = res.NextVersion
+	}
+	for i := range res.Events {
+		evt := &res.Events[i]
+		history.Entries = append(history.Entries, AggregateTimelineEntry{
+			EventID:       evt.ID,
+			EventType:     evt.Type,
+			Version:       evt.Version,
+			SchemaVersion: evt.SchemaVersion,
+			Timestamp:     evt.Timestamp,
+			Summary:       s.summarizer(evt),
+			Metadata:      evt.Metadata.MapCopy(),
+		})
+	}
+	if loader == nil || len(history.Entries) == 0 {
+		return history, nil
+	}
+
+	// 相邻版本复用上一次重建的状态，每页共需 len(Entries)+1 次重建。
+	history.Diffs = make([]StateDiff, 0, len(history.Entries))
+	prevVersion := history.Entries[0].Version - 1
+	prev, err := loadStateDocument(ctx, loader, id, prevVersion)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range history.Entries {
+		next, err := loadStateDocument(ctx, loader, id, entry.Version)
+		if err != nil {
+			return nil, err
+		}
+		history.Diffs = append(history.Diffs, StateDiff{
+			FromVersion: prevVersion,
+			ToVersion:   entry.Version,
+			Changes:     diffStateDocuments(prev, next),
+		})
+		prev, prevVersion = next, entry.Version
+	}
+	return history, nil
+}
+
+// Diff 计算聚合在 fromVersion 与 toVersion 之间的状态差异；fromVersion=0 表示与初始空状态比较。
+func (s *AggregateHistoryService) Diff(ctx context.Context, aggregateType string, id int64, fromVersion, toVersion uint64) (*StateDiff, error) {
+	if toVersion == 0 || fromVersion > toVersion {
+		return nil, errors.NewCode(errors.InvalidInput, "invalid diff version range").
+			WithContext("from_version", fromVersion).
+			WithContext("to_version", toVersion)
+	}
+	loader, err := s.stateLoader(aggregateType)
+	if err != nil {
+		return nil, err
+	}
+	before, err := loadStateDocument(ctx, loader, id, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	after, err := loadStateDocument(ctx, loader, id, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &StateDiff{FromVersion: fromVersion, ToVersion: toVersion, Changes: diffStateDocuments(before, after)}, nil
+}
+
+func (s *AggregateHistoryService) stateLoader(aggregateType string) (AggregateStateLoader, error) {
+	s.mu.RLock()
+	loader, ok := s.loaders[aggregateType]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, errors.NewCode(errors.Unsupported, "state diff is not available for aggregate type").
+			WithContext("aggregate_type", aggregateType)
+	}
+	return loader, nil
+}
+
+func defaultPayloadSummarizer(evt *eventing.Event[int64]) any {
+	value := messaging.PayloadValue(evt.Payload)
+	if raw, ok := value.([]byte); ok && json.Valid(raw) {
+		return json.RawMessage(raw)
+	}
+	return value
+}
+
+// loadStateDocument 重建状态并转换为通用 JSON 文档（map/slice/标量）以便逐字段比较。
+func loadStateDocument(ctx context.Context, loader AggregateStateLoader, id int64, version uint64) (any, error) {
+	state, err := loader(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "encode aggregate state failed").
+			WithContext("aggregate_id", id).
+			WithContext("version", version)
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "decode aggregate state failed").
+			WithContext("aggregate_id", id).
+			WithContext("version", version)
+	}
+	return doc, nil
+}
+
+// diffStateDocuments 比较两个 JSON 文档；对象逐键递归，数组与标量整体比较。
+func diffStateDocuments(before, after any) []StateChange {
+	changes := make([]StateChange, 0)
+	collectStateChanges("", before, after, &changes)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+func collectStateChanges(path string, before, after any, changes *[]StateChange) {
+	beforeObj, beforeIsObj := before.(map[string]any)
+	afterObj, afterIsObj := after.(map[string]any)
+	if beforeIsObj && afterIsObj || before == nil && afterIsObj || beforeIsObj && after == nil {
+		for key, value := range beforeObj {
+			collectStateChanges(joinStatePath(path, key), value, afterObj[key], changes)
+		}
+		for key, value := range afterObj {
+			if _, ok := beforeObj[key]; !ok {
+				collectStateChanges(joinStatePath(path, key), nil, value, changes)
+			}
+		}
+		return
+	}
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, StateChange{Path: path, Before: before, After: after})
+	}
+}
+
+func joinStatePath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
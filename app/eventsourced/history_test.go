@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"gochen/errors"
 	"gochen/eventing"
 	"gochen/eventing/store"
 )
@@ -55,3 +56,86 @@ func TestGetEventHistoryPage_TotalIsScopedByAggregateType(t *testing.T) {
 		require.Equal(t, "AggA", entry.AggregateType)
 	}
 }
+
+// TestAggregateHistoryService_TimelineWithDiffs 验证时间线附带相邻版本状态差异。
+func TestAggregateHistoryService_TimelineWithDiffs(t *testing.T) {
+	ctx := context.Background()
+	es := store.NewMemoryEventStore()
+	reg, upgraders := newTestRegistryAndUpgraders()
+	require.NoError(t, reg.Register("ValueSet", func() any { return &valueSetEvent{} }))
+	adapter, err := NewDomainEventStore(DomainEventStoreOptions[*testAggregate, int64]{
+		AggregateType:    "TestAggregate",
+		EventStore:       es,
+		EventRegistry:    reg,
+		UpgraderRegistry: upgraders,
+	})
+	require.NoError(t, err)
+	repo, err := newTestEventSourcedRepository[*testAggregate]("TestAggregate", &testAggregate{}, AdaptAggregateFactory(newTestAggregate), adapter)
+	require.NoError(t, err)
+
+	for v := 1; v <= 3; v++ {
+		evt := eventing.NewEvent[int64](1, "TestAggregate", "ValueSet", uint64(v), &valueSetEvent{V: v * 10})
+		evt.Metadata.Set("actor_id", "u1")
+		require.NoError(t, es.AppendEvents(ctx, 1, []eventing.IStorableEvent[int64]{evt}, uint64(v-1)))
+	}
+
+	svc, err := NewAggregateHistoryService(es, nil)
+	require.NoError(t, err)
+
+	_, err = svc.Timeline(ctx, "TestAggregate", 1, HistoryQuery{IncludeDiffs: true})
+	require.True(t, errors.Is(err, errors.Unsupported))
+
+	require.NoError(t, svc.RegisterStateLoader("TestAggregate", TemporalStateLoader[*testAggregate](repo)))
+	history, err := svc.Timeline(ctx, "TestAggregate", 1, HistoryQuery{AfterVersion: 1, Limit: 1, IncludeDiffs: true})
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), history.HeadVersion)
+	require.True(t, history.HasMore)
+	require.Equal(t, uint64(2), history.NextVersion)
+	require.Len(t, history.Entries, 1)
+	require.Equal(t, uint64(2), history.Entries[0].Version)
+	require.Equal(t, "u1", history.Entries[0].Metadata["actor_id"])
+	require.Equal(t, []StateDiff{{
+		FromVersion: 1,
+		ToVersion:   2,
+		Changes:     []StateChange{{Path: "Value", Before: float64(10), After: float64(20)}},
+	}}, history.Diffs)
+
+	full, err := svc.Timeline(ctx, "TestAggregate", 1, HistoryQuery{Limit: MaxHistoryPageSize})
+	require.NoError(t, err)
+	require.Len(t, full.Entries, 3)
+	require.Empty(t, full.Diffs)
+
+	diff, err := svc.Diff(ctx, "TestAggregate", 1, 0, 3)
+	require.NoError(t, err)
+	require.Equal(t, []StateChange{{Path: "Value", After: float64(30)}}, diff.Changes)
+
+	_, err = svc.Timeline(ctx, "TestAggregate", 2, HistoryQuery{})
+	require.True(t, errors.Is(err, errors.NotFound))
+}
+
+// TestAggregateHistoryService_TimelineWithDiffsCapsPageSize 验证附带状态差异时单页事件数受 MaxHistoryDiffPageSize 限制。
+func TestAggregateHistoryService_TimelineWithDiffsCapsPageSize(t *testing.T) {
+	ctx := context.Background()
+	es := store.NewMemoryEventStore()
+	total := MaxHistoryDiffPageSize + 5
+	for v := 1; v <= total; v++ {
+		evt := eventing.NewEvent[int64](1, "Counter", "Incremented", uint64(v), map[string]any{"v": v})
+		require.NoError(t, es.AppendEvents(ctx, 1, []eventing.IStorableEvent[int64]{evt}, uint64(v-1)))
+	}
+
+	svc, err := NewAggregateHistoryService(es, nil)
+	require.NoError(t, err)
+	replays := 0
+	require.NoError(t, svc.RegisterStateLoader("Counter", func(_ context.Context, _ int64, version uint64) (any, error) {
+		replays++
+		return map[string]uint64{"version": version}, nil
+	}))
+
+	history, err := svc.Timeline(ctx, "Counter", 1, HistoryQuery{Limit: MaxHistoryPageSize, IncludeDiffs: true})
+	require.NoError(t, err)
+	require.Len(t, history.Entries, MaxHistoryDiffPageSize)
+	require.Len(t, history.Diffs, MaxHistoryDiffPageSize)
+	require.Equal(t, MaxHistoryDiffPageSize+1, replays)
+	require.True(t, history.HasMore)
+	require.Equal(t, uint64(MaxHistoryDiffPageSize), history.NextVersion)
+}
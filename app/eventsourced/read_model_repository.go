@@ -0,0 +1,216 @@
+package eventsourced
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"gochen/domain"
+	"gochen/domain/crud"
+	deventsourced "gochen/domain/eventsourced"
+	"gochen/errors"
+	"gochen/logging"
+)
+
+// ReadModelProjector 把聚合当前状态投影为读模型行；返回 nil 行表示删除该聚合的读模型行（如聚合已关闭/注销）。
+type ReadModelProjector[T deventsourced.IEventSourcedAggregate[ID], R domain.IEntity[ID], ID comparable] func(ctx context.Context, aggregate T) (R, error)
+
+// IReadModelRepository 是承载读模型行的仓储能力：CRUD 写入 + 按 ID/分页查询。
+type IReadModelRepository[R domain.IEntity[ID], ID comparable] interface {
+	crud.IRepository[R, ID]
+	crud.IQueryRepository[R, ID]
+}
+
+// ReadModelRepositoryOptions 读模型装饰器配置。
+type ReadModelRepositoryOptions[T deventsourced.IEventSourcedAggregate[ID], R domain.IEntity[ID], ID comparable] struct {
+	// ReadModel 承载当前状态行的 CRUD 仓储（通常是 db/orm/repo.Repo，提供列表/过滤/分页能力）。
+	// 行版本由 Projector 决定（通常取聚合版本），因此该仓储不应再启用基于 version 的乐观锁（如 ORM Repo 的审计字段）。
+	ReadModel IReadModelRepository[R, ID]
+
+	// Projector 把聚合投影为读模型行。
+	Projector ReadModelProjector[T, R, ID]
+
+	// FailOnError 为 true 时：读模型写入失败将导致 Save 返回错误（事件已追加，调用方不应按并发冲突重试）；
+	// 为 false 时：仅记录日志，读模型可通过 Rebuild 修复（推荐默认）。
+	FailOnError bool
+
+	Logger logging.ILogger
+}
+
+// ReadModelRepository 是事件溯源 + CRUD 的混合仓储装饰器：Save 追加事件后同步维护一行当前状态。
+//
+// 说明：
+//   - 写路径仍以事件流为准；读模型行由注册的 Projector 从保存后的聚合状态生成，按 ID Upsert 到 ReadModel；
+//   - 若 ctx 携带事务且事件存储与读模型共用同一数据库，两者在同一事务内提交；否则读模型为"尽力同步"，
+//     漂移时可调用 Rebuild 按事件重建；
+//   - GetReadModel/ListReadModels 直接查询读模型表，无需重放事件；更复杂的过滤请通过 ReadModel() 使用
+//     底层仓储的查询能力（如 crud.IQueryRepository）。
+type ReadModelRepository[T deventsourced.IEventSourcedAggregate[ID], R domain.IEntity[ID], ID comparable] struct {
+	inner       deventsourced.IEventSourcedRepository[T, ID]
+	readModel   IReadModelRepository[R, ID]
+	projector   ReadModelProjector[T, R, ID]
+	failOnError bool
+	logger      logging.ILogger
+}
+
+// NewReadModelRepository 创建读模型装饰器。
+func NewReadModelRepository[T deventsourced.IEventSourcedAggregate[ID], R domain.IEntity[ID], ID comparable](
+	inner deventsourced.IEventSourcedRepository[T, ID],
+	opts ReadModelRepositoryOptions[T, R, ID],
+) (*ReadModelRepository[T, R, ID], error) {
+	if inner == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "inner repository cannot be nil")
+	}
+	if opts.ReadModel == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "read model repository cannot be nil")
+	}
+	if opts.Projector == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "read model projector cannot be nil")
+	}
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = logging.ComponentLogger("app.eventsourced.read_model_repository")
+	}
+	return &ReadModelRepository[T, R, ID]{
+		inner:       inner,
+		readModel:   opts.ReadModel,
+		projector:   opts.Projector,
+		failOnError: opts.FailOnError,
+		logger:      logger,
+	}, nil
+}
+
+// Save 保存聚合事件，随后把当前状态投影到读模型。
+func (r *ReadModelRepository[T, R, ID]) Save(ctx context.Context, aggregate T) error {
+	if any(aggregate) == nil {
+		return errors.NewCode(errors.InvalidInput, "aggregate cannot be nil")
+	}
+	if err := r.inner.Save(ctx, aggregate); err != nil {
+		return err
+	}
+	if err := r.project(ctx, aggregate); err != nil {
+		if r.failOnError {
+			return err
+		}
+		r.logger.Warn(ctx, "update read model failed",
+			logging.Any("aggregate_id", aggregate.GetID()),
+			logging.Uint64("version", aggregate.GetVersion()),
+			logging.Error(err))
+	}
+	return nil
+}
+
+// Rebuild 从事件流重放聚合并重写其读模型行；聚合不存在时删除残留行。
+func (r *ReadModelRepository[T, R, ID]) Rebuild(ctx context.Context, id ID) error {
+	aggregate, err := r.inner.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, errors.NotFound) {
+			return r.deleteRow(ctx, id)
+		}
+		return err
+	}
+	return r.project(ctx, aggregate)
+}
+
+// GetReadModel 按 ID 读取读模型行（不重放事件）；不存在返回 errors.NotFound。
+func (r *ReadModelRepository[T, R, ID]) GetReadModel(ctx context.Context, id ID) (R, error) {
+	return r.readModel.Get(ctx, id)
+}
+
+// ListReadModels 分页列出读模型行（不重放事件）。
+func (r *ReadModelRepository[T, R, ID]) ListReadModels(ctx context.Context, offset, limit int) ([]R, error) {
+	return r.readModel.List(ctx, offset, limit)
+}
+
+// ReadModel 返回底层读模型仓储，用于过滤/排序等查询能力。
+func (r *ReadModelRepository[T, R, ID]) ReadModel() IReadModelRepository[R, ID] {
+	return r.readModel
+}
+
+// Get 通过重放事件获取聚合（写路径仍以事件流为准）。
+func (r *ReadModelRepository[T, R, ID]) Get(ctx context.Context, id ID) (T, error) {
+	return r.inner.Get(ctx, id)
+}
+
+// GetOrCreate 获取或创建聚合。
+func (r *ReadModelRepository[T, R, ID]) GetOrCreate(ctx context.Context, id ID) (T, error) {
+	return r.inner.GetOrCreate(ctx, id)
+}
+
+// Exists 判断聚合是否存在（以事件流为准）。
+func (r *ReadModelRepository[T, R, ID]) Exists(ctx context.Context, id ID) (bool, error) {
+	return r.inner.Exists(ctx, id)
+}
+
+// GetAggregateVersion 获取聚合当前版本。
+func (r *ReadModelRepository[T, R, ID]) GetAggregateVersion(ctx context.Context, id ID) (uint64, error) {
+	return r.inner.GetAggregateVersion(ctx, id)
+}
+
+// GetByIDAsOf 按时间重建历史状态（委托 inner，inner 不支持时返回 errors.Unsupported）。
+func (r *ReadModelRepository[T, R, ID]) GetByIDAsOf(ctx context.Context, id ID, asOf time.Time) (T, error) {
+	temporal, err := temporalRepository(r.inner)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return temporal.GetByIDAsOf(ctx, id, asOf)
+}
+
+// GetByIDAtVersion 按版本重建历史状态（委托 inner，inner 不支持时返回 errors.Unsupported）。
+func (r *ReadModelRepository[T, R, ID]) GetByIDAtVersion(ctx context.Context, id ID, version uint64) (T, error) {
+	temporal, err := temporalRepository(r.inner)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return temporal.GetByIDAtVersion(ctx, id, version)
+}
+
+// project 投影聚合并按 ID Upsert 读模型行。
+func (r *ReadModelRepository[T, R, ID]) project(ctx context.Context, aggregate T) error {
+	row, err := r.projector(ctx, aggregate)
+	if err != nil {
+		return err
+	}
+	if isNilReadModelRow(row) {
+		return r.deleteRow(ctx, aggregate.GetID())
+	}
+	if row.GetID() != aggregate.GetID() {
+		return errors.NewCode(errors.Internal, "read model row id does not match aggregate id").
+			WithContext("aggregate_id", aggregate.GetID()).
+			WithContext("row_id", row.GetID())
+	}
+	exists, err := r.readModel.Exists(ctx, row.GetID())
+	if err != nil {
+		return err
+	}
+	if exists {
+		return r.readModel.Update(ctx, row)
+	}
+	return r.readModel.Create(ctx, row)
+}
+
+func (r *ReadModelRepository[T, R, ID]) deleteRow(ctx context.Context, id ID) error {
+	exists, err := r.readModel.Exists(ctx, id)
+	if err != nil || !exists {
+		return err
+	}
+	return r.readModel.Delete(ctx, id)
+}
+
+func isNilReadModelRow(row any) bool {
+	if row == nil {
+		return true
+	}
+	rv := reflect.ValueOf(row)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+var _ deventsourced.IEventSourcedRepository[deventsourced.IEventSourcedAggregate[int64], int64] = (*ReadModelRepository[deventsourced.IEventSourcedAggregate[int64], domain.IEntity[int64], int64])(nil)
@@ -0,0 +1,153 @@
+package eventsourced
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/eventing/store"
+	"gochen/logging"
+)
+
+type snapshotRow struct {
+	ID      int64
+	Version uint64
+	Value   int
+}
+
+func (r *snapshotRow) GetID() int64       { return r.ID }
+func (r *snapshotRow) GetVersion() uint64 { return r.Version }
+
+// memReadModelRepo 是读模型测试用的内存 CRUD 仓储。
+type memReadModelRepo struct {
+	rows    map[int64]*snapshotRow
+	failing bool
+}
+
+func (m *memReadModelRepo) Create(_ context.Context, row *snapshotRow) error {
+	if m.failing {
+		return errors.NewCode(errors.Database, "read model unavailable")
+	}
+	m.rows[row.ID] = row
+	return nil
+}
+
+func (m *memReadModelRepo) Update(ctx context.Context, row *snapshotRow) error {
+	return m.Create(ctx, row)
+}
+
+func (m *memReadModelRepo) Delete(_ context.Context, id int64) error {
+	delete(m.rows, id)
+	return nil
+}
+
+func (m *memReadModelRepo) Get(_ context.Context, id int64) (*snapshotRow, error) {
+	if row, ok := m.rows[id]; ok {
+		return row, nil
+	}
+	return nil, errors.NewCode(errors.NotFound, "record not found")
+}
+
+func (m *memReadModelRepo) List(context.Context, int, int) ([]*snapshotRow, error) {
+	out := make([]*snapshotRow, 0, len(m.rows))
+	for _, row := range m.rows {
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+func (m *memReadModelRepo) Count(context.Context) (int64, error) { return int64(len(m.rows)), nil }
+
+func (m *memReadModelRepo) Exists(_ context.Context, id int64) (bool, error) {
+	_, ok := m.rows[id]
+	return ok, nil
+}
+
+func newReadModelTestRepo(t *testing.T, rows *memReadModelRepo, failOnError bool) *ReadModelRepository[*snapshotAggregate, *snapshotRow, int64] {
+	t.Helper()
+	reg, upgraders := newTestRegistryAndUpgraders()
+	require.NoError(t, reg.Register("SnapSet", func() any { return &snapEvent{} }))
+	adapter, err := NewDomainEventStore(DomainEventStoreOptions[*snapshotAggregate, int64]{
+		AggregateType:    "SnapAggregate",
+		EventStore:       store.NewMemoryEventStore(),
+		EventRegistry:    reg,
+		UpgraderRegistry: upgraders,
+	})
+	require.NoError(t, err)
+	baseRepo, err := newTestEventSourcedRepository[*snapshotAggregate, int64]("SnapAggregate", &snapshotAggregate{}, AdaptAggregateFactory(newSnapshotAggregate), adapter)
+	require.NoError(t, err)
+
+	repo, err := NewReadModelRepository[*snapshotAggregate, *snapshotRow, int64](baseRepo, ReadModelRepositoryOptions[*snapshotAggregate, *snapshotRow, int64]{
+		ReadModel: rows,
+		Projector: func(_ context.Context, agg *snapshotAggregate) (*snapshotRow, error) {
+			if agg.Value < 0 {
+				return nil, nil
+			}
+			return &snapshotRow{ID: agg.GetID(), Version: agg.GetVersion(), Value: agg.Value}, nil
+		},
+		FailOnError: failOnError,
+		Logger:      logging.NewNoopLogger(),
+	})
+	require.NoError(t, err)
+	return repo
+}
+
+func TestReadModelRepository_Save_UpsertsAndDeletesRow(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	rows := &memReadModelRepo{rows: map[int64]*snapshotRow{}}
+	repo := newReadModelTestRepo(t, rows, true)
+
+	agg := newSnapshotAggregate(1)
+	require.NoError(t, agg.ApplyAndRecord(&snapEvent{V: 1}))
+	require.NoError(t, repo.Save(ctx, agg))
+
+	row, err := repo.GetReadModel(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, row.Value)
+	require.Equal(t, uint64(1), row.Version)
+
+	agg, err = repo.Get(ctx, 1)
+	require.NoError(t, err)
+	require.NoError(t, agg.ApplyAndRecord(&snapEvent{V: 5}))
+	require.NoError(t, repo.Save(ctx, agg))
+
+	listed, err := repo.ListReadModels(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.Equal(t, 5, listed[0].Value)
+
+	require.NoError(t, agg.ApplyAndRecord(&snapEvent{V: -1}))
+	require.NoError(t, repo.Save(ctx, agg))
+	_, err = repo.GetReadModel(ctx, 1)
+	require.True(t, errors.Is(err, errors.NotFound))
+}
+
+func TestReadModelRepository_FailureAndRebuild(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	rows := &memReadModelRepo{rows: map[int64]*snapshotRow{}, failing: true}
+	repo := newReadModelTestRepo(t, rows, false)
+
+	agg := newSnapshotAggregate(2)
+	require.NoError(t, agg.ApplyAndRecord(&snapEvent{V: 7}))
+	require.NoError(t, repo.Save(ctx, agg), "read model failure must not fail Save by default")
+	require.Empty(t, rows.rows)
+
+	rows.failing = false
+	require.NoError(t, repo.Rebuild(ctx, 2))
+	row, err := repo.GetReadModel(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, 7, row.Value)
+
+	rows.rows[3] = &snapshotRow{ID: 3}
+	require.NoError(t, repo.Rebuild(ctx, 3), "rebuilding a missing aggregate removes the stale row")
+	require.NotContains(t, rows.rows, int64(3))
+
+	strict := newReadModelTestRepo(t, &memReadModelRepo{rows: map[int64]*snapshotRow{}, failing: true}, true)
+	agg = newSnapshotAggregate(4)
+	require.NoError(t, agg.ApplyAndRecord(&snapEvent{V: 1}))
+	require.True(t, errors.Is(strict.Save(ctx, agg), errors.Database))
+}
@@ -2,7 +2,10 @@ package eventsourced
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"gochen/contextx"
 	deventsourced "gochen/domain/eventsourced"
 	"gochen/errors"
 )
@@ -110,6 +113,7 @@ func (r *EventSourcedRepository[T, ID]) Save(ctx context.Context, aggregate T) e
 	if len(events) == 0 {
 		return nil
 	}
+	ctx = withAggregateContext(ctx, aggregate.GetID())
 
 	if err := r.store.AppendEvents(ctx, aggregate.GetID(), events, aggregate.GetExpectedVersion()); err != nil {
 		return err
@@ -130,6 +134,7 @@ func (r *EventSourcedRepository[T, ID]) Get(ctx context.Context, id ID) (T, erro
 		var zero T
 		return zero, errors.NewCode(errors.InvalidInput, "ctx is nil")
 	}
+	ctx = withAggregateContext(ctx, id)
 	aggregate, err := r.newAggregate(id)
 	if err != nil {
 		var zero T
@@ -159,6 +164,7 @@ func (r *EventSourcedRepository[T, ID]) GetOrCreate(ctx context.Context, id ID)
 		var zero T
 		return zero, errors.NewCode(errors.InvalidInput, "ctx is nil")
 	}
+	ctx = withAggregateContext(ctx, id)
 	aggregate, err := r.newAggregate(id)
 	if err != nil {
 		var zero T
@@ -195,5 +201,95 @@ func (r *EventSourcedRepository[T, ID]) GetAggregateVersion(ctx context.Context,
 	return version, nil
 }
 
+// GetByIDAsOf 重建聚合在 asOf 时刻的历史状态（只重放时间戳不晚于 asOf 的事件）。
+//
+// 说明：
+// - 需要事件存储实现 deventsourced.IPointInTimeRestorer，否则返回 errors.Unsupported；
+// - 该时刻之前聚合不存在时返回 errors.NotFound；
+// - 返回的聚合为只读历史视图，不应再 Save。
+func (r *EventSourcedRepository[T, ID]) GetByIDAsOf(ctx context.Context, id ID, asOf time.Time) (T, error) {
+	if asOf.IsZero() {
+		var zero T
+		return zero, errors.NewCode(errors.InvalidInput, "as-of time cannot be zero")
+	}
+	return r.getAt(ctx, id, deventsourced.RestorePoint{AsOf: asOf})
+}
+
+// GetByIDAtVersion 重建聚合在指定版本的历史状态（只重放版本号不大于 version 的事件）。
+//
+// 说明：
+// - 需要事件存储实现 deventsourced.IPointInTimeRestorer，否则返回 errors.Unsupported；
+// - 聚合当前版本小于 version 时返回 errors.NotFound；
+// - 返回的聚合为只读历史视图，不应再 Save。
+func (r *EventSourcedRepository[T, ID]) GetByIDAtVersion(ctx context.Context, id ID, version uint64) (T, error) {
+	if version == 0 {
+		var zero T
+		return zero, errors.NewCode(errors.InvalidInput, "version must be greater than 0")
+	}
+	return r.getAt(ctx, id, deventsourced.RestorePoint{Version: version})
+}
+
+// getAt 按历史时点恢复聚合。
+func (r *EventSourcedRepository[T, ID]) getAt(ctx context.Context, id ID, point deventsourced.RestorePoint) (T, error) {
+	var zero T
+	if ctx == nil {
+		return zero, errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	restorer, ok := r.store.(deventsourced.IPointInTimeRestorer[ID])
+	if !ok {
+		return zero, errors.NewCode(errors.Unsupported, "event store does not support point-in-time restore").
+			WithContext("aggregate_type", r.aggregateType)
+	}
+	aggregate, err := r.newAggregate(id)
+	if err != nil {
+		return zero, err
+	}
+	result, err := restorer.RestoreAggregateAt(ctx, aggregate, point)
+	if err != nil {
+		return zero, err
+	}
+	if !result.Exists || (point.Version > 0 && result.Version < point.Version) {
+		return zero, errors.NewCode(errors.NotFound, "aggregate not found at restore point").
+			WithContext("aggregate_type", r.aggregateType).
+			WithContext("id", id).
+			WithContext("version", point.Version).
+			WithContext("as_of", point.AsOf).
+			WithContext("restored_version", result.Version)
+	}
+	return aggregate, nil
+}
+
+// SplitStream 在聚合当前版本处关闭其事件流，后续事件写入 newStreamID 对应的新流。
+//
+// 说明：
+// - 需要事件存储实现 deventsourced.IStreamSplitter（如配置了 StreamLinks 的 DomainEventStore），否则返回 errors.Unsupported；
+// - 切换后 Get/Save 透明地定位到最新流，逻辑版本号保持连续；
+// - 适合在创建快照后对无界增长的聚合（如账本）周期性调用，使重放耗时保持恒定。
+func (r *EventSourcedRepository[T, ID]) SplitStream(ctx context.Context, id ID, newStreamID ID) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	splitter, ok := r.store.(deventsourced.IStreamSplitter[ID])
+	if !ok {
+		return errors.NewCode(errors.Unsupported, "event store does not support stream splitting").
+			WithContext("aggregate_type", r.aggregateType)
+	}
+	ctx = withAggregateContext(ctx, id)
+	aggregate, err := r.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	return splitter.SplitStream(ctx, aggregate, newStreamID)
+}
+
 // Ensure interface compliance.
+var _ deventsourced.ITemporalRepository[deventsourced.IEventSourcedAggregate[int64], int64] = (*EventSourcedRepository[deventsourced.IEventSourcedAggregate[int64], int64])(nil)
 var _ deventsourced.IEventSourcedRepository[deventsourced.IEventSourcedAggregate[int64], int64] = (*EventSourcedRepository[deventsourced.IEventSourcedAggregate[int64], int64])(nil)
+
+// withAggregateContext 把聚合 ID 写入 ctx，使事件存储、Outbox 与处理器中的日志自动携带 aggregate_id。
+func withAggregateContext[ID comparable](ctx context.Context, id ID) context.Context {
+	if derived, err := contextx.WithAggregateID(ctx, fmt.Sprint(id)); err == nil {
+		return derived
+	}
+	return ctx
+}
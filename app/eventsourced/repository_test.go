@@ -3,10 +3,13 @@ package eventsourced
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	deventsourced "gochen/domain/eventsourced"
+	"gochen/errors"
+	"gochen/eventing"
 	"gochen/eventing/store"
 )
 
@@ -83,3 +86,49 @@ func TestEventSourcedRepository_SaveAndLoad(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, uint64(1), version)
 }
+
+// TestEventSourcedRepository_PointInTime 验证按版本/时间重建历史状态。
+func TestEventSourcedRepository_PointInTime(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewMemoryEventStore()
+	reg, upgraders := newTestRegistryAndUpgraders()
+	require.NoError(t, reg.Register("ValueSet", func() any { return &valueSetEvent{} }))
+
+	adapter, err := NewDomainEventStore(DomainEventStoreOptions[*testAggregate, int64]{
+		AggregateType:    "TestAggregate",
+		EventStore:       eventStore,
+		EventRegistry:    reg,
+		UpgraderRegistry: upgraders,
+	})
+	require.NoError(t, err)
+	repo, err := newTestEventSourcedRepository[*testAggregate]("TestAggregate", &testAggregate{}, AdaptAggregateFactory(newTestAggregate), adapter)
+	require.NoError(t, err)
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := make([]eventing.IStorableEvent[int64], 0, 3)
+	for v := uint64(1); v <= 3; v++ {
+		evt := eventing.NewEvent[int64](1, "TestAggregate", "ValueSet", v, &valueSetEvent{V: int(v) * 10})
+		evt.Timestamp = base.Add(time.Duration(v) * 24 * time.Hour)
+		events = append(events, evt)
+	}
+	require.NoError(t, eventStore.AppendEvents(ctx, 1, events, 0))
+
+	atVersion, err := repo.GetByIDAtVersion(ctx, 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, 20, atVersion.Value)
+	require.Equal(t, uint64(2), atVersion.GetVersion())
+
+	asOf, err := repo.GetByIDAsOf(ctx, 1, base.Add(36*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 10, asOf.Value)
+	require.Equal(t, uint64(1), asOf.GetVersion())
+
+	_, err = repo.GetByIDAsOf(ctx, 1, base)
+	require.True(t, errors.Is(err, errors.NotFound))
+	_, err = repo.GetByIDAtVersion(ctx, 1, 4)
+	require.True(t, errors.Is(err, errors.NotFound))
+
+	latest, err := repo.Get(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, 30, latest.Value)
+}
@@ -2,6 +2,7 @@ package eventsourced
 
 import (
 	"context"
+	"time"
 
 	deventsourced "gochen/domain/eventsourced"
 	"gochen/errors"
@@ -116,3 +117,31 @@ func (r *SnapshottingRepository[T, ID]) Exists(ctx context.Context, id ID) (bool
 func (r *SnapshottingRepository[T, ID]) GetAggregateVersion(ctx context.Context, id ID) (uint64, error) {
 	return r.inner.GetAggregateVersion(ctx, id)
 }
+
+// GetByIDAsOf 按时间重建历史状态（委托 inner，inner 不支持时返回 errors.Unsupported）。
+func (r *SnapshottingRepository[T, ID]) GetByIDAsOf(ctx context.Context, id ID, asOf time.Time) (T, error) {
+	temporal, err := temporalRepository(r.inner)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return temporal.GetByIDAsOf(ctx, id, asOf)
+}
+
+// GetByIDAtVersion 按版本重建历史状态（委托 inner，inner 不支持时返回 errors.Unsupported）。
+func (r *SnapshottingRepository[T, ID]) GetByIDAtVersion(ctx context.Context, id ID, version uint64) (T, error) {
+	temporal, err := temporalRepository(r.inner)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return temporal.GetByIDAtVersion(ctx, id, version)
+}
+
+func temporalRepository[T deventsourced.IEventSourcedAggregate[ID], ID comparable](repo deventsourced.IEventSourcedRepository[T, ID]) (deventsourced.ITemporalRepository[T, ID], error) {
+	temporal, ok := repo.(deventsourced.ITemporalRepository[T, ID])
+	if !ok {
+		return nil, errors.NewCode(errors.Unsupported, "repository does not support point-in-time reads")
+	}
+	return temporal, nil
+}
@@ -0,0 +1,315 @@
+package eventsourced
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"gochen/contextx"
+	"gochen/domain"
+	deventsourced "gochen/domain/eventsourced"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/registry"
+	"gochen/eventing/store"
+	"gochen/logging"
+)
+
+const (
+	// MetadataStreamIDKey 记录流切换后事件实际所在的物理事件流 ID。
+	MetadataStreamIDKey = "stream_id"
+	// MetadataStreamVersionKey 记录流切换后事件在物理事件流内的版本。
+	MetadataStreamVersionKey = "stream_version"
+
+	// StreamMigratedEventType 是旧流末尾的关闭标记：此后该聚合的事件写入 ToStreamID 指向的新流。
+	StreamMigratedEventType = "StreamMigrated"
+	// StreamStartedEventType 是新流的首个事件，携带切换时的聚合状态检查点。
+	StreamStartedEventType = "StreamStarted"
+)
+
+// StreamMigrated 旧流关闭标记的载荷。
+type StreamMigrated[ID comparable] struct {
+	ToStreamID ID `json:"to_stream_id"`
+	// Version 是切换时聚合的逻辑版本。
+	Version uint64 `json:"version"`
+}
+
+// EventType 返回事件类型。
+func (e *StreamMigrated[ID]) EventType() string { return StreamMigratedEventType }
+
+// StreamStarted 新流首个事件的载荷。
+type StreamStarted[ID comparable] struct {
+	FromStreamID ID `json:"from_stream_id"`
+	// Version 是检查点对应的逻辑版本（即新流物理版本 1 对应的逻辑版本）。
+	Version uint64 `json:"version"`
+	// State 是聚合状态（优先 SnapshotData()，否则为聚合本身的 JSON）。
+	State json.RawMessage `json:"state"`
+}
+
+// EventType 返回事件类型。
+func (e *StreamStarted[ID]) EventType() string { return StreamStartedEventType }
+
+// StreamLink 记录聚合当前所在的事件流。
+//
+// 逻辑版本 = BaseVersion + 流内物理版本；未切换过的聚合没有链接，等价于 {StreamID: AggregateID, BaseVersion: 0}。
+type StreamLink[ID comparable] struct {
+	AggregateID ID
+	StreamID    ID
+	BaseVersion uint64
+	// Generation 每次切换递增，用于拒绝过期链接覆盖新链接。
+	Generation int
+}
+
+// IStreamLinkStore 持久化聚合到当前事件流的链接。
+//
+// 实验性：目前只提供内存实现 MemoryStreamLinkStore；多进程部署需自行提供持久化实现。
+//
+// 链接只是加速"直接定位最新流"的索引：丢失或落后时，恢复会沿旧流末尾的 StreamMigrated 标记追溯并修复。
+type IStreamLinkStore[ID comparable] interface {
+	// GetStreamLink 读取链接；聚合从未切换过时返回 (nil, nil)。
+	GetStreamLink(ctx context.Context, aggregateID ID) (*StreamLink[ID], error)
+	// SaveStreamLink 保存链接；Generation 不大于已存链接时应忽略（单调推进）。
+	SaveStreamLink(ctx context.Context, link StreamLink[ID]) error
+}
+
+// MemoryStreamLinkStore 内存链接存储（测试/单进程场景）。
+type MemoryStreamLinkStore[ID comparable] struct {
+	mu    sync.RWMutex
+	links map[ID]StreamLink[ID]
+}
+
+// NewMemoryStreamLinkStore 创建内存链接存储。
+func NewMemoryStreamLinkStore[ID comparable]() *MemoryStreamLinkStore[ID] {
+	return &MemoryStreamLinkStore[ID]{links: make(map[ID]StreamLink[ID])}
+}
+
+// GetStreamLink 读取链接。
+func (s *MemoryStreamLinkStore[ID]) GetStreamLink(_ context.Context, aggregateID ID) (*StreamLink[ID], error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	link, ok := s.links[aggregateID]
+	if !ok {
+		return nil, nil
+	}
+	return &link, nil
+}
+
+// SaveStreamLink 保存链接（忽略过期代次）。
+func (s *MemoryStreamLinkStore[ID]) SaveStreamLink(_ context.Context, link StreamLink[ID]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current, ok := s.links[link.AggregateID]; ok && current.Generation >= link.Generation {
+		return nil
+	}
+	s.links[link.AggregateID] = link
+	return nil
+}
+
+// streamRef 描述一次读写所定位到的物理事件流。
+type streamRef[ID comparable] struct {
+	id         ID
+	base       uint64
+	generation int
+}
+
+// logical 把流内物理版本换算为聚合逻辑版本。
+func (r streamRef[ID]) logical(physical uint64) uint64 { return r.base + physical }
+
+// split 判断聚合是否已切换到其他事件流。
+func (r streamRef[ID]) split(aggregateID ID) bool { return r.id != aggregateID }
+
+// physical 返回写入事件存储用的副本：AggregateID 为物理流、版本为流内版本（存储按二者定位与做并发检查）。
+// 未切换的流原样返回。
+func (r streamRef[ID]) physical(events []eventing.Event[ID]) []eventing.Event[ID] {
+	if len(events) == 0 || (events[0].AggregateID == r.id && r.base == 0) {
+		return events
+	}
+	out := make([]eventing.Event[ID], len(events))
+	for i, evt := range events {
+		evt.AggregateID = r.id
+		evt.Version -= r.base
+		out[i] = evt
+	}
+	return out
+}
+
+// stampStreamLocation 在 Metadata 中记录事件的物理流与流内版本。
+func stampStreamLocation[ID comparable](md contextx.IMetadata, streamID ID, version uint64) {
+	md.Set(MetadataStreamIDKey, fmt.Sprint(streamID))
+	md.Set(MetadataStreamVersionKey, strconv.FormatUint(version, 10))
+}
+
+// resolveStream 定位聚合当前的事件流；未启用流切换或从未切换时即聚合 ID 本身。
+func (a *DomainEventStore[T, ID]) resolveStream(ctx context.Context, aggregateID ID) (streamRef[ID], error) {
+	ref := streamRef[ID]{id: aggregateID}
+	if a.streamLinks == nil {
+		return ref, nil
+	}
+	link, err := a.streamLinks.GetStreamLink(ctx, aggregateID)
+	if err != nil {
+		return ref, errors.Wrap(err, errors.Database, "failed to load stream link").
+			WithContext("aggregate_type", a.aggregateType).
+			WithContext("aggregate_id", aggregateID)
+	}
+	if link == nil {
+		return ref, nil
+	}
+	return streamRef[ID]{id: link.StreamID, base: link.BaseVersion, generation: link.Generation}, nil
+}
+
+// SplitStream 在聚合当前版本处关闭其事件流，后续事件写入 newStreamID 对应的新流。
+//
+// 步骤：
+//  1. 在新流写入 StreamStarted（物理版本 1，携带当前状态检查点）；
+//  2. 在旧流末尾追加 StreamMigrated 标记（期望版本即聚合当前版本，作为并发保护：期间有其他写入则返回 errors.Concurrency）；
+//  3. 保存链接，之后的读写直接定位新流。
+//
+// 说明：
+// - 需要配置 DomainEventStoreOptions.StreamLinks；
+// - 聚合不得有未提交事件（应先 Save）；
+// - newStreamID 由调用方分配，且在事件存储中必须尚未使用；
+// - 实验性功能：链接存储目前只有内存实现，且不能与 OutboxRepo 同时启用（改用 bus.WithTxPublishing）；
+// - 切换事件不经 EventBus/Outbox 发布；切换后的事件仍以聚合 ID 与逻辑版本发布；
+// - 新流内的物理版本从 2 开始，物理流与版本记录在 Metadata 的 stream_id/stream_version 中；
+// - 第 2 步失败时新流成为孤儿流（不会被链接引用），可安全忽略。
+func (a *DomainEventStore[T, ID]) SplitStream(ctx context.Context, aggregate deventsourced.IEventSourcedAggregate[ID], newStreamID ID) error {
+	if a.streamLinks == nil {
+		return errors.NewCode(errors.Unsupported, "stream splitting requires a stream link store").
+			WithContext("aggregate_type", a.aggregateType)
+	}
+	if aggregate == nil {
+		return errors.NewCode(errors.InvalidInput, "aggregate cannot be nil")
+	}
+	aggregateID := aggregate.GetID()
+	if len(aggregate.GetUncommittedEvents()) > 0 {
+		return errors.NewCode(errors.InvalidInput, "aggregate has uncommitted events").
+			WithContext("aggregate_id", aggregateID)
+	}
+	version := aggregate.GetVersion()
+	if version == 0 {
+		return errors.NewCode(errors.NotFound, "aggregate has no events to split").
+			WithContext("aggregate_type", a.aggregateType).
+			WithContext("aggregate_id", aggregateID)
+	}
+
+	ref, err := a.resolveStream(ctx, aggregateID)
+	if err != nil {
+		return err
+	}
+	if newStreamID == aggregateID || newStreamID == ref.id {
+		return errors.NewCode(errors.InvalidInput, "new stream id must differ from the current stream").
+			WithContext("aggregate_id", aggregateID).
+			WithContext("stream_id", newStreamID)
+	}
+	if version <= ref.base {
+		return errors.NewCode(errors.Concurrency, "aggregate version is behind the current stream").
+			WithContext("aggregate_id", aggregateID).
+			WithContext("version", version)
+	}
+
+	state, err := marshalStreamState(aggregate)
+	if err != nil {
+		return err
+	}
+	now := a.clock.Now()
+	started := eventing.NewEvent(newStreamID, a.aggregateType, StreamStartedEventType, 1,
+		&StreamStarted[ID]{FromStreamID: ref.id, Version: version, State: state}, 1)
+	started.Timestamp = now
+	if err := a.eventStore.AppendEvents(ctx, newStreamID, store.ToStorable([]eventing.Event[ID]{*started}), 0); err != nil {
+		return err
+	}
+
+	physical := version - ref.base
+	migrated := eventing.NewEvent(ref.id, a.aggregateType, StreamMigratedEventType, physical+1,
+		&StreamMigrated[ID]{ToStreamID: newStreamID, Version: version}, 1)
+	migrated.Timestamp = now
+	if err := a.eventStore.AppendEvents(ctx, ref.id, store.ToStorable([]eventing.Event[ID]{*migrated}), physical); err != nil {
+		return err
+	}
+
+	link := StreamLink[ID]{AggregateID: aggregateID, StreamID: newStreamID, BaseVersion: version - 1, Generation: ref.generation + 1}
+	if err := a.streamLinks.SaveStreamLink(ctx, link); err != nil {
+		// 标记已写入：后续恢复会沿标记追溯并修复链接，这里只记录日志。
+		a.logger.Warn(ctx, "save stream link failed",
+			logging.Any("aggregate_id", aggregateID),
+			logging.Any("stream_id", newStreamID),
+			logging.Error(err))
+	}
+	a.logger.Info(ctx, "aggregate stream split",
+		logging.Any("aggregate_id", aggregateID),
+		logging.Any("from_stream_id", ref.id),
+		logging.Any("to_stream_id", newStreamID),
+		logging.Uint64("version", version))
+	return nil
+}
+
+// followStream 处理旧流末尾的 StreamMigrated 标记：修复链接并返回新流定位。
+func (a *DomainEventStore[T, ID]) followStream(ctx context.Context, aggregateID ID, from streamRef[ID], marker *StreamMigrated[ID]) streamRef[ID] {
+	next := streamRef[ID]{id: marker.ToStreamID, base: marker.Version - 1, generation: from.generation + 1}
+	if a.streamLinks != nil {
+		link := StreamLink[ID]{AggregateID: aggregateID, StreamID: next.id, BaseVersion: next.base, Generation: next.generation}
+		if err := a.streamLinks.SaveStreamLink(ctx, link); err != nil {
+			a.logger.Warn(ctx, "repair stream link failed",
+				logging.Any("aggregate_id", aggregateID),
+				logging.Any("stream_id", next.id),
+				logging.Error(err))
+		}
+	}
+	return next
+}
+
+// restoreStreamCheckpoint 把 StreamStarted 检查点恢复到聚合。
+func restoreStreamCheckpoint[ID comparable](aggregate deventsourced.IEventSourcedAggregate[ID], started *StreamStarted[ID]) error {
+	if restorer, ok := aggregate.(interface{ RestoreFromSnapshotData(data any) error }); ok {
+		var data any
+		if err := json.Unmarshal(started.State, &data); err != nil {
+			return errors.Wrap(err, errors.InvalidInput, "failed to decode stream checkpoint")
+		}
+		if err := restorer.RestoreFromSnapshotData(data); err != nil {
+			return errors.Wrap(err, errors.Internal, "failed to restore stream checkpoint")
+		}
+	} else if err := json.Unmarshal(started.State, aggregate); err != nil {
+		return errors.Wrap(err, errors.InvalidInput, "failed to decode stream checkpoint")
+	}
+	if versioned, ok := aggregate.(deventsourced.IVersionSettable); ok {
+		versioned.SetVersion(started.Version)
+	}
+	return nil
+}
+
+// marshalStreamState 序列化聚合状态检查点（与快照约定一致：优先 SnapshotData()）。
+func marshalStreamState(aggregate any) (json.RawMessage, error) {
+	data := aggregate
+	if lightweight, ok := aggregate.(interface{ SnapshotData() any }); ok {
+		data = lightweight.SnapshotData()
+	}
+	state, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "failed to serialize stream checkpoint")
+	}
+	return state, nil
+}
+
+// registerStreamEvents 向事件注册表登记流切换事件，使其能从持久化载荷还原。
+func registerStreamEvents[ID comparable](reg *registry.Registry) error {
+	if !reg.HasEvent(StreamMigratedEventType) {
+		if err := reg.Register(StreamMigratedEventType, func() any { return &StreamMigrated[ID]{} }); err != nil {
+			return err
+		}
+	}
+	if !reg.HasEvent(StreamStartedEventType) {
+		if err := reg.Register(StreamStartedEventType, func() any { return &StreamStarted[ID]{} }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	_ domain.IDomainEvent                  = (*StreamMigrated[int64])(nil)
+	_ domain.IDomainEvent                  = (*StreamStarted[int64])(nil)
+	_ IStreamLinkStore[int64]              = (*MemoryStreamLinkStore[int64])(nil)
+	_ deventsourced.IStreamSplitter[int64] = (*DomainEventStore[deventsourced.IEventSourcedAggregate[int64], int64])(nil)
+)
@@ -0,0 +1,191 @@
+package eventsourced
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/bus"
+	"gochen/eventing/store"
+	"gochen/messaging"
+	synctransport "gochen/messaging/transport/direct"
+)
+
+func newStreamSplitTestRepo(t *testing.T, links IStreamLinkStore[int64]) (*EventSourcedRepository[*snapshotAggregate, int64], *DomainEventStore[*snapshotAggregate, int64]) {
+	t.Helper()
+	reg, upgraders := newTestRegistryAndUpgraders()
+	require.NoError(t, reg.Register("SnapSet", func() any { return &snapEvent{} }))
+	adapter, err := NewDomainEventStore(DomainEventStoreOptions[*snapshotAggregate, int64]{
+		AggregateType:    "SnapAggregate",
+		EventStore:       store.NewMemoryEventStore(),
+		EventRegistry:    reg,
+		UpgraderRegistry: upgraders,
+		StreamLinks:      links,
+	})
+	require.NoError(t, err)
+	repo, err := newTestEventSourcedRepository[*snapshotAggregate, int64]("SnapAggregate", &snapshotAggregate{}, AdaptAggregateFactory(newSnapshotAggregate), adapter)
+	require.NoError(t, err)
+	return repo, adapter.(*DomainEventStore[*snapshotAggregate, int64])
+}
+
+func TestDomainEventStore_SplitStream_FollowsLatestStream(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	links := NewMemoryStreamLinkStore[int64]()
+	repo, es := newStreamSplitTestRepo(t, links)
+
+	agg := newSnapshotAggregate(1)
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, agg.ApplyAndRecord(&snapEvent{V: i}))
+	}
+	require.NoError(t, repo.Save(ctx, agg))
+	stale, err := repo.Get(ctx, 1)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.SplitStream(ctx, 1, 1001))
+	link, err := links.GetStreamLink(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, StreamLink[int64]{AggregateID: 1, StreamID: 1001, BaseVersion: 4, Generation: 1}, *link)
+
+	agg, err = repo.Get(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, 5, agg.Value)
+	require.Equal(t, uint64(5), agg.GetVersion())
+
+	require.NoError(t, agg.ApplyAndRecord(&snapEvent{V: 6}))
+	require.NoError(t, repo.Save(ctx, agg))
+
+	restored := newSnapshotAggregate(1)
+	result, err := es.RestoreAggregate(ctx, restored)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.EventCount, "replay starts at the checkpoint of the new stream")
+	require.Equal(t, uint64(6), result.Version)
+	require.Equal(t, 6, restored.Value)
+
+	version, err := repo.GetAggregateVersion(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), version)
+
+	require.NoError(t, stale.ApplyAndRecord(&snapEvent{V: 99}))
+	require.True(t, errors.Is(repo.Save(ctx, stale), errors.Concurrency), "writers loaded before the split must conflict")
+
+	historic, err := repo.GetByIDAtVersion(ctx, 1, 3)
+	require.NoError(t, err)
+	require.Equal(t, 3, historic.Value)
+	historic, err = repo.GetByIDAtVersion(ctx, 1, 6)
+	require.NoError(t, err)
+	require.Equal(t, 6, historic.Value)
+}
+
+func TestDomainEventStore_SplitStream_RepairsMissingLink(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	links := NewMemoryStreamLinkStore[int64]()
+	repo, es := newStreamSplitTestRepo(t, links)
+
+	agg := newSnapshotAggregate(2)
+	require.NoError(t, agg.ApplyAndRecord(&snapEvent{V: 1}))
+	require.NoError(t, agg.ApplyAndRecord(&snapEvent{V: 2}))
+	require.NoError(t, repo.Save(ctx, agg))
+	require.NoError(t, repo.SplitStream(ctx, 2, 2002))
+
+	// 模拟链接丢失：恢复沿旧流末尾的 StreamMigrated 标记追溯到新流并修复链接。
+	links.mu.Lock()
+	delete(links.links, 2)
+	links.mu.Unlock()
+
+	restored := newSnapshotAggregate(2)
+	result, err := es.RestoreAggregate(ctx, restored)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), result.Version)
+	require.Equal(t, 2, restored.Value)
+	link, err := links.GetStreamLink(ctx, 2)
+	require.NoError(t, err)
+	require.NotNil(t, link)
+	require.Equal(t, int64(2002), link.StreamID)
+
+	require.True(t, errors.Is(repo.SplitStream(ctx, 2, 2002), errors.InvalidInput))
+}
+
+func TestEventSourcedRepository_SplitStream_Unsupported(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo, _ := newStreamSplitTestRepo(t, nil)
+	agg := newSnapshotAggregate(1)
+	require.NoError(t, agg.ApplyAndRecord(&snapEvent{V: 1}))
+	require.NoError(t, repo.Save(ctx, agg))
+	require.True(t, errors.Is(repo.SplitStream(ctx, 1, 2), errors.Unsupported), "splitting requires a stream link store")
+}
+
+func TestDomainEventStore_SplitStream_PublishesLogicalAggregate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	transport := synctransport.NewSyncTransport()
+	require.NoError(t, transport.Start(ctx))
+	t.Cleanup(func() { _ = transport.Stop(ctx) })
+	eventBus := bus.NewEventBus(messaging.NewMessageBus(transport))
+	var published []*eventing.Event[int64]
+	_, err := eventBus.SubscribeEvent(ctx, "SnapSet", bus.EventHandlerFunc(func(_ context.Context, evt eventing.IEvent) error {
+		published = append(published, evt.(*eventing.Event[int64]))
+		return nil
+	}))
+	require.NoError(t, err)
+
+	reg, upgraders := newTestRegistryAndUpgraders()
+	require.NoError(t, reg.Register("SnapSet", func() any { return &snapEvent{} }))
+	memStore := store.NewMemoryEventStore()
+	adapter, err := NewDomainEventStore(DomainEventStoreOptions[*snapshotAggregate, int64]{
+		AggregateType:    "SnapAggregate",
+		EventStore:       memStore,
+		EventBus:         eventBus,
+		PublishEvents:    true,
+		EventRegistry:    reg,
+		UpgraderRegistry: upgraders,
+		StreamLinks:      NewMemoryStreamLinkStore[int64](),
+	})
+	require.NoError(t, err)
+	repo, err := newTestEventSourcedRepository[*snapshotAggregate, int64]("SnapAggregate", &snapshotAggregate{}, AdaptAggregateFactory(newSnapshotAggregate), adapter)
+	require.NoError(t, err)
+
+	agg := newSnapshotAggregate(1)
+	require.NoError(t, agg.ApplyAndRecord(&snapEvent{V: 1}))
+	require.NoError(t, agg.ApplyAndRecord(&snapEvent{V: 2}))
+	require.NoError(t, repo.Save(ctx, agg))
+	require.NoError(t, repo.SplitStream(ctx, 1, 1001))
+
+	agg, err = repo.Get(ctx, 1)
+	require.NoError(t, err)
+	require.NoError(t, agg.ApplyAndRecord(&snapEvent{V: 3}))
+	require.NoError(t, repo.Save(ctx, agg))
+
+	require.Len(t, published, 3)
+	evt := published[2]
+	require.Equal(t, int64(1), evt.AggregateID, "published events keep the logical aggregate id")
+	require.Equal(t, uint64(3), evt.Version, "published events carry the logical version")
+	streamID, _ := evt.GetMetadata().Get(MetadataStreamIDKey)
+	streamVersion, _ := evt.GetMetadata().Get(MetadataStreamVersionKey)
+	require.Equal(t, "1001", streamID)
+	require.Equal(t, "2", streamVersion)
+	_, ok := published[0].GetMetadata().Get(MetadataStreamIDKey)
+	require.False(t, ok, "events of unsplit streams carry no stream location")
+
+	stored, err := memStore.LoadEvents(ctx, 1001, 0)
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	require.Equal(t, int64(1001), stored[1].AggregateID, "storage stays keyed by the physical stream")
+	require.Equal(t, uint64(2), stored[1].Version)
+
+	_, err = NewDomainEventStore(DomainEventStoreOptions[*snapshotAggregate, int64]{
+		AggregateType:    "SnapAggregate",
+		EventStore:       memStore,
+		OutboxRepo:       &mockOutboxRepo{},
+		EventRegistry:    reg,
+		UpgraderRegistry: upgraders,
+		StreamLinks:      NewMemoryStreamLinkStore[int64](),
+	})
+	require.True(t, errors.Is(err, errors.InvalidInput), "stream links cannot be combined with an outbox repository")
+}
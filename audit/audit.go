@@ -0,0 +1,156 @@
+// Package audit 提供命令执行审计：记录“谁（主体）在何时对哪个聚合执行了什么命令、结果如何”。
+//
+// 组成：
+//   - Record：一条命令审计记录；
+//   - ISink / IStore：审计落地与合规查询接口，内置 MemoryStore、LoggerSink 与 audit/sqlstore；
+//   - NewCommandHook：接入 app/eventsourced.EventSourcedService 的命令钩子；
+//   - NewCommandMiddleware：接入 messaging 命令总线/执行器的中间件。
+//
+// 主体、租户、trace/request ID 均从执行上下文读取（auth.PrincipalFromContext、contextx），
+// 因此审计接入点应位于认证与上下文派生之后。审计写入失败不会改变命令结果，只记录告警日志。
+package audit
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	auth "gochen/auth"
+	"gochen/contextx"
+	"gochen/errors"
+)
+
+// Outcome 表示命令执行结果。
+type Outcome string
+
+const (
+	// OutcomeSuccess 表示命令执行成功。
+	OutcomeSuccess Outcome = "success"
+	// OutcomeFailure 表示命令执行失败（含授权拒绝、校验失败、保存冲突等）。
+	OutcomeFailure Outcome = "failure"
+)
+
+// Record 是一条命令审计记录。
+type Record struct {
+	ID            string        `json:"id"`
+	CommandName   string        `json:"command_name"`
+	CommandID     string        `json:"command_id,omitempty"`
+	AggregateType string        `json:"aggregate_type,omitempty"`
+	AggregateID   string        `json:"aggregate_id,omitempty"`
+	SubjectID     int64         `json:"subject_id,omitempty"`
+	Operator      string        `json:"operator,omitempty"`
+	TenantID      string        `json:"tenant_id,omitempty"`
+	TraceID       string        `json:"trace_id,omitempty"`
+	RequestID     string        `json:"request_id,omitempty"`
+	Outcome       Outcome       `json:"outcome"`
+	ErrorCode     string        `json:"error_code,omitempty"`
+	ErrorMessage  string        `json:"error_message,omitempty"`
+	Attempts      int           `json:"attempts,omitempty"`
+	StartedAt     time.Time     `json:"started_at"`
+	Duration      time.Duration `json:"duration"`
+}
+
+// Actor 返回记录的操作人展示值：优先 operator，其次主体 ID。
+func (r Record) Actor() string {
+	if r.Operator != "" {
+		return r.Operator
+	}
+	if r.SubjectID > 0 {
+		return strconv.FormatInt(r.SubjectID, 10)
+	}
+	return ""
+}
+
+// ISink 审计记录落地接口。
+type ISink interface {
+	Record(ctx context.Context, record Record) error
+}
+
+// SinkFunc 是 ISink 的函数适配器。
+type SinkFunc func(ctx context.Context, record Record) error
+
+// Record 调用函数本身。
+func (f SinkFunc) Record(ctx context.Context, record Record) error { return f(ctx, record) }
+
+// Filter 定义合规查询条件；零值字段表示不限制。
+type Filter struct {
+	SubjectID     int64
+	Operator      string
+	TenantID      string
+	AggregateType string
+	AggregateID   string
+	CommandName   string
+	TraceID       string
+	Outcome       Outcome
+
+	// From / To 按 StartedAt 过滤，区间为 [From, To)。
+	From time.Time
+	To   time.Time
+
+	// Limit <=0 时使用 DefaultQueryLimit；结果按 StartedAt 倒序。
+	Limit  int
+	Offset int
+}
+
+// DefaultQueryLimit 是未指定 Limit 时的默认返回条数。
+const DefaultQueryLimit = 100
+
+// IStore 可查询的审计存储。
+type IStore interface {
+	ISink
+	Query(ctx context.Context, filter Filter) ([]Record, error)
+}
+
+// NewRecord 基于上下文补齐主体与关联字段，构造一条审计记录。
+//
+// err 为 nil 时记为成功，否则记为失败并附带错误码与错误信息。
+func NewRecord(ctx context.Context, commandName string, startedAt time.Time, duration time.Duration, err error) Record {
+	record := Record{
+		CommandName: strings.TrimSpace(commandName),
+		StartedAt:   startedAt,
+		Duration:    duration,
+		Outcome:     OutcomeSuccess,
+	}
+	if ctx != nil {
+		if principal, ok := auth.PrincipalFromContext(ctx); ok {
+			record.SubjectID = principal.SubjectID
+		}
+		record.Operator = contextx.Operator(ctx)
+		record.TenantID = contextx.TenantID(ctx)
+		record.TraceID = contextx.TraceID(ctx)
+		record.RequestID = contextx.RequestID(ctx)
+	}
+	if err != nil {
+		record.Outcome = OutcomeFailure
+		record.ErrorCode = string(errors.Code(err))
+		record.ErrorMessage = err.Error()
+	}
+	return record
+}
+
+// Matches 判断记录是否满足过滤条件（不含分页）。
+func (f Filter) Matches(record Record) bool {
+	switch {
+	case f.SubjectID != 0 && record.SubjectID != f.SubjectID,
+		f.Operator != "" && record.Operator != f.Operator,
+		f.TenantID != "" && record.TenantID != f.TenantID,
+		f.AggregateType != "" && record.AggregateType != f.AggregateType,
+		f.AggregateID != "" && record.AggregateID != f.AggregateID,
+		f.CommandName != "" && record.CommandName != f.CommandName,
+		f.TraceID != "" && record.TraceID != f.TraceID,
+		f.Outcome != "" && record.Outcome != f.Outcome,
+		!f.From.IsZero() && record.StartedAt.Before(f.From),
+		!f.To.IsZero() && !record.StartedAt.Before(f.To):
+		return false
+	}
+	return true
+}
+
+// NormalizedLimit 返回生效的 Limit；IStore 实现应统一使用该方法处理默认值。
+func (f Filter) NormalizedLimit() int {
+	if f.Limit <= 0 {
+		return DefaultQueryLimit
+	}
+	return f.Limit
+}
@@ -0,0 +1,152 @@
+package audit
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	appeventsourced "gochen/app/eventsourced"
+	auth "gochen/auth"
+	"gochen/contextx"
+	deventsourced "gochen/domain/eventsourced"
+	"gochen/errors"
+	"gochen/messaging"
+	"gochen/messaging/command"
+)
+
+type orderAggregate struct {
+	*deventsourced.EventSourcedAggregate[int64]
+}
+
+type placeOrder struct{ id int64 }
+
+func (c *placeOrder) AggregateID() int64 { return c.id }
+
+type orderRepo struct{}
+
+func (orderRepo) Save(context.Context, *orderAggregate) error { return nil }
+func (orderRepo) Get(context.Context, int64) (*orderAggregate, error) {
+	return nil, errors.NewCode(errors.NotFound, "not used")
+}
+func (orderRepo) GetOrCreate(_ context.Context, id int64) (*orderAggregate, error) {
+	return &orderAggregate{EventSourcedAggregate: deventsourced.NewEventSourcedAggregate[int64](id, "Order")}, nil
+}
+func (orderRepo) Exists(context.Context, int64) (bool, error)                { return true, nil }
+func (orderRepo) GetAggregateVersion(context.Context, int64) (uint64, error) { return 0, nil }
+
+// fixedIDs 返回递增的记录 ID，便于断言。
+type fixedIDs struct{ n int }
+
+func (g *fixedIDs) Next() (string, error) {
+	g.n++
+	return "audit-" + strconv.Itoa(g.n), nil
+}
+
+func principalContext(t *testing.T, subjectID int64) context.Context {
+	t.Helper()
+	ctx, err := auth.WithPrincipal(context.Background(), auth.Principal{SubjectID: subjectID})
+	require.NoError(t, err)
+	ctx, err = contextx.WithTraceID(ctx, "trace-1")
+	require.NoError(t, err)
+	return ctx
+}
+
+func TestCommandHook_RecordsOutcomePerCommand(t *testing.T) {
+	store := NewMemoryStore(0)
+	svc, err := appeventsourced.NewEventSourcedService[*orderAggregate, int64](orderRepo{}, &appeventsourced.EventSourcedServiceOptions[*orderAggregate, int64]{
+		CommandHooks: []appeventsourced.IEventSourcedCommandHook[*orderAggregate, int64]{
+			NewCommandHook[*orderAggregate, int64](store, &Options{IDGenerator: &fixedIDs{}}),
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, svc.RegisterCommandHandler(&placeOrder{}, func(ctx context.Context, cmd appeventsourced.IEventSourcedCommand[int64], agg *orderAggregate) error {
+		if cmd.AggregateID() == 2 {
+			return errors.NewCode(errors.Validation, "out of stock")
+		}
+		return nil
+	}))
+
+	ctx := principalContext(t, 7)
+	require.NoError(t, svc.ExecuteCommand(ctx, &placeOrder{id: 1}))
+	require.Error(t, svc.ExecuteCommand(ctx, &placeOrder{id: 2}))
+
+	records, err := store.Query(context.Background(), Filter{SubjectID: 7})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	byAggregate := map[string]Record{}
+	for _, r := range records {
+		byAggregate[r.AggregateID] = r
+	}
+	ok := byAggregate["1"]
+	require.Equal(t, "audit.placeOrder", ok.CommandName)
+	require.Equal(t, "Order", ok.AggregateType)
+	require.Equal(t, OutcomeSuccess, ok.Outcome)
+	require.Equal(t, "trace-1", ok.TraceID)
+	require.Equal(t, 1, ok.Attempts)
+	require.NotEmpty(t, ok.ID)
+
+	failed := byAggregate["2"]
+	require.Equal(t, OutcomeFailure, failed.Outcome)
+	require.Equal(t, string(errors.Validation), failed.ErrorCode)
+
+	onlyFailures, err := store.Query(context.Background(), Filter{Outcome: OutcomeFailure})
+	require.NoError(t, err)
+	require.Len(t, onlyFailures, 1)
+}
+
+func TestCommandMiddleware_RecordsCommandMessages(t *testing.T) {
+	store := NewMemoryStore(0)
+	mw := NewCommandMiddleware(store, nil)
+
+	cmd := command.NewCommand("cmd-1", "PlaceOrder", "42", "Order", nil).
+		WithMetadata(contextx.MetadataOperatorKey, "alice")
+	boom := errors.NewCode(errors.Conflict, "duplicate")
+	err := mw.Handle(principalContext(t, 7), cmd, func(context.Context, messaging.IMessage) error { return boom })
+	require.ErrorIs(t, err, boom)
+
+	// 非命令消息不记录。
+	evt := &messaging.Message{ID: "evt-1", Kind: messaging.KindEvent, Type: "OrderPlaced"}
+	require.NoError(t, mw.Handle(context.Background(), evt, func(context.Context, messaging.IMessage) error { return nil }))
+
+	records, err := store.Query(context.Background(), Filter{})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	r := records[0]
+	require.Equal(t, "PlaceOrder", r.CommandName)
+	require.Equal(t, "cmd-1", r.CommandID)
+	require.Equal(t, "42", r.AggregateID)
+	require.Equal(t, "alice", r.Operator, "operator falls back to command metadata")
+	require.Equal(t, "alice", r.Actor())
+	require.Equal(t, int64(7), r.SubjectID)
+	require.Equal(t, string(errors.Conflict), r.ErrorCode)
+}
+
+func TestMemoryStore_QueryOrdersAndPaginates(t *testing.T) {
+	store := NewMemoryStore(3)
+	base := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 4; i++ {
+		require.NoError(t, store.Record(context.Background(), Record{
+			ID:          string(rune('a' + i)),
+			CommandName: "PlaceOrder",
+			StartedAt:   base.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+
+	records, err := store.Query(context.Background(), Filter{Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{"d", "c"}, []string{records[0].ID, records[1].ID})
+
+	records, err = store.Query(context.Background(), Filter{Offset: 2})
+	require.NoError(t, err)
+	require.Len(t, records, 1, "capacity evicts the oldest record")
+	require.Equal(t, "b", records[0].ID)
+
+	records, err = store.Query(context.Background(), Filter{From: base.Add(2 * time.Minute), To: base.Add(3 * time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, "c", records[0].ID)
+}
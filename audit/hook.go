@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	appeventsourced "gochen/app/eventsourced"
+	deventsourced "gochen/domain/eventsourced"
+)
+
+type commandStartKey struct{}
+
+// ICommandNamer 允许命令自定义审计中的命令名；未实现时使用命令的 Go 类型名（去掉指针前缀）。
+type ICommandNamer interface {
+	CommandName() string
+}
+
+// CommandHook 把 EventSourcedService 的每次命令执行记录为一条审计记录。
+//
+// 同时实现 IEventSourcedCommandStartHook 与 IEventSourcedCommandFinalizeHook：
+// 开始时打点，结束时记录一次（并发重试的多次尝试合并为一条，Attempts 记录尝试次数）。
+type CommandHook[T deventsourced.IEventSourcedAggregate[ID], ID comparable] struct {
+	recorder *recorder
+}
+
+var (
+	_ appeventsourced.IEventSourcedCommandHook[deventsourced.IEventSourcedAggregate[int64], int64]         = (*CommandHook[deventsourced.IEventSourcedAggregate[int64], int64])(nil)
+	_ appeventsourced.IEventSourcedCommandFinalizeHook[deventsourced.IEventSourcedAggregate[int64], int64] = (*CommandHook[deventsourced.IEventSourcedAggregate[int64], int64])(nil)
+	_ appeventsourced.IEventSourcedCommandStartHook[int64]                                                 = (*CommandHook[deventsourced.IEventSourcedAggregate[int64], int64])(nil)
+)
+
+// NewCommandHook 创建命令审计钩子，通过 EventSourcedServiceOptions.CommandHooks 注册。
+func NewCommandHook[T deventsourced.IEventSourcedAggregate[ID], ID comparable](sink ISink, opts *Options) *CommandHook[T, ID] {
+	return &CommandHook[T, ID]{recorder: newRecorder(sink, opts)}
+}
+
+// BeforeStart 记录命令开始时间。
+func (h *CommandHook[T, ID]) BeforeStart(ctx context.Context, _ appeventsourced.IEventSourcedCommand[ID]) context.Context {
+	return context.WithValue(ctx, commandStartKey{}, h.recorder.now())
+}
+
+// BeforeExecute 不做处理。
+func (h *CommandHook[T, ID]) BeforeExecute(context.Context, appeventsourced.IEventSourcedCommand[ID], T) error {
+	return nil
+}
+
+// AfterExecute 不做处理；审计只在 AfterFinalize 记录一次。
+func (h *CommandHook[T, ID]) AfterExecute(context.Context, appeventsourced.IEventSourcedCommand[ID], T, error) error {
+	return nil
+}
+
+// AfterFinalize 写入审计记录。
+func (h *CommandHook[T, ID]) AfterFinalize(ctx context.Context, cmd appeventsourced.IEventSourcedCommand[ID], agg T, err error, attempts int) error {
+	now := h.recorder.now()
+	startedAt, ok := ctx.Value(commandStartKey{}).(time.Time)
+	if !ok {
+		startedAt = now
+	}
+	record := NewRecord(ctx, commandName(cmd), startedAt, now.Sub(startedAt), err)
+	record.AggregateID = fmt.Sprint(cmd.AggregateID())
+	if !isNilAggregate(agg) {
+		record.AggregateType = agg.GetAggregateType()
+	}
+	record.Attempts = attempts
+	h.recorder.write(ctx, record)
+	return nil
+}
+
+// commandName 返回命令的审计名称。
+func commandName(cmd any) string {
+	if namer, ok := cmd.(ICommandNamer); ok {
+		if name := strings.TrimSpace(namer.CommandName()); name != "" {
+			return name
+		}
+	}
+	return strings.TrimPrefix(reflect.TypeOf(cmd).String(), "*")
+}
+
+// isNilAggregate 判断聚合是否为零值（加载失败时 hook 收到的是 T 的零值）。
+func isNilAggregate(agg any) bool {
+	if agg == nil {
+		return true
+	}
+	v := reflect.ValueOf(agg)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
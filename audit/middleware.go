@@ -0,0 +1,69 @@
+package audit
+
+import (
+	"context"
+
+	"gochen/contextx"
+	"gochen/messaging"
+	"gochen/messaging/command"
+)
+
+// CommandMiddleware 是记录命令审计的 messaging 中间件。
+//
+// 可挂到 CommandExecutor（执行侧，推荐：上下文已从 metadata 派生）或 CommandBus（投递侧）。
+// 上下文缺失 operator/tenant/trace 时回退读取命令 metadata。非命令消息直接透传。
+type CommandMiddleware struct {
+	recorder *recorder
+}
+
+var _ messaging.IMiddleware = (*CommandMiddleware)(nil)
+
+// NewCommandMiddleware 创建命令审计中间件。
+func NewCommandMiddleware(sink ISink, opts *Options) *CommandMiddleware {
+	return &CommandMiddleware{recorder: newRecorder(sink, opts)}
+}
+
+// Handle 执行后续链路并记录结果。
+func (m *CommandMiddleware) Handle(ctx context.Context, message messaging.IMessage, next messaging.HandlerFunc) error {
+	if m == nil || message == nil || message.GetKind() != messaging.KindCommand {
+		return next(ctx, message)
+	}
+	cmd, ok := message.(*command.Command)
+	if !ok {
+		return next(ctx, message)
+	}
+
+	startedAt := m.recorder.now()
+	err := next(ctx, message)
+	record := NewRecord(ctx, cmd.GetCommandType(), startedAt, m.recorder.now().Sub(startedAt), err)
+	record.CommandID = cmd.GetID()
+	record.AggregateType = cmd.GetAggregateType()
+	record.AggregateID = cmd.GetAggregateID()
+	fillFromMetadata(&record, cmd.GetMetadata())
+	m.recorder.write(ctx, record)
+	return err
+}
+
+// Name 返回中间件名称。
+func (m *CommandMiddleware) Name() string {
+	return "CommandAudit"
+}
+
+// fillFromMetadata 用命令 metadata 补齐上下文中缺失的关联字段。
+func fillFromMetadata(record *Record, md *messaging.Metadata) {
+	if md == nil {
+		return
+	}
+	fill := func(target *string, key string) {
+		if *target != "" {
+			return
+		}
+		if v, ok := md.Get(key); ok {
+			*target = v
+		}
+	}
+	fill(&record.Operator, contextx.MetadataOperatorKey)
+	fill(&record.TenantID, contextx.MetadataTenantKey)
+	fill(&record.TraceID, contextx.MetadataTraceKey)
+	fill(&record.RequestID, contextx.MetadataRequestIDKey)
+}
@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"gochen/ident"
+	"gochen/logging"
+)
+
+// Options 定义审计接入点（钩子、中间件）的公共配置。
+type Options struct {
+	// Logger 记录审计写入失败；为 nil 时使用组件 logger。
+	Logger logging.ILogger
+
+	// IDGenerator 生成记录 ID；为 nil 时使用 ident.DefaultStringGenerator（UUID v4）。
+	IDGenerator ident.IGenerator[string]
+
+	// Now 返回当前时间；为 nil 时使用 time.Now。
+	Now func() time.Time
+}
+
+// recorder 负责补齐记录 ID 并写入 sink；写入失败只记录告警，不影响命令结果。
+type recorder struct {
+	sink   ISink
+	logger logging.ILogger
+	ids    ident.IGenerator[string]
+	now    func() time.Time
+}
+
+func newRecorder(sink ISink, opts *Options) *recorder {
+	r := &recorder{sink: sink}
+	if opts != nil {
+		r.logger = opts.Logger
+		r.ids = opts.IDGenerator
+		r.now = opts.Now
+	}
+	if r.logger == nil {
+		r.logger = logging.ComponentLogger("audit")
+	}
+	if r.ids == nil {
+		r.ids = ident.DefaultStringGenerator()
+	}
+	if r.now == nil {
+		r.now = time.Now
+	}
+	return r
+}
+
+// write 写入一条记录。
+func (r *recorder) write(ctx context.Context, record Record) {
+	if r.sink == nil {
+		return
+	}
+	if record.ID == "" {
+		id, err := r.ids.Next()
+		if err != nil {
+			r.logger.Warn(ctx, "generate audit record id failed",
+				logging.Error(err),
+				logging.String("command", record.CommandName))
+			return
+		}
+		record.ID = id
+	}
+	if err := r.sink.Record(ctx, record); err != nil {
+		r.logger.Warn(ctx, "write audit record failed",
+			logging.Error(err),
+			logging.String("command", record.CommandName),
+			logging.String("aggregate_id", record.AggregateID))
+	}
+}
@@ -0,0 +1,137 @@
+package audit
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"gochen/errors"
+	"gochen/logging"
+)
+
+// DefaultMemoryCapacity 是 MemoryStore 默认保留的最大记录数。
+const DefaultMemoryCapacity = 10000
+
+// MemoryStore 是基于内存的审计存储，适用于测试、示例与单进程开发环境。
+//
+// 超过容量时淘汰最早写入的记录。
+type MemoryStore struct {
+	mu       sync.RWMutex
+	records  []Record
+	capacity int
+}
+
+// NewMemoryStore 创建内存审计存储；capacity<=0 时使用 DefaultMemoryCapacity。
+func NewMemoryStore(capacity int) *MemoryStore {
+	if capacity <= 0 {
+		capacity = DefaultMemoryCapacity
+	}
+	return &MemoryStore{capacity: capacity}
+}
+
+// Record 保存一条审计记录。
+func (s *MemoryStore) Record(_ context.Context, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.records) >= s.capacity {
+		s.records = slices.Delete(s.records, 0, len(s.records)-s.capacity+1)
+	}
+	s.records = append(s.records, record)
+	return nil
+}
+
+// Query 按过滤条件查询，结果按 StartedAt 倒序（同一时刻按写入顺序倒序）。
+func (s *MemoryStore) Query(_ context.Context, filter Filter) ([]Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	matched := make([]Record, 0)
+	for i := len(s.records) - 1; i >= 0; i-- {
+		if filter.Matches(s.records[i]) {
+			matched = append(matched, s.records[i])
+		}
+	}
+	slices.SortStableFunc(matched, func(a, b Record) int { return b.StartedAt.Compare(a.StartedAt) })
+	if filter.Offset >= len(matched) {
+		return []Record{}, nil
+	}
+	matched = matched[max(filter.Offset, 0):]
+	if limit := filter.NormalizedLimit(); len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched, nil
+}
+
+// PruneBefore 删除 StartedAt 早于 cutoff 的记录并返回删除条数（实现 retention.ITarget）。
+func (s *MemoryStore) PruneBefore(_ context.Context, cutoff time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	before := len(s.records)
+	s.records = slices.DeleteFunc(s.records, func(r Record) bool { return r.StartedAt.Before(cutoff) })
+	return int64(before - len(s.records)), nil
+}
+
+// PruneKeepLast 只保留最近写入的 keep 条记录并返回删除条数（实现 retention.ICountTarget）。
+func (s *MemoryStore) PruneKeepLast(_ context.Context, keep int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	excess := int64(len(s.records)) - max(keep, 0)
+	if excess <= 0 {
+		return 0, nil
+	}
+	s.records = slices.Delete(s.records, 0, int(excess))
+	return excess, nil
+}
+
+// LoggerSink 把审计记录输出为结构化日志，适合接入已有的日志采集链路。
+type LoggerSink struct {
+	logger logging.ILogger
+}
+
+// NewLoggerSink 创建日志审计 sink；logger 为 nil 时使用组件 logger。
+func NewLoggerSink(logger logging.ILogger) *LoggerSink {
+	if logger == nil {
+		logger = logging.ComponentLogger("audit")
+	}
+	return &LoggerSink{logger: logger}
+}
+
+// Record 以 Info 级别输出一条审计日志。
+func (s *LoggerSink) Record(ctx context.Context, record Record) error {
+	fields := []logging.Field{
+		logging.String("audit_id", record.ID),
+		logging.String("command", record.CommandName),
+		logging.String("aggregate_type", record.AggregateType),
+		logging.String("aggregate_id", record.AggregateID),
+		logging.String("actor", record.Actor()),
+		logging.String("outcome", string(record.Outcome)),
+		logging.Duration("duration", record.Duration),
+	}
+	if record.TraceID != "" {
+		fields = append(fields, logging.String("trace_id", record.TraceID))
+	}
+	if record.ErrorCode != "" {
+		fields = append(fields,
+			logging.String("error_code", record.ErrorCode),
+			logging.String("error_message", record.ErrorMessage))
+	}
+	s.logger.Info(ctx, "command audit", fields...)
+	return nil
+}
+
+// MultiSink 依次写入多个 sink；某个 sink 失败不影响后续 sink，最终合并返回错误。
+type MultiSink []ISink
+
+// Record 写入全部 sink。
+func (m MultiSink) Record(ctx context.Context, record Record) error {
+	var errs []error
+	for _, sink := range m {
+		if sink == nil {
+			continue
+		}
+		if err := sink.Record(ctx, record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,201 @@
+// Package sqlstore 提供基于 db.IDatabase 的命令审计存储。
+//
+// 表结构（列名固定，表名可配置，默认 command_audit_logs）：
+//
+//	id TEXT PRIMARY KEY,
+//	command_name TEXT NOT NULL, command_id TEXT NOT NULL,
+//	aggregate_type TEXT NOT NULL, aggregate_id TEXT NOT NULL,
+//	subject_id BIGINT NOT NULL, operator TEXT NOT NULL, tenant_id TEXT NOT NULL,
+//	trace_id TEXT NOT NULL, request_id TEXT NOT NULL,
+//	outcome TEXT NOT NULL, error_code TEXT NOT NULL, error_message TEXT NOT NULL,
+//	attempts INTEGER NOT NULL, started_at TIMESTAMP NOT NULL, duration_ms BIGINT NOT NULL
+//
+// 建议按合规查询维度建立索引：(subject_id, started_at)、(aggregate_type, aggregate_id, started_at)、(trace_id)。
+// 建表由业务迁移负责，本包不自动执行 DDL。
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gochen/audit"
+	"gochen/db"
+	"gochen/db/dialect"
+	"gochen/db/sql/safeident"
+	"gochen/errors"
+)
+
+// DefaultTableName 是默认审计表名。
+const DefaultTableName = "command_audit_logs"
+
+const columns = `id, command_name, command_id, aggregate_type, aggregate_id, subject_id, operator, tenant_id, trace_id, request_id, outcome, error_code, error_message, attempts, started_at, duration_ms`
+
+// Store 是 audit.IStore 的 SQL 实现。
+type Store struct {
+	db        db.IDatabase
+	tableName string
+}
+
+var _ audit.IStore = (*Store)(nil)
+
+// NewStore 创建 SQL 审计存储；tableName 为空时使用 DefaultTableName。
+func NewStore(database db.IDatabase, tableName string) (*Store, error) {
+	if database == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "audit database cannot be nil")
+	}
+	tableName = strings.TrimSpace(tableName)
+	if tableName == "" {
+		tableName = DefaultTableName
+	}
+	if !safeident.IsSafeIdentifier(tableName) {
+		return nil, errors.NewCode(errors.InvalidInput, fmt.Sprintf("invalid SQL table name: %s", tableName))
+	}
+	return &Store{
+		db:        database,
+		tableName: dialect.FromDatabase(database).QuoteIdentifier(tableName),
+	}, nil
+}
+
+// Record 写入一条审计记录。
+func (s *Store) Record(ctx context.Context, record audit.Record) error {
+	if strings.TrimSpace(record.ID) == "" {
+		return errors.NewCode(errors.InvalidInput, "audit record id cannot be empty")
+	}
+	if record.StartedAt.IsZero() {
+		record.StartedAt = time.Now()
+	}
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, s.tableName, columns)
+	if _, err := s.db.Exec(ctx, query,
+		record.ID,
+		record.CommandName,
+		record.CommandID,
+		record.AggregateType,
+		record.AggregateID,
+		record.SubjectID,
+		record.Operator,
+		record.TenantID,
+		record.TraceID,
+		record.RequestID,
+		string(record.Outcome),
+		record.ErrorCode,
+		record.ErrorMessage,
+		record.Attempts,
+		record.StartedAt.UTC(),
+		record.Duration.Milliseconds(),
+	); err != nil {
+		return errors.Wrap(err, errors.Database, "insert audit record failed").WithContext("record_id", record.ID)
+	}
+	return nil
+}
+
+// Query 按过滤条件查询审计记录，结果按 started_at 倒序。
+func (s *Store) Query(ctx context.Context, filter audit.Filter) ([]audit.Record, error) {
+	where, args := buildWhere(filter)
+	query := fmt.Sprintf(`SELECT %s FROM %s%s ORDER BY started_at DESC, id DESC LIMIT ?`, columns, s.tableName, where)
+	args = append(args, filter.NormalizedLimit())
+	if filter.Offset > 0 {
+		query += ` OFFSET ?`
+		args = append(args, filter.Offset)
+	}
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Database, "query audit records failed")
+	}
+	defer rows.Close()
+
+	result := make([]audit.Record, 0)
+	for rows.Next() {
+		var (
+			record     audit.Record
+			outcome    string
+			durationMs int64
+		)
+		if err := rows.Scan(
+			&record.ID,
+			&record.CommandName,
+			&record.CommandID,
+			&record.AggregateType,
+			&record.AggregateID,
+			&record.SubjectID,
+			&record.Operator,
+			&record.TenantID,
+			&record.TraceID,
+			&record.RequestID,
+			&outcome,
+			&record.ErrorCode,
+			&record.ErrorMessage,
+			&record.Attempts,
+			&record.StartedAt,
+			&durationMs,
+		); err != nil {
+			return nil, errors.Wrap(err, errors.Database, "scan audit record failed")
+		}
+		record.Outcome = audit.Outcome(outcome)
+		record.Duration = time.Duration(durationMs) * time.Millisecond
+		result = append(result, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, errors.Database, "iterate audit records failed")
+	}
+	return result, nil
+}
+
+// DeleteBefore 删除 started_at 早于 cutoff 的记录，用于按保留期清理。
+func (s *Store) DeleteBefore(ctx context.Context, cutoff time.Time) error {
+	_, err := s.PruneBefore(ctx, cutoff)
+	return err
+}
+
+// PruneBefore 删除 started_at 早于 cutoff 的记录并返回删除条数（实现 retention.ITarget）。
+func (s *Store) PruneBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE started_at < ?`, s.tableName)
+	res, err := s.db.Exec(ctx, query, cutoff.UTC())
+	if err != nil {
+		return 0, errors.Wrap(err, errors.Database, "delete audit records failed")
+	}
+	deleted, _ := res.RowsAffected()
+	return deleted, nil
+}
+
+// buildWhere 把过滤条件转换为参数化 WHERE 子句。
+func buildWhere(filter audit.Filter) (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	add := func(cond string, arg any) {
+		conds = append(conds, cond)
+		args = append(args, arg)
+	}
+	if filter.SubjectID != 0 {
+		add("subject_id = ?", filter.SubjectID)
+	}
+	for _, f := range []struct {
+		column string
+		value  string
+	}{
+		{"operator", filter.Operator},
+		{"tenant_id", filter.TenantID},
+		{"aggregate_type", filter.AggregateType},
+		{"aggregate_id", filter.AggregateID},
+		{"command_name", filter.CommandName},
+		{"trace_id", filter.TraceID},
+		{"outcome", string(filter.Outcome)},
+	} {
+		if f.value != "" {
+			add(f.column+" = ?", f.value)
+		}
+	}
+	if !filter.From.IsZero() {
+		add("started_at >= ?", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		add("started_at < ?", filter.To.UTC())
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
@@ -0,0 +1,96 @@
+package sqlstore_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"gochen/audit"
+	auditstore "gochen/audit/sqlstore"
+	"gochen/db"
+	"gochen/db/sql/stdsql"
+	"gochen/errors"
+)
+
+func TestStore_RecordQueryAndDelete(t *testing.T) {
+	database := setupAuditSQLDB(t)
+	store, err := auditstore.NewStore(database, "")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, store.Record(ctx, audit.Record{
+		ID: "a-1", CommandName: "PlaceOrder", AggregateType: "Order", AggregateID: "1",
+		SubjectID: 7, Outcome: audit.OutcomeSuccess, Attempts: 1,
+		StartedAt: base, Duration: 15 * time.Millisecond,
+	}))
+	require.NoError(t, store.Record(ctx, audit.Record{
+		ID: "a-2", CommandName: "CancelOrder", AggregateType: "Order", AggregateID: "1",
+		SubjectID: 8, Outcome: audit.OutcomeFailure, ErrorCode: "FORBIDDEN", ErrorMessage: "denied",
+		StartedAt: base.Add(time.Minute),
+	}))
+
+	records, err := store.Query(ctx, audit.Filter{AggregateType: "Order", AggregateID: "1"})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, "a-2", records[0].ID, "newest first")
+	require.Equal(t, 15*time.Millisecond, records[1].Duration)
+
+	records, err = store.Query(ctx, audit.Filter{SubjectID: 8, Outcome: audit.OutcomeFailure})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, "FORBIDDEN", records[0].ErrorCode)
+
+	records, err = store.Query(ctx, audit.Filter{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, "a-1", records[0].ID)
+
+	require.NoError(t, store.DeleteBefore(ctx, base.Add(30*time.Second)))
+	records, err = store.Query(ctx, audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, "a-2", records[0].ID)
+}
+
+func TestStore_RejectsUnsafeTableName(t *testing.T) {
+	database := setupAuditSQLDB(t)
+	store, err := auditstore.NewStore(database, "audit; DROP TABLE audit")
+	require.Error(t, err)
+	require.Nil(t, store)
+	require.True(t, errors.Is(err, errors.InvalidInput))
+}
+
+func setupAuditSQLDB(t *testing.T) db.IDatabase {
+	t.Helper()
+
+	database, err := stdsql.New(db.DBConfig{Driver: "sqlite", Database: ":memory:"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = database.Close() })
+
+	_, err = database.Exec(context.Background(), `
+		CREATE TABLE command_audit_logs (
+			id TEXT PRIMARY KEY,
+			command_name TEXT NOT NULL,
+			command_id TEXT NOT NULL,
+			aggregate_type TEXT NOT NULL,
+			aggregate_id TEXT NOT NULL,
+			subject_id INTEGER NOT NULL,
+			operator TEXT NOT NULL,
+			tenant_id TEXT NOT NULL,
+			trace_id TEXT NOT NULL,
+			request_id TEXT NOT NULL,
+			outcome TEXT NOT NULL,
+			error_code TEXT NOT NULL,
+			error_message TEXT NOT NULL,
+			attempts INTEGER NOT NULL,
+			started_at DATETIME NOT NULL,
+			duration_ms INTEGER NOT NULL
+		);
+	`)
+	require.NoError(t, err)
+	return database
+}
@@ -0,0 +1,84 @@
+package authhttp
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	auth "gochen/auth"
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+// TokenResolver 把请求携带的 bearer token 解析为主体。
+//
+// token 无效时应返回 Unauthorized 错误；其他错误按原样透传给 httpx 错误处理链。
+type TokenResolver func(ctx context.Context, token string) (auth.Principal, error)
+
+// BearerAuthMiddleware 构造认证中间件：解析 `Authorization: Bearer <token>`，
+// 并通过 auth.WithPrincipal 把主体绑定到请求上下文。
+//
+// 说明：
+// - 该中间件只负责“你是谁”，授权判断仍由 PermissionMiddleware 完成；
+// - 同进程执行的命令处理器可通过 auth.PrincipalFromContext 读取主体，
+//   跨传输边界时通过 metadata 中的 operator（主体 ID）追溯操作人。
+func BearerAuthMiddleware(resolve TokenResolver) httpx.Middleware {
+	if resolve == nil {
+		return func(httpx.IContext, func() error) error {
+			return errors.NewCode(errors.Internal, "token resolver is nil")
+		}
+	}
+	return func(ctx httpx.IContext, next func() error) error {
+		token, ok := bearerToken(ctx.Header("Authorization"))
+		if !ok {
+			return errors.NewCode(errors.Unauthorized, "bearer token is required")
+		}
+		reqCtx := ctx.RequestContext()
+		if reqCtx == nil {
+			return errors.NewCode(errors.Internal, "request context is nil")
+		}
+		principal, err := resolve(reqCtx, token)
+		if err != nil {
+			return err
+		}
+		bound, err := auth.WithPrincipal(reqCtx, principal)
+		if err != nil {
+			return err
+		}
+		// 未显式设置操作人时以主体 ID 作为 operator，随命令/事件 metadata 传播供审计使用。
+		if auth.Operator(bound) == "" && principal.SubjectID > 0 {
+			bound, err = auth.WithOperator(bound, strconv.FormatInt(principal.SubjectID, 10))
+			if err != nil {
+				return err
+			}
+		}
+		ctx.SetContext(reqCtx.WithContext(bound))
+		return next()
+	}
+}
+
+// StaticTokenResolver 基于固定 token 表解析主体，适用于示例、测试与内部服务间调用。
+func StaticTokenResolver(tokens map[string]auth.Principal) TokenResolver {
+	table := make(map[string]auth.Principal, len(tokens))
+	for token, principal := range tokens {
+		if token = strings.TrimSpace(token); token != "" {
+			table[token] = principal.Clone()
+		}
+	}
+	return func(_ context.Context, token string) (auth.Principal, error) {
+		principal, ok := table[token]
+		if !ok {
+			return auth.Principal{}, errors.NewCode(errors.Unauthorized, "invalid bearer token")
+		}
+		return principal.Clone(), nil
+	}
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,52 @@
+package authhttp
+
+import (
+	"context"
+	"testing"
+
+	auth "gochen/auth"
+	"gochen/errors"
+)
+
+func TestBearerAuthMiddlewareBindsPrincipal(t *testing.T) {
+	mw := BearerAuthMiddleware(StaticTokenResolver(map[string]auth.Principal{
+		"secret": {SubjectID: 7, Permissions: []string{"api:orders:read"}},
+	}))
+
+	ctx := &testContext{
+		reqCtx:  requestContext{Context: context.Background()},
+		headers: map[string]string{"Authorization": "Bearer secret"},
+	}
+	called := false
+	err := mw(ctx, func() error {
+		called = true
+		principal, ok := auth.PrincipalFromContext(ctx.RequestContext())
+		if !ok || principal.SubjectID != 7 {
+			t.Fatalf("expected principal 7, got %+v ok=%v", principal, ok)
+		}
+		if !HasPermission(ctx.RequestContext(), "api:orders:read") {
+			t.Fatal("expected resolved permissions to be bound")
+		}
+		return nil
+	})
+	if err != nil || !called {
+		t.Fatalf("expected success, got err=%v called=%v", err, called)
+	}
+}
+
+func TestBearerAuthMiddlewareRejectsMissingOrUnknownToken(t *testing.T) {
+	mw := BearerAuthMiddleware(StaticTokenResolver(map[string]auth.Principal{"secret": {SubjectID: 7}}))
+	for _, header := range []string{"", "Basic secret", "Bearer ", "Bearer other"} {
+		ctx := &testContext{
+			reqCtx:  requestContext{Context: context.Background()},
+			headers: map[string]string{"Authorization": header},
+		}
+		err := mw(ctx, func() error {
+			t.Fatalf("handler must not run for header %q", header)
+			return nil
+		})
+		if !errors.Is(err, errors.Unauthorized) {
+			t.Fatalf("expected unauthorized for header %q, got %v", header, err)
+		}
+	}
+}
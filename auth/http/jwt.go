@@ -0,0 +1,397 @@
+package authhttp
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"hash"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	auth "gochen/auth"
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+// 支持的 JWS 签名算法（RFC 7518）。
+const (
+	JWTAlgHS256 = "HS256"
+	JWTAlgHS384 = "HS384"
+	JWTAlgHS512 = "HS512"
+	JWTAlgRS256 = "RS256"
+	JWTAlgRS384 = "RS384"
+	JWTAlgRS512 = "RS512"
+	JWTAlgES256 = "ES256"
+	JWTAlgES384 = "ES384"
+	JWTAlgES512 = "ES512"
+	JWTAlgEdDSA = "EdDSA"
+)
+
+var (
+	hmacAlgorithms      = []string{JWTAlgHS256, JWTAlgHS384, JWTAlgHS512}
+	publicKeyAlgorithms = []string{JWTAlgRS256, JWTAlgRS384, JWTAlgRS512, JWTAlgES256, JWTAlgES384, JWTAlgES512, JWTAlgEdDSA}
+	supportedAlgorithms = append(append([]string(nil), hmacAlgorithms...), publicKeyAlgorithms...)
+)
+
+// JWTClaims 是解码后的 JWT 载荷；数字以 json.Number 保留原始精度。
+type JWTClaims map[string]any
+
+// String 读取字符串 claim；不存在或类型不符时返回空串。
+func (c JWTClaims) String(name string) string {
+	switch v := c[name].(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case json.Number:
+		return v.String()
+	}
+	return ""
+}
+
+// Strings 读取字符串数组 claim；字符串值按空白切分（兼容 OAuth2 `scope` 约定）。
+func (c JWTClaims) Strings(name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				out = append(out, strings.TrimSpace(s))
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// Int64 读取整数 claim；兼容数字与数字字符串。
+func (c JWTClaims) Int64(name string) (int64, bool) {
+	raw := c.String(name)
+	if raw == "" {
+		return 0, false
+	}
+	v, err := strconv.ParseInt(raw, 10, 64)
+	return v, err == nil
+}
+
+// JWTConfig 定义 JWT 校验与主体映射配置。
+type JWTConfig struct {
+	// Secret 是 HS256/HS384/HS512 使用的共享密钥。
+	Secret []byte
+
+	// PublicKeys 是非对称算法使用的验签公钥，按 JWT header 的 `kid` 索引；
+	// token 未携带 kid 时使用键为空串的公钥。
+	//
+	// 支持 *rsa.PublicKey（RS*）、*ecdsa.PublicKey（ES*）与 ed25519.PublicKey（EdDSA）。
+	PublicKeys map[string]crypto.PublicKey
+
+	// Algorithms 是允许的签名算法；为空时按已配置的密钥推断（Secret → HS*，PublicKeys → RS*/ES*/EdDSA）。
+	//
+	// 说明：`none` 永远不被接受；公钥类型与算法不匹配时拒绝，避免算法混淆攻击。
+	Algorithms []string
+
+	// Issuer 非空时要求 `iss` 完全匹配。
+	Issuer string
+
+	// Audience 非空时要求 `aud`（字符串或数组）包含该值。
+	Audience string
+
+	// Leeway 是校验 exp/nbf/iat 时容忍的时钟偏差。
+	Leeway time.Duration
+
+	// Now 返回当前时间；为 nil 时使用 time.Now（测试可注入固定时钟）。
+	Now func() time.Time
+
+	// ClaimsMapper 把已验签的 claims 映射为主体；为 nil 时使用 DefaultJWTClaimsMapper。
+	ClaimsMapper func(claims JWTClaims) (auth.Principal, error)
+}
+
+// DefaultJWTClaimsMapper 按约定字段把 claims 映射为主体：
+//   - `sub`：SubjectID（必须为正整数）；
+//   - `tenant_id`：HomeTenantID（可选）；
+//   - `roles`：Roles（数组或空白分隔字符串）；
+//   - `permissions` 与 `scope`：合并为 Permissions。
+func DefaultJWTClaimsMapper(claims JWTClaims) (auth.Principal, error) {
+	subjectID, ok := claims.Int64("sub")
+	if !ok || subjectID <= 0 {
+		return auth.Principal{}, errors.NewCode(errors.Unauthorized, "jwt subject must be a positive integer").
+			WithContext("sub", claims.String("sub"))
+	}
+	principal := auth.Principal{SubjectID: subjectID}
+	if tenantID, ok := claims.Int64("tenant_id"); ok {
+		principal.HomeTenantID = tenantID
+	}
+	principal.Roles = claims.Strings("roles")
+	principal.Permissions = append(claims.Strings("permissions"), claims.Strings("scope")...)
+	return principal, nil
+}
+
+// jwtVerifier 持有归一化后的 JWT 校验配置。
+type jwtVerifier struct {
+	secret     []byte
+	publicKeys map[string]crypto.PublicKey
+	algorithms []string
+	issuer     string
+	audience   string
+	leeway     time.Duration
+	now        func() time.Time
+	mapper     func(JWTClaims) (auth.Principal, error)
+}
+
+// NewJWTResolver 基于 JWTConfig 构造 TokenResolver，可直接交给 BearerAuthMiddleware 使用。
+//
+// 配置不完整（未提供任何密钥、算法不受支持）时返回 InvalidInput；
+// 运行时 token 无效（格式、签名、时效、iss/aud 不符）统一返回 Unauthorized。
+func NewJWTResolver(cfg JWTConfig) (TokenResolver, error) {
+	v := &jwtVerifier{
+		secret:     cfg.Secret,
+		publicKeys: cfg.PublicKeys,
+		issuer:     strings.TrimSpace(cfg.Issuer),
+		audience:   strings.TrimSpace(cfg.Audience),
+		leeway:     cfg.Leeway,
+		now:        cfg.Now,
+		mapper:     cfg.ClaimsMapper,
+	}
+	if len(v.secret) == 0 && len(v.publicKeys) == 0 {
+		return nil, errors.NewCode(errors.InvalidInput, "JWTConfig requires Secret or PublicKeys")
+	}
+	if v.now == nil {
+		v.now = time.Now
+	}
+	if v.mapper == nil {
+		v.mapper = DefaultJWTClaimsMapper
+	}
+	if len(cfg.Algorithms) == 0 {
+		if len(v.secret) > 0 {
+			v.algorithms = append(v.algorithms, hmacAlgorithms...)
+		}
+		if len(v.publicKeys) > 0 {
+			v.algorithms = append(v.algorithms, publicKeyAlgorithms...)
+		}
+	}
+	for _, alg := range cfg.Algorithms {
+		alg = strings.TrimSpace(alg)
+		if !slices.Contains(supportedAlgorithms, alg) {
+			return nil, errors.NewCode(errors.InvalidInput, "unsupported jwt algorithm").WithContext("alg", alg)
+		}
+		v.algorithms = append(v.algorithms, alg)
+	}
+	return v.resolve, nil
+}
+
+// JWTAuthMiddleware 构造基于 JWT 的 bearer 认证中间件。
+//
+// 配置错误时返回的中间件对每个请求都报 Internal，与 BearerAuthMiddleware(nil) 的 fail-closed 语义一致。
+func JWTAuthMiddleware(cfg JWTConfig) httpx.Middleware {
+	resolve, err := NewJWTResolver(cfg)
+	if err != nil {
+		return func(httpx.IContext, func() error) error {
+			return errors.Wrap(err, errors.Internal, "invalid jwt configuration")
+		}
+	}
+	return BearerAuthMiddleware(resolve)
+}
+
+// jwtHeader 是 JOSE header 中参与校验的字段。
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// resolve 校验 token 并映射为主体。
+func (v *jwtVerifier) resolve(_ context.Context, token string) (auth.Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return auth.Principal{}, invalidToken("malformed token")
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return auth.Principal{}, invalidToken("malformed header")
+	}
+	var header jwtHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return auth.Principal{}, invalidToken("malformed header")
+	}
+	if !slices.Contains(v.algorithms, header.Alg) {
+		return auth.Principal{}, invalidToken("algorithm not allowed").WithContext("alg", header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return auth.Principal{}, invalidToken("malformed signature")
+	}
+	if err := v.verifySignature(header, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return auth.Principal{}, err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return auth.Principal{}, invalidToken("malformed payload")
+	}
+	var claims JWTClaims
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&claims); err != nil || claims == nil {
+		return auth.Principal{}, invalidToken("malformed payload")
+	}
+	if err := v.validateClaims(claims); err != nil {
+		return auth.Principal{}, err
+	}
+	return v.mapper(claims)
+}
+
+// verifySignature 按 alg 选择密钥并验签；密钥类型与算法不匹配视为无效 token。
+func (v *jwtVerifier) verifySignature(header jwtHeader, signingInput, signature []byte) error {
+	if strings.HasPrefix(header.Alg, "HS") {
+		if len(v.secret) == 0 {
+			return invalidToken("no secret configured for algorithm").WithContext("alg", header.Alg)
+		}
+		mac := hmac.New(hashFactory(header.Alg), v.secret)
+		mac.Write(signingInput)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return invalidToken("signature mismatch")
+		}
+		return nil
+	}
+
+	key, ok := v.publicKeys[header.Kid]
+	if !ok {
+		return invalidToken("unknown key id").WithContext("kid", header.Kid)
+	}
+	var valid bool
+	switch {
+	case strings.HasPrefix(header.Alg, "RS"):
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return invalidToken("key type does not match algorithm").WithContext("alg", header.Alg)
+		}
+		hashed, hashID := digest(header.Alg, signingInput)
+		valid = rsa.VerifyPKCS1v15(pub, hashID, hashed, signature) == nil
+	case strings.HasPrefix(header.Alg, "ES"):
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok || pub.Curve.Params().BitSize != ecdsaCurveBits(header.Alg) {
+			return invalidToken("key type does not match algorithm").WithContext("alg", header.Alg)
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return invalidToken("signature mismatch")
+		}
+		hashed, _ := digest(header.Alg, signingInput)
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		valid = ecdsa.Verify(pub, hashed, r, s)
+	case header.Alg == JWTAlgEdDSA:
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return invalidToken("key type does not match algorithm").WithContext("alg", header.Alg)
+		}
+		valid = ed25519.Verify(pub, signingInput, signature)
+	}
+	if !valid {
+		return invalidToken("signature mismatch")
+	}
+	return nil
+}
+
+// validateClaims 校验 exp/nbf/iat 与 iss/aud。
+func (v *jwtVerifier) validateClaims(claims JWTClaims) error {
+	now := v.now()
+	exp, ok, err := numericDate(claims, "exp")
+	if err != nil {
+		return err
+	}
+	if ok && !now.Before(exp.Add(v.leeway)) {
+		return invalidToken("token expired")
+	}
+	nbf, ok, err := numericDate(claims, "nbf")
+	if err != nil {
+		return err
+	}
+	if ok && now.Add(v.leeway).Before(nbf) {
+		return invalidToken("token not yet valid")
+	}
+	iat, ok, err := numericDate(claims, "iat")
+	if err != nil {
+		return err
+	}
+	if ok && now.Add(v.leeway).Before(iat) {
+		return invalidToken("token issued in the future")
+	}
+	if v.issuer != "" && claims.String("iss") != v.issuer {
+		return invalidToken("issuer mismatch").WithContext("iss", claims.String("iss"))
+	}
+	if v.audience != "" && !slices.Contains(claims.Strings("aud"), v.audience) {
+		return invalidToken("audience mismatch")
+	}
+	return nil
+}
+
+// numericDate 读取 NumericDate claim（允许小数秒）；存在但无法解析时视为无效 token，避免绕过时效校验。
+func numericDate(claims JWTClaims, name string) (time.Time, bool, error) {
+	raw, ok := claims[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	number, ok := raw.(json.Number)
+	if !ok {
+		return time.Time{}, false, invalidToken("malformed time claim").WithContext("claim", name)
+	}
+	seconds, err := number.Float64()
+	if err != nil {
+		return time.Time{}, false, invalidToken("malformed time claim").WithContext("claim", name)
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second))), true, nil
+}
+
+// invalidToken 构造统一的 Unauthorized 错误。
+func invalidToken(reason string) *errors.AppError {
+	return errors.NewCode(errors.Unauthorized, "invalid bearer token").WithContext("reason", reason)
+}
+
+// hashFactory 返回算法后缀对应的哈希构造函数。
+func hashFactory(alg string) func() hash.Hash {
+	switch alg[len(alg)-3:] {
+	case "384":
+		return sha512.New384
+	case "512":
+		return sha512.New
+	default:
+		return sha256.New
+	}
+}
+
+// digest 计算签名输入的摘要，并返回对应的 crypto.Hash 标识。
+func digest(alg string, input []byte) ([]byte, crypto.Hash) {
+	h := hashFactory(alg)()
+	h.Write(input)
+	switch alg[len(alg)-3:] {
+	case "384":
+		return h.Sum(nil), crypto.SHA384
+	case "512":
+		return h.Sum(nil), crypto.SHA512
+	default:
+		return h.Sum(nil), crypto.SHA256
+	}
+}
+
+// ecdsaCurveBits 返回 ES* 算法要求的曲线位数（ES512 对应 P-521）。
+func ecdsaCurveBits(alg string) int {
+	switch alg {
+	case JWTAlgES384:
+		return 384
+	case JWTAlgES512:
+		return 521
+	default:
+		return 256
+	}
+}
@@ -0,0 +1,198 @@
+package authhttp
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	auth "gochen/auth"
+	"gochen/errors"
+)
+
+var jwtTestNow = time.Unix(1_700_000_000, 0)
+
+// signJWT 使用给定签名函数生成 compact JWT。
+func signJWT(t *testing.T, header, claims map[string]any, sign func(input []byte) []byte) string {
+	t.Helper()
+	h, err := json.Marshal(header)
+	if err != nil {
+		t.Fatalf("marshal header: %v", err)
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("marshal claims: %v", err)
+	}
+	input := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	return input + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(input)))
+}
+
+func hs256(secret []byte) func([]byte) []byte {
+	return func(input []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(input)
+		return mac.Sum(nil)
+	}
+}
+
+func TestJWTResolverMapsHS256Claims(t *testing.T) {
+	secret := []byte("top-secret")
+	resolve, err := NewJWTResolver(JWTConfig{
+		Secret:   secret,
+		Issuer:   "gochen",
+		Audience: "orders",
+		Now:      func() time.Time { return jwtTestNow },
+	})
+	if err != nil {
+		t.Fatalf("new resolver: %v", err)
+	}
+	token := signJWT(t, map[string]any{"alg": "HS256", "typ": "JWT"}, map[string]any{
+		"sub":         "42",
+		"iss":         "gochen",
+		"aud":         []string{"billing", "orders"},
+		"exp":         jwtTestNow.Add(time.Minute).Unix(),
+		"roles":       []string{"admin"},
+		"permissions": []string{"api:orders:read"},
+		"scope":       "api:orders:write",
+	}, hs256(secret))
+
+	principal, err := resolve(context.Background(), token)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if principal.SubjectID != 42 || !principal.HasAnyRole("ADMIN") {
+		t.Fatalf("unexpected principal: %+v", principal)
+	}
+	if !principal.AllowsPermission("api:orders:read") || !principal.AllowsPermission("api:orders:write") {
+		t.Fatalf("expected permissions and scope to be merged: %+v", principal.Permissions)
+	}
+}
+
+func TestJWTResolverRejectsInvalidTokens(t *testing.T) {
+	secret := []byte("top-secret")
+	resolve, err := NewJWTResolver(JWTConfig{Secret: secret, Issuer: "gochen", Now: func() time.Time { return jwtTestNow }})
+	if err != nil {
+		t.Fatalf("new resolver: %v", err)
+	}
+	valid := map[string]any{"sub": 1, "iss": "gochen", "exp": jwtTestNow.Add(time.Minute).Unix()}
+	with := func(key string, value any) map[string]any {
+		claims := map[string]any{}
+		for k, v := range valid {
+			claims[k] = v
+		}
+		claims[key] = value
+		return claims
+	}
+	hs := map[string]any{"alg": "HS256"}
+
+	cases := map[string]string{
+		"malformed":        "not-a-jwt",
+		"alg none":         signJWT(t, map[string]any{"alg": "none"}, valid, func([]byte) []byte { return nil }),
+		"wrong secret":     signJWT(t, hs, valid, hs256([]byte("other"))),
+		"expired":          signJWT(t, hs, with("exp", jwtTestNow.Add(-time.Second).Unix()), hs256(secret)),
+		"not yet valid":    signJWT(t, hs, with("nbf", jwtTestNow.Add(time.Minute).Unix()), hs256(secret)),
+		"malformed exp":    signJWT(t, hs, with("exp", "tomorrow"), hs256(secret)),
+		"issuer mismatch":  signJWT(t, hs, with("iss", "other"), hs256(secret)),
+		"non numeric sub":  signJWT(t, hs, with("sub", "alice"), hs256(secret)),
+		"public key alg":   signJWT(t, map[string]any{"alg": "RS256"}, valid, hs256(secret)),
+		"tampered payload": signJWT(t, hs, valid, hs256(secret))[:10] + "x" + signJWT(t, hs, valid, hs256(secret))[11:],
+	}
+	for name, token := range cases {
+		if _, err := resolve(context.Background(), token); !errors.Is(err, errors.Unauthorized) {
+			t.Fatalf("%s: expected unauthorized, got %v", name, err)
+		}
+	}
+}
+
+func TestJWTResolverVerifiesPublicKeyAlgorithms(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ecdsa key: %v", err)
+	}
+	resolve, err := NewJWTResolver(JWTConfig{
+		PublicKeys: map[string]crypto.PublicKey{"rsa": &rsaKey.PublicKey, "ec": &ecKey.PublicKey},
+		Now:        func() time.Time { return jwtTestNow },
+	})
+	if err != nil {
+		t.Fatalf("new resolver: %v", err)
+	}
+	claims := map[string]any{"sub": 7}
+
+	rs := signJWT(t, map[string]any{"alg": "RS256", "kid": "rsa"}, claims, func(input []byte) []byte {
+		sum := sha256.Sum256(input)
+		sig, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, sum[:])
+		if err != nil {
+			t.Fatalf("sign rs256: %v", err)
+		}
+		return sig
+	})
+	es := signJWT(t, map[string]any{"alg": "ES256", "kid": "ec"}, claims, func(input []byte) []byte {
+		sum := sha256.Sum256(input)
+		r, s, err := ecdsa.Sign(rand.Reader, ecKey, sum[:])
+		if err != nil {
+			t.Fatalf("sign es256: %v", err)
+		}
+		sig := make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+		return sig
+	})
+	for name, token := range map[string]string{"RS256": rs, "ES256": es} {
+		principal, err := resolve(context.Background(), token)
+		if err != nil || principal.SubjectID != 7 {
+			t.Fatalf("%s: expected subject 7, got %+v err=%v", name, principal, err)
+		}
+	}
+
+	// kid 指向 EC 公钥但 alg 声明为 RS256：密钥类型不匹配必须拒绝。
+	confused := signJWT(t, map[string]any{"alg": "RS256", "kid": "ec"}, claims, func([]byte) []byte { return []byte("sig") })
+	if _, err := resolve(context.Background(), confused); !errors.Is(err, errors.Unauthorized) {
+		t.Fatalf("expected unauthorized for key confusion, got %v", err)
+	}
+}
+
+func TestJWTAuthMiddlewareBindsPrincipalAndOperator(t *testing.T) {
+	secret := []byte("top-secret")
+	token := signJWT(t, map[string]any{"alg": "HS256"}, map[string]any{"sub": "9", "roles": "editor viewer"}, hs256(secret))
+	mw := JWTAuthMiddleware(JWTConfig{Secret: secret})
+
+	ctx := &testContext{
+		reqCtx:  requestContext{Context: context.Background()},
+		headers: map[string]string{"Authorization": "Bearer " + token},
+	}
+	called := false
+	err := mw(ctx, func() error {
+		called = true
+		principal, ok := auth.PrincipalFromContext(ctx.RequestContext())
+		if !ok || principal.SubjectID != 9 || !HasAnyRole(ctx.RequestContext(), "viewer") {
+			t.Fatalf("unexpected principal: %+v ok=%v", principal, ok)
+		}
+		if op := auth.Operator(ctx.RequestContext()); op != "9" {
+			t.Fatalf("expected operator 9, got %q", op)
+		}
+		return nil
+	})
+	if err != nil || !called {
+		t.Fatalf("expected success, got err=%v called=%v", err, called)
+	}
+
+	if _, err := NewJWTResolver(JWTConfig{}); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected invalid input for empty config, got %v", err)
+	}
+	broken := JWTAuthMiddleware(JWTConfig{Secret: secret, Algorithms: []string{"none"}})
+	if err := broken(ctx, func() error { return nil }); !errors.Is(err, errors.Internal) {
+		t.Fatalf("expected internal for invalid config, got %v", err)
+	}
+}
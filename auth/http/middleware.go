@@ -39,18 +39,7 @@ func HasAnyRole(ctx httpx.IRequestContext, required ...string) bool {
 	if !ok {
 		return false
 	}
-	for _, need := range required {
-		need = strings.TrimSpace(need)
-		if need == "" {
-			continue
-		}
-		for _, role := range principal.Roles {
-			if strings.EqualFold(strings.TrimSpace(role), need) {
-				return true
-			}
-		}
-	}
-	return false
+	return principal.HasAnyRole(required...)
 }
 
 // HasPermission 适配 `httpx.PermissionChecker` 对当前 HTTP 上下文做权限判断。
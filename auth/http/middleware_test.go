@@ -35,12 +35,13 @@ func (c requestContext) WithDeadline(deadline time.Time) (httpx.IRequestContext,
 func (c requestContext) Clone() httpx.IRequestContext { return requestContext{Context: c.Context} }
 
 type testContext struct {
-	reqCtx httpx.IRequestContext
+	reqCtx  httpx.IRequestContext
+	headers map[string]string
 }
 
 func (c *testContext) Method() string                        { return "GET" }
 func (c *testContext) Path() string                          { return "/" }
-func (c *testContext) Header(string) string                  { return "" }
+func (c *testContext) Header(key string) string              { return c.headers[key] }
 func (c *testContext) Query(string) string                   { return "" }
 func (c *testContext) Param(string) string                   { return "" }
 func (c *testContext) QueryParams() url.Values               { return nil }
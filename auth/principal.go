@@ -54,6 +54,25 @@ func (p Principal) HasPermission(permission string) bool {
 	return ok
 }
 
+// HasAnyRole 判断主体是否命中任一角色（大小写不敏感）；required 为空时视为满足。
+func (p Principal) HasAnyRole(required ...string) bool {
+	if len(required) == 0 {
+		return true
+	}
+	for _, need := range required {
+		need = strings.TrimSpace(need)
+		if need == "" {
+			continue
+		}
+		for _, role := range p.Roles {
+			if strings.EqualFold(strings.TrimSpace(role), need) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // AllowsPermission 判断主体是否满足某个权限要求。
 //
 // 约定：
@@ -0,0 +1,413 @@
+// Package jsonpatch 实现 JSON Merge Patch（RFC 7386）与 JSON Patch（RFC 6902）。
+//
+// 文档以 encoding/json 解码后的通用值表示（map[string]any、[]any、json.Number、string、bool、nil）；
+// 推荐使用 Decode 解码，以 json.Number 保留数字精度。
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"gochen/errors"
+)
+
+// Decode 把 JSON 文本解码为通用值（数字保留为 json.Number）。
+func Decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, "invalid JSON document")
+	}
+	if decoder.More() {
+		return nil, errors.NewCode(errors.InvalidInput, "invalid JSON document: trailing data")
+	}
+	return value, nil
+}
+
+// Merge 按 RFC 7386 把 patch 合并到 doc，返回合并结果；doc 与 patch 均不会被修改。
+//
+// 说明：patch 为对象时逐字段合并（null 表示删除字段），否则整体替换 doc。
+func Merge(doc, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return clone(patch)
+	}
+	target, ok := doc.(map[string]any)
+	if !ok {
+		target = map[string]any{}
+	}
+	result := make(map[string]any, len(target)+len(patchObj))
+	for key, value := range target {
+		result[key] = value
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(result, key)
+			continue
+		}
+		result[key] = Merge(result[key], value)
+	}
+	return result
+}
+
+// Operation 是 JSON Patch 的单个操作。
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Patch 是 RFC 6902 JSON Patch 文档（操作按顺序执行）。
+type Patch []Operation
+
+// DecodePatch 解析 JSON Patch 文档，并校验操作类型与必需字段。
+func DecodePatch(data []byte) (Patch, error) {
+	var patch Patch
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patch); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, "invalid JSON Patch document")
+	}
+	for i, op := range patch {
+		switch op.Op {
+		case "add", "replace", "test":
+			if len(op.Value) == 0 {
+				return nil, errors.NewCode(errors.InvalidInput, "JSON Patch operation requires value").
+					WithContext("index", i).WithContext("op", op.Op)
+			}
+		case "move", "copy":
+			if _, err := ParsePointer(op.From); err != nil {
+				return nil, err
+			}
+		case "remove":
+		default:
+			return nil, errors.NewCode(errors.InvalidInput, "unsupported JSON Patch operation").
+				WithContext("index", i).WithContext("op", op.Op)
+		}
+		if _, err := ParsePointer(op.Path); err != nil {
+			return nil, err
+		}
+	}
+	return patch, nil
+}
+
+// Apply 在 doc 的副本上依次执行操作并返回结果；任一操作失败时整体失败，doc 不会被修改。
+//
+// 错误约定：文档或路径不合法返回 errors.InvalidInput；test 操作不满足返回 errors.Conflict。
+func (p Patch) Apply(doc any) (any, error) {
+	doc = clone(doc)
+	for i, op := range p {
+		var err error
+		doc, err = applyOperation(doc, op)
+		if err != nil {
+			if appErr, ok := errors.AsType[*errors.AppError](err); ok {
+				return nil, appErr.WithContext("index", i)
+			}
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+func applyOperation(doc any, op Operation) (any, error) {
+	path, err := ParsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case "add":
+		value, err := Decode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case "remove":
+		doc, _, err := remove(doc, path)
+		return doc, err
+	case "replace":
+		value, err := Decode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if doc, _, err = remove(doc, path); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case "move":
+		from, err := ParsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if isPrefix(from, path) && len(from) < len(path) {
+			return nil, errors.NewCode(errors.InvalidInput, "JSON Patch cannot move a value into its own child").
+				WithContext("from", op.From).WithContext("path", op.Path)
+		}
+		doc, value, err := remove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case "copy":
+		from, err := ParsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, clone(value))
+	case "test":
+		want, err := Decode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		got, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !Equal(got, want) {
+			return nil, errors.NewCode(errors.Conflict, "JSON Patch test operation failed").
+				WithContext("path", op.Path)
+		}
+		return doc, nil
+	default:
+		return nil, errors.NewCode(errors.InvalidInput, "unsupported JSON Patch operation").WithContext("op", op.Op)
+	}
+}
+
+// ParsePointer 解析 RFC 6901 JSON Pointer，返回反转义后的引用标记；空字符串表示整个文档。
+func ParsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.NewCode(errors.InvalidInput, "JSON Pointer must start with '/'").WithContext("pointer", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func get(doc any, path []string) (any, error) {
+	current := doc
+	for _, token := range path {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, pathNotFound(path)
+			}
+			current = value
+		case []any:
+			idx, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			current = node[idx]
+		default:
+			return nil, pathNotFound(path)
+		}
+	}
+	return current, nil
+}
+
+// add 把 value 写入 path；数组路径按插入语义处理，"-" 表示追加到末尾。
+func add(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = value
+		return doc, nil
+	case []any:
+		idx, err := arrayIndex(last, len(node), true)
+		if err != nil {
+			return nil, err
+		}
+		grown := make([]any, 0, len(node)+1)
+		grown = append(grown, node[:idx]...)
+		grown = append(grown, value)
+		grown = append(grown, node[idx:]...)
+		return replaceAt(doc, path[:len(path)-1], grown)
+	default:
+		return nil, pathNotFound(path)
+	}
+}
+
+// remove 删除 path 处的值并返回被删除的值。
+func remove(doc any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, doc, nil
+	}
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		value, ok := node[last]
+		if !ok {
+			return nil, nil, pathNotFound(path)
+		}
+		delete(node, last)
+		return doc, value, nil
+	case []any:
+		idx, err := arrayIndex(last, len(node), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		value := node[idx]
+		shrunk := append(append(make([]any, 0, len(node)-1), node[:idx]...), node[idx+1:]...)
+		doc, err = replaceAt(doc, path[:len(path)-1], shrunk)
+		return doc, value, err
+	default:
+		return nil, nil, pathNotFound(path)
+	}
+}
+
+// replaceAt 把 path 处的值替换为 value（用于数组长度变化后回写父节点）。
+func replaceAt(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = value
+	case []any:
+		idx, err := arrayIndex(last, len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		node[idx] = value
+	default:
+		return nil, pathNotFound(path)
+	}
+	return doc, nil
+}
+
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, errors.NewCode(errors.InvalidInput, "invalid JSON Patch array index").WithContext("index", token)
+	}
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 {
+		return 0, errors.NewCode(errors.InvalidInput, "invalid JSON Patch array index").WithContext("index", token)
+	}
+	limit := length - 1
+	if allowEnd {
+		limit = length
+	}
+	if idx > limit {
+		return 0, errors.NewCode(errors.InvalidInput, "JSON Patch array index out of range").WithContext("index", token)
+	}
+	return idx, nil
+}
+
+func pathNotFound(path []string) error {
+	return errors.NewCode(errors.InvalidInput, "JSON Patch path not found").WithContext("path", FormatPointer(path))
+}
+
+// FormatPointer 把引用标记格式化为 JSON Pointer。
+func FormatPointer(tokens []string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal 按 JSON 语义比较两个值（数字按数值比较，对象忽略字段顺序）。
+func Equal(a, b any) bool {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for key, value := range av {
+			other, ok := bv[key]
+			if !ok || !Equal(value, other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !Equal(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		bv, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if av == bv {
+			return true
+		}
+		af, aerr := av.Float64()
+		bf, berr := bv.Float64()
+		return aerr == nil && berr == nil && af == bf
+	default:
+		return a == b
+	}
+}
+
+func clone(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = clone(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = clone(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
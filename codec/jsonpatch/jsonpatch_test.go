@@ -0,0 +1,89 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gochen/errors"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustDecode(t *testing.T, text string) any {
+	t.Helper()
+	value, err := Decode([]byte(text))
+	require.NoError(t, err)
+	return value
+}
+
+func requireJSON(t *testing.T, want string, got any) {
+	t.Helper()
+	out, err := json.Marshal(got)
+	require.NoError(t, err)
+	require.JSONEq(t, want, string(out))
+}
+
+func TestMerge_RFC7386Example(t *testing.T) {
+	doc := mustDecode(t, `{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`)
+	patch := mustDecode(t, `{"title":"Hello!","phoneNumber":"+01-123-456-7890","author":{"familyName":null},"tags":["example"]}`)
+
+	merged := Merge(doc, patch)
+	requireJSON(t, `{"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"content":"This will be unchanged","phoneNumber":"+01-123-456-7890"}`, merged)
+	requireJSON(t, `{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`, doc)
+}
+
+func TestPatch_RFC6902Operations(t *testing.T) {
+	doc := mustDecode(t, `{"foo":"bar","list":[1,2],"nested":{"a~b":{"c/d":1}}}`)
+	patch, err := DecodePatch([]byte(`[
+		{"op":"test","path":"/foo","value":"bar"},
+		{"op":"add","path":"/list/1","value":9},
+		{"op":"add","path":"/list/-","value":3},
+		{"op":"remove","path":"/list/0"},
+		{"op":"replace","path":"/nested/a~0b/c~1d","value":2.0},
+		{"op":"copy","from":"/foo","path":"/baz"},
+		{"op":"move","from":"/foo","path":"/qux"},
+		{"op":"test","path":"/nested/a~0b/c~1d","value":2}
+	]`))
+	require.NoError(t, err)
+
+	patched, err := patch.Apply(doc)
+	require.NoError(t, err)
+	requireJSON(t, `{"list":[9,2,3],"nested":{"a~b":{"c/d":2.0}},"baz":"bar","qux":"bar"}`, patched)
+	requireJSON(t, `{"foo":"bar","list":[1,2],"nested":{"a~b":{"c/d":1}}}`, doc)
+}
+
+func TestPatch_Errors(t *testing.T) {
+	doc := mustDecode(t, `{"foo":"bar","list":[1]}`)
+
+	_, err := DecodePatch([]byte(`[{"op":"increment","path":"/foo"}]`))
+	require.True(t, errors.Is(err, errors.InvalidInput))
+
+	_, err = DecodePatch([]byte(`[{"op":"add","path":"foo","value":1}]`))
+	require.True(t, errors.Is(err, errors.InvalidInput))
+
+	cases := map[string]string{
+		"missing path":        `[{"op":"remove","path":"/missing"}]`,
+		"index out of range":  `[{"op":"add","path":"/list/2","value":1}]`,
+		"leading zero index":  `[{"op":"replace","path":"/list/00","value":1}]`,
+		"move into own child": `[{"op":"move","from":"/list","path":"/list/0"}]`,
+	}
+	for name, text := range cases {
+		patch, err := DecodePatch([]byte(text))
+		require.NoError(t, err, name)
+		_, err = patch.Apply(doc)
+		require.True(t, errors.Is(err, errors.InvalidInput), name)
+	}
+
+	patch, err := DecodePatch([]byte(`[{"op":"replace","path":"/foo","value":"x"},{"op":"test","path":"/foo","value":"bar"}]`))
+	require.NoError(t, err)
+	_, err = patch.Apply(doc)
+	require.True(t, errors.Is(err, errors.Conflict))
+	requireJSON(t, `{"foo":"bar","list":[1]}`, doc)
+}
+
+func TestPointer_RoundTrip(t *testing.T) {
+	tokens, err := ParsePointer("/a~1b/c~0d/0")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/b", "c~d", "0"}, tokens)
+	require.Equal(t, "/a~1b/c~0d/0", FormatPointer(tokens))
+}
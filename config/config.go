@@ -36,6 +36,9 @@
 //	if err := validator.Validate(provider); err != nil {
 //	    return err
 //	}
+//
+// 结构体配置可经 Load 按“default tag <- 配置文件 <- 环境变量 <- 命令行参数”分层加载，
+// 框架标准配置见 Settings；Get / Section 按类型从 IConfigProvider 读取配置。
 package config
 
 import (
@@ -115,7 +118,7 @@ type IConfigProvider interface {
 	// Bind 绑定配置到结构体
 	//
 	// key 为配置的根路径，target 必须是结构体指针
-	// 支持 `config:"name"` 标签指定字段映射
+	// 支持 `config:"name"` 标签指定字段映射，未设置时回退到 `yaml:"name"`
 	Bind(key string, target any) error
 
 	// Has 检查配置是否存在
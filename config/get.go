@@ -0,0 +1,93 @@
+package config
+
+import (
+	"reflect"
+	"time"
+
+	"gochen/errors"
+)
+
+// Get 按类型 T 读取 key 对应的配置（严格模式）。
+//
+// 支持：
+// - string、int、int64、float64、bool、time.Duration、[]string、map[string]string：key 缺失或类型不匹配时返回 Validation 错误；
+// - 结构体：以 key 为根路径绑定（字段映射优先 `config` tag，其次 `yaml` tag），先注入 default tag，绑定后按 validate tag 校验；key 为空表示根路径。
+//
+// 其他类型返回 InvalidInput。
+func Get[T any](p IConfigProvider, key string) (T, error) {
+	var zero T
+	if p == nil {
+		return zero, errors.NewCode(errors.InvalidInput, "config provider cannot be nil")
+	}
+
+	var (
+		out any
+		err error
+	)
+	switch any(zero).(type) {
+	case string:
+		out, err = p.GetStringStrict(key)
+	case int:
+		out, err = p.GetIntStrict(key)
+	case bool:
+		out, err = p.GetBoolStrict(key)
+	case time.Duration:
+		out, err = p.GetDurationStrict(key)
+	case int64:
+		if err = requireKey(p, key); err == nil {
+			out = p.GetInt64(key, 0)
+		}
+	case float64:
+		if err = requireKey(p, key); err == nil {
+			out = p.GetFloat64(key, 0)
+		}
+	case []string:
+		if err = requireKey(p, key); err == nil {
+			out = p.GetStringSlice(key, nil)
+		}
+	case map[string]string:
+		if err = requireKey(p, key); err == nil {
+			out = p.GetStringMap(key, nil)
+		}
+	default:
+		if reflect.TypeOf(zero) == nil || reflect.TypeOf(zero).Kind() != reflect.Struct {
+			return zero, errors.NewCode(errors.InvalidInput, "unsupported config value type").
+				WithContext("key", key).
+				WithContext("type", reflect.TypeOf(&zero).Elem().String())
+		}
+		target := new(T)
+		ApplyDefaultsByTag(target)
+		if err := p.Bind(key, target); err != nil {
+			return zero, err
+		}
+		if err := ValidateTaggedStruct(target); err != nil {
+			return zero, err
+		}
+		return *target, nil
+	}
+	if err != nil {
+		return zero, err
+	}
+	return out.(T), nil
+}
+
+// Section 返回把 key 下的配置绑定为 *T 的构造函数，可直接注册到 DI 容器：
+//
+//	container.RegisterConstructor(di.NewConstructor(config.Section[ServerSettings]("server")))
+func Section[T any](key string) func(IConfigProvider) (*T, error) {
+	return func(p IConfigProvider) (*T, error) {
+		v, err := Get[T](p, key)
+		if err != nil {
+			return nil, err
+		}
+		return &v, nil
+	}
+}
+
+func requireKey(p IConfigProvider, key string) error {
+	if !p.Has(key) {
+		return errors.NewCode(errors.Validation, "missing configuration").
+			WithContext("key", key)
+	}
+	return nil
+}
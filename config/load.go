@@ -0,0 +1,140 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gochen/errors"
+)
+
+// LoadOption 配置分层加载（Load）的来源。
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	files     []loadFile
+	envPrefix string
+	lookupEnv func(string) (string, bool)
+	flags     *flag.FlagSet
+}
+
+type loadFile struct {
+	path     string
+	optional bool
+}
+
+// WithFile 追加一个 YAML/JSON 配置文件（按扩展名 .yaml/.yml/.json 识别）；多个文件按追加顺序覆盖。
+func WithFile(path string) LoadOption {
+	return func(o *loadOptions) {
+		o.files = append(o.files, loadFile{path: path})
+	}
+}
+
+// WithOptionalFile 追加一个可选配置文件，文件不存在时跳过。
+func WithOptionalFile(path string) LoadOption {
+	return func(o *loadOptions) {
+		o.files = append(o.files, loadFile{path: path, optional: true})
+	}
+}
+
+// WithEnvPrefix 为按 yaml 路径推导的环境变量名追加前缀，如 "APP_" 时读取 APP_SERVER_PORT。
+//
+// 字段通过 `env:"..."` 显式指定的变量名同样追加前缀。
+func WithEnvPrefix(prefix string) LoadOption {
+	return func(o *loadOptions) {
+		o.envPrefix = prefix
+	}
+}
+
+// WithEnvLookup 替换环境变量查找函数（默认 os.LookupEnv），便于测试或接入其他配置中心。
+func WithEnvLookup(lookup func(string) (string, bool)) LoadOption {
+	return func(o *loadOptions) {
+		o.lookupEnv = lookup
+	}
+}
+
+// WithFlags 使用已解析的命令行参数覆盖配置，只有显式设置的 flag 生效。
+//
+// flag 名与 yaml 路径对应，分隔符 "." "-" "_" 等价，如 -server.port、-database.max-open-conns。
+func WithFlags(fs *flag.FlagSet) LoadOption {
+	return func(o *loadOptions) {
+		o.flags = fs
+	}
+}
+
+// Load 按“default tag <- 配置文件 <- 环境变量 <- 命令行参数 -> validate tag”的顺序分层加载结构体配置。
+//
+// 说明：
+// - 先注入 default tag，再用配置文件覆盖，因此文件中显式写出的零值（如 enabled: false）同样生效；
+// - JSON 文件按 YAML 子集解析，字段映射统一使用 yaml tag；
+// - 环境变量与命令行参数的覆盖规则与 ApplyEnvOverridesByYAMLPath 一致，解析失败时保持原值；
+// - 校验失败返回 ValidationErrors。
+func Load[T any](opts ...LoadOption) (*T, error) {
+	o := loadOptions{lookupEnv: os.LookupEnv}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	target := new(T)
+	ApplyDefaultsByTag(target)
+
+	for _, file := range o.files {
+		path := strings.TrimSpace(file.path)
+		if path == "" {
+			continue
+		}
+		if file.optional {
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				continue
+			}
+		}
+		if err := DecodeConfigFile(path, target); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.lookupEnv != nil {
+		lookup := o.lookupEnv
+		if o.envPrefix != "" {
+			prefix := o.envPrefix
+			lookup = func(key string) (string, bool) { return o.lookupEnv(prefix + key) }
+		}
+		ApplyEnvOverridesByYAMLPathWithLookup(target, lookup)
+	}
+
+	if o.flags != nil {
+		ApplyEnvOverridesByYAMLPathWithLookup(target, flagLookup(o.flags))
+	}
+
+	if err := ValidateTaggedStruct(target); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// DecodeConfigFile 按扩展名读取 YAML（.yaml/.yml）或 JSON（.json）配置文件并解码到目标结构体。
+func DecodeConfigFile(path string, target any) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		// JSON 是 YAML 的子集，统一按 yaml tag 解码，保持两种格式的字段映射一致。
+		return DecodeYAMLFile(path, target)
+	default:
+		return errors.NewCode(errors.InvalidInput, "unsupported config file format").
+			WithContext("path", path)
+	}
+}
+
+// flagLookup 把显式设置的 flag 转为按环境变量名查找的函数。
+func flagLookup(fs *flag.FlagSet) func(string) (string, bool) {
+	values := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		values[pathToEnvKey([]string{f.Name})] = f.Value.String()
+	})
+	return func(key string) (string, bool) {
+		v, ok := values[key]
+		return v, ok
+	}
+}
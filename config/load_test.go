@@ -0,0 +1,125 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gochen/errors"
+	"gochen/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_LayersDefaultsFileEnvAndFlags(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(base, []byte(
+		"server:\n  name: orders\n  port: 9000\noutbox:\n  enabled: false\n  batch_size: 50\ndatabase:\n  driver: postgres\n  database: orders\n",
+	), 0o600))
+	override := filepath.Join(dir, "config.local.json")
+	require.NoError(t, os.WriteFile(override, []byte(`{"server":{"port":9100},"transport":{"kind":"kafka","brokers":["b1:9092"]}}`), 0o600))
+
+	env := map[string]string{
+		"APP_SERVER_PORT":       "9200",
+		"APP_OUTBOX_BATCH_SIZE": "75",
+		"APP_SERVER_LOG_FORMAT": "json",
+		"SERVER_HOST":           "ignored-without-prefix",
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("server.port", 0, "")
+	fs.String("database.max-open-conns", "", "")
+	fs.String("server.host", "", "")
+	require.NoError(t, fs.Parse([]string{"-server.port=9300", "-database.max-open-conns=20"}))
+
+	settings, err := Load[Settings](
+		WithFile(base),
+		WithOptionalFile(filepath.Join(dir, "missing.yaml")),
+		WithFile(override),
+		WithEnvPrefix("APP_"),
+		WithEnvLookup(func(key string) (string, bool) {
+			v, ok := env[key]
+			return v, ok
+		}),
+		WithFlags(fs),
+	)
+	require.NoError(t, err)
+
+	// flag > env > 后加载的文件 > 先加载的文件 > default tag
+	assert.Equal(t, 9300, settings.Server.Port)
+	assert.Equal(t, "orders", settings.Server.Name)
+	assert.Equal(t, "0.0.0.0", settings.Server.Host, "未显式设置的 flag 不生效")
+	assert.Equal(t, "/api/v1", settings.Server.BasePath)
+	assert.Equal(t, logging.Config{Prefix: "orders", Level: logging.InfoLevel, Format: logging.JSONFormat}, settings.Server.LoggingConfig())
+
+	assert.False(t, settings.Outbox.Enabled, "文件中显式写出的零值同样覆盖默认值")
+	assert.Equal(t, 75, settings.Outbox.BatchSize)
+	assert.Equal(t, 5*time.Second, settings.Outbox.PublishInterval)
+
+	assert.Equal(t, "postgres", settings.Database.Driver)
+	assert.Equal(t, 20, settings.Database.MaxOpenConns)
+	assert.Equal(t, 1800, settings.Database.DBConfig().ConnMaxLifetime)
+
+	assert.Equal(t, "kafka", settings.Transport.Kind)
+	assert.Equal(t, []string{"b1:9092"}, settings.Transport.Brokers)
+	assert.Equal(t, 3, settings.Projection.MaxRetries)
+}
+
+func TestLoad_ValidationAndFileErrors(t *testing.T) {
+	noEnv := WithEnvLookup(func(string) (string, bool) { return "", false })
+
+	_, err := Load[Settings](WithEnvLookup(func(key string) (string, bool) {
+		if key == "TRANSPORT_KIND" {
+			return "carrier-pigeon", true
+		}
+		return "", false
+	}))
+	var validationErrors ValidationErrors
+	require.ErrorAs(t, err, &validationErrors)
+
+	_, err = Load[Settings](noEnv, WithFile(filepath.Join(t.TempDir(), "missing.yaml")))
+	require.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte("x = 1\n"), 0o600))
+	_, err = Load[Settings](noEnv, WithFile(path))
+	assert.True(t, errors.Is(err, errors.InvalidInput))
+}
+
+func TestGet_ScalarsAndSections(t *testing.T) {
+	provider, err := NewProvider(WithDefaults(map[string]any{
+		"server.name":     "orders",
+		"server.port":     "9000",
+		"outbox.interval": "2s",
+	}))
+	require.NoError(t, err)
+
+	port, err := Get[int](provider, "server.port")
+	require.NoError(t, err)
+	assert.Equal(t, 9000, port)
+
+	interval, err := Get[time.Duration](provider, "outbox.interval")
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, interval)
+
+	_, err = Get[float64](provider, "server.missing")
+	assert.True(t, errors.Is(err, errors.Validation))
+
+	_, err = Get[chan int](provider, "server.port")
+	assert.True(t, errors.Is(err, errors.InvalidInput))
+
+	server, err := Section[ServerSettings]("server")(provider)
+	require.NoError(t, err)
+	assert.Equal(t, "orders", server.Name)
+	assert.Equal(t, 9000, server.Port)
+	assert.Equal(t, "/api/v1", server.BasePath, "未配置的字段使用 default tag")
+
+	bad, err := NewProvider(WithDefaults(map[string]any{"server.port": 70000}))
+	require.NoError(t, err)
+	_, err = Get[ServerSettings](bad, "server")
+	var validationErrors ValidationErrors
+	require.ErrorAs(t, err, &validationErrors)
+}
@@ -2,7 +2,6 @@ package config
 
 import (
 	"reflect"
-	"strings"
 	"time"
 
 	"gochen/errors"
@@ -30,10 +29,14 @@ func (p *Provider) bindStruct(prefix string, rv reflect.Value) error {
 			continue
 		}
 
-		// 获取配置键名
+		// 获取配置键名：优先 config tag，其次 yaml tag，最后小写字段名
 		keyName := field.Tag.Get("config")
 		if keyName == "" {
-			keyName = strings.ToLower(field.Name)
+			name, ok := yamlFieldName(field)
+			if !ok {
+				continue
+			}
+			keyName = name
 		}
 		if keyName == "-" {
 			continue
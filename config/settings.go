@@ -0,0 +1,119 @@
+package config
+
+import (
+	"time"
+
+	"gochen/db"
+	"gochen/logging"
+)
+
+// Settings 是框架标准配置：服务、数据库、消息传输、Outbox 与投影。
+//
+// 通常经 Load 分层加载：
+//
+//	settings, err := config.Load[config.Settings](
+//	    config.WithOptionalFile("config.yaml"),
+//	    config.WithEnvPrefix("APP_"),
+//	    config.WithFlags(flag.CommandLine),
+//	)
+//
+// 业务配置可内嵌 Settings（`yaml:",inline"`）或作为独立字段与之并列。
+type Settings struct {
+	Server     ServerSettings     `yaml:"server"`
+	Database   DatabaseSettings   `yaml:"database"`
+	Transport  TransportSettings  `yaml:"transport"`
+	Outbox     OutboxSettings     `yaml:"outbox"`
+	Projection ProjectionSettings `yaml:"projection"`
+}
+
+// ServerSettings 定义 HTTP 服务配置。
+type ServerSettings struct {
+	Name     string `yaml:"name" default:"gochen-service" validate:"required"`
+	Host     string `yaml:"host" default:"0.0.0.0"`
+	Port     int    `yaml:"port" default:"8080" validate:"min=1,max=65535"`
+	BasePath string `yaml:"base_path" default:"/api/v1" validate:"startswith=/"`
+
+	// LogLevel / LogFormat 配置日志级别与输出格式，经 LoggingConfig 交给 logging 或第三方日志适配器。
+	LogLevel  string `yaml:"log_level" default:"info" validate:"oneof=debug info warn error"`
+	LogFormat string `yaml:"log_format" default:"text" validate:"oneof=text json"`
+}
+
+// LoggingConfig 转换为 logging.Config，服务名作为日志前缀。
+func (s ServerSettings) LoggingConfig() logging.Config {
+	return logging.Config{
+		Prefix: s.Name,
+		Level:  logging.ParseLevel(s.LogLevel, logging.InfoLevel),
+		Format: logging.ParseFormat(s.LogFormat, logging.TextFormat),
+	}
+}
+
+// DatabaseSettings 定义数据库连接与连接池配置。
+type DatabaseSettings struct {
+	// Driver 为 database/sql 驱动名，如 sqlite、postgres、mysql。
+	Driver   string `yaml:"driver" default:"sqlite" validate:"required,nospace"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port" validate:"min=0,max=65535"`
+	Database string `yaml:"database" default:":memory:" validate:"required"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+
+	MaxOpenConns    int           `yaml:"max_open_conns" default:"10" validate:"min=0"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" default:"5" validate:"min=0"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" default:"30m"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" default:"5m"`
+}
+
+// DBConfig 转换为 db.DBConfig。
+func (s DatabaseSettings) DBConfig() db.DBConfig {
+	return db.DBConfig{
+		Driver:          s.Driver,
+		Host:            s.Host,
+		Port:            s.Port,
+		Database:        s.Database,
+		Username:        s.Username,
+		Password:        s.Password,
+		MaxOpenConns:    s.MaxOpenConns,
+		MaxIdleConns:    s.MaxIdleConns,
+		ConnMaxLifetime: int(s.ConnMaxLifetime / time.Second),
+		ConnMaxIdleTime: int(s.ConnMaxIdleTime / time.Second),
+	}
+}
+
+// TransportSettings 定义消息传输层配置；Kind 之外的字段按所选传输解释。
+type TransportSettings struct {
+	Kind string `yaml:"kind" default:"memory" validate:"oneof=memory direct kafka rabbitmq awssqs natsjetstream redisstreams grpc"`
+
+	// QueueSize / Workers 用于进程内传输（memory）。
+	QueueSize int `yaml:"queue_size" default:"1000" validate:"min=1"`
+	Workers   int `yaml:"workers" default:"4" validate:"min=1"`
+
+	// Brokers / Topic / ConsumerGroup 用于外部 broker（kafka、rabbitmq、natsjetstream 等）。
+	Brokers       []string `yaml:"brokers"`
+	Topic         string   `yaml:"topic"`
+	ConsumerGroup string   `yaml:"consumer_group"`
+}
+
+// OutboxSettings 定义 Outbox 发布器配置，字段与 outbox.OutboxConfig 一一对应。
+type OutboxSettings struct {
+	Enabled           bool          `yaml:"enabled" default:"true"`
+	PublishInterval   time.Duration `yaml:"publish_interval" default:"5s"`
+	BatchSize         int           `yaml:"batch_size" default:"100" validate:"min=1"`
+	PublishPartitions int           `yaml:"publish_partitions" default:"1" validate:"min=1"`
+	MaxRetries        int           `yaml:"max_retries" default:"5" validate:"min=0"`
+	RetryInterval     time.Duration `yaml:"retry_interval" default:"30s"`
+	CleanupInterval   time.Duration `yaml:"cleanup_interval" default:"1h"`
+	RetentionPeriod   time.Duration `yaml:"retention_period" default:"168h"`
+	DisableCleanup    bool          `yaml:"disable_cleanup"`
+	ClaimLease        time.Duration `yaml:"claim_lease" default:"5m"`
+	ProducerKeyPrefix string        `yaml:"producer_key_prefix"`
+}
+
+// ProjectionSettings 定义投影重试与检查点配置，字段与 projection.ProjectionConfig 一一对应。
+type ProjectionSettings struct {
+	MaxRetries             int           `yaml:"max_retries" default:"3" validate:"min=0"`
+	RetryBackoff           time.Duration `yaml:"retry_backoff" default:"1s"`
+	CheckpointSaveInterval time.Duration `yaml:"checkpoint_save_interval" default:"5s"`
+	CheckpointSaveCount    int           `yaml:"checkpoint_save_count" default:"100" validate:"min=0"`
+	ConsumerGroup          string        `yaml:"consumer_group"`
+	HandlerTimeout         time.Duration `yaml:"handler_timeout"`
+}
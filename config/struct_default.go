@@ -160,3 +160,16 @@ func assignDefaultSliceValue(field reflect.Value, raw string) {
 	}
 	field.Set(reflect.ValueOf(splitStringSlice(raw)))
 }
+
+func isZeroValue(value reflect.Value) bool {
+	if !value.IsValid() {
+		return true
+	}
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return true
+		}
+		value = value.Elem()
+	}
+	return value.IsZero()
+}
@@ -0,0 +1,25 @@
+package config
+
+import "strings"
+
+// LoadStruct 按“YAML 文件 -> default tag -> 环境变量 -> validate tag”的顺序加载结构体配置。
+//
+// 说明：
+// - path 为空时跳过文件读取，仅使用默认值与环境变量；
+// - default tag 只填充 YAML 未提供的零值字段；
+// - 环境变量覆盖规则与 ApplyEnvOverridesByYAMLPath 一致；
+// - 校验失败返回 ValidationErrors。
+func LoadStruct[T any](path string) (*T, error) {
+	target := new(T)
+	if path = strings.TrimSpace(path); path != "" {
+		if err := DecodeYAMLFile(path, target); err != nil {
+			return nil, err
+		}
+	}
+	ApplyDefaultsByTag(target)
+	ApplyEnvOverridesByYAMLPath(target)
+	if err := ValidateTaggedStruct(target); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
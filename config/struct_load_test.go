@@ -0,0 +1,36 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadStruct(t *testing.T) {
+	type serviceConfig struct {
+		Server struct {
+			Port int    `yaml:"port" default:"8080"`
+			Mode string `yaml:"mode" default:"debug" validate:"oneof=debug release"`
+		} `yaml:"server"`
+		Database struct {
+			DSN string `yaml:"dsn" validate:"required"`
+		} `yaml:"database"`
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("server:\n  mode: release\ndatabase:\n  dsn: file.db\n"), 0o600))
+	t.Setenv("SERVER_PORT", "9090")
+
+	cfg, err := LoadStruct[serviceConfig](path)
+	require.NoError(t, err)
+	assert.Equal(t, 9090, cfg.Server.Port)
+	assert.Equal(t, "release", cfg.Server.Mode)
+	assert.Equal(t, "file.db", cfg.Database.DSN)
+
+	_, err = LoadStruct[serviceConfig]("")
+	var validationErrors ValidationErrors
+	require.ErrorAs(t, err, &validationErrors)
+}
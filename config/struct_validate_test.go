@@ -32,5 +32,20 @@ func TestValidateTaggedStruct(t *testing.T) {
 			Mode: "broken",
 		},
 	})
-	require.Error(t, err)
+	var validationErrors ValidationErrors
+	require.ErrorAs(t, err, &validationErrors)
+	keys := make([]string, 0, len(validationErrors))
+	for _, validationError := range validationErrors {
+		keys = append(keys, validationError.Key)
+	}
+	require.Equal(t, []string{"port", "path", "nested.mode"}, keys)
+}
+
+// TestValidateTaggedStructIgnoresUnknownRules 验证配置校验忽略未知规则，保持对自定义 tag 的兼容。
+func TestValidateTaggedStructIgnoresUnknownRules(t *testing.T) {
+	type root struct {
+		Region string `yaml:"region" validate:"required,custom"`
+	}
+	require.NoError(t, ValidateTaggedStruct(&root{Region: "cn"}))
+	require.Error(t, ValidateTaggedStruct(&root{}))
 }
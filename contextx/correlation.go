@@ -16,6 +16,10 @@ const (
 	MetadataRequestIDKey = fields.MetadataRequestIDKey
 	// MetadataOperatorKey 定义操作人字段键名。
 	MetadataOperatorKey = fields.MetadataOperatorKey
+	// MetadataCorrelationKey 定义业务关联字段键名。
+	MetadataCorrelationKey = fields.MetadataCorrelationKey
+	// MetadataCausationKey 定义因果字段键名。
+	MetadataCausationKey = fields.MetadataCausationKey
 )
 
 // WithTraceID 返回携带 traceID 的 context。
@@ -37,3 +41,45 @@ func WithRequestID(ctx stdctx.Context, requestID string) (stdctx.Context, error)
 func RequestID(ctx stdctx.Context) string {
 	return fields.RequestID(ctx)
 }
+
+// WithCorrelationID 返回携带 correlationID 的 context。
+func WithCorrelationID(ctx stdctx.Context, correlationID string) (stdctx.Context, error) {
+	return fields.WithCorrelationID(ctx, correlationID)
+}
+
+// CorrelationID 从 context 中获取 correlationID。
+func CorrelationID(ctx stdctx.Context) string {
+	return fields.CorrelationID(ctx)
+}
+
+// WithCausationID 返回携带 causationID 的 context。
+//
+// 处理某条命令/消息时以其 ID 作为 causationID，其间产生的事件与下游消息据此记录直接原因。
+func WithCausationID(ctx stdctx.Context, causationID string) (stdctx.Context, error) {
+	return fields.WithCausationID(ctx, causationID)
+}
+
+// CausationID 从 context 中获取 causationID。
+func CausationID(ctx stdctx.Context) string {
+	return fields.CausationID(ctx)
+}
+
+// WithSagaID 返回携带 sagaID 的 context（仅进程内，用于日志关联）。
+func WithSagaID(ctx stdctx.Context, sagaID string) (stdctx.Context, error) {
+	return fields.WithSagaID(ctx, sagaID)
+}
+
+// SagaID 从 context 中获取 sagaID。
+func SagaID(ctx stdctx.Context) string {
+	return fields.SagaID(ctx)
+}
+
+// WithAggregateID 返回携带 aggregateID 的 context（仅进程内，用于日志关联）。
+func WithAggregateID(ctx stdctx.Context, aggregateID string) (stdctx.Context, error) {
+	return fields.WithAggregateID(ctx, aggregateID)
+}
+
+// AggregateID 从 context 中获取 aggregateID。
+func AggregateID(ctx stdctx.Context) string {
+	return fields.AggregateID(ctx)
+}
@@ -19,10 +19,11 @@ func TestTraceAndRequestContext(t *testing.T) {
 
 func TestDeriveFromMetadata(t *testing.T) {
 	md := MapMetadata{
-		MetadataTenantKey:    "t1",
-		MetadataTraceKey:     "t1-trace",
-		MetadataRequestIDKey: "req-1",
-		MetadataOperatorKey:  "alice",
+		MetadataTenantKey:      "t1",
+		MetadataTraceKey:       "t1-trace",
+		MetadataRequestIDKey:   "req-1",
+		MetadataOperatorKey:    "alice",
+		MetadataCorrelationKey: "corr-1",
 	}
 
 	ctx, err := DeriveFromMetadata(stdctx.Background(), md)
@@ -31,6 +32,7 @@ func TestDeriveFromMetadata(t *testing.T) {
 	require.Equal(t, "t1-trace", TraceID(ctx))
 	require.Equal(t, "req-1", RequestID(ctx))
 	require.Equal(t, "alice", Operator(ctx))
+	require.Equal(t, "corr-1", CorrelationID(ctx))
 
 	ctx2, err := WithTraceID(stdctx.Background(), "t2-trace")
 	require.NoError(t, err)
@@ -70,6 +72,10 @@ func TestInjectAll(t *testing.T) {
 	require.NoError(t, err)
 	ctx, err = WithOperator(ctx, "alice")
 	require.NoError(t, err)
+	ctx, err = WithCorrelationID(ctx, "corr-1")
+	require.NoError(t, err)
+	ctx, err = WithSagaID(ctx, "saga-1")
+	require.NoError(t, err)
 
 	md := MapMetadata{}
 	require.NoError(t, InjectAll(ctx, md))
@@ -77,4 +83,50 @@ func TestInjectAll(t *testing.T) {
 	require.Equal(t, "trc-1", md[MetadataTraceKey])
 	require.Equal(t, "req-1", md[MetadataRequestIDKey])
 	require.Equal(t, "alice", md[MetadataOperatorKey])
+	require.Equal(t, "corr-1", md[MetadataCorrelationKey])
+	require.Len(t, md, 5, "saga_id is process-local and must not be propagated")
+}
+
+func TestEnsureCorrelationID(t *testing.T) {
+	md := MapMetadata{}
+	ctx, err := EnsureCorrelationID(stdctx.Background(), md, "cmd-1")
+	require.NoError(t, err)
+	require.Equal(t, "cmd-1", CorrelationID(ctx))
+	require.Equal(t, "cmd-1", md[MetadataCorrelationKey])
+
+	md2 := MapMetadata{MetadataCorrelationKey: "upstream"}
+	ctx2, err := EnsureCorrelationID(stdctx.Background(), md2, "cmd-2")
+	require.NoError(t, err)
+	require.Equal(t, "upstream", CorrelationID(ctx2))
+
+	base, err := WithCorrelationID(stdctx.Background(), "root")
+	require.NoError(t, err)
+	md3 := MapMetadata{MetadataCorrelationKey: "own"}
+	ctx3, err := EnsureCorrelationID(base, md3, "cmd-3")
+	require.NoError(t, err)
+	require.Equal(t, "root", CorrelationID(ctx3))
+	require.Equal(t, "own", md3[MetadataCorrelationKey], "metadata value must not be overwritten")
+
+	ctx4, err := EnsureCorrelationID(stdctx.Background(), nil, "")
+	require.NoError(t, err)
+	require.Empty(t, CorrelationID(ctx4))
+}
+
+func TestCausationPropagation(t *testing.T) {
+	ctx, err := WithCausationID(stdctx.Background(), "cmd-1")
+	require.NoError(t, err)
+	require.Equal(t, "cmd-1", CausationID(ctx))
+
+	md := MapMetadata{}
+	require.NoError(t, InjectAll(ctx, md))
+	require.Equal(t, "cmd-1", md[MetadataCausationKey])
+
+	md2 := MapMetadata{MetadataCausationKey: "evt-9"}
+	require.NoError(t, InjectCausationID(ctx, md2))
+	require.Equal(t, "evt-9", md2[MetadataCausationKey])
+
+	// 消息自身的 causation_id 是上游原因，不回填到处理方 ctx。
+	derived, err := DeriveFromMetadata(stdctx.Background(), MapMetadata{MetadataCausationKey: "evt-9"})
+	require.NoError(t, err)
+	require.Empty(t, CausationID(derived))
 }
@@ -18,12 +18,26 @@ const (
 	MetadataRequestIDKey = "request_id"
 	// MetadataOperatorKey 定义操作人字段键名。
 	MetadataOperatorKey = "operator"
+	// MetadataCorrelationKey 定义业务关联字段键名（串联同一业务流程的请求、命令与事件）。
+	MetadataCorrelationKey = "correlation_id"
+	// MetadataCausationKey 定义因果字段键名（直接引起当前消息/事件的命令或消息 ID）。
+	MetadataCausationKey = "causation_id"
+)
+
+// 进程内作用域字段名：只用于日志与诊断，不随消息跨进程传播。
+const (
+	// SagaIDKey 定义 Saga 实例字段名。
+	SagaIDKey = "saga_id"
+	// AggregateIDKey 定义聚合 ID 字段名。
+	AggregateIDKey = "aggregate_id"
 )
 
 type principalKey uint8
 
 type correlationKey uint8
 
+type scopeKey uint8
+
 const (
 	keyTenantID principalKey = iota + 1
 	keyOperator
@@ -32,6 +46,13 @@ const (
 const (
 	keyTraceID correlationKey = iota + 1
 	keyRequestID
+	keyCorrelationID
+	keyCausationID
+)
+
+const (
+	keySagaID scopeKey = iota + 1
+	keyAggregateID
 )
 
 func ensure(ctx stdctx.Context) (stdctx.Context, error) {
@@ -120,3 +141,69 @@ func RequestID(ctx stdctx.Context) string {
 	}
 	return ""
 }
+
+// WithCorrelationID 返回携带 correlationID 的 context。
+func WithCorrelationID(ctx stdctx.Context, correlationID string) (stdctx.Context, error) {
+	ctx, err := ensure(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return stdctx.WithValue(ctx, keyCorrelationID, strings.TrimSpace(correlationID)), nil
+}
+
+// CorrelationID 从 context 中获取 correlationID。
+func CorrelationID(ctx stdctx.Context) string {
+	return stringValue(ctx, keyCorrelationID)
+}
+
+// WithCausationID 返回携带 causationID 的 context；嵌套调用时内层覆盖外层。
+func WithCausationID(ctx stdctx.Context, causationID string) (stdctx.Context, error) {
+	ctx, err := ensure(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return stdctx.WithValue(ctx, keyCausationID, strings.TrimSpace(causationID)), nil
+}
+
+// CausationID 从 context 中获取 causationID。
+func CausationID(ctx stdctx.Context) string {
+	return stringValue(ctx, keyCausationID)
+}
+
+// WithSagaID 返回携带 sagaID 的 context。
+func WithSagaID(ctx stdctx.Context, sagaID string) (stdctx.Context, error) {
+	ctx, err := ensure(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return stdctx.WithValue(ctx, keySagaID, strings.TrimSpace(sagaID)), nil
+}
+
+// SagaID 从 context 中获取 sagaID。
+func SagaID(ctx stdctx.Context) string {
+	return stringValue(ctx, keySagaID)
+}
+
+// WithAggregateID 返回携带 aggregateID 的 context；嵌套调用时内层覆盖外层。
+func WithAggregateID(ctx stdctx.Context, aggregateID string) (stdctx.Context, error) {
+	ctx, err := ensure(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return stdctx.WithValue(ctx, keyAggregateID, strings.TrimSpace(aggregateID)), nil
+}
+
+// AggregateID 从 context 中获取 aggregateID。
+func AggregateID(ctx stdctx.Context) string {
+	return stringValue(ctx, keyAggregateID)
+}
+
+func stringValue(ctx stdctx.Context, key any) string {
+	if ctx == nil {
+		return ""
+	}
+	if v, ok := ctx.Value(key).(string); ok {
+		return v
+	}
+	return ""
+}
@@ -97,7 +97,69 @@ func InjectOperator(ctx stdctx.Context, metadata IMetadata) error {
 	return nil
 }
 
-// InjectAll 将当前 context 中的 tenant/trace/request/operator 注入到 metadata（缺失时补齐）。
+// InjectCorrelationID 将当前 context 中的 correlation_id 注入到 metadata（若 metadata 未设置该字段）。
+func InjectCorrelationID(ctx stdctx.Context, metadata IMetadata) error {
+	_, err := Ensure(ctx)
+	if err != nil {
+		return err
+	}
+	if metadata == nil {
+		return nil
+	}
+	if v, ok := metadata.Get(MetadataCorrelationKey); ok && strings.TrimSpace(v) != "" {
+		return nil
+	}
+	if correlationID := CorrelationID(ctx); correlationID != "" {
+		metadata.Set(MetadataCorrelationKey, correlationID)
+	}
+	return nil
+}
+
+// InjectCausationID 将当前 context 中的 causation_id 注入到 metadata（若 metadata 未设置该字段）。
+func InjectCausationID(ctx stdctx.Context, metadata IMetadata) error {
+	_, err := Ensure(ctx)
+	if err != nil {
+		return err
+	}
+	if metadata == nil {
+		return nil
+	}
+	if v, ok := metadata.Get(MetadataCausationKey); ok && strings.TrimSpace(v) != "" {
+		return nil
+	}
+	if causationID := CausationID(ctx); causationID != "" {
+		metadata.Set(MetadataCausationKey, causationID)
+	}
+	return nil
+}
+
+// EnsureCorrelationID 确保 ctx 与 metadata 都具备 correlation_id。
+//
+// 优先级：ctx > metadata > fallback；fallback 通常是根命令/消息 ID，使一次业务流程的起点成为其关联 ID。
+// metadata 已有的值不会被覆盖；metadata 为 nil 时只补齐 ctx。
+func EnsureCorrelationID(ctx stdctx.Context, metadata IMetadata, fallback string) (stdctx.Context, error) {
+	ctx, err := Ensure(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if CorrelationID(ctx) == "" {
+		correlationID := strings.TrimSpace(fallback)
+		if metadata != nil {
+			if v, ok := metadata.Get(MetadataCorrelationKey); ok && strings.TrimSpace(v) != "" {
+				correlationID = v
+			}
+		}
+		if correlationID == "" {
+			return ctx, nil
+		}
+		if ctx, err = WithCorrelationID(ctx, correlationID); err != nil {
+			return nil, err
+		}
+	}
+	return ctx, InjectCorrelationID(ctx, metadata)
+}
+
+// InjectAll 将当前 context 中的 tenant/trace/request/operator/correlation/causation 注入到 metadata（缺失时补齐）。
 func InjectAll(ctx stdctx.Context, metadata IMetadata) error {
 	if err := InjectTenantID(ctx, metadata); err != nil {
 		return err
@@ -108,10 +170,19 @@ func InjectAll(ctx stdctx.Context, metadata IMetadata) error {
 	if err := InjectRequestID(ctx, metadata); err != nil {
 		return err
 	}
+	if err := InjectCorrelationID(ctx, metadata); err != nil {
+		return err
+	}
+	if err := InjectCausationID(ctx, metadata); err != nil {
+		return err
+	}
 	return InjectOperator(ctx, metadata)
 }
 
-// DeriveFromMetadata 从 metadata 补齐 ctx 中的 tenant/trace/request/operator（仅当 ctx 缺失时）。
+// DeriveFromMetadata 从 metadata 补齐 ctx 中的 tenant/trace/request/operator/correlation（仅当 ctx 缺失时）。
+//
+// causation_id 不从 metadata 派生：消息自身的 causation_id 是它的上游原因，
+// 处理该消息时应以消息 ID 作为 ctx 的 causationID（见 WithCausationID）。
 func DeriveFromMetadata(ctx stdctx.Context, metadata IMetadata) (stdctx.Context, error) {
 	ctx, err := Ensure(ctx)
 	if err != nil {
@@ -152,6 +223,14 @@ func DeriveFromMetadata(ctx stdctx.Context, metadata IMetadata) (stdctx.Context,
 			}
 		}
 	}
+	if CorrelationID(ctx) == "" {
+		if v, ok := metadata.Get(MetadataCorrelationKey); ok && strings.TrimSpace(v) != "" {
+			ctx, err = WithCorrelationID(ctx, v)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
 	return ctx, nil
 }
 
@@ -0,0 +1,152 @@
+package repo
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"gochen/db/orm"
+	"gochen/db/sql/safeident"
+	"gochen/domain"
+	"gochen/domain/audited"
+	"gochen/errors"
+)
+
+// UpdateFields 只把 fields（实体 JSON 字段名）对应的列写入存储，底层翻译为 UpdateValues。
+//
+// 说明：
+//   - 与 Update 一致：启用审计字段时附带乐观锁检查，并同步写入 updated_at/updated_by 与推进后的版本号；
+//   - 主键、版本、审计、软删与数据范围列由仓储维护，出现在 fields 中时返回 errors.InvalidInput。
+func (r *Repo[T, ID]) UpdateFields(ctx context.Context, entity T, fields []string) error {
+	values, err := r.patchValues(entity, fields)
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	expectedVersion := entity.GetVersion()
+	if err := r.prepareUpdate(ctx, entity); err != nil {
+		return err
+	}
+	if aud, ok := any(entity).(audited.IAuditable); ok {
+		values["updated_at"] = aud.GetUpdatedAt()
+		values["updated_by"] = aud.GetUpdatedBy()
+	} else if ts, ok := any(entity).(domain.ITimestamps); ok {
+		values["updated_at"] = ts.GetUpdatedAt()
+	}
+
+	quer, err := r.query(ctx)
+	if err != nil {
+		return err
+	}
+	quer = quer.Where("id = ?", entity.GetID())
+	if r.auditFields {
+		values[r.versionColumn()] = entity.GetVersion()
+		quer = quer.Where(r.versionColumn()+" = ?", expectedVersion)
+		if _, ok := r.model.(orm.IModelWithResult); ok {
+			res, err := quer.UpdateValuesWithResult(values)
+			if err != nil {
+				return errors.Wrap(err, errors.Database, "failed to update record fields")
+			}
+			if affected, aerr := res.RowsAffected(); aerr == nil && affected == 0 {
+				exists, err := r.existsIncludingDeleted(ctx, entity.GetID())
+				if err != nil {
+					return err
+				}
+				if !exists {
+					return errors.NewCode(errors.NotFound, "record not found")
+				}
+				return errors.NewCode(errors.Concurrency, "concurrent modification detected").
+					WithContext("id", entity.GetID()).
+					WithContext("expected_version", expectedVersion)
+			}
+			return nil
+		}
+		// 不支持 result 的模型：回退为普通更新（不做冲突检测）
+	}
+	if err := quer.UpdateValues(values); err != nil {
+		return errors.Wrap(err, errors.Database, "failed to update record fields")
+	}
+	return nil
+}
+
+// patchValues 把 JSON 字段名解析为列名，并从实体读取对应值。
+func (r *Repo[T, ID]) patchValues(entity T, fields []string) (map[string]any, error) {
+	value := reflect.ValueOf(entity)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, errors.NewCode(errors.InvalidInput, "entity cannot be nil")
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, errors.NewCode(errors.InvalidInput, "partial update requires a struct entity")
+	}
+
+	managed := r.managedColumns()
+	values := make(map[string]any, len(fields))
+	for _, name := range fields {
+		field, ok := fieldByJSONName(value.Type(), name)
+		if !ok {
+			return nil, errors.NewCode(errors.InvalidInput, "unknown field for partial update").WithContext("field", name)
+		}
+		column := normalizeColumnName(field)
+		if !safeident.IsSafeIdentifier(column) {
+			return nil, errors.NewCode(errors.InvalidInput, "unsafe column for partial update").WithContext("field", name)
+		}
+		if _, ok := managed[column]; ok {
+			return nil, errors.NewCode(errors.InvalidInput, "field is managed by the repository").WithContext("field", name)
+		}
+		fieldValue, err := value.FieldByIndexErr(field.Index)
+		if err != nil {
+			// 嵌入的指针结构体为 nil：字段按 NULL 写入。
+			values[column] = nil
+			continue
+		}
+		values[column] = fieldValue.Interface()
+	}
+	return values, nil
+}
+
+// managedColumns 返回由仓储维护、不允许局部更新写入的列。
+func (r *Repo[T, ID]) managedColumns() map[string]struct{} {
+	schema := r.accessSchema()
+	columns := []string{
+		"id", r.versionColumn(), schema.managedScope.column, schema.ownerID.column,
+		"created_at", "created_by", "updated_at", "updated_by",
+		r.softDeleteCols.DeletedAt, r.softDeleteCols.DeletedBy,
+	}
+	managed := make(map[string]struct{}, len(columns))
+	for _, column := range columns {
+		if column != "" {
+			managed[column] = struct{}{}
+		}
+	}
+	return managed
+}
+
+// fieldByJSONName 按 JSON 字段名查找可导出字段（含嵌入结构体提升的字段）。
+func fieldByJSONName(typ reflect.Type, name string) (reflect.StructField, bool) {
+	for _, field := range reflect.VisibleFields(typ) {
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		jsonName, _, _ := strings.Cut(tag, ",")
+		if jsonName == "" {
+			if field.Anonymous {
+				continue
+			}
+			jsonName = field.Name
+		}
+		if jsonName == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
@@ -0,0 +1,89 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"gochen/db/orm"
+	"gochen/domain/audited"
+	"gochen/domain/crud"
+	"gochen/errors"
+)
+
+type patchedEntity struct {
+	*audited.AuditedEntity[int64]
+	Name  string `json:"name"`
+	Price int    `json:"price" gorm:"column:unit_price"`
+}
+
+type patchCapturingModel struct {
+	optimisticModel
+	values map[string]any
+	opts   orm.QueryOptions
+}
+
+func (m *patchCapturingModel) UpdateValuesWithResult(ctx context.Context, values map[string]any, opts ...orm.QueryOption) (sql.Result, error) {
+	_ = ctx
+	m.values = values
+	m.opts = orm.CollectQueryOptions(opts...)
+	return fakeResult(m.optimisticModel.affected), nil
+}
+
+func newPatchedEntity() *patchedEntity {
+	return &patchedEntity{
+		AuditedEntity: &audited.AuditedEntity[int64]{Entity: crud.Entity[int64]{ID: 1, Version: 7}},
+		Name:          "renamed",
+		Price:         42,
+	}
+}
+
+func TestRepo_UpdateFields_WritesOnlyRequestedColumns(t *testing.T) {
+	m := &patchCapturingModel{optimisticModel: optimisticModel{affected: 1, count: 1}}
+	r := &Repo[*patchedEntity, int64]{model: m, auditFields: true, defaultActor: "system"}
+
+	e := newPatchedEntity()
+	if err := r.UpdateFields(context.Background(), e, []string{"price"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := m.values["unit_price"]; got != 42 {
+		t.Fatalf("expected unit_price=42, got %v", got)
+	}
+	if _, ok := m.values["name"]; ok {
+		t.Fatalf("expected name to be left untouched, got %+v", m.values)
+	}
+	if got := m.values["version"]; got != uint64(8) {
+		t.Fatalf("expected version to advance to 8, got %v", got)
+	}
+	if _, ok := m.values["updated_at"]; !ok {
+		t.Fatalf("expected updated_at to be written, got %+v", m.values)
+	}
+	if !hasWhereExpr(m.opts, "version") {
+		t.Fatalf("expected optimistic lock in where clause, got %+v", m.opts.Where)
+	}
+}
+
+func TestRepo_UpdateFields_RejectsManagedAndUnknownFields(t *testing.T) {
+	m := &patchCapturingModel{optimisticModel: optimisticModel{affected: 1, count: 1}}
+	r := &Repo[*patchedEntity, int64]{model: m, auditFields: true, defaultActor: "system"}
+
+	for _, field := range []string{"id", "version", "created_by", "missing"} {
+		err := r.UpdateFields(context.Background(), newPatchedEntity(), []string{field})
+		if !errors.Is(err, errors.InvalidInput) {
+			t.Fatalf("field %q: expected InvalidInput, got %v", field, err)
+		}
+	}
+	if m.values != nil {
+		t.Fatalf("expected no write for rejected fields, got %+v", m.values)
+	}
+}
+
+func TestRepo_UpdateFields_ReturnsConcurrencyWhenZeroAffectedButExists(t *testing.T) {
+	m := &patchCapturingModel{optimisticModel: optimisticModel{affected: 0, count: 1}}
+	r := &Repo[*patchedEntity, int64]{model: m, auditFields: true, defaultActor: "system"}
+
+	err := r.UpdateFields(context.Background(), newPatchedEntity(), []string{"name"})
+	if !errors.Is(err, errors.Concurrency) {
+		t.Fatalf("expected Concurrency, got %v", err)
+	}
+}
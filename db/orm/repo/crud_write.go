@@ -142,6 +142,33 @@ func (r *Repo[T, ID]) Update(ctx context.Context, entity T) error {
 			return nil
 		}
 		// 不支持 result 的模型：回退为普通更新（不做冲突检测）
+	} else if versioned, ok := any(entity).(domain.ISettableVersion); ok {
+		// 未启用审计字段但实体可回写版本：同样以旧版本为条件并推进版本。
+		quer = quer.Where(r.versionColumn()+" = ?", expectedVersion)
+		versioned.SetVersion(expectedVersion + 1)
+		res, err := quer.SaveWithResult(entity)
+		if err == nil && res != nil {
+			affected, aerr := res.RowsAffected()
+			if aerr == nil && affected == 0 {
+				versioned.SetVersion(expectedVersion)
+				exists, err := r.existsIncludingDeleted(ctx, entity.GetID())
+				if err != nil {
+					return err
+				}
+				if !exists {
+					return errors.NewCode(errors.NotFound, "record not found")
+				}
+				return errors.NewCode(errors.Concurrency, "concurrent modification detected").
+					WithContext("id", entity.GetID()).
+					WithContext("expected_version", expectedVersion)
+			}
+			return nil
+		}
+		versioned.SetVersion(expectedVersion)
+		if !errors.Is(err, errors.Unsupported) {
+			return errors.Wrap(err, errors.Database, "failed to update record")
+		}
+		// 不支持 result 的模型：回退为普通更新（不做冲突检测）
 	}
 	if err := quer.Save(entity); err != nil {
 		return errors.Wrap(err, errors.Database, "failed to update record")
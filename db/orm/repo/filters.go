@@ -5,8 +5,34 @@ import (
 	"strings"
 
 	"gochen/db/query"
+	"gochen/errors"
 )
 
+// validateQueryFilters 校验过滤字段与操作符。
+//
+// 说明：与排序字段保持一致，未知/不安全字段与非法操作符直接返回 InvalidInput，
+// 避免字段拼写错误时静默返回未过滤的结果。
+func (quer *queryBuilder) validateQueryFilters(filters query.QueryFilters) error {
+	for field, exprs := range filters {
+		if len(exprs) == 0 {
+			continue
+		}
+		name := strings.TrimSpace(field)
+		if name == "" || !quer.isAllowedField(name) {
+			return errors.NewCode(errors.InvalidInput, "invalid filter field").
+				WithContext("field", field)
+		}
+		for _, expr := range exprs {
+			if !expr.Op.IsValid() {
+				return errors.NewCode(errors.InvalidInput, "invalid filter operator").
+					WithContext("field", field).
+					WithContext("operator", string(expr.Op))
+			}
+		}
+	}
+	return nil
+}
+
 func (quer *queryBuilder) withFilters(filters []query.Filter) *queryBuilder {
 	for _, f := range filters {
 		field := strings.TrimSpace(f.Field)
@@ -316,3 +316,32 @@ func TestRepo_Update_ExistsCheckIgnoresSoftDeleteFilter(t *testing.T) {
 		t.Fatalf("expected exists check to not include deleted_at filter, got: %+v", m.countOpts.Where)
 	}
 }
+
+type plainVersionedEntity struct {
+	crud.Entity[int64]
+}
+
+// TestRepo_Update_VersionGuardWithoutAuditFields 验证未启用审计字段时，可回写版本的实体同样按旧版本条件更新并推进版本。
+func TestRepo_Update_VersionGuardWithoutAuditFields(t *testing.T) {
+	m := &capturingOptimisticModel{optimisticModel: optimisticModel{affected: 1, count: 1}}
+	r := &Repo[*plainVersionedEntity, int64]{model: m, defaultActor: "system"}
+
+	e := &plainVersionedEntity{Entity: crud.Entity[int64]{ID: 1, Version: 3}}
+	if err := r.Update(context.Background(), e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hasWhereExpr(m.saveOpts, "version = ?") {
+		t.Fatalf("expected version predicate, got: %+v", m.saveOpts.Where)
+	}
+	if e.Version != 4 {
+		t.Fatalf("expected version advanced to 4, got %d", e.Version)
+	}
+
+	m.affected = 0
+	if err := r.Update(context.Background(), e); !errors.Is(err, errors.Concurrency) {
+		t.Fatalf("expected Concurrency, got %v", err)
+	}
+	if e.Version != 4 {
+		t.Fatalf("expected version restored to 4 after conflict, got %d", e.Version)
+	}
+}
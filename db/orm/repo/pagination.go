@@ -28,6 +28,9 @@ func (r *Repo[T, ID]) ListPage(ctx context.Context, request *query.PageRequest)
 		quer = quer.Where(r.softDeleteCols.DeletedAt + " IS NULL")
 	}
 	if !request.Filters.IsZero() {
+		if err := quer.validateQueryFilters(request.Filters); err != nil {
+			return nil, err
+		}
 		quer = quer.withQueryFilters(request.Filters)
 	}
 	if !request.Advanced.IsZero() {
@@ -8,11 +8,14 @@ import (
 	"gochen/errors"
 )
 
-func applyQueryFilters(quer *queryBuilder, opts query.QueryOptions) *queryBuilder {
-	if !opts.Filters.IsZero() {
-		quer = quer.withQueryFilters(opts.Filters)
+func applyQueryFilters(quer *queryBuilder, opts query.QueryOptions) (*queryBuilder, error) {
+	if opts.Filters.IsZero() {
+		return quer, nil
 	}
-	return quer
+	if err := quer.validateQueryFilters(opts.Filters); err != nil {
+		return nil, err
+	}
+	return quer.withQueryFilters(opts.Filters), nil
 }
 
 func (r *Repo[T, ID]) applyAdvancedQueryFilters(quer *queryBuilder, opts query.QueryOptions) *queryBuilder {
@@ -57,7 +60,10 @@ func (r *Repo[T, ID]) Query(ctx context.Context, opts query.QueryOptions) ([]T,
 		quer = quer.Where(r.softDeleteCols.DeletedAt + " IS NULL")
 	}
 	quer = r.applySelectFields(quer, opts.Fields)
-	quer = applyQueryFilters(quer, opts)
+	quer, err = applyQueryFilters(quer, opts)
+	if err != nil {
+		return nil, err
+	}
 	quer = r.applyAdvancedQueryFilters(quer, opts)
 	if len(opts.Sorts) > 0 {
 		for _, s := range opts.Sorts {
@@ -99,7 +105,11 @@ func (r *Repo[T, ID]) QueryOne(ctx context.Context, opts query.QueryOptions) (T,
 		quer = quer.Where(r.softDeleteCols.DeletedAt + " IS NULL")
 	}
 	quer = r.applySelectFields(quer, opts.Fields)
-	quer = applyQueryFilters(quer, opts)
+	quer, err = applyQueryFilters(quer, opts)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
 	quer = r.applyAdvancedQueryFilters(quer, opts)
 	if len(opts.Sorts) > 0 {
 		for _, s := range opts.Sorts {
@@ -138,7 +148,10 @@ func (r *Repo[T, ID]) QueryCount(ctx context.Context, opts query.QueryOptions) (
 	if r.softDelete {
 		quer = quer.Where(r.softDeleteCols.DeletedAt + " IS NULL")
 	}
-	quer = applyQueryFilters(quer, opts)
+	quer, err = applyQueryFilters(quer, opts)
+	if err != nil {
+		return 0, err
+	}
 	quer = r.applyAdvancedQueryFilters(quer, opts)
 	count, err := quer.Count()
 	if err != nil {
@@ -285,6 +285,33 @@ func TestRepo_Query_UsesCriteriaFilters(t *testing.T) {
 	require.Equal(t, []any{int64(18), int64(21)}, inArgs)
 }
 
+// TestRepo_Query_UnknownFilterFieldReturnsInvalidInput 验证未知过滤字段直接报错，而不是静默返回未过滤结果。
+func TestRepo_Query_UnknownFilterFieldReturnsInvalidInput(t *testing.T) {
+	m := &capturingQueryModel{
+		meta: &orm.ModelMeta{
+			Fields: []orm.FieldMeta{
+				{Name: "Age", Column: "age"},
+			},
+		},
+	}
+	r := &Repo[*queryIfaceEntity, int64]{model: m}
+
+	_, err := r.QueryCount(context.Background(), query.QueryOptions{
+		Filters: query.Where(query.IntRef("agee").Gte(18)),
+	})
+	require.Error(t, err)
+	require.True(t, errors.Is(err, errors.InvalidInput))
+	require.Empty(t, m.lastCountOpts.Where, "Count should not be called when filter field is invalid")
+
+	_, err = r.Query(context.Background(), query.QueryOptions{
+		Filters: query.Where(query.IntRef("age").Gte(18)),
+	})
+	require.NoError(t, err)
+	require.Len(t, m.lastFindOpts.Where, 1)
+	require.Equal(t, "age >= ?", m.lastFindOpts.Where[0].Expr)
+	require.Equal(t, int64(18), m.lastFindOpts.Where[0].Args[0])
+}
+
 // TestRepo_QueryCount_UsesCriteriaFilters 验证 Repo QueryCount UsesCriteriaFilters。
 func TestRepo_QueryCount_UsesCriteriaFilters(t *testing.T) {
 	m := &capturingQueryModel{
@@ -8,7 +8,10 @@
 //   - 对 time/int/float 这类有序字段，可通过 ParseRange/RangeFor 把同字段上的 gt/gte/lt/lte/eq 表达式收口为显式 Range；
 //   - 若需要把动态字段过滤升级为“声明式 DSL”，可结合 QuerySchema/QueryField 声明字段类型、允许操作符与排序/投影能力；
 //   - 若希望减少样板，也可通过 InferQuerySchema[T](...) 从 struct + query tag 自动推导 QuerySchema；
+//   - 代码内构造过滤条件时优先使用 StringRef/IntRef/EnumRef/... 声明的强类型字段引用与 Where(...)，
+//     并用 QuerySchema.ValidateQueryFilters 做严格校验，避免字段拼写错误静默返回未过滤的结果；
 //   - 若业务需要保留 `eq/like` 等 operator 语义并在内存/自定义仓储侧复用，可直接使用 `querymatch.Text` / `querymatch.Prefix`。
+//   - 内存/mock 仓储可使用 `querymatch.MatchFilters` 按与 db/orm/repo 相同的语义解释 QueryFilters。
 //
 // 安全说明：
 // - 字段白名单/注入安全由仓储实现负责（例如 db/orm/repo 的 isAllowedField 校验）；
@@ -0,0 +1,224 @@
+package querymatch
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gochen/db/query"
+	"gochen/errors"
+)
+
+// FieldLookup 按字段名读取候选记录的字段值。
+//
+// 返回 ok=false 表示记录不存在该字段；值为 nil（或 nil 指针）按 SQL NULL 处理。
+type FieldLookup func(field string) (value any, ok bool)
+
+// MapLookup 把 map 适配为 FieldLookup。
+func MapLookup(record map[string]any) FieldLookup {
+	return func(field string) (any, bool) {
+		value, ok := record[field]
+		return value, ok
+	}
+}
+
+// MatchFilters 在内存中按与 db/orm/repo 相同的语义判断记录是否命中 QueryFilters。
+//
+// 说明：
+//   - 所有表达式按 AND 组合；比较值取 QueryValue 的强类型值（与 ORM 传给 SQL 的参数一致）；
+//   - like 为区分大小写的子串匹配（对应 `LIKE %v%`，实际数据库的大小写语义取决于排序规则）；
+//   - NULL 只命中 is_null；与 NULL 的比较（含 ne/not_in）均不命中，与 SQL 三值逻辑一致；
+//   - 未知字段返回 InvalidInput，而不是静默忽略该条件。
+func MatchFilters(filters query.QueryFilters, lookup FieldLookup) (bool, error) {
+	if filters.IsZero() {
+		return true, nil
+	}
+	if lookup == nil {
+		return false, errors.NewCode(errors.InvalidInput, "field lookup is nil")
+	}
+	fields := make([]string, 0, len(filters))
+	for field := range filters {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		exprs := filters[field]
+		if len(exprs) == 0 {
+			continue
+		}
+		raw, ok := lookup(field)
+		if !ok {
+			return false, errors.NewCode(errors.InvalidInput, "invalid filter field").
+				WithContext("field", field)
+		}
+		candidate, isNull := derefCandidate(raw)
+		for _, expr := range exprs {
+			matched, err := matchExpr(field, candidate, isNull, expr)
+			if err != nil || !matched {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+func matchExpr(field string, candidate any, isNull bool, expr query.QueryExpr) (bool, error) {
+	switch expr.Op {
+	case query.FilterOpIsNull:
+		return isNull, nil
+	case query.FilterOpNotNull:
+		return !isNull, nil
+	}
+	if isNull {
+		return false, nil
+	}
+
+	switch expr.Op {
+	case query.FilterOpIn, query.FilterOpNotIn:
+		found := false
+		for _, value := range expr.Values {
+			cmp, err := compareCandidate(field, candidate, value)
+			if err != nil {
+				return false, err
+			}
+			if cmp == 0 {
+				found = true
+				break
+			}
+		}
+		return found == (expr.Op == query.FilterOpIn), nil
+	case query.FilterOpLike:
+		text, ok := candidateString(candidate)
+		if !ok {
+			return false, mismatchedValue(field, expr.Value)
+		}
+		return strings.Contains(text, expr.Value.Normalized), nil
+	}
+
+	cmp, err := compareCandidate(field, candidate, expr.Value)
+	if err != nil {
+		return false, err
+	}
+	switch expr.Op {
+	case query.FilterOpEq:
+		return cmp == 0, nil
+	case query.FilterOpNe:
+		return cmp != 0, nil
+	case query.FilterOpGt:
+		return cmp > 0, nil
+	case query.FilterOpGte:
+		return cmp >= 0, nil
+	case query.FilterOpLt:
+		return cmp < 0, nil
+	case query.FilterOpLte:
+		return cmp <= 0, nil
+	default:
+		return false, errors.NewCode(errors.InvalidInput, "invalid filter operator").
+			WithContext("field", field).
+			WithContext("operator", string(expr.Op))
+	}
+}
+
+// compareCandidate 按 QueryValue 的类型比较候选值，返回 -1/0/1。
+func compareCandidate(field string, candidate any, value query.QueryValue) (int, error) {
+	switch value.Type {
+	case query.FieldTypeInt:
+		if n, ok := candidateInt(candidate); ok {
+			return compareOrdered(n, value.Int), nil
+		}
+	case query.FieldTypeFloat:
+		if f, ok := candidateFloat(candidate); ok {
+			return compareOrdered(f, value.Float), nil
+		}
+	case query.FieldTypeBool:
+		if rv := reflect.ValueOf(candidate); rv.Kind() == reflect.Bool {
+			return compareBool(rv.Bool(), value.Bool), nil
+		}
+	case query.FieldTypeTime:
+		if t, ok := candidate.(time.Time); ok {
+			return t.Compare(value.Time), nil
+		}
+	default:
+		if s, ok := candidateString(candidate); ok {
+			return strings.Compare(s, value.String), nil
+		}
+	}
+	return 0, mismatchedValue(field, value)
+}
+
+func compareOrdered[T int64 | float64](left, right T) int {
+	switch {
+	case left < right:
+		return -1
+	case left > right:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareBool(left, right bool) int {
+	switch {
+	case left == right:
+		return 0
+	case !left:
+		return -1
+	default:
+		return 1
+	}
+}
+
+func derefCandidate(raw any) (any, bool) {
+	if raw == nil {
+		return nil, true
+	}
+	rv := reflect.ValueOf(raw)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, true
+		}
+		rv = rv.Elem()
+	}
+	return rv.Interface(), false
+}
+
+func candidateString(candidate any) (string, bool) {
+	rv := reflect.ValueOf(candidate)
+	if rv.Kind() == reflect.String {
+		return rv.String(), true
+	}
+	return "", false
+}
+
+func candidateInt(candidate any) (int64, bool) {
+	rv := reflect.ValueOf(candidate)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), true
+	default:
+		return 0, false
+	}
+}
+
+func candidateFloat(candidate any) (float64, bool) {
+	rv := reflect.ValueOf(candidate)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		if n, ok := candidateInt(candidate); ok {
+			return float64(n), true
+		}
+		return 0, false
+	}
+}
+
+func mismatchedValue(field string, value query.QueryValue) error {
+	return errors.NewCode(errors.InvalidInput, "filter value type does not match field").
+		WithContext("field", field).
+		WithContext("value_type", string(value.Type))
+}
@@ -0,0 +1,54 @@
+package querymatch
+
+import (
+	"testing"
+
+	"gochen/db/query"
+	"gochen/errors"
+)
+
+// TestMatchFilters_EvaluatesTypedExpressions 验证内存匹配与 SQL 语义一致（含 NULL 三值逻辑）。
+func TestMatchFilters_EvaluatesTypedExpressions(t *testing.T) {
+	var deletedAt *string
+	record := MapLookup(map[string]any{
+		"name":       "Alice",
+		"age":        int(30),
+		"score":      float32(8.5),
+		"active":     true,
+		"deleted_at": deletedAt,
+	})
+
+	cases := []struct {
+		name    string
+		filters query.QueryFilters
+		want    bool
+	}{
+		{name: "range", filters: query.Where(query.IntRef("age").Gte(18), query.IntRef("age").Lt(65)), want: true},
+		{name: "in", filters: query.Where(query.StringRef("name").In("Bob", "Alice")), want: true},
+		{name: "like is case sensitive", filters: query.Where(query.StringRef("name").Like("ali")), want: false},
+		{name: "float", filters: query.Where(query.FloatRef("score").Gt(8)), want: true},
+		{name: "bool", filters: query.Where(query.BoolRef("active").Eq(false)), want: false},
+		{name: "is null", filters: query.Where(query.StringRef("deleted_at").IsNull()), want: true},
+		{name: "ne on null", filters: query.Where(query.StringRef("deleted_at").Ne("x")), want: false},
+	}
+	for _, tc := range cases {
+		got, err := MatchFilters(tc.filters, record)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+// TestMatchFilters_RejectsUnknownField 验证未知字段返回错误而不是静默放行。
+func TestMatchFilters_RejectsUnknownField(t *testing.T) {
+	record := MapLookup(map[string]any{"name": "Alice"})
+	if _, err := MatchFilters(query.Where(query.StringRef("nmae").Eq("Alice")), record); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected InvalidInput, got %v", err)
+	}
+	if _, err := MatchFilters(query.Where(query.IntRef("name").Eq(1)), record); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected type mismatch to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,236 @@
+package query
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"gochen/errors"
+)
+
+// FieldValue 约束强类型字段引用可承载的 Go 值类型。
+//
+// 说明：~string 允许业务自定义的字符串枚举类型（如 `type Status string`）。
+type FieldValue interface {
+	~string | ~int64 | ~float64 | ~bool | time.Time
+}
+
+// Condition 表示一条已绑定字段的强类型过滤条件。
+type Condition struct {
+	Field string
+	Expr  QueryExpr
+}
+
+// TypedField 是强类型字段引用，用于代替手写字段名与 QueryValue 构造过滤条件。
+//
+// 推荐在实体旁以包级变量声明一次，并同时用于 QuerySchema 声明与过滤条件构造：
+//
+//	var (
+//		UserName   = query.StringRef("name")
+//		UserAge    = query.IntRef("age")
+//		UserStatus = query.EnumRef[Status]("status")
+//	)
+//
+//	schema := query.NewQuerySchema(UserName.Declare(query.AllowSort()), UserAge.Declare(), UserStatus.Declare())
+//	opts := query.QueryOptions{Filters: query.Where(UserAge.Gte(18), UserStatus.In(StatusActive, StatusPending))}
+//
+// 这样字段名拼写只出现一次，值类型由编译器保证与字段类型一致。
+type TypedField[V FieldValue] struct {
+	name string
+	typ  FieldType
+}
+
+// StringRef 创建字符串字段引用。
+func StringRef(name string) TypedField[string] {
+	return TypedField[string]{name: strings.TrimSpace(name), typ: FieldTypeString}
+}
+
+// EnumRef 创建枚举字段引用。
+func EnumRef[V ~string](name string) TypedField[V] {
+	return TypedField[V]{name: strings.TrimSpace(name), typ: FieldTypeEnum}
+}
+
+// IntRef 创建整数字段引用。
+func IntRef(name string) TypedField[int64] {
+	return TypedField[int64]{name: strings.TrimSpace(name), typ: FieldTypeInt}
+}
+
+// FloatRef 创建浮点字段引用。
+func FloatRef(name string) TypedField[float64] {
+	return TypedField[float64]{name: strings.TrimSpace(name), typ: FieldTypeFloat}
+}
+
+// BoolRef 创建布尔字段引用。
+func BoolRef(name string) TypedField[bool] {
+	return TypedField[bool]{name: strings.TrimSpace(name), typ: FieldTypeBool}
+}
+
+// TimeRef 创建时间字段引用。
+func TimeRef(name string) TypedField[time.Time] {
+	return TypedField[time.Time]{name: strings.TrimSpace(name), typ: FieldTypeTime}
+}
+
+// Name 返回字段名。
+func (f TypedField[V]) Name() string { return f.name }
+
+// Type 返回字段类型。
+func (f TypedField[V]) Type() FieldType { return f.typ }
+
+// Declare 生成对应的 QueryField 声明，便于与 NewQuerySchema 共用同一份字段定义。
+func (f TypedField[V]) Declare(options ...FieldOption) QueryField {
+	return newQueryField(f.name, f.typ, options...)
+}
+
+// Eq 构造等于条件。
+func (f TypedField[V]) Eq(value V) Condition { return f.unary(FilterOpEq, value) }
+
+// Ne 构造不等于条件。
+func (f TypedField[V]) Ne(value V) Condition { return f.unary(FilterOpNe, value) }
+
+// Like 构造模糊匹配条件（仅对 string/enum 字段有意义，schema 校验会拒绝其他类型）。
+func (f TypedField[V]) Like(value V) Condition { return f.unary(FilterOpLike, value) }
+
+// Gt 构造大于条件。
+func (f TypedField[V]) Gt(value V) Condition { return f.unary(FilterOpGt, value) }
+
+// Gte 构造大于等于条件。
+func (f TypedField[V]) Gte(value V) Condition { return f.unary(FilterOpGte, value) }
+
+// Lt 构造小于条件。
+func (f TypedField[V]) Lt(value V) Condition { return f.unary(FilterOpLt, value) }
+
+// Lte 构造小于等于条件。
+func (f TypedField[V]) Lte(value V) Condition { return f.unary(FilterOpLte, value) }
+
+// In 构造 IN 条件。
+func (f TypedField[V]) In(values ...V) Condition { return f.list(FilterOpIn, values) }
+
+// NotIn 构造 NOT IN 条件。
+func (f TypedField[V]) NotIn(values ...V) Condition { return f.list(FilterOpNotIn, values) }
+
+// IsNull 构造 IS NULL 条件。
+func (f TypedField[V]) IsNull() Condition {
+	return Condition{Field: f.name, Expr: QueryExpr{Op: FilterOpIsNull}}
+}
+
+// NotNull 构造 IS NOT NULL 条件。
+func (f TypedField[V]) NotNull() Condition {
+	return Condition{Field: f.name, Expr: QueryExpr{Op: FilterOpNotNull}}
+}
+
+// Asc 构造升序排序。
+func (f TypedField[V]) Asc() Sort { return Sort{Field: f.name, Direction: ASC} }
+
+// Desc 构造降序排序。
+func (f TypedField[V]) Desc() Sort { return Sort{Field: f.name, Direction: DESC} }
+
+func (f TypedField[V]) unary(op FilterOp, value V) Condition {
+	return Condition{Field: f.name, Expr: QueryExpr{Op: op, Value: typedQueryValue(f.typ, value)}}
+}
+
+func (f TypedField[V]) list(op FilterOp, values []V) Condition {
+	out := make([]QueryValue, 0, len(values))
+	for _, value := range values {
+		out = append(out, typedQueryValue(f.typ, value))
+	}
+	return Condition{Field: f.name, Expr: QueryExpr{Op: op, Values: out}}
+}
+
+func typedQueryValue[V FieldValue](typ FieldType, value V) QueryValue {
+	if t, ok := any(value).(time.Time); ok {
+		return TimeValue(t)
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int64:
+		return IntValue(rv.Int())
+	case reflect.Float64:
+		return FloatValue(rv.Float())
+	case reflect.Bool:
+		return BoolValue(rv.Bool())
+	default:
+		if typ == FieldTypeEnum {
+			return EnumValue(rv.String())
+		}
+		return StringValue(rv.String())
+	}
+}
+
+// Where 把强类型条件组合为 QueryFilters；同字段的多个条件按 AND 语义归组。
+func Where(conditions ...Condition) QueryFilters {
+	var out QueryFilters
+	return out.With(conditions...)
+}
+
+// With 追加强类型条件，返回追加后的过滤集合。
+func (f QueryFilters) With(conditions ...Condition) QueryFilters {
+	for _, condition := range conditions {
+		f = f.Append(condition.Field, condition.Expr)
+	}
+	return f
+}
+
+// ValidateQueryFilters 严格校验已构造的 QueryFilters：字段必须在 schema 中声明、操作符必须被允许、
+// 值类型必须与字段类型一致。
+//
+// 说明：仓储对未知字段的处理方式不一（ORM 报错、内存仓储可能忽略），在进入仓储前统一校验，
+// 可避免字段拼写错误静默返回未过滤的结果。
+func (s *QuerySchema) ValidateQueryFilters(filters QueryFilters) error {
+	if filters.IsZero() || s == nil {
+		return nil
+	}
+	for name, exprs := range filters {
+		if len(exprs) == 0 {
+			continue
+		}
+		field, ok := s.Field(name)
+		if !ok {
+			return errors.NewCode(errors.InvalidInput, "invalid filter field").
+				WithContext("field", name)
+		}
+		for _, expr := range exprs {
+			if !field.supportsFilterOp(expr.Op) {
+				return errors.NewCode(errors.InvalidInput, "invalid filter operator").
+					WithContext("field", name).
+					WithContext("operator", string(expr.Op))
+			}
+			switch expr.Op {
+			case FilterOpIsNull, FilterOpNotNull:
+				continue
+			case FilterOpIn, FilterOpNotIn:
+				if len(expr.Values) == 0 {
+					return errors.NewCode(errors.InvalidInput, "empty filter value list").
+						WithContext("field", name)
+				}
+				for _, value := range expr.Values {
+					if !field.acceptsValueType(value.Type) {
+						return invalidFilterValueType(field, value.Type)
+					}
+				}
+			default:
+				if !field.acceptsValueType(expr.Value.Type) {
+					return invalidFilterValueType(field, expr.Value.Type)
+				}
+			}
+		}
+		if err := validateFieldExpressions(field, exprs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f QueryField) acceptsValueType(typ FieldType) bool {
+	if typ == f.Type {
+		return true
+	}
+	isText := func(t FieldType) bool { return t == FieldTypeString || t == FieldTypeEnum }
+	return isText(typ) && isText(f.Type)
+}
+
+func invalidFilterValueType(field QueryField, got FieldType) error {
+	return errors.NewCode(errors.InvalidInput, "invalid filter value type").
+		WithContext("field", field.Name).
+		WithContext("expected", string(field.Type)).
+		WithContext("actual", string(got))
+}
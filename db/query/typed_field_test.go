@@ -0,0 +1,68 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"gochen/errors"
+)
+
+type testStatus string
+
+var (
+	testUserName   = StringRef("name")
+	testUserAge    = IntRef("age")
+	testUserStatus = EnumRef[testStatus]("status")
+	testUserSince  = TimeRef("created_at")
+)
+
+// TestTypedField_BuildsTypedFilters 验证强类型字段引用生成带类型信息的 QueryFilters。
+func TestTypedField_BuildsTypedFilters(t *testing.T) {
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	filters := Where(
+		testUserAge.Gte(18),
+		testUserAge.Lt(65),
+		testUserStatus.In("active", "pending"),
+		testUserSince.Gt(since),
+		testUserName.NotNull(),
+	)
+
+	ages := filters.Get("age")
+	if len(ages) != 2 || ages[0].Op != FilterOpGte || ages[0].Value.Type != FieldTypeInt || ages[0].Value.Int != 18 {
+		t.Fatalf("unexpected age filters: %+v", ages)
+	}
+	status, ok := filters.First("status")
+	if !ok || status.Op != FilterOpIn || len(status.Values) != 2 || status.Values[1].Type != FieldTypeEnum || status.Values[1].String != "pending" {
+		t.Fatalf("unexpected status filter: %+v", status)
+	}
+	created, ok := filters.First("created_at")
+	if !ok || created.Value.Type != FieldTypeTime || !created.Value.Time.Equal(since) {
+		t.Fatalf("unexpected created_at filter: %+v", created)
+	}
+	if name, ok := filters.First("name"); !ok || name.Op != FilterOpNotNull {
+		t.Fatalf("unexpected name filter: %+v", name)
+	}
+	if sort := testUserAge.Desc(); sort.Field != "age" || sort.Direction != DESC {
+		t.Fatalf("unexpected sort: %+v", sort)
+	}
+}
+
+// TestQuerySchema_ValidateQueryFilters 验证 schema 严格拒绝未知字段、非法操作符与类型不匹配。
+func TestQuerySchema_ValidateQueryFilters(t *testing.T) {
+	schema := NewQuerySchema(testUserName.Declare(), testUserAge.Declare(), testUserStatus.Declare())
+
+	if err := schema.ValidateQueryFilters(Where(testUserAge.Gte(18), testUserName.Like("ali"))); err != nil {
+		t.Fatalf("expected valid filters, got %v", err)
+	}
+
+	cases := map[string]QueryFilters{
+		"unknown field": Where(IntRef("agee").Eq(1)),
+		"invalid op":    Where(testUserStatus.Gt("active")),
+		"type mismatch": Where(StringRef("age").Eq("18")),
+	}
+	for name, filters := range cases {
+		if err := schema.ValidateQueryFilters(filters); !errors.Is(err, errors.InvalidInput) {
+			t.Fatalf("%s: expected InvalidInput, got %v", name, err)
+		}
+	}
+}
@@ -162,3 +162,34 @@ func (c *Container) RegisterConstructor(constructor di.Constructor) error {
 	}
 	return nil
 }
+
+// RegisterConstructorWithLifetime 按指定生命周期注册构造函数。
+//
+// 单例与 RegisterConstructor 一致（支持多返回值）；transient/scoped 要求构造函数只有一个非 error 返回值。
+func (c *Container) RegisterConstructorWithLifetime(constructor di.Constructor, lifetime di.Lifetime) error {
+	if lifetime == di.LifetimeSingleton {
+		return c.RegisterConstructor(constructor)
+	}
+	if di.ConstructorValue(constructor) == nil {
+		return errors.NewCode(errors.InvalidInput, "constructor cannot be nil")
+	}
+	outs, err := splitConstructorOutputs(reflect.TypeOf(di.ConstructorValue(constructor)))
+	if err != nil {
+		return err
+	}
+	if len(outs) != 1 {
+		return errors.NewCode(errors.InvalidInput, "constructor with non-singleton lifetime must return exactly one service").
+			WithContext("lifetime", lifetime.String())
+	}
+
+	factory := di.NewFactory(di.ConstructorValue(constructor))
+	switch lifetime {
+	case di.LifetimeTransient:
+		return c.RegisterTransient(outs[0], factory)
+	case di.LifetimeScoped:
+		return c.RegisterScoped(outs[0], factory)
+	default:
+		return errors.NewCode(errors.InvalidInput, "unknown lifetime").
+			WithContext("lifetime", lifetime.String())
+	}
+}
@@ -17,6 +17,7 @@ type serviceLifetime uint8
 const (
 	lifetimeSingleton serviceLifetime = iota
 	lifetimeTransient
+	lifetimeScoped
 )
 
 type serviceEntry struct {
@@ -724,3 +724,161 @@ func TestContainer_RegisterInstance_RejectsTypedNilInstance(t *testing.T) {
 		t.Fatal("expected typed nil instance registration not to pollute container state")
 	}
 }
+
+type scopedTestRequest struct {
+	id int64
+}
+
+type scopedTestHandler struct {
+	req *scopedTestRequest
+}
+
+func TestContainer_Scope_SharesScopedInstancesWithinScope(t *testing.T) {
+	c := New()
+
+	var seq atomic.Int64
+	if err := c.RegisterConstructorWithLifetime(di.NewConstructor(func() *scopedTestRequest {
+		return &scopedTestRequest{id: seq.Add(1)}
+	}), di.LifetimeScoped); err != nil {
+		t.Fatalf("register scoped failed: %v", err)
+	}
+	if err := c.RegisterConstructorWithLifetime(di.NewConstructor(func(req *scopedTestRequest) *scopedTestHandler {
+		return &scopedTestHandler{req: req}
+	}), di.LifetimeTransient); err != nil {
+		t.Fatalf("register transient failed: %v", err)
+	}
+
+	handlerType := reflect.TypeOf((*scopedTestHandler)(nil))
+	requestType := reflect.TypeOf((*scopedTestRequest)(nil))
+
+	scope1 := c.NewScope(context.Background())
+	h1, err := scope1.Resolve(handlerType)
+	if err != nil {
+		t.Fatalf("resolve handler failed: %v", err)
+	}
+	h2, err := scope1.Resolve(handlerType)
+	if err != nil {
+		t.Fatalf("resolve handler failed: %v", err)
+	}
+	req1, err := scope1.Resolve(requestType)
+	if err != nil {
+		t.Fatalf("resolve request failed: %v", err)
+	}
+	if h1 == h2 {
+		t.Fatal("expected transient handler to be recreated")
+	}
+	if h1.(*scopedTestHandler).req != req1 || h2.(*scopedTestHandler).req != req1 {
+		t.Fatal("expected handlers in one scope to share the scoped request")
+	}
+
+	var req2 *scopedTestRequest
+	if err := c.NewScope(context.Background()).Invoke(di.NewInvocation(func(req *scopedTestRequest) { req2 = req })); err != nil {
+		t.Fatalf("invoke in scope failed: %v", err)
+	}
+	if req2 == nil || req2 == req1 {
+		t.Fatalf("expected a new scoped instance per scope, got %#v", req2)
+	}
+
+	if _, err := c.Resolve(requestType); !errors.Is(err, errors.Dependency) {
+		t.Fatalf("expected Dependency error resolving scoped service from root, got: %v", err)
+	}
+}
+
+func TestContainer_Scope_SingletonCannotCaptureScopedService(t *testing.T) {
+	c := New()
+
+	if err := c.RegisterScoped(reflect.TypeOf((*scopedTestRequest)(nil)), di.NewFactory(func() *scopedTestRequest {
+		return &scopedTestRequest{}
+	})); err != nil {
+		t.Fatalf("register scoped failed: %v", err)
+	}
+	if err := c.RegisterConstructor(di.NewConstructor(func(req *scopedTestRequest) *scopedTestHandler {
+		return &scopedTestHandler{req: req}
+	})); err != nil {
+		t.Fatalf("register singleton failed: %v", err)
+	}
+
+	_, err := c.NewScope(context.Background()).Resolve(reflect.TypeOf((*scopedTestHandler)(nil)))
+	if !errors.Is(err, errors.Dependency) {
+		t.Fatalf("expected Dependency error, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "outside a scope") {
+		t.Fatalf("expected captive dependency message, got: %v", err)
+	}
+}
+
+func TestContainer_RegisterConstructorWithLifetime_RejectsMultiOutputs(t *testing.T) {
+	c := New()
+
+	err := c.RegisterConstructorWithLifetime(di.NewConstructor(func() (*multiOutA, *multiOutB) {
+		return &multiOutA{}, &multiOutB{}
+	}), di.LifetimeScoped)
+	if !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected InvalidInput, got: %v", err)
+	}
+}
+
+type scopedTestCtxKey struct{}
+
+type scopedTestUnitOfWork struct {
+	tenant string
+	log    *[]string
+	name   string
+}
+
+func (u *scopedTestUnitOfWork) Close(ctx context.Context) error {
+	*u.log = append(*u.log, u.name+":"+ctx.Value(scopedTestCtxKey{}).(string))
+	return nil
+}
+
+type scopedTestCloser struct {
+	uow *scopedTestUnitOfWork
+	log *[]string
+}
+
+func (c *scopedTestCloser) Close() error {
+	*c.log = append(*c.log, "closer")
+	return errors.NewCode(errors.Internal, "close failed")
+}
+
+func TestContainer_Scope_InjectsContextAndClosesInReverseOrder(t *testing.T) {
+	c := New()
+
+	var closed []string
+	if err := c.RegisterConstructorWithLifetime(di.NewConstructor(func(ctx context.Context) *scopedTestUnitOfWork {
+		return &scopedTestUnitOfWork{tenant: ctx.Value(scopedTestCtxKey{}).(string), log: &closed, name: "uow"}
+	}), di.LifetimeScoped); err != nil {
+		t.Fatalf("register uow failed: %v", err)
+	}
+	if err := c.RegisterConstructorWithLifetime(di.NewConstructor(func(uow *scopedTestUnitOfWork) *scopedTestCloser {
+		return &scopedTestCloser{uow: uow, log: &closed}
+	}), di.LifetimeScoped); err != nil {
+		t.Fatalf("register closer failed: %v", err)
+	}
+
+	ctx := context.WithValue(context.Background(), scopedTestCtxKey{}, "t1")
+	scope := c.NewScope(ctx)
+	got, err := di.ResolveScoped[*scopedTestCloser](di.WithScope(ctx, scope))
+	if err != nil {
+		t.Fatalf("ResolveScoped failed: %v", err)
+	}
+	if got.uow.tenant != "t1" {
+		t.Fatalf("expected scope context to be injected, got tenant %q", got.uow.tenant)
+	}
+
+	if err := scope.Close(); !errors.Is(err, errors.Internal) {
+		t.Fatalf("expected close error to be reported, got: %v", err)
+	}
+	if strings.Join(closed, ",") != "closer,uow:t1" {
+		t.Fatalf("unexpected close order: %v", closed)
+	}
+	if err := scope.Close(); err != nil {
+		t.Fatalf("expected repeated Close to be a no-op, got: %v", err)
+	}
+	if _, err := scope.Resolve(reflect.TypeOf((*scopedTestUnitOfWork)(nil))); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected InvalidInput after Close, got: %v", err)
+	}
+	if _, err := di.ResolveScoped[*scopedTestCloser](context.Background()); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected InvalidInput without scope, got: %v", err)
+	}
+}
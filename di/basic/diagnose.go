@@ -42,9 +42,12 @@ func (c *Container) Diagnose() *di.DiagnoseResult {
 		info.Created = entry.created
 		entry.mu.Unlock()
 
-		if entry.lifetime == lifetimeSingleton {
+		switch entry.lifetime {
+		case lifetimeSingleton:
 			info.Lifetime = "singleton"
-		} else {
+		case lifetimeScoped:
+			info.Lifetime = "scoped"
+		default:
 			info.Lifetime = "transient"
 		}
 
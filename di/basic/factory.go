@@ -9,7 +9,7 @@ import (
 )
 
 // createInstance 执行工厂函数并返回实例。
-func (c *Container) createInstance(factory any) (instance any, err error) {
+func (c *Container) createInstance(scope *Scope, factory any) (instance any, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			instance = nil
@@ -45,7 +45,7 @@ func (c *Container) createInstance(factory any) (instance any, err error) {
 		return nil, errors.NewCode(errors.InvalidInput, "factory must return (T) or (T, error)")
 	}
 
-	args, err := c.buildCallArgs(ft)
+	args, err := c.buildCallArgs(scope, ft)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +82,7 @@ func (c *Container) createMultiInstance(constructor any, outTypes []reflect.Type
 		return nil, errors.NewCode(errors.InvalidInput, "constructor must be a function")
 	}
 
-	args, resolveErr := c.buildCallArgs(ft)
+	args, resolveErr := c.buildCallArgs(nil, ft)
 	if resolveErr != nil {
 		return zeroValues(outTypes), resolveErr
 	}
@@ -14,6 +14,10 @@ import (
 // - 函数最后一个返回值若为 error 且非 nil，会被包装成 Internal 错误返回；
 // - 不缓存调用结果；transient 服务每次都会重新解析。
 func (c *Container) Invoke(invocation di.Invocation) error {
+	return c.invoke(nil, invocation)
+}
+
+func (c *Container) invoke(scope *Scope, invocation di.Invocation) error {
 	if di.InvocationValue(invocation) == nil {
 		return errors.NewCode(errors.InvalidInput, "function cannot be nil")
 	}
@@ -24,7 +28,7 @@ func (c *Container) Invoke(invocation di.Invocation) error {
 	args := make([]reflect.Value, fv.Type().NumIn())
 	for i := 0; i < fv.Type().NumIn(); i++ {
 		paramType := fv.Type().In(i)
-		inst, err := c.resolveParameter(paramType)
+		inst, err := c.resolveParameter(scope, paramType)
 		if err != nil {
 			return errors.Wrap(err, errors.Dependency, "failed to resolve parameter").
 				WithContext("parameter_type", di.TypeKey(paramType)).
@@ -49,7 +53,7 @@ func (c *Container) Invoke(invocation di.Invocation) error {
 }
 
 // buildCallArgs 为反射调用构造自动注入参数列表。
-func (c *Container) buildCallArgs(ft reflect.Type) ([]reflect.Value, error) {
+func (c *Container) buildCallArgs(scope *Scope, ft reflect.Type) ([]reflect.Value, error) {
 	if c == nil {
 		return nil, errors.NewCode(errors.Internal, "container is nil")
 	}
@@ -57,7 +61,7 @@ func (c *Container) buildCallArgs(ft reflect.Type) ([]reflect.Value, error) {
 	args := make([]reflect.Value, ft.NumIn())
 	for i := 0; i < ft.NumIn(); i++ {
 		paramType := ft.In(i)
-		inst, err := c.resolveParameter(paramType)
+		inst, err := c.resolveParameter(scope, paramType)
 		if err != nil {
 			return nil, err
 		}
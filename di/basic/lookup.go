@@ -19,7 +19,7 @@ func (c *Container) Resolve(serviceType reflect.Type) (any, error) {
 	if !exists {
 		return nil, errors.NewCode(errors.NotFound, "service not registered").WithContext("service_type", di.TypeKey(serviceType))
 	}
-	return c.resolveEntry(di.TypeKey(serviceType), entry)
+	return c.resolveEntry(nil, di.TypeKey(serviceType), entry)
 }
 
 // IsRegistered 判断某个类型是否已注册。
@@ -57,18 +57,34 @@ func serviceEntryOutputType(entry *serviceEntry) reflect.Type {
 	return serviceOutputType(entry.factory)
 }
 
-func (c *Container) resolveEntry(serviceLabel string, entry *serviceEntry) (any, error) {
+// resolveEntry 按生命周期解析服务；scope 为 nil 表示在根容器（或单例的构造链路）中解析。
+func (c *Container) resolveEntry(scope *Scope, serviceLabel string, entry *serviceEntry) (any, error) {
 	if entry == nil {
 		return nil, errors.NewCode(errors.NotFound, "service not registered").WithContext("service", serviceLabel)
 	}
 
 	return c.withResolutionFrame(serviceLabel, func() (any, error) {
-		if entry.lifetime == lifetimeTransient {
-			inst, err := c.createInstance(entry.factory)
+		switch entry.lifetime {
+		case lifetimeTransient:
+			inst, err := c.createInstance(scope, entry.factory)
 			if err != nil {
 				return nil, wrapResolveEntryError(err, serviceLabel)
 			}
 			return inst, nil
+		case lifetimeScoped:
+			if scope == nil {
+				// 单例构造链路同样走到这里：单例不得捕获作用域服务。
+				return nil, errors.NewCode(errors.Dependency, "scoped service cannot be resolved outside a scope").
+					WithContext("service", serviceLabel)
+			}
+			slot, err := scope.slot(entry)
+			if err != nil {
+				return nil, err
+			}
+			entry = slot
+		default:
+			// 单例始终在根容器中构造，避免捕获某个作用域的实例。
+			scope = nil
 		}
 
 		entry.mu.Lock()
@@ -96,7 +112,10 @@ func (c *Container) resolveEntry(serviceLabel string, entry *serviceEntry) (any,
 		entry.creating = true
 		entry.mu.Unlock()
 
-		inst, err := c.createInstance(entry.factory)
+		inst, err := c.createInstance(scope, entry.factory)
+		if err == nil && entry.lifetime == lifetimeScoped {
+			scope.track(inst)
+		}
 
 		entry.mu.Lock()
 		entry.instance = inst
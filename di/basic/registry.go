@@ -45,6 +45,25 @@ func (c *Container) RegisterTransient(serviceType reflect.Type, factory di.Facto
 	return nil
 }
 
+// RegisterScoped 按类型注册作用域工厂；只能经 NewScope 返回的作用域解析。
+func (c *Container) RegisterScoped(serviceType reflect.Type, factory di.Factory) error {
+	if serviceType == nil {
+		return errors.NewCode(errors.InvalidInput, "service type cannot be nil")
+	}
+	if err := validateTypedFactoryCompatibility(serviceType, factory); err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.ensureServiceTypeAvailableLocked(serviceType); err != nil {
+		return err
+	}
+	c.typedServices[serviceType] = newServiceEntry(serviceType, di.FactoryValue(factory), lifetimeScoped)
+	return nil
+}
+
 func validateTypedFactoryCompatibility(serviceType reflect.Type, factory di.Factory) error {
 	if factory.IsZero() {
 		return errors.NewCode(errors.InvalidInput, "factory cannot be nil").
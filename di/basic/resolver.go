@@ -9,10 +9,14 @@ import (
 )
 
 // resolveParameter 解析Parameter。
-func (c *Container) resolveParameter(paramType reflect.Type) (any, error) {
+func (c *Container) resolveParameter(scope *Scope, paramType reflect.Type) (any, error) {
 	if paramType == nil {
 		return nil, errors.NewCode(errors.InvalidInput, "parameter type is nil")
 	}
+	if scope != nil && paramType == contextType {
+		// 作用域内的 context.Context 参数注入作用域 context（如请求上下文）。
+		return scope.ctx, nil
+	}
 
 	c.mutex.RLock()
 	key, candidates, ok := c.findRegisteredServiceKeyLocked(paramType)
@@ -24,7 +28,7 @@ func (c *Container) resolveParameter(paramType reflect.Type) (any, error) {
 		if !exists {
 			return nil, errors.NewCode(errors.NotFound, "service not registered").WithContext("service", key)
 		}
-		return c.resolveEntry(key, entry)
+		return c.resolveEntry(scope, key, entry)
 	}
 	if len(candidates) > 1 {
 		return nil, errors.NewCode(errors.Conflict, "multiple services match parameter type").
@@ -0,0 +1,144 @@
+package basic
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"gochen/di"
+	"gochen/errors"
+)
+
+// Scope 是基础容器的解析作用域：scoped 服务在同一 Scope 内只创建一次。
+//
+// Scope 共享根容器的注册表；在 Scope 上注册服务需回到根容器完成。
+type Scope struct {
+	root *Container
+	ctx  context.Context
+
+	mu      sync.Mutex
+	slots   map[*serviceEntry]*serviceEntry
+	created []any
+	closed  bool
+}
+
+var (
+	_ di.IScope        = (*Scope)(nil)
+	_ di.IScopeFactory = (*Container)(nil)
+
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// NewScope 以 ctx 创建一个新的解析作用域；ctx 为 nil 时使用 context.Background()。
+func (c *Container) NewScope(ctx context.Context) di.IScope {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &Scope{
+		root:  c,
+		ctx:   ctx,
+		slots: make(map[*serviceEntry]*serviceEntry),
+	}
+}
+
+// Context 返回创建作用域时的 context。
+func (s *Scope) Context() context.Context {
+	return s.ctx
+}
+
+// Resolve 按类型在作用域内解析依赖。
+func (s *Scope) Resolve(serviceType reflect.Type) (any, error) {
+	if serviceType == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "service type cannot be nil")
+	}
+	if err := s.ensureOpen(); err != nil {
+		return nil, err
+	}
+
+	s.root.mutex.RLock()
+	entry, exists := s.root.findServiceEntryByTypeLocked(serviceType)
+	s.root.mutex.RUnlock()
+	if !exists {
+		return nil, errors.NewCode(errors.NotFound, "service not registered").WithContext("service_type", di.TypeKey(serviceType))
+	}
+	return s.root.resolveEntry(s, di.TypeKey(serviceType), entry)
+}
+
+// IsRegistered 判断某个类型是否已在根容器注册。
+func (s *Scope) IsRegistered(serviceType reflect.Type) bool {
+	return s.root.IsRegistered(serviceType)
+}
+
+// Invoke 调用函数并按参数类型在作用域内解析依赖。
+func (s *Scope) Invoke(invocation di.Invocation) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+	return s.root.invoke(s, invocation)
+}
+
+// Close 按创建倒序释放 scoped 实例，并聚合返回释放错误。
+func (s *Scope) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	created := s.created
+	s.created = nil
+	s.slots = nil
+	s.mu.Unlock()
+
+	var errs []error
+	for i := len(created) - 1; i >= 0; i-- {
+		var err error
+		switch closer := created[i].(type) {
+		case interface{ Close(context.Context) error }:
+			err = closer.Close(s.ctx)
+		case interface{ Close() error }:
+			err = closer.Close()
+		default:
+			continue
+		}
+		if err != nil {
+			errs = append(errs, errors.Wrap(err, errors.Internal, "failed to close scoped service").
+				WithContext("service_type", di.TypeKey(reflect.TypeOf(created[i]))))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Scope) ensureOpen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errors.NewCode(errors.InvalidInput, "scope already closed")
+	}
+	return nil
+}
+
+// slot 返回 entry 在本作用域内的实例槽位（按需创建）。
+func (s *Scope) slot(entry *serviceEntry) (*serviceEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, errors.NewCode(errors.InvalidInput, "scope already closed")
+	}
+	if existing, ok := s.slots[entry]; ok {
+		return existing, nil
+	}
+	slot := newServiceEntry(entry.serviceType, entry.factory, lifetimeScoped)
+	s.slots[entry] = slot
+	return slot, nil
+}
+
+// track 记录新建的 scoped 实例，供 Close 释放。
+func (s *Scope) track(instance any) {
+	if instance == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.created = append(s.created, instance)
+}
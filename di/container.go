@@ -2,9 +2,15 @@
 //
 // 容器只服务组合根、Host 和框架装配阶段；业务运行期应通过构造函数参数接收依赖，
 // 不应把容器作为 Service Locator 传入业务对象。
+//
+// 构造函数注册（Provide / RegisterConstructor）按参数类型自动注入依赖，解析时检测循环依赖；
+// 生命周期分 singleton（默认）、transient 与 scoped（经 IScopeFactory.NewScope 创建的作用域内共享）。
 package di
 
-import "reflect"
+import (
+	"context"
+	"reflect"
+)
 
 // IRegistry 表示 DI 显式注册能力。
 type IRegistry interface {
@@ -27,6 +33,65 @@ type IConstructorRegistry interface {
 	RegisterConstructor(constructor Constructor) error
 }
 
+// Lifetime 表示服务实例的生命周期。
+type Lifetime uint8
+
+const (
+	// LifetimeSingleton 表示容器内只创建一次（默认）。
+	LifetimeSingleton Lifetime = iota
+	// LifetimeTransient 表示每次解析都重新创建。
+	LifetimeTransient
+	// LifetimeScoped 表示在同一 IScope 内只创建一次，不同作用域互不共享。
+	LifetimeScoped
+)
+
+// String 返回生命周期名称。
+func (l Lifetime) String() string {
+	switch l {
+	case LifetimeSingleton:
+		return "singleton"
+	case LifetimeTransient:
+		return "transient"
+	case LifetimeScoped:
+		return "scoped"
+	default:
+		return "unknown"
+	}
+}
+
+// IScopedRegistry 表示按作用域生命周期注册服务的能力。
+type IScopedRegistry interface {
+	// RegisterScoped 按类型注册作用域工厂；只能经 IScope 解析，单例依赖作用域服务时解析失败。
+	RegisterScoped(serviceType reflect.Type, factory Factory) error
+}
+
+// ILifetimeConstructorRegistry 表示按指定生命周期注册构造函数的能力。
+type ILifetimeConstructorRegistry interface {
+	// RegisterConstructorWithLifetime 按构造函数返回值注册服务；非单例生命周期要求构造函数只有一个非 error 返回值。
+	RegisterConstructorWithLifetime(constructor Constructor, lifetime Lifetime) error
+}
+
+// IScope 表示一个解析作用域（如一次请求或一次命令处理）。
+//
+// 作用域内 scoped 服务只创建一次；singleton 与 transient 服务的语义与根容器一致。
+type IScope interface {
+	IResolver
+	IInvoker
+
+	// Context 返回创建作用域时的 context；作用域内构造函数的 context.Context 参数注入该值。
+	Context() context.Context
+
+	// Close 按创建倒序释放作用域内实现 Close() error 或 Close(ctx) error 的 scoped 实例；
+	// 重复调用返回 nil，关闭后解析返回错误。
+	Close() error
+}
+
+// IScopeFactory 表示创建解析作用域的能力。
+type IScopeFactory interface {
+	// NewScope 以 ctx 创建一个新的解析作用域（如请求上下文，携带 principal、tenant、request id 等）。
+	NewScope(ctx context.Context) IScope
+}
+
 // IResolver 表示 DI 按类型解析能力。
 type IResolver interface {
 	// Resolve 按类型解析依赖。
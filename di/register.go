@@ -60,3 +60,43 @@ func RegisterInstance[T any](registry IRegistry, instance T) error {
 
 	return registry.RegisterInstance(t, NewInstance(instance))
 }
+
+// RegisterScoped 按泛型类型注册作用域工厂；registry 需实现 IScopedRegistry。
+func RegisterScoped[T any](registry IRegistry, factory any) error {
+	if registry == nil {
+		return errors.NewCode(errors.InvalidInput, "registry is nil")
+	}
+	scoped, ok := registry.(IScopedRegistry)
+	if !ok {
+		return errors.NewCode(errors.Unsupported, "registry does not support scoped lifetime")
+	}
+	t := reflect.TypeFor[T]()
+	if t == nil {
+		return errors.NewCode(errors.Internal, "cannot infer type for RegisterScoped")
+	}
+	return scoped.RegisterScoped(t, NewFactory(factory))
+}
+
+// Provide 注册构造函数：参数按类型自动注入，返回值（除尾部 error）作为服务暴露，默认单例。
+//
+//	di.Provide(container, func(db IDatabase, bus IEventBus) *OrderService { ... })
+//	di.Provide(container, NewRequestContext, di.LifetimeScoped)
+//
+// 非单例生命周期要求 registry 实现 ILifetimeConstructorRegistry。
+func Provide(registry IConstructorRegistry, constructor any, lifetime ...Lifetime) error {
+	if registry == nil {
+		return errors.NewCode(errors.InvalidInput, "registry is nil")
+	}
+	if len(lifetime) > 1 {
+		return errors.NewCode(errors.InvalidInput, "at most one lifetime can be specified")
+	}
+	if len(lifetime) == 0 || lifetime[0] == LifetimeSingleton {
+		return registry.RegisterConstructor(NewConstructor(constructor))
+	}
+	withLifetime, ok := registry.(ILifetimeConstructorRegistry)
+	if !ok {
+		return errors.NewCode(errors.Unsupported, "registry does not support constructor lifetimes").
+			WithContext("lifetime", lifetime[0].String())
+	}
+	return withLifetime.RegisterConstructorWithLifetime(NewConstructor(constructor), lifetime[0])
+}
@@ -72,3 +72,41 @@ func TestRegisterSingleton_Generic_InputValidation(t *testing.T) {
 		t.Fatalf("expected InvalidInput for non-assignable factory, got: %v", err)
 	}
 }
+
+type provideTestRepo struct{}
+
+type provideTestService struct {
+	repo IRegisterTestIface
+}
+
+func TestProvide_WiresConstructorParameters(t *testing.T) {
+	c := dibasic.New()
+
+	if err := di.Provide(c, func() IRegisterTestIface { return &registerTestImpl{} }); err != nil {
+		t.Fatalf("Provide repo failed: %v", err)
+	}
+	if err := di.Provide(c, func(repo IRegisterTestIface) *provideTestService {
+		return &provideTestService{repo: repo}
+	}, di.LifetimeTransient); err != nil {
+		t.Fatalf("Provide service failed: %v", err)
+	}
+
+	a, err := di.Resolve[*provideTestService](c)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	b, err := di.Resolve[*provideTestService](c)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if a == b || a.repo != b.repo || a.repo.Foo() != "ok" {
+		t.Fatalf("unexpected wiring: %#v %#v", a, b)
+	}
+
+	if err := di.Provide(c, func() *provideTestRepo { return nil }, di.LifetimeScoped, di.LifetimeTransient); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected InvalidInput for multiple lifetimes, got: %v", err)
+	}
+	if err := di.RegisterScoped[*provideTestRepo](di.RegistryOnly(c), func() *provideTestRepo { return &provideTestRepo{} }); !errors.Is(err, errors.Unsupported) {
+		t.Fatalf("expected Unsupported for registry without scopes, got: %v", err)
+	}
+}
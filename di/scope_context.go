@@ -0,0 +1,38 @@
+package di
+
+import (
+	"context"
+	"reflect"
+
+	"gochen/errors"
+)
+
+type scopeContextKey struct{}
+
+// WithScope 把作用域挂到 ctx 上，供 handler、命令钩子等下游经 ScopeFrom / ResolveScoped 取用。
+func WithScope(ctx context.Context, scope IScope) context.Context {
+	if ctx == nil || scope == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, scopeContextKey{}, scope)
+}
+
+// ScopeFrom 返回 ctx 上的作用域。
+func ScopeFrom(ctx context.Context) (IScope, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	scope, ok := ctx.Value(scopeContextKey{}).(IScope)
+	return scope, ok && scope != nil
+}
+
+// ResolveScoped 从 ctx 上的作用域按泛型类型解析依赖；ctx 未携带作用域时返回 InvalidInput。
+func ResolveScoped[T any](ctx context.Context) (T, error) {
+	var zero T
+	scope, ok := ScopeFrom(ctx)
+	if !ok {
+		return zero, errors.NewCode(errors.InvalidInput, "context has no DI scope").
+			WithContext("service_type", TypeKey(reflect.TypeFor[T]()))
+	}
+	return Resolve[T](scope)
+}
@@ -47,6 +47,17 @@ type FieldChange struct {
 	New   any    `json:"new"`
 }
 
+// ChangeEntry 是审计记录面向阅读的投影：谁（ChangedBy）在何时（ChangedAt）改了哪些字段（Changes）。
+//
+// 创建记录的 Changes 为全部业务字段（Old 为 nil）；删除/恢复等无字段差异的操作 Changes 为空。
+type ChangeEntry struct {
+	ID        int64          `json:"id"`
+	Operation AuditOperation `json:"operation"`
+	ChangedBy string         `json:"changed_by"`
+	ChangedAt time.Time      `json:"changed_at"`
+	Changes   []FieldChange  `json:"changes"`
+}
+
 // IAuditedRepository 带审计语义的实体仓储接口。
 //
 // # 错误契约。
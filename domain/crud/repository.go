@@ -64,6 +64,17 @@ type IPurgeRepository[T domain.IEntity[ID], ID comparable] interface {
 	Purge(ctx context.Context, id ID) error
 }
 
+// IPatchRepository 定义“按字段局部更新”的可选扩展能力。
+//
+// 说明：
+// - fields 为实体字段的 JSON 名称；仓储只把这些字段对应的列写入存储，其余列保持不变；
+// - 主键、版本、审计与数据范围等由服务端维护的字段不允许出现在 fields 中（返回 errors.InvalidInput）；
+// - 错误契约与 Update 一致：未找到返回 errors.NotFound，乐观锁冲突返回 errors.Concurrency。
+type IPatchRepository[T domain.IEntity[ID], ID comparable] interface {
+	// UpdateFields 以 entity 中的字段值更新 fields 对应的列。
+	UpdateFields(ctx context.Context, e T, fields []string) error
+}
+
 // IBatchOperations 定义批量操作接口（可选扩展）
 //
 // 提供批量 CRUD 操作能力，用于提升大量数据操作的性能。
@@ -83,3 +94,41 @@ type IBatchOperations[T domain.IEntity[ID], ID comparable] interface {
 	// DeleteAll 批量删除实体
 	DeleteAll(ctx context.Context, ids []ID) error
 }
+
+// IRepositoryUnwrapper 表示包装了另一个仓储的装饰器。
+//
+// 说明：
+// - 装饰器通常会静态实现全部可选扩展接口，以便透传底层能力；
+// - 因此调用方不应直接对装饰器做类型断言，而应使用 RepositoryAs 沿装饰链确认底层仓储确实具备该能力。
+type IRepositoryUnwrapper[T domain.IEntity[ID], ID comparable] interface {
+	// Unwrap 返回被包装的仓储。
+	Unwrap() IRepository[T, ID]
+}
+
+// RepositoryAs 探测仓储（含装饰链）是否具备可选能力 C，并返回最外层仓储的能力视图。
+//
+// 说明：装饰链上的每一层以及最底层仓储都必须实现 C，才视为具备该能力。
+func RepositoryAs[C any, T domain.IEntity[ID], ID comparable](repo IRepository[T, ID]) (C, bool) {
+	var zero C
+	if repo == nil {
+		return zero, false
+	}
+	capability, ok := any(repo).(C)
+	if !ok {
+		return zero, false
+	}
+	current := repo
+	for {
+		wrapper, isWrapper := current.(IRepositoryUnwrapper[T, ID])
+		if !isWrapper {
+			return capability, true
+		}
+		current = wrapper.Unwrap()
+		if current == nil {
+			return zero, false
+		}
+		if _, ok := any(current).(C); !ok {
+			return zero, false
+		}
+	}
+}
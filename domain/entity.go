@@ -44,6 +44,16 @@ type ISettableID[T comparable] interface {
 	SetID(id T)
 }
 
+// ISettableVersion 可选的乐观锁版本回写接口。
+//
+// 说明：
+// - 默认仓储在未启用审计字段时，通过该能力推进版本并以旧版本作为更新条件；
+// - API 层用它把请求体中的 version 还原为已校验的版本，避免请求体绕过 If-Match。
+type ISettableVersion interface {
+	// SetVersion 设置实体的乐观锁版本
+	SetVersion(version uint64)
+}
+
 // IValidatable 可验证接口。
 // 实现此接口的实体可以验证自身状态的有效性。
 type IValidatable interface {
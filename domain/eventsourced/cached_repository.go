@@ -0,0 +1,244 @@
+package eventsourced
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gochen/clock"
+	"gochen/errors"
+)
+
+// CacheConfig 聚合缓存配置。
+type CacheConfig struct {
+	TTL           time.Duration // 缓存过期时间（默认: 5分钟）
+	MaxAggregates int           // 最大缓存聚合数，超出后按 LRU 淘汰（默认: 1000）
+	Clock         clock.IClock  // TTL 过期判断的时间来源（默认: 真实时钟）
+}
+
+// DefaultCacheConfig 默认聚合缓存配置。
+func DefaultCacheConfig() *CacheConfig {
+	return &CacheConfig{TTL: 5 * time.Minute, MaxAggregates: 1000}
+}
+
+// CacheStats 聚合缓存统计。
+type CacheStats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Size      int
+}
+
+// CachedRepository 是聚合级缓存装饰器：在进程内 LRU 中保留热点聚合，减少"读-改-写"循环中的事件重放。
+//
+// 与 eventing/store/cached.CachedEventStore 缓存事件列表不同，这里缓存的是已重放完成的聚合实例：
+//   - 缓存条目以 ID + 版本为键：命中前先查询底层当前版本（GetAggregateVersion），版本不一致视为未命中，
+//     因此其他实例写入的事件不会被忽略；
+//   - 聚合实例可变且不会被并发共享，因此采用"签出"语义：Get 命中时把实例从缓存中取出（独占），
+//     Save 成功后再以新版本放回；同一聚合的并发 Get 中只有一个命中缓存，其余从底层重放，
+//     各自得到独立实例，由底层的乐观并发控制决定谁的 Save 生效；
+//   - Get 未命中时从底层加载的实例只有在 Save 成功后才进入缓存；签出实例的 Save 失败时该实例已被修改，直接丢弃，
+//     但不影响缓存中其他仍然有效的条目（例如并发请求已放回的新版本），命中前的版本校验会剔除过期条目；
+//   - Save 之后缓存持有该实例，调用方不应再修改它，后续修改请重新 Get。
+type CachedRepository[T IEventSourcedAggregate[ID], ID comparable] struct {
+	inner IEventSourcedRepository[T, ID]
+	ttl   time.Duration
+	max   int
+	clock clock.IClock
+
+	mutex   sync.Mutex
+	entries map[ID]*list.Element
+	lru     *list.List // Front 为最近使用
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
+}
+
+type cachedAggregateEntry[T any, ID comparable] struct {
+	id        ID
+	aggregate T
+	version   uint64
+	expiresAt time.Time
+}
+
+// NewCachedRepository 创建聚合级缓存仓储。
+func NewCachedRepository[T IEventSourcedAggregate[ID], ID comparable](repo IEventSourcedRepository[T, ID], config *CacheConfig) (*CachedRepository[T, ID], error) {
+	if repo == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "inner repository cannot be nil")
+	}
+	defaults := DefaultCacheConfig()
+	if config == nil {
+		config = defaults
+	}
+	ttl, maxAggregates := config.TTL, config.MaxAggregates
+	if ttl <= 0 {
+		ttl = defaults.TTL
+	}
+	if maxAggregates <= 0 {
+		maxAggregates = defaults.MaxAggregates
+	}
+	clk := config.Clock
+	if clk == nil {
+		clk = clock.NewRealClock()
+	}
+	return &CachedRepository[T, ID]{
+		inner:   repo,
+		ttl:     ttl,
+		max:     maxAggregates,
+		clock:   clk,
+		entries: make(map[ID]*list.Element),
+		lru:     list.New(),
+	}, nil
+}
+
+// Save 保存聚合；成功后以新版本放回缓存，失败时保留缓存中已有的条目（其有效性在命中前按底层版本校验）。
+func (r *CachedRepository[T, ID]) Save(ctx context.Context, aggregate T) error {
+	if any(aggregate) == nil {
+		return errors.NewCode(errors.InvalidInput, "aggregate cannot be nil")
+	}
+	if err := r.inner.Save(ctx, aggregate); err != nil {
+		return err
+	}
+	r.put(aggregate.GetID(), aggregate)
+	return nil
+}
+
+// Get 获取聚合；缓存命中且版本与底层一致时跳过事件重放。
+func (r *CachedRepository[T, ID]) Get(ctx context.Context, id ID) (T, error) {
+	if aggregate, ok := r.checkout(ctx, id); ok {
+		return aggregate, nil
+	}
+	return r.inner.Get(ctx, id)
+}
+
+// GetOrCreate 获取或创建聚合；缓存命中时直接返回缓存实例。
+func (r *CachedRepository[T, ID]) GetOrCreate(ctx context.Context, id ID) (T, error) {
+	if aggregate, ok := r.checkout(ctx, id); ok {
+		return aggregate, nil
+	}
+	return r.inner.GetOrCreate(ctx, id)
+}
+
+// Exists 检查聚合是否存在（直接委托底层）。
+func (r *CachedRepository[T, ID]) Exists(ctx context.Context, id ID) (bool, error) {
+	return r.inner.Exists(ctx, id)
+}
+
+// GetAggregateVersion 获取聚合当前版本（直接委托底层）。
+func (r *CachedRepository[T, ID]) GetAggregateVersion(ctx context.Context, id ID) (uint64, error) {
+	return r.inner.GetAggregateVersion(ctx, id)
+}
+
+// GetByIDAsOf 按时间重建历史状态（绕过缓存，委托 inner；inner 不支持时返回 errors.Unsupported）。
+func (r *CachedRepository[T, ID]) GetByIDAsOf(ctx context.Context, id ID, asOf time.Time) (T, error) {
+	temporal, ok := r.inner.(ITemporalRepository[T, ID])
+	if !ok {
+		var zero T
+		return zero, errors.NewCode(errors.Unsupported, "repository does not support point-in-time reads")
+	}
+	return temporal.GetByIDAsOf(ctx, id, asOf)
+}
+
+// GetByIDAtVersion 按版本重建历史状态（绕过缓存，委托 inner；inner 不支持时返回 errors.Unsupported）。
+func (r *CachedRepository[T, ID]) GetByIDAtVersion(ctx context.Context, id ID, version uint64) (T, error) {
+	temporal, ok := r.inner.(ITemporalRepository[T, ID])
+	if !ok {
+		var zero T
+		return zero, errors.NewCode(errors.Unsupported, "repository does not support point-in-time reads")
+	}
+	return temporal.GetByIDAtVersion(ctx, id, version)
+}
+
+// Invalidate 失效指定聚合的缓存。
+func (r *CachedRepository[T, ID]) Invalidate(id ID) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if elem, ok := r.entries[id]; ok {
+		r.lru.Remove(elem)
+		delete(r.entries, id)
+	}
+}
+
+// Purge 清空缓存。
+func (r *CachedRepository[T, ID]) Purge() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.entries = make(map[ID]*list.Element)
+	r.lru.Init()
+}
+
+// Stats 返回缓存统计。
+func (r *CachedRepository[T, ID]) Stats() CacheStats {
+	r.mutex.Lock()
+	size := r.lru.Len()
+	r.mutex.Unlock()
+	return CacheStats{
+		Hits:      r.hits.Load(),
+		Misses:    r.misses.Load(),
+		Evictions: r.evictions.Load(),
+		Size:      size,
+	}
+}
+
+// checkout 从缓存取出聚合（独占），并校验其版本与底层一致且没有未提交事件。
+func (r *CachedRepository[T, ID]) checkout(ctx context.Context, id ID) (T, bool) {
+	var zero T
+	r.mutex.Lock()
+	elem, ok := r.entries[id]
+	if ok {
+		r.lru.Remove(elem)
+		delete(r.entries, id)
+	}
+	r.mutex.Unlock()
+	if !ok {
+		r.misses.Add(1)
+		return zero, false
+	}
+
+	entry := elem.Value.(*cachedAggregateEntry[T, ID])
+	aggregate := entry.aggregate
+	if r.clock.Now().After(entry.expiresAt) ||
+		aggregate.GetVersion() != entry.version ||
+		len(aggregate.GetUncommittedEvents()) > 0 {
+		r.misses.Add(1)
+		return zero, false
+	}
+	current, err := r.inner.GetAggregateVersion(ctx, id)
+	if err != nil || current != entry.version {
+		r.misses.Add(1)
+		return zero, false
+	}
+	r.hits.Add(1)
+	return aggregate, true
+}
+
+// put 以聚合当前版本放入缓存，超出容量时淘汰最久未使用的条目。
+func (r *CachedRepository[T, ID]) put(id ID, aggregate T) {
+	if len(aggregate.GetUncommittedEvents()) > 0 {
+		return
+	}
+	entry := &cachedAggregateEntry[T, ID]{
+		id:        id,
+		aggregate: aggregate,
+		version:   aggregate.GetVersion(),
+		expiresAt: r.clock.Now().Add(r.ttl),
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if elem, ok := r.entries[id]; ok {
+		r.lru.Remove(elem)
+	}
+	r.entries[id] = r.lru.PushFront(entry)
+	for r.lru.Len() > r.max {
+		oldest := r.lru.Back()
+		r.lru.Remove(oldest)
+		delete(r.entries, oldest.Value.(*cachedAggregateEntry[T, ID]).id)
+		r.evictions.Add(1)
+	}
+}
+
+// 编译期断言：CachedRepository 实现 IEventSourcedRepository。
+var _ IEventSourcedRepository[*EventSourcedAggregate[int64], int64] = (*CachedRepository[*EventSourcedAggregate[int64], int64])(nil)
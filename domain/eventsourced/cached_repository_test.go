@@ -0,0 +1,259 @@
+package eventsourced
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"gochen/clock"
+	"gochen/errors"
+)
+
+// countingRepository 是记录重放次数的内存仓储。
+type countingRepository struct {
+	versions map[int64]uint64
+	loads    int
+}
+
+func (r *countingRepository) Save(_ context.Context, aggregate *TestAggregate) error {
+	if r.versions[aggregate.GetID()] != aggregate.GetExpectedVersion() {
+		return errors.NewCode(errors.Concurrency, "version mismatch")
+	}
+	r.versions[aggregate.GetID()] = aggregate.GetVersion()
+	aggregate.MarkEventsAsCommitted()
+	return nil
+}
+
+func (r *countingRepository) Get(_ context.Context, id int64) (*TestAggregate, error) {
+	version, ok := r.versions[id]
+	if !ok {
+		return nil, errors.NewCode(errors.NotFound, "aggregate not found")
+	}
+	r.loads++
+	agg := NewTestAggregate(id)
+	agg.SetVersion(version)
+	return agg, nil
+}
+
+func (r *countingRepository) GetOrCreate(ctx context.Context, id int64) (*TestAggregate, error) {
+	if _, ok := r.versions[id]; !ok {
+		return NewTestAggregate(id), nil
+	}
+	return r.Get(ctx, id)
+}
+
+func (r *countingRepository) Exists(_ context.Context, id int64) (bool, error) {
+	_, ok := r.versions[id]
+	return ok, nil
+}
+
+func (r *countingRepository) GetAggregateVersion(_ context.Context, id int64) (uint64, error) {
+	return r.versions[id], nil
+}
+
+// TestCachedRepository_ReadModifyWrite 验证读-改-写循环命中缓存、外部写入使缓存失效。
+func TestCachedRepository_ReadModifyWrite(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingRepository{versions: map[int64]uint64{}}
+	repo, err := NewCachedRepository[*TestAggregate, int64](inner, nil)
+	if err != nil {
+		t.Fatalf("NewCachedRepository failed: %v", err)
+	}
+
+	agg, err := repo.GetOrCreate(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetOrCreate failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := agg.ApplyAndRecord(&TestEvent{eventType: "TestEvent", data: "x"}); err != nil {
+			t.Fatalf("ApplyAndRecord failed: %v", err)
+		}
+		if err := repo.Save(ctx, agg); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		if agg, err = repo.Get(ctx, 1); err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+	}
+	if inner.loads != 0 {
+		t.Fatalf("expected no replay in read-modify-write loop, got %d loads", inner.loads)
+	}
+	if agg.GetVersion() != 3 {
+		t.Fatalf("expected version 3, got %d", agg.GetVersion())
+	}
+
+	// 其他实例写入导致版本前进：缓存条目不再可用。
+	if err := repo.Save(ctx, agg); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	inner.versions[1] = 4
+	if agg, err = repo.Get(ctx, 1); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if inner.loads != 1 || agg.GetVersion() != 4 {
+		t.Fatalf("expected reload at version 4, got loads=%d version=%d", inner.loads, agg.GetVersion())
+	}
+
+	stats := repo.Stats()
+	if stats.Hits != 3 || stats.Misses != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+// TestCachedRepository_SaveFailureAndEviction 验证保存失败保留有效缓存与 LRU 淘汰。
+func TestCachedRepository_SaveFailureAndEviction(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingRepository{versions: map[int64]uint64{}}
+	repo, err := NewCachedRepository[*TestAggregate, int64](inner, &CacheConfig{MaxAggregates: 1})
+	if err != nil {
+		t.Fatalf("NewCachedRepository failed: %v", err)
+	}
+
+	for _, id := range []int64{1, 2} {
+		agg := NewTestAggregate(id)
+		if err := agg.ApplyAndRecord(&TestEvent{eventType: "TestEvent"}); err != nil {
+			t.Fatalf("ApplyAndRecord failed: %v", err)
+		}
+		if err := repo.Save(ctx, agg); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	if stats := repo.Stats(); stats.Size != 1 || stats.Evictions != 1 {
+		t.Fatalf("expected LRU eviction, got %+v", stats)
+	}
+
+	stale := NewTestAggregate(2)
+	if err := stale.ApplyAndRecord(&TestEvent{eventType: "TestEvent"}); err != nil {
+		t.Fatalf("ApplyAndRecord failed: %v", err)
+	}
+	if err := repo.Save(ctx, stale); !errors.Is(err, errors.Concurrency) {
+		t.Fatalf("expected Concurrency, got %v", err)
+	}
+	if stats := repo.Stats(); stats.Size != 1 {
+		t.Fatalf("expected failed save to keep the valid cached aggregate, got %+v", stats)
+	}
+	cached, err := repo.Get(ctx, 2)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if cached == stale || inner.loads != 0 {
+		t.Fatalf("expected cached aggregate without replay, got stale=%v loads=%d", cached == stale, inner.loads)
+	}
+
+	if _, err := NewCachedRepository[*TestAggregate, int64](nil, nil); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected InvalidInput for nil repo, got %v", err)
+	}
+}
+
+// TestCachedRepository_TTLUsesInjectedClock 验证缓存条目按注入的时钟过期。
+func TestCachedRepository_TTLUsesInjectedClock(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewManualClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	inner := &countingRepository{versions: map[int64]uint64{}}
+	repo, err := NewCachedRepository[*TestAggregate, int64](inner, &CacheConfig{TTL: time.Minute, Clock: clk})
+	if err != nil {
+		t.Fatalf("NewCachedRepository failed: %v", err)
+	}
+
+	agg := NewTestAggregate(1)
+	if err := agg.ApplyAndRecord(&TestEvent{eventType: "TestEvent"}); err != nil {
+		t.Fatalf("ApplyAndRecord failed: %v", err)
+	}
+	if err := repo.Save(ctx, agg); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if agg, err = repo.Get(ctx, 1); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if inner.loads != 0 {
+		t.Fatalf("expected cache hit before TTL, got %d loads", inner.loads)
+	}
+	if err := repo.Save(ctx, agg); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	clk.Advance(2 * time.Minute)
+	if _, err := repo.Get(ctx, 1); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if inner.loads != 1 {
+		t.Fatalf("expected reload after TTL, got %d loads", inner.loads)
+	}
+}
+
+// lockedRepository 为 countingRepository 加锁，供并发测试使用。
+type lockedRepository struct {
+	mu sync.Mutex
+	countingRepository
+}
+
+func (r *lockedRepository) Save(ctx context.Context, aggregate *TestAggregate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.countingRepository.Save(ctx, aggregate)
+}
+
+func (r *lockedRepository) Get(ctx context.Context, id int64) (*TestAggregate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.countingRepository.Get(ctx, id)
+}
+
+func (r *lockedRepository) GetAggregateVersion(ctx context.Context, id int64) (uint64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.countingRepository.GetAggregateVersion(ctx, id)
+}
+
+// TestCachedRepository_ConcurrentGetChecksOutExclusively 验证并发 Get 只有一个签出缓存实例，其余各自重放，互不共享。
+func TestCachedRepository_ConcurrentGetChecksOutExclusively(t *testing.T) {
+	ctx := context.Background()
+	inner := &lockedRepository{countingRepository: countingRepository{versions: map[int64]uint64{}}}
+	repo, err := NewCachedRepository[*TestAggregate, int64](inner, nil)
+	if err != nil {
+		t.Fatalf("NewCachedRepository failed: %v", err)
+	}
+	agg := NewTestAggregate(1)
+	if err := agg.ApplyAndRecord(&TestEvent{eventType: "TestEvent"}); err != nil {
+		t.Fatalf("ApplyAndRecord failed: %v", err)
+	}
+	if err := repo.Save(ctx, agg); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	const readers = 8
+	results := make([]*TestAggregate, readers)
+	var wg sync.WaitGroup
+	for i := range readers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := repo.Get(ctx, 1)
+			if err != nil {
+				t.Errorf("Get failed: %v", err)
+				return
+			}
+			results[i] = got
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[*TestAggregate]struct{}, readers)
+	for _, got := range results {
+		if got == nil || got.GetVersion() != 1 {
+			t.Fatalf("expected aggregate at version 1, got %+v", got)
+		}
+		if _, dup := seen[got]; dup {
+			t.Fatal("expected concurrent Get calls not to share an aggregate instance")
+		}
+		seen[got] = struct{}{}
+	}
+	stats := repo.Stats()
+	if stats.Hits != 1 || stats.Misses != readers-1 || inner.loads != readers-1 {
+		t.Fatalf("expected exactly one checkout hit, got %+v loads=%d", stats, inner.loads)
+	}
+	if stats.Size != 0 {
+		t.Fatalf("expected checked-out entry to leave the cache until saved, got %+v", stats)
+	}
+}
@@ -12,6 +12,9 @@
 // 仓储 ports：
 //   - [IEventSourcedRepository] — 事件溯源仓储接口。
 //   - [IEventStore] — 领域事件存储接口。
+//   - [CachedRepository] — 聚合级 LRU 缓存装饰器（[NewCachedRepository]），减少读-改-写循环的事件重放。
+//   - [ITemporalRepository] / [IPointInTimeRestorer] — 按时间或版本重建历史状态（[RestorePoint]）。
+//   - [IStreamSplitter] — 在快照点关闭事件流并切换到新流，使无界聚合的重放耗时保持恒定。
 //
 // 反射元数据：
 //   - [Metadata] — 预编译的聚合元数据。
@@ -2,6 +2,7 @@ package eventsourced
 
 import (
 	"context"
+	"time"
 
 	"gochen/domain"
 )
@@ -95,3 +96,41 @@ type RestoreResult struct {
 	// Exists 聚合是否存在（version > 0）。
 	Exists bool
 }
+
+// RestorePoint 描述聚合的历史恢复时点。
+//
+// Version 与 AsOf 可同时设置，取两者中更早的一个；零值表示不限制该维度。
+type RestorePoint struct {
+	// Version 只重放版本号不大于该值的事件。
+	Version uint64
+
+	// AsOf 只重放时间戳不晚于该时刻的事件。
+	AsOf time.Time
+}
+
+// IPointInTimeRestorer 可选能力：把聚合恢复到历史时点（由 IDomainEventStore 实现按需提供）。
+//
+// 历史恢复不使用快照（快照只代表最新状态附近的某个版本），总是从版本 1 开始重放。
+// 恢复到的时点之前不存在事件时返回 Exists=false，与 RestoreAggregate 一致。
+type IPointInTimeRestorer[ID comparable] interface {
+	RestoreAggregateAt(ctx context.Context, aggregate IEventSourcedAggregate[ID], point RestorePoint) (*RestoreResult, error)
+}
+
+// ITemporalRepository 可选能力：按历史时点只读地重建聚合（审计/排障视图）。
+//
+// 返回的聚合仅反映历史状态，不应再用于 Save（其版本落后于事件流，保存将返回 errors.Concurrency）。
+//
+//   - GetByIDAsOf: 重放时间戳不晚于 asOf 的事件；该时刻之前聚合不存在返回 errors.NotFound。
+//   - GetByIDAtVersion: 重放版本号不大于 version 的事件；聚合未达到该版本返回 errors.NotFound。
+type ITemporalRepository[T IEventSourcedAggregate[ID], ID comparable] interface {
+	GetByIDAsOf(ctx context.Context, id ID, asOf time.Time) (T, error)
+	GetByIDAtVersion(ctx context.Context, id ID, version uint64) (T, error)
+}
+
+// IStreamSplitter 可选能力：在当前版本处关闭聚合事件流并切换到新流（由 IDomainEventStore 实现按需提供）。
+//
+// 用于长生命周期聚合（如账本）：新流以当前状态的检查点开头，后续重放只需从新流开始，重放耗时不再随历史增长。
+// 逻辑版本号在切换前后保持连续；旧流保留用于审计与历史时点恢复。
+type IStreamSplitter[ID comparable] interface {
+	SplitStream(ctx context.Context, aggregate IEventSourcedAggregate[ID], newStreamID ID) error
+}
@@ -42,6 +42,8 @@ const (
 	Dependency ErrorCode = "DEPENDENCY_ERROR"
 	// Concurrency 表示并发写入或乐观锁冲突。
 	Concurrency ErrorCode = "CONCURRENCY_ERROR"
+	// PreconditionFailed 表示请求携带的前置条件（如 If-Match）与资源当前状态不符。
+	PreconditionFailed ErrorCode = "PRECONDITION_FAILED"
 
 	// Database 表示数据库访问失败。
 	Database ErrorCode = "DATABASE_ERROR"
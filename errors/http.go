@@ -26,6 +26,8 @@ func ErrorCodeToHTTPStatus(code ErrorCode) int {
 		return 413
 	case Conflict, Duplicate, Concurrency:
 		return 409
+	case PreconditionFailed:
+		return 412
 	case Unauthorized:
 		return 401
 	case Forbidden:
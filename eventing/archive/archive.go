@@ -0,0 +1,114 @@
+// Package archive 把聚合中已关闭的历史事件区间导出到对象存储（S3/MinIO 等），并收缩热存储。
+//
+// 组成：
+//   - Archiver：把"热窗口"之前的事件区间编码为归档段写入对象存储，更新清单后从热存储删除；
+//   - ArchivedEventStore：事件存储装饰器，读取需要热窗口之前的版本时透明回退到归档段；
+//   - IObjectStore / IEncoder：对象存储与编码扩展点。框架核心不引入 S3 SDK 与 Parquet 依赖，
+//     内置 JSONL 编码与内存对象存储，S3 兼容存储的适配示例见 README.md。
+//
+// 对象布局（KeyPrefix 默认 "gochen/events/"）：
+//
+//	<KeyPrefix><aggregate_id>/manifest.json
+//	<KeyPrefix><aggregate_id>/<aggregate_type>/<from>-<to>.<format>
+//
+// 聚合最新事件始终保留在热存储中，乐观锁与版本查询不受归档影响。
+package archive
+
+import (
+	"context"
+	"time"
+
+	"gochen/eventing/store"
+)
+
+const (
+	// DefaultKeyPrefix 是默认的对象键前缀。
+	DefaultKeyPrefix = "gochen/events/"
+	// DefaultHotWindow 是默认保留在热存储中的最近事件数。
+	DefaultHotWindow = 1000
+	// DefaultMinSegmentEvents 是默认的单段最少事件数，不足时跳过归档。
+	DefaultMinSegmentEvents = 1000
+	// DefaultMaxSegmentEvents 是默认的单段最多事件数。
+	DefaultMaxSegmentEvents = 10000
+)
+
+// IObjectStore 是归档所需的最小对象存储能力（S3/MinIO/GCS 等）。
+type IObjectStore interface {
+	// Put 写入对象（覆盖）。
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get 读取对象；不存在时返回 NotFound 错误。
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete 删除对象；不存在不视为错误。
+	Delete(ctx context.Context, key string) error
+}
+
+// IArchivableEventStore 是可被归档的热存储：在流式读取之外支持删除已归档的事件。
+type IArchivableEventStore[ID comparable] interface {
+	store.IEventStreamStore[ID]
+	// PurgeEvents 删除指定聚合类型下版本不大于 throughVersion 的事件；不得删除最新事件。
+	PurgeEvents(ctx context.Context, aggregateType string, aggregateID ID, throughVersion uint64) (int64, error)
+}
+
+// Config 定义归档配置；Archiver 与 ArchivedEventStore 须使用相同的 KeyPrefix 与编码。
+type Config struct {
+	// KeyPrefix 为空时使用 DefaultKeyPrefix。
+	KeyPrefix string
+	// HotWindow 为保留在热存储中的最近事件数，为 0 时使用 DefaultHotWindow。
+	HotWindow uint64
+	// MinSegmentEvents 为单段最少事件数，为 0 时使用 DefaultMinSegmentEvents。
+	MinSegmentEvents int
+	// MaxSegmentEvents 为单段最多事件数，为 0 时使用 DefaultMaxSegmentEvents；更长的区间分多次归档。
+	MaxSegmentEvents int
+	// Encoder 为空时使用 JSONLEncoder。
+	Encoder IEncoder
+}
+
+func (c Config) withDefaults() Config {
+	if c.KeyPrefix == "" {
+		c.KeyPrefix = DefaultKeyPrefix
+	}
+	if c.HotWindow == 0 {
+		c.HotWindow = DefaultHotWindow
+	}
+	if c.MinSegmentEvents <= 0 {
+		c.MinSegmentEvents = DefaultMinSegmentEvents
+	}
+	if c.MaxSegmentEvents <= 0 {
+		c.MaxSegmentEvents = DefaultMaxSegmentEvents
+	}
+	if c.MaxSegmentEvents < c.MinSegmentEvents {
+		c.MaxSegmentEvents = c.MinSegmentEvents
+	}
+	if c.Encoder == nil {
+		c.Encoder = JSONLEncoder{}
+	}
+	return c
+}
+
+// Segment 描述一个归档段（同一聚合类型下连续的版本区间）。
+type Segment struct {
+	AggregateType string    `json:"aggregate_type"`
+	FromVersion   uint64    `json:"from_version"`
+	ToVersion     uint64    `json:"to_version"`
+	EventCount    int       `json:"event_count"`
+	Key           string    `json:"key"`
+	Format        string    `json:"format"`
+	ArchivedAt    time.Time `json:"archived_at"`
+}
+
+// Manifest 是单个聚合的归档清单。
+type Manifest struct {
+	AggregateID string    `json:"aggregate_id"`
+	Segments    []Segment `json:"segments"`
+}
+
+// ArchivedThrough 返回指定聚合类型已归档的最高版本；未归档时返回 0。
+func (m *Manifest) ArchivedThrough(aggregateType string) uint64 {
+	var through uint64
+	for _, seg := range m.Segments {
+		if seg.AggregateType == aggregateType && seg.ToVersion > through {
+			through = seg.ToVersion
+		}
+	}
+	return through
+}
@@ -0,0 +1,142 @@
+package archive
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/store"
+	"gochen/eventing/store/sqlstore"
+)
+
+// 编译期断言：内置存储可作为归档热存储。
+var (
+	_ IArchivableEventStore[int64] = (*store.MemoryEventStore)(nil)
+	_ IArchivableEventStore[int64] = (*sqlstore.SQLEventStore[int64])(nil)
+	_ IArchivableEventStore[int64] = (*sqlstore.PartitionedEventStore[int64])(nil)
+)
+
+func appendOrderEvents(t *testing.T, es store.IEventStore[int64], id int64, count int) {
+	t.Helper()
+	events := make([]eventing.IStorableEvent[int64], 0, count)
+	for v := 1; v <= count; v++ {
+		events = append(events, eventing.NewEvent[int64](id, "Order", "OrderChanged", uint64(v), map[string]any{"n": v}))
+	}
+	require.NoError(t, es.AppendEvents(context.Background(), id, events, 0))
+}
+
+func versions(events []eventing.Event[int64]) []uint64 {
+	out := make([]uint64, len(events))
+	for i := range events {
+		out[i] = events[i].Version
+	}
+	return out
+}
+
+// TestArchiver_ArchiveAndTransparentLoad 验证归档区间写入对象存储、热存储收缩，且读取透明回退到归档。
+func TestArchiver_ArchiveAndTransparentLoad(t *testing.T) {
+	ctx := context.Background()
+	hot := store.NewMemoryEventStore()
+	objects := NewMemoryObjectStore()
+	cfg := Config{KeyPrefix: "archive/", HotWindow: 3, MinSegmentEvents: 2, MaxSegmentEvents: 4}
+	appendOrderEvents(t, hot, 1, 10)
+
+	archiver, err := NewArchiver[int64](hot, objects, cfg)
+	require.NoError(t, err)
+
+	seg, err := archiver.ArchiveAggregate(ctx, "Order", 1)
+	require.NoError(t, err)
+	require.NotNil(t, seg)
+	require.Equal(t, uint64(1), seg.FromVersion)
+	require.Equal(t, uint64(4), seg.ToVersion)
+	seg, err = archiver.ArchiveAggregate(ctx, "Order", 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), seg.ToVersion)
+	seg, err = archiver.ArchiveAggregate(ctx, "Order", 1)
+	require.NoError(t, err)
+	require.Nil(t, seg, "only the hot window remains")
+
+	require.Equal(t, []string{
+		"archive/1/Order/00000000000000000001-00000000000000000004.jsonl",
+		"archive/1/Order/00000000000000000005-00000000000000000007.jsonl",
+		"archive/1/manifest.json",
+	}, objects.Keys("archive/"))
+	remaining, err := hot.LoadEvents(ctx, 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{8, 9, 10}, versions(remaining))
+
+	es, err := NewArchivedEventStore[int64](hot, objects, cfg)
+	require.NoError(t, err)
+
+	all, err := es.LoadEvents(ctx, 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, versions(all))
+	require.Equal(t, "OrderChanged", all[0].GetType())
+
+	partial, err := es.LoadEventsByType(ctx, "Order", 1, 5)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{6, 7, 8, 9, 10}, versions(partial))
+
+	it, err := es.LoadEventsIter(ctx, 1, 2)
+	require.NoError(t, err)
+	iterated, err := store.CollectEvents(it)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{3, 4, 5, 6, 7, 8, 9, 10}, versions(iterated))
+
+	paged, err := store.CollectEvents(store.NewAggregateStreamIterator(ctx, es.StreamAggregate, store.AggregateStreamOptions[int64]{
+		AggregateType: "Order", AggregateID: 1, Limit: 2,
+	}))
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, versions(paged))
+
+	count, err := es.CountEvents(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), count)
+
+	// 版本检查仍基于热存储中的最新事件。
+	require.NoError(t, es.AppendEvents(ctx, 1, []eventing.IStorableEvent[int64]{
+		eventing.NewEvent[int64](1, "Order", "OrderChanged", 11, nil),
+	}, 10))
+}
+
+// TestArchiver_SkipsShortRanges 验证区间不足 MinSegmentEvents 时不归档。
+func TestArchiver_SkipsShortRanges(t *testing.T) {
+	hot := store.NewMemoryEventStore()
+	appendOrderEvents(t, hot, 1, 5)
+	archiver, err := NewArchiver[int64](hot, NewMemoryObjectStore(), Config{HotWindow: 3, MinSegmentEvents: 3})
+	require.NoError(t, err)
+
+	seg, err := archiver.ArchiveAggregate(context.Background(), "Order", 1)
+	require.NoError(t, err)
+	require.Nil(t, seg)
+
+	_, err = archiver.ArchiveAggregate(context.Background(), "", 1)
+	require.True(t, errors.Is(err, errors.InvalidInput))
+}
+
+// TestJSONLEncoder_RoundTrip 验证 JSONL 编解码保留事件字段。
+func TestJSONLEncoder_RoundTrip(t *testing.T) {
+	evt := eventing.NewEvent[int64](7, "Order", "OrderPlaced", 3, map[string]any{"sku": "a"}, 2)
+	evt.Metadata.Set("tenant_id", "acme")
+	rec, err := toRecord(evt)
+	require.NoError(t, err)
+
+	data, err := JSONLEncoder{}.Encode([]Record{rec, rec})
+	require.NoError(t, err)
+	decoded, err := JSONLEncoder{}.Decode(data)
+	require.NoError(t, err)
+	require.Len(t, decoded, 2)
+
+	restored := toEvent(decoded[0], int64(7))
+	require.Equal(t, evt.ID, restored.ID)
+	require.Equal(t, uint64(3), restored.Version)
+	require.Equal(t, 2, restored.SchemaVersion)
+	tenant, _ := restored.GetMetadata().GetString("tenant_id")
+	require.Equal(t, "acme", tenant)
+	var payload map[string]string
+	require.NoError(t, restored.Payload.DecodeTo(&payload))
+	require.Equal(t, "a", payload["sku"])
+}
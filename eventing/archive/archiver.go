@@ -0,0 +1,127 @@
+package archive
+
+import (
+	"context"
+	"time"
+
+	"gochen/errors"
+	"gochen/eventing/store"
+	"gochen/logging"
+)
+
+// Archiver 把聚合热窗口之前的事件区间归档到对象存储，并从热存储删除。
+//
+// 每次 ArchiveAggregate 按顺序执行：写入归档段 → 更新清单 → 删除热存储中的已归档事件。
+// 任一步失败时已写入的对象保持可读，ArchivedEventStore 只在热存储缺失对应版本时才读取归档，
+// 因此重试是安全的。同一聚合的归档应由单个工作者串行执行（清单为读-改-写）。
+type Archiver[ID comparable] struct {
+	source  IArchivableEventStore[ID]
+	catalog *catalog
+	config  Config
+	logger  logging.ILogger
+}
+
+// NewArchiver 创建归档器。
+func NewArchiver[ID comparable](source IArchivableEventStore[ID], objects IObjectStore, config Config) (*Archiver[ID], error) {
+	if source == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "archive source event store cannot be nil")
+	}
+	if objects == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "archive object store cannot be nil")
+	}
+	config = config.withDefaults()
+	return &Archiver[ID]{
+		source:  source,
+		catalog: &catalog{objects: objects, config: config},
+		config:  config,
+		logger:  logging.ComponentLogger("eventing.archive"),
+	}, nil
+}
+
+// ArchiveAggregate 归档指定聚合的下一个已关闭区间。
+//
+// 可归档区间为"上次归档版本之后、热窗口之前"，长度不足 MinSegmentEvents 时返回 (nil, nil)；
+// 超过 MaxSegmentEvents 时只归档前 MaxSegmentEvents 条，调用方可重复调用直到返回 nil。
+func (a *Archiver[ID]) ArchiveAggregate(ctx context.Context, aggregateType string, aggregateID ID) (*Segment, error) {
+	if aggregateType == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "aggregate type cannot be empty")
+	}
+	head, err := a.source.HeadVersion(ctx, aggregateType, aggregateID)
+	if err != nil {
+		return nil, err
+	}
+	if head <= a.config.HotWindow {
+		return nil, nil
+	}
+	key := aggregateKey(aggregateID)
+	manifest, err := a.catalog.loadManifest(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	from := manifest.ArchivedThrough(aggregateType) + 1
+	through := min(head-a.config.HotWindow, from+uint64(a.config.MaxSegmentEvents)-1)
+	if through < from || through-from+1 < uint64(a.config.MinSegmentEvents) {
+		return nil, nil
+	}
+
+	records := make([]Record, 0, through-from+1)
+	it := store.NewAggregateStreamIterator(ctx, a.source.StreamAggregate, store.AggregateStreamOptions[ID]{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		AfterVersion:  from - 1,
+	})
+	defer func() { _ = it.Close() }()
+	for it.Next() {
+		evt := it.Event()
+		if evt.Version > through {
+			break
+		}
+		rec, err := toRecord(evt)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.Internal, "encode archived event failed").WithContext("event_id", evt.ID)
+		}
+		records = append(records, rec)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	// 已被删除或从未写入的版本不会出现在记录中，以实际读到的区间为准。
+	from, through = records[0].Version, records[len(records)-1].Version
+
+	data, err := a.config.Encoder.Encode(records)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "encode archive segment failed")
+	}
+	segment := Segment{
+		AggregateType: aggregateType,
+		FromVersion:   from,
+		ToVersion:     through,
+		EventCount:    len(records),
+		Key:           a.catalog.segmentKey(key, aggregateType, from, through),
+		Format:        a.config.Encoder.Format(),
+		ArchivedAt:    time.Now(),
+	}
+	if err := a.catalog.objects.Put(ctx, segment.Key, data, a.config.Encoder.ContentType()); err != nil {
+		return nil, errors.Wrap(err, errors.Dependency, "write archive segment failed").WithContext("key", segment.Key)
+	}
+	manifest.Segments = append(manifest.Segments, segment)
+	if err := a.catalog.saveManifest(ctx, manifest); err != nil {
+		return nil, err
+	}
+	purged, err := a.source.PurgeEvents(ctx, aggregateType, aggregateID, through)
+	if err != nil {
+		return nil, err
+	}
+
+	a.logger.Info(ctx, "events archived",
+		logging.Any("aggregate_id", aggregateID),
+		logging.String("aggregate_type", aggregateType),
+		logging.Uint64("from_version", from),
+		logging.Uint64("to_version", through),
+		logging.Int64("purged_count", purged),
+		logging.String("key", segment.Key))
+	return &segment, nil
+}
@@ -0,0 +1,99 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"gochen/errors"
+	"gochen/eventing"
+)
+
+// catalog 封装对象键布局与清单/归档段读写，供 Archiver 与 ArchivedEventStore 共用。
+type catalog struct {
+	objects IObjectStore
+	config  Config
+}
+
+func aggregateKey[ID comparable](aggregateID ID) string {
+	return url.PathEscape(fmt.Sprint(aggregateID))
+}
+
+func (c *catalog) manifestKey(aggregateKey string) string {
+	return c.config.KeyPrefix + aggregateKey + "/manifest.json"
+}
+
+func (c *catalog) segmentKey(aggregateKey, aggregateType string, from, to uint64) string {
+	// 版本号补零，使同一聚合类型下的段按键名有序。
+	return fmt.Sprintf("%s%s/%s/%020d-%020d.%s", c.config.KeyPrefix, aggregateKey, url.PathEscape(aggregateType), from, to, c.config.Encoder.Format())
+}
+
+// loadManifest 读取清单；不存在时返回空清单。
+func (c *catalog) loadManifest(ctx context.Context, aggregateKey string) (*Manifest, error) {
+	data, err := c.objects.Get(ctx, c.manifestKey(aggregateKey))
+	if err != nil {
+		if errors.Is(err, errors.NotFound) {
+			return &Manifest{AggregateID: aggregateKey}, nil
+		}
+		return nil, errors.Wrap(err, errors.Dependency, "load archive manifest failed").WithContext("aggregate_id", aggregateKey)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "decode archive manifest failed").WithContext("aggregate_id", aggregateKey)
+	}
+	return &manifest, nil
+}
+
+func (c *catalog) saveManifest(ctx context.Context, manifest *Manifest) error {
+	sort.Slice(manifest.Segments, func(i, j int) bool {
+		a, b := manifest.Segments[i], manifest.Segments[j]
+		if a.AggregateType != b.AggregateType {
+			return a.AggregateType < b.AggregateType
+		}
+		return a.FromVersion < b.FromVersion
+	})
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return errors.Wrap(err, errors.Internal, "encode archive manifest failed")
+	}
+	if err := c.objects.Put(ctx, c.manifestKey(manifest.AggregateID), data, "application/json"); err != nil {
+		return errors.Wrap(err, errors.Dependency, "save archive manifest failed").WithContext("aggregate_id", manifest.AggregateID)
+	}
+	return nil
+}
+
+// loadArchived 读取版本在 (afterVersion, beforeVersion) 区间内的归档事件，按版本升序返回。
+//
+// aggregateType 为空时读取该聚合 ID 下所有类型的归档段。
+func loadArchived[ID comparable](ctx context.Context, c *catalog, aggregateType string, aggregateID ID, afterVersion, beforeVersion uint64) ([]eventing.Event[ID], error) {
+	manifest, err := c.loadManifest(ctx, aggregateKey(aggregateID))
+	if err != nil {
+		return nil, err
+	}
+	events := make([]eventing.Event[ID], 0)
+	for _, seg := range manifest.Segments {
+		if aggregateType != "" && seg.AggregateType != aggregateType {
+			continue
+		}
+		if seg.ToVersion <= afterVersion || seg.FromVersion >= beforeVersion {
+			continue
+		}
+		data, err := c.objects.Get(ctx, seg.Key)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.Dependency, "load archive segment failed").WithContext("key", seg.Key)
+		}
+		records, err := c.config.Encoder.Decode(data)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.Internal, "decode archive segment failed").WithContext("key", seg.Key)
+		}
+		for _, rec := range records {
+			if rec.Version > afterVersion && rec.Version < beforeVersion {
+				events = append(events, toEvent(rec, aggregateID))
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Version < events[j].Version })
+	return events, nil
+}
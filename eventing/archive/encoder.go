@@ -0,0 +1,123 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"gochen/eventing"
+	"gochen/messaging"
+)
+
+// Record 是归档段中的单条事件记录（聚合 ID 由清单确定，不重复存储）。
+//
+// 载荷保留为 JSON bytes，回放时与 SQL 存储读取的事件一样经 upcast/hydration 解码。
+type Record struct {
+	ID            string                `json:"id"`
+	Kind          messaging.MessageKind `json:"kind,omitempty"`
+	Type          string                `json:"type"`
+	Timestamp     time.Time             `json:"timestamp"`
+	Payload       json.RawMessage       `json:"payload,omitempty"`
+	Metadata      *messaging.Metadata   `json:"metadata,omitempty"`
+	AggregateType string                `json:"aggregate_type"`
+	Version       uint64                `json:"version"`
+	SchemaVersion int                   `json:"schema_version"`
+}
+
+// IEncoder 定义归档段的编码格式。
+//
+// 内置 JSONLEncoder；需要列式格式（如 Parquet）时在业务侧基于 Record 实现该接口，
+// 框架核心不引入相应依赖。
+type IEncoder interface {
+	// Format 返回格式名，同时用作对象键扩展名（如 "jsonl"、"parquet"）。
+	Format() string
+	// ContentType 返回对象的 Content-Type。
+	ContentType() string
+	Encode(records []Record) ([]byte, error)
+	Decode(data []byte) ([]Record, error)
+}
+
+// JSONLEncoder 把每条记录编码为一行 JSON。
+type JSONLEncoder struct{}
+
+// Format 返回 "jsonl"。
+func (JSONLEncoder) Format() string { return "jsonl" }
+
+// ContentType 返回 "application/x-ndjson"。
+func (JSONLEncoder) ContentType() string { return "application/x-ndjson" }
+
+// Encode 逐行编码记录。
+func (JSONLEncoder) Encode(records []Record) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for i := range records {
+		if err := enc.Encode(&records[i]); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode 逐行解码记录，忽略空行。
+func (JSONLEncoder) Decode(data []byte) ([]Record, error) {
+	records := make([]Record, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// toRecord 把事件转换为归档记录。
+func toRecord[ID comparable](evt *eventing.Event[ID]) (Record, error) {
+	payload, err := json.Marshal(evt.Payload)
+	if err != nil {
+		return Record{}, err
+	}
+	return Record{
+		ID:            evt.ID,
+		Kind:          evt.Kind,
+		Type:          evt.Type,
+		Timestamp:     evt.Timestamp,
+		Payload:       payload,
+		Metadata:      evt.Metadata,
+		AggregateType: evt.AggregateType,
+		Version:       evt.Version,
+		SchemaVersion: evt.SchemaVersion,
+	}, nil
+}
+
+// toEvent 把归档记录还原为事件。
+func toEvent[ID comparable](rec Record, aggregateID ID) eventing.Event[ID] {
+	var payload any
+	if len(rec.Payload) > 0 && string(rec.Payload) != "null" {
+		payload = rec.Payload
+	}
+	return eventing.Event[ID]{
+		Message: messaging.Message{
+			ID:        rec.ID,
+			Kind:      rec.Kind,
+			Type:      rec.Type,
+			Timestamp: rec.Timestamp,
+			Payload:   messaging.NewPayload(payload),
+			Metadata:  rec.Metadata,
+		},
+		AggregateID:   aggregateID,
+		AggregateType: rec.AggregateType,
+		Version:       rec.Version,
+		SchemaVersion: rec.SchemaVersion,
+	}
+}
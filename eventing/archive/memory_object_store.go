@@ -0,0 +1,64 @@
+package archive
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"gochen/errors"
+)
+
+// MemoryObjectStore 是内存对象存储，仅用于测试与示例。
+type MemoryObjectStore struct {
+	mu      sync.RWMutex
+	objects map[string][]byte
+}
+
+// NewMemoryObjectStore 创建内存对象存储。
+func NewMemoryObjectStore() *MemoryObjectStore {
+	return &MemoryObjectStore{objects: make(map[string][]byte)}
+}
+
+// Put 写入对象。
+func (s *MemoryObjectStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = append([]byte(nil), data...)
+	return nil
+}
+
+// Get 读取对象；不存在时返回 NotFound。
+func (s *MemoryObjectStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, errors.NewCode(errors.NotFound, "object not found").WithContext("key", key)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// Delete 删除对象。
+func (s *MemoryObjectStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
+
+// Keys 返回指定前缀下的对象键（按字典序）。
+func (s *MemoryObjectStore) Keys(prefix string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0)
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var _ IObjectStore = (*MemoryObjectStore)(nil)
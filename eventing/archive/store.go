@@ -0,0 +1,210 @@
+package archive
+
+import (
+	"context"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/store"
+)
+
+// ArchivedEventStore 是归档感知的事件存储装饰器。
+//
+// 行为：
+//   - 写入与版本/存在性查询直接委托热存储（最新事件始终在热存储中）；
+//   - LoadEvents/LoadEventsByType/LoadEventsIter/StreamAggregate 发现热存储缺失请求的起始版本时，
+//     从归档段补齐缺失区间，调用方无需感知归档；
+//   - CountEvents 计入已归档的事件；StreamEvents 只覆盖热存储（全局扫描不回读归档）。
+type ArchivedEventStore[ID comparable] struct {
+	inner   store.IEventStreamStore[ID]
+	catalog *catalog
+}
+
+// NewArchivedEventStore 创建归档感知的事件存储。
+func NewArchivedEventStore[ID comparable](inner store.IEventStreamStore[ID], objects IObjectStore, config Config) (*ArchivedEventStore[ID], error) {
+	if inner == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	if objects == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "archive object store cannot be nil")
+	}
+	return &ArchivedEventStore[ID]{
+		inner:   inner,
+		catalog: &catalog{objects: objects, config: config.withDefaults()},
+	}, nil
+}
+
+// AppendEvents 向热存储追加事件。
+func (s *ArchivedEventStore[ID]) AppendEvents(ctx context.Context, aggregateID ID, events []eventing.IStorableEvent[ID], expectedVersion uint64) error {
+	return s.inner.AppendEvents(ctx, aggregateID, events, expectedVersion)
+}
+
+// LoadEvents 加载聚合事件，必要时从归档补齐热窗口之前的版本。
+func (s *ArchivedEventStore[ID]) LoadEvents(ctx context.Context, aggregateID ID, afterVersion uint64) ([]eventing.Event[ID], error) {
+	hot, err := s.inner.LoadEvents(ctx, aggregateID, afterVersion)
+	if err != nil {
+		return nil, err
+	}
+	return s.withArchived(ctx, "", aggregateID, afterVersion, hot)
+}
+
+// LoadEventsByType 按聚合类型加载事件，必要时从归档补齐。
+func (s *ArchivedEventStore[ID]) LoadEventsByType(ctx context.Context, aggregateType string, aggregateID ID, afterVersion uint64) ([]eventing.Event[ID], error) {
+	hot, err := s.inner.LoadEventsByType(ctx, aggregateType, aggregateID, afterVersion)
+	if err != nil {
+		return nil, err
+	}
+	return s.withArchived(ctx, aggregateType, aggregateID, afterVersion, hot)
+}
+
+// LoadEventsIter 以迭代器加载聚合事件，必要时先产出归档中的缺失区间。
+func (s *ArchivedEventStore[ID]) LoadEventsIter(ctx context.Context, aggregateID ID, afterVersion uint64) (store.IEventIterator[ID], error) {
+	it, err := s.inner.LoadEventsIter(ctx, aggregateID, afterVersion)
+	if err != nil {
+		return nil, err
+	}
+	if !it.Next() {
+		return it, nil
+	}
+	first := it.Event().Version
+	var archived []eventing.Event[ID]
+	if first > afterVersion+1 {
+		archived, err = loadArchived(ctx, s.catalog, "", aggregateID, afterVersion, first)
+		if err != nil {
+			_ = it.Close()
+			return nil, err
+		}
+	}
+	return &prefixedIterator[ID]{prefix: archived, pos: -1, inner: it, pending: true}, nil
+}
+
+// StreamAggregate 按版本分页读取；请求区间位于热窗口之前时返回归档中的一页。
+func (s *ArchivedEventStore[ID]) StreamAggregate(ctx context.Context, opts *store.AggregateStreamOptions[ID]) (*store.AggregateStreamResult[ID], error) {
+	res, err := s.inner.StreamAggregate(ctx, opts)
+	if err != nil || opts == nil || res == nil || len(res.Events) == 0 {
+		return res, err
+	}
+	first := res.Events[0].Version
+	if first <= opts.AfterVersion+1 {
+		return res, nil
+	}
+	archived, err := loadArchived(ctx, s.catalog, opts.AggregateType, opts.AggregateID, opts.AfterVersion, first)
+	if err != nil || len(archived) == 0 {
+		return res, err
+	}
+	limit := opts.Limit
+	if limit > 0 && len(archived) > limit {
+		archived = archived[:limit]
+	}
+	// 热存储中仍有后续事件，因此归档页之后必然 HasMore。
+	return &store.AggregateStreamResult[ID]{
+		Events:      archived,
+		NextVersion: archived[len(archived)-1].Version,
+		HasMore:     true,
+	}, nil
+}
+
+// StreamEvents 按游标遍历热存储的全局事件流。
+func (s *ArchivedEventStore[ID]) StreamEvents(ctx context.Context, opts *store.StreamOptions) (*store.StreamResult[ID], error) {
+	return s.inner.StreamEvents(ctx, opts)
+}
+
+// HasAggregate 检查聚合是否存在。
+func (s *ArchivedEventStore[ID]) HasAggregate(ctx context.Context, aggregateID ID) (bool, error) {
+	return s.inner.HasAggregate(ctx, aggregateID)
+}
+
+// GetAggregateVersion 获取聚合当前版本。
+func (s *ArchivedEventStore[ID]) GetAggregateVersion(ctx context.Context, aggregateID ID) (uint64, error) {
+	return s.inner.GetAggregateVersion(ctx, aggregateID)
+}
+
+// CountEvents 统计聚合事件数量（热存储 + 热存储中已不存在的归档事件）。
+func (s *ArchivedEventStore[ID]) CountEvents(ctx context.Context, aggregateID ID) (uint64, error) {
+	count, err := s.inner.CountEvents(ctx, aggregateID)
+	if err != nil || count == 0 {
+		return count, err
+	}
+	it, err := s.inner.LoadEventsIter(ctx, aggregateID, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = it.Close() }()
+	if !it.Next() {
+		return count, it.Err()
+	}
+	first := it.Event().Version
+	if first <= 1 {
+		return count, nil
+	}
+	manifest, err := s.catalog.loadManifest(ctx, aggregateKey(aggregateID))
+	if err != nil {
+		return 0, err
+	}
+	for _, seg := range manifest.Segments {
+		if seg.FromVersion < first {
+			count += min(seg.ToVersion, first-1) - seg.FromVersion + 1
+		}
+	}
+	return count, nil
+}
+
+// HeadVersion 获取指定聚合类型下聚合的最新版本号。
+func (s *ArchivedEventStore[ID]) HeadVersion(ctx context.Context, aggregateType string, aggregateID ID) (uint64, error) {
+	return s.inner.HeadVersion(ctx, aggregateType, aggregateID)
+}
+
+// withArchived 在热存储结果缺失起始版本时，从归档补齐 (afterVersion, 热存储首个版本) 区间。
+func (s *ArchivedEventStore[ID]) withArchived(ctx context.Context, aggregateType string, aggregateID ID, afterVersion uint64, hot []eventing.Event[ID]) ([]eventing.Event[ID], error) {
+	if len(hot) == 0 || hot[0].Version <= afterVersion+1 {
+		return hot, nil
+	}
+	archived, err := loadArchived(ctx, s.catalog, aggregateType, aggregateID, afterVersion, hot[0].Version)
+	if err != nil {
+		return nil, err
+	}
+	if len(archived) == 0 {
+		return hot, nil
+	}
+	return append(archived, hot...), nil
+}
+
+// prefixedIterator 先产出归档事件，再产出热存储迭代器（已前进到首条事件）的事件。
+type prefixedIterator[ID comparable] struct {
+	prefix  []eventing.Event[ID]
+	pos     int
+	inner   store.IEventIterator[ID]
+	pending bool
+	current *eventing.Event[ID]
+}
+
+func (it *prefixedIterator[ID]) Next() bool {
+	if it.pos+1 < len(it.prefix) {
+		it.pos++
+		it.current = &it.prefix[it.pos]
+		return true
+	}
+	it.pos = len(it.prefix)
+	if it.pending {
+		it.pending = false
+		it.current = it.inner.Event()
+		return true
+	}
+	if it.inner.Next() {
+		it.current = it.inner.Event()
+		return true
+	}
+	it.current = nil
+	return false
+}
+
+func (it *prefixedIterator[ID]) Event() *eventing.Event[ID] { return it.current }
+
+func (it *prefixedIterator[ID]) Err() error { return it.inner.Err() }
+
+func (it *prefixedIterator[ID]) Close() error {
+	it.prefix, it.current, it.pending = nil, nil, false
+	return it.inner.Close()
+}
+
+var _ store.IEventStreamStore[int64] = (*ArchivedEventStore[int64])(nil)
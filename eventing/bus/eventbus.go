@@ -53,6 +53,14 @@ type IEventBus interface {
 	SubscribeHandler(ctx context.Context, handler IEventHandler) (messaging.UnsubscribeFunc, error)
 }
 
+// IGroupEventBus 是事件总线的可选消费组能力（竞争消费）；EventBus 实现了该接口。
+//
+// 同一事件类型下同组处理器（通常跨多个实例）每个事件只处理一次，不同组各自收到一份。
+type IGroupEventBus interface {
+	SubscribeEventGroup(ctx context.Context, eventType, group string, handler IEventHandler) (messaging.UnsubscribeFunc, error)
+	SubscribeHandlerGroup(ctx context.Context, group string, handler IEventHandler) (messaging.UnsubscribeFunc, error)
+}
+
 // EventBus 是消息总线的类型安全包装器。
 //
 // 并发语义：
@@ -90,9 +98,40 @@ func (eb *EventBus) SubscribeEvent(ctx context.Context, eventType string, handle
 
 // SubscribeHandler 按处理器声明的事件类型批量订阅。
 func (eb *EventBus) SubscribeHandler(ctx context.Context, handler IEventHandler) (messaging.UnsubscribeFunc, error) {
+	return subscribeHandlerTypes(ctx, handler, eb.SubscribeEvent)
+}
+
+// SubscribeEventGroup 以消费组方式订阅指定类型事件：同组处理器竞争消费，每个事件只由组内一个成员处理。
+//
+// 底层 IMessageBus 未实现 messaging.IGroupSubscriber 时返回 Unsupported。
+func (eb *EventBus) SubscribeEventGroup(ctx context.Context, eventType, group string, handler IEventHandler) (messaging.UnsubscribeFunc, error) {
+	grouped, ok := eb.IMessageBus.(messaging.IGroupSubscriber)
+	if !ok {
+		return nil, errors.NewCode(errors.Unsupported, "message bus does not support consumer groups").
+			WithContext("message_bus", fmt.Sprintf("%T", eb.IMessageBus))
+	}
+	return grouped.SubscribeGroup(ctx, eventType, group, handler)
+}
+
+// SubscribeHandlerGroup 按处理器声明的事件类型以消费组方式批量订阅。
+func (eb *EventBus) SubscribeHandlerGroup(ctx context.Context, group string, handler IEventHandler) (messaging.UnsubscribeFunc, error) {
+	return subscribeHandlerTypes(ctx, handler, func(ctx context.Context, eventType string, handler IEventHandler) (messaging.UnsubscribeFunc, error) {
+		return eb.SubscribeEventGroup(ctx, eventType, group, handler)
+	})
+}
+
+// subscribeHandlerTypes 对处理器声明的每个事件类型调用 subscribe，失败时回滚已创建的订阅。
+func subscribeHandlerTypes(
+	ctx context.Context,
+	handler IEventHandler,
+	subscribe func(ctx context.Context, eventType string, handler IEventHandler) (messaging.UnsubscribeFunc, error),
+) (messaging.UnsubscribeFunc, error) {
 	if ctx == nil {
 		return nil, errors.NewCode(errors.InvalidInput, "ctx is nil")
 	}
+	if handler == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "handler cannot be nil")
+	}
 	types := handler.EventTypes()
 	if len(types) == 0 {
 		types = []string{"*"}
@@ -100,7 +139,7 @@ func (eb *EventBus) SubscribeHandler(ctx context.Context, handler IEventHandler)
 
 	unsubs := make([]messaging.UnsubscribeFunc, 0, len(types))
 	for _, t := range types {
-		unsub, err := eb.SubscribeEvent(ctx, t, handler)
+		unsub, err := subscribe(ctx, t, handler)
 		if err != nil {
 			// 回滚已创建订阅，避免半注册状态。
 			for i := len(unsubs) - 1; i >= 0; i-- {
@@ -127,3 +166,5 @@ func (eb *EventBus) SubscribeHandler(ctx context.Context, handler IEventHandler)
 		return firstErr
 	}, nil
 }
+
+var _ IGroupEventBus = (*EventBus)(nil)
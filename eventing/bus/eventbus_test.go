@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"gochen/errors"
 	"gochen/eventing"
 	msg "gochen/messaging"
 	synctransport "gochen/messaging/transport/direct"
@@ -96,3 +97,36 @@ func TestEventBus_PublishSubscribe(t *testing.T) {
 		t.Fatalf("handler not invoked")
 	}
 }
+
+// TestEventBus_SubscribeHandlerGroup 验证同组处理器竞争消费，以及底层总线不支持消费组时返回 Unsupported。
+func TestEventBus_SubscribeHandlerGroup(t *testing.T) {
+	tpt := synctransport.NewSyncTransport()
+	if err := tpt.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer func() { _ = tpt.Stop(context.Background()) }()
+
+	eb := NewEventBus(msg.NewMessageBus(tpt))
+	var a, b int32
+	for _, h := range []testEventHandler{{cnt: &a}, {cnt: &b}} {
+		if _, err := eb.SubscribeHandlerGroup(context.Background(), "projections", h); err != nil {
+			t.Fatalf("subscribe group: %v", err)
+		}
+	}
+
+	for i := range 4 {
+		evt := eventing.NewEvent[int64](int64(i+1), "Agg", "TestEvt", 1, nil)
+		if err := eb.PublishEvent(context.Background(), evt); err != nil {
+			t.Fatalf("publish event: %v", err)
+		}
+	}
+	if atomic.LoadInt32(&a) != 2 || atomic.LoadInt32(&b) != 2 {
+		t.Fatalf("expected round-robin 2/2, got %d/%d", a, b)
+	}
+
+	plain := NewEventBus(struct{ msg.IMessageBus }{msg.NewMessageBus(tpt)})
+	_, err := plain.SubscribeHandlerGroup(context.Background(), "projections", testEventHandler{cnt: &a})
+	if !errors.Is(err, errors.Unsupported) {
+		t.Fatalf("expected unsupported, got %v", err)
+	}
+}
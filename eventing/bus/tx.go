@@ -0,0 +1,83 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+
+	"gochen/db"
+	"gochen/errors"
+	"gochen/eventing"
+)
+
+// ITxEventStager 把事件暂存到调用方的数据库事务中（通常由 Outbox 仓储实现）。
+//
+// 暂存的事件随事务提交才对 Outbox 发布器可见，事务回滚时一并丢弃。
+type ITxEventStager interface {
+	StageEvents(ctx context.Context, tx db.ITransaction, events []eventing.IEvent) error
+}
+
+// PublishInTx 把事件经 stager 暂存到 tx，事务提交后由 Outbox 发布器投递到事件总线。
+//
+// 与 PublishEvents 不同，PublishInTx 不会在事务持久化之前把事件交给订阅方。
+func PublishInTx(ctx context.Context, tx db.ITransaction, stager ITxEventStager, events ...eventing.IEvent) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	if tx == nil {
+		return errors.NewCode(errors.InvalidInput, "transaction is nil")
+	}
+	if stager == nil {
+		return errors.NewCode(errors.InvalidInput, "event stager is nil")
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	for i, evt := range events {
+		if evt == nil {
+			return errors.NewCode(errors.InvalidInput, "event cannot be nil").
+				WithContext("index", i)
+		}
+	}
+	if err := stager.StageEvents(ctx, tx, events); err != nil {
+		return errors.Wrap(err, errors.Code(err), "stage events in transaction failed").
+			WithContext("event_count", len(events)).
+			WithContext("stager", fmt.Sprintf("%T", stager))
+	}
+	return nil
+}
+
+type txPublishingKey struct{}
+
+type txPublishing struct {
+	tx     db.ITransaction
+	stager ITxEventStager
+}
+
+// WithTxPublishing 把数据库事务与暂存器绑定到 ctx，声明“本链路的事件须随该事务发布”。
+//
+// 启用 PublishEvents 的 DomainEventStore 检测到后，在该事务内追加事件并暂存到 Outbox，
+// 不再在事务持久化之前直接发布。
+func WithTxPublishing(ctx context.Context, tx db.ITransaction, stager ITxEventStager) (context.Context, error) {
+	if ctx == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	if tx == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "transaction is nil")
+	}
+	if stager == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "event stager is nil")
+	}
+	return context.WithValue(ctx, txPublishingKey{}, txPublishing{tx: tx, stager: stager}), nil
+}
+
+// TxPublishingFromContext 返回 WithTxPublishing 绑定的事务与暂存器。
+func TxPublishingFromContext(ctx context.Context) (db.ITransaction, ITxEventStager, bool) {
+	if ctx == nil {
+		return nil, nil, false
+	}
+	binding, ok := ctx.Value(txPublishingKey{}).(txPublishing)
+	if !ok {
+		return nil, nil, false
+	}
+	return binding.tx, binding.stager, true
+}
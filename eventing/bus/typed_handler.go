@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"time"
 
+	"gochen/domain"
 	"gochen/errors"
 	"gochen/eventing"
 	"gochen/messaging"
@@ -119,3 +120,72 @@ func (h *TypedEventHandler[T]) Type() string {
 
 // 编译期断言：确保 TypedEventHandler 满足 IEventHandler 接口。
 var _ IEventHandler = (*TypedEventHandler[any])(nil)
+
+// TypedEvent 是 SubscribeTyped 交给处理函数的类型化事件：嵌入原始事件信封（ID、版本、元数据等），
+// Payload 为解码后的领域事件。
+type TypedEvent[T domain.IDomainEvent] struct {
+	eventing.IEvent
+	Payload T
+}
+
+// SubscribeTyped 按领域事件类型 T 订阅事件。
+//
+// 订阅的事件类型取 T.EventType()（T 为指针类型时在零值对象上调用）；载荷按 T 断言或解码（跨进程的 JSON 载荷同样适用），
+// 解码失败返回 errors.InvalidInput；处理函数返回的错误附带 event_type / event_id / handler 上下文，非 AppError 包装为 errors.Internal。
+func SubscribeTyped[T domain.IDomainEvent](
+	ctx context.Context,
+	bus IEventBus,
+	fn func(ctx context.Context, evt TypedEvent[T]) error,
+) (messaging.UnsubscribeFunc, error) {
+	if bus == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "event bus is nil")
+	}
+	if fn == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "typed event handler func is nil")
+	}
+	eventType, err := DomainEventType[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	handlerName := fmt.Sprintf("typed[%s]", reflect.TypeFor[T]())
+	handler := NewTypedEventHandler(handlerName, []string{eventType},
+		func(ctx context.Context, evt eventing.IEvent, payload T) error {
+			err := fn(ctx, TypedEvent[T]{IEvent: evt, Payload: payload})
+			if err == nil {
+				return nil
+			}
+			appErr, ok := errors.AsType[*errors.AppError](err)
+			switch {
+			case !ok || appErr == nil:
+				appErr = errors.Wrap(err, errors.Internal, "typed event handler failed")
+			case error(appErr) != err:
+				// 被 fmt.Errorf 等包装的 AppError：保留其错误码与外层包装信息。
+				appErr = errors.Wrap(err, appErr.Code(), "typed event handler failed")
+			}
+			return appErr.
+				WithContext("event_type", evt.GetType()).
+				WithContext("event_id", evt.GetID()).
+				WithContext("handler", handlerName)
+		})
+	return bus.SubscribeHandler(ctx, handler)
+}
+
+// DomainEventType 返回领域事件类型 T 的事件类型名：在 T 的零值（指针类型为新分配的零值对象）上调用 EventType()。
+func DomainEventType[T domain.IDomainEvent]() (string, error) {
+	typ := reflect.TypeFor[T]()
+	var sample T
+	switch typ.Kind() {
+	case reflect.Interface:
+		return "", errors.NewCode(errors.InvalidInput, "typed subscription requires a concrete event type").
+			WithContext("event_go_type", typ.String())
+	case reflect.Pointer:
+		sample = reflect.New(typ.Elem()).Interface().(T)
+	}
+	eventType := sample.EventType()
+	if eventType == "" {
+		return "", errors.NewCode(errors.InvalidInput, "domain event type is empty").
+			WithContext("event_go_type", typ.String())
+	}
+	return eventType, nil
+}
@@ -2,14 +2,17 @@ package bus
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"gochen/domain"
 	"gochen/errors"
 	"gochen/eventing"
 	"gochen/messaging"
+	synctransport "gochen/messaging/transport/direct"
 )
 
 // testPayload 测试用 Payload 类型
@@ -217,3 +220,100 @@ func TestTypedEventHandler_NilFunc_DoesNotPanic(t *testing.T) {
 		assert.True(t, errors.Is(err, errors.InvalidInput))
 	})
 }
+
+type orderPlaced struct {
+	OrderID string
+	Total   int
+}
+
+func (orderPlaced) EventType() string { return "OrderPlaced" }
+
+type orderCancelled struct {
+	OrderID string
+}
+
+func (*orderCancelled) EventType() string { return "OrderCancelled" }
+
+func newTypedTestBus(t *testing.T) *EventBus {
+	t.Helper()
+	tpt := synctransport.NewSyncTransport()
+	require.NoError(t, tpt.Start(context.Background()))
+	t.Cleanup(func() { _ = tpt.Stop(context.Background()) })
+	return NewEventBus(messaging.NewMessageBus(tpt))
+}
+
+func TestSubscribeTyped_DecodesPayload(t *testing.T) {
+	eb := newTypedTestBus(t)
+
+	var placed []TypedEvent[orderPlaced]
+	_, err := SubscribeTyped(context.Background(), eb, func(_ context.Context, evt TypedEvent[orderPlaced]) error {
+		placed = append(placed, evt)
+		return nil
+	})
+	require.NoError(t, err)
+	var cancelled []string
+	_, err = SubscribeTyped(context.Background(), eb, func(_ context.Context, evt TypedEvent[*orderCancelled]) error {
+		cancelled = append(cancelled, evt.Payload.OrderID)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, eb.PublishEvent(context.Background(), eventing.NewEvent[int64](1, "Order", "OrderPlaced", 3, orderPlaced{OrderID: "o1", Total: 10})))
+	// 跨进程/从存储加载的事件载荷为通用 JSON 值，同样按 T 解码。
+	require.NoError(t, eb.PublishEvent(context.Background(), eventing.NewEvent[int64](2, "Order", "OrderPlaced", 1, map[string]any{"OrderID": "o2", "Total": 20})))
+	require.NoError(t, eb.PublishEvent(context.Background(), eventing.NewEvent[int64](1, "Order", "OrderCancelled", 4, &orderCancelled{OrderID: "o1"})))
+
+	require.Len(t, placed, 2)
+	assert.Equal(t, orderPlaced{OrderID: "o1", Total: 10}, placed[0].Payload)
+	assert.Equal(t, uint64(3), placed[0].GetVersion())
+	assert.Equal(t, orderPlaced{OrderID: "o2", Total: 20}, placed[1].Payload)
+	assert.Equal(t, []string{"o1"}, cancelled)
+}
+
+func TestSubscribeTyped_WrapsErrors(t *testing.T) {
+	eb := newTypedTestBus(t)
+	_, err := SubscribeTyped(context.Background(), eb, func(_ context.Context, evt TypedEvent[orderPlaced]) error {
+		if evt.Payload.OrderID == "conflict" {
+			return errors.NewCode(errors.Conflict, "order already shipped")
+		}
+		if evt.Payload.OrderID == "wrapped" {
+			return fmt.Errorf("reserve stock: %w", errors.NewCode(errors.Conflict, "out of stock"))
+		}
+		return errors.New("projection store down")
+	})
+	require.NoError(t, err)
+
+	evt := eventing.NewEvent[int64](1, "Order", "OrderPlaced", 1, orderPlaced{OrderID: "o1"})
+	err = eb.PublishEvent(context.Background(), evt)
+	require.True(t, errors.Is(err, errors.Internal))
+	appErr, ok := errors.AsType[*errors.AppError](err)
+	require.True(t, ok)
+	assert.Equal(t, "OrderPlaced", appErr.Details()["event_type"])
+	assert.Equal(t, evt.GetID(), appErr.Details()["event_id"])
+
+	err = eb.PublishEvent(context.Background(), eventing.NewEvent[int64](2, "Order", "OrderPlaced", 1, orderPlaced{OrderID: "conflict"}))
+	assert.True(t, errors.Is(err, errors.Conflict))
+
+	wrapped := eventing.NewEvent[int64](4, "Order", "OrderPlaced", 1, orderPlaced{OrderID: "wrapped"})
+	err = eb.PublishEvent(context.Background(), wrapped)
+	assert.Equal(t, errors.Conflict, errors.Code(err))
+	assert.Contains(t, err.Error(), "reserve stock")
+	appErr, ok = errors.AsType[*errors.AppError](err)
+	require.True(t, ok)
+	assert.Equal(t, wrapped.GetID(), appErr.Details()["event_id"])
+
+	err = eb.PublishEvent(context.Background(), eventing.NewEvent[int64](3, "Order", "OrderPlaced", 1, "not an order"))
+	assert.True(t, errors.Is(err, errors.InvalidInput))
+}
+
+func TestSubscribeTyped_Validation(t *testing.T) {
+	eb := newTypedTestBus(t)
+	_, err := SubscribeTyped(context.Background(), eb, func(context.Context, TypedEvent[domain.IDomainEvent]) error { return nil })
+	assert.True(t, errors.Is(err, errors.InvalidInput))
+
+	_, err = SubscribeTyped[orderPlaced](context.Background(), eb, nil)
+	assert.True(t, errors.Is(err, errors.InvalidInput))
+
+	_, err = SubscribeTyped(context.Background(), nil, func(context.Context, TypedEvent[orderPlaced]) error { return nil })
+	assert.True(t, errors.Is(err, errors.InvalidInput))
+}
@@ -0,0 +1,298 @@
+// Package cloudevents 在 eventing.Event 与 CloudEvents 1.0 信封之间转换，用于与 Knative 等外部系统集成。
+//
+// 支持 JSON 结构化模式（Content-Type: application/cloudevents+json，含批量模式）与 HTTP 二进制模式
+// （上下文属性放在 ce-* 请求头，请求体为 data）。事件字段映射：
+//
+//   - id/type/time ↔ Event.ID/Type/Timestamp；subject ↔ 聚合 ID；
+//   - 扩展属性 aggregatetype/aggregateversion/schemaversion ↔ AggregateType/Version/SchemaVersion；
+//   - 其余扩展属性 ↔ Metadata（tenant_id、trace_id 等常用键去掉下划线后作为扩展名，见 ExtensionName）。
+//
+// 入站由 api/cloudevents 的路由注册器把 webhook 投递到事件总线；出站由 Publisher 订阅总线并向目标地址 POST。
+package cloudevents
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"strings"
+	"time"
+
+	"gochen/errors"
+)
+
+const (
+	// SpecVersion 是支持的 CloudEvents 规范版本。
+	SpecVersion = "1.0"
+	// ContentTypeStructured 是 JSON 结构化模式的 Content-Type。
+	ContentTypeStructured = "application/cloudevents+json"
+	// ContentTypeBatch 是 JSON 批量模式的 Content-Type。
+	ContentTypeBatch = "application/cloudevents-batch+json"
+	// ContentTypeJSON 是事件载荷的默认 datacontenttype。
+	ContentTypeJSON = "application/json"
+	// ContentTypeOctetStream 是二进制载荷（[]byte）的 datacontenttype。
+	ContentTypeOctetStream = "application/octet-stream"
+)
+
+// 承载聚合信息的扩展属性名。
+const (
+	ExtAggregateType    = "aggregatetype"
+	ExtAggregateVersion = "aggregateversion"
+	ExtSchemaVersion    = "schemaversion"
+)
+
+// CloudEvent 是 CloudEvents 1.0 事件信封。
+//
+// Data 保存原始 data 字节：datacontenttype 为 JSON 时是 JSON 文本，否则是二进制内容
+// （结构化模式下以 data_base64 编码）。Extensions 的值为 string、int64 或 bool。
+type CloudEvent struct {
+	SpecVersion     string
+	ID              string
+	Source          string
+	Type            string
+	Subject         string
+	Time            time.Time
+	DataContentType string
+	DataSchema      string
+	Data            []byte
+	Extensions      map[string]any
+}
+
+// contextAttributes 是规范定义的上下文属性名（扩展属性不得与之重名）。
+var contextAttributes = map[string]bool{
+	"specversion":     true,
+	"id":              true,
+	"source":          true,
+	"type":            true,
+	"subject":         true,
+	"time":            true,
+	"datacontenttype": true,
+	"dataschema":      true,
+	"data":            true,
+	"data_base64":     true,
+}
+
+// Validate 校验必填属性、规范版本与扩展属性名。
+func (e *CloudEvent) Validate() error {
+	if e == nil {
+		return errors.NewCode(errors.InvalidInput, "cloudevent cannot be nil")
+	}
+	if e.SpecVersion != SpecVersion {
+		return errors.NewCode(errors.InvalidInput, "unsupported cloudevents specversion").
+			WithContext("specversion", e.SpecVersion)
+	}
+	for attr, value := range map[string]string{"id": e.ID, "source": e.Source, "type": e.Type} {
+		if value == "" {
+			return errors.NewCode(errors.InvalidInput, "cloudevent required attribute is empty").
+				WithContext("attribute", attr)
+		}
+	}
+	for name, value := range e.Extensions {
+		if !validAttributeName(name) || contextAttributes[name] {
+			return errors.NewCode(errors.InvalidInput, "invalid cloudevent extension name").
+				WithContext("extension", name)
+		}
+		switch value.(type) {
+		case string, int64, bool:
+		default:
+			return errors.NewCode(errors.InvalidInput, "unsupported cloudevent extension value").
+				WithContext("extension", name)
+		}
+	}
+	return nil
+}
+
+// SetExtension 设置扩展属性；int 类值统一保存为 int64。
+func (e *CloudEvent) SetExtension(name string, value any) {
+	if e.Extensions == nil {
+		e.Extensions = make(map[string]any)
+	}
+	switch v := value.(type) {
+	case int:
+		value = int64(v)
+	case uint64:
+		value = int64(v)
+	}
+	e.Extensions[name] = value
+}
+
+// Extension 返回扩展属性的字符串形式。
+func (e *CloudEvent) Extension(name string) (string, bool) {
+	value, ok := e.Extensions[name]
+	if !ok {
+		return "", false
+	}
+	return formatExtension(value), true
+}
+
+// IsJSONData 判断 datacontenttype 是否为 JSON（为空时按 JSON 处理）。
+func (e *CloudEvent) IsJSONData() bool {
+	return isJSONContentType(e.DataContentType)
+}
+
+// MarshalJSON 按 JSON 结构化模式编码。
+func (e CloudEvent) MarshalJSON() ([]byte, error) {
+	out := make(map[string]any, 10+len(e.Extensions))
+	for name, value := range e.Extensions {
+		out[name] = value
+	}
+	out["specversion"] = e.SpecVersion
+	out["id"] = e.ID
+	out["source"] = e.Source
+	out["type"] = e.Type
+	setIfNotEmpty(out, "subject", e.Subject)
+	setIfNotEmpty(out, "datacontenttype", e.DataContentType)
+	setIfNotEmpty(out, "dataschema", e.DataSchema)
+	if !e.Time.IsZero() {
+		out["time"] = e.Time.UTC().Format(time.RFC3339Nano)
+	}
+	if e.Data != nil {
+		if e.IsJSONData() {
+			if !json.Valid(e.Data) {
+				return nil, errors.NewCode(errors.InvalidInput, "cloudevent data is not valid json").
+					WithContext("id", e.ID)
+			}
+			out["data"] = json.RawMessage(e.Data)
+		} else {
+			out["data_base64"] = e.Data
+		}
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON 按 JSON 结构化模式解码；未知属性作为扩展属性保留。
+func (e *CloudEvent) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return errors.Wrap(err, errors.InvalidInput, "invalid cloudevent json")
+	}
+	*e = CloudEvent{}
+	for name, value := range raw {
+		if name == "data" {
+			continue
+		}
+		if err := e.setAttribute(name, value); err != nil {
+			return errors.Wrap(err, errors.InvalidInput, "invalid cloudevent attribute").
+				WithContext("attribute", name)
+		}
+	}
+	if data, ok := raw["data"]; ok && !bytes.Equal(data, []byte("null")) {
+		e.Data = bytes.Clone(data)
+		// 非 JSON 类型的 data 在结构化模式中是 JSON 字符串。
+		var text string
+		if !e.IsJSONData() && json.Unmarshal(data, &text) == nil {
+			e.Data = []byte(text)
+		}
+	}
+	return e.Validate()
+}
+
+func (e *CloudEvent) setAttribute(name string, value json.RawMessage) error {
+	switch name {
+	case "data_base64":
+		return json.Unmarshal(value, &e.Data)
+	case "time":
+		var text string
+		if err := json.Unmarshal(value, &text); err != nil {
+			return err
+		}
+		t, err := time.Parse(time.RFC3339Nano, text)
+		if err != nil {
+			return err
+		}
+		e.Time = t
+		return nil
+	}
+
+	var decoded any
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		return err
+	}
+	if decoded == nil {
+		return nil
+	}
+	switch name {
+	case "specversion", "id", "source", "type", "subject", "datacontenttype", "dataschema":
+		text, ok := decoded.(string)
+		if !ok {
+			return errors.NewCode(errors.InvalidInput, "cloudevent attribute must be a string")
+		}
+		switch name {
+		case "specversion":
+			e.SpecVersion = text
+		case "id":
+			e.ID = text
+		case "source":
+			e.Source = text
+		case "type":
+			e.Type = text
+		case "subject":
+			e.Subject = text
+		case "datacontenttype":
+			e.DataContentType = text
+		case "dataschema":
+			e.DataSchema = text
+		}
+		return nil
+	}
+
+	switch v := decoded.(type) {
+	case string, bool:
+		e.SetExtension(name, v)
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			return errors.Wrap(err, errors.InvalidInput, "cloudevent extension must be an integer")
+		}
+		e.SetExtension(name, n)
+	default:
+		return errors.NewCode(errors.InvalidInput, "unsupported cloudevent extension value")
+	}
+	return nil
+}
+
+// UnmarshalBatch 解码 JSON 批量模式的事件数组。
+func UnmarshalBatch(data []byte) ([]*CloudEvent, error) {
+	var events []*CloudEvent
+	if err := json.Unmarshal(data, &events); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, "invalid cloudevents batch")
+	}
+	for i, evt := range events {
+		if evt == nil {
+			return nil, errors.NewCode(errors.InvalidInput, "cloudevents batch contains null").
+				WithContext("index", i)
+		}
+	}
+	return events, nil
+}
+
+// validAttributeName 判断是否为合法属性名（小写字母与数字）。
+func validAttributeName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == ContentTypeJSON || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func setIfNotEmpty(out map[string]any, key, value string) {
+	if value != "" {
+		out[key] = value
+	}
+}
@@ -0,0 +1,186 @@
+package cloudevents
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/registry"
+	"gochen/messaging"
+)
+
+type orderPlaced struct {
+	OrderID string `json:"order_id"`
+	Total   int64  `json:"total"`
+}
+
+func newOrderEvent() *eventing.Event[int64] {
+	evt := eventing.NewEvent(int64(42), "Order", "OrderPlaced", 3, &orderPlaced{OrderID: "o-1", Total: 1250}, 2)
+	evt.ID = "evt-1"
+	evt.Timestamp = time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)
+	evt.SetMetadata("trace_id", "trace 100%")
+	evt.SetMetadata("tenant_id", "acme")
+	return evt
+}
+
+func TestFromEvent_ToEvent_RoundTrip(t *testing.T) {
+	ce, err := FromEvent(newOrderEvent(), "/orders")
+	require.NoError(t, err)
+	assert.Equal(t, "42", ce.Subject)
+	assert.Equal(t, ContentTypeJSON, ce.DataContentType)
+	assert.JSONEq(t, `{"order_id":"o-1","total":1250}`, string(ce.Data))
+	assert.Equal(t, map[string]any{
+		"traceid":           "trace 100%",
+		"tenantid":          "acme",
+		ExtAggregateType:    "Order",
+		ExtAggregateVersion: int64(3),
+		ExtSchemaVersion:    int64(2),
+	}, ce.Extensions)
+
+	reg := registry.NewRegistry()
+	require.NoError(t, reg.RegisterWithVersion("OrderPlaced", 2, func() any { return &orderPlaced{} }))
+	evt, err := ToEvent[int64](ce, reg)
+	require.NoError(t, err)
+	assert.Equal(t, "evt-1", evt.ID)
+	assert.Equal(t, int64(42), evt.AggregateID)
+	assert.Equal(t, "Order", evt.AggregateType)
+	assert.Equal(t, uint64(3), evt.Version)
+	assert.Equal(t, 2, evt.SchemaVersion)
+	assert.True(t, evt.Timestamp.Equal(time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)))
+	assert.Equal(t, map[string]string{"trace_id": "trace 100%", "tenant_id": "acme"}, evt.GetMetadata().MapCopy())
+	payload, ok := messaging.PayloadValue(evt.GetPayload()).(*orderPlaced)
+	require.True(t, ok, "registered event types decode to the typed payload")
+	assert.Equal(t, orderPlaced{OrderID: "o-1", Total: 1250}, *payload)
+
+	generic, err := ToEvent[string](ce, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "42", generic.AggregateID)
+	assert.Equal(t, map[string]any{"order_id": "o-1", "total": json.Number("1250")}, messaging.PayloadValue(generic.GetPayload()))
+}
+
+func TestStructuredMode_JSON(t *testing.T) {
+	ce, err := FromEvent(newOrderEvent(), "/orders")
+	require.NoError(t, err)
+
+	header := http.Header{}
+	body, err := WriteHTTP(ce, ModeStructured, header)
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypeStructured, header.Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"specversion": "1.0",
+		"id": "evt-1",
+		"source": "/orders",
+		"type": "OrderPlaced",
+		"subject": "42",
+		"time": "2026-10-01T08:30:00Z",
+		"datacontenttype": "application/json",
+		"data": {"order_id": "o-1", "total": 1250},
+		"traceid": "trace 100%",
+		"tenantid": "acme",
+		"aggregatetype": "Order",
+		"aggregateversion": 3,
+		"schemaversion": 2
+	}`, string(body))
+
+	decoded, err := ReadHTTP(header, body)
+	require.NoError(t, err)
+	assert.Equal(t, ce, decoded)
+
+	binary := &CloudEvent{SpecVersion: SpecVersion, ID: "b-1", Source: "/s", Type: "Blob",
+		DataContentType: ContentTypeOctetStream, Data: []byte{0, 1, 2}}
+	body, err = json.Marshal(binary)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"data_base64":"AAEC"`)
+	var back CloudEvent
+	require.NoError(t, json.Unmarshal(body, &back))
+	assert.Equal(t, binary.Data, back.Data)
+
+	var text CloudEvent
+	require.NoError(t, json.Unmarshal([]byte(`{"data":"hello","specversion":"1.0","id":"t-1","source":"/s","type":"Note","datacontenttype":"text/plain"}`), &text))
+	assert.Equal(t, []byte("hello"), text.Data)
+}
+
+func TestBinaryMode_HTTP(t *testing.T) {
+	ce, err := FromEvent(newOrderEvent(), "/orders")
+	require.NoError(t, err)
+
+	header := http.Header{}
+	body, err := WriteHTTP(ce, ModeBinary, header)
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypeJSON, header.Get("Content-Type"))
+	assert.Equal(t, "evt-1", header.Get("ce-id"))
+	assert.Equal(t, "trace%20100%25", header.Get("ce-traceid"), "spaces and percent signs are percent-encoded")
+	assert.Equal(t, "3", header.Get("ce-aggregateversion"))
+	assert.JSONEq(t, `{"order_id":"o-1","total":1250}`, string(body))
+
+	decoded, err := ReadHTTP(header, body)
+	require.NoError(t, err)
+	assert.Equal(t, "trace 100%", decoded.Extensions["traceid"])
+	assert.Equal(t, "3", decoded.Extensions[ExtAggregateVersion], "binary mode extensions are strings")
+
+	evt, err := ToEvent[int64](decoded, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), evt.Version)
+	assert.Equal(t, 2, evt.SchemaVersion)
+
+	_, err = ReadHTTP(http.Header{"Ce-Id": {"x"}, "Ce-Source": {"/s"}, "Ce-Type": {"T"}}, nil)
+	assert.True(t, errors.Is(err, errors.InvalidInput), "missing specversion is rejected")
+}
+
+func TestReadHTTPBatch(t *testing.T) {
+	header := http.Header{"Content-Type": {ContentTypeBatch}}
+	events, err := ReadHTTPBatch(header, []byte(`[
+		{"specversion":"1.0","id":"1","source":"/s","type":"A"},
+		{"specversion":"1.0","id":"2","source":"/s","type":"B","data":{"n":1}}
+	]`))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "B", events[1].Type)
+
+	_, err = ReadHTTPBatch(header, []byte(`[{"specversion":"0.3","id":"1","source":"/s","type":"A"}]`))
+	assert.True(t, errors.Is(err, errors.InvalidInput))
+}
+
+func TestPublisher(t *testing.T) {
+	var received *CloudEvent
+	var auth string
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		auth = r.Header.Get("Authorization")
+		received, _ = ReadHTTP(r.Header, body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	pub, err := NewPublisher(&PublisherConfig{
+		Target:  server.URL,
+		Source:  "/orders",
+		Mode:    ModeStructured,
+		Headers: http.Header{"Authorization": {"Bearer t"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"*"}, pub.EventTypes())
+
+	require.NoError(t, pub.HandleEvent(context.Background(), newOrderEvent()))
+	require.NotNil(t, received)
+	assert.Equal(t, "evt-1", received.ID)
+	assert.Equal(t, "/orders", received.Source)
+	assert.Equal(t, "Bearer t", auth)
+
+	status = http.StatusServiceUnavailable
+	err = pub.HandleEvent(context.Background(), newOrderEvent())
+	assert.True(t, errors.Is(err, errors.Dependency))
+
+	_, err = NewPublisher(&PublisherConfig{Source: "/orders"})
+	assert.True(t, errors.Is(err, errors.InvalidInput))
+}
@@ -0,0 +1,241 @@
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gochen/codec/idcodec"
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/registry"
+	"gochen/messaging"
+)
+
+// 入站事件的原始 CloudEvent source 与 id 在 metadata 中的键（见 ScopeInbound）。
+const (
+	MetadataSourceKey   = "cloudevent_source"
+	MetadataSourceIDKey = "cloudevent_id"
+)
+
+// metadataKeys 把常用 metadata 键的扩展名映射回原始键，保证 tenant_id/trace_id 等在往返后保持不变。
+var metadataKeys = func() map[string]string {
+	keys := []string{
+		contextx.MetadataTenantKey,
+		contextx.MetadataTraceKey,
+		contextx.MetadataRequestIDKey,
+		contextx.MetadataOperatorKey,
+		contextx.MetadataCorrelationKey,
+		contextx.MetadataCausationKey,
+		messaging.MetadataProducerKey,
+		messaging.MetadataPriorityKey,
+	}
+	m := make(map[string]string, len(keys))
+	for _, key := range keys {
+		m[ExtensionName(key)] = key
+	}
+	return m
+}()
+
+// ExtensionName 把 metadata 键转换为扩展属性名：转小写并去掉非字母数字字符（如 "trace_id" -> "traceid"）。
+func ExtensionName(metadataKey string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(metadataKey) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// FromEvent 把事件转换为 CloudEvent；source 标识事件来源（如 "/orders-service"）。
+//
+// 载荷按其 JSON 编码写入 data（protobuf 等自定义编码的载荷同样生效）；[]byte 载荷按二进制数据输出。
+func FromEvent(evt eventing.IEvent, source string) (*CloudEvent, error) {
+	if evt == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "event cannot be nil")
+	}
+	out := &CloudEvent{
+		SpecVersion: SpecVersion,
+		ID:          evt.GetID(),
+		Source:      source,
+		Type:        evt.GetType(),
+		Subject:     aggregateIDOf(evt),
+		Time:        evt.GetTimestamp(),
+	}
+
+	payload := evt.GetPayload()
+	if raw, ok := messaging.PayloadAs[[]byte](payload); ok && payload.Type() == reflect.TypeFor[[]byte]() {
+		out.DataContentType = ContentTypeOctetStream
+		out.Data = raw
+	} else if !payload.IsNil() {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.InvalidInput, "failed to encode event payload").
+				WithContext("event_id", evt.GetID()).
+				WithContext("event_type", evt.GetType())
+		}
+		out.DataContentType = ContentTypeJSON
+		out.Data = data
+	}
+
+	for key, value := range evt.GetMetadata().MapCopy() {
+		if name := ExtensionName(key); name != "" && !contextAttributes[name] {
+			out.SetExtension(name, value)
+		}
+	}
+	if aggregateType := evt.GetAggregateType(); aggregateType != "" {
+		out.SetExtension(ExtAggregateType, aggregateType)
+	}
+	if version := evt.GetVersion(); version > 0 {
+		out.SetExtension(ExtAggregateVersion, version)
+	}
+	if storable, ok := evt.(interface{ EventSchemaVersion() int }); ok {
+		out.SetExtension(ExtSchemaVersion, storable.EventSchemaVersion())
+	}
+	if err := out.Validate(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ToEvent 把 CloudEvent 转换为事件。
+//
+// 扩展属性原样写入 metadata（含 tenantid/operator/correlationid 等）；来自不受信任来源的事件应再经 ScopeInbound 处理。
+//
+// subject 按 ID 的默认 codec 解析为聚合 ID（int64/string 及其派生类型）；reg 不为 nil 且注册了该事件类型时，
+// JSON data 反序列化为强类型载荷，否则解码为保留数字精度的通用值；非 JSON data 作为 []byte 载荷。
+func ToEvent[ID comparable](ce *CloudEvent, reg *registry.Registry) (*eventing.Event[ID], error) {
+	if err := ce.Validate(); err != nil {
+		return nil, err
+	}
+	var aggregateID ID
+	if ce.Subject != "" {
+		idCodec, err := idcodec.NewDefault[ID]()
+		if err != nil {
+			return nil, err
+		}
+		if aggregateID, err = idCodec.Decode(ce.Subject); err != nil {
+			return nil, errors.Wrap(err, errors.InvalidInput, "invalid cloudevent subject").
+				WithContext("id", ce.ID).
+				WithContext("subject", ce.Subject)
+		}
+	}
+
+	payload, err := decodeData(ce, reg)
+	if err != nil {
+		return nil, err
+	}
+	msg := messaging.NewMessage(ce.ID, messaging.KindEvent, ce.Type, payload)
+	if !ce.Time.IsZero() {
+		msg.Timestamp = ce.Time
+	}
+	evt := &eventing.Event[ID]{Message: *msg, AggregateID: aggregateID}
+
+	for name := range ce.Extensions {
+		value, _ := ce.Extension(name)
+		switch name {
+		case ExtAggregateType:
+			evt.AggregateType = value
+		case ExtAggregateVersion:
+			if evt.Version, err = strconv.ParseUint(value, 10, 64); err != nil {
+				return nil, errors.Wrap(err, errors.InvalidInput, "invalid cloudevent aggregate version").
+					WithContext("id", ce.ID)
+			}
+		case ExtSchemaVersion:
+			if evt.SchemaVersion, err = strconv.Atoi(value); err != nil {
+				return nil, errors.Wrap(err, errors.InvalidInput, "invalid cloudevent schema version").
+					WithContext("id", ce.ID)
+			}
+		default:
+			if key, ok := metadataKeys[name]; ok {
+				name = key
+			}
+			evt.SetMetadata(name, value)
+		}
+	}
+	if evt.SchemaVersion <= 0 && reg != nil && reg.HasEvent(ce.Type) {
+		evt.SchemaVersion = reg.EventSchemaVersion(ce.Type)
+	}
+	return evt, nil
+}
+
+// ScopeInbound 把来自外部系统的事件收敛到当前请求的信任边界：
+//   - tenant_id 与 operator 以 ctx 中的认证信息为准，ctx 未绑定时删除外部声明的值；
+//   - 删除外部声明的 correlation_id 与 causation_id，ctx 带有关联 ID 时以其为准；
+//   - 事件 ID 按 source 命名空间重新派生（同一 source+id 得到同一 ID，便于去重），
+//     原始 source 与 id 记录在 MetadataSourceKey/MetadataSourceIDKey，避免冒用内部事件或其他来源的事件 ID。
+func ScopeInbound[ID comparable](ctx context.Context, evt *eventing.Event[ID], source string) {
+	if evt == nil {
+		return
+	}
+	md := evt.GetMetadata()
+	trusted := map[string]string{
+		contextx.MetadataTenantKey:      contextx.TenantID(ctx),
+		contextx.MetadataOperatorKey:    contextx.Operator(ctx),
+		contextx.MetadataCorrelationKey: contextx.CorrelationID(ctx),
+		contextx.MetadataCausationKey:   "",
+	}
+	for key, value := range trusted {
+		if value == "" {
+			md.Delete(key)
+			continue
+		}
+		md.Set(key, value)
+	}
+	md.Set(MetadataSourceKey, source)
+	md.Set(MetadataSourceIDKey, evt.ID)
+	evt.ID = InboundEventID(source, evt.ID)
+}
+
+// InboundEventID 返回入站 CloudEvent 在本系统中的事件 ID：对 source 与 id 做 SHA-256，取前 128 位十六进制并加 "ce-" 前缀。
+func InboundEventID(source, id string) string {
+	sum := sha256.Sum256([]byte(source + "\x00" + id))
+	return "ce-" + hex.EncodeToString(sum[:16])
+}
+
+func decodeData(ce *CloudEvent, reg *registry.Registry) (any, error) {
+	if len(ce.Data) == 0 {
+		return nil, nil
+	}
+	if !ce.IsJSONData() {
+		return bytes.Clone(ce.Data), nil
+	}
+	if reg != nil && reg.HasEvent(ce.Type) {
+		payload, err := reg.DeserializeWithUseNumber(ce.Type, ce.Data)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.InvalidInput, "invalid cloudevent data").
+				WithContext("id", ce.ID)
+		}
+		return payload, nil
+	}
+	var payload any
+	decoder := json.NewDecoder(bytes.NewReader(ce.Data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, "invalid cloudevent json data").
+			WithContext("id", ce.ID).
+			WithContext("event_type", ce.Type)
+	}
+	return payload, nil
+}
+
+// aggregateIDOf 返回事件聚合 ID 的字符串形式；事件未实现 GetAggregateID 或 ID 为零值时返回空串。
+func aggregateIDOf(evt eventing.IEvent) string {
+	method := reflect.ValueOf(evt).MethodByName("GetAggregateID")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return ""
+	}
+	id := method.Call(nil)[0]
+	if id.IsZero() {
+		return ""
+	}
+	return fmt.Sprint(id.Interface())
+}
@@ -0,0 +1,200 @@
+package cloudevents
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"gochen/errors"
+)
+
+// Mode 是 HTTP 传输模式。
+type Mode int
+
+const (
+	// ModeBinary 上下文属性放在 ce-* 请求头，请求体为 data（默认）。
+	ModeBinary Mode = iota
+	// ModeStructured 请求体为 application/cloudevents+json 编码的完整事件。
+	ModeStructured
+)
+
+// HeaderPrefix 是二进制模式上下文属性请求头的前缀。
+const HeaderPrefix = "Ce-"
+
+// WriteHTTP 按 mode 把事件写入请求/响应头，并返回请求体。
+func WriteHTTP(evt *CloudEvent, mode Mode, header http.Header) ([]byte, error) {
+	if err := evt.Validate(); err != nil {
+		return nil, err
+	}
+	if mode == ModeStructured {
+		body, err := json.Marshal(evt)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.InvalidInput, "failed to encode structured cloudevent").
+				WithContext("id", evt.ID)
+		}
+		header.Set("Content-Type", ContentTypeStructured)
+		return body, nil
+	}
+
+	setHeader(header, "specversion", evt.SpecVersion)
+	setHeader(header, "id", evt.ID)
+	setHeader(header, "source", evt.Source)
+	setHeader(header, "type", evt.Type)
+	setHeader(header, "subject", evt.Subject)
+	setHeader(header, "dataschema", evt.DataSchema)
+	if !evt.Time.IsZero() {
+		setHeader(header, "time", evt.Time.UTC().Format(time.RFC3339Nano))
+	}
+	for name, value := range evt.Extensions {
+		setHeader(header, name, formatExtension(value))
+	}
+	if evt.DataContentType != "" {
+		header.Set("Content-Type", evt.DataContentType)
+	}
+	return evt.Data, nil
+}
+
+// ReadHTTP 从请求头与请求体解析单个事件：Content-Type 为 application/cloudevents+json 时按结构化模式，
+// 否则按二进制模式读取 ce-* 请求头。批量模式请使用 ReadHTTPBatch。
+func ReadHTTP(header http.Header, body []byte) (*CloudEvent, error) {
+	switch mediaType(header) {
+	case ContentTypeStructured:
+		var evt CloudEvent
+		if err := json.Unmarshal(body, &evt); err != nil {
+			return nil, errors.Wrap(err, errors.InvalidInput, "invalid structured cloudevent")
+		}
+		return &evt, nil
+	case ContentTypeBatch:
+		return nil, errors.NewCode(errors.InvalidInput, "batched cloudevents require ReadHTTPBatch")
+	}
+
+	evt := &CloudEvent{DataContentType: header.Get("Content-Type")}
+	for key, values := range header {
+		canonical := textproto.CanonicalMIMEHeaderKey(key)
+		if !strings.HasPrefix(canonical, HeaderPrefix) || len(values) == 0 {
+			continue
+		}
+		name := strings.ToLower(canonical[len(HeaderPrefix):])
+		value, err := decodeHeaderValue(values[0])
+		if err != nil {
+			return nil, errors.Wrap(err, errors.InvalidInput, "invalid cloudevent header").
+				WithContext("header", canonical)
+		}
+		switch name {
+		case "specversion":
+			evt.SpecVersion = value
+		case "id":
+			evt.ID = value
+		case "source":
+			evt.Source = value
+		case "type":
+			evt.Type = value
+		case "subject":
+			evt.Subject = value
+		case "dataschema":
+			evt.DataSchema = value
+		case "time":
+			t, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return nil, errors.Wrap(err, errors.InvalidInput, "invalid cloudevent time").
+					WithContext("time", value)
+			}
+			evt.Time = t
+		case "datacontenttype":
+			// 二进制模式以 Content-Type 承载 datacontenttype，忽略同名请求头。
+		default:
+			// 二进制模式下扩展属性一律为字符串，类型由消费方按需解析。
+			evt.SetExtension(name, value)
+		}
+	}
+	if len(body) > 0 {
+		evt.Data = body
+	}
+	if err := evt.Validate(); err != nil {
+		return nil, err
+	}
+	return evt, nil
+}
+
+// ReadHTTPBatch 解析请求中的一个或多个事件：批量模式返回数组中的全部事件，其余模式等同于 ReadHTTP。
+func ReadHTTPBatch(header http.Header, body []byte) ([]*CloudEvent, error) {
+	if mediaType(header) == ContentTypeBatch {
+		return UnmarshalBatch(body)
+	}
+	evt, err := ReadHTTP(header, body)
+	if err != nil {
+		return nil, err
+	}
+	return []*CloudEvent{evt}, nil
+}
+
+func mediaType(header http.Header) string {
+	mt, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return mt
+}
+
+func setHeader(header http.Header, name, value string) {
+	if value == "" {
+		return
+	}
+	header.Set(HeaderPrefix+name, encodeHeaderValue(value))
+}
+
+func formatExtension(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return ""
+	}
+}
+
+// encodeHeaderValue 按 HTTP 绑定规范对空格、双引号、百分号以及非可打印 ASCII 字符做百分号编码（UTF-8）。
+func encodeHeaderValue(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c <= ' ' || c >= 0x7f || c == '"' || c == '%' {
+			b.WriteByte('%')
+			b.WriteString(strings.ToUpper(strconv.FormatUint(uint64(c)>>4, 16)))
+			b.WriteString(strings.ToUpper(strconv.FormatUint(uint64(c)&0xf, 16)))
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+func decodeHeaderValue(value string) (string, error) {
+	if !strings.Contains(value, "%") {
+		return value, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '%' {
+			b.WriteByte(value[i])
+			continue
+		}
+		if i+2 >= len(value) {
+			return "", errors.NewCode(errors.InvalidInput, "truncated percent-encoding")
+		}
+		n, err := strconv.ParseUint(value[i+1:i+3], 16, 8)
+		if err != nil {
+			return "", errors.Wrap(err, errors.InvalidInput, "invalid percent-encoding")
+		}
+		b.WriteByte(byte(n))
+		i += 2
+	}
+	return b.String(), nil
+}
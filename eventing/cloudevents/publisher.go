@@ -0,0 +1,143 @@
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/bus"
+	"gochen/messaging"
+)
+
+// DefaultPublisherTimeout 是 Publisher 默认 HTTP 客户端的超时时间。
+const DefaultPublisherTimeout = 10 * time.Second
+
+// PublisherConfig 定义 CloudEvents 出站发布配置。
+type PublisherConfig struct {
+	// Target 是接收事件的 HTTP 地址（如 Knative Broker 入口）。
+	Target string
+	// Source 写入 CloudEvent 的 source 属性。
+	Source string
+	// Mode 是 HTTP 传输模式，默认 ModeBinary。
+	Mode Mode
+	// EventTypes 是订阅的事件类型；为空时订阅全部（"*"）。
+	EventTypes []string
+	// Name 是处理器名称；为空时为 "cloudevents-publisher"。
+	Name string
+	// Client 是发送请求的 HTTP 客户端；为 nil 时使用超时为 DefaultPublisherTimeout 的客户端。
+	Client *http.Client
+	// Headers 是附加到每个请求的请求头（如认证头）。
+	Headers http.Header
+}
+
+// Publisher 把事件以 CloudEvents 形式 POST 到目标地址，实现 bus.IEventHandler，可直接订阅事件总线：
+//
+//	pub, _ := cloudevents.NewPublisher(&cloudevents.PublisherConfig{Target: brokerURL, Source: "/orders"})
+//	unsubscribe, _ := eventBus.SubscribeHandler(ctx, pub)
+//
+// 目标返回非 2xx 状态码时返回 Dependency 错误，交由总线的重试/死信中间件处理。
+type Publisher struct {
+	config PublisherConfig
+	client *http.Client
+}
+
+// NewPublisher 创建 CloudEvents 发布器。
+func NewPublisher(cfg *PublisherConfig) (*Publisher, error) {
+	if cfg == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "publisher config cannot be nil")
+	}
+	config := *cfg
+	config.Target = strings.TrimSpace(config.Target)
+	if config.Target == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "publisher target cannot be empty")
+	}
+	if strings.TrimSpace(config.Source) == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "publisher source cannot be empty")
+	}
+	if len(config.EventTypes) == 0 {
+		config.EventTypes = []string{"*"}
+	}
+	if config.Name == "" {
+		config.Name = "cloudevents-publisher"
+	}
+	client := config.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultPublisherTimeout}
+	}
+	return &Publisher{config: config, client: client}, nil
+}
+
+// Publish 把事件发送到目标地址。
+func (p *Publisher) Publish(ctx context.Context, evt eventing.IEvent) error {
+	ce, err := FromEvent(evt, p.config.Source)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.Target, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.InvalidInput, "invalid publisher target").
+			WithContext("target", p.config.Target)
+	}
+	for key, values := range p.config.Headers {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+	body, err := WriteHTTP(ce, p.config.Mode, req.Header)
+	if err != nil {
+		return err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errors.Network, "failed to deliver cloudevent").
+			WithContext("target", p.config.Target).
+			WithContext("event_id", ce.ID)
+	}
+	defer resp.Body.Close()
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.NewCode(errors.Dependency, "cloudevent rejected by target").
+			WithContext("target", p.config.Target).
+			WithContext("event_id", ce.ID).
+			WithContext("status", resp.StatusCode).
+			WithContext("response", string(detail))
+	}
+	return nil
+}
+
+// HandleEvent 实现 bus.IEventHandler。
+func (p *Publisher) HandleEvent(ctx context.Context, evt eventing.IEvent) error {
+	return p.Publish(ctx, evt)
+}
+
+// Handle 实现 messaging.IMessageHandler。
+func (p *Publisher) Handle(ctx context.Context, message messaging.IMessage) error {
+	evt, ok := message.(eventing.IEvent)
+	if !ok {
+		return errors.NewCode(errors.InvalidInput, "message is not an event").
+			WithContext("message_type", fmt.Sprintf("%T", message))
+	}
+	return p.Publish(ctx, evt)
+}
+
+// EventTypes 返回订阅的事件类型。
+func (p *Publisher) EventTypes() []string { return p.config.EventTypes }
+
+// HandlerName 返回处理器名称。
+func (p *Publisher) HandlerName() string { return p.config.Name }
+
+// Type 返回处理器名称。
+func (p *Publisher) Type() string { return p.config.Name }
+
+// 编译期断言：确保 Publisher 实现 bus.IEventHandler。
+var _ bus.IEventHandler = (*Publisher)(nil)
@@ -0,0 +1,187 @@
+package contracts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gochen/errors"
+	"gochen/eventing/upcast"
+)
+
+// Change 是破坏性变更的类别。
+type Change string
+
+const (
+	// ChangeRemoved 字段被删除（重命名表现为删除旧字段）。
+	ChangeRemoved Change = "removed"
+	// ChangeTypeChanged 字段类型改变。
+	ChangeTypeChanged Change = "type_changed"
+	// ChangeRequiredAdded 新增了必填字段，旧事件缺少该字段。
+	ChangeRequiredAdded Change = "required_added"
+	// ChangeBecameRequired 可选字段变为必填。
+	ChangeBecameRequired Change = "became_required"
+)
+
+// Violation 描述相邻两个版本之间的一处破坏性变更。
+type Violation struct {
+	EventType   string `json:"event_type"`
+	FromVersion int    `json:"from_version"`
+	ToVersion   int    `json:"to_version"`
+	// Path 字段路径：嵌套以 "." 连接，数组元素为 "items[]"。
+	Path   string `json:"path"`
+	Change Change `json:"change"`
+	// Detail 可读说明（如类型变化 "integer -> string"）。
+	Detail string `json:"detail,omitempty"`
+}
+
+// String 返回形如 "OrderPlaced v1->v2: items[].sku removed" 的描述。
+func (v Violation) String() string {
+	s := fmt.Sprintf("%s v%d->v%d: %s %s", v.EventType, v.FromVersion, v.ToVersion, v.Path, v.Change)
+	if v.Detail != "" {
+		s += " (" + v.Detail + ")"
+	}
+	return s
+}
+
+// Violations 是一次检查发现的全部破坏性变更。
+type Violations []Violation
+
+// Error 实现 error 接口。
+func (e Violations) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, v := range e {
+		parts = append(parts, v.String())
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ViolationsOf 从错误链中提取破坏性变更。
+func ViolationsOf(err error) (Violations, bool) {
+	var violations Violations
+	if errors.As(err, &violations) {
+		return violations, true
+	}
+	return nil, false
+}
+
+// CheckOption 配置 Check。
+type CheckOption func(*checkOptions)
+
+type checkOptions struct {
+	upgraders *upcast.UpgraderRegistry
+}
+
+// WithUpgraders 让 Check 接受已由升级器覆盖的版本变化：
+// 存在 from -> to 的升级器链时，旧事件会在消费前被升级，两版本之间的破坏性变更不再报告。
+func WithUpgraders(upgraders *upcast.UpgraderRegistry) CheckOption {
+	return func(o *checkOptions) { o.upgraders = upgraders }
+}
+
+// Check 比较每个事件类型相邻版本的契约，发现破坏性变更时返回 errors.Validation，错误链中包含 Violations。
+//
+// 允许的演进：新增可选字段、必填字段变为可选、integer 放宽为 number、任一侧为 KindAny。
+func Check(reg *Registry, opts ...CheckOption) error {
+	if reg == nil {
+		return errors.NewCode(errors.InvalidInput, "contract registry cannot be nil")
+	}
+	var o checkOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	var violations Violations
+	for _, eventType := range reg.EventTypes() {
+		versions := reg.Versions(eventType)
+		for i := 1; i < len(versions); i++ {
+			from, to := versions[i-1], versions[i]
+			if o.upgraders != nil && o.upgraders.HasUpgradePath(eventType, from, to) {
+				continue
+			}
+			oldSchema, _ := reg.Schema(eventType, from)
+			newSchema, _ := reg.Schema(eventType, to)
+			for _, v := range Compare(oldSchema, newSchema) {
+				v.EventType, v.FromVersion, v.ToVersion = eventType, from, to
+				violations = append(violations, v)
+			}
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return errors.NewCodeWithCause(errors.Validation, "breaking event contract changes", violations).
+		WithContext("violations", []Violation(violations))
+}
+
+// Compare 返回从 oldSchema 演进到 newSchema 的破坏性变更（不含事件类型与版本信息），按路径排序。
+func Compare(oldSchema, newSchema Schema) Violations {
+	var violations Violations
+	compareFields("", oldSchema.Fields, newSchema.Fields, &violations)
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Path < violations[j].Path })
+	return violations
+}
+
+func compareFields(prefix string, oldFields, newFields map[string]Field, out *Violations) {
+	if newFields == nil {
+		return // 新版本为开放对象：接受任意子字段
+	}
+	for name, oldField := range oldFields {
+		path := joinPath(prefix, name)
+		newField, ok := newFields[name]
+		if !ok {
+			*out = append(*out, Violation{Path: path, Change: ChangeRemoved})
+			continue
+		}
+		compareField(path, oldField, newField, out)
+	}
+	if oldFields == nil {
+		return // 旧版本为开放对象：无法判断旧事件是否带有新增字段
+	}
+	for name, newField := range newFields {
+		if _, ok := oldFields[name]; !ok && newField.Required {
+			*out = append(*out, Violation{Path: joinPath(prefix, name), Change: ChangeRequiredAdded})
+		}
+	}
+}
+
+func compareField(path string, oldField, newField Field, out *Violations) {
+	if !oldField.Required && newField.Required {
+		*out = append(*out, Violation{Path: path, Change: ChangeBecameRequired})
+	}
+	if !compatibleKind(oldField.Type, newField.Type) {
+		*out = append(*out, Violation{
+			Path:   path,
+			Change: ChangeTypeChanged,
+			Detail: string(oldField.Type) + " -> " + string(newField.Type),
+		})
+		return
+	}
+	switch {
+	case oldField.Type == KindObject && newField.Type == KindObject:
+		compareFields(path, oldField.Fields, newField.Fields, out)
+	case oldField.Items != nil && newField.Items != nil:
+		compareField(path+"[]", *oldField.Items, *newField.Items, out)
+	}
+}
+
+func compatibleKind(oldKind, newKind Kind) bool {
+	switch {
+	case oldKind == newKind:
+		return true
+	case oldKind == KindAny || newKind == KindAny || oldKind == "" || newKind == "":
+		return true
+	case oldKind == KindInteger && newKind == KindNumber:
+		return true
+	default:
+		return false
+	}
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,111 @@
+// Package contracts 维护事件载荷的版本化契约，并检查版本之间的破坏性变更。
+//
+// 事件一旦写入事件存储就不可修改：新版本载荷删除或重命名字段、改变字段类型、新增必填字段，
+// 都会让旧事件无法被正确解码。contracts 为每个事件类型登记各 schema 版本的结构
+// （来自 Go 结构体或 JSON Schema），Check 逐个比较相邻版本并报告破坏性变更，适合放在 CI 的单元测试中：
+//
+//	reg := contracts.NewRegistry()
+//	_ = reg.RegisterJSONSchema("OrderPlaced", 1, orderPlacedV1JSON) // 已冻结的历史版本
+//	_ = reg.RegisterStruct("OrderPlaced", 2, &OrderPlaced{})        // 当前代码中的载荷
+//	require.NoError(t, contracts.Check(reg, contracts.WithUpgraders(upgraders)))
+package contracts
+
+import (
+	"slices"
+	"sort"
+	"sync"
+
+	"gochen/errors"
+)
+
+// Registry 按事件类型与 schema 版本登记载荷契约。
+type Registry struct {
+	schemas map[string]map[int]Schema
+	mutex   sync.RWMutex
+}
+
+// NewRegistry 创建一个空的契约注册表。
+func NewRegistry() *Registry {
+	return &Registry{schemas: make(map[string]map[int]Schema)}
+}
+
+// Register 登记事件类型某个 schema 版本的契约；同一版本重复登记返回 Conflict。
+func (r *Registry) Register(eventType string, version int, schema Schema) error {
+	if eventType == "" {
+		return errors.NewCode(errors.InvalidInput, "event type cannot be empty")
+	}
+	if version <= 0 {
+		return errors.NewCode(errors.InvalidInput, "schema version must be greater than 0").
+			WithContext("event_type", eventType)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	versions, ok := r.schemas[eventType]
+	if !ok {
+		versions = make(map[int]Schema)
+		r.schemas[eventType] = versions
+	}
+	if _, exists := versions[version]; exists {
+		return errors.NewCode(errors.Conflict, "event contract version already registered").
+			WithContext("event_type", eventType).
+			WithContext("version", version)
+	}
+	versions[version] = schema
+	return nil
+}
+
+// RegisterStruct 从载荷结构体推导契约并登记，语义见 FromStruct。
+func (r *Registry) RegisterStruct(eventType string, version int, v any) error {
+	schema, err := FromStruct(v)
+	if err != nil {
+		return errors.Wrap(err, errors.InvalidInput, "invalid event contract").
+			WithContext("event_type", eventType).
+			WithContext("version", version)
+	}
+	return r.Register(eventType, version, schema)
+}
+
+// RegisterJSONSchema 从 JSON Schema 文档推导契约并登记，语义见 FromJSONSchema。
+func (r *Registry) RegisterJSONSchema(eventType string, version int, data []byte) error {
+	schema, err := FromJSONSchema(data)
+	if err != nil {
+		return errors.Wrap(err, errors.InvalidInput, "invalid event contract").
+			WithContext("event_type", eventType).
+			WithContext("version", version)
+	}
+	return r.Register(eventType, version, schema)
+}
+
+// Schema 返回事件类型指定版本的契约。
+func (r *Registry) Schema(eventType string, version int) (Schema, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	schema, ok := r.schemas[eventType][version]
+	return schema, ok
+}
+
+// Versions 返回事件类型已登记的版本（升序）。
+func (r *Registry) Versions(eventType string) []int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	versions := make([]int, 0, len(r.schemas[eventType]))
+	for v := range r.schemas[eventType] {
+		versions = append(versions, v)
+	}
+	slices.Sort(versions)
+	return versions
+}
+
+// EventTypes 返回已登记契约的事件类型（升序）。
+func (r *Registry) EventTypes() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	types := make([]string, 0, len(r.schemas))
+	for t := range r.schemas {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
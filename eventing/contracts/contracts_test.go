@@ -0,0 +1,142 @@
+package contracts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/eventing/upcast"
+)
+
+type orderLineV1 struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+type orderPlacedV1 struct {
+	OrderID  string        `json:"order_id" validate:"required"`
+	Customer string        `json:"customer"`
+	Total    int64         `json:"total"`
+	Lines    []orderLineV1 `json:"lines"`
+}
+
+type orderLineV2 struct {
+	ProductSKU string  `json:"product_sku"`
+	Qty        float64 `json:"qty"`
+}
+
+type orderPlacedV2 struct {
+	OrderID  string        `json:"order_id"`
+	Customer string        `json:"customer"`
+	Total    string        `json:"total"`
+	Lines    []orderLineV2 `json:"lines"`
+	Channel  string        `json:"channel" validate:"required"`
+	Note     string        `json:"note,omitempty"`
+}
+
+type audited struct {
+	At time.Time `json:"at"`
+}
+
+type withEmbedded struct {
+	audited
+	Tags   map[string]string `json:"tags"`
+	Secret string            `json:"-"`
+	Raw    []byte            `json:"raw"`
+}
+
+func TestFromStruct(t *testing.T) {
+	schema, err := FromStruct(&withEmbedded{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Field{
+		"at":   {Type: KindString},
+		"tags": {Type: KindObject},
+		"raw":  {Type: KindString},
+	}, schema.Fields)
+
+	schema, err = FromStruct(orderPlacedV1{})
+	require.NoError(t, err)
+	assert.True(t, schema.Fields["order_id"].Required)
+	assert.Equal(t, KindArray, schema.Fields["lines"].Type)
+	assert.Equal(t, KindInteger, schema.Fields["lines"].Items.Fields["qty"].Type)
+
+	_, err = FromStruct("not a struct")
+	assert.True(t, errors.Is(err, errors.InvalidInput))
+}
+
+func TestCheck_ReportsBreakingChanges(t *testing.T) {
+	reg := NewRegistry()
+	require.NoError(t, reg.RegisterStruct("OrderPlaced", 1, &orderPlacedV1{}))
+	require.NoError(t, reg.RegisterStruct("OrderPlaced", 2, &orderPlacedV2{}))
+
+	err := Check(reg)
+	require.True(t, errors.Is(err, errors.Validation))
+	violations, ok := ViolationsOf(err)
+	require.True(t, ok)
+	assert.Equal(t, Violations{
+		{EventType: "OrderPlaced", FromVersion: 1, ToVersion: 2, Path: "channel", Change: ChangeRequiredAdded},
+		{EventType: "OrderPlaced", FromVersion: 1, ToVersion: 2, Path: "lines[].sku", Change: ChangeRemoved},
+		{EventType: "OrderPlaced", FromVersion: 1, ToVersion: 2, Path: "total", Change: ChangeTypeChanged, Detail: "integer -> string"},
+	}, violations, "widening qty to number, relaxing order_id and adding an optional note are compatible")
+	assert.Contains(t, err.Error(), "OrderPlaced v1->v2: lines[].sku removed")
+}
+
+type orderPlacedUpgrader struct{}
+
+func (orderPlacedUpgrader) FromVersion() int { return 1 }
+func (orderPlacedUpgrader) ToVersion() int   { return 2 }
+func (orderPlacedUpgrader) Upgrade(data map[string]any) (map[string]any, error) {
+	return data, nil
+}
+
+func TestCheck_AcceptsUpgradedVersions(t *testing.T) {
+	reg := NewRegistry()
+	require.NoError(t, reg.RegisterStruct("OrderPlaced", 1, &orderPlacedV1{}))
+	require.NoError(t, reg.RegisterStruct("OrderPlaced", 2, &orderPlacedV2{}))
+
+	upgraders := upcast.NewUpgraderRegistry()
+	require.NoError(t, upgraders.Register("OrderPlaced", orderPlacedUpgrader{}))
+	assert.NoError(t, Check(reg, WithUpgraders(upgraders)))
+}
+
+func TestCheck_JSONSchemaHistory(t *testing.T) {
+	current, err := FromStruct(&orderPlacedV1{})
+	require.NoError(t, err)
+	frozen, err := current.JSONSchema()
+	require.NoError(t, err)
+
+	reg := NewRegistry()
+	require.NoError(t, reg.RegisterJSONSchema("OrderPlaced", 1, frozen))
+	require.NoError(t, reg.RegisterStruct("OrderPlaced", 2, &orderPlacedV1{}))
+	require.NoError(t, Check(reg), "an exported schema round-trips without differences")
+
+	require.NoError(t, reg.RegisterJSONSchema("OrderPlaced", 3, []byte(`{
+		"type": "object",
+		"required": ["order_id"],
+		"properties": {
+			"order_id": {"type": "string"},
+			"customer": {"type": ["string", "null"]},
+			"total": {"type": "number"},
+			"lines": {"type": "array", "items": {"type": "object", "properties": {"sku": {"type": "string"}}}}
+		}
+	}`)))
+	violations, ok := ViolationsOf(Check(reg))
+	require.True(t, ok)
+	assert.Equal(t, Violations{
+		{EventType: "OrderPlaced", FromVersion: 2, ToVersion: 3, Path: "lines[].qty", Change: ChangeRemoved},
+	}, violations)
+}
+
+func TestRegistry_Validation(t *testing.T) {
+	reg := NewRegistry()
+	require.NoError(t, reg.Register("OrderPlaced", 1, Schema{}))
+	assert.True(t, errors.Is(reg.Register("OrderPlaced", 1, Schema{}), errors.Conflict))
+	assert.True(t, errors.Is(reg.Register("", 1, Schema{}), errors.InvalidInput))
+	assert.True(t, errors.Is(reg.Register("OrderPlaced", 0, Schema{}), errors.InvalidInput))
+	assert.True(t, errors.Is(reg.RegisterJSONSchema("OrderPlaced", 2, []byte(`{"type": "string"}`)), errors.InvalidInput))
+	assert.Equal(t, []int{1}, reg.Versions("OrderPlaced"))
+	assert.Equal(t, []string{"OrderPlaced"}, reg.EventTypes())
+}
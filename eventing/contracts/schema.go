@@ -0,0 +1,265 @@
+package contracts
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"gochen/errors"
+)
+
+// Kind 是字段的 JSON 类型。
+type Kind string
+
+const (
+	KindString  Kind = "string"
+	KindNumber  Kind = "number"
+	KindInteger Kind = "integer"
+	KindBoolean Kind = "boolean"
+	KindObject  Kind = "object"
+	KindArray   Kind = "array"
+	KindAny     Kind = "any" // 类型不受约束（interface{}、自定义 JSON 编码或 JSON Schema 未声明 type）
+)
+
+// Field 描述载荷中的一个字段。
+type Field struct {
+	Type Kind
+	// Required 表示字段必须出现在载荷中。
+	Required bool
+	// Fields 是对象的子字段；为 nil 表示开放对象（如 map），不比较子字段。
+	Fields map[string]Field
+	// Items 是数组元素的结构。
+	Items *Field
+}
+
+// Schema 描述某个版本事件载荷的结构（顶层为对象）。
+type Schema struct {
+	Fields map[string]Field
+}
+
+// FromStruct 通过反射从载荷结构体推导 Schema。
+//
+// 说明：
+//   - 字段名取 json tag（`json:"-"` 与未导出字段跳过），未打 tag 的匿名结构体字段展开到外层；
+//   - 带 `validate:"required"` 的字段视为必填，其余字段可选：旧事件缺少可选字段时按零值解码；
+//   - time.Time 视为字符串；实现 json.Marshaler/encoding.TextMarshaler 的类型视为 KindAny。
+func FromStruct(v any) (Schema, error) {
+	if v == nil {
+		return Schema{}, errors.NewCode(errors.InvalidInput, "contract struct cannot be nil")
+	}
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return Schema{}, errors.NewCode(errors.InvalidInput, "contract value must be a struct").
+			WithContext("type", t.String())
+	}
+	return Schema{Fields: structFields(t, nil)}, nil
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+func structFields(t reflect.Type, seen []reflect.Type) map[string]Field {
+	seen = append(seen, t)
+	fields := make(map[string]Field)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for k, f := range structFields(ft, seen) {
+				if _, exists := fields[k]; !exists {
+					fields[k] = f
+				}
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		field := typeField(sf.Type, seen)
+		if slices.Contains(strings.Split(opts, ","), "string") {
+			field = Field{Type: KindString}
+		}
+		field.Required = slices.Contains(strings.Split(sf.Tag.Get("validate"), ","), "required")
+		fields[name] = field
+	}
+	return fields
+}
+
+func typeField(t reflect.Type, seen []reflect.Type) Field {
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		if t == timeType {
+			return Field{Type: KindString}
+		}
+		return Field{Type: KindAny}
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return Field{Type: KindString}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeField(t.Elem(), seen)
+	case reflect.String:
+		return Field{Type: KindString}
+	case reflect.Bool:
+		return Field{Type: KindBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Field{Type: KindInteger}
+	case reflect.Float32, reflect.Float64:
+		return Field{Type: KindNumber}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Field{Type: KindString} // []byte 以 base64 字符串编码
+		}
+		items := typeField(t.Elem(), seen)
+		return Field{Type: KindArray, Items: &items}
+	case reflect.Map:
+		return Field{Type: KindObject}
+	case reflect.Struct:
+		if slices.Contains(seen, t) {
+			return Field{Type: KindObject} // 递归类型按开放对象处理
+		}
+		return Field{Type: KindObject, Fields: structFields(t, seen)}
+	default:
+		return Field{Type: KindAny}
+	}
+}
+
+// jsonSchema 是 FromJSONSchema 支持的 JSON Schema 子集。
+type jsonSchema struct {
+	Type       json.RawMessage        `json:"type,omitempty"`
+	Properties map[string]*jsonSchema `json:"properties,omitempty"`
+	Required   []string               `json:"required,omitempty"`
+	Items      *jsonSchema            `json:"items,omitempty"`
+}
+
+// FromJSONSchema 从 JSON Schema 文档推导 Schema。
+//
+// 仅使用 type/properties/required/items：type 为数组时取第一个非 null 类型；未声明 type 视为 KindAny；
+// 未声明 properties 的对象视为开放对象。
+func FromJSONSchema(data []byte) (Schema, error) {
+	var doc jsonSchema
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Schema{}, errors.Wrap(err, errors.InvalidInput, "invalid json schema")
+	}
+	root, err := doc.field()
+	if err != nil {
+		return Schema{}, err
+	}
+	if root.Type != KindObject {
+		return Schema{}, errors.NewCode(errors.InvalidInput, "json schema root must be an object").
+			WithContext("type", string(root.Type))
+	}
+	return Schema{Fields: root.Fields}, nil
+}
+
+func (s *jsonSchema) field() (Field, error) {
+	kind, err := s.kind()
+	if err != nil {
+		return Field{}, err
+	}
+	field := Field{Type: kind}
+	if s.Properties != nil {
+		field.Type = KindObject
+		field.Fields = make(map[string]Field, len(s.Properties))
+		for name, prop := range s.Properties {
+			if prop == nil {
+				prop = &jsonSchema{}
+			}
+			child, err := prop.field()
+			if err != nil {
+				return Field{}, errors.Wrap(err, errors.InvalidInput, "invalid json schema property").
+					WithContext("property", name)
+			}
+			child.Required = slices.Contains(s.Required, name)
+			field.Fields[name] = child
+		}
+	}
+	if s.Items != nil {
+		items, err := s.Items.field()
+		if err != nil {
+			return Field{}, err
+		}
+		field.Type = KindArray
+		field.Items = &items
+	}
+	return field, nil
+}
+
+func (s *jsonSchema) kind() (Kind, error) {
+	if len(s.Type) == 0 {
+		return KindAny, nil
+	}
+	var single string
+	if err := json.Unmarshal(s.Type, &single); err == nil {
+		return parseKind(single)
+	}
+	var multiple []string
+	if err := json.Unmarshal(s.Type, &multiple); err != nil {
+		return "", errors.Wrap(err, errors.InvalidInput, "invalid json schema type")
+	}
+	for _, t := range multiple {
+		if t != "null" {
+			return parseKind(t)
+		}
+	}
+	return KindAny, nil
+}
+
+func parseKind(t string) (Kind, error) {
+	switch Kind(t) {
+	case KindString, KindNumber, KindInteger, KindBoolean, KindObject, KindArray:
+		return Kind(t), nil
+	case "null":
+		return KindAny, nil
+	default:
+		return "", errors.NewCode(errors.InvalidInput, "unsupported json schema type").
+			WithContext("type", t)
+	}
+}
+
+// JSONSchema 把 Schema 导出为 JSON Schema 文档，便于把当前版本冻结为契约文件纳入版本库。
+func (s Schema) JSONSchema() ([]byte, error) {
+	return json.MarshalIndent(toJSONSchema(Field{Type: KindObject, Fields: s.Fields}), "", "  ")
+}
+
+func toJSONSchema(f Field) *jsonSchema {
+	out := &jsonSchema{}
+	if f.Type != KindAny && f.Type != "" {
+		out.Type, _ = json.Marshal(string(f.Type))
+	}
+	if f.Fields != nil {
+		out.Properties = make(map[string]*jsonSchema, len(f.Fields))
+		for name, child := range f.Fields {
+			out.Properties[name] = toJSONSchema(child)
+			if child.Required {
+				out.Required = append(out.Required, name)
+			}
+		}
+		slices.Sort(out.Required)
+	}
+	if f.Items != nil {
+		out.Items = toJSONSchema(*f.Items)
+	}
+	return out
+}
@@ -46,28 +46,91 @@ type HealthReport struct {
 	Checks    []CheckResult `json:"checks"`
 }
 
+// Probe 标识健康检查参与的探针。
+//
+// 存活探针（liveness）失败会导致进程被重启，只应包含进程自身的检查（如死锁、关键协程退出）；
+// 依赖（数据库、消息传输、投影延迟、Outbox 积压）应只参与就绪探针（readiness），失败时摘除流量而不是重启。
+type Probe string
+
+const (
+	// ProbeLiveness 是存活探针。
+	ProbeLiveness Probe = "liveness"
+	// ProbeReadiness 是就绪探针。
+	ProbeReadiness Probe = "readiness"
+)
+
+// IHealthChecker 是具名健康检查，供依赖组件以对象形式提供检查。
+type IHealthChecker interface {
+	Name() string
+	Check(ctx context.Context) (HealthStatus, string, error)
+}
+
+type namedChecker struct {
+	name  string
+	check Check
+}
+
+func (c namedChecker) Name() string { return c.name }
+
+func (c namedChecker) Check(ctx context.Context) (HealthStatus, string, error) { return c.check(ctx) }
+
+// NamedCheck 把检查函数包装为 IHealthChecker。
+func NamedCheck(name string, check Check) IHealthChecker {
+	return namedChecker{name: name, check: check}
+}
+
+type registeredCheck struct {
+	check  Check
+	probes map[Probe]struct{}
+}
+
 // HealthRegistry 管理一组健康检查。
 type HealthRegistry struct {
 	mu     sync.RWMutex
 	order  []string
-	checks map[string]Check
+	checks map[string]registeredCheck
 }
 
 // NewHealthRegistry 创建健康检查注册表。
 func NewHealthRegistry() *HealthRegistry {
 	return &HealthRegistry{
-		checks: make(map[string]Check),
+		checks: make(map[string]registeredCheck),
 	}
 }
 
-// Register 注册或覆盖一个检查项，并保持输出顺序与首次注册顺序一致。
+// Register 注册或覆盖一个只参与就绪探针的检查项，并保持输出顺序与首次注册顺序一致。
 func (r *HealthRegistry) Register(name string, check Check) error {
+	return r.RegisterProbe(name, check, ProbeReadiness)
+}
+
+// RegisterChecker 注册或覆盖一个具名检查；probes 为空时只参与就绪探针。
+func (r *HealthRegistry) RegisterChecker(checker IHealthChecker, probes ...Probe) error {
+	if checker == nil {
+		return errors.NewCode(errors.InvalidInput, "health checker cannot be nil")
+	}
+	return r.RegisterProbe(checker.Name(), checker.Check, probes...)
+}
+
+// RegisterProbe 注册或覆盖一个检查项，并声明其参与的探针；probes 为空时只参与就绪探针。
+func (r *HealthRegistry) RegisterProbe(name string, check Check, probes ...Probe) error {
 	if name == "" {
 		return errors.NewCode(errors.InvalidInput, "health check name cannot be empty")
 	}
 	if check == nil {
 		return errors.NewCode(errors.InvalidInput, "health check cannot be nil").WithContext("name", name)
 	}
+	if len(probes) == 0 {
+		probes = []Probe{ProbeReadiness}
+	}
+	set := make(map[Probe]struct{}, len(probes))
+	for _, probe := range probes {
+		if probe != ProbeLiveness && probe != ProbeReadiness {
+			return errors.NewCode(errors.InvalidInput, "unknown health probe").
+				WithContext("name", name).
+				WithContext("probe", string(probe))
+		}
+		set[probe] = struct{}{}
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -75,7 +138,7 @@ func (r *HealthRegistry) Register(name string, check Check) error {
 	if _, exists := r.checks[name]; !exists {
 		r.order = append(r.order, name)
 	}
-	r.checks[name] = check
+	r.checks[name] = registeredCheck{check: check, probes: set}
 	return nil
 }
 
@@ -101,15 +164,32 @@ func (r *HealthRegistry) Unregister(name string) bool {
 
 // Report 按注册顺序执行全部检查，并生成一份可直接对外输出的健康报告。
 func (r *HealthRegistry) Report(ctx context.Context) HealthReport {
+	return r.report(ctx, "")
+}
+
+// ReportProbe 按注册顺序只执行参与指定探针的检查；没有检查项时报告为 healthy。
+func (r *HealthRegistry) ReportProbe(ctx context.Context, probe Probe) HealthReport {
+	return r.report(ctx, probe)
+}
+
+// report 执行参与 probe 的检查；probe 为空时执行全部检查。
+func (r *HealthRegistry) report(ctx context.Context, probe Probe) HealthReport {
 	if r == nil {
 		return HealthReport{Timestamp: Now(), Status: HealthStatusUnhealthy}
 	}
 
 	r.mu.RLock()
-	names := append([]string(nil), r.order...)
-	checks := make([]Check, 0, len(names))
-	for _, name := range names {
-		checks = append(checks, r.checks[name])
+	names := make([]string, 0, len(r.order))
+	checks := make([]Check, 0, len(r.order))
+	for _, name := range r.order {
+		entry := r.checks[name]
+		if probe != "" {
+			if _, ok := entry.probes[probe]; !ok {
+				continue
+			}
+		}
+		names = append(names, name)
+		checks = append(checks, entry.check)
 	}
 	r.mu.RUnlock()
 
@@ -150,24 +230,24 @@ func (r *HealthRegistry) Report(ctx context.Context) HealthReport {
 
 func worseStatus(a, b HealthStatus) HealthStatus {
 	// 顺序：healthy < degraded < unhealthy
-	rank := func(s HealthStatus) int {
-		switch s {
-		case HealthStatusHealthy:
-			return 0
-		case HealthStatusDegraded:
-			return 1
-		case HealthStatusUnhealthy:
-			return 2
-		default:
-			return 2
-		}
-	}
-	if rank(b) > rank(a) {
+	if statusRank(b) > statusRank(a) {
 		return b
 	}
 	return a
 }
 
+// statusRank 返回健康状态的严重程度（同时用作 gauge 数值）；未知状态按 unhealthy 处理。
+func statusRank(s HealthStatus) int {
+	switch s {
+	case HealthStatusHealthy:
+		return 0
+	case HealthStatusDegraded:
+		return 1
+	default:
+		return 2
+	}
+}
+
 // MetricsHealthConfig 定义指标健康检查配置。
 type MetricsHealthConfig struct {
 	MaxEventStoreErrorRatePercent float64
@@ -218,3 +298,43 @@ func MetricsHealthCheck(metrics *Metrics, cfg MetricsHealthConfig) (Check, error
 		return HealthStatusDegraded, strings.Join(issues, "; "), nil
 	}, nil
 }
+
+// IPinger 是可探活依赖的最小视图（如 db.IDatabase、消息中间件客户端）。
+type IPinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PingHealthCheck 基于依赖的 Ping 生成健康检查函数。
+//
+// timeout > 0 时为单次探活附加超时；Ping 失败返回 unhealthy，并把错误作为 message。
+func PingHealthCheck(pinger IPinger, timeout time.Duration) (Check, error) {
+	if pinger == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "pinger cannot be nil")
+	}
+	return func(ctx context.Context) (HealthStatus, string, error) {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		if err := pinger.Ping(ctx); err != nil {
+			return HealthStatusUnhealthy, err.Error(), nil
+		}
+		return HealthStatusHealthy, "ok", nil
+	}, nil
+}
+
+// RunningHealthCheck 基于依赖的运行状态生成健康检查：running 返回 false 时 unhealthy。
+//
+// 适用于无法主动探活、但可报告运行状态的依赖，例如 messaging.ITransport 的 Stats().Running。
+func RunningHealthCheck(running func() bool) (Check, error) {
+	if running == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "running func cannot be nil")
+	}
+	return func(context.Context) (HealthStatus, string, error) {
+		if !running() {
+			return HealthStatusUnhealthy, "not running", nil
+		}
+		return HealthStatusHealthy, "running", nil
+	}, nil
+}
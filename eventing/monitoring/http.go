@@ -5,7 +5,9 @@ import (
 	"net/http"
 )
 
-// NewHTTPHandler 暴露 `/healthz`、`/readyz`、`/metrics` 和 `/snapshot` 监控端点。
+// NewHTTPHandler 暴露 `/healthz`、`/livez`、`/readyz`、`/metrics` 和 `/snapshot` 监控端点。
+//
+// `/healthz` 执行全部检查；`/livez`、`/readyz` 只执行参与存活/就绪探针的检查（见 Probe）。
 func NewHTTPHandler(reg *Registry) http.Handler {
 	if reg == nil {
 		reg = DefaultRegistry()
@@ -26,12 +28,25 @@ func NewHTTPHandler(reg *Registry) http.Handler {
 		writeJSON(w, code, report)
 	})
 
+	mux.HandleFunc("/livez", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		report := reg.Health.ReportProbe(r.Context(), ProbeLiveness)
+		code := http.StatusOK
+		if report.Status == HealthStatusUnhealthy {
+			code = http.StatusServiceUnavailable
+		}
+		writeJSON(w, code, report)
+	})
+
 	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
-		report := reg.Health.Report(r.Context())
+		report := reg.Health.ReportProbe(r.Context(), ProbeReadiness)
 		code := http.StatusOK
 		if report.Status == HealthStatusUnhealthy {
 			code = http.StatusServiceUnavailable
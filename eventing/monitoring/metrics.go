@@ -31,9 +31,10 @@ type Metrics struct {
 	EventProcessingErrors int64 // 事件处理错误数
 
 	// 投影指标
-	ProjectionUpdates int64 // 投影更新次数
-	ProjectionErrors  int64 // 投影错误数
-	ProjectionLag     int64 // 投影延迟（毫秒）
+	ProjectionUpdates  int64 // 投影更新次数
+	ProjectionErrors   int64 // 投影错误数
+	ProjectionLag      int64 // 投影延迟（毫秒）
+	ProjectionTimeouts int64 // 投影处理器超时次数
 
 	// 缓存指标（如果启用）
 	CacheHits      int64 // 缓存命中次数
@@ -95,6 +96,11 @@ type IProjectionMetricsRecorder interface {
 	RecordProjectionUpdate(success bool, lag time.Duration)
 }
 
+// IProjectionTimeoutRecorder 是投影埋点的可选扩展：记录被 ProjectionConfig.HandlerTimeout 强制超时的处理。
+type IProjectionTimeoutRecorder interface {
+	RecordProjectionTimeout()
+}
+
 // ICacheMetricsRecorder 抽象缓存埋点。
 type ICacheMetricsRecorder interface {
 	RecordCacheHit()
@@ -157,6 +163,9 @@ func (m *Metrics) RecordProjectionUpdate(success bool, lag time.Duration) {
 	atomic.StoreInt64(&m.ProjectionLag, lag.Milliseconds())
 }
 
+// RecordProjectionTimeout 累计投影处理器超时次数。
+func (m *Metrics) RecordProjectionTimeout() { atomic.AddInt64(&m.ProjectionTimeouts, 1) }
+
 // RecordCacheHit 累计缓存命中次数。
 func (m *Metrics) RecordCacheHit() { atomic.AddInt64(&m.CacheHits, 1) }
 
@@ -206,9 +215,10 @@ type MetricsSnapshot struct {
 	EventProcessingErrors int64
 
 	// 投影指标
-	ProjectionUpdates int64
-	ProjectionErrors  int64
-	ProjectionLag     time.Duration
+	ProjectionUpdates  int64
+	ProjectionErrors   int64
+	ProjectionLag      time.Duration
+	ProjectionTimeouts int64
 
 	// 缓存指标
 	CacheHits      int64
@@ -246,9 +256,10 @@ func (m *Metrics) Snapshot() MetricsSnapshot {
 		EventProcessingTime:   time.Duration(atomic.LoadInt64(&m.EventProcessingTime)),
 		EventProcessingErrors: atomic.LoadInt64(&m.EventProcessingErrors),
 
-		ProjectionUpdates: atomic.LoadInt64(&m.ProjectionUpdates),
-		ProjectionErrors:  atomic.LoadInt64(&m.ProjectionErrors),
-		ProjectionLag:     time.Duration(atomic.LoadInt64(&m.ProjectionLag)) * time.Millisecond,
+		ProjectionUpdates:  atomic.LoadInt64(&m.ProjectionUpdates),
+		ProjectionErrors:   atomic.LoadInt64(&m.ProjectionErrors),
+		ProjectionLag:      time.Duration(atomic.LoadInt64(&m.ProjectionLag)) * time.Millisecond,
+		ProjectionTimeouts: atomic.LoadInt64(&m.ProjectionTimeouts),
 
 		CacheHits:      atomic.LoadInt64(&m.CacheHits),
 		CacheMisses:    atomic.LoadInt64(&m.CacheMisses),
@@ -287,6 +298,7 @@ func (m *Metrics) Reset() {
 	atomic.StoreInt64(&m.ProjectionUpdates, 0)
 	atomic.StoreInt64(&m.ProjectionErrors, 0)
 	atomic.StoreInt64(&m.ProjectionLag, 0)
+	atomic.StoreInt64(&m.ProjectionTimeouts, 0)
 
 	atomic.StoreInt64(&m.CacheHits, 0)
 	atomic.StoreInt64(&m.CacheMisses, 0)
@@ -338,6 +350,7 @@ func (s MetricsSnapshot) Summary() Summary {
 			Updates:          s.ProjectionUpdates,
 			Errors:           s.ProjectionErrors,
 			LagMillis:        s.ProjectionLag.Milliseconds(),
+			Timeouts:         s.ProjectionTimeouts,
 			ErrorRatePercent: errorRatePercent(s.ProjectionErrors, s.ProjectionUpdates),
 		},
 		Cache: CacheSummary{
@@ -409,6 +422,7 @@ type ProjectionSummary struct {
 	Updates          int64   `json:"updates"`
 	Errors           int64   `json:"errors"`
 	LagMillis        int64   `json:"lag_ms"`
+	Timeouts         int64   `json:"timeouts"`
 	ErrorRatePercent float64 `json:"error_rate"`
 }
 
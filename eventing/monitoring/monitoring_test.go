@@ -8,6 +8,8 @@ import (
 	"testing"
 	"time"
 
+	"gochen/errors"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -37,6 +39,34 @@ func TestHealthRegistry_ReportAggregatesWorstStatus(t *testing.T) {
 	require.Equal(t, "bad", rep.Checks[2].Name)
 }
 
+type pingerFunc func(ctx context.Context) error
+
+func (f pingerFunc) Ping(ctx context.Context) error { return f(ctx) }
+
+// TestPingHealthCheck 验证 PingHealthCheck 按探活结果映射健康状态并附加超时。
+func TestPingHealthCheck(t *testing.T) {
+	_, err := PingHealthCheck(nil, 0)
+	require.Error(t, err)
+
+	check, err := PingHealthCheck(pingerFunc(func(ctx context.Context) error {
+		_, hasDeadline := ctx.Deadline()
+		require.True(t, hasDeadline)
+		return nil
+	}), time.Second)
+	require.NoError(t, err)
+	status, msg, err := check(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, HealthStatusHealthy, status)
+	require.Equal(t, "ok", msg)
+
+	check, err = PingHealthCheck(pingerFunc(func(context.Context) error { return context.DeadlineExceeded }), 0)
+	require.NoError(t, err)
+	status, msg, err = check(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, HealthStatusUnhealthy, status)
+	require.Equal(t, context.DeadlineExceeded.Error(), msg)
+}
+
 // TestNewHTTPHandler_Endpoints 验证 NewHTTPHandler Endpoints。
 func TestNewHTTPHandler_Endpoints(t *testing.T) {
 	reg, err := NewRegistry()
@@ -58,6 +88,18 @@ func TestNewHTTPHandler_Endpoints(t *testing.T) {
 		require.Equal(t, HealthStatusUnhealthy, rep.Status)
 	})
 
+	t.Run("livez", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/livez", nil)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var rep HealthReport
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&rep))
+		require.Equal(t, HealthStatusHealthy, rep.Status)
+		require.Empty(t, rep.Checks)
+	})
+
 	t.Run("readyz", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
 		rec := httptest.NewRecorder()
@@ -89,6 +131,45 @@ func TestNewHTTPHandler_Endpoints(t *testing.T) {
 	})
 }
 
+// TestHealthRegistry_ReportProbe 验证检查按探针划分，Register 默认只参与就绪探针。
+func TestHealthRegistry_ReportProbe(t *testing.T) {
+	r := NewHealthRegistry()
+	healthy := func(context.Context) (HealthStatus, string, error) { return HealthStatusHealthy, "ok", nil }
+	unhealthy := func(context.Context) (HealthStatus, string, error) { return HealthStatusUnhealthy, "down", nil }
+
+	require.NoError(t, r.Register("db", unhealthy))
+	require.NoError(t, r.RegisterChecker(NamedCheck("loop", healthy), ProbeLiveness, ProbeReadiness))
+	require.NoError(t, r.RegisterProbe("deadlock", healthy, ProbeLiveness))
+
+	live := r.ReportProbe(context.Background(), ProbeLiveness)
+	require.Equal(t, HealthStatusHealthy, live.Status)
+	require.Len(t, live.Checks, 2)
+	require.Equal(t, "loop", live.Checks[0].Name)
+	require.Equal(t, "deadlock", live.Checks[1].Name)
+
+	ready := r.ReportProbe(context.Background(), ProbeReadiness)
+	require.Equal(t, HealthStatusUnhealthy, ready.Status)
+	require.Len(t, ready.Checks, 2)
+	require.Equal(t, "db", ready.Checks[0].Name)
+	require.Equal(t, "loop", ready.Checks[1].Name)
+
+	require.Len(t, r.Report(context.Background()).Checks, 3)
+
+	err := r.RegisterProbe("bad", healthy, Probe("startup"))
+	require.True(t, errors.Is(err, errors.InvalidInput))
+	require.Error(t, r.RegisterChecker(nil))
+
+	running := false
+	check, err := RunningHealthCheck(func() bool { return running })
+	require.NoError(t, err)
+	status, _, err := check(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, HealthStatusUnhealthy, status)
+	running = true
+	status, _, _ = check(context.Background())
+	require.Equal(t, HealthStatusHealthy, status)
+}
+
 func TestNewRegistry_MetricsWiring_CheckIsInfoByDefault(t *testing.T) {
 	reg, err := NewRegistry()
 	require.NoError(t, err)
@@ -0,0 +1,54 @@
+package monitoring
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PrometheusContentType 是 Prometheus 文本暴露格式的 Content-Type。
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WritePrometheus 以 Prometheus 文本格式输出事件链路状态的 gauge（不依赖 Prometheus SDK）。
+//
+// 指标：
+//   - gochen_eventing_health_status：0 healthy / 1 degraded / 2 unhealthy
+//   - gochen_outbox_pending_entries、gochen_outbox_oldest_pending_age_seconds（接入 Outbox 时输出）
+//   - gochen_outbox_publish_error_ratio：累计发布失败率（0~1）
+//   - gochen_projection_checkpoint_position、gochen_projection_checkpoint_lag_seconds（label projection）
+func WritePrometheus(w io.Writer, s EventingStatus) error {
+	bw := bufio.NewWriter(w)
+
+	writeGauge(bw, "gochen_eventing_health_status", "Eventing health status (0 healthy, 1 degraded, 2 unhealthy).", float64(statusRank(s.Health.Status)))
+	if s.Outbox != nil {
+		writeGauge(bw, "gochen_outbox_pending_entries", "Outbox entries waiting to be published.", float64(s.Outbox.PendingCount))
+		writeGauge(bw, "gochen_outbox_oldest_pending_age_seconds", "Age of the oldest pending outbox entry.", s.Outbox.OldestPendingAge.Seconds())
+	}
+	writeGauge(bw, "gochen_outbox_publish_error_ratio", "Ratio of failed outbox publish attempts.", s.OutboxPublishErrorRatePercent/100)
+
+	if len(s.Projections) > 0 {
+		writeHeader(bw, "gochen_projection_checkpoint_position", "Events processed as recorded by the projection checkpoint.")
+		for _, p := range s.Projections {
+			fmt.Fprintf(bw, "gochen_projection_checkpoint_position{projection=\"%s\"} %d\n", escapeLabel(p.Name), p.Position)
+		}
+		writeHeader(bw, "gochen_projection_checkpoint_lag_seconds", "Time since the last event recorded by the projection checkpoint.")
+		for _, p := range s.Projections {
+			fmt.Fprintf(bw, "gochen_projection_checkpoint_lag_seconds{projection=\"%s\"} %g\n", escapeLabel(p.Name), p.Lag.Seconds())
+		}
+	}
+	return bw.Flush()
+}
+
+func writeHeader(w io.Writer, name, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+func writeGauge(w io.Writer, name, help string, value float64) {
+	writeHeader(w, name, help)
+	fmt.Fprintf(w, "%s %g\n", name, value)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string { return labelEscaper.Replace(v) }
@@ -53,6 +53,8 @@ type CacheStats struct {
 	CacheSize  int     `json:"cache_size"`
 	MaxSize    int     `json:"max_size"`
 	TTLSeconds float64 `json:"ttl_seconds"`
+
+	EvictionPolicy string `json:"eviction_policy,omitempty"`
 }
 
 // CacheSnapshot 是缓存快照。
@@ -0,0 +1,222 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gochen/errors"
+)
+
+// OutboxBacklog 是 Outbox 积压统计。
+type OutboxBacklog struct {
+	PendingCount     int64         `json:"pending_count"`
+	OldestPendingAge time.Duration `json:"oldest_pending_age"`
+}
+
+// IOutboxBacklogProvider 提供 Outbox 积压统计（eventing/outbox/monitoring.NewBacklogProvider 提供 SQL 实现）。
+type IOutboxBacklogProvider interface {
+	OutboxBacklog(ctx context.Context) (OutboxBacklog, error)
+}
+
+// ProjectionLag 是单个投影的检查点延迟。
+//
+// Lag 为当前时间与检查点最后事件时间之差；写入停止时同样会增长，阈值应结合写入频率设置。
+// 尚未处理过事件（LastEventTime 为零值）时 Lag 为 0。
+type ProjectionLag struct {
+	Name          string        `json:"name"`
+	Status        string        `json:"status,omitempty"`
+	Position      int64         `json:"position"`
+	LastEventID   string        `json:"last_event_id,omitempty"`
+	LastEventTime time.Time     `json:"last_event_time"`
+	Lag           time.Duration `json:"lag"`
+}
+
+// IProjectionLagProvider 提供各投影的检查点延迟（projection.ProjectionManager 已实现）。
+type IProjectionLagProvider interface {
+	ProjectionLags(ctx context.Context) ([]ProjectionLag, error)
+}
+
+// StatusConfig 定义事件链路状态的健康阈值；各项为 0 时不检查。
+type StatusConfig struct {
+	MaxPendingOutbox           int64
+	MaxOldestPendingAge        time.Duration
+	MaxPublishErrorRatePercent float64
+	// MaxProjectionLag 默认不检查：投影延迟在无写入时也会增长，需按业务写入频率显式配置。
+	MaxProjectionLag time.Duration
+}
+
+// DefaultStatusConfig 返回默认阈值。
+func DefaultStatusConfig() StatusConfig {
+	return StatusConfig{
+		MaxPendingOutbox:           1000,
+		MaxOldestPendingAge:        5 * time.Minute,
+		MaxPublishErrorRatePercent: 5,
+	}
+}
+
+// EventingStatus 是事件链路的运维状态：Outbox 积压、发布错误率与投影检查点延迟。
+type EventingStatus struct {
+	Timestamp time.Time `json:"timestamp"`
+
+	Outbox *OutboxBacklog `json:"outbox,omitempty"`
+	// OutboxPublishErrorRatePercent 来自进程内 Metrics 的累计发布失败率（百分比）。
+	OutboxPublishErrorRatePercent float64 `json:"outbox_publish_error_rate"`
+
+	Projections []ProjectionLag `json:"projections,omitempty"`
+
+	Health HealthReport `json:"health"`
+}
+
+// 状态检查项名称。
+const (
+	CheckOutboxBacklog = "eventing.outbox_backlog"
+	CheckOutboxPublish = "eventing.outbox_publish"
+	CheckProjectionLag = "eventing.projection_lag"
+)
+
+// StatusReporter 汇总事件链路状态，并按 StatusConfig 评估健康。
+type StatusReporter struct {
+	metrics     *Metrics
+	outbox      IOutboxBacklogProvider
+	projections IProjectionLagProvider
+	cfg         StatusConfig
+}
+
+// NewStatusReporter 创建状态汇总器；outbox 与 projections 可为 nil（对应项不输出、不检查）。
+func NewStatusReporter(metrics *Metrics, outbox IOutboxBacklogProvider, projections IProjectionLagProvider, cfg StatusConfig) (*StatusReporter, error) {
+	if metrics == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "metrics cannot be nil")
+	}
+	return &StatusReporter{metrics: metrics, outbox: outbox, projections: projections, cfg: cfg}, nil
+}
+
+// Status 采集一次状态并生成健康报告；单项采集失败时该项为 unhealthy，其余项照常输出。
+func (r *StatusReporter) Status(ctx context.Context) EventingStatus {
+	s := EventingStatus{Timestamp: Now()}
+	var results []CheckResult
+
+	if r.outbox != nil {
+		start := time.Now()
+		backlog, err := r.outbox.OutboxBacklog(ctx)
+		if err == nil {
+			s.Outbox = &backlog
+		}
+		results = append(results, checkResult(CheckOutboxBacklog, start, r.evaluateBacklog(backlog, err)))
+	}
+
+	start := time.Now()
+	s.OutboxPublishErrorRatePercent = r.publishErrorRate()
+	results = append(results, checkResult(CheckOutboxPublish, start, r.evaluatePublish(s.OutboxPublishErrorRatePercent)))
+
+	if r.projections != nil {
+		start := time.Now()
+		lags, err := r.projections.ProjectionLags(ctx)
+		if err == nil {
+			s.Projections = lags
+		}
+		results = append(results, checkResult(CheckProjectionLag, start, r.evaluateProjections(lags, err)))
+	}
+
+	overall := HealthStatusHealthy
+	for _, res := range results {
+		overall = worseStatus(overall, res.Status)
+	}
+	s.Health = HealthReport{Timestamp: s.Timestamp, Status: overall, Checks: results}
+	return s
+}
+
+// RegisterChecks 把状态检查项注册到健康注册表，使 /healthz 与状态端点使用同一套阈值。
+func (r *StatusReporter) RegisterChecks(h *HealthRegistry) error {
+	if h == nil {
+		return errors.NewCode(errors.InvalidInput, "health registry cannot be nil")
+	}
+	if r.outbox != nil {
+		if err := h.Register(CheckOutboxBacklog, func(ctx context.Context) (HealthStatus, string, error) {
+			backlog, err := r.outbox.OutboxBacklog(ctx)
+			return r.evaluateBacklog(backlog, err).split()
+		}); err != nil {
+			return err
+		}
+	}
+	if err := h.Register(CheckOutboxPublish, func(context.Context) (HealthStatus, string, error) {
+		return r.evaluatePublish(r.publishErrorRate()).split()
+	}); err != nil {
+		return err
+	}
+	if r.projections != nil {
+		if err := h.Register(CheckProjectionLag, func(ctx context.Context) (HealthStatus, string, error) {
+			lags, err := r.projections.ProjectionLags(ctx)
+			return r.evaluateProjections(lags, err).split()
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type evaluation struct {
+	status  HealthStatus
+	message string
+	err     error
+}
+
+func (e evaluation) split() (HealthStatus, string, error) { return e.status, e.message, e.err }
+
+func checkResult(name string, start time.Time, e evaluation) CheckResult {
+	res := CheckResult{Name: name, Status: e.status, Message: e.message, DurationMillis: time.Since(start).Milliseconds()}
+	if e.err != nil {
+		res.Error = e.err.Error()
+	}
+	return res
+}
+
+func (r *StatusReporter) publishErrorRate() float64 {
+	s := r.metrics.Snapshot()
+	return errorRatePercent(s.OutboxPublishErrors, s.OutboxPublishCount)
+}
+
+func (r *StatusReporter) evaluateBacklog(backlog OutboxBacklog, err error) evaluation {
+	if err != nil {
+		return evaluation{status: HealthStatusUnhealthy, message: "collect outbox backlog failed", err: err}
+	}
+	var issues []string
+	if r.cfg.MaxPendingOutbox > 0 && backlog.PendingCount > r.cfg.MaxPendingOutbox {
+		issues = append(issues, fmt.Sprintf("pending %d > %d", backlog.PendingCount, r.cfg.MaxPendingOutbox))
+	}
+	if r.cfg.MaxOldestPendingAge > 0 && backlog.OldestPendingAge > r.cfg.MaxOldestPendingAge {
+		issues = append(issues, fmt.Sprintf("oldest pending age %s > %s", backlog.OldestPendingAge, r.cfg.MaxOldestPendingAge))
+	}
+	return degradedIf(issues)
+}
+
+func (r *StatusReporter) evaluatePublish(rate float64) evaluation {
+	var issues []string
+	if r.cfg.MaxPublishErrorRatePercent > 0 && rate > r.cfg.MaxPublishErrorRatePercent {
+		issues = append(issues, fmt.Sprintf("publish error_rate %.2f%% > %.2f%%", rate, r.cfg.MaxPublishErrorRatePercent))
+	}
+	return degradedIf(issues)
+}
+
+func (r *StatusReporter) evaluateProjections(lags []ProjectionLag, err error) evaluation {
+	if err != nil {
+		return evaluation{status: HealthStatusUnhealthy, message: "collect projection lag failed", err: err}
+	}
+	var issues []string
+	if r.cfg.MaxProjectionLag > 0 {
+		for _, lag := range lags {
+			if lag.Lag > r.cfg.MaxProjectionLag {
+				issues = append(issues, fmt.Sprintf("projection %s lag %s > %s", lag.Name, lag.Lag, r.cfg.MaxProjectionLag))
+			}
+		}
+	}
+	return degradedIf(issues)
+}
+
+func degradedIf(issues []string) evaluation {
+	if len(issues) == 0 {
+		return evaluation{status: HealthStatusHealthy, message: "ok"}
+	}
+	return evaluation{status: HealthStatusDegraded, message: strings.Join(issues, "; ")}
+}
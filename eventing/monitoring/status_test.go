@@ -0,0 +1,76 @@
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+)
+
+type fakeBacklog struct {
+	backlog OutboxBacklog
+	err     error
+}
+
+func (f fakeBacklog) OutboxBacklog(context.Context) (OutboxBacklog, error) { return f.backlog, f.err }
+
+type fakeLags []ProjectionLag
+
+func (f fakeLags) ProjectionLags(context.Context) ([]ProjectionLag, error) { return f, nil }
+
+// TestStatusReporter_Status 验证状态汇总、阈值评估与 Prometheus 输出。
+func TestStatusReporter_Status(t *testing.T) {
+	metrics := NewMetrics()
+	metrics.RecordOutboxPublish(time.Millisecond, false)
+	metrics.RecordOutboxPublish(time.Millisecond, true)
+
+	cfg := DefaultStatusConfig()
+	cfg.MaxProjectionLag = time.Minute
+	reporter, err := NewStatusReporter(metrics,
+		fakeBacklog{backlog: OutboxBacklog{PendingCount: 3, OldestPendingAge: 10 * time.Second}},
+		fakeLags{{Name: `or"ders`, Position: 7, Lag: 2 * time.Minute}},
+		cfg)
+	require.NoError(t, err)
+
+	status := reporter.Status(context.Background())
+	require.Equal(t, int64(3), status.Outbox.PendingCount)
+	require.InDelta(t, 50.0, status.OutboxPublishErrorRatePercent, 0.001)
+	require.Len(t, status.Projections, 1)
+	require.Equal(t, HealthStatusDegraded, status.Health.Status)
+	require.Len(t, status.Health.Checks, 3)
+	require.Equal(t, HealthStatusHealthy, status.Health.Checks[0].Status)
+	require.Equal(t, HealthStatusDegraded, status.Health.Checks[1].Status)
+	require.Equal(t, HealthStatusDegraded, status.Health.Checks[2].Status)
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePrometheus(&buf, status))
+	out := buf.String()
+	require.Contains(t, out, "# TYPE gochen_outbox_pending_entries gauge\ngochen_outbox_pending_entries 3\n")
+	require.Contains(t, out, "gochen_outbox_oldest_pending_age_seconds 10\n")
+	require.Contains(t, out, "gochen_outbox_publish_error_ratio 0.5\n")
+	require.Contains(t, out, `gochen_projection_checkpoint_lag_seconds{projection="or\"ders"} 120`)
+	require.Contains(t, out, "gochen_eventing_health_status 1\n")
+
+	health := NewHealthRegistry()
+	require.NoError(t, reporter.RegisterChecks(health))
+	require.Equal(t, HealthStatusDegraded, health.Report(context.Background()).Status)
+}
+
+// TestStatusReporter_CollectFailureIsUnhealthy 验证采集失败时对应项为 unhealthy。
+func TestStatusReporter_CollectFailureIsUnhealthy(t *testing.T) {
+	reporter, err := NewStatusReporter(NewMetrics(), fakeBacklog{err: errors.NewCode(errors.Database, "db down")}, nil, DefaultStatusConfig())
+	require.NoError(t, err)
+
+	status := reporter.Status(context.Background())
+	require.Nil(t, status.Outbox)
+	require.Equal(t, HealthStatusUnhealthy, status.Health.Status)
+	require.Equal(t, CheckOutboxBacklog, status.Health.Checks[0].Name)
+	require.NotEmpty(t, status.Health.Checks[0].Error)
+
+	_, err = NewStatusReporter(nil, nil, nil, DefaultStatusConfig())
+	require.True(t, errors.Is(err, errors.InvalidInput))
+}
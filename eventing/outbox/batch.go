@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"gochen/clock"
 	"gochen/db"
 	"gochen/db/sql/sqlbuilder"
 	"gochen/errors"
@@ -41,6 +42,7 @@ type ClaimedEntry struct {
 type BatchOperations struct {
 	db        db.IDatabase
 	tableName string
+	clock     clock.IClock
 }
 
 // NewBatchOperations 创建批量Operations。
@@ -53,7 +55,18 @@ func NewBatchOperationsWithTable(db db.IDatabase, tableName string) IBatchReposi
 	if tableName == "" {
 		tableName = "event_outbox"
 	}
-	return &BatchOperations{db: db, tableName: tableName}
+	return NewBatchOperationsWithClock(db, tableName, nil)
+}
+
+// NewBatchOperationsWithClock 创建批量Operations，并使用注入的时钟写入 published_at（nil 时使用真实时钟）。
+func NewBatchOperationsWithClock(db db.IDatabase, tableName string, clk clock.IClock) IBatchRepository {
+	if tableName == "" {
+		tableName = "event_outbox"
+	}
+	if clk == nil {
+		clk = clock.NewRealClock()
+	}
+	return &BatchOperations{db: db, tableName: tableName, clock: clk}
 }
 
 // MarkAsPublishedBatch 批量标记为已发布。
@@ -68,7 +81,7 @@ func (b *BatchOperations) MarkAsPublishedBatch(ctx context.Context, entries []Cl
 		return nil
 	}
 
-	now := time.Now()
+	now := b.clock.Now()
 
 	sq, err := sqlbuilder.New(b.db)
 	if err != nil {
@@ -0,0 +1,277 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gochen/clock"
+	"gochen/db"
+	"gochen/db/dialect"
+	"gochen/db/sql/safeident"
+	"gochen/db/sql/sqlbuilder"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/bus"
+	estore "gochen/eventing/store"
+	"gochen/logging"
+)
+
+// DefaultCDCTableName 是 CDC Outbox 表的默认表名（与 Debezium outbox event router 的默认约定一致）。
+const DefaultCDCTableName = "outbox"
+
+// CDCOutboxConfig 定义 CDC Outbox 仓储配置。
+type CDCOutboxConfig struct {
+	// TableName 表名（默认 DefaultCDCTableName）。
+	TableName string
+
+	// DeleteAfterInsert 为 true 时在同一事务内写入后立即删除记录：CDC 从 WAL/binlog 捕获 INSERT，
+	// Debezium outbox event router 忽略 DELETE，表始终保持为空，无需清理。
+	DeleteAfterInsert bool
+
+	// Clock 用于生成 created_at（nil 时使用真实时钟）。
+	Clock clock.IClock
+}
+
+// CDCOutboxRepository 按 Debezium outbox event router 约定的表结构写入 Outbox 记录，由 CDC 负责投递。
+//
+// 表结构（见 EnsureTable）：
+//   - id：事件 ID（router 写入消息头 id，可用于消费侧去重）
+//   - aggregatetype：聚合类型（router 默认据此路由到 outbox.event.<aggregatetype> topic）
+//   - aggregateid：聚合 ID 的字符串形式（router 用作消息 key，保证同一聚合的分区内顺序）
+//   - type：事件类型
+//   - payload：事件信封 JSON（与轮询 Outbox 的 event_data 相同，可按注册表还原为 eventing.Event）
+//   - created_at：写入时间，供 DeletePublished 按保留期清理
+//
+// 仓储不维护发布状态：ClaimPendingEntries 始终返回空，配套使用 NoopPublisher，
+// 不要与轮询 Publisher/ParallelPublisher 同时投递同一张表。
+type CDCOutboxRepository[ID comparable] struct {
+	db                db.IDatabase
+	eventStore        IEventStoreWithDB[ID]
+	tableName         string
+	deleteAfterInsert bool
+	dialect           dialect.Dialect
+	logger            logging.ILogger
+	clock             clock.IClock
+}
+
+// NewCDCOutboxRepository 创建 CDC Outbox 仓储。
+func NewCDCOutboxRepository[ID comparable](
+	database db.IDatabase,
+	eventStore IEventStoreWithDB[ID],
+	logger logging.ILogger,
+	cfg CDCOutboxConfig,
+) (*CDCOutboxRepository[ID], error) {
+	if logger == nil {
+		logger = logging.ComponentLogger("eventing.outbox.cdc")
+	}
+	if database == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "db cannot be nil")
+	}
+	if eventStore == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "eventStore cannot be nil")
+	}
+	tableName := strings.TrimSpace(cfg.TableName)
+	if tableName == "" {
+		tableName = DefaultCDCTableName
+	}
+	if !safeident.IsSafeIdentifier(tableName) {
+		return nil, errors.NewCode(errors.InvalidInput, fmt.Sprintf("invalid cdc outbox table name: %s", tableName))
+	}
+	clk := cfg.Clock
+	if clk == nil {
+		clk = clock.NewRealClock()
+	}
+	return &CDCOutboxRepository[ID]{
+		db:                database,
+		eventStore:        eventStore,
+		tableName:         tableName,
+		deleteAfterInsert: cfg.DeleteAfterInsert,
+		dialect:           dialect.FromDatabase(database),
+		logger:            logger,
+		clock:             clk,
+	}, nil
+}
+
+// EnsureTable 创建 CDC Outbox 表（已存在时不做修改）。
+func (r *CDCOutboxRepository[ID]) EnsureTable(ctx context.Context) error {
+	quotedTable := r.dialect.QuoteIdentifier(r.tableName)
+	var query string
+	switch r.dialect.Name() {
+	case dialect.NameSQLite:
+		query = fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				id TEXT PRIMARY KEY,
+				aggregatetype TEXT NOT NULL,
+				aggregateid TEXT NOT NULL,
+				type TEXT NOT NULL,
+				payload TEXT NOT NULL,
+				created_at DATETIME NOT NULL
+			)
+		`, quotedTable)
+	case dialect.NamePostgres:
+		query = fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				id VARCHAR(255) PRIMARY KEY,
+				aggregatetype VARCHAR(255) NOT NULL,
+				aggregateid VARCHAR(255) NOT NULL,
+				type VARCHAR(255) NOT NULL,
+				payload JSONB NOT NULL,
+				created_at TIMESTAMPTZ NOT NULL
+			)
+		`, quotedTable)
+	default:
+		query = fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				id VARCHAR(255) PRIMARY KEY,
+				aggregatetype VARCHAR(255) NOT NULL,
+				aggregateid VARCHAR(255) NOT NULL,
+				type VARCHAR(255) NOT NULL,
+				payload JSON NOT NULL,
+				created_at DATETIME NOT NULL
+			)
+		`, quotedTable)
+	}
+	if _, err := r.db.Exec(ctx, query); err != nil {
+		return errors.Wrap(err, errors.Database, "create cdc outbox table failed").
+			WithContext("table", r.tableName)
+	}
+	return nil
+}
+
+// SaveWithEvents 在同一事务内追加事件流并写入 CDC Outbox 记录。
+func (r *CDCOutboxRepository[ID]) SaveWithEvents(ctx context.Context, aggregateID ID, events []eventing.Event[ID]) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, errors.Database, "begin transaction failed").
+			WithContext("aggregate_id", aggregateID)
+	}
+	defer tx.Rollback()
+
+	storable := estore.ToStorable(events)
+	expectedVersion := events[0].GetVersion() - 1
+	if err := r.eventStore.AppendEventsWithDB(ctx, tx, aggregateID, storable, expectedVersion); err != nil {
+		// 保留事件存储的原始错误码语义（例如 Concurrency），仅补充上下文信息。
+		var appErr *errors.AppError
+		if errors.As(err, &appErr) && appErr != nil {
+			return appErr.
+				WithContext("aggregate_id", aggregateID).
+				WithContext("event_count", len(events))
+		}
+		return errors.Wrap(err, errors.Database, "append events failed").
+			WithContext("aggregate_id", aggregateID).
+			WithContext("event_count", len(events))
+	}
+
+	if err := r.insertEntries(ctx, tx, storable); err != nil {
+		r.logger.Warn(ctx, "failed to save cdc outbox entries", logging.Any("aggregate_id", aggregateID), logging.Error(err))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.Database, "commit transaction failed").
+			WithContext("aggregate_id", aggregateID)
+	}
+	return nil
+}
+
+// StageEvents 在调用方事务内写入 CDC Outbox 记录（实现 bus.ITxEventStager）。
+func (r *CDCOutboxRepository[ID]) StageEvents(ctx context.Context, tx db.ITransaction, events []eventing.IEvent) error {
+	if tx == nil {
+		return errors.NewCode(errors.InvalidInput, "transaction is nil")
+	}
+	storable := make([]eventing.IStorableEvent[ID], 0, len(events))
+	for i, evt := range events {
+		se, ok := evt.(eventing.IStorableEvent[ID])
+		if !ok {
+			return errors.NewCode(errors.InvalidInput, "event is not storable with the repository aggregate id type").
+				WithContext("index", i).
+				WithContext("event_type", fmt.Sprintf("%T", evt))
+		}
+		storable = append(storable, se)
+	}
+	return r.insertEntries(ctx, tx, storable)
+}
+
+// insertEntries 逐条写入 CDC Outbox 记录；DeleteAfterInsert 时随即删除。
+func (r *CDCOutboxRepository[ID]) insertEntries(ctx context.Context, tx db.ITransaction, events []eventing.IStorableEvent[ID]) error {
+	sq, err := sqlbuilder.New(tx)
+	if err != nil {
+		return errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+
+	now := r.clock.Now()
+	for _, event := range events {
+		payload, err := serializeOutboxEvent(event)
+		if err != nil {
+			return errors.Wrap(err, errors.Internal, "serialize event failed").
+				WithContext("event_id", event.GetID())
+		}
+		_, err = sq.InsertInto(r.tableName).
+			Columns("id", "aggregatetype", "aggregateid", "type", "payload", "created_at").
+			Values(event.GetID(), event.GetAggregateType(), fmt.Sprint(event.GetAggregateID()), event.GetType(), payload, now).
+			Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, errors.Database, "insert cdc outbox entry failed").
+				WithContext("event_id", event.GetID())
+		}
+		if !r.deleteAfterInsert {
+			continue
+		}
+		if _, err := sq.DeleteFrom(r.tableName).Where("id = ?", event.GetID()).Exec(ctx); err != nil {
+			return errors.Wrap(err, errors.Database, "delete cdc outbox entry after insert failed").
+				WithContext("event_id", event.GetID())
+		}
+	}
+	return nil
+}
+
+// ClaimPendingEntries 始终返回空：CDC 模式下记录由 CDC 投递，不参与轮询。
+func (r *CDCOutboxRepository[ID]) ClaimPendingEntries(ctx context.Context, limit int) ([]OutboxEntry[ID], error) {
+	return nil, nil
+}
+
+// MarkAsPublished 在 CDC 模式下不受支持。
+func (r *CDCOutboxRepository[ID]) MarkAsPublished(ctx context.Context, entryID int64, claimToken string) error {
+	return errors.NewCode(errors.Unsupported, "cdc outbox does not track publish state")
+}
+
+// MarkAsFailed 在 CDC 模式下不受支持。
+func (r *CDCOutboxRepository[ID]) MarkAsFailed(ctx context.Context, entryID int64, claimToken string, errorMsg string, nextRetryAt time.Time) error {
+	return errors.NewCode(errors.Unsupported, "cdc outbox does not track publish state")
+}
+
+// RenewClaim 在 CDC 模式下不受支持。
+func (r *CDCOutboxRepository[ID]) RenewClaim(ctx context.Context, entryID int64, claimToken string) error {
+	return errors.NewCode(errors.Unsupported, "cdc outbox does not support claims")
+}
+
+// DeletePublished 删除早于 olderThan 的记录：记录提交后即已进入 WAL/binlog，保留期只用于排障与 CDC 重放。
+func (r *CDCOutboxRepository[ID]) DeletePublished(ctx context.Context, olderThan time.Time) error {
+	if r.deleteAfterInsert {
+		return nil
+	}
+	sq, err := sqlbuilder.New(r.db)
+	if err != nil {
+		return errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+	result, err := sq.DeleteFrom(r.tableName).Where("created_at < ?", olderThan).Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, errors.Database, "delete cdc outbox entries failed").
+			WithContext("older_than", olderThan)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected > 0 {
+		r.logger.Info(ctx, "cleaned up cdc outbox entries", logging.Int64("deleted", rowsAffected))
+	}
+	return nil
+}
+
+var (
+	_ IOutboxRepository[int64] = (*CDCOutboxRepository[int64])(nil)
+	_ bus.ITxEventStager       = (*CDCOutboxRepository[int64])(nil)
+)
@@ -0,0 +1,82 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/logging"
+)
+
+// TestCDCOutboxRepository_SaveWithEvents 验证记录按 Debezium outbox 约定的列写入，payload 可还原为事件。
+func TestCDCOutboxRepository_SaveWithEvents(t *testing.T) {
+	database := setupTestDB(t)
+	eventStore := &MockEventStoreWithDB{}
+	repo, err := NewCDCOutboxRepository[int64](database, eventStore, logging.NewNoopLogger(), CDCOutboxConfig{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, repo.EnsureTable(ctx))
+	require.NoError(t, repo.SaveWithEvents(ctx, 42, []eventing.Event[int64]{
+		newTestEvent(42, 1, "event-1", map[string]any{"value": 7}),
+	}))
+	require.Len(t, eventStore.events, 1)
+
+	var id, aggregateType, aggregateID, eventType, payload string
+	require.NoError(t, database.QueryRow(ctx,
+		`SELECT id, aggregatetype, aggregateid, type, payload FROM outbox`).
+		Scan(&id, &aggregateType, &aggregateID, &eventType, &payload))
+	require.Equal(t, "event-1", id)
+	require.Equal(t, "TestAggregate", aggregateType)
+	require.Equal(t, "42", aggregateID)
+	require.Equal(t, "TestEvent", eventType)
+
+	entry := OutboxEntry[int64]{EventData: payload}
+	evt, err := entry.ToEventWith(newTestRegistry(t), newTestUpgraders())
+	require.NoError(t, err)
+	require.Equal(t, int64(42), evt.AggregateID)
+	var decoded testEventPayload
+	require.NoError(t, evt.GetPayload().DecodeTo(&decoded))
+	require.Equal(t, 7, decoded.Value)
+
+	// CDC 模式不参与轮询。
+	entries, err := repo.ClaimPendingEntries(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+	require.True(t, errors.Is(repo.MarkAsPublished(ctx, 1, "token"), errors.Unsupported))
+
+	require.NoError(t, repo.DeletePublished(ctx, time.Now().Add(time.Minute)))
+	var count int
+	require.NoError(t, database.QueryRow(ctx, `SELECT COUNT(1) FROM outbox`).Scan(&count))
+	require.Zero(t, count)
+}
+
+// TestCDCOutboxRepository_StageEventsDeleteAfterInsert 验证事务内暂存且 DeleteAfterInsert 时表保持为空。
+func TestCDCOutboxRepository_StageEventsDeleteAfterInsert(t *testing.T) {
+	database := setupTestDB(t)
+	repo, err := NewCDCOutboxRepository[int64](database, &MockEventStoreWithDB{}, logging.NewNoopLogger(), CDCOutboxConfig{
+		TableName:         "outbox_events",
+		DeleteAfterInsert: true,
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, repo.EnsureTable(ctx))
+
+	tx, err := database.Begin(ctx)
+	require.NoError(t, err)
+	evt := newTestEvent(1, 1, "event-1", nil)
+	require.NoError(t, repo.StageEvents(ctx, tx, []eventing.IEvent{&evt}))
+	require.NoError(t, tx.Commit())
+
+	var count int
+	require.NoError(t, database.QueryRow(ctx, `SELECT COUNT(1) FROM outbox_events`).Scan(&count))
+	require.Zero(t, count)
+
+	_, err = NewCDCOutboxRepository[int64](database, &MockEventStoreWithDB{}, nil, CDCOutboxConfig{TableName: "bad name"})
+	require.True(t, errors.Is(err, errors.InvalidInput))
+}
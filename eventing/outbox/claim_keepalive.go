@@ -5,6 +5,7 @@ import (
 	stderrors "errors"
 	"time"
 
+	"gochen/clock"
 	"gochen/errors"
 )
 
@@ -30,6 +31,7 @@ func runWithClaimKeepalive[ID comparable](
 	ctx context.Context,
 	repo IOutboxRepository[ID],
 	entry OutboxEntry[ID],
+	clk clock.IClock,
 	claimLease time.Duration,
 	renewInterval time.Duration,
 	run func(context.Context) error,
@@ -43,7 +45,10 @@ func runWithClaimKeepalive[ID comparable](
 	if run == nil {
 		return &claimKeepaliveError{cause: errors.NewCode(errors.InvalidInput, "run func is nil")}
 	}
-	if !entry.claimIsActive() {
+	if clk == nil {
+		clk = clock.NewRealClock()
+	}
+	if !entry.claimIsActive(clk.Now()) {
 		return &claimKeepaliveError{cause: errors.NewCode(errors.Conflict, "outbox claim is expired").
 			WithContext("entry_id", entry.ID)}
 	}
@@ -68,14 +73,19 @@ func runWithClaimKeepalive[ID comparable](
 	go func() {
 		defer close(doneCh)
 
-		ticker := time.NewTicker(interval)
+		ticker, err := clk.NewTicker(interval)
+		if err != nil {
+			renewErrCh <- err
+			cancel()
+			return
+		}
 		defer ticker.Stop()
 
 		for {
 			select {
 			case <-runCtx.Done():
 				return
-			case <-ticker.C:
+			case <-ticker.C():
 				if err := repo.RenewClaim(runCtx, entry.ID, entry.ClaimToken); err != nil {
 					if stderrors.Is(err, context.Canceled) {
 						select {
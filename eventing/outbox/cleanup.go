@@ -4,6 +4,7 @@ import (
 	"strings"
 	"time"
 
+	"gochen/clock"
 	"gochen/db"
 	"gochen/db/dialect"
 	"gochen/errors"
@@ -43,6 +44,9 @@ type CleanupPolicy struct {
 	// 如果为 true，只统计不实际删除。
 	// 默认：false
 	DryRun bool `json:"dry_run"`
+
+	// Clock 用于计算保留期截止时间与耗时（nil 时使用真实时钟）。
+	Clock clock.IClock `json:"-"`
 }
 
 const defaultCleanupBatchSize = 1000
@@ -64,6 +68,7 @@ type CleanupService struct {
 	dialect dialect.Dialect
 	policy  CleanupPolicy
 	log     logging.ILogger
+	clock   clock.IClock
 }
 
 // CleanupResult 表示一次清理任务的执行结果。
@@ -98,6 +103,10 @@ func NewCleanupService(
 	if policy.BatchSize <= 0 {
 		policy.BatchSize = defaultCleanupBatchSize
 	}
+	clk := policy.Clock
+	if clk == nil {
+		clk = clock.NewRealClock()
+	}
 	if policy.ArchiveTable == "" {
 		policy.ArchiveTable = "event_outbox_archive"
 	}
@@ -115,6 +124,7 @@ func NewCleanupService(
 		dialect: dialect.FromDatabase(db),
 		policy:  policy,
 		log:     logger,
+		clock:   clk,
 	}, nil
 }
 
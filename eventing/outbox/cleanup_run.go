@@ -7,10 +7,10 @@ import (
 
 // Cleanup 根据策略执行一次删除或归档任务，并返回结果摘要。
 func (s *CleanupService) Cleanup(ctx context.Context) (*CleanupResult, error) {
-	startTime := time.Now()
+	startTime := s.clock.Now()
 	result := &CleanupResult{}
 
-	cutoffTime := time.Now().AddDate(0, 0, -s.policy.RetentionDays)
+	cutoffTime := startTime.AddDate(0, 0, -s.policy.RetentionDays)
 
 	s.log.Info(ctx, "cleanup started")
 
@@ -21,7 +21,7 @@ func (s *CleanupService) Cleanup(ctx context.Context) (*CleanupResult, error) {
 		result.DeletedCount, err = s.deleteOldRecords(ctx, cutoffTime)
 	}
 
-	result.Duration = time.Since(startTime)
+	result.Duration = s.clock.Now().Sub(startTime)
 	result.Error = err
 
 	if err != nil {
@@ -32,3 +32,13 @@ func (s *CleanupService) Cleanup(ctx context.Context) (*CleanupResult, error) {
 	s.log.Info(ctx, "cleanup completed")
 	return result, nil
 }
+
+// PruneBefore 按策略删除或归档发布时间早于 cutoff 的已发布记录，返回处理的记录数。
+//
+// 截止时间由调用方决定（忽略 RetentionDays），供 retention.Engine 按统一的保留策略调度。
+func (s *CleanupService) PruneBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	if s.policy.ArchiveEnabled {
+		return s.archiveOldRecords(ctx, cutoff)
+	}
+	return s.deleteOldRecords(ctx, cutoff)
+}
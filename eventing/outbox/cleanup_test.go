@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"gochen/clock"
 	"gochen/db"
 	"gochen/logging"
 )
@@ -112,6 +113,40 @@ func TestCleanupService_DeleteOldRecords_WorksOnSQLiteWithoutDeleteLimit(t *test
 	assert.Equal(t, 0, outboxCount)
 }
 
+func TestCleanupService_UsesInjectedClockForCutoff(t *testing.T) {
+	database := setupTestDB(t)
+	ctx := context.Background()
+
+	now := time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC)
+	for i, publishedAt := range []time.Time{now.AddDate(0, 0, -3), now.Add(-time.Hour)} {
+		_, err := database.Exec(ctx, `
+			INSERT INTO event_outbox (
+				id, aggregate_id, aggregate_type, event_id, event_type, event_data,
+				status, claim_token, created_at, published_at, retry_count, last_error, lease_until, next_retry_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`,
+			i+1, 1001, "Agg", fmt.Sprintf("event-%d", i+1), "TestEvent", `{"ok":true}`,
+			OutboxStatusPublished, "", publishedAt, publishedAt, 0, "", nil, nil,
+		)
+		require.NoError(t, err)
+	}
+
+	service, err := NewCleanupService(database, CleanupPolicy{
+		RetentionDays: 1,
+		BatchSize:     10,
+		Clock:         clock.NewManualClock(now),
+	}, logging.NewNoopLogger())
+	require.NoError(t, err)
+
+	result, err := service.Cleanup(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.DeletedCount)
+
+	var remainingID int64
+	require.NoError(t, database.QueryRow(ctx, `SELECT id FROM event_outbox`).Scan(&remainingID))
+	assert.Equal(t, int64(2), remainingID)
+}
+
 func TestCleanupService_ArchiveOldRecords_UsesSameBatchIDsForInsertAndDelete(t *testing.T) {
 	database := setupTestDB(t)
 	ctx := context.Background()
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"gochen/clock"
 	"gochen/codec"
 	"gochen/codec/idcodec"
 	"gochen/db"
@@ -72,6 +73,7 @@ type SQLDLQRepository[ID comparable] struct {
 	maxRetries  int
 	autoCleanup bool
 	codec       codec.ICodec[ID, any]
+	clock       clock.IClock
 }
 
 // NewSQLDLQRepository 为 `int64` 聚合 ID 创建一个 SQL DLQ 仓储实现。
@@ -104,12 +106,17 @@ func NewSQLDLQRepositoryWithCodec[ID comparable](
 	if idCodec == nil {
 		return nil, gerrors.NewCode(gerrors.InvalidInput, "codec cannot be nil")
 	}
+	clk := clock.IClock(clock.NewRealClock())
+	if provider, ok := any(outboxRepo).(clockProvider); ok {
+		clk = provider.GetClock()
+	}
 	return &SQLDLQRepository[ID]{
 		db:          db,
 		outboxRepo:  outboxRepo,
 		maxRetries:  maxRetries,
 		autoCleanup: autoCleanup,
 		codec:       idCodec,
+		clock:       clk,
 	}, nil
 }
 
@@ -125,7 +132,7 @@ func (r *SQLDLQRepository[ID]) MoveToDLQ(ctx context.Context, entry OutboxEntry[
 		EventData:       entry.EventData,
 		FailureReason:   entry.LastError,
 		RetryCount:      entry.RetryCount,
-		MovedAt:         time.Now(),
+		MovedAt:         r.clock.Now(),
 	}
 
 	// 使用事务保证 DLQ 与 Outbox 操作的原子性
@@ -302,7 +309,7 @@ func (r *SQLDLQRepository[ID]) RetryFromDLQ(ctx context.Context, entryID int64)
 		dlqEntry.EventData,
 		OutboxStatusPending,
 		"",
-		time.Now(),
+		r.clock.Now(),
 		0, // 重置重试计数
 	)
 	if err != nil {
@@ -2,6 +2,7 @@ package outbox
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -130,12 +131,14 @@ func (c *MetricsCollector) Collect(ctx context.Context) (*OutboxMetrics, error)
 }
 
 // collectStatusCounts 统计 pending/published/failed 三类记录数量。
+//
+// 说明：空表上 SUM 返回 NULL，需要 COALESCE 兜底为 0，否则扫描到 int64 会失败。
 func (c *MetricsCollector) collectStatusCounts(ctx context.Context, metrics *OutboxMetrics) error {
 	query := `
 		SELECT 
-			SUM(CASE WHEN status = 'pending' THEN 1 ELSE 0 END) as pending,
-			SUM(CASE WHEN status = 'published' THEN 1 ELSE 0 END) as published,
-			SUM(CASE WHEN status = 'failed' THEN 1 ELSE 0 END) as failed
+			COALESCE(SUM(CASE WHEN status = 'pending' THEN 1 ELSE 0 END), 0) as pending,
+			COALESCE(SUM(CASE WHEN status = 'published' THEN 1 ELSE 0 END), 0) as published,
+			COALESCE(SUM(CASE WHEN status = 'failed' THEN 1 ELSE 0 END), 0) as failed
 		FROM event_outbox
 	`
 
@@ -169,7 +172,7 @@ func (c *MetricsCollector) collectRetryStats(ctx context.Context, metrics *Outbo
 		SELECT 
 			COALESCE(MAX(retry_count), 0) as max_retry,
 			COALESCE(AVG(retry_count), 0) as avg_retry,
-			SUM(CASE WHEN retry_count > 3 THEN 1 ELSE 0 END) as high_retry
+			COALESCE(SUM(CASE WHEN retry_count > 3 THEN 1 ELSE 0 END), 0) as high_retry
 		FROM event_outbox
 		WHERE status != 'published'
 	`
@@ -194,14 +197,19 @@ func (c *MetricsCollector) collectTimeStats(ctx context.Context, metrics *Outbox
 	// 主要用于默认 Outbox SQL 仓储的监控场景；若使用其他数据库方言，
 	// 建议在业务侧提供自定义 MetricsCollector 实现或调整查询语句。
 
+	//
+	// 使用 ORDER BY + LIMIT 而不是 MIN(created_at)：聚合函数会丢失列的声明类型，
+	// SQLite 驱动会把结果当作字符串返回，无法扫描到 time.Time。
 	query := `
-		SELECT MIN(created_at)
+		SELECT created_at
 		FROM event_outbox
 		WHERE status = 'pending'
+		ORDER BY created_at ASC
+		LIMIT 1
 	`
 
 	err := c.db.QueryRow(ctx, query).Scan(&oldestCreatedAt)
-	if err != nil {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return err
 	}
 
@@ -0,0 +1,41 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestMetricsCollector_EmptyOutbox 验证空 Outbox 表上的聚合统计返回零值而不是 NULL 扫描错误。
+func TestMetricsCollector_EmptyOutbox(t *testing.T) {
+	ctx := context.Background()
+	database := setupTestDB(t)
+	_, err := database.Exec(ctx, `CREATE TABLE event_outbox_dlq (id INTEGER PRIMARY KEY AUTOINCREMENT)`)
+	require.NoError(t, err)
+
+	metrics, err := NewMetricsCollector(database).Collect(ctx)
+	require.NoError(t, err)
+	require.Zero(t, metrics.PendingCount)
+	require.Zero(t, metrics.PublishedCount)
+	require.Zero(t, metrics.FailedCount)
+	require.Zero(t, metrics.HighRetryCount)
+	require.Zero(t, metrics.DLQCount)
+}
+
+// TestMetricsCollector_PendingAge 验证 SQLite 下可以正确读取最老待发布记录的创建时间。
+func TestMetricsCollector_PendingAge(t *testing.T) {
+	ctx := context.Background()
+	database := setupTestDB(t)
+	_, err := database.Exec(ctx, `CREATE TABLE event_outbox_dlq (id INTEGER PRIMARY KEY AUTOINCREMENT)`)
+	require.NoError(t, err)
+	_, err = database.Exec(ctx, `INSERT INTO event_outbox (aggregate_id, aggregate_type, event_id, event_type, event_data, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		1, "TestAggregate", "evt-1", "TestEvent", "{}", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	metrics, err := NewMetricsCollector(database).Collect(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), metrics.PendingCount)
+	require.GreaterOrEqual(t, metrics.OldestPendingAge, time.Minute)
+}
@@ -21,6 +21,25 @@ func NewProvider(collector outbox.IMetricsCollector) (monitoring.IOutboxMetricsP
 	return &provider{collector: collector}, nil
 }
 
+// NewBacklogProvider 创建 Outbox 积压统计提供者（用于 monitoring.StatusReporter）。
+func NewBacklogProvider(collector outbox.IMetricsCollector) (monitoring.IOutboxBacklogProvider, error) {
+	if collector == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "collector cannot be nil")
+	}
+	return &provider{collector: collector}, nil
+}
+
+func (p *provider) OutboxBacklog(ctx context.Context) (monitoring.OutboxBacklog, error) {
+	metrics, err := p.collector.Collect(ctx)
+	if err != nil {
+		return monitoring.OutboxBacklog{}, err
+	}
+	return monitoring.OutboxBacklog{
+		PendingCount:     metrics.PendingCount,
+		OldestPendingAge: metrics.OldestPendingAge,
+	}, nil
+}
+
 func (p *provider) Health(ctx context.Context) (monitoring.HealthStatus, string, error) {
 	status, msg, err := p.collector.HealthStatus(ctx)
 	return mapHealthStatus(status), msg, err
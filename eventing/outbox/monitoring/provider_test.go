@@ -115,3 +115,24 @@ func TestNewProviderReturnsErrorOnNilCollector(t *testing.T) {
 		t.Fatalf("expected error")
 	}
 }
+
+// TestBacklogProvider 验证积压统计取自 collector 的待发布数与最老待发布年龄。
+func TestBacklogProvider(t *testing.T) {
+	t.Parallel()
+
+	collector := &fakeCollector{metrics: &outbox.OutboxMetrics{PendingCount: 4, OldestPendingAge: time.Minute}}
+	p, err := outboxmon.NewBacklogProvider(collector)
+	if err != nil {
+		t.Fatalf("NewBacklogProvider() error: %v", err)
+	}
+	backlog, err := p.OutboxBacklog(context.Background())
+	if err != nil {
+		t.Fatalf("OutboxBacklog() error: %v", err)
+	}
+	if backlog.PendingCount != 4 || backlog.OldestPendingAge != time.Minute {
+		t.Fatalf("unexpected backlog: %+v", backlog)
+	}
+	if _, err := outboxmon.NewBacklogProvider(nil); err == nil {
+		t.Fatalf("expected error for nil collector")
+	}
+}
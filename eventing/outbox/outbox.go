@@ -9,11 +9,14 @@ package outbox
 import (
 	"context"
 	"encoding/json"
+	"gochen/clock"
+	"gochen/config"
 	"gochen/contextx"
 	"gochen/errors"
 	"gochen/eventing"
 	"gochen/eventing/registry"
 	"gochen/eventing/upcast"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -75,6 +78,14 @@ type IOutboxRepository[ID comparable] interface {
 	DeletePublished(ctx context.Context, olderThan time.Time) error
 }
 
+// IClaimReleaser 可选能力：释放 claim 并安排下次发布时间，不增加 retry_count。
+//
+// Publisher 用它延后“同聚合前序记录失败”而未发布的记录：延后不是发布尝试，不应计入 MaxRetries 与 DLQ 判断。
+// 未实现时退化为 MarkAsFailed（延后会计入重试次数）。
+type IClaimReleaser interface {
+	ReleaseClaim(ctx context.Context, entryID int64, claimToken string, reason string, nextRetryAt time.Time) error
+}
+
 // IOutboxPublisher 抽象发件箱Publisher能力接口。
 type IOutboxPublisher interface {
 	// Start 启动后台发布任务
@@ -95,6 +106,13 @@ type OutboxConfig struct {
 	// 每次处理的最大记录数
 	BatchSize int `json:"batch_size"`
 
+	// PublishPartitions 是 Publisher 单批发布的分区并发度（默认 1，即串行）。
+	//
+	// 大于 1 时一批记录按聚合（AggregateType + AggregateID）哈希分区并发发布：同一聚合始终落在同一分区、
+	// 按 claim 顺序发布；某条记录失败后，同批内该聚合的后续记录不再发布、一并标记失败等待重试，
+	// 其他聚合与分区不受影响。
+	PublishPartitions int `json:"publish_partitions"`
+
 	// 最大重试次数
 	MaxRetries int `json:"max_retries"`
 
@@ -107,11 +125,41 @@ type OutboxConfig struct {
 	// 保留已发布记录的时间
 	RetentionPeriod time.Duration `json:"retention_period"`
 
+	// DisableCleanup 为 true 时发布器不再按 CleanupInterval/RetentionPeriod 清理已发布记录，
+	// 由 retention.Engine 等外部保留任务统一按策略删除或归档（见 retention.Outbox 与 CleanupService.PruneBefore）。
+	DisableCleanup bool `json:"disable_cleanup"`
+
 	// ClaimLease 是单条记录被 publisher claim 后的租约时长。
 	ClaimLease time.Duration `json:"claim_lease"`
 
 	// ClaimRenewInterval 是发布过程中续约 claim 的间隔；为 0 时默认使用 ClaimLease 的一半。
 	ClaimRenewInterval time.Duration `json:"claim_renew_interval"`
+
+	// TenantColumn 非空时（通常为 "tenant_id"）写入 Outbox 记录的租户 ID，便于按租户运维与排障。
+	// 租户取自事件 metadata，缺失时取 ctx；publisher claim 始终跨租户执行，下游通过事件 metadata 恢复租户上下文。
+	TenantColumn string `json:"tenant_column"`
+
+	// ProducerKeyPrefix 是幂等生产者键前缀（默认 DefaultProducerKeyPrefix）。
+	//
+	// 发布时写入事件元数据 messaging.MetadataProducerKey = "<前缀>:<记录 ID>"，同一记录的每次重发键不变；
+	// 多个服务共用 broker 或去重表时应使用不同前缀。
+	ProducerKeyPrefix string `json:"producer_key_prefix"`
+
+	// Clock 是发布轮询、清理截止时间、重试时间（NextRetryAt）与 claim 租约的时间来源；为 nil 时使用真实时钟。
+	//
+	// 测试中注入 clock.ManualClock 后可通过 Advance 推进发布周期与重试退避，无需等待真实时间。
+	Clock clock.IClock `json:"-"`
+}
+
+// DefaultProducerKeyPrefix 是 OutboxConfig.ProducerKeyPrefix 的默认值。
+const DefaultProducerKeyPrefix = "outbox"
+
+// ProducerKey 返回 Outbox 记录的幂等生产者键。
+func ProducerKey(prefix string, entryID int64) string {
+	if strings.TrimSpace(prefix) == "" {
+		prefix = DefaultProducerKeyPrefix
+	}
+	return prefix + ":" + strconv.FormatInt(entryID, 10)
 }
 
 func DefaultOutboxConfig() OutboxConfig {
@@ -123,9 +171,30 @@ func DefaultOutboxConfig() OutboxConfig {
 		CleanupInterval: 1 * time.Hour,
 		RetentionPeriod: 7 * 24 * time.Hour, // 保留 7 天
 		ClaimLease:      defaultClaimLease,
+
+		PublishPartitions: 1,
+		ProducerKeyPrefix: DefaultProducerKeyPrefix,
 	}
 }
 
+// ConfigFromSettings 把分层加载的 config.OutboxSettings 转换为 OutboxConfig（未设置的字段由发布器按默认值补齐）。
+func ConfigFromSettings(s config.OutboxSettings) OutboxConfig {
+	cfg := DefaultOutboxConfig()
+	cfg.PublishInterval = s.PublishInterval
+	cfg.BatchSize = s.BatchSize
+	cfg.PublishPartitions = s.PublishPartitions
+	cfg.MaxRetries = s.MaxRetries
+	cfg.RetryInterval = s.RetryInterval
+	cfg.CleanupInterval = s.CleanupInterval
+	cfg.RetentionPeriod = s.RetentionPeriod
+	cfg.DisableCleanup = s.DisableCleanup
+	cfg.ClaimLease = s.ClaimLease
+	if s.ProducerKeyPrefix != "" {
+		cfg.ProducerKeyPrefix = s.ProducerKeyPrefix
+	}
+	return cfg
+}
+
 func normalizeOutboxConfig(cfg OutboxConfig) OutboxConfig {
 	defaults := DefaultOutboxConfig()
 	if cfg.PublishInterval <= 0 {
@@ -134,6 +203,9 @@ func normalizeOutboxConfig(cfg OutboxConfig) OutboxConfig {
 	if cfg.BatchSize <= 0 {
 		cfg.BatchSize = defaults.BatchSize
 	}
+	if cfg.PublishPartitions <= 0 {
+		cfg.PublishPartitions = defaults.PublishPartitions
+	}
 	if cfg.MaxRetries <= 0 {
 		cfg.MaxRetries = defaults.MaxRetries
 	}
@@ -149,19 +221,38 @@ func normalizeOutboxConfig(cfg OutboxConfig) OutboxConfig {
 	if cfg.ClaimLease <= 0 {
 		cfg.ClaimLease = defaults.ClaimLease
 	}
+	if strings.TrimSpace(cfg.ProducerKeyPrefix) == "" {
+		cfg.ProducerKeyPrefix = defaults.ProducerKeyPrefix
+	}
 	if cfg.ClaimRenewInterval <= 0 || cfg.ClaimRenewInterval >= cfg.ClaimLease {
 		cfg.ClaimRenewInterval = cfg.ClaimLease / 2
 	}
 	if cfg.ClaimRenewInterval <= 0 {
 		cfg.ClaimRenewInterval = time.Second
 	}
+	if cfg.Clock == nil {
+		cfg.Clock = clock.NewRealClock()
+	}
 	return cfg
 }
 
+// clock 返回配置的时钟；未经 normalizeOutboxConfig 补齐时回退为真实时钟。
+func (cfg OutboxConfig) clock() clock.IClock {
+	if cfg.Clock == nil {
+		return clock.NewRealClock()
+	}
+	return cfg.Clock
+}
+
 type claimLeaseProvider interface {
 	GetClaimLease() time.Duration
 }
 
+// clockProvider 由携带注入时钟的仓储实现，供 DLQ 等配套组件复用同一时钟。
+type clockProvider interface {
+	GetClock() clock.IClock
+}
+
 func normalizeOutboxConfigForRepository[ID comparable](cfg OutboxConfig, repo IOutboxRepository[ID]) (OutboxConfig, error) {
 	requestedLease := cfg.ClaimLease
 	requestedRenewInterval := cfg.ClaimRenewInterval
@@ -247,12 +338,17 @@ func (entry *OutboxEntry[ID]) ToEventWith(
 
 // ShouldRetry 判断是否应该重试。
 func (entry *OutboxEntry[ID]) ShouldRetry(maxRetries int) bool {
+	return entry.ShouldRetryAt(time.Now(), maxRetries)
+}
+
+// ShouldRetryAt 以 now 为当前时间判断是否应该重试。
+func (entry *OutboxEntry[ID]) ShouldRetryAt(now time.Time, maxRetries int) bool {
 	return entry.Status == OutboxStatusFailed &&
 		entry.RetryCount < maxRetries &&
-		(entry.NextRetryAt == nil || time.Now().After(*entry.NextRetryAt))
+		(entry.NextRetryAt == nil || now.After(*entry.NextRetryAt))
 }
 
-func (entry *OutboxEntry[ID]) claimIsActive() bool {
+func (entry *OutboxEntry[ID]) claimIsActive(now time.Time) bool {
 	if entry == nil {
 		return false
 	}
@@ -262,11 +358,16 @@ func (entry *OutboxEntry[ID]) claimIsActive() bool {
 	if entry.ClaimToken == "" || entry.LeaseUntil == nil {
 		return false
 	}
-	return time.Now().Before(*entry.LeaseUntil)
+	return now.Before(*entry.LeaseUntil)
 }
 
 // CalculateNextRetryTime 计算下次重试时间（指数退避）。
 func (entry *OutboxEntry[ID]) CalculateNextRetryTime(baseInterval time.Duration) time.Time {
+	return entry.CalculateNextRetryTimeAt(time.Now(), baseInterval)
+}
+
+// CalculateNextRetryTimeAt 以 now 为当前时间计算下次重试时间（指数退避）。
+func (entry *OutboxEntry[ID]) CalculateNextRetryTimeAt(now time.Time, baseInterval time.Duration) time.Time {
 	// 指数退避：baseInterval * 2^retryCount，避免移位溢出
 	retryCount := entry.RetryCount
 	if retryCount < 0 {
@@ -279,5 +380,5 @@ func (entry *OutboxEntry[ID]) CalculateNextRetryTime(baseInterval time.Duration)
 	backoffMultiplier := 1 << retryCount // 2^retryCount，范围 [1,32]
 
 	delay := baseInterval * time.Duration(backoffMultiplier)
-	return time.Now().Add(delay)
+	return now.Add(delay)
 }
@@ -2,6 +2,8 @@ package outbox
 
 import (
 	"context"
+	"fmt"
+	"gochen/clock"
 	"gochen/errors"
 	"gochen/eventing/bus"
 	"gochen/eventing/registry"
@@ -134,12 +136,18 @@ func (p *Publisher[ID]) Start(ctx context.Context) error {
 		p.mu.Unlock()
 		return nil
 	}
+	// 在启动 goroutine 前创建 ticker，保证 Start 返回后推进注入的时钟即可触发首轮发布。
+	ticker, err := p.cfg.clock().NewTicker(p.cfg.PublishInterval)
+	if err != nil {
+		p.mu.Unlock()
+		return errors.Wrap(err, errors.InvalidInput, "failed to create outbox publish ticker")
+	}
 	p.started = true
 	runCtx, cancel := context.WithCancel(ctx)
 	p.runCancel = cancel
 	p.mu.Unlock()
 
-	go p.loop(runCtx)
+	go p.loop(runCtx, ticker)
 	return nil
 }
 
@@ -210,8 +218,7 @@ func (p *Publisher[ID]) PublishPending(ctx context.Context) error {
 }
 
 // loop 按配置周期重复执行单次发布与已发布记录清理。
-func (p *Publisher[ID]) loop(ctx context.Context) {
-	ticker := time.NewTicker(p.cfg.PublishInterval)
+func (p *Publisher[ID]) loop(ctx context.Context, ticker clock.ITicker) {
 	defer func() {
 		ticker.Stop()
 
@@ -228,15 +235,18 @@ func (p *Publisher[ID]) loop(ctx context.Context) {
 		select {
 		case <-p.stopCh:
 			return
-		case <-ticker.C:
+		case <-ticker.C():
 			p.processMu.Lock()
 			err := p.processOnce(ctx)
 			p.processMu.Unlock()
 			if err != nil {
 				p.log.Error(ctx, "outbox processOnce failed in loop", logging.Error(err))
 			}
-			// 定期清理已发布
-			if err := p.core().cleanupPublished(ctx, time.Now()); err != nil {
+			// 定期清理已发布（DisableCleanup 时由外部保留任务负责）
+			if p.cfg.DisableCleanup {
+				continue
+			}
+			if err := p.core().cleanupPublished(ctx, p.cfg.clock().Now()); err != nil {
 				p.log.Error(ctx, "outbox delete published failed", logging.Error(err))
 			}
 		case <-ctx.Done():
@@ -247,8 +257,6 @@ func (p *Publisher[ID]) loop(ctx context.Context) {
 
 // processOnce 处理一批待发布记录，并返回首个需要上报的错误。
 func (p *Publisher[ID]) processOnce(ctx context.Context) error {
-	var firstErr error
-
 	entries, err := p.core().claimPending(ctx)
 	if err != nil {
 		return err
@@ -261,7 +269,44 @@ func (p *Publisher[ID]) processOnce(ctx context.Context) error {
 		return err
 	}
 
+	partitions := partitionEntries(entries, p.cfg.PublishPartitions)
+	if len(partitions) == 1 {
+		return p.publishPartition(ctx, partitions[0])
+	}
+
+	// 各分区并发发布、分区内串行，错误按分区顺序取首个返回。
+	errs := make([]error, len(partitions))
+	var wg sync.WaitGroup
+	for i, partition := range partitions {
+		wg.Add(1)
+		go func(i int, partition []OutboxEntry[ID]) {
+			defer wg.Done()
+			errs[i] = p.publishPartition(ctx, partition)
+		}(i, partition)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishPartition 按 claim 顺序发布一个分区的记录，并返回首个需要上报的错误。
+//
+// 某条记录失败后，同一聚合在本分区内的后续记录不再发布，而是一并标记失败等待重试，以保持聚合内顺序。
+func (p *Publisher[ID]) publishPartition(ctx context.Context, entries []OutboxEntry[ID]) error {
+	var firstErr error
+	var blocked map[aggregateKey[ID]]blockedBy
+
 	for _, e := range entries {
+		key := aggregateKeyOf(e)
+		if blocker, ok := blocked[key]; ok {
+			p.deferEntry(ctx, &firstErr, e, blocker)
+			continue
+		}
+
 		result := p.core().publishClaimed(ctx, e)
 		if result.keepaliveErr != nil {
 			p.log.Error(ctx, "outbox claim keepalive failed",
@@ -271,10 +316,12 @@ func (p *Publisher[ID]) processOnce(ctx context.Context) error {
 			if firstErr == nil {
 				firstErr = result.keepaliveErr
 			}
+			blocked = p.blockAggregate(blocked, key, e)
 			continue
 		}
 		if result.err != nil {
 			p.handleEntryFailure(ctx, &firstErr, e, result.stage, result.err)
+			blocked = p.blockAggregate(blocked, key, e)
 			continue
 		}
 		if !p.core().markPublishedAfterSuccessfulPublish(ctx, ClaimedEntry{ID: e.ID, ClaimToken: e.ClaimToken}, 2) {
@@ -286,6 +333,50 @@ func (p *Publisher[ID]) processOnce(ctx context.Context) error {
 	return firstErr
 }
 
+// blockedBy 记录阻断某聚合后续记录的失败记录及其下次重试时间。
+type blockedBy struct {
+	entryID   int64
+	nextRetry time.Time
+}
+
+// blockAggregate 记录聚合在本批内被 failed 阻断；后续记录延后到与 failed 相同的重试时间，避免越过它先发布。
+func (p *Publisher[ID]) blockAggregate(blocked map[aggregateKey[ID]]blockedBy, key aggregateKey[ID], failed OutboxEntry[ID]) map[aggregateKey[ID]]blockedBy {
+	if blocked == nil {
+		blocked = make(map[aggregateKey[ID]]blockedBy)
+	}
+	blocked[key] = blockedBy{
+		entryID:   failed.ID,
+		nextRetry: failed.CalculateNextRetryTimeAt(p.cfg.clock().Now(), p.cfg.RetryInterval),
+	}
+	return blocked
+}
+
+// deferEntry 延后因同聚合前序记录失败而未发布的记录。
+//
+// 仓储实现 IClaimReleaser 时只释放 claim，不计入重试次数与 DLQ 判断；否则退化为标记失败。
+func (p *Publisher[ID]) deferEntry(ctx context.Context, firstErr *error, e OutboxEntry[ID], blocker blockedBy) {
+	core := p.core()
+	reason := fmt.Sprintf("deferred: earlier outbox entry %d of the same aggregate failed", blocker.entryID)
+	var err error
+	if releaser, ok := core.repo.(IClaimReleaser); ok {
+		err = releaser.ReleaseClaim(ctx, e.ID, e.ClaimToken, reason, blocker.nextRetry)
+	} else {
+		mark := core.buildFailureMark(e, reason)
+		mark.nextRetry = blocker.nextRetry
+		err = core.markFailed(ctx, mark)
+	}
+	if err != nil {
+		p.log.Error(ctx, "outbox mark deferred entry as failed", logging.Int64("entry", e.ID), logging.Error(err))
+		if *firstErr == nil {
+			*firstErr = err
+		}
+		return
+	}
+	p.log.Debug(ctx, "outbox entry deferred after earlier failure of the same aggregate",
+		logging.Int64("entry", e.ID),
+		logging.Int64("blocked_by", blocker.entryID))
+}
+
 type outboxFailureStage uint8
 
 const (
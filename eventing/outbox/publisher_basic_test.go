@@ -10,6 +10,7 @@ import (
 
 	"gochen/eventing"
 	"gochen/logging"
+	"gochen/messaging"
 )
 
 // TestNewPublisher 验证 NewPublisher。
@@ -156,6 +157,36 @@ func TestPublisher_MarkPublishedError(t *testing.T) {
 	assert.Equal(t, 1, eventBus.PublishedEventsLen())
 }
 
+// TestPublisher_RepublishKeepsProducerKey 验证“已发布但标记失败”后的重发携带相同的生产者键。
+func TestPublisher_RepublishKeepsProducerKey(t *testing.T) {
+	repo := &MockOutboxRepository{markPublishError: assert.AnError}
+	eventBus := &MockEventBus{}
+	cfg := OutboxConfig{BatchSize: 10, RetryInterval: 30 * time.Second, ProducerKeyPrefix: "orders"}
+
+	ctx := context.Background()
+	_ = repo.SaveWithEvents(ctx, 1, []eventing.Event[int64]{newTestEvent(1, 1, "event-1", nil)})
+	publisher, err := NewPublisher(repo, eventBus, cfg, logging.NewNoopLogger(), newTestRegistry(t), newTestUpgraders())
+	assert.NoError(t, err)
+	assert.NoError(t, publisher.PublishPending(ctx))
+
+	// 模拟崩溃后租约过期：记录回到 pending，被再次 claim 并重发。
+	repo.mu.Lock()
+	repo.markPublishError = nil
+	repo.entries[0].Status = OutboxStatusPending
+	entryID := repo.entries[0].ID
+	repo.mu.Unlock()
+	assert.NoError(t, publisher.PublishPending(ctx))
+
+	eventBus.mu.Lock()
+	defer eventBus.mu.Unlock()
+	assert.Len(t, eventBus.publishedEvents, 2)
+	for _, evt := range eventBus.publishedEvents {
+		key, ok := evt.GetMetadata().Get(messaging.MetadataProducerKey)
+		assert.True(t, ok)
+		assert.Equal(t, ProducerKey("orders", entryID), key)
+	}
+}
+
 func TestPublisher_PublishPending_RenewsClaimDuringSlowPublish(t *testing.T) {
 	repo := &MockOutboxRepository{}
 	publishStarted := make(chan struct{})
@@ -11,6 +11,7 @@ import (
 	"gochen/eventing/registry"
 	"gochen/eventing/upcast"
 	"gochen/logging"
+	"gochen/messaging"
 )
 
 type outboxPublisherCore[ID comparable] struct {
@@ -75,22 +76,25 @@ func (c outboxPublisherCore[ID]) publishClaimed(ctx context.Context, entry Outbo
 	}
 
 	var evt eventing.Event[ID]
-	err := runWithClaimKeepalive(ctx, c.repo, entry, c.cfg.ClaimLease, c.cfg.ClaimRenewInterval, func(processCtx context.Context) error {
-		decodeStart := time.Now()
+	err := runWithClaimKeepalive(ctx, c.repo, entry, c.cfg.clock(), c.cfg.ClaimLease, c.cfg.ClaimRenewInterval, func(processCtx context.Context) error {
+		decodeStart := c.cfg.clock().Now()
 		var decodeErr error
 		evt, decodeErr = entry.ToEventWith(c.eventRegistry, c.upgraders)
 		if c.metrics != nil {
-			c.metrics.RecordOutboxDecode(time.Since(decodeStart), decodeErr != nil)
+			c.metrics.RecordOutboxDecode(c.cfg.clock().Now().Sub(decodeStart), decodeErr != nil)
 		}
 		if decodeErr != nil {
 			return decodeErr
 		}
 
+		// 同一记录的每次重发携带相同的生产者键，供 broker 幂等生产或消费侧去重识别重复投递。
+		evt.GetMetadata().Set(messaging.MetadataProducerKey, ProducerKey(c.cfg.ProducerKeyPrefix, entry.ID))
+
 		stage = outboxFailurePublish
-		publishStart := time.Now()
+		publishStart := c.cfg.clock().Now()
 		publishErr := c.bus.PublishEvent(processCtx, &evt)
 		if c.metrics != nil {
-			c.metrics.RecordOutboxPublish(time.Since(publishStart), publishErr != nil)
+			c.metrics.RecordOutboxPublish(c.cfg.clock().Now().Sub(publishStart), publishErr != nil)
 		}
 		return publishErr
 	})
@@ -152,7 +156,7 @@ func (c outboxPublisherCore[ID]) buildFailureMark(entry OutboxEntry[ID], errorMs
 		entryID:    entry.ID,
 		claimToken: entry.ClaimToken,
 		errorMsg:   errorMsg,
-		nextRetry:  entry.CalculateNextRetryTime(c.cfg.RetryInterval),
+		nextRetry:  entry.CalculateNextRetryTimeAt(c.cfg.clock().Now(), c.cfg.RetryInterval),
 		moveToDLQ:  c.dlq != nil && entry.RetryCount+1 >= c.cfg.MaxRetries,
 		dlqEntry:   dlqEntry,
 	}
@@ -108,3 +108,22 @@ func TestPublisher_CleanupPublished(t *testing.T) {
 
 	_ = publisher.Stop(ctx)
 }
+
+// TestPublisher_DisableCleanup 验证 DisableCleanup 时发布器不再清理已发布记录（交由外部保留任务）。
+func TestPublisher_DisableCleanup(t *testing.T) {
+	repo := &MockOutboxRepository{}
+	cfg := OutboxConfig{
+		PublishInterval: 20 * time.Millisecond,
+		BatchSize:       10,
+		RetentionPeriod: 1 * time.Second,
+		DisableCleanup:  true,
+	}
+	publisher, err := NewPublisher(repo, &MockEventBus{}, cfg, logging.NewNoopLogger(), newTestRegistry(t), newTestUpgraders())
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	assert.NoError(t, publisher.Start(ctx))
+	time.Sleep(100 * time.Millisecond)
+	_ = publisher.Stop(ctx)
+	assert.False(t, repo.DeletedPublished())
+}
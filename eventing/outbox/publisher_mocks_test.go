@@ -16,6 +16,7 @@ type MockOutboxRepository struct {
 	entries            []OutboxEntry[int64]
 	markedPublished    []int64
 	markedFailed       []int64
+	released           []int64
 	renewedClaims      []int64
 	renewClaimFunc     func(ctx context.Context, entryID int64, claimToken string) error
 	deletedPublished   bool
@@ -192,6 +193,31 @@ func (m *MockOutboxRepository) MarkedPublishedLen() int {
 // MarkedFailedLen result：数量/计数。
 //
 // 返回：
+// ReleaseClaim 释放 claim 并记录延后原因，不增加重试次数。
+func (m *MockOutboxRepository) ReleaseClaim(ctx context.Context, entryID int64, claimToken string, reason string, nextRetryAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.released = append(m.released, entryID)
+	for i := range m.entries {
+		if m.entries[i].ID == entryID && m.entries[i].ClaimToken == claimToken {
+			m.entries[i].Status = OutboxStatusFailed
+			m.entries[i].ClaimToken = ""
+			m.entries[i].LeaseUntil = nil
+			m.entries[i].LastError = reason
+			m.entries[i].NextRetryAt = &nextRetryAt
+			break
+		}
+	}
+	return nil
+}
+
+// ReleasedLen 返回被延后（释放 claim）的记录数。
+func (m *MockOutboxRepository) ReleasedLen() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.released)
+}
+
 func (m *MockOutboxRepository) MarkedFailedLen() int {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -0,0 +1,24 @@
+package outbox
+
+import "context"
+
+// NoopPublisher 是不做任何投递的 IOutboxPublisher，用于由 CDC（如 Debezium）抽取 Outbox 表的部署。
+//
+// 组合根仍可按 IOutboxPublisher 统一管理生命周期，切换轮询/CDC 模式时无需改动调用方。
+type NoopPublisher struct{}
+
+// NewNoopPublisher 创建 NoopPublisher。
+func NewNoopPublisher() *NoopPublisher {
+	return &NoopPublisher{}
+}
+
+// Start 不启动任何后台任务。
+func (p *NoopPublisher) Start(ctx context.Context) error { return nil }
+
+// Stop 立即返回。
+func (p *NoopPublisher) Stop(ctx context.Context) error { return nil }
+
+// PublishPending 立即返回：记录由 CDC 投递。
+func (p *NoopPublisher) PublishPending(ctx context.Context) error { return nil }
+
+var _ IOutboxPublisher = (*NoopPublisher)(nil)
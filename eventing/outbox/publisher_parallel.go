@@ -2,12 +2,10 @@ package outbox
 
 import (
 	"context"
-	"encoding/binary"
-	"fmt"
-	"hash/fnv"
 	"sync"
 	"time"
 
+	"gochen/clock"
 	"gochen/errors"
 	"gochen/eventing/bus"
 	"gochen/eventing/registry"
@@ -157,6 +155,21 @@ func (p *ParallelPublisher[ID]) Start(ctx context.Context) error {
 		p.mu.Unlock()
 		return nil
 	}
+	// 在启动 goroutine 前创建 ticker，保证 Start 返回后推进注入的时钟即可触发拉取与清理。
+	fetchTicker, err := p.cfg.clock().NewTicker(p.cfg.PublishInterval)
+	if err != nil {
+		p.mu.Unlock()
+		return errors.Wrap(err, errors.InvalidInput, "failed to create outbox publish ticker")
+	}
+	var cleanupTicker clock.ITicker
+	if !p.cfg.DisableCleanup {
+		cleanupTicker, err = p.cfg.clock().NewTicker(p.cfg.CleanupInterval)
+		if err != nil {
+			fetchTicker.Stop()
+			p.mu.Unlock()
+			return errors.Wrap(err, errors.InvalidInput, "failed to create outbox cleanup ticker")
+		}
+	}
 	p.started = true
 	p.mu.Unlock()
 
@@ -181,11 +194,13 @@ func (p *ParallelPublisher[ID]) Start(ctx context.Context) error {
 
 	// 启动主循环（拉取任务）
 	p.wg.Add(1)
-	go p.fetchLoop(ctx)
+	go p.fetchLoop(ctx, fetchTicker)
 
-	// 启动清理任务
-	p.wg.Add(1)
-	go p.cleanupLoop(ctx)
+	// 启动清理任务（DisableCleanup 时由外部保留任务负责）
+	if cleanupTicker != nil {
+		p.wg.Add(1)
+		go p.cleanupLoop(ctx, cleanupTicker)
+	}
 
 	// 启用批量标记时，启动异步标记聚合器。
 	if p.batchOps != nil {
@@ -284,37 +299,7 @@ func (p *ParallelPublisher[ID]) PublishPending(ctx context.Context) error {
 
 // shardIndex 根据聚合类型和聚合 ID 计算记录应进入的 worker 分片。
 func (p *ParallelPublisher[ID]) shardIndex(entry OutboxEntry[ID]) int {
-	if p.workerCount <= 1 {
-		return 0
-	}
-	h := fnv.New32a()
-	_, _ = h.Write([]byte(entry.AggregateType))
-	_, _ = h.Write([]byte{0})
-
-	switch v := any(entry.AggregateID).(type) {
-	case string:
-		_, _ = h.Write([]byte(v))
-	case int64:
-		var b [8]byte
-		binary.LittleEndian.PutUint64(b[:], uint64(v))
-		_, _ = h.Write(b[:])
-	case uint64:
-		var b [8]byte
-		binary.LittleEndian.PutUint64(b[:], v)
-		_, _ = h.Write(b[:])
-	case int:
-		var b [8]byte
-		binary.LittleEndian.PutUint64(b[:], uint64(v))
-		_, _ = h.Write(b[:])
-	case uint:
-		var b [8]byte
-		binary.LittleEndian.PutUint64(b[:], uint64(v))
-		_, _ = h.Write(b[:])
-	default:
-		_, _ = fmt.Fprintf(h, "%v", v)
-	}
-
-	return int(h.Sum32() % uint32(p.workerCount))
+	return partitionIndex(entry, p.workerCount)
 }
 
 const defaultParallelPublisherStopTimeout = 30 * time.Second
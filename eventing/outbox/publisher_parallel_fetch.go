@@ -2,17 +2,15 @@ package outbox
 
 import (
 	"context"
-	"time"
 
+	"gochen/clock"
 	"gochen/errors"
 	"gochen/logging"
 )
 
 // fetchLoop 主循环，定期拉取待发布记录。
-func (p *ParallelPublisher[ID]) fetchLoop(ctx context.Context) {
+func (p *ParallelPublisher[ID]) fetchLoop(ctx context.Context, ticker clock.ITicker) {
 	defer p.wg.Done()
-
-	ticker := time.NewTicker(p.cfg.PublishInterval)
 	defer ticker.Stop()
 
 	for {
@@ -23,7 +21,7 @@ func (p *ParallelPublisher[ID]) fetchLoop(ctx context.Context) {
 			p.closeWorkChannels()
 			p.dispatchMu.Unlock()
 			return
-		case <-ticker.C:
+		case <-ticker.C():
 			p.dispatchMu.Lock()
 			_ = p.fetchOnce(ctx)
 			p.dispatchMu.Unlock()
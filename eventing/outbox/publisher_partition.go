@@ -0,0 +1,71 @@
+package outbox
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+)
+
+// partitionIndex 根据聚合类型和聚合 ID 计算记录所属分区；同一聚合的记录始终落在同一分区。
+func partitionIndex[ID comparable](entry OutboxEntry[ID], partitions int) int {
+	if partitions <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(entry.AggregateType))
+	_, _ = h.Write([]byte{0})
+
+	switch v := any(entry.AggregateID).(type) {
+	case string:
+		_, _ = h.Write([]byte(v))
+	case int64:
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], uint64(v))
+		_, _ = h.Write(b[:])
+	case uint64:
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], v)
+		_, _ = h.Write(b[:])
+	case int:
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], uint64(v))
+		_, _ = h.Write(b[:])
+	case uint:
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], uint64(v))
+		_, _ = h.Write(b[:])
+	default:
+		_, _ = fmt.Fprintf(h, "%v", v)
+	}
+
+	return int(h.Sum32() % uint32(partitions))
+}
+
+// partitionEntries 按聚合把一批记录划分为至多 partitions 个分区，分区内保持 claim 顺序；空分区被丢弃。
+func partitionEntries[ID comparable](entries []OutboxEntry[ID], partitions int) [][]OutboxEntry[ID] {
+	if partitions <= 1 || len(entries) <= 1 {
+		return [][]OutboxEntry[ID]{entries}
+	}
+	buckets := make([][]OutboxEntry[ID], partitions)
+	for _, entry := range entries {
+		idx := partitionIndex(entry, partitions)
+		buckets[idx] = append(buckets[idx], entry)
+	}
+	out := buckets[:0]
+	for _, bucket := range buckets {
+		if len(bucket) > 0 {
+			out = append(out, bucket)
+		}
+	}
+	return out
+}
+
+// aggregateKey 标识一条记录所属的聚合，用于批内失败隔离。
+type aggregateKey[ID comparable] struct {
+	aggregateType string
+	aggregateID   ID
+}
+
+func aggregateKeyOf[ID comparable](entry OutboxEntry[ID]) aggregateKey[ID] {
+	return aggregateKey[ID]{aggregateType: entry.AggregateType, aggregateID: entry.AggregateID}
+}
@@ -2,11 +2,18 @@ package outbox
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/eventing"
 	"gochen/logging"
 )
 
@@ -68,3 +75,111 @@ func TestPublisher_PublishPending_PublishesAllEntries(t *testing.T) {
 		require.Equal(t, OutboxStatusPublished, repo.entries[int64(i)].Status)
 	}
 }
+
+// TestPublisher_PublishPending_PartitionedIsolatesFailures 验证分区并发发布保持聚合内顺序，且失败只阻断同一聚合的后续记录。
+func TestPublisher_PublishPending_PartitionedIsolatesFailures(t *testing.T) {
+	const aggregates = 8
+	const eventsPerAggregate = 3
+
+	repo := &MockOutboxRepository{}
+	ctx := context.Background()
+	for agg := int64(1); agg <= aggregates; agg++ {
+		events := make([]eventing.Event[int64], 0, eventsPerAggregate)
+		for v := uint64(1); v <= eventsPerAggregate; v++ {
+			events = append(events, newTestEvent(agg, v, fmt.Sprintf("agg-%d-v%d", agg, v), nil))
+		}
+		require.NoError(t, repo.SaveWithEvents(ctx, agg, events))
+	}
+
+	var (
+		mu        sync.Mutex
+		published = make(map[int64][]uint64)
+		inFlight  atomic.Int32
+		maxFlight atomic.Int32
+	)
+	eventBus := &MockEventBus{
+		publishEventFunc: func(ctx context.Context, event eventing.IEvent) error {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				cur := maxFlight.Load()
+				if n <= cur || maxFlight.CompareAndSwap(cur, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+
+			if event.GetID() == "agg-1-v2" {
+				return assert.AnError
+			}
+			evt := event.(*eventing.Event[int64])
+			mu.Lock()
+			published[evt.AggregateID] = append(published[evt.AggregateID], evt.GetVersion())
+			mu.Unlock()
+			return nil
+		},
+	}
+
+	cfg := OutboxConfig{
+		BatchSize:         aggregates * eventsPerAggregate,
+		RetryInterval:     30 * time.Second,
+		PublishPartitions: 4,
+	}
+	p, err := NewPublisher(repo, eventBus, cfg, logging.NewNoopLogger(), newTestRegistry(t), newTestUpgraders())
+	require.NoError(t, err)
+	require.NoError(t, p.PublishPending(ctx))
+
+	require.Greater(t, maxFlight.Load(), int32(1), "partitions should publish concurrently")
+
+	mu.Lock()
+	defer mu.Unlock()
+	// 聚合 1 的第 2 条失败：第 3 条不得越过它发布。
+	require.Equal(t, []uint64{1}, published[1])
+	for agg := int64(2); agg <= aggregates; agg++ {
+		require.Equal(t, []uint64{1, 2, 3}, published[agg], "aggregate %d", agg)
+	}
+	require.Equal(t, (aggregates-1)*eventsPerAggregate+1, repo.MarkedPublishedLen())
+	// 只有失败的第 2 条计入重试；被阻断的第 3 条仅释放 claim，重试时间与第 2 条一致。
+	require.Equal(t, 1, repo.MarkedFailedLen())
+	require.Equal(t, 1, repo.ReleasedLen())
+	var failed, deferred OutboxEntry[int64]
+	for _, e := range repo.entries {
+		switch e.EventID {
+		case "agg-1-v2":
+			failed = e
+		case "agg-1-v3":
+			deferred = e
+		}
+	}
+	require.Equal(t, 1, failed.RetryCount)
+	require.Zero(t, deferred.RetryCount)
+	require.Equal(t, OutboxStatusFailed, deferred.Status)
+	require.Contains(t, deferred.LastError, "deferred:")
+	require.NotNil(t, deferred.NextRetryAt)
+	require.WithinDuration(t, *failed.NextRetryAt, *deferred.NextRetryAt, time.Second)
+}
+
+// TestPublisher_UsesInjectedClockForPollingAndRetry 验证发布轮询与 NextRetryAt 由 OutboxConfig.Clock 驱动，推进时钟即可触发而无需等待。
+func TestPublisher_UsesInjectedClockForPollingAndRetry(t *testing.T) {
+	clk := clock.NewManualClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	repo := &MockOutboxRepository{}
+	require.NoError(t, repo.SaveWithEvents(context.Background(), 1, []eventing.Event[int64]{newTestEvent(1, 1, "evt-1", nil)}))
+	bus := &MockEventBus{publishError: errors.NewCode(errors.Dependency, "broker down")}
+
+	cfg := OutboxConfig{PublishInterval: time.Hour, RetryInterval: 30 * time.Second, MaxRetries: 3, Clock: clk}
+	p, err := NewPublisher[int64](repo, bus, cfg, logging.NewNoopLogger(), newTestRegistry(t), newTestUpgraders())
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background()))
+	defer func() { _ = p.Stop(context.Background()) }()
+
+	require.Zero(t, repo.MarkedFailedLen(), "nothing should be published before the clock advances")
+	clk.Advance(time.Hour)
+	require.Eventually(t, func() bool { return repo.MarkedFailedLen() == 1 }, time.Second, time.Millisecond)
+
+	repo.mu.Lock()
+	nextRetryAt := repo.entries[0].NextRetryAt
+	repo.mu.Unlock()
+	require.NotNil(t, nextRetryAt)
+	require.Equal(t, clk.Now().Add(30*time.Second), *nextRetryAt)
+	require.True(t, repo.entries[0].ShouldRetryAt(clk.Now().Add(31*time.Second), cfg.MaxRetries))
+}
@@ -10,14 +10,18 @@ import (
 	"strings"
 	"time"
 
+	"gochen/clock"
 	"gochen/codec"
 	"gochen/codec/idcodec"
+	"gochen/contextx"
 	"gochen/errors"
 
+	"gochen/db/sql/safeident"
 	"gochen/db/sql/sqlbuilder"
 
 	"gochen/db"
 	"gochen/eventing"
+	"gochen/eventing/bus"
 	estore "gochen/eventing/store"
 	"gochen/logging"
 	"gochen/messaging"
@@ -34,6 +38,9 @@ type SimpleSQLOutboxRepository[ID comparable] struct {
 	logger      logging.ILogger
 	codec       codec.ICodec[ID, any]
 	claimLease  time.Duration
+	clock       clock.IClock
+	// tenantColumn 非空时写入记录的租户 ID，见 OutboxConfig.TenantColumn。
+	tenantColumn string
 }
 
 // IEventStoreWithDB 定义同时支持普通追加和事务内追加的事件存储能力。
@@ -86,17 +93,38 @@ func NewSimpleSQLOutboxRepositoryWithCodecAndConfig[ID comparable](
 		return nil, errors.NewCode(errors.InvalidInput, "codec cannot be nil")
 	}
 	cfg = normalizeOutboxConfig(cfg)
+	tenantColumn := strings.TrimSpace(cfg.TenantColumn)
+	if tenantColumn != "" && !safeident.IsSafeIdentifier(tenantColumn) {
+		return nil, errors.NewCode(errors.InvalidInput, fmt.Sprintf("invalid outbox tenant column: %s", tenantColumn))
+	}
 	return &SimpleSQLOutboxRepository[ID]{
-		db:          db,
-		eventStore:  eventStore,
-		tableName:   "event_store",
-		outboxTable: "event_outbox",
-		logger:      logger,
-		codec:       idCodec,
-		claimLease:  cfg.ClaimLease,
+		db:           db,
+		eventStore:   eventStore,
+		tableName:    "event_store",
+		outboxTable:  "event_outbox",
+		logger:       logger,
+		codec:        idCodec,
+		claimLease:   cfg.ClaimLease,
+		clock:        cfg.Clock,
+		tenantColumn: tenantColumn,
 	}, nil
 }
 
+func (r *SimpleSQLOutboxRepository[ID]) now() time.Time {
+	if r.clock == nil {
+		return time.Now()
+	}
+	return r.clock.Now()
+}
+
+// GetClock 返回仓储使用的时钟（未注入时为真实时钟）。
+func (r *SimpleSQLOutboxRepository[ID]) GetClock() clock.IClock {
+	if r == nil || r.clock == nil {
+		return clock.NewRealClock()
+	}
+	return r.clock
+}
+
 // GetClaimLease 返回仓储实际用于 claim/renew 的租约时长。
 func (r *SimpleSQLOutboxRepository[ID]) GetClaimLease() time.Duration {
 	if r == nil || r.claimLease <= 0 {
@@ -161,6 +189,29 @@ func (r *SimpleSQLOutboxRepository[ID]) SaveWithEvents(ctx context.Context, aggr
 	return nil
 }
 
+// StageEvents 在调用方事务内写入 Outbox 记录（实现 bus.ITxEventStager）。
+//
+// 事件须为 eventing.IStorableEvent[ID]（通常是 *eventing.Event[ID]）；记录随事务提交后才会被 publisher claim。
+// 事件流本身不在此写入，需要时由调用方在同一事务内通过 AppendEventsWithDB 追加。
+func (r *SimpleSQLOutboxRepository[ID]) StageEvents(ctx context.Context, tx db.ITransaction, events []eventing.IEvent) error {
+	if tx == nil {
+		return errors.NewCode(errors.InvalidInput, "transaction is nil")
+	}
+	for i, evt := range events {
+		storable, ok := evt.(eventing.IStorableEvent[ID])
+		if !ok {
+			return errors.NewCode(errors.InvalidInput, "event is not storable with the repository aggregate id type").
+				WithContext("index", i).
+				WithContext("event_type", fmt.Sprintf("%T", evt))
+		}
+		if err := r.saveOutboxEntries(ctx, tx, storable.GetAggregateID(), []eventing.IStorableEvent[ID]{storable}); err != nil {
+			r.logger.Warn(ctx, "failed to stage outbox entry", logging.String("event_id", storable.GetID()), logging.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
 // saveOutboxEntries 把事件批次逐条写入 Outbox 表。
 func (r *SimpleSQLOutboxRepository[ID]) saveOutboxEntries(ctx context.Context, tx db.ITransaction, aggregateID ID, events []eventing.IStorableEvent[ID]) error {
 	agg, err := r.codec.Encode(aggregateID)
@@ -180,19 +231,26 @@ func (r *SimpleSQLOutboxRepository[ID]) saveOutboxEntries(ctx context.Context, t
 				WithContext("event_id", event.GetID())
 		}
 
+		columns := []string{"aggregate_id", "aggregate_type", "event_id", "event_type", "event_data", "status", "claim_token", "created_at", "retry_count"}
+		values := []any{
+			agg,
+			event.GetAggregateType(),
+			event.GetID(),
+			event.GetType(),
+			eventData,
+			OutboxStatusPending,
+			"",
+			r.now(),
+			0,
+		}
+		if r.tenantColumn != "" {
+			columns = append(columns, r.tenantColumn)
+			values = append(values, eventTenantID(ctx, event))
+		}
 		_, err = sq.InsertInto(r.outboxTable).
-			Columns("aggregate_id", "aggregate_type", "event_id", "event_type", "event_data", "status", "claim_token", "created_at", "retry_count").
-			Values(
-				agg,
-				event.GetAggregateType(),
-				event.GetID(),
-				event.GetType(),
-				eventData,
-				OutboxStatusPending,
-				"",
-				time.Now(),
-				0,
-			).Exec(ctx)
+			Columns(columns...).
+			Values(values...).
+			Exec(ctx)
 		if err != nil {
 			return errors.Wrap(err, errors.Database, "insert outbox entry failed")
 		}
@@ -201,6 +259,14 @@ func (r *SimpleSQLOutboxRepository[ID]) saveOutboxEntries(ctx context.Context, t
 	return nil
 }
 
+// eventTenantID 返回事件归属租户：优先事件 metadata，其次 ctx。
+func eventTenantID[ID comparable](ctx context.Context, event eventing.IStorableEvent[ID]) string {
+	if v, ok := event.GetMetadata().Get(contextx.MetadataTenantKey); ok && strings.TrimSpace(v) != "" {
+		return strings.TrimSpace(v)
+	}
+	return strings.TrimSpace(contextx.TenantID(ctx))
+}
+
 // ClaimPendingEntries 原子 claim 当前可发布或到期可重试的 Outbox 记录。
 func (r *SimpleSQLOutboxRepository[ID]) ClaimPendingEntries(ctx context.Context, limit int) ([]OutboxEntry[ID], error) {
 	if limit <= 0 {
@@ -223,7 +289,7 @@ func (r *SimpleSQLOutboxRepository[ID]) ClaimPendingEntries(ctx context.Context,
 		return nil, errors.Wrap(err, errors.Internal, "failed to create sql builder")
 	}
 
-	now := time.Now()
+	now := r.now()
 	claimToken, err := newClaimToken()
 	if err != nil {
 		return nil, err
@@ -340,7 +406,7 @@ func (r *SimpleSQLOutboxRepository[ID]) MarkAsPublished(ctx context.Context, ent
 		Set("status", OutboxStatusPublished).
 		Set("claim_token", "").
 		Set("lease_until", nil).
-		Set("published_at", time.Now()).
+		Set("published_at", r.now()).
 		Set("next_retry_at", nil).
 		Where("id = ?", entryID).
 		Where("claim_token = ?", claimToken).
@@ -388,6 +454,35 @@ func (r *SimpleSQLOutboxRepository[ID]) MarkAsFailed(ctx context.Context, entryI
 	return nil
 }
 
+// ReleaseClaim 释放 claim 并安排下次发布时间；与 MarkAsFailed 不同，不增加 retry_count。
+func (r *SimpleSQLOutboxRepository[ID]) ReleaseClaim(ctx context.Context, entryID int64, claimToken string, reason string, nextRetryAt time.Time) error {
+	sq, err := sqlbuilder.New(r.db)
+	if err != nil {
+		return errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+
+	result, err := sq.Update(r.outboxTable).
+		Set("status", OutboxStatusFailed).
+		Set("claim_token", "").
+		Set("lease_until", nil).
+		Set("last_error", reason).
+		Set("next_retry_at", nextRetryAt).
+		Where("id = ?", entryID).
+		Where("claim_token = ?", claimToken).
+		Where("status = ?", OutboxStatusProcessing).
+		Exec(ctx)
+	if err != nil {
+		r.logger.Warn(ctx, "failed to release outbox claim", logging.Int64("entry_id", entryID), logging.Error(err))
+		return errors.Wrap(err, errors.Database, "release outbox claim failed").
+			WithContext("entry_id", entryID)
+	}
+
+	if err := ensureRowsAffected(result, 1, "outbox entry claim is no longer owned"); err != nil {
+		return err.WithContext("entry_id", entryID)
+	}
+	return nil
+}
+
 // RenewClaim 延长指定 claim 的 lease，避免长时间处理过程中被其他 worker 重新 claim。
 func (r *SimpleSQLOutboxRepository[ID]) RenewClaim(ctx context.Context, entryID int64, claimToken string) error {
 	sq, err := sqlbuilder.New(r.db)
@@ -395,7 +490,7 @@ func (r *SimpleSQLOutboxRepository[ID]) RenewClaim(ctx context.Context, entryID
 		return errors.Wrap(err, errors.Internal, "failed to create sql builder")
 	}
 
-	leaseUntil := time.Now().Add(r.claimLease)
+	leaseUntil := r.now().Add(r.claimLease)
 	result, err := sq.Update(r.outboxTable).
 		Set("lease_until", leaseUntil).
 		Where("id = ?", entryID).
@@ -437,6 +532,38 @@ func (r *SimpleSQLOutboxRepository[ID]) DeletePublished(ctx context.Context, old
 	return nil
 }
 
+// DeleteAggregateEntries 删除指定聚合的全部 Outbox 记录（不区分状态），用于聚合硬删除时的级联清理。
+//
+// 可通过 store.AggregateDataCleanerFunc 适配为 store.IAggregateDataCleaner 传给 store.DeleteAggregate。
+func (r *SimpleSQLOutboxRepository[ID]) DeleteAggregateEntries(ctx context.Context, aggregateType string, aggregateID ID) error {
+	agg, err := r.codec.Encode(aggregateID)
+	if err != nil {
+		return errors.Wrap(err, errors.InvalidInput, "invalid aggregate id")
+	}
+	sq, err := sqlbuilder.New(r.db)
+	if err != nil {
+		return errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+
+	result, err := sq.DeleteFrom(r.outboxTable).
+		Where("aggregate_type = ?", aggregateType).
+		Where("aggregate_id = ?", agg).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, errors.Database, "delete aggregate outbox entries failed").
+			WithContext("aggregate_type", aggregateType).
+			WithContext("aggregate_id", aggregateID)
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected > 0 {
+		r.logger.Info(ctx, "deleted aggregate outbox entries",
+			logging.String("aggregate_type", aggregateType),
+			logging.Any("aggregate_id", aggregateID),
+			logging.Int64("deleted", rowsAffected))
+	}
+	return nil
+}
+
 // calculateExpectedVersion 根据事件批次首条事件的版本推导 expectedVersion。
 func (r *SimpleSQLOutboxRepository[ID]) calculateExpectedVersion(events []eventing.Event[ID]) uint64 {
 	if len(events) == 0 {
@@ -447,6 +574,11 @@ func (r *SimpleSQLOutboxRepository[ID]) calculateExpectedVersion(events []eventi
 
 // serializeEvent 把事件编码为存入 Outbox 的 JSON 文本。
 func (r *SimpleSQLOutboxRepository[ID]) serializeEvent(event eventing.IStorableEvent[ID]) (string, error) {
+	return serializeOutboxEvent(event)
+}
+
+// serializeOutboxEvent 把事件编码为 Outbox 事件信封 JSON（轮询与 CDC 两种仓储共用）。
+func serializeOutboxEvent[ID comparable](event eventing.IStorableEvent[ID]) (string, error) {
 	data := map[string]any{
 		"id":             event.GetID(),
 		"type":           event.GetType(),
@@ -506,3 +638,5 @@ func newClaimToken() (string, error) {
 	}
 	return fmt.Sprintf("%x", raw[:]), nil
 }
+
+var _ bus.ITxEventStager = (*SimpleSQLOutboxRepository[int64])(nil)
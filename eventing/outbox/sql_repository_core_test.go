@@ -8,8 +8,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"gochen/contextx"
 	"gochen/errors"
 	"gochen/eventing"
+	"gochen/eventing/bus"
 	"gochen/logging"
 )
 
@@ -53,6 +55,38 @@ func TestSQLOutboxRepository_SaveWithEvents(t *testing.T) {
 	assert.NotEmpty(t, entry1.EventData)
 }
 
+// TestSQLOutboxRepository_StageEvents 验证暂存的记录随调用方事务提交才可被 claim，回滚时丢弃。
+func TestSQLOutboxRepository_StageEvents(t *testing.T) {
+	database := setupTestDB(t)
+	repo, err := NewSimpleSQLOutboxRepository(database, &MockEventStoreWithDB{}, logging.NewNoopLogger())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	rolledBack := newTestEvent(1, 1, "event-rolled-back", nil)
+	tx, err := database.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, bus.PublishInTx(ctx, tx, repo, &rolledBack))
+	require.NoError(t, tx.Rollback())
+
+	first, second := newTestEvent(1, 2, "event-1", nil), newTestEvent(2, 1, "event-2", nil)
+	tx, err = database.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, bus.PublishInTx(ctx, tx, repo, &first, &second))
+	require.NoError(t, tx.Commit())
+
+	entries, err := repo.ClaimPendingEntries(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "event-1", entries[0].EventID)
+	assert.Equal(t, int64(2), entries[1].AggregateID)
+
+	tx, err = database.Begin(ctx)
+	require.NoError(t, err)
+	defer func() { _ = tx.Rollback() }()
+	err = repo.StageEvents(ctx, tx, []eventing.IEvent{eventing.NewEvent[string]("agg", "TestAggregate", "TestEvent", 1, nil)})
+	assert.True(t, errors.Is(err, errors.InvalidInput))
+}
+
 // TestSQLOutboxRepository_ClaimPendingEntries 验证 SQLOutboxRepository ClaimPendingEntries。
 func TestSQLOutboxRepository_ClaimPendingEntries(t *testing.T) {
 	database := setupTestDB(t)
@@ -297,6 +331,37 @@ func TestSQLOutboxRepository_MarkAsFailed(t *testing.T) {
 	assert.Nil(t, entry.NextRetryAt)
 }
 
+// TestSQLOutboxRepository_ReleaseClaim 验证释放 claim 会安排下次发布时间但不增加重试次数。
+func TestSQLOutboxRepository_ReleaseClaim(t *testing.T) {
+	database := setupTestDB(t)
+	repo, err := NewSimpleSQLOutboxRepository(database, &MockEventStoreWithDB{}, logging.NewNoopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, repo.SaveWithEvents(ctx, 1, []eventing.Event[int64]{
+		newTestEvent(1, 1, "event-1", nil),
+	}))
+	entries, err := repo.ClaimPendingEntries(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	require.NoError(t, repo.ReleaseClaim(ctx, entries[0].ID, entries[0].ClaimToken, "deferred: earlier entry failed", time.Now().Add(time.Minute)))
+	require.Error(t, repo.ReleaseClaim(ctx, entries[0].ID, entries[0].ClaimToken, "again", time.Now()))
+
+	row := database.QueryRow(ctx, `SELECT status, claim_token, last_error, retry_count FROM event_outbox WHERE id = ?`, entries[0].ID)
+	var status, claimToken, lastError string
+	var retryCount int
+	require.NoError(t, row.Scan(&status, &claimToken, &lastError, &retryCount))
+	assert.Equal(t, string(OutboxStatusFailed), status)
+	assert.Empty(t, claimToken)
+	assert.Equal(t, "deferred: earlier entry failed", lastError)
+	assert.Zero(t, retryCount)
+
+	entries, err = repo.ClaimPendingEntries(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "released entry must wait for its next retry time")
+}
+
 func TestSQLOutboxRepository_RenewClaim(t *testing.T) {
 	database := setupTestDB(t)
 	eventStore := &MockEventStoreWithDB{}
@@ -432,3 +497,34 @@ func TestSQLOutboxRepository_DeletePublished(t *testing.T) {
 	}
 	assert.Equal(t, int64(0), count)
 }
+
+// TestSQLOutboxRepository_SaveWithEvents_WritesTenantColumn 验证配置租户列后写入事件租户。
+func TestSQLOutboxRepository_SaveWithEvents_WritesTenantColumn(t *testing.T) {
+	database := setupTestDB(t)
+	_, err := database.Exec(context.Background(), `ALTER TABLE event_outbox ADD COLUMN tenant_id TEXT NOT NULL DEFAULT ''`)
+	require.NoError(t, err)
+
+	cfg := DefaultOutboxConfig()
+	cfg.TenantColumn = "tenant_id"
+	repo, err := NewSimpleSQLOutboxRepositoryWithConfig(database, &MockEventStoreWithDB{}, logging.NewNoopLogger(), cfg)
+	require.NoError(t, err)
+
+	ctx, err := contextx.WithTenantID(context.Background(), "acme")
+	require.NoError(t, err)
+	fromMetadata := newTestEvent(1, 2, "event-2", nil)
+	fromMetadata.GetMetadata().Set(contextx.MetadataTenantKey, "globex")
+	require.NoError(t, repo.SaveWithEvents(ctx, 1, []eventing.Event[int64]{
+		newTestEvent(1, 1, "event-1", nil),
+		fromMetadata,
+	}))
+
+	for eventID, want := range map[string]string{"event-1": "acme", "event-2": "globex"} {
+		var tenantID string
+		require.NoError(t, database.QueryRow(context.Background(), `SELECT tenant_id FROM event_outbox WHERE event_id = ?`, eventID).Scan(&tenantID))
+		assert.Equal(t, want, tenantID, eventID)
+	}
+
+	cfg.TenantColumn = "tenant-id"
+	_, err = NewSimpleSQLOutboxRepositoryWithConfig(database, &MockEventStoreWithDB{}, logging.NewNoopLogger(), cfg)
+	require.True(t, errors.Is(err, errors.InvalidInput))
+}
@@ -2,12 +2,19 @@ package projection
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"gochen/clock"
+	"gochen/contextx"
 	gerrors "gochen/errors"
 	"gochen/eventing"
+	"gochen/eventing/monitoring"
 	"gochen/eventing/upcast"
 	"gochen/logging"
+	"gochen/messaging"
+	"gochen/policy/retry"
 )
 
 type applyEventCommonOptions struct {
@@ -15,6 +22,8 @@ type applyEventCommonOptions struct {
 	enableRetry             bool
 	allowCheckpoint         bool
 	clearLastErrorOnSuccess bool
+	deadLetter              bool // 失败时写入死信队列并跳过该事件（需配置死信存储）
+	redeliver               bool // 重放死信：不推进游标与处理计数
 	upgradeLogMessage       string
 }
 
@@ -23,6 +32,7 @@ type applyEventCommonResult struct {
 	handleDuration  time.Duration
 	processedEvents int64
 	failedEvents    int64
+	deadLettered    bool
 }
 
 // applyEventCommon 应用事件Common。
@@ -54,13 +64,21 @@ func (pm *ProjectionManager[ID]) applyEventCommon(
 	}
 
 	if e, ok := evt.(*eventing.Event[ID]); ok && e != nil {
+		// 投影处理器内的日志自动携带 aggregate_id（见 logging.ContextFields）。
+		if derived, derr := contextx.WithAggregateID(ctx, fmt.Sprint(e.GetAggregateID())); derr == nil {
+			ctx = derived
+		}
 		if _, uerr := upcast.UpgradeEventPayload(ctx, reg, upgraders, e); uerr != nil {
 			msg := opts.upgradeLogMessage
 			if msg == "" {
 				msg = "projection event payload upgrade/hydrate failed"
 			}
-			if appErr, ok := uerr.(*gerrors.AppError); ok && appErr != nil {
-				return res, appErr.Wrap(msg).
+			if appErr, ok := gerrors.AsType[*gerrors.AppError](uerr); ok && appErr != nil {
+				wrapped := appErr.Wrap(msg)
+				if error(appErr) != uerr {
+					wrapped = gerrors.Wrap(uerr, appErr.Code(), msg)
+				}
+				return res, wrapped.
 					WithContext("projection", projectionName).
 					WithContext("event_id", e.GetID()).
 					WithContext("event_type", e.GetType())
@@ -70,13 +88,23 @@ func (pm *ProjectionManager[ID]) applyEventCommon(
 	}
 
 	var err error
-	nextCursor := NewCheckpoint(
+	nextCursor := NewCheckpointAt(
+		pm.clock().Now(),
 		projectionName,
 		processedBefore+1,
 		evt.GetID(),
 		evt.GetTimestamp(),
 	)
-	handle := func(handleCtx context.Context) error {
+	// 投影 panic 转换为普通错误：走同样的重试、错误状态、指标与 DeadLetterFunc 流程，不会带崩消费 goroutine。
+	handle := func(handleCtx context.Context) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = messaging.PanicError("projection handler panicked", r).
+					WithContext("projection", projectionName).
+					WithContext("event_id", evt.GetID()).
+					WithContext("event_type", evt.GetType())
+			}
+		}()
 		if saveCheckpointOnSuccess {
 			cpProjection, ok := rt.projection.(ICheckpointingProjection[ID])
 			if !ok {
@@ -88,22 +116,60 @@ func (pm *ProjectionManager[ID]) applyEventCommon(
 		return rt.projection.Handle(handleCtx, evt)
 	}
 
-	handleStart := time.Now()
+	if timeout := pm.handlerTimeout(); timeout > 0 {
+		handleOnce := handle
+		handle = func(handleCtx context.Context) error {
+			err := messaging.RunWithTimeoutGrace(handleCtx, timeout, pm.handlerStopGrace(), handleOnce)
+			if messaging.IsHandlerTimeout(err) {
+				pm.recordHandlerTimeout(handleCtx, projectionName, evt, timeout, err)
+			}
+			if messaging.IsHandlerAbandoned(err) {
+				// 放弃的处理器仍可能在写读模型，继续投递会与其并发：停用该投影，等待人工介入。
+				rt.markError(err)
+			}
+			return err
+		}
+	}
+
+	clk := pm.clock()
+	handleStart := clk.Now()
 	if opts.enableRetry {
 		var aborted bool
 		err, aborted = pm.handleWithRetry(ctx, projectionName, evt, handle)
 		if aborted {
-			res.handleDuration = time.Since(handleStart)
+			res.handleDuration = clk.Now().Sub(handleStart)
+			if messaging.IsHandlerAbandoned(err) {
+				pm.persistStatus(ctx, rt)
+			}
 			return res, err
 		}
 	} else {
 		err = handle(ctx)
 	}
-	res.handleDuration = time.Since(handleStart)
+	res.handleDuration = clk.Now().Sub(handleStart)
+	if opts.redeliver {
+		return res, err
+	}
 
-	recorded := rt.recordApplyResult(evt, err, opts.clearLastErrorOnSuccess, nextCursor, saveCheckpointOnSuccess)
+	shouldPark := err != nil && opts.deadLetter && !messaging.IsHandlerAbandoned(err)
+	if !opts.enableRetry {
+		// 在线投递不在进程内重试：由总线重投，达到重试上限或错误不可重试时才进入死信队列。
+		shouldPark = shouldPark && pm.deliveryExhausted(rt, evt, err)
+		if err == nil {
+			rt.clearFailedDelivery()
+		}
+	}
+	parked := shouldPark && pm.parkFailedEvent(ctx, projectionName, evt, err)
+	if parked && !opts.enableRetry {
+		rt.clearFailedDelivery()
+	}
+	recorded := rt.recordApplyResult(evt, err, opts.clearLastErrorOnSuccess, nextCursor, saveCheckpointOnSuccess, parked)
 	recorded.handleDuration = res.handleDuration
+	recorded.deadLettered = parked
 	res = recorded
+	if err != nil || saveCheckpointOnSuccess {
+		pm.persistStatus(ctx, rt)
+	}
 
 	if err != nil {
 		return res, err
@@ -111,6 +177,21 @@ func (pm *ProjectionManager[ID]) applyEventCommon(
 	return res, nil
 }
 
+// deliveryExhausted 记录在线投递失败并判断事件是否应进入死信队列：
+// 错误显式不可重试（retry.IRetryableError 返回 false），或同一事件已连续失败 1+MaxRetries 次。
+// 调用方需持有 rt.execMu。
+func (pm *ProjectionManager[ID]) deliveryExhausted(rt *projectionRuntime[ID], evt eventing.IEvent, err error) bool {
+	var re retry.IRetryableError
+	if errors.As(err, &re) && !re.IsRetryable() {
+		return true
+	}
+	maxRetries := 0
+	if pm.config != nil && pm.config.MaxRetries > 0 {
+		maxRetries = pm.config.MaxRetries
+	}
+	return rt.recordFailedDelivery(evt.GetID()) > maxRetries
+}
+
 func (pm *ProjectionManager[ID]) handleWithRetry(
 	ctx context.Context,
 	projectionName string,
@@ -127,13 +208,13 @@ func (pm *ProjectionManager[ID]) handleWithRetry(
 		backoff = pm.config.RetryBackoff
 	}
 
-	var backoffTimer *time.Timer
+	var backoffTimer clock.ITimer
 	if backoff > 0 {
 		// Reuse a timer to avoid per-retry allocation from time.After in retry loops.
-		backoffTimer = time.NewTimer(0)
+		backoffTimer = pm.clock().NewTimer(backoff)
 		if !backoffTimer.Stop() {
 			select {
-			case <-backoffTimer.C:
+			case <-backoffTimer.C():
 			default:
 			}
 		}
@@ -145,6 +226,9 @@ func (pm *ProjectionManager[ID]) handleWithRetry(
 		if err == nil {
 			return nil, false
 		}
+		if messaging.IsHandlerAbandoned(err) {
+			return err, true
+		}
 
 		// 已达到最大重试次数（attempt 表示已进行的重试次数）
 		if attempt >= maxRetries {
@@ -161,7 +245,7 @@ func (pm *ProjectionManager[ID]) handleWithRetry(
 		if backoff > 0 {
 			if !backoffTimer.Stop() {
 				select {
-				case <-backoffTimer.C:
+				case <-backoffTimer.C():
 				default:
 				}
 			}
@@ -169,8 +253,46 @@ func (pm *ProjectionManager[ID]) handleWithRetry(
 			select {
 			case <-ctx.Done():
 				return ctx.Err(), true
-			case <-backoffTimer.C:
+			case <-backoffTimer.C():
 			}
 		}
 	}
 }
+
+// handlerTimeout 返回配置的单次处理截止时间。
+// clock 返回配置的时钟；配置缺失时回退为真实时钟。
+func (pm *ProjectionManager[ID]) clock() clock.IClock {
+	if pm == nil || pm.config == nil || pm.config.Clock == nil {
+		return clock.NewRealClock()
+	}
+	return pm.config.Clock
+}
+
+func (pm *ProjectionManager[ID]) handlerTimeout() time.Duration {
+	if pm == nil || pm.config == nil {
+		return 0
+	}
+	return pm.config.HandlerTimeout
+}
+
+// handlerStopGrace 返回超时后等待处理器退出的宽限期。
+func (pm *ProjectionManager[ID]) handlerStopGrace() time.Duration {
+	if pm == nil || pm.config == nil || pm.config.HandlerStopGrace <= 0 {
+		return messaging.DefaultHandlerStopGrace
+	}
+	return pm.config.HandlerStopGrace
+}
+
+// recordHandlerTimeout 记录超时的投影处理器（含调用栈采样）并上报超时指标。
+func (pm *ProjectionManager[ID]) recordHandlerTimeout(ctx context.Context, projectionName string, evt eventing.IEvent, timeout time.Duration, err error) {
+	if rec, ok := pm.getMetrics().(monitoring.IProjectionTimeoutRecorder); ok {
+		rec.RecordProjectionTimeout()
+	}
+	projectionLogger().Error(ctx, "projection handler timed out",
+		logging.Error(err),
+		logging.String("projection", projectionName),
+		logging.String("event_id", evt.GetID()),
+		logging.String("event_type", evt.GetType()),
+		logging.Duration("timeout", timeout),
+	)
+}
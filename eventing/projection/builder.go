@@ -0,0 +1,196 @@
+package projection
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+
+	"gochen/domain"
+	gerrors "gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/bus"
+	"gochen/messaging"
+)
+
+// EventHandler 是声明式投影中的单个类型化事件处理器，由 Handler 创建。
+type EventHandler struct {
+	eventType string
+	handle    func(ctx context.Context, evt eventing.IEvent) error
+	err       error
+}
+
+// Handler 把类型化处理函数包装为 EventHandler：事件类型取 T.EventType()（T 为指针类型时在零值对象上调用），
+// 处理时载荷按 T 断言或解码，解码失败返回 errors.InvalidInput。
+//
+// Go 方法不能声明类型参数，因此类型推断放在该函数上，再交给 Builder.On 登记。
+func Handler[T domain.IDomainEvent](fn func(ctx context.Context, e T) error) EventHandler {
+	if fn == nil {
+		return HandlerWithEvent[T](nil)
+	}
+	return HandlerWithEvent(func(ctx context.Context, _ eventing.IEvent, e T) error {
+		return fn(ctx, e)
+	})
+}
+
+// HandlerWithEvent 与 Handler 相同，但处理函数同时收到事件信封（聚合 ID、版本、事件 ID 等）。
+func HandlerWithEvent[T domain.IDomainEvent](fn func(ctx context.Context, evt eventing.IEvent, e T) error) EventHandler {
+	eventType, err := bus.DomainEventType[T]()
+	if err != nil {
+		return EventHandler{err: err}
+	}
+	if fn == nil {
+		return EventHandler{eventType: eventType, err: gerrors.NewCode(gerrors.InvalidInput, "projection handler func is nil").
+			WithContext("event_type", eventType)}
+	}
+	return EventHandler{
+		eventType: eventType,
+		handle: func(ctx context.Context, evt eventing.IEvent) error {
+			payload, ok := messaging.PayloadAs[T](evt.GetPayload())
+			if !ok {
+				return gerrors.NewCode(gerrors.InvalidInput, "invalid payload type").
+					WithContext("event_type", evt.GetType()).
+					WithContext("expected_payload_type", reflect.TypeFor[T]().String()).
+					WithContext("actual_payload_type", evt.GetPayload().TypeName())
+			}
+			return fn(ctx, evt, payload)
+		},
+	}
+}
+
+// Builder 以声明方式构造投影，免去手写 IProjection 的五个方法。
+//
+// 示例：
+//
+//	p, err := projection.New[int64]("order_summary").
+//		On(projection.Handler(func(ctx context.Context, e *OrderCreated) error { ... })).
+//		OnRebuildReset(func(ctx context.Context) error { return truncate(ctx) }).
+//		Build()
+type Builder[ID comparable] struct {
+	name     string
+	types    []string
+	handlers map[string]func(ctx context.Context, evt eventing.IEvent) error
+	reset    func(ctx context.Context) error
+	err      error
+}
+
+// New 创建声明式投影构造器。
+func New[ID comparable](name string) *Builder[ID] {
+	return &Builder[ID]{
+		name:     name,
+		handlers: make(map[string]func(ctx context.Context, evt eventing.IEvent) error),
+	}
+}
+
+// On 登记事件处理器；支持的事件类型按登记顺序汇总。同一事件类型重复登记时 Build 返回 Conflict。
+func (b *Builder[ID]) On(handlers ...EventHandler) *Builder[ID] {
+	for _, h := range handlers {
+		if b.err != nil {
+			return b
+		}
+		if h.err != nil {
+			b.err = h.err
+			return b
+		}
+		if h.handle == nil {
+			b.err = gerrors.NewCode(gerrors.InvalidInput, "projection handler is empty, use projection.Handler to create it")
+			return b
+		}
+		if _, exists := b.handlers[h.eventType]; exists {
+			b.err = gerrors.NewCode(gerrors.Conflict, "projection handler already registered for event type").
+				WithContext("event_type", h.eventType)
+			return b
+		}
+		b.handlers[h.eventType] = h.handle
+		b.types = append(b.types, h.eventType)
+	}
+	return b
+}
+
+// OnRebuildReset 设置重建前清空读模型的钩子；未设置时重建直接在现有读模型上重放事件（处理函数需幂等）。
+func (b *Builder[ID]) OnRebuildReset(fn func(ctx context.Context) error) *Builder[ID] {
+	b.reset = fn
+	return b
+}
+
+// Build 校验声明并返回投影。
+func (b *Builder[ID]) Build() (IProjection[ID], error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.name == "" {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "projection name cannot be empty")
+	}
+	if len(b.handlers) == 0 {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "projection has no event handlers").
+			WithContext("projection", b.name)
+	}
+	handlers := make(map[string]func(ctx context.Context, evt eventing.IEvent) error, len(b.handlers))
+	for eventType, handle := range b.handlers {
+		handlers[eventType] = handle
+	}
+	return &declarativeProjection[ID]{
+		name:     b.name,
+		types:    append([]string(nil), b.types...),
+		handlers: handlers,
+		reset:    b.reset,
+	}, nil
+}
+
+// declarativeProjection 是 Builder 构造的投影。
+type declarativeProjection[ID comparable] struct {
+	name      string
+	types     []string
+	handlers  map[string]func(ctx context.Context, evt eventing.IEvent) error
+	reset     func(ctx context.Context) error
+	processed atomic.Int64
+	failed    atomic.Int64
+}
+
+func (p *declarativeProjection[ID]) Name() string { return p.name }
+
+func (p *declarativeProjection[ID]) SupportedEventTypes() []string {
+	return append([]string(nil), p.types...)
+}
+
+// Handle 按事件类型分发；未声明的事件类型直接忽略。
+func (p *declarativeProjection[ID]) Handle(ctx context.Context, evt eventing.IEvent) error {
+	if evt == nil {
+		return gerrors.NewCode(gerrors.InvalidInput, "evt is nil")
+	}
+	handle, ok := p.handlers[evt.GetType()]
+	if !ok {
+		return nil
+	}
+	if err := handle(ctx, evt); err != nil {
+		p.failed.Add(1)
+		return err
+	}
+	p.processed.Add(1)
+	return nil
+}
+
+// Rebuild 先执行重建钩子，再按顺序重放事件。
+func (p *declarativeProjection[ID]) Rebuild(ctx context.Context, events []eventing.Event[ID]) error {
+	if p.reset != nil {
+		if err := p.reset(ctx); err != nil {
+			return gerrors.Wrap(err, gerrors.Internal, "projection rebuild reset failed").
+				WithContext("projection", p.name)
+		}
+	}
+	p.processed.Store(0)
+	p.failed.Store(0)
+	for i := range events {
+		if err := p.Handle(ctx, &events[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *declarativeProjection[ID]) Status() ProjectionStatus {
+	return ProjectionStatus{
+		Name:            p.name,
+		ProcessedEvents: p.processed.Load(),
+		FailedEvents:    p.failed.Load(),
+	}
+}
@@ -0,0 +1,85 @@
+package projection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gerrors "gochen/errors"
+	"gochen/eventing"
+)
+
+type builderOrderCreated struct{ Total int }
+
+func (*builderOrderCreated) EventType() string { return "OrderCreated" }
+
+type builderOrderCancelled struct{ Reason string }
+
+func (*builderOrderCancelled) EventType() string { return "OrderCancelled" }
+
+func TestBuilder_DispatchesTypedHandlers(t *testing.T) {
+	ctx := context.Background()
+	var total, cancelled, resets int
+
+	p, err := New[int64]("order_summary").
+		On(Handler(func(_ context.Context, e *builderOrderCreated) error {
+			total += e.Total
+			return nil
+		})).
+		On(Handler(func(_ context.Context, e *builderOrderCancelled) error {
+			cancelled++
+			return nil
+		})).
+		OnRebuildReset(func(context.Context) error {
+			resets++
+			total, cancelled = 0, 0
+			return nil
+		}).
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, "order_summary", p.Name())
+	assert.Equal(t, []string{"OrderCreated", "OrderCancelled"}, p.SupportedEventTypes())
+
+	created := eventing.NewEvent[int64](1, "Order", "OrderCreated", 1, &builderOrderCreated{Total: 7})
+	require.NoError(t, p.Handle(ctx, created))
+	// 跨进程传输后载荷为 map，同样按处理函数的参数类型解码。
+	require.NoError(t, p.Handle(ctx, eventing.NewEvent[int64](2, "Order", "OrderCreated", 1, map[string]any{"Total": 3})))
+	require.NoError(t, p.Handle(ctx, eventing.NewEvent[int64](1, "Order", "OrderShipped", 2, nil)), "undeclared event types are ignored")
+	assert.Equal(t, 10, total)
+	assert.Equal(t, int64(2), p.Status().ProcessedEvents)
+
+	err = p.Handle(ctx, eventing.NewEvent[int64](1, "Order", "OrderCancelled", 2, "not a struct"))
+	assert.True(t, gerrors.Is(err, gerrors.InvalidInput))
+	assert.Equal(t, int64(1), p.Status().FailedEvents)
+
+	events := []eventing.Event[int64]{
+		*created,
+		*eventing.NewEvent[int64](1, "Order", "OrderCancelled", 2, &builderOrderCancelled{Reason: "late"}),
+	}
+	require.NoError(t, p.Rebuild(ctx, events))
+	assert.Equal(t, 1, resets)
+	assert.Equal(t, 7, total)
+	assert.Equal(t, 1, cancelled)
+	assert.Equal(t, int64(2), p.Status().ProcessedEvents)
+}
+
+func TestBuilder_Validation(t *testing.T) {
+	noop := Handler(func(context.Context, *builderOrderCreated) error { return nil })
+
+	_, err := New[int64]("").On(noop).Build()
+	assert.True(t, gerrors.Is(err, gerrors.InvalidInput))
+
+	_, err = New[int64]("empty").Build()
+	assert.True(t, gerrors.Is(err, gerrors.InvalidInput))
+
+	_, err = New[int64]("dup").On(noop, noop).Build()
+	assert.True(t, gerrors.Is(err, gerrors.Conflict))
+
+	_, err = New[int64]("nil_func").On(Handler[*builderOrderCreated](nil)).Build()
+	assert.True(t, gerrors.Is(err, gerrors.InvalidInput))
+
+	_, err = New[int64]("zero").On(EventHandler{}).Build()
+	assert.True(t, gerrors.Is(err, gerrors.InvalidInput))
+}
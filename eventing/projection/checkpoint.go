@@ -97,14 +97,19 @@ type ICheckpointTxSessionRequirer interface {
 	RequiresORMTxSession() bool
 }
 
-// NewCheckpoint 创建Checkpoint。
+// NewCheckpoint 创建Checkpoint（UpdatedAt 取真实时钟）。
 func NewCheckpoint(projectionName string, position int64, lastEventID string, lastEventTime time.Time) *Checkpoint {
+	return NewCheckpointAt(time.Now(), projectionName, position, lastEventID, lastEventTime)
+}
+
+// NewCheckpointAt 以 now 作为 UpdatedAt 创建Checkpoint。
+func NewCheckpointAt(now time.Time, projectionName string, position int64, lastEventID string, lastEventTime time.Time) *Checkpoint {
 	return &Checkpoint{
 		ProjectionName: projectionName,
 		Position:       position,
 		LastEventID:    lastEventID,
 		LastEventTime:  lastEventTime,
-		UpdatedAt:      time.Now(),
+		UpdatedAt:      now,
 	}
 }
 
@@ -124,10 +129,15 @@ func (c *Checkpoint) Clone() *Checkpoint {
 	}
 }
 
-// Update 更新检查点位置。
+// Update 更新检查点位置（UpdatedAt 取真实时钟）。
 func (c *Checkpoint) Update(position int64, eventID string, eventTime time.Time) {
+	c.UpdateAt(time.Now(), position, eventID, eventTime)
+}
+
+// UpdateAt 更新检查点位置，并以 now 作为 UpdatedAt。
+func (c *Checkpoint) UpdateAt(now time.Time, position int64, eventID string, eventTime time.Time) {
 	c.Position = position
 	c.LastEventID = eventID
 	c.LastEventTime = eventTime
-	c.UpdatedAt = time.Now()
+	c.UpdatedAt = now
 }
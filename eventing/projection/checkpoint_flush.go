@@ -0,0 +1,157 @@
+package projection
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"gochen/clock"
+	gerrors "gochen/errors"
+	"gochen/logging"
+)
+
+// checkpointFlusher 保存后台检查点刷新循环的生命周期状态。
+type checkpointFlusher struct {
+	mu      sync.Mutex
+	started bool
+	stopped bool
+	cancel  context.CancelFunc
+	doneCh  chan struct{}
+}
+
+// StartCheckpointFlusher 启动后台检查点刷新循环。
+//
+// 说明：
+//   - 按 CheckpointSaveCount/CheckpointSaveInterval 批量保存时，检查点只在处理下一个事件时才会判断是否到期；
+//     事件流停顿后，最后一批进度会一直停留在内存中。刷新循环每隔 CheckpointSaveInterval 把到期未保存的游标写入检查点存储；
+//   - 刷新只写入已经成功处理的位置：进程崩溃时最多回放最近一批事件（at-least-once），投影处理需保持幂等；
+//   - 要求已配置检查点存储且 CheckpointSaveInterval > 0；Shutdown 之后不可再次启动。
+func (pm *ProjectionManager[ID]) StartCheckpointFlusher(ctx context.Context) error {
+	if ctx == nil {
+		return gerrors.NewCode(gerrors.InvalidInput, "ctx is nil")
+	}
+	if !pm.hasCheckpointStore() {
+		return gerrors.NewCode(gerrors.InvalidInput, "checkpoint flusher requires a checkpoint store")
+	}
+	ticker, err := pm.clock().NewTicker(pm.config.CheckpointSaveInterval)
+	if err != nil {
+		return gerrors.Wrap(err, gerrors.InvalidInput, "checkpoint flusher requires a positive checkpoint save interval")
+	}
+
+	f := &pm.flusher
+	f.mu.Lock()
+	if f.stopped {
+		f.mu.Unlock()
+		ticker.Stop()
+		return gerrors.NewCode(gerrors.InvalidInput, "projection manager has been shut down")
+	}
+	if f.started {
+		f.mu.Unlock()
+		ticker.Stop()
+		return nil
+	}
+	f.started = true
+	runCtx, cancel := context.WithCancel(ctx)
+	f.cancel = cancel
+	f.doneCh = make(chan struct{})
+	f.mu.Unlock()
+
+	go pm.flushLoop(runCtx, ticker, f.doneCh)
+	return nil
+}
+
+func (pm *ProjectionManager[ID]) flushLoop(ctx context.Context, ticker clock.ITicker, doneCh chan struct{}) {
+	defer func() {
+		ticker.Stop()
+		close(doneCh)
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			if err := pm.flushCheckpoints(ctx, false); err != nil {
+				pm.logger.Warn(ctx, "projection checkpoint flush failed", logging.Error(err))
+			}
+		}
+	}
+}
+
+// FlushCheckpoints 立即保存所有投影尚未持久化的检查点游标；未配置检查点存储时直接返回。
+func (pm *ProjectionManager[ID]) FlushCheckpoints(ctx context.Context) error {
+	if ctx == nil {
+		return gerrors.NewCode(gerrors.InvalidInput, "ctx is nil")
+	}
+	return pm.flushCheckpoints(ctx, true)
+}
+
+// Shutdown 停止后台检查点刷新循环，并刷新所有投影尚未持久化的检查点。
+//
+// 适合挂在 host 的 ShutdownPhaseProjections 阶段，避免正常退出后重启时回放最近一批事件。
+func (pm *ProjectionManager[ID]) Shutdown(ctx context.Context) error {
+	if ctx == nil {
+		return gerrors.NewCode(gerrors.InvalidInput, "ctx is nil")
+	}
+	f := &pm.flusher
+	f.mu.Lock()
+	f.stopped = true
+	cancel, doneCh := f.cancel, f.doneCh
+	f.cancel = nil
+	f.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		select {
+		case <-doneCh:
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return gerrors.NewCode(gerrors.Timeout, "checkpoint flusher stop timeout")
+			}
+			return ctx.Err()
+		}
+	}
+	return pm.flushCheckpoints(ctx, true)
+}
+
+// flushCheckpoints 保存有未持久化进度的投影检查点；force 为 false 时只保存距上次保存已超过 CheckpointSaveInterval 的投影。
+func (pm *ProjectionManager[ID]) flushCheckpoints(ctx context.Context, force bool) error {
+	pm.mutex.RLock()
+	checkpointStore := pm.checkpointStore
+	runtimes := make([]*projectionRuntime[ID], 0, len(pm.runtimes))
+	for _, rt := range pm.runtimes {
+		runtimes = append(runtimes, rt)
+	}
+	pm.mutex.RUnlock()
+	if checkpointStore == nil {
+		return nil
+	}
+	sort.Slice(runtimes, func(i, j int) bool { return runtimes[i].projection.Name() < runtimes[j].projection.Name() })
+
+	var errs []error
+	for _, rt := range runtimes {
+		if err := pm.flushRuntimeCheckpoint(ctx, checkpointStore, rt, force); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return gerrors.Join(errs...)
+}
+
+func (pm *ProjectionManager[ID]) flushRuntimeCheckpoint(ctx context.Context, checkpointStore ICheckpointStore, rt *projectionRuntime[ID], force bool) error {
+	rt.execMu.Lock()
+	defer rt.execMu.Unlock()
+
+	checkpoint := rt.pendingCheckpoint(pm.config, force)
+	if checkpoint == nil {
+		return nil
+	}
+	if err := checkpointStore.Save(ctx, checkpoint); err != nil {
+		return gerrors.Wrap(err, gerrors.Database, "failed to flush checkpoint").
+			WithContext("projection", checkpoint.ProjectionName)
+	}
+	rt.markCheckpointSaved()
+	pm.persistStatus(ctx, rt)
+	pm.logger.Debug(ctx, "projection checkpoint flushed",
+		logging.String("projection", checkpoint.ProjectionName),
+		logging.Int64("position", checkpoint.Position))
+	return nil
+}
@@ -0,0 +1,81 @@
+package projection
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gochen/clock"
+	gerrors "gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/store"
+	"gochen/eventing/upcast"
+)
+
+func newFlushTestManager(t *testing.T, clk clock.IClock) (*ProjectionManager[int64], *store.MemoryEventStore, *MemoryCheckpointStore) {
+	t.Helper()
+	eventStore := store.NewMemoryEventStore()
+	checkpointStore := NewMemoryCheckpointStore()
+	manager, err := NewProjectionManagerWithConfig[int64](eventStore, &MockEventBus{}, newTestRegistry(t), upcast.NewUpgraderRegistry(), &ProjectionConfig{
+		CheckpointSaveInterval: time.Minute,
+		CheckpointSaveCount:    100,
+		Clock:                  clk,
+	})
+	require.NoError(t, err)
+	manager, err = manager.WithCheckpointStore(checkpointStore)
+	require.NoError(t, err)
+	require.NoError(t, manager.RegisterProjection(NewMockProjection("orders", []string{"TestEvent"})))
+	return manager, eventStore, checkpointStore
+}
+
+func appendTestEvents(t *testing.T, eventStore *store.MemoryEventStore, from, to uint64) {
+	t.Helper()
+	events := make([]eventing.Event[int64], 0, to-from+1)
+	for v := from; v <= to; v++ {
+		events = append(events, *eventing.NewEvent[int64](1, "Agg", "TestEvent", v, map[string]any{"v": v}))
+	}
+	require.NoError(t, eventStore.AppendEvents(context.Background(), 1, toStorableEvents(events), from-1))
+}
+
+// TestShutdown_FlushesPendingCheckpoint 验证批量保存未到阈值的进度在 Shutdown 时写入检查点存储。
+func TestShutdown_FlushesPendingCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	manager, eventStore, checkpointStore := newFlushTestManager(t, clock.NewManualClock(time.Now()))
+	appendTestEvents(t, eventStore, 1, 3)
+
+	require.NoError(t, manager.ResumeFromCheckpoint(ctx, "orders"))
+	_, err := checkpointStore.Load(ctx, "orders")
+	assert.True(t, gerrors.Is(err, gerrors.NotFound), "below both thresholds nothing is saved yet")
+
+	require.NoError(t, manager.Shutdown(ctx))
+	checkpoint, err := checkpointStore.Load(ctx, "orders")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), checkpoint.Position)
+
+	require.NoError(t, manager.FlushCheckpoints(ctx), "flushing without pending progress is a no-op")
+	assert.True(t, gerrors.Is(manager.StartCheckpointFlusher(ctx), gerrors.InvalidInput), "a shut down manager cannot restart the flusher")
+}
+
+// TestStartCheckpointFlusher_FlushesIdleProgress 验证事件流停顿后后台循环按 CheckpointSaveInterval 保存进度。
+func TestStartCheckpointFlusher_FlushesIdleProgress(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewManualClock(time.Now())
+	manager, eventStore, checkpointStore := newFlushTestManager(t, clk)
+	appendTestEvents(t, eventStore, 1, 2)
+
+	require.NoError(t, manager.StartCheckpointFlusher(ctx))
+	require.NoError(t, manager.ResumeFromCheckpoint(ctx, "orders"))
+
+	clk.Advance(time.Minute)
+	require.Eventually(t, func() bool {
+		checkpoint, err := checkpointStore.Load(ctx, "orders")
+		return err == nil && checkpoint.Position == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	stopCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	require.NoError(t, manager.Shutdown(stopCtx))
+}
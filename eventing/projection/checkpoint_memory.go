@@ -13,6 +13,8 @@ import (
 // 仅用于开发和测试环境。
 type MemoryCheckpointStore struct {
 	checkpoints map[string]*Checkpoint
+	versions    map[string]int
+	statuses    map[string]*ProjectionStatus
 	mutex       sync.RWMutex
 }
 
@@ -20,6 +22,8 @@ type MemoryCheckpointStore struct {
 func NewMemoryCheckpointStore() *MemoryCheckpointStore {
 	return &MemoryCheckpointStore{
 		checkpoints: make(map[string]*Checkpoint),
+		versions:    make(map[string]int),
+		statuses:    make(map[string]*ProjectionStatus),
 	}
 }
 
@@ -68,12 +72,68 @@ func (s *MemoryCheckpointStore) Delete(ctx context.Context, projectionName strin
 	return nil
 }
 
+// LoadProjectionVersion 读取投影读模型版本。
+func (s *MemoryCheckpointStore) LoadProjectionVersion(ctx context.Context, projectionName string) (int, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	version, exists := s.versions[projectionName]
+	if !exists {
+		return 0, errors.NewCode(errors.NotFound, "projection version not found").
+			WithContext("projection_name", projectionName)
+	}
+	return version, nil
+}
+
+// SaveProjectionVersion 记录投影读模型版本。
+func (s *MemoryCheckpointStore) SaveProjectionVersion(ctx context.Context, projectionName string, version int) error {
+	if projectionName == "" || version < 1 {
+		return errors.NewCode(errors.InvalidInput, "invalid projection version").
+			WithContext("projection_name", projectionName).
+			WithContext("version", version)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.versions[projectionName] = version
+	return nil
+}
+
+// LoadProjectionStatus 读取投影运行状态。
+func (s *MemoryCheckpointStore) LoadProjectionStatus(ctx context.Context, projectionName string) (*ProjectionStatus, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	status, exists := s.statuses[projectionName]
+	if !exists {
+		return nil, errors.NewCode(errors.NotFound, "projection status not found").
+			WithContext("projection_name", projectionName)
+	}
+	cp := *status
+	return &cp, nil
+}
+
+// SaveProjectionStatus 记录投影运行状态。
+func (s *MemoryCheckpointStore) SaveProjectionStatus(ctx context.Context, status *ProjectionStatus) error {
+	if status == nil || status.Name == "" {
+		return errors.NewCode(errors.InvalidInput, "invalid projection status")
+	}
+	cp := *status
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.statuses[status.Name] = &cp
+	return nil
+}
+
 // Clear 清空所有检查点（测试用）。
 func (s *MemoryCheckpointStore) Clear() {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
 	s.checkpoints = make(map[string]*Checkpoint)
+	s.versions = make(map[string]int)
+	s.statuses = make(map[string]*ProjectionStatus)
 }
 
 // Count 返回检查点数量（测试用）。
@@ -86,3 +146,5 @@ func (s *MemoryCheckpointStore) Count() int {
 
 // Ensure MemoryCheckpointStore implements ICheckpointStore
 var _ ICheckpointStore = (*MemoryCheckpointStore)(nil)
+var _ IProjectionVersionStore = (*MemoryCheckpointStore)(nil)
+var _ IProjectionStatusStore = (*MemoryCheckpointStore)(nil)
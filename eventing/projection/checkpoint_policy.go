@@ -9,7 +9,7 @@ func (pm *ProjectionManager[ID]) shouldSaveCheckpointAfterEvent(rt *projectionRu
 	return rt.shouldSaveCheckpointAfterEvent(pm.config)
 }
 
-func shouldSaveCheckpointAfterEventState(checkpoint checkpointState, config *ProjectionConfig) bool {
+func shouldSaveCheckpointAfterEventState(checkpoint checkpointState, config *ProjectionConfig, now time.Time) bool {
 	if config == nil {
 		return true
 	}
@@ -22,18 +22,18 @@ func shouldSaveCheckpointAfterEventState(checkpoint checkpointState, config *Pro
 	if countThreshold > 0 && checkpoint.eventsSinceLastSave+1 >= countThreshold {
 		return true
 	}
-	if intervalThreshold > 0 && time.Since(checkpoint.lastSaveTime) >= intervalThreshold {
+	if intervalThreshold > 0 && now.Sub(checkpoint.lastSaveTime) >= intervalThreshold {
 		return true
 	}
 	return false
 }
 
-func updateCheckpointTrackerState(checkpoint *checkpointState, saved bool) {
+func updateCheckpointTrackerState(checkpoint *checkpointState, saved bool, now time.Time) {
 	if checkpoint == nil {
 		return
 	}
 	if saved {
-		checkpoint.lastSaveTime = time.Now()
+		checkpoint.lastSaveTime = now
 		checkpoint.eventsSinceLastSave = 0
 	} else {
 		checkpoint.eventsSinceLastSave++
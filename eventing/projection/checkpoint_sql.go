@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"gochen/clock"
 	"gochen/db"
 	"gochen/db/dialect"
 	"gochen/db/orm"
@@ -18,10 +19,16 @@ type SQLCheckpointStore struct {
 	db        db.IDatabase
 	tableName string
 	dialect   dialect.Dialect
+	clock     clock.IClock
 }
 
 // NewSQLCheckpointStore 创建一个基于 SQL 的检查点存储实现。
 func NewSQLCheckpointStore(db db.IDatabase, tableName string) *SQLCheckpointStore {
+	return NewSQLCheckpointStoreWithClock(db, tableName, nil)
+}
+
+// NewSQLCheckpointStoreWithClock 创建使用指定时钟填充 updated_at/created_at 的 SQL 检查点存储；clk 为 nil 时使用真实时钟。
+func NewSQLCheckpointStoreWithClock(db db.IDatabase, tableName string, clk clock.IClock) *SQLCheckpointStore {
 	if tableName == "" {
 		tableName = "projection_checkpoints"
 	}
@@ -30,7 +37,15 @@ func NewSQLCheckpointStore(db db.IDatabase, tableName string) *SQLCheckpointStor
 		db:        db,
 		tableName: tableName,
 		dialect:   dialect.FromDatabase(db),
+		clock:     clk,
+	}
+}
+
+func (s *SQLCheckpointStore) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
 	}
+	return s.clock.Now()
 }
 
 // RequiresORMTxSession 声明 SQL checkpoint store 需要复用 ctx 中的 ORM 事务 session。
@@ -94,7 +109,7 @@ func (s *SQLCheckpointStore) SaveWithDB(ctx context.Context, database db.IDataba
 	}
 
 	// 更新 UpdatedAt
-	checkpoint.UpdatedAt = time.Now()
+	checkpoint.UpdatedAt = s.now()
 
 	// 通过 ISql.UpsertInto 实现通用 UPSERT 语义
 	if database == nil {
@@ -196,6 +211,243 @@ func (s *SQLCheckpointStore) CreateTable(ctx context.Context) error {
 			WithContext("table_name", s.tableName)
 	}
 
+	if err := s.createVersionTable(ctx); err != nil {
+		return err
+	}
+	return s.createStatusTable(ctx)
+}
+
+// versionTableName 返回记录投影读模型版本的表名。
+func (s *SQLCheckpointStore) versionTableName() string {
+	return s.tableName + "_versions"
+}
+
+func (s *SQLCheckpointStore) createVersionTable(ctx context.Context) error {
+	var query string
+	switch s.dialect.Name() {
+	case dialect.NameSQLite:
+		query = fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				projection_name TEXT PRIMARY KEY,
+				version INTEGER NOT NULL,
+				updated_at DATETIME NOT NULL
+			)
+		`, s.versionTableName())
+	case dialect.NamePostgres:
+		query = fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				projection_name VARCHAR(255) PRIMARY KEY,
+				version INTEGER NOT NULL,
+				updated_at TIMESTAMPTZ NOT NULL
+			)
+		`, s.versionTableName())
+	default:
+		query = fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				projection_name VARCHAR(255) PRIMARY KEY,
+				version INT NOT NULL,
+				updated_at DATETIME NOT NULL
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`, s.versionTableName())
+	}
+
+	if _, err := s.db.Exec(ctx, query); err != nil {
+		return errors.NewCodeWithCause(errors.Database, "failed to create projection version table", err).
+			WithContext("table_name", s.versionTableName())
+	}
+	return nil
+}
+
+// LoadProjectionVersion 读取投影读模型版本；未记录时返回 NotFound。
+func (s *SQLCheckpointStore) LoadProjectionVersion(ctx context.Context, projectionName string) (int, error) {
+	sq, err := sqlbuilder.New(s.db)
+	if err != nil {
+		return 0, errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+
+	var version int
+	err = sq.Select("version").
+		From(s.versionTableName()).
+		Where("projection_name = ?", projectionName).
+		QueryRow(ctx).
+		Scan(&version)
+	if err == sql.ErrNoRows {
+		return 0, errors.NewCode(errors.NotFound, "projection version not found").
+			WithContext("projection_name", projectionName)
+	}
+	if err != nil {
+		return 0, errors.NewCodeWithCause(errors.Database, "checkpoint store failed", err).
+			WithContext("projection_name", projectionName)
+	}
+	return version, nil
+}
+
+// SaveProjectionVersion 用 UPSERT 语义记录投影读模型版本。
+func (s *SQLCheckpointStore) SaveProjectionVersion(ctx context.Context, projectionName string, version int) error {
+	if projectionName == "" || version < 1 {
+		return errors.NewCode(errors.InvalidInput, "invalid projection version").
+			WithContext("projection_name", projectionName).
+			WithContext("version", version)
+	}
+
+	sq, err := sqlbuilder.New(s.databaseFor(ctx))
+	if err != nil {
+		return errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+
+	_, err = sq.UpsertInto(s.versionTableName()).
+		Columns("projection_name", "version", "updated_at").
+		Values(projectionName, version, s.now()).
+		Key("projection_name").
+		Exec(ctx)
+	if err != nil {
+		return errors.NewCodeWithCause(errors.Database, "checkpoint store failed", err).
+			WithContext("projection_name", projectionName)
+	}
+	return nil
+}
+
+// statusTableName 返回记录投影运行状态的表名。
+func (s *SQLCheckpointStore) statusTableName() string {
+	return s.tableName + "_status"
+}
+
+func (s *SQLCheckpointStore) createStatusTable(ctx context.Context) error {
+	var query string
+	switch s.dialect.Name() {
+	case dialect.NameSQLite:
+		query = fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				projection_name TEXT PRIMARY KEY,
+				status TEXT NOT NULL DEFAULT '',
+				processed_events INTEGER NOT NULL DEFAULT 0,
+				failed_events INTEGER NOT NULL DEFAULT 0,
+				last_error TEXT NOT NULL DEFAULT '',
+				last_event_id TEXT NOT NULL DEFAULT '',
+				last_event_time DATETIME NULL,
+				created_at DATETIME NOT NULL,
+				updated_at DATETIME NOT NULL
+			)
+		`, s.statusTableName())
+	case dialect.NamePostgres:
+		query = fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				projection_name VARCHAR(255) PRIMARY KEY,
+				status VARCHAR(32) NOT NULL DEFAULT '',
+				processed_events BIGINT NOT NULL DEFAULT 0,
+				failed_events BIGINT NOT NULL DEFAULT 0,
+				last_error TEXT NOT NULL DEFAULT '',
+				last_event_id VARCHAR(255) NOT NULL DEFAULT '',
+				last_event_time TIMESTAMPTZ NULL,
+				created_at TIMESTAMPTZ NOT NULL,
+				updated_at TIMESTAMPTZ NOT NULL
+			)
+		`, s.statusTableName())
+	default:
+		query = fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				projection_name VARCHAR(255) PRIMARY KEY,
+				status VARCHAR(32) NOT NULL DEFAULT '',
+				processed_events BIGINT NOT NULL DEFAULT 0,
+				failed_events BIGINT NOT NULL DEFAULT 0,
+				last_error TEXT NOT NULL,
+				last_event_id VARCHAR(255) NOT NULL DEFAULT '',
+				last_event_time DATETIME NULL,
+				created_at DATETIME NOT NULL,
+				updated_at DATETIME NOT NULL
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`, s.statusTableName())
+	}
+
+	if _, err := s.db.Exec(ctx, query); err != nil {
+		return errors.NewCodeWithCause(errors.Database, "failed to create projection status table", err).
+			WithContext("table_name", s.statusTableName())
+	}
+	return nil
+}
+
+// LoadProjectionStatus 读取投影运行状态；未记录时返回 NotFound。
+func (s *SQLCheckpointStore) LoadProjectionStatus(ctx context.Context, projectionName string) (*ProjectionStatus, error) {
+	sq, err := sqlbuilder.New(s.db)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+
+	var status ProjectionStatus
+	var lastEventTime sql.NullTime
+	err = sq.Select(
+		"projection_name", "status", "processed_events", "failed_events",
+		"last_error", "last_event_id", "last_event_time", "created_at", "updated_at",
+	).From(s.statusTableName()).
+		Where("projection_name = ?", projectionName).
+		QueryRow(ctx).
+		Scan(
+			&status.Name,
+			&status.Status,
+			&status.ProcessedEvents,
+			&status.FailedEvents,
+			&status.LastError,
+			&status.LastEventID,
+			&lastEventTime,
+			&status.CreatedAt,
+			&status.UpdatedAt,
+		)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewCode(errors.NotFound, "projection status not found").
+			WithContext("projection_name", projectionName)
+	}
+	if err != nil {
+		return nil, errors.NewCodeWithCause(errors.Database, "checkpoint store failed", err).
+			WithContext("projection_name", projectionName)
+	}
+	if lastEventTime.Valid {
+		status.LastEventTime = lastEventTime.Time
+	}
+	return &status, nil
+}
+
+// SaveProjectionStatus 用 UPSERT 语义记录投影运行状态。
+func (s *SQLCheckpointStore) SaveProjectionStatus(ctx context.Context, status *ProjectionStatus) error {
+	if status == nil || status.Name == "" {
+		return errors.NewCode(errors.InvalidInput, "invalid projection status")
+	}
+
+	sq, err := sqlbuilder.New(s.databaseFor(ctx))
+	if err != nil {
+		return errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+
+	var lastEventTime any
+	if !status.LastEventTime.IsZero() {
+		lastEventTime = status.LastEventTime
+	}
+	createdAt := status.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = s.now()
+	}
+
+	_, err = sq.UpsertInto(s.statusTableName()).
+		Columns(
+			"projection_name", "status", "processed_events", "failed_events",
+			"last_error", "last_event_id", "last_event_time", "created_at", "updated_at",
+		).
+		Values(
+			status.Name,
+			status.Status,
+			status.ProcessedEvents,
+			status.FailedEvents,
+			status.LastError,
+			status.LastEventID,
+			lastEventTime,
+			createdAt,
+			s.now(),
+		).
+		Key("projection_name").
+		Exec(ctx)
+	if err != nil {
+		return errors.NewCodeWithCause(errors.Database, "checkpoint store failed", err).
+			WithContext("projection_name", status.Name)
+	}
 	return nil
 }
 
@@ -290,7 +542,7 @@ func (s *SQLCheckpointStore) SaveBatch(ctx context.Context, checkpoints []*Check
 			continue
 		}
 
-		cp.UpdatedAt = time.Now()
+		cp.UpdatedAt = s.now()
 
 		sq, err := sqlbuilder.New(tx)
 		if err != nil {
@@ -345,3 +597,5 @@ func validateCheckpointTableName(name string) error {
 
 // Ensure SQLCheckpointStore implements ICheckpointStore
 var _ ICheckpointStore = (*SQLCheckpointStore)(nil)
+var _ IProjectionVersionStore = (*SQLCheckpointStore)(nil)
+var _ IProjectionStatusStore = (*SQLCheckpointStore)(nil)
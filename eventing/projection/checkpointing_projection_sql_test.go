@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	_ "modernc.org/sqlite"
 
+	"gochen/clock"
 	"gochen/db"
 	"gochen/db/orm"
 	ormlite "gochen/db/orm/lite"
@@ -110,6 +111,20 @@ func TestCheckpointingProjector_ReadModelFailureDoesNotSaveSQLCheckpoint(t *test
 	require.Equal(t, errors.NotFound, errors.Code(err))
 }
 
+func TestSQLCheckpointStore_UsesInjectedClockForUpdatedAt(t *testing.T) {
+	ctx := context.Background()
+	database := newProjectionCheckpointTestDB(t)
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	store := NewSQLCheckpointStoreWithClock(database, "projection_checkpoints", clock.NewManualClock(now))
+	require.NoError(t, store.CreateTable(ctx))
+
+	require.NoError(t, store.Save(ctx, NewCheckpointAt(now, "clocked", 3, "evt-3", now)))
+
+	loaded, err := store.Load(ctx, "clocked")
+	require.NoError(t, err)
+	require.True(t, loaded.UpdatedAt.Equal(now), "updated_at=%s", loaded.UpdatedAt)
+}
+
 func newProjectionCheckpointTestDB(tb testing.TB) db.IDatabase {
 	tb.Helper()
 
@@ -1,6 +1,8 @@
 package projection
 
 import (
+	"gochen/clock"
+	"gochen/config"
 	"gochen/contextx"
 	"gochen/eventing"
 	"gochen/logging"
@@ -16,7 +18,8 @@ type ProjectionConfig struct {
 	// 语义说明：
 	//   - 0 表示不重试（仅执行一次 Handle）；
 	//   - >0 表示在首次失败后最多再重试 MaxRetries 次（总尝试次数 <= 1+MaxRetries）；
-	//   - <0 视为配置错误，将按 0 处理（不重试）。
+	//   - <0 视为配置错误，将按 0 处理（不重试）；
+	//   - 在线事件总线处理不在进程内重试：失败交由总线重投，同一事件连续失败 1+MaxRetries 次后才进入死信队列。
 	MaxRetries int
 
 	// RetryBackoff 重试退避时间
@@ -38,6 +41,43 @@ type ProjectionConfig struct {
 	// 默认值 100，设置为 0 表示禁用事件数维度策略。
 	// 注意：如果两个维度都设置为 0，则每个事件都会保存检查点（不推荐）。
 	CheckpointSaveCount int
+
+	// ConsumerGroup 消费组前缀（可选）。
+	//
+	// 非空时投影以 "<ConsumerGroup>.<投影名>" 为组名订阅事件：多个实例注册同名投影时竞争消费，
+	// 每个事件只由其中一个实例处理；要求事件总线实现 bus.IGroupEventBus，否则注册返回 Unsupported。
+	// 为空时每个实例各自收到全部事件（默认行为）。
+	ConsumerGroup string
+
+	// HandlerTimeout 单次 Handle 的截止时间；<= 0 表示不限制。
+	//
+	// 超时后取消处理器 ctx 并按失败处理（走重试、错误状态与 DeadLetterFunc），同时记录调用栈采样与超时指标，
+	// 避免卡住的处理器无声地阻塞投影消费；处理器应响应 ctx 取消。
+	HandlerTimeout time.Duration
+
+	// HandlerStopGrace 是超时后等待处理器退出的宽限期；<= 0 时使用 messaging.DefaultHandlerStopGrace。
+	//
+	// 超时后的重试只在处理器退出后进行，不会与仍在运行的旧调用并发；宽限期内仍未退出的处理器被放弃，
+	// 投影标记为 error 并停止处理后续事件（不重试、不写入死信），需排查后重新启动。
+	HandlerStopGrace time.Duration
+
+	// Clock 是投影状态时间戳、检查点时间维度策略、重试退避与延迟指标的时间来源；为 nil 时使用真实时钟。
+	//
+	// 测试中注入 clock.ManualClock 后可通过 Advance 推进检查点间隔与重试退避，无需等待真实时间。
+	Clock clock.IClock
+}
+
+// ConfigFromSettings 把分层加载的 config.ProjectionSettings 转换为 ProjectionConfig（DeadLetterFunc 使用默认实现）。
+func ConfigFromSettings(s config.ProjectionSettings) *ProjectionConfig {
+	return &ProjectionConfig{
+		MaxRetries:             s.MaxRetries,
+		RetryBackoff:           s.RetryBackoff,
+		DeadLetterFunc:         defaultDeadLetterFunc(),
+		CheckpointSaveInterval: s.CheckpointSaveInterval,
+		CheckpointSaveCount:    s.CheckpointSaveCount,
+		ConsumerGroup:          s.ConsumerGroup,
+		HandlerTimeout:         s.HandlerTimeout,
+	}
 }
 
 func defaultDeadLetterFunc() func(err error, event eventing.IEvent, projection string) {
@@ -77,9 +117,15 @@ func normalizeProjectionConfig(config *ProjectionConfig) *ProjectionConfig {
 	if out.RetryBackoff < 0 {
 		out.RetryBackoff = 0
 	}
+	if out.HandlerTimeout < 0 {
+		out.HandlerTimeout = 0
+	}
 	if out.DeadLetterFunc == nil {
 		out.DeadLetterFunc = defaultDeadLetterFunc()
 	}
+	if out.Clock == nil {
+		out.Clock = clock.NewRealClock()
+	}
 
 	// CheckpointSaveInterval/Count 的 0/0 组合语义为“每个事件都保存检查点”（不推荐），但负数通常是误配置；
 	// 若检测到负数误配置且最终落入 0/0，则回退到默认值，避免引入意外的高频写入。
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"gochen/clock"
 )
 
 // TestNormalizeProjectionConfig_Defaults 验证 NormalizeProjectionConfig Defaults。
@@ -48,3 +50,17 @@ func TestPresetProjectionConfigs(t *testing.T) {
 	require.Equal(t, 15*time.Second, highThroughput.CheckpointSaveInterval)
 	require.Equal(t, 1000, highThroughput.CheckpointSaveCount)
 }
+
+// TestProjectionConfig_ClockDrivesCheckpointInterval 验证检查点时间维度策略与状态时间戳由 ProjectionConfig.Clock 驱动。
+func TestProjectionConfig_ClockDrivesCheckpointInterval(t *testing.T) {
+	clk := clock.NewManualClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	cfg := normalizeProjectionConfig(&ProjectionConfig{CheckpointSaveInterval: time.Minute, CheckpointSaveCount: 1000, Clock: clk})
+	rt := newProjectionRuntime[int64](NewMockProjection("orders", []string{"OrderPlaced"}), cfg.Clock)
+	require.Equal(t, clk.Now(), rt.statusCopy().CreatedAt)
+
+	require.False(t, rt.shouldSaveCheckpointAfterEvent(cfg))
+	clk.Advance(time.Minute)
+	require.True(t, rt.shouldSaveCheckpointAfterEvent(cfg))
+
+	require.NotNil(t, normalizeProjectionConfig(nil).Clock, "nil clock falls back to the real clock")
+}
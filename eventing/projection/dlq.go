@@ -0,0 +1,189 @@
+package projection
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	gerrors "gochen/errors"
+	"gochen/eventing"
+	"gochen/logging"
+)
+
+// DLQEntry 表示一条投影处理失败、被移入投影死信队列的事件。
+type DLQEntry struct {
+	ProjectionName string    `json:"projection_name"`
+	EventID        string    `json:"event_id"`
+	EventType      string    `json:"event_type"`
+	EventData      string    `json:"event_data"` // 事件 JSON（含原始载荷），重放时按 ID 类型解码
+	LastError      string    `json:"last_error"`
+	Attempts       int       `json:"attempts"` // 失败次数：入队计 1，每次重放失败加 1
+	FailedAt       time.Time `json:"failed_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// IProjectionDLQStore 定义投影死信队列的持久化能力。
+//
+// 说明：
+//   - 记录以 (ProjectionName, EventID) 为键；同一事件重复入队时覆盖原记录；
+//   - 写入不参与投影事务：处理失败时事务已回滚，死信必须独立提交。
+type IProjectionDLQStore interface {
+	// SaveDLQEntry 以 UPSERT 语义保存一条死信记录。
+	SaveDLQEntry(ctx context.Context, entry *DLQEntry) error
+
+	// ListDLQEntries 按 FailedAt 升序列出指定投影的全部死信记录。
+	ListDLQEntries(ctx context.Context, projectionName string) ([]*DLQEntry, error)
+
+	// DeleteDLQEntry 删除一条死信记录；记录不存在时不返回错误。
+	DeleteDLQEntry(ctx context.Context, projectionName, eventID string) error
+}
+
+// WithDLQStore 配置投影死信队列存储。
+//
+// 说明：
+//   - 配置后，重试耗尽仍失败的事件会写入死信队列并被跳过，投影继续处理后续事件，
+//     不再阻塞检查点回放；DeadLetterFunc 仍会在在线处理失败时调用；
+//   - 在线处理失败先交回总线重投，同一事件连续失败 1+MaxRetries 次或错误显式不可重试
+//     （retry.IRetryableError 返回 false）时才入队；
+//   - 修复投影（或其依赖）后调用 ReplayFailed 重新处理死信事件；
+//   - 写入死信失败时按普通失败处理（事件不会被跳过）。
+func (pm *ProjectionManager[ID]) WithDLQStore(store IProjectionDLQStore) *ProjectionManager[ID] {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+	pm.dlqStore = store
+	return pm
+}
+
+// ReplayFailed 重新处理指定投影死信队列中的事件。
+//
+// 说明：
+//   - 按入队顺序逐条处理（遵循 MaxRetries/RetryBackoff），成功的记录从死信队列删除；
+//   - 仍失败的记录保留并累加 Attempts，返回聚合后的错误；
+//   - 重放不推进检查点与处理计数：死信事件入队时已计入投影位置。
+func (pm *ProjectionManager[ID]) ReplayFailed(ctx context.Context, projectionName string) error {
+	if ctx == nil {
+		return gerrors.NewCode(gerrors.InvalidInput, "ctx is nil")
+	}
+	rt, exists := pm.runtime(projectionName)
+	if !exists {
+		return gerrors.NewCode(gerrors.NotFound, "projection not found").
+			WithContext("projection", projectionName)
+	}
+	dlqStore := pm.deadLetterStore()
+	if dlqStore == nil {
+		return gerrors.NewCode(gerrors.InvalidInput, "dead letter store not configured").
+			WithContext("projection", projectionName)
+	}
+
+	entries, err := dlqStore.ListDLQEntries(ctx, projectionName)
+	if err != nil {
+		return gerrors.Wrap(err, gerrors.Database, "failed to list dead letters").
+			WithContext("projection", projectionName)
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := pm.replayDLQEntry(ctx, dlqStore, rt, entry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return gerrors.Join(errs...)
+}
+
+func (pm *ProjectionManager[ID]) replayDLQEntry(ctx context.Context, dlqStore IProjectionDLQStore, rt *projectionRuntime[ID], entry *DLQEntry) error {
+	rt.execMu.Lock()
+	defer rt.execMu.Unlock()
+
+	evt, err := decodeDLQEvent[ID](entry)
+	if err == nil {
+		_, err = pm.applyEventCommon(ctx, rt, evt, applyEventCommonOptions{
+			enableRetry:       true,
+			redeliver:         true,
+			upgradeLogMessage: "projection dead letter payload upgrade/hydrate failed",
+		})
+	}
+	if err == nil {
+		if derr := dlqStore.DeleteDLQEntry(ctx, entry.ProjectionName, entry.EventID); derr != nil {
+			return gerrors.Wrap(derr, gerrors.Database, "failed to delete dead letter").
+				WithContext("projection", entry.ProjectionName).
+				WithContext("event_id", entry.EventID)
+		}
+		pm.logger.Info(ctx, "projection dead letter replayed",
+			logging.String("projection", entry.ProjectionName),
+			logging.String("event_id", entry.EventID))
+		return nil
+	}
+
+	entry.Attempts++
+	entry.LastError = err.Error()
+	entry.UpdatedAt = pm.clock().Now()
+	if serr := dlqStore.SaveDLQEntry(ctx, entry); serr != nil {
+		pm.logger.Warn(ctx, "failed to update dead letter",
+			logging.String("projection", entry.ProjectionName),
+			logging.String("event_id", entry.EventID),
+			logging.Error(serr))
+	}
+	return gerrors.Wrap(err, gerrors.Internal, "replay dead letter failed").
+		WithContext("projection", entry.ProjectionName).
+		WithContext("event_id", entry.EventID)
+}
+
+// deadLetterStore 返回配置的死信队列存储；未配置时返回 nil。
+func (pm *ProjectionManager[ID]) deadLetterStore() IProjectionDLQStore {
+	if pm == nil {
+		return nil
+	}
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
+	return pm.dlqStore
+}
+
+// parkFailedEvent 把处理失败的事件写入死信队列；未配置存储或写入失败时返回 false。
+func (pm *ProjectionManager[ID]) parkFailedEvent(ctx context.Context, projectionName string, evt eventing.IEvent, cause error) bool {
+	dlqStore := pm.deadLetterStore()
+	if dlqStore == nil {
+		return false
+	}
+	data, err := json.Marshal(evt)
+	if err == nil {
+		now := pm.clock().Now()
+		err = dlqStore.SaveDLQEntry(ctx, &DLQEntry{
+			ProjectionName: projectionName,
+			EventID:        evt.GetID(),
+			EventType:      evt.GetType(),
+			EventData:      string(data),
+			LastError:      cause.Error(),
+			Attempts:       1,
+			FailedAt:       now,
+			UpdatedAt:      now,
+		})
+	}
+	if err != nil {
+		pm.logger.Error(ctx, "failed to move projection event to dead letter queue", logging.Error(err),
+			logging.String("projection", projectionName),
+			logging.String("event_id", evt.GetID()))
+		return false
+	}
+	pm.logger.Warn(ctx, "projection event moved to dead letter queue", logging.Error(cause),
+		logging.String("projection", projectionName),
+		logging.String("event_id", evt.GetID()),
+		logging.String("event_type", evt.GetType()))
+	return true
+}
+
+// decodeDLQEvent 把死信记录还原为事件；载荷保持原始 JSON 结构，由处理流程按注册表重新水合。
+func decodeDLQEvent[ID comparable](entry *DLQEntry) (*eventing.Event[ID], error) {
+	var evt eventing.Event[ID]
+	decoder := json.NewDecoder(strings.NewReader(entry.EventData))
+	decoder.UseNumber()
+	if err := decoder.Decode(&evt); err != nil {
+		return nil, gerrors.Wrap(err, gerrors.InvalidInput, "unmarshal dead letter event failed").
+			WithContext("projection", entry.ProjectionName).
+			WithContext("event_id", entry.EventID)
+	}
+	return &evt, nil
+}
@@ -0,0 +1,70 @@
+package projection
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"gochen/errors"
+)
+
+// MemoryDLQStore 内存投影死信队列（用于测试）。
+//
+// 不持久化，进程重启后数据丢失。
+type MemoryDLQStore struct {
+	entries map[string]map[string]*DLQEntry // projection -> event_id -> entry
+	mutex   sync.RWMutex
+}
+
+// NewMemoryDLQStore 创建内存投影死信队列。
+func NewMemoryDLQStore() *MemoryDLQStore {
+	return &MemoryDLQStore{entries: make(map[string]map[string]*DLQEntry)}
+}
+
+// SaveDLQEntry 保存一条死信记录。
+func (s *MemoryDLQStore) SaveDLQEntry(ctx context.Context, entry *DLQEntry) error {
+	if entry == nil || entry.ProjectionName == "" || entry.EventID == "" {
+		return errors.NewCode(errors.InvalidInput, "invalid dead letter entry")
+	}
+	cp := *entry
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	byEvent, ok := s.entries[entry.ProjectionName]
+	if !ok {
+		byEvent = make(map[string]*DLQEntry)
+		s.entries[entry.ProjectionName] = byEvent
+	}
+	byEvent[entry.EventID] = &cp
+	return nil
+}
+
+// ListDLQEntries 按 FailedAt 升序列出死信记录。
+func (s *MemoryDLQStore) ListDLQEntries(ctx context.Context, projectionName string) ([]*DLQEntry, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	entries := make([]*DLQEntry, 0, len(s.entries[projectionName]))
+	for _, entry := range s.entries[projectionName] {
+		cp := *entry
+		entries = append(entries, &cp)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].FailedAt.Equal(entries[j].FailedAt) {
+			return entries[i].FailedAt.Before(entries[j].FailedAt)
+		}
+		return entries[i].EventID < entries[j].EventID
+	})
+	return entries, nil
+}
+
+// DeleteDLQEntry 删除一条死信记录。
+func (s *MemoryDLQStore) DeleteDLQEntry(ctx context.Context, projectionName, eventID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.entries[projectionName], eventID)
+	return nil
+}
+
+var _ IProjectionDLQStore = (*MemoryDLQStore)(nil)
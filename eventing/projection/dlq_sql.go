@@ -0,0 +1,194 @@
+package projection
+
+import (
+	"context"
+	"fmt"
+
+	"gochen/db"
+	"gochen/db/dialect"
+	"gochen/db/sql/sqlbuilder"
+	"gochen/errors"
+)
+
+// SQLDLQStore 使用现有数据库抽象持久化投影死信队列。
+//
+// 写入始终使用构造时的数据库句柄，不复用 ctx 中的 ORM 事务 session：
+// 事件处理失败时投影事务已回滚，死信需要独立提交。
+type SQLDLQStore struct {
+	db        db.IDatabase
+	tableName string
+	dialect   dialect.Dialect
+}
+
+// NewSQLDLQStore 创建一个基于 SQL 的投影死信队列存储。
+func NewSQLDLQStore(db db.IDatabase, tableName string) *SQLDLQStore {
+	if tableName == "" {
+		tableName = "projection_dlq"
+	}
+	return &SQLDLQStore{
+		db:        db,
+		tableName: tableName,
+		dialect:   dialect.FromDatabase(db),
+	}
+}
+
+// SaveDLQEntry 用 UPSERT 语义保存一条死信记录。
+func (s *SQLDLQStore) SaveDLQEntry(ctx context.Context, entry *DLQEntry) error {
+	if entry == nil || entry.ProjectionName == "" || entry.EventID == "" {
+		return errors.NewCode(errors.InvalidInput, "invalid dead letter entry")
+	}
+	sq, err := sqlbuilder.New(s.db)
+	if err != nil {
+		return errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+
+	_, err = sq.UpsertInto(s.tableName).
+		Columns(
+			"projection_name", "event_id", "event_type", "event_data",
+			"last_error", "attempts", "failed_at", "updated_at",
+		).
+		Values(
+			entry.ProjectionName,
+			entry.EventID,
+			entry.EventType,
+			entry.EventData,
+			entry.LastError,
+			entry.Attempts,
+			entry.FailedAt,
+			entry.UpdatedAt,
+		).
+		Key("projection_name", "event_id").
+		Exec(ctx)
+	if err != nil {
+		return errors.NewCodeWithCause(errors.Database, "dead letter store failed", err).
+			WithContext("projection_name", entry.ProjectionName).
+			WithContext("event_id", entry.EventID)
+	}
+	return nil
+}
+
+// ListDLQEntries 按 FailedAt 升序列出指定投影的死信记录。
+func (s *SQLDLQStore) ListDLQEntries(ctx context.Context, projectionName string) ([]*DLQEntry, error) {
+	sq, err := sqlbuilder.New(s.db)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+
+	rows, err := sq.Select(
+		"projection_name", "event_id", "event_type", "event_data",
+		"last_error", "attempts", "failed_at", "updated_at",
+	).From(s.tableName).
+		Where("projection_name = ?", projectionName).
+		OrderBy(sqlbuilder.OrderAsc("failed_at"), sqlbuilder.OrderAsc("event_id")).
+		Query(ctx)
+	if err != nil {
+		return nil, errors.NewCodeWithCause(errors.Database, "dead letter store failed", err).
+			WithContext("projection_name", projectionName)
+	}
+	defer rows.Close()
+
+	var entries []*DLQEntry
+	for rows.Next() {
+		var entry DLQEntry
+		if err := rows.Scan(
+			&entry.ProjectionName,
+			&entry.EventID,
+			&entry.EventType,
+			&entry.EventData,
+			&entry.LastError,
+			&entry.Attempts,
+			&entry.FailedAt,
+			&entry.UpdatedAt,
+		); err != nil {
+			return nil, errors.NewCodeWithCause(errors.Database, "dead letter store failed", err).
+				WithContext("projection_name", projectionName)
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewCodeWithCause(errors.Database, "dead letter store failed", err).
+			WithContext("projection_name", projectionName)
+	}
+	return entries, nil
+}
+
+// DeleteDLQEntry 删除一条死信记录。
+func (s *SQLDLQStore) DeleteDLQEntry(ctx context.Context, projectionName, eventID string) error {
+	sq, err := sqlbuilder.New(s.db)
+	if err != nil {
+		return errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+
+	_, err = sq.DeleteFrom(s.tableName).
+		Where("projection_name = ? AND event_id = ?", projectionName, eventID).
+		Exec(ctx)
+	if err != nil {
+		return errors.NewCodeWithCause(errors.Database, "dead letter store failed", err).
+			WithContext("projection_name", projectionName).
+			WithContext("event_id", eventID)
+	}
+	return nil
+}
+
+// CreateTable 创建死信队列表。
+func (s *SQLDLQStore) CreateTable(ctx context.Context) error {
+	if err := validateCheckpointTableName(s.tableName); err != nil {
+		return errors.NewCode(errors.InvalidInput, "invalid dead letter table name").
+			WithContext("table_name", s.tableName).
+			WithContext("cause", err.Error())
+	}
+
+	var query string
+	switch s.dialect.Name() {
+	case dialect.NameSQLite:
+		query = fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				projection_name TEXT NOT NULL,
+				event_id TEXT NOT NULL,
+				event_type TEXT NOT NULL DEFAULT '',
+				event_data TEXT NOT NULL,
+				last_error TEXT NOT NULL DEFAULT '',
+				attempts INTEGER NOT NULL DEFAULT 0,
+				failed_at DATETIME NOT NULL,
+				updated_at DATETIME NOT NULL,
+				PRIMARY KEY (projection_name, event_id)
+			)
+		`, s.tableName)
+	case dialect.NamePostgres:
+		query = fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				projection_name VARCHAR(255) NOT NULL,
+				event_id VARCHAR(255) NOT NULL,
+				event_type VARCHAR(255) NOT NULL DEFAULT '',
+				event_data TEXT NOT NULL,
+				last_error TEXT NOT NULL DEFAULT '',
+				attempts INTEGER NOT NULL DEFAULT 0,
+				failed_at TIMESTAMPTZ NOT NULL,
+				updated_at TIMESTAMPTZ NOT NULL,
+				PRIMARY KEY (projection_name, event_id)
+			)
+		`, s.tableName)
+	default:
+		query = fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				projection_name VARCHAR(255) NOT NULL,
+				event_id VARCHAR(255) NOT NULL,
+				event_type VARCHAR(255) NOT NULL DEFAULT '',
+				event_data TEXT NOT NULL,
+				last_error TEXT NOT NULL,
+				attempts INT NOT NULL DEFAULT 0,
+				failed_at DATETIME NOT NULL,
+				updated_at DATETIME NOT NULL,
+				PRIMARY KEY (projection_name, event_id)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`, s.tableName)
+	}
+
+	if _, err := s.db.Exec(ctx, query); err != nil {
+		return errors.NewCodeWithCause(errors.Database, "failed to create projection dead letter table", err).
+			WithContext("table_name", s.tableName)
+	}
+	return nil
+}
+
+var _ IProjectionDLQStore = (*SQLDLQStore)(nil)
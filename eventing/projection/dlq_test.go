@@ -0,0 +1,114 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gerrors "gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/store"
+)
+
+// TestReplayFailed_ParksAndRepairs 验证重试耗尽的事件进入死信队列且不阻塞回放，修复后可重新处理。
+func TestReplayFailed_ParksAndRepairs(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewMemoryEventStore()
+	checkpointStore := NewMemoryCheckpointStore()
+	dlqStore := NewMemoryDLQStore()
+	appendTestEvents(t, eventStore, 1, 3)
+
+	manager := newStatusTestManager(t, eventStore, checkpointStore).WithDLQStore(dlqStore)
+	broken := true
+	var handled []uint64
+	p := NewMockProjection("orders", []string{"TestEvent"})
+	p.handleFunc = func(_ context.Context, evt eventing.IEvent) error {
+		typed := evt.(*eventing.Event[int64])
+		if typed.Version == 2 && broken {
+			return errors.New("read model unavailable")
+		}
+		handled = append(handled, typed.Version)
+		return nil
+	}
+	require.NoError(t, manager.RegisterProjection(p))
+
+	require.NoError(t, manager.ResumeFromCheckpoint(ctx, "orders"), "the failed event is parked instead of blocking replay")
+	status, err := manager.ProjectionStatus("orders")
+	require.NoError(t, err)
+	assert.Equal(t, "running", status.Status)
+	assert.Equal(t, int64(3), status.ProcessedEvents)
+	assert.Equal(t, int64(1), status.FailedEvents)
+	assert.Equal(t, []uint64{1, 3}, handled)
+
+	require.NoError(t, manager.FlushCheckpoints(ctx))
+	checkpoint, err := checkpointStore.Load(ctx, "orders")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), checkpoint.Position)
+
+	entries, err := dlqStore.ListDLQEntries(ctx, "orders")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "TestEvent", entries[0].EventType)
+	assert.Equal(t, "read model unavailable", entries[0].LastError)
+	assert.Equal(t, 1, entries[0].Attempts)
+
+	require.Error(t, manager.ReplayFailed(ctx, "orders"))
+	entries, err = dlqStore.ListDLQEntries(ctx, "orders")
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "events that still fail stay in the queue")
+	assert.Equal(t, 2, entries[0].Attempts)
+
+	broken = false
+	require.NoError(t, manager.ReplayFailed(ctx, "orders"))
+	assert.Equal(t, []uint64{1, 3, 2}, handled)
+	entries, err = dlqStore.ListDLQEntries(ctx, "orders")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	status, err = manager.ProjectionStatus("orders")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), status.ProcessedEvents, "replaying dead letters does not move the projection position")
+}
+
+// TestReplayFailed_RequiresStore 验证未配置死信存储或投影不存在时返回错误。
+func TestReplayFailed_RequiresStore(t *testing.T) {
+	ctx := context.Background()
+	manager := newStatusTestManager(t, store.NewMemoryEventStore(), NewMemoryCheckpointStore())
+	require.NoError(t, manager.RegisterProjection(NewMockProjection("orders", []string{"TestEvent"})))
+
+	assert.True(t, gerrors.Is(manager.ReplayFailed(ctx, "orders"), gerrors.InvalidInput))
+	assert.True(t, gerrors.Is(manager.WithDLQStore(NewMemoryDLQStore()).ReplayFailed(ctx, "missing"), gerrors.NotFound))
+}
+
+// TestSQLDLQStore_RoundTrip 验证 SQL 死信队列的 UPSERT、排序与删除。
+func TestSQLDLQStore_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	dlqStore := NewSQLDLQStore(newProjectionCheckpointTestDB(t), "")
+	require.NoError(t, dlqStore.CreateTable(ctx))
+
+	now := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, dlqStore.SaveDLQEntry(ctx, &DLQEntry{ProjectionName: "orders", EventID: "e2", EventType: "TestEvent", EventData: "{}", LastError: "boom", Attempts: 1, FailedAt: now.Add(time.Second), UpdatedAt: now}))
+	require.NoError(t, dlqStore.SaveDLQEntry(ctx, &DLQEntry{ProjectionName: "orders", EventID: "e1", EventType: "TestEvent", EventData: "{}", LastError: "boom", Attempts: 1, FailedAt: now, UpdatedAt: now}))
+	require.NoError(t, dlqStore.SaveDLQEntry(ctx, &DLQEntry{ProjectionName: "orders", EventID: "e1", EventType: "TestEvent", EventData: "{}", LastError: "still broken", Attempts: 2, FailedAt: now, UpdatedAt: now}))
+	require.NoError(t, dlqStore.SaveDLQEntry(ctx, &DLQEntry{ProjectionName: "users", EventID: "e1", EventType: "TestEvent", EventData: "{}", FailedAt: now, UpdatedAt: now}))
+
+	entries, err := dlqStore.ListDLQEntries(ctx, "orders")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "e1", entries[0].EventID)
+	assert.Equal(t, "still broken", entries[0].LastError)
+	assert.Equal(t, 2, entries[0].Attempts)
+	assert.Equal(t, "e2", entries[1].EventID)
+
+	require.NoError(t, dlqStore.DeleteDLQEntry(ctx, "orders", "e1"))
+	entries, err = dlqStore.ListDLQEntries(ctx, "orders")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "e2", entries[0].EventID)
+
+	assert.True(t, gerrors.Is(dlqStore.SaveDLQEntry(ctx, &DLQEntry{ProjectionName: "orders"}), gerrors.InvalidInput))
+}
@@ -0,0 +1,243 @@
+package projection
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	gerrors "gochen/errors"
+	"gochen/eventing"
+	"gochen/logging"
+)
+
+// ProjectionGroup 描述一组共享生命周期的投影（如 "reporting"）。
+//
+// 说明：
+//   - 组内成员按依赖顺序启动与重建（被依赖者在前），按逆序停止；
+//   - 无依赖关系的成员保持 Projections 中的声明顺序；
+//   - 一个投影可以属于多个组，组只引用投影名称，成员在操作时才要求已注册。
+type ProjectionGroup struct {
+	// Name 是组名（唯一）。
+	Name string
+
+	// Projections 是组成员的投影名称。
+	Projections []string
+
+	// DependsOn 声明组内依赖：键对应的投影依赖值中列出的投影（如聚合器依赖反范式化投影）。
+	// 依赖只能指向组内成员，且不能成环。
+	DependsOn map[string][]string
+}
+
+// DefineProjectionGroup 定义投影组；组名已存在时返回 Conflict，依赖非法或成环时返回 InvalidInput。
+func (pm *ProjectionManager[ID]) DefineProjectionGroup(group ProjectionGroup) error {
+	if pm == nil {
+		return gerrors.NewCode(gerrors.InvalidInput, "projection manager is nil")
+	}
+	name := strings.TrimSpace(group.Name)
+	if name == "" {
+		return gerrors.NewCode(gerrors.InvalidInput, "projection group name cannot be empty")
+	}
+	order, err := sortProjectionGroup(group)
+	if err != nil {
+		return err.WithContext("group", name)
+	}
+
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+	if _, exists := pm.groups[name]; exists {
+		return gerrors.NewCode(gerrors.Conflict, "projection group already defined").
+			WithContext("group", name)
+	}
+	pm.groups[name] = order
+	return nil
+}
+
+// ProjectionGroupOrder 返回组成员的启动顺序（依赖在前）。
+func (pm *ProjectionManager[ID]) ProjectionGroupOrder(name string) ([]string, error) {
+	pm.mutex.RLock()
+	order, exists := pm.groups[name]
+	pm.mutex.RUnlock()
+	if !exists {
+		return nil, gerrors.NewCode(gerrors.NotFound, "projection group not found").
+			WithContext("group", name)
+	}
+	return slices.Clone(order), nil
+}
+
+// StartProjectionGroup 按依赖顺序从检查点恢复并启动组内全部投影。
+//
+// 任一成员恢复失败时，已启动的成员按逆序停止后返回错误，组不会停留在部分启动状态。
+func (pm *ProjectionManager[ID]) StartProjectionGroup(ctx context.Context, name string) error {
+	runtimes, err := pm.groupRuntimes(name)
+	if err != nil {
+		return err
+	}
+
+	started := make([]*projectionRuntime[ID], 0, len(runtimes))
+	for _, rt := range runtimes {
+		wasRunning := rt.isRunning()
+		if err := pm.ResumeFromCheckpoint(ctx, rt.projection.Name()); err != nil {
+			for i := len(started) - 1; i >= 0; i-- {
+				pm.stopRuntime(started[i])
+			}
+			return gerrors.Wrap(err, gerrors.Internal, "failed to start projection group").
+				WithContext("group", name).
+				WithContext("projection", rt.projection.Name())
+		}
+		if !wasRunning {
+			started = append(started, rt)
+		}
+	}
+
+	pm.logger.Info(ctx, "projection group started",
+		logging.String("group", name),
+		logging.Int("projections", len(runtimes)))
+	return nil
+}
+
+// StopProjectionGroup 按依赖逆序停止组内全部投影（下游先停，避免读取到停更的上游读模型）。
+func (pm *ProjectionManager[ID]) StopProjectionGroup(ctx context.Context, name string) error {
+	runtimes, err := pm.groupRuntimes(name)
+	if err != nil {
+		return err
+	}
+	for i := len(runtimes) - 1; i >= 0; i-- {
+		pm.stopRuntime(runtimes[i])
+	}
+	pm.logger.Info(ctx, "projection group stopped",
+		logging.String("group", name),
+		logging.Int("projections", len(runtimes)))
+	return nil
+}
+
+// RebuildProjectionGroup 停止组内投影后按依赖顺序逐个重建，成功后恢复原先处于运行状态的成员。
+//
+// 任一成员重建失败时立即返回错误，整组保持停止，避免下游基于不完整的上游读模型重建。
+func (pm *ProjectionManager[ID]) RebuildProjectionGroup(ctx context.Context, name string, events []eventing.Event[ID]) error {
+	runtimes, err := pm.groupRuntimes(name)
+	if err != nil {
+		return err
+	}
+
+	wasRunning := make([]bool, len(runtimes))
+	for i := len(runtimes) - 1; i >= 0; i-- {
+		wasRunning[i] = runtimes[i].isRunning()
+		pm.stopRuntime(runtimes[i])
+	}
+
+	for _, rt := range runtimes {
+		if err := pm.RebuildProjection(ctx, rt.projection.Name(), events); err != nil {
+			return gerrors.Wrap(err, gerrors.Internal, "failed to rebuild projection group").
+				WithContext("group", name).
+				WithContext("projection", rt.projection.Name())
+		}
+	}
+
+	for i, rt := range runtimes {
+		if wasRunning[i] {
+			pm.startRuntime(rt)
+		}
+	}
+	pm.logger.Info(ctx, "projection group rebuilt",
+		logging.String("group", name),
+		logging.Int("projections", len(runtimes)),
+		logging.Int("events", len(events)))
+	return nil
+}
+
+// groupRuntimes 按启动顺序返回组成员的运行时；任一成员未注册时返回 NotFound。
+func (pm *ProjectionManager[ID]) groupRuntimes(name string) ([]*projectionRuntime[ID], error) {
+	if pm == nil {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "projection manager is nil")
+	}
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
+
+	order, exists := pm.groups[name]
+	if !exists {
+		return nil, gerrors.NewCode(gerrors.NotFound, "projection group not found").
+			WithContext("group", name)
+	}
+	runtimes := make([]*projectionRuntime[ID], 0, len(order))
+	for _, member := range order {
+		rt, ok := pm.runtimes[member]
+		if !ok {
+			return nil, gerrors.NewCode(gerrors.NotFound, "projection not found").
+				WithContext("group", name).
+				WithContext("projection", member)
+		}
+		runtimes = append(runtimes, rt)
+	}
+	return runtimes, nil
+}
+
+func (pm *ProjectionManager[ID]) startRuntime(rt *projectionRuntime[ID]) {
+	rt.execMu.Lock()
+	defer rt.execMu.Unlock()
+	rt.markRunning()
+}
+
+func (pm *ProjectionManager[ID]) stopRuntime(rt *projectionRuntime[ID]) {
+	rt.execMu.Lock()
+	defer rt.execMu.Unlock()
+	rt.markStopped()
+}
+
+// sortProjectionGroup 校验组定义并返回拓扑排序后的成员（同层保持声明顺序）。
+func sortProjectionGroup(group ProjectionGroup) ([]string, *gerrors.AppError) {
+	if len(group.Projections) == 0 {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "projection group has no projections")
+	}
+	index := make(map[string]int, len(group.Projections))
+	for i, member := range group.Projections {
+		if member == "" {
+			return nil, gerrors.NewCode(gerrors.InvalidInput, "projection name cannot be empty")
+		}
+		if _, dup := index[member]; dup {
+			return nil, gerrors.NewCode(gerrors.InvalidInput, "duplicate projection in group").
+				WithContext("projection", member)
+		}
+		index[member] = i
+	}
+
+	pending := make([]int, len(group.Projections))
+	dependents := make([][]int, len(group.Projections))
+	for member, deps := range group.DependsOn {
+		i, ok := index[member]
+		if !ok {
+			return nil, gerrors.NewCode(gerrors.InvalidInput, "projection dependency declared for non-member").
+				WithContext("projection", member)
+		}
+		for _, dep := range deps {
+			j, ok := index[dep]
+			if !ok {
+				return nil, gerrors.NewCode(gerrors.InvalidInput, "projection depends on non-member").
+					WithContext("projection", member).
+					WithContext("dependency", dep)
+			}
+			pending[i]++
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	order := make([]string, 0, len(group.Projections))
+	done := make([]bool, len(group.Projections))
+	for len(order) < len(group.Projections) {
+		next := -1
+		for i := range group.Projections {
+			if !done[i] && pending[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return nil, gerrors.NewCode(gerrors.InvalidInput, "projection group has cyclic dependencies")
+		}
+		done[next] = true
+		order = append(order, group.Projections[next])
+		for _, dependent := range dependents[next] {
+			pending[dependent]--
+		}
+	}
+	return order, nil
+}
@@ -0,0 +1,128 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gerrors "gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/store"
+)
+
+func TestDefineProjectionGroup_OrdersByDependencies(t *testing.T) {
+	manager, _ := newVersionedTestManager(t, nil)
+
+	require.NoError(t, manager.DefineProjectionGroup(ProjectionGroup{
+		Name:        "reporting",
+		Projections: []string{"dashboard", "daily_totals", "orders_flat", "customers_flat"},
+		DependsOn: map[string][]string{
+			"daily_totals": {"orders_flat"},
+			"dashboard":    {"daily_totals", "customers_flat"},
+		},
+	}))
+	order, err := manager.ProjectionGroupOrder("reporting")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders_flat", "daily_totals", "customers_flat", "dashboard"}, order)
+
+	err = manager.DefineProjectionGroup(ProjectionGroup{Name: "reporting", Projections: []string{"x"}})
+	assert.True(t, gerrors.Is(err, gerrors.Conflict))
+
+	err = manager.DefineProjectionGroup(ProjectionGroup{
+		Name:        "cyclic",
+		Projections: []string{"a", "b"},
+		DependsOn:   map[string][]string{"a": {"b"}, "b": {"a"}},
+	})
+	assert.True(t, gerrors.Is(err, gerrors.InvalidInput))
+
+	err = manager.DefineProjectionGroup(ProjectionGroup{
+		Name:        "external",
+		Projections: []string{"a"},
+		DependsOn:   map[string][]string{"a": {"outside"}},
+	})
+	assert.True(t, gerrors.Is(err, gerrors.InvalidInput))
+
+	_, err = manager.ProjectionGroupOrder("missing")
+	assert.True(t, gerrors.Is(err, gerrors.NotFound))
+}
+
+func TestProjectionGroup_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newVersionedTestManager(t, NewMemoryCheckpointStore())
+
+	var rebuilt []string
+	record := func(name string) func(context.Context, []eventing.Event[int64]) error {
+		return func(context.Context, []eventing.Event[int64]) error {
+			rebuilt = append(rebuilt, name)
+			return nil
+		}
+	}
+	aggregator := NewMockProjection("aggregator", []string{"TestEvent"})
+	aggregator.rebuildFunc = record("aggregator")
+	denormalizer := NewMockProjection("denormalizer", []string{"TestEvent"})
+	denormalizer.rebuildFunc = record("denormalizer")
+	require.NoError(t, manager.RegisterProjection(aggregator))
+	require.NoError(t, manager.RegisterProjection(denormalizer))
+
+	require.NoError(t, manager.DefineProjectionGroup(ProjectionGroup{
+		Name:        "reporting",
+		Projections: []string{"aggregator", "denormalizer"},
+		DependsOn:   map[string][]string{"aggregator": {"denormalizer"}},
+	}))
+
+	require.NoError(t, manager.StartProjectionGroup(ctx, "reporting"))
+	for _, name := range []string{"aggregator", "denormalizer"} {
+		status, err := manager.ProjectionStatus(name)
+		require.NoError(t, err)
+		assert.Equal(t, "running", status.Status, name)
+	}
+
+	events := []eventing.Event[int64]{*eventing.NewEvent[int64](1, "Agg", "TestEvent", 1, nil)}
+	require.NoError(t, manager.RebuildProjectionGroup(ctx, "reporting", events))
+	assert.Equal(t, []string{"denormalizer", "aggregator"}, rebuilt)
+	status, err := manager.ProjectionStatus("aggregator")
+	require.NoError(t, err)
+	assert.Equal(t, "running", status.Status, "members running before rebuild are restarted")
+	assert.Equal(t, int64(1), status.ProcessedEvents)
+
+	require.NoError(t, manager.StopProjectionGroup(ctx, "reporting"))
+	for _, name := range []string{"aggregator", "denormalizer"} {
+		status, err := manager.ProjectionStatus(name)
+		require.NoError(t, err)
+		assert.Equal(t, "stopped", status.Status, name)
+	}
+}
+
+func TestStartProjectionGroup_RollsBackOnFailure(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewMemoryEventStore()
+	manager := newStatusTestManager(t, eventStore, NewMemoryCheckpointStore())
+
+	evt := eventing.NewEvent[int64](1, "Agg", "TestEvent", 1, nil)
+	require.NoError(t, eventStore.AppendEvents(ctx, 1, toStorableEvents([]eventing.Event[int64]{*evt}), 0))
+
+	upstream := NewMockProjection("upstream", []string{"TestEvent"})
+	downstream := NewMockProjection("downstream", []string{"TestEvent"})
+	downstream.handleFunc = func(context.Context, eventing.IEvent) error { return errors.New("boom") }
+	require.NoError(t, manager.RegisterProjection(upstream))
+	require.NoError(t, manager.RegisterProjection(downstream))
+	require.NoError(t, manager.DefineProjectionGroup(ProjectionGroup{
+		Name:        "reporting",
+		Projections: []string{"downstream", "upstream"},
+		DependsOn:   map[string][]string{"downstream": {"upstream"}},
+	}))
+
+	require.Error(t, manager.StartProjectionGroup(ctx, "reporting"))
+	for _, name := range []string{"upstream", "downstream"} {
+		status, err := manager.ProjectionStatus(name)
+		require.NoError(t, err)
+		assert.NotEqual(t, "running", status.Status, name)
+	}
+
+	require.NoError(t, manager.UnregisterProjection("upstream"))
+	err := manager.StopProjectionGroup(ctx, "reporting")
+	assert.True(t, gerrors.Is(err, gerrors.NotFound), "operations require every member to be registered")
+}
@@ -3,7 +3,6 @@ package projection
 import (
 	"context"
 	"fmt"
-	"time"
 
 	gerrors "gochen/errors"
 	"gochen/eventing"
@@ -47,13 +46,14 @@ func (h *projectionEventHandler[ID]) HandleEvent(ctx context.Context, event even
 		enableRetry:             false,
 		allowCheckpoint:         true,
 		clearLastErrorOnSuccess: false,
+		deadLetter:              true,
 		upgradeLogMessage:       "projection event payload upgrade/hydrate failed",
 	})
 	if res.skipped {
 		return nil
 	}
 
-	lag := time.Since(event.GetTimestamp())
+	lag := h.manager.clock().Now().Sub(event.GetTimestamp())
 	if lag < 0 {
 		lag = 0
 	}
@@ -73,6 +73,10 @@ func (h *projectionEventHandler[ID]) HandleEvent(ctx context.Context, event even
 			logging.Int64("processed_events", res.processedEvents),
 			logging.Int64("failed_events", res.failedEvents),
 		)
+		if res.deadLettered {
+			// 事件已写入死信队列，不再交由总线重投。
+			return nil
+		}
 		return err
 	}
 
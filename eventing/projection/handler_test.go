@@ -2,13 +2,16 @@ package projection
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	gerrors "gochen/errors"
 	"gochen/eventing"
+	"gochen/eventing/monitoring"
 	"gochen/eventing/registry"
 	"gochen/eventing/store"
 	"gochen/eventing/upcast"
@@ -56,3 +59,139 @@ func TestProjectionEventHandler_ShouldProcessOnlyWhenRunning(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, projection.processedEvents)
 }
+
+// TestProjectionEventHandler_PanicBecomesErrorAndDeadLetters 验证投影 panic 被转换为错误并进入死信回调，而不是带崩消费 goroutine。
+func TestProjectionEventHandler_PanicBecomesErrorAndDeadLetters(t *testing.T) {
+	reg := registry.NewRegistry()
+	require.NoError(t, reg.Register("TestEvent", func() any { return &struct{}{} }))
+	var deadLettered error
+	manager, err := NewProjectionManagerWithConfig[int64](store.NewMemoryEventStore(), &MockEventBus{}, reg, upcast.NewUpgraderRegistry(), &ProjectionConfig{
+		DeadLetterFunc: func(err error, _ eventing.IEvent, projection string) {
+			assert.Equal(t, "panicking-projection", projection)
+			deadLettered = err
+		},
+	})
+	require.NoError(t, err)
+
+	projection := NewMockProjection("panicking-projection", []string{"TestEvent"})
+	projection.handleFunc = func(context.Context, eventing.IEvent) error { panic("nil read model") }
+	require.NoError(t, manager.RegisterProjection(projection))
+	require.NoError(t, manager.StartProjection(projection.Name()))
+
+	rt, ok := manager.runtime(projection.Name())
+	require.True(t, ok)
+	evt := &eventing.Event[int64]{
+		Message: messaging.Message{ID: "event-1", Type: "TestEvent", Timestamp: time.Now(), Metadata: messaging.NewMetadata()},
+	}
+
+	err = rt.handlers["TestEvent"].HandleEvent(context.Background(), evt)
+	require.Error(t, err)
+	assert.True(t, messaging.IsPanicError(err))
+	assert.Same(t, err, deadLettered)
+	status, err := manager.ProjectionStatus(projection.Name())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), status.FailedEvents)
+}
+
+// TestProjectionEventHandler_HandlerTimeoutCancelsStuckHandler 验证 HandlerTimeout 取消卡住的处理器并计入超时指标，
+// 宽限期内仍未退出的处理器被放弃，投影标记为 error 且不再处理后续事件。
+func TestProjectionEventHandler_HandlerTimeoutCancelsStuckHandler(t *testing.T) {
+	reg := registry.NewRegistry()
+	require.NoError(t, reg.Register("TestEvent", func() any { return &struct{}{} }))
+	var deadLettered error
+	manager, err := NewProjectionManagerWithConfig[int64](store.NewMemoryEventStore(), &MockEventBus{}, reg, upcast.NewUpgraderRegistry(), &ProjectionConfig{
+		HandlerTimeout:   20 * time.Millisecond,
+		HandlerStopGrace: 20 * time.Millisecond,
+		DeadLetterFunc:   func(err error, _ eventing.IEvent, _ string) { deadLettered = err },
+	})
+	require.NoError(t, err)
+	metrics := monitoring.NewMetrics()
+	manager.SetMetricsRecorder(metrics)
+
+	release := make(chan struct{})
+	defer close(release)
+	projection := NewMockProjection("stuck-projection", []string{"TestEvent"})
+	var calls atomic.Int32
+	projection.handleFunc = func(context.Context, eventing.IEvent) error {
+		calls.Add(1)
+		<-release // 忽略 ctx 取消的处理器也不会拖住调用方
+		return nil
+	}
+	require.NoError(t, manager.RegisterProjection(projection))
+	require.NoError(t, manager.StartProjection(projection.Name()))
+
+	rt, ok := manager.runtime(projection.Name())
+	require.True(t, ok)
+	evt := &eventing.Event[int64]{
+		Message: messaging.Message{ID: "event-1", Type: "TestEvent", Timestamp: time.Now(), Metadata: messaging.NewMetadata()},
+	}
+
+	err = rt.handlers["TestEvent"].HandleEvent(context.Background(), evt)
+	require.Error(t, err)
+	assert.True(t, messaging.IsHandlerTimeout(err))
+	assert.True(t, gerrors.Is(err, gerrors.Timeout))
+	assert.True(t, messaging.IsHandlerAbandoned(err))
+	assert.Same(t, err, deadLettered)
+	assert.Equal(t, int64(1), metrics.Snapshot().ProjectionTimeouts)
+
+	status, err := manager.ProjectionStatus(projection.Name())
+	require.NoError(t, err)
+	assert.Equal(t, "error", status.Status)
+	next := &eventing.Event[int64]{
+		Message: messaging.Message{ID: "event-2", Type: "TestEvent", Timestamp: time.Now(), Metadata: messaging.NewMetadata()},
+	}
+	require.NoError(t, rt.handlers["TestEvent"].HandleEvent(context.Background(), next))
+	assert.Equal(t, int32(1), calls.Load(), "abandoned projection must not run concurrently with the stuck handler")
+}
+
+type permanentProjectionError struct{}
+
+func (permanentProjectionError) Error() string     { return "malformed payload" }
+func (permanentProjectionError) IsRetryable() bool { return false }
+
+// TestProjectionEventHandler_DeadLettersAfterRedeliveries 验证在线处理失败先交回总线重投，
+// 同一事件连续失败 1+MaxRetries 次或错误不可重试时才进入死信队列。
+func TestProjectionEventHandler_DeadLettersAfterRedeliveries(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewRegistry()
+	require.NoError(t, reg.Register("TestEvent", func() any { return &struct{}{} }))
+	manager, err := NewProjectionManagerWithConfig[int64](store.NewMemoryEventStore(), &MockEventBus{}, reg, upcast.NewUpgraderRegistry(), &ProjectionConfig{
+		MaxRetries: 2,
+	})
+	require.NoError(t, err)
+	dlqStore := NewMemoryDLQStore()
+	manager.WithDLQStore(dlqStore)
+
+	var failure error = gerrors.NewCode(gerrors.Database, "read model unavailable")
+	projection := NewMockProjection("orders", []string{"TestEvent"})
+	projection.handleFunc = func(context.Context, eventing.IEvent) error { return failure }
+	require.NoError(t, manager.RegisterProjection(projection))
+	require.NoError(t, manager.StartProjection(projection.Name()))
+	rt, ok := manager.runtime(projection.Name())
+	require.True(t, ok)
+	handler := rt.handlers["TestEvent"]
+	newEvent := func(id string) *eventing.Event[int64] {
+		return &eventing.Event[int64]{
+			Message: messaging.Message{ID: id, Type: "TestEvent", Timestamp: time.Now(), Metadata: messaging.NewMetadata()},
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		require.Error(t, handler.HandleEvent(ctx, newEvent("event-1")), "delivery %d is returned to the bus", i+1)
+	}
+	entries, err := dlqStore.ListDLQEntries(ctx, "orders")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	require.NoError(t, handler.HandleEvent(ctx, newEvent("event-1")), "the third consecutive failure exhausts MaxRetries")
+	entries, err = dlqStore.ListDLQEntries(ctx, "orders")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "event-1", entries[0].EventID)
+
+	failure = permanentProjectionError{}
+	require.NoError(t, handler.HandleEvent(ctx, newEvent("event-2")), "non-retryable errors are parked immediately")
+	entries, err = dlqStore.ListDLQEntries(ctx, "orders")
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
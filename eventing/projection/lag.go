@@ -0,0 +1,63 @@
+package projection
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	gerrors "gochen/errors"
+	"gochen/eventing/monitoring"
+)
+
+// ProjectionLags 返回各投影的检查点延迟（实现 monitoring.IProjectionLagProvider），按名称排序。
+//
+// 配置了检查点存储时读取持久化检查点；否则使用运行时内存状态。
+func (pm *ProjectionManager[ID]) ProjectionLags(ctx context.Context) ([]monitoring.ProjectionLag, error) {
+	pm.mutex.RLock()
+	checkpointStore := pm.checkpointStore
+	runtimes := make([]*projectionRuntime[ID], 0, len(pm.runtimes))
+	for _, rt := range pm.runtimes {
+		runtimes = append(runtimes, rt)
+	}
+	pm.mutex.RUnlock()
+
+	now := monitoring.Now()
+	lags := make([]monitoring.ProjectionLag, 0, len(runtimes))
+	for _, rt := range runtimes {
+		status := rt.statusCopy()
+		if status == nil {
+			continue
+		}
+		name := rt.projection.Name()
+		lag := monitoring.ProjectionLag{
+			Name:          name,
+			Status:        status.Status,
+			Position:      status.ProcessedEvents,
+			LastEventID:   status.LastEventID,
+			LastEventTime: status.LastEventTime,
+		}
+		if checkpointStore != nil {
+			checkpoint, err := checkpointStore.Load(ctx, name)
+			switch {
+			case err == nil:
+				lag.Position = checkpoint.Position
+				lag.LastEventID = checkpoint.LastEventID
+				lag.LastEventTime = checkpoint.LastEventTime
+			case gerrors.Is(err, gerrors.NotFound):
+				// 尚未保存检查点：视为从头开始。
+				lag.Position, lag.LastEventID, lag.LastEventTime = 0, "", time.Time{}
+			default:
+				return nil, gerrors.Wrap(err, gerrors.Database, "failed to load checkpoint").
+					WithContext("projection", name)
+			}
+		}
+		if !lag.LastEventTime.IsZero() {
+			lag.Lag = max(now.Sub(lag.LastEventTime), time.Duration(0))
+		}
+		lags = append(lags, lag)
+	}
+	sort.Slice(lags, func(i, j int) bool { return lags[i].Name < lags[j].Name })
+	return lags, nil
+}
+
+var _ monitoring.IProjectionLagProvider = (*ProjectionManager[int64])(nil)
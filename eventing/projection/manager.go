@@ -17,16 +17,20 @@ import (
 // ProjectionManager 表示投影管理器。
 type ProjectionManager[ID comparable] struct {
 	runtimes        map[string]*projectionRuntime[ID]
+	groups          map[string][]string // 投影组名 -> 按依赖排序的成员
 	eventStore      store.IEventStreamStore[ID]
 	eventBus        bus.IEventBus
 	config          *ProjectionConfig
-	checkpointStore ICheckpointStore // 检查点存储（可选）
+	checkpointStore ICheckpointStore    // 检查点存储（可选）
+	dlqStore        IProjectionDLQStore // 死信队列存储（可选）
 	mutex           sync.RWMutex
 	logger          logging.ILogger
 
 	eventRegistry *registry.Registry
 	upgraders     *upcast.UpgraderRegistry
 	metrics       atomic.Value // projectionMetricsHolder（承载 monitoring.IProjectionMetricsRecorder），用于并发热替换且避免 data race
+
+	flusher checkpointFlusher // 后台检查点刷新循环（见 StartCheckpointFlusher）
 }
 
 type projectionMetricsHolder struct {
@@ -72,6 +76,7 @@ func NewProjectionManagerWithConfig[ID comparable](
 
 	pm := &ProjectionManager[ID]{
 		runtimes:      make(map[string]*projectionRuntime[ID]),
+		groups:        make(map[string][]string),
 		eventStore:    eventStore,
 		eventBus:      eventBus,
 		config:        config,
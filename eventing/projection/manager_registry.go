@@ -4,7 +4,10 @@ import (
 	"context"
 	"gochen/contextx"
 	gerrors "gochen/errors"
+	"gochen/eventing/bus"
 	"gochen/logging"
+	"gochen/messaging"
+	"strings"
 )
 
 // RegisterProjection 用默认后台上下文注册一个投影。
@@ -44,14 +47,27 @@ func (pm *ProjectionManager[ID]) RegisterProjectionWithContext(ctx context.Conte
 		return err
 	}
 
-	rt := newProjectionRuntime(projection)
+	subscribe := pm.eventBus.SubscribeEvent
+	if group := pm.consumerGroup(name); group != "" {
+		grouped, ok := pm.eventBus.(bus.IGroupEventBus)
+		if !ok {
+			return gerrors.NewCode(gerrors.Unsupported, "event bus does not support consumer groups").
+				WithContext("projection", name).
+				WithContext("consumer_group", group)
+		}
+		subscribe = func(ctx context.Context, eventType string, handler bus.IEventHandler) (messaging.UnsubscribeFunc, error) {
+			return grouped.SubscribeEventGroup(ctx, eventType, group, handler)
+		}
+	}
+
+	rt := newProjectionRuntime(projection, pm.clock())
 
 	subscribedHandlers := make(map[string]*projectionEventHandler[ID])
 	for _, eventType := range projection.SupportedEventTypes() {
 		handler := &projectionEventHandler[ID]{runtime: rt, manager: pm}
 		rt.handlers[eventType] = handler
 
-		unsub, err := pm.eventBus.SubscribeEvent(ctx, eventType, handler)
+		unsub, err := subscribe(ctx, eventType, handler)
 		if err != nil {
 			// rollback registration state to avoid partial registration
 			rt.deactivate()
@@ -220,3 +236,11 @@ func (pm *ProjectionManager[ID]) ProjectionStatuses() map[string]*ProjectionStat
 
 	return result
 }
+
+// consumerGroup 返回投影的消费组名；未配置 ConsumerGroup 时返回空串。
+func (pm *ProjectionManager[ID]) consumerGroup(name string) string {
+	if pm.config == nil || strings.TrimSpace(pm.config.ConsumerGroup) == "" {
+		return ""
+	}
+	return strings.TrimSpace(pm.config.ConsumerGroup) + "." + name
+}
@@ -1,14 +1,20 @@
 package projection
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/bus"
 	"gochen/eventing/registry"
 	"gochen/eventing/store"
 	"gochen/eventing/upcast"
+	"gochen/messaging"
+	synctransport "gochen/messaging/transport/direct"
 )
 
 // TestProjectionManager_RegisterProjection 验证 ProjectionManager RegisterProjection。
@@ -168,3 +174,63 @@ func BenchmarkProjectionManager_RegisterProjection(b *testing.B) {
 		manager.RegisterProjection(projection)
 	}
 }
+
+// TestProjectionManager_RegisterProjectionConsumerGroup 验证配置 ConsumerGroup 后多个实例的同名投影竞争消费。
+func TestProjectionManager_RegisterProjectionConsumerGroup(t *testing.T) {
+	tpt := synctransport.NewSyncTransport()
+	assert.NoError(t, tpt.Start(context.Background()))
+	t.Cleanup(func() { _ = tpt.Stop(context.Background()) })
+	eventBus := bus.NewEventBus(messaging.NewMessageBus(tpt))
+	config := DefaultProjectionConfig()
+	config.ConsumerGroup = "workers"
+	reg := registry.NewRegistry()
+	assert.NoError(t, reg.Register("OrderPlaced", func() any { return &struct{}{} }))
+
+	projections := make([]*MockProjection, 2)
+	for i := range projections {
+		manager, err := NewProjectionManagerWithConfig[int64](store.NewMemoryEventStore(), eventBus, reg, upcast.NewUpgraderRegistry(), config)
+		assert.NoError(t, err)
+		projections[i] = NewMockProjection("orders", []string{"OrderPlaced"})
+		assert.NoError(t, manager.RegisterProjection(projections[i]))
+		assert.NoError(t, manager.StartProjection("orders"))
+	}
+
+	for i := range 4 {
+		assert.NoError(t, eventBus.PublishEvent(context.Background(), eventing.NewEvent[int64](int64(i+1), "Order", "OrderPlaced", 1, nil)))
+	}
+	for _, p := range projections {
+		p.mu.Lock()
+		assert.Equal(t, 2, p.processedEvents)
+		p.mu.Unlock()
+	}
+
+	manager, err := NewProjectionManagerWithConfig[int64](store.NewMemoryEventStore(), &MockEventBus{}, registry.NewRegistry(), upcast.NewUpgraderRegistry(), config)
+	assert.NoError(t, err)
+	err = manager.RegisterProjection(NewMockProjection("orders", []string{"OrderPlaced"}))
+	assert.True(t, errors.Is(err, errors.Unsupported), "expected Unsupported, got: %v", err)
+}
+
+// TestProjectionManager_ProjectionLags 验证检查点延迟读取持久化检查点，未保存检查点时视为从头开始。
+func TestProjectionManager_ProjectionLags(t *testing.T) {
+	ctx := context.Background()
+	manager, err := NewProjectionManager[int64](store.NewMemoryEventStore(), &MockEventBus{}, registry.NewRegistry(), upcast.NewUpgraderRegistry())
+	assert.NoError(t, err)
+	assert.NoError(t, manager.RegisterProjection(NewMockProjection("orders", []string{"OrderPlaced"})))
+	assert.NoError(t, manager.RegisterProjection(NewMockProjection("billing", []string{"OrderPlaced"})))
+
+	checkpoints := NewMemoryCheckpointStore()
+	_, err = manager.WithCheckpointStore(checkpoints)
+	assert.NoError(t, err)
+	assert.NoError(t, checkpoints.Save(ctx, NewCheckpoint("orders", 5, "evt-5", time.Now().Add(-time.Minute))))
+
+	lags, err := manager.ProjectionLags(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, lags, 2)
+	assert.Equal(t, "billing", lags[0].Name)
+	assert.Zero(t, lags[0].Position)
+	assert.Zero(t, lags[0].Lag)
+	assert.Equal(t, "orders", lags[1].Name)
+	assert.Equal(t, int64(5), lags[1].Position)
+	assert.Equal(t, "evt-5", lags[1].LastEventID)
+	assert.GreaterOrEqual(t, lags[1].Lag, time.Minute)
+}
@@ -46,6 +46,11 @@ func (s *cursorGapEventStore) LoadEvents(ctx context.Context, aggregateID int64,
 	return nil, fmt.Errorf("not implemented")
 }
 
+// LoadEventsIter 以迭代器加载聚合事件。
+func (s *cursorGapEventStore) LoadEventsIter(ctx context.Context, aggregateID int64, afterVersion uint64) (store.IEventIterator[int64], error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
 // LoadEventsByType 加载指定聚合类型的事件。
 func (s *cursorGapEventStore) LoadEventsByType(
 	ctx context.Context,
@@ -66,6 +71,16 @@ func (s *cursorGapEventStore) GetAggregateVersion(ctx context.Context, aggregate
 	return 0, fmt.Errorf("not implemented")
 }
 
+// CountEvents 统计聚合事件数量。
+func (s *cursorGapEventStore) CountEvents(ctx context.Context, aggregateID int64) (uint64, error) {
+	return 0, fmt.Errorf("not implemented")
+}
+
+// HeadVersion 获取指定聚合类型下聚合的最新版本。
+func (s *cursorGapEventStore) HeadVersion(ctx context.Context, aggregateType string, aggregateID int64) (uint64, error) {
+	return 0, fmt.Errorf("not implemented")
+}
+
 // StreamAggregate 遍历指定聚合的事件流。
 //
 // 参数：
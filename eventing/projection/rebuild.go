@@ -56,7 +56,8 @@ func (pm *ProjectionManager[ID]) RebuildProjection(ctx context.Context, name str
 	var rebuildErr error
 	if checkpointStore != nil && len(events) > 0 {
 		lastEvent := events[len(events)-1]
-		checkpoint := NewCheckpoint(
+		checkpoint := NewCheckpointAt(
+			pm.clock().Now(),
 			name,
 			int64(len(events)),
 			lastEvent.ID,
@@ -74,11 +75,13 @@ func (pm *ProjectionManager[ID]) RebuildProjection(ctx context.Context, name str
 
 	if rebuildErr != nil {
 		rt.markError(rebuildErr)
+		pm.persistStatus(ctx, rt)
 		return gerrors.Wrap(rebuildErr, gerrors.Internal, "failed to rebuild projection").
 			WithContext("projection", name)
 	}
 
 	rt.updateAfterRebuild(events)
+	pm.persistStatus(ctx, rt)
 
 	pm.logger.Info(ctx, "projection rebuild completed",
 		logging.String("projection", name),
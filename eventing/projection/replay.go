@@ -21,6 +21,9 @@ func (pm *ProjectionManager[ID]) ResumeFromCheckpoint(ctx context.Context, proje
 		return gerrors.NewCode(gerrors.NotFound, "projection not found").
 			WithContext("projection", projectionName)
 	}
+	if err := pm.migrateRuntime(ctx, rt); err != nil {
+		return err
+	}
 	return pm.resumeRuntimeFromCheckpoint(ctx, rt, true)
 }
 
@@ -60,10 +63,11 @@ func (pm *ProjectionManager[ID]) resumeRuntimeFromCheckpoint(ctx context.Context
 		if shouldUseDurableCheckpoint(checkpoint, durable) {
 			checkpoint = durable
 			rt.prefillFromCheckpoint(checkpoint)
+			pm.restoreStatus(ctx, rt)
 		}
 	}
 	if checkpoint == nil {
-		checkpoint = NewCheckpoint(projectionName, 0, "", time.Time{})
+		checkpoint = NewCheckpointAt(pm.clock().Now(), projectionName, 0, "", time.Time{})
 		rt.prefillFromCheckpoint(checkpoint)
 	}
 
@@ -104,7 +108,7 @@ func (pm *ProjectionManager[ID]) loadCheckpoint(ctx context.Context, checkpointS
 		if gerrors.Is(err, gerrors.NotFound) {
 			pm.logger.Info(ctx, "checkpoint not found, starting from beginning",
 				logging.String("projection", projectionName))
-			return NewCheckpoint(projectionName, 0, "", time.Time{}), nil
+			return NewCheckpointAt(pm.clock().Now(), projectionName, 0, "", time.Time{}), nil
 		}
 		return nil, gerrors.Wrap(err, gerrors.Database, "failed to load checkpoint").
 			WithContext("projection", projectionName)
@@ -199,13 +203,17 @@ func (pm *ProjectionManager[ID]) fetchEventsForReplay(ctx context.Context, after
 
 // applyReplayEvent 应用Replay事件。
 func (pm *ProjectionManager[ID]) applyReplayEvent(ctx context.Context, rt *projectionRuntime[ID], evt eventing.IEvent) error {
-	_, err := pm.applyEventCommon(ctx, rt, evt, applyEventCommonOptions{
+	res, err := pm.applyEventCommon(ctx, rt, evt, applyEventCommonOptions{
 		requireRunning:          false,
 		enableRetry:             true,
 		allowCheckpoint:         true,
 		clearLastErrorOnSuccess: true,
+		deadLetter:              true,
 		upgradeLogMessage:       "projection replay event payload upgrade/hydrate failed",
 	})
+	if res.deadLettered {
+		return nil
+	}
 	return err
 }
 
@@ -4,6 +4,7 @@ import (
 	"sync"
 	"time"
 
+	"gochen/clock"
 	"gochen/eventing"
 )
 
@@ -14,13 +15,23 @@ type projectionRuntime[ID comparable] struct {
 	checkpoint checkpointState
 	cursor     *Checkpoint
 	active     bool
+	clock      clock.IClock
+
+	// failedDelivery 记录在线投递中连续失败的事件及其失败次数（由 execMu 保护）。
+	failedDelivery struct {
+		eventID  string
+		failures int
+	}
 
 	execMu  sync.Mutex
 	stateMu sync.RWMutex
 }
 
-func newProjectionRuntime[ID comparable](projection IProjection[ID]) *projectionRuntime[ID] {
-	now := time.Now()
+func newProjectionRuntime[ID comparable](projection IProjection[ID], clk clock.IClock) *projectionRuntime[ID] {
+	if clk == nil {
+		clk = clock.NewRealClock()
+	}
+	now := clk.Now()
 	return &projectionRuntime[ID]{
 		projection: projection,
 		status: &ProjectionStatus{
@@ -35,7 +46,24 @@ func newProjectionRuntime[ID comparable](projection IProjection[ID]) *projection
 			eventsSinceLastSave: 0,
 		},
 		active: true,
+		clock:  clk,
+	}
+}
+
+// recordFailedDelivery 累加事件的连续失败次数并返回累计值；换了事件则重新计数。调用方需持有 execMu。
+func (rt *projectionRuntime[ID]) recordFailedDelivery(eventID string) int {
+	if rt.failedDelivery.eventID != eventID {
+		rt.failedDelivery.eventID = eventID
+		rt.failedDelivery.failures = 0
 	}
+	rt.failedDelivery.failures++
+	return rt.failedDelivery.failures
+}
+
+// clearFailedDelivery 清除连续失败记录。调用方需持有 execMu。
+func (rt *projectionRuntime[ID]) clearFailedDelivery() {
+	rt.failedDelivery.eventID = ""
+	rt.failedDelivery.failures = 0
 }
 
 func (rt *projectionRuntime[ID]) statusCopy() *ProjectionStatus {
@@ -76,7 +104,7 @@ func (rt *projectionRuntime[ID]) markStopped() {
 	rt.stateMu.Lock()
 	defer rt.stateMu.Unlock()
 	rt.status.Status = "stopped"
-	rt.status.UpdatedAt = time.Now()
+	rt.status.UpdatedAt = rt.clock.Now()
 }
 
 func (rt *projectionRuntime[ID]) markRunning() {
@@ -86,7 +114,7 @@ func (rt *projectionRuntime[ID]) markRunning() {
 		return
 	}
 	rt.status.Status = "running"
-	rt.status.UpdatedAt = time.Now()
+	rt.status.UpdatedAt = rt.clock.Now()
 }
 
 func (rt *projectionRuntime[ID]) deactivate() {
@@ -94,14 +122,14 @@ func (rt *projectionRuntime[ID]) deactivate() {
 	defer rt.stateMu.Unlock()
 	rt.active = false
 	rt.status.Status = "stopped"
-	rt.status.UpdatedAt = time.Now()
+	rt.status.UpdatedAt = rt.clock.Now()
 }
 
 func (rt *projectionRuntime[ID]) markRebuilding() {
 	rt.stateMu.Lock()
 	defer rt.stateMu.Unlock()
 	rt.status.Status = "rebuilding"
-	rt.status.UpdatedAt = time.Now()
+	rt.status.UpdatedAt = rt.clock.Now()
 }
 
 func (rt *projectionRuntime[ID]) markError(err error) {
@@ -111,7 +139,7 @@ func (rt *projectionRuntime[ID]) markError(err error) {
 	if err != nil {
 		rt.status.LastError = err.Error()
 	}
-	rt.status.UpdatedAt = time.Now()
+	rt.status.UpdatedAt = rt.clock.Now()
 }
 
 func (rt *projectionRuntime[ID]) prefillFromCheckpoint(checkpoint *Checkpoint) {
@@ -125,12 +153,26 @@ func (rt *projectionRuntime[ID]) prefillFromCheckpoint(checkpoint *Checkpoint) {
 	rt.status.ProcessedEvents = checkpoint.Position
 	rt.status.Status = "stopped"
 	rt.status.LastError = ""
-	rt.status.UpdatedAt = time.Now()
+	rt.status.UpdatedAt = rt.clock.Now()
 	rt.cursor = checkpoint.Clone()
-	rt.checkpoint.lastSaveTime = time.Now()
+	rt.checkpoint.lastSaveTime = rt.clock.Now()
 	rt.checkpoint.eventsSinceLastSave = 0
 }
 
+// restoreStatus 回填持久化的运维历史（失败计数、最近错误、创建时间）；处理位置仍以检查点为准。
+func (rt *projectionRuntime[ID]) restoreStatus(stored *ProjectionStatus) {
+	if stored == nil {
+		return
+	}
+	rt.stateMu.Lock()
+	defer rt.stateMu.Unlock()
+	rt.status.FailedEvents = stored.FailedEvents
+	rt.status.LastError = stored.LastError
+	if !stored.CreatedAt.IsZero() {
+		rt.status.CreatedAt = stored.CreatedAt
+	}
+}
+
 func (rt *projectionRuntime[ID]) updateAfterRebuild(events []eventing.Event[ID]) {
 	rt.stateMu.Lock()
 	defer rt.stateMu.Unlock()
@@ -145,9 +187,9 @@ func (rt *projectionRuntime[ID]) updateAfterRebuild(events []eventing.Event[ID])
 		rt.status.LastEventID = ""
 		rt.status.LastEventTime = time.Time{}
 	}
-	rt.status.UpdatedAt = time.Now()
-	rt.cursor = NewCheckpoint(rt.status.Name, rt.status.ProcessedEvents, rt.status.LastEventID, rt.status.LastEventTime)
-	rt.checkpoint.lastSaveTime = time.Now()
+	rt.status.UpdatedAt = rt.clock.Now()
+	rt.cursor = NewCheckpointAt(rt.clock.Now(), rt.status.Name, rt.status.ProcessedEvents, rt.status.LastEventID, rt.status.LastEventTime)
+	rt.checkpoint.lastSaveTime = rt.clock.Now()
 	rt.checkpoint.eventsSinceLastSave = 0
 }
 
@@ -169,7 +211,26 @@ func (rt *projectionRuntime[ID]) shouldSaveCheckpointAfterEvent(config *Projecti
 	}
 	rt.stateMu.RLock()
 	defer rt.stateMu.RUnlock()
-	return shouldSaveCheckpointAfterEventState(rt.checkpoint, config)
+	return shouldSaveCheckpointAfterEventState(rt.checkpoint, config, rt.clock.Now())
+}
+
+// pendingCheckpoint 返回尚未持久化的游标；force 为 false 时仅在距上次保存超过 CheckpointSaveInterval 后返回。
+func (rt *projectionRuntime[ID]) pendingCheckpoint(config *ProjectionConfig, force bool) *Checkpoint {
+	rt.stateMu.RLock()
+	defer rt.stateMu.RUnlock()
+	if rt.cursor == nil || rt.checkpoint.eventsSinceLastSave == 0 {
+		return nil
+	}
+	if !force && config != nil && rt.clock.Now().Sub(rt.checkpoint.lastSaveTime) < config.CheckpointSaveInterval {
+		return nil
+	}
+	return rt.cursor.Clone()
+}
+
+func (rt *projectionRuntime[ID]) markCheckpointSaved() {
+	rt.stateMu.Lock()
+	defer rt.stateMu.Unlock()
+	updateCheckpointTrackerState(&rt.checkpoint, true, rt.clock.Now())
 }
 
 func (rt *projectionRuntime[ID]) recordApplyResult(
@@ -178,6 +239,7 @@ func (rt *projectionRuntime[ID]) recordApplyResult(
 	clearLastErrorOnSuccess bool,
 	nextCursor *Checkpoint,
 	checkpointSaved bool,
+	parked bool,
 ) applyEventCommonResult {
 	var res applyEventCommonResult
 	if rt == nil {
@@ -189,21 +251,23 @@ func (rt *projectionRuntime[ID]) recordApplyResult(
 	if rt.status == nil {
 		return res
 	}
-	now := time.Now()
+	now := rt.clock.Now()
 	if err != nil {
 		rt.status.FailedEvents++
 		rt.status.LastError = err.Error()
-	} else {
+	}
+	if err == nil || parked {
+		// 进入死信队列的事件同样推进位置（ProcessedEvents 与游标保持一致），检查点随下一次保存落盘。
 		rt.status.ProcessedEvents++
 		rt.status.LastEventID = evt.GetID()
 		rt.status.LastEventTime = evt.GetTimestamp()
-		if clearLastErrorOnSuccess {
+		if err == nil && clearLastErrorOnSuccess {
 			rt.status.LastError = ""
 		}
 		if nextCursor != nil {
 			rt.cursor = nextCursor.Clone()
 		}
-		updateCheckpointTrackerState(&rt.checkpoint, checkpointSaved)
+		updateCheckpointTrackerState(&rt.checkpoint, err == nil && checkpointSaved, now)
 	}
 	rt.status.UpdatedAt = now
 	res.processedEvents = rt.status.ProcessedEvents
@@ -0,0 +1,332 @@
+// Package sqlprojection 把类型化事件处理函数映射为 SQL 读模型表的 upsert/delete，常见的反范式化投影无需手写 SQL。
+//
+// 读模型表除业务列外需包含两列幂等元数据（列名见 ColumnLastEventID / ColumnLastEventVersion）：
+//
+//	last_event_id      VARCHAR(255) NOT NULL DEFAULT ''
+//	last_event_version BIGINT       NOT NULL DEFAULT 0
+//
+// 每行记录最后一次写入它的事件；事件版本不大于行上记录的版本时写入被跳过，
+// 因此事件重复投递或从检查点回放不会重复累加或回退读模型。版本取聚合事件流内的序号，
+// 适用于一行对应一个聚合（行主键即聚合 ID 或由其派生）的读模型。
+//
+// 删除行时在墓碑表（DefaultTombstoneTable，可用 Writer.CreateTombstoneTable 创建）记录删除事件的版本，
+// 此后同一行版本不大于墓碑的写入被跳过，删除后重复投递的旧事件不会让行“复活”：
+//
+//	table_name         VARCHAR(191) NOT NULL
+//	row_key            VARCHAR(191) NOT NULL  -- 主键值的 JSON 数组
+//	last_event_id      VARCHAR(255) NOT NULL DEFAULT ''
+//	last_event_version BIGINT       NOT NULL DEFAULT 0
+//	PRIMARY KEY (table_name, row_key)
+//
+// 写入优先复用 ctx 中的 ORM 事务 session（见 projection.NewCheckpointingProjector），与检查点同一提交边界。
+package sqlprojection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"gochen/db"
+	"gochen/db/dialect"
+	"gochen/db/orm"
+	"gochen/db/sql/safeident"
+	"gochen/db/sql/sqlbuilder"
+	"gochen/domain"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/projection"
+)
+
+const (
+	// ColumnLastEventID 记录最后写入该行的事件 ID。
+	ColumnLastEventID = "last_event_id"
+	// ColumnLastEventVersion 记录最后写入该行的事件版本，用于幂等判断。
+	ColumnLastEventVersion = "last_event_version"
+
+	// DefaultTombstoneTable 是记录已删除行版本的墓碑表。
+	DefaultTombstoneTable = "read_model_tombstones"
+)
+
+// tombstones 描述墓碑表：按“读模型表 + 行主键”记录删除事件的版本。
+var tombstones = Table{Name: DefaultTombstoneTable, Key: []string{"table_name", "row_key"}}
+
+// Row 是读模型的一行（列名 -> 值）。
+type Row map[string]any
+
+// Table 描述读模型表。
+type Table struct {
+	// Name 是表名。
+	Name string
+	// Key 是主键列；Row 中必须包含全部主键列。
+	Key []string
+}
+
+// Writer 按事件把行写入读模型表。
+type Writer struct {
+	db      db.IDatabase
+	dialect dialect.Dialect
+}
+
+// NewWriter 创建读模型写入器。
+func NewWriter(database db.IDatabase) (*Writer, error) {
+	if database == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "database cannot be nil")
+	}
+	return &Writer{db: database, dialect: dialect.FromDatabase(database)}, nil
+}
+
+// Upsert 把事件映射为对 table 的 upsert：行不存在时插入，存在且版本较旧时更新 Row 中的非主键列。
+//
+// 聚合 ID 类型 ID 与载荷类型 T 均从 fn 的参数推断；fn 返回 nil 表示该事件不写入。
+func Upsert[ID comparable, T domain.IDomainEvent](w *Writer, table Table, fn func(aggregateID ID, e T) Row) projection.EventHandler {
+	return handler(w, table, fn, false)
+}
+
+// Delete 把事件映射为对 table 的删除：fn 返回待删除行的主键列；仅删除版本较旧的行，并记录墓碑。
+func Delete[ID comparable, T domain.IDomainEvent](w *Writer, table Table, fn func(aggregateID ID, e T) Row) projection.EventHandler {
+	return handler(w, table, fn, true)
+}
+
+func handler[ID comparable, T domain.IDomainEvent](w *Writer, table Table, fn func(aggregateID ID, e T) Row, remove bool) projection.EventHandler {
+	if w == nil || fn == nil {
+		return projection.HandlerWithEvent[T](nil)
+	}
+	return projection.HandlerWithEvent(func(ctx context.Context, evt eventing.IEvent, e T) error {
+		typed, ok := evt.(eventing.ITypedEvent[ID])
+		if !ok {
+			return errors.NewCode(errors.InvalidInput, "event aggregate id type mismatch").
+				WithContext("event_type", evt.GetType()).
+				WithContext("event_go_type", fmt.Sprintf("%T", evt))
+		}
+		row := fn(typed.GetAggregateID(), e)
+		if row == nil {
+			return nil
+		}
+		if remove {
+			return w.Delete(ctx, table, evt, row)
+		}
+		return w.Upsert(ctx, table, evt, row)
+	})
+}
+
+// Upsert 以 evt 为版本依据写入一行；evt 不比行上记录的事件（或该行的删除墓碑）新时跳过。
+func (w *Writer) Upsert(ctx context.Context, table Table, evt eventing.IEvent, row Row) error {
+	return w.upsert(ctx, table, evt, row, true)
+}
+
+// upsert 写入一行；checkTombstone 为 true 时插入前比对墓碑版本。
+func (w *Writer) upsert(ctx context.Context, table Table, evt eventing.IEvent, row Row, checkTombstone bool) error {
+	keyCond, keyArgs, err := w.keyCondition(table, row)
+	if err != nil {
+		return err
+	}
+	sq, err := sqlbuilder.New(w.databaseFor(ctx))
+	if err != nil {
+		return errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+	version := evt.GetVersion()
+
+	values := make(map[string]any, len(row)+2)
+	for col, val := range row {
+		if !slices.Contains(table.Key, col) {
+			values[col] = val
+		}
+	}
+	values[ColumnLastEventID] = evt.GetID()
+	values[ColumnLastEventVersion] = version
+
+	// 先做带版本条件的更新；未命中再区分“行不存在”（插入）与“行已是更新版本”（跳过）。
+	// 不依赖唯一键冲突：Postgres 事务内的失败语句会中止整个事务。
+	res, err := sq.Update(table.Name).
+		SetMap(values).
+		Where(keyCond, keyArgs...).
+		Where(w.dialect.QuoteIdentifier(ColumnLastEventVersion)+" < ?", version).
+		Exec(ctx)
+	if err != nil {
+		return w.wrap(err, table, evt)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected > 0 {
+		return nil
+	}
+
+	exists, err := w.exists(ctx, sq, table, keyCond, keyArgs)
+	if err != nil {
+		return w.wrap(err, table, evt)
+	}
+	if exists {
+		return nil
+	}
+	if checkTombstone {
+		deleted, err := w.deletedSince(ctx, sq, table, row, version)
+		if err != nil {
+			return w.wrap(err, table, evt)
+		}
+		if deleted {
+			return nil
+		}
+	}
+
+	for col, val := range row {
+		values[col] = val
+	}
+	columns := make([]string, 0, len(values))
+	for col := range values {
+		columns = append(columns, col)
+	}
+	sort.Strings(columns)
+	args := make([]any, 0, len(columns))
+	for _, col := range columns {
+		args = append(args, values[col])
+	}
+	if _, err := sq.InsertInto(table.Name).Columns(columns...).Values(args...).Exec(ctx); err != nil {
+		return w.wrap(err, table, evt)
+	}
+	return nil
+}
+
+// Delete 删除主键匹配且版本早于 evt 的行，并以 evt 的版本记录墓碑，阻止旧事件重新插入该行。
+func (w *Writer) Delete(ctx context.Context, table Table, evt eventing.IEvent, key Row) error {
+	keyCond, keyArgs, err := w.keyCondition(table, key)
+	if err != nil {
+		return err
+	}
+	rowKey, err := encodeRowKey(table, key)
+	if err != nil {
+		return err
+	}
+	sq, err := sqlbuilder.New(w.databaseFor(ctx))
+	if err != nil {
+		return errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+	if _, err := sq.DeleteFrom(table.Name).
+		Where(keyCond, keyArgs...).
+		Where(w.dialect.QuoteIdentifier(ColumnLastEventVersion)+" < ?", evt.GetVersion()).
+		Exec(ctx); err != nil {
+		return w.wrap(err, table, evt)
+	}
+	return w.upsert(ctx, tombstones, evt, Row{"table_name": table.Name, "row_key": rowKey}, false)
+}
+
+// deletedSince 判断该行是否已被不早于 version 的事件删除。
+func (w *Writer) deletedSince(ctx context.Context, sq sqlbuilder.ISql, table Table, row Row, version uint64) (bool, error) {
+	rowKey, err := encodeRowKey(table, row)
+	if err != nil {
+		return false, err
+	}
+	rows, err := sq.Select(ColumnLastEventVersion).From(tombstones.Name).
+		Where("table_name = ? AND row_key = ?", table.Name, rowKey).
+		Where(w.dialect.QuoteIdentifier(ColumnLastEventVersion)+" >= ?", version).
+		Limit(1).
+		Query(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	found := rows.Next()
+	return found, rows.Err()
+}
+
+// encodeRowKey 按 table.Key 的顺序把主键值编码为 JSON 数组，作为墓碑的行标识。
+func encodeRowKey(table Table, row Row) (string, error) {
+	values := make([]any, 0, len(table.Key))
+	for _, col := range table.Key {
+		values = append(values, row[col])
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", errors.Wrap(err, errors.InvalidInput, "read model key cannot be encoded").
+			WithContext("table", table.Name)
+	}
+	return string(data), nil
+}
+
+// CreateTombstoneTable 幂等创建墓碑表；也可由迁移按包文档的表结构创建。
+func (w *Writer) CreateTombstoneTable(ctx context.Context) error {
+	if _, err := w.db.Exec(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+table_name VARCHAR(191) NOT NULL,
+row_key VARCHAR(191) NOT NULL,
+last_event_id VARCHAR(255) NOT NULL DEFAULT '',
+last_event_version BIGINT NOT NULL DEFAULT 0,
+PRIMARY KEY (table_name, row_key)
+)`, tombstones.Name)); err != nil {
+		return errors.NewCodeWithCause(errors.Database, "failed to create read model tombstone table", err).
+			WithContext("table", tombstones.Name)
+	}
+	return nil
+}
+
+// Reset 返回清空给定表（及其墓碑）的函数，用作 projection.Builder.OnRebuildReset。
+func (w *Writer) Reset(tables ...Table) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		sq, err := sqlbuilder.New(w.databaseFor(ctx))
+		if err != nil {
+			return errors.Wrap(err, errors.Internal, "failed to create sql builder")
+		}
+		for _, table := range tables {
+			if _, err := sq.DeleteFrom(table.Name).Exec(ctx); err != nil {
+				return errors.NewCodeWithCause(errors.Database, "failed to reset read model table", err).
+					WithContext("table", table.Name)
+			}
+			if _, err := sq.DeleteFrom(tombstones.Name).Where("table_name = ?", table.Name).Exec(ctx); err != nil {
+				return errors.NewCodeWithCause(errors.Database, "failed to reset read model tombstones", err).
+					WithContext("table", table.Name)
+			}
+		}
+		return nil
+	}
+}
+
+func (w *Writer) exists(ctx context.Context, sq sqlbuilder.ISql, table Table, keyCond string, keyArgs []any) (bool, error) {
+	rows, err := sq.Select(ColumnLastEventVersion).From(table.Name).Where(keyCond, keyArgs...).Limit(1).Query(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	found := rows.Next()
+	return found, rows.Err()
+}
+
+// keyCondition 校验主键列并生成 WHERE 条件。
+func (w *Writer) keyCondition(table Table, row Row) (string, []any, error) {
+	if len(table.Key) == 0 {
+		return "", nil, errors.NewCode(errors.InvalidInput, "read model table key is required").
+			WithContext("table", table.Name)
+	}
+	parts := make([]string, 0, len(table.Key))
+	args := make([]any, 0, len(table.Key))
+	for _, col := range table.Key {
+		if !safeident.IsSafeIdentifier(col) {
+			return "", nil, errors.NewCode(errors.InvalidInput, "unsafe key column").
+				WithContext("table", table.Name).
+				WithContext("column", col)
+		}
+		val, ok := row[col]
+		if !ok {
+			return "", nil, errors.NewCode(errors.InvalidInput, "row is missing key column").
+				WithContext("table", table.Name).
+				WithContext("column", col)
+		}
+		parts = append(parts, w.dialect.QuoteIdentifier(col)+" = ?")
+		args = append(args, val)
+	}
+	return strings.Join(parts, " AND "), args, nil
+}
+
+func (w *Writer) databaseFor(ctx context.Context) db.IDatabase {
+	if session, ok := orm.SessionFromContext(ctx); ok && session != nil {
+		if database := session.Database(); database != nil {
+			return database
+		}
+	}
+	return w.db
+}
+
+func (w *Writer) wrap(err error, table Table, evt eventing.IEvent) error {
+	return errors.NewCodeWithCause(errors.Database, "read model write failed", err).
+		WithContext("table", table.Name).
+		WithContext("event_id", evt.GetID()).
+		WithContext("event_type", evt.GetType())
+}
@@ -0,0 +1,151 @@
+package sqlprojection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"gochen/db"
+	basicdb "gochen/db/sql/stdsql"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/projection"
+)
+
+type orderPlaced struct{ Total int }
+
+func (*orderPlaced) EventType() string { return "OrderPlaced" }
+
+type orderShipped struct{ Carrier string }
+
+func (*orderShipped) EventType() string { return "OrderShipped" }
+
+type orderCancelled struct{}
+
+func (*orderCancelled) EventType() string { return "OrderCancelled" }
+
+var orders = Table{Name: "order_view", Key: []string{"id"}}
+
+func newTestDB(t *testing.T) db.IDatabase {
+	t.Helper()
+	database, err := basicdb.New(db.DBConfig{Driver: "sqlite", Database: ":memory:", MaxOpenConns: 1, MaxIdleConns: 1})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = database.Close() })
+	_, err = database.Exec(context.Background(), `
+		CREATE TABLE order_view (
+			id INTEGER PRIMARY KEY,
+			total INTEGER NOT NULL DEFAULT 0,
+			carrier TEXT NOT NULL DEFAULT '',
+			last_event_id TEXT NOT NULL DEFAULT '',
+			last_event_version INTEGER NOT NULL DEFAULT 0
+		)`)
+	require.NoError(t, err)
+	w, err := NewWriter(database)
+	require.NoError(t, err)
+	require.NoError(t, w.CreateTombstoneTable(context.Background()))
+	return database
+}
+
+func newOrderView(t *testing.T, database db.IDatabase) projection.IProjection[int64] {
+	t.Helper()
+	w, err := NewWriter(database)
+	require.NoError(t, err)
+	p, err := projection.New[int64]("order_view").
+		On(Upsert(w, orders, func(id int64, e *orderPlaced) Row {
+			return Row{"id": id, "total": e.Total}
+		})).
+		On(Upsert(w, orders, func(id int64, e *orderShipped) Row {
+			return Row{"id": id, "carrier": e.Carrier}
+		})).
+		On(Delete(w, orders, func(id int64, _ *orderCancelled) Row {
+			return Row{"id": id}
+		})).
+		OnRebuildReset(w.Reset(orders)).
+		Build()
+	require.NoError(t, err)
+	return p
+}
+
+type orderRow struct {
+	total   int
+	carrier string
+	version int64
+}
+
+func loadOrder(t *testing.T, database db.IDatabase, id int64) (orderRow, bool) {
+	t.Helper()
+	rows, err := database.Query(context.Background(), `SELECT total, carrier, last_event_version FROM order_view WHERE id = ?`, id)
+	require.NoError(t, err)
+	defer rows.Close()
+	if !rows.Next() {
+		return orderRow{}, false
+	}
+	var row orderRow
+	require.NoError(t, rows.Scan(&row.total, &row.carrier, &row.version))
+	return row, true
+}
+
+func TestProjection_UpsertIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	database := newTestDB(t)
+	p := newOrderView(t, database)
+
+	placed := eventing.NewEvent[int64](1, "Order", "OrderPlaced", 1, &orderPlaced{Total: 10})
+	shipped := eventing.NewEvent[int64](1, "Order", "OrderShipped", 2, &orderShipped{Carrier: "ups"})
+	require.NoError(t, p.Handle(ctx, placed))
+	require.NoError(t, p.Handle(ctx, shipped))
+	// 重复投递与乱序的旧事件都不会回退读模型。
+	require.NoError(t, p.Handle(ctx, shipped))
+	require.NoError(t, p.Handle(ctx, eventing.NewEvent[int64](1, "Order", "OrderPlaced", 1, &orderPlaced{Total: 99})))
+
+	row, ok := loadOrder(t, database, 1)
+	require.True(t, ok)
+	require.Equal(t, orderRow{total: 10, carrier: "ups", version: 2}, row)
+
+	require.NoError(t, p.Handle(ctx, eventing.NewEvent[int64](1, "Order", "OrderCancelled", 3, &orderCancelled{})))
+	_, ok = loadOrder(t, database, 1)
+	require.False(t, ok)
+
+	// 删除后重复投递的旧事件不会让行复活；更新版本的事件仍可重新插入。
+	require.NoError(t, p.Handle(ctx, shipped))
+	_, ok = loadOrder(t, database, 1)
+	require.False(t, ok, "stale events must not resurrect a deleted row")
+	require.NoError(t, p.Handle(ctx, eventing.NewEvent[int64](1, "Order", "OrderPlaced", 4, &orderPlaced{Total: 5})))
+	row, ok = loadOrder(t, database, 1)
+	require.True(t, ok)
+	require.Equal(t, 5, row.total)
+}
+
+func TestProjection_RebuildResetsTables(t *testing.T) {
+	ctx := context.Background()
+	database := newTestDB(t)
+	p := newOrderView(t, database)
+
+	require.NoError(t, p.Handle(ctx, eventing.NewEvent[int64](7, "Order", "OrderPlaced", 5, &orderPlaced{Total: 1})))
+	require.NoError(t, p.Rebuild(ctx, []eventing.Event[int64]{
+		*eventing.NewEvent[int64](2, "Order", "OrderPlaced", 1, &orderPlaced{Total: 3}),
+	}))
+
+	_, ok := loadOrder(t, database, 7)
+	require.False(t, ok, "rows not produced by the replayed events are removed")
+	row, ok := loadOrder(t, database, 2)
+	require.True(t, ok)
+	require.Equal(t, 3, row.total)
+}
+
+func TestWriter_Validation(t *testing.T) {
+	ctx := context.Background()
+	w, err := NewWriter(newTestDB(t))
+	require.NoError(t, err)
+	evt := eventing.NewEvent[int64](1, "Order", "OrderPlaced", 1, nil)
+
+	err = w.Upsert(ctx, orders, evt, Row{"total": 1})
+	require.True(t, errors.Is(err, errors.InvalidInput), "rows must contain the key columns")
+	err = w.Upsert(ctx, Table{Name: "order_view"}, evt, Row{"id": 1})
+	require.True(t, errors.Is(err, errors.InvalidInput), "tables must declare a key")
+
+	_, err = NewWriter(nil)
+	require.True(t, errors.Is(err, errors.InvalidInput))
+}
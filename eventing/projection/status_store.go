@@ -0,0 +1,71 @@
+package projection
+
+import (
+	"context"
+
+	gerrors "gochen/errors"
+	"gochen/logging"
+)
+
+// IProjectionStatusStore 表示可以持久化投影运行状态的检查点存储能力。
+//
+// 说明：
+//   - 检查点存储实现该接口时，ProjectionManager 在保存检查点或处理失败后同步记录状态，
+//     恢复时回填失败计数、最近错误与创建时间，使运维历史跨重启保留；
+//   - 状态写入为尽力而为：失败只记录日志，不影响事件处理与检查点推进；
+//   - ProcessedEvents 以检查点位置为准，恢复时不会用已记录的状态覆盖。
+type IProjectionStatusStore interface {
+	// LoadProjectionStatus 读取已记录的投影状态；未记录时返回 errors.NotFound。
+	LoadProjectionStatus(ctx context.Context, projectionName string) (*ProjectionStatus, error)
+
+	// SaveProjectionStatus 以 UPSERT 语义记录投影状态。
+	SaveProjectionStatus(ctx context.Context, status *ProjectionStatus) error
+}
+
+// statusStore 返回检查点存储上的状态持久化能力；未配置或不支持时返回 nil。
+func (pm *ProjectionManager[ID]) statusStore() IProjectionStatusStore {
+	pm.mutex.RLock()
+	checkpointStore := pm.checkpointStore
+	pm.mutex.RUnlock()
+	statusStore, ok := checkpointStore.(IProjectionStatusStore)
+	if !ok {
+		return nil
+	}
+	return statusStore
+}
+
+// persistStatus 把运行时状态写入检查点存储（尽力而为）。
+func (pm *ProjectionManager[ID]) persistStatus(ctx context.Context, rt *projectionRuntime[ID]) {
+	statusStore := pm.statusStore()
+	if statusStore == nil {
+		return
+	}
+	status := rt.statusCopy()
+	if status == nil {
+		return
+	}
+	if err := statusStore.SaveProjectionStatus(ctx, status); err != nil {
+		pm.logger.Warn(ctx, "failed to persist projection status",
+			logging.String("projection", status.Name),
+			logging.Error(err))
+	}
+}
+
+// restoreStatus 从检查点存储回填上次运行记录的状态；未记录或读取失败时保持运行时状态不变。
+func (pm *ProjectionManager[ID]) restoreStatus(ctx context.Context, rt *projectionRuntime[ID]) {
+	statusStore := pm.statusStore()
+	if statusStore == nil {
+		return
+	}
+	name := rt.projection.Name()
+	stored, err := statusStore.LoadProjectionStatus(ctx, name)
+	if err != nil {
+		if !gerrors.Is(err, gerrors.NotFound) {
+			pm.logger.Warn(ctx, "failed to load projection status",
+				logging.String("projection", name),
+				logging.Error(err))
+		}
+		return
+	}
+	rt.restoreStatus(stored)
+}
@@ -0,0 +1,115 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gerrors "gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/store"
+	"gochen/eventing/upcast"
+)
+
+func newStatusTestManager(t *testing.T, eventStore *store.MemoryEventStore, checkpointStore ICheckpointStore) *ProjectionManager[int64] {
+	t.Helper()
+	manager, err := NewProjectionManagerWithConfig[int64](eventStore, &MockEventBus{}, newTestRegistry(t), upcast.NewUpgraderRegistry(), &ProjectionConfig{
+		CheckpointSaveCount: 1,
+	})
+	require.NoError(t, err)
+	manager, err = manager.WithCheckpointStore(checkpointStore)
+	require.NoError(t, err)
+	return manager
+}
+
+// TestProjectionStatus_SurvivesRestart 验证失败计数与创建时间在管理器重建（模拟进程重启）后保留。
+func TestProjectionStatus_SurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewMemoryEventStore()
+	checkpointStore := NewMemoryCheckpointStore()
+
+	events := []eventing.Event[int64]{
+		*eventing.NewEvent[int64](1, "Agg", "TestEvent", 1, map[string]any{"i": 1}),
+		*eventing.NewEvent[int64](1, "Agg", "TestEvent", 2, map[string]any{"i": 2}),
+	}
+	require.NoError(t, eventStore.AppendEvents(ctx, 1, toStorableEvents(events), 0))
+
+	first := newStatusTestManager(t, eventStore, checkpointStore)
+	failing := NewMockProjection("orders", []string{"TestEvent"})
+	failing.handleFunc = func(_ context.Context, evt eventing.IEvent) error {
+		if evt.GetID() == events[1].ID {
+			return errors.New("read model unavailable")
+		}
+		return nil
+	}
+	require.NoError(t, first.RegisterProjection(failing))
+	require.Error(t, first.ResumeFromCheckpoint(ctx, "orders"))
+
+	stored, err := checkpointStore.LoadProjectionStatus(ctx, "orders")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stored.ProcessedEvents)
+	assert.Equal(t, int64(1), stored.FailedEvents)
+	assert.Equal(t, "read model unavailable", stored.LastError)
+	createdAt := stored.CreatedAt
+
+	second := newStatusTestManager(t, eventStore, checkpointStore)
+	require.NoError(t, second.RegisterProjection(NewMockProjection("orders", []string{"TestEvent"})))
+	require.NoError(t, second.ResumeFromCheckpoint(ctx, "orders"))
+
+	status, err := second.ProjectionStatus("orders")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), status.ProcessedEvents)
+	assert.Equal(t, int64(1), status.FailedEvents, "failure history is restored from the status store")
+	assert.Empty(t, status.LastError, "a successful replay clears the last error")
+	assert.True(t, status.CreatedAt.Equal(createdAt))
+
+	stored, err = checkpointStore.LoadProjectionStatus(ctx, "orders")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stored.ProcessedEvents)
+	assert.Equal(t, int64(1), stored.FailedEvents)
+}
+
+// TestSQLCheckpointStore_ProjectionStatus 验证 SQL 存储的状态读写。
+func TestSQLCheckpointStore_ProjectionStatus(t *testing.T) {
+	ctx := context.Background()
+	checkpointStore := NewSQLCheckpointStore(newProjectionCheckpointTestDB(t), "projection_checkpoints")
+	require.NoError(t, checkpointStore.CreateTable(ctx))
+
+	_, err := checkpointStore.LoadProjectionStatus(ctx, "orders")
+	assert.True(t, gerrors.Is(err, gerrors.NotFound))
+
+	createdAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, checkpointStore.SaveProjectionStatus(ctx, &ProjectionStatus{
+		Name:            "orders",
+		Status:          "error",
+		ProcessedEvents: 10,
+		FailedEvents:    2,
+		LastError:       "boom",
+		CreatedAt:       createdAt,
+	}))
+	require.NoError(t, checkpointStore.SaveProjectionStatus(ctx, &ProjectionStatus{
+		Name:            "orders",
+		Status:          "running",
+		ProcessedEvents: 12,
+		FailedEvents:    3,
+		LastEventID:     "evt-12",
+		LastEventTime:   createdAt.Add(time.Hour),
+		CreatedAt:       createdAt,
+	}))
+
+	status, err := checkpointStore.LoadProjectionStatus(ctx, "orders")
+	require.NoError(t, err)
+	assert.Equal(t, "running", status.Status)
+	assert.Equal(t, int64(12), status.ProcessedEvents)
+	assert.Equal(t, int64(3), status.FailedEvents)
+	assert.Empty(t, status.LastError)
+	assert.Equal(t, "evt-12", status.LastEventID)
+	assert.True(t, status.LastEventTime.Equal(createdAt.Add(time.Hour)))
+	assert.True(t, status.CreatedAt.Equal(createdAt))
+
+	assert.True(t, gerrors.Is(checkpointStore.SaveProjectionStatus(ctx, &ProjectionStatus{}), gerrors.InvalidInput))
+}
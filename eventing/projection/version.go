@@ -0,0 +1,177 @@
+package projection
+
+import (
+	"context"
+	"time"
+
+	gerrors "gochen/errors"
+	"gochen/logging"
+)
+
+// MigrationAction 表示读模型迁移钩子执行后对检查点的处理方式。
+type MigrationAction string
+
+const (
+	// MigrationKeepCheckpoint 表示迁移已就地完成（如补列），继续沿用现有检查点。
+	MigrationKeepCheckpoint MigrationAction = "keep"
+	// MigrationReplay 表示迁移清空了读模型（如 truncate），需要删除检查点并在恢复时从头回放。
+	MigrationReplay MigrationAction = "replay"
+)
+
+// IVersionedProjection 表示声明了读模型版本的投影。
+//
+// 说明：
+//   - 版本号从 1 开始单调递增；检查点存储中没有版本记录时视为 0（首次部署或接入版本化之前）；
+//   - 当声明版本与已记录版本不同时，ProjectionManager 会先调用 MigrateReadModel，再记录新版本；
+//   - 声明版本低于已记录版本（回滚部署）会被拒绝，避免旧逻辑写入新结构的读模型。
+type IVersionedProjection interface {
+	// ProjectionVersion 返回当前投影逻辑对应的读模型版本。
+	ProjectionVersion() int
+
+	// MigrateReadModel 把读模型从 fromVersion 迁移到 toVersion（改表结构、清空数据等）。
+	MigrateReadModel(ctx context.Context, fromVersion, toVersion int) (MigrationAction, error)
+}
+
+// IProjectionVersionStore 表示可以持久化投影读模型版本的检查点存储能力。
+type IProjectionVersionStore interface {
+	// LoadProjectionVersion 读取已记录的版本；未记录时返回 errors.NotFound。
+	LoadProjectionVersion(ctx context.Context, projectionName string) (int, error)
+
+	// SaveProjectionVersion 以 UPSERT 语义记录投影版本。
+	SaveProjectionVersion(ctx context.Context, projectionName string, version int) error
+}
+
+// MigrateProjection 在投影声明版本发生变化时执行读模型迁移，并记录新版本。
+//
+// 说明：
+//   - 未实现 IVersionedProjection 的投影直接返回 nil；
+//   - 版本化投影要求检查点存储实现 IProjectionVersionStore；
+//   - ResumeFromCheckpoint 会自动先执行本方法，保证回放发生在迁移之后。
+func (pm *ProjectionManager[ID]) MigrateProjection(ctx context.Context, name string) error {
+	if ctx == nil {
+		return gerrors.NewCode(gerrors.InvalidInput, "ctx is nil")
+	}
+	rt, exists := pm.runtime(name)
+	if !exists {
+		return gerrors.NewCode(gerrors.NotFound, "projection not found").
+			WithContext("projection", name)
+	}
+	return pm.migrateRuntime(ctx, rt)
+}
+
+// MigrateProjections 依次对全部已注册投影执行 MigrateProjection。
+func (pm *ProjectionManager[ID]) MigrateProjections(ctx context.Context) error {
+	if ctx == nil {
+		return gerrors.NewCode(gerrors.InvalidInput, "ctx is nil")
+	}
+	pm.mutex.RLock()
+	runtimes := make([]*projectionRuntime[ID], 0, len(pm.runtimes))
+	for _, rt := range pm.runtimes {
+		runtimes = append(runtimes, rt)
+	}
+	pm.mutex.RUnlock()
+
+	for _, rt := range runtimes {
+		if err := pm.migrateRuntime(ctx, rt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (pm *ProjectionManager[ID]) migrateRuntime(ctx context.Context, rt *projectionRuntime[ID]) error {
+	if rt == nil || rt.projection == nil {
+		return gerrors.NewCode(gerrors.NotFound, "projection not found")
+	}
+	versioned, ok := rt.projection.(IVersionedProjection)
+	if !ok {
+		return nil
+	}
+	name := rt.projection.Name()
+	target := versioned.ProjectionVersion()
+	if target < 1 {
+		return gerrors.NewCode(gerrors.InvalidInput, "projection version must be positive").
+			WithContext("projection", name).
+			WithContext("version", target)
+	}
+
+	pm.mutex.RLock()
+	checkpointStore := pm.checkpointStore
+	pm.mutex.RUnlock()
+	versionStore, ok := checkpointStore.(IProjectionVersionStore)
+	if !ok || versionStore == nil {
+		return gerrors.NewCode(gerrors.InvalidInput, "versioned projection requires checkpoint store with version support").
+			WithContext("projection", name)
+	}
+
+	rt.execMu.Lock()
+	defer rt.execMu.Unlock()
+
+	current, err := versionStore.LoadProjectionVersion(ctx, name)
+	if err != nil {
+		if !gerrors.Is(err, gerrors.NotFound) {
+			return gerrors.Wrap(err, gerrors.Database, "failed to load projection version").
+				WithContext("projection", name)
+		}
+		current = 0
+	}
+	if current == target {
+		return nil
+	}
+	if current > target {
+		return gerrors.NewCode(gerrors.Conflict, "projection version is older than recorded read model version").
+			WithContext("projection", name).
+			WithContext("recorded_version", current).
+			WithContext("declared_version", target)
+	}
+
+	wasRunning := rt.isRunning()
+	pm.logger.Info(ctx, "migrating projection read model",
+		logging.String("projection", name),
+		logging.Int("from_version", current),
+		logging.Int("to_version", target))
+	rt.markRebuilding()
+
+	action, err := versioned.MigrateReadModel(ctx, current, target)
+	if err != nil {
+		rt.markError(err)
+		return gerrors.Wrap(err, gerrors.Internal, "projection read model migration failed").
+			WithContext("projection", name).
+			WithContext("from_version", current).
+			WithContext("to_version", target)
+	}
+
+	switch action {
+	case MigrationReplay:
+		if err := checkpointStore.Delete(ctx, name); err != nil {
+			rt.markError(err)
+			return gerrors.Wrap(err, gerrors.Database, "failed to delete checkpoint after migration").
+				WithContext("projection", name)
+		}
+		rt.prefillFromCheckpoint(NewCheckpointAt(pm.clock().Now(), name, 0, "", time.Time{}))
+	case MigrationKeepCheckpoint, "":
+	default:
+		err := gerrors.NewCode(gerrors.InvalidInput, "unknown projection migration action").
+			WithContext("projection", name).
+			WithContext("action", string(action))
+		rt.markError(err)
+		return err
+	}
+
+	if err := versionStore.SaveProjectionVersion(ctx, name, target); err != nil {
+		rt.markError(err)
+		return gerrors.Wrap(err, gerrors.Database, "failed to save projection version").
+			WithContext("projection", name)
+	}
+
+	if wasRunning {
+		rt.markRunning()
+	} else {
+		rt.markStopped()
+	}
+	pm.logger.Info(ctx, "projection read model migrated",
+		logging.String("projection", name),
+		logging.Int("version", target),
+		logging.String("action", string(action)))
+	return nil
+}
@@ -0,0 +1,174 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gerrors "gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/store"
+	"gochen/eventing/upcast"
+)
+
+type versionedMockProjection struct {
+	*MockProjection
+	version    int
+	action     MigrationAction
+	migrateErr error
+	migrations [][2]int
+}
+
+func (p *versionedMockProjection) ProjectionVersion() int { return p.version }
+
+func (p *versionedMockProjection) MigrateReadModel(ctx context.Context, fromVersion, toVersion int) (MigrationAction, error) {
+	p.migrations = append(p.migrations, [2]int{fromVersion, toVersion})
+	if p.migrateErr != nil {
+		return "", p.migrateErr
+	}
+	return p.action, nil
+}
+
+func newVersionedTestManager(t *testing.T, checkpointStore ICheckpointStore) (*ProjectionManager[int64], *store.MemoryEventStore) {
+	t.Helper()
+	eventStore := store.NewMemoryEventStore()
+	manager, err := NewProjectionManager[int64](eventStore, &MockEventBus{}, newTestRegistry(t), upcast.NewUpgraderRegistry())
+	require.NoError(t, err)
+	if checkpointStore != nil {
+		manager, err = manager.WithCheckpointStore(checkpointStore)
+		require.NoError(t, err)
+	}
+	return manager, eventStore
+}
+
+// TestMigrateProjection_FirstDeployRecordsVersion 验证首次部署时执行迁移并记录版本。
+func TestMigrateProjection_FirstDeployRecordsVersion(t *testing.T) {
+	ctx := context.Background()
+	checkpointStore := NewMemoryCheckpointStore()
+	manager, _ := newVersionedTestManager(t, checkpointStore)
+
+	projection := &versionedMockProjection{
+		MockProjection: NewMockProjection("versioned", []string{"TestEvent"}),
+		version:        1,
+		action:         MigrationKeepCheckpoint,
+	}
+	require.NoError(t, manager.RegisterProjection(projection))
+
+	require.NoError(t, manager.MigrateProjection(ctx, "versioned"))
+	assert.Equal(t, [][2]int{{0, 1}}, projection.migrations)
+
+	version, err := checkpointStore.LoadProjectionVersion(ctx, "versioned")
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+
+	// 版本未变化时不再执行迁移。
+	require.NoError(t, manager.MigrateProjection(ctx, "versioned"))
+	assert.Len(t, projection.migrations, 1)
+}
+
+// TestMigrateProjection_ReplayDeletesCheckpoint 验证 Replay 迁移会删除检查点并在恢复时从头回放。
+func TestMigrateProjection_ReplayDeletesCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	checkpointStore := NewMemoryCheckpointStore()
+	manager, eventStore := newVersionedTestManager(t, checkpointStore)
+
+	projection := &versionedMockProjection{
+		MockProjection: NewMockProjection("versioned", []string{"TestEvent"}),
+		version:        2,
+		action:         MigrationReplay,
+	}
+	require.NoError(t, manager.RegisterProjection(projection))
+
+	events := []eventing.Event[int64]{
+		*eventing.NewEvent[int64](1, "Agg", "TestEvent", 1, map[string]any{"i": 1}),
+		*eventing.NewEvent[int64](1, "Agg", "TestEvent", 2, map[string]any{"i": 2}),
+	}
+	require.NoError(t, eventStore.AppendEvents(ctx, 1, toStorableEvents(events), 0))
+	require.NoError(t, checkpointStore.Save(ctx, NewCheckpoint("versioned", 2, events[1].ID, time.Now())))
+	require.NoError(t, checkpointStore.SaveProjectionVersion(ctx, "versioned", 1))
+
+	require.NoError(t, manager.ResumeFromCheckpoint(ctx, "versioned"))
+	assert.Equal(t, [][2]int{{1, 2}}, projection.migrations)
+	assert.Equal(t, 2, projection.processedEvents)
+
+	version, err := checkpointStore.LoadProjectionVersion(ctx, "versioned")
+	require.NoError(t, err)
+	assert.Equal(t, 2, version)
+}
+
+// TestMigrateProjection_RejectsDowngrade 验证声明版本低于已记录版本时返回 Conflict。
+func TestMigrateProjection_RejectsDowngrade(t *testing.T) {
+	ctx := context.Background()
+	checkpointStore := NewMemoryCheckpointStore()
+	manager, _ := newVersionedTestManager(t, checkpointStore)
+
+	projection := &versionedMockProjection{
+		MockProjection: NewMockProjection("versioned", []string{"TestEvent"}),
+		version:        1,
+	}
+	require.NoError(t, manager.RegisterProjection(projection))
+	require.NoError(t, checkpointStore.SaveProjectionVersion(ctx, "versioned", 3))
+
+	err := manager.MigrateProjection(ctx, "versioned")
+	require.Error(t, err)
+	assert.True(t, gerrors.Is(err, gerrors.Conflict))
+	assert.Empty(t, projection.migrations)
+}
+
+// TestMigrateProjection_HookFailureMarksError 验证迁移钩子失败时不记录版本并把投影标记为错误。
+func TestMigrateProjection_HookFailureMarksError(t *testing.T) {
+	ctx := context.Background()
+	checkpointStore := NewMemoryCheckpointStore()
+	manager, _ := newVersionedTestManager(t, checkpointStore)
+
+	projection := &versionedMockProjection{
+		MockProjection: NewMockProjection("versioned", []string{"TestEvent"}),
+		version:        1,
+		migrateErr:     errors.New("alter table failed"),
+	}
+	require.NoError(t, manager.RegisterProjection(projection))
+
+	require.Error(t, manager.MigrateProjection(ctx, "versioned"))
+
+	_, err := checkpointStore.LoadProjectionVersion(ctx, "versioned")
+	assert.True(t, gerrors.Is(err, gerrors.NotFound))
+
+	status, err := manager.ProjectionStatus("versioned")
+	require.NoError(t, err)
+	assert.Equal(t, "error", status.Status)
+}
+
+// TestMigrateProjection_RequiresVersionStore 验证未配置检查点存储时拒绝迁移版本化投影。
+func TestMigrateProjection_RequiresVersionStore(t *testing.T) {
+	manager, _ := newVersionedTestManager(t, nil)
+	projection := &versionedMockProjection{
+		MockProjection: NewMockProjection("versioned", []string{"TestEvent"}),
+		version:        1,
+	}
+	require.NoError(t, manager.RegisterProjection(projection))
+
+	err := manager.MigrateProjections(context.Background())
+	require.Error(t, err)
+	assert.True(t, gerrors.Is(err, gerrors.InvalidInput))
+}
+
+// TestSQLCheckpointStore_ProjectionVersion 验证 SQL 检查点存储读写投影版本。
+func TestSQLCheckpointStore_ProjectionVersion(t *testing.T) {
+	ctx := context.Background()
+	checkpointStore := NewSQLCheckpointStore(newProjectionCheckpointTestDB(t), "projection_checkpoints")
+	require.NoError(t, checkpointStore.CreateTable(ctx))
+
+	_, err := checkpointStore.LoadProjectionVersion(ctx, "versioned")
+	assert.True(t, gerrors.Is(err, gerrors.NotFound))
+
+	require.NoError(t, checkpointStore.SaveProjectionVersion(ctx, "versioned", 1))
+	require.NoError(t, checkpointStore.SaveProjectionVersion(ctx, "versioned", 2))
+
+	version, err := checkpointStore.LoadProjectionVersion(ctx, "versioned")
+	require.NoError(t, err)
+	assert.Equal(t, 2, version)
+}
@@ -0,0 +1,18 @@
+module gochen/eventing/protox
+
+go 1.26.0
+
+require (
+	github.com/stretchr/testify v1.9.0
+	gochen v0.0.0
+	google.golang.org/protobuf v1.36.10
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace gochen => ../..
@@ -0,0 +1,208 @@
+// Package protox 支持以 protobuf 消息定义领域事件载荷。
+//
+// 该包是独立 module（gochen/eventing/protox），仅在团队以 proto 契约跨服务共享事件时引入，
+// gochen 核心 module 不依赖 protobuf。
+//
+// 载荷在事件存储、Outbox 与消息传输中以 JSON 信封保存类型 URL 与二进制编码：
+//
+//	{"@type": "type.googleapis.com/orders.v1.OrderPlaced", "value": "<base64 protobuf>"}
+//
+// 事件类型默认取消息全名（如 "orders.v1.OrderPlaced"）。注册到 registry 后，
+// 从存储读取的事件经 upcast 水合后 GetPayload() 返回生成的消息类型，而不是 map。
+// 未经信封编码写入的载荷（如直接以 eventing.NewEvent 创建的事件）按 protojson 解析。
+package protox
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/registry"
+	"gochen/messaging"
+)
+
+// TypeURLPrefix 是信封中类型 URL 的前缀（与 google.protobuf.Any 一致）。
+const TypeURLPrefix = "type.googleapis.com/"
+
+// envelope 是 protobuf 载荷的 JSON 信封；Value 以 base64 编码。
+type envelope struct {
+	TypeURL string `json:"@type"`
+	Value   []byte `json:"value"`
+}
+
+// Codec 在 protobuf 消息与 JSON 信封之间转换，实现 registry.IPayloadCodec。
+type Codec struct {
+	messageType protoreflect.MessageType
+	newMessage  func() proto.Message
+}
+
+// NewCodec 为 msg 的消息类型创建 Codec。
+func NewCodec(msg proto.Message) (*Codec, error) {
+	if msg == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "proto message cannot be nil")
+	}
+	mt := msg.ProtoReflect().Type()
+	return &Codec{messageType: mt, newMessage: func() proto.Message { return mt.New().Interface() }}, nil
+}
+
+// TypeURL 返回该消息类型的类型 URL。
+func (c *Codec) TypeURL() string {
+	return TypeURLPrefix + string(c.messageType.Descriptor().FullName())
+}
+
+// MarshalPayload 把消息编码为 JSON 信封（确定性二进制编码）。
+func (c *Codec) MarshalPayload(value any) ([]byte, error) {
+	msg, ok := value.(proto.Message)
+	if !ok {
+		return nil, errors.NewCode(errors.InvalidInput, "payload is not a proto message").
+			WithContext("payload_type", fmt.Sprintf("%T", value))
+	}
+	if got, want := msg.ProtoReflect().Descriptor().FullName(), c.messageType.Descriptor().FullName(); got != want {
+		return nil, errors.NewCode(errors.InvalidInput, "proto message type mismatch").
+			WithContext("expected", string(want)).
+			WithContext("actual", string(got))
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "failed to marshal proto message").
+			WithContext("type_url", c.TypeURL())
+	}
+	return json.Marshal(envelope{TypeURL: c.TypeURL(), Value: data})
+}
+
+// UnmarshalPayload 把 JSON 信封还原为生成的消息类型；类型 URL 与注册的消息不一致时返回 InvalidInput。
+//
+// 不含 "@type" 的载荷按 protojson 解析（忽略未知字段），兼容未经信封编码写入的事件。
+func (c *Codec) UnmarshalPayload(data []byte) (any, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, "invalid proto payload envelope")
+	}
+	if _, ok := fields["@type"]; !ok {
+		msg := c.newMessage()
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, msg); err != nil {
+			return nil, errors.Wrap(err, errors.InvalidInput, "failed to unmarshal proto json payload").
+				WithContext("type_url", c.TypeURL())
+		}
+		return msg, nil
+	}
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, "invalid proto payload envelope")
+	}
+	want := c.messageType.Descriptor().FullName()
+	if name := env.TypeURL[strings.LastIndex(env.TypeURL, "/")+1:]; protoreflect.FullName(name) != want {
+		return nil, errors.NewCode(errors.InvalidInput, "proto payload type url mismatch").
+			WithContext("expected", c.TypeURL()).
+			WithContext("actual", env.TypeURL)
+	}
+	msg := c.newMessage()
+	if err := proto.Unmarshal(env.Value, msg); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, "failed to unmarshal proto message").
+			WithContext("type_url", env.TypeURL)
+	}
+	return msg, nil
+}
+
+// EventType 返回消息对应的事件类型（消息全名）。
+func EventType(msg proto.Message) string {
+	if msg == nil {
+		return ""
+	}
+	return string(msg.ProtoReflect().Descriptor().FullName())
+}
+
+// Register 以消息全名为事件类型、schema 版本 1 注册消息类型。
+func Register(reg *registry.Registry, msgs ...proto.Message) error {
+	for _, msg := range msgs {
+		if err := RegisterWithVersion(reg, EventType(msg), 1, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterWithVersion 以指定事件类型与 schema 版本注册消息类型。
+func RegisterWithVersion(reg *registry.Registry, eventType string, schemaVersion int, msg proto.Message) error {
+	if reg == nil {
+		return errors.NewCode(errors.InvalidInput, "event registry cannot be nil")
+	}
+	if msg == nil {
+		return errors.NewCode(errors.InvalidInput, "proto message cannot be nil")
+	}
+	mt := msg.ProtoReflect().Type()
+	return RegisterWithFactory(reg, eventType, schemaVersion, func() proto.Message { return mt.New().Interface() })
+}
+
+// RegisterWithFactory 以指定事件类型与 schema 版本注册消息类型，反序列化时由 factory 创建实例。
+//
+// 用于在生成类型外包装领域事件（实现 domain.IDomainEvent，经 DomainEventStore 读写）的场景：
+//
+//	type OrderPlaced struct{ *ordersv1.OrderPlaced }
+//	func (OrderPlaced) EventType() string { return "orders.v1.OrderPlaced" }
+//
+//	protox.RegisterWithFactory(reg, "orders.v1.OrderPlaced", 1, func() *OrderPlaced {
+//		return &OrderPlaced{OrderPlaced: &ordersv1.OrderPlaced{}}
+//	})
+func RegisterWithFactory[T proto.Message](reg *registry.Registry, eventType string, schemaVersion int, factory func() T) error {
+	if reg == nil {
+		return errors.NewCode(errors.InvalidInput, "event registry cannot be nil")
+	}
+	if factory == nil {
+		return errors.NewCode(errors.InvalidInput, "proto message factory cannot be nil")
+	}
+	codec, err := NewCodec(factory())
+	if err != nil {
+		return err
+	}
+	codec.newMessage = func() proto.Message { return factory() }
+	return reg.RegisterWithCodec(eventType, schemaVersion, func() any { return factory() }, codec)
+}
+
+// RegisterFile 注册生成文件中声明的全部顶层消息，便于每个 .proto 文件一行完成注册：
+//
+//	protox.RegisterFile(reg, ordersv1.File_orders_v1_events_proto)
+//
+// 消息类型从 protoregistry.GlobalTypes 查找，要求对应的生成包已被导入。
+func RegisterFile(reg *registry.Registry, file protoreflect.FileDescriptor) error {
+	if file == nil {
+		return errors.NewCode(errors.InvalidInput, "proto file descriptor cannot be nil")
+	}
+	messages := file.Messages()
+	for i := 0; i < messages.Len(); i++ {
+		name := messages.Get(i).FullName()
+		mt, err := protoregistry.GlobalTypes.FindMessageByName(name)
+		if err != nil {
+			return errors.Wrap(err, errors.NotFound, "proto message type not linked").
+				WithContext("message", string(name))
+		}
+		if err := Register(reg, mt.New().Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewPayload 把消息封装为载荷：PayloadValue 返回消息本身，序列化时使用 JSON 信封。
+func NewPayload(msg proto.Message) messaging.Payload {
+	codec, err := NewCodec(msg)
+	if err != nil {
+		return messaging.NewPayload(nil)
+	}
+	return messaging.NewPayloadWithMarshaler(msg, codec)
+}
+
+// NewEvent 以消息全名为事件类型创建载荷为 protobuf 消息的事件。
+func NewEvent[ID comparable](aggregateID ID, aggregateType string, version uint64, msg proto.Message, schemaVersion ...int) *eventing.Event[ID] {
+	return eventing.NewEvent(aggregateID, aggregateType, EventType(msg), version, NewPayload(msg), schemaVersion...)
+}
+
+// 编译期断言：确保 Codec 实现 registry.IPayloadCodec。
+var _ registry.IPayloadCodec = (*Codec)(nil)
@@ -0,0 +1,138 @@
+package protox
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"gochen/app/eventsourced"
+	"gochen/domain"
+	deventsourced "gochen/domain/eventsourced"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/registry"
+	"gochen/eventing/store"
+	"gochen/eventing/upcast"
+	"gochen/messaging"
+)
+
+// TestEvent_RoundTripsThroughJSONEnvelope 验证事件序列化为类型 URL + 二进制信封，水合后载荷为生成的消息类型。
+func TestEvent_RoundTripsThroughJSONEnvelope(t *testing.T) {
+	reg := registry.NewRegistry()
+	require.NoError(t, Register(reg, &wrapperspb.StringValue{}))
+
+	evt := NewEvent[int64](1, "Order", 1, wrapperspb.String("placed"))
+	assert.Equal(t, "google.protobuf.StringValue", evt.GetType())
+	msg, ok := messaging.PayloadAs[*wrapperspb.StringValue](evt.GetPayload())
+	require.True(t, ok)
+	assert.Equal(t, "placed", msg.GetValue())
+
+	data, err := json.Marshal(evt)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"@type":"type.googleapis.com/google.protobuf.StringValue"`)
+
+	var stored eventing.Event[int64]
+	require.NoError(t, json.Unmarshal(data, &stored))
+	_, err = upcast.UpgradeEventPayload(context.Background(), reg, upcast.NewUpgraderRegistry(), &stored)
+	require.NoError(t, err)
+	hydrated, ok := messaging.PayloadValue(stored.GetPayload()).(*wrapperspb.StringValue)
+	require.True(t, ok, "hydrated payload is the generated type")
+	assert.True(t, proto.Equal(wrapperspb.String("placed"), hydrated))
+
+	again, err := json.Marshal(stored.GetPayload())
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(again), `{"@type":`), "hydrated payloads keep the envelope encoding")
+}
+
+// noteAdded 把生成类型包装为领域事件（模拟在生成类型上声明 EventType 方法）。
+type noteAdded struct{ *wrapperspb.StringValue }
+
+func (noteAdded) EventType() string { return "notes.NoteAdded" }
+
+// TestDomainEventStore_RoundTripsProtoPayload 验证经 DomainEventStore 追加的领域事件以信封编码写入，读取后水合为领域事件类型。
+func TestDomainEventStore_RoundTripsProtoPayload(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewRegistry()
+	require.NoError(t, RegisterWithFactory(reg, "notes.NoteAdded", 1, func() *noteAdded {
+		return &noteAdded{StringValue: &wrapperspb.StringValue{}}
+	}))
+	eventStore := store.NewMemoryEventStore()
+	adapter, err := eventsourced.NewDomainEventStore(eventsourced.DomainEventStoreOptions[*deventsourced.EventSourcedAggregate[int64], int64]{
+		AggregateType:    "Note",
+		EventStore:       eventStore,
+		EventRegistry:    reg,
+		UpgraderRegistry: upcast.NewUpgraderRegistry(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, adapter.AppendEvents(ctx, 1, []domain.IDomainEvent{&noteAdded{StringValue: wrapperspb.String("hello")}}, 0))
+
+	loaded, err := eventStore.LoadEvents(ctx, 1, 0)
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	data, err := json.Marshal(loaded[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"@type":"type.googleapis.com/google.protobuf.StringValue"`)
+
+	var stored eventing.Event[int64]
+	require.NoError(t, json.Unmarshal(data, &stored))
+	_, err = upcast.UpgradeEventPayload(ctx, reg, upcast.NewUpgraderRegistry(), &stored)
+	require.NoError(t, err)
+	hydrated, ok := messaging.PayloadValue(stored.GetPayload()).(*noteAdded)
+	require.True(t, ok, "hydrated payload is the registered domain event type")
+	assert.Equal(t, "hello", hydrated.GetValue())
+}
+
+// TestCodec_AcceptsProtoJSON 验证未经信封编码写入的载荷（直接以 eventing.NewEvent 创建）按 protojson 解析。
+func TestCodec_AcceptsProtoJSON(t *testing.T) {
+	reg := registry.NewRegistry()
+	require.NoError(t, Register(reg, &sourcecontextpb.SourceContext{}))
+
+	msg := &sourcecontextpb.SourceContext{FileName: "orders.proto"}
+	evt := eventing.NewEvent[int64](1, "Order", EventType(msg), 1, msg)
+	data, err := json.Marshal(evt)
+	require.NoError(t, err)
+	var stored eventing.Event[int64]
+	require.NoError(t, json.Unmarshal(data, &stored))
+	_, err = upcast.UpgradeEventPayload(context.Background(), reg, upcast.NewUpgraderRegistry(), &stored)
+	require.NoError(t, err)
+	hydrated, ok := messaging.PayloadValue(stored.GetPayload()).(*sourcecontextpb.SourceContext)
+	require.True(t, ok)
+	assert.True(t, proto.Equal(msg, hydrated))
+}
+
+func TestRegisterFile(t *testing.T) {
+	reg := registry.NewRegistry()
+	require.NoError(t, RegisterFile(reg, timestamppb.File_google_protobuf_timestamp_proto))
+	assert.True(t, reg.HasEvent("google.protobuf.Timestamp"))
+
+	payload, err := json.Marshal(NewPayload(timestamppb.New(timestamppb.Now().AsTime())))
+	require.NoError(t, err)
+	typed, err := reg.DeserializeWithUseNumber("google.protobuf.Timestamp", payload)
+	require.NoError(t, err)
+	assert.IsType(t, &timestamppb.Timestamp{}, typed)
+}
+
+func TestCodec_RejectsMismatchedTypes(t *testing.T) {
+	codec, err := NewCodec(&wrapperspb.StringValue{})
+	require.NoError(t, err)
+
+	_, err = codec.MarshalPayload(wrapperspb.Int64(1))
+	assert.True(t, errors.Is(err, errors.InvalidInput))
+	_, err = codec.MarshalPayload("not a message")
+	assert.True(t, errors.Is(err, errors.InvalidInput))
+
+	other, err := NewCodec(&wrapperspb.Int64Value{})
+	require.NoError(t, err)
+	data, err := other.MarshalPayload(wrapperspb.Int64(1))
+	require.NoError(t, err)
+	_, err = codec.UnmarshalPayload(data)
+	assert.True(t, errors.Is(err, errors.InvalidInput))
+}
@@ -9,16 +9,26 @@ import (
 
 	"gochen/codec/jsoncodec"
 	"gochen/errors"
+	"gochen/messaging"
 )
 
 // EventFactory 事件工厂函数。
 type EventFactory func() any
 
+// IPayloadCodec 自定义某个事件类型载荷的编解码（默认按 JSON 结构体处理），如 protobuf 信封。
+type IPayloadCodec interface {
+	messaging.IPayloadMarshaler
+
+	// UnmarshalPayload 把存储/传输中的 JSON 载荷还原为强类型对象。
+	UnmarshalPayload(data []byte) (any, error)
+}
+
 // Registry 事件注册表。
 type Registry struct {
 	eventTypes map[string]reflect.Type
 	factories  map[string]EventFactory
 	versions   map[string]int
+	codecs     map[string]IPayloadCodec
 	mutex      sync.RWMutex
 }
 
@@ -28,6 +38,7 @@ func NewRegistry() *Registry {
 		eventTypes: make(map[string]reflect.Type),
 		factories:  make(map[string]EventFactory),
 		versions:   make(map[string]int),
+		codecs:     make(map[string]IPayloadCodec),
 	}
 }
 
@@ -38,6 +49,14 @@ func (r *Registry) Register(eventType string, factory EventFactory) error {
 
 // RegisterWithVersion 注册带 schema 版本的事件类型。
 func (r *Registry) RegisterWithVersion(eventType string, schemaVersion int, factory EventFactory) error {
+	return r.RegisterWithCodec(eventType, schemaVersion, factory, nil)
+}
+
+// RegisterWithCodec 注册使用自定义载荷编解码的事件类型；codec 为 nil 时等同 RegisterWithVersion。
+//
+// 反序列化（Deserialize*/DeserializeFromMap）改由 codec.UnmarshalPayload 完成，
+// NewPayload 为该类型的载荷附加 codec 编码，使其写入事件存储/Outbox 时保持同一格式。
+func (r *Registry) RegisterWithCodec(eventType string, schemaVersion int, factory EventFactory, codec IPayloadCodec) error {
 	if eventType == "" {
 		return errors.NewCode(errors.InvalidInput, "event type cannot be empty")
 	}
@@ -63,6 +82,9 @@ func (r *Registry) RegisterWithVersion(eventType string, schemaVersion int, fact
 	r.eventTypes[eventType] = reflect.TypeOf(instance)
 	r.factories[eventType] = factory
 	r.versions[eventType] = schemaVersion
+	if codec != nil {
+		r.codecs[eventType] = codec
+	}
 	return nil
 }
 
@@ -73,16 +95,48 @@ func (r *Registry) Unregister(eventType string) {
 	delete(r.eventTypes, eventType)
 	delete(r.factories, eventType)
 	delete(r.versions, eventType)
+	delete(r.codecs, eventType)
 }
 
-// Deserialize 按事件类型把 JSON 字节反序列化为强类型事件对象。
-func (r *Registry) Deserialize(eventType string, data []byte) (any, error) {
+// NewPayload 把强类型载荷封装为 messaging.Payload；事件类型注册了 codec 时附加其编码。
+func (r *Registry) NewPayload(eventType string, value any) messaging.Payload {
 	r.mutex.RLock()
-	factory, exists := r.factories[eventType]
+	codec := r.codecs[eventType]
 	r.mutex.RUnlock()
+	if codec == nil {
+		return messaging.NewPayload(value)
+	}
+	return messaging.NewPayloadWithMarshaler(value, codec)
+}
 
+// lookup 返回事件类型的工厂与 codec。
+func (r *Registry) lookup(eventType string) (EventFactory, IPayloadCodec, error) {
+	r.mutex.RLock()
+	factory, exists := r.factories[eventType]
+	codec := r.codecs[eventType]
+	r.mutex.RUnlock()
 	if !exists {
-		return nil, errors.NewCode(errors.NotFound, "unknown event type").WithContext("event_type", eventType)
+		return nil, nil, errors.NewCode(errors.NotFound, "unknown event type").WithContext("event_type", eventType)
+	}
+	return factory, codec, nil
+}
+
+func unmarshalWithCodec(codec IPayloadCodec, eventType string, data []byte) (any, error) {
+	instance, err := codec.UnmarshalPayload(data)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, "failed to deserialize event").WithContext("event_type", eventType)
+	}
+	return instance, nil
+}
+
+// Deserialize 按事件类型把 JSON 字节反序列化为强类型事件对象。
+func (r *Registry) Deserialize(eventType string, data []byte) (any, error) {
+	factory, codec, err := r.lookup(eventType)
+	if err != nil {
+		return nil, err
+	}
+	if codec != nil {
+		return unmarshalWithCodec(codec, eventType, data)
 	}
 
 	instance := factory()
@@ -94,12 +148,12 @@ func (r *Registry) Deserialize(eventType string, data []byte) (any, error) {
 
 // DeserializeWithUseNumber 在反序列化时保留数字精度。
 func (r *Registry) DeserializeWithUseNumber(eventType string, data []byte) (any, error) {
-	r.mutex.RLock()
-	factory, exists := r.factories[eventType]
-	r.mutex.RUnlock()
-
-	if !exists {
-		return nil, errors.NewCode(errors.NotFound, "unknown event type").WithContext("event_type", eventType)
+	factory, codec, err := r.lookup(eventType)
+	if err != nil {
+		return nil, err
+	}
+	if codec != nil {
+		return unmarshalWithCodec(codec, eventType, data)
 	}
 
 	instance := factory()
@@ -117,12 +171,9 @@ func (r *Registry) DeserializeFromMap(eventType string, data map[string]any) (an
 		return nil, errors.NewCode(errors.InvalidInput, "event data map cannot be nil")
 	}
 
-	r.mutex.RLock()
-	factory, exists := r.factories[eventType]
-	r.mutex.RUnlock()
-
-	if !exists {
-		return nil, errors.NewCode(errors.NotFound, "unknown event type").WithContext("event_type", eventType)
+	factory, codec, err := r.lookup(eventType)
+	if err != nil {
+		return nil, err
 	}
 
 	// 先将 map 序列化为 JSON bytes
@@ -132,6 +183,9 @@ func (r *Registry) DeserializeFromMap(eventType string, data map[string]any) (an
 	if err != nil {
 		return nil, errors.Wrap(err, errors.Internal, "failed to marshal event map").WithContext("event_type", eventType)
 	}
+	if codec != nil {
+		return unmarshalWithCodec(codec, eventType, jsonBytes)
+	}
 
 	// 使用 json.Decoder + UseNumber() 反序列化，保持数字精度
 	instance := factory()
@@ -105,3 +105,54 @@ func TestRegistry_DeserializeFromMap_Int64Precision(t *testing.T) {
 		t.Errorf("FloatVal mismatch: expected 3.14159, got %f", ev.FloatVal)
 	}
 }
+
+// envelopeCodec 把载荷编码为 {"wrapped": name}，用于验证自定义 codec。
+type envelopeCodec struct{}
+
+func (envelopeCodec) MarshalPayload(value any) ([]byte, error) {
+	return json.Marshal(map[string]string{"wrapped": value.(*sampleEvent).Name})
+}
+
+func (envelopeCodec) UnmarshalPayload(data []byte) (any, error) {
+	var env map[string]string
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	return &sampleEvent{Name: env["wrapped"]}, nil
+}
+
+// TestRegistry_RegisterWithCodec 验证注册 codec 的事件类型使用 codec 编解码。
+func TestRegistry_RegisterWithCodec(t *testing.T) {
+	r := NewRegistry()
+	if err := r.RegisterWithCodec("CodecEvent", 1, func() any { return &sampleEvent{} }, envelopeCodec{}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	data, err := json.Marshal(r.NewPayload("CodecEvent", &sampleEvent{Name: "demo"}))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(data) != `{"wrapped":"demo"}` {
+		t.Fatalf("unexpected encoding %s", data)
+	}
+
+	typed, err := r.DeserializeWithUseNumber("CodecEvent", data)
+	if err != nil {
+		t.Fatalf("deserialize: %v", err)
+	}
+	if ev := typed.(*sampleEvent); ev.Name != "demo" {
+		t.Fatalf("unexpected value %s", ev.Name)
+	}
+	typed, err = r.DeserializeFromMap("CodecEvent", map[string]any{"wrapped": "from-map"})
+	if err != nil {
+		t.Fatalf("deserialize from map: %v", err)
+	}
+	if ev := typed.(*sampleEvent); ev.Name != "from-map" {
+		t.Fatalf("unexpected value %s", ev.Name)
+	}
+
+	r.Unregister("CodecEvent")
+	if _, err := r.Deserialize("CodecEvent", data); err == nil {
+		t.Fatalf("expected unknown event type after unregister")
+	}
+}
@@ -12,16 +12,68 @@ import (
 type EventCache[ID comparable] struct {
 	aggregateCache map[string]*CachedAggregate[ID] // 聚合缓存（key: aggregate_type:aggregate_id）
 	ttl            time.Duration                   // 缓存过期时间
-	maxAggregates  int                             // 最大缓存聚合数
+	maxAggregates  int                             // 共享分区的最大缓存聚合数
+	typeLimits     map[string]int                  // 单独限额的聚合类型
+	newPolicy      func(capacity int) IEvictionPolicy
+	partitions     map[string]*cachePartition // key: 单独限额的聚合类型；共享分区为 ""
 	mutex          sync.RWMutex
 }
 
+// cachePartition 是一组共享容量与淘汰顺序的缓存条目。
+type cachePartition struct {
+	policy IEvictionPolicy
+	limit  int
+	size   int
+}
+
 // CachedAggregate 缓存的聚合数据。
 type CachedAggregate[ID comparable] struct {
 	Events     []eventing.Event[ID] // 事件列表
 	Version    uint64               // 当前版本
 	LastAccess time.Time            // 最后访问时间
 	CreatedAt  time.Time            // 创建时间
+
+	partition string
+}
+
+// resetPartitionsUnsafe 重建所有分区（非线程安全）。
+func (c *EventCache[ID]) resetPartitionsUnsafe() {
+	c.partitions = map[string]*cachePartition{
+		"": {policy: c.newPolicy(c.maxAggregates), limit: c.maxAggregates},
+	}
+}
+
+// partitionFor 返回聚合类型所属分区名：单独限额的类型独占分区，其余共享 "" 分区。
+func (c *EventCache[ID]) partitionFor(aggregateType string) string {
+	if _, ok := c.typeLimits[aggregateType]; ok {
+		return aggregateType
+	}
+	return ""
+}
+
+// partitionUnsafe 返回（必要时创建）分区（非线程安全）。
+func (c *EventCache[ID]) partitionUnsafe(name string) *cachePartition {
+	part, ok := c.partitions[name]
+	if !ok {
+		limit := c.typeLimits[name]
+		part = &cachePartition{policy: c.newPolicy(limit), limit: limit}
+		c.partitions[name] = part
+	}
+	return part
+}
+
+// removeUnsafe 移除缓存条目并同步淘汰策略（非线程安全）。
+func (c *EventCache[ID]) removeUnsafe(key string) bool {
+	cached, ok := c.aggregateCache[key]
+	if !ok {
+		return false
+	}
+	delete(c.aggregateCache, key)
+	if part, ok := c.partitions[cached.partition]; ok {
+		part.policy.Remove(key)
+		part.size--
+	}
+	return true
 }
 
 func cacheKey[ID comparable](aggregateType string, aggregateID ID) string {
@@ -54,7 +106,10 @@ func (s *CachedEventStore[ID]) getCachedEvents(key string, fromVersion uint64) [
 	// 单独获取写锁更新 LastAccess，避免在读锁下写入
 	s.cache.mutex.Lock()
 	if cachedLatest, ok := s.cache.aggregateCache[key]; ok {
-		cachedLatest.LastAccess = time.Now()
+		cachedLatest.LastAccess = s.clock.Now()
+		if part, ok := s.cache.partitions[cachedLatest.partition]; ok {
+			part.policy.Touch(key)
+		}
 	}
 	s.cache.mutex.Unlock()
 
@@ -70,9 +125,14 @@ func (s *CachedEventStore[ID]) cacheAggregate(key string, events []eventing.Even
 	s.cache.mutex.Lock()
 	defer s.cache.mutex.Unlock()
 
-	// 检查缓存大小，必要时驱逐
-	if len(s.cache.aggregateCache) >= s.cache.maxAggregates {
-		s.evictOldestUnsafe()
+	// 覆盖写入时先移除旧条目，再按所属分区的容量驱逐
+	s.cache.removeUnsafe(key)
+	partitionName := s.cache.partitionFor(events[0].GetAggregateType())
+	part := s.cache.partitionUnsafe(partitionName)
+	for part.size >= part.limit {
+		if !s.evictUnsafe(part) {
+			break
+		}
 	}
 
 	// 获取最新版本
@@ -82,12 +142,16 @@ func (s *CachedEventStore[ID]) cacheAggregate(key string, events []eventing.Even
 	cachedEvents := make([]eventing.Event[ID], len(events))
 	copy(cachedEvents, events)
 
+	now := s.clock.Now()
 	s.cache.aggregateCache[key] = &CachedAggregate[ID]{
 		Events:     cachedEvents,
 		Version:    latestVersion,
-		LastAccess: time.Now(),
-		CreatedAt:  time.Now(),
+		LastAccess: now,
+		CreatedAt:  now,
+		partition:  partitionName,
 	}
+	part.policy.Add(key)
+	part.size++
 }
 
 // invalidateCache 失效缓存。
@@ -95,33 +159,36 @@ func (s *CachedEventStore[ID]) invalidateCache(aggregateType string, aggregateID
 	s.cache.mutex.Lock()
 	defer s.cache.mutex.Unlock()
 
-	key := cacheKey(aggregateType, aggregateID)
-	if _, exists := s.cache.aggregateCache[key]; exists {
-		delete(s.cache.aggregateCache, key)
+	if s.cache.removeUnsafe(cacheKey(aggregateType, aggregateID)) {
 		s.recordInvalidation()
 	}
 }
 
-// evictOldestUnsafe 驱逐最旧的缓存项（非线程安全）。
-func (s *CachedEventStore[ID]) evictOldestUnsafe() {
-	var oldestKey string
-	var oldestTime time.Time
+// invalidateKey 按缓存键失效本地缓存（用于处理其他实例广播的失效）。
+func (s *CachedEventStore[ID]) invalidateKey(key string) {
+	s.cache.mutex.Lock()
+	defer s.cache.mutex.Unlock()
 
-	// 查找最久未访问的聚合
-	for key, cached := range s.cache.aggregateCache {
-		if oldestTime.IsZero() || cached.LastAccess.Before(oldestTime) {
-			oldestTime = cached.LastAccess
-			oldestKey = key
-		}
+	if s.cache.removeUnsafe(key) {
+		s.recordInvalidation()
 	}
+}
 
-	if !oldestTime.IsZero() {
-		delete(s.cache.aggregateCache, oldestKey)
-		s.recordEviction()
+// evictUnsafe 按分区的淘汰策略驱逐一个缓存项（非线程安全）。
+func (s *CachedEventStore[ID]) evictUnsafe(part *cachePartition) bool {
+	key, ok := part.policy.Evict()
+	if !ok {
+		return false
+	}
+	if _, exists := s.cache.aggregateCache[key]; exists {
+		delete(s.cache.aggregateCache, key)
+		part.size--
 	}
+	s.recordEviction()
+	return true
 }
 
 // isExpired 检查缓存是否过期。
 func (s *CachedEventStore[ID]) isExpired(cached *CachedAggregate[ID]) bool {
-	return time.Since(cached.CreatedAt) > s.cache.ttl
+	return s.clock.Now().Sub(cached.CreatedAt) > s.cache.ttl
 }
@@ -6,6 +6,8 @@ import (
 	"sync/atomic"
 	"time"
 
+	"gochen/clock"
+	"gochen/errors"
 	"gochen/eventing"
 	"gochen/eventing/monitoring"
 	"gochen/eventing/store"
@@ -19,6 +21,10 @@ type CachedEventStore[ID comparable] struct {
 	stats   *CacheStats                 // 缓存统计
 	metrics atomic.Value                // cacheMetricsHolder（承载 monitoring.ICacheMetricsRecorder），用于并发热替换且避免 data race
 
+	l2 *secondLevel[ID] // 共享缓存层（可选）
+
+	clock clock.IClock // 访问时间、TTL 过期与定期清理的时间来源
+
 	stopCh chan struct{}
 	// 关闭标识用于避免重复关闭
 	stopOnce sync.Once
@@ -45,19 +51,48 @@ func NewCachedEventStore[ID comparable](inner store.IEventStreamStore[ID], confi
 		config.MaxAggregates = defaultMaxAggregates
 	}
 
+	newPolicy := config.NewPolicy
+	if newPolicy == nil {
+		policy := config.EvictionPolicy
+		newPolicy = func(capacity int) IEvictionPolicy { return NewEvictionPolicy(policy, capacity) }
+	}
+	typeLimits := make(map[string]int, len(config.MaxAggregatesPerType))
+	for aggregateType, limit := range config.MaxAggregatesPerType {
+		if aggregateType != "" && limit > 0 {
+			typeLimits[aggregateType] = limit
+		}
+	}
+
 	cache := &EventCache[ID]{
 		aggregateCache: make(map[string]*CachedAggregate[ID]),
 		ttl:            config.TTL,
 		maxAggregates:  config.MaxAggregates,
+		typeLimits:     typeLimits,
+		newPolicy:      newPolicy,
+	}
+	cache.resetPartitionsUnsafe()
+
+	clk := config.Clock
+	if clk == nil {
+		clk = clock.NewRealClock()
 	}
 
 	cached := &CachedEventStore[ID]{
 		store:  inner,
 		cache:  cache,
 		stats:  &CacheStats{},
+		clock:  clk,
 		stopCh: make(chan struct{}),
 	}
 
+	if config.SecondLevel != nil {
+		ttl := config.SecondLevelTTL
+		if ttl <= 0 {
+			ttl = config.TTL
+		}
+		cached.l2 = newSecondLevel[ID](cached, config.SecondLevel, ttl)
+	}
+
 	// 启动定期清理过期缓存
 	go cached.startCleanupWorker(config.CleanupInterval)
 
@@ -105,6 +140,14 @@ func (s *CachedEventStore[ID]) AppendEvents(ctx context.Context, aggregateID ID,
 	// 始终清理通用缓存（key 为空类型）
 	s.invalidateCache("", aggregateID)
 
+	if s.l2 != nil {
+		keys := []string{cacheKey("", aggregateID)}
+		if len(events) > 0 && events[0].GetAggregateType() != "" {
+			keys = append(keys, cacheKey(events[0].GetAggregateType(), aggregateID))
+		}
+		s.l2.invalidate(ctx, keys...)
+	}
+
 	return nil
 }
 
@@ -121,6 +164,12 @@ func (s *CachedEventStore[ID]) LoadEvents(ctx context.Context, aggregateID ID, a
 
 	s.recordMiss()
 
+	if s.l2 != nil {
+		if events, ok := s.l2.load(ctx, "", aggregateID, afterVersion); ok {
+			return events, nil
+		}
+	}
+
 	// 缓存未命中，从底层存储加载
 	events, err := s.store.LoadEvents(ctx, aggregateID, afterVersion)
 	if err != nil {
@@ -130,11 +179,27 @@ func (s *CachedEventStore[ID]) LoadEvents(ctx context.Context, aggregateID ID, a
 	// 如果 afterVersion 为 0，表示加载全部事件，可以缓存
 	if afterVersion == 0 && len(events) > 0 {
 		s.cacheAggregate(cacheKey("", aggregateID), events)
+		if s.l2 != nil {
+			s.l2.store(ctx, cacheKey("", aggregateID), events)
+		}
 	}
 
 	return events, nil
 }
 
+// LoadEventsIter 以迭代器加载聚合事件。
+//
+// 本地缓存命中时直接迭代缓存列表；未命中时委托底层存储分页读取，且不回填缓存
+// （迭代器面向超长事件流，整体缓存会抵消分页读取的内存收益）。
+func (s *CachedEventStore[ID]) LoadEventsIter(ctx context.Context, aggregateID ID, afterVersion uint64) (store.IEventIterator[ID], error) {
+	if cached := s.getCachedEvents(cacheKey("", aggregateID), afterVersion); cached != nil {
+		s.recordHit()
+		return store.NewSliceIterator(cached), nil
+	}
+	s.recordMiss()
+	return s.store.LoadEventsIter(ctx, aggregateID, afterVersion)
+}
+
 // LoadEventsByType 加载聚合事件（按聚合类型）。
 //
 // 说明：
@@ -148,6 +213,12 @@ func (s *CachedEventStore[ID]) LoadEventsByType(ctx context.Context, aggregateTy
 
 	s.recordMiss()
 
+	if s.l2 != nil {
+		if events, ok := s.l2.load(ctx, aggregateType, aggregateID, afterVersion); ok {
+			return events, nil
+		}
+	}
+
 	var (
 		events []eventing.Event[ID]
 		err    error
@@ -160,6 +231,9 @@ func (s *CachedEventStore[ID]) LoadEventsByType(ctx context.Context, aggregateTy
 
 	if afterVersion == 0 && len(events) > 0 {
 		s.cacheAggregate(key, events)
+		if s.l2 != nil {
+			s.l2.store(ctx, key, events)
+		}
 	}
 
 	return events, nil
@@ -183,3 +257,36 @@ func (s *CachedEventStore[ID]) HasAggregate(ctx context.Context, aggregateID ID)
 func (s *CachedEventStore[ID]) GetAggregateVersion(ctx context.Context, aggregateID ID) (uint64, error) {
 	return s.store.GetAggregateVersion(ctx, aggregateID)
 }
+
+// CountEvents 统计聚合事件数量。
+func (s *CachedEventStore[ID]) CountEvents(ctx context.Context, aggregateID ID) (uint64, error) {
+	return s.store.CountEvents(ctx, aggregateID)
+}
+
+// HeadVersion 获取指定聚合类型下聚合的最新版本号。
+func (s *CachedEventStore[ID]) HeadVersion(ctx context.Context, aggregateType string, aggregateID ID) (uint64, error) {
+	return s.store.HeadVersion(ctx, aggregateType, aggregateID)
+}
+
+// DeleteAggregate 删除聚合事件流并失效缓存；底层存储不支持时返回 errors.Unsupported。
+func (s *CachedEventStore[ID]) DeleteAggregate(ctx context.Context, aggregateID ID, opts store.DeleteOptions) error {
+	deleter, ok := s.store.(store.IAggregateDeleter[ID])
+	if !ok {
+		return errors.NewCode(errors.Unsupported, "event store does not support deleting aggregates")
+	}
+	if err := deleter.DeleteAggregate(ctx, aggregateID, opts); err != nil {
+		return err
+	}
+	s.invalidateCache(opts.AggregateType, aggregateID)
+	s.invalidateCache("", aggregateID)
+	if s.l2 != nil {
+		s.l2.invalidate(ctx, cacheKey("", aggregateID), cacheKey(opts.AggregateType, aggregateID))
+	}
+	return nil
+}
+
+// 接口断言。
+var (
+	_ store.IEventStreamStore[int64] = (*CachedEventStore[int64])(nil)
+	_ store.IAggregateDeleter[int64] = (*CachedEventStore[int64])(nil)
+)
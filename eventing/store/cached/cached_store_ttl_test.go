@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"gochen/clock"
 	"gochen/eventing"
 	"gochen/eventing/store"
 )
@@ -70,3 +71,27 @@ func TestCachedEventStore_DefaultConfig(t *testing.T) {
 	assert.Equal(t, 1000, config.MaxAggregates)
 	assert.Equal(t, 1*time.Minute, config.CleanupInterval)
 }
+
+// TestCachedEventStore_UsesInjectedClockForTTL 验证 TTL 过期与清理使用注入的时钟。
+func TestCachedEventStore_UsesInjectedClockForTTL(t *testing.T) {
+	clk := clock.NewManualClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	cachedStore := NewCachedEventStore(store.NewMemoryEventStore(), &Config{
+		TTL:             time.Minute,
+		MaxAggregates:   100,
+		CleanupInterval: time.Hour,
+		Clock:           clk,
+	})
+	defer cachedStore.Close()
+
+	ctx := context.Background()
+	events := []eventing.Event[int64]{makeTestEvent(600, "Event1", 1)}
+	_ = cachedStore.AppendEvents(ctx, 600, toStorableEvents(events), 0)
+	_, _ = cachedStore.LoadEvents(ctx, 600, 0)
+
+	cachedStore.cleanupExpiredCache()
+	assert.Len(t, cachedStore.cache.aggregateCache, 1)
+
+	clk.Advance(2 * time.Minute)
+	cachedStore.cleanupExpiredCache()
+	assert.Empty(t, cachedStore.cache.aggregateCache)
+}
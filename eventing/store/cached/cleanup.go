@@ -6,12 +6,15 @@ import (
 
 // startCleanupWorker 启动定期清理过期缓存。
 func (s *CachedEventStore[ID]) startCleanupWorker(interval time.Duration) {
-	ticker := time.NewTicker(interval)
+	ticker, err := s.clock.NewTicker(interval)
+	if err != nil {
+		return
+	}
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-ticker.C():
 			s.cleanupExpiredCache()
 		case <-s.stopCh:
 			return
@@ -24,10 +27,10 @@ func (s *CachedEventStore[ID]) cleanupExpiredCache() {
 	s.cache.mutex.Lock()
 	defer s.cache.mutex.Unlock()
 
-	now := time.Now()
+	now := s.clock.Now()
 	for key, cached := range s.cache.aggregateCache {
 		if now.Sub(cached.CreatedAt) > s.cache.ttl {
-			delete(s.cache.aggregateCache, key)
+			s.cache.removeUnsafe(key)
 			s.recordEviction()
 		}
 	}
@@ -40,6 +43,7 @@ func (s *CachedEventStore[ID]) ClearCache() {
 
 	count := len(s.cache.aggregateCache)
 	s.cache.aggregateCache = make(map[string]*CachedAggregate[ID])
+	s.cache.resetPartitionsUnsafe()
 
 	s.stats.mutex.Lock()
 	s.stats.Evictions += int64(count)
@@ -59,6 +63,9 @@ func (s *CachedEventStore[ID]) Close() error {
 		if s.stopCh != nil {
 			close(s.stopCh)
 		}
+		if s.l2 != nil {
+			s.l2.close()
+		}
 	})
 
 	if closer, ok := s.store.(interface{ Close() error }); ok {
@@ -1,12 +1,32 @@
 package cached
 
-import "time"
+import (
+	"time"
+
+	"gochen/clock"
+)
 
 // Config 缓存配置，用于控制事件存储的缓存行为（TTL、最大聚合数、清理间隔）。
 type Config struct {
 	TTL             time.Duration // 缓存过期时间（默认: 5分钟）
-	MaxAggregates   int           // 最大缓存聚合数（默认: 1000）
+	MaxAggregates   int           // 最大缓存聚合数（默认: 1000），不含 MaxAggregatesPerType 中单独限额的类型
 	CleanupInterval time.Duration // 清理间隔（默认: 1分钟）
+
+	// EvictionPolicy 淘汰策略（lru/lfu/arc，默认: lru；未知值按 lru 处理）。
+	EvictionPolicy EvictionPolicy
+	// NewPolicy 自定义淘汰策略工厂（可选），设置后优先于 EvictionPolicy；capacity 为对应分区的容量。
+	NewPolicy func(capacity int) IEvictionPolicy
+	// MaxAggregatesPerType 按聚合类型单独限额（可选）：这些类型使用独立的容量与淘汰顺序，
+	// 不占用也不挤占 MaxAggregates 的共享容量。
+	MaxAggregatesPerType map[string]int
+
+	// SecondLevel 进程内缓存之下的共享缓存层（可选，例如 Redis，见 cached/redis）。
+	SecondLevel ISecondLevelCache
+	// SecondLevelTTL 共享缓存条目的过期时间（默认与 TTL 相同）。
+	SecondLevelTTL time.Duration
+
+	// Clock 用于访问时间、TTL 过期判断与定期清理（默认真实时钟）；测试中可注入 clock.ManualClock。
+	Clock clock.IClock
 }
 
 const defaultMaxAggregates = 1000
@@ -17,5 +37,6 @@ func DefaultConfig() *Config {
 		TTL:             5 * time.Minute,
 		MaxAggregates:   defaultMaxAggregates,
 		CleanupInterval: 1 * time.Minute,
+		EvictionPolicy:  EvictionLRU,
 	}
 }
@@ -0,0 +1,286 @@
+package cached
+
+import (
+	"container/list"
+	"strings"
+)
+
+// EvictionPolicy 缓存淘汰策略名称。
+type EvictionPolicy string
+
+const (
+	// EvictionLRU 淘汰最久未访问的聚合（默认）。
+	EvictionLRU EvictionPolicy = "lru"
+	// EvictionLFU 淘汰访问次数最少的聚合，次数相同时淘汰最久未访问的。
+	EvictionLFU EvictionPolicy = "lfu"
+	// EvictionARC 自适应替换缓存：在"最近访问"与"频繁访问"之间自动平衡，抗一次性扫描污染。
+	EvictionARC EvictionPolicy = "arc"
+)
+
+// IEvictionPolicy 定义缓存淘汰策略。
+//
+// 实现只维护键的淘汰顺序，不持有缓存数据；所有操作须为 O(1)（均摊）。
+// 调用方在持有缓存锁时调用，实现无需自行加锁。
+type IEvictionPolicy interface {
+	// Name 返回策略名称（用于统计与诊断）。
+	Name() string
+	// Add 记录新写入的键。
+	Add(key string)
+	// Touch 记录键被访问。
+	Touch(key string)
+	// Remove 移除键（失效/过期），不计入淘汰。
+	Remove(key string)
+	// Evict 选出并移除一个淘汰对象；没有可淘汰的键时返回 false。
+	Evict() (key string, ok bool)
+}
+
+// NewEvictionPolicy 按名称创建淘汰策略；名称为空或未知时使用 LRU。
+func NewEvictionPolicy(policy EvictionPolicy, capacity int) IEvictionPolicy {
+	switch EvictionPolicy(strings.ToLower(strings.TrimSpace(string(policy)))) {
+	case EvictionLFU:
+		return NewLFUPolicy()
+	case EvictionARC:
+		return NewARCPolicy(capacity)
+	default:
+		return NewLRUPolicy()
+	}
+}
+
+// LRUPolicy 基于双向链表的 LRU 策略。
+type LRUPolicy struct {
+	order *list.List // Front 为最近访问
+	items map[string]*list.Element
+}
+
+// NewLRUPolicy 创建 LRU 策略。
+func NewLRUPolicy() *LRUPolicy {
+	return &LRUPolicy{order: list.New(), items: make(map[string]*list.Element)}
+}
+
+// Name 返回 "lru"。
+func (p *LRUPolicy) Name() string { return string(EvictionLRU) }
+
+// Add 记录新写入的键。
+func (p *LRUPolicy) Add(key string) {
+	if elem, ok := p.items[key]; ok {
+		p.order.MoveToFront(elem)
+		return
+	}
+	p.items[key] = p.order.PushFront(key)
+}
+
+// Touch 把键移到最近访问位置。
+func (p *LRUPolicy) Touch(key string) {
+	if elem, ok := p.items[key]; ok {
+		p.order.MoveToFront(elem)
+	}
+}
+
+// Remove 移除键。
+func (p *LRUPolicy) Remove(key string) {
+	if elem, ok := p.items[key]; ok {
+		p.order.Remove(elem)
+		delete(p.items, key)
+	}
+}
+
+// Evict 淘汰最久未访问的键。
+func (p *LRUPolicy) Evict() (string, bool) {
+	elem := p.order.Back()
+	if elem == nil {
+		return "", false
+	}
+	key := elem.Value.(string)
+	p.order.Remove(elem)
+	delete(p.items, key)
+	return key, true
+}
+
+// LFUPolicy 基于频次桶的 O(1) LFU 策略。
+type LFUPolicy struct {
+	items   map[string]*lfuItem
+	buckets map[int]*list.List // 频次 -> 键链表（Front 为最近访问）
+	minFreq int
+}
+
+type lfuItem struct {
+	freq int
+	elem *list.Element
+}
+
+// NewLFUPolicy 创建 LFU 策略。
+func NewLFUPolicy() *LFUPolicy {
+	return &LFUPolicy{items: make(map[string]*lfuItem), buckets: make(map[int]*list.List)}
+}
+
+// Name 返回 "lfu"。
+func (p *LFUPolicy) Name() string { return string(EvictionLFU) }
+
+// Add 以频次 1 记录新写入的键。
+func (p *LFUPolicy) Add(key string) {
+	if _, ok := p.items[key]; ok {
+		p.Touch(key)
+		return
+	}
+	p.items[key] = &lfuItem{freq: 1, elem: p.bucket(1).PushFront(key)}
+	p.minFreq = 1
+}
+
+// Touch 把键的访问频次加一。
+func (p *LFUPolicy) Touch(key string) {
+	item, ok := p.items[key]
+	if !ok {
+		return
+	}
+	p.unlink(item)
+	if p.minFreq == item.freq && p.buckets[item.freq] == nil {
+		p.minFreq++
+	}
+	item.freq++
+	item.elem = p.bucket(item.freq).PushFront(key)
+}
+
+// Remove 移除键。
+func (p *LFUPolicy) Remove(key string) {
+	if item, ok := p.items[key]; ok {
+		p.unlink(item)
+		delete(p.items, key)
+	}
+}
+
+// Evict 淘汰频次最低且最久未访问的键。
+func (p *LFUPolicy) Evict() (string, bool) {
+	if len(p.items) == 0 {
+		return "", false
+	}
+	// Remove 可能清空最低频次桶，此时向上查找；minFreq 始终不大于实际最低频次。
+	for p.buckets[p.minFreq] == nil {
+		p.minFreq++
+	}
+	elem := p.buckets[p.minFreq].Back()
+	key := elem.Value.(string)
+	p.unlink(p.items[key])
+	delete(p.items, key)
+	return key, true
+}
+
+func (p *LFUPolicy) bucket(freq int) *list.List {
+	b, ok := p.buckets[freq]
+	if !ok {
+		b = list.New()
+		p.buckets[freq] = b
+	}
+	return b
+}
+
+// unlink 从频次桶中摘除，空桶随即删除。
+func (p *LFUPolicy) unlink(item *lfuItem) {
+	b := p.buckets[item.freq]
+	b.Remove(item.elem)
+	if b.Len() == 0 {
+		delete(p.buckets, item.freq)
+	}
+}
+
+// ARCPolicy 自适应替换缓存（Adaptive Replacement Cache）策略。
+//
+// T1 保存只访问过一次的键，T2 保存访问过多次的键；B1/B2 为对应的"幽灵"列表，
+// 只记录最近被淘汰的键。幽灵命中时调整 T1 的目标大小 p，使策略在近期性与频率之间自适应。
+type ARCPolicy struct {
+	capacity int
+	p        int // T1 的目标大小
+	t1, t2   *list.List
+	b1, b2   *list.List
+	items    map[string]*arcItem
+}
+
+type arcItem struct {
+	list *list.List
+	elem *list.Element
+}
+
+// NewARCPolicy 创建 ARC 策略；capacity 为缓存容量（决定幽灵列表长度与 p 的上限）。
+func NewARCPolicy(capacity int) *ARCPolicy {
+	if capacity <= 0 {
+		capacity = defaultMaxAggregates
+	}
+	return &ARCPolicy{
+		capacity: capacity,
+		t1:       list.New(),
+		t2:       list.New(),
+		b1:       list.New(),
+		b2:       list.New(),
+		items:    make(map[string]*arcItem),
+	}
+}
+
+// Name 返回 "arc"。
+func (p *ARCPolicy) Name() string { return string(EvictionARC) }
+
+// Add 记录新写入的键；幽灵命中时调整 p 并直接进入 T2。
+func (p *ARCPolicy) Add(key string) {
+	item, ok := p.items[key]
+	switch {
+	case !ok:
+		p.push(p.t1, key)
+	case item.list == p.t1 || item.list == p.t2:
+		p.Touch(key)
+	case item.list == p.b1:
+		p.p = min(p.capacity, p.p+max(1, p.b2.Len()/p.b1.Len()))
+		p.move(item, p.t2)
+	default: // b2
+		p.p = max(0, p.p-max(1, p.b1.Len()/p.b2.Len()))
+		p.move(item, p.t2)
+	}
+	p.trimGhosts()
+}
+
+// Touch 把命中的常驻键移到 T2 的最近访问位置。
+func (p *ARCPolicy) Touch(key string) {
+	if item, ok := p.items[key]; ok && (item.list == p.t1 || item.list == p.t2) {
+		p.move(item, p.t2)
+	}
+}
+
+// Remove 移除键（包括幽灵记录）。
+func (p *ARCPolicy) Remove(key string) {
+	if item, ok := p.items[key]; ok {
+		item.list.Remove(item.elem)
+		delete(p.items, key)
+	}
+}
+
+// Evict 按 p 从 T1 或 T2 淘汰最久未访问的键，并把它记入对应的幽灵列表。
+func (p *ARCPolicy) Evict() (string, bool) {
+	var from, ghost *list.List
+	switch {
+	case p.t1.Len() > 0 && (p.t1.Len() > p.p || p.t2.Len() == 0):
+		from, ghost = p.t1, p.b1
+	case p.t2.Len() > 0:
+		from, ghost = p.t2, p.b2
+	default:
+		return "", false
+	}
+	key := from.Back().Value.(string)
+	p.move(p.items[key], ghost)
+	p.trimGhosts()
+	return key, true
+}
+
+func (p *ARCPolicy) push(l *list.List, key string) {
+	p.items[key] = &arcItem{list: l, elem: l.PushFront(key)}
+}
+
+func (p *ARCPolicy) move(item *arcItem, to *list.List) {
+	key := item.list.Remove(item.elem).(string)
+	item.list, item.elem = to, to.PushFront(key)
+}
+
+// trimGhosts 把幽灵列表长度限制在容量以内。
+func (p *ARCPolicy) trimGhosts() {
+	for _, ghost := range []*list.List{p.b1, p.b2} {
+		for ghost.Len() > p.capacity {
+			delete(p.items, ghost.Remove(ghost.Back()).(string))
+		}
+	}
+}
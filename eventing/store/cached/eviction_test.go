@@ -0,0 +1,92 @@
+package cached
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gochen/eventing"
+	"gochen/eventing/store"
+)
+
+func evictAll(p IEvictionPolicy) []string {
+	var keys []string
+	for {
+		key, ok := p.Evict()
+		if !ok {
+			return keys
+		}
+		keys = append(keys, key)
+	}
+}
+
+// TestEvictionPolicies 验证 LRU/LFU/ARC 的淘汰顺序。
+func TestEvictionPolicies(t *testing.T) {
+	lru := NewLRUPolicy()
+	lru.Add("a")
+	lru.Add("b")
+	lru.Add("c")
+	lru.Touch("a")
+	lru.Remove("c")
+	assert.Equal(t, []string{"b", "a"}, evictAll(lru))
+
+	lfu := NewLFUPolicy()
+	lfu.Add("a")
+	lfu.Add("b")
+	lfu.Add("c")
+	lfu.Touch("a")
+	lfu.Touch("a")
+	lfu.Touch("c")
+	lfu.Remove("b")
+	assert.Equal(t, []string{"c", "a"}, evictAll(lfu))
+
+	// ARC：访问过两次的键进入 T2，一次性扫描的键优先淘汰。
+	arc := NewARCPolicy(2)
+	arc.Add("hot")
+	arc.Touch("hot")
+	arc.Add("scan-1")
+	key, ok := arc.Evict()
+	require.True(t, ok)
+	assert.Equal(t, "scan-1", key)
+	// 幽灵命中：重新写入被淘汰的键直接进入 T2，并调大 T1 目标大小，此后优先从 T2 淘汰。
+	arc.Add("scan-1")
+	arc.Add("scan-2")
+	assert.Equal(t, []string{"hot", "scan-1", "scan-2"}, evictAll(arc))
+
+	assert.Equal(t, "lru", NewEvictionPolicy("unknown", 1).Name())
+	assert.Equal(t, "arc", NewEvictionPolicy(" ARC ", 1).Name())
+}
+
+// TestCachedEventStore_PerTypeLimits 验证按聚合类型的独立限额与策略统计。
+func TestCachedEventStore_PerTypeLimits(t *testing.T) {
+	ctx := context.Background()
+	memStore := store.NewMemoryEventStore()
+	cachedStore := NewCachedEventStore(memStore, &Config{
+		TTL:                  time.Minute,
+		MaxAggregates:        10,
+		CleanupInterval:      time.Minute,
+		EvictionPolicy:       EvictionLFU,
+		MaxAggregatesPerType: map[string]int{"Hot": 1},
+	})
+	defer cachedStore.Close()
+
+	for id := int64(1); id <= 3; id++ {
+		evt := *eventing.NewEvent[int64](id, "Hot", "Created", 1, nil)
+		require.NoError(t, memStore.AppendEvents(ctx, id, toStorableEvents([]eventing.Event[int64]{evt}), 0))
+		_, err := cachedStore.LoadEventsByType(ctx, "Hot", id, 0)
+		require.NoError(t, err)
+	}
+	evt := makeTestEvent(100, "Created", 1)
+	require.NoError(t, memStore.AppendEvents(ctx, 100, toStorableEvents([]eventing.Event[int64]{evt}), 0))
+	_, err := cachedStore.LoadEvents(ctx, 100, 0)
+	require.NoError(t, err)
+
+	stats := cachedStore.CacheStats()
+	assert.Equal(t, 2, stats.CacheSize, "Hot is capped at 1 without evicting other types")
+	assert.Equal(t, int64(2), stats.Evictions)
+	assert.Equal(t, 11, stats.MaxSize)
+	assert.Equal(t, "lfu", stats.EvictionPolicy)
+}
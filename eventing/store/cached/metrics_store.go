@@ -50,6 +50,11 @@ func (m *MetricsEventStore[ID]) LoadEvents(ctx context.Context, aggregateID ID,
 	return evs, err
 }
 
+// LoadEventsIter 以迭代器加载聚合事件（委托到底层存储，按页读取不计入 Load 指标）。
+func (m *MetricsEventStore[ID]) LoadEventsIter(ctx context.Context, aggregateID ID, afterVersion uint64) (estore.IEventIterator[ID], error) {
+	return m.inner.LoadEventsIter(ctx, aggregateID, afterVersion)
+}
+
 // LoadEventsByType 加载指定聚合类型的事件。
 func (m *MetricsEventStore[ID]) LoadEventsByType(ctx context.Context, aggregateType string, aggregateID ID, afterVersion uint64) ([]eventing.Event[ID], error) {
 	start := time.Now()
@@ -92,5 +97,15 @@ func (m *MetricsEventStore[ID]) GetAggregateVersion(ctx context.Context, aggrega
 	return m.inner.GetAggregateVersion(ctx, aggregateID)
 }
 
+// CountEvents 统计聚合事件数量。
+func (m *MetricsEventStore[ID]) CountEvents(ctx context.Context, aggregateID ID) (uint64, error) {
+	return m.inner.CountEvents(ctx, aggregateID)
+}
+
+// HeadVersion 获取指定聚合类型下聚合的最新版本号。
+func (m *MetricsEventStore[ID]) HeadVersion(ctx context.Context, aggregateType string, aggregateID ID) (uint64, error) {
+	return m.inner.HeadVersion(ctx, aggregateType, aggregateID)
+}
+
 // 接口断言。
 var _ estore.IEventStreamStore[int64] = (*MetricsEventStore[int64])(nil)
@@ -0,0 +1,114 @@
+// Package redis 提供基于 Redis 的 CachedEventStore 共享缓存层（cached.ISecondLevelCache）。
+//
+// 事件列表写入 Redis 字符串键，追加事件时删除键并通过 Pub/Sub 广播缓存键，其他实例据此失效进程内缓存。
+// 框架核心不引入 Redis 客户端依赖：通过 IClient 适配任意客户端（go-redis 适配示例见 README.md）。
+package redis
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gochen/errors"
+	"gochen/eventing/store/cached"
+)
+
+const (
+	// DefaultKeyPrefix 是默认的键前缀。
+	DefaultKeyPrefix = "gochen:eventcache:"
+	// DefaultChannel 是默认的失效广播频道。
+	DefaultChannel = "gochen:eventcache:invalidate"
+)
+
+// IClient 是共享缓存所需的最小 Redis 客户端能力。
+type IClient interface {
+	// Get 读取键值；键不存在时返回 found=false 且 err=nil。
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	// Set 写入键值；ttl<=0 表示不过期。
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Del 删除键；键不存在不视为错误。
+	Del(ctx context.Context, keys ...string) error
+	// Publish 向频道发布消息。
+	Publish(ctx context.Context, channel, message string) error
+	// Subscribe 订阅频道，消息到达时回调 handler，直到返回的取消函数被调用。
+	Subscribe(ctx context.Context, channel string, handler func(message string)) (unsubscribe func(), err error)
+}
+
+// Config 定义 Redis 共享缓存配置。
+type Config struct {
+	// KeyPrefix 为空时使用 DefaultKeyPrefix。
+	KeyPrefix string
+	// Channel 为空时使用 DefaultChannel；同一频道内的实例互相广播失效。
+	Channel string
+}
+
+// Cache 是基于 Redis 的共享缓存层。
+type Cache struct {
+	client IClient
+	config Config
+}
+
+// NewCache 创建 Redis 共享缓存层。
+func NewCache(client IClient, config Config) (*Cache, error) {
+	if client == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "redis event cache client cannot be nil")
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = DefaultKeyPrefix
+	}
+	if config.Channel == "" {
+		config.Channel = DefaultChannel
+	}
+	return &Cache{client: client, config: config}, nil
+}
+
+// Get 读取共享缓存条目。
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, found, err := c.client.Get(ctx, c.config.KeyPrefix+key)
+	if err != nil {
+		return nil, false, errors.Wrap(err, errors.Dependency, "get event cache entry from redis failed").WithContext("key", key)
+	}
+	return data, found, nil
+}
+
+// Set 写入共享缓存条目。
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.client.Set(ctx, c.config.KeyPrefix+key, value, ttl); err != nil {
+		return errors.Wrap(err, errors.Dependency, "set event cache entry to redis failed").WithContext("key", key)
+	}
+	return nil
+}
+
+// Invalidate 删除共享缓存条目，并逐个广播失效键。
+func (c *Cache) Invalidate(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.config.KeyPrefix + key
+	}
+	if err := c.client.Del(ctx, prefixed...); err != nil {
+		return errors.Wrap(err, errors.Dependency, "delete event cache entries from redis failed").WithContext("keys", strings.Join(keys, ","))
+	}
+	for _, key := range keys {
+		if err := c.client.Publish(ctx, c.config.Channel, key); err != nil {
+			return errors.Wrap(err, errors.Dependency, "publish event cache invalidation failed").WithContext("key", key)
+		}
+	}
+	return nil
+}
+
+// Subscribe 订阅失效广播。
+func (c *Cache) Subscribe(ctx context.Context, handler func(key string)) (func(), error) {
+	if handler == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "invalidation handler cannot be nil")
+	}
+	unsubscribe, err := c.client.Subscribe(ctx, c.config.Channel, handler)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Dependency, "subscribe event cache invalidation failed").WithContext("channel", c.config.Channel)
+	}
+	return unsubscribe, nil
+}
+
+var _ cached.ISecondLevelCache = (*Cache)(nil)
@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+)
+
+// fakeClient 是内存版 IClient，同步投递发布的消息。
+type fakeClient struct {
+	mu       sync.Mutex
+	data     map[string][]byte
+	handlers map[string][]func(string)
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{data: make(map[string][]byte), handlers: make(map[string][]func(string))}
+}
+
+func (c *fakeClient) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.data[key]
+	return v, ok, nil
+}
+
+func (c *fakeClient) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data[key] = value
+	return nil
+}
+
+func (c *fakeClient) Del(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.data, key)
+	}
+	return nil
+}
+
+func (c *fakeClient) Publish(_ context.Context, channel, message string) error {
+	c.mu.Lock()
+	handlers := append([]func(string){}, c.handlers[channel]...)
+	c.mu.Unlock()
+	for _, h := range handlers {
+		h(message)
+	}
+	return nil
+}
+
+func (c *fakeClient) Subscribe(_ context.Context, channel string, handler func(string)) (func(), error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[channel] = append(c.handlers[channel], handler)
+	return func() {}, nil
+}
+
+func TestCache_InvalidatePublishes(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient()
+	cache, err := NewCache(client, Config{KeyPrefix: "app:"})
+	require.NoError(t, err)
+
+	var received []string
+	_, err = cache.Subscribe(ctx, func(key string) { received = append(received, key) })
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Set(ctx, "Order:1", []byte("[]"), time.Minute))
+	_, found, err := client.Get(ctx, "app:Order:1")
+	require.NoError(t, err)
+	require.True(t, found)
+
+	require.NoError(t, cache.Invalidate(ctx, "Order:1", ":1"))
+	_, found, err = cache.Get(ctx, "Order:1")
+	require.NoError(t, err)
+	require.False(t, found)
+	require.Equal(t, []string{"Order:1", ":1"}, received)
+}
+
+func TestNewCache_NilClient(t *testing.T) {
+	_, err := NewCache(nil, Config{})
+	require.True(t, errors.Is(err, errors.InvalidInput))
+}
@@ -0,0 +1,191 @@
+package cached
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gochen/eventing"
+	"gochen/logging"
+	"gochen/messaging"
+)
+
+// ISecondLevelCache 是进程内缓存之下的共享缓存层（L2），供水平扩展的多个实例共享已加载的事件列表。
+//
+// 键为 CachedEventStore 的缓存键（"<aggregate_type>:<aggregate_id>"），值为序列化后的事件列表。
+// 实现（例如 cached/redis）负责键前缀、过期与跨实例失效广播。
+type ISecondLevelCache interface {
+	// Get 读取共享缓存；不存在时返回 found=false 且 err=nil。
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	// Set 写入共享缓存。
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Invalidate 删除共享缓存条目，并向其他实例广播失效。
+	Invalidate(ctx context.Context, keys ...string) error
+	// Subscribe 订阅其他实例广播的失效键，直到返回的取消函数被调用。
+	Subscribe(ctx context.Context, handler func(key string)) (unsubscribe func(), err error)
+}
+
+// secondLevel 把 ISecondLevelCache 接入 CachedEventStore。
+//
+// 一致性约束：
+//   - 追加事件后先失效本地缓存，再删除共享条目并广播，其他实例收到广播后失效本地缓存；
+//   - 共享缓存命中时用底层 HeadVersion/GetAggregateVersion 校验最新版本，版本不一致视为未命中，
+//     因此"并发加载写回旧列表"或广播丢失都不会返回过期事件列表；
+//   - 共享缓存读写失败只记录日志并回退到底层存储，不影响主流程。
+type secondLevel[ID comparable] struct {
+	owner       *CachedEventStore[ID]
+	cache       ISecondLevelCache
+	ttl         time.Duration
+	logger      logging.ILogger
+	unsubscribe func()
+}
+
+// cachedEventWire 是共享缓存中的事件编码；载荷保留为 JSON bytes，与 SQL 存储的读取语义一致。
+type cachedEventWire[ID comparable] struct {
+	ID            string                `json:"id"`
+	Kind          messaging.MessageKind `json:"kind,omitempty"`
+	Type          string                `json:"type"`
+	Timestamp     time.Time             `json:"timestamp"`
+	Payload       json.RawMessage       `json:"payload,omitempty"`
+	Metadata      *messaging.Metadata   `json:"metadata,omitempty"`
+	AggregateID   ID                    `json:"aggregate_id"`
+	AggregateType string                `json:"aggregate_type"`
+	Version       uint64                `json:"version"`
+	SchemaVersion int                   `json:"schema_version"`
+}
+
+func newSecondLevel[ID comparable](owner *CachedEventStore[ID], cache ISecondLevelCache, ttl time.Duration) *secondLevel[ID] {
+	l2 := &secondLevel[ID]{
+		owner:  owner,
+		cache:  cache,
+		ttl:    ttl,
+		logger: logging.ComponentLogger("eventstore.cache.l2"),
+	}
+	unsubscribe, err := cache.Subscribe(context.Background(), func(key string) {
+		owner.invalidateKey(key)
+	})
+	if err != nil {
+		l2.logger.Warn(context.Background(), "subscribe cache invalidation failed; relying on version checks and TTL", logging.Error(err))
+	} else {
+		l2.unsubscribe = unsubscribe
+	}
+	return l2
+}
+
+// load 读取共享缓存并校验版本；命中时回填本地缓存并按 afterVersion 过滤。
+func (l *secondLevel[ID]) load(ctx context.Context, aggregateType string, aggregateID ID, afterVersion uint64) ([]eventing.Event[ID], bool) {
+	key := cacheKey(aggregateType, aggregateID)
+	data, found, err := l.cache.Get(ctx, key)
+	if err != nil {
+		l.logger.Warn(ctx, "load from second-level cache failed", logging.String("key", key), logging.Error(err))
+		return nil, false
+	}
+	if !found {
+		return nil, false
+	}
+	events, err := decodeEvents[ID](data)
+	if err != nil || len(events) == 0 {
+		l.logger.Warn(ctx, "discard undecodable second-level cache entry", logging.String("key", key), logging.Error(err))
+		return nil, false
+	}
+
+	var head uint64
+	if aggregateType != "" {
+		head, err = l.owner.store.HeadVersion(ctx, aggregateType, aggregateID)
+	} else {
+		head, err = l.owner.store.GetAggregateVersion(ctx, aggregateID)
+	}
+	if err != nil || head != events[len(events)-1].Version {
+		return nil, false
+	}
+
+	l.owner.cacheAggregate(key, events)
+	if afterVersion == 0 {
+		return events, true
+	}
+	var result []eventing.Event[ID]
+	for _, evt := range events {
+		if evt.Version > afterVersion {
+			result = append(result, evt)
+		}
+	}
+	return result, true
+}
+
+// store 把完整事件列表写入共享缓存。
+func (l *secondLevel[ID]) store(ctx context.Context, key string, events []eventing.Event[ID]) {
+	data, err := encodeEvents(events)
+	if err != nil {
+		l.logger.Warn(ctx, "encode events for second-level cache failed", logging.String("key", key), logging.Error(err))
+		return
+	}
+	if err := l.cache.Set(ctx, key, data, l.ttl); err != nil {
+		l.logger.Warn(ctx, "store to second-level cache failed", logging.String("key", key), logging.Error(err))
+	}
+}
+
+// invalidate 删除共享缓存条目并广播失效。
+func (l *secondLevel[ID]) invalidate(ctx context.Context, keys ...string) {
+	if err := l.cache.Invalidate(ctx, keys...); err != nil {
+		l.logger.Warn(ctx, "invalidate second-level cache failed", logging.Any("keys", keys), logging.Error(err))
+	}
+}
+
+func (l *secondLevel[ID]) close() {
+	if l.unsubscribe != nil {
+		l.unsubscribe()
+	}
+}
+
+func encodeEvents[ID comparable](events []eventing.Event[ID]) ([]byte, error) {
+	wire := make([]cachedEventWire[ID], len(events))
+	for i := range events {
+		evt := &events[i]
+		payload, err := json.Marshal(evt.Payload)
+		if err != nil {
+			return nil, err
+		}
+		wire[i] = cachedEventWire[ID]{
+			ID:            evt.ID,
+			Kind:          evt.Kind,
+			Type:          evt.Type,
+			Timestamp:     evt.Timestamp,
+			Payload:       payload,
+			Metadata:      evt.Metadata,
+			AggregateID:   evt.AggregateID,
+			AggregateType: evt.AggregateType,
+			Version:       evt.Version,
+			SchemaVersion: evt.SchemaVersion,
+		}
+	}
+	return json.Marshal(wire)
+}
+
+func decodeEvents[ID comparable](data []byte) ([]eventing.Event[ID], error) {
+	var wire []cachedEventWire[ID]
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return nil, err
+	}
+	events := make([]eventing.Event[ID], len(wire))
+	for i, w := range wire {
+		var payload any
+		if len(w.Payload) > 0 && string(w.Payload) != "null" {
+			payload = w.Payload
+		}
+		events[i] = eventing.Event[ID]{
+			Message: messaging.Message{
+				ID:        w.ID,
+				Kind:      w.Kind,
+				Type:      w.Type,
+				Timestamp: w.Timestamp,
+				Payload:   messaging.NewPayload(payload),
+				Metadata:  w.Metadata,
+			},
+			AggregateID:   w.AggregateID,
+			AggregateType: w.AggregateType,
+			Version:       w.Version,
+			SchemaVersion: w.SchemaVersion,
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,108 @@
+package cached
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gochen/eventing"
+	"gochen/eventing/store"
+)
+
+// memorySecondLevel 是多个实例共享的内存版 ISecondLevelCache，同步广播失效。
+type memorySecondLevel struct {
+	mu       sync.Mutex
+	data     map[string][]byte
+	handlers []func(string)
+}
+
+func (c *memorySecondLevel) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.data[key]
+	return v, ok, nil
+}
+
+func (c *memorySecondLevel) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data[key] = value
+	return nil
+}
+
+func (c *memorySecondLevel) Invalidate(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	for _, key := range keys {
+		delete(c.data, key)
+	}
+	handlers := append([]func(string){}, c.handlers...)
+	c.mu.Unlock()
+	for _, key := range keys {
+		for _, h := range handlers {
+			h(key)
+		}
+	}
+	return nil
+}
+
+func (c *memorySecondLevel) Subscribe(_ context.Context, handler func(string)) (func(), error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers = append(c.handlers, handler)
+	return func() {}, nil
+}
+
+// countingStore 统计底层事件加载次数。
+type countingStore struct {
+	store.IEventStreamStore[int64]
+	loads atomic.Int64
+}
+
+func (s *countingStore) LoadEvents(ctx context.Context, aggregateID int64, afterVersion uint64) ([]eventing.Event[int64], error) {
+	s.loads.Add(1)
+	return s.IEventStreamStore.LoadEvents(ctx, aggregateID, afterVersion)
+}
+
+// TestCachedEventStore_SecondLevel 验证实例间共享缓存、广播失效与版本校验。
+func TestCachedEventStore_SecondLevel(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingStore{IEventStreamStore: store.NewMemoryEventStore()}
+	l2 := &memorySecondLevel{data: make(map[string][]byte)}
+	config := func() *Config {
+		return &Config{TTL: time.Minute, MaxAggregates: 10, CleanupInterval: time.Minute, SecondLevel: l2}
+	}
+	a := NewCachedEventStore[int64](inner, config())
+	defer a.Close()
+	b := NewCachedEventStore[int64](inner, config())
+	defer b.Close()
+
+	require.NoError(t, a.AppendEvents(ctx, 1, toStorableEvents([]eventing.Event[int64]{makeTestEvent(1, "Created", 1)}), 0))
+	_, err := a.LoadEvents(ctx, 1, 0)
+	require.NoError(t, err)
+	loaded, err := b.LoadEvents(ctx, 1, 0)
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, int64(1), inner.loads.Load(), "second instance served from shared cache")
+
+	// 实例 A 追加事件：广播失效实例 B 的本地缓存。
+	require.NoError(t, a.AppendEvents(ctx, 1, toStorableEvents([]eventing.Event[int64]{makeTestEvent(1, "Updated", 2)}), 1))
+	loaded, err = b.LoadEvents(ctx, 1, 0)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+
+	// 共享缓存中的旧列表（例如并发加载写回）通过版本校验被拒绝。
+	stale, err := encodeEvents([]eventing.Event[int64]{makeTestEvent(1, "Created", 1)})
+	require.NoError(t, err)
+	require.NoError(t, l2.Set(ctx, cacheKey("", int64(1)), stale, time.Minute))
+	c := NewCachedEventStore[int64](inner, config())
+	defer c.Close()
+	loaded, err = c.LoadEvents(ctx, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, uint64(2), loaded[0].Version)
+}
@@ -30,6 +30,10 @@ func (s *CachedEventStore[ID]) CacheStats() monitoring.CacheStats {
 	s.cache.mutex.RLock()
 	cacheSize := len(s.cache.aggregateCache)
 	maxSize := s.cache.maxAggregates
+	for _, limit := range s.cache.typeLimits {
+		maxSize += limit
+	}
+	policy := s.cache.partitions[""].policy.Name()
 	ttlSeconds := s.cache.ttl.Seconds()
 	s.cache.mutex.RUnlock()
 
@@ -48,6 +52,7 @@ func (s *CachedEventStore[ID]) CacheStats() monitoring.CacheStats {
 		CacheSize:      cacheSize,
 		MaxSize:        maxSize,
 		TTLSeconds:     ttlSeconds,
+		EvictionPolicy: policy,
 	}
 }
 
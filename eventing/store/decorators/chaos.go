@@ -0,0 +1,194 @@
+package decorators
+
+import (
+	"context"
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	stderrors "errors"
+	mrand "math/rand"
+	"sync"
+	"time"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/store"
+)
+
+// ErrInjectedAppendFailure 标识由 ChaosEventStore 注入的追加失败，测试可用 errors.Is 识别。
+var ErrInjectedAppendFailure = stderrors.New("injected append failure")
+
+// ChaosConfig 定义事件存储故障注入配置。
+type ChaosConfig struct {
+	// AppendFailureRate AppendEvents 失败的概率，取值范围 [0, 1]。
+	AppendFailureRate float64
+
+	// FailAfterAppend 为 true 时先写入底层存储再返回错误，模拟“已提交但确认丢失”，用于验证重试幂等；
+	// 默认在写入前失败，底层存储不变。
+	FailAfterAppend bool
+
+	// Seed 伪随机数种子；0 表示随机种子。
+	Seed int64
+}
+
+// ChaosEventStore 是按概率让 AppendEvents 失败的事件存储装饰器，用于在集成测试中验证重试、幂等与 Saga 补偿。
+//
+// 行为：
+// - AppendEvents：按 AppendFailureRate 返回 errors.Database 错误（cause 为 ErrInjectedAppendFailure）；
+// - 其余读取方法透传到底层 store，不注入故障。
+//
+// 仅用于测试环境，不应出现在生产装配中。
+type ChaosEventStore[ID comparable] struct {
+	inner store.IEventStreamStore[ID]
+	cfg   ChaosConfig
+
+	mu     sync.Mutex
+	rng    *mrand.Rand
+	failed int64
+}
+
+// NewChaosEventStore 创建故障注入事件存储；inner 为 nil 或失败概率非法时返回 InvalidInput。
+func NewChaosEventStore[ID comparable](inner store.IEventStreamStore[ID], cfg *ChaosConfig) (*ChaosEventStore[ID], error) {
+	if inner == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	config := ChaosConfig{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.AppendFailureRate < 0 || config.AppendFailureRate > 1 {
+		return nil, errors.NewCode(errors.InvalidInput, "append failure rate must be within [0, 1]").
+			WithContext("value", config.AppendFailureRate)
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = chaosSeed()
+	}
+	return &ChaosEventStore[ID]{inner: inner, cfg: config, rng: mrand.New(mrand.NewSource(seed))}, nil
+}
+
+// Inner 返回被装饰的事件存储实现。
+func (s *ChaosEventStore[ID]) Inner() store.IEventStreamStore[ID] {
+	if s == nil {
+		return nil
+	}
+	return s.inner
+}
+
+// InjectedFailures 返回已注入的追加失败次数。
+func (s *ChaosEventStore[ID]) InjectedFailures() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.failed
+}
+
+// AppendEvents 按配置注入失败，否则透传到底层存储。
+func (s *ChaosEventStore[ID]) AppendEvents(ctx context.Context, aggregateID ID, events []eventing.IStorableEvent[ID], expectedVersion uint64) error {
+	if s == nil || s.inner == nil {
+		return errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	if !s.shouldFail() {
+		return s.inner.AppendEvents(ctx, aggregateID, events, expectedVersion)
+	}
+	if s.cfg.FailAfterAppend {
+		if err := s.inner.AppendEvents(ctx, aggregateID, events, expectedVersion); err != nil {
+			return err
+		}
+	}
+	return errors.NewCodeWithCause(errors.Database, "chaos: injected append failure", ErrInjectedAppendFailure).
+		WithContext("after_append", s.cfg.FailAfterAppend)
+}
+
+// LoadEvents 加载聚合事件。
+func (s *ChaosEventStore[ID]) LoadEvents(ctx context.Context, aggregateID ID, afterVersion uint64) ([]eventing.Event[ID], error) {
+	if s == nil || s.inner == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	return s.inner.LoadEvents(ctx, aggregateID, afterVersion)
+}
+
+// LoadEventsIter 以迭代器加载聚合事件。
+func (s *ChaosEventStore[ID]) LoadEventsIter(ctx context.Context, aggregateID ID, afterVersion uint64) (store.IEventIterator[ID], error) {
+	if s == nil || s.inner == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	return s.inner.LoadEventsIter(ctx, aggregateID, afterVersion)
+}
+
+// LoadEventsByType 加载指定聚合类型的事件。
+func (s *ChaosEventStore[ID]) LoadEventsByType(ctx context.Context, aggregateType string, aggregateID ID, afterVersion uint64) ([]eventing.Event[ID], error) {
+	if s == nil || s.inner == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	return s.inner.LoadEventsByType(ctx, aggregateType, aggregateID, afterVersion)
+}
+
+// StreamEvents 按游标遍历事件流。
+func (s *ChaosEventStore[ID]) StreamEvents(ctx context.Context, opts *store.StreamOptions) (*store.StreamResult[ID], error) {
+	if s == nil || s.inner == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	return s.inner.StreamEvents(ctx, opts)
+}
+
+// StreamAggregate 遍历指定聚合的事件流。
+func (s *ChaosEventStore[ID]) StreamAggregate(ctx context.Context, opts *store.AggregateStreamOptions[ID]) (*store.AggregateStreamResult[ID], error) {
+	if s == nil || s.inner == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	return s.inner.StreamAggregate(ctx, opts)
+}
+
+// HasAggregate 检查聚合是否存在。
+func (s *ChaosEventStore[ID]) HasAggregate(ctx context.Context, aggregateID ID) (bool, error) {
+	if s == nil || s.inner == nil {
+		return false, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	return s.inner.HasAggregate(ctx, aggregateID)
+}
+
+// GetAggregateVersion 获取聚合当前版本。
+func (s *ChaosEventStore[ID]) GetAggregateVersion(ctx context.Context, aggregateID ID) (uint64, error) {
+	if s == nil || s.inner == nil {
+		return 0, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	return s.inner.GetAggregateVersion(ctx, aggregateID)
+}
+
+// CountEvents 统计聚合事件数量。
+func (s *ChaosEventStore[ID]) CountEvents(ctx context.Context, aggregateID ID) (uint64, error) {
+	if s == nil || s.inner == nil {
+		return 0, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	return s.inner.CountEvents(ctx, aggregateID)
+}
+
+// HeadVersion 获取指定聚合类型下聚合的最新版本号。
+func (s *ChaosEventStore[ID]) HeadVersion(ctx context.Context, aggregateType string, aggregateID ID) (uint64, error) {
+	if s == nil || s.inner == nil {
+		return 0, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	return s.inner.HeadVersion(ctx, aggregateType, aggregateID)
+}
+
+func (s *ChaosEventStore[ID]) shouldFail() bool {
+	if s.cfg.AppendFailureRate <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rng.Float64() >= s.cfg.AppendFailureRate {
+		return false
+	}
+	s.failed++
+	return true
+}
+
+func chaosSeed() int64 {
+	var b [8]byte
+	if _, err := cryptorand.Read(b[:]); err == nil {
+		return int64(binary.LittleEndian.Uint64(b[:]))
+	}
+	return time.Now().UnixNano()
+}
+
+var _ store.IEventStreamStore[int64] = (*ChaosEventStore[int64])(nil)
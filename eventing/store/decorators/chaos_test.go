@@ -0,0 +1,61 @@
+package decorators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/store"
+)
+
+func TestChaosEventStore_FailsBeforeAppendByDefault(t *testing.T) {
+	base := store.NewMemoryEventStore()
+	es, err := NewChaosEventStore[int64](base, &ChaosConfig{AppendFailureRate: 1})
+	require.NoError(t, err)
+
+	evt := eventing.NewEvent[int64](1, "Agg", "Evt", 1, nil)
+	err = es.AppendEvents(context.Background(), 1, []eventing.IStorableEvent[int64]{evt}, 0)
+	require.True(t, errors.Is(err, errors.Database))
+	require.True(t, errors.Is(err, ErrInjectedAppendFailure))
+	require.Equal(t, int64(1), es.InjectedFailures())
+
+	version, err := base.GetAggregateVersion(context.Background(), 1)
+	require.NoError(t, err)
+	require.Zero(t, version)
+}
+
+func TestChaosEventStore_FailAfterAppendSimulatesLostAck(t *testing.T) {
+	base := store.NewMemoryEventStore()
+	es, err := NewChaosEventStore[int64](base, &ChaosConfig{AppendFailureRate: 1, FailAfterAppend: true})
+	require.NoError(t, err)
+
+	evt := eventing.NewEvent[int64](1, "Agg", "Evt", 1, nil)
+	err = es.AppendEvents(context.Background(), 1, []eventing.IStorableEvent[int64]{evt}, 0)
+	require.True(t, errors.Is(err, ErrInjectedAppendFailure))
+
+	events, err := es.LoadEvents(context.Background(), 1, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+}
+
+func TestChaosEventStore_FailureRateIsSeededAndValidated(t *testing.T) {
+	_, err := NewChaosEventStore[int64](store.NewMemoryEventStore(), &ChaosConfig{AppendFailureRate: 1.5})
+	require.True(t, errors.Is(err, errors.InvalidInput))
+
+	run := func() []bool {
+		es, err := NewChaosEventStore[int64](store.NewMemoryEventStore(), &ChaosConfig{AppendFailureRate: 0.5, Seed: 7})
+		require.NoError(t, err)
+		outcomes := make([]bool, 0, 20)
+		for i := int64(1); i <= 20; i++ {
+			evt := eventing.NewEvent[int64](i, "Agg", "Evt", 1, nil)
+			outcomes = append(outcomes, es.AppendEvents(context.Background(), i, []eventing.IStorableEvent[int64]{evt}, 0) == nil)
+		}
+		return outcomes
+	}
+	first := run()
+	require.Equal(t, first, run())
+	require.Contains(t, first, true)
+	require.Contains(t, first, false)
+}
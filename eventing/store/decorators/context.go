@@ -8,11 +8,11 @@ import (
 	"gochen/eventing/store"
 )
 
-// ContextAwareEventStore 是贯通 tenant/trace/operator 的事件存储装饰器。
+// ContextAwareEventStore 是贯通 tenant/trace/operator/correlation/causation 的事件存储装饰器。
 //
 // 行为：
 //   - AppendEvents：
-//   - tenant/operator：将 ctx 中的 tenant_id/operator 注入到每个事件的 Metadata（若未设置）；
+//   - tenant/operator/correlation/causation：将 ctx 中的 tenant_id/operator/correlation_id/causation_id 注入到每个事件的 Metadata（若未设置）；
 //   - trace：确保批内每个事件的 metadata.trace_id 一致并补齐（ctx 优先；ctx 缺失时继承批内唯一 trace_id；批内不一致则返回 INVALID_INPUT）。
 //   - LoadEvents/Stream*：若 ctx 中存在 tenant_id，则仅返回 metadata.tenant_id 与之相等的事件；否则不做过滤。
 type ContextAwareEventStore[ID comparable] struct {
@@ -55,6 +55,12 @@ func (s *ContextAwareEventStore[ID]) AppendEvents(ctx context.Context, aggregate
 		if err := contextx.InjectOperator(ctx, evt.GetMetadata()); err != nil {
 			return err
 		}
+		if err := contextx.InjectCorrelationID(ctx, evt.GetMetadata()); err != nil {
+			return err
+		}
+		if err := contextx.InjectCausationID(ctx, evt.GetMetadata()); err != nil {
+			return err
+		}
 	}
 	return s.inner.AppendEvents(ctx, aggregateID, events, expectedVersion)
 }
@@ -71,6 +77,24 @@ func (s *ContextAwareEventStore[ID]) LoadEvents(ctx context.Context, aggregateID
 	return filterEventsByTenant(ctx, events), nil
 }
 
+// LoadEventsIter 以迭代器加载事件并按 tenant 过滤（ctx 携带 tenant_id 时生效）。
+func (s *ContextAwareEventStore[ID]) LoadEventsIter(ctx context.Context, aggregateID ID, afterVersion uint64) (store.IEventIterator[ID], error) {
+	if s == nil || s.inner == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	it, err := s.inner.LoadEventsIter(ctx, aggregateID, afterVersion)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := contextx.TenantID(ctx)
+	if tenantID == "" {
+		return it, nil
+	}
+	return store.NewFilterIterator(it, func(evt *eventing.Event[ID]) bool {
+		return eventBelongsToTenant(evt, tenantID)
+	}), nil
+}
+
 // LoadEventsByType 加载指定聚合类型的事件并按 tenant 过滤。
 func (s *ContextAwareEventStore[ID]) LoadEventsByType(ctx context.Context, aggregateType string, aggregateID ID, afterVersion uint64) ([]eventing.Event[ID], error) {
 	if s == nil || s.inner == nil {
@@ -148,7 +172,15 @@ func (s *ContextAwareEventStore[ID]) StreamAggregate(ctx context.Context, opts *
 }
 
 // HasAggregate 检查聚合是否存在，并保持与租户过滤一致。
+//
+// 说明：ctx 未携带 tenant_id 时直接走内层存储的快速路径；否则需加载事件按租户过滤。
 func (s *ContextAwareEventStore[ID]) HasAggregate(ctx context.Context, aggregateID ID) (bool, error) {
+	if s == nil || s.inner == nil {
+		return false, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	if contextx.TenantID(ctx) == "" {
+		return s.inner.HasAggregate(ctx, aggregateID)
+	}
 	events, err := s.LoadEvents(ctx, aggregateID, 0)
 	if err != nil {
 		return false, err
@@ -156,15 +188,56 @@ func (s *ContextAwareEventStore[ID]) HasAggregate(ctx context.Context, aggregate
 	return len(events) > 0, nil
 }
 
+// GetAggregateVersion 获取聚合当前版本，并保持与租户过滤一致。
 func (s *ContextAwareEventStore[ID]) GetAggregateVersion(ctx context.Context, aggregateID ID) (uint64, error) {
+	if s == nil || s.inner == nil {
+		return 0, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	if contextx.TenantID(ctx) == "" {
+		return s.inner.GetAggregateVersion(ctx, aggregateID)
+	}
+	events, err := s.LoadEvents(ctx, aggregateID, 0)
+	if err != nil {
+		return 0, err
+	}
+	return lastVersion(events), nil
+}
+
+// CountEvents 统计聚合事件数量，并保持与租户过滤一致。
+func (s *ContextAwareEventStore[ID]) CountEvents(ctx context.Context, aggregateID ID) (uint64, error) {
+	if s == nil || s.inner == nil {
+		return 0, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	if contextx.TenantID(ctx) == "" {
+		return s.inner.CountEvents(ctx, aggregateID)
+	}
 	events, err := s.LoadEvents(ctx, aggregateID, 0)
 	if err != nil {
 		return 0, err
 	}
+	return uint64(len(events)), nil
+}
+
+// HeadVersion 获取指定聚合类型下聚合的最新版本号，并保持与租户过滤一致。
+func (s *ContextAwareEventStore[ID]) HeadVersion(ctx context.Context, aggregateType string, aggregateID ID) (uint64, error) {
+	if s == nil || s.inner == nil {
+		return 0, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	if contextx.TenantID(ctx) == "" {
+		return s.inner.HeadVersion(ctx, aggregateType, aggregateID)
+	}
+	events, err := s.LoadEventsByType(ctx, aggregateType, aggregateID, 0)
+	if err != nil {
+		return 0, err
+	}
+	return lastVersion(events), nil
+}
+
+func lastVersion[ID comparable](events []eventing.Event[ID]) uint64 {
 	if len(events) == 0 {
-		return 0, nil
+		return 0
 	}
-	return events[len(events)-1].GetVersion(), nil
+	return events[len(events)-1].GetVersion()
 }
 
 var _ store.IEventStreamStore[int64] = (*ContextAwareEventStore[int64])(nil)
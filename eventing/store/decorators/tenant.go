@@ -65,6 +65,14 @@ func (s *TenantAwareEventStore[ID]) LoadEvents(ctx context.Context, aggregateID
 	return s.contextDecorator().LoadEvents(ctx, aggregateID, afterVersion)
 }
 
+// LoadEventsIter 以迭代器加载聚合事件。
+func (s *TenantAwareEventStore[ID]) LoadEventsIter(ctx context.Context, aggregateID ID, afterVersion uint64) (store.IEventIterator[ID], error) {
+	if s == nil || s.inner == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	return s.contextDecorator().LoadEventsIter(ctx, aggregateID, afterVersion)
+}
+
 // LoadEventsByType 加载指定聚合类型的事件。
 func (s *TenantAwareEventStore[ID]) LoadEventsByType(ctx context.Context, aggregateType string, aggregateID ID, afterVersion uint64) ([]eventing.Event[ID], error) {
 	if s == nil || s.inner == nil {
@@ -104,4 +112,20 @@ func (s *TenantAwareEventStore[ID]) GetAggregateVersion(ctx context.Context, agg
 	return s.contextDecorator().GetAggregateVersion(ctx, aggregateID)
 }
 
+// CountEvents 统计聚合事件数量。
+func (s *TenantAwareEventStore[ID]) CountEvents(ctx context.Context, aggregateID ID) (uint64, error) {
+	if s == nil || s.inner == nil {
+		return 0, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	return s.contextDecorator().CountEvents(ctx, aggregateID)
+}
+
+// HeadVersion 获取指定聚合类型下聚合的最新版本号。
+func (s *TenantAwareEventStore[ID]) HeadVersion(ctx context.Context, aggregateType string, aggregateID ID) (uint64, error) {
+	if s == nil || s.inner == nil {
+		return 0, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	return s.contextDecorator().HeadVersion(ctx, aggregateType, aggregateID)
+}
+
 var _ store.IEventStreamStore[int64] = (*TenantAwareEventStore[int64])(nil)
@@ -14,11 +14,15 @@ func filterEventsByTenant[ID comparable](ctx context.Context, events []eventing.
 	}
 	filtered := make([]eventing.Event[ID], 0, len(events))
 	for i := range events {
-		evt := events[i]
-		v, ok := evt.GetMetadata().GetString(contextx.MetadataTenantKey)
-		if ok && v == tenantID {
-			filtered = append(filtered, evt)
+		if eventBelongsToTenant(&events[i], tenantID) {
+			filtered = append(filtered, events[i])
 		}
 	}
 	return filtered
 }
+
+// eventBelongsToTenant 判断事件 metadata.tenant_id 是否与 tenantID 相等。
+func eventBelongsToTenant[ID comparable](evt *eventing.Event[ID], tenantID string) bool {
+	v, ok := evt.GetMetadata().GetString(contextx.MetadataTenantKey)
+	return ok && v == tenantID
+}
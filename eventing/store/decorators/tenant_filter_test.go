@@ -92,3 +92,66 @@ func TestTenantAwareEventStore_StreamAggregate_SkipsEmptyPages(t *testing.T) {
 	// NextVersion should advance to the last version returned by the underlying store.
 	require.Equal(t, uint64(2), res.NextVersion)
 }
+
+func TestTenantAwareEventStore_CountAndHeadVersion_RespectTenant(t *testing.T) {
+	base := store.NewMemoryEventStore()
+	es := NewTenantAwareEventStore[int64](base)
+	cas := NewContextAwareEventStore[int64](base)
+
+	ctxA, err := contextx.WithTenantID(context.Background(), "t1")
+	require.NoError(t, err)
+	ctxB, err := contextx.WithTenantID(context.Background(), "t2")
+	require.NoError(t, err)
+
+	e1 := eventing.NewEvent[int64](1, "Agg", "Evt", 1, nil)
+	require.NoError(t, contextx.InjectTenantID(ctxA, e1.GetMetadata()))
+	e2 := eventing.NewEvent[int64](1, "Agg", "Evt", 2, nil)
+	require.NoError(t, contextx.InjectTenantID(ctxB, e2.GetMetadata()))
+	require.NoError(t, base.AppendEvents(ctxA, 1, []eventing.IStorableEvent[int64]{e1, e2}, 0))
+
+	count, err := es.CountEvents(ctxA, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), count)
+
+	head, err := es.HeadVersion(ctxA, "Agg", 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), head)
+
+	head, err = es.HeadVersion(ctxB, "Agg", 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), head)
+
+	// 未携带租户时走内层快速路径，不做过滤。
+	count, err = cas.CountEvents(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), count)
+}
+
+func TestTenantAwareEventStore_LoadEventsIter_FiltersByTenant(t *testing.T) {
+	base := store.NewMemoryEventStore()
+	es := NewTenantAwareEventStore[int64](base)
+
+	ctxA, err := contextx.WithTenantID(context.Background(), "t1")
+	require.NoError(t, err)
+	ctxB, err := contextx.WithTenantID(context.Background(), "t2")
+	require.NoError(t, err)
+
+	for v, ctx := range []context.Context{ctxB, ctxA, ctxB} {
+		evt := eventing.NewEvent[int64](1, "Agg", "Evt", uint64(v+1), nil)
+		require.NoError(t, contextx.InjectTenantID(ctx, evt.GetMetadata()))
+		require.NoError(t, base.AppendEvents(ctx, 1, []eventing.IStorableEvent[int64]{evt}, uint64(v)))
+	}
+
+	it, err := es.LoadEventsIter(ctxA, 1, 0)
+	require.NoError(t, err)
+	got, err := store.CollectEvents(it)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, uint64(2), got[0].GetVersion())
+
+	it, err = es.LoadEventsIter(context.Background(), 1, 0)
+	require.NoError(t, err)
+	all, err := store.CollectEvents(it)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+}
@@ -43,6 +43,14 @@ func (s *TracingEventStore[ID]) LoadEvents(ctx context.Context, aggregateID ID,
 	return s.inner.LoadEvents(ctx, aggregateID, afterVersion)
 }
 
+// LoadEventsIter 以迭代器加载聚合事件。
+func (s *TracingEventStore[ID]) LoadEventsIter(ctx context.Context, aggregateID ID, afterVersion uint64) (store.IEventIterator[ID], error) {
+	if s == nil || s.inner == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	return s.inner.LoadEventsIter(ctx, aggregateID, afterVersion)
+}
+
 // LoadEventsByType 加载指定聚合类型的事件。
 func (s *TracingEventStore[ID]) LoadEventsByType(ctx context.Context, aggregateType string, aggregateID ID, afterVersion uint64) ([]eventing.Event[ID], error) {
 	if s == nil || s.inner == nil {
@@ -82,4 +90,20 @@ func (s *TracingEventStore[ID]) GetAggregateVersion(ctx context.Context, aggrega
 	return s.inner.GetAggregateVersion(ctx, aggregateID)
 }
 
+// CountEvents 统计聚合事件数量。
+func (s *TracingEventStore[ID]) CountEvents(ctx context.Context, aggregateID ID) (uint64, error) {
+	if s == nil || s.inner == nil {
+		return 0, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	return s.inner.CountEvents(ctx, aggregateID)
+}
+
+// HeadVersion 获取指定聚合类型下聚合的最新版本号。
+func (s *TracingEventStore[ID]) HeadVersion(ctx context.Context, aggregateType string, aggregateID ID) (uint64, error) {
+	if s == nil || s.inner == nil {
+		return 0, errors.NewCode(errors.InvalidInput, "inner event store cannot be nil")
+	}
+	return s.inner.HeadVersion(ctx, aggregateType, aggregateID)
+}
+
 var _ store.IEventStreamStore[int64] = (*TracingEventStore[int64])(nil)
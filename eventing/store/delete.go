@@ -0,0 +1,167 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	gerrors "gochen/errors"
+	"gochen/eventing"
+)
+
+// TombstoneEventType 是软删除标记事件的类型：事件流末尾为该事件的聚合对加载/流式读取不可见。
+const TombstoneEventType = "$tombstone"
+
+// DeleteMode 聚合删除方式。
+type DeleteMode int
+
+const (
+	// DeleteModeTombstone 软删除：在事件流末尾追加墓碑事件，聚合对加载与流式读取不可见，事件仍保留在存储中。
+	DeleteModeTombstone DeleteMode = iota
+	// DeleteModeHard 硬删除：物理删除聚合的全部事件（含墓碑事件），不可恢复。
+	DeleteModeHard
+)
+
+// String 返回删除方式名称。
+func (m DeleteMode) String() string {
+	switch m {
+	case DeleteModeTombstone:
+		return "tombstone"
+	case DeleteModeHard:
+		return "hard"
+	default:
+		return "unknown"
+	}
+}
+
+// DeleteOptions 聚合删除选项。
+type DeleteOptions struct {
+	// AggregateType 聚合类型（必填），与 HeadVersion/LoadEventsByType 的定位口径一致。
+	AggregateType string
+	// ExpectedVersion 调用方确认过的聚合当前版本（必填，防止误删并发写入的新事件）。
+	// 对已软删除的聚合做硬删除时，应传入软删除时的版本。
+	ExpectedVersion uint64
+	// Mode 删除方式，默认软删除。
+	Mode DeleteMode
+	// Reason 删除原因（写入墓碑事件载荷，便于合规审计）。
+	Reason string
+}
+
+// Tombstone 墓碑事件载荷。
+type Tombstone struct {
+	Reason string `json:"reason,omitempty"`
+	// Version 是软删除时聚合的版本。
+	Version uint64 `json:"version"`
+}
+
+// IAggregateDeleter 事件存储的可选能力：删除单个聚合的事件流。
+//
+// 实现约定：
+//   - ExpectedVersion 为 0 时返回 errors.InvalidInput；聚合不存在时返回 errors.NotFound；
+//     版本不一致时返回 errors.Concurrency；
+//   - 软删除已软删除的聚合（版本一致）视为成功；软删除后对该聚合追加事件以并发冲突失败。
+type IAggregateDeleter[ID comparable] interface {
+	DeleteAggregate(ctx context.Context, aggregateID ID, opts DeleteOptions) error
+}
+
+// ITombstonePurger 事件存储的可选能力：硬删除软删除时间早于 before 的事件流（供数据保留任务使用）。
+//
+// 以墓碑事件的时间戳判断软删除时间；返回实际删除的事件数（含墓碑事件）。
+type ITombstonePurger interface {
+	PurgeTombstoned(ctx context.Context, before time.Time) (int64, error)
+}
+
+// IAggregateDataCleaner 清理聚合的派生数据（快照、Outbox 记录等），用于删除聚合时级联清理。
+//
+// 实现应幂等：数据不存在时返回 nil。
+type IAggregateDataCleaner[ID comparable] interface {
+	DeleteAggregateData(ctx context.Context, aggregateType string, aggregateID ID) error
+}
+
+// AggregateDataCleanerFunc 把函数适配为 IAggregateDataCleaner（如 snapshot 存储的 DeleteSnapshot）。
+type AggregateDataCleanerFunc[ID comparable] func(ctx context.Context, aggregateType string, aggregateID ID) error
+
+// DeleteAggregateData 调用函数本身。
+func (f AggregateDataCleanerFunc[ID]) DeleteAggregateData(ctx context.Context, aggregateType string, aggregateID ID) error {
+	return f(ctx, aggregateType, aggregateID)
+}
+
+// DeleteAggregate 按数据保留策略删除聚合事件流，并级联清理派生数据。
+//
+// 说明：
+//   - 事件存储需实现 IAggregateDeleter，否则返回 errors.Unsupported；
+//   - 事件流删除成功后依次调用 cascade；任一清理失败时返回 errors.Dependency（事件流已删除，
+//     清理器幂等，可直接重试清理器）；
+//   - 软删除同样执行级联清理：快照等派生数据会绕过墓碑让聚合"复活"，应一并删除。
+func DeleteAggregate[ID comparable](ctx context.Context, s IEventStore[ID], aggregateID ID, opts DeleteOptions, cascade ...IAggregateDataCleaner[ID]) error {
+	deleter, ok := s.(IAggregateDeleter[ID])
+	if !ok {
+		return gerrors.NewCode(gerrors.Unsupported, "event store does not support deleting aggregates")
+	}
+	if err := validateDeleteOptions(opts); err != nil {
+		return err
+	}
+	if err := deleter.DeleteAggregate(ctx, aggregateID, opts); err != nil {
+		return err
+	}
+	for i, cleaner := range cascade {
+		if cleaner == nil {
+			continue
+		}
+		if err := cleaner.DeleteAggregateData(ctx, opts.AggregateType, aggregateID); err != nil {
+			return gerrors.Wrap(err, gerrors.Dependency, "cascade cleanup failed after deleting aggregate").
+				WithContext("aggregate_type", opts.AggregateType).
+				WithContext("aggregate_id", aggregateID).
+				WithContext("cleaner_index", i)
+		}
+	}
+	return nil
+}
+
+// validateDeleteOptions 校验删除选项的必填项。
+func validateDeleteOptions(opts DeleteOptions) error {
+	if opts.AggregateType == "" {
+		return gerrors.NewCode(gerrors.InvalidInput, "aggregate type is required to delete an aggregate")
+	}
+	if opts.ExpectedVersion == 0 {
+		return gerrors.NewCode(gerrors.InvalidInput, "expected version is required to delete an aggregate")
+	}
+	if opts.Mode != DeleteModeTombstone && opts.Mode != DeleteModeHard {
+		return gerrors.NewCode(gerrors.InvalidInput, "unknown delete mode").
+			WithContext("mode", int(opts.Mode))
+	}
+	return nil
+}
+
+// NewTombstoneEvent 构造软删除标记事件（版本为 ExpectedVersion+1）。
+func NewTombstoneEvent[ID comparable](aggregateID ID, opts DeleteOptions) *eventing.Event[ID] {
+	return eventing.NewEvent(aggregateID, opts.AggregateType, TombstoneEventType, opts.ExpectedVersion+1,
+		Tombstone{Reason: opts.Reason, Version: opts.ExpectedVersion})
+}
+
+// CheckDeleteVersion 按事件流头部（版本与类型）校验删除前置条件。
+//
+// 返回 tombstoned=true 表示聚合已软删除（此时 ExpectedVersion 与软删除时的版本比较）。
+// 供 IAggregateDeleter 实现复用。
+func CheckDeleteVersion[ID comparable](aggregateID ID, opts DeleteOptions, headVersion uint64, headType string) (tombstoned bool, err error) {
+	if err := validateDeleteOptions(opts); err != nil {
+		return false, err
+	}
+	if headVersion == 0 {
+		return false, gerrors.NewCode(gerrors.NotFound, "aggregate not found").
+			WithContext("aggregate_type", opts.AggregateType).
+			WithContext("aggregate_id", aggregateID)
+	}
+	live := headVersion
+	if headType == TombstoneEventType {
+		tombstoned = true
+		live = headVersion - 1
+	}
+	if live != opts.ExpectedVersion {
+		return tombstoned, gerrors.NewCode(gerrors.Concurrency, "aggregate version changed before delete").
+			WithContext("aggregate_type", opts.AggregateType).
+			WithContext("aggregate_id", aggregateID).
+			WithContext("expected_version", opts.ExpectedVersion).
+			WithContext("actual_version", live)
+	}
+	return tombstoned, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"gochen/db"
 	gerrors "gochen/errors"
 	"gochen/eventing"
 )
@@ -82,6 +83,16 @@ type IEventStore[ID comparable] interface {
 	//   - error: 加载失败时返回错误
 	LoadEvents(ctx context.Context, aggregateID ID, afterVersion uint64) ([]eventing.Event[ID], error)
 
+	// LoadEventsIter 以拉取式迭代器加载聚合的事件历史
+	//
+	// 语义与 LoadEvents 一致（按版本升序、不包含 afterVersion），但实现应按页从存储读取，
+	// 避免超长事件流一次性分配巨大切片。调用方必须在使用完毕后调用 Close，并通过 Err 检查读取错误。
+	//
+	// 返回：
+	//   - IEventIterator: 事件迭代器
+	//   - error: 参数非法等无法开始迭代时返回错误
+	LoadEventsIter(ctx context.Context, aggregateID ID, afterVersion uint64) (IEventIterator[ID], error)
+
 	// LoadEventsByType 按聚合类型加载事件。
 	LoadEventsByType(ctx context.Context, aggregateType string, aggregateID ID, afterVersion uint64) ([]eventing.Event[ID], error)
 
@@ -98,6 +109,26 @@ type IEventStore[ID comparable] interface {
 	//   - uint64: 版本号，0表示聚合不存在
 	//   - error: 查询失败时返回错误
 	GetAggregateVersion(ctx context.Context, aggregateID ID) (uint64, error)
+
+	// CountEvents 统计指定聚合已持久化的事件数量
+	//
+	// 实现应只读取索引或计数，不加载事件体；快照频率、版本检查等只关心数量的路径应使用该方法，
+	// 而不是 len(LoadEvents(...))。
+	//
+	// 返回：
+	//   - uint64: 事件数量，0表示聚合不存在
+	//   - error: 查询失败时返回错误
+	CountEvents(ctx context.Context, aggregateID ID) (uint64, error)
+
+	// HeadVersion 获取指定聚合类型下聚合的最新版本号
+	//
+	// 与 GetAggregateVersion 的区别在于按 (aggregateType, aggregateID) 定位事件流，
+	// 与 LoadEventsByType 的口径一致；实现同样不应加载事件体。
+	//
+	// 返回：
+	//   - uint64: 版本号，0表示该类型下聚合不存在
+	//   - error: 查询失败时返回错误
+	HeadVersion(ctx context.Context, aggregateType string, aggregateID ID) (uint64, error)
 }
 
 // IEventStreamStore 全局事件流存储接口。
@@ -149,6 +180,13 @@ type IEventStreamStore[ID comparable] interface {
 	StreamAggregate(ctx context.Context, opts *AggregateStreamOptions[ID]) (*AggregateStreamResult[ID], error)
 }
 
+// ITxEventAppender 事件存储的可选能力：在调用方提供的数据库连接/事务内追加事件。
+//
+// 用于把事件流写入与其他写操作（如 Outbox 记录）放入同一事务；sqlstore.SQLEventStore 实现了该接口。
+type ITxEventAppender[ID comparable] interface {
+	AppendEventsWithDB(ctx context.Context, db db.IDatabase, aggregateID ID, events []eventing.IStorableEvent[ID], expectedVersion uint64) error
+}
+
 // StreamOptions 全局事件流查询选项。
 type StreamOptions struct {
 	After          string    // 游标，从该位置之后开始查询
@@ -157,6 +195,8 @@ type StreamOptions struct {
 	FromTime       time.Time // 起始时间（包含）
 	ToTime         time.Time // 结束时间（包含）
 	AggregateTypes []string  // 聚合类型过滤
+	CorrelationID  string    // 按 metadata.correlation_id 过滤，用于排障时查看同一业务流程产生的事件
+	CausationID    string    // 按 metadata.causation_id 过滤，用于排障时查看某条命令/消息直接产生的事件
 }
 
 // DefaultStreamLimit 默认事件流 limit。
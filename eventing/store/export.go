@@ -0,0 +1,249 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/messaging"
+)
+
+// ExportFormatVersion 是事件导出 NDJSON 信封的格式版本。
+const ExportFormatVersion = 1
+
+// ExportEnvelope 是事件导出的 NDJSON 信封：每行一个 JSON 对象，对应一条事件。
+//
+// 字段：
+//   - format_version：信封格式版本（当前为 1），导入时拒绝更高版本；
+//   - id/kind/type/timestamp：事件消息字段；
+//   - aggregate_id/aggregate_type/version：事件在聚合事件流中的位置；
+//   - schema_version：事件载荷 schema 版本，导入后仍由 upcast 按该版本升级；
+//   - payload：原始载荷 JSON；metadata：事件元数据（tenant_id/trace_id 等）。
+type ExportEnvelope[ID comparable] struct {
+	FormatVersion int                   `json:"format_version"`
+	ID            string                `json:"id"`
+	Kind          messaging.MessageKind `json:"kind,omitempty"`
+	Type          string                `json:"type"`
+	Timestamp     time.Time             `json:"timestamp"`
+	AggregateID   ID                    `json:"aggregate_id"`
+	AggregateType string                `json:"aggregate_type"`
+	Version       uint64                `json:"version"`
+	SchemaVersion int                   `json:"schema_version"`
+	Payload       json.RawMessage       `json:"payload,omitempty"`
+	Metadata      *messaging.Metadata   `json:"metadata,omitempty"`
+}
+
+// ExportOptions 事件导出选项。
+type ExportOptions struct {
+	Types          []string  // 事件类型过滤
+	AggregateTypes []string  // 聚合类型过滤
+	FromTime       time.Time // 起始时间（包含）
+	ToTime         time.Time // 结束时间（包含）
+	BatchSize      int       // 每页读取数量（默认 DefaultStreamLimit）
+}
+
+// ImportOptions 事件导入选项。
+type ImportOptions struct {
+	// SkipExisting 为 true 时跳过目标存储中已存在的版本（用于重复导入/断点续传）；
+	// 为 false 时遇到版本冲突返回 Concurrency 错误。
+	SkipExisting bool
+	// BatchSize 为同一聚合连续事件合并追加的最大数量（默认 DefaultStreamLimit）。
+	BatchSize int
+}
+
+// ImportStats 事件导入统计。
+type ImportStats struct {
+	Imported int // 写入的事件数
+	Skipped  int // 因已存在而跳过的事件数
+}
+
+// Export 按全局事件流顺序把事件写为 NDJSON，返回导出的事件数。
+//
+// 导出基于 StreamEvents 游标分页，与并发写入之间不保证快照一致性；
+// 用于环境间复制、测试数据种子与存储后端迁移。
+func Export[ID comparable](ctx context.Context, src IEventStreamStore[ID], w io.Writer, opts *ExportOptions) (int, error) {
+	if src == nil {
+		return 0, errors.NewCode(errors.InvalidInput, "export source event store cannot be nil")
+	}
+	if w == nil {
+		return 0, errors.NewCode(errors.InvalidInput, "export writer cannot be nil")
+	}
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+	stream := &StreamOptions{
+		Limit:          opts.BatchSize,
+		Types:          opts.Types,
+		AggregateTypes: opts.AggregateTypes,
+		FromTime:       opts.FromTime,
+		ToTime:         opts.ToTime,
+	}
+	if stream.Limit <= 0 {
+		stream.Limit = DefaultStreamLimit
+	}
+
+	enc := json.NewEncoder(w)
+	exported := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return exported, err
+		}
+		res, err := src.StreamEvents(ctx, stream)
+		if err != nil {
+			return exported, err
+		}
+		if res == nil {
+			return exported, nil
+		}
+		for i := range res.Events {
+			envelope, err := newExportEnvelope(&res.Events[i])
+			if err != nil {
+				return exported, err
+			}
+			if err := enc.Encode(envelope); err != nil {
+				return exported, errors.Wrap(err, errors.Internal, "write export envelope failed").WithContext("event_id", envelope.ID)
+			}
+			exported++
+		}
+		if !res.HasMore || res.NextCursor == "" || res.NextCursor == stream.After {
+			return exported, nil
+		}
+		stream.After = res.NextCursor
+	}
+}
+
+// Import 读取 Export 生成的 NDJSON 并追加到目标存储。
+//
+// 同一聚合的连续事件合并为一次 AppendEvents，expectedVersion 取首条事件版本减一，
+// 因此每个聚合的事件必须按版本连续出现（Export 的全局顺序满足该要求）。
+func Import[ID comparable](ctx context.Context, dst IEventStore[ID], r io.Reader, opts *ImportOptions) (ImportStats, error) {
+	var stats ImportStats
+	if dst == nil {
+		return stats, errors.NewCode(errors.InvalidInput, "import target event store cannot be nil")
+	}
+	if r == nil {
+		return stats, errors.NewCode(errors.InvalidInput, "import reader cannot be nil")
+	}
+	if opts == nil {
+		opts = &ImportOptions{}
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultStreamLimit
+	}
+
+	var batch []eventing.IStorableEvent[ID]
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		first, last := batch[0], batch[len(batch)-1]
+		err := dst.AppendEvents(ctx, first.GetAggregateID(), batch, first.GetVersion()-1)
+		switch {
+		case err == nil:
+			stats.Imported += len(batch)
+		case opts.SkipExisting && errors.Is(err, errors.Concurrency):
+			head, headErr := dst.HeadVersion(ctx, first.GetAggregateType(), first.GetAggregateID())
+			if headErr != nil {
+				return headErr
+			}
+			if head < last.GetVersion() {
+				// 仅部分版本已存在：导入流与目标存储不一致，不做部分跳过。
+				return err
+			}
+			stats.Skipped += len(batch)
+		default:
+			return err
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	dec := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+		var envelope ExportEnvelope[ID]
+		if err := dec.Decode(&envelope); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return stats, errors.Wrap(err, errors.InvalidInput, "decode export envelope failed").WithContext("record", line)
+		}
+		if envelope.FormatVersion > ExportFormatVersion || envelope.FormatVersion <= 0 {
+			return stats, errors.NewCode(errors.Unsupported, "unsupported export format version").
+				WithContext("record", line).
+				WithContext("format_version", envelope.FormatVersion)
+		}
+		if envelope.Version == 0 {
+			return stats, errors.NewCode(errors.InvalidInput, "export envelope version must be greater than 0").WithContext("record", line)
+		}
+		evt := envelope.event()
+		if len(batch) > 0 {
+			prev := batch[len(batch)-1]
+			if prev.GetAggregateID() != evt.AggregateID ||
+				prev.GetAggregateType() != evt.AggregateType ||
+				prev.GetVersion()+1 != evt.Version ||
+				len(batch) >= batchSize {
+				if err := flush(); err != nil {
+					return stats, err
+				}
+			}
+		}
+		batch = append(batch, evt)
+	}
+	if err := flush(); err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
+
+func newExportEnvelope[ID comparable](evt *eventing.Event[ID]) (*ExportEnvelope[ID], error) {
+	payload, err := json.Marshal(evt.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "serialize payload failed").WithContext("event_id", evt.ID)
+	}
+	return &ExportEnvelope[ID]{
+		FormatVersion: ExportFormatVersion,
+		ID:            evt.ID,
+		Kind:          evt.Kind,
+		Type:          evt.Type,
+		Timestamp:     evt.Timestamp,
+		AggregateID:   evt.AggregateID,
+		AggregateType: evt.AggregateType,
+		Version:       evt.Version,
+		SchemaVersion: evt.SchemaVersion,
+		Payload:       payload,
+		Metadata:      evt.Metadata,
+	}, nil
+}
+
+// event 把信封还原为可追加的事件；载荷保留为 JSON bytes，由消费侧 upcast/hydration 解码。
+func (e *ExportEnvelope[ID]) event() *eventing.Event[ID] {
+	var payload any
+	if len(e.Payload) > 0 && string(e.Payload) != "null" {
+		payload = e.Payload
+	}
+	metadata := e.Metadata
+	if metadata == nil {
+		metadata = &messaging.Metadata{}
+	}
+	return &eventing.Event[ID]{
+		Message: messaging.Message{
+			ID:        e.ID,
+			Kind:      e.Kind,
+			Type:      e.Type,
+			Timestamp: e.Timestamp,
+			Payload:   messaging.NewPayload(payload),
+			Metadata:  metadata,
+		},
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		Version:       e.Version,
+		SchemaVersion: e.SchemaVersion,
+	}
+}
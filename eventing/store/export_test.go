@@ -0,0 +1,71 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/eventing"
+)
+
+// TestExportImport_RoundTrip 验证导出的 NDJSON 可导入到另一存储，且重复导入时可跳过已存在事件。
+func TestExportImport_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	src := NewMemoryEventStore()
+	for id := int64(1); id <= 2; id++ {
+		events := make([]eventing.IStorableEvent[int64], 0, 3)
+		for v := uint64(1); v <= 3; v++ {
+			evt := eventing.NewEvent[int64](id, "Order", "OrderChanged", v, map[string]any{"step": v})
+			evt.SchemaVersion = 2
+			evt.Metadata.Set("tenant_id", "t1")
+			events = append(events, evt)
+		}
+		require.NoError(t, src.AppendEvents(ctx, id, events, 0))
+	}
+
+	var buf bytes.Buffer
+	exported, err := Export[int64](ctx, src, &buf, &ExportOptions{BatchSize: 2})
+	require.NoError(t, err)
+	require.Equal(t, 6, exported)
+	require.Equal(t, 6, strings.Count(buf.String(), "\n"))
+
+	dst := NewMemoryEventStore()
+	data := buf.Bytes()
+	stats, err := Import[int64](ctx, dst, bytes.NewReader(data), &ImportOptions{BatchSize: 2})
+	require.NoError(t, err)
+	require.Equal(t, ImportStats{Imported: 6}, stats)
+
+	loaded, err := dst.LoadEvents(ctx, 2, 0)
+	require.NoError(t, err)
+	require.Len(t, loaded, 3)
+	original, err := src.LoadEvents(ctx, 2, 0)
+	require.NoError(t, err)
+	for i := range loaded {
+		require.Equal(t, original[i].ID, loaded[i].ID)
+		require.Equal(t, original[i].Version, loaded[i].Version)
+		require.Equal(t, 2, loaded[i].SchemaVersion)
+		tenantID, _ := loaded[i].Metadata.Get("tenant_id")
+		require.Equal(t, "t1", tenantID)
+		var payload map[string]uint64
+		require.NoError(t, loaded[i].Payload.DecodeTo(&payload))
+		require.Equal(t, loaded[i].Version, payload["step"])
+	}
+
+	_, err = Import[int64](ctx, dst, bytes.NewReader(data), nil)
+	require.True(t, errors.Is(err, errors.Concurrency))
+
+	stats, err = Import[int64](ctx, dst, bytes.NewReader(data), &ImportOptions{SkipExisting: true})
+	require.NoError(t, err)
+	require.Equal(t, ImportStats{Skipped: 6}, stats)
+}
+
+// TestImport_RejectsNewerFormat 验证导入拒绝更高版本的信封格式。
+func TestImport_RejectsNewerFormat(t *testing.T) {
+	input := `{"format_version":2,"id":"e1","type":"T","aggregate_id":1,"aggregate_type":"A","version":1}` + "\n"
+	_, err := Import[int64](context.Background(), NewMemoryEventStore(), strings.NewReader(input), nil)
+	require.True(t, errors.Is(err, errors.Unsupported))
+}
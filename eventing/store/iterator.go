@@ -0,0 +1,201 @@
+package store
+
+import (
+	"context"
+
+	"gochen/eventing"
+)
+
+// DefaultIteratorPageSize 事件迭代器默认每页读取的事件数量。
+const DefaultIteratorPageSize = 500
+
+// IEventIterator 拉取式事件迭代器。
+//
+// 用于逐条消费聚合事件流而不把整条事件流加载到内存：实现按页从存储读取，
+// 调用方每次 Next 只持有当前页。典型用法：
+//
+//	it, err := store.LoadEventsIter(ctx, id, 0)
+//	if err != nil {
+//		return err
+//	}
+//	defer it.Close()
+//	for it.Next() {
+//		evt := it.Event()
+//		// ...
+//	}
+//	return it.Err()
+//
+// 迭代器不保证并发安全；创建时传入的 ctx 作用于后续所有分页读取。
+type IEventIterator[ID comparable] interface {
+	// Next 前进到下一条事件；没有更多事件或读取失败时返回 false（通过 Err 区分）。
+	Next() bool
+	// Event 返回当前事件；仅在 Next 返回 true 后有效，下一次 Next 后可能被覆盖。
+	Event() *eventing.Event[ID]
+	// Err 返回迭代过程中的第一个错误。
+	Err() error
+	// Close 释放迭代器持有的资源；可重复调用。
+	Close() error
+}
+
+// AggregatePageFunc 按版本游标读取单个聚合的一页事件，签名与 IEventStreamStore.StreamAggregate 一致。
+type AggregatePageFunc[ID comparable] func(ctx context.Context, opts *AggregateStreamOptions[ID]) (*AggregateStreamResult[ID], error)
+
+// sliceIterator 基于已加载事件切片的迭代器。
+type sliceIterator[ID comparable] struct {
+	events []eventing.Event[ID]
+	pos    int
+}
+
+// NewSliceIterator 以迭代器视图返回已加载的事件切片（用于内存存储或缓存命中）。
+func NewSliceIterator[ID comparable](events []eventing.Event[ID]) IEventIterator[ID] {
+	return &sliceIterator[ID]{events: events, pos: -1}
+}
+
+func (it *sliceIterator[ID]) Next() bool {
+	if it.pos+1 >= len(it.events) {
+		it.pos = len(it.events)
+		return false
+	}
+	it.pos++
+	return true
+}
+
+func (it *sliceIterator[ID]) Event() *eventing.Event[ID] {
+	if it.pos < 0 || it.pos >= len(it.events) {
+		return nil
+	}
+	return &it.events[it.pos]
+}
+
+func (it *sliceIterator[ID]) Err() error { return nil }
+
+func (it *sliceIterator[ID]) Close() error {
+	it.events = nil
+	it.pos = 0
+	return nil
+}
+
+// pagedIterator 按版本游标分页读取的迭代器。
+type pagedIterator[ID comparable] struct {
+	ctx   context.Context
+	fetch AggregatePageFunc[ID]
+	opts  AggregateStreamOptions[ID]
+
+	page    []eventing.Event[ID]
+	pos     int
+	hasMore bool
+	err     error
+	closed  bool
+}
+
+// NewAggregateStreamIterator 基于 StreamAggregate 风格的分页函数创建聚合事件迭代器。
+//
+// opts.AfterVersion 为起始版本（不包含），opts.Limit 为每页大小（<=0 时使用 DefaultIteratorPageSize）。
+// 每页读取完毕后以该页最后一条事件的版本（或 NextVersion）作为下一页游标；
+// 过滤后的空页（HasMore=true）会继续向后翻页。
+func NewAggregateStreamIterator[ID comparable](ctx context.Context, fetch AggregatePageFunc[ID], opts AggregateStreamOptions[ID]) IEventIterator[ID] {
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultIteratorPageSize
+	}
+	return &pagedIterator[ID]{ctx: ctx, fetch: fetch, opts: opts, pos: -1, hasMore: true}
+}
+
+func (it *pagedIterator[ID]) Next() bool {
+	if it.closed || it.err != nil {
+		return false
+	}
+	for it.pos+1 >= len(it.page) {
+		if !it.hasMore || !it.nextPage() {
+			it.page, it.pos = nil, -1
+			return false
+		}
+	}
+	it.pos++
+	return true
+}
+
+// nextPage 读取下一页；返回 false 表示出错或游标无法推进。
+func (it *pagedIterator[ID]) nextPage() bool {
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+	opts := it.opts
+	res, err := it.fetch(it.ctx, &opts)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.page, it.pos = nil, -1
+	if res == nil {
+		it.hasMore = false
+		return true
+	}
+	it.page = res.Events
+	it.hasMore = res.HasMore
+
+	next := res.NextVersion
+	if n := len(res.Events); n > 0 && res.Events[n-1].GetVersion() > next {
+		next = res.Events[n-1].GetVersion()
+	}
+	if next <= it.opts.AfterVersion {
+		// 游标无法推进时停止翻页，避免实现缺陷导致死循环。
+		it.hasMore = false
+	}
+	it.opts.AfterVersion = next
+	return true
+}
+
+func (it *pagedIterator[ID]) Event() *eventing.Event[ID] {
+	if it.pos < 0 || it.pos >= len(it.page) {
+		return nil
+	}
+	return &it.page[it.pos]
+}
+
+func (it *pagedIterator[ID]) Err() error { return it.err }
+
+func (it *pagedIterator[ID]) Close() error {
+	it.closed = true
+	it.page, it.pos = nil, -1
+	return nil
+}
+
+// filterIterator 跳过不满足条件的事件。
+type filterIterator[ID comparable] struct {
+	inner IEventIterator[ID]
+	keep  func(evt *eventing.Event[ID]) bool
+}
+
+// NewFilterIterator 返回仅产出满足 keep 的事件的迭代器（用于租户过滤等装饰场景）。
+func NewFilterIterator[ID comparable](inner IEventIterator[ID], keep func(evt *eventing.Event[ID]) bool) IEventIterator[ID] {
+	return &filterIterator[ID]{inner: inner, keep: keep}
+}
+
+func (it *filterIterator[ID]) Next() bool {
+	for it.inner.Next() {
+		if it.keep(it.inner.Event()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (it *filterIterator[ID]) Event() *eventing.Event[ID] { return it.inner.Event() }
+
+func (it *filterIterator[ID]) Err() error { return it.inner.Err() }
+
+func (it *filterIterator[ID]) Close() error { return it.inner.Close() }
+
+// CollectEvents 读取迭代器中剩余的全部事件并关闭迭代器。
+func CollectEvents[ID comparable](it IEventIterator[ID]) ([]eventing.Event[ID], error) {
+	defer func() { _ = it.Close() }()
+	events := make([]eventing.Event[ID], 0)
+	for it.Next() {
+		events = append(events, *it.Event())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	stdErrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/eventing"
+)
+
+// TestAggregateStreamIterator_Pages 验证迭代器按版本游标逐页读取，并跳过过滤后的空页。
+func TestAggregateStreamIterator_Pages(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryEventStore()
+	events := make([]eventing.IStorableEvent[int64], 0, 5)
+	for v := uint64(1); v <= 5; v++ {
+		events = append(events, eventing.NewEvent[int64](1, "Agg", "Evt", v, nil))
+	}
+	require.NoError(t, store.AppendEvents(ctx, 1, events, 0))
+
+	var calls []uint64
+	fetch := func(ctx context.Context, opts *AggregateStreamOptions[int64]) (*AggregateStreamResult[int64], error) {
+		calls = append(calls, opts.AfterVersion)
+		res, err := store.StreamAggregate(ctx, opts)
+		if err != nil || opts.AfterVersion != 2 {
+			return res, err
+		}
+		// 模拟装饰器过滤掉整页事件。
+		return &AggregateStreamResult[int64]{HasMore: res.HasMore, NextVersion: res.NextVersion}, nil
+	}
+
+	it := NewAggregateStreamIterator(ctx, fetch, AggregateStreamOptions[int64]{AggregateType: "Agg", AggregateID: 1, Limit: 2})
+	got, err := CollectEvents(it)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{0, 2, 4}, calls)
+	require.Len(t, got, 3)
+	require.Equal(t, []uint64{1, 2, 5}, []uint64{got[0].Version, got[1].Version, got[2].Version})
+}
+
+// TestAggregateStreamIterator_Error 验证分页读取错误通过 Err 返回，且迭代在取消的 ctx 上立即结束。
+func TestAggregateStreamIterator_Error(t *testing.T) {
+	boom := stdErrors.New("boom")
+	it := NewAggregateStreamIterator(context.Background(), func(context.Context, *AggregateStreamOptions[int64]) (*AggregateStreamResult[int64], error) {
+		return nil, boom
+	}, AggregateStreamOptions[int64]{AggregateID: 1})
+	require.False(t, it.Next())
+	require.ErrorIs(t, it.Err(), boom)
+	require.NoError(t, it.Close())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	it = NewAggregateStreamIterator(ctx, func(context.Context, *AggregateStreamOptions[int64]) (*AggregateStreamResult[int64], error) {
+		t.Fatal("fetch should not be called after cancellation")
+		return nil, nil
+	}, AggregateStreamOptions[int64]{AggregateID: 1})
+	require.False(t, it.Next())
+	require.ErrorIs(t, it.Err(), context.Canceled)
+}
+
+// TestMemoryEventStore_LoadEventsIter 验证内存存储迭代器与 LoadEvents 结果一致。
+func TestMemoryEventStore_LoadEventsIter(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryEventStore()
+	require.NoError(t, store.AppendEvents(ctx, 7, []eventing.IStorableEvent[int64]{
+		eventing.NewEvent[int64](7, "Agg", "Evt", 1, nil),
+		eventing.NewEvent[int64](7, "Agg", "Evt", 2, nil),
+		eventing.NewEvent[int64](7, "Agg", "Evt", 3, nil),
+	}, 0))
+
+	it, err := store.LoadEventsIter(ctx, 7, 1)
+	require.NoError(t, err)
+	got, err := CollectEvents(it)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, uint64(2), got[0].Version)
+	require.Nil(t, it.Event(), "closed iterator has no current event")
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"gochen/errors"
 	"gochen/eventing"
@@ -20,6 +21,8 @@ type MemoryEventStore struct {
 	events map[string][]eventing.Event[int64] // aggregateType:aggregateID -> ordered events
 	// eventsByID 按聚合 ID 维度组织，用于快速按聚合 ID 读取/检查版本，避免对 events 做 O(N) 级扫描。
 	eventsByID map[int64][]eventing.Event[int64] // aggregateID -> ordered events (跨类型聚合)
+	// tombstoned 记录已软删除的 aggregateType:aggregateID，其事件对加载与流式读取不可见。
+	tombstoned map[string]struct{}
 }
 
 // NewMemoryEventStore 创建一个仅供测试和示例使用的内存事件存储。
@@ -27,6 +30,7 @@ func NewMemoryEventStore() *MemoryEventStore {
 	return &MemoryEventStore{
 		events:     make(map[string][]eventing.Event[int64]),
 		eventsByID: make(map[int64][]eventing.Event[int64]),
+		tombstoned: make(map[string]struct{}),
 	}
 }
 
@@ -110,8 +114,8 @@ func (m *MemoryEventStore) LoadEvents(ctx context.Context, aggregateID int64, af
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	aggregateEvents, ok := m.eventsByID[aggregateID]
-	if !ok || len(aggregateEvents) == 0 {
+	aggregateEvents := m.visibleByIDUnsafe(aggregateID)
+	if len(aggregateEvents) == 0 {
 		return []eventing.Event[int64]{}, nil
 	}
 
@@ -130,13 +134,22 @@ func (m *MemoryEventStore) LoadEvents(ctx context.Context, aggregateID int64, af
 	return aggregateEvents[startIdx:], nil
 }
 
+// LoadEventsIter 以迭代器视图返回 LoadEvents 的结果（事件已在内存中，无需分页）。
+func (m *MemoryEventStore) LoadEventsIter(ctx context.Context, aggregateID int64, afterVersion uint64) (IEventIterator[int64], error) {
+	events, err := m.LoadEvents(ctx, aggregateID, afterVersion)
+	if err != nil {
+		return nil, err
+	}
+	return NewSliceIterator(events), nil
+}
+
 // LoadEventsByType 返回指定聚合类型下、某个版本之后的事件。
 func (m *MemoryEventStore) LoadEventsByType(ctx context.Context, aggregateType string, aggregateID int64, afterVersion uint64) ([]eventing.Event[int64], error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	key := eventAggregateKey(aggregateType, aggregateID)
 	aggregateEvents := m.events[key]
-	if len(aggregateEvents) == 0 {
+	if len(aggregateEvents) == 0 || m.isTombstonedUnsafe(key) {
 		return []eventing.Event[int64]{}, nil
 	}
 	res := make([]eventing.Event[int64], 0, len(aggregateEvents))
@@ -166,7 +179,7 @@ func (m *MemoryEventStore) StreamAggregate(ctx context.Context, opts *AggregateS
 
 	key := eventAggregateKey(opts.AggregateType, opts.AggregateID)
 	aggregateEvents := m.events[key]
-	if len(aggregateEvents) == 0 {
+	if len(aggregateEvents) == 0 || m.isTombstonedUnsafe(key) {
 		return &AggregateStreamResult[int64]{Events: []eventing.Event[int64]{}}, nil
 	}
 
@@ -200,7 +213,10 @@ func (m *MemoryEventStore) StreamAggregate(ctx context.Context, opts *AggregateS
 func (m *MemoryEventStore) StreamEvents(ctx context.Context, opts *StreamOptions) (*StreamResult[int64], error) {
 	m.mu.RLock()
 	var all []eventing.Event[int64]
-	for _, arr := range m.events {
+	for key, arr := range m.events {
+		if m.isTombstonedUnsafe(key) {
+			continue
+		}
 		all = append(all, arr...)
 	}
 	m.mu.RUnlock()
@@ -213,20 +229,158 @@ func (m *MemoryEventStore) StreamEvents(ctx context.Context, opts *StreamOptions
 func (m *MemoryEventStore) HasAggregate(ctx context.Context, aggregateID int64) (bool, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	events, ok := m.eventsByID[aggregateID]
-	return ok && len(events) > 0, nil
+	return len(m.visibleByIDUnsafe(aggregateID)) > 0, nil
 }
 
 func (m *MemoryEventStore) GetAggregateVersion(ctx context.Context, aggregateID int64) (uint64, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	events, ok := m.eventsByID[aggregateID]
-	if !ok || len(events) == 0 {
+	events := m.visibleByIDUnsafe(aggregateID)
+	if len(events) == 0 {
 		return 0, nil
 	}
 	return events[len(events)-1].GetVersion(), nil
 }
 
+// CountEvents 返回聚合（跨类型）已追加的事件数量。
+func (m *MemoryEventStore) CountEvents(ctx context.Context, aggregateID int64) (uint64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return uint64(len(m.visibleByIDUnsafe(aggregateID))), nil
+}
+
+// HeadVersion 返回指定聚合类型下聚合的最新版本号。
+func (m *MemoryEventStore) HeadVersion(ctx context.Context, aggregateType string, aggregateID int64) (uint64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	key := eventAggregateKey(aggregateType, aggregateID)
+	if m.isTombstonedUnsafe(key) {
+		return 0, nil
+	}
+	return m.getAggregateVersionUnsafe(key)
+}
+
+// PurgeEvents 删除指定聚合类型下版本不大于 throughVersion 的事件（最新事件必须保留）。
+func (m *MemoryEventStore) PurgeEvents(ctx context.Context, aggregateType string, aggregateID int64, throughVersion uint64) (int64, error) {
+	if throughVersion == 0 {
+		return 0, nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := eventAggregateKey(aggregateType, aggregateID)
+	head, _ := m.getAggregateVersionUnsafe(key)
+	if throughVersion >= head {
+		return 0, errors.NewCode(errors.InvalidInput, "cannot purge the head event of an aggregate").
+			WithContext("aggregate_id", aggregateID).
+			WithContext("through_version", throughVersion).
+			WithContext("head_version", head)
+	}
+	// 重新分配切片：LoadEvents 返回的是底层切片引用，不能原地修改。
+	keep := func(events []eventing.Event[int64]) []eventing.Event[int64] {
+		kept := make([]eventing.Event[int64], 0, len(events))
+		for _, e := range events {
+			if e.GetAggregateType() != aggregateType || e.GetVersion() > throughVersion {
+				kept = append(kept, e)
+			}
+		}
+		return kept
+	}
+	before := len(m.events[key])
+	m.events[key] = keep(m.events[key])
+	m.eventsByID[aggregateID] = keep(m.eventsByID[aggregateID])
+	return int64(before - len(m.events[key])), nil
+}
+
+// DeleteAggregate 软删除（追加墓碑事件）或硬删除聚合的事件流，语义见 IAggregateDeleter。
+func (m *MemoryEventStore) DeleteAggregate(ctx context.Context, aggregateID int64, opts DeleteOptions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := eventAggregateKey(opts.AggregateType, aggregateID)
+	var headVersion uint64
+	var headType string
+	if events := m.events[key]; len(events) > 0 {
+		headVersion = events[len(events)-1].GetVersion()
+		headType = events[len(events)-1].GetType()
+	}
+	tombstoned, err := CheckDeleteVersion(aggregateID, opts, headVersion, headType)
+	if err != nil {
+		return err
+	}
+
+	if opts.Mode == DeleteModeHard {
+		m.removeStreamUnsafe(key, opts.AggregateType, aggregateID)
+		return nil
+	}
+	if tombstoned {
+		return nil
+	}
+	marker := NewTombstoneEvent(aggregateID, opts)
+	m.events[key] = append(m.events[key], *marker)
+	m.eventsByID[aggregateID] = append(m.eventsByID[aggregateID], *marker)
+	m.tombstoned[key] = struct{}{}
+	return nil
+}
+
+// PurgeTombstoned 硬删除墓碑事件时间戳早于 before 的事件流，返回删除的事件数。
+func (m *MemoryEventStore) PurgeTombstoned(ctx context.Context, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted int64
+	for key := range m.tombstoned {
+		events := m.events[key]
+		if len(events) == 0 {
+			delete(m.tombstoned, key)
+			continue
+		}
+		head := events[len(events)-1]
+		if !head.GetTimestamp().Before(before) {
+			continue
+		}
+		deleted += int64(len(events))
+		m.removeStreamUnsafe(key, head.GetAggregateType(), head.GetAggregateID())
+	}
+	return deleted, nil
+}
+
+// removeStreamUnsafe 在持锁前提下物理删除一条事件流。
+func (m *MemoryEventStore) removeStreamUnsafe(key, aggregateType string, aggregateID int64) {
+	delete(m.events, key)
+	delete(m.tombstoned, key)
+	// 重新分配切片：LoadEvents 返回的是底层切片引用，不能原地修改。
+	kept := make([]eventing.Event[int64], 0, len(m.eventsByID[aggregateID]))
+	for _, e := range m.eventsByID[aggregateID] {
+		if e.GetAggregateType() != aggregateType {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) == 0 {
+		delete(m.eventsByID, aggregateID)
+	} else {
+		m.eventsByID[aggregateID] = kept
+	}
+}
+
+// isTombstonedUnsafe 在持锁前提下判断复合键对应的事件流是否已软删除。
+func (m *MemoryEventStore) isTombstonedUnsafe(key string) bool {
+	_, ok := m.tombstoned[key]
+	return ok
+}
+
+// visibleByIDUnsafe 在持锁前提下返回聚合（跨类型）未被软删除的事件。
+func (m *MemoryEventStore) visibleByIDUnsafe(aggregateID int64) []eventing.Event[int64] {
+	events := m.eventsByID[aggregateID]
+	if len(m.tombstoned) == 0 || len(events) == 0 {
+		return events
+	}
+	visible := events[:0:0]
+	for _, e := range events {
+		if !m.isTombstonedUnsafe(eventAggregateKey(e.GetAggregateType(), aggregateID)) {
+			visible = append(visible, e)
+		}
+	}
+	return visible
+}
+
 // eventAggregateKey 生成内部使用的 `aggregateType:aggregateID` 复合键。
 func eventAggregateKey(aggregateType string, aggregateID int64) string {
 	return fmt.Sprintf("%s:%d", aggregateType, aggregateID)
@@ -241,5 +395,9 @@ func (m *MemoryEventStore) getAggregateVersionUnsafe(key string) (uint64, error)
 	return aggregateEvents[len(aggregateEvents)-1].GetVersion(), nil
 }
 
-// 编译期断言：确保 MemoryEventStore 实现 IEventStreamStore[int64]。
-var _ IEventStreamStore[int64] = (*MemoryEventStore)(nil)
+// 编译期断言：确保 MemoryEventStore 实现 IEventStreamStore[int64]、IAggregateDeleter[int64] 与 ITombstonePurger。
+var (
+	_ IEventStreamStore[int64] = (*MemoryEventStore)(nil)
+	_ IAggregateDeleter[int64] = (*MemoryEventStore)(nil)
+	_ ITombstonePurger         = (*MemoryEventStore)(nil)
+)
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"gochen/errors"
 	"gochen/eventing"
 )
 
@@ -39,3 +40,66 @@ func TestMemoryEventStore_StreamEvents_FilterByAfterAndType(t *testing.T) {
 	require.False(t, result.HasMore)
 	require.Equal(t, e3.ID, result.NextCursor)
 }
+
+// TestMemoryEventStore_CountEventsAndHeadVersion 验证 MemoryEventStore 计数与按类型版本查询。
+func TestMemoryEventStore_CountEventsAndHeadVersion(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryEventStore()
+
+	count, err := store.CountEvents(ctx, 1)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	e1 := eventing.NewEvent[int64](1, "Agg", "Evt", 1, nil)
+	e2 := eventing.NewEvent[int64](1, "Agg", "Evt", 2, nil)
+	require.NoError(t, store.AppendEvents(ctx, 1, []eventing.IStorableEvent[int64]{e1, e2}, 0))
+
+	count, err = store.CountEvents(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), count)
+
+	head, err := store.HeadVersion(ctx, "Agg", 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), head)
+
+	head, err = store.HeadVersion(ctx, "Other", 1)
+	require.NoError(t, err)
+	require.Zero(t, head)
+}
+
+// TestMemoryEventStore_DeleteAggregate 验证内存存储软删除隐藏事件流、硬删除移除事件并校验期望版本。
+func TestMemoryEventStore_DeleteAggregate(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryEventStore()
+	for id := int64(1); id <= 2; id++ {
+		require.NoError(t, store.AppendEvents(ctx, id, []eventing.IStorableEvent[int64]{
+			eventing.NewEvent[int64](id, "Agg", "Created", 1, nil),
+			eventing.NewEvent[int64](id, "Agg", "Renamed", 2, nil),
+		}, 0))
+	}
+
+	opts := DeleteOptions{AggregateType: "Agg", ExpectedVersion: 1}
+	require.True(t, errors.Is(DeleteAggregate[int64](ctx, store, 1, opts), errors.Concurrency))
+
+	opts.ExpectedVersion = 2
+	require.NoError(t, DeleteAggregate[int64](ctx, store, 1, opts))
+	loaded, err := store.LoadEvents(ctx, 1, 0)
+	require.NoError(t, err)
+	require.Empty(t, loaded)
+	version, err := store.GetAggregateVersion(ctx, 1)
+	require.NoError(t, err)
+	require.Zero(t, version)
+	stream, err := store.StreamEvents(ctx, &StreamOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, stream.Events, 2)
+	require.True(t, errors.Is(store.AppendEvents(ctx, 1, []eventing.IStorableEvent[int64]{
+		eventing.NewEvent[int64](1, "Agg", "Renamed", 3, nil),
+	}, 2), errors.Concurrency), "writes after tombstoning must conflict")
+
+	opts.Mode = DeleteModeHard
+	require.NoError(t, DeleteAggregate[int64](ctx, store, 1, opts))
+	count, err := store.CountEvents(ctx, 1)
+	require.NoError(t, err)
+	require.Zero(t, count)
+	require.True(t, errors.Is(DeleteAggregate[int64](ctx, store, 1, opts), errors.NotFound))
+}
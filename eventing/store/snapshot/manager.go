@@ -2,11 +2,11 @@ package snapshot
 
 import (
 	"context"
-	"encoding/json"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"gochen/clock"
 	"gochen/errors"
 	"gochen/eventing/monitoring"
 	"gochen/logging"
@@ -17,8 +17,15 @@ type Manager[ID comparable] struct {
 	snapshotStore ISnapshotStore[ID]
 	config        *Config
 	strategy      ISnapshotStrategy[ID] // 快照策略
+	serializer    ISerializer           // 快照数据序列化器（默认 JSON）
+	upgraders     *UpgraderRegistry     // 快照 schema 升级器（可选）
+	clock         clock.IClock          // 快照时间戳、耗时与后台清理的时间来源
 	metrics       atomic.Value          // snapshotMetricsHolder（承载 monitoring.ISnapshotMetricsRecorder），用于并发热替换且避免 data race
 	mutex         sync.RWMutex
+
+	pruneMu   sync.Mutex
+	pruneStop chan struct{} // 非 nil 表示后台清理协程运行中
+	pruneDone chan struct{}
 }
 
 type snapshotMetricsHolder struct {
@@ -28,9 +35,14 @@ type snapshotMetricsHolder struct {
 // Config 快照配置，用于控制事件快照的生成和存储策略。
 type Config struct {
 	Frequency       int           `json:"frequency"`
-	RetentionPeriod time.Duration `json:"retention_period"`
+	RetentionPeriod time.Duration `json:"retention_period"` // 快照最大保留时长（按快照时间戳）
 	MaxSnapshots    int           `json:"max_snapshots"`
 	Enabled         bool          `json:"enabled"`
+
+	// KeepLast 为每个聚合保留的最近快照数（0 表示不按数量裁剪），仅对实现 ISnapshotHistoryPruner 的存储生效。
+	KeepLast int `json:"keep_last"`
+	// PruneInterval 为 StartPruneWorker 的执行间隔，<=0 时不允许启动后台清理。
+	PruneInterval time.Duration `json:"prune_interval"`
 }
 
 func DefaultConfig() *Config {
@@ -41,7 +53,7 @@ func DefaultConfig() *Config {
 func NewManager[ID comparable](snapshotStore ISnapshotStore[ID], config *Config) *Manager[ID] {
 	config = normalizeConfig(config)
 	defaultStrategy := NewEventCountStrategy[ID](config.Frequency)
-	return &Manager[ID]{snapshotStore: snapshotStore, config: config, strategy: defaultStrategy}
+	return &Manager[ID]{snapshotStore: snapshotStore, config: config, strategy: defaultStrategy, serializer: JSONSerializer{}, clock: clock.NewRealClock()}
 }
 
 func normalizeConfig(config *Config) *Config {
@@ -93,6 +105,87 @@ func (sm *Manager[ID]) Strategy() ISnapshotStrategy[ID] {
 	return sm.strategy
 }
 
+// SetSerializer 替换快照数据序列化器；nil 恢复为 JSONSerializer。
+//
+// 序列化器名称写入快照 metadata，恢复时名称不一致会返回 Unsupported。
+func (sm *Manager[ID]) SetSerializer(serializer ISerializer) {
+	if serializer == nil {
+		serializer = JSONSerializer{}
+	}
+	sm.mutex.Lock()
+	sm.serializer = serializer
+	sm.mutex.Unlock()
+}
+
+// SetUpgraders 设置快照 schema 升级器注册表；新快照按注册表中的当前版本写入，旧快照恢复时逐级升级。
+func (sm *Manager[ID]) SetUpgraders(upgraders *UpgraderRegistry) {
+	sm.mutex.Lock()
+	sm.upgraders = upgraders
+	sm.mutex.Unlock()
+}
+
+// SetClock 替换快照时间戳、耗时统计与后台清理使用的时钟；nil 恢复为真实时钟。
+//
+// 测试中可注入 clock.ManualClock，以推进时间代替等待。
+func (sm *Manager[ID]) SetClock(clk clock.IClock) {
+	if clk == nil {
+		clk = clock.NewRealClock()
+	}
+	sm.mutex.Lock()
+	sm.clock = clk
+	sm.mutex.Unlock()
+}
+
+func (sm *Manager[ID]) getClock() clock.IClock {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+	return sm.clock
+}
+
+func (sm *Manager[ID]) codecs() (ISerializer, *UpgraderRegistry) {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+	return sm.serializer, sm.upgraders
+}
+
+// decodeData 按快照编码信息把数据升级到当前 schema 版本，并更新快照的 Data 与 metadata。
+func (sm *Manager[ID]) decodeData(snapshot *Snapshot[ID]) (ISerializer, error) {
+	serializer, upgraders := sm.codecs()
+	env := EnvelopeOf(snapshot)
+	if env.Serializer != serializer.Name() {
+		return nil, errors.NewCode(errors.Unsupported, "snapshot serializer mismatch").
+			WithContext("aggregate_type", snapshot.AggregateType).
+			WithContext("aggregate_id", snapshot.AggregateID).
+			WithContext("snapshot_serializer", env.Serializer).
+			WithContext("serializer", serializer.Name())
+	}
+	if upgraders == nil || env.SchemaVersion >= upgraders.SchemaVersion(snapshot.AggregateType) {
+		return serializer, nil
+	}
+	var data map[string]any
+	if err := serializer.Unmarshal(env.Data, &data); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, "failed to decode snapshot data for upgrade").
+			WithContext("aggregate_type", snapshot.AggregateType).
+			WithContext("aggregate_id", snapshot.AggregateID).
+			WithContext("schema_version", env.SchemaVersion)
+	}
+	upgraded, version, err := upgraders.Upgrade(snapshot.AggregateType, env.SchemaVersion, data)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := serializer.Marshal(upgraded)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "failed to encode upgraded snapshot data")
+	}
+	metadata := make(map[string]any, len(snapshot.Metadata)+1)
+	for k, v := range snapshot.Metadata {
+		metadata[k] = v
+	}
+	metadata[MetadataSchemaVersion] = version
+	snapshot.Data, snapshot.Metadata = encoded, metadata
+	return serializer, nil
+}
+
 // ShouldCreateSnapshot 判断当前聚合版本是否已经满足创建快照的条件。
 func (sm *Manager[ID]) ShouldCreateSnapshot(ctx context.Context, aggregate ISnapshotAggregate[ID]) (bool, error) {
 	if !sm.config.Enabled || aggregate == nil {
@@ -130,25 +223,32 @@ func (sm *Manager[ID]) CreateSnapshot(ctx context.Context, aggregateID ID, aggre
 	if !sm.config.Enabled {
 		return nil
 	}
-	start := time.Now()
+	clk := sm.getClock()
+	start := clk.Now()
 	var snapshotData any = data
 	if lightweight, ok := data.(interface{ SnapshotData() any }); ok {
 		snapshotData = lightweight.SnapshotData()
 		snapshotLogger().Debug(ctx, "using lightweight snapshot",
 			logging.Any("aggregate_id", aggregateID), logging.String("aggregate_type", aggregateType))
 	}
-	serializedData, err := json.Marshal(snapshotData)
+	serializer, upgraders := sm.codecs()
+	serializedData, err := serializer.Marshal(snapshotData)
 	if err != nil {
 		return errors.Wrap(err, errors.Internal, "failed to serialize snapshot data")
 	}
-	snap := Snapshot[ID]{AggregateID: aggregateID, AggregateType: aggregateType, Version: version, Data: serializedData, Timestamp: time.Now(), Metadata: map[string]any{"created_by": "snapshot_manager", "data_size": len(serializedData)}}
+	snap := Snapshot[ID]{AggregateID: aggregateID, AggregateType: aggregateType, Version: version, Data: serializedData, Timestamp: clk.Now(), Metadata: map[string]any{
+		"created_by":          "snapshot_manager",
+		"data_size":           len(serializedData),
+		MetadataSchemaVersion: upgraders.SchemaVersion(aggregateType),
+		MetadataSerializer:    serializer.Name(),
+	}}
 	if err := sm.snapshotStore.SaveSnapshot(ctx, snap); err != nil {
 		return errors.Wrap(err, errors.Database, "failed to save snapshot").
 			WithContext("aggregate_type", aggregateType).
 			WithContext("aggregate_id", aggregateID)
 	}
 	if m := sm.getMetrics(); m != nil {
-		m.RecordSnapshotCreated(time.Since(start))
+		m.RecordSnapshotCreated(clk.Now().Sub(start))
 	}
 	snapshotLogger().Info(ctx, "snapshot created",
 		logging.Any("aggregate_id", aggregateID),
@@ -159,7 +259,8 @@ func (sm *Manager[ID]) CreateSnapshot(ctx context.Context, aggregateID ID, aggre
 
 // LoadSnapshot 读取快照并把状态恢复到目标对象。
 func (sm *Manager[ID]) LoadSnapshot(ctx context.Context, aggregateID ID, target any) (*Snapshot[ID], error) {
-	start := time.Now()
+	clk := sm.getClock()
+	start := clk.Now()
 	aggregateType := ""
 	if typed, ok := target.(interface{ GetAggregateType() string }); ok {
 		aggregateType = typed.GetAggregateType()
@@ -167,15 +268,22 @@ func (sm *Manager[ID]) LoadSnapshot(ctx context.Context, aggregateID ID, target
 	snapshot, err := sm.snapshotStore.FindSnapshot(ctx, aggregateType, aggregateID)
 	if err != nil {
 		if m := sm.getMetrics(); m != nil {
-			m.RecordSnapshotLoaded(time.Since(start), false)
+			m.RecordSnapshotLoaded(clk.Now().Sub(start), false)
+		}
+		return nil, err
+	}
+	serializer, err := sm.decodeData(snapshot)
+	if err != nil {
+		if m := sm.getMetrics(); m != nil {
+			m.RecordSnapshotLoaded(clk.Now().Sub(start), false)
 		}
 		return nil, err
 	}
 	if restorer, ok := target.(interface{ RestoreFromSnapshotData(data any) error }); ok {
 		var snapshotData any
-		if err := json.Unmarshal(snapshot.Data, &snapshotData); err != nil {
+		if err := serializer.Unmarshal(snapshot.Data, &snapshotData); err != nil {
 			if m := sm.getMetrics(); m != nil {
-				m.RecordSnapshotLoaded(time.Since(start), false)
+				m.RecordSnapshotLoaded(clk.Now().Sub(start), false)
 			}
 			return nil, errors.Wrap(err, errors.InvalidInput, "failed to deserialize lightweight snapshot data").
 				WithContext("aggregate_type", aggregateType).
@@ -183,7 +291,7 @@ func (sm *Manager[ID]) LoadSnapshot(ctx context.Context, aggregateID ID, target
 		}
 		if err := restorer.RestoreFromSnapshotData(snapshotData); err != nil {
 			if m := sm.getMetrics(); m != nil {
-				m.RecordSnapshotLoaded(time.Since(start), false)
+				m.RecordSnapshotLoaded(clk.Now().Sub(start), false)
 			}
 			return nil, errors.Wrap(err, errors.Internal, "failed to restore from lightweight snapshot").
 				WithContext("aggregate_type", aggregateType).
@@ -192,9 +300,9 @@ func (sm *Manager[ID]) LoadSnapshot(ctx context.Context, aggregateID ID, target
 		snapshotLogger().Debug(ctx, "restored from lightweight snapshot",
 			logging.Any("aggregate_id", aggregateID))
 	} else {
-		if err := json.Unmarshal(snapshot.Data, target); err != nil {
+		if err := serializer.Unmarshal(snapshot.Data, target); err != nil {
 			if m := sm.getMetrics(); m != nil {
-				m.RecordSnapshotLoaded(time.Since(start), false)
+				m.RecordSnapshotLoaded(clk.Now().Sub(start), false)
 			}
 			return nil, errors.Wrap(err, errors.InvalidInput, "failed to deserialize snapshot data").
 				WithContext("aggregate_type", aggregateType).
@@ -202,7 +310,7 @@ func (sm *Manager[ID]) LoadSnapshot(ctx context.Context, aggregateID ID, target
 		}
 	}
 	if m := sm.getMetrics(); m != nil {
-		m.RecordSnapshotLoaded(time.Since(start), true)
+		m.RecordSnapshotLoaded(clk.Now().Sub(start), true)
 	}
 	snapshotLogger().Debug(ctx, "snapshot loaded",
 		logging.Any("aggregate_id", aggregateID),
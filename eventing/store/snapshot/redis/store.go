@@ -0,0 +1,294 @@
+// Package redis 提供基于 Redis 的快照存储，实现 snapshot.ISnapshotStore。
+//
+// 快照写入时带 TTL，依赖 Redis 过期实现保留期管理，适合高吞吐聚合快速恢复、不占用主 SQL 库的场景。
+// 框架核心不引入 Redis 客户端依赖：通过 IClient 适配任意客户端（go-redis 适配示例见 README.md）。
+//
+// 键格式为 "<KeyPrefix><aggregate_type>:<aggregate_id>"；ctx 携带租户时为
+// "<KeyPrefix>@<tenant_id>:<aggregate_type>:<aggregate_id>"。各段中的 '%'、':'、'@' 按百分号编码转义，
+// 因此聚合类型或 ID 含 ':' 时不会与其他类型/租户的键混淆。值为快照 JSON。
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gochen/clock"
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/eventing/store/snapshot"
+	"gochen/logging"
+)
+
+const (
+	// DefaultKeyPrefix 是默认的键前缀。
+	DefaultKeyPrefix = "gochen:snapshot:"
+	// DefaultTTL 是默认的快照保留时长。
+	DefaultTTL = 7 * 24 * time.Hour
+	// DefaultScanCount 是 SCAN 每批建议返回的键数量。
+	DefaultScanCount = 100
+)
+
+// IClient 是快照存储所需的最小 Redis 客户端能力。
+type IClient interface {
+	// Get 读取键值；键不存在时返回 found=false 且 err=nil。
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	// Set 写入键值；ttl<=0 表示不过期。
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Del 删除键；键不存在不视为错误。
+	Del(ctx context.Context, keys ...string) error
+	// Scan 按 match 模式增量遍历键；返回的 next 为 0 表示遍历结束。
+	Scan(ctx context.Context, cursor uint64, match string, count int64) (keys []string, next uint64, err error)
+}
+
+// Config 定义 Redis 快照存储配置。
+type Config struct {
+	// KeyPrefix 为空时使用 DefaultKeyPrefix。
+	KeyPrefix string
+	// TTL 为每条快照的保留时长，每次保存会刷新；为 0 时使用 DefaultTTL，小于 0 表示不过期。
+	TTL time.Duration
+	// ScanCount 为 0 时使用 DefaultScanCount。
+	ScanCount int64
+	// Clock 用于补全快照时间戳与计算保留期截止时间；为 nil 时使用真实时钟。
+	Clock clock.IClock
+}
+
+// Store 是基于 Redis 的快照存储。
+type Store[ID comparable] struct {
+	client IClient
+	config Config
+	logger logging.ILogger
+}
+
+// NewStore 创建 Redis 快照存储。
+func NewStore[ID comparable](client IClient, config Config) (*Store[ID], error) {
+	if client == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "redis snapshot store client cannot be nil")
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = DefaultKeyPrefix
+	}
+	if config.TTL == 0 {
+		config.TTL = DefaultTTL
+	}
+	if config.ScanCount <= 0 {
+		config.ScanCount = DefaultScanCount
+	}
+	if config.Clock == nil {
+		config.Clock = clock.NewRealClock()
+	}
+	return &Store[ID]{client: client, config: config, logger: logging.ComponentLogger("eventstore.snapshot.redis")}, nil
+}
+
+// key 返回快照键：ctx 携带租户时追加租户段，各段均经 escapeKeySegment 转义。
+func (s *Store[ID]) key(ctx context.Context, aggregateType string, aggregateID ID) string {
+	return s.scopePrefix(ctx) + escapeKeySegment(aggregateType) + ":" + escapeKeySegment(fmt.Sprint(aggregateID))
+}
+
+// scopePrefix 返回 ctx 租户对应的键前缀；无租户时为 KeyPrefix。
+func (s *Store[ID]) scopePrefix(ctx context.Context) string {
+	tenantID := strings.TrimSpace(contextx.TenantID(ctx))
+	if tenantID == "" {
+		return s.config.KeyPrefix
+	}
+	return s.config.KeyPrefix + tenantSegmentMarker + escapeKeySegment(tenantID) + ":"
+}
+
+// aggregateTypeSegment 返回键中（已转义的）聚合类型段；键格式不符时 ok=false。
+func (s *Store[ID]) aggregateTypeSegment(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, s.config.KeyPrefix)
+	if !ok {
+		return "", false
+	}
+	if strings.HasPrefix(rest, tenantSegmentMarker) {
+		if _, rest, ok = strings.Cut(rest, ":"); !ok {
+			return "", false
+		}
+	}
+	aggregateType, _, ok := strings.Cut(rest, ":")
+	return aggregateType, ok
+}
+
+// SaveSnapshot 覆盖保存一条聚合快照，并刷新 TTL。
+func (s *Store[ID]) SaveSnapshot(ctx context.Context, snap snapshot.Snapshot[ID]) error {
+	if snap.Timestamp.IsZero() {
+		snap.Timestamp = s.config.Clock.Now()
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return errors.Wrap(err, errors.InvalidInput, "serialize snapshot failed").
+			WithContext("aggregate_type", snap.AggregateType).
+			WithContext("aggregate_id", snap.AggregateID)
+	}
+	ttl := s.config.TTL
+	if ttl < 0 {
+		ttl = 0
+	}
+	if err := s.client.Set(ctx, s.key(ctx, snap.AggregateType, snap.AggregateID), data, ttl); err != nil {
+		return errors.Wrap(err, errors.Dependency, "save snapshot to redis failed").
+			WithContext("aggregate_type", snap.AggregateType).
+			WithContext("aggregate_id", snap.AggregateID)
+	}
+	s.logger.Debug(ctx, "snapshot saved",
+		logging.Any("aggregate_id", snap.AggregateID),
+		logging.String("aggregate_type", snap.AggregateType),
+		logging.Any("version", snap.Version))
+	return nil
+}
+
+// FindSnapshot 读取指定聚合的最新快照；不存在或已过期时返回 NotFound。
+func (s *Store[ID]) FindSnapshot(ctx context.Context, aggregateType string, aggregateID ID) (*snapshot.Snapshot[ID], error) {
+	data, found, err := s.client.Get(ctx, s.key(ctx, aggregateType, aggregateID))
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Dependency, "load snapshot from redis failed").
+			WithContext("aggregate_type", aggregateType).
+			WithContext("aggregate_id", aggregateID)
+	}
+	if !found {
+		return nil, errors.NewCode(errors.NotFound, "snapshot not found").
+			WithContext("aggregate_type", aggregateType).
+			WithContext("aggregate_id", aggregateID)
+	}
+	var snap snapshot.Snapshot[ID]
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, "decode snapshot failed").
+			WithContext("aggregate_type", aggregateType).
+			WithContext("aggregate_id", aggregateID)
+	}
+	return &snap, nil
+}
+
+// DeleteSnapshot 删除指定聚合的快照。
+func (s *Store[ID]) DeleteSnapshot(ctx context.Context, aggregateType string, aggregateID ID) error {
+	if err := s.client.Del(ctx, s.key(ctx, aggregateType, aggregateID)); err != nil {
+		return errors.Wrap(err, errors.Dependency, "delete snapshot from redis failed").
+			WithContext("aggregate_type", aggregateType).
+			WithContext("aggregate_id", aggregateID)
+	}
+	return nil
+}
+
+// ListSnapshots 通过 SCAN 列出匹配聚合类型的快照，按时间倒序返回。
+//
+// ctx 携带租户时只列出该租户的快照；无租户时不按租户过滤（与 SQLStore 一致）。
+// SCAN 需要遍历键空间，只适合运维与诊断，不应出现在请求热路径上。
+func (s *Store[ID]) ListSnapshots(ctx context.Context, aggregateType string, limit int) ([]snapshot.Snapshot[ID], error) {
+	snapshots, _, err := s.scan(ctx, s.scopePrefix(ctx), aggregateType)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Timestamp.After(snapshots[j].Timestamp)
+	})
+	if limit > 0 && len(snapshots) > limit {
+		snapshots = snapshots[:limit]
+	}
+	return snapshots, nil
+}
+
+// CleanupSnapshots 删除早于保留期限的快照。
+//
+// 常规保留由 TTL 完成；该方法用于缩短保留期或清理未设置 TTL 的历史键。属于运维操作，始终跨租户执行。
+func (s *Store[ID]) CleanupSnapshots(ctx context.Context, retentionPeriod time.Duration) error {
+	if retentionPeriod <= 0 {
+		return nil
+	}
+	snapshots, keys, err := s.scan(ctx, s.config.KeyPrefix, "")
+	if err != nil {
+		return err
+	}
+	cutoff := s.config.Clock.Now().Add(-retentionPeriod)
+	expired := make([]string, 0)
+	for i, snap := range snapshots {
+		if snap.Timestamp.Before(cutoff) {
+			expired = append(expired, keys[i])
+		}
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+	if err := s.client.Del(ctx, expired...); err != nil {
+		return errors.Wrap(err, errors.Dependency, "cleanup snapshots from redis failed")
+	}
+	s.logger.Info(ctx, "expired snapshots cleaned up", logging.Int("deleted_count", len(expired)))
+	return nil
+}
+
+// scan 遍历 prefix 下匹配聚合类型的快照，返回快照与对应键（下标一致）；遍历期间过期的键直接跳过。
+//
+// prefix 为 KeyPrefix 时覆盖全部租户，此时聚合类型无法用 MATCH 精确表达，改为解析键后过滤。
+func (s *Store[ID]) scan(ctx context.Context, prefix, aggregateType string) ([]snapshot.Snapshot[ID], []string, error) {
+	match := escapeGlob(prefix) + "*"
+	filterType := ""
+	if aggregateType != "" {
+		if prefix == s.config.KeyPrefix {
+			filterType = escapeKeySegment(aggregateType)
+		} else {
+			match = escapeGlob(prefix+escapeKeySegment(aggregateType)+":") + "*"
+		}
+	}
+	var (
+		snapshots []snapshot.Snapshot[ID]
+		keys      []string
+		cursor    uint64
+	)
+	for {
+		batch, next, err := s.client.Scan(ctx, cursor, match, s.config.ScanCount)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, errors.Dependency, "scan snapshots from redis failed")
+		}
+		for _, key := range batch {
+			if filterType != "" {
+				if segment, ok := s.aggregateTypeSegment(key); !ok || segment != filterType {
+					continue
+				}
+			}
+			data, found, err := s.client.Get(ctx, key)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, errors.Dependency, "load snapshot from redis failed").WithContext("key", key)
+			}
+			if !found {
+				continue
+			}
+			var snap snapshot.Snapshot[ID]
+			if err := json.Unmarshal(data, &snap); err != nil {
+				s.logger.Warn(ctx, "skip undecodable snapshot", logging.String("key", key), logging.Error(err))
+				continue
+			}
+			snapshots = append(snapshots, snap)
+			keys = append(keys, key)
+		}
+		if next == 0 {
+			return snapshots, keys, nil
+		}
+		cursor = next
+	}
+}
+
+// tenantSegmentMarker 标记租户段；转义后的聚合类型段不会以它开头。
+const tenantSegmentMarker = "@"
+
+var keySegmentEscaper = strings.NewReplacer("%", "%25", ":", "%3A", "@", "%40")
+
+// escapeKeySegment 转义键段中的分隔符，保证 ':' 只作为段分隔符出现。
+func escapeKeySegment(s string) string {
+	return keySegmentEscaper.Replace(s)
+}
+
+// escapeGlob 转义 Redis MATCH 模式中的特殊字符。
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+var _ snapshot.ISnapshotStore[int64] = (*Store[int64])(nil)
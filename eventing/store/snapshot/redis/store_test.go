@@ -0,0 +1,199 @@
+package redis
+
+import (
+	"context"
+	"path"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/clock"
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/eventing/store/snapshot"
+)
+
+// fakeClient 是内存版 IClient，记录 TTL 并按 MATCH 模式一次返回全部键。
+type fakeClient struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	ttls map[string]time.Duration
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{data: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (c *fakeClient) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.data[key]
+	return v, ok, nil
+}
+
+func (c *fakeClient) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data[key] = value
+	c.ttls[key] = ttl
+	return nil
+}
+
+func (c *fakeClient) Del(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.data, key)
+		delete(c.ttls, key)
+	}
+	return nil
+}
+
+func (c *fakeClient) Scan(_ context.Context, _ uint64, match string, _ int64) ([]string, uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]string, 0, len(c.data))
+	for key := range c.data {
+		if ok, _ := path.Match(match, key); ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, 0, nil
+}
+
+func TestStore_SaveFindDelete(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient()
+	store, err := NewStore[int64](client, Config{TTL: time.Hour})
+	require.NoError(t, err)
+
+	require.NoError(t, store.SaveSnapshot(ctx, snapshot.Snapshot[int64]{
+		AggregateID:   42,
+		AggregateType: "Order",
+		Version:       7,
+		Data:          []byte(`{"total":10}`),
+		Metadata:      map[string]any{"source": "test"},
+	}))
+	require.Equal(t, time.Hour, client.ttls["gochen:snapshot:Order:42"])
+
+	snap, err := store.FindSnapshot(ctx, "Order", 42)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), snap.AggregateID)
+	require.Equal(t, uint64(7), snap.Version)
+	require.JSONEq(t, `{"total":10}`, string(snap.Data))
+	require.False(t, snap.Timestamp.IsZero())
+
+	require.NoError(t, store.DeleteSnapshot(ctx, "Order", 42))
+	_, err = store.FindSnapshot(ctx, "Order", 42)
+	require.True(t, errors.Is(err, errors.NotFound))
+}
+
+func TestStore_ListAndCleanup(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient()
+	store, err := NewStore[string](client, Config{KeyPrefix: "app:snap:", TTL: -1})
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, store.SaveSnapshot(ctx, snapshot.Snapshot[string]{AggregateID: "a", AggregateType: "Order", Version: 1, Timestamp: now.Add(-48 * time.Hour)}))
+	require.NoError(t, store.SaveSnapshot(ctx, snapshot.Snapshot[string]{AggregateID: "b", AggregateType: "Order", Version: 2, Timestamp: now}))
+	require.NoError(t, store.SaveSnapshot(ctx, snapshot.Snapshot[string]{AggregateID: "c", AggregateType: "Order*", Version: 3, Timestamp: now}))
+	require.Equal(t, time.Duration(0), client.ttls["app:snap:Order:a"], "negative TTL means no expiry")
+
+	list, err := store.ListSnapshots(ctx, "Order", 0)
+	require.NoError(t, err)
+	require.Len(t, list, 2, "glob characters in aggregate type are escaped")
+	require.Equal(t, "b", list[0].AggregateID)
+
+	list, err = store.ListSnapshots(ctx, "Order", 1)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	require.NoError(t, store.CleanupSnapshots(ctx, 24*time.Hour))
+	_, err = store.FindSnapshot(ctx, "Order", "a")
+	require.True(t, errors.Is(err, errors.NotFound))
+	_, err = store.FindSnapshot(ctx, "Order", "b")
+	require.NoError(t, err)
+}
+
+func TestStore_UsesInjectedClock(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewManualClock(now)
+	store, err := NewStore[int64](newFakeClient(), Config{TTL: -1, Clock: clk})
+	require.NoError(t, err)
+
+	require.NoError(t, store.SaveSnapshot(ctx, snapshot.Snapshot[int64]{AggregateID: 1, AggregateType: "Order", Version: 1}))
+	snap, err := store.FindSnapshot(ctx, "Order", 1)
+	require.NoError(t, err)
+	require.True(t, snap.Timestamp.Equal(now), "timestamp=%s", snap.Timestamp)
+
+	require.NoError(t, store.CleanupSnapshots(ctx, time.Hour))
+	_, err = store.FindSnapshot(ctx, "Order", 1)
+	require.NoError(t, err, "snapshot is within retention by the injected clock")
+
+	clk.Advance(2 * time.Hour)
+	require.NoError(t, store.CleanupSnapshots(ctx, time.Hour))
+	_, err = store.FindSnapshot(ctx, "Order", 1)
+	require.True(t, errors.Is(err, errors.NotFound))
+}
+
+func TestStore_AggregateTypeWithColonIsNotAmbiguous(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewStore[string](newFakeClient(), Config{TTL: -1})
+	require.NoError(t, err)
+
+	require.NoError(t, store.SaveSnapshot(ctx, snapshot.Snapshot[string]{AggregateID: "1", AggregateType: "A", Version: 1}))
+	require.NoError(t, store.SaveSnapshot(ctx, snapshot.Snapshot[string]{AggregateID: "1", AggregateType: "A:B", Version: 2}))
+	require.NoError(t, store.SaveSnapshot(ctx, snapshot.Snapshot[string]{AggregateID: "B:1", AggregateType: "A", Version: 3}))
+
+	list, err := store.ListSnapshots(ctx, "A", 0)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	for _, snap := range list {
+		require.Equal(t, "A", snap.AggregateType)
+	}
+
+	snap, err := store.FindSnapshot(ctx, "A:B", "1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), snap.Version)
+}
+
+func TestStore_ScopesSnapshotsByTenant(t *testing.T) {
+	client := newFakeClient()
+	store, err := NewStore[int64](client, Config{TTL: -1})
+	require.NoError(t, err)
+	tenantA, err := contextx.WithTenantID(context.Background(), "tenant-a")
+	require.NoError(t, err)
+	tenantB, err := contextx.WithTenantID(context.Background(), "tenant-b")
+	require.NoError(t, err)
+
+	require.NoError(t, store.SaveSnapshot(tenantA, snapshot.Snapshot[int64]{AggregateID: 1, AggregateType: "Order", Version: 1}))
+	require.Contains(t, client.data, "gochen:snapshot:@tenant-a:Order:1")
+
+	_, err = store.FindSnapshot(tenantB, "Order", 1)
+	require.True(t, errors.Is(err, errors.NotFound))
+	require.NoError(t, store.DeleteSnapshot(tenantB, "Order", 1))
+	_, err = store.FindSnapshot(tenantA, "Order", 1)
+	require.NoError(t, err)
+
+	list, err := store.ListSnapshots(tenantB, "Order", 0)
+	require.NoError(t, err)
+	require.Empty(t, list)
+	list, err = store.ListSnapshots(tenantA, "Order", 0)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	list, err = store.ListSnapshots(context.Background(), "Order", 0)
+	require.NoError(t, err)
+	require.Len(t, list, 1, "without a tenant in ctx listing is not tenant-filtered")
+}
+
+func TestNewStore_NilClient(t *testing.T) {
+	_, err := NewStore[int64](nil, Config{})
+	require.True(t, errors.Is(err, errors.InvalidInput))
+}
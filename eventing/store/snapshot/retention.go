@@ -0,0 +1,103 @@
+package snapshot
+
+import (
+	"context"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/logging"
+)
+
+// ISnapshotHistoryPruner 是保留多版本快照的存储可选实现的能力：每个聚合只保留最近 keepLast 份。
+//
+// 内置的 MemoryStore / SQLStore / redis.Store 对每个聚合只保存最新一份（SaveSnapshot 覆盖写），
+// 天然满足 KeepLast>=1，因此无需实现该接口。
+type ISnapshotHistoryPruner interface {
+	PruneSnapshotHistory(ctx context.Context, keepLast int) (deleted int64, err error)
+}
+
+// PruneSnapshots 按配置的保留策略清理快照：
+//   - RetentionPeriod：删除时间戳早于保留期的快照；
+//   - KeepLast：存储实现 ISnapshotHistoryPruner 时，每个聚合只保留最近 KeepLast 份。
+//
+// 快照被清理后，聚合恢复会退回事件重放，因此清理不影响正确性。
+func (sm *Manager[ID]) PruneSnapshots(ctx context.Context) error {
+	if sm.config.RetentionPeriod > 0 {
+		if err := sm.snapshotStore.CleanupSnapshots(ctx, sm.config.RetentionPeriod); err != nil {
+			return errors.Wrap(err, errors.Database, "prune expired snapshots failed")
+		}
+	}
+	if sm.config.KeepLast <= 0 {
+		return nil
+	}
+	pruner, ok := sm.snapshotStore.(ISnapshotHistoryPruner)
+	if !ok {
+		return nil
+	}
+	deleted, err := pruner.PruneSnapshotHistory(ctx, sm.config.KeepLast)
+	if err != nil {
+		return errors.Wrap(err, errors.Database, "prune snapshot history failed").
+			WithContext("keep_last", sm.config.KeepLast)
+	}
+	if deleted > 0 {
+		snapshotLogger().Info(ctx, "snapshot history pruned",
+			logging.Int64("deleted_count", deleted),
+			logging.Int("keep_last", sm.config.KeepLast))
+	}
+	return nil
+}
+
+// StartPruneWorker 启动后台协程，按 Config.PruneInterval 周期执行 PruneSnapshots。
+//
+// 协程在 ctx 取消或调用 StopPruneWorker 时退出；单次清理失败只记录日志，不中断后续周期。
+func (sm *Manager[ID]) StartPruneWorker(ctx context.Context) error {
+	interval := sm.config.PruneInterval
+	if interval <= 0 {
+		return errors.NewCode(errors.InvalidInput, "snapshot prune interval must be positive")
+	}
+	sm.pruneMu.Lock()
+	defer sm.pruneMu.Unlock()
+	if sm.pruneStop != nil {
+		return errors.NewCode(errors.Conflict, "snapshot prune worker already started")
+	}
+	ticker, err := sm.getClock().NewTicker(interval)
+	if err != nil {
+		return err
+	}
+	stop, done := make(chan struct{}), make(chan struct{})
+	sm.pruneStop, sm.pruneDone = stop, done
+	go sm.pruneLoop(ctx, ticker, stop, done)
+	return nil
+}
+
+// StopPruneWorker 停止后台清理协程并等待其退出；未启动时为空操作。
+func (sm *Manager[ID]) StopPruneWorker() {
+	sm.pruneMu.Lock()
+	stop, done := sm.pruneStop, sm.pruneDone
+	sm.pruneStop, sm.pruneDone = nil, nil
+	sm.pruneMu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// pruneLoop 周期执行快照清理。
+func (sm *Manager[ID]) pruneLoop(ctx context.Context, ticker clock.ITicker, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			if err := sm.PruneSnapshots(ctx); err != nil {
+				snapshotLogger().Error(ctx, "prune snapshots failed", logging.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package snapshot
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gochen/clock"
+	"gochen/errors"
+)
+
+// historyStore 在 MemoryStore 之上模拟支持多版本快照裁剪的存储。
+type historyStore struct {
+	*MemoryStore[int64]
+	keepLast atomic.Int64
+	calls    atomic.Int64
+}
+
+func (s *historyStore) PruneSnapshotHistory(_ context.Context, keepLast int) (int64, error) {
+	s.keepLast.Store(int64(keepLast))
+	s.calls.Add(1)
+	return 3, nil
+}
+
+// TestManager_PruneSnapshots 验证按保留期与 KeepLast 清理快照。
+func TestManager_PruneSnapshots(t *testing.T) {
+	ctx := context.Background()
+	store := &historyStore{MemoryStore: NewMemoryStore[int64]()}
+	mgr := NewManager[int64](store, &Config{Enabled: true, RetentionPeriod: time.Hour, KeepLast: 2})
+
+	if err := store.SaveSnapshot(ctx, Snapshot[int64]{AggregateID: 1, AggregateType: "Order", Version: 1, Timestamp: time.Now().Add(-2 * time.Hour)}); err != nil {
+		t.Fatalf("save snapshot failed: %v", err)
+	}
+	if err := store.SaveSnapshot(ctx, Snapshot[int64]{AggregateID: 2, AggregateType: "Order", Version: 1, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("save snapshot failed: %v", err)
+	}
+
+	if err := mgr.PruneSnapshots(ctx); err != nil {
+		t.Fatalf("prune snapshots failed: %v", err)
+	}
+	if _, err := store.FindSnapshot(ctx, "Order", 1); !errors.Is(err, errors.NotFound) {
+		t.Fatalf("expected expired snapshot to be pruned, got %v", err)
+	}
+	if _, err := store.FindSnapshot(ctx, "Order", 2); err != nil {
+		t.Fatalf("expected recent snapshot to be kept: %v", err)
+	}
+	if got := store.keepLast.Load(); got != 2 {
+		t.Fatalf("expected keepLast=2 passed to pruner, got %d", got)
+	}
+}
+
+// TestManager_PruneWorker 验证后台清理协程的启动、重复启动与停止。
+func TestManager_PruneWorker(t *testing.T) {
+	store := &historyStore{MemoryStore: NewMemoryStore[int64]()}
+
+	if err := NewManager[int64](store, &Config{Enabled: true}).StartPruneWorker(context.Background()); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected InvalidInput without prune interval, got %v", err)
+	}
+
+	mgr := NewManager[int64](store, &Config{Enabled: true, KeepLast: 1, PruneInterval: 5 * time.Millisecond})
+	if err := mgr.StartPruneWorker(context.Background()); err != nil {
+		t.Fatalf("start prune worker failed: %v", err)
+	}
+	defer mgr.StopPruneWorker()
+	if err := mgr.StartPruneWorker(context.Background()); !errors.Is(err, errors.Conflict) {
+		t.Fatalf("expected Conflict on second start, got %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for store.calls.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("prune worker did not run")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	mgr.StopPruneWorker()
+	mgr.StopPruneWorker()
+}
+
+// TestManager_PruneWorkerUsesInjectedClock 验证快照时间戳、保留期与后台清理周期均由注入的时钟驱动。
+func TestManager_PruneWorkerUsesInjectedClock(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewManualClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	store := &historyStore{MemoryStore: NewMemoryStore[int64]().WithClock(clk)}
+	mgr := NewManager[int64](store, &Config{Enabled: true, RetentionPeriod: time.Hour, KeepLast: 1, PruneInterval: time.Minute})
+	mgr.SetClock(clk)
+
+	if err := mgr.CreateSnapshot(ctx, 1, "Order", map[string]any{"n": 1}, 1); err != nil {
+		t.Fatalf("create snapshot failed: %v", err)
+	}
+	snap, err := store.FindSnapshot(ctx, "Order", 1)
+	if err != nil {
+		t.Fatalf("find snapshot failed: %v", err)
+	}
+	if !snap.Timestamp.Equal(clk.Now()) {
+		t.Fatalf("expected snapshot timestamp from injected clock, got %v", snap.Timestamp)
+	}
+
+	if err := mgr.StartPruneWorker(ctx); err != nil {
+		t.Fatalf("start prune worker failed: %v", err)
+	}
+	defer mgr.StopPruneWorker()
+	clk.Advance(2 * time.Hour)
+
+	deadline := time.Now().Add(time.Second)
+	for store.calls.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("prune worker did not run after advancing the clock")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := store.FindSnapshot(ctx, "Order", 1); !errors.Is(err, errors.NotFound) {
+		t.Fatalf("expected snapshot older than retention to be pruned, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MetadataSchemaVersion 是快照 metadata 中记录快照数据 schema 版本的键。
+	MetadataSchemaVersion = "schema_version"
+	// MetadataSerializer 是快照 metadata 中记录序列化器名称的键。
+	MetadataSerializer = "serializer"
+)
+
+// ISerializer 定义快照数据的序列化方式。
+//
+// 需要配合 UpgraderRegistry 使用时，Unmarshal 必须支持解码到 *map[string]any。
+type ISerializer interface {
+	// Name 返回写入快照 metadata 的序列化器名称，用于恢复时校验。
+	Name() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONSerializer 是默认的 JSON 快照序列化器。
+type JSONSerializer struct{}
+
+// Name 返回 "json"。
+func (JSONSerializer) Name() string { return "json" }
+
+// Marshal 使用 encoding/json 序列化。
+func (JSONSerializer) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+// Unmarshal 使用 encoding/json 反序列化。
+func (JSONSerializer) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// Envelope 描述快照数据的编码信息：数据本身与其 schema 版本、序列化器。
+//
+// 编码信息保存在 Snapshot.Metadata 中，因此无需修改存储结构；
+// 未记录编码信息的历史快照视为 schema 版本 1、JSON 序列化。
+type Envelope struct {
+	SchemaVersion int
+	Serializer    string
+	Data          []byte
+}
+
+// EnvelopeOf 从快照中读取编码信息。
+func EnvelopeOf[ID comparable](snap *Snapshot[ID]) Envelope {
+	env := Envelope{SchemaVersion: 1, Serializer: JSONSerializer{}.Name()}
+	if snap == nil {
+		return env
+	}
+	env.Data = snap.Data
+	if v, ok := snap.Metadata[MetadataSchemaVersion]; ok {
+		if version := metadataInt(v); version > 0 {
+			env.SchemaVersion = version
+		}
+	}
+	if v, ok := snap.Metadata[MetadataSerializer].(string); ok && strings.TrimSpace(v) != "" {
+		env.Serializer = strings.TrimSpace(v)
+	}
+	return env
+}
+
+// metadataInt 兼容 metadata 经 JSON 往返后数字变为 float64/json.Number/字符串的情况。
+func metadataInt(v any) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	case json.Number:
+		i, _ := n.Int64()
+		return int(i)
+	case string:
+		i, _ := strconv.Atoi(n)
+		return i
+	default:
+		i, _ := strconv.Atoi(fmt.Sprint(v))
+		return i
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"gochen/clock"
 	"gochen/errors"
 	"gochen/logging"
 )
@@ -38,12 +39,21 @@ type ISnapshotStore[ID comparable] interface {
 // MemoryStore 使用内存 map 保存快照，适合测试和轻量演示场景。
 type MemoryStore[ID comparable] struct {
 	snapshots map[string]Snapshot[ID]
+	clock     clock.IClock
 	mutex     sync.RWMutex
 }
 
 // NewMemoryStore 创建一个空的内存快照存储。
 func NewMemoryStore[ID comparable]() *MemoryStore[ID] {
-	return &MemoryStore[ID]{snapshots: make(map[string]Snapshot[ID])}
+	return &MemoryStore[ID]{snapshots: make(map[string]Snapshot[ID]), clock: clock.NewRealClock()}
+}
+
+// WithClock 替换计算保留期截止时间使用的时钟；nil 时忽略。
+func (s *MemoryStore[ID]) WithClock(clk clock.IClock) *MemoryStore[ID] {
+	if clk != nil {
+		s.clock = clk
+	}
+	return s
 }
 
 // snapshotKey 生成快照在内存 map 中使用的复合键。
@@ -122,7 +132,7 @@ func (s *MemoryStore[ID]) CleanupSnapshots(ctx context.Context, retentionPeriod
 	}
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
-	cutoff := time.Now().Add(-retentionPeriod)
+	cutoff := s.clock.Now().Add(-retentionPeriod)
 	deleted := 0
 	for k, v := range s.snapshots {
 		if v.Timestamp.Before(cutoff) {
@@ -8,8 +8,10 @@ import (
 	"strings"
 	"time"
 
+	"gochen/clock"
 	"gochen/codec"
 	"gochen/codec/idcodec"
+	"gochen/contextx"
 	"gochen/db"
 	"gochen/db/dialect"
 	"gochen/db/sql/safeident"
@@ -28,6 +30,10 @@ type SQLStore[ID comparable] struct {
 	tableName string
 	codec     codec.ICodec[ID, any]
 	tableErr  error
+	clock     clock.IClock
+
+	// tenantColumn 非空时按 ctx 租户读写快照，见 WithTenantColumn。
+	tenantColumn string
 }
 
 // NewSQLStore 为 `int64` 聚合 ID 创建一个 SQL 快照存储。
@@ -38,7 +44,41 @@ func NewSQLStore(db db.IDatabase, tableName string) *SQLStore[int64] {
 // NewSQLStoreWithCodec 创建可自定义聚合 ID 编解码方式的 SQL 快照存储。
 func NewSQLStoreWithCodec[ID comparable](db db.IDatabase, tableName string, idCodec codec.ICodec[ID, any]) *SQLStore[ID] {
 	normalizedTableName, err := normalizeSnapshotSQLTableName(db, tableName, "event_snapshots")
-	return &SQLStore[ID]{db: db, tableName: normalizedTableName, codec: idCodec, tableErr: err}
+	return &SQLStore[ID]{db: db, tableName: normalizedTableName, codec: idCodec, tableErr: err, clock: clock.NewRealClock()}
+}
+
+// WithClock 替换补全快照时间戳与计算保留期截止时间使用的时钟；nil 时忽略。
+func (s *SQLStore[ID]) WithClock(clk clock.IClock) *SQLStore[ID] {
+	if clk != nil {
+		s.clock = clk
+	}
+	return s
+}
+
+// WithTenantColumn 启用租户列（通常为 "tenant_id"），与事件存储的租户列配合使用。
+//
+// 启用后保存快照会写入 ctx 中的租户 ID，查询/删除/列举按 ctx 租户追加过滤条件；
+// ctx 无租户时不过滤。CleanupSnapshots 属于运维操作，始终跨租户执行。列名非法时后续调用返回 InvalidInput。
+func (s *SQLStore[ID]) WithTenantColumn(column string) *SQLStore[ID] {
+	column = strings.TrimSpace(column)
+	if column != "" && !safeident.IsSafeIdentifier(column) {
+		s.tableErr = gerrors.NewCode(gerrors.InvalidInput, fmt.Sprintf("invalid SQL tenant column: %s", column))
+		return s
+	}
+	s.tenantColumn = column
+	return s
+}
+
+// tenantFilter 返回按 ctx 租户过滤的 SQL 片段与参数。
+func (s *SQLStore[ID]) tenantFilter(ctx context.Context) (string, []any) {
+	if s.tenantColumn == "" {
+		return "", nil
+	}
+	tenantID := strings.TrimSpace(contextx.TenantID(ctx))
+	if tenantID == "" {
+		return "", nil
+	}
+	return " AND " + s.tenantColumn + " = ?", []any{tenantID}
 }
 
 // SaveSnapshot 保存或覆盖指定聚合的最新快照。
@@ -56,7 +96,7 @@ func (s *SQLStore[ID]) SaveSnapshot(ctx context.Context, snapshot Snapshot[ID])
 	// 确保时间有效
 	ts := snapshot.Timestamp
 	if ts.IsZero() {
-		ts = time.Now()
+		ts = s.clock.Now()
 	}
 
 	// 元数据序列化为 JSON（可为空）
@@ -71,21 +111,23 @@ func (s *SQLStore[ID]) SaveSnapshot(ctx context.Context, snapshot Snapshot[ID])
 	}
 
 	// 先尝试 UPDATE，存在则更新
+	tenantClause, tenantArgs := s.tenantFilter(ctx)
 	updateSQL := fmt.Sprintf(`UPDATE %s
 		SET version = ?, data = ?, timestamp = ?, metadata = ?
-		WHERE aggregate_type = ? AND aggregate_id = ?`, s.tableName)
+		WHERE aggregate_type = ? AND aggregate_id = ?%s`, s.tableName, tenantClause)
 	agg, err := s.codec.Encode(snapshot.AggregateID)
 	if err != nil {
 		return gerrors.Wrap(err, gerrors.InvalidInput, "invalid aggregate id")
 	}
-	res, err := s.db.Exec(ctx, updateSQL,
+	updateArgs := append([]any{
 		snapshot.Version,
 		snapshot.Data,
 		ts,
 		metaJSON,
 		snapshot.AggregateType,
 		agg,
-	)
+	}, tenantArgs...)
+	res, err := s.db.Exec(ctx, updateSQL, updateArgs...)
 	if err != nil {
 		return gerrors.Wrap(err, gerrors.Database, "update snapshot failed").
 			WithContext("aggregate_type", snapshot.AggregateType).
@@ -101,17 +143,25 @@ func (s *SQLStore[ID]) SaveSnapshot(ctx context.Context, snapshot Snapshot[ID])
 	}
 
 	// 不存在则 INSERT
-	insertSQL := fmt.Sprintf(`INSERT INTO %s
-		(aggregate_type, aggregate_id, version, data, timestamp, metadata)
-		VALUES (?, ?, ?, ?, ?, ?)`, s.tableName)
-	if _, err := s.db.Exec(ctx, insertSQL,
+	columns := "aggregate_type, aggregate_id, version, data, timestamp, metadata"
+	values := "?, ?, ?, ?, ?, ?"
+	insertArgs := []any{
 		snapshot.AggregateType,
 		agg,
 		snapshot.Version,
 		snapshot.Data,
 		ts,
 		metaJSON,
-	); err != nil {
+	}
+	if s.tenantColumn != "" {
+		columns += ", " + s.tenantColumn
+		values += ", ?"
+		insertArgs = append(insertArgs, strings.TrimSpace(contextx.TenantID(ctx)))
+	}
+	insertSQL := fmt.Sprintf(`INSERT INTO %s
+		(%s)
+		VALUES (%s)`, s.tableName, columns, values)
+	if _, err := s.db.Exec(ctx, insertSQL, insertArgs...); err != nil {
 		return gerrors.Wrap(err, gerrors.Database, "insert snapshot failed").
 			WithContext("aggregate_type", snapshot.AggregateType).
 			WithContext("aggregate_id", snapshot.AggregateID)
@@ -137,16 +187,17 @@ func (s *SQLStore[ID]) FindSnapshot(ctx context.Context, aggregateType string, a
 		return nil, s.tableErr
 	}
 
+	tenantClause, tenantArgs := s.tenantFilter(ctx)
 	query := fmt.Sprintf(`SELECT aggregate_id, aggregate_type, version, data, timestamp, metadata
 		FROM %s
-		WHERE aggregate_type = ? AND aggregate_id = ?`, s.tableName)
+		WHERE aggregate_type = ? AND aggregate_id = ?%s`, s.tableName, tenantClause)
 
 	agg, err := s.codec.Encode(aggregateID)
 	if err != nil {
 		return nil, gerrors.Wrap(err, gerrors.InvalidInput, "invalid aggregate id")
 	}
 
-	row := s.db.QueryRow(ctx, query, aggregateType, agg)
+	row := s.db.QueryRow(ctx, query, append([]any{aggregateType, agg}, tenantArgs...)...)
 	var snap Snapshot[ID]
 	var rawAggID any
 	var versionInt int64
@@ -203,13 +254,14 @@ func (s *SQLStore[ID]) DeleteSnapshot(ctx context.Context, aggregateType string,
 		return s.tableErr
 	}
 
-	query := fmt.Sprintf(`DELETE FROM %s WHERE aggregate_type = ? AND aggregate_id = ?`, s.tableName)
+	tenantClause, tenantArgs := s.tenantFilter(ctx)
+	query := fmt.Sprintf(`DELETE FROM %s WHERE aggregate_type = ? AND aggregate_id = ?%s`, s.tableName, tenantClause)
 	agg, err := s.codec.Encode(aggregateID)
 	if err != nil {
 		return gerrors.Wrap(err, gerrors.InvalidInput, "invalid aggregate id")
 	}
 
-	if _, err := s.db.Exec(ctx, query, aggregateType, agg); err != nil {
+	if _, err := s.db.Exec(ctx, query, append([]any{aggregateType, agg}, tenantArgs...)...); err != nil {
 		return gerrors.Wrap(err, gerrors.Database, "delete snapshot failed").
 			WithContext("aggregate_type", aggregateType).
 			WithContext("aggregate_id", aggregateID)
@@ -238,13 +290,17 @@ func (s *SQLStore[ID]) ListSnapshots(ctx context.Context, aggregateType string,
 		FROM %s`, s.tableName)
 	var (
 		args  []any
-		where string
+		where = " WHERE 1=1"
 		lim   string
 	)
 	if aggregateType != "" {
-		where = " WHERE aggregate_type = ?"
+		where += " AND aggregate_type = ?"
 		args = append(args, aggregateType)
 	}
+	if tenantClause, tenantArgs := s.tenantFilter(ctx); tenantClause != "" {
+		where += tenantClause
+		args = append(args, tenantArgs...)
+	}
 	if limit > 0 {
 		lim = " LIMIT ?"
 		args = append(args, limit)
@@ -314,7 +370,7 @@ func (s *SQLStore[ID]) CleanupSnapshots(ctx context.Context, retentionPeriod tim
 		return nil
 	}
 
-	cutoff := time.Now().Add(-retentionPeriod)
+	cutoff := s.clock.Now().Add(-retentionPeriod)
 	query := fmt.Sprintf(`DELETE FROM %s WHERE timestamp < ?`, s.tableName)
 	res, err := s.db.Exec(ctx, query, cutoff)
 	if err != nil {
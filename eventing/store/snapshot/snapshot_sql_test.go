@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 	_ "modernc.org/sqlite"
 
+	"gochen/contextx"
 	"gochen/db"
 	"gochen/db/sql/stdsql"
 	"gochen/errors"
@@ -27,3 +28,50 @@ func TestSQLStore_RejectsUnsafeTableName(t *testing.T) {
 	require.Error(t, err)
 	require.True(t, errors.Is(err, errors.InvalidInput))
 }
+
+// TestSQLStore_TenantColumn_ScopesSnapshots 验证启用租户列后快照按 ctx 租户隔离。
+func TestSQLStore_TenantColumn_ScopesSnapshots(t *testing.T) {
+	database, err := stdsql.New(db.DBConfig{Driver: "sqlite", Database: ":memory:"})
+	require.NoError(t, err)
+	defer database.Close()
+	_, err = database.Exec(context.Background(), `CREATE TABLE event_snapshots (
+		aggregate_type TEXT NOT NULL,
+		aggregate_id INTEGER NOT NULL,
+		version INTEGER NOT NULL,
+		data BLOB NOT NULL,
+		timestamp DATETIME NOT NULL,
+		metadata TEXT NULL,
+		tenant_id TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (aggregate_type, aggregate_id)
+	)`)
+	require.NoError(t, err)
+
+	store := NewSQLStore(database, "").WithTenantColumn("tenant_id")
+	acme, err := contextx.WithTenantID(context.Background(), "acme")
+	require.NoError(t, err)
+	globex, err := contextx.WithTenantID(context.Background(), "globex")
+	require.NoError(t, err)
+
+	snap := Snapshot[int64]{AggregateID: 1, AggregateType: "counter", Version: 3, Data: []byte(`{}`)}
+	require.NoError(t, store.SaveSnapshot(acme, snap))
+
+	found, err := store.FindSnapshot(acme, "counter", 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), found.Version)
+
+	_, err = store.FindSnapshot(globex, "counter", 1)
+	require.True(t, errors.Is(err, errors.NotFound))
+
+	listed, err := store.ListSnapshots(globex, "", 0)
+	require.NoError(t, err)
+	require.Empty(t, listed)
+
+	require.NoError(t, store.DeleteSnapshot(globex, "counter", 1))
+	listed, err = store.ListSnapshots(context.Background(), "counter", 0)
+	require.NoError(t, err)
+	require.Len(t, listed, 1, "deletes from another tenant do not apply")
+
+	bad := NewSQLStore(database, "").WithTenantColumn("tenant id")
+	_, err = bad.FindSnapshot(acme, "counter", 1)
+	require.True(t, errors.Is(err, errors.InvalidInput))
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"sync"
 	"time"
+
+	"gochen/clock"
 )
 
 // ISnapshotAggregate 快照聚合接口。
@@ -55,6 +57,7 @@ type TimeDurationStrategy[ID comparable] struct {
 	Duration          time.Duration
 	lastSnapshotTimes map[string]time.Time // 聚合键 -> 上次快照时间
 	snapshotStore     ISnapshotStore[ID]
+	clock             clock.IClock
 	mutex             sync.RWMutex
 }
 
@@ -67,7 +70,16 @@ func NewTimeDurationStrategy[ID comparable](duration time.Duration, snapshotStor
 		Duration:          duration,
 		lastSnapshotTimes: make(map[string]time.Time),
 		snapshotStore:     snapshotStore,
+		clock:             clock.NewRealClock(),
+	}
+}
+
+// WithClock 替换判断时间间隔使用的时钟；nil 时忽略。
+func (s *TimeDurationStrategy[ID]) WithClock(clk clock.IClock) *TimeDurationStrategy[ID] {
+	if clk != nil {
+		s.clock = clk
 	}
+	return s
 }
 
 // ShouldCreateSnapshot 在距离上次快照超过阈值时返回 true。
@@ -91,7 +103,7 @@ func (s *TimeDurationStrategy[ID]) ShouldCreateSnapshot(ctx context.Context, agg
 	}
 
 	// 检查是否超过时间间隔
-	return time.Since(lastTime) >= s.Duration, nil
+	return s.clock.Now().Sub(lastTime) >= s.Duration, nil
 }
 
 func (s *TimeDurationStrategy[ID]) Name() string {
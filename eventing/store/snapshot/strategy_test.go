@@ -3,6 +3,9 @@ package snapshot
 import (
 	"context"
 	"testing"
+	"time"
+
+	"gochen/clock"
 )
 
 type mockAggregate struct {
@@ -45,3 +48,23 @@ func TestAggregateSizeStrategy_SizeEstimator(t *testing.T) {
 		t.Fatalf("expected snapshot due to size")
 	}
 }
+
+// TestTimeDurationStrategy_UsesInjectedClock 验证时间间隔策略按注入的时钟判断，而非等待真实时间。
+func TestTimeDurationStrategy_UsesInjectedClock(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewManualClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	store := NewMemoryStore[int64]()
+	strategy := NewTimeDurationStrategy[int64](time.Hour, store).WithClock(clk)
+	agg := mockAggregate{id: 1, version: 3, aggregateType: "Test"}
+
+	if err := store.SaveSnapshot(ctx, Snapshot[int64]{AggregateID: 1, AggregateType: "Test", Version: 1, Timestamp: clk.Now()}); err != nil {
+		t.Fatalf("save snapshot failed: %v", err)
+	}
+	if should, err := strategy.ShouldCreateSnapshot(ctx, agg); err != nil || should {
+		t.Fatalf("expected no snapshot right after the last one, got should=%v err=%v", should, err)
+	}
+	clk.Advance(time.Hour)
+	if should, err := strategy.ShouldCreateSnapshot(ctx, agg); err != nil || !should {
+		t.Fatalf("expected snapshot once the interval elapsed, got should=%v err=%v", should, err)
+	}
+}
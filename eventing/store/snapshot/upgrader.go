@@ -0,0 +1,123 @@
+package snapshot
+
+import (
+	"sort"
+	"sync"
+
+	"gochen/errors"
+)
+
+// ISnapshotUpgrader 定义快照数据 schema 升级器，语义与 upcast.IEventUpgrader 一致。
+type ISnapshotUpgrader interface {
+	// FromVersion 源 schema 版本
+	FromVersion() int
+	// ToVersion 目标 schema 版本
+	ToVersion() int
+	// Upgrade 将旧版本快照数据升级为新版本
+	Upgrade(data map[string]any) (map[string]any, error)
+}
+
+// UpgraderRegistry 按聚合类型注册快照升级器。
+//
+// 聚合类型的当前 schema 版本取已注册升级器的最大 ToVersion（未注册时为 1），
+// Manager 以该版本写入新快照，并在恢复时把旧版本快照逐级升级到该版本。
+type UpgraderRegistry struct {
+	mutex     sync.RWMutex
+	upgraders map[string][]ISnapshotUpgrader
+}
+
+// NewUpgraderRegistry 创建快照升级器注册表。
+func NewUpgraderRegistry() *UpgraderRegistry {
+	return &UpgraderRegistry{upgraders: make(map[string][]ISnapshotUpgrader)}
+}
+
+// Register 为聚合类型注册快照升级器。
+func (r *UpgraderRegistry) Register(aggregateType string, upgrader ISnapshotUpgrader) error {
+	if aggregateType == "" {
+		return errors.NewCode(errors.InvalidInput, "aggregate type cannot be empty")
+	}
+	if upgrader == nil {
+		return errors.NewCode(errors.InvalidInput, "snapshot upgrader cannot be nil").WithContext("aggregate_type", aggregateType)
+	}
+	if upgrader.FromVersion() <= 0 || upgrader.ToVersion() <= 0 {
+		return errors.NewCode(errors.InvalidInput, "snapshot upgrader versions must be greater than 0").WithContext("aggregate_type", aggregateType)
+	}
+	if upgrader.ToVersion() <= upgrader.FromVersion() {
+		return errors.NewCode(errors.InvalidInput, "snapshot upgrader to-version must be greater than from-version").WithContext("aggregate_type", aggregateType)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	list := r.upgraders[aggregateType]
+	for _, existing := range list {
+		if existing.FromVersion() == upgrader.FromVersion() {
+			return errors.NewCode(errors.Conflict, "snapshot upgrader already registered").
+				WithContext("aggregate_type", aggregateType).
+				WithContext("from_version", upgrader.FromVersion())
+		}
+	}
+	list = append(list, upgrader)
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].FromVersion() < list[j].FromVersion()
+	})
+	r.upgraders[aggregateType] = list
+	return nil
+}
+
+// SchemaVersion 返回聚合类型当前的快照 schema 版本。
+func (r *UpgraderRegistry) SchemaVersion(aggregateType string) int {
+	if r == nil {
+		return 1
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	version := 1
+	for _, u := range r.upgraders[aggregateType] {
+		if u.ToVersion() > version {
+			version = u.ToVersion()
+		}
+	}
+	return version
+}
+
+// Upgrade 把 currentVersion 的快照数据逐级升级到当前 schema 版本，返回升级后的数据与版本。
+func (r *UpgraderRegistry) Upgrade(aggregateType string, currentVersion int, data map[string]any) (map[string]any, int, error) {
+	if currentVersion <= 0 {
+		currentVersion = 1
+	}
+	target := r.SchemaVersion(aggregateType)
+	if currentVersion >= target {
+		return data, currentVersion, nil
+	}
+
+	r.mutex.RLock()
+	list := append([]ISnapshotUpgrader(nil), r.upgraders[aggregateType]...)
+	r.mutex.RUnlock()
+
+	result, version := data, currentVersion
+	for version < target {
+		var next ISnapshotUpgrader
+		for _, u := range list {
+			if u.FromVersion() == version {
+				next = u
+				break
+			}
+		}
+		if next == nil {
+			return nil, version, errors.NewCode(errors.NotFound, "missing snapshot upgrader").
+				WithContext("aggregate_type", aggregateType).
+				WithContext("from_version", version).
+				WithContext("target_version", target)
+		}
+		upgraded, err := next.Upgrade(result)
+		if err != nil {
+			return nil, version, errors.Wrap(err, errors.Internal, "upgrade snapshot failed").
+				WithContext("aggregate_type", aggregateType).
+				WithContext("from_version", next.FromVersion()).
+				WithContext("to_version", next.ToVersion())
+		}
+		result, version = upgraded, next.ToVersion()
+	}
+	return result, version, nil
+}
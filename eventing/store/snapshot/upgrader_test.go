@@ -0,0 +1,102 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gochen/errors"
+)
+
+type counterStateV2 struct {
+	ID            int64  `json:"id"`
+	Version       uint64 `json:"version"`
+	AggregateType string `json:"aggregate_type"`
+	Total         int    `json:"total"`
+}
+
+func (s *counterStateV2) GetAggregateType() string { return s.AggregateType }
+
+// counterV1ToV2 把 v1 的 `count` 字段迁移为 v2 的 `total`。
+type counterV1ToV2 struct{}
+
+func (counterV1ToV2) FromVersion() int { return 1 }
+func (counterV1ToV2) ToVersion() int   { return 2 }
+func (counterV1ToV2) Upgrade(data map[string]any) (map[string]any, error) {
+	out := make(map[string]any, len(data))
+	for k, v := range data {
+		out[k] = v
+	}
+	out["total"] = out["count"]
+	delete(out, "count")
+	return out, nil
+}
+
+// TestManager_LoadSnapshotUpgradesLegacySchema 验证未记录 schema 版本的旧快照按升级链恢复。
+func TestManager_LoadSnapshotUpgradesLegacySchema(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore[int64]()
+	legacy, _ := json.Marshal(map[string]any{"id": 1, "version": 3, "aggregate_type": "Counter", "count": 7})
+	if err := store.SaveSnapshot(ctx, Snapshot[int64]{AggregateID: 1, AggregateType: "Counter", Version: 3, Data: legacy, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("save snapshot failed: %v", err)
+	}
+
+	upgraders := NewUpgraderRegistry()
+	if err := upgraders.Register("Counter", counterV1ToV2{}); err != nil {
+		t.Fatalf("register upgrader failed: %v", err)
+	}
+	if err := upgraders.Register("Counter", counterV1ToV2{}); !errors.Is(err, errors.Conflict) {
+		t.Fatalf("expected Conflict on duplicate upgrader, got %v", err)
+	}
+	mgr := NewManager[int64](store, &Config{Enabled: true, Frequency: 1})
+	mgr.SetUpgraders(upgraders)
+
+	target := &counterStateV2{ID: 1, AggregateType: "Counter"}
+	snap, err := mgr.LoadSnapshot(ctx, 1, target)
+	if err != nil {
+		t.Fatalf("load snapshot failed: %v", err)
+	}
+	if target.Total != 7 {
+		t.Fatalf("expected upgraded total=7, got %d", target.Total)
+	}
+	if got := EnvelopeOf(snap).SchemaVersion; got != 2 {
+		t.Fatalf("expected returned snapshot schema version 2, got %d", got)
+	}
+
+	// 新快照按当前 schema 版本写入。
+	if err := mgr.CreateSnapshot(ctx, 1, "Counter", target, 4); err != nil {
+		t.Fatalf("create snapshot failed: %v", err)
+	}
+	stored, err := store.FindSnapshot(ctx, "Counter", 1)
+	if err != nil {
+		t.Fatalf("find snapshot failed: %v", err)
+	}
+	if env := EnvelopeOf(stored); env.SchemaVersion != 2 || env.Serializer != "json" {
+		t.Fatalf("unexpected envelope: %+v", env)
+	}
+}
+
+type prefixedSerializer struct{ JSONSerializer }
+
+func (prefixedSerializer) Name() string { return "prefixed-json" }
+
+// TestManager_SerializerMismatch 验证序列化器不一致时拒绝恢复。
+func TestManager_SerializerMismatch(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore[int64]()
+	writer := NewManager[int64](store, &Config{Enabled: true})
+	writer.SetSerializer(prefixedSerializer{})
+	agg := &testSnapshotAggregate{ID: 1, Version: 1, AggregateType: "test"}
+	if err := writer.CreateSnapshot(ctx, 1, "test", agg, 1); err != nil {
+		t.Fatalf("create snapshot failed: %v", err)
+	}
+
+	if _, err := writer.LoadSnapshot(ctx, 1, &testSnapshotAggregate{AggregateType: "test"}); err != nil {
+		t.Fatalf("load with same serializer failed: %v", err)
+	}
+	reader := NewManager[int64](store, &Config{Enabled: true})
+	if _, err := reader.LoadSnapshot(ctx, 1, &testSnapshotAggregate{AggregateType: "test"}); !errors.Is(err, errors.Unsupported) {
+		t.Fatalf("expected Unsupported on serializer mismatch, got %v", err)
+	}
+}
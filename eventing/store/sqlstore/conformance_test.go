@@ -0,0 +1,15 @@
+package sqlstore
+
+import (
+	"testing"
+
+	estore "gochen/eventing/store"
+	"gochen/eventing/store/storetest"
+)
+
+// TestSQLEventStore_Conformance 验证 SQLEventStore 满足事件存储一致性契约。
+func TestSQLEventStore_Conformance(t *testing.T) {
+	storetest.RunConformanceTests(t, func(t *testing.T) estore.IEventStreamStore[int64] {
+		return newTestStore(t, setupTestDB(t), "event_store")
+	})
+}
@@ -0,0 +1,217 @@
+package sqlstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"gochen/db"
+	"gochen/db/dialect"
+	"gochen/errors"
+)
+
+// maxPartitionIdentifierLength 是分区表名/schema 名的最大长度（取 PostgreSQL 的 63 字节上限）。
+const maxPartitionIdentifierLength = 63
+
+// partitionReadableLength 是分区名中可读租户片段的最大长度。
+const partitionReadableLength = 24
+
+// Partition 描述一个租户的事件存储分区。
+type Partition struct {
+	// TenantID 是分区所属租户。
+	TenantID string
+	// Schema 是分区所在 schema（MySQL 为 database）；表级分区时为空。
+	Schema string
+	// Table 是事件表名；Schema 非空时为 "<schema>.<table>" 形式的限定名。
+	Table string
+}
+
+// IPartitionStrategy 把租户映射到独立的事件存储分区。
+//
+// 实现必须是确定性的纯函数：同一租户总是得到同一分区，不同租户不得映射到同一分区。
+type IPartitionStrategy interface {
+	Partition(tenantID string) (Partition, error)
+}
+
+// TablePerTenant 为每个租户使用独立事件表："<Prefix>_<tenant>_<hash>"（见 partitionSuffix）。
+type TablePerTenant struct {
+	// Prefix 为空时使用 "event_store"。
+	Prefix string
+}
+
+// Partition 返回租户对应的事件表。
+func (s TablePerTenant) Partition(tenantID string) (Partition, error) {
+	suffix, err := partitionSuffix(tenantID)
+	if err != nil {
+		return Partition{}, err
+	}
+	prefix := strings.TrimSpace(s.Prefix)
+	if prefix == "" {
+		prefix = "event_store"
+	}
+	table := prefix + "_" + suffix
+	if err := validatePartitionIdentifier(table); err != nil {
+		return Partition{}, err
+	}
+	return Partition{TenantID: tenantID, Table: table}, nil
+}
+
+// SchemaPerTenant 为每个租户使用独立 schema（MySQL 为 database）："<SchemaPrefix><tenant>_<hash>.<Table>"。
+//
+// SQLite 不支持按连接之外的 schema 建表，懒加载建表会返回 Unsupported。
+type SchemaPerTenant struct {
+	// SchemaPrefix 为空时使用 "tenant_"。
+	SchemaPrefix string
+	// Table 为空时使用 "event_store"。
+	Table string
+}
+
+// Partition 返回租户对应的 schema 与事件表。
+func (s SchemaPerTenant) Partition(tenantID string) (Partition, error) {
+	suffix, err := partitionSuffix(tenantID)
+	if err != nil {
+		return Partition{}, err
+	}
+	prefix := strings.TrimSpace(s.SchemaPrefix)
+	if prefix == "" {
+		prefix = "tenant_"
+	}
+	table := strings.TrimSpace(s.Table)
+	if table == "" {
+		table = "event_store"
+	}
+	schema := prefix + suffix
+	if err := validatePartitionIdentifier(schema); err != nil {
+		return Partition{}, err
+	}
+	if err := validatePartitionIdentifier(table); err != nil {
+		return Partition{}, err
+	}
+	return Partition{TenantID: tenantID, Schema: schema, Table: schema + "." + table}, nil
+}
+
+// partitionSuffix 把租户 ID 映射为标识符片段："<可读片段>_<哈希>"。
+//
+// 可读片段取小写后的字母、数字（其他字符替换为下划线，最长 partitionReadableLength）便于排查；
+// 哈希取原始租户 ID 的 SHA-256 前 16 位十六进制，区分大小写与被替换的字符，不同租户不会映射到同一分区。
+func partitionSuffix(tenantID string) (string, error) {
+	tenantID = strings.TrimSpace(tenantID)
+	if tenantID == "" {
+		return "", errors.NewCode(errors.InvalidInput, "tenant ID is required for partitioned event store")
+	}
+	sum := sha256.Sum256([]byte(tenantID))
+	hash := hex.EncodeToString(sum[:8])
+
+	readable := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '_'
+		}
+	}, tenantID)
+	readable = strings.Trim(readable, "_")
+	if len(readable) > partitionReadableLength {
+		readable = readable[:partitionReadableLength]
+	}
+	if readable == "" {
+		return hash, nil
+	}
+	return readable + "_" + hash, nil
+}
+
+func validatePartitionIdentifier(name string) error {
+	if !tableNamePattern.MatchString(name) || strings.Contains(name, ".") {
+		return errors.NewCode(errors.InvalidInput, fmt.Sprintf("invalid partition identifier: %s", name))
+	}
+	if len(name) > maxPartitionIdentifierLength {
+		return errors.NewCode(errors.InvalidInput, fmt.Sprintf("partition identifier too long: %s", name))
+	}
+	return nil
+}
+
+// bootstrapPartition 幂等创建分区所需的 schema 与事件表。
+func bootstrapPartition(ctx context.Context, database db.IDatabase, p Partition, aggregateIDType string) error {
+	d := dialect.FromDatabase(database)
+	if p.Schema != "" {
+		var query string
+		switch d.Name() {
+		case dialect.NameSQLite:
+			return errors.NewCode(errors.Unsupported, "schema-per-tenant partitioning is not supported on sqlite").
+				WithContext("schema", p.Schema)
+		case dialect.NamePostgres:
+			query = "CREATE SCHEMA IF NOT EXISTS " + p.Schema
+		default:
+			query = "CREATE DATABASE IF NOT EXISTS " + p.Schema
+		}
+		if _, err := database.Exec(ctx, query); err != nil {
+			return errors.Wrap(err, errors.Dependency, "create partition schema failed").
+				WithContext("schema", p.Schema).
+				WithContext("tenant_id", p.TenantID)
+		}
+	}
+	if _, err := database.Exec(ctx, eventTableDDL(d.Name(), p.Table, aggregateIDType)); err != nil {
+		return errors.Wrap(err, errors.Dependency, "create partition event table failed").
+			WithContext("table", p.Table).
+			WithContext("tenant_id", p.TenantID)
+	}
+	return nil
+}
+
+// eventTableDDL 返回与 SQLEventStore 读写列一致的建表语句。
+func eventTableDDL(name dialect.Name, table, aggregateIDType string) string {
+	switch name {
+	case dialect.NameSQLite:
+		if aggregateIDType == "" {
+			aggregateIDType = "INTEGER"
+		}
+		return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id TEXT PRIMARY KEY,
+			type TEXT NOT NULL,
+			aggregate_id %s NOT NULL,
+			aggregate_type TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			schema_version INTEGER NOT NULL,
+			timestamp DATETIME NOT NULL,
+			payload TEXT NOT NULL,
+			metadata TEXT NOT NULL,
+			UNIQUE(aggregate_id, aggregate_type, version)
+		)`, table, aggregateIDType)
+	case dialect.NamePostgres:
+		if aggregateIDType == "" {
+			aggregateIDType = "BIGINT"
+		}
+		return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id TEXT PRIMARY KEY,
+			type TEXT NOT NULL,
+			aggregate_id %s NOT NULL,
+			aggregate_type TEXT NOT NULL,
+			version BIGINT NOT NULL,
+			schema_version INTEGER NOT NULL,
+			timestamp TIMESTAMPTZ NOT NULL,
+			payload TEXT NOT NULL,
+			metadata TEXT NOT NULL,
+			UNIQUE (aggregate_id, aggregate_type, version)
+		)`, table, aggregateIDType)
+	default:
+		if aggregateIDType == "" {
+			aggregateIDType = "BIGINT"
+		}
+		return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id VARCHAR(255) PRIMARY KEY,
+			type VARCHAR(255) NOT NULL,
+			aggregate_id %s NOT NULL,
+			aggregate_type VARCHAR(255) NOT NULL,
+			version BIGINT NOT NULL,
+			schema_version INTEGER NOT NULL,
+			timestamp DATETIME(6) NOT NULL,
+			payload LONGTEXT NOT NULL,
+			metadata TEXT NOT NULL,
+			UNIQUE (aggregate_id, aggregate_type, version)
+		)`, table, aggregateIDType)
+	}
+}
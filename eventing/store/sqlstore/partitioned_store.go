@@ -0,0 +1,300 @@
+package sqlstore
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"gochen/codec"
+	"gochen/codec/idcodec"
+	"gochen/contextx"
+	"gochen/db"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/monitoring"
+	estore "gochen/eventing/store"
+)
+
+// PartitionConfig 定义按租户分区的事件存储配置。
+type PartitionConfig struct {
+	// Strategy 决定租户对应的分区（TablePerTenant / SchemaPerTenant / 自定义实现）。
+	Strategy IPartitionStrategy
+
+	// SkipBootstrap 为 true 时不在首次访问租户时自动建 schema/表，由业务迁移负责。
+	SkipBootstrap bool
+
+	// AggregateIDColumnType 覆盖自动建表时 aggregate_id 列的类型（例如 string ID 使用 "TEXT"）；
+	// 为空时按方言使用整型。
+	AggregateIDColumnType string
+}
+
+// PartitionedEventStore 是按租户物理隔离的 SQL 事件存储。
+//
+// 每个租户的事件写入独立的表或 schema，租户从 ctx（contextx 租户字段）解析；ctx 中没有租户时返回 InvalidInput，
+// 不存在跨租户读取。首次访问某租户时懒加载创建分区（可通过 SkipBootstrap 关闭），之后复用同一个 SQLEventStore。
+// 建分区的 DDL 始终在连接池上、业务事务之外执行，并且只串行化同一租户的首次访问。
+type PartitionedEventStore[ID comparable] struct {
+	db     db.IDatabase
+	codec  codec.ICodec[ID, any]
+	config PartitionConfig
+	opts   []SQLEventStoreOption
+
+	mu      sync.Mutex
+	stores  map[string]*SQLEventStore[ID]
+	pending map[string]*sync.Mutex
+	metrics monitoring.IEventStoreMetricsRecorder
+}
+
+// NewPartitionedEventStoreWithCodec 创建按租户分区的 SQL 事件存储。
+//
+// opts 作用于每个租户分区的 SQLEventStore。
+func NewPartitionedEventStoreWithCodec[ID comparable](database db.IDatabase, config PartitionConfig, idCodec codec.ICodec[ID, any], opts ...SQLEventStoreOption) (*PartitionedEventStore[ID], error) {
+	if database == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "NewPartitionedEventStoreWithCodec: db cannot be nil")
+	}
+	if config.Strategy == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "NewPartitionedEventStoreWithCodec: partition strategy cannot be nil")
+	}
+	if idCodec == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "NewPartitionedEventStoreWithCodec: codec cannot be nil")
+	}
+	config.AggregateIDColumnType = strings.TrimSpace(config.AggregateIDColumnType)
+	return &PartitionedEventStore[ID]{
+		db:      database,
+		codec:   idCodec,
+		config:  config,
+		opts:    opts,
+		stores:  make(map[string]*SQLEventStore[ID]),
+		pending: make(map[string]*sync.Mutex),
+	}, nil
+}
+
+// NewPartitionedEventStore 为 `int64` 聚合 ID 创建按租户分区的 SQL 事件存储。
+func NewPartitionedEventStore(database db.IDatabase, config PartitionConfig, opts ...SQLEventStoreOption) (*PartitionedEventStore[int64], error) {
+	return NewPartitionedEventStoreWithCodec[int64](database, config, idcodec.NewInt64[int64](), opts...)
+}
+
+// Init 通过一次 Ping 校验底层数据库是否可用。
+func (s *PartitionedEventStore[ID]) Init(ctx context.Context) error { return s.db.Ping(ctx) }
+
+// SetMetricsRecorder 设置事件存储指标记录器，对已创建与后续创建的分区统一生效。
+func (s *PartitionedEventStore[ID]) SetMetricsRecorder(rec monitoring.IEventStoreMetricsRecorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metrics = rec
+	for _, store := range s.stores {
+		store.SetMetricsRecorder(rec)
+	}
+}
+
+// Bootstrap 预热指定租户的分区（建 schema/表并缓存），适合在租户开通时调用。
+func (s *PartitionedEventStore[ID]) Bootstrap(ctx context.Context, tenantID string) error {
+	_, err := s.storeForTenant(ctx, tenantID)
+	return err
+}
+
+// ForTenant 返回指定租户分区对应的 SQLEventStore（必要时懒加载创建）。
+func (s *PartitionedEventStore[ID]) ForTenant(ctx context.Context, tenantID string) (*SQLEventStore[ID], error) {
+	return s.storeForTenant(ctx, tenantID)
+}
+
+// storeFor 根据 ctx 租户返回分区存储。
+func (s *PartitionedEventStore[ID]) storeFor(ctx context.Context) (*SQLEventStore[ID], error) {
+	tenantID, err := s.tenantFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.storeForTenant(ctx, tenantID)
+}
+
+func (s *PartitionedEventStore[ID]) tenantFrom(ctx context.Context) (string, error) {
+	tenantID := strings.TrimSpace(contextx.TenantID(ctx))
+	if tenantID == "" {
+		return "", errors.NewCode(errors.InvalidInput, "tenant ID is required for partitioned event store")
+	}
+	return tenantID, nil
+}
+
+// storeForTenant 返回租户分区存储，必要时在连接池上懒加载建表。
+//
+// s.mu 只保护缓存；DDL 在租户级锁下执行，不阻塞其他租户，也不占用调用方的事务。
+func (s *PartitionedEventStore[ID]) storeForTenant(ctx context.Context, tenantID string) (*SQLEventStore[ID], error) {
+	s.mu.Lock()
+	if store, ok := s.stores[tenantID]; ok {
+		s.mu.Unlock()
+		return store, nil
+	}
+	tenantMu, ok := s.pending[tenantID]
+	if !ok {
+		tenantMu = &sync.Mutex{}
+		s.pending[tenantID] = tenantMu
+	}
+	s.mu.Unlock()
+
+	tenantMu.Lock()
+	defer tenantMu.Unlock()
+	s.mu.Lock()
+	store, ok := s.stores[tenantID]
+	s.mu.Unlock()
+	if ok {
+		return store, nil
+	}
+
+	partition, err := s.config.Strategy.Partition(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.config.SkipBootstrap {
+		if err := bootstrapPartition(ctx, s.db, partition, s.config.AggregateIDColumnType); err != nil {
+			return nil, err
+		}
+	}
+	store, err = NewSQLEventStoreWithCodec[ID](s.db, partition.Table, s.codec, s.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.metrics != nil {
+		store.SetMetricsRecorder(s.metrics)
+	}
+	s.stores[tenantID] = store
+	delete(s.pending, tenantID)
+	return store, nil
+}
+
+// AppendEvents 向 ctx 租户的分区追加事件。
+func (s *PartitionedEventStore[ID]) AppendEvents(ctx context.Context, aggregateID ID, events []eventing.IStorableEvent[ID], expectedVersion uint64) error {
+	store, err := s.storeFor(ctx)
+	if err != nil {
+		return err
+	}
+	return store.AppendEvents(ctx, aggregateID, events, expectedVersion)
+}
+
+// AppendEventsWithDB 使用给定数据库句柄（通常为事务）向 ctx 租户的分区追加事件。
+//
+// 分区尚未创建时先在连接池上建表（不在 database 事务内执行 DDL）；单连接的数据库应在开启事务前调用 Bootstrap。
+func (s *PartitionedEventStore[ID]) AppendEventsWithDB(ctx context.Context, database db.IDatabase, aggregateID ID, events []eventing.IStorableEvent[ID], expectedVersion uint64) error {
+	store, err := s.storeFor(ctx)
+	if err != nil {
+		return err
+	}
+	return store.AppendEventsWithDB(ctx, database, aggregateID, events, expectedVersion)
+}
+
+// AppendEventsBatch 在 ctx 租户的分区内为多个聚合批量追加事件。
+func (s *PartitionedEventStore[ID]) AppendEventsBatch(ctx context.Context, batches []AggregateBatch[ID]) error {
+	store, err := s.storeFor(ctx)
+	if err != nil {
+		return err
+	}
+	return store.AppendEventsBatch(ctx, batches)
+}
+
+// LoadEvents 从 ctx 租户的分区加载聚合事件。
+func (s *PartitionedEventStore[ID]) LoadEvents(ctx context.Context, aggregateID ID, afterVersion uint64) ([]eventing.Event[ID], error) {
+	store, err := s.storeFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.LoadEvents(ctx, aggregateID, afterVersion)
+}
+
+// LoadEventsIter 从 ctx 租户的分区分页迭代聚合事件。
+func (s *PartitionedEventStore[ID]) LoadEventsIter(ctx context.Context, aggregateID ID, afterVersion uint64) (estore.IEventIterator[ID], error) {
+	store, err := s.storeFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.LoadEventsIter(ctx, aggregateID, afterVersion)
+}
+
+// LoadEventsByType 从 ctx 租户的分区按聚合类型加载事件。
+func (s *PartitionedEventStore[ID]) LoadEventsByType(ctx context.Context, aggregateType string, aggregateID ID, afterVersion uint64) ([]eventing.Event[ID], error) {
+	store, err := s.storeFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.LoadEventsByType(ctx, aggregateType, aggregateID, afterVersion)
+}
+
+// HasAggregate 判断 ctx 租户的分区中是否存在聚合。
+func (s *PartitionedEventStore[ID]) HasAggregate(ctx context.Context, aggregateID ID) (bool, error) {
+	store, err := s.storeFor(ctx)
+	if err != nil {
+		return false, err
+	}
+	return store.HasAggregate(ctx, aggregateID)
+}
+
+// GetAggregateVersion 查询 ctx 租户分区中聚合的当前版本。
+func (s *PartitionedEventStore[ID]) GetAggregateVersion(ctx context.Context, aggregateID ID) (uint64, error) {
+	store, err := s.storeFor(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return store.GetAggregateVersion(ctx, aggregateID)
+}
+
+// CountEvents 统计 ctx 租户分区中聚合的事件数量。
+func (s *PartitionedEventStore[ID]) CountEvents(ctx context.Context, aggregateID ID) (uint64, error) {
+	store, err := s.storeFor(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return store.CountEvents(ctx, aggregateID)
+}
+
+// HeadVersion 查询 ctx 租户分区中指定聚合类型下聚合的最新版本号。
+func (s *PartitionedEventStore[ID]) HeadVersion(ctx context.Context, aggregateType string, aggregateID ID) (uint64, error) {
+	store, err := s.storeFor(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return store.HeadVersion(ctx, aggregateType, aggregateID)
+}
+
+// StreamEvents 在 ctx 租户的分区内按游标遍历事件流。
+func (s *PartitionedEventStore[ID]) StreamEvents(ctx context.Context, opts *estore.StreamOptions) (*estore.StreamResult[ID], error) {
+	store, err := s.storeFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.StreamEvents(ctx, opts)
+}
+
+// PurgeEvents 在 ctx 租户的分区内删除已归档的事件。
+func (s *PartitionedEventStore[ID]) PurgeEvents(ctx context.Context, aggregateType string, aggregateID ID, throughVersion uint64) (int64, error) {
+	store, err := s.storeFor(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return store.PurgeEvents(ctx, aggregateType, aggregateID, throughVersion)
+}
+
+// DeleteAggregate 在 ctx 租户的分区内软删除或硬删除聚合事件流。
+func (s *PartitionedEventStore[ID]) DeleteAggregate(ctx context.Context, aggregateID ID, opts estore.DeleteOptions) error {
+	store, err := s.storeFor(ctx)
+	if err != nil {
+		return err
+	}
+	return store.DeleteAggregate(ctx, aggregateID, opts)
+}
+
+// StreamAggregate 在 ctx 租户的分区内按聚合顺序读取事件。
+func (s *PartitionedEventStore[ID]) StreamAggregate(ctx context.Context, opts *estore.AggregateStreamOptions[ID]) (*estore.AggregateStreamResult[ID], error) {
+	store, err := s.storeFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.StreamAggregate(ctx, opts)
+}
+
+// 编译期断言：PartitionedEventStore 满足事件存储与聚合流接口约束。
+var (
+	_ estore.IEventStore[int64]       = (*PartitionedEventStore[int64])(nil)
+	_ estore.IEventStreamStore[int64] = (*PartitionedEventStore[int64])(nil)
+	_ estore.IAggregateDeleter[int64] = (*PartitionedEventStore[int64])(nil)
+)
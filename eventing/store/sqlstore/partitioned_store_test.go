@@ -0,0 +1,99 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/eventing"
+	estore "gochen/eventing/store"
+)
+
+// TestPartitionedEventStore_TablePerTenant 验证按租户分表、懒加载建表与租户间隔离。
+func TestPartitionedEventStore_TablePerTenant(t *testing.T) {
+	database := setupTestDB(t)
+	store, err := NewPartitionedEventStore(database, PartitionConfig{Strategy: TablePerTenant{}})
+	require.NoError(t, err)
+
+	acme, globex := tenantCtx(t, "acme"), tenantCtx(t, "Globex")
+	require.NoError(t, store.AppendEvents(acme, 1, toStorableEvents([]eventing.Event[int64]{
+		makeEvent(1, "Order", "a-1", 1, nil),
+	}), 0))
+	// 不同租户使用同一聚合 ID 互不冲突。
+	require.NoError(t, store.AppendEvents(globex, 1, toStorableEvents([]eventing.Event[int64]{
+		makeEvent(1, "Order", "g-1", 1, nil),
+		makeEvent(1, "Order", "g-2", 2, nil),
+	}), 0))
+
+	globexPartition, err := TablePerTenant{}.Partition("Globex")
+	require.NoError(t, err)
+	var count int
+	require.NoError(t, database.QueryRow(context.Background(), `SELECT COUNT(*) FROM `+globexPartition.Table).Scan(&count))
+	require.Equal(t, 2, count)
+
+	loaded, err := store.LoadEvents(acme, 1, 0)
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	require.Equal(t, "a-1", loaded[0].GetID())
+
+	version, err := store.HeadVersion(globex, "Order", 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), version)
+
+	stream, err := store.StreamEvents(globex, &estore.StreamOptions{})
+	require.NoError(t, err)
+	require.Len(t, stream.Events, 2)
+
+	_, err = store.LoadEvents(context.Background(), 1, 0)
+	require.True(t, errors.Is(err, errors.InvalidInput), "tenant is required")
+
+	// 仅大小写或特殊字符不同的租户映射到不同分区。
+	loaded, err = store.LoadEvents(tenantCtx(t, "ACME"), 1, 0)
+	require.NoError(t, err)
+	require.Empty(t, loaded)
+	loaded, err = store.LoadEvents(tenantCtx(t, "acme-"), 1, 0)
+	require.NoError(t, err)
+	require.Empty(t, loaded)
+}
+
+// TestPartitionedEventStore_AppendEventsWithDB 验证分区就绪后可在调用方事务内追加事件。
+func TestPartitionedEventStore_AppendEventsWithDB(t *testing.T) {
+	database := setupTestDB(t)
+	store, err := NewPartitionedEventStore(database, PartitionConfig{Strategy: TablePerTenant{Prefix: "events"}})
+	require.NoError(t, err)
+
+	ctx := tenantCtx(t, "acme")
+	// 内存 SQLite 的每个连接是独立数据库：先在连接池上建分区，再开启事务。
+	require.NoError(t, store.Bootstrap(ctx, "acme"))
+	tx, err := database.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, store.AppendEventsWithDB(ctx, tx, 7, toStorableEvents([]eventing.Event[int64]{
+		makeEvent(7, "Order", "a-1", 1, nil),
+	}), 0))
+	require.NoError(t, tx.Commit())
+
+	loaded, err := store.LoadEvents(ctx, 7, 0)
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+}
+
+// TestSchemaPerTenant 验证 schema 分区命名与 SQLite 不支持自动建 schema。
+func TestSchemaPerTenant(t *testing.T) {
+	partition, err := SchemaPerTenant{}.Partition("Acme")
+	require.NoError(t, err)
+	require.Regexp(t, `^tenant_acme_[0-9a-f]{16}$`, partition.Schema)
+	require.Equal(t, partition.Schema+".event_store", partition.Table)
+
+	other, err := SchemaPerTenant{}.Partition("acme")
+	require.NoError(t, err)
+	require.NotEqual(t, partition.Schema, other.Schema, "tenant IDs differing only in case must not share a partition")
+
+	_, err = SchemaPerTenant{Table: "events; DROP"}.Partition("acme")
+	require.True(t, errors.Is(err, errors.InvalidInput))
+
+	store, err := NewPartitionedEventStore(setupTestDB(t), PartitionConfig{Strategy: SchemaPerTenant{}})
+	require.NoError(t, err)
+	require.True(t, errors.Is(store.Bootstrap(context.Background(), "acme"), errors.Unsupported))
+}
@@ -23,6 +23,7 @@ type preparedEvent struct {
 	timestamp     time.Time
 	payloadJSON   string
 	metadataJSON  string
+	tenantID      string
 }
 
 // AppendEvents 向事件存储追加事件。
@@ -58,13 +59,7 @@ func (s *SQLEventStore[ID]) AppendEventsWithDB(ctx context.Context, db db.IDatab
 	}
 
 	// 第一步：确定聚合类型
-	aggregateType := ""
-	for _, evt := range events {
-		if evt.GetAggregateType() != "" {
-			aggregateType = evt.GetAggregateType()
-			break
-		}
-	}
+	aggregateType := batchAggregateType(events)
 
 	// 第二步：版本检查（必须在事务内）
 	currentVersion, err := s.getCurrentVersion(ctx, db, agg, aggregateType)
@@ -82,53 +77,10 @@ func (s *SQLEventStore[ID]) AppendEventsWithDB(ctx context.Context, db db.IDatab
 
 	// 第三步：性能优化 - 预先验证和序列化所有事件（减少数据库往返）
 	// 这一步在版本检查后、插入前执行，避免无效写入
-	prepared := make([]preparedEvent, 0, len(events))
 	start := time.Now()
-
-	for idx, evt := range events {
-
-		// 聚合类型处理
-		if evt.GetAggregateType() == "" {
-			evt.SetAggregateType(aggregateType)
-		} else if aggregateType == "" {
-			aggregateType = evt.GetAggregateType()
-		} else if evt.GetAggregateType() != aggregateType {
-			return errors.NewCode(errors.InvalidInput, "mixed aggregate types in append batch").WithContext("event_id", evt.GetID()).WithContext("event_type", evt.GetType())
-		}
-
-		// 版本校验
-		expectedEventVersion := expectedVersion + uint64(idx) + 1
-		if evt.GetVersion() != expectedEventVersion {
-			return errors.NewCode(errors.InvalidInput, fmt.Sprintf("event version mismatch: expected %d, got %d", expectedEventVersion, evt.GetVersion())).WithContext("event_id", evt.GetID()).WithContext("event_type", evt.GetType())
-		}
-
-		// 事件验证
-		if err := evt.Validate(); err != nil {
-			return errors.NewCode(errors.InvalidInput, fmt.Sprintf("event validation failed: %v", err)).WithContext("event_id", evt.GetID()).WithContext("event_type", evt.GetType())
-		}
-
-		// 序列化（CPU密集，但避免了数据库往返）
-		payloadJSON, err := json.Marshal(evt.GetPayload())
-		if err != nil {
-			return errors.NewCodeWithCause(errors.Internal, "serialize payload failed", err).WithContext("event_id", evt.GetID()).WithContext("event_type", evt.GetType())
-		}
-
-		metadataJSON, err := json.Marshal(evt.GetMetadata())
-		if err != nil {
-			return errors.NewCodeWithCause(errors.Internal, "serialize metadata failed", err).WithContext("event_id", evt.GetID()).WithContext("event_type", evt.GetType())
-		}
-
-		// 使用 append 而非索引赋值，更健壮且语义更清晰
-		prepared = append(prepared, preparedEvent{
-			id:            evt.GetID(),
-			typ:           evt.GetType(),
-			aggregateType: evt.GetAggregateType(),
-			version:       evt.GetVersion(),
-			schemaVersion: evt.EventSchemaVersion(),
-			timestamp:     evt.GetTimestamp(),
-			payloadJSON:   string(payloadJSON),
-			metadataJSON:  string(metadataJSON),
-		})
+	prepared, err := s.prepareEvents(ctx, events, aggregateType, expectedVersion)
+	if err != nil {
+		return err
 	}
 
 	// 第四步：性能优化 - 批量INSERT
@@ -137,9 +89,10 @@ func (s *SQLEventStore[ID]) AppendEventsWithDB(ctx context.Context, db db.IDatab
 	if len(prepared) == 1 {
 		// 单个事件：使用简单INSERT（更易读的错误信息）
 		p := prepared[0]
-		insertSQL := fmt.Sprintf(`INSERT INTO %s (id, type, aggregate_id, aggregate_type, version, schema_version, timestamp, payload, metadata) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, s.tableName)
+		columns, rowPlaceholder := s.insertColumns()
+		insertSQL := fmt.Sprintf(`INSERT INTO %s (%s) VALUES %s`, s.tableName, columns, rowPlaceholder)
 		err = executeRecoverableStatement(ctx, db, "append_single", func() error {
-			_, err := db.Exec(ctx, insertSQL, p.id, p.typ, agg, p.aggregateType, p.version, p.schemaVersion, p.timestamp, p.payloadJSON, p.metadataJSON)
+			_, err := db.Exec(ctx, insertSQL, s.insertArgs(agg, p)...)
 			return err
 		})
 		if err != nil {
@@ -158,21 +111,19 @@ func (s *SQLEventStore[ID]) AppendEventsWithDB(ctx context.Context, db db.IDatab
 		}
 	} else {
 		// 多个事件：使用批量INSERT
+		columns, rowPlaceholder := s.insertColumns()
 		placeholders := make([]string, len(prepared))
-		args := make([]any, 0, len(prepared)*9)
+		args := make([]any, 0, len(prepared)*10)
 
 		for i, p := range prepared {
-			placeholders[i] = "(?, ?, ?, ?, ?, ?, ?, ?, ?)"
-			args = append(args,
-				p.id, p.typ, agg, p.aggregateType,
-				p.version, p.schemaVersion, p.timestamp,
-				p.payloadJSON, p.metadataJSON,
-			)
+			placeholders[i] = rowPlaceholder
+			args = append(args, s.insertArgs(agg, p)...)
 		}
 
 		batchSQL := fmt.Sprintf(
-			"INSERT INTO %s (id, type, aggregate_id, aggregate_type, version, schema_version, timestamp, payload, metadata) VALUES %s",
+			"INSERT INTO %s (%s) VALUES %s",
 			s.tableName,
+			columns,
 			strings.Join(placeholders, ","),
 		)
 
@@ -198,12 +149,82 @@ func (s *SQLEventStore[ID]) AppendEventsWithDB(ctx context.Context, db db.IDatab
 	return nil
 }
 
+// batchAggregateType 返回批内第一个非空的聚合类型。
+func batchAggregateType[ID comparable](events []eventing.IStorableEvent[ID]) string {
+	for _, evt := range events {
+		if evt.GetAggregateType() != "" {
+			return evt.GetAggregateType()
+		}
+	}
+	return ""
+}
+
+// prepareEvents 校验批内事件（聚合类型、连续版本、Validate、租户）并预先序列化。
+func (s *SQLEventStore[ID]) prepareEvents(ctx context.Context, events []eventing.IStorableEvent[ID], aggregateType string, expectedVersion uint64) ([]preparedEvent, error) {
+	prepared := make([]preparedEvent, 0, len(events))
+
+	for idx, evt := range events {
+
+		// 聚合类型处理
+		if evt.GetAggregateType() == "" {
+			evt.SetAggregateType(aggregateType)
+		} else if aggregateType == "" {
+			aggregateType = evt.GetAggregateType()
+		} else if evt.GetAggregateType() != aggregateType {
+			return nil, errors.NewCode(errors.InvalidInput, "mixed aggregate types in append batch").WithContext("event_id", evt.GetID()).WithContext("event_type", evt.GetType())
+		}
+
+		// 版本校验
+		expectedEventVersion := expectedVersion + uint64(idx) + 1
+		if evt.GetVersion() != expectedEventVersion {
+			return nil, errors.NewCode(errors.InvalidInput, fmt.Sprintf("event version mismatch: expected %d, got %d", expectedEventVersion, evt.GetVersion())).WithContext("event_id", evt.GetID()).WithContext("event_type", evt.GetType())
+		}
+
+		// 事件验证
+		if err := evt.Validate(); err != nil {
+			return nil, errors.NewCode(errors.InvalidInput, fmt.Sprintf("event validation failed: %v", err)).WithContext("event_id", evt.GetID()).WithContext("event_type", evt.GetType())
+		}
+
+		// 租户归属（启用租户列时补齐 metadata，需在序列化 metadata 之前）
+		tenantID, err := s.resolveEventTenant(ctx, evt)
+		if err != nil {
+			return nil, err
+		}
+
+		// 序列化（CPU密集，但避免了数据库往返）
+		payloadJSON, err := json.Marshal(evt.GetPayload())
+		if err != nil {
+			return nil, errors.NewCodeWithCause(errors.Internal, "serialize payload failed", err).WithContext("event_id", evt.GetID()).WithContext("event_type", evt.GetType())
+		}
+
+		metadataJSON, err := json.Marshal(evt.GetMetadata())
+		if err != nil {
+			return nil, errors.NewCodeWithCause(errors.Internal, "serialize metadata failed", err).WithContext("event_id", evt.GetID()).WithContext("event_type", evt.GetType())
+		}
+
+		// 使用 append 而非索引赋值，更健壮且语义更清晰
+		prepared = append(prepared, preparedEvent{
+			id:            evt.GetID(),
+			typ:           evt.GetType(),
+			aggregateType: evt.GetAggregateType(),
+			version:       evt.GetVersion(),
+			schemaVersion: evt.EventSchemaVersion(),
+			timestamp:     evt.GetTimestamp(),
+			payloadJSON:   string(payloadJSON),
+			metadataJSON:  string(metadataJSON),
+			tenantID:      tenantID,
+		})
+	}
+	return prepared, nil
+}
+
 func (s *SQLEventStore[ID]) appendEventsIndividually(ctx context.Context, db db.IDatabase, aggregateID ID, agg any, prepared []preparedEvent, start time.Time) error {
-	insertSQL := fmt.Sprintf(`INSERT INTO %s (id, type, aggregate_id, aggregate_type, version, schema_version, timestamp, payload, metadata) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, s.tableName)
+	columns, rowPlaceholder := s.insertColumns()
+	insertSQL := fmt.Sprintf(`INSERT INTO %s (%s) VALUES %s`, s.tableName, columns, rowPlaceholder)
 
 	for _, p := range prepared {
 		err := executeRecoverableStatement(ctx, db, "append_single", func() error {
-			_, err := db.Exec(ctx, insertSQL, p.id, p.typ, agg, p.aggregateType, p.version, p.schemaVersion, p.timestamp, p.payloadJSON, p.metadataJSON)
+			_, err := db.Exec(ctx, insertSQL, s.insertArgs(agg, p)...)
 			return err
 		})
 		if err != nil {
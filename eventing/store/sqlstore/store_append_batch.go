@@ -0,0 +1,233 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gochen/db"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/logging"
+)
+
+const (
+	// appendBatchMaxRows 单条批量 INSERT 的最大行数，避免超出数据库的参数数量上限。
+	appendBatchMaxRows = 500
+	// appendBatchVersionChunk 单次版本查询覆盖的最大聚合数。
+	appendBatchVersionChunk = 200
+)
+
+// AggregateBatch 描述跨聚合批量追加中单个聚合的事件。
+type AggregateBatch[ID comparable] struct {
+	AggregateID     ID
+	Events          []eventing.IStorableEvent[ID]
+	ExpectedVersion uint64 // 语义同 AppendEvents 的 expectedVersion
+}
+
+// preparedAggregateBatch 预处理后的单个聚合批次（编码后的聚合 ID 与事件）。
+type preparedAggregateBatch[ID comparable] struct {
+	aggregateID   ID
+	agg           any
+	aggregateType string
+	expected      uint64
+	events        []preparedEvent
+}
+
+// aggregateVersionKey 以 (聚合 ID, 聚合类型) 定位事件流，与 getCurrentVersion 的口径一致。
+type aggregateVersionKey[ID comparable] struct {
+	id  ID
+	typ string
+}
+
+// AppendEventsBatch 在一个事务内为多个聚合追加事件。
+//
+// 用于数据导入/迁移与高吞吐命令处理：版本检查按聚合分组一次查询，事件合并为多行 INSERT，
+// 避免逐聚合开启事务与往返。任一聚合版本冲突或事件校验失败时整批回滚。
+func (s *SQLEventStore[ID]) AppendEventsBatch(ctx context.Context, batches []AggregateBatch[ID]) error {
+	if len(batches) == 0 {
+		return nil
+	}
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		s.recordEventStoreError()
+		return errors.NewCodeWithCause(errors.Database, "begin transaction failed", err)
+	}
+	defer tx.Rollback()
+	if err := s.AppendEventsBatchWithDB(ctx, tx, batches); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		s.recordEventStoreError()
+		return errors.NewCodeWithCause(errors.Database, "commit transaction failed", err)
+	}
+	s.getLogger().Info(ctx, "event batches appended", logging.Int("aggregate_count", len(batches)))
+	return nil
+}
+
+// AppendEventsBatchWithDB 在调用方提供的事务内为多个聚合追加事件（语义同 AppendEventsBatch）。
+func (s *SQLEventStore[ID]) AppendEventsBatchWithDB(ctx context.Context, database db.IDatabase, batches []AggregateBatch[ID]) error {
+	prepared := make([]preparedAggregateBatch[ID], 0, len(batches))
+	seen := make(map[aggregateVersionKey[ID]]struct{}, len(batches))
+	for i := range batches {
+		batch := &batches[i]
+		if len(batch.Events) == 0 {
+			continue
+		}
+		agg, err := s.codec.Encode(batch.AggregateID)
+		if err != nil {
+			return errors.Wrap(err, errors.InvalidInput, "invalid aggregate id").WithContext("batch_index", i)
+		}
+		aggregateType := batchAggregateType(batch.Events)
+		key := aggregateVersionKey[ID]{id: batch.AggregateID, typ: aggregateType}
+		if _, dup := seen[key]; dup {
+			return errors.NewCode(errors.InvalidInput, "duplicate aggregate in append batch").
+				WithContext("aggregate_id", batch.AggregateID).
+				WithContext("aggregate_type", aggregateType)
+		}
+		seen[key] = struct{}{}
+		prepared = append(prepared, preparedAggregateBatch[ID]{
+			aggregateID:   batch.AggregateID,
+			agg:           agg,
+			aggregateType: aggregateType,
+			expected:      batch.ExpectedVersion,
+		})
+	}
+	if len(prepared) == 0 {
+		return nil
+	}
+
+	current, err := s.getCurrentVersions(ctx, database, prepared)
+	if err != nil {
+		s.recordEventStoreError()
+		return errors.NewCodeWithCause(errors.Database, "query current versions failed", err)
+	}
+	for _, b := range prepared {
+		actual := current[aggregateVersionKey[ID]{id: b.aggregateID, typ: b.aggregateType}]
+		if actual != b.expected {
+			return errors.NewCode(errors.Concurrency,
+				fmt.Sprintf("concurrency conflict: aggregate=%v, expected=%d, actual=%d", b.aggregateID, b.expected, actual),
+			).WithContext("aggregate_id", b.aggregateID).
+				WithContext("expected_version", b.expected).
+				WithContext("actual_version", actual)
+		}
+	}
+
+	start := time.Now()
+	total := 0
+	idx := 0
+	for i := range batches {
+		if len(batches[i].Events) == 0 {
+			continue
+		}
+		b := &prepared[idx]
+		idx++
+		events, err := s.prepareEvents(ctx, batches[i].Events, b.aggregateType, b.expected)
+		if err != nil {
+			return err
+		}
+		b.events = events
+		total += len(events)
+	}
+
+	if err := s.insertAggregateBatches(ctx, database, prepared); err != nil {
+		if !isDuplicateKeyError(err) {
+			s.recordEventStoreError()
+			return errors.NewCodeWithCause(errors.Database, "batch insert events failed", err)
+		}
+		// 存在重复事件：逐聚合逐条插入，沿用 AppendEvents 的幂等与冲突分类语义。
+		s.getLogger().Debug(ctx, "multi-aggregate insert failed with duplicate key, falling back to individual inserts", logging.Int("event_count", total))
+		for _, b := range prepared {
+			if err := s.appendEventsIndividually(ctx, database, b.aggregateID, b.agg, b.events, start); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	duration := time.Since(start)
+	s.recordEventSaved(total, duration)
+	s.getLogger().Debug(ctx, "multi-aggregate append done",
+		logging.Int("aggregate_count", len(prepared)),
+		logging.Int("written", total),
+		logging.Int64("ms", duration.Milliseconds()))
+	return nil
+}
+
+// insertAggregateBatches 把所有聚合的事件合并为多行 INSERT（按 appendBatchMaxRows 分段）。
+//
+// 任一分段失败时回滚到保存点（若支持），以便调用方降级为逐条插入。
+func (s *SQLEventStore[ID]) insertAggregateBatches(ctx context.Context, database db.IDatabase, batches []preparedAggregateBatch[ID]) error {
+	columns, rowPlaceholder := s.insertColumns()
+	return executeRecoverableStatement(ctx, database, "append_multi", func() error {
+		placeholders := make([]string, 0, appendBatchMaxRows)
+		args := make([]any, 0, appendBatchMaxRows*10)
+		flush := func() error {
+			if len(placeholders) == 0 {
+				return nil
+			}
+			query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", s.tableName, columns, strings.Join(placeholders, ","))
+			if _, err := database.Exec(ctx, query, args...); err != nil {
+				return err
+			}
+			placeholders, args = placeholders[:0], args[:0]
+			return nil
+		}
+		for _, b := range batches {
+			for _, p := range b.events {
+				placeholders = append(placeholders, rowPlaceholder)
+				args = append(args, s.insertArgs(b.agg, p)...)
+				if len(placeholders) == appendBatchMaxRows {
+					if err := flush(); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return flush()
+	})
+}
+
+// getCurrentVersions 按 (aggregate_id, aggregate_type) 分组查询多个聚合的当前版本；不存在的聚合不出现在结果中。
+func (s *SQLEventStore[ID]) getCurrentVersions(ctx context.Context, database db.IDatabase, batches []preparedAggregateBatch[ID]) (map[aggregateVersionKey[ID]]uint64, error) {
+	versions := make(map[aggregateVersionKey[ID]]uint64, len(batches))
+	for from := 0; from < len(batches); from += appendBatchVersionChunk {
+		chunk := batches[from:min(from+appendBatchVersionChunk, len(batches))]
+		conditions := make([]string, len(chunk))
+		args := make([]any, 0, len(chunk)*2)
+		for i, b := range chunk {
+			conditions[i] = "(aggregate_id = ? AND aggregate_type = ?)"
+			args = append(args, b.agg, b.aggregateType)
+		}
+		query := fmt.Sprintf("SELECT aggregate_id, aggregate_type, MAX(version) FROM %s WHERE %s GROUP BY aggregate_id, aggregate_type",
+			s.tableName, strings.Join(conditions, " OR "))
+		rows, err := database.Query(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var (
+				rawID         any
+				aggregateType string
+				version       uint64
+			)
+			if err := rows.Scan(&rawID, &aggregateType, &version); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			id, err := s.codec.Decode(rawID)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			versions[aggregateVersionKey[ID]{id: id, typ: aggregateType}] = version
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+	}
+	return versions, nil
+}
@@ -0,0 +1,94 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/eventing"
+)
+
+// TestSQLEventStore_AppendEventsBatch 验证跨聚合批量追加在一个事务内写入，并按行数上限分段。
+func TestSQLEventStore_AppendEventsBatch(t *testing.T) {
+	database := setupTestDB(t)
+	store := newTestStore(t, database, "event_store")
+	ctx := context.Background()
+
+	require.NoError(t, store.AppendEvents(ctx, 2, toStorableEvents([]eventing.Event[int64]{
+		makeEvent(2, "Order", "o2-1", 1, nil),
+	}), 0))
+
+	bulk := make([]eventing.Event[int64], 0, appendBatchMaxRows+1)
+	for v := 1; v <= appendBatchMaxRows+1; v++ {
+		bulk = append(bulk, makeEvent(3, "Order", fmt.Sprintf("o3-%d", v), uint64(v), nil))
+	}
+	err := store.AppendEventsBatch(ctx, []AggregateBatch[int64]{
+		{AggregateID: 1, Events: toStorableEvents([]eventing.Event[int64]{
+			makeEvent(1, "Order", "o1-1", 1, nil),
+			makeEvent(1, "Order", "o1-2", 2, nil),
+		})},
+		{AggregateID: 2, ExpectedVersion: 1, Events: toStorableEvents([]eventing.Event[int64]{
+			makeEvent(2, "Order", "o2-2", 2, nil),
+		})},
+		{AggregateID: 3, Events: toStorableEvents(bulk)},
+		{AggregateID: 4},
+	})
+	require.NoError(t, err)
+
+	for id, want := range map[int64]uint64{1: 2, 2: 2, 3: appendBatchMaxRows + 1} {
+		version, err := store.GetAggregateVersion(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, want, version, "aggregate %d", id)
+	}
+}
+
+// TestSQLEventStore_AppendEventsBatch_ConflictRollsBack 验证任一聚合版本冲突时整批不写入。
+func TestSQLEventStore_AppendEventsBatch_ConflictRollsBack(t *testing.T) {
+	database := setupTestDB(t)
+	store := newTestStore(t, database, "event_store")
+	ctx := context.Background()
+
+	require.NoError(t, store.AppendEvents(ctx, 2, toStorableEvents([]eventing.Event[int64]{
+		makeEvent(2, "Order", "o2-1", 1, nil),
+	}), 0))
+
+	err := store.AppendEventsBatch(ctx, []AggregateBatch[int64]{
+		{AggregateID: 1, Events: toStorableEvents([]eventing.Event[int64]{makeEvent(1, "Order", "o1-1", 1, nil)})},
+		{AggregateID: 2, Events: toStorableEvents([]eventing.Event[int64]{makeEvent(2, "Order", "o2-x", 1, nil)})},
+	})
+	require.True(t, errors.Is(err, errors.Concurrency))
+
+	exists, err := store.HasAggregate(ctx, 1)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	err = store.AppendEventsBatch(ctx, []AggregateBatch[int64]{
+		{AggregateID: 1, Events: toStorableEvents([]eventing.Event[int64]{makeEvent(1, "Order", "a", 1, nil)})},
+		{AggregateID: 1, Events: toStorableEvents([]eventing.Event[int64]{makeEvent(1, "Order", "b", 1, nil)})},
+	})
+	require.True(t, errors.Is(err, errors.InvalidInput), "same aggregate twice in one batch")
+}
+
+// TestSQLEventStore_AppendEventsBatch_DuplicateEventID 验证事件 ID 与已有事件冲突时降级逐条插入并整批回滚。
+func TestSQLEventStore_AppendEventsBatch_DuplicateEventID(t *testing.T) {
+	database := setupTestDB(t)
+	store := newTestStore(t, database, "event_store")
+	ctx := context.Background()
+
+	require.NoError(t, store.AppendEvents(ctx, 1, toStorableEvents([]eventing.Event[int64]{
+		makeEvent(1, "Order", "shared-id", 1, nil),
+	}), 0))
+
+	err := store.AppendEventsBatch(ctx, []AggregateBatch[int64]{
+		{AggregateID: 2, Events: toStorableEvents([]eventing.Event[int64]{makeEvent(2, "Order", "o2-1", 1, nil)})},
+		{AggregateID: 3, Events: toStorableEvents([]eventing.Event[int64]{makeEvent(3, "Order", "shared-id", 1, nil)})},
+	})
+	require.Error(t, err)
+
+	exists, err := store.HasAggregate(ctx, 2)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
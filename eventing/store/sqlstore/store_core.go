@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -22,14 +23,23 @@ type SQLEventStore[ID comparable] struct {
 	codec     codec.ICodec[ID, any]
 	logger    logging.ILogger
 	metrics   atomic.Value // eventStoreMetricsHolder（承载 monitoring.IEventStoreMetricsRecorder），用于并发热替换且避免 data race
+
+	// tenantColumn 非空时启用租户列：写入时落库租户 ID，读取时按 ctx 租户追加过滤条件。
+	tenantColumn string
+
+	// tombstones 为 true 时读取路径隐藏已软删除（末尾为墓碑事件）的聚合事件流。
+	tombstones bool
 }
 
 var defaultNoopLogger logging.ILogger = logging.NewNoopLogger()
 
-var tableNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+// tableNamePattern 允许可选的 schema 限定（schema.table），用于按 schema 分区。
+var tableNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_]+(\.[a-zA-Z0-9_]+)?$`)
 
 type sqLEventStoreOptions struct {
-	logger logging.ILogger
+	logger       logging.ILogger
+	tenantColumn string
+	tombstones   bool
 }
 
 // SQLEventStoreOption 用于配置 SQL 事件存储的可选项。
@@ -44,6 +54,26 @@ func WithLogger(logger logging.ILogger) SQLEventStoreOption {
 	return func(o *sqLEventStoreOptions) { o.logger = logger }
 }
 
+// WithTenantColumn 启用租户列（通常为 "tenant_id"），用于同一部署服务多个租户。
+//
+// 启用后：
+// - 追加事件时把租户 ID 写入该列，并补齐到事件 metadata；租户取自事件 metadata，缺失时取 ctx；
+// - 加载、流式读取与统计按 ctx 中的租户追加 `AND <column> = ?`；ctx 无租户时不过滤（后台投影/运维任务）；
+// - 乐观锁版本检查不按租户过滤：聚合 ID 需全局唯一，跨租户写入同一聚合会以并发冲突失败。
+//
+// 表结构需要预先包含该列（建议 NOT NULL DEFAULT ” 并建立索引）。
+func WithTenantColumn(column string) SQLEventStoreOption {
+	return func(o *sqLEventStoreOptions) { o.tenantColumn = column }
+}
+
+// WithTombstones 启用软删除（DeleteAggregate 的 DeleteModeTombstone）。
+//
+// 启用后加载、流式读取、存在性与版本查询追加 NOT EXISTS 子查询，隐藏含墓碑事件的聚合事件流；
+// 未启用时软删除返回 errors.Unsupported（硬删除始终可用）。建议同时为 (aggregate_id, aggregate_type, type) 建立索引。
+func WithTombstones() SQLEventStoreOption {
+	return func(o *sqLEventStoreOptions) { o.tombstones = true }
+}
+
 // validateTableName 校验表名称。
 func validateTableName(tableName string) error {
 	if tableName == "" {
@@ -80,12 +110,16 @@ func NewSQLEventStoreWithCodec[ID comparable](db db.IDatabase, tableName string,
 		opt(o)
 	}
 
+	if o.tenantColumn != "" && (!tableNamePattern.MatchString(o.tenantColumn) || strings.Contains(o.tenantColumn, ".")) {
+		return nil, errors.NewCode(errors.InvalidInput, fmt.Sprintf("invalid tenant column: %s", o.tenantColumn))
+	}
+
 	logger := o.logger
 	if logger == nil {
 		// Avoid nil panics. Composition root should inject a real logger.
 		logger = defaultNoopLogger
 	}
-	return &SQLEventStore[ID]{db: db, tableName: tableName, codec: idCodec, logger: logger}, nil
+	return &SQLEventStore[ID]{db: db, tableName: tableName, codec: idCodec, logger: logger, tenantColumn: o.tenantColumn, tombstones: o.tombstones}, nil
 }
 
 // NewSQLEventStore 为 `int64` 聚合 ID 创建一个 SQL 事件存储。
@@ -101,6 +135,9 @@ func (s *SQLEventStore[ID]) GetDB() db.IDatabase { return s.db }
 
 func (s *SQLEventStore[ID]) GetTableName() string { return s.tableName }
 
+// GetTenantColumn 返回启用的租户列名；未启用时为空。
+func (s *SQLEventStore[ID]) GetTenantColumn() string { return s.tenantColumn }
+
 // GetCodec 返回当前使用的聚合 ID 编解码器。
 func (s *SQLEventStore[ID]) GetCodec() codec.ICodec[ID, any] { return s.codec }
 
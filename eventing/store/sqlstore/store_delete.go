@@ -0,0 +1,210 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gochen/db"
+	"gochen/errors"
+	"gochen/eventing"
+	estore "gochen/eventing/store"
+	"gochen/logging"
+)
+
+// DeleteAggregate 软删除或硬删除聚合事件流，语义见 estore.IAggregateDeleter。
+//
+// 软删除在同一事务内校验版本并追加墓碑事件（需 WithTombstones，否则返回 Unsupported）；
+// 硬删除物理删除 (aggregate_id, aggregate_type) 下已校验版本内的事件，启用租户列时只删除 ctx 租户的事件；
+// 校验后有并发追加时返回 Concurrency 并回滚。
+func (s *SQLEventStore[ID]) DeleteAggregate(ctx context.Context, aggregateID ID, opts estore.DeleteOptions) error {
+	if opts.Mode == estore.DeleteModeTombstone && !s.tombstones {
+		return errors.NewCode(errors.Unsupported, "tombstone delete requires WithTombstones").
+			WithContext("aggregate_id", aggregateID)
+	}
+	agg, err := s.codec.Encode(aggregateID)
+	if err != nil {
+		return errors.Wrap(err, errors.InvalidInput, "invalid aggregate id")
+	}
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		s.recordEventStoreError()
+		return errors.NewCodeWithCause(errors.Database, "begin transaction failed", err)
+	}
+	defer tx.Rollback()
+
+	headVersion, headType, err := s.getHead(ctx, tx, agg, opts.AggregateType)
+	if err != nil {
+		s.recordEventStoreError()
+		return errors.NewCodeWithCause(errors.Database, "query current version failed", err)
+	}
+	tombstoned, err := estore.CheckDeleteVersion(aggregateID, opts, headVersion, headType)
+	if err != nil {
+		return err
+	}
+
+	var deleted int64
+	switch {
+	case opts.Mode == estore.DeleteModeHard:
+		if deleted, err = s.deleteStreamUpTo(ctx, tx, agg, opts.AggregateType, headVersion); err != nil {
+			return err
+		}
+	case tombstoned:
+		return nil
+	default:
+		marker := estore.NewTombstoneEvent(aggregateID, opts)
+		if err := s.AppendEventsWithDB(ctx, tx, aggregateID, []eventing.IStorableEvent[ID]{marker}, opts.ExpectedVersion); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		s.recordEventStoreError()
+		return errors.NewCodeWithCause(errors.Database, "commit transaction failed", err)
+	}
+	s.getLogger().Info(ctx, "aggregate deleted",
+		logging.Any("aggregate_id", aggregateID),
+		logging.String("aggregate_type", opts.AggregateType),
+		logging.String("mode", opts.Mode.String()),
+		logging.Uint64("version", opts.ExpectedVersion),
+		logging.Int64("deleted_count", deleted))
+	return nil
+}
+
+// PurgeTombstoned 硬删除墓碑事件时间戳早于 before 的事件流，返回删除的事件数（需 WithTombstones）。
+//
+// 逐条按软删除时的版本执行硬删除：软删除后若事件流发生变化（版本不一致）则跳过该流。
+func (s *SQLEventStore[ID]) PurgeTombstoned(ctx context.Context, before time.Time) (int64, error) {
+	if !s.tombstones {
+		return 0, errors.NewCode(errors.Unsupported, "purging tombstoned streams requires WithTombstones")
+	}
+	tenantClause, tenantArgs := s.tenantFilter(ctx)
+	query := fmt.Sprintf("SELECT aggregate_id, aggregate_type, version FROM %s WHERE type = ? AND timestamp < ?%s", s.tableName, tenantClause)
+	rows, err := s.db.Query(ctx, query, append([]any{estore.TombstoneEventType, before}, tenantArgs...)...)
+	if err != nil {
+		s.recordEventStoreError()
+		return 0, errors.NewCodeWithCause(errors.Database, "query tombstoned streams failed", err)
+	}
+	type tombstonedStream struct {
+		id      ID
+		typ     string
+		version uint64
+	}
+	var streams []tombstonedStream
+	for rows.Next() {
+		var (
+			rawID  any
+			stream tombstonedStream
+		)
+		if err := rows.Scan(&rawID, &stream.typ, &stream.version); err != nil {
+			rows.Close()
+			return 0, errors.NewCodeWithCause(errors.Database, "scan tombstoned stream failed", err)
+		}
+		id, err := s.codec.Decode(rawID)
+		if err != nil {
+			rows.Close()
+			return 0, errors.Wrap(err, errors.Internal, "decode tombstoned aggregate id failed")
+		}
+		stream.id = id
+		streams = append(streams, stream)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return 0, errors.NewCodeWithCause(errors.Database, "iterate tombstoned streams failed", err)
+	}
+
+	var deleted int64
+	for _, stream := range streams {
+		n, err := s.hardDeleteTombstoned(ctx, stream.id, stream.typ, stream.version)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+	return deleted, nil
+}
+
+// hardDeleteTombstoned 在事件流头部仍为指定版本的墓碑事件时物理删除整条流。
+func (s *SQLEventStore[ID]) hardDeleteTombstoned(ctx context.Context, aggregateID ID, aggregateType string, version uint64) (int64, error) {
+	agg, err := s.codec.Encode(aggregateID)
+	if err != nil {
+		return 0, errors.Wrap(err, errors.InvalidInput, "invalid aggregate id")
+	}
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		s.recordEventStoreError()
+		return 0, errors.NewCodeWithCause(errors.Database, "begin transaction failed", err)
+	}
+	defer tx.Rollback()
+
+	headVersion, headType, err := s.getHead(ctx, tx, agg, aggregateType)
+	if err != nil {
+		s.recordEventStoreError()
+		return 0, errors.NewCodeWithCause(errors.Database, "query current version failed", err)
+	}
+	if headVersion != version || headType != estore.TombstoneEventType {
+		return 0, nil
+	}
+	deleted, err := s.deleteStreamUpTo(ctx, tx, agg, aggregateType, version)
+	if err != nil {
+		if errors.Is(err, errors.Concurrency) {
+			// 墓碑之后又追加了事件：与版本不一致的流一样跳过。
+			return 0, nil
+		}
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		s.recordEventStoreError()
+		return 0, errors.NewCodeWithCause(errors.Database, "commit transaction failed", err)
+	}
+	s.getLogger().Info(ctx, "tombstoned aggregate purged",
+		logging.Any("aggregate_id", aggregateID),
+		logging.String("aggregate_type", aggregateType),
+		logging.Uint64("version", version),
+		logging.Int64("deleted_count", deleted))
+	return deleted, nil
+}
+
+// deleteStreamUpTo 在 tx 内删除版本不超过 maxVersion 的事件（启用租户列时只删除 ctx 租户的事件）。
+//
+// getHead 不加锁，删除前可能有并发追加：删除后若流中仍有更高版本的事件，返回 Concurrency，由调用方回滚事务。
+func (s *SQLEventStore[ID]) deleteStreamUpTo(ctx context.Context, tx db.ITransaction, aggregateID any, aggregateType string, maxVersion uint64) (int64, error) {
+	tenantClause, tenantArgs := s.tenantFilter(ctx)
+	query := fmt.Sprintf("DELETE FROM %s WHERE aggregate_id = ? AND aggregate_type = ? AND version <= ?%s", s.tableName, tenantClause)
+	res, err := tx.Exec(ctx, query, append([]any{aggregateID, aggregateType, maxVersion}, tenantArgs...)...)
+	if err != nil {
+		s.recordEventStoreError()
+		return 0, errors.NewCodeWithCause(errors.Database, "delete aggregate events failed", err)
+	}
+	deleted, _ := res.RowsAffected()
+
+	headVersion, _, err := s.getHead(ctx, tx, aggregateID, aggregateType)
+	if err != nil {
+		s.recordEventStoreError()
+		return 0, errors.NewCodeWithCause(errors.Database, "query current version failed", err)
+	}
+	if headVersion > maxVersion {
+		return 0, errors.NewCode(errors.Concurrency, "aggregate version changed before delete").
+			WithContext("aggregate_type", aggregateType).
+			WithContext("expected_version", maxVersion).
+			WithContext("actual_version", headVersion)
+	}
+	return deleted, nil
+}
+
+// getHead 查询事件流最新事件的版本与类型（不按租户/软删除过滤，与乐观锁检查口径一致）。
+func (s *SQLEventStore[ID]) getHead(ctx context.Context, database db.IDatabase, aggregateID any, aggregateType string) (uint64, string, error) {
+	query := fmt.Sprintf("SELECT version, type FROM %s WHERE aggregate_id = ? AND aggregate_type = ? ORDER BY version DESC LIMIT 1", s.tableName)
+	var (
+		version uint64
+		typ     string
+	)
+	if err := database.QueryRow(ctx, query, aggregateID, aggregateType).Scan(&version, &typ); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, "", nil
+		}
+		return 0, "", err
+	}
+	return version, typ, nil
+}
@@ -0,0 +1,139 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/eventing"
+	estore "gochen/eventing/store"
+	"gochen/logging"
+)
+
+// TestSQLEventStore_DeleteAggregate_Tombstone 验证软删除隐藏事件流、阻止旧版本写入，并可再硬删除。
+func TestSQLEventStore_DeleteAggregate_Tombstone(t *testing.T) {
+	database := setupTestDB(t)
+	store, err := NewSQLEventStore(database, "event_store", WithLogger(logging.NewNoopLogger()), WithTombstones())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for id := int64(1); id <= 2; id++ {
+		events := make([]eventing.Event[int64], 0, 3)
+		for v := 1; v <= 3; v++ {
+			events = append(events, makeEvent(id, "Order", fmt.Sprintf("e-%d-%d", id, v), uint64(v), nil))
+		}
+		require.NoError(t, store.AppendEvents(ctx, id, toStorableEvents(events), 0))
+	}
+
+	opts := estore.DeleteOptions{AggregateType: "Order", ExpectedVersion: 2, Reason: "retention"}
+	require.True(t, errors.Is(store.DeleteAggregate(ctx, 1, opts), errors.Concurrency))
+	opts.ExpectedVersion = 0
+	require.True(t, errors.Is(estore.DeleteAggregate[int64](ctx, store, 1, opts), errors.InvalidInput))
+
+	opts.ExpectedVersion = 3
+	require.NoError(t, store.DeleteAggregate(ctx, 1, opts))
+	require.NoError(t, store.DeleteAggregate(ctx, 1, opts), "tombstoning twice is idempotent")
+
+	loaded, err := store.LoadEvents(ctx, 1, 0)
+	require.NoError(t, err)
+	require.Empty(t, loaded)
+	exists, err := store.HasAggregate(ctx, 1)
+	require.NoError(t, err)
+	require.False(t, exists)
+	head, err := store.HeadVersion(ctx, "Order", 1)
+	require.NoError(t, err)
+	require.Zero(t, head)
+	stream, err := store.StreamEvents(ctx, &estore.StreamOptions{Limit: 100})
+	require.NoError(t, err)
+	require.Len(t, stream.Events, 3, "only the live aggregate remains visible")
+
+	stale := makeEvent(1, "Order", "e-1-4", 4, nil)
+	require.True(t, errors.Is(store.AppendEvents(ctx, 1, toStorableEvents([]eventing.Event[int64]{stale}), 3), errors.Concurrency))
+
+	opts.Mode = estore.DeleteModeHard
+	require.NoError(t, store.DeleteAggregate(ctx, 1, opts))
+	var remaining int
+	require.NoError(t, database.QueryRow(ctx, "SELECT COUNT(*) FROM event_store WHERE aggregate_id = 1").Scan(&remaining))
+	require.Zero(t, remaining)
+	require.True(t, errors.Is(store.DeleteAggregate(ctx, 1, opts), errors.NotFound))
+}
+
+// TestSQLEventStore_DeleteAggregate_HardWithCascade 验证硬删除级联清理派生数据，且未启用软删除时拒绝墓碑。
+func TestSQLEventStore_DeleteAggregate_HardWithCascade(t *testing.T) {
+	database := setupTestDB(t)
+	store := newTestStore(t, database, "event_store")
+	ctx := context.Background()
+	require.NoError(t, store.AppendEvents(ctx, 7, toStorableEvents([]eventing.Event[int64]{makeEvent(7, "Order", "e-7-1", 1, nil)}), 0))
+
+	opts := estore.DeleteOptions{AggregateType: "Order", ExpectedVersion: 1}
+	require.True(t, errors.Is(store.DeleteAggregate(ctx, 7, opts), errors.Unsupported))
+
+	var cleaned []string
+	cleaner := estore.AggregateDataCleanerFunc[int64](func(_ context.Context, aggregateType string, aggregateID int64) error {
+		cleaned = append(cleaned, fmt.Sprintf("%s:%d", aggregateType, aggregateID))
+		return nil
+	})
+	opts.Mode = estore.DeleteModeHard
+	require.NoError(t, estore.DeleteAggregate[int64](ctx, store, 7, opts, cleaner))
+	require.Equal(t, []string{"Order:7"}, cleaned)
+
+	version, err := store.GetAggregateVersion(ctx, 7)
+	require.NoError(t, err)
+	require.Zero(t, version)
+}
+
+// TestSQLEventStore_DeleteStreamUpTo_RejectsConcurrentAppend 验证硬删除只删除已校验版本内的事件，
+// 校验后并发追加的事件使删除返回 Concurrency 并回滚。
+func TestSQLEventStore_DeleteStreamUpTo_RejectsConcurrentAppend(t *testing.T) {
+	database := setupTestDB(t)
+	store := newTestStore(t, database, "event_store")
+	ctx := context.Background()
+	events := []eventing.Event[int64]{
+		makeEvent(9, "Order", "e-9-1", 1, nil),
+		makeEvent(9, "Order", "e-9-2", 2, nil),
+		makeEvent(9, "Order", "e-9-3", 3, nil),
+	}
+	require.NoError(t, store.AppendEvents(ctx, 9, toStorableEvents(events), 0))
+
+	tx, err := database.Begin(ctx)
+	require.NoError(t, err)
+	// 模拟 getHead 读到版本 2 之后又追加了版本 3。
+	_, err = store.deleteStreamUpTo(ctx, tx, int64(9), "Order", 2)
+	require.True(t, errors.Is(err, errors.Concurrency))
+	require.NoError(t, tx.Rollback())
+
+	version, err := store.GetAggregateVersion(ctx, 9)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), version, "the rolled back delete keeps the stream intact")
+}
+
+// TestSQLEventStore_PurgeTombstoned 验证按软删除时间硬删除墓碑事件流，活跃聚合不受影响。
+func TestSQLEventStore_PurgeTombstoned(t *testing.T) {
+	database := setupTestDB(t)
+	store, err := NewSQLEventStore(database, "event_store", WithLogger(logging.NewNoopLogger()), WithTombstones())
+	require.NoError(t, err)
+	ctx := context.Background()
+	for id := int64(1); id <= 2; id++ {
+		require.NoError(t, store.AppendEvents(ctx, id, toStorableEvents([]eventing.Event[int64]{makeEvent(id, "Order", fmt.Sprintf("e-%d-1", id), 1, nil)}), 0))
+	}
+	require.NoError(t, store.DeleteAggregate(ctx, 1, estore.DeleteOptions{AggregateType: "Order", ExpectedVersion: 1}))
+
+	purged, err := store.PurgeTombstoned(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, purged, "tombstones newer than the cutoff are kept")
+
+	purged, err = store.PurgeTombstoned(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(2), purged)
+	var remaining int
+	require.NoError(t, database.QueryRow(ctx, "SELECT COUNT(*) FROM event_store").Scan(&remaining))
+	require.Equal(t, 1, remaining)
+
+	plain := newTestStore(t, database, "event_store")
+	_, err = plain.PurgeTombstoned(ctx, time.Now())
+	require.True(t, errors.Is(err, errors.Unsupported))
+}
@@ -2,6 +2,7 @@ package sqlstore
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -21,8 +22,9 @@ func (s *SQLEventStore[ID]) LoadEvents(ctx context.Context, aggregateID ID, afte
 	if err != nil {
 		return nil, errors.Wrap(err, errors.InvalidInput, "invalid aggregate id")
 	}
-	query := fmt.Sprintf("SELECT id, type, aggregate_id, aggregate_type, version, schema_version, timestamp, payload, metadata FROM %s WHERE aggregate_id = ? AND version > ? ORDER BY version ASC", s.tableName)
-	rows, err := s.db.Query(ctx, query, agg, afterVersion)
+	filterClause, filterArgs := s.readFilter(ctx)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE aggregate_id = ? AND version > ?%s ORDER BY version ASC", eventColumns, s.tableName, filterClause)
+	rows, err := s.db.Query(ctx, query, append([]any{agg, afterVersion}, filterArgs...)...)
 	if err != nil {
 		s.recordEventStoreError()
 		return nil, err
@@ -37,6 +39,21 @@ func (s *SQLEventStore[ID]) LoadEvents(ctx context.Context, aggregateID ID, afte
 	return events, nil
 }
 
+// LoadEventsIter 以迭代器分页加载聚合事件。
+//
+// 每页通过 StreamAggregate 按版本游标读取（页大小为 DefaultIteratorPageSize），
+// 每次查询独立执行，迭代期间不持有数据库连接。
+func (s *SQLEventStore[ID]) LoadEventsIter(ctx context.Context, aggregateID ID, afterVersion uint64) (estore.IEventIterator[ID], error) {
+	if _, err := s.codec.Encode(aggregateID); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, "invalid aggregate id")
+	}
+	return estore.NewAggregateStreamIterator(ctx, s.StreamAggregate, estore.AggregateStreamOptions[ID]{
+		AggregateID:  aggregateID,
+		AfterVersion: afterVersion,
+		Limit:        estore.DefaultIteratorPageSize,
+	}), nil
+}
+
 // LoadEventsByType 加载聚合事件（按聚合类型）。
 //
 // 说明：
@@ -47,8 +64,9 @@ func (s *SQLEventStore[ID]) LoadEventsByType(ctx context.Context, aggregateType
 	if err != nil {
 		return nil, errors.Wrap(err, errors.InvalidInput, "invalid aggregate id")
 	}
-	query := fmt.Sprintf("SELECT id, type, aggregate_id, aggregate_type, version, schema_version, timestamp, payload, metadata FROM %s WHERE aggregate_id = ? AND aggregate_type = ? AND version > ? ORDER BY version ASC", s.tableName)
-	rows, err := s.db.Query(ctx, query, agg, aggregateType, afterVersion)
+	filterClause, filterArgs := s.readFilter(ctx)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE aggregate_id = ? AND aggregate_type = ? AND version > ?%s ORDER BY version ASC", eventColumns, s.tableName, filterClause)
+	rows, err := s.db.Query(ctx, query, append([]any{agg, aggregateType, afterVersion}, filterArgs...)...)
 	if err != nil {
 		s.recordEventStoreError()
 		return nil, err
@@ -74,7 +92,7 @@ func (s *SQLEventStore[ID]) StreamAggregate(ctx context.Context, opts *estore.Ag
 	}
 
 	// 构建查询：按版本升序读取指定聚合的事件，限制条数
-	base := fmt.Sprintf("SELECT id, type, aggregate_id, aggregate_type, version, schema_version, timestamp, payload, metadata FROM %s WHERE aggregate_id = ?", s.tableName)
+	base := fmt.Sprintf("SELECT %s FROM %s WHERE aggregate_id = ?", eventColumns, s.tableName)
 	agg, err := s.codec.Encode(opts.AggregateID)
 	if err != nil {
 		return nil, errors.Wrap(err, errors.InvalidInput, "invalid aggregate id")
@@ -84,6 +102,9 @@ func (s *SQLEventStore[ID]) StreamAggregate(ctx context.Context, opts *estore.Ag
 		base += " AND aggregate_type = ?"
 		args = append(args, opts.AggregateType)
 	}
+	filterClause, filterArgs := s.readFilter(ctx)
+	base += filterClause
+	args = append(args, filterArgs...)
 	base += " AND version > ? ORDER BY version ASC LIMIT ?"
 	args = append(args, opts.AfterVersion, limit+1) // 多取一条判断 HasMore
 
@@ -188,20 +209,71 @@ func (s *SQLEventStore[ID]) scanEvents(rows IRowScanner) ([]eventing.Event[ID],
 }
 
 // HasAggregate 判断聚合。
+//
+// 说明：
+// - 命中第一行即返回，不统计整段事件流。
 func (s *SQLEventStore[ID]) HasAggregate(ctx context.Context, aggregateID ID) (bool, error) {
 	agg, err := s.codec.Encode(aggregateID)
 	if err != nil {
 		return false, errors.Wrap(err, errors.InvalidInput, "invalid aggregate id")
 	}
-	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE aggregate_id = ?", s.tableName)
-	row := s.db.QueryRow(ctx, query, agg)
+	filterClause, filterArgs := s.readFilter(ctx)
+	query := fmt.Sprintf("SELECT 1 FROM %s WHERE aggregate_id = ?%s LIMIT 1", s.tableName, filterClause)
+	row := s.db.QueryRow(ctx, query, append([]any{agg}, filterArgs...)...)
 
-	var count int64
-	if err := row.Scan(&count); err != nil {
+	var one int
+	if err := row.Scan(&one); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
 		return false, err
 	}
 
-	return count > 0, nil
+	return true, nil
+}
+
+// CountEvents 统计聚合事件数量。
+//
+// 说明：
+// - 仅对 aggregate_id 计数，可由 (aggregate_id, aggregate_type, version) 唯一索引直接覆盖。
+func (s *SQLEventStore[ID]) CountEvents(ctx context.Context, aggregateID ID) (uint64, error) {
+	agg, err := s.codec.Encode(aggregateID)
+	if err != nil {
+		return 0, errors.Wrap(err, errors.InvalidInput, "invalid aggregate id")
+	}
+	filterClause, filterArgs := s.readFilter(ctx)
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE aggregate_id = ?%s", s.tableName, filterClause)
+	row := s.db.QueryRow(ctx, query, append([]any{agg}, filterArgs...)...)
+
+	var count uint64
+	if err := row.Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// HeadVersion 查询指定聚合类型下聚合的最新版本号（与追加时的乐观锁检查使用同一查询）。
+func (s *SQLEventStore[ID]) HeadVersion(ctx context.Context, aggregateType string, aggregateID ID) (uint64, error) {
+	agg, err := s.codec.Encode(aggregateID)
+	if err != nil {
+		return 0, errors.Wrap(err, errors.InvalidInput, "invalid aggregate id")
+	}
+	if s.tombstones {
+		return s.getVisibleVersion(ctx, agg, aggregateType)
+	}
+	return s.getCurrentVersion(ctx, s.db, agg, aggregateType)
+}
+
+// getVisibleVersion 查询未软删除事件流的最新版本号（已软删除返回 0）。
+func (s *SQLEventStore[ID]) getVisibleVersion(ctx context.Context, aggregateID any, aggregateType string) (uint64, error) {
+	tombstoneClause, tombstoneArgs := s.tombstoneFilter()
+	query := fmt.Sprintf("SELECT COALESCE(MAX(version), 0) FROM %s WHERE aggregate_id = ? AND aggregate_type = ?%s", s.tableName, tombstoneClause)
+	var version uint64
+	if err := s.db.QueryRow(ctx, query, append([]any{aggregateID, aggregateType}, tombstoneArgs...)...).Scan(&version); err != nil {
+		return 0, err
+	}
+	return version, nil
 }
 
 // GetAggregateVersion 从存储中查询对象。
@@ -213,8 +285,9 @@ func (s *SQLEventStore[ID]) GetAggregateVersion(ctx context.Context, aggregateID
 	if err != nil {
 		return 0, errors.Wrap(err, errors.InvalidInput, "invalid aggregate id")
 	}
-	query := fmt.Sprintf("SELECT COALESCE(MAX(version), 0) FROM %s WHERE aggregate_id = ?", s.tableName)
-	row := s.db.QueryRow(ctx, query, agg)
+	filterClause, filterArgs := s.readFilter(ctx)
+	query := fmt.Sprintf("SELECT COALESCE(MAX(version), 0) FROM %s WHERE aggregate_id = ?%s", s.tableName, filterClause)
+	row := s.db.QueryRow(ctx, query, append([]any{agg}, filterArgs...)...)
 
 	var version uint64
 	if err := row.Scan(&version); err != nil {
@@ -228,4 +301,7 @@ func (s *SQLEventStore[ID]) GetAggregateVersion(ctx context.Context, aggregateID
 var (
 	_ estore.IEventStore[int64]       = (*SQLEventStore[int64])(nil)
 	_ estore.IEventStreamStore[int64] = (*SQLEventStore[int64])(nil)
+	_ estore.ITxEventAppender[int64]  = (*SQLEventStore[int64])(nil)
+	_ estore.IAggregateDeleter[int64] = (*SQLEventStore[int64])(nil)
+	_ estore.ITombstonePurger         = (*SQLEventStore[int64])(nil)
 )
@@ -2,12 +2,14 @@ package sqlstore
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"gochen/eventing"
+	estore "gochen/eventing/store"
 	"gochen/logging"
 )
 
@@ -101,6 +103,43 @@ func TestSQLEventStore_GetAggregateVersion(t *testing.T) {
 	assert.Equal(t, uint64(3), version)
 }
 
+// TestSQLEventStore_CountEventsAndHeadVersion 验证 SQLEventStore 计数与按类型版本查询。
+func TestSQLEventStore_CountEventsAndHeadVersion(t *testing.T) {
+	database := setupTestDB(t)
+	store := newTestStore(t, database, "event_store")
+
+	ctx := context.Background()
+	aggregateID := int64(400)
+
+	count, err := store.CountEvents(ctx, aggregateID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), count)
+
+	head, err := store.HeadVersion(ctx, "TestAggregate", aggregateID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), head)
+
+	events := []eventing.Event[int64]{
+		makeEvent(aggregateID, "TestAggregate", "event-1", 1, nil),
+		makeEvent(aggregateID, "TestAggregate", "event-2", 2, nil),
+	}
+	require.NoError(t, store.AppendEvents(ctx, aggregateID, toStorableEvents(events), 0))
+	other := []eventing.Event[int64]{makeEvent(aggregateID, "OtherAggregate", "event-3", 1, nil)}
+	require.NoError(t, store.AppendEvents(ctx, aggregateID, toStorableEvents(other), 0))
+
+	count, err = store.CountEvents(ctx, aggregateID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), count)
+
+	head, err = store.HeadVersion(ctx, "TestAggregate", aggregateID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), head)
+
+	head, err = store.HeadVersion(ctx, "OtherAggregate", aggregateID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), head)
+}
+
 // TestSQLEventStore_Init 验证 SQLEventStore Init。
 func TestSQLEventStore_Init(t *testing.T) {
 	database := setupTestDB(t)
@@ -136,3 +175,28 @@ func TestSQLEventStore_TableNameValidation(t *testing.T) {
 	_, err := NewSQLEventStore(database, "event_store;DROP TABLE event_store;", WithLogger(logging.NewNoopLogger()))
 	require.Error(t, err)
 }
+
+// TestSQLEventStore_LoadEventsIter 验证迭代器跨页读取且结果与 LoadEvents 一致。
+func TestSQLEventStore_LoadEventsIter(t *testing.T) {
+	database := setupTestDB(t)
+	store := newTestStore(t, database, "event_store")
+	ctx := context.Background()
+
+	total := estore.DefaultIteratorPageSize + 3
+	events := make([]eventing.Event[int64], 0, total)
+	for v := 1; v <= total; v++ {
+		events = append(events, makeEvent(9, "Order", fmt.Sprintf("iter-%d", v), uint64(v), nil))
+	}
+	require.NoError(t, store.AppendEvents(ctx, 9, toStorableEvents(events), 0))
+
+	it, err := store.LoadEventsIter(ctx, 9, 1)
+	require.NoError(t, err)
+	defer it.Close()
+	expected := uint64(2)
+	for it.Next() {
+		require.Equal(t, expected, it.Event().Version)
+		expected++
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, uint64(total+1), expected)
+}
@@ -0,0 +1,61 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+
+	"gochen/errors"
+	"gochen/logging"
+)
+
+// PurgeEvents 删除聚合中版本不大于 throughVersion 的事件，用于归档后收缩热存储。
+//
+// 聚合的最新事件必须保留：乐观锁与 GetAggregateVersion/HeadVersion 依赖 MAX(version)，
+// 因此 throughVersion 不小于当前版本时返回 InvalidInput。返回实际删除的事件数。
+func (s *SQLEventStore[ID]) PurgeEvents(ctx context.Context, aggregateType string, aggregateID ID, throughVersion uint64) (int64, error) {
+	if throughVersion == 0 {
+		return 0, nil
+	}
+	agg, err := s.codec.Encode(aggregateID)
+	if err != nil {
+		return 0, errors.Wrap(err, errors.InvalidInput, "invalid aggregate id")
+	}
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		s.recordEventStoreError()
+		return 0, errors.NewCodeWithCause(errors.Database, "begin transaction failed", err)
+	}
+	defer tx.Rollback()
+
+	head, err := s.getCurrentVersion(ctx, tx, agg, aggregateType)
+	if err != nil {
+		s.recordEventStoreError()
+		return 0, errors.NewCodeWithCause(errors.Database, "query current version failed", err)
+	}
+	if throughVersion >= head {
+		return 0, errors.NewCode(errors.InvalidInput, "cannot purge the head event of an aggregate").
+			WithContext("aggregate_id", aggregateID).
+			WithContext("aggregate_type", aggregateType).
+			WithContext("through_version", throughVersion).
+			WithContext("head_version", head)
+	}
+
+	tenantClause, tenantArgs := s.tenantFilter(ctx)
+	query := fmt.Sprintf("DELETE FROM %s WHERE aggregate_id = ? AND aggregate_type = ? AND version <= ?%s", s.tableName, tenantClause)
+	res, err := tx.Exec(ctx, query, append([]any{agg, aggregateType, throughVersion}, tenantArgs...)...)
+	if err != nil {
+		s.recordEventStoreError()
+		return 0, errors.NewCodeWithCause(errors.Database, "purge events failed", err)
+	}
+	deleted, _ := res.RowsAffected()
+	if err := tx.Commit(); err != nil {
+		s.recordEventStoreError()
+		return 0, errors.NewCodeWithCause(errors.Database, "commit transaction failed", err)
+	}
+	s.getLogger().Info(ctx, "events purged",
+		logging.Any("aggregate_id", aggregateID),
+		logging.String("aggregate_type", aggregateType),
+		logging.Uint64("through_version", throughVersion),
+		logging.Int64("deleted_count", deleted))
+	return deleted, nil
+}
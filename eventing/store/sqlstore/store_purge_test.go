@@ -0,0 +1,41 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/eventing"
+)
+
+// TestSQLEventStore_PurgeEvents 验证删除已归档区间后最新版本不变，且不允许删除最新事件。
+func TestSQLEventStore_PurgeEvents(t *testing.T) {
+	database := setupTestDB(t)
+	store := newTestStore(t, database, "event_store")
+	ctx := context.Background()
+
+	events := make([]eventing.Event[int64], 0, 5)
+	for v := 1; v <= 5; v++ {
+		events = append(events, makeEvent(1, "Order", fmt.Sprintf("e-%d", v), uint64(v), nil))
+	}
+	require.NoError(t, store.AppendEvents(ctx, 1, toStorableEvents(events), 0))
+
+	deleted, err := store.PurgeEvents(ctx, "Order", 1, 3)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), deleted)
+
+	loaded, err := store.LoadEvents(ctx, 1, 0)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	require.Equal(t, uint64(4), loaded[0].Version)
+
+	version, err := store.HeadVersion(ctx, "Order", 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), version)
+
+	_, err = store.PurgeEvents(ctx, "Order", 1, 5)
+	require.True(t, errors.Is(err, errors.InvalidInput))
+}
@@ -3,10 +3,13 @@ package sqlstore
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
+	"gochen/contextx"
+	"gochen/db/dialect"
 	"gochen/errors"
 	estore "gochen/eventing/store"
 )
@@ -29,9 +32,14 @@ func (s *SQLEventStore[ID]) StreamEvents(ctx context.Context, opts *estore.Strea
 	}
 
 	var builder strings.Builder
-	fmt.Fprintf(&builder, "SELECT id, type, aggregate_id, aggregate_type, version, schema_version, timestamp, payload, metadata FROM %s WHERE 1=1", s.tableName)
+	fmt.Fprintf(&builder, "SELECT %s FROM %s WHERE 1=1", eventColumns, s.tableName)
 	args := make([]any, 0, 10)
 
+	if filterClause, filterArgs := s.readFilter(ctx); filterClause != "" {
+		builder.WriteString(filterClause)
+		args = append(args, filterArgs...)
+	}
+
 	if !opts.FromTime.IsZero() {
 		builder.WriteString(" AND timestamp >= ?")
 		args = append(args, opts.FromTime)
@@ -52,6 +60,21 @@ func (s *SQLEventStore[ID]) StreamEvents(ctx context.Context, opts *estore.Strea
 			args = append(args, t)
 		}
 	}
+	dialectName := dialect.FromDatabase(s.db).Name()
+	for _, f := range []struct{ key, value string }{
+		{contextx.MetadataCorrelationKey, opts.CorrelationID},
+		{contextx.MetadataCausationKey, opts.CausationID},
+	} {
+		if f.value == "" {
+			continue
+		}
+		cond, condArgs, err := metadataFilter(dialectName, f.key, f.value)
+		if err != nil {
+			return nil, err
+		}
+		builder.WriteString(" AND " + cond)
+		args = append(args, condArgs...)
+	}
 	if opts.After != "" && !cursorTimestamp.IsZero() {
 		builder.WriteString(" AND (timestamp > ? OR (timestamp = ? AND id > ?))")
 		args = append(args, cursorTimestamp, cursorTimestamp, opts.After)
@@ -91,6 +114,43 @@ func (s *SQLEventStore[ID]) StreamEvents(ctx context.Context, opts *estore.Strea
 	return result, nil
 }
 
+// metadataFilter 返回按 metadata 字段精确匹配的条件：已知方言使用 JSON 函数取值比较，
+// 不依赖 metadata 列的具体文本格式（空白、键顺序、jsonb 规范化）；未知方言回退到 metadataLikePattern。
+func metadataFilter(name dialect.Name, key, value string) (string, []any, error) {
+	switch name {
+	case dialect.NameSQLite:
+		return "json_extract(metadata, ?) = ?", []any{"$." + key, value}, nil
+	case dialect.NamePostgres:
+		return "(metadata::jsonb ->> ?) = ?", []any{key, value}, nil
+	case dialect.NameMySQL:
+		return "JSON_UNQUOTE(JSON_EXTRACT(metadata, ?)) = ?", []any{"$." + key, value}, nil
+	default:
+		pattern, err := metadataLikePattern(key, value)
+		if err != nil {
+			return "", nil, err
+		}
+		return "metadata LIKE ? ESCAPE '!'", []any{pattern}, nil
+	}
+}
+
+// metadataLikePattern 返回匹配 metadata JSON 中 "key":"value" 片段的 LIKE 模式。
+//
+// 该模式依赖 metadata 列保存的正是 encoding/json 对 messaging.Metadata 的紧凑编码（store_append 写入的格式）：
+// 键值之间没有空白，且特殊字符按 Go 的规则转义（如 '<' 编码为 \u003c）。其他工具写入或被数据库规范化
+// （如 Postgres jsonb 输出 "key": "value"）的行将无法匹配。LIKE 通配符以 '!' 转义，大小写是否敏感取决于排序规则。
+func metadataLikePattern(key, value string) (string, error) {
+	k, err := json.Marshal(key)
+	if err != nil {
+		return "", errors.NewCodeWithCause(errors.InvalidInput, "invalid metadata filter key", err)
+	}
+	v, err := json.Marshal(value)
+	if err != nil {
+		return "", errors.NewCodeWithCause(errors.InvalidInput, "invalid metadata filter value", err)
+	}
+	escaped := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(string(k) + ":" + string(v))
+	return "%" + escaped + "%", nil
+}
+
 func placeholders(n int) string {
 	if n <= 0 {
 		return ""
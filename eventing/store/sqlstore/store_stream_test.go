@@ -9,6 +9,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"gochen/contextx"
+	"gochen/db/dialect"
 	"gochen/errors"
 	"gochen/eventing"
 	estore "gochen/eventing/store"
@@ -168,6 +170,56 @@ func TestSQLEventStore_StreamEvents_TypeFilters(t *testing.T) {
 	})
 }
 
+// TestSQLEventStore_StreamEvents_CorrelationFilters 验证按 correlation_id/causation_id 精确过滤（LIKE 通配符被转义）。
+func TestSQLEventStore_StreamEvents_CorrelationFilters(t *testing.T) {
+	database := setupTestDB(t)
+	store := newTestStore(t, database, "event_store")
+
+	ctx := context.Background()
+
+	for i, ids := range [][2]string{{"flow_1", "cmd-1"}, {"flowX1", "cmd-1"}, {"flow_1", "cmd-2"}} {
+		evt := makeEvent(int64(i+1), "OrderAggregate", fmt.Sprintf("event-%d", i+1), 1, nil)
+		evt.Metadata.Set(contextx.MetadataCorrelationKey, ids[0])
+		evt.Metadata.Set(contextx.MetadataCausationKey, ids[1])
+		require.NoError(t, store.AppendEvents(ctx, int64(i+1), toStorableEvents([]eventing.Event[int64]{evt}), 0))
+	}
+
+	cases := []struct {
+		name string
+		opts estore.StreamOptions
+		want []string
+	}{
+		{"按 correlation 过滤", estore.StreamOptions{CorrelationID: "flow_1"}, []string{"event-1", "event-3"}},
+		{"按 causation 过滤", estore.StreamOptions{CausationID: "cmd-2"}, []string{"event-3"}},
+		{"组合过滤", estore.StreamOptions{CorrelationID: "flow_1", CausationID: "cmd-1"}, []string{"event-1"}},
+		{"不做子串匹配", estore.StreamOptions{CorrelationID: "flow"}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := store.StreamEvents(ctx, &tc.opts)
+			require.NoError(t, err)
+			var got []string
+			for _, evt := range result.Events {
+				got = append(got, evt.GetID())
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// TestMetadataFilter_FallsBackToLikeForUnknownDialect 验证已知方言使用 JSON 函数，未知方言回退到转义的 LIKE 模式。
+func TestMetadataFilter_FallsBackToLikeForUnknownDialect(t *testing.T) {
+	cond, args, err := metadataFilter(dialect.NamePostgres, contextx.MetadataCorrelationKey, "flow_1")
+	require.NoError(t, err)
+	assert.Equal(t, "(metadata::jsonb ->> ?) = ?", cond)
+	assert.Equal(t, []any{"correlation_id", "flow_1"}, args)
+
+	cond, args, err = metadataFilter(dialect.NameUnknown, contextx.MetadataCorrelationKey, "flow_1")
+	require.NoError(t, err)
+	assert.Equal(t, "metadata LIKE ? ESCAPE '!'", cond)
+	assert.Equal(t, []any{`%"correlation!_id":"flow!_1"%`}, args)
+}
+
 // TestSQLEventStore_StreamEvents_EmptyResult 验证 SQLEventStore StreamEvents EmptyResult。
 func TestSQLEventStore_StreamEvents_EmptyResult(t *testing.T) {
 	database := setupTestDB(t)
@@ -0,0 +1,85 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/eventing"
+	estore "gochen/eventing/store"
+)
+
+// eventColumns 是事件表的基础列（与 scanEvents 的扫描顺序一致）。
+const eventColumns = "id, type, aggregate_id, aggregate_type, version, schema_version, timestamp, payload, metadata"
+
+// insertColumns 返回 INSERT 使用的列清单与每行占位符。
+func (s *SQLEventStore[ID]) insertColumns() (columns string, rowPlaceholder string) {
+	if s.tenantColumn == "" {
+		return eventColumns, "(?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	}
+	return eventColumns + ", " + s.tenantColumn, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+}
+
+// insertArgs 返回单行 INSERT 参数（与 insertColumns 对齐）。
+func (s *SQLEventStore[ID]) insertArgs(agg any, p preparedEvent) []any {
+	args := []any{p.id, p.typ, agg, p.aggregateType, p.version, p.schemaVersion, p.timestamp, p.payloadJSON, p.metadataJSON}
+	if s.tenantColumn != "" {
+		args = append(args, p.tenantID)
+	}
+	return args
+}
+
+// tenantFilter 返回按 ctx 租户过滤的 SQL 片段与参数；未启用租户列或 ctx 无租户时返回空。
+func (s *SQLEventStore[ID]) tenantFilter(ctx context.Context) (string, []any) {
+	if s.tenantColumn == "" || ctx == nil {
+		return "", nil
+	}
+	tenantID := strings.TrimSpace(contextx.TenantID(ctx))
+	if tenantID == "" {
+		return "", nil
+	}
+	return " AND " + s.tenantColumn + " = ?", []any{tenantID}
+}
+
+// tombstoneFilter 返回隐藏已软删除事件流的 SQL 片段与参数；未启用软删除时返回空。
+func (s *SQLEventStore[ID]) tombstoneFilter() (string, []any) {
+	if !s.tombstones {
+		return "", nil
+	}
+	return fmt.Sprintf(" AND NOT EXISTS (SELECT 1 FROM %[1]s ts WHERE ts.aggregate_id = %[1]s.aggregate_id AND ts.aggregate_type = %[1]s.aggregate_type AND ts.type = ?)", s.tableName),
+		[]any{estore.TombstoneEventType}
+}
+
+// readFilter 组合租户与软删除过滤（读取路径使用）。
+func (s *SQLEventStore[ID]) readFilter(ctx context.Context) (string, []any) {
+	tenantClause, tenantArgs := s.tenantFilter(ctx)
+	tombstoneClause, tombstoneArgs := s.tombstoneFilter()
+	return tenantClause + tombstoneClause, append(tenantArgs, tombstoneArgs...)
+}
+
+// resolveEventTenant 确定事件归属租户并补齐到事件 metadata。
+//
+// 事件 metadata 已携带租户且与 ctx 租户不一致时拒绝写入，避免跨租户落库。
+func (s *SQLEventStore[ID]) resolveEventTenant(ctx context.Context, evt eventing.IStorableEvent[ID]) (string, error) {
+	if s.tenantColumn == "" {
+		return "", nil
+	}
+	ctxTenant := strings.TrimSpace(contextx.TenantID(ctx))
+	md := evt.GetMetadata()
+	if v, ok := md.Get(contextx.MetadataTenantKey); ok && strings.TrimSpace(v) != "" {
+		v = strings.TrimSpace(v)
+		if ctxTenant != "" && v != ctxTenant {
+			return "", errors.NewCode(errors.Forbidden, "event tenant does not match context tenant").
+				WithContext("event_id", evt.GetID()).
+				WithContext("event_tenant", v).
+				WithContext("context_tenant", ctxTenant)
+		}
+		return v, nil
+	}
+	if ctxTenant != "" {
+		md.Set(contextx.MetadataTenantKey, ctxTenant)
+	}
+	return ctxTenant, nil
+}
@@ -0,0 +1,85 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/eventing"
+	estore "gochen/eventing/store"
+)
+
+func tenantCtx(t *testing.T, tenantID string) context.Context {
+	t.Helper()
+	ctx, err := contextx.WithTenantID(context.Background(), tenantID)
+	require.NoError(t, err)
+	return ctx
+}
+
+// TestSQLEventStore_TenantColumn_ScopesReads 验证启用租户列后写入租户并按 ctx 租户过滤读取。
+func TestSQLEventStore_TenantColumn_ScopesReads(t *testing.T) {
+	database := setupTestDB(t)
+	_, err := database.Exec(context.Background(), `ALTER TABLE event_store ADD COLUMN tenant_id TEXT NOT NULL DEFAULT ''`)
+	require.NoError(t, err)
+	store, err := NewSQLEventStore(database, "event_store", WithTenantColumn("tenant_id"))
+	require.NoError(t, err)
+
+	acme, globex := tenantCtx(t, "acme"), tenantCtx(t, "globex")
+	require.NoError(t, store.AppendEvents(acme, 1, toStorableEvents([]eventing.Event[int64]{
+		makeEvent(1, "Order", "a-1", 1, nil),
+		makeEvent(1, "Order", "a-2", 2, nil),
+	}), 0))
+	require.NoError(t, store.AppendEvents(globex, 2, toStorableEvents([]eventing.Event[int64]{
+		makeEvent(2, "Order", "g-1", 1, nil),
+	}), 0))
+
+	var stored string
+	require.NoError(t, database.QueryRow(context.Background(), `SELECT tenant_id FROM event_store WHERE id = ?`, "g-1").Scan(&stored))
+	require.Equal(t, "globex", stored)
+
+	loaded, err := store.LoadEvents(acme, 1, 0)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	tenantID, _ := loaded[0].GetMetadata().Get(contextx.MetadataTenantKey)
+	require.Equal(t, "acme", tenantID, "tenant is also carried in event metadata")
+
+	loaded, err = store.LoadEvents(globex, 1, 0)
+	require.NoError(t, err)
+	require.Empty(t, loaded)
+
+	exists, err := store.HasAggregate(globex, 1)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	stream, err := store.StreamEvents(acme, &estore.StreamOptions{})
+	require.NoError(t, err)
+	require.Len(t, stream.Events, 2)
+
+	stream, err = store.StreamEvents(context.Background(), &estore.StreamOptions{})
+	require.NoError(t, err)
+	require.Len(t, stream.Events, 3, "no tenant in ctx reads across tenants")
+
+	// 另一租户向已有聚合追加时，全局版本检查以并发冲突拒绝。
+	err = store.AppendEvents(globex, 1, toStorableEvents([]eventing.Event[int64]{makeEvent(1, "Order", "g-2", 1, nil)}), 0)
+	require.True(t, errors.Is(err, errors.Concurrency))
+}
+
+// TestSQLEventStore_TenantColumn_RejectsMismatchedMetadata 验证事件 metadata 租户与 ctx 不一致时拒绝写入。
+func TestSQLEventStore_TenantColumn_RejectsMismatchedMetadata(t *testing.T) {
+	database := setupTestDB(t)
+	_, err := database.Exec(context.Background(), `ALTER TABLE event_store ADD COLUMN tenant_id TEXT NOT NULL DEFAULT ''`)
+	require.NoError(t, err)
+	store, err := NewSQLEventStore(database, "event_store", WithTenantColumn("tenant_id"))
+	require.NoError(t, err)
+
+	evt := makeEvent(1, "Order", "a-1", 1, nil)
+	evt.GetMetadata().Set(contextx.MetadataTenantKey, "globex")
+	err = store.AppendEvents(tenantCtx(t, "acme"), 1, toStorableEvents([]eventing.Event[int64]{evt}), 0)
+	require.True(t, errors.Is(err, errors.Forbidden))
+
+	_, err = NewSQLEventStore(database, "event_store", WithTenantColumn("tenant_id; DROP"))
+	require.True(t, errors.Is(err, errors.InvalidInput))
+}
@@ -0,0 +1,380 @@
+// Package storetest 提供事件存储实现的一致性（conformance）测试套件。
+//
+// 新的存储后端（Postgres、Mongo、Badger 等）只需提供创建空存储的工厂即可复用同一组契约用例：
+//
+//	func TestMyStore_Conformance(t *testing.T) {
+//	    storetest.RunConformanceTests(t, func(t *testing.T) store.IEventStreamStore[int64] {
+//	        return mystore.New(openTestDB(t))
+//	    })
+//	}
+//
+// 覆盖的契约：追加与加载、重复追加幂等、乐观并发冲突、聚合流分页、全局游标分页、类型过滤与时间过滤。
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/store"
+)
+
+// Factory 为每个用例创建一个全新的空事件存储。
+//
+// 用例之间不共享存储；需要清理的资源应通过 t.Cleanup 注册。
+type Factory[ID comparable] func(t *testing.T) store.IEventStreamStore[ID]
+
+// Option 调整一致性测试的运行方式。
+type Option[ID comparable] func(*suite[ID])
+
+// WithAggregateIDs 指定用例使用的聚合 ID 生成函数（n 从 1 开始，不同 n 必须生成不同 ID）。
+//
+// 默认支持 int64、int、uint64 与 string 类型的 ID；其他 ID 类型必须提供该选项。
+func WithAggregateIDs[ID comparable](fn func(n int) ID) Option[ID] {
+	return func(s *suite[ID]) {
+		s.aggregateID = fn
+	}
+}
+
+// WithAggregateType 指定用例事件使用的聚合类型（默认 "ConformanceAggregate"）。
+func WithAggregateType[ID comparable](aggregateType string) Option[ID] {
+	return func(s *suite[ID]) {
+		if aggregateType != "" {
+			s.aggregateType = aggregateType
+		}
+	}
+}
+
+type suite[ID comparable] struct {
+	factory       Factory[ID]
+	aggregateID   func(n int) ID
+	aggregateType string
+	// base 是用例事件时间戳的起点；截断到秒，避免后端时间精度差异影响时间过滤断言。
+	base time.Time
+}
+
+// RunConformanceTests 以子测试形式对 factory 创建的事件存储运行全部契约用例。
+func RunConformanceTests[ID comparable](t *testing.T, factory Factory[ID], opts ...Option[ID]) {
+	t.Helper()
+	if factory == nil {
+		t.Fatal("storetest: factory is nil")
+	}
+	s := &suite[ID]{
+		factory:       factory,
+		aggregateID:   defaultAggregateIDs[ID](),
+		aggregateType: "ConformanceAggregate",
+		base:          time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	if s.aggregateID == nil {
+		t.Fatalf("storetest: aggregate id type %T is not supported by default, use WithAggregateIDs", *new(ID))
+	}
+
+	t.Run("AppendAndLoad", s.testAppendAndLoad)
+	t.Run("AppendEmptyIsNoop", s.testAppendEmptyIsNoop)
+	t.Run("AppendIdempotency", s.testAppendIdempotency)
+	t.Run("OptimisticConcurrency", s.testOptimisticConcurrency)
+	t.Run("StreamAggregatePagination", s.testStreamAggregatePagination)
+	t.Run("CursorPagination", s.testCursorPagination)
+	t.Run("TypeFilters", s.testTypeFilters)
+	t.Run("TimeFilters", s.testTimeFilters)
+}
+
+// defaultAggregateIDs 返回常见 ID 类型的测试用聚合 ID 生成函数；不支持的类型返回 nil。
+func defaultAggregateIDs[ID comparable]() func(n int) ID {
+	var fn any
+	switch any(*new(ID)).(type) {
+	case int64:
+		fn = func(n int) int64 { return int64(n) }
+	case int:
+		fn = func(n int) int { return n }
+	case uint64:
+		fn = func(n int) uint64 { return uint64(n) }
+	case string:
+		fn = func(n int) string { return fmt.Sprintf("agg-%d", n) }
+	}
+	typed, _ := fn.(func(n int) ID)
+	return typed
+}
+
+// event 构造第 n 个聚合的第 version 个事件，时间戳为 base + offset 秒。
+func (s *suite[ID]) event(n int, eventType string, version uint64, offset int) *eventing.Event[ID] {
+	evt := eventing.NewEvent(s.aggregateID(n), s.aggregateType, eventType, version, map[string]any{"n": n, "version": version})
+	evt.ID = fmt.Sprintf("evt-%d-%d", n, version)
+	evt.Timestamp = s.base.Add(time.Duration(offset) * time.Second)
+	return evt
+}
+
+func storable[ID comparable](events ...*eventing.Event[ID]) []eventing.IStorableEvent[ID] {
+	out := make([]eventing.IStorableEvent[ID], len(events))
+	for i, evt := range events {
+		out[i] = evt
+	}
+	return out
+}
+
+func eventIDs[ID comparable](events []eventing.Event[ID]) []string {
+	ids := make([]string, len(events))
+	for i := range events {
+		ids[i] = events[i].GetID()
+	}
+	return ids
+}
+
+// seed 为聚合 n 追加 count 个事件（版本 1..count），事件类型按 types 轮换，时间戳从 offset 起逐秒递增。
+func (s *suite[ID]) seed(t *testing.T, es store.IEventStreamStore[ID], n, count, offset int, types ...string) {
+	t.Helper()
+	if len(types) == 0 {
+		types = []string{"Happened"}
+	}
+	events := make([]*eventing.Event[ID], count)
+	for i := range events {
+		events[i] = s.event(n, types[i%len(types)], uint64(i+1), offset+i)
+	}
+	require.NoError(t, es.AppendEvents(context.Background(), s.aggregateID(n), storable(events...), 0))
+}
+
+func (s *suite[ID]) testAppendAndLoad(t *testing.T) {
+	ctx := context.Background()
+	es := s.factory(t)
+	id := s.aggregateID(1)
+
+	exists, err := es.HasAggregate(ctx, id)
+	require.NoError(t, err)
+	require.False(t, exists, "new store must not report aggregate")
+
+	s.seed(t, es, 1, 3, 0)
+
+	loaded, err := es.LoadEvents(ctx, id, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"evt-1-1", "evt-1-2", "evt-1-3"}, eventIDs(loaded))
+	for i, evt := range loaded {
+		require.Equal(t, uint64(i+1), evt.GetVersion())
+		require.Equal(t, s.aggregateType, evt.GetAggregateType())
+		require.Equal(t, id, evt.GetAggregateID())
+	}
+
+	after, err := es.LoadEvents(ctx, id, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"evt-1-2", "evt-1-3"}, eventIDs(after), "afterVersion is exclusive")
+
+	byType, err := es.LoadEventsByType(ctx, s.aggregateType, id, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"evt-1-3"}, eventIDs(byType))
+
+	iter, err := es.LoadEventsIter(ctx, id, 0)
+	require.NoError(t, err)
+	var iterated []eventing.Event[ID]
+	for iter.Next() {
+		iterated = append(iterated, *iter.Event())
+	}
+	require.NoError(t, iter.Err())
+	require.NoError(t, iter.Close())
+	require.Equal(t, eventIDs(loaded), eventIDs(iterated), "LoadEventsIter must match LoadEvents")
+
+	exists, err = es.HasAggregate(ctx, id)
+	require.NoError(t, err)
+	require.True(t, exists)
+	version, err := es.GetAggregateVersion(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), version)
+	count, err := es.CountEvents(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), count)
+	head, err := es.HeadVersion(ctx, s.aggregateType, id)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), head)
+
+	missing := s.aggregateID(2)
+	version, err = es.GetAggregateVersion(ctx, missing)
+	require.NoError(t, err)
+	require.Zero(t, version, "unknown aggregate has version 0")
+	count, err = es.CountEvents(ctx, missing)
+	require.NoError(t, err)
+	require.Zero(t, count)
+	loaded, err = es.LoadEvents(ctx, missing, 0)
+	require.NoError(t, err)
+	require.Empty(t, loaded)
+}
+
+func (s *suite[ID]) testAppendEmptyIsNoop(t *testing.T) {
+	ctx := context.Background()
+	es := s.factory(t)
+	id := s.aggregateID(1)
+
+	require.NoError(t, es.AppendEvents(ctx, id, nil, 0))
+	require.NoError(t, es.AppendEvents(ctx, id, []eventing.IStorableEvent[ID]{}, 7))
+	exists, err := es.HasAggregate(ctx, id)
+	require.NoError(t, err)
+	require.False(t, exists, "empty append must not create aggregate")
+}
+
+// testAppendIdempotency 验证重试同一批事件不会产生重复：实现可以视为幂等成功，也可以返回 Concurrency。
+func (s *suite[ID]) testAppendIdempotency(t *testing.T) {
+	ctx := context.Background()
+	es := s.factory(t)
+	id := s.aggregateID(1)
+	batch := storable(s.event(1, "Happened", 1, 0), s.event(1, "Happened", 2, 1))
+
+	require.NoError(t, es.AppendEvents(ctx, id, batch, 0))
+	if err := es.AppendEvents(ctx, id, batch, 0); err != nil {
+		require.True(t, errors.Is(err, errors.Concurrency), "retried append must succeed or fail with Concurrency, got: %v", err)
+	}
+
+	loaded, err := es.LoadEvents(ctx, id, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"evt-1-1", "evt-1-2"}, eventIDs(loaded), "retried append must not duplicate events")
+	count, err := es.CountEvents(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), count)
+}
+
+func (s *suite[ID]) testOptimisticConcurrency(t *testing.T) {
+	ctx := context.Background()
+	es := s.factory(t)
+	id := s.aggregateID(1)
+	s.seed(t, es, 1, 2, 0)
+
+	stale := es.AppendEvents(ctx, id, storable(s.event(1, "Stale", 2, 10)), 1)
+	require.Error(t, stale, "stale expectedVersion must be rejected")
+	require.True(t, errors.Is(stale, errors.Concurrency), "stale expectedVersion must fail with Concurrency, got: %v", stale)
+
+	ahead := es.AppendEvents(ctx, id, storable(s.event(1, "Ahead", 6, 10)), 5)
+	require.Error(t, ahead, "expectedVersion ahead of stream must be rejected")
+	require.True(t, errors.Is(ahead, errors.Concurrency), "expectedVersion ahead of stream must fail with Concurrency, got: %v", ahead)
+
+	version, err := es.GetAggregateVersion(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), version, "rejected appends must not change version")
+
+	require.NoError(t, es.AppendEvents(ctx, id, storable(s.event(1, "Happened", 3, 2)), 2))
+	version, err = es.GetAggregateVersion(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), version)
+
+	// 不同聚合的版本互不影响。
+	require.NoError(t, es.AppendEvents(ctx, s.aggregateID(2), storable(s.event(2, "Happened", 1, 3)), 0))
+}
+
+func (s *suite[ID]) testStreamAggregatePagination(t *testing.T) {
+	ctx := context.Background()
+	es := s.factory(t)
+	id := s.aggregateID(1)
+	s.seed(t, es, 1, 5, 0)
+
+	var got []string
+	after := uint64(0)
+	for page := 0; ; page++ {
+		require.Less(t, page, 10, "StreamAggregate did not terminate")
+		result, err := es.StreamAggregate(ctx, &store.AggregateStreamOptions[ID]{
+			AggregateType: s.aggregateType,
+			AggregateID:   id,
+			AfterVersion:  after,
+			Limit:         2,
+		})
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(result.Events), 2)
+		got = append(got, eventIDs(result.Events)...)
+		if !result.HasMore {
+			break
+		}
+		require.Greater(t, result.NextVersion, after, "NextVersion must advance")
+		after = result.NextVersion
+	}
+	require.Equal(t, []string{"evt-1-1", "evt-1-2", "evt-1-3", "evt-1-4", "evt-1-5"}, got)
+}
+
+func (s *suite[ID]) testCursorPagination(t *testing.T) {
+	ctx := context.Background()
+	es := s.factory(t)
+	s.seed(t, es, 1, 3, 0)
+	s.seed(t, es, 2, 3, 10)
+
+	var got []string
+	cursor := ""
+	for page := 0; ; page++ {
+		require.Less(t, page, 10, "StreamEvents did not terminate")
+		result, err := es.StreamEvents(ctx, &store.StreamOptions{After: cursor, Limit: 2})
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(result.Events), 2)
+		got = append(got, eventIDs(result.Events)...)
+		if !result.HasMore {
+			break
+		}
+		require.NotEmpty(t, result.NextCursor, "HasMore requires NextCursor")
+		cursor = result.NextCursor
+	}
+	require.Equal(t, []string{"evt-1-1", "evt-1-2", "evt-1-3", "evt-2-1", "evt-2-2", "evt-2-3"}, got,
+		"cursor pagination must return every event once in (timestamp, id) order")
+
+	result, err := es.StreamEvents(ctx, &store.StreamOptions{Limit: 6})
+	require.NoError(t, err)
+	require.Len(t, result.Events, 6)
+	require.False(t, result.HasMore, "HasMore must be false when limit covers the stream")
+}
+
+func (s *suite[ID]) testTypeFilters(t *testing.T) {
+	ctx := context.Background()
+	es := s.factory(t)
+	s.seed(t, es, 1, 4, 0, "Created", "Updated")
+	other := s.event(2, "Created", 1, 10)
+	other.AggregateType = s.aggregateType + "Other"
+	require.NoError(t, es.AppendEvents(ctx, s.aggregateID(2), storable(other), 0))
+
+	result, err := es.StreamEvents(ctx, &store.StreamOptions{Types: []string{"Updated"}, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []string{"evt-1-2", "evt-1-4"}, eventIDs(result.Events))
+
+	result, err = es.StreamEvents(ctx, &store.StreamOptions{Types: []string{"Created"}, Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{"evt-1-1"}, eventIDs(result.Events))
+	require.True(t, result.HasMore, "HasMore must count only matching events")
+
+	result, err = es.StreamEvents(ctx, &store.StreamOptions{AggregateTypes: []string{other.AggregateType}, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []string{"evt-2-1"}, eventIDs(result.Events))
+
+	result, err = es.StreamEvents(ctx, &store.StreamOptions{Types: []string{"Missing"}, Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, result.Events)
+	require.False(t, result.HasMore)
+}
+
+func (s *suite[ID]) testTimeFilters(t *testing.T) {
+	ctx := context.Background()
+	es := s.factory(t)
+	for n := 1; n <= 5; n++ {
+		require.NoError(t, es.AppendEvents(ctx, s.aggregateID(n), storable(s.event(n, "Happened", 1, n)), 0))
+	}
+	at := func(offset int) time.Time { return s.base.Add(time.Duration(offset) * time.Second) }
+
+	result, err := es.StreamEvents(ctx, &store.StreamOptions{FromTime: at(3), Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []string{"evt-3-1", "evt-4-1", "evt-5-1"}, eventIDs(result.Events), "FromTime is inclusive")
+
+	result, err = es.StreamEvents(ctx, &store.StreamOptions{ToTime: at(2), Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []string{"evt-1-1", "evt-2-1"}, eventIDs(result.Events), "ToTime is inclusive")
+
+	result, err = es.StreamEvents(ctx, &store.StreamOptions{FromTime: at(2), ToTime: at(4), Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []string{"evt-2-1", "evt-3-1", "evt-4-1"}, eventIDs(result.Events))
+
+	result, err = es.StreamEvents(ctx, &store.StreamOptions{FromTime: at(2), ToTime: at(4), Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{"evt-2-1", "evt-3-1"}, eventIDs(result.Events))
+	require.True(t, result.HasMore)
+	result, err = es.StreamEvents(ctx, &store.StreamOptions{After: result.NextCursor, FromTime: at(2), ToTime: at(4), Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{"evt-4-1"}, eventIDs(result.Events), "cursor combines with time window")
+	require.False(t, result.HasMore)
+}
@@ -0,0 +1,14 @@
+package storetest
+
+import (
+	"testing"
+
+	"gochen/eventing/store"
+)
+
+// TestRunConformanceTests_MemoryEventStore 验证内存事件存储满足一致性契约。
+func TestRunConformanceTests_MemoryEventStore(t *testing.T) {
+	RunConformanceTests(t, func(*testing.T) store.IEventStreamStore[int64] {
+		return store.NewMemoryEventStreamStore()
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"sort"
 	"time"
 
+	"gochen/contextx"
 	"gochen/eventing"
 )
 
@@ -88,6 +89,10 @@ func FilterEventsWithOptions[ID comparable](events []eventing.Event[ID], opts *S
 				continue
 			}
 		}
+		if !metadataMatches(&evt, contextx.MetadataCorrelationKey, opts.CorrelationID) ||
+			!metadataMatches(&evt, contextx.MetadataCausationKey, opts.CausationID) {
+			continue
+		}
 		// 通过所有过滤条件后才计入匹配数量
 		if matched < limit {
 			result.Events = append(result.Events, evt)
@@ -105,3 +110,12 @@ func FilterEventsWithOptions[ID comparable](events []eventing.Event[ID], opts *S
 
 	return result
 }
+
+// metadataMatches 判断事件 metadata[key] 是否等于 want；want 为空表示不过滤。
+func metadataMatches[ID comparable](evt *eventing.Event[ID], key, want string) bool {
+	if want == "" {
+		return true
+	}
+	v, ok := evt.GetMetadata().Get(key)
+	return ok && v == want
+}
@@ -166,7 +166,7 @@ func upgradeEventPayloadFromJSONBytes[ID comparable](
 	if currentSchema >= targetSchema {
 		typed, err := reg.DeserializeWithUseNumber(evt.GetType(), trimmed)
 		if err == nil {
-			evt.Payload = messaging.NewPayload(typed)
+			evt.Payload = reg.NewPayload(evt.GetType(), typed)
 			return evt, nil
 		}
 	}
@@ -321,7 +321,7 @@ func upgradeEventPayloadFromMap[ID comparable](
 		}
 		return evt, derr
 	}
-	evt.Payload = messaging.NewPayload(typed)
+	evt.Payload = reg.NewPayload(evt.GetType(), typed)
 	if ver > 0 {
 		evt.SchemaVersion = ver
 	}
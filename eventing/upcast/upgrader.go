@@ -149,6 +149,20 @@ func UpgradeEventData(
 	return result, version, nil
 }
 
+// HasUpgradePath 判断已注册的升级器能否把 fromVersion 的事件数据逐级升级到 toVersion。
+func (r *UpgraderRegistry) HasUpgradePath(eventType string, fromVersion, toVersion int) bool {
+	list := r.snapshot(eventType)
+	version := fromVersion
+	for version < toVersion {
+		next := findNextUpgrader(list, version)
+		if next == nil || next.ToVersion() <= version {
+			return false
+		}
+		version = next.ToVersion()
+	}
+	return true
+}
+
 func (r *UpgraderRegistry) snapshot(eventType string) []IEventUpgrader {
 	if r == nil || r.inner == nil {
 		return nil
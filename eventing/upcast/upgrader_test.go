@@ -116,6 +116,31 @@ func TestUpgradeEventData_MissingUpgraderFails(t *testing.T) {
 	}
 }
 
+// TestUpgraderRegistry_HasUpgradePath 验证 HasUpgradePath 按升级器链判断可达性。
+func TestUpgraderRegistry_HasUpgradePath(t *testing.T) {
+	const eventType = "UpgraderPathEvent"
+	upgraders := NewUpgraderRegistry()
+	if err := upgraders.Register(eventType, upgradeV1ToV2{}); err != nil {
+		t.Fatalf("register upgrader v1->v2: %v", err)
+	}
+
+	if !upgraders.HasUpgradePath(eventType, 1, 2) {
+		t.Fatalf("expected path v1->v2")
+	}
+	if upgraders.HasUpgradePath(eventType, 1, 3) {
+		t.Fatalf("expected no path v1->v3 without v2->v3 upgrader")
+	}
+	if err := upgraders.Register(eventType, upgradeV2ToV3{}); err != nil {
+		t.Fatalf("register upgrader v2->v3: %v", err)
+	}
+	if !upgraders.HasUpgradePath(eventType, 1, 3) {
+		t.Fatalf("expected chained path v1->v3")
+	}
+	if upgraders.HasUpgradePath("Unknown", 1, 2) {
+		t.Fatalf("expected no path for unknown event type")
+	}
+}
+
 // TestUpgradeEventPayload_UpgradesAndHydratesFromJSONBytes 验证 UpgradeEventPayload UpgradesAndHydratesFromJSONBytes。
 func TestUpgradeEventPayload_UpgradesAndHydratesFromJSONBytes(t *testing.T) {
 	const eventType = "UpgraderHydrateEvent"
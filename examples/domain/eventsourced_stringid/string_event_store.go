@@ -87,6 +87,15 @@ func (m *StringMemoryEventStore) LoadEvents(ctx context.Context, aggregateID str
 	return res, nil
 }
 
+// LoadEventsIter 以迭代器视图返回 LoadEvents 的结果。
+func (m *StringMemoryEventStore) LoadEventsIter(ctx context.Context, aggregateID string, afterVersion uint64) (estore.IEventIterator[string], error) {
+	events, err := m.LoadEvents(ctx, aggregateID, afterVersion)
+	if err != nil {
+		return nil, err
+	}
+	return estore.NewSliceIterator(events), nil
+}
+
 // LoadEventsByType 在读取事件后额外按聚合类型做一次过滤。
 func (m *StringMemoryEventStore) LoadEventsByType(ctx context.Context, aggregateType string, aggregateID string, afterVersion uint64) ([]eventing.Event[string], error) {
 	events, err := m.LoadEvents(ctx, aggregateID, afterVersion)
@@ -124,6 +133,26 @@ func (m *StringMemoryEventStore) GetAggregateVersion(ctx context.Context, aggreg
 	return events[len(events)-1].GetVersion(), nil
 }
 
+// CountEvents 返回聚合事件流中的事件数量。
+func (m *StringMemoryEventStore) CountEvents(ctx context.Context, aggregateID string) (uint64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return uint64(len(m.events[aggregateID])), nil
+}
+
+// HeadVersion 返回指定聚合类型下聚合的最新版本号。
+func (m *StringMemoryEventStore) HeadVersion(ctx context.Context, aggregateType string, aggregateID string) (uint64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	events := m.events[aggregateID]
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].AggregateType == aggregateType {
+			return events[i].GetVersion(), nil
+		}
+	}
+	return 0, nil
+}
+
 // StreamEvents 用简单 offset 游标分页读取全局事件流。
 func (m *StringMemoryEventStore) StreamEvents(ctx context.Context, opts *estore.StreamOptions) (*estore.StreamResult[string], error) {
 	m.mu.RLock()
@@ -0,0 +1,47 @@
+// Command server 启动 gochen 参考服务。
+//
+// 配置来源：CONFIG_PATH 指向的文件，或当前目录下的 config.yaml；都不存在时只使用默认值与环境变量。
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	gconfig "gochen/config"
+	"gochen/examples/reference/internal/app"
+	"gochen/examples/reference/internal/config"
+	"gochen/host"
+)
+
+func main() {
+	log.SetPrefix("[reference] ")
+	ctx := context.Background()
+
+	cfg, err := config.Load(configPath())
+	if err != nil {
+		log.Fatal(err)
+	}
+	service, err := app.New(ctx, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer service.Close()
+
+	log.Printf("listening on :%d (transport=%s, database=%s)", cfg.Service.Port, cfg.Transport.Kind, cfg.Database.Driver)
+	if err := host.Run(ctx, service.HostOptions()...); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// configPath 解析配置文件路径；显式设置了 CONFIG_PATH 但文件不存在时直接失败。
+func configPath() string {
+	path, err := gconfig.ResolveConfigFilePath("CONFIG_PATH", "config.yaml")
+	if err != nil {
+		if os.Getenv("CONFIG_PATH") != "" {
+			log.Fatal(err)
+		}
+		return ""
+	}
+	return path
+}
@@ -0,0 +1,216 @@
+// Package app 是参考服务的组合根：按配置装配基础设施，并产出 host.Run 所需的选项。
+package app
+
+import (
+	"context"
+	"time"
+
+	"gochen/api/openapi"
+	"gochen/auth"
+	authhttp "gochen/auth/http"
+	"gochen/db"
+	"gochen/di"
+	dibasic "gochen/di/basic"
+	"gochen/errors"
+	ebus "gochen/eventing/bus"
+	"gochen/eventing/monitoring"
+	"gochen/eventing/outbox"
+	outboxmon "gochen/eventing/outbox/monitoring"
+	"gochen/eventing/projection"
+	"gochen/examples/reference/internal/checkout"
+	"gochen/examples/reference/internal/config"
+	"gochen/examples/reference/internal/inventory"
+	"gochen/examples/reference/internal/orders"
+	"gochen/examples/reference/internal/payments"
+	"gochen/examples/reference/internal/platform"
+	"gochen/examples/reference/internal/transport/kafka"
+	"gochen/host"
+	"gochen/httpx"
+	"gochen/httpx/middleware"
+	"gochen/httpx/nethttp"
+	"gochen/messaging"
+	"gochen/messaging/command"
+	mtransport "gochen/messaging/transport/memory"
+)
+
+// App 持有参考服务装配完成的基础设施。
+type App struct {
+	Config      *config.Config
+	Infra       *platform.Infra
+	Transport   messaging.ITransport
+	Bus         ebus.IEventBus
+	Projections *projection.ProjectionManager[int64]
+	Server      *nethttp.Server
+	Docs        *openapi.Builder
+	Container   di.IContainer
+}
+
+// New 打开数据库、创建传输层与事件溯源组件，并注册监控与 OpenAPI 路由。
+func New(ctx context.Context, cfg *config.Config) (*App, error) {
+	if cfg == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "config cannot be nil")
+	}
+	database, err := platform.OpenDatabase(ctx, cfg.Database)
+	if err != nil {
+		return nil, err
+	}
+	a, err := assemble(ctx, cfg, database)
+	if err != nil {
+		_ = database.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+func assemble(ctx context.Context, cfg *config.Config, database db.IDatabase) (*App, error) {
+	transport, err := newTransport(cfg.Transport)
+	if err != nil {
+		return nil, err
+	}
+	eventBus := ebus.NewEventBus(messaging.NewMessageBus(transport))
+
+	infra, err := platform.NewInfra(database, eventBus, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := infra.Checkpoints.CreateTable(ctx); err != nil {
+		return nil, err
+	}
+
+	registry, err := newMonitoring(database)
+	if err != nil {
+		return nil, err
+	}
+	infra.Snapshots.SetMetricsRecorder(registry.Metrics)
+
+	projections, err := projection.NewProjectionManager[int64](infra.EventStore, eventBus, infra.Events, infra.Upgraders)
+	if err != nil {
+		return nil, err
+	}
+	if projections, err = projections.WithCheckpointStore(infra.Checkpoints); err != nil {
+		return nil, err
+	}
+	projections.SetMetricsRecorder(registry.Metrics)
+
+	docs := openapi.NewBuilder(openapi.Info{
+		Title:       cfg.Service.Name,
+		Version:     "1.0.0",
+		Description: "gochen reference service: orders, inventory and payments coordinated by a checkout saga.",
+	})
+	server := nethttp.NewServer(&httpx.WebConfig{
+		Port:         cfg.Service.Port,
+		ReadTimeout:  httpx.DefaultReadTimeout,
+		WriteTimeout: httpx.DefaultWriteTimeout,
+		IdleTimeout:  httpx.DefaultIdleTimeout,
+	})
+	server.Use(middleware.ProblemDetails(middleware.ProblemDetailsConfig{IncludeDetails: true}))
+	// 文档挂载在根路径下，不经过 BasePath 上的鉴权中间件。
+	if err := openapi.Mount(server.Group(""), docs, &openapi.MountConfig{SpecPath: "/openapi.json", UIPath: "/docs"}); err != nil {
+		return nil, err
+	}
+
+	container := dibasic.New()
+	if err := di.RegisterInstance(container, cfg); err != nil {
+		return nil, err
+	}
+	if err := di.RegisterInstance(container, infra); err != nil {
+		return nil, err
+	}
+	if err := di.RegisterInstance(container, command.NewCommandExecutor()); err != nil {
+		return nil, err
+	}
+	if err := di.RegisterInstance(container, docs); err != nil {
+		return nil, err
+	}
+
+	return &App{
+		Config:      cfg,
+		Infra:       infra,
+		Transport:   transport,
+		Bus:         eventBus,
+		Projections: projections,
+		Server:      server,
+		Docs:        docs,
+		Container:   container,
+	}, nil
+}
+
+// HostOptions 返回运行参考服务所需的 host 选项。
+func (a *App) HostOptions() []host.Option {
+	return []host.Option{
+		host.WithName(a.Config.Service.Name),
+		host.WithPort(a.Config.Service.Port),
+		host.WithContainer(a.Container),
+		host.WithHTTPServer(a.Server),
+		host.WithTransport(a.Transport),
+		host.WithEventBus(a.Bus),
+		host.WithProjectionManager(a.Projections),
+		host.WithMetadataRegistry(a.Infra.Metadata),
+		host.WithRouteMiddlewares(authhttp.BearerAuthMiddleware(authhttp.StaticTokenResolver(a.principals()))),
+		host.WithFailFastOnRouteConflicts(true),
+		// 投影在模块启动时只订阅新事件；启动完成后从 checkpoint 补齐停机期间写入的事件。
+		host.WithAfterStart(a.Projections.ResumeAllFromCheckpoint),
+		host.WithModules(
+			orders.NewModule,
+			inventory.NewModule,
+			payments.NewModule,
+			checkout.NewModule,
+			platform.NewModule,
+		),
+	}
+}
+
+// Close 释放数据库连接。
+func (a *App) Close() error {
+	if a == nil || a.Infra == nil {
+		return nil
+	}
+	return a.Infra.DB.Close()
+}
+
+// principals 返回静态 token 对应的主体：admin 拥有全部权限，viewer 只读。
+func (a *App) principals() map[string]auth.Principal {
+	viewer := []string{
+		orders.Permissions.Code(auth.PermissionActionRead),
+		inventory.Permissions.Code(auth.PermissionActionRead),
+		payments.Permissions.Code(auth.PermissionActionRead),
+	}
+	return map[string]auth.Principal{
+		a.Config.Auth.AdminToken:  {SubjectID: 1, Roles: []string{"admin"}, Permissions: []string{"*:*:*"}},
+		a.Config.Auth.ViewerToken: {SubjectID: 2, Roles: []string{"viewer"}, Permissions: viewer},
+	}
+}
+
+func newTransport(cfg config.TransportConfig) (messaging.ITransport, error) {
+	if cfg.Kind == "kafka" {
+		return kafka.NewTransport(kafka.Config{
+			Brokers: cfg.Brokers,
+			Topic:   cfg.Topic,
+			GroupID: cfg.GroupID,
+		})
+	}
+	return mtransport.NewMemoryTransport(1024, 4), nil
+}
+
+// newMonitoring 构造监控注册表并设为默认：接入 Outbox 积压指标与数据库连通性检查。
+func newMonitoring(database db.IDatabase) (*monitoring.Registry, error) {
+	provider, err := outboxmon.NewProvider(outbox.NewMetricsCollector(database))
+	if err != nil {
+		return nil, err
+	}
+	registry, err := monitoring.NewRegistry(monitoring.WithOutboxMetricsProvider(provider))
+	if err != nil {
+		return nil, err
+	}
+	dbCheck, err := monitoring.PingHealthCheck(database, 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if err := registry.Health.Register("database", dbCheck); err != nil {
+		return nil, err
+	}
+	if err := monitoring.SetDefaultRegistry(registry); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
@@ -0,0 +1,110 @@
+package checkout
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/db"
+	"gochen/db/sql/stdsql"
+	"gochen/errors"
+	ebus "gochen/eventing/bus"
+	"gochen/examples/reference/internal/config"
+	"gochen/examples/reference/internal/inventory"
+	"gochen/examples/reference/internal/orders"
+	"gochen/examples/reference/internal/payments"
+	"gochen/examples/reference/internal/platform"
+	"gochen/messaging"
+	"gochen/messaging/command"
+	mtransport "gochen/messaging/transport/memory"
+)
+
+type fixture struct {
+	checkout  *Service
+	inventory *inventory.Service
+	payments  *payments.Service
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	ctx := context.Background()
+
+	database, err := stdsql.New(db.DBConfig{Driver: "sqlite", Database: filepath.Join(t.TempDir(), "checkout.db")})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = database.Close() })
+	require.NoError(t, platform.EnsureSchema(ctx, database, "sqlite"))
+
+	cfg := &config.Config{
+		Snapshot: config.SnapshotConfig{Frequency: 20},
+		Payments: config.PaymentsConfig{AuthorizationLimit: 1000},
+	}
+	eventBus := ebus.NewEventBus(messaging.NewMessageBus(mtransport.NewMemoryTransport(16, 1)))
+	infra, err := platform.NewInfra(database, eventBus, cfg)
+	require.NoError(t, err)
+
+	executor := command.NewCommandExecutor()
+	orderService, err := orders.NewService(infra, executor)
+	require.NoError(t, err)
+	inventoryService, err := inventory.NewService(infra, executor)
+	require.NoError(t, err)
+	paymentService, err := payments.NewService(infra, executor, cfg)
+	require.NoError(t, err)
+
+	return &fixture{
+		checkout:  NewService(orderService, inventoryService, paymentService, executor, eventBus),
+		inventory: inventoryService,
+		payments:  paymentService,
+	}
+}
+
+// TestCheckout_Confirmed 验证库存充足且金额未超限时订单被确认，库存与支付状态同步更新。
+func TestCheckout_Confirmed(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	require.NoError(t, f.inventory.Add(ctx, 7, 5))
+
+	result, err := f.checkout.Checkout(ctx, Request{CustomerID: 1, ProductID: 7, Quantity: 2, Amount: 500})
+	require.NoError(t, err)
+	require.Equal(t, orders.StatusConfirmed, result.Status)
+
+	stock, err := f.inventory.Get(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, 3, stock.Available)
+	require.Equal(t, 2, stock.Reserved)
+
+	payment, err := f.payments.Get(ctx, result.OrderID)
+	require.NoError(t, err)
+	require.Equal(t, payments.StatusAuthorized, payment.Status)
+}
+
+// TestCheckout_PaymentDeclinedReleasesStock 验证支付被拒时 Saga 补偿释放已预留的库存。
+func TestCheckout_PaymentDeclinedReleasesStock(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	require.NoError(t, f.inventory.Add(ctx, 7, 5))
+
+	_, err := f.checkout.Checkout(ctx, Request{CustomerID: 1, ProductID: 7, Quantity: 2, Amount: 5000})
+	require.Error(t, err)
+	require.True(t, errors.Is(err, errors.Conflict))
+
+	stock, err := f.inventory.Get(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, 5, stock.Available)
+	require.Zero(t, stock.Reserved)
+}
+
+// TestCheckout_InsufficientStock 验证库存不足时不会发起支付授权。
+func TestCheckout_InsufficientStock(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	require.NoError(t, f.inventory.Add(ctx, 7, 1))
+
+	_, err := f.checkout.Checkout(ctx, Request{CustomerID: 1, ProductID: 7, Quantity: 2, Amount: 500})
+	require.Error(t, err)
+
+	stock, err := f.inventory.Get(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, 1, stock.Available)
+}
@@ -0,0 +1,110 @@
+package checkout
+
+import (
+	"context"
+	"net/http"
+
+	"gochen/api/openapi"
+	"gochen/auth"
+	"gochen/errors"
+	"gochen/eventing/bus"
+	"gochen/examples/reference/internal/inventory"
+	"gochen/examples/reference/internal/orders"
+	"gochen/examples/reference/internal/payments"
+	"gochen/examples/reference/internal/platform"
+	"gochen/host"
+	"gochen/httpx"
+	"gochen/ident/snowflake"
+	"gochen/messaging/command"
+	"gochen/process/saga"
+)
+
+// ModuleID 结账模块标识，路由挂载在 /api/v1/checkout 下。
+const ModuleID = "checkout"
+
+// Result 结账结果。
+type Result struct {
+	OrderID int64  `json:"order_id"`
+	Status  string `json:"status"`
+}
+
+// Service 负责创建订单并执行结账 Saga。
+type Service struct {
+	orders       *orders.Service
+	orchestrator *saga.SagaOrchestrator
+}
+
+// NewService 创建结账服务。
+//
+// 显式依赖库存与支付服务，确保它们的 Saga 命令处理器在结账前已注册到命令执行器。
+func NewService(
+	orderService *orders.Service,
+	_ *inventory.Service,
+	_ *payments.Service,
+	executor *command.CommandExecutor,
+	eventBus bus.IEventBus,
+) *Service {
+	return &Service{
+		orders:       orderService,
+		orchestrator: saga.NewSagaOrchestrator(executor, eventBus, saga.NewMemorySagaStateStore()),
+	}
+}
+
+// Checkout 下单并同步执行结账 Saga；Saga 失败时订单已被取消，错误中携带订单 ID。
+func (s *Service) Checkout(ctx context.Context, req Request) (*Result, error) {
+	orderID, err := snowflake.NextID()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.orders.Place(ctx, &orders.PlaceOrder{
+		OrderID:    orderID,
+		CustomerID: req.CustomerID,
+		ProductID:  req.ProductID,
+		Quantity:   req.Quantity,
+		Amount:     req.Amount,
+	}); err != nil {
+		return nil, err
+	}
+	if err := s.orchestrator.Execute(ctx, &checkoutSaga{orderID: orderID, req: req, orders: s.orders}); err != nil {
+		return nil, errors.Wrap(err, errors.Conflict, "checkout failed").WithContext("order_id", orderID)
+	}
+	return &Result{OrderID: orderID, Status: orders.StatusConfirmed}, nil
+}
+
+// Routes 结账接口。
+type Routes struct {
+	service *Service
+	docs    *openapi.Builder
+}
+
+// NewRoutes 创建结账路由注册器。
+func NewRoutes(service *Service, docs *openapi.Builder) *Routes {
+	return &Routes{service: service, docs: docs}
+}
+
+// RegisterRoutes 注册下单接口；需要订单写权限。
+func (r *Routes) RegisterRoutes(group httpx.IRouteGroup) error {
+	router := platform.NewRouter(group, r.docs, ModuleID).Require(orders.Permissions.Must(auth.PermissionActionWrite))
+	return router.POST("", router.Operation("Place an order and run the checkout saga", &Request{}, http.StatusCreated, &Result{}), r.checkout)
+}
+
+func (r *Routes) checkout(ctx httpx.IContext) error {
+	var req Request
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return err
+	}
+	result, err := r.service.Checkout(ctx.RequestContext(), req)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusCreated, httpx.JSONValue(result))
+}
+
+// NewModule 创建结账模块。
+func NewModule() (host.IModule, error) {
+	return host.Module(ModuleID).
+		Name("Checkout").
+		Provide(NewService).
+		RouteRegistrar(NewRoutes).
+		Build()
+}
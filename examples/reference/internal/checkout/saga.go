@@ -0,0 +1,76 @@
+// Package checkout 编排跨订单、库存、支付三个模块的下单 Saga。
+//
+// 流程：下单 -> 预留库存 -> 授权支付 -> 确认订单；任一步骤失败时按逆序执行补偿
+// （退款、释放库存），最后取消订单。
+package checkout
+
+import (
+	"context"
+	"strconv"
+
+	"gochen/examples/reference/internal/inventory"
+	"gochen/examples/reference/internal/orders"
+	"gochen/examples/reference/internal/payments"
+	"gochen/ident/uuid"
+	"gochen/messaging/command"
+	"gochen/process/saga"
+)
+
+// Request 下单请求。
+type Request struct {
+	CustomerID int64 `json:"customer_id"`
+	ProductID  int64 `json:"product_id"`
+	Quantity   int   `json:"quantity"`
+	Amount     int64 `json:"amount"`
+}
+
+// checkoutSaga 描述单个订单的结账流程。
+type checkoutSaga struct {
+	saga.BaseSaga
+
+	orderID int64
+	req     Request
+	orders  *orders.Service
+}
+
+var _ saga.ISaga = (*checkoutSaga)(nil)
+
+// ID 返回 Saga 标识；同一订单只会有一个结账流程。
+func (s *checkoutSaga) ID() string { return "checkout-" + strconv.FormatInt(s.orderID, 10) }
+
+// Steps 返回结账步骤。
+func (s *checkoutSaga) Steps() []*saga.SagaStep {
+	orderAggregateID := strconv.FormatInt(s.orderID, 10)
+	productAggregateID := strconv.FormatInt(s.req.ProductID, 10)
+	return []*saga.SagaStep{
+		saga.NewSagaStep("reserve-stock", commandFor(inventory.CommandReserve, productAggregateID, "stock",
+			&inventory.ReserveStock{ProductID: s.req.ProductID, OrderID: s.orderID, Quantity: s.req.Quantity})).
+			WithCompensation(commandFor(inventory.CommandRelease, productAggregateID, "stock",
+				&inventory.ReleaseStock{ProductID: s.req.ProductID, OrderID: s.orderID})),
+		saga.NewSagaStep("authorize-payment", commandFor(payments.CommandAuthorize, orderAggregateID, "payment",
+			&payments.AuthorizePayment{OrderID: s.orderID, Amount: s.req.Amount})).
+			WithCompensation(commandFor(payments.CommandRefund, orderAggregateID, "payment",
+				&payments.RefundPayment{OrderID: s.orderID})),
+		saga.NewSagaStep("confirm-order", commandFor(orders.CommandConfirm, orderAggregateID, "order",
+			&orders.ConfirmOrder{OrderID: s.orderID})),
+	}
+}
+
+// OnFailed 在补偿完成后取消订单，并记录失败原因。
+func (s *checkoutSaga) OnFailed(ctx context.Context, err error) error {
+	reason := "checkout failed"
+	if err != nil {
+		reason = err.Error()
+	}
+	return s.orders.Cancel(ctx, s.orderID, reason)
+}
+
+func commandFor(commandType, aggregateID, aggregateType string, payload any) saga.CommandFunc {
+	return func(ctx context.Context) (*command.Command, error) {
+		id, err := uuid.New()
+		if err != nil {
+			return nil, err
+		}
+		return command.NewCommand(id, commandType, aggregateID, aggregateType, payload), nil
+	}
+}
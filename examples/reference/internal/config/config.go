@@ -0,0 +1,74 @@
+// Package config 定义参考服务的配置结构。
+//
+// 加载顺序：config.yaml -> default tag -> 环境变量（按 yaml 路径推导，如 DATABASE_DRIVER）-> validate tag。
+package config
+
+import (
+	"time"
+
+	gconfig "gochen/config"
+)
+
+// Config 是参考服务的完整配置。
+type Config struct {
+	Service   ServiceConfig   `yaml:"service"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Transport TransportConfig `yaml:"transport"`
+	Outbox    OutboxConfig    `yaml:"outbox"`
+	Snapshot  SnapshotConfig  `yaml:"snapshot"`
+	Payments  PaymentsConfig  `yaml:"payments"`
+	Auth      AuthConfig      `yaml:"auth"`
+}
+
+// ServiceConfig 定义 HTTP 服务配置。
+type ServiceConfig struct {
+	Name string `yaml:"name" default:"reference-service" validate:"required"`
+	Port int    `yaml:"port" default:"8080" validate:"min=1,max=65535"`
+}
+
+// DatabaseConfig 定义事件存储、Outbox、快照与读模型共用的数据库。
+type DatabaseConfig struct {
+	// Driver 为 database/sql 驱动名：sqlite（默认，本地开发）或 postgres。
+	Driver string `yaml:"driver" default:"sqlite" validate:"oneof=sqlite postgres"`
+	DSN    string `yaml:"dsn" default:"file:reference.db?_pragma=busy_timeout(5000)" validate:"required"`
+
+	MaxOpenConns int `yaml:"max_open_conns" default:"10"`
+}
+
+// TransportConfig 定义消息传输层。
+type TransportConfig struct {
+	// Kind 为 memory（单进程）或 kafka。
+	Kind    string   `yaml:"kind" default:"memory" validate:"oneof=memory kafka"`
+	Brokers []string `yaml:"brokers" default:"localhost:9092"`
+	Topic   string   `yaml:"topic" default:"reference.events"`
+	GroupID string   `yaml:"group_id" default:"reference-service"`
+}
+
+// OutboxConfig 定义 Outbox 轮询发布配置。
+type OutboxConfig struct {
+	PublishInterval time.Duration `yaml:"publish_interval" default:"1s"`
+	BatchSize       int           `yaml:"batch_size" default:"100" validate:"min=1"`
+}
+
+// SnapshotConfig 定义聚合快照策略。
+type SnapshotConfig struct {
+	// Frequency 表示每 N 个事件创建一次快照。
+	Frequency int `yaml:"frequency" default:"20" validate:"min=1"`
+}
+
+// PaymentsConfig 定义模拟支付网关的授权规则。
+type PaymentsConfig struct {
+	// AuthorizationLimit 单笔授权上限（分）；超过上限的支付会被拒绝，用于演示 Saga 补偿。
+	AuthorizationLimit int64 `yaml:"authorization_limit" default:"100000" validate:"min=1"`
+}
+
+// AuthConfig 定义静态 bearer token；生产环境应替换为 JWT/OIDC 等 TokenResolver。
+type AuthConfig struct {
+	AdminToken  string `yaml:"admin_token" default:"dev-admin-token" validate:"required,nospace"`
+	ViewerToken string `yaml:"viewer_token" default:"dev-viewer-token" validate:"required,nospace"`
+}
+
+// Load 从 path 加载配置；path 为空时仅使用默认值与环境变量。
+func Load(path string) (*Config, error) {
+	return gconfig.LoadStruct[Config](path)
+}
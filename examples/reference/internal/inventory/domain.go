@@ -0,0 +1,145 @@
+// Package inventory 实现参考服务的库存模块：按商品维护可用库存与订单预留。
+package inventory
+
+import (
+	deventsourced "gochen/domain/eventsourced"
+	"gochen/errors"
+	"gochen/eventing/registry"
+)
+
+// StockAdded 表示商品入库。
+type StockAdded struct {
+	Quantity int `json:"quantity"`
+}
+
+// EventType 返回事件类型标识。
+func (e *StockAdded) EventType() string { return "StockAdded" }
+
+// StockReserved 表示为订单预留库存。
+type StockReserved struct {
+	OrderID  int64 `json:"order_id"`
+	Quantity int   `json:"quantity"`
+}
+
+// EventType 返回事件类型标识。
+func (e *StockReserved) EventType() string { return "StockReserved" }
+
+// StockReleased 表示释放订单预留的库存（Saga 补偿）。
+type StockReleased struct {
+	OrderID  int64 `json:"order_id"`
+	Quantity int   `json:"quantity"`
+}
+
+// EventType 返回事件类型标识。
+func (e *StockReleased) EventType() string { return "StockReleased" }
+
+// RegisterEvents 把库存事件注册到事件注册表。
+func RegisterEvents(reg *registry.Registry) error {
+	if err := reg.Register("StockAdded", func() any { return &StockAdded{} }); err != nil {
+		return err
+	}
+	if err := reg.Register("StockReserved", func() any { return &StockReserved{} }); err != nil {
+		return err
+	}
+	return reg.Register("StockReleased", func() any { return &StockReleased{} })
+}
+
+// Stock 是以商品 ID 为聚合 ID 的库存聚合。
+type Stock struct {
+	*deventsourced.EventSourcedAggregate[int64] `aggregate:"stock"`
+
+	Available    int           `json:"available"`
+	Reservations map[int64]int `json:"reservations"`
+}
+
+// NewStock 创建空库存聚合。
+func NewStock(metadata *deventsourced.MetadataRegistry, productID int64) (*Stock, error) {
+	stock, err := deventsourced.New[Stock, int64](metadata, productID)
+	if err != nil {
+		return nil, err
+	}
+	stock.Reservations = make(map[int64]int)
+	return stock, nil
+}
+
+// Add 入库。
+func (s *Stock) Add(quantity int) error {
+	if quantity <= 0 {
+		return errors.NewCode(errors.Validation, "quantity must be positive")
+	}
+	return s.ApplyAndRecord(&StockAdded{Quantity: quantity})
+}
+
+// Reserve 为订单预留库存；同一订单重复预留视为成功。
+func (s *Stock) Reserve(orderID int64, quantity int) error {
+	if _, ok := s.Reservations[orderID]; ok {
+		return nil
+	}
+	if quantity <= 0 {
+		return errors.NewCode(errors.Validation, "quantity must be positive")
+	}
+	if s.Available < quantity {
+		return errors.NewCode(errors.Conflict, "insufficient stock").
+			WithContext("product_id", s.GetID()).
+			WithContext("available", s.Available).
+			WithContext("requested", quantity)
+	}
+	return s.ApplyAndRecord(&StockReserved{OrderID: orderID, Quantity: quantity})
+}
+
+// Release 释放订单预留；不存在的预留直接忽略，便于补偿重试。
+func (s *Stock) Release(orderID int64) error {
+	quantity, ok := s.Reservations[orderID]
+	if !ok {
+		return nil
+	}
+	return s.ApplyAndRecord(&StockReleased{OrderID: orderID, Quantity: quantity})
+}
+
+// ApplyStockAdded 应用入库事件。
+func (s *Stock) ApplyStockAdded(e *StockAdded) {
+	s.Available += e.Quantity
+}
+
+// ApplyStockReserved 应用预留事件。
+func (s *Stock) ApplyStockReserved(e *StockReserved) {
+	if s.Reservations == nil {
+		s.Reservations = make(map[int64]int)
+	}
+	s.Available -= e.Quantity
+	s.Reservations[e.OrderID] = e.Quantity
+}
+
+// ApplyStockReleased 应用释放事件。
+func (s *Stock) ApplyStockReleased(e *StockReleased) {
+	s.Available += e.Quantity
+	delete(s.Reservations, e.OrderID)
+}
+
+// AddStock 入库命令。
+type AddStock struct {
+	ProductID int64 `json:"-"`
+	Quantity  int   `json:"quantity"`
+}
+
+// AggregateID 返回目标商品 ID。
+func (c *AddStock) AggregateID() int64 { return c.ProductID }
+
+// ReserveStock 预留库存命令。
+type ReserveStock struct {
+	ProductID int64 `json:"product_id"`
+	OrderID   int64 `json:"order_id"`
+	Quantity  int   `json:"quantity"`
+}
+
+// AggregateID 返回目标商品 ID。
+func (c *ReserveStock) AggregateID() int64 { return c.ProductID }
+
+// ReleaseStock 释放预留命令。
+type ReleaseStock struct {
+	ProductID int64 `json:"product_id"`
+	OrderID   int64 `json:"order_id"`
+}
+
+// AggregateID 返回目标商品 ID。
+func (c *ReleaseStock) AggregateID() int64 { return c.ProductID }
@@ -0,0 +1,82 @@
+package inventory
+
+import (
+	"net/http"
+
+	"gochen/api/openapi"
+	"gochen/auth"
+	"gochen/examples/reference/internal/platform"
+	"gochen/host"
+	"gochen/httpx"
+)
+
+// ModuleID 库存模块标识，路由挂载在 /api/v1/inventory 下。
+const ModuleID = "inventory"
+
+// Permissions 库存模块的 API 权限集合。
+var Permissions = auth.NewAPIPermissionSet(ModuleID, auth.PermissionActionRead, auth.PermissionActionWrite)
+
+// Routes 库存接口。
+type Routes struct {
+	service *Service
+	docs    *openapi.Builder
+}
+
+// NewRoutes 创建库存路由注册器。
+func NewRoutes(service *Service, docs *openapi.Builder) *Routes {
+	return &Routes{service: service, docs: docs}
+}
+
+// RegisterRoutes 注册入库与库存查询接口。
+func (r *Routes) RegisterRoutes(group httpx.IRouteGroup) error {
+	router := platform.NewRouter(group, r.docs, ModuleID)
+	reads := router.Require(Permissions.Must(auth.PermissionActionRead))
+	writes := router.Require(Permissions.Must(auth.PermissionActionWrite))
+
+	if err := reads.GET("/:id", router.Operation("Get product stock", nil, http.StatusOK, &View{}), r.get); err != nil {
+		return err
+	}
+	return writes.POST("/:id/stock", router.Operation("Add product stock", &AddStock{}, http.StatusOK, &View{}), r.add)
+}
+
+func (r *Routes) get(ctx httpx.IContext) error {
+	productID, err := platform.ParseID(ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+	view, err := r.service.Get(ctx.RequestContext(), productID)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, httpx.JSONValue(view))
+}
+
+func (r *Routes) add(ctx httpx.IContext) error {
+	productID, err := platform.ParseID(ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+	var req AddStock
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return err
+	}
+	if err := r.service.Add(ctx.RequestContext(), productID, req.Quantity); err != nil {
+		return err
+	}
+	view, err := r.service.Get(ctx.RequestContext(), productID)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, httpx.JSONValue(view))
+}
+
+// NewModule 创建库存模块。
+func NewModule() (host.IModule, error) {
+	return host.Module(ModuleID).
+		Name("Inventory").
+		Aggregate(host.AggregateFromTag(&Stock{})).
+		Provide(NewService).
+		RouteRegistrar(NewRoutes).
+		PermissionDefinitions(Permissions.Definitions()...).
+		Build()
+}
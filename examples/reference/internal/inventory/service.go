@@ -0,0 +1,89 @@
+package inventory
+
+import (
+	"context"
+
+	"gochen/app/eventsourced"
+	"gochen/examples/reference/internal/platform"
+	"gochen/messaging/command"
+)
+
+// Saga 使用的库存命令类型。
+const (
+	CommandReserve = "inventory.reserve"
+	CommandRelease = "inventory.release"
+)
+
+// View 是库存查询结果。
+type View struct {
+	ProductID int64 `json:"product_id"`
+	Available int   `json:"available"`
+	Reserved  int   `json:"reserved"`
+	Version   int64 `json:"version"`
+}
+
+// Service 库存写侧服务，并向共享命令执行器注册预留/释放命令。
+type Service struct {
+	repo     *eventsourced.EventSourcedRepository[*Stock, int64]
+	commands *eventsourced.EventSourcedService[*Stock, int64]
+}
+
+// NewService 装配库存聚合的仓储与命令服务，并注册 Saga 命令处理器。
+func NewService(infra *platform.Infra, executor *command.CommandExecutor) (*Service, error) {
+	if err := RegisterEvents(infra.Events); err != nil {
+		return nil, err
+	}
+	repo, commands, err := platform.NewService(infra, &Stock{}, func(id int64) (*Stock, error) {
+		return NewStock(infra.Metadata, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s := &Service{repo: repo, commands: commands}
+	if err := s.registerHandlers(executor); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Add 为商品入库。
+func (s *Service) Add(ctx context.Context, productID int64, quantity int) error {
+	return s.commands.ExecuteCommand(ctx, &AddStock{ProductID: productID, Quantity: quantity})
+}
+
+// Get 返回商品库存；商品从未入库时返回 NotFound。
+func (s *Service) Get(ctx context.Context, productID int64) (*View, error) {
+	stock, err := s.repo.Get(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	view := &View{ProductID: productID, Available: stock.Available, Version: int64(stock.GetVersion())}
+	for _, quantity := range stock.Reservations {
+		view.Reserved += quantity
+	}
+	return view, nil
+}
+
+func (s *Service) registerHandlers(executor *command.CommandExecutor) error {
+	if err := s.commands.RegisterCommandHandler(&AddStock{}, func(ctx context.Context, cmd eventsourced.IEventSourcedCommand[int64], stock *Stock) error {
+		return stock.Add(cmd.(*AddStock).Quantity)
+	}); err != nil {
+		return err
+	}
+	if err := s.commands.RegisterCommandHandler(&ReserveStock{}, func(ctx context.Context, cmd eventsourced.IEventSourcedCommand[int64], stock *Stock) error {
+		c := cmd.(*ReserveStock)
+		return stock.Reserve(c.OrderID, c.Quantity)
+	}); err != nil {
+		return err
+	}
+	if err := s.commands.RegisterCommandHandler(&ReleaseStock{}, func(ctx context.Context, cmd eventsourced.IEventSourcedCommand[int64], stock *Stock) error {
+		return stock.Release(cmd.(*ReleaseStock).OrderID)
+	}); err != nil {
+		return err
+	}
+
+	if err := platform.HandleCommand[*ReserveStock](executor, CommandReserve, s.commands.ExecuteCommand); err != nil {
+		return err
+	}
+	return platform.HandleCommand[*ReleaseStock](executor, CommandRelease, s.commands.ExecuteCommand)
+}
@@ -0,0 +1,153 @@
+// Package orders 实现参考服务的订单模块：事件溯源聚合、订单摘要投影与查询接口。
+package orders
+
+import (
+	deventsourced "gochen/domain/eventsourced"
+	"gochen/errors"
+	"gochen/eventing/registry"
+)
+
+// 订单状态。
+const (
+	StatusPending   = "pending"
+	StatusConfirmed = "confirmed"
+	StatusCancelled = "cancelled"
+)
+
+// OrderPlaced 表示订单已创建，等待库存与支付确认。
+type OrderPlaced struct {
+	CustomerID int64 `json:"customer_id"`
+	ProductID  int64 `json:"product_id"`
+	Quantity   int   `json:"quantity"`
+	Amount     int64 `json:"amount"`
+}
+
+// EventType 返回事件类型标识。
+func (e *OrderPlaced) EventType() string { return "OrderPlaced" }
+
+// OrderConfirmed 表示库存预留与支付授权均已完成。
+type OrderConfirmed struct{}
+
+// EventType 返回事件类型标识。
+func (e *OrderConfirmed) EventType() string { return "OrderConfirmed" }
+
+// OrderCancelled 表示订单因下游步骤失败或人工取消而关闭。
+type OrderCancelled struct {
+	Reason string `json:"reason"`
+}
+
+// EventType 返回事件类型标识。
+func (e *OrderCancelled) EventType() string { return "OrderCancelled" }
+
+// RegisterEvents 把订单事件注册到事件注册表，供存储回放与 Outbox 反序列化使用。
+func RegisterEvents(reg *registry.Registry) error {
+	if err := reg.Register("OrderPlaced", func() any { return &OrderPlaced{} }); err != nil {
+		return err
+	}
+	if err := reg.Register("OrderConfirmed", func() any { return &OrderConfirmed{} }); err != nil {
+		return err
+	}
+	return reg.Register("OrderCancelled", func() any { return &OrderCancelled{} })
+}
+
+// Order 是事件溯源的订单聚合。
+type Order struct {
+	*deventsourced.EventSourcedAggregate[int64] `aggregate:"order"`
+
+	CustomerID int64
+	ProductID  int64
+	Quantity   int
+	Amount     int64
+	Status     string
+}
+
+// NewOrder 创建空订单聚合，用于回放或首次下单。
+func NewOrder(metadata *deventsourced.MetadataRegistry, id int64) (*Order, error) {
+	return deventsourced.New[Order, int64](metadata, id)
+}
+
+// Place 下单；同一订单 ID 只能下单一次。
+func (o *Order) Place(customerID, productID int64, quantity int, amount int64) error {
+	if o.GetVersion() != 0 {
+		return errors.NewCode(errors.Conflict, "order already placed").WithContext("order_id", o.GetID())
+	}
+	if customerID <= 0 || productID <= 0 {
+		return errors.NewCode(errors.Validation, "customer_id and product_id are required")
+	}
+	if quantity <= 0 || amount <= 0 {
+		return errors.NewCode(errors.Validation, "quantity and amount must be positive")
+	}
+	return o.ApplyAndRecord(&OrderPlaced{CustomerID: customerID, ProductID: productID, Quantity: quantity, Amount: amount})
+}
+
+// Confirm 确认订单；仅待处理订单可确认。
+func (o *Order) Confirm() error {
+	if o.Status != StatusPending {
+		return errors.NewCode(errors.Conflict, "order is not pending").
+			WithContext("order_id", o.GetID()).
+			WithContext("status", o.Status)
+	}
+	return o.ApplyAndRecord(&OrderConfirmed{})
+}
+
+// Cancel 取消订单；已取消的订单重复取消视为成功，便于 Saga 补偿重试。
+func (o *Order) Cancel(reason string) error {
+	switch o.Status {
+	case StatusCancelled:
+		return nil
+	case StatusPending:
+		return o.ApplyAndRecord(&OrderCancelled{Reason: reason})
+	default:
+		return errors.NewCode(errors.Conflict, "order cannot be cancelled").
+			WithContext("order_id", o.GetID()).
+			WithContext("status", o.Status)
+	}
+}
+
+// ApplyOrderPlaced 应用下单事件。
+func (o *Order) ApplyOrderPlaced(e *OrderPlaced) {
+	o.CustomerID = e.CustomerID
+	o.ProductID = e.ProductID
+	o.Quantity = e.Quantity
+	o.Amount = e.Amount
+	o.Status = StatusPending
+}
+
+// ApplyOrderConfirmed 应用订单确认事件。
+func (o *Order) ApplyOrderConfirmed(_ *OrderConfirmed) {
+	o.Status = StatusConfirmed
+}
+
+// ApplyOrderCancelled 应用订单取消事件。
+func (o *Order) ApplyOrderCancelled(_ *OrderCancelled) {
+	o.Status = StatusCancelled
+}
+
+// PlaceOrder 下单命令。
+type PlaceOrder struct {
+	OrderID    int64 `json:"order_id"`
+	CustomerID int64 `json:"customer_id"`
+	ProductID  int64 `json:"product_id"`
+	Quantity   int   `json:"quantity"`
+	Amount     int64 `json:"amount"`
+}
+
+// AggregateID 返回目标订单 ID。
+func (c *PlaceOrder) AggregateID() int64 { return c.OrderID }
+
+// ConfirmOrder 确认订单命令。
+type ConfirmOrder struct {
+	OrderID int64 `json:"order_id"`
+}
+
+// AggregateID 返回目标订单 ID。
+func (c *ConfirmOrder) AggregateID() int64 { return c.OrderID }
+
+// CancelOrder 取消订单命令。
+type CancelOrder struct {
+	OrderID int64  `json:"order_id"`
+	Reason  string `json:"reason"`
+}
+
+// AggregateID 返回目标订单 ID。
+func (c *CancelOrder) AggregateID() int64 { return c.OrderID }
@@ -0,0 +1,71 @@
+package orders
+
+import (
+	"net/http"
+	"strconv"
+
+	"gochen/api/openapi"
+	"gochen/auth"
+	"gochen/examples/reference/internal/platform"
+	"gochen/host"
+	"gochen/httpx"
+)
+
+// ModuleID 订单模块标识，路由挂载在 /api/v1/orders 下。
+const ModuleID = "orders"
+
+// Permissions 订单模块的 API 权限集合。
+var Permissions = auth.NewAPIPermissionSet(ModuleID, auth.PermissionActionRead, auth.PermissionActionWrite)
+
+// Routes 订单查询接口。
+type Routes struct {
+	reader *Reader
+	docs   *openapi.Builder
+}
+
+// NewRoutes 创建订单查询路由注册器。
+func NewRoutes(reader *Reader, docs *openapi.Builder) *Routes {
+	return &Routes{reader: reader, docs: docs}
+}
+
+// RegisterRoutes 注册订单摘要查询接口。
+func (r *Routes) RegisterRoutes(group httpx.IRouteGroup) error {
+	router := platform.NewRouter(group, r.docs, ModuleID).Require(Permissions.Must(auth.PermissionActionRead))
+	if err := router.GET("", router.Operation("List recent orders", nil, http.StatusOK, []*Summary{}), r.list); err != nil {
+		return err
+	}
+	return router.GET("/:id", router.Operation("Get order summary", nil, http.StatusOK, &Summary{}), r.get)
+}
+
+func (r *Routes) list(ctx httpx.IContext) error {
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+	summaries, err := r.reader.List(ctx.RequestContext(), limit)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, httpx.JSONValue(summaries))
+}
+
+func (r *Routes) get(ctx httpx.IContext) error {
+	orderID, err := platform.ParseID(ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+	summary, err := r.reader.Get(ctx.RequestContext(), orderID)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, httpx.JSONValue(summary))
+}
+
+// NewModule 创建订单模块：写侧服务、摘要投影与查询接口。
+func NewModule() (host.IModule, error) {
+	return host.Module(ModuleID).
+		Name("Orders").
+		Aggregate(host.AggregateFromTag(&Order{})).
+		Provide(NewService, NewReader).
+		Projection(NewSummaryProjection).
+		RouteRegistrar(NewRoutes).
+		PermissionDefinitions(Permissions.Definitions()...).
+		Build()
+}
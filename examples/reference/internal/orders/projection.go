@@ -0,0 +1,128 @@
+package orders
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/projection"
+	"gochen/examples/reference/internal/platform"
+	"gochen/messaging"
+)
+
+// SummaryProjectionName 订单摘要投影名称，同时作为 checkpoint 键。
+const SummaryProjectionName = "order_summaries"
+
+// SummaryProjection 把订单事件投影到 order_summaries 表。
+//
+// 写入通过 platform.SessionDB 获取事务句柄，与 checkpoint 保存共享同一提交边界。
+type SummaryProjection struct {
+	infra *platform.Infra
+
+	mu     sync.Mutex
+	status projection.ProjectionStatus
+}
+
+// NewSummaryProjection 创建订单摘要投影，并用 checkpoint 事务包装后交给投影管理器。
+func NewSummaryProjection(infra *platform.Infra) (*projection.CheckpointingProjector[int64], error) {
+	now := time.Now()
+	inner := &SummaryProjection{
+		infra: infra,
+		status: projection.ProjectionStatus{
+			Name:      SummaryProjectionName,
+			Status:    "running",
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+	}
+	return platform.NewCheckpointingProjection(infra, inner)
+}
+
+// Name 返回投影名称。
+func (p *SummaryProjection) Name() string { return SummaryProjectionName }
+
+// SupportedEventTypes 返回投影关心的事件类型。
+func (p *SummaryProjection) SupportedEventTypes() []string {
+	return []string{"OrderPlaced", "OrderConfirmed", "OrderCancelled"}
+}
+
+// Handle 根据事件类型插入或更新订单摘要；重复投递时保持幂等。
+func (p *SummaryProjection) Handle(ctx context.Context, evt eventing.IEvent) error {
+	typed, ok := evt.(eventing.ITypedEvent[int64])
+	if !ok {
+		return errors.NewCode(errors.InvalidInput, "order event requires int64 aggregate id").
+			WithContext("event_type", evt.GetType())
+	}
+	orderID := typed.GetAggregateID()
+	database := platform.SessionDB(ctx, p.infra)
+
+	var err error
+	switch evt.GetType() {
+	case "OrderPlaced":
+		payload, ok := messaging.PayloadAs[*OrderPlaced](evt.GetPayload())
+		if !ok || payload == nil {
+			return errors.NewCode(errors.InvalidInput, "invalid OrderPlaced payload").WithContext("order_id", orderID)
+		}
+		_, err = database.Exec(ctx,
+			`INSERT INTO order_summaries (order_id, customer_id, product_id, quantity, amount, status, reason, updated_at)
+			 VALUES (?, ?, ?, ?, ?, ?, '', ?) ON CONFLICT (order_id) DO NOTHING`,
+			orderID, payload.CustomerID, payload.ProductID, payload.Quantity, payload.Amount, StatusPending, evt.GetTimestamp())
+	case "OrderConfirmed":
+		_, err = database.Exec(ctx,
+			`UPDATE order_summaries SET status = ?, updated_at = ? WHERE order_id = ?`,
+			StatusConfirmed, evt.GetTimestamp(), orderID)
+	case "OrderCancelled":
+		reason := ""
+		if payload, ok := messaging.PayloadAs[*OrderCancelled](evt.GetPayload()); ok && payload != nil {
+			reason = payload.Reason
+		}
+		_, err = database.Exec(ctx,
+			`UPDATE order_summaries SET status = ?, reason = ?, updated_at = ? WHERE order_id = ?`,
+			StatusCancelled, reason, evt.GetTimestamp(), orderID)
+	default:
+		return nil
+	}
+	p.record(evt, err)
+	if err != nil {
+		return errors.Wrap(err, errors.Database, "project order summary failed").
+			WithContext("order_id", orderID).
+			WithContext("event_type", evt.GetType())
+	}
+	return nil
+}
+
+// Rebuild 按顺序重放事件重建读模型。
+func (p *SummaryProjection) Rebuild(ctx context.Context, events []eventing.Event[int64]) error {
+	if _, err := platform.SessionDB(ctx, p.infra).Exec(ctx, `DELETE FROM order_summaries`); err != nil {
+		return errors.Wrap(err, errors.Database, "reset order summaries failed")
+	}
+	for i := range events {
+		if err := p.Handle(ctx, &events[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Status 返回投影运行状态快照。
+func (p *SummaryProjection) Status() projection.ProjectionStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.status
+}
+
+func (p *SummaryProjection) record(evt eventing.IEvent, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status.UpdatedAt = time.Now()
+	if err != nil {
+		p.status.FailedEvents++
+		p.status.LastError = err.Error()
+		return
+	}
+	p.status.ProcessedEvents++
+	p.status.LastEventID = evt.GetID()
+	p.status.LastEventTime = evt.GetTimestamp()
+}
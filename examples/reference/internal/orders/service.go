@@ -0,0 +1,155 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gochen/app/eventsourced"
+	"gochen/db"
+	"gochen/errors"
+	"gochen/examples/reference/internal/platform"
+	"gochen/messaging/command"
+)
+
+// Saga 使用的订单命令类型。
+const (
+	CommandConfirm = "orders.confirm"
+	CommandCancel  = "orders.cancel"
+)
+
+// Service 订单写侧服务；同时把确认/取消命令注册到共享的命令执行器，供 Saga 编排调用。
+type Service struct {
+	commands *eventsourced.EventSourcedService[*Order, int64]
+}
+
+// NewService 装配订单聚合的仓储与命令服务，并注册 Saga 命令处理器。
+func NewService(infra *platform.Infra, executor *command.CommandExecutor) (*Service, error) {
+	if err := RegisterEvents(infra.Events); err != nil {
+		return nil, err
+	}
+	_, commands, err := platform.NewService(infra, &Order{}, func(id int64) (*Order, error) {
+		return NewOrder(infra.Metadata, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s := &Service{commands: commands}
+	if err := s.registerHandlers(executor); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Place 创建订单。
+func (s *Service) Place(ctx context.Context, cmd *PlaceOrder) error {
+	return s.commands.ExecuteCommand(ctx, cmd)
+}
+
+// Confirm 确认订单。
+func (s *Service) Confirm(ctx context.Context, orderID int64) error {
+	return s.commands.ExecuteCommand(ctx, &ConfirmOrder{OrderID: orderID})
+}
+
+// Cancel 取消订单。
+func (s *Service) Cancel(ctx context.Context, orderID int64, reason string) error {
+	return s.commands.ExecuteCommand(ctx, &CancelOrder{OrderID: orderID, Reason: reason})
+}
+
+func (s *Service) registerHandlers(executor *command.CommandExecutor) error {
+	if err := s.commands.RegisterCommandHandler(&PlaceOrder{}, func(ctx context.Context, cmd eventsourced.IEventSourcedCommand[int64], order *Order) error {
+		c := cmd.(*PlaceOrder)
+		return order.Place(c.CustomerID, c.ProductID, c.Quantity, c.Amount)
+	}); err != nil {
+		return err
+	}
+	if err := s.commands.RegisterCommandHandler(&ConfirmOrder{}, func(ctx context.Context, cmd eventsourced.IEventSourcedCommand[int64], order *Order) error {
+		return order.Confirm()
+	}); err != nil {
+		return err
+	}
+	if err := s.commands.RegisterCommandHandler(&CancelOrder{}, func(ctx context.Context, cmd eventsourced.IEventSourcedCommand[int64], order *Order) error {
+		return order.Cancel(cmd.(*CancelOrder).Reason)
+	}); err != nil {
+		return err
+	}
+
+	if err := platform.HandleCommand[*ConfirmOrder](executor, CommandConfirm, s.commands.ExecuteCommand); err != nil {
+		return err
+	}
+	return platform.HandleCommand[*CancelOrder](executor, CommandCancel, s.commands.ExecuteCommand)
+}
+
+// Summary 是订单摘要读模型。
+type Summary struct {
+	OrderID    int64     `json:"order_id"`
+	CustomerID int64     `json:"customer_id"`
+	ProductID  int64     `json:"product_id"`
+	Quantity   int       `json:"quantity"`
+	Amount     int64     `json:"amount"`
+	Status     string    `json:"status"`
+	Reason     string    `json:"reason,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Reader 查询订单摘要读模型。
+type Reader struct {
+	db db.IDatabase
+}
+
+// NewReader 创建订单摘要查询器。
+func NewReader(infra *platform.Infra) *Reader {
+	return &Reader{db: infra.DB}
+}
+
+const summaryColumns = `order_id, customer_id, product_id, quantity, amount, status, reason, updated_at`
+
+// Get 按订单 ID 查询摘要；读模型尚未追上时返回 NotFound。
+func (r *Reader) Get(ctx context.Context, orderID int64) (*Summary, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+summaryColumns+` FROM order_summaries WHERE order_id = ?`, orderID)
+	summary, err := scanSummary(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.NewCode(errors.NotFound, "order not found").WithContext("order_id", orderID)
+		}
+		return nil, errors.Wrap(err, errors.Database, "query order summary failed").WithContext("order_id", orderID)
+	}
+	return summary, nil
+}
+
+// List 按更新时间倒序返回最近的订单摘要。
+func (r *Reader) List(ctx context.Context, limit int) ([]*Summary, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	rows, err := r.db.Query(ctx, `SELECT `+summaryColumns+` FROM order_summaries ORDER BY updated_at DESC, order_id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Database, "list order summaries failed")
+	}
+	defer rows.Close()
+
+	summaries := make([]*Summary, 0)
+	for rows.Next() {
+		summary, err := scanSummary(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.Database, "scan order summary failed")
+		}
+		summaries = append(summaries, summary)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, errors.Database, "list order summaries failed")
+	}
+	return summaries, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSummary(row scanner) (*Summary, error) {
+	var s Summary
+	if err := row.Scan(&s.OrderID, &s.CustomerID, &s.ProductID, &s.Quantity, &s.Amount, &s.Status, &s.Reason, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,109 @@
+// Package payments 实现参考服务的支付模块：以订单 ID 为聚合 ID 的支付授权与退款。
+package payments
+
+import (
+	deventsourced "gochen/domain/eventsourced"
+	"gochen/errors"
+	"gochen/eventing/registry"
+)
+
+// 支付状态。
+const (
+	StatusAuthorized = "authorized"
+	StatusRefunded   = "refunded"
+)
+
+// PaymentAuthorized 表示订单金额已授权。
+type PaymentAuthorized struct {
+	Amount int64 `json:"amount"`
+}
+
+// EventType 返回事件类型标识。
+func (e *PaymentAuthorized) EventType() string { return "PaymentAuthorized" }
+
+// PaymentRefunded 表示已授权金额被退回（Saga 补偿）。
+type PaymentRefunded struct {
+	Amount int64 `json:"amount"`
+}
+
+// EventType 返回事件类型标识。
+func (e *PaymentRefunded) EventType() string { return "PaymentRefunded" }
+
+// RegisterEvents 把支付事件注册到事件注册表。
+func RegisterEvents(reg *registry.Registry) error {
+	if err := reg.Register("PaymentAuthorized", func() any { return &PaymentAuthorized{} }); err != nil {
+		return err
+	}
+	return reg.Register("PaymentRefunded", func() any { return &PaymentRefunded{} })
+}
+
+// Payment 是以订单 ID 为聚合 ID 的支付聚合。
+type Payment struct {
+	*deventsourced.EventSourcedAggregate[int64] `aggregate:"payment"`
+
+	Amount int64  `json:"amount"`
+	Status string `json:"status"`
+}
+
+// NewPayment 创建空支付聚合。
+func NewPayment(metadata *deventsourced.MetadataRegistry, orderID int64) (*Payment, error) {
+	return deventsourced.New[Payment, int64](metadata, orderID)
+}
+
+// Authorize 授权支付；超过单笔限额时拒绝，同一订单重复授权视为成功。
+func (p *Payment) Authorize(amount, limit int64) error {
+	if p.Status != "" {
+		if p.Status == StatusAuthorized && p.Amount == amount {
+			return nil
+		}
+		return errors.NewCode(errors.Conflict, "payment already processed").
+			WithContext("order_id", p.GetID()).
+			WithContext("status", p.Status)
+	}
+	if amount <= 0 {
+		return errors.NewCode(errors.Validation, "amount must be positive")
+	}
+	if limit > 0 && amount > limit {
+		return errors.NewCode(errors.Conflict, "payment declined: amount exceeds authorization limit").
+			WithContext("order_id", p.GetID()).
+			WithContext("amount", amount).
+			WithContext("limit", limit)
+	}
+	return p.ApplyAndRecord(&PaymentAuthorized{Amount: amount})
+}
+
+// Refund 退回已授权金额；未授权或已退款时直接忽略，便于补偿重试。
+func (p *Payment) Refund() error {
+	if p.Status != StatusAuthorized {
+		return nil
+	}
+	return p.ApplyAndRecord(&PaymentRefunded{Amount: p.Amount})
+}
+
+// ApplyPaymentAuthorized 应用授权事件。
+func (p *Payment) ApplyPaymentAuthorized(e *PaymentAuthorized) {
+	p.Amount = e.Amount
+	p.Status = StatusAuthorized
+}
+
+// ApplyPaymentRefunded 应用退款事件。
+func (p *Payment) ApplyPaymentRefunded(_ *PaymentRefunded) {
+	p.Status = StatusRefunded
+}
+
+// AuthorizePayment 授权支付命令。
+type AuthorizePayment struct {
+	OrderID int64 `json:"order_id"`
+	Amount  int64 `json:"amount"`
+}
+
+// AggregateID 返回目标订单 ID。
+func (c *AuthorizePayment) AggregateID() int64 { return c.OrderID }
+
+// RefundPayment 退款命令。
+type RefundPayment struct {
+	OrderID int64 `json:"order_id"`
+}
+
+// AggregateID 返回目标订单 ID。
+func (c *RefundPayment) AggregateID() int64 { return c.OrderID }
@@ -0,0 +1,57 @@
+package payments
+
+import (
+	"net/http"
+
+	"gochen/api/openapi"
+	"gochen/auth"
+	"gochen/examples/reference/internal/platform"
+	"gochen/host"
+	"gochen/httpx"
+)
+
+// ModuleID 支付模块标识，路由挂载在 /api/v1/payments 下。
+const ModuleID = "payments"
+
+// Permissions 支付模块的 API 权限集合。
+var Permissions = auth.NewAPIPermissionSet(ModuleID, auth.PermissionActionRead)
+
+// Routes 支付查询接口。
+type Routes struct {
+	service *Service
+	docs    *openapi.Builder
+}
+
+// NewRoutes 创建支付路由注册器。
+func NewRoutes(service *Service, docs *openapi.Builder) *Routes {
+	return &Routes{service: service, docs: docs}
+}
+
+// RegisterRoutes 注册按订单查询支付状态的接口。
+func (r *Routes) RegisterRoutes(group httpx.IRouteGroup) error {
+	router := platform.NewRouter(group, r.docs, ModuleID).Require(Permissions.Must(auth.PermissionActionRead))
+	return router.GET("/:id", router.Operation("Get payment by order id", nil, http.StatusOK, &View{}), r.get)
+}
+
+func (r *Routes) get(ctx httpx.IContext) error {
+	orderID, err := platform.ParseID(ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+	view, err := r.service.Get(ctx.RequestContext(), orderID)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, httpx.JSONValue(view))
+}
+
+// NewModule 创建支付模块。
+func NewModule() (host.IModule, error) {
+	return host.Module(ModuleID).
+		Name("Payments").
+		Aggregate(host.AggregateFromTag(&Payment{})).
+		Provide(NewService).
+		RouteRegistrar(NewRoutes).
+		PermissionDefinitions(Permissions.Definitions()...).
+		Build()
+}
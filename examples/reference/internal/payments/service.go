@@ -0,0 +1,75 @@
+package payments
+
+import (
+	"context"
+
+	"gochen/app/eventsourced"
+	"gochen/examples/reference/internal/config"
+	"gochen/examples/reference/internal/platform"
+	"gochen/messaging/command"
+)
+
+// Saga 使用的支付命令类型。
+const (
+	CommandAuthorize = "payments.authorize"
+	CommandRefund    = "payments.refund"
+)
+
+// View 是支付查询结果。
+type View struct {
+	OrderID int64  `json:"order_id"`
+	Amount  int64  `json:"amount"`
+	Status  string `json:"status"`
+}
+
+// Service 支付写侧服务，并向共享命令执行器注册授权/退款命令。
+type Service struct {
+	repo     *eventsourced.EventSourcedRepository[*Payment, int64]
+	commands *eventsourced.EventSourcedService[*Payment, int64]
+	limit    int64
+}
+
+// NewService 装配支付聚合的仓储与命令服务，并注册 Saga 命令处理器。
+func NewService(infra *platform.Infra, executor *command.CommandExecutor, cfg *config.Config) (*Service, error) {
+	if err := RegisterEvents(infra.Events); err != nil {
+		return nil, err
+	}
+	repo, commands, err := platform.NewService(infra, &Payment{}, func(id int64) (*Payment, error) {
+		return NewPayment(infra.Metadata, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s := &Service{repo: repo, commands: commands, limit: cfg.Payments.AuthorizationLimit}
+	if err := s.registerHandlers(executor); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get 返回订单的支付状态；尚未发起授权时返回 NotFound。
+func (s *Service) Get(ctx context.Context, orderID int64) (*View, error) {
+	payment, err := s.repo.Get(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return &View{OrderID: orderID, Amount: payment.Amount, Status: payment.Status}, nil
+}
+
+func (s *Service) registerHandlers(executor *command.CommandExecutor) error {
+	if err := s.commands.RegisterCommandHandler(&AuthorizePayment{}, func(ctx context.Context, cmd eventsourced.IEventSourcedCommand[int64], payment *Payment) error {
+		return payment.Authorize(cmd.(*AuthorizePayment).Amount, s.limit)
+	}); err != nil {
+		return err
+	}
+	if err := s.commands.RegisterCommandHandler(&RefundPayment{}, func(ctx context.Context, cmd eventsourced.IEventSourcedCommand[int64], payment *Payment) error {
+		return payment.Refund()
+	}); err != nil {
+		return err
+	}
+
+	if err := platform.HandleCommand[*AuthorizePayment](executor, CommandAuthorize, s.commands.ExecuteCommand); err != nil {
+		return err
+	}
+	return platform.HandleCommand[*RefundPayment](executor, CommandRefund, s.commands.ExecuteCommand)
+}
@@ -0,0 +1,176 @@
+// Package platform 提供参考服务的基础设施装配：数据库、表结构、事件溯源组件与 Outbox relay。
+package platform
+
+import (
+	"context"
+
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+
+	"gochen/db"
+	"gochen/db/sql/stdsql"
+	"gochen/errors"
+	"gochen/examples/reference/internal/config"
+)
+
+// OpenDatabase 按配置打开数据库并创建参考服务所需的表结构。
+func OpenDatabase(ctx context.Context, cfg config.DatabaseConfig) (db.IDatabase, error) {
+	database, err := stdsql.NewWithContext(ctx, db.DBConfig{
+		Driver:       cfg.Driver,
+		Database:     cfg.DSN,
+		MaxOpenConns: cfg.MaxOpenConns,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Dependency, "open database failed").
+			WithContext("driver", cfg.Driver)
+	}
+	if err := EnsureSchema(ctx, database, cfg.Driver); err != nil {
+		_ = database.Close()
+		return nil, err
+	}
+	return database, nil
+}
+
+// EnsureSchema 幂等创建事件存储、Outbox（含死信表）、快照与读模型表；投影检查点表由 SQLCheckpointStore 创建。
+//
+// 说明：参考服务为了开箱即用在启动时建表；生产环境建议改为独立的迁移流程。
+func EnsureSchema(ctx context.Context, database db.IDatabase, driver string) error {
+	statements := sqliteSchema
+	if driver == "postgres" {
+		statements = postgresSchema
+	}
+	for _, stmt := range statements {
+		if _, err := database.Exec(ctx, stmt); err != nil {
+			return errors.Wrap(err, errors.Database, "ensure schema failed").
+				WithContext("driver", driver).
+				WithContext("statement", stmt)
+		}
+	}
+	return nil
+}
+
+var sqliteSchema = []string{
+	`CREATE TABLE IF NOT EXISTS event_store (
+		id TEXT PRIMARY KEY,
+		type TEXT NOT NULL,
+		aggregate_id INTEGER NOT NULL,
+		aggregate_type TEXT NOT NULL,
+		version INTEGER NOT NULL,
+		schema_version INTEGER NOT NULL,
+		timestamp DATETIME NOT NULL,
+		payload TEXT NOT NULL,
+		metadata TEXT NOT NULL,
+		UNIQUE(aggregate_id, aggregate_type, version)
+	)`,
+	`CREATE TABLE IF NOT EXISTS event_outbox (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		aggregate_id INTEGER NOT NULL,
+		aggregate_type TEXT NOT NULL,
+		event_id TEXT NOT NULL UNIQUE,
+		event_type TEXT NOT NULL,
+		event_data TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		claim_token TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		published_at DATETIME NULL,
+		retry_count INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NULL,
+		lease_until DATETIME NULL,
+		next_retry_at DATETIME NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_event_outbox_status_retry ON event_outbox (status, next_retry_at, lease_until)`,
+	`CREATE TABLE IF NOT EXISTS event_outbox_dlq (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		original_entry_id INTEGER NOT NULL,
+		aggregate_id INTEGER NOT NULL,
+		aggregate_type TEXT NOT NULL,
+		event_id TEXT NOT NULL UNIQUE,
+		event_type TEXT NOT NULL,
+		event_data TEXT NOT NULL,
+		failure_reason TEXT NULL,
+		retry_count INTEGER NOT NULL,
+		moved_at DATETIME NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS event_snapshots (
+		aggregate_type TEXT NOT NULL,
+		aggregate_id INTEGER NOT NULL,
+		version INTEGER NOT NULL,
+		data BLOB NOT NULL,
+		timestamp DATETIME NOT NULL,
+		metadata TEXT NULL,
+		PRIMARY KEY (aggregate_type, aggregate_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS order_summaries (
+		order_id INTEGER PRIMARY KEY,
+		customer_id INTEGER NOT NULL,
+		product_id INTEGER NOT NULL,
+		quantity INTEGER NOT NULL,
+		amount INTEGER NOT NULL,
+		status TEXT NOT NULL,
+		reason TEXT NOT NULL DEFAULT '',
+		updated_at DATETIME NOT NULL
+	)`,
+}
+
+var postgresSchema = []string{
+	`CREATE TABLE IF NOT EXISTS event_store (
+		id TEXT PRIMARY KEY,
+		type TEXT NOT NULL,
+		aggregate_id BIGINT NOT NULL,
+		aggregate_type TEXT NOT NULL,
+		version BIGINT NOT NULL,
+		schema_version INTEGER NOT NULL,
+		timestamp TIMESTAMPTZ NOT NULL,
+		payload TEXT NOT NULL,
+		metadata TEXT NOT NULL,
+		UNIQUE (aggregate_id, aggregate_type, version)
+	)`,
+	`CREATE TABLE IF NOT EXISTS event_outbox (
+		id BIGSERIAL PRIMARY KEY,
+		aggregate_id BIGINT NOT NULL,
+		aggregate_type TEXT NOT NULL,
+		event_id TEXT NOT NULL UNIQUE,
+		event_type TEXT NOT NULL,
+		event_data TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		claim_token TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		published_at TIMESTAMPTZ NULL,
+		retry_count INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NULL,
+		lease_until TIMESTAMPTZ NULL,
+		next_retry_at TIMESTAMPTZ NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_event_outbox_status_retry ON event_outbox (status, next_retry_at, lease_until)`,
+	`CREATE TABLE IF NOT EXISTS event_outbox_dlq (
+		id BIGSERIAL PRIMARY KEY,
+		original_entry_id BIGINT NOT NULL,
+		aggregate_id BIGINT NOT NULL,
+		aggregate_type TEXT NOT NULL,
+		event_id TEXT NOT NULL UNIQUE,
+		event_type TEXT NOT NULL,
+		event_data TEXT NOT NULL,
+		failure_reason TEXT NULL,
+		retry_count INTEGER NOT NULL,
+		moved_at TIMESTAMPTZ NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS event_snapshots (
+		aggregate_type TEXT NOT NULL,
+		aggregate_id BIGINT NOT NULL,
+		version BIGINT NOT NULL,
+		data BYTEA NOT NULL,
+		timestamp TIMESTAMPTZ NOT NULL,
+		metadata TEXT NULL,
+		PRIMARY KEY (aggregate_type, aggregate_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS order_summaries (
+		order_id BIGINT PRIMARY KEY,
+		customer_id BIGINT NOT NULL,
+		product_id BIGINT NOT NULL,
+		quantity INTEGER NOT NULL,
+		amount BIGINT NOT NULL,
+		status TEXT NOT NULL,
+		reason TEXT NOT NULL DEFAULT '',
+		updated_at TIMESTAMPTZ NOT NULL
+	)`,
+}
@@ -0,0 +1,182 @@
+package platform
+
+import (
+	"context"
+
+	"gochen/app/eventsourced"
+	"gochen/db"
+	"gochen/db/orm"
+	ormlite "gochen/db/orm/lite"
+	deventsourced "gochen/domain/eventsourced"
+	"gochen/errors"
+	"gochen/eventing/bus"
+	"gochen/eventing/outbox"
+	"gochen/eventing/projection"
+	"gochen/eventing/registry"
+	"gochen/eventing/store/snapshot"
+	"gochen/eventing/store/sqlstore"
+	"gochen/eventing/upcast"
+	"gochen/examples/reference/internal/config"
+	"gochen/messaging"
+	"gochen/messaging/command"
+)
+
+// Infra 汇总各业务模块共享的事件溯源基础设施。
+//
+// 组合根负责创建一次并以实例形式注册到 DI 容器，模块 provider 通过类型注入获取。
+type Infra struct {
+	DB          db.IDatabase
+	ORM         orm.IOrm
+	EventStore  *sqlstore.SQLEventStore[int64]
+	Outbox      *outbox.SimpleSQLOutboxRepository[int64]
+	Snapshots   *snapshot.Manager[int64]
+	Checkpoints *projection.SQLCheckpointStore
+	Events      *registry.Registry
+	Upgraders   *upcast.UpgraderRegistry
+	Metadata    *deventsourced.MetadataRegistry
+	Bus         bus.IEventBus
+}
+
+// NewInfra 基于已建表的数据库装配事件存储、Outbox、快照与检查点存储。
+func NewInfra(database db.IDatabase, eventBus bus.IEventBus, cfg *config.Config) (*Infra, error) {
+	if database == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "database cannot be nil")
+	}
+	if eventBus == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "event bus cannot be nil")
+	}
+	if cfg == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "config cannot be nil")
+	}
+
+	eventStore, err := sqlstore.NewSQLEventStore(database, "event_store")
+	if err != nil {
+		return nil, err
+	}
+	outboxRepo, err := outbox.NewSimpleSQLOutboxRepository(database, eventStore, nil)
+	if err != nil {
+		return nil, err
+	}
+	ormEngine, err := ormlite.New(database)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotCfg := snapshot.DefaultConfig()
+	snapshotCfg.Frequency = cfg.Snapshot.Frequency
+
+	return &Infra{
+		DB:          database,
+		ORM:         ormEngine,
+		EventStore:  eventStore,
+		Outbox:      outboxRepo,
+		Snapshots:   snapshot.NewManager[int64](snapshot.NewSQLStore(database, "event_snapshots"), snapshotCfg),
+		Checkpoints: projection.NewSQLCheckpointStore(database, "projection_checkpoints"),
+		Events:      registry.NewRegistry(),
+		Upgraders:   upcast.NewUpgraderRegistry(),
+		Metadata:    deventsourced.NewMetadataRegistry(),
+		Bus:         eventBus,
+	}, nil
+}
+
+// NewService 为指定聚合装配“事件存储 + Outbox + 快照”的仓储与命令服务。
+//
+// 事件与 Outbox 记录在同一事务内写入，由 Outbox publisher 异步投递到总线。
+func NewService[T deventsourced.IEventSourcedAggregate[int64]](
+	infra *Infra,
+	sample T,
+	factory func(id int64) (T, error),
+) (*eventsourced.EventSourcedRepository[T, int64], *eventsourced.EventSourcedService[T, int64], error) {
+	if infra == nil {
+		return nil, nil, errors.NewCode(errors.InvalidInput, "infra cannot be nil")
+	}
+	aggregateType, err := deventsourced.ResolveAggregateType(sample)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	domainStore, err := eventsourced.NewDomainEventStore(eventsourced.DomainEventStoreOptions[T, int64]{
+		AggregateType:    aggregateType,
+		EventStore:       infra.EventStore,
+		SnapshotManager:  infra.Snapshots,
+		EventBus:         infra.Bus,
+		OutboxRepo:       infra.Outbox,
+		PublishEvents:    true,
+		EventRegistry:    infra.Events,
+		UpgraderRegistry: infra.Upgraders,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	repo, err := eventsourced.NewEventSourcedRepository(eventsourced.RepositoryOptions[T, int64]{
+		AggregateType:    aggregateType,
+		Sample:           sample,
+		Factory:          factory,
+		Store:            domainStore,
+		MetadataRegistry: infra.Metadata,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	service, err := eventsourced.NewEventSourcedService[T, int64](repo, &eventsourced.EventSourcedServiceOptions[T, int64]{
+		ConcurrencyRetry: eventsourced.DefaultRetryConfig(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return repo, service, nil
+}
+
+// NewOutboxPublisher 创建 Outbox publisher；它实现 Start/Stop，可直接作为模块运行时组件托管。
+func NewOutboxPublisher(infra *Infra, cfg *config.Config) (*outbox.Publisher[int64], error) {
+	if infra == nil || cfg == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "infra and config cannot be nil")
+	}
+	publisherCfg := outbox.DefaultOutboxConfig()
+	publisherCfg.PublishInterval = cfg.Outbox.PublishInterval
+	publisherCfg.BatchSize = cfg.Outbox.BatchSize
+	return outbox.NewPublisher[int64](infra.Outbox, infra.Bus, publisherCfg, nil, infra.Events, infra.Upgraders)
+}
+
+// NewCheckpointingProjection 用 ORM 事务包装投影，使读模型写入与 checkpoint 保存共享同一提交边界。
+func NewCheckpointingProjection(infra *Infra, inner projection.IProjection[int64]) (*projection.CheckpointingProjector[int64], error) {
+	if infra == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "infra cannot be nil")
+	}
+	txRunner, err := projection.NewSQLCheckpointTxRunner(infra.ORM)
+	if err != nil {
+		return nil, err
+	}
+	return projection.NewCheckpointingProjector[int64](inner, txRunner)
+}
+
+// SessionDB 返回投影事务内应使用的数据库句柄；不在事务中时回退到 infra.DB。
+func SessionDB(ctx context.Context, infra *Infra) db.IDatabase {
+	if session, ok := orm.SessionFromContext(ctx); ok && session != nil {
+		if database := session.Database(); database != nil {
+			return database
+		}
+	}
+	return infra.DB
+}
+
+// HandleCommand 把 Saga 使用的 gochen 命令类型映射到事件溯源命令。
+//
+// 命令载荷必须是 C 类型；execute 通常为 EventSourcedService.ExecuteCommand。
+func HandleCommand[C eventsourced.IEventSourcedCommand[int64]](
+	executor *command.CommandExecutor,
+	commandType string,
+	execute func(ctx context.Context, cmd eventsourced.IEventSourcedCommand[int64]) error,
+) error {
+	if executor == nil {
+		return errors.NewCode(errors.InvalidInput, "command executor cannot be nil")
+	}
+	return executor.RegisterHandler(commandType, func(ctx context.Context, cmd *command.Command) error {
+		payload, ok := messaging.PayloadAs[C](cmd.GetPayload())
+		if !ok {
+			return errors.NewCode(errors.InvalidInput, "unexpected command payload").
+				WithContext("command_type", commandType)
+		}
+		return execute(ctx, payload)
+	})
+}
@@ -0,0 +1,29 @@
+package platform
+
+import (
+	"gochen/eventing/monitoring"
+	"gochen/eventing/outbox"
+	"gochen/examples/reference/internal/config"
+	"gochen/host"
+)
+
+// ModuleID 平台模块标识；该模块不暴露路由，只托管基础设施运行时组件。
+const ModuleID = "platform"
+
+// NewModule 创建平台模块：Outbox publisher 随 Host 生命周期启动与停止。
+func NewModule() (host.IModule, error) {
+	return host.Module(ModuleID).
+		Name("Platform").
+		RuntimeComponent(newRelay).
+		Build()
+}
+
+// newRelay 创建 Outbox publisher，并把发布指标写入默认监控注册表（/metrics 暴露）。
+func newRelay(infra *Infra, cfg *config.Config) (*outbox.Publisher[int64], error) {
+	publisher, err := NewOutboxPublisher(infra, cfg)
+	if err != nil {
+		return nil, err
+	}
+	publisher.SetMetricsRecorder(monitoring.DefaultRegistry().Metrics)
+	return publisher, nil
+}
@@ -0,0 +1,108 @@
+package platform
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"gochen/api/openapi"
+	"gochen/auth"
+	authhttp "gochen/auth/http"
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+// BasePath 与 host 默认 BasePath 保持一致，模块路由挂载在 BasePath + "/" + moduleID 下。
+const BasePath = "/api/v1"
+
+// Router 在模块路由组上注册 handler，同时把操作登记到共享的 OpenAPI 文档。
+type Router struct {
+	group  httpx.IRouteGroup
+	docs   *openapi.Builder
+	prefix string
+	tag    string
+}
+
+// NewRouter 为模块创建带文档登记能力的路由器。
+func NewRouter(group httpx.IRouteGroup, docs *openapi.Builder, moduleID string) *Router {
+	return &Router{
+		group:  group,
+		docs:   docs,
+		prefix: openapi.JoinPath(BasePath, moduleID),
+		tag:    moduleID,
+	}
+}
+
+// Require 返回附加权限校验的子路由器，权限只作用于通过它注册的路由。
+func (r *Router) Require(spec auth.PermissionSpec) *Router {
+	child := *r
+	child.group = r.group.Group("").Use(authhttp.PermissionMiddleware(spec))
+	return &child
+}
+
+// GET 注册 GET 路由及其文档。
+func (r *Router) GET(path string, op *openapi.Operation, handler httpx.Handler) error {
+	r.group.GET(path, handler)
+	return r.document(http.MethodGet, path, op)
+}
+
+// POST 注册 POST 路由及其文档。
+func (r *Router) POST(path string, op *openapi.Operation, handler httpx.Handler) error {
+	r.group.POST(path, handler)
+	return r.document(http.MethodPost, path, op)
+}
+
+var pathParamPattern = regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_]*)`)
+
+func (r *Router) document(method, path string, op *openapi.Operation) error {
+	if r.docs == nil || op == nil {
+		return nil
+	}
+	fullPath := openapi.JoinPath(r.prefix, path)
+	if len(op.Tags) == 0 {
+		op.Tags = []string{r.tag}
+	}
+	for _, match := range pathParamPattern.FindAllStringSubmatch(fullPath, -1) {
+		op.Parameters = append(op.Parameters, openapi.Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &openapi.Schema{Type: "integer", Format: "int64"},
+		})
+	}
+	return r.docs.AddOperation(method, fullPath, op)
+}
+
+// Operation 描述一个 JSON 操作；request/response 为 nil 时省略对应的 schema。
+func (r *Router) Operation(summary string, request any, status int, response any) *openapi.Operation {
+	op := &openapi.Operation{
+		Summary:   summary,
+		Responses: map[string]*openapi.Response{},
+	}
+	if request != nil && r.docs != nil {
+		op.RequestBody = &openapi.RequestBody{
+			Required: true,
+			Content:  openapi.JSONContent(r.docs.SchemaFor(reflect.TypeOf(request))),
+		}
+	}
+	resp := &openapi.Response{Description: http.StatusText(status)}
+	if response != nil && r.docs != nil {
+		resp.Content = openapi.JSONContent(r.docs.SchemaFor(reflect.TypeOf(response)))
+	}
+	op.Responses[strconv.Itoa(status)] = resp
+	op.Responses["default"] = &openapi.Response{
+		Description: "RFC 7807 problem details",
+		Content:     map[string]openapi.MediaType{httpx.ProblemContentType: {Schema: &openapi.Schema{Type: "object"}}},
+	}
+	return op
+}
+
+// ParseID 解析路径参数中的 int64 聚合 ID。
+func ParseID(raw string) (int64, error) {
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.NewCode(errors.InvalidInput, "id must be a positive integer").WithContext("id", raw)
+	}
+	return id, nil
+}
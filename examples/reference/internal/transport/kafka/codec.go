@@ -0,0 +1,59 @@
+package kafka
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/messaging"
+)
+
+// encodeMessage 把消息编码为 Kafka record 的 key/value。
+//
+// 事件按 eventing.Event 的 JSON 形态整体写入，保留聚合字段与 schema 版本，
+// key 取聚合 ID，保证同一聚合的事件落在同一分区内有序；其他消息只写信封字段。
+func encodeMessage(message messaging.IMessage) (key []byte, value []byte, err error) {
+	if message == nil {
+		return nil, nil, errors.NewCode(errors.InvalidInput, "message is nil")
+	}
+	var body any
+	if evt, ok := message.(*eventing.Event[int64]); ok && evt != nil {
+		key = []byte(strconv.FormatInt(evt.AggregateID, 10))
+		body = evt
+	} else {
+		key = []byte(message.GetID())
+		body = &messaging.Message{
+			ID:        message.GetID(),
+			Kind:      message.GetKind(),
+			Type:      message.GetType(),
+			Timestamp: message.GetTimestamp(),
+			Payload:   message.GetPayload(),
+			Metadata:  message.GetMetadata(),
+		}
+	}
+	value, err = json.Marshal(body)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.InvalidInput, "encode kafka message failed").
+			WithContext("message_type", message.GetType())
+	}
+	return key, value, nil
+}
+
+// decodeMessage 把 Kafka record value 还原为消息。
+//
+// 事件消息还原为 *eventing.Event[int64]，payload 保持 JSON 宽松形态，
+// 由投影/处理器在消费边界通过 registry 完成 upcast 与强类型反序列化。
+func decodeMessage(value []byte) (messaging.IMessage, error) {
+	var evt eventing.Event[int64]
+	if err := json.Unmarshal(value, &evt); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, "decode kafka message failed")
+	}
+	if evt.Type == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "kafka message type is empty")
+	}
+	if evt.GetKind() == messaging.KindEvent {
+		return &evt, nil
+	}
+	return &evt.Message, nil
+}
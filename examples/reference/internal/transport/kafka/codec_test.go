@@ -0,0 +1,54 @@
+package kafka
+
+import (
+	"testing"
+
+	"gochen/eventing"
+	"gochen/messaging"
+)
+
+func TestCodecRoundTripKeepsEventEnvelope(t *testing.T) {
+	evt := eventing.NewEvent[int64](42, "order", "OrderPlaced", 3, map[string]any{"total": 100})
+	key, value, err := encodeMessage(evt)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if string(key) != "42" {
+		t.Fatalf("expected aggregate id as key, got %q", key)
+	}
+
+	decoded, err := decodeMessage(value)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	got, ok := decoded.(*eventing.Event[int64])
+	if !ok {
+		t.Fatalf("expected *eventing.Event[int64], got %T", decoded)
+	}
+	if got.GetID() != evt.GetID() || got.AggregateID != 42 || got.AggregateType != "order" || got.Version != 3 {
+		t.Fatalf("unexpected event envelope: %+v", got)
+	}
+	payload, ok := messaging.PayloadAs[map[string]any](got.GetPayload())
+	if !ok || payload["total"] == nil {
+		t.Fatalf("expected payload to survive round trip, got %v", messaging.PayloadValue(got.GetPayload()))
+	}
+}
+
+func TestCodecRoundTripPlainMessage(t *testing.T) {
+	msg := messaging.NewMessage("m-1", messaging.KindCommand, "ReserveStock", map[string]any{"sku": "A"})
+	_, value, err := encodeMessage(msg)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	decoded, err := decodeMessage(value)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if _, ok := decoded.(*messaging.Message); !ok || decoded.GetType() != "ReserveStock" || decoded.GetKind() != messaging.KindCommand {
+		t.Fatalf("unexpected message: %T %+v", decoded, decoded)
+	}
+
+	if _, err := decodeMessage([]byte(`{"id":"x"}`)); err == nil {
+		t.Fatal("expected error for message without type")
+	}
+}
@@ -0,0 +1,74 @@
+package kafka
+
+import (
+	"context"
+
+	kafkago "github.com/segmentio/kafka-go"
+
+	"gochen/errors"
+	"gochen/integrations/ingest"
+)
+
+// IngestReader 把 kafka-go 的 consumer group Reader 适配为 ingest.IKafkaReader，
+// 用于从合作方 topic 接入外部消息（ingest.NewKafkaConsumer）。
+type IngestReader struct {
+	reader *kafkago.Reader
+}
+
+var _ ingest.IKafkaReader = (*IngestReader)(nil)
+
+// NewIngestReader 创建外部 topic 的读取端；调用方负责在退出时 Close。
+func NewIngestReader(brokers []string, topic, groupID string) (*IngestReader, error) {
+	if len(brokers) == 0 || topic == "" || groupID == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "kafka brokers, topic and group are required")
+	}
+	return &IngestReader{reader: kafkago.NewReader(kafkago.ReaderConfig{
+		Brokers:  brokers,
+		Topic:    topic,
+		GroupID:  groupID,
+		MinBytes: 1,
+		MaxBytes: 10e6,
+	})}, nil
+}
+
+// Fetch 实现 ingest.IKafkaReader。
+func (r *IngestReader) Fetch(ctx context.Context) (ingest.KafkaRecord, error) {
+	msg, err := r.reader.FetchMessage(ctx)
+	if err != nil {
+		return ingest.KafkaRecord{}, err
+	}
+	return toIngestRecord(msg), nil
+}
+
+// Commit 实现 ingest.IKafkaReader。
+func (r *IngestReader) Commit(ctx context.Context, records ...ingest.KafkaRecord) error {
+	msgs := make([]kafkago.Message, len(records))
+	for i, record := range records {
+		msgs[i] = kafkago.Message{Topic: record.Topic, Partition: record.Partition, Offset: record.Offset}
+	}
+	if err := r.reader.CommitMessages(ctx, msgs...); err != nil {
+		return errors.Wrap(err, errors.Dependency, "kafka commit failed")
+	}
+	return nil
+}
+
+// Close 关闭读取端。
+func (r *IngestReader) Close() error { return r.reader.Close() }
+
+func toIngestRecord(msg kafkago.Message) ingest.KafkaRecord {
+	record := ingest.KafkaRecord{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       msg.Key,
+		Value:     msg.Value,
+		Time:      msg.Time,
+	}
+	if len(msg.Headers) > 0 {
+		record.Headers = make(map[string]string, len(msg.Headers))
+		for _, h := range msg.Headers {
+			record.Headers[h.Key] = string(h.Value)
+		}
+	}
+	return record
+}
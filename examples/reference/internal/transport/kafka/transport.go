@@ -0,0 +1,303 @@
+// Package kafka 提供基于 segmentio/kafka-go 的 messaging.ITransport 实现。
+//
+// 该实现按 messaging/transport/redisstreams 的建议放在应用仓库内，而不是框架核心：
+//   - 所有消息写入同一个 topic，消息类型由信封字段 type 区分；
+//   - 事件以聚合 ID 作为 key，同一聚合的事件在分区内有序；
+//   - 消费使用 consumer group，处理完成后提交 offset（至少一次投递），处理器需幂等。
+package kafka
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+)
+
+// Config 定义 Kafka transport 配置。
+type Config struct {
+	Brokers []string
+	Topic   string
+	GroupID string
+
+	// WriteTimeout 单次写入超时；默认 10s。
+	WriteTimeout time.Duration
+	// MinReadBackoff/MaxReadBackoff 为消费失败时的退避区间；默认 100ms/5s。
+	MinReadBackoff time.Duration
+	MaxReadBackoff time.Duration
+
+	Logger logging.ILogger
+}
+
+// Transport 是基于 Kafka topic + consumer group 的异步传输实现。
+type Transport struct {
+	cfg    Config
+	writer *kafkago.Writer
+	logger logging.ILogger
+
+	mu       sync.RWMutex
+	handlers map[string][]messaging.IMessageHandler
+	running  bool
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+var _ messaging.ITransport = (*Transport)(nil)
+
+// NewTransport 创建 Kafka transport；Start 之前不会建立消费连接。
+func NewTransport(cfg Config) (*Transport, error) {
+	brokers := make([]string, 0, len(cfg.Brokers))
+	for _, broker := range cfg.Brokers {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+	if len(brokers) == 0 {
+		return nil, errors.NewCode(errors.InvalidInput, "kafka brokers are required")
+	}
+	if strings.TrimSpace(cfg.Topic) == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "kafka topic is required")
+	}
+	if strings.TrimSpace(cfg.GroupID) == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "kafka consumer group is required")
+	}
+	cfg.Brokers = brokers
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = 10 * time.Second
+	}
+	if cfg.MinReadBackoff <= 0 {
+		cfg.MinReadBackoff = 100 * time.Millisecond
+	}
+	if cfg.MaxReadBackoff <= 0 {
+		cfg.MaxReadBackoff = 5 * time.Second
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = logging.ComponentLogger("reference.transport.kafka")
+	}
+
+	return &Transport{
+		cfg: cfg,
+		writer: &kafkago.Writer{
+			Addr:                   kafkago.TCP(brokers...),
+			Topic:                  cfg.Topic,
+			Balancer:               &kafkago.Hash{},
+			RequiredAcks:           kafkago.RequireAll,
+			WriteTimeout:           cfg.WriteTimeout,
+			AllowAutoTopicCreation: true,
+		},
+		logger:   cfg.Logger,
+		handlers: make(map[string][]messaging.IMessageHandler),
+	}, nil
+}
+
+// Publish 写入单条消息。
+func (t *Transport) Publish(ctx context.Context, message messaging.IMessage) error {
+	return t.PublishAll(ctx, []messaging.IMessage{message})
+}
+
+// PublishAll 批量写入消息；任一消息编码失败时整批不写入。
+func (t *Transport) PublishAll(ctx context.Context, messages []messaging.IMessage) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	records := make([]kafkago.Message, 0, len(messages))
+	for _, message := range messages {
+		key, value, err := encodeMessage(message)
+		if err != nil {
+			return err
+		}
+		records = append(records, kafkago.Message{
+			Key:     key,
+			Value:   value,
+			Headers: []kafkago.Header{{Key: "type", Value: []byte(message.GetType())}},
+		})
+	}
+	if err := t.writer.WriteMessages(ctx, records...); err != nil {
+		return errors.Wrap(err, errors.Dependency, "kafka publish failed").
+			WithContext("topic", t.cfg.Topic).
+			WithContext("count", len(records))
+	}
+	return nil
+}
+
+// Subscribe 注册消息处理器；支持 "*" 订阅全部消息类型。
+func (t *Transport) Subscribe(ctx context.Context, messageType string, handler messaging.IMessageHandler) (messaging.UnsubscribeFunc, error) {
+	if ctx == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	if strings.TrimSpace(messageType) == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "messageType is required")
+	}
+	if handler == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "handler is nil")
+	}
+
+	t.mu.Lock()
+	t.handlers[messageType] = append(t.handlers[messageType], handler)
+	t.mu.Unlock()
+
+	var once sync.Once
+	return func(context.Context) error {
+		once.Do(func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			handlers := t.handlers[messageType]
+			for i, h := range handlers {
+				if h == handler {
+					t.handlers[messageType] = append(handlers[:i:i], handlers[i+1:]...)
+					break
+				}
+			}
+		})
+		return nil
+	}, nil
+}
+
+// Start 启动 consumer group 消费循环。
+func (t *Transport) Start(ctx context.Context) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.running {
+		return errors.NewCode(errors.Conflict, "kafka transport already running")
+	}
+
+	reader := kafkago.NewReader(kafkago.ReaderConfig{
+		Brokers:  t.cfg.Brokers,
+		Topic:    t.cfg.Topic,
+		GroupID:  t.cfg.GroupID,
+		MinBytes: 1,
+		MaxBytes: 10e6,
+	})
+	// 消费循环生命周期独立于 Start 的调用上下文，由 Stop 统一取消。
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	t.cancel = cancel
+	t.running = true
+	t.wg.Add(1)
+	go t.consume(runCtx, reader)
+	return nil
+}
+
+// Stop 停止消费循环并关闭 writer。
+func (t *Transport) Stop(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	t.mu.Lock()
+	if !t.running {
+		t.mu.Unlock()
+		return messaging.NewTransportAlreadyStoppedError("kafka transport already stopped")
+	}
+	t.running = false
+	cancel := t.cancel
+	t.cancel = nil
+	t.mu.Unlock()
+
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if err := t.writer.Close(); err != nil {
+		return errors.Wrap(err, errors.Dependency, "close kafka writer failed")
+	}
+	return nil
+}
+
+// Stats 返回当前订阅概况。
+func (t *Transport) Stats() messaging.TransportStats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	count := 0
+	types := make([]string, 0, len(t.handlers))
+	for messageType, handlers := range t.handlers {
+		if len(handlers) == 0 {
+			continue
+		}
+		count += len(handlers)
+		types = append(types, messageType)
+	}
+	return messaging.TransportStats{Running: t.running, HandlerCount: count, MessageTypes: types}
+}
+
+func (t *Transport) consume(ctx context.Context, reader *kafkago.Reader) {
+	defer t.wg.Done()
+	defer func() { _ = reader.Close() }()
+
+	backoff := t.cfg.MinReadBackoff
+	for {
+		record, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			t.logger.Warn(ctx, "kafka fetch failed", logging.Error(err), logging.String("topic", t.cfg.Topic))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, t.cfg.MaxReadBackoff)
+			continue
+		}
+		backoff = t.cfg.MinReadBackoff
+
+		t.dispatch(ctx, record)
+		if err := reader.CommitMessages(ctx, record); err != nil && ctx.Err() == nil {
+			t.logger.Warn(ctx, "kafka commit failed", logging.Error(err),
+				logging.Int64("offset", record.Offset),
+				logging.Int("partition", record.Partition))
+		}
+	}
+}
+
+func (t *Transport) dispatch(ctx context.Context, record kafkago.Message) {
+	message, err := decodeMessage(record.Value)
+	if err != nil {
+		// 无法解码的消息直接跳过，避免毒消息阻塞分区。
+		t.logger.Error(ctx, "kafka message decode failed", logging.Error(err),
+			logging.Int64("offset", record.Offset),
+			logging.Int("partition", record.Partition))
+		return
+	}
+
+	t.mu.RLock()
+	handlers := append([]messaging.IMessageHandler(nil), t.handlers[message.GetType()]...)
+	handlers = append(handlers, t.handlers["*"]...)
+	t.mu.RUnlock()
+	if len(handlers) == 0 {
+		return
+	}
+
+	handleCtx := ctx
+	if md := message.GetMetadata(); md != nil {
+		if derived, err := contextx.DeriveFromMetadata(handleCtx, md); err == nil && derived != nil {
+			handleCtx = derived
+		}
+	}
+	for _, handler := range handlers {
+		if err := handler.Handle(handleCtx, message); err != nil {
+			t.logger.Warn(handleCtx, "kafka message handler failed", logging.Error(err),
+				logging.String("message_type", message.GetType()),
+				logging.String("message_id", message.GetID()),
+				logging.String("handler", handler.Type()))
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package capability
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"gochen/errors"
+)
+
+// ShutdownPhase 标识 Host 优雅关闭的阶段；Host 按 ShutdownPhases 的顺序依次执行。
+type ShutdownPhase string
+
+const (
+	// ShutdownPhaseHTTP 停止接收 HTTP 请求，并等待进行中的请求完成。
+	ShutdownPhaseHTTP ShutdownPhase = "http"
+	// ShutdownPhaseDrain 排空进行中的命令（如命令总线、后台任务队列），Host 在此阶段停止 Workers。
+	ShutdownPhaseDrain ShutdownPhase = "drain"
+	// ShutdownPhaseModules 按注册倒序停止模块（事件订阅、投影、运行期组件、OnStop）。
+	ShutdownPhaseModules ShutdownPhase = "modules"
+	// ShutdownPhaseProjections 停止模块之外托管的投影。
+	ShutdownPhaseProjections ShutdownPhase = "projections"
+	// ShutdownPhaseOutbox 冲刷 Outbox 发布器（如最后一次 PublishPending 后 Stop）。
+	ShutdownPhaseOutbox ShutdownPhase = "outbox"
+	// ShutdownPhaseTransport 停止消息传输层。
+	ShutdownPhaseTransport ShutdownPhase = "transport"
+	// ShutdownPhaseDatabase 关闭数据库等最底层资源。
+	ShutdownPhaseDatabase ShutdownPhase = "database"
+)
+
+// ShutdownPhases 按执行顺序返回全部关闭阶段。
+func ShutdownPhases() []ShutdownPhase {
+	return []ShutdownPhase{
+		ShutdownPhaseHTTP,
+		ShutdownPhaseDrain,
+		ShutdownPhaseModules,
+		ShutdownPhaseProjections,
+		ShutdownPhaseOutbox,
+		ShutdownPhaseTransport,
+		ShutdownPhaseDatabase,
+	}
+}
+
+// ValidShutdownPhase 判断 phase 是否为已知的关闭阶段。
+func ValidShutdownPhase(phase ShutdownPhase) bool {
+	return slices.Contains(ShutdownPhases(), phase)
+}
+
+// ShutdownHook 是在指定关闭阶段执行的钩子。
+type ShutdownHook func(ctx context.Context) error
+
+// ShutdownHookRegistration 描述一个待注册的关闭钩子。
+type ShutdownHookRegistration struct {
+	Phase ShutdownPhase
+	Name  string
+	Hook  ShutdownHook
+}
+
+type namedShutdownHook struct {
+	name string
+	hook ShutdownHook
+}
+
+// ShutdownHooks 收集按阶段注册的关闭钩子（并发安全）。
+//
+// 同一阶段内的钩子按注册倒序执行（与 defer 一致）：后启动、依赖更多的组件先关闭。
+type ShutdownHooks struct {
+	mu    sync.Mutex
+	hooks map[ShutdownPhase][]namedShutdownHook
+}
+
+// NewShutdownHooks 创建关闭钩子注册表。
+func NewShutdownHooks() *ShutdownHooks {
+	return &ShutdownHooks{hooks: make(map[ShutdownPhase][]namedShutdownHook)}
+}
+
+// OnShutdown 在 phase 阶段注册一个钩子；name 用于错误诊断。
+func (h *ShutdownHooks) OnShutdown(phase ShutdownPhase, name string, hook ShutdownHook) error {
+	if h == nil {
+		return errors.NewCode(errors.InvalidInput, "shutdown hooks is nil")
+	}
+	if !ValidShutdownPhase(phase) {
+		return errors.NewCode(errors.InvalidInput, "unknown shutdown phase").
+			WithContext("phase", string(phase)).
+			WithContext("hook", name)
+	}
+	if hook == nil {
+		return errors.NewCode(errors.InvalidInput, "shutdown hook cannot be nil").
+			WithContext("phase", string(phase)).
+			WithContext("hook", name)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hooks[phase] = append(h.hooks[phase], namedShutdownHook{name: name, hook: hook})
+	return nil
+}
+
+// Run 按注册倒序执行 phase 阶段的钩子，并聚合返回错误。
+//
+// 成功的钩子被移除；失败的钩子保留，以便上层重试 Shutdown 时再次执行。
+func (h *ShutdownHooks) Run(ctx context.Context, phase ShutdownPhase) error {
+	if h == nil {
+		return nil
+	}
+
+	h.mu.Lock()
+	hooks := h.hooks[phase]
+	delete(h.hooks, phase)
+	h.mu.Unlock()
+
+	var (
+		errs      []error
+		remaining []namedShutdownHook
+	)
+	for i := len(hooks) - 1; i >= 0; i-- {
+		item := hooks[i]
+		if err := item.hook(ctx); err != nil {
+			errs = append(errs, errors.Wrap(err, errors.Internal, "shutdown hook failed").
+				WithContext("phase", string(phase)).
+				WithContext("hook", item.name))
+			remaining = append(remaining, item)
+		}
+	}
+
+	if len(remaining) > 0 {
+		// remaining 是倒序收集的，翻转后恢复注册顺序；期间新注册的钩子排在其后。
+		slices.Reverse(remaining)
+		h.mu.Lock()
+		h.hooks[phase] = append(remaining, h.hooks[phase]...)
+		h.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// Len 返回 phase 阶段尚未执行（或执行失败）的钩子数量。
+func (h *ShutdownHooks) Len(phase ShutdownPhase) int {
+	if h == nil {
+		return 0
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.hooks[phase])
+}
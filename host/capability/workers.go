@@ -0,0 +1,354 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/task"
+)
+
+// Worker 是由 Workers 托管的后台任务，应阻塞运行直到 ctx 结束。
+//
+// 返回 nil 表示任务正常完成，不再重启；返回错误或 panic 时按 WorkerPolicy 退避后重启。
+type Worker func(ctx context.Context) error
+
+// WorkerPolicy 定义后台任务的重启策略。
+type WorkerPolicy struct {
+	// InitialBackoff 是首次重启前的等待时间；<= 0 时使用 1s。
+	InitialBackoff time.Duration
+	// MaxBackoff 是退避等待的上限（每次失败翻倍）；<= 0 时使用 1m。
+	MaxBackoff time.Duration
+	// MaxRestarts 是最大连续重启次数；0 表示不限。
+	MaxRestarts int
+}
+
+const (
+	defaultWorkerInitialBackoff = time.Second
+	defaultWorkerMaxBackoff     = time.Minute
+	defaultWorkerStopTimeout    = 30 * time.Second
+)
+
+func (p WorkerPolicy) normalized() WorkerPolicy {
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = defaultWorkerInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaultWorkerMaxBackoff
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		p.MaxBackoff = p.InitialBackoff
+	}
+	if p.MaxRestarts < 0 {
+		p.MaxRestarts = 0
+	}
+	return p
+}
+
+// WorkerRegistration 描述一个待注册的后台任务。
+type WorkerRegistration struct {
+	Name   string
+	Run    Worker
+	Policy WorkerPolicy
+}
+
+// WorkerState 表示后台任务的当前状态。
+type WorkerState string
+
+const (
+	// WorkerStatePending 表示已注册、尚未启动。
+	WorkerStatePending WorkerState = "pending"
+	// WorkerStateRunning 表示正在运行。
+	WorkerStateRunning WorkerState = "running"
+	// WorkerStateBackoff 表示失败后等待重启。
+	WorkerStateBackoff WorkerState = "backoff"
+	// WorkerStateCompleted 表示任务返回 nil 后正常结束。
+	WorkerStateCompleted WorkerState = "completed"
+	// WorkerStateFailed 表示重启次数耗尽后放弃。
+	WorkerStateFailed WorkerState = "failed"
+	// WorkerStateStopped 表示随 Workers.Stop 停止。
+	WorkerStateStopped WorkerState = "stopped"
+)
+
+// WorkerStatus 是后台任务的运行状态快照。
+type WorkerStatus struct {
+	Name          string
+	State         WorkerState
+	Restarts      int
+	LastError     error
+	LastFailureAt time.Time
+}
+
+type workerEntry struct {
+	reg    WorkerRegistration
+	status WorkerStatus
+}
+
+// Workers 是 Host 的后台任务注册表：Outbox 发布器、投影追赶循环、调度器与模块自定义任务在此登记，
+// 由 Host 在 StartBackground 末尾统一启动，并在 ShutdownPhaseDrain 阶段停止（并发安全）。
+//
+// 说明：
+// - 每个任务运行在独立的受监督 goroutine 中，panic 被恢复并视为失败；
+// - 失败后按 WorkerPolicy 指数退避重启；单次运行超过 MaxBackoff 视为恢复健康，退避重新从 InitialBackoff 计算；
+// - 启动后注册的任务立即启动。
+type Workers struct {
+	logger logging.ILogger
+	clock  clock.IClock
+
+	mu         sync.Mutex
+	entries    []*workerEntry
+	supervisor *task.TaskSupervisor
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+// NewWorkers 创建后台任务注册表；logger 为 nil 时使用 noop logger。
+func NewWorkers(logger logging.ILogger) *Workers {
+	if logger == nil {
+		logger = logging.NewNoopLogger()
+	}
+	return &Workers{logger: logger, clock: clock.NewRealClock()}
+}
+
+// Register 登记一个后台任务；名称不能为空且不能重复。
+func (w *Workers) Register(reg WorkerRegistration) error {
+	if w == nil {
+		return errors.NewCode(errors.InvalidInput, "workers is nil")
+	}
+	reg.Name = strings.TrimSpace(reg.Name)
+	if reg.Name == "" {
+		return errors.NewCode(errors.InvalidInput, "worker name cannot be empty")
+	}
+	if reg.Run == nil {
+		return errors.NewCode(errors.InvalidInput, "worker cannot be nil").WithContext("worker", reg.Name)
+	}
+	reg.Policy = reg.Policy.normalized()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, entry := range w.entries {
+		if entry.reg.Name == reg.Name {
+			return errors.NewCode(errors.Duplicate, "worker already registered").WithContext("worker", reg.Name)
+		}
+	}
+	entry := &workerEntry{reg: reg, status: WorkerStatus{Name: reg.Name, State: WorkerStatePending}}
+	w.entries = append(w.entries, entry)
+	if w.supervisor != nil {
+		return w.launchLocked(entry)
+	}
+	return nil
+}
+
+// Start 启动全部已登记的任务；重复调用无副作用。
+//
+// 任务运行在与 ctx 取消信号分离的 context 中，只在 Stop 时取消。
+func (w *Workers) Start(ctx context.Context) error {
+	if w == nil {
+		return nil
+	}
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.supervisor != nil {
+		return nil
+	}
+	w.supervisor = task.NewTaskSupervisorWithClock("host.workers", w.logger, w.clock)
+	w.ctx, w.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	for _, entry := range w.entries {
+		if err := w.launchLocked(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop 取消全部任务并等待其在 ctx 截止前退出。
+func (w *Workers) Stop(ctx context.Context) error {
+	if w == nil {
+		return nil
+	}
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+
+	w.mu.Lock()
+	supervisor, cancel := w.supervisor, w.cancel
+	w.supervisor, w.ctx, w.cancel = nil, nil, nil
+	w.mu.Unlock()
+	if supervisor == nil {
+		return nil
+	}
+
+	cancel()
+	if err := supervisor.Stop(ctx); err != nil {
+		return errors.Wrap(err, errors.Timeout, "failed to stop background workers")
+	}
+	return nil
+}
+
+// Status 按注册顺序返回全部任务的状态快照。
+func (w *Workers) Status() []WorkerStatus {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	out := make([]WorkerStatus, 0, len(w.entries))
+	for _, entry := range w.entries {
+		out = append(out, entry.status)
+	}
+	return out
+}
+
+func (w *Workers) launchLocked(entry *workerEntry) error {
+	entry.status.State = WorkerStateRunning
+	if err := w.supervisor.Go(w.ctx, entry.reg.Name, func(ctx context.Context) {
+		w.supervise(ctx, entry)
+	}); err != nil {
+		return errors.Wrap(err, errors.Internal, "failed to start worker").WithContext("worker", entry.reg.Name)
+	}
+	return nil
+}
+
+// supervise 运行任务并在失败时按策略退避重启，直到任务完成、重启次数耗尽或 ctx 取消。
+func (w *Workers) supervise(ctx context.Context, entry *workerEntry) {
+	policy := entry.reg.Policy
+	backoff := policy.InitialBackoff
+	restarts := 0
+	for {
+		w.setState(entry, WorkerStateRunning)
+		startedAt := w.clock.Now()
+		err := w.runOnce(ctx, entry.reg)
+
+		if ctx.Err() != nil {
+			w.setState(entry, WorkerStateStopped)
+			return
+		}
+		if err == nil {
+			w.setState(entry, WorkerStateCompleted)
+			return
+		}
+
+		if w.clock.Now().Sub(startedAt) > policy.MaxBackoff {
+			backoff = policy.InitialBackoff
+			restarts = 0
+		}
+		w.recordFailure(entry, err)
+		if policy.MaxRestarts > 0 && restarts >= policy.MaxRestarts {
+			w.setState(entry, WorkerStateFailed)
+			w.logger.Error(ctx, "background worker gave up after restarts",
+				logging.String("worker", entry.reg.Name),
+				logging.Int("restarts", restarts),
+				logging.Error(err))
+			return
+		}
+
+		w.setState(entry, WorkerStateBackoff)
+		w.logger.Warn(ctx, "background worker failed, restarting",
+			logging.String("worker", entry.reg.Name),
+			logging.Int("restarts", restarts),
+			logging.Duration("backoff", backoff),
+			logging.Error(err))
+
+		timer := w.clock.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			w.setState(entry, WorkerStateStopped)
+			return
+		case <-timer.C():
+		}
+
+		restarts++
+		w.mu.Lock()
+		entry.status.Restarts++
+		w.mu.Unlock()
+		backoff = min(backoff*2, policy.MaxBackoff)
+	}
+}
+
+// runOnce 执行一次任务，并把 panic 转换为错误。
+func (w *Workers) runOnce(ctx context.Context, reg WorkerRegistration) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.NewCode(errors.Internal, "worker panic").
+				WithContext("worker", reg.Name).
+				WithContext("panic", fmt.Sprint(r))
+			w.logger.Error(ctx, "background worker panic recovered",
+				logging.String("worker", reg.Name),
+				logging.Any("panic", r),
+				logging.String("stack", string(debug.Stack())))
+		}
+	}()
+	return reg.Run(ctx)
+}
+
+func (w *Workers) setState(entry *workerEntry, state WorkerState) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	entry.status.State = state
+}
+
+func (w *Workers) recordFailure(entry *workerEntry, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	entry.status.LastError = err
+	entry.status.LastFailureAt = w.clock.Now()
+}
+
+// StartStopWorker 把“Start 后台启动、Stop 停止”的组件（如 outbox.Publisher）适配为 Worker。
+//
+// Start 返回后阻塞到 ctx 结束，再调用 Stop（若组件实现 IRuntimeStopper）。
+func StartStopWorker(component IRuntimeComponent) Worker {
+	return func(ctx context.Context) error {
+		if component == nil {
+			return errors.NewCode(errors.InvalidInput, "runtime component is nil")
+		}
+		if err := component.Start(ctx); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		stopper, ok := component.(IRuntimeStopper)
+		if !ok {
+			return nil
+		}
+		stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), defaultWorkerStopTimeout)
+		defer cancel()
+		return stopper.Stop(stopCtx)
+	}
+}
+
+// LoopWorker 把周期任务（如投影追赶、定时调度）适配为 Worker：立即执行一次，之后每隔 interval 执行。
+//
+// fn 返回错误时结束本轮运行，由 Workers 按退避策略重启。
+func LoopWorker(interval time.Duration, fn func(ctx context.Context) error) Worker {
+	return func(ctx context.Context) error {
+		if fn == nil {
+			return errors.NewCode(errors.InvalidInput, "loop worker fn is nil")
+		}
+		if interval <= 0 {
+			return errors.NewCode(errors.InvalidInput, "loop worker interval must be positive")
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := fn(ctx); err != nil {
+				return err
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	}
+}
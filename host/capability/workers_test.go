@@ -0,0 +1,124 @@
+package capability
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gochen/errors"
+)
+
+func waitWorkerState(t *testing.T, w *Workers, name string, state WorkerState) WorkerStatus {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, status := range w.Status() {
+			if status.Name == name && status.State == state {
+				return status
+			}
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("worker %q did not reach state %q, got: %+v", name, state, w.Status())
+	return WorkerStatus{}
+}
+
+func TestWorkers_RestartsOnPanicWithBackoffUntilMaxRestarts(t *testing.T) {
+	w := NewWorkers(nil)
+	var runs atomic.Int32
+	if err := w.Register(WorkerRegistration{
+		Name: "flaky",
+		Run: func(context.Context) error {
+			runs.Add(1)
+			panic("boom")
+		},
+		Policy: WorkerPolicy{InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond, MaxRestarts: 2},
+	}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := w.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	status := waitWorkerState(t, w, "flaky", WorkerStateFailed)
+	if got := runs.Load(); got != 3 {
+		t.Fatalf("expected 1 run + 2 restarts, got %d runs", got)
+	}
+	if status.Restarts != 2 {
+		t.Fatalf("expected 2 restarts, got %d", status.Restarts)
+	}
+	if !errors.Is(status.LastError, errors.Internal) || status.LastFailureAt.IsZero() {
+		t.Fatalf("expected panic to be recorded as Internal failure, got: %+v", status)
+	}
+	if err := w.Stop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+}
+
+func TestWorkers_CompletedWorkerIsNotRestartedAndStopCancelsRunning(t *testing.T) {
+	w := NewWorkers(nil)
+	var onceRuns atomic.Int32
+	stopped := make(chan struct{})
+	if err := w.Register(WorkerRegistration{Name: "once", Run: func(context.Context) error {
+		onceRuns.Add(1)
+		return nil
+	}}); err != nil {
+		t.Fatalf("register once: %v", err)
+	}
+	if err := w.Register(WorkerRegistration{Name: "once", Run: func(context.Context) error { return nil }}); !errors.Is(err, errors.Duplicate) {
+		t.Fatalf("expected Duplicate for repeated name, got: %v", err)
+	}
+	if err := w.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	// 启动后注册的任务立即运行。
+	if err := w.Register(WorkerRegistration{Name: "loop", Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		close(stopped)
+		return nil
+	}}); err != nil {
+		t.Fatalf("register loop: %v", err)
+	}
+
+	waitWorkerState(t, w, "once", WorkerStateCompleted)
+	waitWorkerState(t, w, "loop", WorkerStateRunning)
+	if err := w.Stop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Fatal("expected Stop to cancel and wait for running worker")
+	}
+	waitWorkerState(t, w, "loop", WorkerStateStopped)
+	if got := onceRuns.Load(); got != 1 {
+		t.Fatalf("expected completed worker to run once, got %d", got)
+	}
+}
+
+type recordingComponent struct {
+	started, stopped atomic.Bool
+}
+
+func (c *recordingComponent) Start(context.Context) error { c.started.Store(true); return nil }
+func (c *recordingComponent) Stop(context.Context) error  { c.stopped.Store(true); return nil }
+
+func TestWorkers_StartStopWorkerAdapter(t *testing.T) {
+	w := NewWorkers(nil)
+	component := &recordingComponent{}
+	if err := w.Register(WorkerRegistration{Name: "publisher", Run: StartStopWorker(component)}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := w.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	waitWorkerState(t, w, "publisher", WorkerStateRunning)
+	if err := w.Stop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if !component.started.Load() || !component.stopped.Load() {
+		t.Fatalf("expected component to be started and stopped, started=%v stopped=%v", component.started.Load(), component.stopped.Load())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	auth "gochen/auth"
+	"gochen/config"
 	"gochen/di"
 	deventsourced "gochen/domain/eventsourced"
 	"gochen/host/capability"
@@ -27,6 +28,23 @@ type IModule = module.IModule
 // 仅在组合根需要覆盖某个模块的路由前缀或中间件时使用。
 type ModuleHTTPConfig = runtime.ModuleHTTPConfig
 
+// ShutdownPhase 标识 Host 优雅关闭的阶段。
+type ShutdownPhase = capability.ShutdownPhase
+
+// 关闭阶段按声明顺序执行（见 WithShutdownHook）。
+const (
+	ShutdownPhaseHTTP        = capability.ShutdownPhaseHTTP
+	ShutdownPhaseDrain       = capability.ShutdownPhaseDrain
+	ShutdownPhaseModules     = capability.ShutdownPhaseModules
+	ShutdownPhaseProjections = capability.ShutdownPhaseProjections
+	ShutdownPhaseOutbox      = capability.ShutdownPhaseOutbox
+	ShutdownPhaseTransport   = capability.ShutdownPhaseTransport
+	ShutdownPhaseDatabase    = capability.ShutdownPhaseDatabase
+)
+
+// WorkerPolicy 定义后台任务的退避重启策略。
+type WorkerPolicy = capability.WorkerPolicy
+
 // Option 配置标准模块化 Host 启动入口。
 type Option func(*options)
 
@@ -106,6 +124,27 @@ func WithShutdownTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithShutdownHook 在指定关闭阶段追加一个钩子，例如 ShutdownPhaseDatabase 关闭数据库。
+func WithShutdownHook(phase ShutdownPhase, name string, hook func(ctx context.Context) error) Option {
+	return func(o *options) {
+		o.hostOptions = append(o.hostOptions, runtime.WithShutdownHook(phase, name, hook))
+	}
+}
+
+// WithWorker 追加一个受监督的后台任务，例如用 capability.StartStopWorker 托管 Outbox 发布器。
+func WithWorker(name string, run func(ctx context.Context) error, policy WorkerPolicy) Option {
+	return func(o *options) {
+		o.hostOptions = append(o.hostOptions, runtime.WithWorker(name, run, policy))
+	}
+}
+
+// WithShutdownPhaseTimeout 为指定关闭阶段设置独立超时（整体仍受 WithShutdownTimeout 约束）。
+func WithShutdownPhaseTimeout(phase ShutdownPhase, timeout time.Duration) Option {
+	return func(o *options) {
+		o.hostOptions = append(o.hostOptions, runtime.WithShutdownPhaseTimeout(phase, timeout))
+	}
+}
+
 // WithLogger 设置生命周期引擎日志实现。
 func WithLogger(logger logging.ILogger) Option {
 	return func(o *options) {
@@ -188,6 +227,22 @@ func WithBasePath(basePath string) Option {
 	}
 }
 
+// WithSettings 注入已加载的框架标准配置（Server 段覆盖名称与监听配置，并注册到 DI 容器）。
+func WithSettings(settings *config.Settings) Option {
+	return func(o *options) {
+		o.hostOptions = append(o.hostOptions, runtime.WithSettings(settings))
+	}
+}
+
+// WithConfigSources 在 LoadConfig 阶段按给定来源分层加载框架标准配置，例如：
+//
+//	host.WithConfigSources(config.WithOptionalFile("config.yaml"), config.WithEnvPrefix("APP_"), config.WithFlags(flag.CommandLine))
+func WithConfigSources(sources ...config.LoadOption) Option {
+	return func(o *options) {
+		o.hostOptions = append(o.hostOptions, runtime.WithConfigSources(sources...))
+	}
+}
+
 // WithContainer 注入自定义 DI 容器。
 func WithContainer(container di.IContainer) Option {
 	return func(o *options) {
@@ -251,6 +306,13 @@ func WithRouteMiddlewares(middlewares ...httpx.Middleware) Option {
 	}
 }
 
+// WithRequestScope 为每个 HTTP 请求创建 DI 作用域，handler 内经 di.ResolveScoped[T](ctx) 解析 scoped 服务。
+func WithRequestScope(enabled bool) Option {
+	return func(o *options) {
+		o.hostOptions = append(o.hostOptions, runtime.WithRequestScope(enabled))
+	}
+}
+
 // WithDisableHealthRoute 控制是否跳过框架默认健康检查路由。
 func WithDisableHealthRoute(disable bool) Option {
 	return func(o *options) {
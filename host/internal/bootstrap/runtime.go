@@ -1,10 +1,12 @@
 package bootstrap
 
 import (
+	"context"
 	dibasic "gochen/di/basic"
 	"net/http"
 	"reflect"
 	"strings"
+	"time"
 
 	"gochen/di"
 	"gochen/errors"
@@ -64,6 +66,11 @@ func Prepare(cfg Config) (*Runtime, error) {
 	if err := prepareHTTP(rt, cfg); err != nil {
 		return nil, err
 	}
+	if !cfg.DisableHealthRoute {
+		if err := registerTransportHealthCheck(rt.Transport); err != nil {
+			return nil, err
+		}
+	}
 
 	baseGroup, err := prepareBaseGroup(rt.HTTPServer, cfg)
 	if err != nil {
@@ -186,8 +193,17 @@ func registerMonitoringRoutes(httpServer httpx.IServer) {
 		return ctx.JSON(code, httpx.JSONValue(report))
 	})
 
+	httpServer.GET("/livez", func(ctx httpx.IContext) error {
+		report := reg.Health.ReportProbe(ctx.RequestContext(), monitoring.ProbeLiveness)
+		code := http.StatusOK
+		if report.Status == monitoring.HealthStatusUnhealthy {
+			code = http.StatusServiceUnavailable
+		}
+		return ctx.JSON(code, httpx.JSONValue(report))
+	})
+
 	httpServer.GET("/readyz", func(ctx httpx.IContext) error {
-		report := reg.Health.Report(ctx.RequestContext())
+		report := reg.Health.ReportProbe(ctx.RequestContext(), monitoring.ProbeReadiness)
 		code := http.StatusOK
 		if report.Status == monitoring.HealthStatusUnhealthy {
 			code = http.StatusServiceUnavailable
@@ -210,6 +226,45 @@ func registerMonitoringRoutes(httpServer httpx.IServer) {
 	})
 }
 
+// TransportHealthCheckName 是 Host 为消息传输自动注册的就绪检查名。
+const TransportHealthCheckName = "messaging.transport"
+
+const transportPingTimeout = 2 * time.Second
+
+type transportStatsProvider interface {
+	Stats() messaging.TransportStats
+}
+
+// registerTransportHealthCheck 把消息传输的连通性注册为就绪检查：未运行时 unhealthy；
+// 传输实现 monitoring.IPinger 时运行中再主动探活。
+func registerTransportHealthCheck(transport capability.ITransport) error {
+	if transport == nil || runtimeutil.IsTypedNil(transport) {
+		return nil
+	}
+	stats, ok := transport.(transportStatsProvider)
+	if !ok {
+		return nil
+	}
+	running, err := monitoring.RunningHealthCheck(func() bool { return stats.Stats().Running })
+	if err != nil {
+		return err
+	}
+	check := running
+	if pinger, ok := transport.(monitoring.IPinger); ok {
+		ping, err := monitoring.PingHealthCheck(pinger, transportPingTimeout)
+		if err != nil {
+			return err
+		}
+		check = func(ctx context.Context) (monitoring.HealthStatus, string, error) {
+			if status, msg, err := running(ctx); status != monitoring.HealthStatusHealthy || err != nil {
+				return status, msg, err
+			}
+			return ping(ctx)
+		}
+	}
+	return monitoring.DefaultRegistry().Health.RegisterProbe(TransportHealthCheckName, check, monitoring.ProbeReadiness)
+}
+
 func normalizeBasePath(basePath string) string {
 	if basePath == "" {
 		return "/api/v1"
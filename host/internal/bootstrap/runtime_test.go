@@ -7,7 +7,9 @@ import (
 	"testing"
 
 	"gochen/di"
+	"gochen/errors"
 	"gochen/eventing/bus"
+	"gochen/eventing/monitoring"
 	"gochen/eventing/projection"
 	"gochen/messaging"
 )
@@ -123,3 +125,56 @@ func TestAssemblyHelpers_UseTypedDIProtocol(t *testing.T) {
 		t.Fatalf("expected typed DI protocol only, got forbidden calls in helper path: %v", other.forbiddenCalls)
 	}
 }
+
+type runningTransport struct {
+	mockTransport
+	running bool
+	pingErr error
+}
+
+func (m *runningTransport) Stats() messaging.TransportStats {
+	return messaging.TransportStats{Running: m.running}
+}
+
+func (m *runningTransport) Ping(context.Context) error { return m.pingErr }
+
+func TestRegisterTransportHealthCheck_ReadinessReflectsTransport(t *testing.T) {
+	health := monitoring.DefaultRegistry().Health
+	t.Cleanup(func() { health.Unregister(TransportHealthCheckName) })
+
+	transport := &runningTransport{}
+	if err := registerTransportHealthCheck(transport); err != nil {
+		t.Fatalf("register transport health check failed: %v", err)
+	}
+
+	transportCheck := func(report monitoring.HealthReport) monitoring.CheckResult {
+		t.Helper()
+		for _, c := range report.Checks {
+			if c.Name == TransportHealthCheckName {
+				return c
+			}
+		}
+		t.Fatalf("expected %s check in report, got=%v", TransportHealthCheckName, report.Checks)
+		return monitoring.CheckResult{}
+	}
+
+	ctx := context.Background()
+	if got := transportCheck(health.ReportProbe(ctx, monitoring.ProbeReadiness)); got.Status != monitoring.HealthStatusUnhealthy {
+		t.Fatalf("expected stopped transport to be unhealthy, got=%v", got)
+	}
+	for _, c := range health.ReportProbe(ctx, monitoring.ProbeLiveness).Checks {
+		if c.Name == TransportHealthCheckName {
+			t.Fatalf("transport check must not participate in liveness")
+		}
+	}
+
+	transport.running = true
+	if got := transportCheck(health.ReportProbe(ctx, monitoring.ProbeReadiness)); got.Status != monitoring.HealthStatusHealthy {
+		t.Fatalf("expected running transport to be healthy, got=%v", got)
+	}
+
+	transport.pingErr = errors.NewCode(errors.Internal, "broker unreachable")
+	if got := transportCheck(health.ReportProbe(ctx, monitoring.ProbeReadiness)); got.Status != monitoring.HealthStatusUnhealthy {
+		t.Fatalf("expected ping failure to be unhealthy, got=%v", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	auth "gochen/auth"
 	"gochen/di"
+	"gochen/host/capability"
 	"gochen/host/module"
 	"gochen/httpx"
 )
@@ -68,6 +69,9 @@ type ModuleDescriptor struct {
 	// Name 模块的展示名称。
 	Name string
 
+	// DependsOn 必须先于本模块初始化/启动的模块 ID（可选），Host 据此拓扑排序。
+	DependsOn []string
+
 	// Registrations 显式注册列表。
 	Registrations []Registration
 
@@ -91,4 +95,10 @@ type ModuleDescriptor struct {
 
 	// OnStop 停止钩子（可选）。
 	OnStop func(ctx context.Context) error
+
+	// ShutdownHooks 按关闭阶段注册的钩子（可选），在 Init 阶段登记到 Host。
+	ShutdownHooks []capability.ShutdownHookRegistration
+
+	// Workers 受监督的后台任务（可选），在 Init 阶段登记到 Host；任务名以 "{模块 ID}." 为前缀。
+	Workers []capability.WorkerRegistration
 }
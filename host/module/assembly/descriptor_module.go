@@ -2,6 +2,7 @@ package assembly
 
 import (
 	auth "gochen/auth"
+	"gochen/errors"
 	"gochen/host/capability"
 	"gochen/host/module"
 	"gochen/host/module/runtimecap"
@@ -34,6 +35,11 @@ func (m *explicitModule) Name() string {
 	return m.desc.Name
 }
 
+// DependsOn 返回模块声明的依赖模块 ID。
+func (m *explicitModule) DependsOn() []string {
+	return append([]string(nil), m.desc.DependsOn...)
+}
+
 // AuthzRegistration 返回模块声明式 authz 目录。
 func (m *explicitModule) AuthzRegistration() auth.ModuleRegistration {
 	return auth.ModuleRegistration{
@@ -68,6 +74,13 @@ func (m *explicitModule) Init(opts module.ModuleInitOptions) error {
 		m.classifyByRole(i, reg)
 	}
 
+	if err := m.registerShutdownHooks(opts); err != nil {
+		return err
+	}
+	if err := m.registerWorkers(opts); err != nil {
+		return err
+	}
+
 	if m.desc.OnInit != nil {
 		if err := m.desc.OnInit(opts); err != nil {
 			return wrapModuleErr(m.desc.ID, err, "module init hook")
@@ -76,3 +89,50 @@ func (m *explicitModule) Init(opts module.ModuleInitOptions) error {
 
 	return nil
 }
+
+// registerShutdownHooks 把描述符声明的关闭钩子登记到 Host 提供的注册表。
+func (m *explicitModule) registerShutdownHooks(opts module.ModuleInitOptions) error {
+	if len(m.desc.ShutdownHooks) == 0 {
+		return nil
+	}
+	hooks := runtimecap.ShutdownHooksFrom(opts)
+	if hooks == nil {
+		return errors.NewCode(errors.InvalidInput, "shutdown hooks configured but host provides no shutdown registry").
+			WithContext("module", m.desc.ID).
+			WithContext("shutdown_hooks_count", len(m.desc.ShutdownHooks))
+	}
+	for i, reg := range m.desc.ShutdownHooks {
+		name := reg.Name
+		if name == "" {
+			name = m.desc.ID
+		}
+		if err := hooks.OnShutdown(reg.Phase, name, reg.Hook); err != nil {
+			return wrapModuleErr(m.desc.ID, err, "register shutdown hook").WithContext("index", i)
+		}
+	}
+	return nil
+}
+
+// registerWorkers 把描述符声明的后台任务登记到 Host 提供的注册表。
+func (m *explicitModule) registerWorkers(opts module.ModuleInitOptions) error {
+	if len(m.desc.Workers) == 0 {
+		return nil
+	}
+	workers := runtimecap.WorkersFrom(opts)
+	if workers == nil {
+		return errors.NewCode(errors.InvalidInput, "workers configured but host provides no worker registry").
+			WithContext("module", m.desc.ID).
+			WithContext("workers_count", len(m.desc.Workers))
+	}
+	for i, reg := range m.desc.Workers {
+		if reg.Name == "" {
+			reg.Name = m.desc.ID
+		} else {
+			reg.Name = m.desc.ID + "." + reg.Name
+		}
+		if err := workers.Register(reg); err != nil {
+			return wrapModuleErr(m.desc.ID, err, "register worker").WithContext("index", i)
+		}
+	}
+	return nil
+}
@@ -99,5 +99,8 @@ func (m *explicitModule) stop(ctx context.Context) error {
 	return m.runtime.Stop(ctx, m.desc.OnStop)
 }
 
-// Ensure explicitModule implements IRouteModule
-var _ module.IRouteModule = (*explicitModule)(nil)
+// Ensure explicitModule implements IRouteModule and IModuleDependencyProvider
+var (
+	_ module.IRouteModule              = (*explicitModule)(nil)
+	_ module.IModuleDependencyProvider = (*explicitModule)(nil)
+)
@@ -206,6 +206,7 @@ func TestHost_ModuleInitAndStart_HTTPOptionsAndMiddlewares(t *testing.T) {
 	}
 
 	foundHealthz := false
+	foundLivez := false
 	foundReadyz := false
 	foundMetrics := false
 	foundSnapshot := false
@@ -213,6 +214,8 @@ func TestHost_ModuleInitAndStart_HTTPOptionsAndMiddlewares(t *testing.T) {
 		switch r {
 		case "GET /healthz":
 			foundHealthz = true
+		case "GET /livez":
+			foundLivez = true
 		case "GET /readyz":
 			foundReadyz = true
 		case "GET /metrics":
@@ -221,7 +224,7 @@ func TestHost_ModuleInitAndStart_HTTPOptionsAndMiddlewares(t *testing.T) {
 			foundSnapshot = true
 		}
 	}
-	if !foundHealthz || !foundReadyz || !foundMetrics || !foundSnapshot {
+	if !foundHealthz || !foundLivez || !foundReadyz || !foundMetrics || !foundSnapshot {
 		t.Fatalf("expected monitoring routes to be registered on server, got=%v", httpServer.registeredGET)
 	}
 
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"gochen/di"
+	"gochen/errors"
 )
 
 type depModule struct {
@@ -108,3 +109,24 @@ func TestHost_StartBackground_FailsFastOnContainerCircularDependencies(t *testin
 		t.Fatalf("expected StartBackground to fail on circular dependencies")
 	}
 }
+
+func TestHost_ModuleDependencies_CycleErrorReportsPath(t *testing.T) {
+	a := &depModule{BaseModule: &BaseModule{ModuleID: "a", ModuleName: "A"}, deps: []string{"b"}}
+	b := &depModule{BaseModule: &BaseModule{ModuleID: "b", ModuleName: "B"}, deps: []string{"c"}}
+	c := &depModule{BaseModule: &BaseModule{ModuleID: "c", ModuleName: "C"}, deps: []string{"b"}}
+
+	srv := moduleruntime.NewHost([]ModuleCtor{
+		func() (IModule, error) { return a, nil },
+		func() (IModule, error) { return b, nil },
+		func() (IModule, error) { return c, nil },
+	})
+
+	err := srv.Prepare(context.Background())
+	var appErr *errors.AppError
+	if !errors.As(err, &appErr) {
+		t.Fatalf("expected AppError, got %v", err)
+	}
+	if got := appErr.Details()["cycle"]; got != "b -> c -> b" {
+		t.Fatalf("unexpected cycle path: %v", got)
+	}
+}
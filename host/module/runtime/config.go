@@ -3,8 +3,10 @@ package runtime
 import (
 	dibasic "gochen/di/basic"
 	"strings"
+	"time"
 
 	auth "gochen/auth"
+	"gochen/config"
 	"gochen/di"
 	deventsourced "gochen/domain/eventsourced"
 	"gochen/host/capability"
@@ -47,6 +49,15 @@ type HostConfig struct {
 	Port     int
 	BasePath string
 
+	// Settings 是框架标准配置（可选），在 LoadConfig 阶段生效并以 *config.Settings 注册到 DI 容器。
+	//
+	// 说明：
+	// - 非 nil 时 Settings.Server 覆盖 Name/Host/Port/BasePath（以配置文件/环境变量为准）；
+	// - 模块可解析 *config.Settings，用 DBConfig()、outbox.ConfigFromSettings、projection.ConfigFromSettings 构造组件。
+	Settings *config.Settings
+	// ConfigSources 非空时，LoadConfig 以这些来源经 config.Load 分层加载 Settings（覆盖已设置的 Settings）。
+	ConfigSources []config.LoadOption
+
 	// 可选：外部注入的基础组件
 	Container         di.IContainer
 	EventBus          capability.IEventSubscriber
@@ -63,14 +74,20 @@ type HostConfig struct {
 	// 再由模块在 Start(ctx) 阶段挂载路由。
 	RouteMiddlewares []httpx.Middleware
 
+	// RequestScope 为 true 时，在 RouteMiddlewares 之后挂载 httpx/middleware.RequestScope：
+	// 每个请求创建一个 DI 作用域（Container 需实现 di.IScopeFactory），请求结束时释放。
+	RequestScope bool
+
 	// DisableHealthRoute 为 true 时不注册默认的健康检查路由。
 	//
 	// 说明：
 	// - module.Host 默认会在根路由注册以下监控端点（与 eventing/monitoring 口径对齐）：
-	// - - GET /healthz   : 健康报告（unhealthy -> 503）
-	// - - GET /readyz    : 就绪探针（当前与 healthz 语义一致）
+	// - - GET /healthz   : 全部检查的健康报告（unhealthy -> 503）
+	// - - GET /livez     : 存活探针，只执行 monitoring.ProbeLiveness 检查
+	// - - GET /readyz    : 就绪探针，只执行 monitoring.ProbeReadiness 检查（Register 注册的检查默认只参与就绪）
 	// - - GET /metrics   : 指标快照（Summary）
 	// - - GET /snapshot  : 聚合快照（指标 + 健康 + 可选扩展）
+	// - 配置了实现 Stats() 的 Transport 时，自动注册就绪检查 "messaging.transport"（未运行 -> unhealthy）。
 	// - 某些应用可能已自行挂载监控路由或需要对监控端点做鉴权/网关隔离，此时可关闭默认路由并在组合根显式装配。
 	DisableHealthRoute bool
 
@@ -81,6 +98,25 @@ type HostConfig struct {
 	// - 对于不支持路由注册记录的 HTTPServer，实现可能无法检测冲突（会跳过）。
 	FailFastOnRouteConflicts bool
 
+	// ShutdownHooks 是组合根注册的关闭钩子（可选），在 Prepare 阶段登记。
+	//
+	// 说明：
+	// - 关闭阶段与顺序见 capability.ShutdownPhases；同一阶段内按注册倒序执行；
+	// - 典型：ShutdownPhaseDrain 等待命令处理完成，ShutdownPhaseOutbox 最后一次 PublishPending，ShutdownPhaseDatabase 关闭数据库；
+	// - 模块可通过 host.Module(...).OnShutdown 或 runtimecap.ShutdownHooksFrom 注册自己的钩子。
+	ShutdownHooks []capability.ShutdownHookRegistration
+
+	// Workers 是组合根注册的后台任务（可选），如 Outbox 发布器、投影追赶循环与调度器。
+	//
+	// 说明：
+	// - 在 StartBackground 末尾（消息传输层启动之后）统一启动，panic 或失败时按 WorkerPolicy 退避重启；
+	// - 在 ShutdownPhaseDrain 阶段停止；
+	// - 模块可通过 host.Module(...).Worker 或 runtimecap.WorkersFrom 注册自己的任务。
+	Workers []capability.WorkerRegistration
+
+	// ShutdownPhaseTimeouts 为关闭阶段设置独立超时（可选）；未配置的阶段只受 Shutdown ctx（引擎的 ShutdownTimeout）约束。
+	ShutdownPhaseTimeouts map[capability.ShutdownPhase]time.Duration
+
 	// ModuleHTTP 定义模块级 HTTP 挂载配置（按模块 ID() 索引）。
 	//
 	// 说明：
@@ -148,6 +184,25 @@ func WithBasePath(basePath string) Option {
 	}
 }
 
+// WithSettings 注入已加载的框架标准配置。
+func WithSettings(settings *config.Settings) Option {
+	return func(cfg *HostConfig) {
+		if settings != nil {
+			cfg.Settings = settings
+		}
+	}
+}
+
+// WithConfigSources 追加框架标准配置的加载来源，LoadConfig 阶段按“default <- 文件 <- 环境变量 <- 命令行参数”分层加载。
+func WithConfigSources(sources ...config.LoadOption) Option {
+	return func(cfg *HostConfig) {
+		if len(sources) == 0 {
+			return
+		}
+		cfg.ConfigSources = append(cfg.ConfigSources, sources...)
+	}
+}
+
 // WithContainer 注入自定义 DI 容器。
 //
 // 典型用法是在组合根提前注册数据库、ORM、配置等基础设施，再交给 Host 继续装配模块。
@@ -234,6 +289,16 @@ func WithRouteMiddlewares(middlewares ...httpx.Middleware) Option {
 	}
 }
 
+// WithRequestScope 控制是否为每个 HTTP 请求创建 DI 作用域。
+func WithRequestScope(enabled bool) Option {
+	return func(cfg *HostConfig) {
+		if cfg == nil {
+			return
+		}
+		cfg.RequestScope = enabled
+	}
+}
+
 // WithDisableHealthRoute 控制是否跳过框架默认注册的健康检查路由。
 func WithDisableHealthRoute(disable bool) Option {
 	return func(cfg *HostConfig) {
@@ -254,6 +319,51 @@ func WithFailFastOnRouteConflicts(enabled bool) Option {
 	}
 }
 
+// WithShutdownHook 在指定关闭阶段追加一个钩子（name 用于错误诊断）。
+func WithShutdownHook(phase capability.ShutdownPhase, name string, hook capability.ShutdownHook) Option {
+	return func(cfg *HostConfig) {
+		if cfg == nil {
+			return
+		}
+		cfg.ShutdownHooks = append(cfg.ShutdownHooks, capability.ShutdownHookRegistration{
+			Phase: phase,
+			Name:  name,
+			Hook:  hook,
+		})
+	}
+}
+
+// WithWorker 追加一个受监督的后台任务（name 需唯一）。
+func WithWorker(name string, run capability.Worker, policy capability.WorkerPolicy) Option {
+	return func(cfg *HostConfig) {
+		if cfg == nil {
+			return
+		}
+		cfg.Workers = append(cfg.Workers, capability.WorkerRegistration{
+			Name:   name,
+			Run:    run,
+			Policy: policy,
+		})
+	}
+}
+
+// WithShutdownPhaseTimeout 为指定关闭阶段设置独立超时；timeout <= 0 时移除该阶段的超时。
+func WithShutdownPhaseTimeout(phase capability.ShutdownPhase, timeout time.Duration) Option {
+	return func(cfg *HostConfig) {
+		if cfg == nil {
+			return
+		}
+		if timeout <= 0 {
+			delete(cfg.ShutdownPhaseTimeouts, phase)
+			return
+		}
+		if cfg.ShutdownPhaseTimeouts == nil {
+			cfg.ShutdownPhaseTimeouts = map[capability.ShutdownPhase]time.Duration{}
+		}
+		cfg.ShutdownPhaseTimeouts[phase] = timeout
+	}
+}
+
 // WithModuleHTTP 设置指定模块的 HTTP 挂载配置（按模块 ID() 索引）。
 func WithModuleHTTP(moduleID string, cfgOverride ModuleHTTPConfig) Option {
 	return func(cfg *HostConfig) {
@@ -291,6 +401,26 @@ func WithAllowSession(allow bool) Option {
 	}
 }
 
+// applySettings 用 Settings.Server 覆盖 HTTP 监听配置。
+func applySettings(cfg *HostConfig) {
+	if cfg == nil || cfg.Settings == nil {
+		return
+	}
+	server := cfg.Settings.Server
+	if server.Name != "" {
+		cfg.Name = server.Name
+	}
+	if server.Host != "" {
+		cfg.Host = server.Host
+	}
+	if server.Port > 0 {
+		cfg.Port = server.Port
+	}
+	if server.BasePath != "" {
+		cfg.BasePath = server.BasePath
+	}
+}
+
 func ensureHostConfig(cfg *HostConfig) *HostConfig {
 	if cfg == nil {
 		cfg = DefaultHostConfig()
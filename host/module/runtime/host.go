@@ -2,8 +2,11 @@ package runtime
 
 import (
 	auth "gochen/auth"
+	"gochen/config"
 	"gochen/di"
 	deventsourced "gochen/domain/eventsourced"
+	"gochen/errors"
+	"gochen/host/capability"
 	"gochen/host/internal/bootstrap"
 )
 
@@ -19,7 +22,9 @@ type Host struct {
 	moduleRegistry   *ModuleRegistry
 	metadataRegistry *deventsourced.MetadataRegistry
 
-	moduleStops []ModuleStopFunc
+	moduleStops   []ModuleStopFunc
+	shutdownHooks *capability.ShutdownHooks
+	workers       *capability.Workers
 
 	moduleIDs map[IModule]string
 }
@@ -47,7 +52,17 @@ func (s *Host) Name() string {
 }
 
 // LoadConfig 加载并规范化服务配置。
+//
+// 配置了 ConfigSources 时先分层加载 Settings，再用 Settings.Server 覆盖监听配置。
 func (s *Host) LoadConfig() error {
+	if s.config != nil && len(s.config.ConfigSources) > 0 {
+		settings, err := config.Load[config.Settings](s.config.ConfigSources...)
+		if err != nil {
+			return errors.Wrap(err, errors.InvalidInput, "load host settings failed")
+		}
+		s.config.Settings = settings
+	}
+	applySettings(s.config)
 	s.config = ensureHostConfig(s.config)
 	s.container = s.config.Container
 	s.runtime = nil
@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"sort"
+	"strings"
 
 	"gochen/errors"
 )
@@ -149,7 +150,9 @@ func (s *Host) sortModulesByDependencies() error {
 			}
 		}
 		sort.Slice(remain, func(i, j int) bool { return originalIndex[remain[i]] < originalIndex[remain[j]] })
-		return errors.NewCode(errors.InvalidInput, "module dependency cycle detected").WithContext("modules", remain)
+		return errors.NewCode(errors.InvalidInput, "module dependency cycle detected").
+			WithContext("modules", remain).
+			WithContext("cycle", strings.Join(findModuleDependencyCycle(remain, deps), " -> "))
 	}
 
 	sorted := make([]IModule, 0, len(order))
@@ -162,4 +165,39 @@ func (s *Host) sortModulesByDependencies() error {
 	return nil
 }
 
+// findModuleDependencyCycle 在未能排序的模块中找出一条依赖环（如 [a b a]），用于错误诊断。
+func findModuleDependencyCycle(remain []string, deps map[string][]string) []string {
+	pending := make(map[string]bool, len(remain))
+	for _, id := range remain {
+		pending[id] = true
+	}
+	if len(remain) == 0 {
+		return nil
+	}
+
+	// 剩余模块的入度均 > 0，且至少有一条依赖指向剩余模块；沿依赖走下去必然回到已访问的模块。
+	visited := make(map[string]int, len(remain))
+	path := make([]string, 0, len(remain)+1)
+	current := remain[0]
+	for {
+		if idx, seen := visited[current]; seen {
+			return append(path[idx:], current)
+		}
+		visited[current] = len(path)
+		path = append(path, current)
+
+		next := ""
+		for _, dep := range deps[current] {
+			if pending[dep] {
+				next = dep
+				break
+			}
+		}
+		if next == "" {
+			return remain
+		}
+		current = next
+	}
+}
+
 // normalizeModuleHTTPConfigKeys 规范化模块HTTP配置Keys。
@@ -64,6 +64,8 @@ func (s *Host) makeModuleInitOptions(m IModule) ModuleInitOptions {
 		runtimecap.EventBus(eventBus),
 		runtimecap.ProjectionManager(pm),
 		runtimecap.Transport(transport),
+		runtimecap.ShutdownHooks(s.shutdownHooks),
+		runtimecap.Workers(s.workers),
 	)
 	return opts
 }
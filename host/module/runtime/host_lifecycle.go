@@ -7,6 +7,7 @@ import (
 	"slices"
 
 	"gochen/errors"
+	"gochen/host/capability"
 	"gochen/messaging"
 )
 
@@ -42,51 +43,117 @@ func (s *Host) Run(ctx context.Context) error {
 // Shutdown 优雅停止并释放资源。
 //
 // 说明：
-// - Shutdown 实现 IServer.Shutdown：先停 HTTP 服务，再按注册倒序停模块，最后关消息传输层；
-// - 任一步出错不会中断后续步骤；所有错误用 errors.Join 聚合返回；
-// - 失败的模块 stop 函数会保留在 s.moduleStops，以便上层重试或诊断；
-// - Transport 仅在所有模块均已成功停止后才被停止：若有模块 stop 失败，说明该模块仍在运行，
-//   Transport 须继续服务，不能提前关闭。
+// - Shutdown 实现 IServer.Shutdown：按 capability.ShutdownPhases 的顺序执行各阶段；
+// - 阶段顺序：停止 HTTP（等待进行中的请求）→ 排空命令并停止后台任务 → 按注册倒序停模块 → 停投影 → 冲刷 Outbox → 停消息传输层 → 关闭数据库；
+// - 每个阶段先执行 Host 内置动作，再按注册倒序执行该阶段的 OnShutdown 钩子；
+// - 配置了 ShutdownPhaseTimeouts 的阶段使用独立超时，其余阶段只受 ctx 约束；
+// - 任一阶段出错不会中断后续阶段（例如 HTTP 阶段超时后仍会停模块、关数据库），所有错误用 errors.Join 聚合返回；
+// - 失败的模块 stop 函数与失败的钩子会被保留，以便上层重试 Shutdown 或诊断；
+// - 仍有模块未成功停止时跳过依赖模块停止的阶段（Outbox、Transport、数据库）：该模块仍在运行，这些组件须继续服务，
+//   返回的错误记录被跳过的阶段，重试 Shutdown 时再执行。
 func (s *Host) Shutdown(ctx context.Context) error {
 	if ctx == nil {
 		return errors.NewCode(errors.InvalidInput, "ctx is nil")
 	}
 
-	var errs []error
+	var (
+		errs    []error
+		skipped []string
+	)
+	for _, phase := range capability.ShutdownPhases() {
+		if len(s.moduleStops) != 0 && dependsOnModuleStop(phase) {
+			skipped = append(skipped, string(phase))
+			continue
+		}
+		if err := s.runShutdownPhase(ctx, phase); err != nil {
+			errs = append(errs, errors.Wrap(err, errors.Internal, "shutdown phase failed").
+				WithContext("phase", string(phase)))
+		}
+	}
+	err := errors.Join(errs...)
+	if len(skipped) != 0 {
+		return errors.Wrap(err, errors.Internal, "shutdown incomplete").
+			WithContext("skipped_phases", skipped)
+	}
+	return err
+}
 
-	if s.runtime != nil && s.runtime.HTTPServer != nil {
-		if err := s.runtime.HTTPServer.Stop(ctx); err != nil {
-			errs = append(errs, errors.Wrap(err, errors.Internal, "failed to stop HTTP server"))
+// dependsOnModuleStop 判断阶段是否要求模块已全部停止（模块运行期间仍会使用 Outbox、Transport 与数据库）。
+func dependsOnModuleStop(phase capability.ShutdownPhase) bool {
+	switch phase {
+	case capability.ShutdownPhaseOutbox, capability.ShutdownPhaseTransport, capability.ShutdownPhaseDatabase:
+		return true
+	default:
+		return false
+	}
+}
+
+// runShutdownPhase 执行单个关闭阶段：内置动作 + 该阶段的钩子。
+func (s *Host) runShutdownPhase(ctx context.Context, phase capability.ShutdownPhase) error {
+	if s.config != nil {
+		if timeout := s.config.ShutdownPhaseTimeouts[phase]; timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
 		}
 	}
 
-	if len(s.moduleStops) > 0 {
-		var remaining []ModuleStopFunc
-		for i := len(s.moduleStops) - 1; i >= 0; i-- {
-			stop := s.moduleStops[i]
-			if stop == nil {
-				continue
+	var errs []error
+
+	switch phase {
+	case capability.ShutdownPhaseHTTP:
+		if s.runtime != nil && s.runtime.HTTPServer != nil {
+			if err := s.runtime.HTTPServer.Stop(ctx); err != nil {
+				errs = append(errs, errors.Wrap(err, errors.Internal, "failed to stop HTTP server"))
 			}
-			if err := stop(ctx); err != nil {
-				errs = append(errs, errors.Wrap(err, errors.Internal, "failed to stop module"))
-				remaining = append(remaining, stop)
+		}
+	case capability.ShutdownPhaseDrain:
+		if err := s.workers.Stop(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	case capability.ShutdownPhaseModules:
+		errs = append(errs, s.stopModules(ctx)...)
+	case capability.ShutdownPhaseTransport:
+		if s.runtime != nil && s.runtime.Transport != nil {
+			if err := messaging.StopTransport(ctx, s.runtime.Transport); err != nil {
+				errs = append(errs, errors.Wrap(err, errors.Internal, "failed to stop message transport"))
 			}
 		}
-		// remaining 是倒序收集的，翻转后恢复原始注册顺序，
-		// 使下次 Shutdown 重试时仍能按注册倒序停止。
-		slices.Reverse(remaining)
-		s.moduleStops = remaining
 	}
 
-	if len(s.moduleStops) == 0 && s.runtime != nil && s.runtime.Transport != nil {
-		if err := messaging.StopTransport(ctx, s.runtime.Transport); err != nil {
-			errs = append(errs, errors.Wrap(err, errors.Internal, "failed to stop message transport"))
-		}
+	if err := s.shutdownHooks.Run(ctx, phase); err != nil {
+		errs = append(errs, err)
 	}
-
 	return errors.Join(errs...)
 }
 
+// stopModules 按注册倒序停止模块，失败的 stop 函数保留在 s.moduleStops。
+func (s *Host) stopModules(ctx context.Context) []error {
+	if len(s.moduleStops) == 0 {
+		return nil
+	}
+
+	var (
+		errs      []error
+		remaining []ModuleStopFunc
+	)
+	for i := len(s.moduleStops) - 1; i >= 0; i-- {
+		stop := s.moduleStops[i]
+		if stop == nil {
+			continue
+		}
+		if err := stop(ctx); err != nil {
+			errs = append(errs, errors.Wrap(err, errors.Internal, "failed to stop module"))
+			remaining = append(remaining, stop)
+		}
+	}
+	// remaining 是倒序收集的，翻转后恢复原始注册顺序，
+	// 使下次 Shutdown 重试时仍能按注册倒序停止。
+	slices.Reverse(remaining)
+	s.moduleStops = remaining
+	return errs
+}
+
 // rollbackModuleStops 回滚模块停止函数集合。
 func (s *Host) rollbackModuleStops(ctx context.Context, stops []ModuleStopFunc) {
 	if len(stops) == 0 {
@@ -4,8 +4,13 @@ import (
 	"context"
 	dibasic "gochen/di/basic"
 
+	"gochen/di"
 	"gochen/errors"
+	"gochen/host/capability"
 	"gochen/host/internal/bootstrap"
+	"gochen/httpx"
+	"gochen/httpx/middleware"
+	"gochen/logging"
 )
 
 // Prepare 准备服务依赖并完成初始化装配。
@@ -22,6 +27,18 @@ func (s *Host) Prepare(ctx context.Context) error {
 		return errors.Wrap(err, errors.Dependency, "failed to initialize framework registries")
 	}
 
+	if err := s.prepareShutdownHooks(); err != nil {
+		return err
+	}
+	if err := s.prepareWorkers(); err != nil {
+		return err
+	}
+
+	routeMiddlewares, err := s.routeMiddlewares()
+	if err != nil {
+		return err
+	}
+
 	runtime, err := bootstrap.Prepare(bootstrap.Config{
 		Container:                s.container,
 		Host:                     s.config.Host,
@@ -29,7 +46,7 @@ func (s *Host) Prepare(ctx context.Context) error {
 		BasePath:                 s.config.BasePath,
 		SecurityLayer:            s.config.SecurityLayer,
 		AllowSession:             s.config.AllowSession,
-		RouteMiddlewares:         s.config.RouteMiddlewares,
+		RouteMiddlewares:         routeMiddlewares,
 		DisableHealthRoute:       s.config.DisableHealthRoute,
 		FailFastOnRouteConflicts: s.config.FailFastOnRouteConflicts,
 		HTTPServer:               s.config.HTTPServer,
@@ -76,3 +93,50 @@ func (s *Host) Prepare(ctx context.Context) error {
 
 	return nil
 }
+
+// prepareShutdownHooks 创建关闭钩子注册表并登记组合根配置的钩子。
+func (s *Host) prepareShutdownHooks() error {
+	s.shutdownHooks = capability.NewShutdownHooks()
+	if s.config == nil {
+		return nil
+	}
+	for phase := range s.config.ShutdownPhaseTimeouts {
+		if !capability.ValidShutdownPhase(phase) {
+			return errors.NewCode(errors.InvalidInput, "unknown shutdown phase in timeouts").
+				WithContext("phase", string(phase))
+		}
+	}
+	for _, reg := range s.config.ShutdownHooks {
+		if err := s.shutdownHooks.OnShutdown(reg.Phase, reg.Name, reg.Hook); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prepareWorkers 创建后台任务注册表并登记组合根配置的任务。
+func (s *Host) prepareWorkers() error {
+	s.workers = capability.NewWorkers(logging.ComponentLogger("host.workers"))
+	if s.config == nil {
+		return nil
+	}
+	for _, reg := range s.config.Workers {
+		if err := s.workers.Register(reg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// routeMiddlewares 返回挂载在 BasePath group 上的中间件；启用 RequestScope 时追加在最内层。
+func (s *Host) routeMiddlewares() ([]httpx.Middleware, error) {
+	middlewares := append([]httpx.Middleware(nil), s.config.RouteMiddlewares...)
+	if !s.config.RequestScope {
+		return middlewares, nil
+	}
+	factory, ok := s.container.(di.IScopeFactory)
+	if !ok {
+		return nil, errors.NewCode(errors.Unsupported, "request scope requires a container implementing di.IScopeFactory")
+	}
+	return append(middlewares, middleware.RequestScope(factory)), nil
+}
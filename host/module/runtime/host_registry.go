@@ -35,6 +35,11 @@ func (s *Host) ensureFrameworkRegistries() error {
 	if err := registerFrameworkRegistry(s.container, s.metadataRegistry); err != nil {
 		return err
 	}
+	if s.config.Settings != nil {
+		if err := registerFrameworkRegistry(s.container, s.config.Settings); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
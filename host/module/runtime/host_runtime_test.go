@@ -3,8 +3,16 @@ package runtime
 import (
 	"context"
 	"errors"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 
+	"gochen/config"
+	"gochen/di"
+	dibasic "gochen/di/basic"
+	gerrors "gochen/errors"
+	"gochen/host/capability"
 	"gochen/host/internal/bootstrap"
 	"gochen/host/module/runtimecap"
 	"gochen/httpx"
@@ -285,6 +293,99 @@ func TestShutdownRetainsFailedModuleStopsAndSkipsTransportClose(t *testing.T) {
 	}
 }
 
+func TestShutdownSkipsLaterPhasesAfterModuleStopFailure(t *testing.T) {
+	t.Parallel()
+
+	stopErr := errors.New("stop failed")
+	failing := true
+	var ran []string
+	hooks := capability.NewShutdownHooks()
+	for _, phase := range []capability.ShutdownPhase{capability.ShutdownPhaseOutbox, capability.ShutdownPhaseDatabase} {
+		if err := hooks.OnShutdown(phase, string(phase), func(context.Context) error {
+			ran = append(ran, string(phase))
+			return nil
+		}); err != nil {
+			t.Fatalf("OnShutdown returned error: %v", err)
+		}
+	}
+	host := &Host{
+		shutdownHooks: hooks,
+		moduleStops: []ModuleStopFunc{
+			func(context.Context) error {
+				if failing {
+					return stopErr
+				}
+				return nil
+			},
+		},
+	}
+
+	err := host.Shutdown(context.Background())
+	if !errors.Is(err, stopErr) {
+		t.Fatalf("expected module stop error to be preserved, got %v", err)
+	}
+	if len(ran) != 0 {
+		t.Fatalf("expected later phases to be skipped while a module is still running, ran %v", ran)
+	}
+
+	failing = false
+	if err := host.Shutdown(context.Background()); err != nil {
+		t.Fatalf("retry Shutdown returned error: %v", err)
+	}
+	if !slices.Equal(ran, []string{"outbox", "database"}) {
+		t.Fatalf("expected skipped phases to run on retry, got %v", ran)
+	}
+}
+
+type blockingStopServer struct {
+	httpx.IServer
+}
+
+func (s *blockingStopServer) Stop(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestShutdownContinuesAfterHTTPPhaseTimeout(t *testing.T) {
+	t.Parallel()
+
+	var closed []string
+	hooks := capability.NewShutdownHooks()
+	if err := hooks.OnShutdown(capability.ShutdownPhaseDatabase, "db", func(context.Context) error {
+		closed = append(closed, "database")
+		return nil
+	}); err != nil {
+		t.Fatalf("OnShutdown returned error: %v", err)
+	}
+	moduleStopped := false
+	host := &Host{
+		config: &HostConfig{
+			ShutdownPhaseTimeouts: map[capability.ShutdownPhase]time.Duration{
+				capability.ShutdownPhaseHTTP: 10 * time.Millisecond,
+			},
+		},
+		runtime:       &bootstrap.Runtime{HTTPServer: &blockingStopServer{IServer: &testHTTPServer{}}},
+		shutdownHooks: hooks,
+		moduleStops: []ModuleStopFunc{
+			func(context.Context) error {
+				moduleStopped = true
+				return nil
+			},
+		},
+	}
+
+	err := host.Shutdown(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected HTTP phase timeout to be reported, got %v", err)
+	}
+	if !moduleStopped {
+		t.Fatal("expected modules to stop after HTTP phase timeout")
+	}
+	if !slices.Equal(closed, []string{"database"}) {
+		t.Fatalf("expected database phase to run after HTTP phase timeout, got %v", closed)
+	}
+}
+
 func TestShutdownIgnoresAlreadyStoppedTransportConflict(t *testing.T) {
 	t.Parallel()
 
@@ -350,3 +451,291 @@ func TestShutdownReturnsTransportStopError(t *testing.T) {
 		t.Fatalf("expected Stop once, got %d", transport.stopCalls)
 	}
 }
+
+type orderedServer struct {
+	httpx.IServer
+	record func(string)
+}
+
+func (s *orderedServer) Stop(context.Context) error {
+	s.record("http")
+	return nil
+}
+
+type orderedTransport struct {
+	stopOnlyTransport
+	record func(string)
+}
+
+func (t *orderedTransport) Stop(context.Context) error {
+	t.record("transport")
+	return nil
+}
+
+type startRecordingTransport struct {
+	orderedTransport
+}
+
+func (t *startRecordingTransport) Start(context.Context) error {
+	t.record("transport.start")
+	return nil
+}
+
+func TestShutdownRunsPhasesInOrderWithModuleAndConfiguredHooks(t *testing.T) {
+	t.Parallel()
+
+	var order []string
+	record := func(step string) { order = append(order, step) }
+	hook := func(step string) capability.ShutdownHook {
+		return func(context.Context) error {
+			record(step)
+			return nil
+		}
+	}
+
+	var drainHasDeadline bool
+	host := NewHost([]ModuleCtor{
+		func() (IModule, error) {
+			return &testModule{
+				id:   "orders",
+				name: "orders",
+				initFn: func(opts ModuleInitOptions) error {
+					hooks := runtimecap.ShutdownHooksFrom(opts)
+					if hooks == nil {
+						t.Fatal("expected shutdown hooks capability")
+					}
+					if err := hooks.OnShutdown(capability.ShutdownPhaseOutbox, "orders.outbox", hook("outbox")); err != nil {
+						return err
+					}
+					return hooks.OnShutdown(capability.ShutdownPhaseProjections, "orders.projections", hook("projections"))
+				},
+				startFn: func(context.Context) (ModuleStopFunc, error) {
+					return func(context.Context) error {
+						record("module")
+						return nil
+					}, nil
+				},
+			}, nil
+		},
+	},
+		WithHTTPServer(&orderedServer{IServer: &testHTTPServer{}, record: record}),
+		WithShutdownHook(capability.ShutdownPhaseDatabase, "db.close", hook("database")),
+		WithShutdownHook(capability.ShutdownPhaseDrain, "commands", func(ctx context.Context) error {
+			_, drainHasDeadline = ctx.Deadline()
+			record("drain")
+			return nil
+		}),
+		WithShutdownPhaseTimeout(capability.ShutdownPhaseDrain, time.Second),
+	)
+
+	if err := host.Prepare(context.Background()); err != nil {
+		t.Fatalf("Prepare returned error: %v", err)
+	}
+	host.runtime.Transport = &orderedTransport{record: record}
+	if err := host.StartBackground(context.Background()); err != nil {
+		t.Fatalf("StartBackground returned error: %v", err)
+	}
+	if err := host.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown returned error: %v", err)
+	}
+
+	want := []string{"http", "drain", "module", "projections", "outbox", "transport", "database"}
+	if !slices.Equal(order, want) {
+		t.Fatalf("unexpected shutdown order: got %v, want %v", order, want)
+	}
+	if !drainHasDeadline {
+		t.Fatal("expected drain phase to run with its own timeout")
+	}
+}
+
+func TestShutdownRetainsFailedHooksForRetry(t *testing.T) {
+	t.Parallel()
+
+	hookErr := errors.New("flush failed")
+	calls := 0
+	hooks := capability.NewShutdownHooks()
+	if err := hooks.OnShutdown(capability.ShutdownPhaseOutbox, "outbox.flush", func(context.Context) error {
+		calls++
+		if calls == 1 {
+			return hookErr
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("OnShutdown returned error: %v", err)
+	}
+	host := &Host{shutdownHooks: hooks}
+
+	err := host.Shutdown(context.Background())
+	if !errors.Is(err, hookErr) {
+		t.Fatalf("expected hook error to be preserved, got %v", err)
+	}
+	if hooks.Len(capability.ShutdownPhaseOutbox) != 1 {
+		t.Fatal("expected failed hook to be retained")
+	}
+
+	if err := host.Shutdown(context.Background()); err != nil {
+		t.Fatalf("retry Shutdown returned error: %v", err)
+	}
+	if calls != 2 || hooks.Len(capability.ShutdownPhaseOutbox) != 0 {
+		t.Fatalf("expected hook to succeed on retry and be removed, calls=%d", calls)
+	}
+}
+
+func TestWorkersStartAfterTransportAndStopInDrainPhase(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	record := func(step string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, step)
+	}
+	worker := func(name string) capability.Worker {
+		return func(ctx context.Context) error {
+			record(name + ".start")
+			<-ctx.Done()
+			record(name + ".stop")
+			return nil
+		}
+	}
+
+	started := make(chan struct{}, 2)
+	host := NewHost([]ModuleCtor{
+		func() (IModule, error) {
+			return &testModule{
+				id:   "orders",
+				name: "orders",
+				initFn: func(opts ModuleInitOptions) error {
+					return runtimecap.WorkersFrom(opts).Register(capability.WorkerRegistration{
+						Name: "orders.catchup",
+						Run: func(ctx context.Context) error {
+							started <- struct{}{}
+							return worker("catchup")(ctx)
+						},
+					})
+				},
+				startFn: func(context.Context) (ModuleStopFunc, error) {
+					return func(context.Context) error {
+						record("module")
+						return nil
+					}, nil
+				},
+			}, nil
+		},
+	},
+		WithHTTPServer(&testHTTPServer{}),
+		WithWorker("outbox.publisher", func(ctx context.Context) error {
+			started <- struct{}{}
+			return worker("publisher")(ctx)
+		}, capability.WorkerPolicy{}),
+	)
+	if err := host.Prepare(context.Background()); err != nil {
+		t.Fatalf("Prepare returned error: %v", err)
+	}
+	host.runtime.Transport = &startRecordingTransport{orderedTransport{record: record}}
+	if err := host.StartBackground(context.Background()); err != nil {
+		t.Fatalf("StartBackground returned error: %v", err)
+	}
+	for range 2 {
+		select {
+		case <-started:
+		case <-time.After(2 * time.Second):
+			t.Fatal("expected workers to start")
+		}
+	}
+	if got := host.workers.Status(); len(got) != 2 || got[0].Name != "outbox.publisher" || got[1].Name != "orders.catchup" {
+		t.Fatalf("unexpected worker status: %+v", got)
+	}
+	if err := host.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown returned error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if order[0] != "transport.start" {
+		t.Fatalf("expected transport to start before workers, got %v", order)
+	}
+	if i := slices.Index(order, "module"); i != len(order)-2 || order[i+1] != "transport" || !slices.Contains(order[:i], "publisher.stop") || !slices.Contains(order[:i], "catchup.stop") {
+		t.Fatalf("expected workers to stop before modules, got %v", order)
+	}
+}
+
+func TestPrepareRejectsUnknownShutdownPhase(t *testing.T) {
+	t.Parallel()
+
+	host := NewHost(nil,
+		WithHTTPServer(&testHTTPServer{}),
+		WithShutdownHook(capability.ShutdownPhase("later"), "noop", func(context.Context) error { return nil }),
+	)
+	if err := host.Prepare(context.Background()); err == nil {
+		t.Fatal("expected unknown shutdown phase error")
+	}
+}
+
+func TestLoadConfigAppliesLayeredSettingsAndRegistersThem(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{"SERVER_NAME": "orders", "SERVER_PORT": "9400", "SERVER_BASE_PATH": "/orders"}
+	host := NewHost(nil,
+		WithHTTPServer(&testHTTPServer{}),
+		WithPort(9000),
+		WithConfigSources(config.WithEnvLookup(func(key string) (string, bool) {
+			v, ok := env[key]
+			return v, ok
+		})),
+	)
+	if err := host.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+	if host.Name() != "orders" || host.config.Port != 9400 || host.config.BasePath != "/orders" {
+		t.Fatalf("unexpected host config: name=%q port=%d basePath=%q", host.Name(), host.config.Port, host.config.BasePath)
+	}
+
+	if err := host.Prepare(context.Background()); err != nil {
+		t.Fatalf("Prepare returned error: %v", err)
+	}
+	settings, err := di.Resolve[*config.Settings](host.container)
+	if err != nil {
+		t.Fatalf("resolve settings: %v", err)
+	}
+	if settings.Server.Port != 9400 || settings.Outbox.BatchSize != 100 {
+		t.Fatalf("unexpected settings: %+v", settings)
+	}
+}
+
+func TestLoadConfigRejectsInvalidSettings(t *testing.T) {
+	t.Parallel()
+
+	host := NewHost(nil, WithConfigSources(config.WithEnvLookup(func(key string) (string, bool) {
+		if key == "SERVER_PORT" {
+			return "0", true
+		}
+		return "", false
+	})))
+	if err := host.LoadConfig(); err == nil {
+		t.Fatal("expected invalid settings error")
+	}
+}
+
+func TestPrepareRequestScopeRequiresScopeFactory(t *testing.T) {
+	t.Parallel()
+
+	host := NewHost(nil, WithHTTPServer(&testHTTPServer{}), WithRequestScope(true))
+	middlewares, err := host.routeMiddlewares()
+	if err != nil || len(middlewares) != 1 {
+		t.Fatalf("expected request scope middleware, got %d middlewares, err=%v", len(middlewares), err)
+	}
+
+	type plainContainer struct{ di.IContainer }
+	host = NewHost(nil,
+		WithHTTPServer(&testHTTPServer{}),
+		WithContainer(plainContainer{IContainer: dibasic.New()}),
+		WithRequestScope(true),
+	)
+	if err := host.Prepare(context.Background()); !gerrors.Is(err, gerrors.Unsupported) {
+		t.Fatalf("expected Unsupported without scope factory, got %v", err)
+	}
+}
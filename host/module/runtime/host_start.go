@@ -7,6 +7,7 @@ import (
 	"gochen/errors"
 	"gochen/host/internal/runtimeutil"
 	"gochen/httpx"
+	"gochen/messaging"
 )
 
 // StartBackground 启动后台任务（不阻塞主服务）。
@@ -15,7 +16,8 @@ import (
 // - StartBackground 实现 IServer.StartBackground。
 // - 该阶段包含“路由挂载”（RegisterRoutes）：只做 HTTP 路由装配与启动期校验，不进入运行期；
 // - 再调用所有模块的 Start(ctx)（事件订阅/投影注册/后台任务等），失败则回滚；
-// - 再启动消息传输层（Transport），避免遗漏订阅导致消息丢失。
+// - 再启动消息传输层（Transport），避免遗漏订阅导致消息丢失；
+// - 最后启动已登记的后台任务（Workers），失败则回滚模块与传输层。
 func (s *Host) StartBackground(ctx context.Context) error {
 	if ctx == nil {
 		return errors.NewCode(errors.InvalidInput, "ctx is nil")
@@ -69,6 +71,11 @@ func (s *Host) StartBackground(ctx context.Context) error {
 				return errors.Wrap(err, errors.Dependency, "failed to start message transport")
 			}
 		}
+		if err := s.startWorkers(ctx); err != nil {
+			s.rollbackModuleStops(ctx, stops)
+			s.moduleStops = nil
+			return err
+		}
 		s.moduleStops = stops
 		return nil
 	}
@@ -78,6 +85,21 @@ func (s *Host) StartBackground(ctx context.Context) error {
 			return errors.Wrap(err, errors.Dependency, "failed to start message transport")
 		}
 	}
+	return s.startWorkers(ctx)
+}
+
+// startWorkers 启动后台任务；失败时停止已启动的任务与消息传输层。
+func (s *Host) startWorkers(ctx context.Context) error {
+	if s.workers == nil {
+		return nil
+	}
+	if err := s.workers.Start(ctx); err != nil {
+		_ = s.workers.Stop(ctx)
+		if s.runtime != nil && s.runtime.Transport != nil {
+			_ = messaging.StopTransport(ctx, s.runtime.Transport)
+		}
+		return errors.Wrap(err, errors.Dependency, "failed to start background workers")
+	}
 	return nil
 }
 
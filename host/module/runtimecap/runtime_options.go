@@ -19,6 +19,8 @@ var (
 	eventBusKey          = initcap.NewKey[capability.IEventSubscriber]("host.module.runtimecap.event_bus")
 	projectionManagerKey = initcap.NewKey[capability.IProjectionManager]("host.module.runtimecap.projection_manager")
 	transportKey         = initcap.NewKey[capability.ITransport]("host.module.runtimecap.transport")
+	shutdownHooksKey     = initcap.NewKey[*capability.ShutdownHooks]("host.module.runtimecap.shutdown_hooks")
+	workersKey           = initcap.NewKey[*capability.Workers]("host.module.runtimecap.workers")
 )
 
 // ModuleHTTPOptions 定义模块的 HTTP 挂载选项。
@@ -192,3 +194,35 @@ func TransportFrom(opts module.ModuleInitOptions) capability.ITransport {
 	transport, _ := module.CapabilityFrom(opts, transportKey)
 	return transport
 }
+
+// ShutdownHooks 创建关闭钩子 capability setter。
+func ShutdownHooks(hooks *capability.ShutdownHooks) initcap.Setter {
+	return initcap.Set(shutdownHooksKey, hooks)
+}
+
+// WithShutdownHooks 注入模块关闭钩子注册能力。
+func WithShutdownHooks(opts module.ModuleInitOptions, hooks *capability.ShutdownHooks) module.ModuleInitOptions {
+	return module.WithCapability(opts, shutdownHooksKey, hooks)
+}
+
+// ShutdownHooksFrom 读取模块关闭钩子注册能力。
+func ShutdownHooksFrom(opts module.ModuleInitOptions) *capability.ShutdownHooks {
+	hooks, _ := module.CapabilityFrom(opts, shutdownHooksKey)
+	return hooks
+}
+
+// Workers 创建后台任务注册表 capability setter。
+func Workers(workers *capability.Workers) initcap.Setter {
+	return initcap.Set(workersKey, workers)
+}
+
+// WithWorkers 注入模块后台任务注册能力。
+func WithWorkers(opts module.ModuleInitOptions, workers *capability.Workers) module.ModuleInitOptions {
+	return module.WithCapability(opts, workersKey, workers)
+}
+
+// WorkersFrom 读取模块后台任务注册能力。
+func WorkersFrom(opts module.ModuleInitOptions) *capability.Workers {
+	workers, _ := module.CapabilityFrom(opts, workersKey)
+	return workers
+}
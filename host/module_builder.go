@@ -8,6 +8,7 @@ import (
 	"gochen/di"
 	deventsourced "gochen/domain/eventsourced"
 	"gochen/errors"
+	"gochen/host/capability"
 	"gochen/host/module"
 	moduleasm "gochen/host/module/assembly"
 	"gochen/host/module/runtimecap"
@@ -39,6 +40,7 @@ func AggregateFromTag(sample any) AggregateConfig {
 type Builder struct {
 	id                    string
 	name                  string
+	dependsOn             []string
 	providers             []any
 	aggregates            []AggregateConfig
 	permissions           []string
@@ -51,6 +53,8 @@ type Builder struct {
 	middlewares           []httpx.Middleware
 	onStart               func(ctx context.Context) error
 	onStop                func(ctx context.Context) error
+	shutdownHooks         []capability.ShutdownHookRegistration
+	workers               []capability.WorkerRegistration
 }
 
 // Module 创建一个链式模块构造器。
@@ -64,6 +68,15 @@ func (b *Builder) Name(name string) *Builder {
 	return b
 }
 
+// DependsOn 声明必须先于本模块初始化与启动的模块（按模块 ID）。
+//
+// Host 按依赖拓扑排序模块：被依赖模块先 Init（先注册其 DI provider）、先 Start、后停止；
+// 依赖不存在或成环时 Prepare 直接失败。
+func (b *Builder) DependsOn(moduleIDs ...string) *Builder {
+	b.dependsOn = append(b.dependsOn, moduleIDs...)
+	return b
+}
+
 // Provide 追加 DI provider。
 func (b *Builder) Provide(providers ...any) *Builder {
 	b.providers = append(b.providers, providers...)
@@ -136,6 +149,32 @@ func (b *Builder) OnStop(fn func(ctx context.Context) error) *Builder {
 	return b
 }
 
+// OnShutdown 追加在指定关闭阶段执行的钩子（见 ShutdownPhase），同一阶段内按注册倒序执行。
+//
+// 与 OnStop 不同，OnShutdown 的钩子按阶段跨模块编排，例如在 ShutdownPhaseOutbox 冲刷 Outbox、
+// 在 ShutdownPhaseDatabase 关闭模块独占的连接池。
+func (b *Builder) OnShutdown(phase ShutdownPhase, fn func(ctx context.Context) error) *Builder {
+	b.shutdownHooks = append(b.shutdownHooks, capability.ShutdownHookRegistration{
+		Phase: phase,
+		Name:  b.id,
+		Hook:  fn,
+	})
+	return b
+}
+
+// Worker 追加一个受 Host 监督的后台任务（如投影追赶循环、调度器），任务名为 "{模块 ID}.{name}"。
+//
+// run 应阻塞到 ctx 结束；panic 或返回错误时按 policy（省略时使用默认 WorkerPolicy）退避重启，返回 nil 视为完成。
+// 任务在 StartBackground 末尾启动，在 ShutdownPhaseDrain 阶段停止。
+func (b *Builder) Worker(name string, run func(ctx context.Context) error, policy ...WorkerPolicy) *Builder {
+	reg := capability.WorkerRegistration{Name: name, Run: run}
+	if len(policy) > 0 {
+		reg.Policy = policy[0]
+	}
+	b.workers = append(b.workers, reg)
+	return b
+}
+
 // Build 根据构造器状态生成模块实例。
 func (b *Builder) Build() (IModule, error) {
 	desc, err := b.descriptor()
@@ -155,8 +194,9 @@ func convertBuilderToDescriptor(builder *Builder) (moduleasm.ModuleDescriptor, e
 	}
 
 	desc := moduleasm.ModuleDescriptor{
-		ID:   builder.id,
-		Name: builder.name,
+		ID:        builder.id,
+		Name:      builder.name,
+		DependsOn: append([]string(nil), builder.dependsOn...),
 		PermissionDefinitions: auth.MergePermissionDefinitions(
 			auth.PermissionDefinitionsFromCodes(builder.permissions...),
 			builder.permissionDefinitions,
@@ -166,6 +206,8 @@ func convertBuilderToDescriptor(builder *Builder) (moduleasm.ModuleDescriptor, e
 		Middlewares:       append([]httpx.Middleware(nil), builder.middlewares...),
 		OnStart:           builder.onStart,
 		OnStop:            builder.onStop,
+		ShutdownHooks:     append([]capability.ShutdownHookRegistration(nil), builder.shutdownHooks...),
+		Workers:           append([]capability.WorkerRegistration(nil), builder.workers...),
 	}
 	desc.Permissions = auth.PermissionCodes(desc.PermissionDefinitions...)
 
@@ -13,8 +13,10 @@ import (
 	"gochen/errors"
 	"gochen/eventing"
 	"gochen/eventing/projection"
+	"gochen/host/capability"
 	hostmodule "gochen/host/module"
 	moduleasm "gochen/host/module/assembly"
+	"gochen/host/module/runtime"
 	"gochen/host/module/runtimecap"
 	"gochen/httpx"
 	"gochen/messaging"
@@ -553,6 +555,60 @@ func TestModuleBuilder_NoHTTP(t *testing.T) {
 	}
 }
 
+func TestModuleBuilder_OnShutdown_RegistersHooksAtInit(t *testing.T) {
+	container := dibasic.New()
+	called := false
+	module := mustBuildHostModule(t,
+		Module("orders").
+			OnShutdown(ShutdownPhaseOutbox, func(context.Context) error {
+				called = true
+				return nil
+			}),
+	)
+
+	if err := module.Init(hostmodule.NewModuleInitOptions(container, container, container)); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected InvalidInput without shutdown registry, got %v", err)
+	}
+
+	hooks := capability.NewShutdownHooks()
+	if err := module.Init(hostmodule.NewModuleInitOptions(container, container, container, runtimecap.ShutdownHooks(hooks))); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if hooks.Len(ShutdownPhaseOutbox) != 1 {
+		t.Fatalf("expected one outbox hook, got %d", hooks.Len(ShutdownPhaseOutbox))
+	}
+	if err := hooks.Run(context.Background(), ShutdownPhaseOutbox); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !called {
+		t.Fatal("expected module shutdown hook to run")
+	}
+}
+
+func TestModuleBuilder_Worker_RegistersPrefixedWorkerAtInit(t *testing.T) {
+	container := dibasic.New()
+	module := mustBuildHostModule(t,
+		Module("orders").
+			Worker("catchup", func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			}, WorkerPolicy{MaxRestarts: 3}),
+	)
+
+	if err := module.Init(hostmodule.NewModuleInitOptions(container, container, container)); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected InvalidInput without worker registry, got %v", err)
+	}
+
+	workers := capability.NewWorkers(nil)
+	if err := module.Init(hostmodule.NewModuleInitOptions(container, container, container, runtimecap.Workers(workers))); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	status := workers.Status()
+	if len(status) != 1 || status[0].Name != "orders.catchup" || status[0].State != capability.WorkerStatePending {
+		t.Fatalf("unexpected worker status: %+v", status)
+	}
+}
+
 func TestModuleBuilder_Aggregates_RegisterMetadataFailFast(t *testing.T) {
 	_, err := Module("test").
 		Name("Test").
@@ -688,3 +744,35 @@ func TestNewModuleDescriptor_Aggregates_RegisterAtInit(t *testing.T) {
 		t.Fatal("expected descriptor init to register aggregate metadata")
 	}
 }
+
+func TestModuleBuilder_DependsOn_OrdersModuleInit(t *testing.T) {
+	var order []string
+	newModule := func(id string, deps ...string) func() (IModule, error) {
+		return func() (IModule, error) {
+			return Module(id).
+				Name(id).
+				DependsOn(deps...).
+				OnStart(func(context.Context) error {
+					order = append(order, id)
+					return nil
+				}).
+				Build()
+		}
+	}
+
+	app := runtime.NewHost([]hostmodule.ModuleCtor{newModule("llm", "iam"), newModule("iam")})
+	if err := app.Prepare(context.Background()); err != nil {
+		t.Fatalf("Prepare failed: %v", err)
+	}
+	if err := app.StartBackground(context.Background()); err != nil {
+		t.Fatalf("StartBackground failed: %v", err)
+	}
+	if len(order) != 2 || order[0] != "iam" || order[1] != "llm" {
+		t.Fatalf("unexpected start order: %v", order)
+	}
+
+	missing := runtime.NewHost([]hostmodule.ModuleCtor{newModule("llm", "iam")})
+	if err := missing.Prepare(context.Background()); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected InvalidInput for missing dependency, got %v", err)
+	}
+}
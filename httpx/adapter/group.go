@@ -0,0 +1,75 @@
+package adapter
+
+import (
+	"net/http"
+
+	"gochen/httpx"
+)
+
+// Group 定义带 prefix 与中间件快照语义的路由分组（与 nethttp.RouteGroup 一致）。
+//
+// 说明：
+// - 子路由 path 会拼接在 group prefix 之后；
+// - 路由注册时捕获当前 group 的中间件快照，后续 Use 不会回追到已注册路由。
+type Group struct {
+	prefix      string
+	table       *Table
+	middlewares []httpx.Middleware
+}
+
+var _ httpx.IRouteGroup = (*Group)(nil)
+
+// GET 在当前 group 上注册 GET 路由。
+func (g *Group) GET(path string, h httpx.Handler) httpx.IRouteGroup {
+	return g.add(http.MethodGet, path, h)
+}
+
+// POST 在当前 group 上注册 POST 路由。
+func (g *Group) POST(path string, h httpx.Handler) httpx.IRouteGroup {
+	return g.add(http.MethodPost, path, h)
+}
+
+// PUT 在当前 group 上注册 PUT 路由。
+func (g *Group) PUT(path string, h httpx.Handler) httpx.IRouteGroup {
+	return g.add(http.MethodPut, path, h)
+}
+
+// DELETE 在当前 group 上注册 DELETE 路由。
+func (g *Group) DELETE(path string, h httpx.Handler) httpx.IRouteGroup {
+	return g.add(http.MethodDelete, path, h)
+}
+
+// PATCH 在当前 group 上注册 PATCH 路由。
+func (g *Group) PATCH(path string, h httpx.Handler) httpx.IRouteGroup {
+	return g.add(http.MethodPatch, path, h)
+}
+
+// HEAD 在当前 group 上注册 HEAD 路由。
+func (g *Group) HEAD(path string, h httpx.Handler) httpx.IRouteGroup {
+	return g.add(http.MethodHead, path, h)
+}
+
+// OPTIONS 在当前 group 上注册 OPTIONS 路由。
+func (g *Group) OPTIONS(path string, h httpx.Handler) httpx.IRouteGroup {
+	return g.add(http.MethodOptions, path, h)
+}
+
+// Group 创建子 group：拼接 prefix 并继承父 group 的中间件快照。
+func (g *Group) Group(prefix string) httpx.IRouteGroup {
+	return &Group{
+		prefix:      g.prefix + prefix,
+		table:       g.table,
+		middlewares: append([]httpx.Middleware{}, g.middlewares...),
+	}
+}
+
+// Use 追加中间件，仅作用于此后在本 group 上注册的路由。
+func (g *Group) Use(mw ...httpx.Middleware) httpx.IRouteGroup {
+	g.middlewares = append(g.middlewares, mw...)
+	return g
+}
+
+func (g *Group) add(method, path string, h httpx.Handler) httpx.IRouteGroup {
+	g.table.add(method, g.prefix+path, h, append([]httpx.Middleware{}, g.middlewares...))
+	return g
+}
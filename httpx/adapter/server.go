@@ -0,0 +1,79 @@
+package adapter
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+// ListenAddr 返回监听地址：addr 为空时按 WebConfig 的 Host/Port 拼接。
+func ListenAddr(config *httpx.WebConfig, addr string) string {
+	if addr != "" || config == nil {
+		return addr
+	}
+	return fmt.Sprintf("%s:%d", config.Host, config.Port)
+}
+
+// NewHTTPServer 根据 WebConfig 构造 `*http.Server`：未设置的超时与 header 限制使用 httpx 默认值，
+// 启用 TLS 时最低版本不低于 TLS 1.2 且必须配置证书来源。
+func NewHTTPServer(config *httpx.WebConfig, addr string, handler http.Handler) (*http.Server, error) {
+	if config == nil {
+		config = &httpx.WebConfig{}
+	}
+	srv := &http.Server{
+		Addr:              ListenAddr(config, addr),
+		Handler:           handler,
+		ReadHeaderTimeout: httpx.DefaultReadHeaderTimeout,
+		ReadTimeout:       httpx.DefaultReadTimeout,
+		WriteTimeout:      httpx.DefaultWriteTimeout,
+		IdleTimeout:       httpx.DefaultIdleTimeout,
+		MaxHeaderBytes:    httpx.DefaultMaxHeaderBytes,
+	}
+	if config.ReadHeaderTimeout > 0 {
+		srv.ReadHeaderTimeout = config.ReadHeaderTimeout
+	}
+	if config.ReadTimeout > 0 {
+		srv.ReadTimeout = config.ReadTimeout
+	}
+	if config.WriteTimeout > 0 {
+		srv.WriteTimeout = config.WriteTimeout
+	}
+	if config.IdleTimeout > 0 {
+		srv.IdleTimeout = config.IdleTimeout
+	}
+	if config.MaxHeaderBytes > 0 {
+		srv.MaxHeaderBytes = config.MaxHeaderBytes
+	}
+
+	if !config.TLSEnabled {
+		return srv, nil
+	}
+	var tlsCfg *tls.Config
+	if config.TLSConfig != nil {
+		tlsCfg = config.TLSConfig.Clone()
+	} else {
+		tlsCfg = &tls.Config{}
+	}
+	if tlsCfg.MinVersion == 0 {
+		tlsCfg.MinVersion = tls.VersionTLS12
+	}
+	if tlsCfg.MinVersion < tls.VersionTLS12 {
+		return nil, errors.NewCode(errors.InvalidInput, "tls config min version too low").WithContext("value", tlsCfg.MinVersion)
+	}
+	if config.CertFile == "" && config.KeyFile == "" && len(tlsCfg.Certificates) == 0 && tlsCfg.GetCertificate == nil && tlsCfg.GetConfigForClient == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "tls enabled but no certificate configured")
+	}
+	srv.TLSConfig = tlsCfg
+	return srv, nil
+}
+
+// ListenAndServe 按 WebConfig 是否启用 TLS 启动 srv。
+func ListenAndServe(config *httpx.WebConfig, srv *http.Server) error {
+	if config != nil && config.TLSEnabled {
+		return srv.ListenAndServeTLS(config.CertFile, config.KeyFile)
+	}
+	return srv.ListenAndServe()
+}
@@ -0,0 +1,158 @@
+// Package adapter 提供第三方 Web 框架适配层共用的路由表与服务器构造。
+//
+// 适配层（如 httpx/ginx）只需把 Table.Routes() 逐条挂到具体框架的路由器上，
+// 并在请求到达时构造 httpx.IContext 后调用 Table.Dispatch，即可获得与 httpx/nethttp 一致的语义：
+//   - 全局中间件在请求时读取，作用于全部路由；group 中间件在注册时捕获快照；
+//   - 未显式注册 OPTIONS 的路径自动补充 OPTIONS 路由（带 Allow 头），并走完整中间件链；
+//   - handler 返回错误且响应未写出时，写入标准错误响应。
+package adapter
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"gochen/httpx"
+	"gochen/httpx/nethttp"
+)
+
+// Route 表示一条已注册的路由。
+type Route struct {
+	Method      string
+	Path        string
+	Handler     httpx.Handler
+	Middlewares []httpx.Middleware
+}
+
+// Table 记录路由与全局中间件。
+type Table struct {
+	routes      []Route
+	middlewares []httpx.Middleware
+	mu          sync.RWMutex
+}
+
+// NewTable 创建空路由表。
+func NewTable() *Table {
+	return &Table{}
+}
+
+// Add 注册一条不带 group 中间件的路由。
+func (t *Table) Add(method, path string, handler httpx.Handler) {
+	t.add(method, path, handler, nil)
+}
+
+func (t *Table) add(method, path string, handler httpx.Handler, middlewares []httpx.Middleware) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.routes = append(t.routes, Route{Method: method, Path: path, Handler: handler, Middlewares: middlewares})
+}
+
+// Use 追加全局中间件。
+func (t *Table) Use(middleware ...httpx.Middleware) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.middlewares = append(t.middlewares, middleware...)
+}
+
+// Group 创建带前缀的路由分组。
+func (t *Table) Group(prefix string) httpx.IRouteGroup {
+	return &Group{prefix: prefix, table: t}
+}
+
+// Routes 按注册顺序返回全部路由，并为缺少显式 OPTIONS 的路径追加自动 OPTIONS 路由。
+func (t *Table) Routes() []Route {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	out := make([]Route, 0, len(t.routes))
+	allow := make(map[string]map[string]struct{})
+	first := make(map[string]Route)
+	paths := make([]string, 0)
+	for _, r := range t.routes {
+		out = append(out, r)
+		if allow[r.Path] == nil {
+			allow[r.Path] = make(map[string]struct{})
+			first[r.Path] = r
+			paths = append(paths, r.Path)
+		}
+		allow[r.Path][r.Method] = struct{}{}
+	}
+
+	for _, path := range paths {
+		methods := allow[path]
+		if _, ok := methods[http.MethodOptions]; ok {
+			continue
+		}
+		allowHeader := AllowHeader(methods)
+		out = append(out, Route{
+			Method:      http.MethodOptions,
+			Path:        path,
+			Middlewares: first[path].Middlewares,
+			Handler: func(ctx httpx.IContext) error {
+				ctx.SetHeader("Allow", allowHeader)
+				return ctx.String(http.StatusNoContent, "")
+			},
+		})
+	}
+	return out
+}
+
+// iRouteTemplateSetter 由嵌入 nethttp.Context 的适配层上下文实现，用于记录匹配到的路由模板。
+type iRouteTemplateSetter interface {
+	SetRouteTemplate(template string)
+}
+
+// Dispatch 依次执行全局中间件、路由中间件与 handler；返回错误时写入标准错误响应。
+func (t *Table) Dispatch(ctx httpx.IContext, r Route) {
+	if setter, ok := ctx.(iRouteTemplateSetter); ok {
+		setter.SetRouteTemplate(r.Path)
+	}
+	t.mu.RLock()
+	middlewares := append([]httpx.Middleware{}, t.middlewares...)
+	t.mu.RUnlock()
+	middlewares = append(middlewares, r.Middlewares...)
+
+	if err := executeMiddlewareChain(ctx, middlewares, r.Handler); err != nil {
+		_ = nethttp.WriteErrorResponse(ctx, err)
+	}
+}
+
+func executeMiddlewareChain(ctx httpx.IContext, middlewares []httpx.Middleware, handler httpx.Handler) error {
+	if len(middlewares) == 0 {
+		return handler(ctx)
+	}
+	return middlewares[0](ctx, func() error { return executeMiddlewareChain(ctx, middlewares[1:], handler) })
+}
+
+// AllowHeader 以稳定顺序生成 Allow 头（总是包含 OPTIONS）。
+func AllowHeader(methods map[string]struct{}) string {
+	known := []string{
+		http.MethodGet,
+		http.MethodPost,
+		http.MethodPut,
+		http.MethodPatch,
+		http.MethodDelete,
+		http.MethodHead,
+		http.MethodOptions,
+	}
+	remain := make(map[string]struct{}, len(methods)+1)
+	for m := range methods {
+		remain[m] = struct{}{}
+	}
+	remain[http.MethodOptions] = struct{}{}
+
+	out := make([]string, 0, len(remain))
+	for _, m := range known {
+		if _, ok := remain[m]; ok {
+			out = append(out, m)
+			delete(remain, m)
+		}
+	}
+	extra := make([]string, 0, len(remain))
+	for m := range remain {
+		extra = append(extra, m)
+	}
+	sort.Strings(extra)
+	return strings.Join(append(out, extra...), ", ")
+}
@@ -0,0 +1,225 @@
+// Package contracttest 提供 httpx.IServer 适配层共享契约测试。
+package contracttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+// ServerUnderTest 描述一个待测的 IServer 实现。
+type ServerUnderTest struct {
+	// Server 为待测服务器，契约测试在其上注册路由与中间件。
+	Server httpx.IServer
+
+	// Handler 在路由注册完成后调用且每个实例只调用一次，返回可直接 ServeHTTP 的处理器。
+	Handler func() http.Handler
+}
+
+// RunServerContractTests 验证 IServer/IRouteGroup/IContext 适配层的公共契约。
+//
+// 说明：每个子测试都会调用 newServer 创建全新实例，适配层无需支持重复注册。
+func RunServerContractTests(t *testing.T, newServer func() ServerUnderTest) {
+	t.Helper()
+
+	serve := func(t *testing.T, h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("path params query and json response", func(t *testing.T) {
+		sut := newServer()
+		sut.Server.GET("/users/:id", func(ctx httpx.IContext) error {
+			return ctx.JSON(http.StatusOK, httpx.JSONValue(map[string]string{
+				"id":     ctx.Param("id"),
+				"expand": ctx.Query("expand"),
+				"method": ctx.Method(),
+				"path":   ctx.Path(),
+			}))
+		})
+
+		rec := serve(t, sut.Handler(), httptest.NewRequest(http.MethodGet, "/users/42?expand=roles", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+			t.Fatalf("expected json content type, got %q", ct)
+		}
+		var body map[string]string
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		want := map[string]string{"id": "42", "expand": "roles", "method": http.MethodGet, "path": "/users/42"}
+		if !reflect.DeepEqual(body, want) {
+			t.Fatalf("unexpected body: got %v, want %v", body, want)
+		}
+	})
+
+	t.Run("middleware order global then group", func(t *testing.T) {
+		sut := newServer()
+		order := make([]string, 0)
+		trace := func(name string) httpx.Middleware {
+			return func(ctx httpx.IContext, next func() error) error {
+				order = append(order, name+"-before")
+				err := next()
+				order = append(order, name+"-after")
+				return err
+			}
+		}
+		sut.Server.Use(trace("global"))
+		api := sut.Server.Group("/api").Use(trace("group"))
+		api.Group("/v1").Use(trace("child")).GET("/ping", func(ctx httpx.IContext) error {
+			order = append(order, "handler")
+			return ctx.String(http.StatusOK, "pong")
+		})
+
+		rec := serve(t, sut.Handler(), httptest.NewRequest(http.MethodGet, "/api/v1/ping", nil))
+		if rec.Code != http.StatusOK || rec.Body.String() != "pong" {
+			t.Fatalf("unexpected response %d %q", rec.Code, rec.Body.String())
+		}
+		want := []string{"global-before", "group-before", "child-before", "handler", "child-after", "group-after", "global-after"}
+		if !reflect.DeepEqual(order, want) {
+			t.Fatalf("unexpected middleware order: got %v, want %v", order, want)
+		}
+	})
+
+	t.Run("group middleware is snapshotted at registration", func(t *testing.T) {
+		sut := newServer()
+		calls := 0
+		group := sut.Server.Group("/g")
+		group.GET("/before", func(ctx httpx.IContext) error { return ctx.String(http.StatusOK, "ok") })
+		group.Use(func(ctx httpx.IContext, next func() error) error {
+			calls++
+			return next()
+		})
+		group.GET("/after", func(ctx httpx.IContext) error { return ctx.String(http.StatusOK, "ok") })
+
+		h := sut.Handler()
+		serve(t, h, httptest.NewRequest(http.MethodGet, "/g/before", nil))
+		if calls != 0 {
+			t.Fatalf("expected middleware added later not to apply to earlier route, got %d calls", calls)
+		}
+		serve(t, h, httptest.NewRequest(http.MethodGet, "/g/after", nil))
+		if calls != 1 {
+			t.Fatalf("expected middleware to apply to later route, got %d calls", calls)
+		}
+	})
+
+	t.Run("handler error writes standard error response", func(t *testing.T) {
+		sut := newServer()
+		sut.Server.GET("/missing", func(ctx httpx.IContext) error {
+			return errors.NewCode(errors.NotFound, "user not found")
+		})
+
+		rec := serve(t, sut.Handler(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), string(errors.NotFound)) {
+			t.Fatalf("expected error code in body, got %q", rec.Body.String())
+		}
+	})
+
+	t.Run("strict json binding rejects unknown fields", func(t *testing.T) {
+		sut := newServer()
+		sut.Server.POST("/users", func(ctx httpx.IContext) error {
+			var in struct {
+				Name string `json:"name"`
+			}
+			if err := ctx.BindJSON(&in); err != nil {
+				return err
+			}
+			return ctx.String(http.StatusCreated, in.Name)
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"alice"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if rec := serve(t, sut.Handler(), req); rec.Code != http.StatusCreated || rec.Body.String() != "alice" {
+			t.Fatalf("unexpected response %d %q", rec.Code, rec.Body.String())
+		}
+
+		sut = newServer()
+		sut.Server.POST("/users", func(ctx httpx.IContext) error {
+			var in struct {
+				Name string `json:"name"`
+			}
+			return ctx.BindJSON(&in)
+		})
+		req = httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"alice","admin":true}`))
+		req.Header.Set("Content-Type", "application/json")
+		if rec := serve(t, sut.Handler(), req); rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for unknown field, got %d", rec.Code)
+		}
+	})
+
+	t.Run("abort stops the chain", func(t *testing.T) {
+		sut := newServer()
+		handled := false
+		sut.Server.Use(func(ctx httpx.IContext, next func() error) error {
+			if ctx.Header("Authorization") == "" {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, httpx.JSONValue(map[string]string{"error": "unauthorized"}))
+				return nil
+			}
+			return next()
+		})
+		sut.Server.GET("/secure", func(ctx httpx.IContext) error {
+			handled = true
+			return ctx.String(http.StatusOK, "ok")
+		})
+
+		rec := serve(t, sut.Handler(), httptest.NewRequest(http.MethodGet, "/secure", nil))
+		if rec.Code != http.StatusUnauthorized || handled {
+			t.Fatalf("expected 401 without reaching handler, got %d handled=%v", rec.Code, handled)
+		}
+	})
+
+	t.Run("context values flow through middlewares", func(t *testing.T) {
+		sut := newServer()
+		sut.Server.Use(func(ctx httpx.IContext, next func() error) error {
+			ctx.Set("operator", httpx.ValueOf("alice"))
+			return next()
+		})
+		sut.Server.GET("/me", func(ctx httpx.IContext) error {
+			v, err := ctx.Required("operator")
+			if err != nil {
+				return err
+			}
+			name, _ := httpx.ValueAs[string](v)
+			return ctx.String(http.StatusOK, name)
+		})
+
+		rec := serve(t, sut.Handler(), httptest.NewRequest(http.MethodGet, "/me", nil))
+		if rec.Code != http.StatusOK || rec.Body.String() != "alice" {
+			t.Fatalf("unexpected response %d %q", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("automatic options goes through middlewares", func(t *testing.T) {
+		sut := newServer()
+		sut.Server.Use(func(ctx httpx.IContext, next func() error) error {
+			ctx.SetHeader("X-Seen", "1")
+			return next()
+		})
+		sut.Server.GET("/items", func(ctx httpx.IContext) error { return ctx.String(http.StatusOK, "ok") })
+		sut.Server.POST("/items", func(ctx httpx.IContext) error { return ctx.String(http.StatusCreated, "ok") })
+
+		rec := serve(t, sut.Handler(), httptest.NewRequest(http.MethodOptions, "/items", nil))
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("expected 204, got %d", rec.Code)
+		}
+		if got := rec.Header().Get("Allow"); got != "GET, POST, OPTIONS" {
+			t.Fatalf("unexpected Allow header %q", got)
+		}
+		if rec.Header().Get("X-Seen") != "1" {
+			t.Fatalf("expected automatic OPTIONS to run middlewares")
+		}
+	})
+}
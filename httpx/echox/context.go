@@ -0,0 +1,42 @@
+package echox
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"gochen/httpx"
+	"gochen/httpx/nethttp"
+)
+
+// Context 是 `httpx.IContext` 在 Echo 适配层上的实现。
+//
+// 说明：
+//   - 请求读取、严格 JSON 绑定、响应写出与状态记录复用 `nethttp.Context`；
+//   - 路由参数取自 echo 路由匹配结果；
+//   - ClientIP 使用 echo 的 IPExtractor 配置（`Echo().IPExtractor`）。
+type Context struct {
+	*nethttp.Context
+	echo echo.Context
+}
+
+var _ httpx.IContext = (*Context)(nil)
+
+// NewContext 基于 echo.Context 创建适配层上下文。
+func NewContext(c echo.Context) (*Context, error) {
+	base, err := nethttp.NewBaseContext(c.Response(), c.Request())
+	if err != nil {
+		return nil, err
+	}
+	values := c.ParamValues()
+	for i, name := range c.ParamNames() {
+		if i < len(values) {
+			base.SetParam(name, values[i])
+		}
+	}
+	return &Context{Context: base, echo: c}, nil
+}
+
+// ClientIP 基于 echo 的 IPExtractor 解析客户端真实 IP。
+func (c *Context) ClientIP() string { return c.echo.RealIP() }
+
+// Echo 返回底层 echo.Context，便于在 handler 中调用 echo 特有能力。
+func (c *Context) Echo() echo.Context { return c.echo }
@@ -0,0 +1,158 @@
+// Package echox 提供基于 labstack/echo 的 httpx 适配实现。
+//
+// 该包是独立 module（gochen/httpx/echox），gochen 核心 module 不依赖 echo。
+package echox
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+
+	"gochen/httpx"
+	"gochen/httpx/adapter"
+)
+
+// Server 是 `httpx.IServer` 在 Echo 上的实现。
+//
+// 说明：
+//   - 路由先记录在 adapter.Table，首次 Start/Handler 时统一注册到 echo.Echo；
+//   - 路由、分组、中间件与自动 OPTIONS 语义与 httpx/nethttp 一致（见 httpx/adapter）；
+//   - echo 原生中间件通过 UseEcho 挂载，在 httpx 中间件之前执行。
+type Server struct {
+	echo       *echo.Echo
+	config     *httpx.WebConfig
+	server     *http.Server
+	table      *adapter.Table
+	registered sync.Once
+}
+
+var _ httpx.IServer = (*Server)(nil)
+
+// NewServer 创建 Echo 适配层服务器；e 为 nil 时使用 echo.New()。
+func NewServer(config *httpx.WebConfig, e *echo.Echo) *Server {
+	if config == nil {
+		config = &httpx.WebConfig{}
+	}
+	if e == nil {
+		e = echo.New()
+		e.HideBanner = true
+		e.HidePort = true
+	}
+	return &Server{echo: e, config: config, table: adapter.NewTable()}
+}
+
+// Echo 返回底层 *echo.Echo。
+func (s *Server) Echo() *echo.Echo { return s.echo }
+
+// UseEcho 挂载 echo 原生中间件（如 middleware.Recover、middleware.Gzip）。
+func (s *Server) UseEcho(middleware ...echo.MiddlewareFunc) *Server {
+	s.echo.Use(middleware...)
+	return s
+}
+
+// GET 注册 GET 路由。
+func (s *Server) GET(path string, handler httpx.Handler) httpx.IServer {
+	s.table.Add(http.MethodGet, path, handler)
+	return s
+}
+
+// POST 注册 POST 路由。
+func (s *Server) POST(path string, handler httpx.Handler) httpx.IServer {
+	s.table.Add(http.MethodPost, path, handler)
+	return s
+}
+
+// PUT 注册 PUT 路由。
+func (s *Server) PUT(path string, handler httpx.Handler) httpx.IServer {
+	s.table.Add(http.MethodPut, path, handler)
+	return s
+}
+
+// DELETE 注册 DELETE 路由。
+func (s *Server) DELETE(path string, handler httpx.Handler) httpx.IServer {
+	s.table.Add(http.MethodDelete, path, handler)
+	return s
+}
+
+// PATCH 注册 PATCH 路由。
+func (s *Server) PATCH(path string, handler httpx.Handler) httpx.IServer {
+	s.table.Add(http.MethodPatch, path, handler)
+	return s
+}
+
+// HEAD 注册 HEAD 路由。
+func (s *Server) HEAD(path string, handler httpx.Handler) httpx.IServer {
+	s.table.Add(http.MethodHead, path, handler)
+	return s
+}
+
+// OPTIONS 注册 OPTIONS 路由。
+func (s *Server) OPTIONS(path string, handler httpx.Handler) httpx.IServer {
+	s.table.Add(http.MethodOptions, path, handler)
+	return s
+}
+
+// Group 创建一个带前缀的路由分组。
+func (s *Server) Group(prefix string) httpx.IRouteGroup { return s.table.Group(prefix) }
+
+// Use 追加全局中间件。
+func (s *Server) Use(middleware ...httpx.Middleware) httpx.IServer {
+	s.table.Use(middleware...)
+	return s
+}
+
+// Static 以 prefix 暴露 root 目录下的静态文件。
+func (s *Server) Static(prefix, root string) httpx.IServer {
+	s.echo.Static(prefix, root)
+	return s
+}
+
+// ServeStatic 将单个文件暴露在 path 上。
+func (s *Server) ServeStatic(path, root string) {
+	s.echo.File(path, root)
+}
+
+// Handler 完成路由注册并返回可挂载的 http.Handler。
+func (s *Server) Handler() http.Handler {
+	s.registered.Do(func() {
+		for _, r := range s.table.Routes() {
+			s.echo.Add(r.Method, r.Path, s.createHandler(r))
+		}
+	})
+	return s.echo
+}
+
+// Start 启动底层 HTTP 服务。
+func (s *Server) Start(addr string) error {
+	srv, err := adapter.NewHTTPServer(s.config, addr, s.Handler())
+	if err != nil {
+		return err
+	}
+	s.server = srv
+	return adapter.ListenAndServe(s.config, srv)
+}
+
+// Stop 在给定上下文约束下优雅关闭底层 HTTP 服务。
+func (s *Server) Stop(ctx context.Context) error {
+	if s.server == nil {
+		return nil
+	}
+	return s.server.Shutdown(ctx)
+}
+
+// HealthCheck 为与上层 Server 抽象对齐保留一个空实现。
+func (s *Server) HealthCheck() error { return nil }
+
+// createHandler 把 httpx 路由包装为 echo handler；错误已由 adapter.Table 写出，不再交给 echo 的错误处理器。
+func (s *Server) createHandler(r adapter.Route) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx, err := NewContext(c)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError)
+		}
+		s.table.Dispatch(ctx, r)
+		return nil
+	}
+}
@@ -0,0 +1,38 @@
+package echox
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"gochen/httpx"
+)
+
+// TestServer_ReusesEchoMiddleware 验证 echo 原生中间件可与 httpx handler 共存。
+func TestServer_ReusesEchoMiddleware(t *testing.T) {
+	s := NewServer(nil, nil)
+	s.UseEcho(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Response().Header().Set("X-Echo", "1")
+			c.Set("from-echo", "yes")
+			return next(c)
+		}
+	})
+	s.GET("/ping", func(ctx httpx.IContext) error {
+		ec, ok := ctx.(*Context)
+		if !ok {
+			t.Fatalf("expected *echox.Context, got %T", ctx)
+		}
+		v, _ := ec.Echo().Get("from-echo").(string)
+		return ctx.String(http.StatusOK, v)
+	})
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	if rec.Header().Get("X-Echo") != "1" || rec.Body.String() != "yes" {
+		t.Fatalf("unexpected response: header=%q body=%q", rec.Header().Get("X-Echo"), rec.Body.String())
+	}
+}
@@ -0,0 +1,44 @@
+package fiberx
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gochen/httpx"
+	"gochen/httpx/nethttp"
+)
+
+// Context 是 `httpx.IContext` 在 Fiber 适配层上的实现。
+//
+// 说明：
+//   - Fiber 基于 fasthttp，请求经 fiber 的 net/http adaptor 转换后复用 `nethttp.Context`，
+//     保证读取、严格 JSON 绑定与响应写出语义与参考实现一致；
+//   - 路由参数与 ClientIP 取自 fiber 路由匹配结果与代理配置（`fiber.Config.ProxyHeader`）。
+type Context struct {
+	*nethttp.Context
+	fiber *fiber.Ctx
+	ip    string
+}
+
+var _ httpx.IContext = (*Context)(nil)
+
+// newContext 基于转换后的 net/http 请求与 fiber.Ctx 创建适配层上下文。
+func newContext(w http.ResponseWriter, r *http.Request, c *fiber.Ctx, params map[string]string, ip string) (*Context, error) {
+	base, err := nethttp.NewBaseContext(w, r)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range params {
+		base.SetParam(name, value)
+	}
+	return &Context{Context: base, fiber: c, ip: ip}, nil
+}
+
+// ClientIP 返回 fiber 解析出的客户端 IP。
+func (c *Context) ClientIP() string { return c.ip }
+
+// Fiber 返回底层 *fiber.Ctx，便于在 handler 中调用 fiber 特有能力。
+//
+// 注意：fiber.Ctx 仅在请求处理期间有效，不得在 handler 返回后继续持有。
+func (c *Context) Fiber() *fiber.Ctx { return c.fiber }
@@ -0,0 +1,194 @@
+// Package fiberx 提供基于 gofiber/fiber 的 httpx 适配实现。
+//
+// 该包是独立 module（gochen/httpx/fiberx），gochen 核心 module 不依赖 fiber。
+package fiberx
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"gochen/errors"
+	"gochen/httpx"
+	"gochen/httpx/adapter"
+)
+
+// Server 是 `httpx.IServer` 在 Fiber 上的实现。
+//
+// 说明：
+//   - 路由先记录在 adapter.Table，首次 Start/Handler 时统一注册到 fiber.App；
+//   - 路由、分组、中间件与自动 OPTIONS 语义与 httpx/nethttp 一致（见 httpx/adapter）；
+//   - fiber 原生中间件通过 UseFiber 挂载，需在首次 Start/Handler 之前调用。
+type Server struct {
+	app        *fiber.App
+	config     *httpx.WebConfig
+	table      *adapter.Table
+	registered sync.Once
+}
+
+var _ httpx.IServer = (*Server)(nil)
+
+// NewServer 创建 Fiber 适配层服务器；app 为 nil 时按 WebConfig 的超时配置创建 fiber.App。
+func NewServer(config *httpx.WebConfig, app *fiber.App) *Server {
+	if config == nil {
+		config = &httpx.WebConfig{}
+	}
+	if app == nil {
+		app = fiber.New(fiberConfig(config))
+	}
+	return &Server{app: app, config: config, table: adapter.NewTable()}
+}
+
+// fiberConfig 把 WebConfig 中的超时设置映射到 fiber.Config，未设置时使用 httpx 默认值。
+func fiberConfig(config *httpx.WebConfig) fiber.Config {
+	cfg := fiber.Config{
+		DisableStartupMessage: true,
+		ReadTimeout:           httpx.DefaultReadTimeout,
+		WriteTimeout:          httpx.DefaultWriteTimeout,
+		IdleTimeout:           httpx.DefaultIdleTimeout,
+	}
+	if config.ReadTimeout > 0 {
+		cfg.ReadTimeout = config.ReadTimeout
+	}
+	if config.WriteTimeout > 0 {
+		cfg.WriteTimeout = config.WriteTimeout
+	}
+	if config.IdleTimeout > 0 {
+		cfg.IdleTimeout = config.IdleTimeout
+	}
+	return cfg
+}
+
+// App 返回底层 *fiber.App。
+func (s *Server) App() *fiber.App { return s.app }
+
+// UseFiber 挂载 fiber 原生中间件（如 recover、compress），在 httpx 中间件之前执行。
+func (s *Server) UseFiber(handlers ...fiber.Handler) *Server {
+	for _, h := range handlers {
+		s.app.Use(h)
+	}
+	return s
+}
+
+// GET 注册 GET 路由。
+func (s *Server) GET(path string, handler httpx.Handler) httpx.IServer {
+	s.table.Add(http.MethodGet, path, handler)
+	return s
+}
+
+// POST 注册 POST 路由。
+func (s *Server) POST(path string, handler httpx.Handler) httpx.IServer {
+	s.table.Add(http.MethodPost, path, handler)
+	return s
+}
+
+// PUT 注册 PUT 路由。
+func (s *Server) PUT(path string, handler httpx.Handler) httpx.IServer {
+	s.table.Add(http.MethodPut, path, handler)
+	return s
+}
+
+// DELETE 注册 DELETE 路由。
+func (s *Server) DELETE(path string, handler httpx.Handler) httpx.IServer {
+	s.table.Add(http.MethodDelete, path, handler)
+	return s
+}
+
+// PATCH 注册 PATCH 路由。
+func (s *Server) PATCH(path string, handler httpx.Handler) httpx.IServer {
+	s.table.Add(http.MethodPatch, path, handler)
+	return s
+}
+
+// HEAD 注册 HEAD 路由。
+func (s *Server) HEAD(path string, handler httpx.Handler) httpx.IServer {
+	s.table.Add(http.MethodHead, path, handler)
+	return s
+}
+
+// OPTIONS 注册 OPTIONS 路由。
+func (s *Server) OPTIONS(path string, handler httpx.Handler) httpx.IServer {
+	s.table.Add(http.MethodOptions, path, handler)
+	return s
+}
+
+// Group 创建一个带前缀的路由分组。
+func (s *Server) Group(prefix string) httpx.IRouteGroup { return s.table.Group(prefix) }
+
+// Use 追加全局中间件。
+func (s *Server) Use(middleware ...httpx.Middleware) httpx.IServer {
+	s.table.Use(middleware...)
+	return s
+}
+
+// Static 以 prefix 暴露 root 目录下的静态文件（不提供目录列表）。
+func (s *Server) Static(prefix, root string) httpx.IServer {
+	s.app.Static(prefix, root)
+	return s
+}
+
+// ServeStatic 将单个文件暴露在 path 上。
+func (s *Server) ServeStatic(path, root string) {
+	s.app.Get(path, func(c *fiber.Ctx) error { return c.SendFile(root) })
+}
+
+// Handler 完成路由注册并返回 net/http 形式的处理器（经 adaptor 转换，主要用于测试与嵌入）。
+func (s *Server) Handler() http.Handler {
+	s.register()
+	return adaptor.FiberApp(s.app)
+}
+
+// Start 启动底层 fasthttp 服务。
+func (s *Server) Start(addr string) error {
+	s.register()
+	addr = adapter.ListenAddr(s.config, addr)
+	if s.config.TLSEnabled {
+		if s.config.CertFile == "" || s.config.KeyFile == "" {
+			return errors.NewCode(errors.InvalidInput, "tls enabled but cert_file/key_file not configured")
+		}
+		return s.app.ListenTLS(addr, s.config.CertFile, s.config.KeyFile)
+	}
+	return s.app.Listen(addr)
+}
+
+// Stop 在给定上下文约束下优雅关闭底层服务。
+func (s *Server) Stop(ctx context.Context) error {
+	return s.app.ShutdownWithContext(ctx)
+}
+
+// HealthCheck 为与上层 Server 抽象对齐保留一个空实现。
+func (s *Server) HealthCheck() error { return nil }
+
+func (s *Server) register() {
+	s.registered.Do(func() {
+		for _, r := range s.table.Routes() {
+			s.app.Add(r.Method, r.Path, s.createHandler(r))
+		}
+	})
+}
+
+// createHandler 把 httpx 路由包装为 fiber handler。
+//
+// 说明：路由参数与 IP 在转换前从 fiber.Ctx 复制出来（fiber 会复用底层内存），
+// 再经 adaptor 以 net/http 语义执行中间件链。
+func (s *Server) createHandler(r adapter.Route) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params := make(map[string]string, len(c.Route().Params))
+		for name, value := range c.AllParams() {
+			params[name] = utils.CopyString(value)
+		}
+		ip := utils.CopyString(c.IP())
+		return adaptor.HTTPHandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			ctx, err := newContext(w, req, c, params, ip)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			s.table.Dispatch(ctx, r)
+		})(c)
+	}
+}
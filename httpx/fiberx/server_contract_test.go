@@ -0,0 +1,15 @@
+package fiberx
+
+import (
+	"net/http"
+	"testing"
+
+	"gochen/httpx/contracttest"
+)
+
+func TestServer_Contract(t *testing.T) {
+	contracttest.RunServerContractTests(t, func() contracttest.ServerUnderTest {
+		srv := NewServer(nil, nil)
+		return contracttest.ServerUnderTest{Server: srv, Handler: func() http.Handler { return srv.Handler() }}
+	})
+}
@@ -0,0 +1,36 @@
+package fiberx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gochen/httpx"
+)
+
+// TestServer_ReusesFiberMiddleware 验证 fiber 原生中间件可与 httpx handler 共存。
+func TestServer_ReusesFiberMiddleware(t *testing.T) {
+	s := NewServer(nil, nil)
+	s.UseFiber(func(c *fiber.Ctx) error {
+		c.Set("X-Fiber", "1")
+		c.Locals("from-fiber", "yes")
+		return c.Next()
+	})
+	s.GET("/ping", func(ctx httpx.IContext) error {
+		fc, ok := ctx.(*Context)
+		if !ok {
+			t.Fatalf("expected *fiberx.Context, got %T", ctx)
+		}
+		v, _ := fc.Fiber().Locals("from-fiber").(string)
+		return ctx.String(http.StatusOK, v)
+	})
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	if rec.Header().Get("X-Fiber") != "1" || rec.Body.String() != "yes" {
+		t.Fatalf("unexpected response: header=%q body=%q", rec.Header().Get("X-Fiber"), rec.Body.String())
+	}
+}
@@ -0,0 +1,39 @@
+package ginx
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"gochen/httpx"
+	"gochen/httpx/nethttp"
+)
+
+// Context 是 `httpx.IContext` 在 Gin 适配层上的实现。
+//
+// 说明：
+//   - 请求读取、严格 JSON 绑定、响应写出与状态记录复用 `nethttp.Context`，保证与参考实现语义一致；
+//   - 路由参数取自 gin 路由匹配结果；
+//   - ClientIP 使用 gin 自身的受信任代理配置（`Engine().SetTrustedProxies`）。
+type Context struct {
+	*nethttp.Context
+	gin *gin.Context
+}
+
+var _ httpx.IContext = (*Context)(nil)
+
+// NewContext 基于 gin.Context 创建适配层上下文。
+func NewContext(c *gin.Context) (*Context, error) {
+	base, err := nethttp.NewBaseContext(c.Writer, c.Request)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range c.Params {
+		base.SetParam(p.Key, p.Value)
+	}
+	return &Context{Context: base, gin: c}, nil
+}
+
+// ClientIP 基于 gin 的受信任代理配置解析客户端真实 IP。
+func (c *Context) ClientIP() string { return c.gin.ClientIP() }
+
+// Gin 返回底层 *gin.Context，便于在 handler 中调用 gin 特有能力。
+func (c *Context) Gin() *gin.Context { return c.gin }
@@ -0,0 +1,39 @@
+package ginx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"gochen/httpx"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// TestServer_ReusesGinMiddleware 验证 gin 原生中间件可与 httpx handler 共存。
+func TestServer_ReusesGinMiddleware(t *testing.T) {
+	s := NewServer(nil, nil)
+	s.UseGin(func(c *gin.Context) {
+		c.Header("X-Gin", "1")
+		c.Set("from-gin", "yes")
+		c.Next()
+	})
+	s.GET("/ping", func(ctx httpx.IContext) error {
+		gc, ok := ctx.(*Context)
+		if !ok {
+			t.Fatalf("expected *ginx.Context, got %T", ctx)
+		}
+		return ctx.String(http.StatusOK, gc.Gin().GetString("from-gin"))
+	})
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	if rec.Header().Get("X-Gin") != "1" || rec.Body.String() != "yes" {
+		t.Fatalf("unexpected response: header=%q body=%q", rec.Header().Get("X-Gin"), rec.Body.String())
+	}
+}
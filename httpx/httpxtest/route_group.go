@@ -1,6 +1,11 @@
-package testutil
+// Package httpxtest 提供测试 httpx 路由注册与处理器的公共工具。
+package httpxtest
 
-import "gochen/httpx"
+import (
+	"testing"
+
+	"gochen/httpx"
+)
 
 // MockRouteGroup 用于测试的路由分组 stub。
 type MockRouteGroup struct {
@@ -35,6 +40,7 @@ func (m *MockRouteGroup) PUT(path string, handler httpx.Handler) httpx.IRouteGro
 	return m
 }
 
+// DELETE 记录一条 DELETE 路由。
 func (m *MockRouteGroup) DELETE(path string, handler httpx.Handler) httpx.IRouteGroup {
 	m.Handlers[m.key("DELETE", path)] = handler
 	return m
@@ -58,6 +64,16 @@ func (m *MockRouteGroup) OPTIONS(path string, handler httpx.Handler) httpx.IRout
 	return m
 }
 
+// Handler 返回已注册的 `METHOD path` 处理器，未注册时终止测试。
+func (m *MockRouteGroup) Handler(t testing.TB, method, path string) httpx.Handler {
+	t.Helper()
+	handler, ok := m.Handlers[m.key(method, path)]
+	if !ok {
+		t.Fatalf("route %s %s not registered", method, path)
+	}
+	return handler
+}
+
 // Group 在测试 stub 中直接复用当前分组实例。
 func (m *MockRouteGroup) Group(prefix string) httpx.IRouteGroup {
 	_ = prefix
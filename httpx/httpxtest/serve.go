@@ -0,0 +1,27 @@
+package httpxtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gochen/httpx"
+	"gochen/httpx/nethttp"
+)
+
+// Serve 以 req 调用处理器并返回响应；params 为路径参数，处理器返回的错误按框架约定写为错误响应。
+func Serve(t testing.TB, handler httpx.Handler, req *http.Request, params map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	ctx, err := nethttp.NewBaseContext(w, req)
+	if err != nil {
+		t.Fatalf("NewBaseContext returned error: %v", err)
+	}
+	for key, value := range params {
+		ctx.SetParam(key, value)
+	}
+	if err := handler(ctx); err != nil {
+		_ = nethttp.WriteErrorResponse(ctx, err)
+	}
+	return w
+}
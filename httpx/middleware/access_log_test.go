@@ -35,6 +35,7 @@ func (l *captureLogger) Error(_ context.Context, msg string, fields ...logging.F
 
 func (l *captureLogger) WithFields(_ ...logging.Field) logging.ILogger { return l }
 func (l *captureLogger) WithField(_ string, _ any) logging.ILogger     { return l }
+func (l *captureLogger) FromContext(context.Context) logging.ILogger   { return l }
 
 func (l *captureLogger) add(level, msg string, fields ...logging.Field) {
 	l.mu.Lock()
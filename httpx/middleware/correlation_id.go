@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"strings"
+
+	"gochen/contextx"
+	"gochen/httpx"
+)
+
+// CorrelationIDConfig 定义业务关联 ID 配置。
+type CorrelationIDConfig struct {
+	// Header 读取/写入 correlation_id 的头（默认 "X-Correlation-ID"）。
+	Header string
+}
+
+// CorrelationID 注入 correlation_id，并回写到响应头。
+//
+// 说明：
+// - 请求头缺失时沿用 ctx 中的 trace_id（TraceID 中间件在前时），否则生成新值；
+// - correlation_id 随命令/事件 metadata 传播（见 contextx.InjectAll），用于串联同一业务流程的日志。
+func CorrelationID(cfg CorrelationIDConfig) httpx.Middleware {
+	header := strings.TrimSpace(cfg.Header)
+	if header == "" {
+		header = "X-Correlation-ID"
+	}
+
+	return func(ctx httpx.IContext, next func() error) error {
+		reqCtx := ctx.RequestContext()
+		correlationID := httpx.SanitizeIdentifierFromHeader(ctx.Header(header), 128)
+		if correlationID == "" {
+			correlationID = contextx.TraceID(reqCtx)
+		}
+		if correlationID == "" {
+			correlationID = contextx.GenerateTraceID()
+		}
+
+		derived, err := contextx.WithCorrelationID(reqCtx, correlationID)
+		if err != nil {
+			return err
+		}
+		reqCtx = reqCtx.WithContext(derived)
+		ctx.SetContext(reqCtx)
+		ctx.SetHeader(header, correlationID)
+
+		return next()
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import "gochen/httpx"
+
+// FromWebConfig 按 WebConfig 组装内置中间件链，顺序为：
+// Recovery -> RequestID -> CORS（启用时）-> Gzip（启用时）-> Timeout（RequestTimeout>0 时）。
+//
+// 说明：
+// - Recovery 位于最外层，保证其后任意中间件的 panic 都能转换为 Internal 错误；
+// - Gzip 位于 Timeout 之外，确保压缩流在 handler chain 结束后才收尾；
+// - 返回值可直接用于 `server.Use(middleware.FromWebConfig(cfg)...)`；cfg 为 nil 时只包含 Recovery 与 RequestID。
+func FromWebConfig(cfg *httpx.WebConfig) []httpx.Middleware {
+	if cfg == nil {
+		cfg = &httpx.WebConfig{}
+	}
+	chain := []httpx.Middleware{
+		Recovery(),
+		RequestID(RequestIDConfig{Header: cfg.RequestIDHeader}),
+	}
+	if cfg.CORSEnabled && len(cfg.CORSAllowOrigins) > 0 {
+		chain = append(chain, CORSFromWebConfig(cfg))
+	}
+	if cfg.GzipEnabled {
+		chain = append(chain, Gzip(GzipConfig{Level: cfg.GzipLevel, MinLength: cfg.GzipMinLength}))
+	}
+	if cfg.RequestTimeout > 0 {
+		chain = append(chain, Timeout(cfg.RequestTimeout))
+	}
+	return chain
+}
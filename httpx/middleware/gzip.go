@@ -0,0 +1,276 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gochen/httpx"
+	"gochen/httpx/nethttp"
+)
+
+// DefaultGzipMinLength 是默认的最小压缩长度（bytes）；更小的响应压缩收益低于开销。
+const DefaultGzipMinLength = 1024
+
+// defaultGzipContentTypes 是默认压缩的响应类型（前缀匹配，忽略参数）。
+var defaultGzipContentTypes = []string{
+	"application/json",
+	"application/problem+json",
+	"application/javascript",
+	"application/xml",
+	"image/svg+xml",
+	"text/",
+}
+
+// GzipConfig 定义响应压缩配置。
+type GzipConfig struct {
+	// Level 压缩级别（gzip.BestSpeed..gzip.BestCompression）；0 或非法值使用 gzip.DefaultCompression。
+	Level int
+
+	// MinLength 响应体小于该长度时不压缩；<=0 时使用 DefaultGzipMinLength。
+	MinLength int
+
+	// ContentTypes 可压缩的 Content-Type 前缀；为空时使用 JSON/文本类默认列表。
+	//
+	// 说明：text/event-stream 等流式响应始终不压缩，避免缓冲破坏实时推送。
+	ContentTypes []string
+
+	// SkipPaths 不压缩的路径（精确匹配）。
+	SkipPaths []string
+}
+
+// Gzip 在客户端声明 `Accept-Encoding: gzip` 时压缩响应体。
+//
+// 说明：
+// - 依赖上下文支持替换 ResponseWriter（`nethttp.IResponseWriterReplacer`），不支持的适配层直接放行；
+// - 是否压缩在首次写出响应体时决定：已设置 Content-Encoding、状态码不允许 body、类型不在白名单时原样写出；
+// - 响应体先缓冲到 MinLength，handler 结束时仍不足 MinLength 的响应不压缩。
+func Gzip(cfg GzipConfig) httpx.Middleware {
+	level := cfg.Level
+	if level == 0 || level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		level = gzip.DefaultCompression
+	}
+	minLength := cfg.MinLength
+	if minLength <= 0 {
+		minLength = DefaultGzipMinLength
+	}
+	contentTypes := cfg.ContentTypes
+	if len(contentTypes) == 0 {
+		contentTypes = defaultGzipContentTypes
+	}
+	skip := make(map[string]struct{}, len(cfg.SkipPaths))
+	for _, p := range cfg.SkipPaths {
+		if p = strings.TrimSpace(p); p != "" {
+			skip[p] = struct{}{}
+		}
+	}
+	pool := &sync.Pool{New: func() any {
+		w, _ := gzip.NewWriterLevel(nil, level)
+		return w
+	}}
+
+	return func(ctx httpx.IContext, next func() error) error {
+		if _, ok := skip[ctx.Path()]; ok || !acceptsGzip(ctx.Header("Accept-Encoding")) {
+			return next()
+		}
+		w, ok := nethttp.ResponseWriterOf(ctx)
+		if !ok {
+			return next()
+		}
+		gw := &gzipResponseWriter{
+			ResponseWriter: w,
+			pool:           pool,
+			minLength:      minLength,
+			contentTypes:   contentTypes,
+		}
+		if !nethttp.ReplaceResponseWriter(ctx, gw) {
+			return next()
+		}
+		gw.Header().Add("Vary", "Accept-Encoding")
+		err := next()
+		if closeErr := gw.finish(); err == nil {
+			err = closeErr
+		}
+		// 还原底层 writer：handler 未写响应时，上层错误处理直接写出未压缩响应。
+		nethttp.ReplaceResponseWriter(ctx, w)
+		return err
+	}
+}
+
+// acceptsGzip 判断 Accept-Encoding 是否接受 gzip（q=0 视为拒绝）。
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(name), "gzip") {
+			continue
+		}
+		params = strings.ReplaceAll(params, " ", "")
+		if q, ok := strings.CutPrefix(params, "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter 延迟决定是否压缩的 ResponseWriter。
+type gzipResponseWriter struct {
+	http.ResponseWriter
+
+	pool         *sync.Pool
+	minLength    int
+	contentTypes []string
+
+	status      int
+	wroteHeader bool
+	decided     bool
+	compress    bool
+	buf         []byte
+	gz          *gzip.Writer
+}
+
+// WriteHeader 记录状态码；真正提交 header 推迟到确定是否压缩之后。
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = code
+	if code < 200 || code == http.StatusNoContent || code == http.StatusNotModified || code == http.StatusResetContent {
+		w.decide(false)
+	}
+}
+
+// Write 写出响应体；未决定时先缓冲到 minLength。
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.decided {
+		if !w.compressible() {
+			w.decide(false)
+		} else {
+			w.buf = append(w.buf, p...)
+			if len(w.buf) < w.minLength {
+				return len(p), nil
+			}
+			w.decide(true)
+			if err := w.flushBuffer(); err != nil {
+				return 0, err
+			}
+			return len(p), nil
+		}
+	}
+	if w.compress {
+		return w.gz.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush 支持流式响应：未决定时按当前缓冲内容决定，随后刷新压缩器与底层连接。
+func (w *gzipResponseWriter) Flush() {
+	if !w.decided {
+		if !w.wroteHeader {
+			w.WriteHeader(http.StatusOK)
+		}
+		w.decide(w.compressible() && len(w.buf) >= w.minLength)
+		_ = w.flushBuffer()
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack 透传连接接管能力（如 WebSocket 升级），接管后不再压缩。
+func (w *gzipResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.decided = true
+	w.compress = false
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap 供 http.ResponseController 访问底层 ResponseWriter。
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// compressible 判断当前响应头是否允许压缩。
+func (w *gzipResponseWriter) compressible() bool {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := strings.ToLower(strings.TrimSpace(strings.Split(header.Get("Content-Type"), ";")[0]))
+	if contentType == "" || contentType == "text/event-stream" {
+		return false
+	}
+	for _, prefix := range w.contentTypes {
+		if strings.HasPrefix(contentType, strings.ToLower(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// decide 确定是否压缩并提交响应头。
+func (w *gzipResponseWriter) decide(compress bool) {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	w.compress = compress
+	if compress {
+		header := w.Header()
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.gz = w.pool.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+	}
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// flushBuffer 把决定前缓冲的数据写入目标。
+func (w *gzipResponseWriter) flushBuffer() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	buf := w.buf
+	w.buf = nil
+	var err error
+	if w.compress {
+		_, err = w.gz.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// finish 在 handler 结束后收尾：不足 minLength 的缓冲原样写出，压缩流写出尾部并归还到池。
+func (w *gzipResponseWriter) finish() error {
+	if !w.wroteHeader {
+		// handler 未写任何响应，交由上层按原语义处理（如错误响应）。
+		return nil
+	}
+	if !w.decided {
+		w.decide(false)
+	}
+	if err := w.flushBuffer(); err != nil {
+		return err
+	}
+	if w.gz == nil {
+		return nil
+	}
+	err := w.gz.Close()
+	w.gz.Reset(nil)
+	w.pool.Put(w.gz)
+	w.gz = nil
+	return err
+}
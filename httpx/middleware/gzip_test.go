@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gochen/contextx"
+	"gochen/httpx"
+	"gochen/httpx/nethttp"
+)
+
+func newGzipContext(t *testing.T, acceptEncoding string) (*nethttp.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/items", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	ctx, err := nethttp.NewBaseContext(rec, req)
+	if err != nil {
+		t.Fatalf("NewBaseContext returned error: %v", err)
+	}
+	return ctx, rec
+}
+
+// TestGzip_CompressesLargeJSON 验证超过最小长度的 JSON 响应被压缩。
+func TestGzip_CompressesLargeJSON(t *testing.T) {
+	ctx, rec := newGzipContext(t, "br, gzip;q=0.8")
+	body := strings.Repeat("a", 2048)
+
+	err := Gzip(GzipConfig{})(ctx, func() error {
+		return ctx.JSON(http.StatusOK, httpx.JSONValue(map[string]string{"data": body}))
+	})
+	if err != nil {
+		t.Fatalf("middleware returned error: %v", err)
+	}
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("expected gzip encoding, got %q", got)
+	}
+	if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+		t.Fatalf("expected Vary header, got %q", got)
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	plain, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("read gzip body: %v", err)
+	}
+	if !strings.Contains(string(plain), body) {
+		t.Fatalf("unexpected decompressed body length %d", len(plain))
+	}
+}
+
+// TestGzip_SkipsSmallAndUnacceptedResponses 验证小响应、客户端拒绝 gzip 与流式类型不压缩。
+func TestGzip_SkipsSmallAndUnacceptedResponses(t *testing.T) {
+	cases := []struct {
+		name           string
+		acceptEncoding string
+		contentType    string
+		body           string
+	}{
+		{name: "below-min-length", acceptEncoding: "gzip", contentType: "application/json", body: `{"ok":true}`},
+		{name: "not-accepted", acceptEncoding: "gzip;q=0", contentType: "application/json", body: strings.Repeat("a", 4096)},
+		{name: "event-stream", acceptEncoding: "gzip", contentType: "text/event-stream", body: strings.Repeat("a", 4096)},
+		{name: "binary", acceptEncoding: "gzip", contentType: "image/png", body: strings.Repeat("a", 4096)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, rec := newGzipContext(t, tc.acceptEncoding)
+			err := Gzip(GzipConfig{})(ctx, func() error {
+				return ctx.Data(http.StatusOK, tc.contentType, []byte(tc.body))
+			})
+			if err != nil {
+				t.Fatalf("middleware returned error: %v", err)
+			}
+			if got := rec.Header().Get("Content-Encoding"); got != "" {
+				t.Fatalf("expected no encoding, got %q", got)
+			}
+			if rec.Body.String() != tc.body {
+				t.Fatalf("unexpected body length %d", rec.Body.Len())
+			}
+		})
+	}
+}
+
+// TestGzip_RestoresWriterForErrorResponses 验证 handler 未写响应时，上层错误响应不会被缓冲吞掉。
+func TestGzip_RestoresWriterForErrorResponses(t *testing.T) {
+	ctx, rec := newGzipContext(t, "gzip")
+
+	_ = Gzip(GzipConfig{})(ctx, func() error { return nil })
+	if err := ctx.JSON(http.StatusBadRequest, httpx.JSONValue(map[string]string{"error": "bad"})); err != nil {
+		t.Fatalf("write error response: %v", err)
+	}
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "bad") {
+		t.Fatalf("unexpected response: %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+// TestFromWebConfig_AssemblesChain 验证按 WebConfig 组装的中间件链注入请求 ID 并启用超时。
+func TestFromWebConfig_AssemblesChain(t *testing.T) {
+	if got := len(FromWebConfig(nil)); got != 2 {
+		t.Fatalf("expected recovery and request id only, got %d middlewares", got)
+	}
+
+	cfg := &httpx.WebConfig{
+		RequestIDHeader: "X-Correlation-ID",
+		RequestTimeout:  time.Second,
+		GzipEnabled:     true,
+	}
+	chain := FromWebConfig(cfg)
+	if len(chain) != 4 {
+		t.Fatalf("expected 4 middlewares, got %d", len(chain))
+	}
+
+	ctx, rec := newGzipContext(t, "")
+	var requestID string
+	var hasDeadline bool
+	err := httpx.Chain(chain...)(ctx, func() error {
+		requestID = contextx.RequestID(ctx.RequestContext())
+		_, hasDeadline = ctx.RequestContext().Deadline()
+		return ctx.String(http.StatusOK, "ok")
+	})
+	if err != nil {
+		t.Fatalf("chain returned error: %v", err)
+	}
+	if requestID == "" || rec.Header().Get("X-Correlation-ID") != requestID {
+		t.Fatalf("expected request id %q echoed, got %q", requestID, rec.Header().Get("X-Correlation-ID"))
+	}
+	if !hasDeadline {
+		t.Fatalf("expected request timeout to set a deadline")
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"strings"
+
+	"gochen/httpx"
+	"gochen/i18n"
+)
+
+// LocaleConfig 定义请求语言协商配置。
+type LocaleConfig struct {
+	// Catalog 消息目录（默认：i18n.DefaultCatalog()）。
+	Catalog *i18n.Catalog
+
+	// QueryParam 可选：优先从该查询参数读取语言（例如 "lang"），为空时只看 Accept-Language。
+	QueryParam string
+}
+
+// Locale 按查询参数或 Accept-Language 选择语言，把 i18n.Localizer 写入请求 context，并回写 Content-Language。
+//
+// 说明：
+// - 错误响应编码（httpx.EncodeErrorResponse / ProblemDetails）会据此本地化错误消息与字段错误；
+// - handler 内可通过 i18n.FromContext(ctx) 取得 Localizer 翻译业务消息。
+func Locale(cfg LocaleConfig) httpx.Middleware {
+	catalog := cfg.Catalog
+	if catalog == nil {
+		catalog = i18n.DefaultCatalog()
+	}
+	queryParam := strings.TrimSpace(cfg.QueryParam)
+
+	return func(ctx httpx.IContext, next func() error) error {
+		requested := ""
+		if queryParam != "" {
+			requested = ctx.Query(queryParam)
+		}
+		if requested == "" {
+			requested = ctx.Header("Accept-Language")
+		}
+		localizer := catalog.Localizer(requested)
+
+		reqCtx := ctx.RequestContext()
+		ctx.SetContext(reqCtx.WithContext(i18n.WithLocalizer(reqCtx, localizer)))
+		ctx.SetHeader("Content-Language", localizer.Locale())
+
+		return next()
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"gochen/errors"
+	"gochen/httpx"
+	"gochen/i18n"
+	"gochen/validate"
+)
+
+// TestLocale_LocalizesProblemDetails 验证按 Accept-Language 本地化错误消息与字段错误。
+func TestLocale_LocalizesProblemDetails(t *testing.T) {
+	ctx, rec := newNetHTTPContext(t, http.MethodPost, "")
+	ctx.Request().Header.Set("Accept-Language", "zh-CN,zh;q=0.9,en;q=0.8")
+
+	locale := Locale(LocaleConfig{})
+	problem := ProblemDetails(ProblemDetailsConfig{IncludeDetails: true})
+
+	type signup struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+	err := locale(ctx, func() error {
+		return problem(ctx, func() error { return validate.Struct(signup{Email: "bad"}) })
+	})
+	if err != nil {
+		t.Fatalf("middleware returned error: %v", err)
+	}
+	if got := rec.Header().Get("Content-Language"); got != i18n.LocaleZH {
+		t.Fatalf("expected Content-Language zh, got %q", got)
+	}
+
+	var body struct {
+		Detail  string `json:"detail"`
+		Details struct {
+			Fields []validate.FieldError `json:"fields"`
+		} `json:"details"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Detail != "参数校验失败" {
+		t.Fatalf("expected localized detail, got %q", body.Detail)
+	}
+	if len(body.Details.Fields) != 1 || body.Details.Fields[0].Message != "邮箱格式不正确" {
+		t.Fatalf("expected localized field errors, got %+v", body.Details.Fields)
+	}
+}
+
+// TestLocale_QueryParamAndFallback 验证查询参数优先、未知语言回退默认语言，且英文保留原始消息。
+func TestLocale_QueryParamAndFallback(t *testing.T) {
+	ctx, rec := newNetHTTPContext(t, http.MethodGet, "")
+	ctx.Request().Header.Set("Accept-Language", "fr-FR")
+
+	err := Locale(LocaleConfig{QueryParam: "lang"})(ctx, func() error {
+		status, payload := httpx.EncodeErrorResponse(ctx, errors.NewCode(errors.NotFound, "order 42 not found"))
+		if status != http.StatusNotFound || payload.Message != "order 42 not found" {
+			t.Fatalf("unexpected response %d %+v", status, payload)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("middleware returned error: %v", err)
+	}
+	if got := rec.Header().Get("Content-Language"); got != i18n.LocaleEN {
+		t.Fatalf("expected fallback to en, got %q", got)
+	}
+}
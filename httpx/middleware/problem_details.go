@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"gochen/httpx"
+)
+
+// ProblemDetailsConfig problem details 中间件配置。
+type ProblemDetailsConfig struct {
+	// TypeBaseURI 问题类型 URI 前缀；为空时 type 为 "about:blank"。
+	TypeBaseURI string
+
+	// IncludeDetails 是否输出 AppError 上下文字段（仅 4xx，默认关闭）。
+	IncludeDetails bool
+}
+
+// ProblemDetails 创建把 handler 返回的错误统一转换为 RFC 7807 响应的中间件。
+//
+// 说明：
+// - 错误码到状态码的映射与 httpx.EncodeErrorResponse 一致（validation/invalid input -> 400，not found -> 404，
+//   conflict/concurrency/duplicate -> 409 等），响应体中的 code 字段为稳定的 errors.ErrorCode；
+// - 写出响应后返回 nil，外层中间件可通过 ctx 的状态码观测结果；
+// - 若响应已有字节写出（handler 已自行输出），则保持原错误返回，交由底层 server 处理。
+func ProblemDetails(cfg ProblemDetailsConfig) httpx.Middleware {
+	opts := &httpx.ProblemOptions{
+		TypeBaseURI:    cfg.TypeBaseURI,
+		IncludeDetails: cfg.IncludeDetails,
+	}
+	return func(ctx httpx.IContext, next func() error) error {
+		err := next()
+		if err == nil || ctx == nil {
+			return err
+		}
+		if bw, ok := ctx.(IBytesWriter); ok && bw.BytesWritten() > 0 {
+			return err
+		}
+		if werr := httpx.WriteProblem(ctx, err, opts); werr != nil {
+			return err
+		}
+		return nil
+	}
+}
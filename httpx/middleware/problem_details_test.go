@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+// TestProblemDetails_MapsErrorCategories 验证常见错误类别被映射为 problem+json 响应。
+func TestProblemDetails_MapsErrorCategories(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		code   errors.ErrorCode
+	}{
+		{name: "validation", err: errors.NewCode(errors.Validation, "name is required"), status: http.StatusBadRequest, code: errors.Validation},
+		{name: "not-found", err: errors.NewCode(errors.NotFound, "user not found"), status: http.StatusNotFound, code: errors.NotFound},
+		{name: "conflict", err: errors.NewCode(errors.Conflict, "user exists"), status: http.StatusConflict, code: errors.Conflict},
+		{name: "concurrency", err: errors.NewCode(errors.Concurrency, "version mismatch"), status: http.StatusConflict, code: errors.Concurrency},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, rec := newNetHTTPContext(t, http.MethodGet, "")
+			mw := ProblemDetails(ProblemDetailsConfig{TypeBaseURI: "https://errors.example.com/"})
+
+			if err := mw(ctx, func() error { return tc.err }); err != nil {
+				t.Fatalf("middleware returned error: %v", err)
+			}
+			if rec.Code != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, rec.Code)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != httpx.ProblemContentType {
+				t.Fatalf("expected problem content type, got %q", ct)
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body["code"] != string(tc.code) {
+				t.Fatalf("expected code %q, got %v", tc.code, body["code"])
+			}
+			if body["type"] != "https://errors.example.com/"+httpx.ProblemTypeSlug(tc.code) {
+				t.Fatalf("unexpected type %v", body["type"])
+			}
+			if body["status"] != float64(tc.status) || body["instance"] != "/" {
+				t.Fatalf("unexpected body %v", body)
+			}
+		})
+	}
+}
+
+// TestProblemDetails_HidesInternalDetail 验证 5xx 错误不泄露内部消息与上下文。
+func TestProblemDetails_HidesInternalDetail(t *testing.T) {
+	ctx, rec := newNetHTTPContext(t, http.MethodGet, "")
+	mw := ProblemDetails(ProblemDetailsConfig{IncludeDetails: true})
+
+	err := errors.NewCode(errors.Database, "dial tcp 10.0.0.1:5432 refused").WithContext("dsn", "secret")
+	if mwErr := mw(ctx, func() error { return err }); mwErr != nil {
+		t.Fatalf("middleware returned error: %v", mwErr)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body["type"] != "about:blank" || body["detail"] != "internal server error" {
+		t.Fatalf("unexpected body %v", body)
+	}
+	if _, ok := body["details"]; ok {
+		t.Fatalf("expected no details extension for 5xx, got %v", body)
+	}
+}
+
+// TestProblemDetails_IncludesDetailsForClientErrors 验证开启后 4xx 会输出上下文扩展字段。
+func TestProblemDetails_IncludesDetailsForClientErrors(t *testing.T) {
+	ctx, rec := newNetHTTPContext(t, http.MethodGet, "")
+	mw := ProblemDetails(ProblemDetailsConfig{IncludeDetails: true})
+
+	err := errors.NewCode(errors.Validation, "invalid field").WithContext("field", "email")
+	if mwErr := mw(ctx, func() error { return err }); mwErr != nil {
+		t.Fatalf("middleware returned error: %v", mwErr)
+	}
+
+	var body struct {
+		Details map[string]any `json:"details"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Details["field"] != "email" {
+		t.Fatalf("expected details extension, got %v", body.Details)
+	}
+}
+
+// TestProblemDetails_PassesThroughWhenResponseWritten 验证 handler 已写出响应时保持原错误。
+func TestProblemDetails_PassesThroughWhenResponseWritten(t *testing.T) {
+	ctx, rec := newNetHTTPContext(t, http.MethodGet, "")
+	mw := ProblemDetails(ProblemDetailsConfig{})
+
+	want := errors.NewCode(errors.Conflict, "late failure")
+	err := mw(ctx, func() error {
+		_ = ctx.String(http.StatusOK, "partial")
+		return want
+	})
+	if err != want {
+		t.Fatalf("expected original error, got %v", err)
+	}
+	if rec.Body.String() != "partial" {
+		t.Fatalf("expected body untouched, got %q", rec.Body.String())
+	}
+}
@@ -1,10 +1,13 @@
 package middleware
 
 import (
+	"context"
 	"strings"
 
+	"gochen/contextx"
 	"gochen/errors"
 	"gochen/httpx"
+	"gochen/logging"
 	"gochen/policy/ratelimit"
 )
 
@@ -12,21 +15,65 @@ import (
 type RateLimitConfig struct {
 	ratelimit.Config
 
-	// KeyFn 生成限流 key（默认使用 ctx.ClientIP()）。
+	// KeyFn 生成限流 key（默认使用 ctx.ClientIP()，见 RateLimitKeyByClientIP / RateLimitKeyByHeader / RateLimitKeyByRoute）。
 	KeyFn func(ctx httpx.IContext) string
 
 	// SkipPaths 不限流的路径（精确匹配）。
 	SkipPaths []string
+
+	// Scope 可选：限流 key 前缀。为单个路由/路由组挂载独立中间件时用于区分桶，
+	// 共享分布式 Limiter 时尤其必要。
+	Scope string
+
+	// Limiter 可选：自定义限流后端（如 policy/ratelimit/redis，多实例共享限额）；为空时使用进程内令牌桶（Config）。
+	Limiter ratelimit.ILimiter
+
+	// FailClosed 为 true 时限流后端出错即拒绝请求（503）；默认放行并记录告警。
+	FailClosed bool
+}
+
+// RateLimitKeyByClientIP 按客户端 IP 限流（默认）。
+func RateLimitKeyByClientIP(ctx httpx.IContext) string { return ctx.ClientIP() }
+
+// RateLimitKeyByHeader 按请求头（如 API Key）限流；请求头缺失时回退为匿名 key。
+func RateLimitKeyByHeader(name string) func(ctx httpx.IContext) string {
+	return func(ctx httpx.IContext) string { return ctx.Header(name) }
+}
+
+// IRouteTemplater 定义路由模板读取能力接口（如 /users/:id，httpx/nethttp 及其适配层均已实现）。
+type IRouteTemplater interface {
+	RouteTemplate() string
+}
+
+// RateLimitKeyByRoute 按“方法 + 路由模板”限流，所有客户端共享同一路由的限额。
+//
+// 同一路由的不同路径参数（/users/1、/users/2）共享一个桶；上下文不提供路由模板时，
+// 按 normalizePath 把数字 ID/UUID 段归一化，避免每个资源各占一个桶。
+func RateLimitKeyByRoute(ctx httpx.IContext) string {
+	if rt, ok := ctx.(IRouteTemplater); ok {
+		if template := rt.RouteTemplate(); template != "" {
+			return ctx.Method() + " " + template
+		}
+	}
+	return ctx.Method() + " " + normalizePath(ctx.Path())
 }
 
 // RateLimit 限流中间件（按 key 维度 token bucket）。
 func RateLimit(cfg RateLimitConfig) httpx.Middleware {
 	keyFn := cfg.KeyFn
 	if keyFn == nil {
-		keyFn = func(ctx httpx.IContext) string { return ctx.ClientIP() }
+		keyFn = RateLimitKeyByClientIP
 	}
 
-	limiter := ratelimit.New(cfg.Config)
+	limiter := cfg.Limiter
+	if limiter == nil {
+		limiter = ratelimit.New(cfg.Config)
+	}
+	scope := strings.TrimSpace(cfg.Scope)
+	if scope != "" {
+		scope += ":"
+	}
+	logger := logging.ComponentLogger("httpx.middleware.ratelimit")
 
 	skip := make(map[string]struct{}, len(cfg.SkipPaths))
 	for _, p := range cfg.SkipPaths {
@@ -46,7 +93,19 @@ func RateLimit(cfg RateLimitConfig) httpx.Middleware {
 			key = "anonymous:" + ctx.ClientIP()
 		}
 
-		if !limiter.Allow(key) {
+		var reqCtx context.Context = contextx.Background()
+		if rc := ctx.RequestContext(); rc != nil {
+			reqCtx = rc
+		}
+		allowed, err := limiter.Take(reqCtx, scope+key)
+		if err != nil {
+			if cfg.FailClosed {
+				return errors.Wrap(err, errors.ServiceUnavailable, "rate limiter unavailable")
+			}
+			logger.Warn(reqCtx, "rate limiter unavailable, request allowed", logging.Error(err), logging.String("key", scope+key))
+			return next()
+		}
+		if !allowed {
 			return errors.NewCode(errors.TooManyRequests, "too many requests")
 		}
 
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -67,3 +68,68 @@ func TestRateLimit_UsesConfigAndAnonymousKeyFallback(t *testing.T) {
 		t.Fatalf("request after refill should pass, err=%v", err)
 	}
 }
+
+type stubLimiter struct {
+	keys    []string
+	allowed bool
+	err     error
+}
+
+func (l *stubLimiter) Take(_ context.Context, key string) (bool, error) {
+	l.keys = append(l.keys, key)
+	return l.allowed, l.err
+}
+
+func TestRateLimit_CustomLimiterScopeAndRouteKey(t *testing.T) {
+	limiter := &stubLimiter{allowed: true}
+	mw := RateLimit(RateLimitConfig{Limiter: limiter, Scope: "orders", KeyFn: RateLimitKeyByRoute})
+
+	ctx := &stubContext{path: "/orders", clientIP: "10.0.0.8"}
+	if err := mw(ctx, func() error { return nil }); err != nil {
+		t.Fatalf("request should pass, err=%v", err)
+	}
+	if len(limiter.keys) != 1 || limiter.keys[0] != "orders:GET /orders" {
+		t.Fatalf("unexpected limiter keys: %v", limiter.keys)
+	}
+
+	limiter.allowed = false
+	if err := mw(ctx, func() error { return nil }); !errors.Is(err, errors.TooManyRequests) {
+		t.Fatalf("expected TooManyRequests, err=%v", err)
+	}
+}
+
+func TestRateLimit_LimiterErrorFailOpenOrClosed(t *testing.T) {
+	limiter := &stubLimiter{err: errors.New("redis down")}
+	ctx := &stubContext{path: "/api", clientIP: "10.0.0.8"}
+
+	calls := 0
+	next := func() error {
+		calls++
+		return nil
+	}
+	if err := RateLimit(RateLimitConfig{Limiter: limiter})(ctx, next); err != nil || calls != 1 {
+		t.Fatalf("fail-open should call next, err=%v calls=%d", err, calls)
+	}
+
+	err := RateLimit(RateLimitConfig{Limiter: limiter, FailClosed: true})(ctx, next)
+	if !errors.Is(err, errors.ServiceUnavailable) || calls != 1 {
+		t.Fatalf("fail-closed should reject, err=%v calls=%d", err, calls)
+	}
+}
+
+type routeStubContext struct {
+	stubContext
+	template string
+}
+
+func (c *routeStubContext) RouteTemplate() string { return c.template }
+
+func TestRateLimitKeyByRoute_UsesRouteTemplate(t *testing.T) {
+	templated := &routeStubContext{stubContext: stubContext{path: "/orders/42"}, template: "/orders/:order_id"}
+	if key := RateLimitKeyByRoute(templated); key != "GET /orders/:order_id" {
+		t.Fatalf("unexpected templated key: %s", key)
+	}
+	if key := RateLimitKeyByRoute(&stubContext{path: "/orders/42"}); key != "GET /orders/:id" {
+		t.Fatalf("unexpected normalized key: %s", key)
+	}
+}
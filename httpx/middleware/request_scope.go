@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"gochen/di"
+	"gochen/errors"
+	"gochen/httpx"
+	"gochen/logging"
+)
+
+// RequestScopeConfig 配置请求级 DI 作用域中间件。
+type RequestScopeConfig struct {
+	// Logger 用于输出作用域释放失败日志；nil 表示使用组件默认 logger。
+	Logger logging.ILogger
+}
+
+// RequestScope 为每个请求创建 DI 作用域，挂到请求 context 上，并在请求结束时释放。
+//
+// 说明：
+// - handler 与下游命令钩子经 di.ResolveScoped[T](ctx) 解析 scoped 服务（如当前 principal、tenant、unit of work）；
+// - 作用域以进入本中间件时的请求 context 创建（scoped 构造函数的 context.Context 参数注入该值），应挂在认证、租户、request id 等中间件之后；
+// - 释放失败只记录日志，不改变已写出的响应。
+func RequestScope(factory di.IScopeFactory) httpx.Middleware {
+	return RequestScopeWithConfig(factory, RequestScopeConfig{})
+}
+
+// RequestScopeWithConfig 按配置创建请求级 DI 作用域中间件。
+func RequestScopeWithConfig(factory di.IScopeFactory, cfg RequestScopeConfig) httpx.Middleware {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = logging.ComponentLogger("gochen.http.request_scope")
+	}
+
+	return func(ctx httpx.IContext, next func() error) error {
+		if factory == nil {
+			return errors.NewCode(errors.Internal, "request scope factory is nil")
+		}
+
+		reqCtx := ctx.RequestContext()
+		scope := factory.NewScope(reqCtx)
+		ctx.SetContext(reqCtx.WithContext(di.WithScope(reqCtx, scope)))
+		defer func() {
+			if err := scope.Close(); err != nil {
+				logger.Warn(reqCtx, "request scope close failed",
+					logging.String("method", ctx.Method()),
+					logging.String("path", ctx.Path()),
+					logging.Error(err))
+			}
+		}()
+
+		return next()
+	}
+}
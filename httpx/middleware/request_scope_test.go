@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"gochen/contextx"
+	"gochen/di"
+	dibasic "gochen/di/basic"
+)
+
+type requestScopeTestTenant struct {
+	id     string
+	closed bool
+}
+
+func (t *requestScopeTestTenant) Close() error {
+	t.closed = true
+	return nil
+}
+
+func TestRequestScope_ResolvesScopedServicesAndClosesAtRequestEnd(t *testing.T) {
+	container := dibasic.New()
+	if err := di.Provide(container, func(ctx context.Context) *requestScopeTestTenant {
+		return &requestScopeTestTenant{id: contextx.TenantID(ctx)}
+	}, di.LifetimeScoped); err != nil {
+		t.Fatalf("Provide failed: %v", err)
+	}
+
+	ctx, _ := newNetHTTPContext(t, http.MethodGet, "")
+	tenantCtx, err := contextx.WithTenantID(ctx.RequestContext(), "t1")
+	if err != nil {
+		t.Fatalf("WithTenantID failed: %v", err)
+	}
+	ctx.SetContext(ctx.RequestContext().WithContext(tenantCtx))
+
+	var first, second *requestScopeTestTenant
+	if err := RequestScope(container)(ctx, func() error {
+		var err error
+		if first, err = di.ResolveScoped[*requestScopeTestTenant](ctx.RequestContext()); err != nil {
+			return err
+		}
+		second, err = di.ResolveScoped[*requestScopeTestTenant](ctx.RequestContext())
+		return err
+	}); err != nil {
+		t.Fatalf("middleware returned error: %v", err)
+	}
+
+	if first == nil || first != second || first.id != "t1" {
+		t.Fatalf("expected one tenant instance per request, got %#v %#v", first, second)
+	}
+	if !first.closed {
+		t.Fatal("expected scoped service to be closed at request end")
+	}
+}
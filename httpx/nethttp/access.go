@@ -11,6 +11,11 @@ type IResponseWriterProvider interface {
 	ResponseWriter() http.ResponseWriter
 }
 
+// IResponseWriterReplacer 暴露替换 net/http ResponseWriter 的能力，供包装响应的中间件使用。
+type IResponseWriterReplacer interface {
+	SetResponseWriter(w http.ResponseWriter)
+}
+
 // IHandlerProvider 暴露底层 http.Handler 访问能力，仅供 nethttp 适配层使用。
 type IHandlerProvider interface {
 	Handler() http.Handler
@@ -31,6 +36,16 @@ func ResponseWriterOf(ctx httpx.IContext) (http.ResponseWriter, bool) {
 	return writer, writer != nil
 }
 
+// ReplaceResponseWriter 把抽象上下文的 ResponseWriter 替换为 w；上下文不支持替换时返回 false。
+func ReplaceResponseWriter(ctx httpx.IContext, w http.ResponseWriter) bool {
+	replacer, ok := ctx.(IResponseWriterReplacer)
+	if !ok || replacer == nil || w == nil {
+		return false
+	}
+	replacer.SetResponseWriter(w)
+	return true
+}
+
 // HandlerOf 从抽象 server 中提取底层 http.Handler。
 func HandlerOf(server httpx.IServer) (http.Handler, bool) {
 	provider, ok := server.(IHandlerProvider)
@@ -21,8 +21,10 @@ type Context struct {
 	request *http.Request
 	writer  http.ResponseWriter
 	params  map[string]string
-	reqCtx  httpx.IRequestContext
-	status  int
+	// routeTemplate 是匹配到的路由模板（如 /users/:id），由路由分派时填充。
+	routeTemplate string
+	reqCtx        httpx.IRequestContext
+	status        int
 	// bytesWritten 记录通过 ctx.JSON/String/Data 写出的响应体字节数（best-effort）。
 	bytesWritten int64
 	aborted      bool
@@ -317,9 +319,22 @@ func (c *Context) Request() *http.Request { return c.request }
 // ResponseWriter 返回底层 net/http ResponseWriter。
 func (c *Context) ResponseWriter() http.ResponseWriter { return c.writer }
 
+// SetResponseWriter 替换后续响应写出使用的 ResponseWriter（供 gzip 等包装型中间件使用）；nil 被忽略。
+func (c *Context) SetResponseWriter(w http.ResponseWriter) {
+	if w != nil {
+		c.writer = w
+	}
+}
+
 // SetParam 为当前上下文补充一项路由参数。
 func (c *Context) SetParam(key, value string) { c.params[key] = value }
 
+// RouteTemplate 返回匹配到的路由模板（如 /users/:id）；未经路由分派时为空。
+func (c *Context) RouteTemplate() string { return c.routeTemplate }
+
+// SetRouteTemplate 记录匹配到的路由模板（供适配层在分派时调用）。
+func (c *Context) SetRouteTemplate(template string) { c.routeTemplate = template }
+
 type uploadedFile struct {
 	header *multipart.FileHeader
 }
@@ -193,6 +193,7 @@ func (s *Server) createHandler(r *route) http.HandlerFunc {
 			return
 		}
 		ctx.trustedProxyChecker = s.isTrustedProxy
+		ctx.SetRouteTemplate(r.pattern)
 		s.parsePathParams(ctx, r.pattern, req)
 		// 组装中间件链：全局 -> 路由级
 		middlewares := append([]httpx.Middleware{}, s.middlewares...)
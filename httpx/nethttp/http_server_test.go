@@ -319,10 +319,11 @@ func TestHttpServer_PathParams(t *testing.T) {
 	cfg := &httpx.WebConfig{}
 	srv := NewServer(cfg)
 
-	var gotID string
+	var gotID, gotTemplate string
 
 	srv.GET("/users/:id", func(ctx httpx.IContext) error {
 		gotID = ctx.Param("id")
+		gotTemplate = ctx.(*Context).RouteTemplate()
 		return ctx.String(http.StatusOK, "ok")
 	})
 
@@ -339,6 +340,9 @@ func TestHttpServer_PathParams(t *testing.T) {
 	if gotID != "42" {
 		t.Fatalf("expected id=42, got %q", gotID)
 	}
+	if gotTemplate != "/users/:id" {
+		t.Fatalf("expected route template /users/:id, got %q", gotTemplate)
+	}
 }
 
 func TestHttpServer_ApplyTimeoutDefaults_IncludesHeaderGuards(t *testing.T) {
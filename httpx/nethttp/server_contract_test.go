@@ -0,0 +1,21 @@
+package nethttp
+
+import (
+	"net/http"
+	"testing"
+
+	"gochen/httpx/contracttest"
+)
+
+func TestServer_Contract(t *testing.T) {
+	contracttest.RunServerContractTests(t, func() contracttest.ServerUnderTest {
+		srv := NewServer(nil)
+		return contracttest.ServerUnderTest{
+			Server: srv,
+			Handler: func() http.Handler {
+				srv.registerRoutes()
+				return srv.mux
+			},
+		}
+	})
+}
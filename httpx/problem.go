@@ -0,0 +1,160 @@
+package httpx
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/validate"
+)
+
+// ProblemContentType 是 RFC 7807 problem details 的媒体类型。
+const ProblemContentType = "application/problem+json"
+
+// ProblemDetails 表示 RFC 7807 的错误响应体。
+//
+// 说明：
+//   - Code 为稳定的 errors.ErrorCode 字符串，客户端应以它而不是 Title/Detail 做分支判断；
+//   - Extensions 中的字段会平铺到 JSON 顶层（RFC 7807 扩展成员），与标准字段同名的键会被忽略。
+type ProblemDetails struct {
+	// Type 标识问题类型的 URI；未配置类型前缀时为 "about:blank"。
+	Type string `json:"type"`
+	// Title 为问题类型的简短描述（HTTP 状态文本）。
+	Title string `json:"title"`
+	// Status 为 HTTP 状态码。
+	Status int `json:"status"`
+	// Detail 为本次错误的可读说明（5xx 会做安全兜底）。
+	Detail string `json:"detail,omitempty"`
+	// Instance 标识出错的请求路径。
+	Instance string `json:"instance,omitempty"`
+	// Code 为稳定错误码。
+	Code string `json:"code"`
+	// TraceID 表示链路追踪标识。
+	TraceID string `json:"trace_id,omitempty"`
+	// RequestID 表示请求唯一标识。
+	RequestID string `json:"request_id,omitempty"`
+	// Extensions 为扩展成员。
+	Extensions map[string]any `json:"-"`
+}
+
+// MarshalJSON 把扩展成员平铺到顶层输出。
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	type plain ProblemDetails
+	base, err := json.Marshal(plain(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return base, err
+	}
+	merged := make(map[string]any, len(p.Extensions)+8)
+	for k, v := range p.Extensions {
+		merged[k] = v
+	}
+	var standard map[string]any
+	if err := json.Unmarshal(base, &standard); err != nil {
+		return nil, err
+	}
+	for k, v := range standard {
+		merged[k] = v
+	}
+	return json.Marshal(merged)
+}
+
+// ProblemOptions 控制错误到 problem details 的编码方式。
+type ProblemOptions struct {
+	// TypeBaseURI 问题类型 URI 前缀（例如 "https://errors.example.com"）；
+	// 为空时 type 固定为 "about:blank"。
+	TypeBaseURI string
+
+	// IncludeDetails 是否把 AppError 的上下文字段作为 "details" 扩展输出（仅 4xx）。
+	//
+	// 说明：上下文字段可能包含内部信息，默认关闭。
+	IncludeDetails bool
+}
+
+// ProblemTypeSlug 把错误码转换为 type URI 中使用的 slug（如 NOT_FOUND -> not-found）。
+func ProblemTypeSlug(code errors.ErrorCode) string {
+	return strings.ReplaceAll(strings.ToLower(string(code)), "_", "-")
+}
+
+// EncodeProblemDetails 将 err 编码为 RFC 7807 problem details。
+//
+// 说明：状态码与错误码映射与 EncodeErrorResponse 保持一致，5xx 的 detail 同样做安全兜底。
+func EncodeProblemDetails(ctx IContext, err error, opts *ProblemOptions) (status int, problem *ProblemDetails) {
+	if err == nil {
+		return http.StatusOK, nil
+	}
+	options := ProblemOptions{}
+	if opts != nil {
+		options = *opts
+	}
+
+	normalized := errors.Normalize(err)
+	status = errors.ToHTTPStatus(normalized)
+	code := errors.Code(normalized)
+
+	problem = &ProblemDetails{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: localizedErrorMessage(ctx, status, normalized),
+		Code:   string(code),
+	}
+	if base := strings.TrimRight(strings.TrimSpace(options.TypeBaseURI), "/"); base != "" {
+		problem.Type = base + "/" + ProblemTypeSlug(code)
+	}
+	if options.IncludeDetails && status < http.StatusInternalServerError {
+		var appErr *errors.AppError
+		if errors.As(normalized, &appErr) && appErr != nil {
+			if details := appErr.Details(); len(details) > 0 {
+				problem.Extensions = map[string]any{"details": localizeDetails(ctx, details)}
+			}
+		}
+	}
+	if ctx != nil {
+		problem.Instance = ctx.Path()
+		if reqCtx := ctx.RequestContext(); reqCtx != nil {
+			problem.TraceID = contextx.TraceID(reqCtx)
+			problem.RequestID = contextx.RequestID(reqCtx)
+		}
+	}
+	return status, problem
+}
+
+// localizeDetails 在请求携带 i18n.Localizer 时本地化 "fields" 中的字段错误。
+func localizeDetails(ctx IContext, details map[string]any) map[string]any {
+	l, ok := requestLocalizer(ctx)
+	if !ok {
+		return details
+	}
+	fields, ok := details["fields"].([]validate.FieldError)
+	if !ok {
+		return details
+	}
+	localized := make(map[string]any, len(details))
+	for k, v := range details {
+		localized[k] = v
+	}
+	localized["fields"] = l.FieldErrors(fields)
+	return localized
+}
+
+// WriteProblem 以 application/problem+json 写入错误响应。
+func WriteProblem(ctx IContext, err error, opts *ProblemOptions) error {
+	if ctx == nil {
+		return fmt.Errorf("httpx: ctx is nil")
+	}
+	if err == nil {
+		return nil
+	}
+	status, problem := EncodeProblemDetails(ctx, err, opts)
+	if problem == nil {
+		return nil
+	}
+	payload, merr := json.Marshal(problem)
+	if merr != nil {
+		return errors.Wrap(merr, errors.Internal, "failed to encode problem details")
+	}
+	return ctx.Data(status, ProblemContentType, payload)
+}
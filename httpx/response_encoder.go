@@ -3,6 +3,7 @@ package httpx
 import (
 	"gochen/contextx"
 	"gochen/errors"
+	"gochen/i18n"
 	"net/http"
 	"strings"
 )
@@ -22,7 +23,7 @@ func EncodeErrorResponse(ctx IContext, err error) (status int, payload *Response
 	status = errors.ToHTTPStatus(normalized)
 
 	code := string(errors.Code(normalized))
-	message := safeErrorMessage(status, normalized)
+	message := localizedErrorMessage(ctx, status, normalized)
 
 	payload = NewResponseMessage(code, message)
 	if ctx != nil {
@@ -59,3 +60,29 @@ func safeErrorMessage(status int, err error) string {
 	}
 	return message
 }
+
+// localizedErrorMessage 在请求 context 携带 i18n.Localizer 时返回本地化消息，否则同 safeErrorMessage。
+func localizedErrorMessage(ctx IContext, status int, err error) string {
+	l, ok := requestLocalizer(ctx)
+	if !ok || err == nil {
+		return safeErrorMessage(status, err)
+	}
+	if status >= http.StatusInternalServerError {
+		if message, ok := l.Code(errors.Code(err)); ok {
+			return message
+		}
+		return safeErrorMessage(status, err)
+	}
+	return l.Error(err)
+}
+
+func requestLocalizer(ctx IContext) (*i18n.Localizer, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	reqCtx := ctx.RequestContext()
+	if reqCtx == nil {
+		return nil, false
+	}
+	return i18n.FromContext(reqCtx)
+}
@@ -27,6 +27,49 @@ type IServer interface {
 // Middleware 定义 HTTP 中间件签名。
 type Middleware func(ctx IContext, next func() error) error
 
+// IMiddleware 定义对象形式的中间件契约。
+//
+// 说明：
+// - 携带配置或依赖的中间件可以实现该接口，再通过 MiddlewareOf 挂载到 IServer/IRouteGroup；
+// - Middleware 函数本身也实现了该接口，两种形式可以混合组合。
+type IMiddleware interface {
+	Handle(ctx IContext, next func() error) error
+}
+
+// Handle 让 Middleware 函数满足 IMiddleware。
+func (m Middleware) Handle(ctx IContext, next func() error) error { return m(ctx, next) }
+
+// MiddlewareOf 把 IMiddleware 适配为 Middleware；m 为 nil 时返回直通中间件。
+func MiddlewareOf(m IMiddleware) Middleware {
+	if m == nil {
+		return func(ctx IContext, next func() error) error { return next() }
+	}
+	if fn, ok := m.(Middleware); ok {
+		return fn
+	}
+	return m.Handle
+}
+
+// Chain 把多个中间件按声明顺序组合为一个：第一个最先执行、最后返回；nil 项会被跳过。
+func Chain(middlewares ...Middleware) Middleware {
+	chain := make([]Middleware, 0, len(middlewares))
+	for _, m := range middlewares {
+		if m != nil {
+			chain = append(chain, m)
+		}
+	}
+	return func(ctx IContext, next func() error) error {
+		var run func(i int) error
+		run = func(i int) error {
+			if i == len(chain) {
+				return next()
+			}
+			return chain[i](ctx, func() error { return run(i + 1) })
+		}
+		return run(0)
+	}
+}
+
 // IRouteGroup 定义路由组接口。
 type IRouteGroup interface {
 	GET(path string, handler Handler) IRouteGroup
@@ -0,0 +1,42 @@
+package httpx
+
+import (
+	"reflect"
+	"testing"
+)
+
+type recordingMiddleware struct {
+	name  string
+	order *[]string
+}
+
+func (m recordingMiddleware) Handle(ctx IContext, next func() error) error {
+	*m.order = append(*m.order, m.name)
+	return next()
+}
+
+// TestChain_RunsInDeclarationOrder 验证 Chain 按声明顺序执行，并兼容 IMiddleware 与 nil 项。
+func TestChain_RunsInDeclarationOrder(t *testing.T) {
+	var order []string
+	fn := Middleware(func(ctx IContext, next func() error) error {
+		order = append(order, "fn")
+		return next()
+	})
+
+	chain := Chain(
+		MiddlewareOf(recordingMiddleware{name: "first", order: &order}),
+		nil,
+		fn,
+		MiddlewareOf(nil),
+	)
+	if err := chain(nil, func() error {
+		order = append(order, "handler")
+		return nil
+	}); err != nil {
+		t.Fatalf("chain returned error: %v", err)
+	}
+
+	if want := []string{"first", "fn", "handler"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("unexpected order: %v", order)
+	}
+}
@@ -106,6 +106,21 @@ type WebConfig struct {
 	CORSAllowOrigins []string `json:"cors_allow_origins" yaml:"cors_allow_origins" default:"*"`
 	CORSAllowMethods []string `json:"cors_allow_methods" yaml:"cors_allow_methods" default:"GET,POST,PUT,DELETE,PATCH,OPTIONS"`
 	CORSAllowHeaders []string `json:"cors_allow_headers" yaml:"cors_allow_headers" default:"Origin,Content-Type,Authorization"`
+
+	// 内置中间件（由 `httpx/middleware.FromWebConfig` 组装，适配层不会自动挂载）。
+	//
+	// RequestIDHeader 读取/回写请求 ID 的头；为空时使用 "X-Request-ID"。
+	RequestIDHeader string `json:"request_id_header" yaml:"request_id_header"`
+	// RequestTimeout 整个 handler chain 的超时；0 表示不启用。
+	//
+	// 说明：路由级超时可在对应分组上单独挂载 `middleware.Timeout`，或放入 RouteConfig.Middlewares。
+	RequestTimeout time.Duration `json:"request_timeout" yaml:"request_timeout"`
+	// GzipEnabled 控制是否按 Accept-Encoding 压缩响应体。
+	GzipEnabled bool `json:"gzip_enabled" yaml:"gzip_enabled"`
+	// GzipLevel 压缩级别；0 表示默认级别。
+	GzipLevel int `json:"gzip_level" yaml:"gzip_level"`
+	// GzipMinLength 最小压缩长度（bytes）；0 表示使用默认值。
+	GzipMinLength int `json:"gzip_min_length" yaml:"gzip_min_length"`
 }
 
 // 默认超时配置（适用于未显式配置的开发/简单场景）：
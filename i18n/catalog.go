@@ -0,0 +1,190 @@
+// Package i18n 提供错误与校验消息的本地化：按语言维护消息目录，从 Accept-Language 或 context 选择语言。
+//
+// 框架内置错误消息与校验消息均为英文（SourceLocale "en"）。本地化按以下顺序查找：
+//  1. 以原始英文消息为键的精确翻译（业务可为自己的错误消息补充翻译）；
+//  2. 当前语言即源语言时，保留原始消息；
+//  3. 以错误码为键的通用消息（"error.NOT_FOUND" 等），保证响应语言一致；
+//  4. 以上都没有时回退原始消息。
+//
+// HTTP 层通过 httpx/middleware.Locale 把 Localizer 写入请求 context，错误响应编码会自动使用。
+package i18n
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// LocaleEN 英文。
+	LocaleEN = "en"
+	// LocaleZH 简体中文。
+	LocaleZH = "zh"
+
+	// SourceLocale 是框架内置消息使用的语言。
+	SourceLocale = LocaleEN
+)
+
+// Catalog 是按语言组织的消息目录，并发安全。
+//
+// 消息模板中的 {name} 占位符由 Translate 的 params 替换。
+type Catalog struct {
+	mu            sync.RWMutex
+	defaultLocale string
+	messages      map[string]map[string]string
+}
+
+// NewCatalog 创建空消息目录；defaultLocale 为空时使用 SourceLocale，作为无法匹配时的回退语言。
+func NewCatalog(defaultLocale string) *Catalog {
+	if defaultLocale = normalizeTag(defaultLocale); defaultLocale == "" {
+		defaultLocale = SourceLocale
+	}
+	return &Catalog{
+		defaultLocale: defaultLocale,
+		messages:      map[string]map[string]string{defaultLocale: {}},
+	}
+}
+
+// DefaultCatalog 创建预置 en/zh 错误码与校验规则消息的目录，默认语言为 en。
+func DefaultCatalog() *Catalog {
+	c := NewCatalog(LocaleEN)
+	c.Add(LocaleEN, defaultEN)
+	c.Add(LocaleZH, defaultZH)
+	return c
+}
+
+// Add 为 locale 添加（或覆盖）消息。
+func (c *Catalog) Add(locale string, messages map[string]string) *Catalog {
+	locale = normalizeTag(locale)
+	if locale == "" {
+		return c
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	bucket, ok := c.messages[locale]
+	if !ok {
+		bucket = make(map[string]string, len(messages))
+		c.messages[locale] = bucket
+	}
+	for key, message := range messages {
+		bucket[key] = message
+	}
+	return c
+}
+
+// DefaultLocale 返回回退语言。
+func (c *Catalog) DefaultLocale() string { return c.defaultLocale }
+
+// Locales 返回目录中的语言（按字典序）。
+func (c *Catalog) Locales() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	locales := make([]string, 0, len(c.messages))
+	for locale := range c.messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Lookup 返回 locale 下 key 的消息模板；不存在时返回 false（不回退其他语言）。
+func (c *Catalog) Lookup(locale, key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	message, ok := c.messages[normalizeTag(locale)][key]
+	return message, ok
+}
+
+// Translate 返回 locale 下 key 的消息并替换 {name} 占位符；不存在时依次回退默认语言与 key 本身。
+func (c *Catalog) Translate(locale, key string, params map[string]string) string {
+	message, ok := c.Lookup(locale, key)
+	if !ok {
+		if message, ok = c.Lookup(c.defaultLocale, key); !ok {
+			message = key
+		}
+	}
+	return format(message, params)
+}
+
+// Match 按 Accept-Language（含 q 权重）选择目录中的语言；"zh-CN" 可匹配 "zh"。无法匹配时返回默认语言。
+func (c *Catalog) Match(acceptLanguage string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		if _, ok := c.messages[tag]; ok {
+			return tag
+		}
+		if base, _, found := strings.Cut(tag, "-"); found {
+			if _, ok := c.messages[base]; ok {
+				return base
+			}
+		}
+	}
+	return c.defaultLocale
+}
+
+// Localizer 返回绑定 locale 的本地化器；locale 不在目录中时按 Match 规则回退。
+func (c *Catalog) Localizer(locale string) *Localizer {
+	return &Localizer{catalog: c, locale: c.Match(locale)}
+}
+
+// parseAcceptLanguage 解析 Accept-Language，按 q 权重降序返回规范化的语言标签（忽略 "*" 与 q=0）。
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for part := range strings.SplitSeq(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = normalizeTag(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag: tag, q: q})
+	}
+	slices.SortStableFunc(tags, func(a, b weighted) int {
+		switch {
+		case a.q > b.q:
+			return -1
+		case a.q < b.q:
+			return 1
+		default:
+			return 0
+		}
+	})
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		result = append(result, t.tag)
+	}
+	return result
+}
+
+// normalizeTag 把语言标签规范为小写、以 "-" 分隔（zh_CN -> zh-cn）。
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+}
+
+func format(message string, params map[string]string) string {
+	if len(params) == 0 || !strings.Contains(message, "{") {
+		return message
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for name, value := range params {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(message)
+}
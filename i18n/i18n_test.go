@@ -0,0 +1,83 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"gochen/errors"
+	"gochen/validate"
+)
+
+// TestCatalog_Match 验证 Accept-Language 协商（q 权重、地区回退、默认语言）。
+func TestCatalog_Match(t *testing.T) {
+	c := DefaultCatalog()
+	cases := map[string]string{
+		"":                         LocaleEN,
+		"zh-CN,zh;q=0.9,en;q=0.8":  LocaleZH,
+		"en-US;q=0.5, zh_TW;q=0.9": LocaleZH,
+		"fr-FR, en;q=0.1":          LocaleEN,
+		"de, *":                    LocaleEN,
+		"zh;q=0, en;q=0.3":         LocaleEN,
+		"ZH":                       LocaleZH,
+	}
+	for header, want := range cases {
+		if got := c.Match(header); got != want {
+			t.Errorf("Match(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+// TestLocalizer_Error 验证错误消息的查找顺序：精确翻译 -> 源语言原文 -> 错误码通用消息。
+func TestLocalizer_Error(t *testing.T) {
+	c := DefaultCatalog().Add(LocaleZH, map[string]string{"insufficient funds": "余额不足"})
+	zh := c.Localizer("zh-CN")
+	en := c.Localizer("en")
+
+	funds := errors.NewCode(errors.Validation, "insufficient funds")
+	if got := zh.Error(funds); got != "余额不足" {
+		t.Fatalf("expected exact translation, got %q", got)
+	}
+	if got := en.Error(funds); got != "insufficient funds" {
+		t.Fatalf("expected source message, got %q", got)
+	}
+	missing := errors.NewCode(errors.NotFound, "order 42 not found")
+	if got := zh.Error(missing); got != "资源不存在" {
+		t.Fatalf("expected code message, got %q", got)
+	}
+	if got := zh.T("greeting", nil); got != "greeting" {
+		t.Fatalf("expected key fallback, got %q", got)
+	}
+
+	ctx := WithLocalizer(context.Background(), zh)
+	if l, ok := FromContext(ctx); !ok || l.Locale() != LocaleZH {
+		t.Fatalf("expected localizer in context, got %v %v", l, ok)
+	}
+}
+
+// TestLocalizer_FieldErrors 验证字段错误按规则模板本地化，长度类规则使用 .length 模板。
+func TestLocalizer_FieldErrors(t *testing.T) {
+	type form struct {
+		Name  string `json:"name" validate:"min=3"`
+		Age   int    `json:"age" validate:"min=18"`
+		Color string `json:"color" validate:"custom"`
+	}
+	v := validate.NewTagValidator()
+	if err := v.RegisterRule("custom", func(validate.RuleContext) (string, error) { return "custom failed", nil }); err != nil {
+		t.Fatalf("register rule: %v", err)
+	}
+	fields, _ := validate.FieldErrorsOf(v.Validate(form{Name: "ab", Age: 3}))
+
+	localized := DefaultCatalog().Localizer(LocaleZH).FieldErrors(fields)
+	want := []string{"长度不能少于 3", "不能小于 18", "custom failed"}
+	if len(localized) != len(want) {
+		t.Fatalf("expected %d field errors, got %+v", len(want), localized)
+	}
+	for i, msg := range want {
+		if localized[i].Message != msg {
+			t.Errorf("field %s: got %q, want %q", localized[i].Field, localized[i].Message, msg)
+		}
+	}
+	if fields[0].Message != "length must be at least 3" {
+		t.Fatalf("expected original field errors untouched, got %q", fields[0].Message)
+	}
+}
@@ -0,0 +1,94 @@
+package i18n
+
+import (
+	"context"
+	"strings"
+
+	"gochen/errors"
+	"gochen/validate"
+)
+
+// Localizer 是绑定语言的消息本地化器。
+type Localizer struct {
+	catalog *Catalog
+	locale  string
+}
+
+type localizerKey struct{}
+
+// WithLocalizer 返回携带 Localizer 的 context。
+func WithLocalizer(ctx context.Context, l *Localizer) context.Context {
+	if ctx == nil || l == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, localizerKey{}, l)
+}
+
+// FromContext 返回 context 中的 Localizer。
+func FromContext(ctx context.Context) (*Localizer, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	l, ok := ctx.Value(localizerKey{}).(*Localizer)
+	return l, ok && l != nil
+}
+
+// Locale 返回语言标签。
+func (l *Localizer) Locale() string { return l.locale }
+
+// T 翻译 key 并替换 {name} 占位符，语义见 Catalog.Translate。
+func (l *Localizer) T(key string, params map[string]string) string {
+	return l.catalog.Translate(l.locale, key, params)
+}
+
+// Code 返回错误码的通用消息（"error.<CODE>"）。
+func (l *Localizer) Code(code errors.ErrorCode) (string, bool) {
+	return l.catalog.Lookup(l.locale, "error."+string(code))
+}
+
+// Error 返回 err 的本地化消息，查找顺序见包文档；err 为 nil 时返回空字符串。
+func (l *Localizer) Error(err error) string {
+	if err == nil {
+		return ""
+	}
+	message := err.Error()
+	if appErr, ok := errors.AsType[*errors.AppError](err); ok && appErr != nil {
+		message = appErr.Message()
+	}
+	if translated, ok := l.catalog.Lookup(l.locale, message); ok {
+		return translated
+	}
+	if l.locale == SourceLocale && message != "" {
+		return message
+	}
+	if generic, ok := l.Code(errors.Code(err)); ok {
+		return generic
+	}
+	return message
+}
+
+// FieldErrors 返回本地化后的字段错误副本。
+//
+// 消息模板键为 "validation.<rule>"，可用占位符 {field}、{param}；min/max 作用于长度时优先使用
+// "validation.<rule>.length"。源语言或缺少模板时保留原消息。
+func (l *Localizer) FieldErrors(fields []validate.FieldError) []validate.FieldError {
+	localized := make([]validate.FieldError, len(fields))
+	for i, fe := range fields {
+		localized[i] = fe
+		if l.locale == SourceLocale {
+			continue
+		}
+		params := map[string]string{"field": fe.Field, "param": fe.Param}
+		key := "validation." + fe.Rule
+		if strings.HasPrefix(fe.Message, "length ") {
+			if template, ok := l.catalog.Lookup(l.locale, key+".length"); ok {
+				localized[i].Message = format(template, params)
+				continue
+			}
+		}
+		if template, ok := l.catalog.Lookup(l.locale, key); ok {
+			localized[i].Message = format(template, params)
+		}
+	}
+	return localized
+}
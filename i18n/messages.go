@@ -0,0 +1,87 @@
+package i18n
+
+// defaultEN 是内置英文消息：错误码通用消息与校验规则模板。
+var defaultEN = map[string]string{
+	"error.INTERNAL_ERROR":        "internal server error",
+	"error.INVALID_INPUT":         "invalid request",
+	"error.PAYLOAD_TOO_LARGE":     "request payload too large",
+	"error.NOT_FOUND":             "resource not found",
+	"error.CONFLICT":              "request conflicts with the current state",
+	"error.UNAUTHORIZED":          "authentication required",
+	"error.FORBIDDEN":             "permission denied",
+	"error.TIMEOUT":               "request timed out",
+	"error.TOO_MANY_REQUESTS":     "too many requests",
+	"error.SERVICE_UNAVAILABLE":   "service unavailable",
+	"error.UNSUPPORTED_OPERATION": "operation not supported",
+	"error.VALIDATION_ERROR":      "validation failed",
+	"error.DUPLICATE_ERROR":       "resource already exists",
+	"error.DEPENDENCY_ERROR":      "dependent service failed",
+	"error.CONCURRENCY_ERROR":     "resource was modified concurrently, please retry",
+	"error.PRECONDITION_FAILED":   "resource has changed, please reload and retry",
+	"error.DATABASE_ERROR":        "internal server error",
+	"error.CACHE_ERROR":           "internal server error",
+	"error.QUEUE_ERROR":           "internal server error",
+	"error.NETWORK_ERROR":         "network error",
+
+	"validation.required":         "is required",
+	"validation.min":              "value must be at least {param}",
+	"validation.min.length":       "length must be at least {param}",
+	"validation.max":              "value must be at most {param}",
+	"validation.max.length":       "length must be at most {param}",
+	"validation.len":              "length must be {param}",
+	"validation.oneof":            "must be one of [{param}]",
+	"validation.email":            "must be a valid email address",
+	"validation.eqfield":          "must equal {param}",
+	"validation.nefield":          "must not equal {param}",
+	"validation.gtfield":          "must be greater than {param}",
+	"validation.gtefield":         "must be greater than or equal to {param}",
+	"validation.ltfield":          "must be less than {param}",
+	"validation.ltefield":         "must be less than or equal to {param}",
+	"validation.required_with":    "is required when {param} is present",
+	"validation.required_without": "is required when {param} is absent",
+}
+
+// defaultZH 是内置简体中文消息。
+var defaultZH = map[string]string{
+	"error.INTERNAL_ERROR":        "服务器内部错误",
+	"error.INVALID_INPUT":         "请求参数无效",
+	"error.PAYLOAD_TOO_LARGE":     "请求体过大",
+	"error.NOT_FOUND":             "资源不存在",
+	"error.CONFLICT":              "请求与当前状态冲突",
+	"error.UNAUTHORIZED":          "需要登录认证",
+	"error.FORBIDDEN":             "没有权限",
+	"error.TIMEOUT":               "请求超时",
+	"error.TOO_MANY_REQUESTS":     "请求过于频繁",
+	"error.SERVICE_UNAVAILABLE":   "服务暂不可用",
+	"error.UNSUPPORTED_OPERATION": "不支持的操作",
+	"error.VALIDATION_ERROR":      "参数校验失败",
+	"error.DUPLICATE_ERROR":       "资源已存在",
+	"error.DEPENDENCY_ERROR":      "依赖服务异常",
+	"error.CONCURRENCY_ERROR":     "资源已被并发修改，请重试",
+	"error.PRECONDITION_FAILED":   "资源已变更，请刷新后重试",
+	"error.DATABASE_ERROR":        "服务器内部错误",
+	"error.CACHE_ERROR":           "服务器内部错误",
+	"error.QUEUE_ERROR":           "服务器内部错误",
+	"error.NETWORK_ERROR":         "网络异常",
+
+	"validation.required":         "不能为空",
+	"validation.min":              "不能小于 {param}",
+	"validation.min.length":       "长度不能少于 {param}",
+	"validation.max":              "不能大于 {param}",
+	"validation.max.length":       "长度不能超过 {param}",
+	"validation.len":              "长度必须为 {param}",
+	"validation.oneof":            "必须是 [{param}] 之一",
+	"validation.email":            "邮箱格式不正确",
+	"validation.eqfield":          "必须与 {param} 一致",
+	"validation.nefield":          "不能与 {param} 相同",
+	"validation.gtfield":          "必须大于 {param}",
+	"validation.gtefield":         "必须大于或等于 {param}",
+	"validation.ltfield":          "必须小于 {param}",
+	"validation.ltefield":         "必须小于或等于 {param}",
+	"validation.required_with":    "填写 {param} 时必填",
+	"validation.required_without": "未填写 {param} 时必填",
+
+	"validation failed":         "参数校验失败",
+	"command validation failed": "命令参数校验失败",
+	"invalid request data":      "请求数据格式错误",
+}
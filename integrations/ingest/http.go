@@ -0,0 +1,118 @@
+package ingest
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/httpx"
+	"gochen/integrations/webhook"
+)
+
+// DefaultPath 是外部 webhook 接收端点的默认路径。
+const DefaultPath = "/ingest"
+
+// DefaultTypeHeader 是携带外部消息类型的默认请求头。
+const DefaultTypeHeader = "X-Event-Type"
+
+// RegistrarConfig 定义 webhook 接收端点配置。
+type RegistrarConfig struct {
+	// Path 是路由路径；为空时使用 DefaultPath。
+	Path string
+	// Secret 非空时按 integrations/webhook 约定（Webhook-Id/Timestamp/Signature 头）校验签名，失败返回 401。
+	Secret string
+	// Tolerance 是签名时间戳允许的偏差，默认 webhook.DefaultTolerance。
+	Tolerance time.Duration
+	// IDHeader 是携带外部消息 ID 的请求头，默认 webhook.HeaderID；缺失时以 body 摘要作为 ID。
+	IDHeader string
+	// TypeHeader 是携带外部消息类型的请求头，默认 DefaultTypeHeader。
+	TypeHeader string
+	// Headers 是复制到 Message.Headers 的请求头白名单（如 "X-Partner-Id"），键保持原样。
+	Headers []string
+	// Clock 用于签名时间校验与接收时间，默认真实时钟。
+	Clock clock.IClock
+}
+
+// Registrar 把 Ingester 暴露为外部 webhook 接收端点，实现 host 模块的路由注册器约定。
+//
+// `POST {Path}`：发布成功或重复投递返回 202 与 Result；消息被拒绝返回 400（发送方不应重试）；
+// 发布失败等可重试错误按错误码返回 5xx，发送方可重投。
+type Registrar struct {
+	ingester *Ingester
+	config   RegistrarConfig
+	clock    clock.IClock
+}
+
+// NewRegistrar 创建 webhook 接收路由注册器。
+func NewRegistrar(ingester *Ingester, cfg *RegistrarConfig) *Registrar {
+	config := RegistrarConfig{}
+	if cfg != nil {
+		config = *cfg
+	}
+	config.Path = strings.TrimRight(strings.TrimSpace(config.Path), "/")
+	if config.Path == "" {
+		config.Path = DefaultPath
+	}
+	if config.IDHeader == "" {
+		config.IDHeader = webhook.HeaderID
+	}
+	if config.TypeHeader == "" {
+		config.TypeHeader = DefaultTypeHeader
+	}
+	clk := config.Clock
+	if clk == nil {
+		clk = clock.NewRealClock()
+	}
+	return &Registrar{ingester: ingester, config: config, clock: clk}
+}
+
+// RegisterRoutes 注册 webhook 接收端点。
+func (r *Registrar) RegisterRoutes(group httpx.IRouteGroup) error {
+	if group == nil {
+		return errors.NewCode(errors.InvalidInput, "route group cannot be nil")
+	}
+	if r.ingester == nil {
+		return errors.NewCode(errors.InvalidInput, "ingester cannot be nil")
+	}
+	group.POST(r.config.Path, r.handleReceive)
+	return nil
+}
+
+func (r *Registrar) handleReceive(c httpx.IContext) error {
+	body, err := c.Body()
+	if err != nil {
+		return err
+	}
+	now := r.clock.Now()
+	if r.config.Secret != "" {
+		header := http.Header{}
+		for _, name := range []string{webhook.HeaderID, webhook.HeaderTimestamp, webhook.HeaderSignature} {
+			header.Set(name, c.Header(name))
+		}
+		if err := webhook.Verify(r.config.Secret, header, body, now, r.config.Tolerance); err != nil {
+			return err
+		}
+	}
+
+	msg := &Message{
+		ID:         strings.TrimSpace(c.Header(r.config.IDHeader)),
+		Type:       strings.TrimSpace(c.Header(r.config.TypeHeader)),
+		Body:       body,
+		ReceivedAt: now,
+	}
+	for _, name := range r.config.Headers {
+		if value := c.Header(name); value != "" {
+			if msg.Headers == nil {
+				msg.Headers = make(map[string]string, len(r.config.Headers))
+			}
+			msg.Headers[name] = value
+		}
+	}
+	result, err := r.ingester.Ingest(c.RequestContext(), msg)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusAccepted, httpx.JSONValue(result))
+}
@@ -0,0 +1,304 @@
+// Package ingest 是接收外部消息的防腐层（anti-corruption layer），与 Outbox 互为镜像：
+// Outbox 把内部事件可靠地发出去，ingest 把外部系统的消息可靠地收进来。
+//
+// 组成：
+//   - Message：外部消息的原始信封（来源、ID、类型、头、body），不带任何内部模型假设；
+//   - ITranslator：业务提供的翻译器，把外部消息映射为内部领域事件或命令（messaging.IMessage）；
+//     Router 按外部消息类型分派到不同翻译器；
+//   - Ingester：去重（messaging/dedup）→ 翻译 → 校验（validate）→ 发布到消息总线；
+//     翻译或校验失败的消息视为被拒绝，可写入死信（messaging/deadletter）；
+//   - KafkaConsumer：从 Kafka topic 拉取记录交给 Ingester，处理完成后提交 offset；
+//   - Registrar：接收外部 webhook 的 HTTP 端点，可选校验 integrations/webhook 约定的签名。
+//
+// 语义：外部系统通常至少一次投递，Ingester 以 "来源 + 外部消息 ID" 去重；
+// 发布失败时释放去重键并返回错误，由上游重投。
+package ingest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/messaging/deadletter"
+	"gochen/messaging/dedup"
+	"gochen/validate"
+)
+
+// 写入翻译结果的元数据键，便于追溯消息来自哪条外部消息。
+const (
+	MetadataSource    = "ingest_source"
+	MetadataMessageID = "ingest_message_id"
+)
+
+// Message 是外部消息的原始信封。
+type Message struct {
+	// Source 是来源名（如 "stripe"、"kafka.partner-orders"）；为空时使用 Config.Source。
+	Source string `json:"source"`
+	// ID 是外部消息 ID；为空时以 body 的 SHA-256 作为 ID。
+	ID string `json:"id"`
+	// Type 是外部消息类型，供 Router 分派；可为空。
+	Type       string            `json:"type,omitempty"`
+	Key        string            `json:"key,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       []byte            `json:"body"`
+	ReceivedAt time.Time         `json:"received_at"`
+}
+
+// ITranslator 把外部消息翻译为内部消息（领域事件、命令等）。
+//
+// 返回空切片表示忽略该消息；返回错误表示消息无法翻译，Ingester 将其视为被拒绝，不会重试。
+type ITranslator interface {
+	Translate(ctx context.Context, msg *Message) ([]messaging.IMessage, error)
+}
+
+// TranslatorFunc 是 ITranslator 的函数适配器。
+type TranslatorFunc func(ctx context.Context, msg *Message) ([]messaging.IMessage, error)
+
+// Translate 实现 ITranslator。
+func (f TranslatorFunc) Translate(ctx context.Context, msg *Message) ([]messaging.IMessage, error) {
+	return f(ctx, msg)
+}
+
+// Router 按外部消息类型分派翻译器。
+type Router struct {
+	routes   map[string]ITranslator
+	fallback ITranslator
+}
+
+// NewRouter 创建翻译器路由。
+func NewRouter() *Router {
+	return &Router{routes: make(map[string]ITranslator)}
+}
+
+// Route 为外部消息类型注册翻译器。
+func (r *Router) Route(messageType string, translator ITranslator) *Router {
+	r.routes[messageType] = translator
+	return r
+}
+
+// Fallback 设置未注册类型使用的翻译器；未设置时未注册类型被拒绝。
+func (r *Router) Fallback(translator ITranslator) *Router {
+	r.fallback = translator
+	return r
+}
+
+// Translate 实现 ITranslator。
+func (r *Router) Translate(ctx context.Context, msg *Message) ([]messaging.IMessage, error) {
+	if translator, ok := r.routes[msg.Type]; ok {
+		return translator.Translate(ctx, msg)
+	}
+	if r.fallback != nil {
+		return r.fallback.Translate(ctx, msg)
+	}
+	return nil, errors.NewCode(errors.Unsupported, "no translator for external message type").
+		WithContext("type", msg.Type)
+}
+
+// Status 是单条外部消息的处理结果。
+type Status string
+
+const (
+	// StatusPublished 翻译结果已发布（含翻译为空、无需发布的情况）。
+	StatusPublished Status = "published"
+	// StatusDuplicate 消息已处理过，被去重跳过。
+	StatusDuplicate Status = "duplicate"
+	// StatusRejected 翻译或校验失败，消息被拒绝（已写入死信，如已配置）。
+	StatusRejected Status = "rejected"
+)
+
+// Result 是 Ingest 的处理结果。
+type Result struct {
+	Status Status `json:"status"`
+	// MessageID 是外部消息 ID（未携带时为生成的内容摘要）。
+	MessageID string `json:"message_id"`
+	// Published 是发布到总线的内部消息数。
+	Published int `json:"published"`
+}
+
+// Config 定义 Ingester 配置。
+type Config struct {
+	// Source 是默认来源名，必填；用于去重键与元数据。
+	Source string
+	// Translator 是翻译器，必填。
+	Translator ITranslator
+	// Dedup 是去重存储；为 nil 时不去重。
+	Dedup dedup.IStore
+	// Validator 校验翻译结果的载荷；为 nil 时不校验，传入 validate.TagValidator{} 按 `validate` tag 校验结构体。
+	Validator validate.IValidator
+	// DeadLetter 记录被拒绝的消息；为 nil 时只记录日志。
+	DeadLetter deadletter.ISink
+	// Clock 用于填充接收时间，默认真实时钟。
+	Clock clock.IClock
+	// Logger 为 nil 时使用 "integrations.ingest" 组件日志。
+	Logger logging.ILogger
+}
+
+// Ingester 把外部消息经翻译、去重、校验后发布到消息总线。
+type Ingester struct {
+	bus    messaging.IMessageBus
+	config Config
+	clock  clock.IClock
+	log    logging.ILogger
+}
+
+// NewIngester 创建外部消息接入器；事件与命令均作为 messaging.IMessage 发布到 messageBus。
+func NewIngester(messageBus messaging.IMessageBus, cfg *Config) (*Ingester, error) {
+	if messageBus == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "message bus cannot be nil")
+	}
+	if cfg == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "ingest config cannot be nil")
+	}
+	config := *cfg
+	if strings.TrimSpace(config.Source) == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "ingest source cannot be empty")
+	}
+	if config.Translator == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "ingest translator cannot be nil")
+	}
+	if config.Validator == nil {
+		config.Validator = validate.Noop{}
+	}
+	clk := config.Clock
+	if clk == nil {
+		clk = clock.NewRealClock()
+	}
+	logger := config.Logger
+	if logger == nil {
+		logger = logging.ComponentLogger("integrations.ingest")
+	}
+	return &Ingester{bus: messageBus, config: config, clock: clk, log: logger}, nil
+}
+
+// Source 返回默认来源名。
+func (i *Ingester) Source() string { return i.config.Source }
+
+// Ingest 处理一条外部消息。
+//
+// 被拒绝时返回 StatusRejected 与 InvalidInput 错误（上游不应重投）；其它错误（去重存储、死信、发布失败）
+// 返回 nil Result，上游应稍后重投。
+func (i *Ingester) Ingest(ctx context.Context, msg *Message) (*Result, error) {
+	if msg == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "external message cannot be nil")
+	}
+	if msg.Source == "" {
+		msg.Source = i.config.Source
+	}
+	if msg.ID == "" {
+		sum := sha256.Sum256(msg.Body)
+		msg.ID = hex.EncodeToString(sum[:])
+	}
+	if msg.ReceivedAt.IsZero() {
+		msg.ReceivedAt = i.clock.Now()
+	}
+
+	key := "ingest:" + msg.Source + ":" + msg.ID
+	if i.config.Dedup != nil {
+		reserved, err := i.config.Dedup.Reserve(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if !reserved {
+			return &Result{Status: StatusDuplicate, MessageID: msg.ID}, nil
+		}
+	}
+
+	messages, err := i.translate(ctx, msg)
+	if err != nil {
+		i.release(ctx, key)
+		return i.reject(ctx, msg, err)
+	}
+	if len(messages) > 0 {
+		if err := i.bus.PublishAll(ctx, messages); err != nil {
+			i.release(ctx, key)
+			return nil, errors.Wrap(err, errors.Dependency, "failed to publish ingested messages").
+				WithContext("source", msg.Source).
+				WithContext("message_id", msg.ID)
+		}
+	}
+	i.complete(ctx, key)
+	return &Result{Status: StatusPublished, MessageID: msg.ID, Published: len(messages)}, nil
+}
+
+// translate 调用翻译器并校验、标记翻译结果。
+func (i *Ingester) translate(ctx context.Context, msg *Message) ([]messaging.IMessage, error) {
+	messages, err := i.config.Translator.Translate(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	for n, m := range messages {
+		if m == nil {
+			return nil, errors.NewCode(errors.InvalidInput, "translator returned nil message").
+				WithContext("index", n)
+		}
+		if strings.TrimSpace(m.GetID()) == "" || strings.TrimSpace(m.GetType()) == "" {
+			return nil, errors.NewCode(errors.InvalidInput, "translated message must have id and type").
+				WithContext("index", n)
+		}
+		if err := i.config.Validator.Validate(messaging.PayloadValue(m.GetPayload())); err != nil {
+			return nil, err
+		}
+		md := m.GetMetadata()
+		md.Set(MetadataSource, msg.Source)
+		md.Set(MetadataMessageID, msg.ID)
+		if _, ok := md.Get(messaging.MetadataProducerKey); !ok {
+			// 同一外部消息重投时生成相同的生产者键，供下游去重。
+			md.Set(messaging.MetadataProducerKey, fmt.Sprintf("%s:%s:%d", msg.Source, msg.ID, n))
+		}
+	}
+	return messages, nil
+}
+
+func (i *Ingester) reject(ctx context.Context, msg *Message, cause error) (*Result, error) {
+	i.log.Warn(ctx, "external message rejected",
+		logging.String("source", msg.Source),
+		logging.String("message_id", msg.ID),
+		logging.String("type", msg.Type),
+		logging.Error(cause))
+	if i.config.DeadLetter != nil {
+		raw := *msg
+		raw.Headers = maps.Clone(msg.Headers)
+		entry := deadletter.Entry{
+			Message:     messaging.NewMessageWithClock(i.clock, msg.ID, messaging.KindUnknown, msg.Type, &raw),
+			HandlerType: "ingest." + msg.Source,
+			Err:         cause,
+			OccurredAt:  i.clock.Now(),
+		}
+		if err := i.config.DeadLetter.Write(ctx, entry); err != nil {
+			return nil, errors.Wrap(err, errors.Dependency, "failed to dead-letter rejected message").
+				WithContext("source", msg.Source).
+				WithContext("message_id", msg.ID)
+		}
+	}
+	return &Result{Status: StatusRejected, MessageID: msg.ID},
+		errors.Wrap(cause, errors.InvalidInput, "external message rejected").
+			WithContext("source", msg.Source).
+			WithContext("message_id", msg.ID)
+}
+
+// complete 在发布成功后把去重键标记为已完成；失败时占用在租约过期后可被重投消息再次占用。
+func (i *Ingester) complete(ctx context.Context, key string) {
+	if i.config.Dedup == nil {
+		return
+	}
+	if err := i.config.Dedup.Complete(context.WithoutCancel(ctx), key); err != nil {
+		i.log.Warn(ctx, "failed to complete ingest dedup key", logging.String("key", key), logging.Error(err))
+	}
+}
+
+func (i *Ingester) release(ctx context.Context, key string) {
+	if i.config.Dedup == nil {
+		return
+	}
+	if err := i.config.Dedup.Release(ctx, key); err != nil {
+		i.log.Warn(ctx, "failed to release ingest dedup key", logging.String("key", key), logging.Error(err))
+	}
+}
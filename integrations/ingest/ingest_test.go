@@ -0,0 +1,281 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/httpx/httpxtest"
+	"gochen/integrations/webhook"
+	"gochen/messaging"
+	"gochen/messaging/command"
+	dlqmemory "gochen/messaging/deadletter/memory"
+	"gochen/messaging/dedup"
+	"gochen/messaging/transport/direct"
+	"gochen/validate"
+)
+
+// partnerOrder 是外部合作方的订单消息格式。
+type partnerOrder struct {
+	Ref    string `json:"ref"`
+	Amount int64  `json:"amount_cents"`
+}
+
+// orderImported 是翻译后的内部事件载荷。
+type orderImported struct {
+	ExternalRef string `validate:"required"`
+	Total       int64  `validate:"min=1"`
+}
+
+var orderTranslator = TranslatorFunc(func(_ context.Context, msg *Message) ([]messaging.IMessage, error) {
+	var in partnerOrder
+	if err := json.Unmarshal(msg.Body, &in); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, "invalid partner order")
+	}
+	evt := eventing.NewEvent(int64(7), "Order", "OrderImported", 1, &orderImported{ExternalRef: in.Ref, Total: in.Amount}, 1)
+	evt.ID = "evt-" + msg.ID
+	cmd := command.NewCommand("cmd-"+msg.ID, "ReserveStock", "7", "Order", in.Ref)
+	return []messaging.IMessage{evt, cmd}, nil
+})
+
+type harness struct {
+	bus       *messaging.MessageBus
+	dlq       *dlqmemory.Sink
+	mu        sync.Mutex
+	received  []messaging.IMessage
+	failNext  bool
+	ingester  *Ingester
+	dedupKeys dedup.IStore
+}
+
+func newHarness(t *testing.T, translator ITranslator) *harness {
+	t.Helper()
+	ctx := context.Background()
+	transport := direct.NewSyncTransport()
+	require.NoError(t, transport.Start(ctx))
+	t.Cleanup(func() { _ = transport.Stop(ctx) })
+
+	h := &harness{bus: messaging.NewMessageBus(transport), dlq: dlqmemory.NewSink(), dedupKeys: dedup.NewMemoryStore(nil)}
+	_, err := h.bus.Subscribe(ctx, "*", h)
+	require.NoError(t, err)
+
+	h.ingester, err = NewIngester(h.bus, &Config{
+		Source: "partner", Translator: translator, Dedup: h.dedupKeys, DeadLetter: h.dlq,
+		Validator: validate.TagValidator{},
+		Clock:     clock.NewManualClock(time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)),
+	})
+	require.NoError(t, err)
+	return h
+}
+
+// Handle 记录发布到总线的消息；failNext 为 true 时模拟一次下游失败。
+func (h *harness) Handle(_ context.Context, m messaging.IMessage) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.failNext {
+		h.failNext = false
+		return errors.NewCode(errors.ServiceUnavailable, "downstream unavailable")
+	}
+	h.received = append(h.received, m)
+	return nil
+}
+
+func (h *harness) Type() string { return "ingest-test" }
+
+func TestIngester_TranslatesDedupsAndPublishes(t *testing.T) {
+	ctx := context.Background()
+	h := newHarness(t, NewRouter().Route("order.created", orderTranslator))
+
+	msg := &Message{ID: "m-1", Type: "order.created", Body: []byte(`{"ref":"PO-1","amount_cents":1250}`)}
+	result, err := h.ingester.Ingest(ctx, msg)
+	require.NoError(t, err)
+	assert.Equal(t, &Result{Status: StatusPublished, MessageID: "m-1", Published: 2}, result)
+
+	require.Len(t, h.received, 2)
+	evt := h.received[0]
+	assert.Equal(t, "OrderImported", evt.GetType())
+	source, _ := evt.GetMetadata().Get(MetadataSource)
+	assert.Equal(t, "partner", source)
+	assert.Equal(t, "partner:m-1:0", messaging.ProducerKey(evt))
+	assert.Equal(t, "ReserveStock", h.received[1].GetType())
+	assert.Equal(t, messaging.KindCommand, h.received[1].GetKind())
+
+	result, err = h.ingester.Ingest(ctx, &Message{ID: "m-1", Type: "order.created", Body: msg.Body})
+	require.NoError(t, err)
+	assert.Equal(t, StatusDuplicate, result.Status)
+	assert.Len(t, h.received, 2)
+
+	// 未携带 ID 时按 body 摘要去重。
+	first, err := h.ingester.Ingest(ctx, &Message{Type: "order.created", Body: []byte(`{"ref":"PO-2","amount_cents":1}`)})
+	require.NoError(t, err)
+	second, err := h.ingester.Ingest(ctx, &Message{Type: "order.created", Body: []byte(`{"ref":"PO-2","amount_cents":1}`)})
+	require.NoError(t, err)
+	assert.Equal(t, first.MessageID, second.MessageID)
+	assert.Equal(t, StatusDuplicate, second.Status)
+}
+
+func TestIngester_RejectsAndDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	h := newHarness(t, NewRouter().Route("order.created", orderTranslator))
+
+	for _, msg := range []*Message{
+		{ID: "bad-json", Type: "order.created", Body: []byte(`{`)},
+		{ID: "bad-amount", Type: "order.created", Body: []byte(`{"ref":"PO-1","amount_cents":0}`)},
+		{ID: "unknown", Type: "invoice.paid", Body: []byte(`{}`)},
+	} {
+		result, err := h.ingester.Ingest(ctx, msg)
+		require.Error(t, err, msg.ID)
+		assert.True(t, errors.Is(err, errors.InvalidInput), msg.ID)
+		assert.Equal(t, StatusRejected, result.Status, msg.ID)
+	}
+	assert.Empty(t, h.received)
+
+	entries := h.dlq.Entries()
+	require.Len(t, entries, 3)
+	assert.Equal(t, "ingest.partner", entries[0].HandlerType)
+	assert.Equal(t, "bad-json", entries[0].Message.GetID())
+	raw, ok := messaging.PayloadValue(entries[1].Message.GetPayload()).(*Message)
+	require.True(t, ok)
+	assert.Equal(t, `{"ref":"PO-1","amount_cents":0}`, string(raw.Body))
+
+	// 被拒绝的消息释放去重键，翻译器修复后可重放。
+	reserved, err := h.dedupKeys.Reserve(ctx, "ingest:partner:bad-amount")
+	require.NoError(t, err)
+	assert.True(t, reserved)
+}
+
+func TestIngester_PublishFailureReleasesDedupKey(t *testing.T) {
+	ctx := context.Background()
+	h := newHarness(t, orderTranslator)
+	h.failNext = true
+
+	msg := &Message{ID: "m-1", Body: []byte(`{"ref":"PO-1","amount_cents":5}`)}
+	result, err := h.ingester.Ingest(ctx, msg)
+	require.Error(t, err)
+	assert.Nil(t, result)
+	assert.False(t, errors.Is(err, errors.InvalidInput))
+	assert.Empty(t, h.dlq.Entries())
+
+	result, err = h.ingester.Ingest(ctx, msg)
+	require.NoError(t, err)
+	assert.Equal(t, StatusPublished, result.Status)
+}
+
+// fakeReader 按顺序返回预置记录，并记录提交的 offset。
+type fakeReader struct {
+	mu        sync.Mutex
+	records   []KafkaRecord
+	committed []int64
+	drained   chan struct{}
+}
+
+func (r *fakeReader) Fetch(ctx context.Context) (KafkaRecord, error) {
+	r.mu.Lock()
+	if len(r.records) > 0 {
+		record := r.records[0]
+		r.records = r.records[1:]
+		r.mu.Unlock()
+		return record, nil
+	}
+	r.mu.Unlock()
+	close(r.drained)
+	<-ctx.Done()
+	return KafkaRecord{}, ctx.Err()
+}
+
+func (r *fakeReader) Commit(_ context.Context, records ...KafkaRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, record := range records {
+		r.committed = append(r.committed, record.Offset)
+	}
+	return nil
+}
+
+func TestKafkaConsumer_CommitsAfterIngestAndRetriesFailures(t *testing.T) {
+	h := newHarness(t, NewRouter().Route("order.created", orderTranslator))
+	h.failNext = true
+	reader := &fakeReader{drained: make(chan struct{}), records: []KafkaRecord{
+		{Topic: "orders", Partition: 0, Offset: 10, Value: []byte(`{"ref":"PO-1","amount_cents":5}`),
+			Headers: map[string]string{"type": "order.created"}},
+		{Topic: "orders", Partition: 0, Offset: 11, Value: []byte(`garbage`),
+			Headers: map[string]string{"type": "order.created", "message_id": "ext-11"}},
+	}}
+	consumer, err := NewKafkaConsumer(reader, h.ingester, &KafkaConsumerConfig{MinBackoff: time.Millisecond})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- consumer.Run(ctx) }()
+	select {
+	case <-reader.drained:
+	case <-time.After(2 * time.Second):
+		t.Fatal("consumer did not drain records")
+	}
+	cancel()
+	require.NoError(t, <-done)
+
+	assert.Equal(t, []int64{10, 11}, reader.committed)
+	require.Len(t, h.received, 2, "first record published once after retry")
+	assert.Equal(t, "evt-orders/0/10", h.received[0].GetID())
+	entries := h.dlq.Entries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "ext-11", entries[0].Message.GetID())
+	assert.Equal(t, "ingest.kafka.orders", entries[0].HandlerType)
+}
+
+func TestRegistrar_VerifiesSignatureAndIngests(t *testing.T) {
+	h := newHarness(t, NewRouter().Route("order.created", orderTranslator))
+	now := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	group := httpxtest.NewMockRouteGroup()
+	require.NoError(t, NewRegistrar(h.ingester, &RegistrarConfig{
+		Path: "/hooks/partner/", Secret: "whsec_partner", Headers: []string{"X-Partner-Id"}, Clock: clock.NewManualClock(now),
+	}).RegisterRoutes(group))
+	handler := group.Handlers["POST /hooks/partner"]
+	require.NotNil(t, handler)
+
+	body := []byte(`{"ref":"PO-9","amount_cents":900}`)
+	newRequest := func(secret string, payload []byte) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/hooks/partner", bytes.NewReader(payload))
+		req.Header.Set(DefaultTypeHeader, "order.created")
+		req.Header.Set("X-Partner-Id", "acme")
+		webhook.SetSignatureHeaders(req.Header, secret, "wh-1", now, payload)
+		return req
+	}
+
+	w := httpxtest.Serve(t, handler, newRequest("whsec_wrong", body), nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httpxtest.Serve(t, handler, newRequest("whsec_partner", body), nil)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var result Result
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, Result{Status: StatusPublished, MessageID: "wh-1", Published: 2}, result)
+
+	w = httpxtest.Serve(t, handler, newRequest("whsec_partner", body), nil)
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, StatusDuplicate, result.Status)
+
+	bad := []byte(`{"ref":""}`)
+	req := newRequest("whsec_partner", bad)
+	req.Header.Set(webhook.HeaderID, "wh-2")
+	webhook.SetSignatureHeaders(req.Header, "whsec_partner", "wh-2", now, bad)
+	w = httpxtest.Serve(t, handler, req, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	raw, ok := messaging.PayloadValue(h.dlq.Entries()[0].Message.GetPayload()).(*Message)
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{"X-Partner-Id": "acme"}, raw.Headers)
+}
@@ -0,0 +1,188 @@
+package ingest
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/logging"
+)
+
+// KafkaRecord 是从 Kafka 拉取的一条记录。
+type KafkaRecord struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   map[string]string
+	Time      time.Time
+}
+
+// IKafkaReader 是 consumer group 读取端的最小抽象。
+//
+// 框架核心不依赖 Kafka 客户端，业务侧用几行代码适配所选客户端（如 segmentio/kafka-go 的 Reader：
+// FetchMessage / CommitMessages），见 examples/reference/internal/transport/kafka。
+type IKafkaReader interface {
+	// Fetch 阻塞拉取下一条记录，不自动提交 offset；ctx 结束时返回错误。
+	Fetch(ctx context.Context) (KafkaRecord, error)
+	// Commit 提交记录的 offset。
+	Commit(ctx context.Context, records ...KafkaRecord) error
+}
+
+// Kafka 消费默认配置。
+const (
+	DefaultKafkaIDHeader   = "message_id"
+	DefaultKafkaTypeHeader = "type"
+	DefaultMinBackoff      = 100 * time.Millisecond
+	DefaultMaxBackoff      = 5 * time.Second
+)
+
+// KafkaConsumerConfig 定义 KafkaConsumer 配置。
+type KafkaConsumerConfig struct {
+	// Source 是来源名；为空时为 "kafka." + topic。
+	Source string
+	// IDHeader 是携带外部消息 ID 的头，默认 DefaultKafkaIDHeader；缺失时以 "topic/partition/offset" 作为 ID。
+	IDHeader string
+	// TypeHeader 是携带外部消息类型的头，默认 DefaultKafkaTypeHeader。
+	TypeHeader string
+	// MinBackoff/MaxBackoff 是拉取或处理失败后的退避区间，默认 DefaultMinBackoff/DefaultMaxBackoff。
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// Clock 用于退避等待，默认真实时钟。
+	Clock clock.IClock
+	// Logger 为 nil 时使用 "integrations.ingest.kafka" 组件日志。
+	Logger logging.ILogger
+}
+
+// KafkaConsumer 从 Kafka 拉取记录交给 Ingester，成功发布、去重跳过或被拒绝（已进入死信）后提交 offset；
+// 可重试错误不提交，退避后重新处理同一条记录，保证同一分区内按序、至少一次处理。
+//
+// Run 阻塞运行，可直接登记为后台任务：host.WithWorker("ingest.kafka", consumer.Run)。
+type KafkaConsumer struct {
+	reader   IKafkaReader
+	ingester *Ingester
+	config   KafkaConsumerConfig
+	clock    clock.IClock
+	log      logging.ILogger
+}
+
+// NewKafkaConsumer 创建 Kafka 消费者。
+func NewKafkaConsumer(reader IKafkaReader, ingester *Ingester, cfg *KafkaConsumerConfig) (*KafkaConsumer, error) {
+	if reader == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "kafka reader cannot be nil")
+	}
+	if ingester == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "ingester cannot be nil")
+	}
+	config := KafkaConsumerConfig{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.IDHeader == "" {
+		config.IDHeader = DefaultKafkaIDHeader
+	}
+	if config.TypeHeader == "" {
+		config.TypeHeader = DefaultKafkaTypeHeader
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = DefaultMinBackoff
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = max(DefaultMaxBackoff, config.MinBackoff)
+	}
+	clk := config.Clock
+	if clk == nil {
+		clk = clock.NewRealClock()
+	}
+	logger := config.Logger
+	if logger == nil {
+		logger = logging.ComponentLogger("integrations.ingest.kafka")
+	}
+	return &KafkaConsumer{reader: reader, ingester: ingester, config: config, clock: clk, log: logger}, nil
+}
+
+// Run 持续消费直到 ctx 结束，ctx 结束时返回 nil。
+func (c *KafkaConsumer) Run(ctx context.Context) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	backoff := c.config.MinBackoff
+	for {
+		record, err := c.reader.Fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			c.log.Warn(ctx, "kafka fetch failed", logging.Error(err))
+			if !c.wait(ctx, &backoff) {
+				return nil
+			}
+			continue
+		}
+		// 同一条记录处理成功或被拒绝前不前进，避免跳过分区内后续记录的顺序。
+		for {
+			err := c.Process(ctx, record)
+			if err == nil {
+				backoff = c.config.MinBackoff
+				break
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+			c.log.Warn(ctx, "kafka record processing failed",
+				logging.String("topic", record.Topic),
+				logging.Int("partition", record.Partition),
+				logging.Int64("offset", record.Offset),
+				logging.Error(err))
+			if !c.wait(ctx, &backoff) {
+				return nil
+			}
+		}
+	}
+}
+
+// Process 处理一条记录并在无需重投时提交 offset；返回错误表示应稍后重试同一条记录。
+func (c *KafkaConsumer) Process(ctx context.Context, record KafkaRecord) error {
+	result, err := c.ingester.Ingest(ctx, c.message(record))
+	if err != nil && (result == nil || result.Status != StatusRejected) {
+		return err
+	}
+	// 被拒绝的记录已进入死信，提交 offset 以免毒消息阻塞分区。
+	return c.reader.Commit(ctx, record)
+}
+
+func (c *KafkaConsumer) message(record KafkaRecord) *Message {
+	source := c.config.Source
+	if source == "" {
+		source = "kafka." + record.Topic
+	}
+	id := record.Headers[c.config.IDHeader]
+	if id == "" {
+		id = record.Topic + "/" + strconv.Itoa(record.Partition) + "/" + strconv.FormatInt(record.Offset, 10)
+	}
+	return &Message{
+		Source:     source,
+		ID:         id,
+		Type:       record.Headers[c.config.TypeHeader],
+		Key:        string(record.Key),
+		Headers:    record.Headers,
+		Body:       record.Value,
+		ReceivedAt: record.Time,
+	}
+}
+
+// wait 按当前退避时间等待并翻倍退避；ctx 结束时返回 false。
+func (c *KafkaConsumer) wait(ctx context.Context, backoff *time.Duration) bool {
+	timer := c.clock.NewTimer(*backoff)
+	defer timer.Stop()
+	*backoff = min(*backoff*2, c.config.MaxBackoff)
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C():
+		return true
+	}
+}
@@ -0,0 +1,334 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gochen/clock"
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/httpx"
+	"gochen/ident/uuid"
+)
+
+const (
+	// DefaultBasePath 是 webhook 管理端点的默认路径前缀。
+	DefaultBasePath = "/webhooks"
+	// DefaultDeliveryPageSize 是投递记录列表默认条数。
+	DefaultDeliveryPageSize = 50
+	// MaxDeliveryPageSize 是投递记录列表单页条数上限。
+	MaxDeliveryPageSize = 500
+
+	// QueryStatus 是投递状态过滤参数名。
+	QueryStatus = "status"
+	// QueryLimit 是投递记录条数参数名。
+	QueryLimit = "limit"
+)
+
+// Config 定义 webhook 管理端点配置。
+type Config struct {
+	// BasePath 是路由前缀；为空时使用 DefaultBasePath。
+	BasePath string
+	// Clock 用于写入订阅时间与重投时间，默认真实时钟。
+	Clock clock.IClock
+	// AllowPrivateTargets 为 true 时允许登记本机与内网地址（见 ValidateTargetURL），仅用于测试或受信任的内网部署。
+	AllowPrivateTargets bool
+}
+
+// SubscriptionRequest 是创建/更新订阅的请求体。
+type SubscriptionRequest struct {
+	// ID 仅在创建时生效；为空时生成 UUID。
+	ID  string `json:"id,omitempty"`
+	URL string `json:"url"`
+	// Secret 为空时：创建会生成随机密钥，更新保留原密钥。
+	Secret      string   `json:"secret,omitempty"`
+	EventTypes  []string `json:"event_types"`
+	Description string   `json:"description,omitempty"`
+	// Active 为 nil 时：创建默认启用，更新保持原状态。
+	Active *bool `json:"active,omitempty"`
+	// RotateSecret 为 true 时更新会生成新密钥（Secret 非空时以其为准）。
+	RotateSecret bool `json:"rotate_secret,omitempty"`
+}
+
+// Registrar 把订阅与投递记录存储暴露为管理端点，实现 host 模块的路由注册器约定。
+//
+// 路由：
+//   - `GET {BasePath}/subscriptions`：列出订阅；
+//   - `POST {BasePath}/subscriptions`：创建订阅，响应包含密钥；
+//   - `GET|PUT|DELETE {BasePath}/subscriptions/:id`：查看、更新（轮换密钥时响应包含新密钥）、删除订阅；
+//   - `GET {BasePath}/subscriptions/:id/deliveries`：按创建时间倒序列出投递记录（查询参数 status、limit）；
+//   - `POST {BasePath}/deliveries/:id/retry`：把投递记录重置为 pending，由 Dispatcher 下一轮重投。
+//
+// 除创建与轮换外，响应中不返回签名密钥。端点可修改外发配置，挂载时应配合认证/授权中间件。
+//
+// 订阅按请求 context 的租户（contextx.TenantID）隔离：创建时写入当前租户，其它租户的订阅与投递记录按不存在处理；
+// 没有租户的请求只能管理平台级订阅。
+type Registrar struct {
+	store  IStore
+	config Config
+	clock  clock.IClock
+}
+
+// NewRegistrar 创建 webhook 管理路由注册器。
+func NewRegistrar(store IStore, cfg *Config) *Registrar {
+	config := Config{}
+	if cfg != nil {
+		config = *cfg
+	}
+	config.BasePath = strings.TrimRight(strings.TrimSpace(config.BasePath), "/")
+	if config.BasePath == "" {
+		config.BasePath = DefaultBasePath
+	}
+	clk := config.Clock
+	if clk == nil {
+		clk = clock.NewRealClock()
+	}
+	return &Registrar{store: store, config: config, clock: clk}
+}
+
+// RegisterRoutes 注册 webhook 管理端点。
+func (r *Registrar) RegisterRoutes(group httpx.IRouteGroup) error {
+	if group == nil {
+		return errors.NewCode(errors.InvalidInput, "route group cannot be nil")
+	}
+	if r.store == nil {
+		return errors.NewCode(errors.InvalidInput, "webhook store cannot be nil")
+	}
+	base := r.config.BasePath
+	group.GET(base+"/subscriptions", r.handleList)
+	group.POST(base+"/subscriptions", r.handleCreate)
+	group.GET(base+"/subscriptions/:id", r.handleGet)
+	group.PUT(base+"/subscriptions/:id", r.handleUpdate)
+	group.DELETE(base+"/subscriptions/:id", r.handleDelete)
+	group.GET(base+"/subscriptions/:id/deliveries", r.handleListDeliveries)
+	group.POST(base+"/deliveries/:id/retry", r.handleRetry)
+	return nil
+}
+
+// loadSubscription 返回当前租户可见的订阅；其它租户的订阅返回 NotFound，不暴露其存在。
+func (r *Registrar) loadSubscription(ctx context.Context, id string) (*Subscription, error) {
+	sub, err := r.store.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub.TenantID != contextx.TenantID(ctx) {
+		return nil, subscriptionNotFound(id)
+	}
+	return sub, nil
+}
+
+// validateTarget 校验订阅地址不指向内网（AllowPrivateTargets 时跳过）。
+func (r *Registrar) validateTarget(target string) error {
+	if r.config.AllowPrivateTargets {
+		return nil
+	}
+	return ValidateTargetURL(target)
+}
+
+func (r *Registrar) handleList(c httpx.IContext) error {
+	ctx := c.RequestContext()
+	all, err := r.store.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	tenantID := contextx.TenantID(ctx)
+	subs := make([]*Subscription, 0, len(all))
+	for _, sub := range all {
+		if sub.TenantID != tenantID {
+			continue
+		}
+		sub.Secret = ""
+		subs = append(subs, sub)
+	}
+	return c.JSON(http.StatusOK, httpx.JSONValue(subs))
+}
+
+func (r *Registrar) handleCreate(c httpx.IContext) error {
+	req, err := bindSubscription(c)
+	if err != nil {
+		return err
+	}
+	sub := &Subscription{
+		ID:          strings.TrimSpace(req.ID),
+		TenantID:    contextx.TenantID(c.RequestContext()),
+		URL:         strings.TrimSpace(req.URL),
+		Secret:      req.Secret,
+		EventTypes:  req.EventTypes,
+		Description: req.Description,
+		Active:      req.Active == nil || *req.Active,
+	}
+	if err := r.validateTarget(sub.URL); err != nil {
+		return err
+	}
+	if sub.ID == "" {
+		if sub.ID, err = uuid.NewV7(); err != nil {
+			return errors.Wrap(err, errors.Internal, "failed to generate subscription id")
+		}
+	}
+	if sub.Secret == "" {
+		if sub.Secret, err = NewSecret(); err != nil {
+			return err
+		}
+	}
+	sub.CreatedAt = r.clock.Now()
+	sub.UpdatedAt = sub.CreatedAt
+	if err := r.store.CreateSubscription(c.RequestContext(), sub); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, httpx.JSONValue(sub))
+}
+
+func (r *Registrar) handleGet(c httpx.IContext) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+	sub, err := r.loadSubscription(c.RequestContext(), id)
+	if err != nil {
+		return err
+	}
+	sub.Secret = ""
+	return c.JSON(http.StatusOK, httpx.JSONValue(sub))
+}
+
+func (r *Registrar) handleUpdate(c httpx.IContext) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+	req, err := bindSubscription(c)
+	if err != nil {
+		return err
+	}
+	ctx := c.RequestContext()
+	sub, err := r.loadSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+	sub.URL = strings.TrimSpace(req.URL)
+	if err := r.validateTarget(sub.URL); err != nil {
+		return err
+	}
+	sub.EventTypes = req.EventTypes
+	sub.Description = req.Description
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+	rotated := req.Secret != "" || req.RotateSecret
+	switch {
+	case req.Secret != "":
+		sub.Secret = req.Secret
+	case req.RotateSecret:
+		if sub.Secret, err = NewSecret(); err != nil {
+			return err
+		}
+	}
+	sub.UpdatedAt = r.clock.Now()
+	if err := r.store.UpdateSubscription(ctx, sub); err != nil {
+		return err
+	}
+	if !rotated {
+		sub.Secret = ""
+	}
+	return c.JSON(http.StatusOK, httpx.JSONValue(sub))
+}
+
+func (r *Registrar) handleDelete(c httpx.IContext) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+	ctx := c.RequestContext()
+	if _, err := r.loadSubscription(ctx, id); err != nil {
+		return err
+	}
+	if err := r.store.DeleteSubscription(ctx, id); err != nil {
+		return err
+	}
+	return c.String(http.StatusNoContent, "")
+}
+
+func (r *Registrar) handleListDeliveries(c httpx.IContext) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+	ctx := c.RequestContext()
+	if _, err := r.loadSubscription(ctx, id); err != nil {
+		return err
+	}
+	filter := DeliveryFilter{SubscriptionID: id, Limit: DefaultDeliveryPageSize}
+	if raw := strings.TrimSpace(c.Query(QueryStatus)); raw != "" {
+		filter.Status = DeliveryStatus(raw)
+		switch filter.Status {
+		case DeliveryPending, DeliveryProcessing, DeliveryDelivered, DeliveryFailed:
+		default:
+			return errors.NewCode(errors.InvalidInput, "unknown delivery status").
+				WithContext("param", QueryStatus).
+				WithContext("status", raw)
+		}
+	}
+	if raw := strings.TrimSpace(c.Query(QueryLimit)); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return errors.NewCode(errors.InvalidInput, "query parameter must be a positive integer").
+				WithContext("param", QueryLimit)
+		}
+		filter.Limit = min(limit, MaxDeliveryPageSize)
+	}
+	deliveries, err := r.store.ListDeliveries(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if deliveries == nil {
+		deliveries = []*Delivery{}
+	}
+	return c.JSON(http.StatusOK, httpx.JSONValue(deliveries))
+}
+
+func (r *Registrar) handleRetry(c httpx.IContext) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+	ctx := c.RequestContext()
+	current, err := r.store.GetDelivery(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := r.loadSubscription(ctx, current.SubscriptionID); err != nil {
+		if errors.Is(err, errors.NotFound) {
+			return deliveryNotFound(id)
+		}
+		return err
+	}
+	if err := r.store.RetryDelivery(ctx, id, r.clock.Now()); err != nil {
+		return err
+	}
+	delivery, err := r.store.GetDelivery(ctx, id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusAccepted, httpx.JSONValue(delivery))
+}
+
+func bindSubscription(c httpx.IContext) (*SubscriptionRequest, error) {
+	var req SubscriptionRequest
+	if err := c.BindJSON(&req); err != nil {
+		if errors.Is(err, errors.PayloadTooLarge) {
+			return nil, err
+		}
+		return nil, errors.Wrap(err, errors.InvalidInput, "invalid subscription request")
+	}
+	return &req, nil
+}
+
+func pathID(c httpx.IContext) (string, error) {
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return "", errors.NewCode(errors.InvalidInput, "parameter id cannot be empty")
+	}
+	return id, nil
+}
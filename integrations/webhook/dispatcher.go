@@ -0,0 +1,426 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"gochen/clock"
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/bus"
+	"gochen/eventing/cloudevents"
+	"gochen/ident/uuid"
+	"gochen/logging"
+	"gochen/messaging"
+)
+
+// Dispatcher 的默认配置。
+const (
+	DefaultPollInterval  = 5 * time.Second
+	DefaultBatchSize     = 50
+	DefaultMaxAttempts   = 8
+	DefaultRetryInterval = 30 * time.Second
+	DefaultMaxRetryDelay = time.Hour
+	DefaultClaimLease    = time.Minute
+	DefaultTimeout       = 10 * time.Second
+	DefaultUserAgent     = "gochen-webhook/1"
+)
+
+// maxResponseDrain 是为复用连接而读取并丢弃的响应体最大字节数。
+const maxResponseDrain = 4 << 10
+
+// DispatcherConfig 定义 Dispatcher 的入队与投递行为。
+type DispatcherConfig struct {
+	// Source 写入 CloudEvent 的 source 属性（如 "/orders-service"），必填。
+	Source string
+	// EventTypes 是订阅事件总线的类型；为空时订阅全部（"*"），再按各订阅的过滤条件展开。
+	EventTypes []string
+	// Name 是处理器名称；为空时为 "webhook-dispatcher"。
+	Name string
+	// PollInterval 是后台轮询间隔，默认 DefaultPollInterval。
+	PollInterval time.Duration
+	// BatchSize 是单轮 claim 的最大记录数，默认 DefaultBatchSize。
+	BatchSize int
+	// MaxAttempts 是最大尝试次数（含首次），默认 DefaultMaxAttempts；耗尽后记录标记为 failed。
+	MaxAttempts int
+	// RetryInterval 是首次重试的等待时间，之后按 2 的幂退避，默认 DefaultRetryInterval。
+	RetryInterval time.Duration
+	// MaxRetryDelay 是退避等待的上限，默认 DefaultMaxRetryDelay。
+	MaxRetryDelay time.Duration
+	// ClaimLease 是 claim 租约时长，超时未回写的记录可被其它实例重新 claim，默认 DefaultClaimLease。
+	//
+	// 一轮内的记录逐条串行投递，租约至少为 (BatchSize+1) × RequestTimeout，小于该值时自动放大，
+	// 避免批次尾部的记录在发送前租约已过期、被其它实例重复 claim。
+	ClaimLease time.Duration
+	// RequestTimeout 是单次投递请求的超时；为 0 时取 Client.Timeout，仍为 0 时使用 DefaultTimeout。
+	RequestTimeout time.Duration
+	// Client 是发送请求的 HTTP 客户端；为 nil 时使用 RequestTimeout 控制超时的默认客户端。
+	//
+	// Dispatcher 使用其副本：总是禁止跟随重定向，且未设置 AllowPrivateTargets 时替换 Transport 的拨号函数，
+	// 拒绝连接本机、私有与链路本地地址（此时 Transport 必须为 nil 或 *http.Transport）。
+	Client *http.Client
+	// AllowPrivateTargets 为 true 时允许投递到本机与内网地址，仅用于测试或受信任的内网部署。
+	AllowPrivateTargets bool
+	// UserAgent 是请求的 User-Agent，默认 DefaultUserAgent。
+	UserAgent string
+	// Clock 用于计算投递与重试时间，默认真实时钟。
+	Clock clock.IClock
+	// Logger 为 nil 时使用 "integrations.webhook" 组件日志。
+	Logger logging.ILogger
+}
+
+// Dispatcher 把事件展开为投递记录并在后台投递，实现 bus.IEventHandler：
+//
+//	dispatcher, _ := webhook.NewDispatcher(store, &webhook.DispatcherConfig{Source: "/orders"})
+//	unsubscribe, _ := eventBus.SubscribeHandler(ctx, dispatcher)
+//	_ = dispatcher.Start(ctx)
+//
+// HandleEvent 只写投递表，不做网络调用，因此不会拖慢事件总线；外部端点的故障只影响对应订阅的投递记录。
+type Dispatcher struct {
+	store  IStore
+	config DispatcherConfig
+	clock  clock.IClock
+	client *http.Client
+	log    logging.ILogger
+
+	// processMu 串行化单轮投递，避免 loop 与 DeliverPending 并发 claim。
+	processMu sync.Mutex
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+
+	mu        sync.Mutex
+	started   bool
+	stopped   bool
+	runCancel context.CancelFunc
+	stopOnce  sync.Once
+}
+
+// NewDispatcher 创建 webhook 投递器。
+func NewDispatcher(store IStore, cfg *DispatcherConfig) (*Dispatcher, error) {
+	if store == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "webhook store cannot be nil")
+	}
+	if cfg == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "dispatcher config cannot be nil")
+	}
+	config := *cfg
+	if strings.TrimSpace(config.Source) == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "dispatcher source cannot be empty")
+	}
+	if len(config.EventTypes) == 0 {
+		config.EventTypes = []string{"*"}
+	}
+	if config.Name == "" {
+		config.Name = "webhook-dispatcher"
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = DefaultRetryInterval
+	}
+	if config.MaxRetryDelay <= 0 {
+		config.MaxRetryDelay = DefaultMaxRetryDelay
+	}
+	if config.RequestTimeout <= 0 && config.Client != nil {
+		config.RequestTimeout = config.Client.Timeout
+	}
+	if config.RequestTimeout <= 0 {
+		config.RequestTimeout = DefaultTimeout
+	}
+	if config.ClaimLease <= 0 {
+		config.ClaimLease = DefaultClaimLease
+	}
+	// 额外预留一个请求超时，覆盖订阅查询与结果回写。
+	config.ClaimLease = max(config.ClaimLease, time.Duration(config.BatchSize+1)*config.RequestTimeout)
+	if config.UserAgent == "" {
+		config.UserAgent = DefaultUserAgent
+	}
+	clk := config.Clock
+	if clk == nil {
+		clk = clock.NewRealClock()
+	}
+	client, err := outboundClient(config.Client, config.AllowPrivateTargets)
+	if err != nil {
+		return nil, err
+	}
+	logger := config.Logger
+	if logger == nil {
+		logger = logging.ComponentLogger("integrations.webhook")
+	}
+	return &Dispatcher{
+		store:  store,
+		config: config,
+		clock:  clk,
+		client: client,
+		log:    logger,
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}, nil
+}
+
+// Enqueue 为每个匹配该事件类型与租户的活跃订阅写入一条投递记录。
+func (d *Dispatcher) Enqueue(ctx context.Context, evt eventing.IEvent) error {
+	if evt == nil {
+		return errors.NewCode(errors.InvalidInput, "event cannot be nil")
+	}
+	subs, err := d.store.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	tenantID := ""
+	if md := evt.GetMetadata(); md != nil {
+		tenantID, _ = md.Get(contextx.MetadataTenantKey)
+	}
+	var matched []*Subscription
+	for _, sub := range subs {
+		if sub.Active && sub.AcceptsTenant(tenantID) && sub.Matches(evt.GetType()) {
+			matched = append(matched, sub)
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	ce, err := cloudevents.FromEvent(evt, d.config.Source)
+	if err != nil {
+		return err
+	}
+	body, err := ce.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	now := d.clock.Now()
+	deliveries := make([]*Delivery, 0, len(matched))
+	for _, sub := range matched {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return errors.Wrap(err, errors.Internal, "failed to generate webhook delivery id")
+		}
+		deliveries = append(deliveries, &Delivery{
+			ID:             id,
+			SubscriptionID: sub.ID,
+			EventID:        evt.GetID(),
+			EventType:      evt.GetType(),
+			ContentType:    cloudevents.ContentTypeStructured,
+			Body:           string(body),
+			Status:         DeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+	}
+	return d.store.EnqueueDeliveries(ctx, deliveries)
+}
+
+// Start 启动后台投递循环；同一个 Dispatcher 只允许启动并停止一次。
+func (d *Dispatcher) Start(ctx context.Context) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	d.mu.Lock()
+	if d.stopped {
+		d.mu.Unlock()
+		return errors.NewCode(errors.InvalidInput, "webhook dispatcher has been stopped; create a new instance")
+	}
+	if d.started {
+		d.mu.Unlock()
+		return nil
+	}
+	ticker, err := d.clock.NewTicker(d.config.PollInterval)
+	if err != nil {
+		d.mu.Unlock()
+		return errors.Wrap(err, errors.InvalidInput, "failed to create webhook poll ticker")
+	}
+	d.started = true
+	runCtx, cancel := context.WithCancel(ctx)
+	d.runCancel = cancel
+	d.mu.Unlock()
+
+	go d.loop(runCtx, ticker)
+	return nil
+}
+
+// Stop 请求后台循环退出，并等待当前一轮投递收尾。
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	d.mu.Lock()
+	if !d.started || d.stopped {
+		// Stop-before-Start 与重复 Stop 均为 no-op。
+		d.mu.Unlock()
+		return nil
+	}
+	d.started = false
+	d.stopped = true
+	cancel := d.runCancel
+	d.runCancel = nil
+	d.mu.Unlock()
+
+	cancel()
+	d.stopOnce.Do(func() { close(d.stopCh) })
+	select {
+	case <-d.doneCh:
+		return nil
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.NewCode(errors.Timeout, "webhook dispatcher stop timeout").WithContext("cause", ctx.Err().Error())
+		}
+		return ctx.Err()
+	}
+}
+
+func (d *Dispatcher) loop(ctx context.Context, ticker clock.ITicker) {
+	defer func() {
+		ticker.Stop()
+		d.mu.Lock()
+		d.started = false
+		d.stopped = true
+		d.mu.Unlock()
+		close(d.doneCh)
+	}()
+	for {
+		select {
+		case <-d.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			if _, err := d.DeliverPending(ctx); err != nil {
+				d.log.Error(ctx, "webhook delivery round failed", logging.Error(err))
+			}
+		}
+	}
+}
+
+// DeliverPending 立即 claim 一批到期记录并逐条投递，返回本轮处理的记录数。
+//
+// 单条记录的投递失败只写回记录本身（等待重试或标记 failed），不作为返回错误；
+// 租约已过期或回写时 claim token 不匹配（记录已被其它实例重新 claim）的记录直接跳过；
+// 返回错误仅表示存储读写失败。
+func (d *Dispatcher) DeliverPending(ctx context.Context) (int, error) {
+	if ctx == nil {
+		return 0, errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	d.processMu.Lock()
+	defer d.processMu.Unlock()
+
+	deliveries, err := d.store.ClaimDeliveries(ctx, d.clock.Now(), d.config.BatchSize, d.config.ClaimLease)
+	if err != nil {
+		return 0, err
+	}
+	for i, delivery := range deliveries {
+		if delivery.LeaseUntil != nil && !d.clock.Now().Before(*delivery.LeaseUntil) {
+			// 租约已过期：记录可能已被其它实例重新 claim，发送会造成重复投递。
+			d.log.Warn(ctx, "webhook delivery lease expired before send; skipping",
+				logging.String("delivery_id", delivery.ID))
+			continue
+		}
+		if err := d.deliver(ctx, delivery); err != nil {
+			if errors.Is(err, errors.Conflict) {
+				d.log.Warn(ctx, "webhook delivery claim lost; skipping", logging.String("delivery_id", delivery.ID), logging.Error(err))
+				continue
+			}
+			return i, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery *Delivery) error {
+	sub, err := d.store.GetSubscription(ctx, delivery.SubscriptionID)
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return err
+	}
+	if sub == nil || !sub.Active {
+		// 订阅已删除或停用：不再重试，保留记录供排查与手动重投。
+		return d.store.MarkFailed(ctx, delivery.ID, delivery.ClaimToken, "subscription is missing or inactive", 0, nil)
+	}
+
+	statusCode, sendErr := d.send(ctx, sub, delivery)
+	if sendErr == nil {
+		return d.store.MarkDelivered(ctx, delivery.ID, delivery.ClaimToken, statusCode, d.clock.Now())
+	}
+	attempts := delivery.Attempts + 1
+	var next *time.Time
+	if attempts < d.config.MaxAttempts {
+		at := d.clock.Now().Add(d.backoff(attempts))
+		next = &at
+	}
+	return d.store.MarkFailed(ctx, delivery.ID, delivery.ClaimToken, sendErr.Error(), statusCode, next)
+}
+
+// backoff 返回第 attempts 次失败后的等待时间：RetryInterval * 2^(attempts-1)，不超过 MaxRetryDelay。
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.config.RetryInterval
+	for i := 1; i < attempts && delay < d.config.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, d.config.MaxRetryDelay)
+}
+
+func (d *Dispatcher) send(ctx context.Context, sub *Subscription, delivery *Delivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.config.RequestTimeout)
+	defer cancel()
+	body := []byte(delivery.Body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", delivery.ContentType)
+	req.Header.Set("User-Agent", d.config.UserAgent)
+	SetSignatureHeaders(req.Header, sub.Secret, delivery.ID, d.clock.Now(), body)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// 响应体来自外部端点，不写入 LastError（会经管理端点返回），只读取丢弃以复用连接。
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseDrain))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, errors.New(fmt.Sprintf("endpoint responded %d", resp.StatusCode))
+	}
+	return resp.StatusCode, nil
+}
+
+// HandleEvent 实现 bus.IEventHandler。
+func (d *Dispatcher) HandleEvent(ctx context.Context, evt eventing.IEvent) error {
+	return d.Enqueue(ctx, evt)
+}
+
+// Handle 实现 messaging.IMessageHandler。
+func (d *Dispatcher) Handle(ctx context.Context, message messaging.IMessage) error {
+	evt, ok := message.(eventing.IEvent)
+	if !ok {
+		return errors.NewCode(errors.InvalidInput, "message is not an event").
+			WithContext("message_type", fmt.Sprintf("%T", message))
+	}
+	return d.Enqueue(ctx, evt)
+}
+
+// EventTypes 返回订阅的事件类型。
+func (d *Dispatcher) EventTypes() []string { return d.config.EventTypes }
+
+// HandlerName 返回处理器名称。
+func (d *Dispatcher) HandlerName() string { return d.config.Name }
+
+// Type 返回处理器名称。
+func (d *Dispatcher) Type() string { return d.config.Name }
+
+// 编译期断言：确保 Dispatcher 实现 bus.IEventHandler。
+var _ bus.IEventHandler = (*Dispatcher)(nil)
@@ -0,0 +1,302 @@
+package webhook
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"gochen/errors"
+)
+
+// MemoryStore 是进程内的订阅与投递记录存储，适用于测试与单实例开发环境。
+type MemoryStore struct {
+	mu            sync.Mutex
+	subscriptions map[string]*Subscription
+	deliveries    map[string]*Delivery
+	// seq 记录投递写入顺序，CreatedAt 相同时保持稳定排序。
+	seq  map[string]int64
+	next int64
+}
+
+// NewMemoryStore 创建内存存储。
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		subscriptions: make(map[string]*Subscription),
+		deliveries:    make(map[string]*Delivery),
+		seq:           make(map[string]int64),
+	}
+}
+
+// CreateSubscription 创建订阅。
+func (s *MemoryStore) CreateSubscription(_ context.Context, sub *Subscription) error {
+	if err := sub.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.subscriptions[sub.ID]; exists {
+		return errors.NewCode(errors.Conflict, "webhook subscription already exists").
+			WithContext("subscription_id", sub.ID)
+	}
+	s.subscriptions[sub.ID] = cloneSubscription(sub)
+	return nil
+}
+
+// UpdateSubscription 更新订阅。
+func (s *MemoryStore) UpdateSubscription(_ context.Context, sub *Subscription) error {
+	if err := sub.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, exists := s.subscriptions[sub.ID]
+	if !exists {
+		return subscriptionNotFound(sub.ID)
+	}
+	updated := cloneSubscription(sub)
+	updated.TenantID = current.TenantID
+	s.subscriptions[sub.ID] = updated
+	return nil
+}
+
+// DeleteSubscription 删除订阅及其投递记录。
+func (s *MemoryStore) DeleteSubscription(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.subscriptions[id]; !exists {
+		return subscriptionNotFound(id)
+	}
+	delete(s.subscriptions, id)
+	for key, d := range s.deliveries {
+		if d.SubscriptionID == id {
+			delete(s.deliveries, key)
+			delete(s.seq, key)
+		}
+	}
+	return nil
+}
+
+// GetSubscription 返回订阅。
+func (s *MemoryStore) GetSubscription(_ context.Context, id string) (*Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		return nil, subscriptionNotFound(id)
+	}
+	return cloneSubscription(sub), nil
+}
+
+// ListSubscriptions 按创建时间升序列出订阅。
+func (s *MemoryStore) ListSubscriptions(context.Context) ([]*Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]*Subscription, 0, len(s.subscriptions))
+	for _, sub := range s.subscriptions {
+		out = append(out, cloneSubscription(sub))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+// EnqueueDeliveries 写入投递记录，忽略已存在的订阅与事件组合。
+func (s *MemoryStore) EnqueueDeliveries(_ context.Context, deliveries []*Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range deliveries {
+		if d == nil {
+			continue
+		}
+		if s.hasDeliveryLocked(d.SubscriptionID, d.EventID) {
+			continue
+		}
+		cp := *d
+		s.deliveries[d.ID] = &cp
+		s.next++
+		s.seq[d.ID] = s.next
+	}
+	return nil
+}
+
+func (s *MemoryStore) hasDeliveryLocked(subscriptionID, eventID string) bool {
+	for _, d := range s.deliveries {
+		if d.SubscriptionID == subscriptionID && d.EventID == eventID {
+			return true
+		}
+	}
+	return false
+}
+
+// ClaimDeliveries claim 到期记录。
+func (s *MemoryStore) ClaimDeliveries(_ context.Context, now time.Time, limit int, lease time.Duration) ([]*Delivery, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	candidates := make([]*Delivery, 0)
+	for _, d := range s.deliveries {
+		if claimable(d, now) {
+			candidates = append(candidates, d)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if !candidates[i].NextAttemptAt.Equal(candidates[j].NextAttemptAt) {
+			return candidates[i].NextAttemptAt.Before(candidates[j].NextAttemptAt)
+		}
+		return s.seq[candidates[i].ID] < s.seq[candidates[j].ID]
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	token, err := newClaimToken()
+	if err != nil {
+		return nil, err
+	}
+	leaseUntil := now.Add(lease)
+	out := make([]*Delivery, 0, len(candidates))
+	for _, d := range candidates {
+		d.Status = DeliveryProcessing
+		d.ClaimToken = token
+		d.LeaseUntil = &leaseUntil
+		cp := *d
+		out = append(out, &cp)
+	}
+	return out, nil
+}
+
+func claimable(d *Delivery, now time.Time) bool {
+	switch d.Status {
+	case DeliveryPending:
+		return !d.NextAttemptAt.After(now)
+	case DeliveryProcessing:
+		return d.LeaseUntil != nil && d.LeaseUntil.Before(now)
+	default:
+		return false
+	}
+}
+
+// MarkDelivered 标记为已投递。
+func (s *MemoryStore) MarkDelivered(_ context.Context, id, claimToken string, statusCode int, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, err := s.claimedLocked(id, claimToken)
+	if err != nil {
+		return err
+	}
+	d.Status = DeliveryDelivered
+	d.Attempts++
+	d.LastError = ""
+	d.LastStatusCode = statusCode
+	d.DeliveredAt = &at
+	d.ClaimToken = ""
+	d.LeaseUntil = nil
+	return nil
+}
+
+// MarkFailed 记录失败。
+func (s *MemoryStore) MarkFailed(_ context.Context, id, claimToken, lastError string, statusCode int, nextAttemptAt *time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, err := s.claimedLocked(id, claimToken)
+	if err != nil {
+		return err
+	}
+	d.Attempts++
+	d.LastError = lastError
+	d.LastStatusCode = statusCode
+	d.ClaimToken = ""
+	d.LeaseUntil = nil
+	if nextAttemptAt != nil {
+		d.Status = DeliveryPending
+		d.NextAttemptAt = *nextAttemptAt
+	} else {
+		d.Status = DeliveryFailed
+	}
+	return nil
+}
+
+func (s *MemoryStore) claimedLocked(id, claimToken string) (*Delivery, error) {
+	d, ok := s.deliveries[id]
+	if !ok {
+		return nil, deliveryNotFound(id)
+	}
+	if d.Status != DeliveryProcessing || d.ClaimToken != claimToken {
+		return nil, claimMismatch(id)
+	}
+	return d, nil
+}
+
+// RetryDelivery 把记录重置为 pending。
+func (s *MemoryStore) RetryDelivery(_ context.Context, id string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.deliveries[id]
+	if !ok {
+		return deliveryNotFound(id)
+	}
+	if d.Status == DeliveryProcessing {
+		return errors.NewCode(errors.Conflict, "webhook delivery is in progress").
+			WithContext("delivery_id", id)
+	}
+	d.Status = DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = at
+	d.DeliveredAt = nil
+	return nil
+}
+
+// GetDelivery 返回投递记录。
+func (s *MemoryStore) GetDelivery(_ context.Context, id string) (*Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.deliveries[id]
+	if !ok {
+		return nil, deliveryNotFound(id)
+	}
+	cp := *d
+	return &cp, nil
+}
+
+// ListDeliveries 按创建时间倒序列出投递记录。
+func (s *MemoryStore) ListDeliveries(_ context.Context, filter DeliveryFilter) ([]*Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]*Delivery, 0)
+	for _, d := range s.deliveries {
+		if filter.SubscriptionID != "" && d.SubscriptionID != filter.SubscriptionID {
+			continue
+		}
+		if filter.Status != "" && d.Status != filter.Status {
+			continue
+		}
+		cp := *d
+		out = append(out, &cp)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.After(out[j].CreatedAt)
+		}
+		return s.seq[out[i].ID] > s.seq[out[j].ID]
+	})
+	if filter.Limit > 0 && len(out) > filter.Limit {
+		out = out[:filter.Limit]
+	}
+	return out, nil
+}
+
+func cloneSubscription(sub *Subscription) *Subscription {
+	cp := *sub
+	cp.EventTypes = append([]string(nil), sub.EventTypes...)
+	return &cp
+}
+
+var _ IStore = (*MemoryStore)(nil)
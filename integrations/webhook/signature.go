@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gochen/errors"
+)
+
+// 签名相关请求头（Standard Webhooks 约定）。
+const (
+	HeaderID        = "Webhook-Id"
+	HeaderTimestamp = "Webhook-Timestamp"
+	HeaderSignature = "Webhook-Signature"
+)
+
+// SecretPrefix 是 NewSecret 生成的密钥前缀。
+const SecretPrefix = "whsec_"
+
+// DefaultTolerance 是 Verify 允许的时间戳偏差。
+const DefaultTolerance = 5 * time.Minute
+
+// NewSecret 生成随机签名密钥（"whsec_" + 32 字节 base64）。
+func NewSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Wrap(err, errors.Internal, "failed to generate webhook secret")
+	}
+	return SecretPrefix + base64.StdEncoding.EncodeToString(buf), nil
+}
+
+// Sign 返回 webhook-signature 头的值："v1," + base64(HMAC-SHA256(secret, "<id>.<timestamp>.<body>"))，
+// 以密钥字符串本身作为 HMAC key，timestamp 取 Unix 秒。
+func Sign(secret, id string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(id + "." + strconv.FormatInt(timestamp.Unix(), 10) + "."))
+	mac.Write(body)
+	return "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SetSignatureHeaders 为请求写入 webhook-id/webhook-timestamp/webhook-signature 头。
+func SetSignatureHeaders(header http.Header, secret, id string, timestamp time.Time, body []byte) {
+	header.Set(HeaderID, id)
+	header.Set(HeaderTimestamp, strconv.FormatInt(timestamp.Unix(), 10))
+	header.Set(HeaderSignature, Sign(secret, id, timestamp, body))
+}
+
+// Verify 供接收方校验请求签名：时间戳与 now 的偏差不得超过 tolerance（<=0 时使用 DefaultTolerance），
+// webhook-signature 中任一以空格分隔的 v1 签名匹配即通过（便于密钥轮换期间并存）。
+func Verify(secret string, header http.Header, body []byte, now time.Time, tolerance time.Duration) error {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	id := header.Get(HeaderID)
+	unix, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+	if id == "" || err != nil {
+		return errors.NewCode(errors.Unauthorized, "missing or invalid webhook signature headers")
+	}
+	timestamp := time.Unix(unix, 0)
+	if delta := now.Sub(timestamp); delta > tolerance || delta < -tolerance {
+		return errors.NewCode(errors.Unauthorized, "webhook timestamp outside tolerance").
+			WithContext("webhook_id", id)
+	}
+	expected := Sign(secret, id, timestamp, body)
+	for _, candidate := range strings.Fields(header.Get(HeaderSignature)) {
+		if hmac.Equal([]byte(candidate), []byte(expected)) {
+			return nil
+		}
+	}
+	return errors.NewCode(errors.Unauthorized, "webhook signature mismatch").
+		WithContext("webhook_id", id)
+}
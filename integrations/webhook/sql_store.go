@@ -0,0 +1,530 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"gochen/db"
+	"gochen/db/dialect"
+	"gochen/db/sql/safeident"
+	"gochen/db/sql/sqlbuilder"
+	"gochen/errors"
+)
+
+const (
+	// DefaultSubscriptionTable 是订阅表的默认表名。
+	DefaultSubscriptionTable = "webhook_subscriptions"
+	// DefaultDeliveryTable 是投递记录表的默认表名。
+	DefaultDeliveryTable = "webhook_deliveries"
+)
+
+// SQLStoreConfig 定义 SQLStore 的表名。
+type SQLStoreConfig struct {
+	// SubscriptionTable 为空时使用 DefaultSubscriptionTable。
+	SubscriptionTable string
+	// DeliveryTable 为空时使用 DefaultDeliveryTable。
+	DeliveryTable string
+}
+
+// SQLStore 使用现有数据库抽象持久化订阅与投递记录。
+//
+// 投递记录以 (subscription_id, event_id) 唯一约束保证入队幂等；claim 在事务内以 FOR UPDATE SKIP LOCKED
+// 选取（方言支持时），多个 Dispatcher 实例可并发轮询同一张表。
+type SQLStore struct {
+	db                db.IDatabase
+	dialect           dialect.Dialect
+	subscriptionTable string
+	deliveryTable     string
+}
+
+var (
+	subscriptionColumns = []string{"id", "tenant_id", "url", "secret", "event_types", "description", "active", "created_at", "updated_at"}
+	deliveryColumns     = []string{
+		"id", "subscription_id", "event_id", "event_type", "content_type", "body", "status", "attempts",
+		"last_error", "last_status_code", "next_attempt_at", "created_at", "delivered_at", "claim_token", "lease_until",
+	}
+)
+
+// NewSQLStore 创建 SQL 存储；表名不是安全标识符时返回 InvalidInput。
+func NewSQLStore(database db.IDatabase, cfg *SQLStoreConfig) (*SQLStore, error) {
+	if database == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "db cannot be nil")
+	}
+	config := SQLStoreConfig{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.SubscriptionTable == "" {
+		config.SubscriptionTable = DefaultSubscriptionTable
+	}
+	if config.DeliveryTable == "" {
+		config.DeliveryTable = DefaultDeliveryTable
+	}
+	for _, table := range []string{config.SubscriptionTable, config.DeliveryTable} {
+		if !safeident.IsSafeIdentifier(table) {
+			return nil, errors.NewCode(errors.InvalidInput, "invalid webhook table name").
+				WithContext("table_name", table)
+		}
+	}
+	return &SQLStore{
+		db:                database,
+		dialect:           dialect.FromDatabase(database),
+		subscriptionTable: config.SubscriptionTable,
+		deliveryTable:     config.DeliveryTable,
+	}, nil
+}
+
+func (s *SQLStore) builder(database db.IDatabase) (sqlbuilder.ISql, error) {
+	sq, err := sqlbuilder.New(database)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+	return sq, nil
+}
+
+func storeError(err error, op string) *errors.AppError {
+	return errors.NewCodeWithCause(errors.Database, "webhook store failed", err).
+		WithContext("op", op)
+}
+
+// CreateSubscription 创建订阅。
+func (s *SQLStore) CreateSubscription(ctx context.Context, sub *Subscription) error {
+	if err := sub.Validate(); err != nil {
+		return err
+	}
+	sq, err := s.builder(s.db)
+	if err != nil {
+		return err
+	}
+	_, err = sq.InsertInto(s.subscriptionTable).
+		Columns(subscriptionColumns...).
+		Values(sub.ID, sub.TenantID, sub.URL, sub.Secret, strings.Join(sub.EventTypes, ","), sub.Description, sub.Active, sub.CreatedAt, sub.UpdatedAt).
+		Exec(ctx)
+	if err != nil {
+		if s.dialect.IsUniqueViolation(err) {
+			return errors.NewCode(errors.Conflict, "webhook subscription already exists").
+				WithContext("subscription_id", sub.ID)
+		}
+		return storeError(err, "create_subscription").WithContext("subscription_id", sub.ID)
+	}
+	return nil
+}
+
+// UpdateSubscription 更新订阅（不修改 tenant_id 与 created_at）。
+func (s *SQLStore) UpdateSubscription(ctx context.Context, sub *Subscription) error {
+	if err := sub.Validate(); err != nil {
+		return err
+	}
+	sq, err := s.builder(s.db)
+	if err != nil {
+		return err
+	}
+	result, err := sq.Update(s.subscriptionTable).
+		Set("url", sub.URL).
+		Set("secret", sub.Secret).
+		Set("event_types", strings.Join(sub.EventTypes, ",")).
+		Set("description", sub.Description).
+		Set("active", sub.Active).
+		Set("updated_at", sub.UpdatedAt).
+		Where("id = ?", sub.ID).
+		Exec(ctx)
+	if err != nil {
+		return storeError(err, "update_subscription").WithContext("subscription_id", sub.ID)
+	}
+	return requireAffected(result, subscriptionNotFound(sub.ID))
+}
+
+// DeleteSubscription 在事务内删除订阅及其投递记录。
+func (s *SQLStore) DeleteSubscription(ctx context.Context, id string) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return storeError(err, "delete_subscription")
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	sq, err := s.builder(tx)
+	if err != nil {
+		return err
+	}
+	if _, err := sq.DeleteFrom(s.deliveryTable).Where("subscription_id = ?", id).Exec(ctx); err != nil {
+		return storeError(err, "delete_subscription").WithContext("subscription_id", id)
+	}
+	result, err := sq.DeleteFrom(s.subscriptionTable).Where("id = ?", id).Exec(ctx)
+	if err != nil {
+		return storeError(err, "delete_subscription").WithContext("subscription_id", id)
+	}
+	if err := requireAffected(result, subscriptionNotFound(id)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return storeError(err, "delete_subscription").WithContext("subscription_id", id)
+	}
+	return nil
+}
+
+// GetSubscription 返回订阅。
+func (s *SQLStore) GetSubscription(ctx context.Context, id string) (*Subscription, error) {
+	subs, err := s.querySubscriptions(ctx, "id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(subs) == 0 {
+		return nil, subscriptionNotFound(id)
+	}
+	return subs[0], nil
+}
+
+// ListSubscriptions 按创建时间升序列出订阅。
+func (s *SQLStore) ListSubscriptions(ctx context.Context) ([]*Subscription, error) {
+	return s.querySubscriptions(ctx, "")
+}
+
+func (s *SQLStore) querySubscriptions(ctx context.Context, where string, args ...any) ([]*Subscription, error) {
+	sq, err := s.builder(s.db)
+	if err != nil {
+		return nil, err
+	}
+	builder := sq.Select(subscriptionColumns...).From(s.subscriptionTable)
+	if where != "" {
+		builder = builder.Where(where, args...)
+	}
+	rows, err := builder.OrderBy(sqlbuilder.OrderAsc("created_at"), sqlbuilder.OrderAsc("id")).Query(ctx)
+	if err != nil {
+		return nil, storeError(err, "list_subscriptions")
+	}
+	defer rows.Close()
+
+	var subs []*Subscription
+	for rows.Next() {
+		var sub Subscription
+		var eventTypes string
+		if err := rows.Scan(&sub.ID, &sub.TenantID, &sub.URL, &sub.Secret, &eventTypes, &sub.Description, &sub.Active, &sub.CreatedAt, &sub.UpdatedAt); err != nil {
+			return nil, storeError(err, "list_subscriptions")
+		}
+		if eventTypes != "" {
+			sub.EventTypes = strings.Split(eventTypes, ",")
+		}
+		subs = append(subs, &sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, storeError(err, "list_subscriptions")
+	}
+	return subs, nil
+}
+
+// EnqueueDeliveries 逐条写入投递记录，唯一键冲突（同一订阅与事件已入队）时忽略。
+func (s *SQLStore) EnqueueDeliveries(ctx context.Context, deliveries []*Delivery) error {
+	sq, err := s.builder(s.db)
+	if err != nil {
+		return err
+	}
+	for _, d := range deliveries {
+		if d == nil {
+			continue
+		}
+		_, err := sq.InsertInto(s.deliveryTable).
+			Columns(deliveryColumns...).
+			Values(
+				d.ID, d.SubscriptionID, d.EventID, d.EventType, d.ContentType, d.Body, d.Status, d.Attempts,
+				d.LastError, d.LastStatusCode, d.NextAttemptAt, d.CreatedAt, d.DeliveredAt, d.ClaimToken, d.LeaseUntil,
+			).
+			Exec(ctx)
+		if err != nil && !s.dialect.IsUniqueViolation(err) {
+			return storeError(err, "enqueue_deliveries").
+				WithContext("subscription_id", d.SubscriptionID).
+				WithContext("event_id", d.EventID)
+		}
+	}
+	return nil
+}
+
+// ClaimDeliveries 在事务内选取到期记录并写入 claim token 与租约。
+func (s *SQLStore) ClaimDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*Delivery, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	token, err := newClaimToken()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, storeError(err, "claim_deliveries")
+	}
+	defer func() { _ = tx.Rollback() }()
+	sq, err := s.builder(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	const claimableWhere = "(status = ? AND next_attempt_at <= ?) OR (status = ? AND lease_until < ?)"
+	claimableArgs := []any{DeliveryPending, now, DeliveryProcessing, now}
+	rows, err := sq.Select(deliveryColumns...).From(s.deliveryTable).
+		Where(claimableWhere, claimableArgs...).
+		OrderBy(sqlbuilder.OrderAsc("next_attempt_at"), sqlbuilder.OrderAsc("created_at")).
+		Limit(limit).
+		ForUpdate().
+		SkipLocked().
+		Query(ctx)
+	if err != nil {
+		return nil, storeError(err, "claim_deliveries")
+	}
+	deliveries, err := scanDeliveries(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(deliveries))
+	for i, d := range deliveries {
+		ids[i] = d.ID
+	}
+	leaseUntil := now.Add(lease)
+	_, err = sq.Update(s.deliveryTable).
+		Set("status", DeliveryProcessing).
+		Set("claim_token", token).
+		Set("lease_until", leaseUntil).
+		Where("id IN ?", ids).
+		Where(claimableWhere, claimableArgs...).
+		Exec(ctx)
+	if err != nil {
+		return nil, storeError(err, "claim_deliveries")
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, storeError(err, "claim_deliveries")
+	}
+	for _, d := range deliveries {
+		d.Status = DeliveryProcessing
+		d.ClaimToken = token
+		d.LeaseUntil = &leaseUntil
+	}
+	return deliveries, nil
+}
+
+// MarkDelivered 标记为已投递。
+func (s *SQLStore) MarkDelivered(ctx context.Context, id, claimToken string, statusCode int, at time.Time) error {
+	sq, err := s.builder(s.db)
+	if err != nil {
+		return err
+	}
+	result, err := sq.Update(s.deliveryTable).
+		Set("status", DeliveryDelivered).
+		SetIncrement("attempts", 1).
+		Set("last_error", "").
+		Set("last_status_code", statusCode).
+		Set("delivered_at", at).
+		Set("claim_token", "").
+		Set("lease_until", nil).
+		Where("id = ? AND status = ? AND claim_token = ?", id, DeliveryProcessing, claimToken).
+		Exec(ctx)
+	if err != nil {
+		return storeError(err, "mark_delivered").WithContext("delivery_id", id)
+	}
+	return requireAffected(result, claimMismatch(id))
+}
+
+// MarkFailed 记录失败。
+func (s *SQLStore) MarkFailed(ctx context.Context, id, claimToken, lastError string, statusCode int, nextAttemptAt *time.Time) error {
+	sq, err := s.builder(s.db)
+	if err != nil {
+		return err
+	}
+	builder := sq.Update(s.deliveryTable).
+		SetIncrement("attempts", 1).
+		Set("last_error", lastError).
+		Set("last_status_code", statusCode).
+		Set("claim_token", "").
+		Set("lease_until", nil)
+	if nextAttemptAt != nil {
+		builder = builder.Set("status", DeliveryPending).Set("next_attempt_at", *nextAttemptAt)
+	} else {
+		builder = builder.Set("status", DeliveryFailed)
+	}
+	result, err := builder.
+		Where("id = ? AND status = ? AND claim_token = ?", id, DeliveryProcessing, claimToken).
+		Exec(ctx)
+	if err != nil {
+		return storeError(err, "mark_failed").WithContext("delivery_id", id)
+	}
+	return requireAffected(result, claimMismatch(id))
+}
+
+// RetryDelivery 把记录重置为 pending。
+func (s *SQLStore) RetryDelivery(ctx context.Context, id string, at time.Time) error {
+	current, err := s.GetDelivery(ctx, id)
+	if err != nil {
+		return err
+	}
+	if current.Status == DeliveryProcessing {
+		return errors.NewCode(errors.Conflict, "webhook delivery is in progress").
+			WithContext("delivery_id", id)
+	}
+	sq, err := s.builder(s.db)
+	if err != nil {
+		return err
+	}
+	result, err := sq.Update(s.deliveryTable).
+		Set("status", DeliveryPending).
+		Set("attempts", 0).
+		Set("next_attempt_at", at).
+		Set("delivered_at", nil).
+		Where("id = ? AND status <> ?", id, DeliveryProcessing).
+		Exec(ctx)
+	if err != nil {
+		return storeError(err, "retry_delivery").WithContext("delivery_id", id)
+	}
+	return requireAffected(result, errors.NewCode(errors.Conflict, "webhook delivery is in progress").
+		WithContext("delivery_id", id))
+}
+
+// GetDelivery 返回投递记录。
+func (s *SQLStore) GetDelivery(ctx context.Context, id string) (*Delivery, error) {
+	sq, err := s.builder(s.db)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := sq.Select(deliveryColumns...).From(s.deliveryTable).Where("id = ?", id).Query(ctx)
+	if err != nil {
+		return nil, storeError(err, "get_delivery").WithContext("delivery_id", id)
+	}
+	deliveries, err := scanDeliveries(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, deliveryNotFound(id)
+	}
+	return deliveries[0], nil
+}
+
+// ListDeliveries 按创建时间倒序列出投递记录。
+func (s *SQLStore) ListDeliveries(ctx context.Context, filter DeliveryFilter) ([]*Delivery, error) {
+	sq, err := s.builder(s.db)
+	if err != nil {
+		return nil, err
+	}
+	builder := sq.Select(deliveryColumns...).From(s.deliveryTable)
+	if filter.SubscriptionID != "" {
+		builder = builder.Where("subscription_id = ?", filter.SubscriptionID)
+	}
+	if filter.Status != "" {
+		builder = builder.Where("status = ?", filter.Status)
+	}
+	builder = builder.OrderBy(sqlbuilder.OrderDesc("created_at"), sqlbuilder.OrderDesc("id"))
+	if filter.Limit > 0 {
+		builder = builder.Limit(filter.Limit)
+	}
+	rows, err := builder.Query(ctx)
+	if err != nil {
+		return nil, storeError(err, "list_deliveries")
+	}
+	return scanDeliveries(rows)
+}
+
+func scanDeliveries(rows db.IRows) ([]*Delivery, error) {
+	defer rows.Close()
+	var deliveries []*Delivery
+	for rows.Next() {
+		var d Delivery
+		var deliveredAt, leaseUntil sql.NullTime
+		if err := rows.Scan(
+			&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.ContentType, &d.Body, &d.Status, &d.Attempts,
+			&d.LastError, &d.LastStatusCode, &d.NextAttemptAt, &d.CreatedAt, &deliveredAt, &d.ClaimToken, &leaseUntil,
+		); err != nil {
+			return nil, storeError(err, "scan_delivery")
+		}
+		if deliveredAt.Valid {
+			d.DeliveredAt = &deliveredAt.Time
+		}
+		if leaseUntil.Valid {
+			d.LeaseUntil = &leaseUntil.Time
+		}
+		deliveries = append(deliveries, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, storeError(err, "scan_delivery")
+	}
+	return deliveries, nil
+}
+
+func requireAffected(result sql.Result, notAffected error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return storeError(err, "rows_affected")
+	}
+	if affected == 0 {
+		return notAffected
+	}
+	return nil
+}
+
+// CreateTables 创建订阅表与投递记录表。
+func (s *SQLStore) CreateTables(ctx context.Context) error {
+	var text, key, bigText, timestamp, boolean, suffix string
+	switch s.dialect.Name() {
+	case dialect.NameSQLite:
+		text, key, bigText, timestamp, boolean = "TEXT", "TEXT", "TEXT", "DATETIME", "BOOLEAN"
+	case dialect.NamePostgres:
+		text, key, bigText, timestamp, boolean = "VARCHAR(255)", "VARCHAR(64)", "TEXT", "TIMESTAMPTZ", "BOOLEAN"
+	default:
+		text, key, bigText, timestamp, boolean = "VARCHAR(255)", "VARCHAR(64)", "MEDIUMTEXT", "DATETIME(6)", "TINYINT(1)"
+		suffix = " ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"
+	}
+
+	queries := []string{
+		fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				id %s NOT NULL PRIMARY KEY,
+				tenant_id VARCHAR(64) NOT NULL DEFAULT '',
+				url VARCHAR(2048) NOT NULL,
+				secret %s NOT NULL,
+				event_types %s NOT NULL,
+				description %s NOT NULL,
+				active %s NOT NULL,
+				created_at %s NOT NULL,
+				updated_at %s NOT NULL
+			)%s
+		`, s.subscriptionTable, key, text, bigText, bigText, boolean, timestamp, timestamp, suffix),
+		fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				id %s NOT NULL PRIMARY KEY,
+				subscription_id %s NOT NULL,
+				event_id %s NOT NULL,
+				event_type %s NOT NULL,
+				content_type %s NOT NULL,
+				body %s NOT NULL,
+				status VARCHAR(16) NOT NULL,
+				attempts INTEGER NOT NULL DEFAULT 0,
+				last_error %s NOT NULL,
+				last_status_code INTEGER NOT NULL DEFAULT 0,
+				next_attempt_at %s NOT NULL,
+				created_at %s NOT NULL,
+				delivered_at %s NULL,
+				claim_token VARCHAR(64) NOT NULL DEFAULT '',
+				lease_until %s NULL,
+				UNIQUE (subscription_id, event_id)
+			)%s
+		`, s.deliveryTable, key, key, text, text, text, bigText, bigText, timestamp, timestamp, timestamp, timestamp, suffix),
+	}
+	if suffix == "" {
+		// MySQL 不支持 CREATE INDEX IF NOT EXISTS，claim 索引由迁移工具维护。
+		queries = append(queries, fmt.Sprintf(
+			`CREATE INDEX IF NOT EXISTS idx_%s_claim ON %s (status, next_attempt_at)`, s.deliveryTable, s.deliveryTable))
+	}
+	for _, query := range queries {
+		if _, err := s.db.Exec(ctx, query); err != nil {
+			return errors.NewCodeWithCause(errors.Database, "failed to create webhook tables", err).
+				WithContext("subscription_table", s.subscriptionTable).
+				WithContext("delivery_table", s.deliveryTable)
+		}
+	}
+	return nil
+}
+
+var _ IStore = (*SQLStore)(nil)
@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"gochen/errors"
+)
+
+// dialTimeout 是默认客户端建立连接的超时。
+const dialTimeout = 10 * time.Second
+
+// sharedAddressSpace 是运营商级 NAT 网段（RFC 6598），同样不应作为外部回调地址。
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// ValidateTargetURL 校验订阅地址不指向本机、私有或链路本地网段。
+//
+// 这里只能识别 IP 字面量与 localhost；域名解析到内网的情况由 Dispatcher 在拨号时按实际连接地址拒绝。
+func ValidateTargetURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return errors.NewCode(errors.InvalidInput, "subscription url must be an absolute http(s) url").
+			WithContext("url", raw)
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return blockedTarget(raw)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && isBlockedAddr(addr) {
+		return blockedTarget(raw)
+	}
+	return nil
+}
+
+func blockedTarget(target string) error {
+	return errors.NewCode(errors.InvalidInput, "webhook target must not be a loopback, private or link-local address").
+		WithContext("target", target)
+}
+
+// isBlockedAddr 判断地址是否属于不允许外发的网段。
+func isBlockedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return !addr.IsValid() ||
+		addr.IsUnspecified() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		sharedAddressSpace.Contains(addr)
+}
+
+// guardDial 在 DNS 解析之后、建立连接之前检查实际连接地址，防止域名解析或重绑定到内网。
+func guardDial(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return blockedTarget(address)
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || isBlockedAddr(addr) {
+		return blockedTarget(address)
+	}
+	return nil
+}
+
+// noRedirect 禁止跟随重定向：3xx 按非 2xx 响应记为失败，避免借跳转访问内网地址。
+func noRedirect(*http.Request, []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// outboundClient 返回投递使用的 HTTP 客户端：总是禁止重定向；allowPrivate 为 false 时在拨号阶段拒绝内网地址。
+//
+// 自定义 Client 的 Transport 必须为 nil 或 *http.Transport，才能安装拨号检查；否则返回 InvalidInput。
+func outboundClient(client *http.Client, allowPrivate bool) (*http.Client, error) {
+	out := &http.Client{}
+	if client != nil {
+		cp := *client
+		out = &cp
+	}
+	out.CheckRedirect = noRedirect
+	if allowPrivate {
+		return out, nil
+	}
+
+	var transport *http.Transport
+	switch rt := out.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = rt.Clone()
+	default:
+		return nil, errors.NewCode(errors.InvalidInput, "webhook client transport must be *http.Transport unless AllowPrivateTargets is set")
+	}
+	// 经代理转发时拨号地址是代理本身，无法校验目标，因此禁用环境代理。
+	transport.Proxy = nil
+	dialer := &net.Dialer{Timeout: dialTimeout, Control: guardDial}
+	transport.DialContext = dialer.DialContext
+	transport.DialTLSContext = nil
+	out.Transport = transport
+	return out, nil
+}
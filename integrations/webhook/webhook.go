@@ -0,0 +1,193 @@
+// Package webhook 把领域事件可靠地推送给外部合作方的 HTTP 端点。
+//
+// 组成：
+//   - Subscription：合作方登记的接收地址、签名密钥与事件过滤条件，持久化在 SQL（SQLStore）；
+//   - Dispatcher：订阅事件总线，把事件按订阅展开为投递记录写入投递表（outbox），
+//     后台轮询 claim 到期记录并 POST，失败按指数退避重试，超过最大次数后标记为 failed；
+//   - 签名：请求携带 webhook-id/webhook-timestamp/webhook-signature 头（Standard Webhooks 约定，
+//     HMAC-SHA256），合作方可用 Verify 校验；
+//   - Registrar：订阅增删改查、投递记录查询与手动重投的管理端点。
+//
+// 请求体是 CloudEvents 结构化模式的 JSON（application/cloudevents+json），在入队时生成，重投时内容不变。
+// 投递语义为至少一次：合作方应按 webhook-id 或事件 id 去重。
+//
+// 租户隔离：订阅带 TenantID 时只接收元数据 tenant_id 相同的事件；TenantID 为空的订阅是平台级订阅，接收全部事件。
+// 管理端点按请求 context 的租户创建并过滤订阅，租户只能看到并管理自己的订阅与投递记录。
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"gochen/errors"
+)
+
+// DeliveryStatus 是投递记录的状态。
+type DeliveryStatus string
+
+const (
+	// DeliveryPending 等待投递（含等待重试）。
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliveryProcessing 已被 Dispatcher claim，正在投递。
+	DeliveryProcessing DeliveryStatus = "processing"
+	// DeliveryDelivered 目标返回 2xx。
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryFailed 重试耗尽或订阅不可用，需要人工重投。
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// Subscription 是合作方的 webhook 订阅。
+type Subscription struct {
+	ID string `json:"id"`
+	// TenantID 是订阅所属租户；为空表示平台级订阅（接收全部租户的事件），创建后不可修改。
+	TenantID string `json:"tenant_id,omitempty"`
+	URL      string `json:"url"`
+	// Secret 是 HMAC 签名密钥；管理端点仅在创建与轮换时返回。
+	Secret string `json:"secret,omitempty"`
+	// EventTypes 是事件类型过滤条件：精确匹配、"*" 匹配全部、以 "*" 结尾时按前缀匹配（如 "Order*"）；为空时匹配全部。
+	EventTypes  []string  `json:"event_types"`
+	Description string    `json:"description,omitempty"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Validate 校验订阅的 ID、地址与密钥。
+func (s *Subscription) Validate() error {
+	if s == nil {
+		return errors.NewCode(errors.InvalidInput, "subscription cannot be nil")
+	}
+	if strings.TrimSpace(s.ID) == "" {
+		return errors.NewCode(errors.InvalidInput, "subscription id cannot be empty")
+	}
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.NewCode(errors.InvalidInput, "subscription url must be an absolute http(s) url").
+			WithContext("subscription_id", s.ID).
+			WithContext("url", s.URL)
+	}
+	if s.Secret == "" {
+		return errors.NewCode(errors.InvalidInput, "subscription secret cannot be empty").
+			WithContext("subscription_id", s.ID)
+	}
+	for _, t := range s.EventTypes {
+		if strings.TrimSpace(t) == "" || strings.Contains(t, ",") {
+			return errors.NewCode(errors.InvalidInput, "invalid subscription event type filter").
+				WithContext("subscription_id", s.ID).
+				WithContext("event_type", t)
+		}
+	}
+	return nil
+}
+
+// AcceptsTenant 判断订阅是否接收该租户的事件：平台级订阅接收全部，租户订阅只接收本租户。
+func (s *Subscription) AcceptsTenant(tenantID string) bool {
+	return s.TenantID == "" || s.TenantID == tenantID
+}
+
+// Matches 判断订阅是否接收该事件类型。
+func (s *Subscription) Matches(eventType string) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(s.EventTypes, func(filter string) bool {
+		if prefix, ok := strings.CutSuffix(filter, "*"); ok {
+			return strings.HasPrefix(eventType, prefix)
+		}
+		return filter == eventType
+	})
+}
+
+// Delivery 是一次事件到一个订阅的投递记录。
+type Delivery struct {
+	ID             string         `json:"id"`
+	SubscriptionID string         `json:"subscription_id"`
+	EventID        string         `json:"event_id"`
+	EventType      string         `json:"event_type"`
+	ContentType    string         `json:"content_type"`
+	Body           string         `json:"body"`
+	Status         DeliveryStatus `json:"status"`
+	Attempts       int            `json:"attempts"`
+	LastError      string         `json:"last_error,omitempty"`
+	// LastStatusCode 是最近一次请求的 HTTP 状态码；请求未得到响应时为 0。
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	ClaimToken     string     `json:"-"`
+	LeaseUntil     *time.Time `json:"-"`
+}
+
+// DeliveryFilter 是投递记录查询条件。
+type DeliveryFilter struct {
+	SubscriptionID string
+	Status         DeliveryStatus
+	// Limit 为 0 时不限制条数。
+	Limit int
+}
+
+// ISubscriptionStore 持久化 webhook 订阅。
+type ISubscriptionStore interface {
+	// CreateSubscription 创建订阅；ID 已存在时返回 Conflict。
+	CreateSubscription(ctx context.Context, sub *Subscription) error
+	// UpdateSubscription 更新订阅；不存在时返回 NotFound。
+	UpdateSubscription(ctx context.Context, sub *Subscription) error
+	// DeleteSubscription 删除订阅及其投递记录；不存在时返回 NotFound。
+	DeleteSubscription(ctx context.Context, id string) error
+	// GetSubscription 返回订阅；不存在时返回 NotFound。
+	GetSubscription(ctx context.Context, id string) (*Subscription, error)
+	// ListSubscriptions 按创建时间升序列出订阅。
+	ListSubscriptions(ctx context.Context) ([]*Subscription, error)
+}
+
+// IDeliveryStore 是投递记录的 outbox 存储。
+type IDeliveryStore interface {
+	// EnqueueDeliveries 写入投递记录；同一订阅与事件的记录已存在时忽略，保证重复投递事件时幂等。
+	EnqueueDeliveries(ctx context.Context, deliveries []*Delivery) error
+	// ClaimDeliveries claim 最多 limit 条到期的 pending 记录或租约过期的 processing 记录，设置 claim token 与租约。
+	ClaimDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*Delivery, error)
+	// MarkDelivered 在 claim token 匹配时把记录标记为已投递；不匹配时返回 Conflict。
+	MarkDelivered(ctx context.Context, id, claimToken string, statusCode int, at time.Time) error
+	// MarkFailed 在 claim token 匹配时记录失败：nextAttemptAt 非 nil 时回到 pending 等待重试，否则标记为 failed。
+	MarkFailed(ctx context.Context, id, claimToken, lastError string, statusCode int, nextAttemptAt *time.Time) error
+	// RetryDelivery 把非 processing 的记录重置为 pending 并清零尝试次数；不存在时返回 NotFound。
+	RetryDelivery(ctx context.Context, id string, at time.Time) error
+	// GetDelivery 返回投递记录；不存在时返回 NotFound。
+	GetDelivery(ctx context.Context, id string) (*Delivery, error)
+	// ListDeliveries 按创建时间倒序列出投递记录。
+	ListDeliveries(ctx context.Context, filter DeliveryFilter) ([]*Delivery, error)
+}
+
+// IStore 组合订阅与投递记录存储。
+type IStore interface {
+	ISubscriptionStore
+	IDeliveryStore
+}
+
+func subscriptionNotFound(id string) error {
+	return errors.NewCode(errors.NotFound, "webhook subscription not found").
+		WithContext("subscription_id", id)
+}
+
+func deliveryNotFound(id string) error {
+	return errors.NewCode(errors.NotFound, "webhook delivery not found").
+		WithContext("delivery_id", id)
+}
+
+func claimMismatch(id string) error {
+	return errors.NewCode(errors.Conflict, "webhook delivery claim token mismatch").
+		WithContext("delivery_id", id)
+}
+
+func newClaimToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Wrap(err, errors.Internal, "failed to generate claim token")
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,547 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"gochen/clock"
+	"gochen/contextx"
+	"gochen/db"
+	basicdb "gochen/db/sql/stdsql"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/cloudevents"
+	"gochen/httpx/httpxtest"
+)
+
+var baseTime = time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+
+func newSQLiteStore(t *testing.T) *SQLStore {
+	t.Helper()
+	database, err := basicdb.New(db.DBConfig{Driver: "sqlite", Database: ":memory:", MaxOpenConns: 1, MaxIdleConns: 1})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = database.Close() })
+	store, err := NewSQLStore(database, nil)
+	require.NoError(t, err)
+	require.NoError(t, store.CreateTables(context.Background()))
+	return store
+}
+
+func newSubscription(id, url string, eventTypes ...string) *Subscription {
+	return &Subscription{
+		ID: id, URL: url, Secret: "whsec_test", EventTypes: eventTypes, Active: true,
+		CreatedAt: baseTime, UpdatedAt: baseTime,
+	}
+}
+
+func newDelivery(id, subscriptionID, eventID string, at time.Time) *Delivery {
+	return &Delivery{
+		ID: id, SubscriptionID: subscriptionID, EventID: eventID, EventType: "OrderPlaced",
+		ContentType: cloudevents.ContentTypeStructured, Body: `{}`, Status: DeliveryPending,
+		NextAttemptAt: at, CreatedAt: at,
+	}
+}
+
+func TestSubscription_ValidateAndMatches(t *testing.T) {
+	sub := newSubscription("s-1", "https://partner.example/hooks", "OrderPlaced", "Payment*")
+	require.NoError(t, sub.Validate())
+	assert.True(t, sub.Matches("OrderPlaced"))
+	assert.True(t, sub.Matches("PaymentCaptured"))
+	assert.False(t, sub.Matches("OrderShipped"))
+	assert.True(t, newSubscription("s-2", "https://partner.example").Matches("Anything"))
+
+	assert.True(t, errors.Is(newSubscription("s-3", "ftp://partner.example").Validate(), errors.InvalidInput))
+	assert.True(t, errors.Is(newSubscription("s-4", "https://partner.example", "a,b").Validate(), errors.InvalidInput))
+	noSecret := newSubscription("s-5", "https://partner.example")
+	noSecret.Secret = ""
+	assert.True(t, errors.Is(noSecret.Validate(), errors.InvalidInput))
+}
+
+func TestSignAndVerify(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	body := []byte(`{"id":"evt-1"}`)
+	header := http.Header{}
+	SetSignatureHeaders(header, secret, "msg-1", baseTime, body)
+
+	require.NoError(t, Verify(secret, header, body, baseTime.Add(time.Minute), 0))
+	assert.True(t, errors.Is(Verify(secret, header, []byte(`{}`), baseTime, 0), errors.Unauthorized))
+	assert.True(t, errors.Is(Verify("whsec_other", header, body, baseTime, 0), errors.Unauthorized))
+	assert.True(t, errors.Is(Verify(secret, header, body, baseTime.Add(10*time.Minute), 0), errors.Unauthorized))
+
+	// 轮换期间携带新旧两个签名，任一匹配即可。
+	header.Set(HeaderSignature, "v1,bogus "+header.Get(HeaderSignature))
+	require.NoError(t, Verify(secret, header, body, baseTime, 0))
+}
+
+func TestStores(t *testing.T) {
+	for name, newStore := range map[string]func(t *testing.T) IStore{
+		"memory": func(*testing.T) IStore { return NewMemoryStore() },
+		"sqlite": func(t *testing.T) IStore { return newSQLiteStore(t) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			testStoreContract(t, newStore(t))
+		})
+	}
+}
+
+func testStoreContract(t *testing.T, store IStore) {
+	ctx := context.Background()
+	sub := newSubscription("s-1", "https://partner.example/hooks", "Order*")
+	require.NoError(t, store.CreateSubscription(ctx, sub))
+	assert.True(t, errors.Is(store.CreateSubscription(ctx, sub), errors.Conflict))
+
+	sub.Description = "orders"
+	sub.Active = false
+	require.NoError(t, store.UpdateSubscription(ctx, sub))
+	loaded, err := store.GetSubscription(ctx, "s-1")
+	require.NoError(t, err)
+	assert.Equal(t, "orders", loaded.Description)
+	assert.False(t, loaded.Active)
+	assert.Equal(t, []string{"Order*"}, loaded.EventTypes)
+	assert.True(t, errors.Is(store.UpdateSubscription(ctx, newSubscription("missing", "https://x.example")), errors.NotFound))
+
+	tenantSub := newSubscription("s-2", "https://other.example")
+	tenantSub.TenantID = "acme"
+	require.NoError(t, store.CreateSubscription(ctx, tenantSub))
+	tenantSub.TenantID = "globex"
+	require.NoError(t, store.UpdateSubscription(ctx, tenantSub))
+	subs, err := store.ListSubscriptions(ctx)
+	require.NoError(t, err)
+	require.Len(t, subs, 2)
+	assert.Empty(t, subs[1].EventTypes)
+	assert.Equal(t, "acme", subs[1].TenantID, "tenant is fixed at creation")
+
+	require.NoError(t, store.EnqueueDeliveries(ctx, []*Delivery{
+		newDelivery("d-1", "s-1", "evt-1", baseTime),
+		newDelivery("d-2", "s-1", "evt-2", baseTime.Add(time.Minute)),
+		newDelivery("d-3", "s-2", "evt-1", baseTime.Add(time.Hour)),
+	}))
+	// 同一订阅与事件重复入队被忽略。
+	require.NoError(t, store.EnqueueDeliveries(ctx, []*Delivery{newDelivery("d-dup", "s-1", "evt-1", baseTime)}))
+	_, err = store.GetDelivery(ctx, "d-dup")
+	assert.True(t, errors.Is(err, errors.NotFound))
+
+	claimed, err := store.ClaimDeliveries(ctx, baseTime.Add(2*time.Minute), 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	assert.Equal(t, "d-1", claimed[0].ID)
+	assert.Equal(t, DeliveryProcessing, claimed[0].Status)
+	token := claimed[0].ClaimToken
+
+	again, err := store.ClaimDeliveries(ctx, baseTime.Add(2*time.Minute), 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+
+	assert.True(t, errors.Is(store.MarkDelivered(ctx, "d-1", "wrong", 200, baseTime), errors.Conflict))
+	require.NoError(t, store.MarkDelivered(ctx, "d-1", token, 200, baseTime.Add(2*time.Minute)))
+	retryAt := baseTime.Add(10 * time.Minute)
+	require.NoError(t, store.MarkFailed(ctx, "d-2", token, "boom", 503, &retryAt))
+
+	d2, err := store.GetDelivery(ctx, "d-2")
+	require.NoError(t, err)
+	assert.Equal(t, DeliveryPending, d2.Status)
+	assert.Equal(t, 1, d2.Attempts)
+	assert.Equal(t, "boom", d2.LastError)
+	assert.Equal(t, 503, d2.LastStatusCode)
+	assert.True(t, d2.NextAttemptAt.Equal(retryAt))
+
+	// 租约过期的 processing 记录可被重新 claim。
+	claimed, err = store.ClaimDeliveries(ctx, retryAt, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	reclaimed, err := store.ClaimDeliveries(ctx, retryAt.Add(2*time.Minute), 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, reclaimed, 1)
+	assert.True(t, errors.Is(store.MarkFailed(ctx, "d-2", claimed[0].ClaimToken, "late", 0, nil), errors.Conflict))
+	assert.True(t, errors.Is(store.RetryDelivery(ctx, "d-2", retryAt), errors.Conflict))
+	require.NoError(t, store.MarkFailed(ctx, "d-2", reclaimed[0].ClaimToken, "gone", 0, nil))
+
+	failed, err := store.ListDeliveries(ctx, DeliveryFilter{SubscriptionID: "s-1", Status: DeliveryFailed})
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, 2, failed[0].Attempts)
+
+	require.NoError(t, store.RetryDelivery(ctx, "d-2", retryAt))
+	d2, err = store.GetDelivery(ctx, "d-2")
+	require.NoError(t, err)
+	assert.Equal(t, DeliveryPending, d2.Status)
+	assert.Zero(t, d2.Attempts)
+
+	all, err := store.ListDeliveries(ctx, DeliveryFilter{SubscriptionID: "s-1"})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "d-2", all[0].ID)
+	require.NotNil(t, all[1].DeliveredAt)
+
+	require.NoError(t, store.DeleteSubscription(ctx, "s-1"))
+	assert.True(t, errors.Is(store.DeleteSubscription(ctx, "s-1"), errors.NotFound))
+	_, err = store.GetDelivery(ctx, "d-1")
+	assert.True(t, errors.Is(err, errors.NotFound))
+	_, err = store.GetDelivery(ctx, "d-3")
+	require.NoError(t, err)
+}
+
+// partner 是记录收到请求并按脚本返回状态码的测试端点。
+type partner struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (p *partner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = append(p.requests, r)
+	p.bodies = append(p.bodies, body)
+	status := http.StatusOK
+	if len(p.statuses) > 0 {
+		status, p.statuses = p.statuses[0], p.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func TestDispatcher_DeliversSignedEventsWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	endpoint := &partner{statuses: []int{http.StatusServiceUnavailable}}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	store := NewMemoryStore()
+	require.NoError(t, store.CreateSubscription(ctx, newSubscription("orders", server.URL, "Order*")))
+	require.NoError(t, store.CreateSubscription(ctx, newSubscription("payments", server.URL, "Payment*")))
+	clk := clock.NewManualClock(baseTime)
+	dispatcher, err := NewDispatcher(store, &DispatcherConfig{
+		Source: "/orders", Clock: clk, RetryInterval: time.Minute, MaxAttempts: 3, Client: server.Client(), AllowPrivateTargets: true,
+	})
+	require.NoError(t, err)
+
+	evt := eventing.NewEvent(int64(42), "Order", "OrderPlaced", 1, map[string]any{"order_id": "o-1"}, 1)
+	evt.ID = "evt-1"
+	require.NoError(t, dispatcher.HandleEvent(ctx, evt))
+	require.NoError(t, dispatcher.HandleEvent(ctx, evt))
+
+	deliveries, err := store.ListDeliveries(ctx, DeliveryFilter{})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, "orders", deliveries[0].SubscriptionID)
+
+	n, err := dispatcher.DeliverPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	failed, err := store.GetDelivery(ctx, deliveries[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, DeliveryPending, failed.Status)
+	assert.Equal(t, 503, failed.LastStatusCode)
+	assert.True(t, failed.NextAttemptAt.Equal(baseTime.Add(time.Minute)))
+
+	n, err = dispatcher.DeliverPending(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n, "retry must wait for backoff")
+
+	clk.Advance(time.Minute)
+	n, err = dispatcher.DeliverPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	delivered, err := store.GetDelivery(ctx, deliveries[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, DeliveryDelivered, delivered.Status)
+	assert.Equal(t, 2, delivered.Attempts)
+
+	require.Len(t, endpoint.requests, 2)
+	req := endpoint.requests[1]
+	assert.Equal(t, cloudevents.ContentTypeStructured, req.Header.Get("Content-Type"))
+	assert.Equal(t, delivered.ID, req.Header.Get(HeaderID))
+	require.NoError(t, Verify("whsec_test", req.Header, endpoint.bodies[1], clk.Now(), 0))
+	var ce cloudevents.CloudEvent
+	require.NoError(t, json.Unmarshal(endpoint.bodies[1], &ce))
+	assert.Equal(t, "evt-1", ce.ID)
+	assert.Equal(t, "/orders", ce.Source)
+	assert.Equal(t, "42", ce.Subject)
+}
+
+func TestDispatcher_GivesUpAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	endpoint := &partner{statuses: []int{500, 500}}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	store := NewMemoryStore()
+	require.NoError(t, store.CreateSubscription(ctx, newSubscription("s-1", server.URL)))
+	clk := clock.NewManualClock(baseTime)
+	dispatcher, err := NewDispatcher(store, &DispatcherConfig{
+		Source: "/orders", Clock: clk, RetryInterval: time.Second, MaxAttempts: 2, Client: server.Client(), AllowPrivateTargets: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, dispatcher.Enqueue(ctx, eventing.NewEvent(int64(1), "Order", "OrderPlaced", 1, nil, 1)))
+
+	_, err = dispatcher.DeliverPending(ctx)
+	require.NoError(t, err)
+	clk.Advance(time.Second)
+	_, err = dispatcher.DeliverPending(ctx)
+	require.NoError(t, err)
+
+	failed, err := store.ListDeliveries(ctx, DeliveryFilter{Status: DeliveryFailed})
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, 2, failed[0].Attempts)
+	assert.Contains(t, failed[0].LastError, "500")
+
+	assert.Equal(t, time.Second, dispatcher.backoff(1))
+	dispatcher.config.MaxRetryDelay = 3 * time.Second
+	assert.Equal(t, 3*time.Second, dispatcher.backoff(10))
+}
+
+func TestDispatcher_StartStop(t *testing.T) {
+	ctx := context.Background()
+	endpoint := &partner{}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	store := newSQLiteStore(t)
+	require.NoError(t, store.CreateSubscription(ctx, newSubscription("s-1", server.URL)))
+	clk := clock.NewManualClock(baseTime)
+	dispatcher, err := NewDispatcher(store, &DispatcherConfig{
+		Source: "/orders", Clock: clk, PollInterval: time.Second, Client: server.Client(), AllowPrivateTargets: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, dispatcher.Enqueue(ctx, eventing.NewEvent(int64(1), "Order", "OrderPlaced", 1, nil, 1)))
+
+	require.NoError(t, dispatcher.Start(ctx))
+	require.Eventually(t, func() bool {
+		clk.Advance(time.Second)
+		delivered, err := store.ListDeliveries(ctx, DeliveryFilter{Status: DeliveryDelivered})
+		return err == nil && len(delivered) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	stopCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	require.NoError(t, dispatcher.Stop(stopCtx))
+	assert.Error(t, dispatcher.Start(ctx))
+}
+
+func TestRegistrar_ManagesSubscriptionsAndRetries(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	group := httpxtest.NewMockRouteGroup()
+	clk := clock.NewManualClock(baseTime)
+	require.NoError(t, NewRegistrar(store, &Config{BasePath: "/admin/webhooks/", Clock: clk}).RegisterRoutes(group))
+	require.Len(t, group.Handlers, 7)
+
+	w := httpxtest.Serve(t, group.Handlers["POST /admin/webhooks/subscriptions"], httptest.NewRequest(http.MethodPost, "/",
+		bytes.NewBufferString(`{"id":"s-1","url":"https://partner.example/hooks","event_types":["Order*"]}`)), nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created Subscription
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.True(t, created.Active)
+	assert.Contains(t, created.Secret, SecretPrefix)
+
+	w = httpxtest.Serve(t, group.Handlers["POST /admin/webhooks/subscriptions"], httptest.NewRequest(http.MethodPost, "/",
+		bytes.NewBufferString(`{"url":"not a url"}`)), nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httpxtest.Serve(t, group.Handlers["GET /admin/webhooks/subscriptions/:id"], httptest.NewRequest(http.MethodGet, "/", nil),
+		map[string]string{"id": "s-1"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+
+	w = httpxtest.Serve(t, group.Handlers["PUT /admin/webhooks/subscriptions/:id"], httptest.NewRequest(http.MethodPut, "/",
+		bytes.NewBufferString(`{"url":"https://partner.example/v2","active":false}`)), map[string]string{"id": "s-1"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), "secret")
+	sub, err := store.GetSubscription(ctx, "s-1")
+	require.NoError(t, err)
+	assert.Equal(t, created.Secret, sub.Secret)
+	assert.False(t, sub.Active)
+	assert.Empty(t, sub.EventTypes)
+
+	w = httpxtest.Serve(t, group.Handlers["PUT /admin/webhooks/subscriptions/:id"], httptest.NewRequest(http.MethodPut, "/",
+		bytes.NewBufferString(`{"url":"https://partner.example/v2","rotate_secret":true}`)), map[string]string{"id": "s-1"})
+	require.Equal(t, http.StatusOK, w.Code)
+	var rotated Subscription
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rotated))
+	assert.NotEqual(t, created.Secret, rotated.Secret)
+	assert.NotEmpty(t, rotated.Secret)
+
+	failedAt := baseTime.Add(time.Minute)
+	require.NoError(t, store.EnqueueDeliveries(ctx, []*Delivery{newDelivery("d-1", "s-1", "evt-1", baseTime)}))
+	claimed, err := store.ClaimDeliveries(ctx, baseTime, 1, time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, store.MarkFailed(ctx, "d-1", claimed[0].ClaimToken, "boom", 500, nil))
+
+	w = httpxtest.Serve(t, group.Handlers["GET /admin/webhooks/subscriptions/:id/deliveries"],
+		httptest.NewRequest(http.MethodGet, "/?status=failed&limit=10", nil), map[string]string{"id": "s-1"})
+	require.Equal(t, http.StatusOK, w.Code)
+	var listed []Delivery
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	require.Len(t, listed, 1)
+	assert.Equal(t, "boom", listed[0].LastError)
+
+	w = httpxtest.Serve(t, group.Handlers["GET /admin/webhooks/subscriptions/:id/deliveries"],
+		httptest.NewRequest(http.MethodGet, "/?status=bogus", nil), map[string]string{"id": "s-1"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	clk.Advance(time.Minute)
+	w = httpxtest.Serve(t, group.Handlers["POST /admin/webhooks/deliveries/:id/retry"], httptest.NewRequest(http.MethodPost, "/", nil),
+		map[string]string{"id": "d-1"})
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	d1, err := store.GetDelivery(ctx, "d-1")
+	require.NoError(t, err)
+	assert.Equal(t, DeliveryPending, d1.Status)
+	assert.True(t, d1.NextAttemptAt.Equal(failedAt))
+
+	w = httpxtest.Serve(t, group.Handlers["DELETE /admin/webhooks/subscriptions/:id"], httptest.NewRequest(http.MethodDelete, "/", nil),
+		map[string]string{"id": "s-1"})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = httpxtest.Serve(t, group.Handlers["GET /admin/webhooks/subscriptions/:id"], httptest.NewRequest(http.MethodGet, "/", nil),
+		map[string]string{"id": "s-1"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httpxtest.Serve(t, group.Handlers["GET /admin/webhooks/subscriptions"], httptest.NewRequest(http.MethodGet, "/", nil), nil)
+	assert.JSONEq(t, `[]`, w.Body.String())
+}
+
+// lostClaimStore 模拟第一条记录在回写前已被其它实例重新 claim。
+type lostClaimStore struct {
+	*MemoryStore
+	lost string
+}
+
+func (s *lostClaimStore) MarkDelivered(ctx context.Context, id, claimToken string, statusCode int, at time.Time) error {
+	if id == s.lost {
+		return claimMismatch(id)
+	}
+	return s.MemoryStore.MarkDelivered(ctx, id, claimToken, statusCode, at)
+}
+
+func TestDispatcher_LeaseCoversBatchAndSkipsLostClaims(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(&partner{})
+	defer server.Close()
+
+	store := &lostClaimStore{MemoryStore: NewMemoryStore()}
+	require.NoError(t, store.CreateSubscription(ctx, newSubscription("s-1", server.URL)))
+	clk := clock.NewManualClock(baseTime)
+	dispatcher, err := NewDispatcher(store, &DispatcherConfig{
+		Source: "/orders", Clock: clk, BatchSize: 50, ClaimLease: time.Minute, RequestTimeout: 10 * time.Second, Client: server.Client(), AllowPrivateTargets: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 51*10*time.Second, dispatcher.config.ClaimLease, "lease must cover a serial batch")
+
+	require.NoError(t, store.EnqueueDeliveries(ctx, []*Delivery{
+		newDelivery("d-1", "s-1", "evt-1", baseTime),
+		newDelivery("d-2", "s-1", "evt-2", baseTime),
+	}))
+	store.lost = "d-1"
+	n, err := dispatcher.DeliverPending(ctx)
+	require.NoError(t, err, "a lost claim must not fail the round")
+	assert.Equal(t, 2, n)
+	delivered, err := store.GetDelivery(ctx, "d-2")
+	require.NoError(t, err)
+	assert.Equal(t, DeliveryDelivered, delivered.Status)
+}
+
+func TestDispatcher_RejectsPrivateTargetsAndRedirects(t *testing.T) {
+	ctx := context.Background()
+	for _, target := range []string{"http://127.0.0.1/hooks", "http://10.0.0.8/hooks", "http://[::1]/hooks", "http://169.254.169.254/latest", "http://localhost:8080"} {
+		assert.True(t, errors.Is(ValidateTargetURL(target), errors.InvalidInput), target)
+	}
+	require.NoError(t, ValidateTargetURL("https://partner.example/hooks"))
+
+	internal := httptest.NewServer(&partner{})
+	defer internal.Close()
+	store := NewMemoryStore()
+	require.NoError(t, store.CreateSubscription(ctx, newSubscription("s-1", internal.URL)))
+	clk := clock.NewManualClock(baseTime)
+	dispatcher, err := NewDispatcher(store, &DispatcherConfig{Source: "/orders", Clock: clk})
+	require.NoError(t, err)
+	require.NoError(t, dispatcher.Enqueue(ctx, eventing.NewEvent(int64(1), "Order", "OrderPlaced", 1, nil, 1)))
+	_, err = dispatcher.DeliverPending(ctx)
+	require.NoError(t, err)
+	pending, err := store.ListDeliveries(ctx, DeliveryFilter{})
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Contains(t, pending[0].LastError, "loopback", "dial guard must reject loopback targets")
+
+	redirecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "http://169.254.169.254/latest/meta-data")
+		w.WriteHeader(http.StatusFound)
+		_, _ = w.Write([]byte("internal detail"))
+	}))
+	defer redirecting.Close()
+	require.NoError(t, store.CreateSubscription(ctx, newSubscription("s-2", redirecting.URL)))
+	relaxed, err := NewDispatcher(store, &DispatcherConfig{Source: "/orders", Clock: clk, Client: redirecting.Client(), AllowPrivateTargets: true})
+	require.NoError(t, err)
+	require.NoError(t, store.EnqueueDeliveries(ctx, []*Delivery{newDelivery("d-redirect", "s-2", "evt-2", baseTime)}))
+	clk.Advance(time.Hour)
+	_, err = relaxed.DeliverPending(ctx)
+	require.NoError(t, err)
+	redirected, err := store.GetDelivery(ctx, "d-redirect")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusFound, redirected.LastStatusCode, "redirects must not be followed")
+	assert.NotContains(t, redirected.LastError, "internal detail", "response bodies must not be stored")
+}
+
+func TestDispatcher_ScopesSubscriptionsByTenant(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	acme := newSubscription("acme", "https://acme.example/hooks")
+	acme.TenantID = "acme"
+	globex := newSubscription("globex", "https://globex.example/hooks")
+	globex.TenantID = "globex"
+	for _, sub := range []*Subscription{acme, globex, newSubscription("platform", "https://ops.example/hooks")} {
+		require.NoError(t, store.CreateSubscription(ctx, sub))
+	}
+	dispatcher, err := NewDispatcher(store, &DispatcherConfig{Source: "/orders", Clock: clock.NewManualClock(baseTime)})
+	require.NoError(t, err)
+
+	evt := eventing.NewEvent(int64(1), "Order", "OrderPlaced", 1, nil, 1)
+	evt.Metadata.Set("tenant_id", "acme")
+	require.NoError(t, dispatcher.Enqueue(ctx, evt))
+	deliveries, err := store.ListDeliveries(ctx, DeliveryFilter{})
+	require.NoError(t, err)
+	var targets []string
+	for _, d := range deliveries {
+		targets = append(targets, d.SubscriptionID)
+	}
+	assert.ElementsMatch(t, []string{"acme", "platform"}, targets)
+
+	group := httpxtest.NewMockRouteGroup()
+	require.NoError(t, NewRegistrar(store, nil).RegisterRoutes(group))
+	tenantCtx, err := contextx.WithTenantID(ctx, "globex")
+	require.NoError(t, err)
+	w := httpxtest.Serve(t, group.Handlers["GET /webhooks/subscriptions"], httptest.NewRequest(http.MethodGet, "/", nil).WithContext(tenantCtx), nil)
+	var listed []Subscription
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	require.Len(t, listed, 1)
+	assert.Equal(t, "globex", listed[0].ID)
+	w = httpxtest.Serve(t, group.Handlers["GET /webhooks/subscriptions/:id/deliveries"], httptest.NewRequest(http.MethodGet, "/", nil).WithContext(tenantCtx),
+		map[string]string{"id": "acme"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = httpxtest.Serve(t, group.Handlers["POST /webhooks/deliveries/:id/retry"], httptest.NewRequest(http.MethodPost, "/", nil).WithContext(tenantCtx),
+		map[string]string{"id": deliveries[0].ID})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httpxtest.Serve(t, group.Handlers["POST /webhooks/subscriptions"], httptest.NewRequest(http.MethodPost, "/",
+		bytes.NewBufferString(`{"url":"http://169.254.169.254/latest"}`)).WithContext(tenantCtx), nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = httpxtest.Serve(t, group.Handlers["POST /webhooks/subscriptions"], httptest.NewRequest(http.MethodPost, "/",
+		bytes.NewBufferString(`{"id":"globex-2","url":"https://globex.example/v2"}`)).WithContext(tenantCtx), nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	created, err := store.GetSubscription(ctx, "globex-2")
+	require.NoError(t, err)
+	assert.Equal(t, "globex", created.TenantID)
+}
@@ -6,10 +6,38 @@ import (
 	"gochen/contextx/fields"
 )
 
+type extraFieldsKey struct{}
+
+// ContextWithFields 返回携带额外日志字段的 context；这些字段会随 ContextFields 出现在该 ctx 的每条日志中。
+//
+// 嵌套调用时字段累加，同名字段以内层为准。
+func ContextWithFields(ctx context.Context, extra ...Field) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(extra) == 0 {
+		return ctx
+	}
+	prev, _ := ctx.Value(extraFieldsKey{}).([]Field)
+	merged := make([]Field, 0, len(prev)+len(extra))
+	merged = append(merged, extra...)
+	seen := make(map[string]struct{}, len(extra))
+	for _, f := range extra {
+		seen[f.Key] = struct{}{}
+	}
+	for _, f := range prev {
+		if _, ok := seen[f.Key]; !ok {
+			merged = append(merged, f)
+		}
+	}
+	return context.WithValue(ctx, extraFieldsKey{}, merged)
+}
+
 // ContextFields 从 ctx 中提取标准化的链路字段（若存在）。
 //
 // 说明：
-// - 用于日志输出的统一维度：tenant_id/trace_id/operator；
+// - 用于日志输出的统一维度：tenant_id/trace_id/request_id/correlation_id/causation_id/operator/saga_id/aggregate_id；
+// - 之后追加 ContextWithFields 写入的额外字段；
 // - 仅在值非空时返回对应字段。
 func ContextFields(ctx context.Context) []Field {
 	if ctx == nil {
@@ -22,19 +50,40 @@ func ContextFields(ctx context.Context) []Field {
 	if v := fields.TraceID(ctx); v != "" {
 		out = append(out, String(fields.MetadataTraceKey, v))
 	}
+	if v := fields.RequestID(ctx); v != "" {
+		out = append(out, String(fields.MetadataRequestIDKey, v))
+	}
+	if v := fields.CorrelationID(ctx); v != "" {
+		out = append(out, String(fields.MetadataCorrelationKey, v))
+	}
+	if v := fields.CausationID(ctx); v != "" {
+		out = append(out, String(fields.MetadataCausationKey, v))
+	}
 	if v := fields.Operator(ctx); v != "" {
 		out = append(out, String(fields.MetadataOperatorKey, v))
 	}
+	if v := fields.SagaID(ctx); v != "" {
+		out = append(out, String(fields.SagaIDKey, v))
+	}
+	if v := fields.AggregateID(ctx); v != "" {
+		out = append(out, String(fields.AggregateIDKey, v))
+	}
+	if extra, ok := ctx.Value(extraFieldsKey{}).([]Field); ok {
+		out = append(out, extra...)
+	}
 	return out
 }
 
-// mergeContextFields 合并上下文字段集合。
-func mergeContextFields(ctx context.Context, fields []Field) []Field {
+// mergeContextFields 把 ctx 字段追加到 fields 之后；base（logger 自带字段）与 fields 中已有的键不重复追加。
+func AppendContextFields(ctx context.Context, base, fields []Field) []Field {
 	ctxFields := ContextFields(ctx)
 	if len(ctxFields) == 0 {
 		return fields
 	}
-	seen := make(map[string]struct{}, len(fields))
+	seen := make(map[string]struct{}, len(base)+len(fields))
+	for _, f := range base {
+		seen[f.Key] = struct{}{}
+	}
 	for _, f := range fields {
 		seen[f.Key] = struct{}{}
 	}
@@ -42,6 +91,7 @@ func mergeContextFields(ctx context.Context, fields []Field) []Field {
 		if _, ok := seen[f.Key]; ok {
 			continue
 		}
+		seen[f.Key] = struct{}{}
 		fields = append(fields, f)
 	}
 	return fields
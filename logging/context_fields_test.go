@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	"gochen/contextx/fields"
+)
+
+// TestContextFields_IncludesRequestID 验证 request_id 与 trace_id 一起进入日志上下文字段。
+func TestContextFields_IncludesRequestID(t *testing.T) {
+	ctx, err := fields.WithTraceID(context.Background(), "trace-1")
+	if err != nil {
+		t.Fatalf("WithTraceID: %v", err)
+	}
+	ctx, err = fields.WithRequestID(ctx, "req-1")
+	if err != nil {
+		t.Fatalf("WithRequestID: %v", err)
+	}
+
+	got := map[string]string{}
+	for _, f := range ContextFields(ctx) {
+		got[f.Key] = formatValue(f.Value)
+	}
+	if got[fields.MetadataTraceKey] != "trace-1" || got[fields.MetadataRequestIDKey] != "req-1" {
+		t.Fatalf("unexpected context fields: %v", got)
+	}
+}
+
+// TestFromContext_EnrichesEveryLine 验证 FromContext 固化链路字段，且与调用时 ctx 中的同名字段不重复输出。
+func TestFromContext_EnrichesEveryLine(t *testing.T) {
+	ctx, err := fields.WithCorrelationID(context.Background(), "corr-1")
+	if err != nil {
+		t.Fatalf("WithCorrelationID: %v", err)
+	}
+	ctx, err = fields.WithSagaID(ctx, "saga-1")
+	if err != nil {
+		t.Fatalf("WithSagaID: %v", err)
+	}
+	ctx, err = fields.WithAggregateID(ctx, "order-1")
+	if err != nil {
+		t.Fatalf("WithAggregateID: %v", err)
+	}
+	ctx = ContextWithFields(ctx, String("projection", "orders"))
+
+	var buf bytes.Buffer
+	prevOut, prevFlags := log.Writer(), log.Flags()
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	defer func() {
+		log.SetOutput(prevOut)
+		log.SetFlags(prevFlags)
+	}()
+
+	logger := NewStdLogger("").FromContext(ctx)
+	logger.Info(context.Background(), "no ctx")
+	logger.Info(ctx, "same ctx")
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		for _, want := range []string{"correlation_id=corr-1", "saga_id=saga-1", "aggregate_id=order-1", "projection=orders"} {
+			if strings.Count(line, want) != 1 {
+				t.Fatalf("expected %q exactly once in %q", want, line)
+			}
+		}
+	}
+}
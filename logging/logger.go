@@ -103,6 +103,10 @@ type ILogger interface {
 
 	// WithField 追加单个结构化字段，是 WithFields 的语法糖。
 	WithField(key string, value any) ILogger
+
+	// FromContext 把 ctx 中的链路字段（见 ContextFields）固化为结构化字段并返回新的 logger 视图，
+	// 便于把 logger 交给不接收 ctx 的组件或在循环中复用。
+	FromContext(ctx context.Context) ILogger
 }
 
 // Field 日志字段，用于在日志中附加结构化的键值对信息。
@@ -357,7 +361,7 @@ func (l *Logger) log(level Level, ctx context.Context, msg string, fields ...Fie
 	if level < l.level {
 		return
 	}
-	fields = mergeContextFields(ctx, fields)
+	fields = AppendContextFields(ctx, l.fields, fields)
 	normalized := normalizeFields(l.fields, fields...)
 	if l.format == JSONFormat {
 		logJSONLine(level, l.prefix, msg, normalized)
@@ -369,25 +373,25 @@ func (l *Logger) log(level Level, ctx context.Context, msg string, fields ...Fie
 
 // Debug 记录一条调试级文本日志。
 func (l *StdLogger) Debug(ctx context.Context, msg string, fields ...Field) {
-	fields = mergeContextFields(ctx, fields)
+	fields = AppendContextFields(ctx, l.fields, fields)
 	log.Println("[DEBUG]", l.format(msg, fields...))
 }
 
 // Info 记录一条信息级文本日志。
 func (l *StdLogger) Info(ctx context.Context, msg string, fields ...Field) {
-	fields = mergeContextFields(ctx, fields)
+	fields = AppendContextFields(ctx, l.fields, fields)
 	log.Println("[INFO]", l.format(msg, fields...))
 }
 
 // Warn 记录一条告警级文本日志。
 func (l *StdLogger) Warn(ctx context.Context, msg string, fields ...Field) {
-	fields = mergeContextFields(ctx, fields)
+	fields = AppendContextFields(ctx, l.fields, fields)
 	log.Println("[WARN]", l.format(msg, fields...))
 }
 
 // Error 记录一条错误级文本日志。
 func (l *StdLogger) Error(ctx context.Context, msg string, fields ...Field) {
-	fields = mergeContextFields(ctx, fields)
+	fields = AppendContextFields(ctx, l.fields, fields)
 	log.Println("[ERROR]", l.format(msg, fields...))
 }
 
@@ -402,6 +406,10 @@ func (l *StdLogger) WithField(key string, value any) ILogger {
 	return l.WithFields(Field{Key: key, Value: value})
 }
 
+func (l *StdLogger) FromContext(ctx context.Context) ILogger {
+	return l.WithFields(AppendContextFields(ctx, l.fields, nil)...)
+}
+
 // Debug 按配置级别输出调试日志。
 func (l *Logger) Debug(ctx context.Context, msg string, fields ...Field) {
 	l.log(DebugLevel, ctx, msg, fields...)
@@ -433,6 +441,10 @@ func (l *Logger) WithField(key string, value any) ILogger {
 	return l.WithFields(Field{Key: key, Value: value})
 }
 
+func (l *Logger) FromContext(ctx context.Context) ILogger {
+	return l.WithFields(AppendContextFields(ctx, l.fields, nil)...)
+}
+
 // NoopLogger 是一个不产生任何输出的空日志实现。
 type NoopLogger struct{}
 
@@ -455,6 +467,8 @@ func (l *NoopLogger) WithFields(fields ...Field) ILogger { return l }
 
 func (l *NoopLogger) WithField(key string, value any) ILogger { return l }
 
+func (l *NoopLogger) FromContext(ctx context.Context) ILogger { return l }
+
 var (
 	defaultFactoryMu sync.RWMutex
 	defaultFactory   func() ILogger
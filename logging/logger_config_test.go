@@ -56,3 +56,25 @@ func TestComponentLogger_UsesDefaultFactory(t *testing.T) {
 		t.Fatalf("expected message hello, got %#v", payload["message"])
 	}
 }
+
+func TestSlogLogger_FromConfigWritesJSONWithContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLoggerFromConfig(Config{Prefix: "app", Level: WarnLevel, Format: JSONFormat}, &buf).
+		WithField("component", "orders")
+
+	ctx := ContextWithFields(context.Background(), String("saga_step", "reserve"))
+	logger.Info(ctx, "hidden")
+	logger.Warn(ctx, "slow", Int("attempt", 2))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected info log to be filtered, got %q", buf.String())
+	}
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if payload["logger"] != "app" || payload["component"] != "orders" || payload["saga_step"] != "reserve" || payload["attempt"] != float64(2) {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+}
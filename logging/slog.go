@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// SlogLogger 把 ILogger 适配到标准库 log/slog，便于复用已有的 slog.Handler 管线。
+type SlogLogger struct {
+	logger *slog.Logger
+	fields []Field
+}
+
+var _ ILogger = (*SlogLogger)(nil)
+
+// NewSlogLogger 基于已有的 slog.Logger 创建 ILogger；logger 为 nil 时使用 slog.Default()。
+func NewSlogLogger(logger *slog.Logger) *SlogLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SlogLogger{logger: logger}
+}
+
+// NewSlogLoggerFromConfig 按 Config 创建输出到 w 的 slog logger（w 为 nil 时输出到 stderr）。
+//
+// Format 选择 slog.JSONHandler 或 slog.TextHandler；Prefix 非空时作为 logger 字段输出。
+func NewSlogLoggerFromConfig(cfg Config, w io.Writer) *SlogLogger {
+	if w == nil {
+		w = os.Stderr
+	}
+	opts := &slog.HandlerOptions{Level: SlogLevel(cfg.Level)}
+	var handler slog.Handler
+	if cfg.Format == JSONFormat {
+		handler = slog.NewJSONHandler(w, opts)
+	} else {
+		handler = slog.NewTextHandler(w, opts)
+	}
+	logger := slog.New(handler)
+	if cfg.Prefix != "" {
+		logger = logger.With(slog.String("logger", cfg.Prefix))
+	}
+	return NewSlogLogger(logger)
+}
+
+// SlogLevel 把 Level 转换为 slog.Level。
+func SlogLevel(level Level) slog.Level {
+	switch level {
+	case DebugLevel:
+		return slog.LevelDebug
+	case WarnLevel:
+		return slog.LevelWarn
+	case ErrorLevel:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Debug 输出调试级日志。
+func (l *SlogLogger) Debug(ctx context.Context, msg string, fields ...Field) {
+	l.log(ctx, slog.LevelDebug, msg, fields)
+}
+
+// Info 输出信息级日志。
+func (l *SlogLogger) Info(ctx context.Context, msg string, fields ...Field) {
+	l.log(ctx, slog.LevelInfo, msg, fields)
+}
+
+// Warn 输出告警级日志。
+func (l *SlogLogger) Warn(ctx context.Context, msg string, fields ...Field) {
+	l.log(ctx, slog.LevelWarn, msg, fields)
+}
+
+// Error 输出错误级日志。
+func (l *SlogLogger) Error(ctx context.Context, msg string, fields ...Field) {
+	l.log(ctx, slog.LevelError, msg, fields)
+}
+
+func (l *SlogLogger) WithFields(fields ...Field) ILogger {
+	newFields := make([]Field, len(l.fields)+len(fields))
+	copy(newFields, l.fields)
+	copy(newFields[len(l.fields):], fields)
+	return &SlogLogger{logger: slog.New(l.logger.Handler().WithAttrs(slogAttrs(fields))), fields: newFields}
+}
+
+func (l *SlogLogger) WithField(key string, value any) ILogger {
+	return l.WithFields(Field{Key: key, Value: value})
+}
+
+func (l *SlogLogger) FromContext(ctx context.Context) ILogger {
+	return l.WithFields(AppendContextFields(ctx, l.fields, nil)...)
+}
+
+func (l *SlogLogger) log(ctx context.Context, level slog.Level, msg string, fields []Field) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	fields = AppendContextFields(ctx, l.fields, fields)
+	l.logger.LogAttrs(ctx, level, msg, slogAttrs(fields)...)
+}
+
+func slogAttrs(fields []Field) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, f := range fields {
+		if err, ok := f.Value.(error); ok && !isTypedNil(f.Value) {
+			attrs = append(attrs, slog.String(f.Key, err.Error()))
+			continue
+		}
+		attrs = append(attrs, slog.Any(f.Key, f.Value))
+	}
+	return attrs
+}
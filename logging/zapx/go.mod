@@ -0,0 +1,12 @@
+module gochen/logging/zapx
+
+go 1.26.0
+
+require (
+	go.uber.org/zap v1.28.0
+	gochen v0.0.0
+)
+
+require go.uber.org/multierr v1.10.0 // indirect
+
+replace gochen => ../..
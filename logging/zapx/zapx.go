@@ -0,0 +1,122 @@
+// Package zapx 提供基于 go.uber.org/zap 的 logging.ILogger 适配实现。
+//
+// 该包是独立 module（gochen/logging/zapx），仅在需要复用 zap 日志管线时引入，
+// gochen 核心 module 不依赖 zap。
+package zapx
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"gochen/logging"
+)
+
+// Logger 把 logging.ILogger 适配到 *zap.Logger。
+type Logger struct {
+	zap    *zap.Logger
+	fields []logging.Field
+}
+
+var _ logging.ILogger = (*Logger)(nil)
+
+// New 基于已有的 zap.Logger 创建 ILogger；logger 为 nil 时使用 zap.NewNop()。
+func New(logger *zap.Logger) *Logger {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Logger{zap: logger}
+}
+
+// NewFromConfig 按 logging.Config 构建 zap logger：JSONFormat 使用 production 编码，否则使用 console 编码。
+//
+// Prefix 非空时作为 logger 名称输出。
+func NewFromConfig(cfg logging.Config, opts ...zap.Option) (*Logger, error) {
+	zcfg := zap.NewProductionConfig()
+	if cfg.Format != logging.JSONFormat {
+		zcfg = zap.NewDevelopmentConfig()
+		zcfg.Development = false
+	}
+	zcfg.Level = zap.NewAtomicLevelAt(Level(cfg.Level))
+	logger, err := zcfg.Build(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Prefix != "" {
+		logger = logger.Named(cfg.Prefix)
+	}
+	return New(logger), nil
+}
+
+// Level 把 logging.Level 转换为 zapcore.Level。
+func Level(level logging.Level) zapcore.Level {
+	switch level {
+	case logging.DebugLevel:
+		return zapcore.DebugLevel
+	case logging.WarnLevel:
+		return zapcore.WarnLevel
+	case logging.ErrorLevel:
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// Zap 返回底层 *zap.Logger。
+func (l *Logger) Zap() *zap.Logger { return l.zap }
+
+// Debug 输出调试级日志。
+func (l *Logger) Debug(ctx context.Context, msg string, fields ...logging.Field) {
+	l.log(ctx, zapcore.DebugLevel, msg, fields)
+}
+
+// Info 输出信息级日志。
+func (l *Logger) Info(ctx context.Context, msg string, fields ...logging.Field) {
+	l.log(ctx, zapcore.InfoLevel, msg, fields)
+}
+
+// Warn 输出告警级日志。
+func (l *Logger) Warn(ctx context.Context, msg string, fields ...logging.Field) {
+	l.log(ctx, zapcore.WarnLevel, msg, fields)
+}
+
+// Error 输出错误级日志。
+func (l *Logger) Error(ctx context.Context, msg string, fields ...logging.Field) {
+	l.log(ctx, zapcore.ErrorLevel, msg, fields)
+}
+
+func (l *Logger) WithFields(fields ...logging.Field) logging.ILogger {
+	newFields := make([]logging.Field, len(l.fields)+len(fields))
+	copy(newFields, l.fields)
+	copy(newFields[len(l.fields):], fields)
+	return &Logger{zap: l.zap.With(zapFields(fields)...), fields: newFields}
+}
+
+func (l *Logger) WithField(key string, value any) logging.ILogger {
+	return l.WithFields(logging.Field{Key: key, Value: value})
+}
+
+func (l *Logger) FromContext(ctx context.Context) logging.ILogger {
+	return l.WithFields(logging.AppendContextFields(ctx, l.fields, nil)...)
+}
+
+func (l *Logger) log(ctx context.Context, level zapcore.Level, msg string, fields []logging.Field) {
+	ce := l.zap.Check(level, msg)
+	if ce == nil {
+		return
+	}
+	ce.Write(zapFields(logging.AppendContextFields(ctx, l.fields, fields))...)
+}
+
+func zapFields(fields []logging.Field) []zap.Field {
+	out := make([]zap.Field, 0, len(fields))
+	for _, f := range fields {
+		if err, ok := f.Value.(error); ok {
+			out = append(out, zap.NamedError(f.Key, err))
+			continue
+		}
+		out = append(out, zap.Any(f.Key, f.Value))
+	}
+	return out
+}
@@ -0,0 +1,53 @@
+package zapx
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"gochen/contextx"
+	"gochen/logging"
+)
+
+func TestLogger_WritesFieldsAndContextWithLevelFilter(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := New(zap.New(core)).WithField("component", "orders")
+
+	ctx, err := contextx.WithCorrelationID(context.Background(), "corr-1")
+	if err != nil {
+		t.Fatalf("WithCorrelationID: %v", err)
+	}
+	logger.Debug(ctx, "hidden")
+	logger.Info(ctx, "placed", logging.String("order_id", "o-1"))
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected debug entry to be filtered, got %d entries", len(entries))
+	}
+	got := entries[0].ContextMap()
+	if got["component"] != "orders" || got["order_id"] != "o-1" || got["correlation_id"] != "corr-1" {
+		t.Fatalf("unexpected fields: %v", got)
+	}
+}
+
+func TestLogger_FromContextDoesNotDuplicateFields(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	ctx, err := contextx.WithTraceID(context.Background(), "trace-1")
+	if err != nil {
+		t.Fatalf("WithTraceID: %v", err)
+	}
+	New(zap.New(core)).FromContext(ctx).Info(ctx, "done")
+
+	count := 0
+	for _, f := range logs.All()[0].Context {
+		if f.Key == "trace_id" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Fatalf("expected trace_id once, got %d", count)
+	}
+}
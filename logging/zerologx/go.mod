@@ -0,0 +1,16 @@
+module gochen/logging/zerologx
+
+go 1.26.0
+
+require (
+	github.com/rs/zerolog v1.35.1
+	gochen v0.0.0
+)
+
+require (
+	github.com/mattn/go-colorable v0.1.14 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	golang.org/x/sys v0.36.0 // indirect
+)
+
+replace gochen => ../..
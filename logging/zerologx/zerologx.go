@@ -0,0 +1,119 @@
+// Package zerologx 提供基于 github.com/rs/zerolog 的 logging.ILogger 适配实现。
+//
+// 该包是独立 module（gochen/logging/zerologx），仅在需要复用 zerolog 日志管线时引入，
+// gochen 核心 module 不依赖 zerolog。
+package zerologx
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/rs/zerolog"
+
+	"gochen/logging"
+)
+
+// Logger 把 logging.ILogger 适配到 zerolog.Logger。
+type Logger struct {
+	zl     zerolog.Logger
+	fields []logging.Field
+}
+
+var _ logging.ILogger = (*Logger)(nil)
+
+// New 基于已有的 zerolog.Logger 创建 ILogger。
+func New(logger zerolog.Logger) *Logger {
+	return &Logger{zl: logger}
+}
+
+// NewFromConfig 按 logging.Config 创建输出到 w 的 zerolog logger（w 为 nil 时输出到 stderr）。
+//
+// JSONFormat 直接输出 JSON；其他格式经 zerolog.ConsoleWriter 输出可读文本。Prefix 非空时作为 logger 字段输出。
+func NewFromConfig(cfg logging.Config, w io.Writer) *Logger {
+	if w == nil {
+		w = os.Stderr
+	}
+	if cfg.Format != logging.JSONFormat {
+		w = zerolog.ConsoleWriter{Out: w, NoColor: true}
+	}
+	zctx := zerolog.New(w).Level(Level(cfg.Level)).With().Timestamp()
+	if cfg.Prefix != "" {
+		zctx = zctx.Str("logger", cfg.Prefix)
+	}
+	return New(zctx.Logger())
+}
+
+// Level 把 logging.Level 转换为 zerolog.Level。
+func Level(level logging.Level) zerolog.Level {
+	switch level {
+	case logging.DebugLevel:
+		return zerolog.DebugLevel
+	case logging.WarnLevel:
+		return zerolog.WarnLevel
+	case logging.ErrorLevel:
+		return zerolog.ErrorLevel
+	default:
+		return zerolog.InfoLevel
+	}
+}
+
+// Zerolog 返回底层 zerolog.Logger。
+func (l *Logger) Zerolog() zerolog.Logger { return l.zl }
+
+// Debug 输出调试级日志。
+func (l *Logger) Debug(ctx context.Context, msg string, fields ...logging.Field) {
+	l.log(ctx, l.zl.Debug(), msg, fields)
+}
+
+// Info 输出信息级日志。
+func (l *Logger) Info(ctx context.Context, msg string, fields ...logging.Field) {
+	l.log(ctx, l.zl.Info(), msg, fields)
+}
+
+// Warn 输出告警级日志。
+func (l *Logger) Warn(ctx context.Context, msg string, fields ...logging.Field) {
+	l.log(ctx, l.zl.Warn(), msg, fields)
+}
+
+// Error 输出错误级日志。
+func (l *Logger) Error(ctx context.Context, msg string, fields ...logging.Field) {
+	l.log(ctx, l.zl.Error(), msg, fields)
+}
+
+func (l *Logger) WithFields(fields ...logging.Field) logging.ILogger {
+	newFields := make([]logging.Field, len(l.fields)+len(fields))
+	copy(newFields, l.fields)
+	copy(newFields[len(l.fields):], fields)
+	zctx := l.zl.With()
+	for _, f := range fields {
+		if err, ok := f.Value.(error); ok {
+			zctx = zctx.AnErr(f.Key, err)
+			continue
+		}
+		zctx = zctx.Interface(f.Key, f.Value)
+	}
+	return &Logger{zl: zctx.Logger(), fields: newFields}
+}
+
+func (l *Logger) WithField(key string, value any) logging.ILogger {
+	return l.WithFields(logging.Field{Key: key, Value: value})
+}
+
+func (l *Logger) FromContext(ctx context.Context) logging.ILogger {
+	return l.WithFields(logging.AppendContextFields(ctx, l.fields, nil)...)
+}
+
+func (l *Logger) log(ctx context.Context, event *zerolog.Event, msg string, fields []logging.Field) {
+	if event == nil {
+		return
+	}
+	for _, f := range logging.AppendContextFields(ctx, l.fields, fields) {
+		if err, ok := f.Value.(error); ok {
+			event = event.AnErr(f.Key, err)
+			continue
+		}
+		event = event.Interface(f.Key, f.Value)
+	}
+	event.Msg(msg)
+}
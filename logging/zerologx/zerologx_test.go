@@ -0,0 +1,41 @@
+package zerologx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/logging"
+)
+
+func TestLogger_JSONOutputWithContextAndLevelFilter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewFromConfig(logging.Config{Prefix: "app", Level: logging.InfoLevel, Format: logging.JSONFormat}, &buf).
+		WithField("component", "orders")
+
+	ctx, err := contextx.WithCorrelationID(context.Background(), "corr-1")
+	if err != nil {
+		t.Fatalf("WithCorrelationID: %v", err)
+	}
+	logger.Debug(ctx, "hidden")
+	logger.Error(ctx, "failed", logging.Error(errors.NewCode(errors.Database, "db down")))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected debug line to be filtered, got %q", buf.String())
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got["logger"] != "app" || got["component"] != "orders" || got["correlation_id"] != "corr-1" || got["level"] != "error" {
+		t.Fatalf("unexpected payload: %v", got)
+	}
+	if msg, _ := got["error"].(string); !strings.Contains(msg, "db down") {
+		t.Fatalf("expected error field, got: %v", got["error"])
+	}
+}
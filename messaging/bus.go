@@ -3,10 +3,8 @@ package messaging
 
 import (
 	"context"
-	"fmt"
 	"gochen/contextx"
 	gerrors "gochen/errors"
-	"runtime/debug"
 	"sync"
 )
 
@@ -42,6 +40,15 @@ type MessageBus struct {
 
 	// handlerErrorHook 可选的处理器错误钩子
 	handlerErrorHook HandlerErrorHook
+
+	// replyMutex 保护请求/应答的惰性初始化（见 request.go）
+	replyMutex sync.Mutex
+	replyTopic string
+	replies    *replyRouter
+
+	// groupMutex 保护进程内消费组分发器（见 group.go）
+	groupMutex sync.Mutex
+	groups     map[string]*groupDispatcher
 }
 
 // NewMessageBus 创建一个基于指定 transport 的消息总线。
@@ -190,7 +197,7 @@ func (bus *MessageBus) executeMiddlewares(ctx context.Context, message IMessage,
 		return gerrors.NewCode(gerrors.InvalidInput, "ctx is nil")
 	}
 
-	// 默认贯通：将 metadata 中的链路信息（tenant/trace/operator）与 message.Metadata 双向补齐。
+	// 默认贯通：将 metadata 中的链路信息（tenant/trace/operator/correlation/causation）与 message.Metadata 双向补齐。
 	// 说明：
 	// - Publish：确保 metadata 携带关键字段，跨进程可关联；
 	// - Consume：若 Transport 未透传 ctx，该信息也可从 metadata 派生回来（见 handlerWithErrorHook.Handle）。
@@ -214,6 +221,13 @@ func (bus *MessageBus) executeMiddlewares(ctx context.Context, message IMessage,
 		if err := contextx.InjectOperator(ctx, md); err != nil {
 			return err
 		}
+		if err := contextx.InjectCorrelationID(ctx, md); err != nil {
+			return err
+		}
+		// 处理上游消息期间发布的消息以上游消息为直接原因（ctx 的 causationID 由消费侧设置）。
+		if err := contextx.InjectCausationID(ctx, md); err != nil {
+			return err
+		}
 	}
 
 	bus.mutex.RLock()
@@ -255,9 +269,7 @@ func (h *handlerWithErrorHook) Handle(ctx context.Context, message IMessage) (er
 			messageID = message.GetID()
 		}
 		if r := recover(); r != nil {
-			err = gerrors.NewCode(gerrors.Internal, "message handler panicked").
-				WithContext("panic", fmt.Sprint(r)).
-				WithContext("stack", string(debug.Stack())).
+			err = PanicError("message handler panicked", r).
 				WithContext("handler_type", h.inner.Type()).
 				WithContext("message_type", messageType).
 				WithContext("message_id", messageID)
@@ -281,6 +293,16 @@ func (h *handlerWithErrorHook) Handle(ctx context.Context, message IMessage) (er
 		if err != nil {
 			return err
 		}
+		// 关联与因果：上游未携带 correlation_id 时该消息即流程起点；
+		// 处理期间持久化的事件与发出的消息均以该消息为直接原因（causation_id）。
+		ctx, err = contextx.EnsureCorrelationID(ctx, md, message.GetID())
+		if err != nil {
+			return err
+		}
+		ctx, err = contextx.WithCausationID(ctx, message.GetID())
+		if err != nil {
+			return err
+		}
 	}
 
 	err = h.inner.Handle(ctx, message)
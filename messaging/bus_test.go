@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"gochen/contextx"
 	"gochen/errors"
 	"gochen/messaging"
 	synctransport "gochen/messaging/transport/direct"
@@ -208,3 +209,57 @@ func TestMessageBus_PublishAll_NilMessage_FailFast(t *testing.T) {
 		t.Fatalf("expected invalid input error, got: %#v", err)
 	}
 }
+
+type funcHandler struct {
+	fn func(ctx context.Context, msg messaging.IMessage) error
+}
+
+// Handle 处理消息并执行业务处理逻辑。
+func (h *funcHandler) Handle(ctx context.Context, msg messaging.IMessage) error {
+	return h.fn(ctx, msg)
+}
+
+// Type 返回类型标识。
+func (h *funcHandler) Type() string { return "funcHandler" }
+
+// TestMessageBus_CorrelationAndCausation 验证消费侧以消息 ID 作为 causation，下游消息继承 correlation 并记录直接原因。
+func TestMessageBus_CorrelationAndCausation(t *testing.T) {
+	transport := synctransport.NewSyncTransport()
+	if err := transport.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start sync transport: %v", err)
+	}
+	bus := messaging.NewMessageBus(transport)
+
+	child := &testMessage{id: "m2", typ: "child"}
+	var childCorrelation, childCausation string
+	unsubRoot, err := bus.Subscribe(context.Background(), "root", &funcHandler{fn: func(ctx context.Context, _ messaging.IMessage) error {
+		return bus.Publish(ctx, child)
+	}})
+	if err != nil {
+		t.Fatalf("subscribe root failed: %v", err)
+	}
+	defer func() { _ = unsubRoot(context.Background()) }()
+	unsubChild, err := bus.Subscribe(context.Background(), "child", &funcHandler{fn: func(ctx context.Context, _ messaging.IMessage) error {
+		childCorrelation = contextx.CorrelationID(ctx)
+		childCausation = contextx.CausationID(ctx)
+		return nil
+	}})
+	if err != nil {
+		t.Fatalf("subscribe child failed: %v", err)
+	}
+	defer func() { _ = unsubChild(context.Background()) }()
+
+	if err := bus.Publish(context.Background(), &testMessage{id: "m1", typ: "root"}); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+
+	if v, _ := child.GetMetadata().Get(contextx.MetadataCorrelationKey); v != "m1" {
+		t.Fatalf("expected child correlation_id m1, got %q", v)
+	}
+	if v, _ := child.GetMetadata().Get(contextx.MetadataCausationKey); v != "m1" {
+		t.Fatalf("expected child causation_id m1, got %q", v)
+	}
+	if childCorrelation != "m1" || childCausation != "m2" {
+		t.Fatalf("unexpected child handler context: correlation=%q causation=%q", childCorrelation, childCausation)
+	}
+}
@@ -0,0 +1,59 @@
+// Package codec 提供消息的 JSON 编解码，供持久化存储（如 messaging/schedule）与跨进程传输共用。
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/messaging"
+	"gochen/messaging/command"
+)
+
+// EncodeMessage 把消息编码为 JSON（用于持久化存储与跨进程传输）。
+//
+// 命令与事件的聚合字段随消息一起编码，DecodeMessage 会还原为 *command.Command / *eventing.Event。
+func EncodeMessage(msg messaging.IMessage) ([]byte, error) {
+	if msg == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "message cannot be nil")
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, "encode message failed").
+			WithContext("message_id", msg.GetID()).
+			WithContext("message_type", msg.GetType())
+	}
+	return data, nil
+}
+
+// DecodeMessage 还原 EncodeMessage 编码的消息。
+//
+// 命令还原为 *command.Command；事件按聚合 ID 的 JSON 类型还原为 *eventing.Event[int64] 或 *eventing.Event[string]；
+// 其他消息还原为 *messaging.Message。载荷还原为通用 JSON 值，处理方通过 Payload.DecodeTo 取用。
+func DecodeMessage(data []byte) (messaging.IMessage, error) {
+	var probe struct {
+		Kind        messaging.MessageKind `json:"kind"`
+		AggregateID json.RawMessage       `json:"aggregate_id"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, "decode message failed")
+	}
+
+	var msg messaging.IMessage
+	switch {
+	case probe.Kind == messaging.KindCommand:
+		msg = &command.Command{}
+	case probe.Kind == messaging.KindEvent && bytes.HasPrefix(bytes.TrimSpace(probe.AggregateID), []byte(`"`)):
+		msg = &eventing.Event[string]{}
+	case probe.Kind == messaging.KindEvent:
+		msg = &eventing.Event[int64]{}
+	default:
+		msg = &messaging.Message{}
+	}
+	if err := json.Unmarshal(data, msg); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, "decode message failed").
+			WithContext("kind", string(probe.Kind))
+	}
+	return msg, nil
+}
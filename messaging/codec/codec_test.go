@@ -0,0 +1,41 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/messaging/command"
+)
+
+// TestMessageRoundTrip 验证命令与事件编码后还原为具体类型。
+func TestMessageRoundTrip(t *testing.T) {
+	cmd := command.NewCommand("cmd-1", "CreateOrder", "order-1", "Order", map[string]any{"sku": "A"})
+	data, err := EncodeMessage(cmd)
+	require.NoError(t, err)
+	decoded, err := DecodeMessage(data)
+	require.NoError(t, err)
+	require.IsType(t, &command.Command{}, decoded)
+	require.Equal(t, "cmd-1", decoded.GetID())
+
+	intEvent := eventing.NewEvent[int64](7, "Order", "OrderCreated", 1, map[string]any{"sku": "A"})
+	data, err = EncodeMessage(intEvent)
+	require.NoError(t, err)
+	decoded, err = DecodeMessage(data)
+	require.NoError(t, err)
+	require.IsType(t, &eventing.Event[int64]{}, decoded)
+
+	stringEvent := eventing.NewEvent("order-7", "Order", "OrderCreated", 1, map[string]any{"sku": "A"})
+	data, err = EncodeMessage(stringEvent)
+	require.NoError(t, err)
+	decoded, err = DecodeMessage(data)
+	require.NoError(t, err)
+	require.IsType(t, &eventing.Event[string]{}, decoded)
+
+	_, err = EncodeMessage(nil)
+	require.True(t, errors.Is(err, errors.InvalidInput))
+	_, err = DecodeMessage([]byte("{"))
+	require.True(t, errors.Is(err, errors.InvalidInput))
+}
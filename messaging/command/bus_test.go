@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"gochen/contextx"
 	"gochen/errors"
 	"gochen/messaging"
 	synctransport "gochen/messaging/transport/direct"
@@ -179,6 +180,39 @@ func TestCommandExecutor_Middleware(t *testing.T) {
 	assert.True(t, handlerExecuted)
 }
 
+func TestCommandExecutor_CorrelationAndCausation(t *testing.T) {
+	executor := NewCommandExecutor()
+
+	var correlationID, causationID string
+	require.NoError(t, executor.RegisterHandler("CreateUser", func(ctx context.Context, cmd *Command) error {
+		correlationID = contextx.CorrelationID(ctx)
+		causationID = contextx.CausationID(ctx)
+		return nil
+	}))
+
+	// 根命令：以命令 ID 作为 correlation_id，命令自身没有上游原因。
+	root := NewCommand("cmd-1", "CreateUser", "1", "User", nil)
+	require.NoError(t, executor.Execute(context.Background(), root))
+	assert.Equal(t, "cmd-1", correlationID)
+	assert.Equal(t, "cmd-1", causationID)
+	got, _ := root.GetMetadata().Get(contextx.MetadataCorrelationKey)
+	assert.Equal(t, "cmd-1", got)
+	_, ok := root.GetMetadata().Get(contextx.MetadataCausationKey)
+	assert.False(t, ok)
+
+	// 下游命令：继承流程 correlation_id，并记录引起它的上游消息。
+	ctx, err := contextx.WithCorrelationID(context.Background(), "flow-1")
+	require.NoError(t, err)
+	ctx, err = contextx.WithCausationID(ctx, "evt-1")
+	require.NoError(t, err)
+	child := NewCommand("cmd-2", "CreateUser", "2", "User", nil)
+	require.NoError(t, executor.Execute(ctx, child))
+	assert.Equal(t, "flow-1", correlationID)
+	assert.Equal(t, "cmd-2", causationID)
+	got, _ = child.GetMetadata().Get(contextx.MetadataCausationKey)
+	assert.Equal(t, "evt-1", got)
+}
+
 func TestCommandExecutor_RegisterHandler_ReplacesExisting(t *testing.T) {
 	executor := NewCommandExecutor()
 
@@ -6,7 +6,6 @@ import (
 	"gochen/contextx"
 	"gochen/errors"
 	"gochen/messaging"
-	"runtime/debug"
 	"strings"
 	"sync"
 )
@@ -163,7 +162,16 @@ func deriveCommandContext(ctx context.Context, cmd *Command) (context.Context, e
 	if err := contextx.InjectOperator(derived, md); err != nil {
 		return nil, err
 	}
-	return derived, nil
+	// 命令自身记录上游原因；命令执行期间产生的事件以命令 ID 为 causation_id，
+	// 上游未携带 correlation_id 时命令即流程起点（root），以其 ID 作为 correlation_id。
+	if err := contextx.InjectCausationID(derived, md); err != nil {
+		return nil, err
+	}
+	derived, err = contextx.EnsureCorrelationID(derived, md, cmd.GetID())
+	if err != nil {
+		return nil, err
+	}
+	return contextx.WithCausationID(derived, cmd.GetID())
 }
 
 func normalizeCommandMessage(message messaging.IMessage) (*Command, string, error) {
@@ -193,9 +201,7 @@ func normalizeCommandMessage(message messaging.IMessage) (*Command, string, erro
 func invokeCommandHandler(ctx context.Context, cmd *Command, commandType string, handler CommandHandlerFunc) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			err = errors.NewCode(errors.Internal, "command handler panicked").
-				WithContext("panic", fmt.Sprint(r)).
-				WithContext("stack", string(debug.Stack())).
+			err = messaging.PanicError("command handler panicked", r).
 				WithContext("command_type", commandType).
 				WithContext("command_id", cmd.GetID())
 		}
@@ -0,0 +1,101 @@
+// Package idempotency 定义按幂等键去重命令的记录与存储抽象。
+//
+// 调用方（通常是 HTTP 客户端）为一次业务意图生成幂等键，重试时复用同一键；
+// middleware.KeyedIdempotencyMiddleware 以该键占用记录、执行命令并保存结果，重复投递直接返回首次结果。
+//
+// 内置实现：
+//   - NewMemoryStore：单进程与测试；
+//   - idempotency/sqlstore：共享数据库表，多实例部署；
+//   - idempotency/redis：基于最小 Redis 客户端接口，多实例部署。
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"gochen/errors"
+)
+
+// Status 是幂等记录状态。
+type Status string
+
+const (
+	// StatusPending 表示命令正在执行，键已被占用。
+	StatusPending Status = "pending"
+	// StatusCompleted 表示命令已执行完成，结果可重放。
+	StatusCompleted Status = "completed"
+)
+
+// Record 是一个幂等键的占用与执行结果。
+type Record struct {
+	// Key 幂等键。
+	Key string `json:"key"`
+	// CommandType 首次执行的命令类型；同一键用于其他命令类型会被拒绝。
+	CommandType string `json:"command_type"`
+	// CommandID 首次执行的命令 ID。
+	CommandID string `json:"command_id"`
+	// PayloadHash 首次执行的命令载荷摘要；同一键携带不同载荷会被拒绝（为空时不比较）。
+	PayloadHash string `json:"payload_hash,omitempty"`
+	// Status 记录状态。
+	Status Status `json:"status"`
+	// ErrorCode/ErrorMessage 为首次执行返回的错误；均为空表示执行成功。
+	ErrorCode    errors.ErrorCode `json:"error_code,omitempty"`
+	ErrorMessage string           `json:"error_message,omitempty"`
+	// CreatedAt 占用时间。
+	CreatedAt time.Time `json:"created_at"`
+	// CompletedAt 完成时间（pending 时为零值）。
+	CompletedAt time.Time `json:"completed_at,omitzero"`
+	// ExpiresAt 记录过期时间：pending 为占用租期，completed 为结果保留期；过期后键可被重新占用。
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Err 把记录的执行结果还原为错误；执行成功时返回 nil。
+func (r *Record) Err() error {
+	if r == nil || (r.ErrorCode == "" && r.ErrorMessage == "") {
+		return nil
+	}
+	code := r.ErrorCode
+	if code == "" {
+		code = errors.Internal
+	}
+	return errors.NewCode(code, r.ErrorMessage).
+		WithContext("idempotency_key", r.Key).
+		WithContext("idempotent_replay", true)
+}
+
+// SetResult 记录执行结果（err 为 nil 表示成功）。
+func (r *Record) SetResult(err error) {
+	if err == nil {
+		r.ErrorCode, r.ErrorMessage = "", ""
+		return
+	}
+	r.ErrorCode = errors.Code(err)
+	if appErr, ok := errors.AsType[*errors.AppError](err); ok && appErr != nil {
+		r.ErrorMessage = appErr.Message()
+		return
+	}
+	r.ErrorMessage = err.Error()
+}
+
+// IStore 抽象幂等记录存储。
+//
+// 实现需保证 Reserve 对同一键原子（并发占用至多一个成功）；过期判断以调用方传入记录的时间为准，便于注入时钟。
+type IStore interface {
+	// Reserve 占用 record.Key：键不存在或既有记录已过期（ExpiresAt <= record.CreatedAt）时写入 record 并返回 (nil, true)；
+	// 否则返回既有记录与 false。
+	Reserve(ctx context.Context, record *Record) (existing *Record, reserved bool, err error)
+
+	// Complete 把 record.Key 的占用更新为 record（通常为 StatusCompleted 与执行结果）；
+	// 占用已过期并被其他命令接管时返回 NewReservationLostError 构造的 Conflict 错误。
+	Complete(ctx context.Context, record *Record) error
+
+	// Release 删除 record.Key 上由 record.CommandID 持有的占用，使后续重试可重新执行。
+	Release(ctx context.Context, record *Record) error
+}
+
+// NewReservationLostError 创建“占用已丢失”错误（errors.Conflict）：pending 租期过期后键被其他命令重新占用。
+func NewReservationLostError(record *Record) *errors.AppError {
+	return errors.NewCode(errors.Conflict, "idempotency key reservation lost").
+		WithContext("idempotency_key", record.Key).
+		WithContext("command_id", record.CommandID)
+}
@@ -0,0 +1,79 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+
+	"gochen/errors"
+)
+
+// MemoryStore 是进程内幂等记录存储，适用于单实例部署与测试。
+//
+// 过期记录在下一次 Reserve 同一键时被覆盖。
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+var _ IStore = (*MemoryStore)(nil)
+
+// NewMemoryStore 创建进程内幂等记录存储。
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record)}
+}
+
+// Reserve 原子占用幂等键。
+func (s *MemoryStore) Reserve(_ context.Context, record *Record) (*Record, bool, error) {
+	if err := validateRecord(record); err != nil {
+		return nil, false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.records[record.Key]; ok && existing.ExpiresAt.After(record.CreatedAt) {
+		return &existing, false, nil
+	}
+	s.records[record.Key] = *record
+	return nil, true, nil
+}
+
+// Complete 写入执行结果；占用已不属于 record.CommandID 时返回 Conflict。
+func (s *MemoryStore) Complete(_ context.Context, record *Record) error {
+	if err := validateRecord(record); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.records[record.Key]; !ok || existing.CommandID != record.CommandID {
+		return NewReservationLostError(record)
+	}
+	s.records[record.Key] = *record
+	return nil
+}
+
+// Release 删除由 record.CommandID 持有的占用。
+func (s *MemoryStore) Release(_ context.Context, record *Record) error {
+	if err := validateRecord(record); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.records[record.Key]; ok && existing.CommandID == record.CommandID {
+		delete(s.records, record.Key)
+	}
+	return nil
+}
+
+// Get 返回幂等键的当前记录（含已过期记录）。
+func (s *MemoryStore) Get(key string) (Record, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[key]
+	return record, ok
+}
+
+func validateRecord(record *Record) error {
+	if record == nil || record.Key == "" {
+		return errors.NewCode(errors.InvalidInput, "idempotency record key cannot be empty")
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+// Package redis 提供基于 Redis 的幂等记录存储（idempotency.IStore），适用于多实例部署。
+//
+// 记录以 JSON 写入 "<KeyPrefix><idempotency_key>"，过期由 Redis TTL 完成；占用使用 `SET NX PX`，
+// 接管与更新以“值匹配才写入”的脚本原子执行，避免覆盖其他命令的占用。
+// 框架核心不依赖 Redis 客户端，业务侧实现 IClient 即可（见 README）。
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gochen/errors"
+	"gochen/messaging/command/idempotency"
+)
+
+const (
+	// DefaultKeyPrefix 是幂等键的默认前缀。
+	DefaultKeyPrefix = "gochen:idempotency:"
+
+	reserveAttempts = 3
+)
+
+// IClient 是幂等记录存储所需的最小 Redis 客户端能力。
+//
+// CompareAndSet / CompareAndDelete 需以 Lua 脚本原子执行“值匹配才操作”。
+type IClient interface {
+	// SetNX 仅在键不存在时写入并设置过期时间，写入成功返回 true。
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	// Get 读取键值；键不存在时返回 found=false 且 err=nil。
+	Get(ctx context.Context, key string) (value string, found bool, err error)
+	// CompareAndSet 仅当键的值等于 old 时写入 value 并重置过期时间，写入成功返回 true。
+	CompareAndSet(ctx context.Context, key, old, value string, ttl time.Duration) (bool, error)
+	// CompareAndDelete 仅当键的值等于 value 时删除键，删除成功返回 true。
+	CompareAndDelete(ctx context.Context, key, value string) (bool, error)
+}
+
+// Config 定义 Redis 幂等记录存储配置。
+type Config struct {
+	// KeyPrefix 为空时使用 DefaultKeyPrefix。
+	KeyPrefix string
+}
+
+// Store 是基于 Redis 的幂等记录存储。
+type Store struct {
+	client IClient
+	config Config
+}
+
+var _ idempotency.IStore = (*Store)(nil)
+
+// NewStore 创建 Redis 幂等记录存储。
+func NewStore(client IClient, config Config) (*Store, error) {
+	if client == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "redis idempotency store client cannot be nil")
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = DefaultKeyPrefix
+	}
+	return &Store{client: client, config: config}, nil
+}
+
+// Reserve 原子占用幂等键；Redis 中仍存在但按 record.CreatedAt 已过期的记录会被接管。
+func (s *Store) Reserve(ctx context.Context, record *idempotency.Record) (*idempotency.Record, bool, error) {
+	if err := validateRecord(record); err != nil {
+		return nil, false, err
+	}
+	value, err := encode(record)
+	if err != nil {
+		return nil, false, err
+	}
+	key := s.config.KeyPrefix + record.Key
+	ttl := ttlOf(record)
+
+	for range reserveAttempts {
+		ok, err := s.client.SetNX(ctx, key, value, ttl)
+		if err != nil {
+			return nil, false, errors.Wrap(err, errors.Dependency, "reserve idempotency key in redis failed").
+				WithContext("idempotency_key", record.Key)
+		}
+		if ok {
+			return nil, true, nil
+		}
+		raw, existing, found, err := s.load(ctx, key, record.Key)
+		if err != nil {
+			return nil, false, err
+		}
+		if !found {
+			// 记录在 SETNX 与 GET 之间过期或被释放：重试占用。
+			continue
+		}
+		if existing.ExpiresAt.After(record.CreatedAt) {
+			return existing, false, nil
+		}
+		swapped, err := s.client.CompareAndSet(ctx, key, raw, value, ttl)
+		if err != nil {
+			return nil, false, errors.Wrap(err, errors.Dependency, "reserve idempotency key in redis failed").
+				WithContext("idempotency_key", record.Key)
+		}
+		if swapped {
+			return nil, true, nil
+		}
+	}
+	return nil, false, errors.NewCode(errors.Conflict, "idempotency key is contended").
+		WithContext("idempotency_key", record.Key)
+}
+
+// Complete 写入执行结果；占用已不属于 record.CommandID 时返回 Conflict。
+func (s *Store) Complete(ctx context.Context, record *idempotency.Record) error {
+	if err := validateRecord(record); err != nil {
+		return err
+	}
+	value, err := encode(record)
+	if err != nil {
+		return err
+	}
+	key := s.config.KeyPrefix + record.Key
+	raw, existing, found, err := s.load(ctx, key, record.Key)
+	if err != nil {
+		return err
+	}
+	if !found || existing.CommandID != record.CommandID {
+		return idempotency.NewReservationLostError(record)
+	}
+	swapped, err := s.client.CompareAndSet(ctx, key, raw, value, ttlOf(record))
+	if err != nil {
+		return errors.Wrap(err, errors.Dependency, "complete idempotency key in redis failed").
+			WithContext("idempotency_key", record.Key)
+	}
+	if !swapped {
+		return idempotency.NewReservationLostError(record)
+	}
+	return nil
+}
+
+// Release 删除由 record.CommandID 持有的 pending 占用。
+func (s *Store) Release(ctx context.Context, record *idempotency.Record) error {
+	if err := validateRecord(record); err != nil {
+		return err
+	}
+	key := s.config.KeyPrefix + record.Key
+	raw, existing, found, err := s.load(ctx, key, record.Key)
+	if err != nil {
+		return err
+	}
+	if !found || existing.CommandID != record.CommandID || existing.Status != idempotency.StatusPending {
+		return nil
+	}
+	if _, err := s.client.CompareAndDelete(ctx, key, raw); err != nil {
+		return errors.Wrap(err, errors.Dependency, "release idempotency key in redis failed").
+			WithContext("idempotency_key", record.Key)
+	}
+	return nil
+}
+
+// load 读取并解码记录，同时返回原始值供 CompareAndSet / CompareAndDelete 比对。
+func (s *Store) load(ctx context.Context, key, idemKey string) (string, *idempotency.Record, bool, error) {
+	raw, found, err := s.client.Get(ctx, key)
+	if err != nil {
+		return "", nil, false, errors.Wrap(err, errors.Dependency, "load idempotency record from redis failed").
+			WithContext("idempotency_key", idemKey)
+	}
+	if !found {
+		return "", nil, false, nil
+	}
+	var record idempotency.Record
+	if err := json.Unmarshal([]byte(raw), &record); err != nil {
+		return "", nil, false, errors.Wrap(err, errors.Internal, "decode idempotency record failed").
+			WithContext("idempotency_key", idemKey)
+	}
+	return raw, &record, true, nil
+}
+
+func encode(record *idempotency.Record) (string, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", errors.Wrap(err, errors.InvalidInput, "serialize idempotency record failed").
+			WithContext("idempotency_key", record.Key)
+	}
+	return string(data), nil
+}
+
+// ttlOf 以记录自身的时间（完成时间或占用时间）计算剩余保留时长，至少 1ms。
+func ttlOf(record *idempotency.Record) time.Duration {
+	from := record.CreatedAt
+	if !record.CompletedAt.IsZero() {
+		from = record.CompletedAt
+	}
+	return max(record.ExpiresAt.Sub(from), time.Millisecond)
+}
+
+func validateRecord(record *idempotency.Record) error {
+	if record == nil || record.Key == "" {
+		return errors.NewCode(errors.InvalidInput, "idempotency record key cannot be empty")
+	}
+	return nil
+}
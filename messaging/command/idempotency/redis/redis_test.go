@@ -0,0 +1,135 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/messaging/command/idempotency"
+)
+
+// fakeClient 是内存版 IClient，记录 TTL，不自动过期。
+type fakeClient struct {
+	mu   sync.Mutex
+	data map[string]string
+	ttls map[string]time.Duration
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{data: make(map[string]string), ttls: make(map[string]time.Duration)}
+}
+
+func (c *fakeClient) SetNX(_ context.Context, key, value string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.data[key]; ok {
+		return false, nil
+	}
+	c.data[key], c.ttls[key] = value, ttl
+	return true, nil
+}
+
+func (c *fakeClient) Get(_ context.Context, key string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.data[key]
+	return v, ok, nil
+}
+
+func (c *fakeClient) CompareAndSet(_ context.Context, key, old, value string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.data[key] != old {
+		return false, nil
+	}
+	c.data[key], c.ttls[key] = value, ttl
+	return true, nil
+}
+
+func (c *fakeClient) CompareAndDelete(_ context.Context, key, value string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.data[key] != value {
+		return false, nil
+	}
+	delete(c.data, key)
+	delete(c.ttls, key)
+	return true, nil
+}
+
+func pending(key, commandID string, now time.Time) *idempotency.Record {
+	return &idempotency.Record{
+		Key:         key,
+		CommandType: "Deposit",
+		CommandID:   commandID,
+		Status:      idempotency.StatusPending,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Minute),
+	}
+}
+
+func TestStore_ReserveCompleteReplay(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient()
+	store, err := NewStore(client, Config{})
+	require.NoError(t, err)
+	now := time.Unix(1000, 0).UTC()
+
+	first := pending("key-1", "cmd-1", now)
+	_, reserved, err := store.Reserve(ctx, first)
+	require.NoError(t, err)
+	require.True(t, reserved)
+	require.Equal(t, time.Minute, client.ttls["gochen:idempotency:key-1"])
+
+	existing, reserved, err := store.Reserve(ctx, pending("key-1", "cmd-2", now))
+	require.NoError(t, err)
+	require.False(t, reserved)
+	require.Equal(t, idempotency.StatusPending, existing.Status)
+
+	first.Status = idempotency.StatusCompleted
+	first.CompletedAt = now.Add(time.Second)
+	first.ExpiresAt = first.CompletedAt.Add(time.Hour)
+	require.NoError(t, store.Complete(ctx, first))
+	require.Equal(t, time.Hour, client.ttls["gochen:idempotency:key-1"])
+
+	existing, reserved, err = store.Reserve(ctx, pending("key-1", "cmd-3", now.Add(time.Minute)))
+	require.NoError(t, err)
+	require.False(t, reserved)
+	require.Equal(t, idempotency.StatusCompleted, existing.Status)
+	require.NoError(t, existing.Err())
+}
+
+func TestStore_TakeoverAndRelease(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewStore(newFakeClient(), Config{KeyPrefix: "test:"})
+	require.NoError(t, err)
+	now := time.Unix(1000, 0).UTC()
+
+	stale := pending("key-1", "cmd-1", now)
+	_, _, err = store.Reserve(ctx, stale)
+	require.NoError(t, err)
+
+	// 键仍在 Redis 中，但按调用方时钟已过期：接管。
+	fresh := pending("key-1", "cmd-2", now.Add(2*time.Minute))
+	_, reserved, err := store.Reserve(ctx, fresh)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	stale.Status = idempotency.StatusCompleted
+	require.True(t, errors.Is(store.Complete(ctx, stale), errors.Conflict))
+
+	// 释放他人的占用无效果。
+	require.NoError(t, store.Release(ctx, stale))
+	_, reserved, err = store.Reserve(ctx, pending("key-1", "cmd-3", now.Add(2*time.Minute)))
+	require.NoError(t, err)
+	require.False(t, reserved)
+
+	require.NoError(t, store.Release(ctx, fresh))
+	_, reserved, err = store.Reserve(ctx, pending("key-1", "cmd-3", now.Add(2*time.Minute)))
+	require.NoError(t, err)
+	require.True(t, reserved)
+}
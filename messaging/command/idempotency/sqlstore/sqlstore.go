@@ -0,0 +1,271 @@
+// Package sqlstore 提供基于共享数据库表的幂等记录存储（idempotency.IStore），适用于多实例部署。
+//
+// 占用依赖主键唯一约束：INSERT 成功即占用；主键冲突时仅当既有记录已过期才以条件 UPDATE 接管。
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"time"
+
+	"gochen/db"
+	"gochen/db/dialect"
+	"gochen/db/sql/sqlbuilder"
+	"gochen/errors"
+	"gochen/messaging/command/idempotency"
+)
+
+// DefaultTableName 是幂等记录表的默认表名。
+const DefaultTableName = "command_idempotency"
+
+const reserveAttempts = 3
+
+var tableNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// Store 是基于 SQL 表的幂等记录存储。
+//
+// 时间以毫秒时间戳存储，避免不同驱动对 DATETIME 的解析差异。
+type Store struct {
+	db        db.IDatabase
+	tableName string
+	dialect   dialect.Dialect
+}
+
+var _ idempotency.IStore = (*Store)(nil)
+
+// New 创建 SQL 幂等记录存储；tableName 为空时使用 DefaultTableName。
+func New(database db.IDatabase, tableName string) (*Store, error) {
+	if database == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "database cannot be nil")
+	}
+	if tableName == "" {
+		tableName = DefaultTableName
+	}
+	if !tableNamePattern.MatchString(tableName) {
+		return nil, errors.NewCode(errors.InvalidInput, "invalid idempotency table name").
+			WithContext("table_name", tableName)
+	}
+	return &Store{db: database, tableName: tableName, dialect: dialect.FromDatabase(database)}, nil
+}
+
+// CreateTable 创建幂等记录表。
+func (s *Store) CreateTable(ctx context.Context) error {
+	var query string
+	switch s.dialect.Name() {
+	case dialect.NameSQLite:
+		query = fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				idem_key TEXT PRIMARY KEY,
+				command_type TEXT NOT NULL,
+				command_id TEXT NOT NULL,
+				payload_hash TEXT NOT NULL DEFAULT '',
+				status TEXT NOT NULL,
+				error_code TEXT NOT NULL DEFAULT '',
+				error_message TEXT NOT NULL DEFAULT '',
+				created_at_ms INTEGER NOT NULL,
+				completed_at_ms INTEGER NOT NULL DEFAULT 0,
+				expires_at_ms INTEGER NOT NULL
+			)
+		`, s.tableName)
+	case dialect.NamePostgres:
+		query = fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				idem_key VARCHAR(255) PRIMARY KEY,
+				command_type VARCHAR(255) NOT NULL,
+				command_id VARCHAR(255) NOT NULL,
+				payload_hash VARCHAR(64) NOT NULL DEFAULT '',
+				status VARCHAR(32) NOT NULL,
+				error_code VARCHAR(64) NOT NULL DEFAULT '',
+				error_message TEXT NOT NULL DEFAULT '',
+				created_at_ms BIGINT NOT NULL,
+				completed_at_ms BIGINT NOT NULL DEFAULT 0,
+				expires_at_ms BIGINT NOT NULL
+			)
+		`, s.tableName)
+	default:
+		query = fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				idem_key VARCHAR(255) PRIMARY KEY,
+				command_type VARCHAR(255) NOT NULL,
+				command_id VARCHAR(255) NOT NULL,
+				payload_hash VARCHAR(64) NOT NULL DEFAULT '',
+				status VARCHAR(32) NOT NULL,
+				error_code VARCHAR(64) NOT NULL DEFAULT '',
+				error_message TEXT NOT NULL,
+				created_at_ms BIGINT NOT NULL,
+				completed_at_ms BIGINT NOT NULL DEFAULT 0,
+				expires_at_ms BIGINT NOT NULL
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`, s.tableName)
+	}
+	if _, err := s.db.Exec(ctx, query); err != nil {
+		return errors.NewCodeWithCause(errors.Database, "failed to create idempotency table", err).
+			WithContext("table_name", s.tableName)
+	}
+	return nil
+}
+
+// Reserve 原子占用幂等键。
+func (s *Store) Reserve(ctx context.Context, record *idempotency.Record) (*idempotency.Record, bool, error) {
+	if err := validateRecord(record); err != nil {
+		return nil, false, err
+	}
+	sq, err := sqlbuilder.New(s.db)
+	if err != nil {
+		return nil, false, errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+
+	var insertErr error
+	for range reserveAttempts {
+		_, insertErr = sq.InsertInto(s.tableName).
+			Columns("idem_key", "command_type", "command_id", "payload_hash", "status", "error_code", "error_message",
+				"created_at_ms", "completed_at_ms", "expires_at_ms").
+			Values(record.Key, record.CommandType, record.CommandID, record.PayloadHash, string(record.Status), string(record.ErrorCode), record.ErrorMessage,
+				millis(record.CreatedAt), millis(record.CompletedAt), millis(record.ExpiresAt)).
+			Exec(ctx)
+		if insertErr == nil {
+			return nil, true, nil
+		}
+
+		// 主键冲突：仅接管已过期的记录。
+		res, err := s.setRecord(sq.Update(s.tableName), record).
+			Where("idem_key = ? AND expires_at_ms <= ?", record.Key, millis(record.CreatedAt)).
+			Exec(ctx)
+		if err != nil {
+			return nil, false, errors.NewCodeWithCause(errors.Database, "idempotency store failed", err).
+				WithContext("idempotency_key", record.Key)
+		}
+		if affected, _ := res.RowsAffected(); affected == 1 {
+			return nil, true, nil
+		}
+
+		existing, found, err := s.load(ctx, sq, record.Key)
+		if err != nil {
+			return nil, false, err
+		}
+		if found {
+			return existing, false, nil
+		}
+		// 记录在 INSERT 与查询之间被删除（Release）：重试占用。
+	}
+	return nil, false, errors.NewCodeWithCause(errors.Database, "reserve idempotency key failed", insertErr).
+		WithContext("idempotency_key", record.Key)
+}
+
+// Complete 写入执行结果；占用已不属于 record.CommandID 时返回 Conflict。
+func (s *Store) Complete(ctx context.Context, record *idempotency.Record) error {
+	if err := validateRecord(record); err != nil {
+		return err
+	}
+	sq, err := sqlbuilder.New(s.db)
+	if err != nil {
+		return errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+	res, err := s.setRecord(sq.Update(s.tableName), record).
+		Where("idem_key = ? AND command_id = ?", record.Key, record.CommandID).
+		Exec(ctx)
+	if err != nil {
+		return errors.NewCodeWithCause(errors.Database, "idempotency store failed", err).
+			WithContext("idempotency_key", record.Key)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return idempotency.NewReservationLostError(record)
+	}
+	return nil
+}
+
+// Release 删除由 record.CommandID 持有的 pending 占用。
+func (s *Store) Release(ctx context.Context, record *idempotency.Record) error {
+	if err := validateRecord(record); err != nil {
+		return err
+	}
+	sq, err := sqlbuilder.New(s.db)
+	if err != nil {
+		return errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+	_, err = sq.DeleteFrom(s.tableName).
+		Where("idem_key = ? AND command_id = ? AND status = ?", record.Key, record.CommandID, string(idempotency.StatusPending)).
+		Exec(ctx)
+	if err != nil {
+		return errors.NewCodeWithCause(errors.Database, "idempotency store failed", err).
+			WithContext("idempotency_key", record.Key)
+	}
+	return nil
+}
+
+// DeleteExpired 删除 now 之前过期的记录，返回删除条数；建议由定时任务调用。
+func (s *Store) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	sq, err := sqlbuilder.New(s.db)
+	if err != nil {
+		return 0, errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+	res, err := sq.DeleteFrom(s.tableName).Where("expires_at_ms <= ?", millis(now)).Exec(ctx)
+	if err != nil {
+		return 0, errors.NewCodeWithCause(errors.Database, "idempotency store failed", err)
+	}
+	affected, _ := res.RowsAffected()
+	return affected, nil
+}
+
+func (s *Store) setRecord(b sqlbuilder.IUpdateBuilder, record *idempotency.Record) sqlbuilder.IUpdateBuilder {
+	return b.Set("command_type", record.CommandType).
+		Set("command_id", record.CommandID).
+		Set("payload_hash", record.PayloadHash).
+		Set("status", string(record.Status)).
+		Set("error_code", string(record.ErrorCode)).
+		Set("error_message", record.ErrorMessage).
+		Set("created_at_ms", millis(record.CreatedAt)).
+		Set("completed_at_ms", millis(record.CompletedAt)).
+		Set("expires_at_ms", millis(record.ExpiresAt))
+}
+
+func (s *Store) load(ctx context.Context, sq sqlbuilder.ISql, key string) (*idempotency.Record, bool, error) {
+	var (
+		record                                idempotency.Record
+		status, errorCode                     string
+		createdAtMs, completedAtMs, expiresMs int64
+	)
+	err := sq.Select("idem_key", "command_type", "command_id", "payload_hash", "status", "error_code", "error_message",
+		"created_at_ms", "completed_at_ms", "expires_at_ms").
+		From(s.tableName).
+		Where("idem_key = ?", key).
+		QueryRow(ctx).
+		Scan(&record.Key, &record.CommandType, &record.CommandID, &record.PayloadHash, &status, &errorCode, &record.ErrorMessage,
+			&createdAtMs, &completedAtMs, &expiresMs)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.NewCodeWithCause(errors.Database, "idempotency store failed", err).
+			WithContext("idempotency_key", key)
+	}
+	record.Status = idempotency.Status(status)
+	record.ErrorCode = errors.ErrorCode(errorCode)
+	record.CreatedAt = fromMillis(createdAtMs)
+	record.CompletedAt = fromMillis(completedAtMs)
+	record.ExpiresAt = fromMillis(expiresMs)
+	return &record, true, nil
+}
+
+func validateRecord(record *idempotency.Record) error {
+	if record == nil || record.Key == "" {
+		return errors.NewCode(errors.InvalidInput, "idempotency record key cannot be empty")
+	}
+	return nil
+}
+
+func millis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func fromMillis(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms).UTC()
+}
@@ -0,0 +1,120 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"gochen/db"
+	basicdb "gochen/db/sql/stdsql"
+	"gochen/errors"
+	"gochen/messaging/command/idempotency"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	database, err := basicdb.New(db.DBConfig{Driver: "sqlite", Database: ":memory:", MaxOpenConns: 1, MaxIdleConns: 1})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = database.Close() })
+
+	store, err := New(database, "")
+	require.NoError(t, err)
+	require.NoError(t, store.CreateTable(context.Background()))
+	return store
+}
+
+func pending(key, commandID string, now time.Time) *idempotency.Record {
+	return &idempotency.Record{
+		Key:         key,
+		CommandType: "Deposit",
+		CommandID:   commandID,
+		Status:      idempotency.StatusPending,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Minute),
+	}
+}
+
+func TestNew_RejectsInvalidTableName(t *testing.T) {
+	database, err := basicdb.New(db.DBConfig{Driver: "sqlite", Database: ":memory:", MaxOpenConns: 1, MaxIdleConns: 1})
+	require.NoError(t, err)
+	defer database.Close()
+
+	_, err = New(database, "idem; DROP TABLE x")
+	require.True(t, errors.Is(err, errors.InvalidInput))
+}
+
+func TestStore_ReserveCompleteReplay(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+	now := time.UnixMilli(1_700_000_000_000).UTC()
+
+	first := pending("key-1", "cmd-1", now)
+	existing, reserved, err := store.Reserve(ctx, first)
+	require.NoError(t, err)
+	require.True(t, reserved)
+	require.Nil(t, existing)
+
+	existing, reserved, err = store.Reserve(ctx, pending("key-1", "cmd-2", now))
+	require.NoError(t, err)
+	require.False(t, reserved)
+	require.Equal(t, idempotency.StatusPending, existing.Status)
+	require.Equal(t, "cmd-1", existing.CommandID)
+
+	first.SetResult(errors.NewCode(errors.Validation, "insufficient funds"))
+	first.Status = idempotency.StatusCompleted
+	first.CompletedAt = now.Add(time.Second)
+	first.ExpiresAt = now.Add(time.Hour)
+	require.NoError(t, store.Complete(ctx, first))
+
+	existing, reserved, err = store.Reserve(ctx, pending("key-1", "cmd-3", now.Add(30*time.Minute)))
+	require.NoError(t, err)
+	require.False(t, reserved)
+	require.Equal(t, *first, *existing)
+	require.True(t, errors.Is(existing.Err(), errors.Validation))
+}
+
+func TestStore_ExpiredRecordIsTakenOver(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+	now := time.UnixMilli(1_700_000_000_000).UTC()
+
+	stale := pending("key-1", "cmd-1", now)
+	_, reserved, err := store.Reserve(ctx, stale)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	_, reserved, err = store.Reserve(ctx, pending("key-1", "cmd-2", now.Add(2*time.Minute)))
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	// 原占用者的结果不能覆盖新占用。
+	stale.Status = idempotency.StatusCompleted
+	err = store.Complete(ctx, stale)
+	require.True(t, errors.Is(err, errors.Conflict))
+}
+
+func TestStore_ReleaseOnlyOwnPendingReservation(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+	now := time.UnixMilli(1_700_000_000_000).UTC()
+
+	_, _, err := store.Reserve(ctx, pending("key-1", "cmd-1", now))
+	require.NoError(t, err)
+
+	require.NoError(t, store.Release(ctx, pending("key-1", "cmd-2", now)))
+	_, reserved, err := store.Reserve(ctx, pending("key-1", "cmd-3", now))
+	require.NoError(t, err)
+	require.False(t, reserved)
+
+	require.NoError(t, store.Release(ctx, pending("key-1", "cmd-1", now)))
+	_, reserved, err = store.Reserve(ctx, pending("key-1", "cmd-3", now))
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	deleted, err := store.DeleteExpired(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+}
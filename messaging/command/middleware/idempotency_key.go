@@ -0,0 +1,250 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"gochen/clock"
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/ident/uuid"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/messaging/command/idempotency"
+)
+
+const (
+	// MetadataIdempotencyKey 是携带幂等键的命令元数据键，对应 HTTP 请求头 Idempotency-Key。
+	MetadataIdempotencyKey = "idempotency_key"
+
+	defaultKeyedIdempotencyTTL = 24 * time.Hour
+	defaultPendingTTL          = 5 * time.Minute
+)
+
+// KeyedIdempotencyConfig 幂等键中间件配置。
+type KeyedIdempotencyConfig struct {
+	// Store 幂等记录存储（必填）；多实例部署使用 idempotency/sqlstore 或 idempotency/redis。
+	Store idempotency.IStore
+
+	// MetadataKey 读取幂等键的元数据键（默认：MetadataIdempotencyKey）。
+	MetadataKey string
+
+	// TTL 执行结果的保留时长，期间同一键的重复投递直接返回首次结果（默认：24小时）。
+	TTL time.Duration
+
+	// PendingTTL 执行中占用的租期（默认：5分钟）；实例崩溃后键最多被占用 PendingTTL，应大于命令的最长执行时间。
+	PendingTTL time.Duration
+
+	// ReplayErrors 判断执行错误是否作为结果保存并重放（默认：DefaultReplayErrors）。
+	// 返回 false 的错误会释放占用，使客户端重试时重新执行。
+	ReplayErrors func(error) bool
+
+	// Clock 可选：时间来源，便于测试稳定控制时间推进。
+	Clock clock.IClock
+
+	Logger logging.ILogger
+}
+
+// DefaultReplayErrors 仅重放确定性的业务拒绝（参数/校验错误、不存在、重复、无权限）；
+// 超时、依赖故障、冲突等暂时性错误不保存，重试会重新执行命令（冲突可能在状态变化后消失）。
+func DefaultReplayErrors(err error) bool {
+	switch errors.Code(err) {
+	case errors.InvalidInput, errors.Validation, errors.NotFound,
+		errors.Duplicate, errors.Unauthorized, errors.Forbidden:
+		return true
+	default:
+		return false
+	}
+}
+
+// KeyedIdempotencyMiddleware 幂等键中间件。
+//
+// 对携带幂等键元数据的命令：首次投递占用该键并执行，执行结果（成功或可重放的错误）写入 Store；
+// 保留期内同一键的重复投递不再执行处理器，直接返回首次结果，防止客户端重试造成重复扣款/入账。
+//
+// 特性：
+//   - 幂等键按 ctx 中的租户与操作人隔离，不同调用方使用相同的键互不影响。
+//   - 同一键仍在执行中时返回 Conflict，客户端应稍后重试。
+//   - 同一键用于不同命令类型或不同载荷时返回 Conflict。
+//   - 未携带幂等键的命令与非命令消息直接放行。
+//
+// 与 IdempotencyMiddleware（按命令 ID、进程内）互补：幂等键由调用方生成，跨实例、跨重启生效。
+type KeyedIdempotencyMiddleware struct {
+	store        idempotency.IStore
+	metadataKey  string
+	ttl          time.Duration
+	pendingTTL   time.Duration
+	replayErrors func(error) bool
+	clock        clock.IClock
+	logger       logging.ILogger
+}
+
+// NewKeyedIdempotencyMiddleware 创建幂等键中间件。
+func NewKeyedIdempotencyMiddleware(config *KeyedIdempotencyConfig) (*KeyedIdempotencyMiddleware, error) {
+	if config == nil || config.Store == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "idempotency store cannot be nil")
+	}
+	m := &KeyedIdempotencyMiddleware{
+		store:        config.Store,
+		metadataKey:  config.MetadataKey,
+		ttl:          config.TTL,
+		pendingTTL:   config.PendingTTL,
+		replayErrors: config.ReplayErrors,
+		clock:        config.Clock,
+		logger:       config.Logger,
+	}
+	if m.metadataKey == "" {
+		m.metadataKey = MetadataIdempotencyKey
+	}
+	if m.ttl <= 0 {
+		m.ttl = defaultKeyedIdempotencyTTL
+	}
+	if m.pendingTTL <= 0 {
+		m.pendingTTL = defaultPendingTTL
+	}
+	if m.replayErrors == nil {
+		m.replayErrors = DefaultReplayErrors
+	}
+	if m.clock == nil {
+		m.clock = clock.NewRealClock()
+	}
+	if m.logger == nil {
+		m.logger = logging.ComponentLogger("messaging.command.idempotency")
+	}
+	return m, nil
+}
+
+// Handle 按幂等键占用、执行并保存结果；重复投递返回首次结果。
+func (m *KeyedIdempotencyMiddleware) Handle(ctx context.Context, message messaging.IMessage, next messaging.HandlerFunc) error {
+	if message.GetKind() != messaging.KindCommand {
+		return next(ctx, message)
+	}
+	key, ok := message.GetMetadata().Get(m.metadataKey)
+	if !ok || key == "" {
+		return next(ctx, message)
+	}
+
+	commandID := message.GetID()
+	if commandID == "" {
+		// 占用归属以命令 ID 区分，缺失时生成一次性 ID。
+		id, err := uuid.New()
+		if err != nil {
+			return errors.Wrap(err, errors.Internal, "generate idempotency owner id failed")
+		}
+		commandID = id
+	}
+
+	payloadHash, err := hashPayload(message)
+	if err != nil {
+		return err
+	}
+
+	now := m.clock.Now()
+	record := &idempotency.Record{
+		Key:         IdempotencyStoreKey(ctx, key),
+		CommandType: message.GetType(),
+		CommandID:   commandID,
+		PayloadHash: payloadHash,
+		Status:      idempotency.StatusPending,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(m.pendingTTL),
+	}
+	existing, reserved, err := m.store.Reserve(ctx, record)
+	if err != nil {
+		return errors.Wrap(err, errors.Dependency, "reserve idempotency key failed").
+			WithContext("idempotency_key", key)
+	}
+	if !reserved {
+		return m.replay(existing, record)
+	}
+
+	// 释放与保存不受调用方取消影响，避免结果已产生但记录丢失。
+	storeCtx := context.WithoutCancel(ctx)
+	completed := false
+	defer func() {
+		if !completed {
+			// 处理器 panic：释放占用后继续向上传播。
+			m.release(storeCtx, record)
+		}
+	}()
+
+	execErr := next(ctx, message)
+	if execErr != nil && !m.replayErrors(execErr) {
+		completed = true
+		m.release(storeCtx, record)
+		return execErr
+	}
+
+	completed = true
+	record.SetResult(execErr)
+	record.Status = idempotency.StatusCompleted
+	record.CompletedAt = m.clock.Now()
+	record.ExpiresAt = record.CompletedAt.Add(m.ttl)
+	if err := m.store.Complete(storeCtx, record); err != nil {
+		// 命令已执行：保存失败只影响后续重放，不改变本次结果。
+		m.logger.Warn(ctx, "save idempotency result failed",
+			logging.String("idempotency_key", key),
+			logging.String("command_id", commandID),
+			logging.Error(err))
+	}
+	return execErr
+}
+
+// replay 处理已被占用的键：执行中或类型不匹配返回 Conflict，已完成则重放首次结果。
+func (m *KeyedIdempotencyMiddleware) replay(existing, record *idempotency.Record) error {
+	if existing == nil {
+		return errors.NewCode(errors.Internal, "idempotency store returned no existing record").
+			WithContext("idempotency_key", record.Key)
+	}
+	if existing.CommandType != record.CommandType {
+		return errors.NewCode(errors.Conflict, "idempotency key already used by another command type").
+			WithContext("idempotency_key", record.Key).
+			WithContext("command_type", record.CommandType).
+			WithContext("original_command_type", existing.CommandType)
+	}
+	if existing.PayloadHash != "" && existing.PayloadHash != record.PayloadHash {
+		return errors.NewCode(errors.Conflict, "idempotency key already used with a different payload").
+			WithContext("idempotency_key", record.Key).
+			WithContext("command_type", record.CommandType)
+	}
+	if existing.Status != idempotency.StatusCompleted {
+		return errors.NewCode(errors.Conflict, "command with the same idempotency key is in progress").
+			WithContext("idempotency_key", record.Key).
+			WithContext("original_command_id", existing.CommandID)
+	}
+	return existing.Err()
+}
+
+// IdempotencyStoreKey 返回客户端幂等键在 Store 中的记录键：对 ctx 中的租户、操作人与 key 做 SHA-256，
+// 避免不同调用方的键相互命中，同时把存储键长度固定为 64 个十六进制字符。
+func IdempotencyStoreKey(ctx context.Context, key string) string {
+	sum := sha256.Sum256([]byte(contextx.TenantID(ctx) + "\x00" + contextx.Operator(ctx) + "\x00" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashPayload 返回命令载荷 JSON 编码的 SHA-256 摘要。
+func hashPayload(message messaging.IMessage) (string, error) {
+	data, err := json.Marshal(message.GetPayload())
+	if err != nil {
+		return "", errors.Wrap(err, errors.InvalidInput, "encode command payload for idempotency failed").
+			WithContext("command_type", message.GetType())
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (m *KeyedIdempotencyMiddleware) release(ctx context.Context, record *idempotency.Record) {
+	if err := m.store.Release(ctx, record); err != nil {
+		m.logger.Warn(ctx, "release idempotency key failed",
+			logging.String("idempotency_key", record.Key),
+			logging.String("command_id", record.CommandID),
+			logging.Error(err))
+	}
+}
+
+func (m *KeyedIdempotencyMiddleware) Name() string {
+	return "CommandIdempotencyKey"
+}
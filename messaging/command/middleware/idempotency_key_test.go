@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gochen/clock"
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/messaging"
+	"gochen/messaging/command"
+	"gochen/messaging/command/idempotency"
+)
+
+func newKeyedTestMiddleware(t *testing.T, store idempotency.IStore, clk clock.IClock) *KeyedIdempotencyMiddleware {
+	t.Helper()
+	m, err := NewKeyedIdempotencyMiddleware(&KeyedIdempotencyConfig{Store: store, Clock: clk, TTL: time.Hour, PendingTTL: time.Minute})
+	require.NoError(t, err)
+	return m
+}
+
+func depositCommand(id, key string) *command.Command {
+	return command.NewCommand(id, "Deposit", "acc-1", "Account", nil).WithMetadata(MetadataIdempotencyKey, key)
+}
+
+func TestKeyedIdempotencyMiddleware_RequiresStore(t *testing.T) {
+	_, err := NewKeyedIdempotencyMiddleware(&KeyedIdempotencyConfig{})
+	assert.True(t, errors.Is(err, errors.InvalidInput))
+}
+
+func TestKeyedIdempotencyMiddleware_DuplicateReturnsOriginalResult(t *testing.T) {
+	store := idempotency.NewMemoryStore()
+	m := newKeyedTestMiddleware(t, store, clock.NewManualClock(time.Unix(1000, 0)))
+
+	calls := 0
+	next := func(context.Context, messaging.IMessage) error {
+		calls++
+		return nil
+	}
+
+	// 客户端重试：命令 ID 不同，幂等键相同。
+	assert.NoError(t, m.Handle(context.Background(), depositCommand("cmd-1", "key-1"), next))
+	assert.NoError(t, m.Handle(context.Background(), depositCommand("cmd-2", "key-1"), next))
+	assert.Equal(t, 1, calls)
+
+	record, ok := store.Get(IdempotencyStoreKey(context.Background(), "key-1"))
+	require.True(t, ok)
+	assert.Equal(t, idempotency.StatusCompleted, record.Status)
+	assert.Equal(t, "cmd-1", record.CommandID)
+
+	assert.NoError(t, m.Handle(context.Background(), depositCommand("cmd-3", "key-2"), next))
+	assert.Equal(t, 2, calls)
+}
+
+func TestKeyedIdempotencyMiddleware_ReplaysBusinessRejection(t *testing.T) {
+	m := newKeyedTestMiddleware(t, idempotency.NewMemoryStore(), clock.NewManualClock(time.Unix(1000, 0)))
+
+	calls := 0
+	next := func(context.Context, messaging.IMessage) error {
+		calls++
+		return errors.NewCode(errors.Validation, "insufficient funds")
+	}
+
+	err := m.Handle(context.Background(), depositCommand("cmd-1", "key-1"), next)
+	assert.True(t, errors.Is(err, errors.Validation))
+
+	err = m.Handle(context.Background(), depositCommand("cmd-2", "key-1"), next)
+	assert.True(t, errors.Is(err, errors.Validation))
+	assert.Contains(t, err.Error(), "insufficient funds")
+	assert.Equal(t, 1, calls)
+}
+
+func TestKeyedIdempotencyMiddleware_TransientErrorAllowsRetry(t *testing.T) {
+	store := idempotency.NewMemoryStore()
+	m := newKeyedTestMiddleware(t, store, clock.NewManualClock(time.Unix(1000, 0)))
+
+	calls := 0
+	next := func(context.Context, messaging.IMessage) error {
+		calls++
+		if calls == 1 {
+			return errors.NewCode(errors.Dependency, "ledger unavailable")
+		}
+		return nil
+	}
+
+	err := m.Handle(context.Background(), depositCommand("cmd-1", "key-1"), next)
+	assert.True(t, errors.Is(err, errors.Dependency))
+	_, ok := store.Get(IdempotencyStoreKey(context.Background(), "key-1"))
+	assert.False(t, ok)
+
+	assert.NoError(t, m.Handle(context.Background(), depositCommand("cmd-2", "key-1"), next))
+	assert.Equal(t, 2, calls)
+}
+
+func TestKeyedIdempotencyMiddleware_ConflictAllowsRetry(t *testing.T) {
+	store := idempotency.NewMemoryStore()
+	m := newKeyedTestMiddleware(t, store, clock.NewManualClock(time.Unix(1000, 0)))
+
+	calls := 0
+	next := func(context.Context, messaging.IMessage) error {
+		calls++
+		if calls == 1 {
+			return errors.NewCode(errors.Conflict, "account is being migrated")
+		}
+		return nil
+	}
+
+	err := m.Handle(context.Background(), depositCommand("cmd-1", "key-1"), next)
+	assert.True(t, errors.Is(err, errors.Conflict))
+	_, ok := store.Get(IdempotencyStoreKey(context.Background(), "key-1"))
+	assert.False(t, ok)
+
+	// 冲突不被记录，重试会再次执行处理器。
+	assert.NoError(t, m.Handle(context.Background(), depositCommand("cmd-2", "key-1"), next))
+	assert.Equal(t, 2, calls)
+}
+
+func TestKeyedIdempotencyMiddleware_InProgressAndTypeMismatch(t *testing.T) {
+	clk := clock.NewManualClock(time.Unix(1000, 0))
+	m := newKeyedTestMiddleware(t, idempotency.NewMemoryStore(), clk)
+
+	var nested error
+	err := m.Handle(context.Background(), depositCommand("cmd-1", "key-1"), func(ctx context.Context, _ messaging.IMessage) error {
+		nested = m.Handle(ctx, depositCommand("cmd-2", "key-1"), func(context.Context, messaging.IMessage) error {
+			t.Fatal("duplicate must not execute while the original is in progress")
+			return nil
+		})
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, errors.Is(nested, errors.Conflict))
+
+	withdraw := command.NewCommand("cmd-3", "Withdraw", "acc-1", "Account", nil).WithMetadata(MetadataIdempotencyKey, "key-1")
+	err = m.Handle(context.Background(), withdraw, func(context.Context, messaging.IMessage) error {
+		t.Fatal("command type mismatch must not execute")
+		return nil
+	})
+	assert.True(t, errors.Is(err, errors.Conflict))
+
+	// 结果保留期过后，同一键可再次执行。
+	clk.Advance(2 * time.Hour)
+	executed := false
+	assert.NoError(t, m.Handle(context.Background(), withdraw, func(context.Context, messaging.IMessage) error {
+		executed = true
+		return nil
+	}))
+	assert.True(t, executed)
+}
+
+func TestKeyedIdempotencyMiddleware_PassesThroughWithoutKey(t *testing.T) {
+	m := newKeyedTestMiddleware(t, idempotency.NewMemoryStore(), nil)
+
+	calls := 0
+	next := func(context.Context, messaging.IMessage) error {
+		calls++
+		return nil
+	}
+	cmd := command.NewCommand("cmd-1", "Deposit", "acc-1", "Account", nil)
+	assert.NoError(t, m.Handle(context.Background(), cmd, next))
+	assert.NoError(t, m.Handle(context.Background(), cmd, next))
+	assert.Equal(t, 2, calls)
+}
+
+// TestKeyedIdempotencyMiddleware_ScopesKeysAndComparesPayload 验证幂等键按租户/操作人隔离，且同一键携带不同载荷返回 Conflict。
+func TestKeyedIdempotencyMiddleware_ScopesKeysAndComparesPayload(t *testing.T) {
+	m := newKeyedTestMiddleware(t, idempotency.NewMemoryStore(), clock.NewManualClock(time.Unix(1000, 0)))
+	calls := 0
+	next := func(context.Context, messaging.IMessage) error {
+		calls++
+		return nil
+	}
+	alice, err := contextx.WithOperator(context.Background(), "alice")
+	require.NoError(t, err)
+	bob, err := contextx.WithOperator(context.Background(), "bob")
+	require.NoError(t, err)
+
+	deposit := func(id string, amount int) *command.Command {
+		return command.NewCommand(id, "Deposit", "acc-1", "Account", map[string]int{"amount": amount}).
+			WithMetadata(MetadataIdempotencyKey, "key-1")
+	}
+	require.NoError(t, m.Handle(alice, deposit("cmd-1", 10), next))
+	require.NoError(t, m.Handle(bob, deposit("cmd-2", 10), next), "another principal's key does not replay alice's result")
+	assert.Equal(t, 2, calls)
+
+	err = m.Handle(alice, deposit("cmd-3", 99), next)
+	assert.True(t, errors.Is(err, errors.Conflict), "same key with a different payload is rejected")
+	assert.Equal(t, 2, calls)
+}
@@ -30,6 +30,13 @@ func NewValidationMiddleware(validator validate.IValidator) *ValidationMiddlewar
 	}
 }
 
+// NewTagValidationMiddleware 创建按 payload 的 `validate` struct tag 校验命令的中间件（validate.TagValidator）。
+//
+// 校验失败返回 errors.Validation，"fields" 上下文中包含逐字段的 validate.FieldError。
+func NewTagValidationMiddleware() *ValidationMiddleware {
+	return NewValidationMiddleware(validate.TagValidator{})
+}
+
 // Handle 处理消息并执行业务处理逻辑。
 //
 // 说明：
@@ -67,8 +74,12 @@ func (m *ValidationMiddleware) Handle(ctx context.Context, message messaging.IMe
 
 	// 使用 validator 验证 Payload
 	if err := m.validator.Validate(messaging.PayloadValue(payload)); err != nil {
-		return errors.Wrap(err, errors.Validation, "command validation failed").
+		wrapped := errors.Wrap(err, errors.Validation, "command validation failed").
 			WithContext("command_type", cmd.GetCommandType())
+		if fields, ok := validate.FieldErrorsOf(err); ok {
+			wrapped = wrapped.WithContext("fields", []validate.FieldError(fields))
+		}
+		return wrapped
 	}
 
 	// 验证通过，继续执行
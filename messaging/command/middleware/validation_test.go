@@ -9,6 +9,7 @@ import (
 
 	"gochen/messaging"
 	"gochen/messaging/command"
+	"gochen/validate"
 )
 
 // mockValidator 模拟验证器
@@ -116,3 +117,37 @@ func TestValidationMiddleware_Name(t *testing.T) {
 
 	assert.Equal(t, "CommandValidation", middleware.Name())
 }
+
+type createUserPayload struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email" validate:"required,email"`
+}
+
+// TestTagValidationMiddleware_FieldErrors 验证 struct tag 校验失败时返回逐字段错误且不执行后续处理器。
+func TestTagValidationMiddleware_FieldErrors(t *testing.T) {
+	middleware := NewTagValidationMiddleware()
+
+	nextCalled := false
+	next := func(ctx context.Context, msg messaging.IMessage) error {
+		nextCalled = true
+		return nil
+	}
+
+	cmd := command.NewCommand("cmd-1", "CreateUser", "1", "User", &createUserPayload{Email: "bad"})
+	err := middleware.Handle(context.Background(), cmd, next)
+
+	assert.True(t, errors.Is(err, errors.Validation))
+	assert.False(t, nextCalled)
+	fields, ok := validate.FieldErrorsOf(err)
+	assert.True(t, ok)
+	assert.Len(t, fields, 2)
+
+	var appErr *errors.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "CreateUser", appErr.Details()["command_type"])
+	assert.NotNil(t, appErr.Details()["fields"])
+
+	cmd = command.NewCommand("cmd-2", "CreateUser", "1", "User", &createUserPayload{Name: "n", Email: "n@example.com"})
+	assert.NoError(t, middleware.Handle(context.Background(), cmd, next))
+	assert.True(t, nextCalled)
+}
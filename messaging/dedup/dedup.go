@@ -0,0 +1,114 @@
+// Package dedup 提供消费侧去重存储：按幂等生产者键（messaging.ProducerKey）记录已处理的消息，
+// 使“发布成功但未来得及确认”导致的重复投递在下游只处理一次。
+package dedup
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"gochen/clock"
+	"gochen/errors"
+)
+
+// DefaultTTL 是去重记录的默认保留时长，应覆盖上游最长的重投窗口（如 Outbox 重试与 broker 重投）。
+const DefaultTTL = 24 * time.Hour
+
+// DefaultLease 是处理中占用的默认租约时长，应大于单条消息的最长处理时间。
+const DefaultLease = 5 * time.Minute
+
+// IStore 去重存储抽象。
+//
+// Reserve 原子地以“处理中”状态占用键并持有租约：首次占用返回 true，键已完成或租约未过期返回 false；
+// 处理成功后调用 Complete 把记录标记为已完成（保留 TTL）；处理失败时调用 Release 释放占用。
+// 占用方在 Complete/Release 前崩溃时，租约过期后重投的消息可再次占用，消息不会因此丢失。
+type IStore interface {
+	Reserve(ctx context.Context, key string) (bool, error)
+	Complete(ctx context.Context, key string) error
+	Release(ctx context.Context, key string) error
+}
+
+// MemoryStore 是进程内去重存储（并发安全），适用于单实例或测试；多实例请使用 SQLStore。
+type MemoryStore struct {
+	mutex    sync.Mutex
+	ttl      time.Duration
+	lease    time.Duration
+	clock    clock.IClock
+	keys     map[string]time.Time
+	reserves int
+}
+
+// MemoryStoreConfig 定义内存去重存储配置。
+type MemoryStoreConfig struct {
+	// TTL 记录保留时长（默认 DefaultTTL），过期后同一键可再次占用。
+	TTL time.Duration
+
+	// Lease 处理中占用的租约时长（默认 DefaultLease），过期未完成的键可再次占用。
+	Lease time.Duration
+
+	Clock clock.IClock
+}
+
+// NewMemoryStore 创建内存去重存储；cfg 为 nil 时使用默认配置。
+func NewMemoryStore(cfg *MemoryStoreConfig) *MemoryStore {
+	config := MemoryStoreConfig{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.TTL <= 0 {
+		config.TTL = DefaultTTL
+	}
+	if config.Lease <= 0 {
+		config.Lease = DefaultLease
+	}
+	if config.Clock == nil {
+		config.Clock = clock.NewRealClock()
+	}
+	return &MemoryStore{ttl: config.TTL, lease: config.Lease, clock: config.Clock, keys: make(map[string]time.Time)}
+}
+
+// memorySweepEvery 每占用多少次清理一次过期记录，避免内存无界增长。
+const memorySweepEvery = 1024
+
+// Reserve 实现 IStore。
+func (s *MemoryStore) Reserve(_ context.Context, key string) (bool, error) {
+	if strings.TrimSpace(key) == "" {
+		return false, errors.NewCode(errors.InvalidInput, "dedup key cannot be empty")
+	}
+	now := s.clock.Now()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.reserves++
+	if s.reserves%memorySweepEvery == 0 {
+		for k, expiresAt := range s.keys {
+			if !now.Before(expiresAt) {
+				delete(s.keys, k)
+			}
+		}
+	}
+	if expiresAt, exists := s.keys[key]; exists && now.Before(expiresAt) {
+		return false, nil
+	}
+	s.keys[key] = now.Add(s.lease)
+	return true, nil
+}
+
+// Complete 实现 IStore：把占用延长为完成记录的 TTL。
+func (s *MemoryStore) Complete(_ context.Context, key string) error {
+	now := s.clock.Now()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.keys[key] = now.Add(s.ttl)
+	return nil
+}
+
+// Release 实现 IStore。
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.keys, key)
+	return nil
+}
+
+var _ IStore = (*MemoryStore)(nil)
@@ -0,0 +1,93 @@
+package dedup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"gochen/clock"
+	"gochen/db"
+	basicdb "gochen/db/sql/stdsql"
+	"gochen/errors"
+)
+
+func newSQLiteStore(t *testing.T, clk clock.IClock) *SQLStore {
+	t.Helper()
+	database, err := basicdb.New(db.DBConfig{Driver: "sqlite", Database: ":memory:", MaxOpenConns: 1, MaxIdleConns: 1})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = database.Close() })
+	store, err := NewSQLStore(database, &SQLStoreConfig{TTL: time.Hour, Clock: clk})
+	require.NoError(t, err)
+	return store
+}
+
+// TestStores_ReserveReleaseExpire 验证内存与 SQL 存储的占用、完成、释放与过期接管语义一致。
+func TestStores_ReserveReleaseExpire(t *testing.T) {
+	stores := map[string]func(clk clock.IClock) IStore{
+		"memory": func(clk clock.IClock) IStore { return NewMemoryStore(&MemoryStoreConfig{TTL: time.Hour, Clock: clk}) },
+		"sql":    func(clk clock.IClock) IStore { return newSQLiteStore(t, clk) },
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			clk := clock.NewManualClock(time.Now())
+			store := newStore(clk)
+			reserved, err := store.Reserve(ctx, "outbox:1")
+			require.NoError(t, err)
+			require.True(t, reserved)
+
+			reserved, err = store.Reserve(ctx, "outbox:1")
+			require.NoError(t, err)
+			require.False(t, reserved, "duplicate key must not be reserved twice")
+
+			require.NoError(t, store.Release(ctx, "outbox:1"))
+			reserved, err = store.Reserve(ctx, "outbox:1")
+			require.NoError(t, err)
+			require.True(t, reserved, "released key can be reserved again")
+
+			// 占用方未完成即崩溃：租约过期后重投的消息可再次占用。
+			clk.Advance(DefaultLease + time.Second)
+			reserved, err = store.Reserve(ctx, "outbox:1")
+			require.NoError(t, err)
+			require.True(t, reserved, "expired pending lease can be taken over")
+
+			require.NoError(t, store.Complete(ctx, "outbox:1"))
+			clk.Advance(DefaultLease + time.Second)
+			reserved, err = store.Reserve(ctx, "outbox:1")
+			require.NoError(t, err)
+			require.False(t, reserved, "completed key is kept for the ttl")
+
+			clk.Advance(2 * time.Hour)
+			reserved, err = store.Reserve(ctx, "outbox:1")
+			require.NoError(t, err)
+			require.True(t, reserved, "expired key can be taken over")
+
+			_, err = store.Reserve(ctx, " ")
+			require.True(t, errors.Is(err, errors.InvalidInput))
+		})
+	}
+}
+
+// TestSQLStore_Purge 验证 Purge 只删除超过 TTL 的记录。
+func TestSQLStore_Purge(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewManualClock(time.Now())
+	store := newSQLiteStore(t, clk)
+
+	_, err := store.Reserve(ctx, "old")
+	require.NoError(t, err)
+	clk.Advance(2 * time.Hour)
+	_, err = store.Reserve(ctx, "new")
+	require.NoError(t, err)
+
+	purged, err := store.Purge(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+
+	reserved, err := store.Reserve(ctx, "new")
+	require.NoError(t, err)
+	require.False(t, reserved)
+}
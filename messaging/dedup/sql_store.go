@@ -0,0 +1,223 @@
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gochen/clock"
+	"gochen/db"
+	"gochen/db/dialect"
+	"gochen/db/sql/safeident"
+	"gochen/db/sql/sqlbuilder"
+	"gochen/errors"
+)
+
+// DefaultTableName 是 SQLStore 的默认表名。
+const DefaultTableName = "message_dedup"
+
+const (
+	statusPending   = "pending"
+	statusCompleted = "completed"
+)
+
+// SQLStoreConfig 定义 SQL 去重存储配置。
+type SQLStoreConfig struct {
+	// TableName 表名（默认：message_dedup）。
+	TableName string
+
+	// TTL 完成记录的保留时长（默认 DefaultTTL）：过期的键可再次占用，Purge 清理过期记录。
+	TTL time.Duration
+
+	// Lease 处理中占用的租约时长（默认 DefaultLease）：占用方未 Complete/Release 即崩溃时，过期后可再次占用。
+	Lease time.Duration
+
+	Clock clock.IClock
+}
+
+// SQLStore 是基于共享数据库表的 IStore 实现，多实例共享同一张表，占用依赖主键唯一约束。
+//
+// 表结构（自动创建）：
+//   - dedup_key VARCHAR(191) PRIMARY KEY：幂等生产者键
+//   - status VARCHAR(16)：pending（处理中）/ completed（已完成）
+//   - expires_at_ms BIGINT：过期时间（毫秒）；pending 为租约到期时间，completed 为 TTL 到期时间
+type SQLStore struct {
+	db        db.IDatabase
+	dialect   dialect.Dialect
+	tableName string
+	ttl       time.Duration
+	lease     time.Duration
+	clock     clock.IClock
+
+	ensureMu sync.Mutex
+	ensured  bool
+}
+
+// NewSQLStore 创建 SQL 去重存储。
+func NewSQLStore(database db.IDatabase, cfg *SQLStoreConfig) (*SQLStore, error) {
+	if database == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "database cannot be nil")
+	}
+	config := SQLStoreConfig{}
+	if cfg != nil {
+		config = *cfg
+	}
+	config.TableName = strings.TrimSpace(config.TableName)
+	if config.TableName == "" {
+		config.TableName = DefaultTableName
+	}
+	if !safeident.IsSafeIdentifier(config.TableName) {
+		return nil, errors.NewCode(errors.InvalidInput, fmt.Sprintf("invalid table name: %s", config.TableName))
+	}
+	if config.TTL <= 0 {
+		config.TTL = DefaultTTL
+	}
+	if config.Lease <= 0 {
+		config.Lease = DefaultLease
+	}
+	if config.Clock == nil {
+		config.Clock = clock.NewRealClock()
+	}
+	return &SQLStore{db: database, dialect: dialect.FromDatabase(database), tableName: config.TableName, ttl: config.TTL, lease: config.Lease, clock: config.Clock}, nil
+}
+
+// ensureTable 首次使用时创建表与过期时间索引；失败不缓存，下次调用重试。
+func (s *SQLStore) ensureTable(ctx context.Context) error {
+	s.ensureMu.Lock()
+	defer s.ensureMu.Unlock()
+	if s.ensured {
+		return nil
+	}
+	var inlineIndex string
+	if name := s.dialect.Name(); name != dialect.NameSQLite && name != dialect.NamePostgres {
+		// MySQL 不支持 CREATE INDEX IF NOT EXISTS，索引随建表语句创建。
+		inlineIndex = fmt.Sprintf(",\nINDEX idx_%s_expires_at (expires_at_ms)", s.tableName)
+	}
+	_, err := s.db.Exec(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+dedup_key VARCHAR(191) PRIMARY KEY,
+status VARCHAR(16) NOT NULL,
+expires_at_ms BIGINT NOT NULL%s
+)`, s.tableName, inlineIndex))
+	if err == nil && inlineIndex == "" {
+		_, err = s.db.Exec(ctx, fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_expires_at ON %s (expires_at_ms)", s.tableName, s.tableName))
+	}
+	if err != nil {
+		return errors.Wrap(err, errors.Database, "ensure dedup table failed").WithContext("table", s.tableName)
+	}
+	s.ensured = true
+	return nil
+}
+
+func (s *SQLStore) builder() (sqlbuilder.ISql, error) {
+	sq, err := sqlbuilder.New(s.db)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+	return sq, nil
+}
+
+// Reserve 实现 IStore：插入 pending 记录即占用；键已存在且未过期（已完成或租约有效）返回 false，
+// 已过期则接管后重新占用。
+func (s *SQLStore) Reserve(ctx context.Context, key string) (bool, error) {
+	if strings.TrimSpace(key) == "" {
+		return false, errors.NewCode(errors.InvalidInput, "dedup key cannot be empty")
+	}
+	if err := s.ensureTable(ctx); err != nil {
+		return false, err
+	}
+	sq, err := s.builder()
+	if err != nil {
+		return false, err
+	}
+	nowMs := s.clock.Now().UnixMilli()
+	insert := func() error {
+		_, err := sq.InsertInto(s.tableName).
+			Columns("dedup_key", "status", "expires_at_ms").
+			Values(key, statusPending, nowMs+s.lease.Milliseconds()).
+			Exec(ctx)
+		return err
+	}
+	insertErr := insert()
+	if insertErr == nil {
+		return true, nil
+	}
+
+	// 主键冲突的错误文本因驱动而异，回查区分“已占用”与其他数据库错误。
+	var expiresAtMs int64
+	if err := sq.Select("expires_at_ms").From(s.tableName).Where("dedup_key = ?", key).QueryRow(ctx).Scan(&expiresAtMs); err != nil {
+		return false, errors.Wrap(insertErr, errors.Database, "reserve dedup key failed").WithContext("dedup_key", key)
+	}
+	if nowMs < expiresAtMs {
+		return false, nil
+	}
+	// 过期记录：仅删除读到的那一行，并发接管时只有一方能重新插入成功。
+	if _, err := sq.DeleteFrom(s.tableName).
+		Where("dedup_key = ?", key).
+		Where("expires_at_ms = ?", expiresAtMs).
+		Exec(ctx); err != nil {
+		return false, errors.Wrap(err, errors.Database, "delete expired dedup key failed").WithContext("dedup_key", key)
+	}
+	if err := insert(); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+// Complete 实现 IStore：把记录标记为 completed 并按 TTL 保留。
+func (s *SQLStore) Complete(ctx context.Context, key string) error {
+	if err := s.ensureTable(ctx); err != nil {
+		return err
+	}
+	sq, err := s.builder()
+	if err != nil {
+		return err
+	}
+	expiresAtMs := s.clock.Now().Add(s.ttl).UnixMilli()
+	if _, err := sq.Update(s.tableName).
+		Set("status", statusCompleted).
+		Set("expires_at_ms", expiresAtMs).
+		Where("dedup_key = ?", key).
+		Exec(ctx); err != nil {
+		return errors.Wrap(err, errors.Database, "complete dedup key failed").WithContext("dedup_key", key)
+	}
+	return nil
+}
+
+// Release 实现 IStore。
+func (s *SQLStore) Release(ctx context.Context, key string) error {
+	if err := s.ensureTable(ctx); err != nil {
+		return err
+	}
+	sq, err := s.builder()
+	if err != nil {
+		return err
+	}
+	if _, err := sq.DeleteFrom(s.tableName).Where("dedup_key = ?", key).Exec(ctx); err != nil {
+		return errors.Wrap(err, errors.Database, "release dedup key failed").WithContext("dedup_key", key)
+	}
+	return nil
+}
+
+// Purge 删除已过期的记录（超过 TTL 的完成记录与租约过期的占用），返回删除条数；建议由定时任务周期调用。
+func (s *SQLStore) Purge(ctx context.Context) (int64, error) {
+	if err := s.ensureTable(ctx); err != nil {
+		return 0, err
+	}
+	sq, err := s.builder()
+	if err != nil {
+		return 0, err
+	}
+	res, err := sq.DeleteFrom(s.tableName).Where("expires_at_ms <= ?", s.clock.Now().UnixMilli()).Exec(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, errors.Database, "purge dedup keys failed")
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, errors.Database, "read purged rows failed")
+	}
+	return affected, nil
+}
+
+var _ IStore = (*SQLStore)(nil)
@@ -0,0 +1,143 @@
+package messaging
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	gerrors "gochen/errors"
+)
+
+// IGroupSubscriber 抽象消费组订阅能力。
+//
+// 同一消息类型下，以同一组名订阅的处理器竞争消费（每条消息只投递给组内一个成员）；
+// 不同组、以及普通 Subscribe 的订阅各自收到一份。跨实例的竞争消费需要 Transport 实现该接口
+// （例如 RabbitMQ 为每个组声明一个共享队列）；未实现时 MessageBus 在进程内按组轮询分发。
+type IGroupSubscriber interface {
+	SubscribeGroup(ctx context.Context, messageType, group string, handler IMessageHandler) (UnsubscribeFunc, error)
+}
+
+// SubscribeGroup 以消费组方式订阅消息类型，并返回幂等的取消订阅函数。
+//
+// Transport 实现 IGroupSubscriber 时委托给 Transport（跨实例竞争消费）；否则在本总线内对组成员轮询分发。
+func (bus *MessageBus) SubscribeGroup(ctx context.Context, messageType, group string, handler IMessageHandler) (UnsubscribeFunc, error) {
+	if bus == nil || bus.transport == nil {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "message bus transport cannot be nil")
+	}
+	if ctx == nil {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "ctx is nil")
+	}
+	if messageType == "" {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "message type cannot be empty")
+	}
+	if strings.TrimSpace(group) == "" {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "consumer group cannot be empty")
+	}
+	if handler == nil {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "handler cannot be nil")
+	}
+
+	wrapped := &handlerWithErrorHook{inner: handler, bus: bus}
+	var unsub UnsubscribeFunc
+	var err error
+	if grouped, ok := bus.transport.(IGroupSubscriber); ok {
+		unsub, err = grouped.SubscribeGroup(ctx, messageType, group, wrapped)
+	} else {
+		unsub, err = bus.subscribeLocalGroup(ctx, messageType, group, wrapped)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var once sync.Once
+	return func(unsubCtx context.Context) error {
+		if unsubCtx == nil {
+			return gerrors.NewCode(gerrors.InvalidInput, "ctx is nil")
+		}
+		var err error
+		once.Do(func() {
+			err = unsub(unsubCtx)
+		})
+		return err
+	}, nil
+}
+
+// subscribeLocalGroup 为 (messageType, group) 在 Transport 上注册一个分发器，组成员在分发器内轮询。
+func (bus *MessageBus) subscribeLocalGroup(ctx context.Context, messageType, group string, handler IMessageHandler) (UnsubscribeFunc, error) {
+	key := messageType + "\x00" + group
+
+	bus.groupMutex.Lock()
+	defer bus.groupMutex.Unlock()
+	dispatcher, exists := bus.groups[key]
+	if !exists {
+		dispatcher = &groupDispatcher{group: group}
+		unsub, err := bus.transport.Subscribe(ctx, messageType, dispatcher)
+		if err != nil {
+			return nil, err
+		}
+		dispatcher.unsubscribe = unsub
+		if bus.groups == nil {
+			bus.groups = make(map[string]*groupDispatcher)
+		}
+		bus.groups[key] = dispatcher
+	}
+	dispatcher.add(handler)
+
+	return func(unsubCtx context.Context) error {
+		bus.groupMutex.Lock()
+		defer bus.groupMutex.Unlock()
+		if dispatcher.remove(handler) > 0 {
+			return nil
+		}
+		if bus.groups[key] == dispatcher {
+			delete(bus.groups, key)
+		}
+		return dispatcher.unsubscribe(unsubCtx)
+	}, nil
+}
+
+// groupDispatcher 把一条消息交给组内的下一个成员（轮询）。
+type groupDispatcher struct {
+	group       string
+	mutex       sync.RWMutex
+	members     []IMessageHandler
+	next        atomic.Uint64
+	unsubscribe UnsubscribeFunc
+}
+
+func (d *groupDispatcher) add(handler IMessageHandler) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.members = append(d.members, handler)
+}
+
+// remove 移除成员并返回剩余成员数。
+func (d *groupDispatcher) remove(handler IMessageHandler) int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for i, member := range d.members {
+		if member == handler {
+			d.members = append(d.members[:i:i], d.members[i+1:]...)
+			break
+		}
+	}
+	return len(d.members)
+}
+
+// Handle 实现 IMessageHandler；组内无成员时丢弃消息。
+func (d *groupDispatcher) Handle(ctx context.Context, message IMessage) error {
+	d.mutex.RLock()
+	if len(d.members) == 0 {
+		d.mutex.RUnlock()
+		return nil
+	}
+	member := d.members[(d.next.Add(1)-1)%uint64(len(d.members))]
+	d.mutex.RUnlock()
+	return member.Handle(ctx, message)
+}
+
+// Type 返回处理器类型。
+func (d *groupDispatcher) Type() string { return "group:" + d.group }
+
+var _ IGroupSubscriber = (*MessageBus)(nil)
@@ -0,0 +1,60 @@
+package messaging_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/messaging"
+	synctransport "gochen/messaging/transport/direct"
+)
+
+func TestMessageBus_SubscribeGroupLocalRoundRobin(t *testing.T) {
+	ctx := context.Background()
+	tpt := synctransport.NewSyncTransport()
+	require.NoError(t, tpt.Start(ctx))
+	t.Cleanup(func() { _ = tpt.Stop(context.Background()) })
+	bus := messaging.NewMessageBus(tpt)
+
+	var calls []string
+	unsubA, err := bus.SubscribeGroup(ctx, "OrderPlaced", "projections", &recordingHandler{id: "projections-a", calls: &calls})
+	require.NoError(t, err)
+	_, err = bus.SubscribeGroup(ctx, "OrderPlaced", "projections", &recordingHandler{id: "projections-b", calls: &calls})
+	require.NoError(t, err)
+	_, err = bus.SubscribeGroup(ctx, "OrderPlaced", "audit", &recordingHandler{id: "audit", calls: &calls})
+	require.NoError(t, err)
+	_, err = bus.Subscribe(ctx, "OrderPlaced", &recordingHandler{id: "plain", calls: &calls})
+	require.NoError(t, err)
+
+	publish := func(n int) {
+		for i := range n {
+			require.NoError(t, bus.Publish(ctx, messaging.NewMessage(fmt.Sprintf("e%d", i), messaging.KindEvent, "OrderPlaced", nil)))
+		}
+	}
+	publish(4)
+	counts := map[string]int{}
+	for _, id := range calls {
+		counts[id]++
+	}
+	require.Equal(t, map[string]int{"projections-a": 2, "projections-b": 2, "audit": 4, "plain": 4}, counts)
+
+	// 组成员退订后，剩余成员接管全部消息。
+	require.NoError(t, unsubA(ctx))
+	require.NoError(t, unsubA(ctx))
+	calls = nil
+	publish(2)
+	require.ElementsMatch(t, []string{"projections-b", "projections-b", "audit", "audit", "plain", "plain"}, calls)
+	require.Equal(t, 3, tpt.Stats().HandlerCount)
+}
+
+func TestMessageBus_SubscribeGroupValidation(t *testing.T) {
+	bus := messaging.NewMessageBus(synctransport.NewSyncTransport())
+	var calls []string
+	_, err := bus.SubscribeGroup(context.Background(), "OrderPlaced", " ", &recordingHandler{calls: &calls})
+	require.True(t, errors.Is(err, errors.InvalidInput))
+	_, err = bus.SubscribeGroup(context.Background(), "OrderPlaced", "projections", nil)
+	require.True(t, errors.Is(err, errors.InvalidInput))
+}
@@ -20,6 +20,8 @@ const (
 	KindEvent MessageKind = "event"
 	// KindQuery 查询消息。
 	KindQuery MessageKind = "query"
+	// KindReply 请求/应答中的应答消息（Type 为请求方的应答主题）。
+	KindReply MessageKind = "reply"
 )
 
 // IMessage 消息接口（信封层最小公共视图）。
@@ -0,0 +1,225 @@
+package middleware
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/observe"
+	"gochen/policy/circuit"
+)
+
+const (
+	// MetricCircuitState 是熔断器状态仪表盘（0=closed，1=open，2=half_open），标签 circuit 为熔断键。
+	MetricCircuitState = "messaging_circuit_state"
+	// MetricCircuitRejected 是熔断器打开期间被拒绝的消息计数，标签 circuit 为熔断键。
+	MetricCircuitRejected = "messaging_circuit_rejected_total"
+)
+
+// CircuitKeyFunc 决定消息使用哪个熔断器。
+type CircuitKeyFunc = KeyFunc
+
+// CircuitBreakerConfig 定义熔断中间件配置。
+type CircuitBreakerConfig struct {
+	// MaxFailures 连续失败多少次后打开（默认：5）。
+	MaxFailures int
+
+	// ResetTimeout 打开后多久进入半开并放行一次试探（默认：10s）。
+	ResetTimeout time.Duration
+
+	// Key 熔断键（默认 KeyByHandler）。
+	Key CircuitKeyFunc
+
+	// FailureIf 判断错误是否计入失败（默认：DefaultErrorClassifier 判定为暂时性的错误）。
+	// 校验失败等终止性错误说明消息本身有问题，不代表下游故障，默认不计入。
+	FailureIf func(err error) bool
+
+	// Metrics 可选：上报熔断状态与拒绝次数。
+	Metrics observe.IMetrics
+
+	Clock  clock.IClock
+	Logger logging.ILogger
+}
+
+// CircuitBreakerMiddleware 按处理器或消息类型维护熔断器，下游持续失败时快速拒绝，避免反复冲击故障的投影/命令处理器。
+//
+// 打开期间消息被拒绝并返回 errors.ServiceUnavailable（携带 circuit 上下文），
+// 外层可配合 RetryMiddleware 退避重试，或由 Transport/DLQ 收敛。
+type CircuitBreakerMiddleware struct {
+	config CircuitBreakerConfig
+
+	mu       sync.Mutex
+	breakers map[string]*circuitEntry
+}
+
+type circuitEntry struct {
+	breaker *circuit.Breaker
+	// reported 是最近一次上报的状态，用于只在状态变化时记录日志与指标。
+	reported circuit.State
+}
+
+var _ messaging.IMiddleware = (*CircuitBreakerMiddleware)(nil)
+
+// NewCircuitBreakerMiddleware 创建熔断中间件；cfg 为 nil 时使用默认配置。
+func NewCircuitBreakerMiddleware(cfg *CircuitBreakerConfig) *CircuitBreakerMiddleware {
+	config := CircuitBreakerConfig{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.Key == nil {
+		config.Key = KeyByHandler
+	}
+	if config.FailureIf == nil {
+		config.FailureIf = func(err error) bool { return DefaultErrorClassifier(err) == ErrorRetryable }
+	}
+	if config.Clock == nil {
+		config.Clock = clock.NewRealClock()
+	}
+	if config.Logger == nil {
+		config.Logger = logging.ComponentLogger("messaging.middleware.circuit")
+	}
+	return &CircuitBreakerMiddleware{config: config, breakers: make(map[string]*circuitEntry)}
+}
+
+// Handle 通过熔断器执行后续链路。
+func (m *CircuitBreakerMiddleware) Handle(ctx context.Context, message messaging.IMessage, next messaging.HandlerFunc) error {
+	if message == nil {
+		return next(ctx, message)
+	}
+	key := m.config.Key(ctx, message)
+	entry := m.entry(key)
+
+	executed := false
+	var handlerErr error
+	err := entry.breaker.Call(func() error {
+		executed = true
+		handlerErr = next(ctx, message)
+		if handlerErr != nil && m.config.FailureIf(handlerErr) {
+			return handlerErr
+		}
+		return nil
+	})
+	m.report(ctx, key, entry)
+
+	if !executed {
+		if m.config.Metrics != nil {
+			m.config.Metrics.Counter(MetricCircuitRejected, 1, map[string]string{"circuit": key})
+		}
+		if appErr, ok := errors.AsType[*errors.AppError](err); ok {
+			return appErr.WithContext("circuit", key).
+				WithContext("message_id", message.GetID())
+		}
+		return err
+	}
+	return handlerErr
+}
+
+// State 返回熔断键当前状态；尚未创建该熔断器时返回 false。
+func (m *CircuitBreakerMiddleware) State(key string) (circuit.State, bool) {
+	m.mu.Lock()
+	entry, ok := m.breakers[key]
+	m.mu.Unlock()
+	if !ok {
+		return circuit.StateClosed, false
+	}
+	return entry.breaker.State(), true
+}
+
+// States 返回所有熔断器的当前状态。
+func (m *CircuitBreakerMiddleware) States() map[string]circuit.State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	states := make(map[string]circuit.State, len(m.breakers))
+	for key, entry := range m.breakers {
+		states[key] = entry.breaker.State()
+	}
+	return states
+}
+
+// Keys 返回已创建的熔断键（按字典序）。
+func (m *CircuitBreakerMiddleware) Keys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]string, 0, len(m.breakers))
+	for key := range m.breakers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Reset 手动闭合指定熔断器；熔断器不存在时返回 false。
+func (m *CircuitBreakerMiddleware) Reset(ctx context.Context, key string) bool {
+	m.mu.Lock()
+	entry, ok := m.breakers[key]
+	m.mu.Unlock()
+	if !ok {
+		return false
+	}
+	entry.breaker.Reset()
+	m.report(ctx, key, entry)
+	return true
+}
+
+// ResetAll 手动闭合所有熔断器。
+func (m *CircuitBreakerMiddleware) ResetAll(ctx context.Context) {
+	for _, key := range m.Keys() {
+		m.Reset(ctx, key)
+	}
+}
+
+// Name 返回中间件名称。
+func (m *CircuitBreakerMiddleware) Name() string {
+	return "CircuitBreaker"
+}
+
+func (m *CircuitBreakerMiddleware) entry(key string) *circuitEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.breakers[key]
+	if !ok {
+		entry = &circuitEntry{
+			breaker: circuit.New(circuit.Config{
+				MaxFailures:  m.config.MaxFailures,
+				ResetTimeout: m.config.ResetTimeout,
+				Clock:        m.config.Clock,
+			}),
+			reported: circuit.StateClosed,
+		}
+		m.breakers[key] = entry
+		if m.config.Metrics != nil {
+			m.config.Metrics.Gauge(MetricCircuitState, float64(circuit.StateClosed), map[string]string{"circuit": key})
+		}
+	}
+	return entry
+}
+
+// report 在状态变化时记录日志并更新状态指标。
+func (m *CircuitBreakerMiddleware) report(ctx context.Context, key string, entry *circuitEntry) {
+	state := entry.breaker.State()
+	m.mu.Lock()
+	previous := entry.reported
+	entry.reported = state
+	m.mu.Unlock()
+	if previous == state {
+		return
+	}
+	fields := []logging.Field{
+		logging.String("circuit", key),
+		logging.String("from", previous.String()),
+		logging.String("to", state.String()),
+	}
+	if state == circuit.StateOpen {
+		m.config.Logger.Warn(ctx, "circuit opened", fields...)
+	} else {
+		m.config.Logger.Info(ctx, "circuit state changed", fields...)
+	}
+	if m.config.Metrics != nil {
+		m.config.Metrics.Gauge(MetricCircuitState, float64(state), map[string]string{"circuit": key})
+	}
+}
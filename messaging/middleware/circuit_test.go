@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/observe"
+	"gochen/policy/circuit"
+)
+
+// TestCircuitBreakerMiddleware_OpensAndRecovers 验证连续失败后熔断打开并拒绝消息，半开试探成功后恢复，状态通过指标暴露。
+func TestCircuitBreakerMiddleware_OpensAndRecovers(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewManualClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	metrics := observe.NewInMemoryMetrics()
+	mw := NewCircuitBreakerMiddleware(&CircuitBreakerConfig{
+		MaxFailures:  2,
+		ResetTimeout: time.Minute,
+		Metrics:      metrics,
+		Clock:        clk,
+		Logger:       logging.NewNoopLogger(),
+	})
+	handler := &flakyHandler{failures: 2, err: errors.NewCode(errors.Database, "projection db down")}
+	wrapped := WrapHandler(handler, mw)
+	msg := messaging.NewMessage("m1", messaging.KindEvent, "OrderPlaced", nil)
+	labels := map[string]string{"circuit": "flaky-handler"}
+
+	require.Error(t, wrapped.Handle(ctx, msg))
+	require.Error(t, wrapped.Handle(ctx, msg))
+	state, ok := mw.State("flaky-handler")
+	require.True(t, ok)
+	require.Equal(t, circuit.StateOpen, state)
+	require.Equal(t, float64(circuit.StateOpen), metrics.GaugeValue(MetricCircuitState, labels))
+
+	err := wrapped.Handle(ctx, msg)
+	require.True(t, errors.Is(err, errors.ServiceUnavailable))
+	require.Equal(t, 2, handler.calls)
+	require.Equal(t, int64(1), metrics.CounterValue(MetricCircuitRejected, labels))
+
+	clk.Advance(2 * time.Minute)
+	require.NoError(t, wrapped.Handle(ctx, msg))
+	require.Equal(t, map[string]circuit.State{"flaky-handler": circuit.StateClosed}, mw.States())
+	require.Equal(t, float64(circuit.StateClosed), metrics.GaugeValue(MetricCircuitState, labels))
+}
+
+// TestCircuitBreakerMiddleware_TerminalErrorsDoNotTrip 验证终止性错误不计入失败。
+func TestCircuitBreakerMiddleware_TerminalErrorsDoNotTrip(t *testing.T) {
+	mw := NewCircuitBreakerMiddleware(&CircuitBreakerConfig{MaxFailures: 1, Key: KeyByMessageType, Logger: logging.NewNoopLogger()})
+	msg := messaging.NewMessage("m1", messaging.KindCommand, "PlaceOrder", nil)
+	next := func(context.Context, messaging.IMessage) error {
+		return errors.NewCode(errors.Validation, "quantity must be positive")
+	}
+
+	for range 3 {
+		err := mw.Handle(context.Background(), msg, next)
+		require.True(t, errors.Is(err, errors.Validation))
+	}
+	state, ok := mw.State("PlaceOrder")
+	require.True(t, ok)
+	require.Equal(t, circuit.StateClosed, state)
+}
+
+// TestCircuitBreakerMiddleware_ManualReset 验证手动重置立即恢复放行。
+func TestCircuitBreakerMiddleware_ManualReset(t *testing.T) {
+	ctx := context.Background()
+	mw := NewCircuitBreakerMiddleware(&CircuitBreakerConfig{MaxFailures: 1, ResetTimeout: time.Hour, Logger: logging.NewNoopLogger()})
+	msg := messaging.NewMessage("m1", messaging.KindCommand, "ChargeCard", nil)
+	failing := func(context.Context, messaging.IMessage) error {
+		return errors.NewCode(errors.ServiceUnavailable, "gateway down")
+	}
+	calls := 0
+	succeeding := func(context.Context, messaging.IMessage) error {
+		calls++
+		return nil
+	}
+
+	require.Error(t, mw.Handle(ctx, msg, failing))
+	require.True(t, errors.Is(mw.Handle(ctx, msg, succeeding), errors.ServiceUnavailable))
+	require.Zero(t, calls)
+
+	require.False(t, mw.Reset(ctx, "unknown"))
+	require.True(t, mw.Reset(ctx, "ChargeCard"))
+	require.NoError(t, mw.Handle(ctx, msg, succeeding))
+	require.Equal(t, 1, calls)
+	require.Equal(t, []string{"ChargeCard"}, mw.Keys())
+}
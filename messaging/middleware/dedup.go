@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/messaging/dedup"
+)
+
+// DedupConfig 定义消费侧去重中间件配置。
+type DedupConfig struct {
+	// Store 去重存储（必填）：单实例可用 dedup.NewMemoryStore，多实例使用 dedup.NewSQLStore。
+	Store dedup.IStore
+
+	// Key 去重键（默认 KeyByHandlerAndProducerKey）：同一处理器对同一幂等生产者键只处理一次。
+	Key KeyFunc
+
+	// FailClosed 为 true 时去重存储出错即返回错误（交由上游重投）；默认放行并记录告警。
+	FailClosed bool
+
+	Logger logging.ILogger
+}
+
+// KeyByHandlerAndProducerKey 以“处理器 + 幂等生产者键”为去重键，订阅同一消息的多个处理器互不影响。
+func KeyByHandlerAndProducerKey(ctx context.Context, message messaging.IMessage) string {
+	return KeyByHandler(ctx, message) + ":" + messaging.ProducerKey(message)
+}
+
+// DedupMiddleware 按幂等生产者键丢弃重复投递（消费侧 exactly-once 效果）。
+//
+// 处理前以租约占用去重键：键已完成或正被处理的消息直接跳过；处理成功后标记完成，处理失败时释放占用，
+// 重投的消息可再次处理。处理中进程崩溃时，租约过期后重投的消息可再次占用。
+// 与 Outbox 发布器写入的 producer_key 配合，可消除“已发布但未标记 published”导致的重复事件。
+type DedupMiddleware struct {
+	config DedupConfig
+}
+
+var _ messaging.IMiddleware = (*DedupMiddleware)(nil)
+
+// NewDedupMiddleware 创建去重中间件；Store 为空时返回 InvalidInput。
+func NewDedupMiddleware(cfg *DedupConfig) (*DedupMiddleware, error) {
+	config := DedupConfig{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.Store == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "dedup store cannot be nil")
+	}
+	if config.Key == nil {
+		config.Key = KeyByHandlerAndProducerKey
+	}
+	if config.Logger == nil {
+		config.Logger = logging.ComponentLogger("messaging.middleware.dedup")
+	}
+	return &DedupMiddleware{config: config}, nil
+}
+
+// Handle 占用去重键后执行后续链路；重复消息不执行后续链路并返回 nil。
+func (m *DedupMiddleware) Handle(ctx context.Context, message messaging.IMessage, next messaging.HandlerFunc) error {
+	if message == nil {
+		return next(ctx, message)
+	}
+	key := strings.TrimSpace(m.config.Key(ctx, message))
+	if key == "" {
+		return next(ctx, message)
+	}
+
+	reserved, err := m.config.Store.Reserve(ctx, key)
+	if err != nil {
+		if m.config.FailClosed || ctx.Err() != nil {
+			return errors.Wrap(err, errors.Dependency, "dedup store unavailable").
+				WithContext("dedup_key", key).
+				WithContext("message_id", message.GetID())
+		}
+		m.config.Logger.Warn(ctx, "dedup store unavailable, message handled without dedup",
+			logging.Error(err),
+			logging.String("dedup_key", key),
+			logging.String("message_id", message.GetID()))
+		return next(ctx, message)
+	}
+	if !reserved {
+		m.config.Logger.Debug(ctx, "duplicate message skipped",
+			logging.String("dedup_key", key),
+			logging.String("message_id", message.GetID()),
+			logging.String("message_type", message.GetType()))
+		return nil
+	}
+
+	if err := next(ctx, message); err != nil {
+		if releaseErr := m.config.Store.Release(context.WithoutCancel(ctx), key); releaseErr != nil {
+			m.config.Logger.Error(ctx, "release dedup key failed, redelivery will be skipped",
+				logging.Error(releaseErr),
+				logging.String("dedup_key", key),
+				logging.String("message_id", message.GetID()))
+		}
+		return err
+	}
+	if err := m.config.Store.Complete(context.WithoutCancel(ctx), key); err != nil {
+		m.config.Logger.Error(ctx, "complete dedup key failed, redelivery after lease expiry will be handled again",
+			logging.Error(err),
+			logging.String("dedup_key", key),
+			logging.String("message_id", message.GetID()))
+	}
+	return nil
+}
+
+// Name 返回中间件名称。
+func (m *DedupMiddleware) Name() string {
+	return "Dedup"
+}
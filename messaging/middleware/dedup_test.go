@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/messaging/dedup"
+)
+
+// TestDedupMiddleware_SkipsRedeliveredProducerKey 验证同一生产者键的重投只处理一次，处理失败后允许重投再处理。
+func TestDedupMiddleware_SkipsRedeliveredProducerKey(t *testing.T) {
+	ctx := context.Background()
+	mw, err := NewDedupMiddleware(&DedupConfig{Store: dedup.NewMemoryStore(nil), Logger: logging.NewNoopLogger()})
+	require.NoError(t, err)
+
+	calls := 0
+	fail := true
+	next := func(context.Context, messaging.IMessage) error {
+		calls++
+		if fail {
+			return errors.NewCode(errors.Dependency, "projection store down")
+		}
+		return nil
+	}
+	// Outbox 重发时事件 ID 相同，生产者键也相同。
+	newDelivery := func() *messaging.Message {
+		msg := messaging.NewMessage("evt-1", messaging.KindEvent, "OrderPlaced", nil)
+		msg.SetMetadata(messaging.MetadataProducerKey, "outbox:42")
+		return msg
+	}
+
+	require.Error(t, mw.Handle(ctx, newDelivery(), next))
+	fail = false
+	require.NoError(t, mw.Handle(ctx, newDelivery(), next))
+	require.NoError(t, mw.Handle(ctx, newDelivery(), next))
+	require.Equal(t, 2, calls)
+
+	// 经 WrapHandler 挂载时按处理器隔离：另一个处理器仍会处理一次。
+	audit := &flakyHandler{}
+	handled := WrapHandler(audit, mw)
+	require.NoError(t, handled.Handle(ctx, newDelivery()))
+	require.NoError(t, handled.Handle(ctx, newDelivery()))
+	require.Equal(t, 1, audit.calls)
+
+	_, err = NewDedupMiddleware(nil)
+	require.True(t, errors.Is(err, errors.InvalidInput))
+	require.Equal(t, "Dedup", mw.Name())
+}
+
+// TestDedupMiddleware_CompletesKeyAfterSuccess 验证处理成功的键在租约过期后仍被视为已处理。
+func TestDedupMiddleware_CompletesKeyAfterSuccess(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewManualClock(time.Now())
+	store := dedup.NewMemoryStore(&dedup.MemoryStoreConfig{Clock: clk})
+	mw, err := NewDedupMiddleware(&DedupConfig{Store: store, Logger: logging.NewNoopLogger()})
+	require.NoError(t, err)
+
+	calls := 0
+	next := func(context.Context, messaging.IMessage) error {
+		calls++
+		return nil
+	}
+	msg := messaging.NewMessage("evt-1", messaging.KindEvent, "OrderPlaced", nil)
+	msg.SetMetadata(messaging.MetadataProducerKey, "outbox:42")
+
+	require.NoError(t, mw.Handle(ctx, msg, next))
+	clk.Advance(dedup.DefaultLease + time.Minute)
+	require.NoError(t, mw.Handle(ctx, msg, next))
+	require.Equal(t, 1, calls)
+}
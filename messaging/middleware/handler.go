@@ -0,0 +1,75 @@
+// Package middleware 提供通用的消息总线中间件（与消息类别无关；命令专用中间件见 messaging/command/middleware）。
+//
+// 消息总线中间件作用在 Publish 链路上：同步 Transport（direct）下 handler 在 Publish 内执行，
+// 中间件天然覆盖消费侧；异步 Transport 下 handler 失败不会回传给发布者，
+// 需要用 WrapHandler 把中间件挂到订阅的 handler 上，才能在消费侧生效。
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"gochen/contextx"
+	"gochen/messaging"
+)
+
+type handlerTypeKey struct{}
+
+// KeyFunc 从消息中提取中间件的隔离键（熔断器、限流桶等）。
+type KeyFunc func(ctx context.Context, message messaging.IMessage) string
+
+// KeyByHandler 按处理器隔离（经 WrapHandler 挂载时取 handler.Type()，否则退化为消息类型）。
+func KeyByHandler(ctx context.Context, message messaging.IMessage) string {
+	if handlerType, ok := handlerTypeFromContext(ctx); ok {
+		return handlerType
+	}
+	return message.GetType()
+}
+
+// KeyByMessageType 按消息类型（topic）隔离。
+func KeyByMessageType(_ context.Context, message messaging.IMessage) string {
+	return message.GetType()
+}
+
+// KeyByTenant 按租户隔离：优先取 ctx 中的租户，其次取消息元数据中的租户；都没有时返回空字符串。
+func KeyByTenant(ctx context.Context, message messaging.IMessage) string {
+	if tenantID := contextx.TenantID(ctx); tenantID != "" {
+		return tenantID
+	}
+	if tenantID, ok := message.GetMetadata().Get(contextx.MetadataTenantKey); ok {
+		return strings.TrimSpace(tenantID)
+	}
+	return ""
+}
+
+// WrapHandler 返回按顺序经过 middlewares 再调用 handler 的处理器（第一个中间件在最外层），用于消费侧。
+func WrapHandler(handler messaging.IMessageHandler, middlewares ...messaging.IMiddleware) messaging.IMessageHandler {
+	return &wrappedHandler{inner: handler, middlewares: append([]messaging.IMiddleware(nil), middlewares...)}
+}
+
+type wrappedHandler struct {
+	inner       messaging.IMessageHandler
+	middlewares []messaging.IMiddleware
+}
+
+func (h *wrappedHandler) Handle(ctx context.Context, message messaging.IMessage) error {
+	ctx = context.WithValue(ctx, handlerTypeKey{}, h.inner.Type())
+	next := h.inner.Handle
+	for i := len(h.middlewares) - 1; i >= 0; i-- {
+		mw, currentNext := h.middlewares[i], next
+		next = func(ctx context.Context, msg messaging.IMessage) error {
+			return mw.Handle(ctx, msg, currentNext)
+		}
+	}
+	return next(ctx, message)
+}
+
+func (h *wrappedHandler) Type() string {
+	return h.inner.Type()
+}
+
+// handlerTypeFromContext 返回 WrapHandler 记录的处理器类型。
+func handlerTypeFromContext(ctx context.Context) (string, bool) {
+	handlerType, ok := ctx.Value(handlerTypeKey{}).(string)
+	return handlerType, ok && handlerType != ""
+}
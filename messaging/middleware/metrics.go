@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/messaging"
+	"gochen/observe"
+)
+
+const (
+	// MetricMessagesHandled 是处理完成的消息计数，标签 handler、message_type、status（ok/error）。
+	MetricMessagesHandled = "messaging_messages_handled_total"
+	// MetricHandlerErrors 是处理失败计数，标签 handler、message_type、code（错误码）。
+	MetricHandlerErrors = "messaging_handler_errors_total"
+	// MetricHandlerDuration 是处理耗时直方图（毫秒），标签 handler、message_type。
+	MetricHandlerDuration = "messaging_handler_duration_ms"
+	// MetricHandlerInFlight 是正在处理的消息数仪表盘，标签 handler。
+	MetricHandlerInFlight = "messaging_handler_in_flight"
+)
+
+// MetricsMiddleware 按处理器与消息类型记录吞吐、耗时与错误率。
+//
+// 说明：
+// - 经 WrapHandler 挂载时 handler 标签取 handler.Type()（投影名、命令处理器名等），否则退化为消息类型；
+// - 错误率 = MetricHandlerErrors / MetricMessagesHandled；panic 计为 Internal 错误后继续向上传播；
+// - 指标经 observe.IMetrics 输出，由其实现对接 Prometheus 等后端。
+type MetricsMiddleware struct {
+	metrics  observe.IMetrics
+	clock    clock.IClock
+	inFlight *keyedCounter
+}
+
+var _ messaging.IMiddleware = (*MetricsMiddleware)(nil)
+
+// NewMetricsMiddleware 创建消息处理指标中间件；metrics 为 nil 时使用无操作实现。
+func NewMetricsMiddleware(metrics observe.IMetrics) *MetricsMiddleware {
+	if metrics == nil {
+		metrics = &observe.DefaultMetrics{}
+	}
+	return &MetricsMiddleware{metrics: metrics, clock: clock.NewRealClock(), inFlight: newKeyedCounter()}
+}
+
+// Handle 执行后续链路并记录指标。
+func (m *MetricsMiddleware) Handle(ctx context.Context, message messaging.IMessage, next messaging.HandlerFunc) (err error) {
+	messageType := ""
+	if message != nil {
+		messageType = message.GetType()
+	}
+	handlerType, ok := handlerTypeFromContext(ctx)
+	if !ok {
+		handlerType = messageType
+	}
+
+	inFlightLabels := map[string]string{"handler": handlerType}
+	m.metrics.Gauge(MetricHandlerInFlight, float64(m.inFlight.add(handlerType, 1)), inFlightLabels)
+	startedAt := m.clock.Now()
+	defer func() {
+		r := recover()
+		if r != nil {
+			err = messaging.PanicError("message handler panicked", r)
+		}
+		m.record(handlerType, messageType, m.clock.Now().Sub(startedAt).Seconds()*1000, err)
+		m.metrics.Gauge(MetricHandlerInFlight, float64(m.inFlight.add(handlerType, -1)), inFlightLabels)
+		if r != nil {
+			panic(r)
+		}
+	}()
+	return next(ctx, message)
+}
+
+func (m *MetricsMiddleware) record(handlerType, messageType string, elapsedMs float64, err error) {
+	labels := map[string]string{"handler": handlerType, "message_type": messageType}
+	m.metrics.Histogram(MetricHandlerDuration, elapsedMs, labels)
+
+	status := "ok"
+	if err != nil {
+		status = "error"
+		m.metrics.Counter(MetricHandlerErrors, 1, map[string]string{
+			"handler":      handlerType,
+			"message_type": messageType,
+			"code":         string(errors.Code(err)),
+		})
+	}
+	m.metrics.Counter(MetricMessagesHandled, 1, map[string]string{
+		"handler":      handlerType,
+		"message_type": messageType,
+		"status":       status,
+	})
+}
+
+// Name 返回中间件名称。
+func (m *MetricsMiddleware) Name() string {
+	return "Metrics"
+}
+
+// keyedCounter 维护按处理器区分的进行中计数。
+type keyedCounter struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+func newKeyedCounter() *keyedCounter {
+	return &keyedCounter{counts: make(map[string]int64)}
+}
+
+func (c *keyedCounter) add(key string, delta int64) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := c.counts[key] + delta
+	if n <= 0 {
+		delete(c.counts, key)
+		return 0
+	}
+	c.counts[key] = n
+	return n
+}
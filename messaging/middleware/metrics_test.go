@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/messaging"
+	"gochen/observe"
+)
+
+type funcHandler struct {
+	name string
+	fn   func(context.Context, messaging.IMessage) error
+}
+
+func (h funcHandler) Handle(ctx context.Context, msg messaging.IMessage) error { return h.fn(ctx, msg) }
+func (h funcHandler) Type() string                                             { return h.name }
+
+// TestMetricsMiddleware_RecordsThroughputLatencyAndErrorsPerHandler 验证按处理器与消息类型记录计数、耗时与错误码。
+func TestMetricsMiddleware_RecordsThroughputLatencyAndErrorsPerHandler(t *testing.T) {
+	metrics := observe.NewInMemoryMetrics()
+	mw := NewMetricsMiddleware(metrics)
+	fail := true
+	handler := WrapHandler(funcHandler{name: "orders-projection", fn: func(context.Context, messaging.IMessage) error {
+		if fail {
+			return errors.NewCode(errors.Database, "db down")
+		}
+		return nil
+	}}, mw)
+	msg := messaging.NewMessage("m1", messaging.KindEvent, "OrderPlaced", nil)
+
+	require.Error(t, handler.Handle(context.Background(), msg))
+	fail = false
+	require.NoError(t, handler.Handle(context.Background(), msg))
+	require.NoError(t, handler.Handle(context.Background(), msg))
+
+	labels := map[string]string{"handler": "orders-projection", "message_type": "OrderPlaced"}
+	require.Equal(t, int64(2), metrics.CounterValue(MetricMessagesHandled, map[string]string{"handler": "orders-projection", "message_type": "OrderPlaced", "status": "ok"}))
+	require.Equal(t, int64(1), metrics.CounterValue(MetricMessagesHandled, map[string]string{"handler": "orders-projection", "message_type": "OrderPlaced", "status": "error"}))
+	require.Equal(t, int64(1), metrics.CounterValue(MetricHandlerErrors, map[string]string{"handler": "orders-projection", "message_type": "OrderPlaced", "code": string(errors.Database)}))
+	require.Len(t, metrics.HistogramValues(MetricHandlerDuration, labels), 3)
+	require.Equal(t, float64(0), metrics.GaugeValue(MetricHandlerInFlight, map[string]string{"handler": "orders-projection"}))
+}
+
+// TestMetricsMiddleware_CountsPanicAsInternalAndRepanics 验证 panic 计为 Internal 错误后继续传播（交给外层 Recovery）。
+func TestMetricsMiddleware_CountsPanicAsInternalAndRepanics(t *testing.T) {
+	metrics := observe.NewInMemoryMetrics()
+	handler := WrapHandler(panickingHandler{}, NewMetricsMiddleware(metrics))
+	msg := messaging.NewMessage("m1", messaging.KindEvent, "OrderPlaced", nil)
+
+	require.Panics(t, func() { _ = handler.Handle(context.Background(), msg) })
+	require.Equal(t, int64(1), metrics.CounterValue(MetricHandlerErrors, map[string]string{"handler": "orders-projection", "message_type": "OrderPlaced", "code": string(errors.Internal)}))
+
+	err := WrapHandler(panickingHandler{}, NewRecoveryMiddleware(nil), NewMetricsMiddleware(metrics)).Handle(context.Background(), msg)
+	require.True(t, messaging.IsPanicError(err))
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/policy/ratelimit"
+)
+
+// DefaultRateLimitPollInterval 是 MaxWait > 0 时重新尝试取令牌的默认间隔。
+const DefaultRateLimitPollInterval = 50 * time.Millisecond
+
+// RateLimitConfig 定义消息限流中间件配置。
+type RateLimitConfig struct {
+	// Config 是进程内令牌桶配置（持续速率 RequestsPerSecond、突发容量 BurstSize）；设置 Limiter 时忽略。
+	ratelimit.Config
+
+	// Key 限流键（默认 KeyByMessageType；按租户限流使用 KeyByTenant）。键为空的消息共享 "anonymous" 桶。
+	Key KeyFunc
+
+	// Scope 可选：限流键前缀，多个中间件共享同一分布式 Limiter 时用于区分桶。
+	Scope string
+
+	// Limiter 可选：自定义限流后端（如 policy/ratelimit/redis，多实例共享限额）；为空时使用进程内令牌桶。
+	Limiter ratelimit.ILimiter
+
+	// MaxWait 大于 0 时，令牌不足的消息最多等待该时长（按 PollInterval 重试）再拒绝，用于平滑消费而非直接失败。
+	MaxWait time.Duration
+
+	// PollInterval 等待期间的重试间隔（默认 DefaultRateLimitPollInterval）。
+	PollInterval time.Duration
+
+	// FailClosed 为 true 时限流后端出错即拒绝消息；默认放行并记录告警。
+	FailClosed bool
+
+	Logger logging.ILogger
+}
+
+// RateLimitMiddleware 按消息类型或租户做令牌桶限流，保护下游处理器免受突发流量冲击。
+//
+// 超出限额的消息返回 errors.TooManyRequests（可重试），外层可配合 RetryMiddleware 退避后重新处理。
+type RateLimitMiddleware struct {
+	config  RateLimitConfig
+	limiter ratelimit.ILimiter
+	scope   string
+}
+
+var _ messaging.IMiddleware = (*RateLimitMiddleware)(nil)
+
+// NewRateLimitMiddleware 创建消息限流中间件；cfg 为 nil 时使用默认配置。
+func NewRateLimitMiddleware(cfg *RateLimitConfig) *RateLimitMiddleware {
+	config := RateLimitConfig{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.Key == nil {
+		config.Key = KeyByMessageType
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultRateLimitPollInterval
+	}
+	if config.Clock == nil {
+		config.Clock = clock.NewRealClock()
+	}
+	if config.Logger == nil {
+		config.Logger = logging.ComponentLogger("messaging.middleware.ratelimit")
+	}
+	limiter := config.Limiter
+	if limiter == nil {
+		limiter = ratelimit.New(config.Config)
+	}
+	scope := strings.TrimSpace(config.Scope)
+	if scope != "" {
+		scope += ":"
+	}
+	return &RateLimitMiddleware{config: config, limiter: limiter, scope: scope}
+}
+
+// Handle 取得令牌后执行后续链路；超出限额时返回 errors.TooManyRequests。
+func (m *RateLimitMiddleware) Handle(ctx context.Context, message messaging.IMessage, next messaging.HandlerFunc) error {
+	if message == nil {
+		return next(ctx, message)
+	}
+	key := strings.TrimSpace(m.config.Key(ctx, message))
+	if key == "" {
+		key = "anonymous"
+	}
+	key = m.scope + key
+
+	allowed, err := m.take(ctx, key)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		if m.config.FailClosed {
+			return errors.Wrap(err, errors.ServiceUnavailable, "rate limiter unavailable").
+				WithContext("rate_limit_key", key)
+		}
+		m.config.Logger.Warn(ctx, "rate limiter unavailable, message allowed",
+			logging.Error(err),
+			logging.String("rate_limit_key", key),
+			logging.String("message_type", message.GetType()))
+		return next(ctx, message)
+	}
+	if !allowed {
+		return errors.NewCode(errors.TooManyRequests, "message rate limit exceeded").
+			WithContext("rate_limit_key", key).
+			WithContext("message_type", message.GetType())
+	}
+	return next(ctx, message)
+}
+
+// take 取令牌；MaxWait > 0 时在等待窗口内按 PollInterval 重试。
+func (m *RateLimitMiddleware) take(ctx context.Context, key string) (bool, error) {
+	allowed, err := m.limiter.Take(ctx, key)
+	if err != nil || allowed || m.config.MaxWait <= 0 {
+		return allowed, err
+	}
+	deadline := m.config.Clock.Now().Add(m.config.MaxWait)
+	for {
+		remaining := deadline.Sub(m.config.Clock.Now())
+		if remaining <= 0 {
+			return false, nil
+		}
+		timer := m.config.Clock.NewTimer(min(m.config.PollInterval, remaining))
+		select {
+		case <-timer.C():
+		case <-ctx.Done():
+			timer.Stop()
+			return false, ctx.Err()
+		}
+		if allowed, err = m.limiter.Take(ctx, key); err != nil || allowed {
+			return allowed, err
+		}
+	}
+}
+
+// Name 返回中间件名称。
+func (m *RateLimitMiddleware) Name() string { return "RateLimit" }
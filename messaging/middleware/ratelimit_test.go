@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/clock"
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/policy/ratelimit"
+)
+
+// TestRateLimitMiddleware_PerMessageType 验证按消息类型隔离令牌桶，超额返回 TooManyRequests，补充后恢复。
+func TestRateLimitMiddleware_PerMessageType(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewManualClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	mw := NewRateLimitMiddleware(&RateLimitConfig{
+		Config: ratelimit.Config{RequestsPerSecond: 1, BurstSize: 2, Clock: clk},
+		Logger: logging.NewNoopLogger(),
+	})
+	calls := 0
+	next := func(context.Context, messaging.IMessage) error {
+		calls++
+		return nil
+	}
+	placed := messaging.NewMessage("m1", messaging.KindEvent, "OrderPlaced", nil)
+	shipped := messaging.NewMessage("m2", messaging.KindEvent, "OrderShipped", nil)
+
+	require.NoError(t, mw.Handle(ctx, placed, next))
+	require.NoError(t, mw.Handle(ctx, placed, next))
+	err := mw.Handle(ctx, placed, next)
+	require.True(t, errors.Is(err, errors.TooManyRequests))
+	require.NoError(t, mw.Handle(ctx, shipped, next))
+
+	clk.Advance(time.Second)
+	require.NoError(t, mw.Handle(ctx, placed, next))
+	require.Equal(t, 4, calls)
+	require.Equal(t, "RateLimit", mw.Name())
+}
+
+// TestRateLimitMiddleware_PerTenant 验证按租户限流时优先取 ctx 中的租户，其次取消息元数据。
+func TestRateLimitMiddleware_PerTenant(t *testing.T) {
+	limiter := &recordingLimiter{allowed: true}
+	mw := NewRateLimitMiddleware(&RateLimitConfig{Key: KeyByTenant, Scope: "commands", Limiter: limiter})
+	next := func(context.Context, messaging.IMessage) error { return nil }
+
+	msg := messaging.NewMessage("m1", messaging.KindCommand, "PlaceOrder", nil)
+	msg.GetMetadata().Set(contextx.MetadataTenantKey, "t-meta")
+	require.NoError(t, mw.Handle(context.Background(), msg, next))
+	tenantCtx, err := contextx.WithTenantID(context.Background(), "t-ctx")
+	require.NoError(t, err)
+	require.NoError(t, mw.Handle(tenantCtx, msg, next))
+	require.NoError(t, mw.Handle(context.Background(), messaging.NewMessage("m2", messaging.KindCommand, "PlaceOrder", nil), next))
+	require.Equal(t, []string{"commands:t-meta", "commands:t-ctx", "commands:anonymous"}, limiter.keys)
+}
+
+// TestRateLimitMiddleware_MaxWait 验证 MaxWait 内等待令牌补充后放行，而非直接拒绝。
+func TestRateLimitMiddleware_MaxWait(t *testing.T) {
+	mw := NewRateLimitMiddleware(&RateLimitConfig{
+		Config:       ratelimit.Config{RequestsPerSecond: 20, BurstSize: 1},
+		MaxWait:      time.Second,
+		PollInterval: 5 * time.Millisecond,
+	})
+	msg := messaging.NewMessage("m1", messaging.KindEvent, "OrderPlaced", nil)
+	next := func(context.Context, messaging.IMessage) error { return nil }
+
+	require.NoError(t, mw.Handle(context.Background(), msg, next))
+	require.NoError(t, mw.Handle(context.Background(), msg, next))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, mw.Handle(ctx, msg, next), context.Canceled)
+}
+
+// TestRateLimitMiddleware_LimiterError 验证限流后端故障时默认放行，FailClosed 时拒绝。
+func TestRateLimitMiddleware_LimiterError(t *testing.T) {
+	limiter := &recordingLimiter{err: errors.NewCode(errors.Dependency, "redis down")}
+	msg := messaging.NewMessage("m1", messaging.KindEvent, "OrderPlaced", nil)
+	calls := 0
+	next := func(context.Context, messaging.IMessage) error {
+		calls++
+		return nil
+	}
+
+	open := NewRateLimitMiddleware(&RateLimitConfig{Limiter: limiter, Logger: logging.NewNoopLogger()})
+	require.NoError(t, open.Handle(context.Background(), msg, next))
+	closed := NewRateLimitMiddleware(&RateLimitConfig{Limiter: limiter, FailClosed: true})
+	require.True(t, errors.Is(closed.Handle(context.Background(), msg, next), errors.ServiceUnavailable))
+	require.Equal(t, 1, calls)
+}
+
+type recordingLimiter struct {
+	keys    []string
+	allowed bool
+	err     error
+}
+
+func (l *recordingLimiter) Take(_ context.Context, key string) (bool, error) {
+	l.keys = append(l.keys, key)
+	return l.allowed, l.err
+}
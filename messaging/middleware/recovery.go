@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/messaging/deadletter"
+	"gochen/observe"
+)
+
+// MetricHandlerPanics 是处理器 panic 计数，标签 handler 为处理器类型、message_type 为消息类型。
+const MetricHandlerPanics = "messaging_handler_panics_total"
+
+// RecoveryConfig 定义 panic 恢复中间件配置。
+type RecoveryConfig struct {
+	// Metrics 可选：上报 panic 次数。
+	Metrics observe.IMetrics
+
+	// DeadLetter 可选：panic 的消息写入死信；写入成功后视为已处理（不再向上返回错误）。
+	DeadLetter deadletter.ISink
+
+	Clock  clock.IClock
+	Logger logging.ILogger
+}
+
+// RecoveryMiddleware 把后续链路中的 panic 转换为 errors.Internal 错误（见 messaging.PanicError），
+// 避免单个处理器 panic 带崩异步 Transport 的消费 goroutine。
+//
+// 与 RetryMiddleware 组合时：放在其内层，panic 转为错误后按退避重试；放在其外层，panic 不重试、直接收敛。
+type RecoveryMiddleware struct {
+	config RecoveryConfig
+}
+
+var _ messaging.IMiddleware = (*RecoveryMiddleware)(nil)
+
+// NewRecoveryMiddleware 创建 panic 恢复中间件；cfg 为 nil 时只恢复并记录日志。
+func NewRecoveryMiddleware(cfg *RecoveryConfig) *RecoveryMiddleware {
+	config := RecoveryConfig{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.Clock == nil {
+		config.Clock = clock.NewRealClock()
+	}
+	if config.Logger == nil {
+		config.Logger = logging.ComponentLogger("messaging.middleware.recovery")
+	}
+	return &RecoveryMiddleware{config: config}
+}
+
+// Handle 执行后续链路，并在 panic 时转换为错误、上报指标，按配置写入死信。
+func (m *RecoveryMiddleware) Handle(ctx context.Context, message messaging.IMessage, next messaging.HandlerFunc) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		err = m.recovered(ctx, message, r)
+	}()
+	return next(ctx, message)
+}
+
+func (m *RecoveryMiddleware) recovered(ctx context.Context, message messaging.IMessage, r any) error {
+	messageID, messageType := "", ""
+	if message != nil {
+		messageID, messageType = message.GetID(), message.GetType()
+	}
+	handlerType, ok := handlerTypeFromContext(ctx)
+	if !ok {
+		handlerType = messageType
+	}
+
+	panicErr := messaging.PanicError("message handler panicked", r).
+		WithContext("handler_type", handlerType).
+		WithContext("message_type", messageType).
+		WithContext("message_id", messageID)
+	if m.config.Metrics != nil {
+		m.config.Metrics.Counter(MetricHandlerPanics, 1, map[string]string{"handler": handlerType, "message_type": messageType})
+	}
+
+	fields := []logging.Field{
+		logging.Error(panicErr),
+		logging.String("handler", handlerType),
+		logging.String("message_id", messageID),
+		logging.String("message_type", messageType),
+	}
+	if m.config.DeadLetter == nil || message == nil {
+		m.config.Logger.Error(ctx, "message handler panicked", fields...)
+		return panicErr
+	}
+	if err := m.config.DeadLetter.Write(ctx, deadletter.Entry{
+		Message:     message,
+		HandlerType: handlerType,
+		Err:         panicErr,
+		OccurredAt:  m.config.Clock.Now(),
+	}); err != nil {
+		m.config.Logger.Error(ctx, "dead-letter write failed", append(fields, logging.Any("dead_letter_error", err))...)
+		return errors.Join(panicErr, errors.Wrap(err, errors.Dependency, "dead-letter write failed").
+			WithContext("message_id", messageID))
+	}
+	m.config.Logger.Error(ctx, "message handler panicked, moved to dead letter", fields...)
+	return nil
+}
+
+// Name 返回中间件名称。
+func (m *RecoveryMiddleware) Name() string {
+	return "Recovery"
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+	dlqmemory "gochen/messaging/deadletter/memory"
+	"gochen/observe"
+)
+
+type panickingHandler struct{}
+
+func (panickingHandler) Handle(context.Context, messaging.IMessage) error {
+	panic("nil projection row")
+}
+func (panickingHandler) Type() string { return "orders-projection" }
+
+// TestRecoveryMiddleware_ConvertsPanicAndRecordsMetrics 验证 panic 被转换为 Internal 错误并计入指标。
+func TestRecoveryMiddleware_ConvertsPanicAndRecordsMetrics(t *testing.T) {
+	metrics := observe.NewInMemoryMetrics()
+	mw := NewRecoveryMiddleware(&RecoveryConfig{Metrics: metrics, Logger: logging.NewNoopLogger()})
+	msg := messaging.NewMessage("m1", messaging.KindEvent, "OrderPlaced", nil)
+
+	err := WrapHandler(panickingHandler{}, mw).Handle(context.Background(), msg)
+	require.True(t, errors.Is(err, errors.Internal))
+	require.True(t, messaging.IsPanicError(err))
+	require.Equal(t, int64(1), metrics.CounterValue(MetricHandlerPanics, map[string]string{"handler": "orders-projection", "message_type": "OrderPlaced"}))
+
+	require.NoError(t, mw.Handle(context.Background(), msg, func(context.Context, messaging.IMessage) error { return nil }))
+	plain := errors.NewCode(errors.Database, "db down")
+	require.False(t, messaging.IsPanicError(mw.Handle(context.Background(), msg, func(context.Context, messaging.IMessage) error { return plain })))
+}
+
+// TestRecoveryMiddleware_DeadLettersPanickedMessage 验证配置死信后 panic 的消息写入死信并视为已处理。
+func TestRecoveryMiddleware_DeadLettersPanickedMessage(t *testing.T) {
+	sink := dlqmemory.NewSink()
+	mw := NewRecoveryMiddleware(&RecoveryConfig{DeadLetter: sink, Logger: logging.NewNoopLogger()})
+	msg := messaging.NewMessage("m1", messaging.KindEvent, "OrderPlaced", nil)
+
+	require.NoError(t, WrapHandler(panickingHandler{}, mw).Handle(context.Background(), msg))
+	entries := sink.Entries()
+	require.Len(t, entries, 1)
+	require.Equal(t, "orders-projection", entries[0].HandlerType)
+	require.Equal(t, "m1", entries[0].Message.GetID())
+	require.True(t, messaging.IsPanicError(entries[0].Err))
+}
@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/messaging/deadletter"
+	"gochen/policy/retry"
+)
+
+// ErrorClass 是处理失败的分类。
+type ErrorClass int
+
+const (
+	// ErrorRetryable 暂时性错误，按退避重试。
+	ErrorRetryable ErrorClass = iota
+	// ErrorTerminal 终止性错误，不重试，直接写入死信。
+	ErrorTerminal
+)
+
+// ErrorClassifier 对处理失败分类。
+type ErrorClassifier func(err error) ErrorClass
+
+// terminalCodes 是默认视为终止性错误的错误码（请求本身有问题，重试不会成功）。
+var terminalCodes = map[errors.ErrorCode]struct{}{
+	errors.InvalidInput:    {},
+	errors.Validation:      {},
+	errors.PayloadTooLarge: {},
+	errors.NotFound:        {},
+	errors.Conflict:        {},
+	errors.Duplicate:       {},
+	errors.Unauthorized:    {},
+	errors.Forbidden:       {},
+	errors.Unsupported:     {},
+}
+
+// DefaultErrorClassifier 是默认的错误分类：
+//   - 实现 retry.IRetryableError 的错误遵循其 IsRetryable()；
+//   - 被放弃的处理器（messaging.IsHandlerAbandoned）视为终止性错误，避免与仍在运行的旧调用并发；
+//   - 输入/校验/不存在/冲突/鉴权/不支持等错误码视为终止性错误；
+//   - 其余按 retry.IsRetryable 判断（context 取消/超时不重试）。
+func DefaultErrorClassifier(err error) ErrorClass {
+	if messaging.IsHandlerAbandoned(err) {
+		return ErrorTerminal
+	}
+	var retryable retry.IRetryableError
+	if errors.As(err, &retryable) {
+		if retryable.IsRetryable() {
+			return ErrorRetryable
+		}
+		return ErrorTerminal
+	}
+	if _, ok := terminalCodes[errors.Code(err)]; ok {
+		return ErrorTerminal
+	}
+	if retry.IsRetryable(err) {
+		return ErrorRetryable
+	}
+	return ErrorTerminal
+}
+
+// RetryPolicy 定义重试中间件的策略。
+type RetryPolicy struct {
+	// MaxAttempts 最大尝试次数（含首次，默认：3）。
+	MaxAttempts int
+
+	// InitialDelay / MaxDelay / BackoffFactor 指数退避参数（默认：100ms / 5s / 2）。
+	InitialDelay  time.Duration
+	MaxDelay      time.Duration
+	BackoffFactor float64
+
+	// JitterRatio 退避抖动比例（0~1，默认不抖动）。
+	JitterRatio float64
+
+	// Classifier 错误分类（默认 DefaultErrorClassifier）。
+	Classifier ErrorClassifier
+
+	// DeadLetter 可选：终止性错误或重试耗尽后写入死信；写入成功后视为已处理（不再向上返回错误）。
+	DeadLetter deadletter.ISink
+
+	Clock  clock.IClock
+	Logger logging.ILogger
+}
+
+// DefaultRetryPolicy 返回默认重试策略。
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:   3,
+		InitialDelay:  100 * time.Millisecond,
+		MaxDelay:      5 * time.Second,
+		BackoffFactor: 2,
+	}
+}
+
+// RetryMiddleware 对处理失败按退避重试，并把终止性失败写入死信（消费侧，与 Outbox 的投递侧重试互补）。
+//
+// 重试会重复执行后续链路，处理方需按消息 ID 幂等。
+type RetryMiddleware struct {
+	policy RetryPolicy
+	clock  clock.IClock
+	logger logging.ILogger
+}
+
+var _ messaging.IMiddleware = (*RetryMiddleware)(nil)
+
+// NewRetryMiddleware 创建重试中间件；policy 中未设置的字段使用 DefaultRetryPolicy 的值。
+func NewRetryMiddleware(policy RetryPolicy) *RetryMiddleware {
+	defaults := DefaultRetryPolicy()
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = defaults.MaxAttempts
+	}
+	if policy.InitialDelay <= 0 {
+		policy.InitialDelay = defaults.InitialDelay
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = defaults.MaxDelay
+	}
+	if policy.BackoffFactor <= 0 {
+		policy.BackoffFactor = defaults.BackoffFactor
+	}
+	if policy.Classifier == nil {
+		policy.Classifier = DefaultErrorClassifier
+	}
+	if policy.Clock == nil {
+		policy.Clock = clock.NewRealClock()
+	}
+	if policy.Logger == nil {
+		policy.Logger = logging.ComponentLogger("messaging.middleware.retry")
+	}
+	return &RetryMiddleware{policy: policy, clock: policy.Clock, logger: policy.Logger}
+}
+
+// Handle 执行后续链路，失败时按策略重试。
+func (m *RetryMiddleware) Handle(ctx context.Context, message messaging.IMessage, next messaging.HandlerFunc) error {
+	if message == nil {
+		return next(ctx, message)
+	}
+	attempts := 0
+	err := retry.DoWithInfo(ctx, func(ctx context.Context, attempt int) error {
+		attempts = attempt
+		err := next(ctx, message)
+		if err != nil && attempt < m.policy.MaxAttempts && m.policy.Classifier(err) == ErrorRetryable {
+			m.logger.Warn(ctx, "message handling failed, will retry", logging.Error(err),
+				logging.String("message_id", message.GetID()),
+				logging.String("message_type", message.GetType()),
+				logging.Int("attempt", attempt))
+		}
+		return err
+	}, retry.Config{
+		MaxAttempts:   m.policy.MaxAttempts,
+		InitialDelay:  m.policy.InitialDelay,
+		BackoffFactor: m.policy.BackoffFactor,
+		MaxDelay:      m.policy.MaxDelay,
+		JitterRatio:   m.policy.JitterRatio,
+		Clock:         m.clock,
+		RetryIf:       func(err error) bool { return m.policy.Classifier(err) == ErrorRetryable },
+	})
+	if err == nil || ctx.Err() != nil {
+		// 停机/取消导致的失败不写死信，交由上游重新投递。
+		return err
+	}
+	return m.deadLetter(ctx, message, err, attempts)
+}
+
+func (m *RetryMiddleware) deadLetter(ctx context.Context, message messaging.IMessage, cause error, attempts int) error {
+	fields := []logging.Field{
+		logging.Error(cause),
+		logging.String("message_id", message.GetID()),
+		logging.String("message_type", message.GetType()),
+		logging.Int("attempts", attempts),
+	}
+	if m.policy.DeadLetter == nil {
+		m.logger.Error(ctx, "message handling failed", fields...)
+		return cause
+	}
+	handlerType, ok := handlerTypeFromContext(ctx)
+	if !ok {
+		handlerType = message.GetType()
+	}
+	if err := m.policy.DeadLetter.Write(ctx, deadletter.Entry{
+		Message:     message,
+		HandlerType: handlerType,
+		Err:         cause,
+		OccurredAt:  m.clock.Now(),
+	}); err != nil {
+		m.logger.Error(ctx, "dead-letter write failed", append(fields, logging.Any("dead_letter_error", err))...)
+		return errors.Join(cause, errors.Wrap(err, errors.Dependency, "dead-letter write failed").
+			WithContext("message_id", message.GetID()))
+	}
+	m.logger.Error(ctx, "message handling failed, moved to dead letter", fields...)
+	return nil
+}
+
+// Name 返回中间件名称。
+func (m *RetryMiddleware) Name() string {
+	return "Retry"
+}
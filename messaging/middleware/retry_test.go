@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+	dlqmemory "gochen/messaging/deadletter/memory"
+	"gochen/messaging/transport/direct"
+)
+
+type flakyHandler struct {
+	calls    int
+	failures int
+	err      error
+}
+
+func (h *flakyHandler) Handle(context.Context, messaging.IMessage) error {
+	h.calls++
+	if h.calls <= h.failures {
+		return h.err
+	}
+	return nil
+}
+
+func (h *flakyHandler) Type() string { return "flaky-handler" }
+
+func fastPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond, Logger: logging.NewNoopLogger()}
+}
+
+// TestRetryMiddleware_RetriesTransientFailures 验证暂时性错误在总线处理链路上被重试直至成功。
+func TestRetryMiddleware_RetriesTransientFailures(t *testing.T) {
+	ctx := context.Background()
+	tpt := direct.NewSyncTransport()
+	require.NoError(t, tpt.Start(ctx))
+	t.Cleanup(func() { _ = tpt.Stop(context.Background()) })
+	bus := messaging.NewMessageBus(tpt)
+	bus.Use(NewRetryMiddleware(fastPolicy()))
+
+	handler := &flakyHandler{failures: 2, err: errors.NewCode(errors.ServiceUnavailable, "downstream unavailable")}
+	_, err := bus.Subscribe(ctx, "OrderPlaced", handler)
+	require.NoError(t, err)
+
+	require.NoError(t, bus.Publish(ctx, messaging.NewMessage("m1", messaging.KindEvent, "OrderPlaced", nil)))
+	require.Equal(t, 3, handler.calls)
+}
+
+// TestRetryMiddleware_TerminalErrorGoesToDeadLetter 验证终止性错误不重试，直接写入死信并视为已处理。
+func TestRetryMiddleware_TerminalErrorGoesToDeadLetter(t *testing.T) {
+	sink := dlqmemory.NewSink()
+	policy := fastPolicy()
+	policy.DeadLetter = sink
+	handler := &flakyHandler{failures: 10, err: errors.NewCode(errors.Validation, "bad payload")}
+	wrapped := WrapHandler(handler, NewRetryMiddleware(policy))
+	require.Equal(t, "flaky-handler", wrapped.Type())
+
+	require.NoError(t, wrapped.Handle(context.Background(), messaging.NewMessage("m1", messaging.KindEvent, "OrderPlaced", nil)))
+	require.Equal(t, 1, handler.calls)
+
+	entries := sink.Entries()
+	require.Len(t, entries, 1)
+	require.Equal(t, "flaky-handler", entries[0].HandlerType)
+	require.True(t, errors.Is(entries[0].Err, errors.Validation))
+}
+
+// TestRetryMiddleware_ExhaustedRetries 验证重试耗尽后写入死信；未配置死信时返回最后一次错误。
+func TestRetryMiddleware_ExhaustedRetries(t *testing.T) {
+	msg := messaging.NewMessage("m1", messaging.KindCommand, "ChargeCard", nil)
+	transient := errors.NewCode(errors.Timeout, "gateway timeout")
+
+	handler := &flakyHandler{failures: 10, err: transient}
+	err := WrapHandler(handler, NewRetryMiddleware(fastPolicy())).Handle(context.Background(), msg)
+	require.True(t, errors.Is(err, errors.Timeout))
+	require.Equal(t, 3, handler.calls)
+
+	sink := dlqmemory.NewSink()
+	policy := fastPolicy()
+	policy.DeadLetter = sink
+	handler = &flakyHandler{failures: 10, err: transient}
+	require.NoError(t, NewRetryMiddleware(policy).Handle(context.Background(), msg, func(ctx context.Context, m messaging.IMessage) error {
+		return handler.Handle(ctx, m)
+	}))
+	require.Equal(t, 3, handler.calls)
+	entries := sink.Entries()
+	require.Len(t, entries, 1)
+	require.Equal(t, "ChargeCard", entries[0].HandlerType)
+}
+
+// TestRetryMiddleware_CustomClassifier 验证自定义错误分类。
+func TestRetryMiddleware_CustomClassifier(t *testing.T) {
+	policy := fastPolicy()
+	policy.Classifier = func(err error) ErrorClass {
+		if errors.Is(err, errors.NotFound) {
+			return ErrorRetryable // 读模型尚未追上时重试
+		}
+		return ErrorTerminal
+	}
+	handler := &flakyHandler{failures: 1, err: errors.NewCode(errors.NotFound, "projection lagging")}
+	msg := messaging.NewMessage("m1", messaging.KindEvent, "OrderPlaced", nil)
+	require.NoError(t, WrapHandler(handler, NewRetryMiddleware(policy)).Handle(context.Background(), msg))
+	require.Equal(t, 2, handler.calls)
+
+	require.Equal(t, ErrorTerminal, DefaultErrorClassifier(context.Canceled))
+	require.Equal(t, ErrorTerminal, DefaultErrorClassifier(errors.NewCode(errors.Forbidden, "nope")))
+	require.Equal(t, ErrorRetryable, DefaultErrorClassifier(errors.NewCode(errors.Database, "deadlock")))
+}
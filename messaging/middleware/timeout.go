@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"gochen/clock"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/observe"
+)
+
+const (
+	// MetricHandlerTimeouts 是被强制超时的处理次数，标签 handler、message_type。
+	MetricHandlerTimeouts = "messaging_handler_timeouts_total"
+	// MetricHandlerSlow 是耗时超过慢处理阈值的处理次数，标签 handler、message_type。
+	MetricHandlerSlow = "messaging_handler_slow_total"
+)
+
+// TimeoutConfig 定义处理器超时与慢处理检测配置。
+type TimeoutConfig struct {
+	// Timeout 是单次处理的截止时间；<= 0 时不强制超时，只做慢处理检测。
+	Timeout time.Duration
+
+	// StopGrace 是超时后等待处理器退出的宽限期；<= 0 时使用 messaging.DefaultHandlerStopGrace。
+	StopGrace time.Duration
+
+	// SlowThreshold 是慢处理阈值；> 0 时耗时超过阈值（但未超时）的处理记录告警日志与指标。
+	SlowThreshold time.Duration
+
+	// Metrics 可选：上报超时与慢处理次数。
+	Metrics observe.IMetrics
+
+	Clock  clock.IClock
+	Logger logging.ILogger
+}
+
+// TimeoutMiddleware 取消超过截止时间的处理器 ctx，在宽限期内等待其退出后返回 Timeout 错误（见 messaging.RunWithTimeoutGrace），
+// 避免单个卡住的处理器悄无声息地拖住消费 goroutine。
+//
+// 超时时以 Error 级别记录处理器与调用栈采样；与 RetryMiddleware 组合时放在其内层，按单次尝试计时。
+// 宽限期内仍未退出的处理器被放弃（messaging.IsHandlerAbandoned），默认分类器将其视为终止性错误，不再重试。
+type TimeoutMiddleware struct {
+	config TimeoutConfig
+}
+
+var _ messaging.IMiddleware = (*TimeoutMiddleware)(nil)
+
+// NewTimeoutMiddleware 创建处理器超时中间件；cfg 为 nil 或未设置 Timeout/SlowThreshold 时直接透传。
+func NewTimeoutMiddleware(cfg *TimeoutConfig) *TimeoutMiddleware {
+	config := TimeoutConfig{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.StopGrace <= 0 {
+		config.StopGrace = messaging.DefaultHandlerStopGrace
+	}
+	if config.Clock == nil {
+		config.Clock = clock.NewRealClock()
+	}
+	if config.Logger == nil {
+		config.Logger = logging.ComponentLogger("messaging.middleware.timeout")
+	}
+	return &TimeoutMiddleware{config: config}
+}
+
+// Handle 在截止时间内执行后续链路，并记录超时与慢处理。
+func (m *TimeoutMiddleware) Handle(ctx context.Context, message messaging.IMessage, next messaging.HandlerFunc) error {
+	if m.config.Timeout <= 0 && m.config.SlowThreshold <= 0 {
+		return next(ctx, message)
+	}
+	startedAt := m.config.Clock.Now()
+	err := messaging.RunWithTimeoutGrace(ctx, m.config.Timeout, m.config.StopGrace, func(runCtx context.Context) error {
+		return next(runCtx, message)
+	})
+	elapsed := m.config.Clock.Now().Sub(startedAt)
+
+	messageID, messageType := "", ""
+	if message != nil {
+		messageID, messageType = message.GetID(), message.GetType()
+	}
+	handlerType, ok := handlerTypeFromContext(ctx)
+	if !ok {
+		handlerType = messageType
+	}
+	labels := map[string]string{"handler": handlerType, "message_type": messageType}
+	fields := []logging.Field{
+		logging.String("handler", handlerType),
+		logging.String("message_id", messageID),
+		logging.String("message_type", messageType),
+		logging.Duration("elapsed", elapsed),
+	}
+
+	switch {
+	case messaging.IsHandlerTimeout(err):
+		if m.config.Metrics != nil {
+			m.config.Metrics.Counter(MetricHandlerTimeouts, 1, labels)
+		}
+		m.config.Logger.Error(ctx, "message handler timed out", append(fields,
+			logging.Duration("timeout", m.config.Timeout),
+			logging.Bool("abandoned", messaging.IsHandlerAbandoned(err)),
+			logging.Error(err),
+		)...)
+	case m.config.SlowThreshold > 0 && elapsed > m.config.SlowThreshold:
+		if m.config.Metrics != nil {
+			m.config.Metrics.Counter(MetricHandlerSlow, 1, labels)
+		}
+		m.config.Logger.Warn(ctx, "slow message handler", append(fields, logging.Duration("threshold", m.config.SlowThreshold))...)
+	}
+	return err
+}
+
+// Name 返回中间件名称。
+func (m *TimeoutMiddleware) Name() string {
+	return "Timeout"
+}
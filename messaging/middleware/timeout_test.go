@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/observe"
+)
+
+func blockUntilReleased(release <-chan struct{}) {
+	<-release
+}
+
+// TestTimeoutMiddleware_CancelsStuckHandlerWithStackSample 验证卡住的处理器被强制超时，错误附带其调用栈采样并计入指标。
+func TestTimeoutMiddleware_CancelsStuckHandlerWithStackSample(t *testing.T) {
+	metrics := observe.NewInMemoryMetrics()
+	mw := NewTimeoutMiddleware(&TimeoutConfig{Timeout: 20 * time.Millisecond, StopGrace: 20 * time.Millisecond, Metrics: metrics, Logger: logging.NewNoopLogger()})
+	release := make(chan struct{})
+	defer close(release)
+	msg := messaging.NewMessage("m1", messaging.KindEvent, "OrderPlaced", nil)
+
+	err := WrapHandler(funcHandler{name: "orders-projection", fn: func(context.Context, messaging.IMessage) error {
+		blockUntilReleased(release)
+		return nil
+	}}, mw).Handle(context.Background(), msg)
+
+	require.True(t, errors.Is(err, errors.Timeout))
+	require.True(t, messaging.IsHandlerTimeout(err))
+	require.True(t, messaging.IsHandlerAbandoned(err), "handler ignoring cancellation should be abandoned after grace")
+	require.Equal(t, ErrorTerminal, DefaultErrorClassifier(err))
+	var appErr *errors.AppError
+	require.True(t, errors.As(err, &appErr))
+	stack, _ := appErr.Details()["stack"].(string)
+	require.True(t, strings.Contains(stack, "blockUntilReleased"), "stack sample should point at the stuck handler, got: %s", stack)
+	require.Equal(t, int64(1), metrics.CounterValue(MetricHandlerTimeouts, map[string]string{"handler": "orders-projection", "message_type": "OrderPlaced"}))
+}
+
+// TestTimeoutMiddleware_ReportsSlowHandlerAndCancelsContext 验证慢处理计入指标，且响应 ctx 取消的处理器同样归为超时。
+func TestTimeoutMiddleware_ReportsSlowHandlerAndCancelsContext(t *testing.T) {
+	metrics := observe.NewInMemoryMetrics()
+	msg := messaging.NewMessage("m1", messaging.KindEvent, "OrderPlaced", nil)
+
+	slow := NewTimeoutMiddleware(&TimeoutConfig{SlowThreshold: time.Millisecond, Metrics: metrics, Logger: logging.NewNoopLogger()})
+	require.NoError(t, slow.Handle(context.Background(), msg, func(context.Context, messaging.IMessage) error {
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	}))
+	require.Equal(t, int64(1), metrics.CounterValue(MetricHandlerSlow, map[string]string{"handler": "OrderPlaced", "message_type": "OrderPlaced"}))
+
+	timeout := NewTimeoutMiddleware(&TimeoutConfig{Timeout: 10 * time.Millisecond, Logger: logging.NewNoopLogger()})
+	err := timeout.Handle(context.Background(), msg, func(ctx context.Context, _ messaging.IMessage) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.True(t, messaging.IsHandlerTimeout(err))
+	require.False(t, messaging.IsHandlerAbandoned(err))
+
+	parent, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = timeout.Handle(parent, msg, func(ctx context.Context, _ messaging.IMessage) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.False(t, messaging.IsHandlerTimeout(err), "upstream cancellation is not a handler timeout")
+}
+
+// TestTimeoutMiddleware_WaitsForHandlerExitBeforeReturning 验证超时后在宽限期内等待处理器退出，重试不会与旧调用并发。
+func TestTimeoutMiddleware_WaitsForHandlerExitBeforeReturning(t *testing.T) {
+	mw := NewTimeoutMiddleware(&TimeoutConfig{Timeout: 10 * time.Millisecond, StopGrace: time.Second, Logger: logging.NewNoopLogger()})
+	msg := messaging.NewMessage("m1", messaging.KindEvent, "OrderPlaced", nil)
+	var exited atomic.Bool
+
+	err := mw.Handle(context.Background(), msg, func(ctx context.Context, _ messaging.IMessage) error {
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond) // 收尾工作晚于截止时间
+		exited.Store(true)
+		return ctx.Err()
+	})
+	require.True(t, messaging.IsHandlerTimeout(err))
+	require.False(t, messaging.IsHandlerAbandoned(err))
+	require.True(t, exited.Load(), "handler must have exited before the timeout error is returned")
+}
@@ -0,0 +1,47 @@
+package messaging
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	gerrors "gochen/errors"
+)
+
+// PanicError 把 recover() 捕获的值转换为 Internal 错误，并附带 panic 与调用栈上下文。
+//
+// 处理器（总线、投影、命令、Saga 步骤）的 panic 统一经此转换，便于错误钩子、指标与死信按普通错误处理。
+func PanicError(message string, recovered any) *gerrors.AppError {
+	return gerrors.NewCode(gerrors.Internal, message).
+		WithContext("panic", fmt.Sprint(recovered)).
+		WithContext("stack", string(debug.Stack()))
+}
+
+// IsPanicError 判断 err（或其错误链）是否由 PanicError 转换而来。
+func IsPanicError(err error) bool {
+	return hasDetail(err, "panic")
+}
+
+// hasDetail 判断 err 的 AppError 链上是否存在指定上下文键。
+func hasDetail(err error, key string) bool {
+	var appErr *gerrors.AppError
+	for err != nil {
+		if !gerrors.As(err, &appErr) || appErr == nil {
+			return false
+		}
+		if _, ok := appErr.Details()[key]; ok {
+			return true
+		}
+		err = appErr.Unwrap()
+	}
+	return false
+}
+
+// CallRecovered 调用 fn，并把 fn 中的 panic 转换为 PanicError(message, ...)。
+func CallRecovered(message string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = PanicError(message, r)
+		}
+	}()
+	return fn()
+}
@@ -9,14 +9,28 @@ import (
 
 // Payload 封装消息/事件载荷，避免核心协议层直接暴露裸 any。
 type Payload struct {
-	value any
+	value     any
+	marshaler IPayloadMarshaler
 }
 
-// NewPayload 创建载荷封装。
+// IPayloadMarshaler 自定义载荷的 JSON 编码（如把 protobuf 消息编码为类型 URL + 二进制信封）。
+type IPayloadMarshaler interface {
+	MarshalPayload(value any) ([]byte, error)
+}
+
+// NewPayload 创建载荷封装；value 已是 Payload 时原样返回（保留其编码方式）。
 func NewPayload(value any) Payload {
+	if p, ok := value.(Payload); ok {
+		return p
+	}
 	return Payload{value: value}
 }
 
+// NewPayloadWithMarshaler 创建使用自定义 JSON 编码的载荷封装；PayloadValue 仍返回 value 本身。
+func NewPayloadWithMarshaler(value any, marshaler IPayloadMarshaler) Payload {
+	return Payload{value: value, marshaler: marshaler}
+}
+
 // Type 返回底层值的反射类型；nil 载荷返回 nil。
 func (p Payload) Type() reflect.Type {
 	if p.value == nil {
@@ -82,8 +96,11 @@ func (p Payload) DecodeTo(target any) error {
 	return decoder.Decode(target)
 }
 
-// MarshalJSON 直接序列化底层值。
+// MarshalJSON 序列化底层值；配置了 IPayloadMarshaler 时使用其编码。
 func (p Payload) MarshalJSON() ([]byte, error) {
+	if p.marshaler != nil && p.value != nil {
+		return p.marshaler.MarshalPayload(p.value)
+	}
 	return json.Marshal(p.value)
 }
 
@@ -99,6 +116,7 @@ func (p *Payload) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	p.value = value
+	p.marshaler = nil
 	return nil
 }
 
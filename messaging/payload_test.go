@@ -1,6 +1,9 @@
 package messaging
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+)
 
 type payloadTestValue struct{}
 
@@ -10,3 +13,35 @@ func TestPayloadIsNil_DetectsTypedNil(t *testing.T) {
 		t.Fatal("expected typed nil payload to be treated as nil")
 	}
 }
+
+type wrapMarshaler struct{}
+
+func (wrapMarshaler) MarshalPayload(value any) ([]byte, error) {
+	return json.Marshal(map[string]any{"wrapped": value})
+}
+
+func TestPayloadMarshaler_UsedForJSONAndKeptByNewPayload(t *testing.T) {
+	p := NewPayload(NewPayloadWithMarshaler("v", wrapMarshaler{}))
+	if got := PayloadValue(p); got != "v" {
+		t.Fatalf("expected underlying value, got %#v", got)
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(data) != `{"wrapped":"v"}` {
+		t.Fatalf("expected custom encoding, got %s", data)
+	}
+
+	var decoded Payload
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	data, err = json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("marshal decoded: %v", err)
+	}
+	if string(data) != `{"wrapped":"v"}` {
+		t.Fatalf("expected decoded payload to re-encode as plain JSON, got %s", data)
+	}
+}
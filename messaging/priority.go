@@ -0,0 +1,70 @@
+package messaging
+
+import "strings"
+
+// MetadataPriorityKey 是消息优先级的元数据键，值为 Priority.String()（跨进程传递时保持可读）。
+const MetadataPriorityKey = "priority"
+
+// Priority 表示消息优先级，数值越大越优先；仅支持优先级的 Transport（如 memory 的优先级模式）会使用它。
+type Priority int
+
+const (
+	// PriorityLow 低优先级：可延后处理的批量流量（投影重放、回填等）。
+	PriorityLow Priority = iota
+	// PriorityNormal 普通优先级（未设置时的缺省值）。
+	PriorityNormal
+	// PriorityHigh 高优先级：对时延敏感的流量（用户发起的命令等）。
+	PriorityHigh
+)
+
+// String 返回优先级名称。
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityHigh:
+		return "high"
+	default:
+		return "normal"
+	}
+}
+
+// ParsePriority 解析优先级名称（low/normal/high，忽略大小写与首尾空白）。
+func ParsePriority(s string) (Priority, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "low":
+		return PriorityLow, true
+	case "normal":
+		return PriorityNormal, true
+	case "high":
+		return PriorityHigh, true
+	default:
+		return PriorityNormal, false
+	}
+}
+
+// SetPriority 把优先级写入消息元数据。
+func SetPriority(message IMessage, priority Priority) {
+	if message == nil {
+		return
+	}
+	message.GetMetadata().Set(MetadataPriorityKey, priority.String())
+}
+
+// LookupPriority 从消息元数据读取显式设置的优先级；未设置或无法识别时返回 (PriorityNormal, false)。
+func LookupPriority(message IMessage) (Priority, bool) {
+	if message == nil {
+		return PriorityNormal, false
+	}
+	value, ok := message.GetMetadata().Get(MetadataPriorityKey)
+	if !ok {
+		return PriorityNormal, false
+	}
+	return ParsePriority(value)
+}
+
+// PriorityOf 返回消息优先级；未显式设置时为 PriorityNormal。
+func PriorityOf(message IMessage) Priority {
+	priority, _ := LookupPriority(message)
+	return priority
+}
@@ -0,0 +1,21 @@
+package messaging
+
+import "strings"
+
+// MetadataProducerKey 是幂等生产者键的元数据键。
+//
+// 同一条逻辑消息的每次（重新）发布都携带相同的键，例如 Outbox 发布器以记录 ID 生成；
+// 支持幂等生产的 broker（SQS FIFO 去重、RabbitMQ 去重插件、Kafka 幂等生产者的记录键）与
+// 消费侧去重（messaging/dedup）据此丢弃重复投递。
+const MetadataProducerKey = "producer_key"
+
+// ProducerKey 返回消息的幂等生产者键；未设置时退化为消息 ID。
+func ProducerKey(message IMessage) string {
+	if message == nil {
+		return ""
+	}
+	if key, ok := message.GetMetadata().Get(MetadataProducerKey); ok && strings.TrimSpace(key) != "" {
+		return key
+	}
+	return message.GetID()
+}
@@ -0,0 +1,245 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"gochen/errors"
+	"gochen/messaging"
+)
+
+// QueryBus 是本地查询总线：按查询的 Go 类型路由到唯一 handler，并在当前调用栈内执行中间件链。
+type QueryBus struct {
+	mu          sync.RWMutex
+	handlers    map[reflect.Type]registration
+	names       map[string]reflect.Type
+	middlewares []IMiddleware
+}
+
+type registration struct {
+	name   string
+	invoke func(ctx context.Context, payload any) (any, error)
+}
+
+// NewQueryBus 创建查询总线。
+func NewQueryBus() *QueryBus {
+	return &QueryBus{
+		handlers: make(map[reflect.Type]registration),
+		names:    make(map[string]reflect.Type),
+	}
+}
+
+// Register 注册类型化查询处理器。
+//
+// 每个查询类型（以及查询名）只能注册一个 handler，重复注册返回 errors.Conflict；
+// Q 必须是具体类型（不能是接口），Dispatch 按查询值的动态类型路由。
+func Register[Q any, R any](bus *QueryBus, handler IQueryHandler[Q, R]) error {
+	if bus == nil {
+		return errors.NewCode(errors.InvalidInput, "query bus is nil")
+	}
+	if handler == nil {
+		return errors.NewCode(errors.InvalidInput, "handler cannot be nil")
+	}
+	queryType := reflect.TypeFor[Q]()
+	if queryType.Kind() == reflect.Interface {
+		return errors.NewCode(errors.InvalidInput, "query type must be concrete").
+			WithContext("query_type", queryType.String())
+	}
+	name := queryNameOf[Q](queryType)
+	if name == "" {
+		return errors.NewCode(errors.InvalidInput, "query name cannot be empty").
+			WithContext("query_type", queryType.String())
+	}
+
+	invoke := func(ctx context.Context, payload any) (any, error) {
+		typed, ok := payload.(Q)
+		if !ok {
+			return nil, errors.NewCode(errors.InvalidInput, "query payload type mismatch").
+				WithContext("query", name).
+				WithContext("expected_type", queryType.String()).
+				WithContext("actual_type", fmt.Sprintf("%T", payload))
+		}
+		return handler.Handle(ctx, typed)
+	}
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	if _, exists := bus.handlers[queryType]; exists {
+		return errors.NewCode(errors.Conflict, "query handler already registered").
+			WithContext("query", name).
+			WithContext("query_type", queryType.String())
+	}
+	if existing, exists := bus.names[name]; exists {
+		return errors.NewCode(errors.Conflict, "query name already registered").
+			WithContext("query", name).
+			WithContext("query_type", queryType.String()).
+			WithContext("registered_type", existing.String())
+	}
+	if bus.handlers == nil {
+		bus.handlers = make(map[reflect.Type]registration)
+		bus.names = make(map[string]reflect.Type)
+	}
+	bus.handlers[queryType] = registration{name: name, invoke: invoke}
+	bus.names[name] = queryType
+	return nil
+}
+
+// RegisterFunc 以函数形式注册查询处理器，语义同 Register。
+func RegisterFunc[Q any, R any](bus *QueryBus, fn func(ctx context.Context, query Q) (R, error)) error {
+	if fn == nil {
+		return errors.NewCode(errors.InvalidInput, "handler cannot be nil")
+	}
+	return Register[Q, R](bus, HandlerFunc[Q, R](fn))
+}
+
+// Ask 执行查询并把结果断言为 R；结果类型不符返回 errors.Internal。
+//
+// Q 可由参数推导：query.Ask[*OrderView](ctx, bus, GetOrder{...})。
+func Ask[R any, Q any](ctx context.Context, dispatcher IQueryDispatcher, query Q) (R, error) {
+	var zero R
+	if dispatcher == nil {
+		return zero, errors.NewCode(errors.InvalidInput, "query dispatcher is nil")
+	}
+	result, err := dispatcher.Dispatch(ctx, query)
+	if err != nil {
+		return zero, err
+	}
+	if result == nil {
+		return zero, nil
+	}
+	typed, ok := result.(R)
+	if !ok {
+		return zero, errors.NewCode(errors.Internal, "query result type mismatch").
+			WithContext("query", NameOf(query)).
+			WithContext("expected_type", reflect.TypeFor[R]().String()).
+			WithContext("actual_type", fmt.Sprintf("%T", result))
+	}
+	return typed, nil
+}
+
+// RegisterAll 依次调用各模块的 RegisterQueries；任一失败立即返回。
+func (b *QueryBus) RegisterAll(registrars ...IQueryRegistrar) error {
+	if b == nil {
+		return errors.NewCode(errors.InvalidInput, "query bus is nil")
+	}
+	for _, registrar := range registrars {
+		if registrar == nil {
+			continue
+		}
+		if err := registrar.RegisterQueries(b); err != nil {
+			return errors.Wrap(err, errors.Internal, "register queries failed").
+				WithContext("registrar", fmt.Sprintf("%T", registrar))
+		}
+	}
+	return nil
+}
+
+// Use 注册查询中间件；先注册的在外层。
+func (b *QueryBus) Use(middleware IMiddleware) {
+	if b == nil || middleware == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.middlewares = append(b.middlewares, middleware)
+}
+
+// HasHandler 检查某个查询名是否已注册处理器。
+func (b *QueryBus) HasHandler(name string) bool {
+	if b == nil {
+		return false
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	_, ok := b.names[name]
+	return ok
+}
+
+// Queries 返回已注册的查询名（按字典序）。
+func (b *QueryBus) Queries() []string {
+	if b == nil {
+		return nil
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	names := make([]string, 0, len(b.names))
+	for name := range b.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Dispatch 按查询值的动态类型找到 handler，经中间件链执行并返回结果。
+func (b *QueryBus) Dispatch(ctx context.Context, query any) (any, error) {
+	if b == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "query bus is nil")
+	}
+	if ctx == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	if query == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "query cannot be nil")
+	}
+
+	reg, ok := b.lookup(reflect.TypeOf(query))
+	if !ok {
+		return nil, errors.NewCode(errors.NotFound, "query handler not found").
+			WithContext("query", NameOf(query)).
+			WithContext("query_type", fmt.Sprintf("%T", query))
+	}
+
+	final := func(ctx context.Context, q *Query) (any, error) {
+		return invokeQueryHandler(ctx, q, reg)
+	}
+
+	middlewares := b.middlewaresSnapshot()
+	next := NextFunc(final)
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		middleware := middlewares[i]
+		currentNext := next
+		next = func(ctx context.Context, q *Query) (any, error) {
+			return middleware.Handle(ctx, q, currentNext)
+		}
+	}
+	return next(ctx, &Query{Name: reg.name, Payload: query})
+}
+
+func (b *QueryBus) lookup(queryType reflect.Type) (registration, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	reg, ok := b.handlers[queryType]
+	return reg, ok
+}
+
+func (b *QueryBus) middlewaresSnapshot() []IMiddleware {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]IMiddleware(nil), b.middlewares...)
+}
+
+func invokeQueryHandler(ctx context.Context, q *Query, reg registration) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = messaging.PanicError("query handler panicked", r).
+				WithContext("query", reg.name)
+		}
+	}()
+	return reg.invoke(ctx, q.Payload)
+}
+
+// queryNameOf 计算查询类型的查询名；指针类型使用新分配的值，避免在 nil 指针上调用 QueryName。
+func queryNameOf[Q any](queryType reflect.Type) string {
+	var sample any = *new(Q)
+	if queryType.Kind() == reflect.Pointer {
+		sample = reflect.New(queryType.Elem()).Interface()
+	}
+	return NameOf(sample)
+}
+
+var _ IQueryDispatcher = (*QueryBus)(nil)
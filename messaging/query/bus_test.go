@@ -0,0 +1,120 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/messaging"
+)
+
+type getOrder struct {
+	OrderID string
+}
+
+type orderView struct {
+	ID     string
+	Status string
+}
+
+type listOrders struct{}
+
+func (*listOrders) QueryName() string { return "orders.List" }
+
+type recordingMiddleware struct {
+	name  string
+	trace *[]string
+}
+
+func (m recordingMiddleware) Handle(ctx context.Context, q *Query, next NextFunc) (any, error) {
+	*m.trace = append(*m.trace, m.name+">"+q.Name)
+	result, err := next(ctx, q)
+	*m.trace = append(*m.trace, m.name+"<")
+	return result, err
+}
+
+func (m recordingMiddleware) Name() string { return m.name }
+
+type orderModule struct{}
+
+func (orderModule) RegisterQueries(bus *QueryBus) error {
+	return RegisterFunc(bus, func(_ context.Context, q getOrder) (*orderView, error) {
+		return &orderView{ID: q.OrderID, Status: "paid"}, nil
+	})
+}
+
+func TestQueryBus_AskReturnsTypedResult(t *testing.T) {
+	bus := NewQueryBus()
+	require.NoError(t, bus.RegisterAll(orderModule{}))
+
+	view, err := Ask[*orderView](context.Background(), bus, getOrder{OrderID: "o-1"})
+	require.NoError(t, err)
+	assert.Equal(t, &orderView{ID: "o-1", Status: "paid"}, view)
+	assert.True(t, bus.HasHandler("getOrder"))
+}
+
+func TestQueryBus_NamedPointerQuery(t *testing.T) {
+	bus := NewQueryBus()
+	require.NoError(t, RegisterFunc(bus, func(context.Context, *listOrders) ([]string, error) {
+		return []string{"o-1", "o-2"}, nil
+	}))
+
+	ids, err := Ask[[]string](context.Background(), bus, &listOrders{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"o-1", "o-2"}, ids)
+	assert.Equal(t, []string{"orders.List"}, bus.Queries())
+}
+
+func TestQueryBus_DuplicateRegistrationConflicts(t *testing.T) {
+	bus := NewQueryBus()
+	require.NoError(t, bus.RegisterAll(orderModule{}))
+
+	err := bus.RegisterAll(orderModule{})
+	assert.True(t, errors.Is(err, errors.Conflict))
+}
+
+func TestQueryBus_RejectsInterfaceQueryType(t *testing.T) {
+	bus := NewQueryBus()
+	err := RegisterFunc(bus, func(context.Context, any) (int, error) { return 0, nil })
+	assert.True(t, errors.Is(err, errors.InvalidInput))
+}
+
+func TestQueryBus_HandlerNotFound(t *testing.T) {
+	_, err := Ask[*orderView](context.Background(), NewQueryBus(), getOrder{})
+	assert.True(t, errors.Is(err, errors.NotFound))
+}
+
+func TestQueryBus_ResultTypeMismatch(t *testing.T) {
+	bus := NewQueryBus()
+	require.NoError(t, bus.RegisterAll(orderModule{}))
+
+	_, err := Ask[orderView](context.Background(), bus, getOrder{OrderID: "o-1"})
+	assert.True(t, errors.Is(err, errors.Internal))
+}
+
+func TestQueryBus_MiddlewareOrder(t *testing.T) {
+	bus := NewQueryBus()
+	require.NoError(t, bus.RegisterAll(orderModule{}))
+
+	var trace []string
+	bus.Use(recordingMiddleware{name: "outer", trace: &trace})
+	bus.Use(recordingMiddleware{name: "inner", trace: &trace})
+
+	_, err := bus.Dispatch(context.Background(), getOrder{OrderID: "o-1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"outer>getOrder", "inner>getOrder", "inner<", "outer<"}, trace)
+}
+
+func TestQueryBus_RecoversHandlerPanic(t *testing.T) {
+	bus := NewQueryBus()
+	require.NoError(t, RegisterFunc(bus, func(context.Context, getOrder) (*orderView, error) {
+		panic("boom")
+	}))
+
+	_, err := bus.Dispatch(context.Background(), getOrder{})
+	require.Error(t, err)
+	assert.True(t, messaging.IsPanicError(err))
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"gochen/auth"
+	"gochen/errors"
+	"gochen/messaging/query"
+)
+
+// IPermissionedQuery 可选：查询类型自行声明所需权限，优先于 AuthorizationConfig.Permissions。
+type IPermissionedQuery interface {
+	QueryPermission() string
+}
+
+// AuthorizationConfig 定义查询授权配置。
+type AuthorizationConfig struct {
+	// Authorizer 授权器（必填）。
+	Authorizer auth.IAuthorizer
+
+	// Permissions 查询名 -> 所需权限。
+	Permissions map[string]string
+
+	// Targets 可选：返回授权目标（交给 Authorizer 解析为资源），为空时只校验权限本身。
+	Targets func(q *query.Query) []any
+
+	// DenyUnmapped 为 true 时，未声明权限的查询返回 errors.Forbidden；默认放行。
+	DenyUnmapped bool
+}
+
+// AuthorizationMiddleware 在执行查询前调用 Authorizer.Require。
+//
+// 应先于 CachingMiddleware 注册（位于外层），避免缓存命中绕过授权。
+type AuthorizationMiddleware struct {
+	authorizer   auth.IAuthorizer
+	permissions  map[string]string
+	targets      func(q *query.Query) []any
+	denyUnmapped bool
+}
+
+// NewAuthorizationMiddleware 创建查询授权中间件。
+func NewAuthorizationMiddleware(cfg AuthorizationConfig) (*AuthorizationMiddleware, error) {
+	if cfg.Authorizer == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "authorizer cannot be nil")
+	}
+	permissions := make(map[string]string, len(cfg.Permissions))
+	for name, permission := range cfg.Permissions {
+		permissions[name] = strings.TrimSpace(permission)
+	}
+	return &AuthorizationMiddleware{
+		authorizer:   cfg.Authorizer,
+		permissions:  permissions,
+		targets:      cfg.Targets,
+		denyUnmapped: cfg.DenyUnmapped,
+	}, nil
+}
+
+// Handle 授权通过后执行查询。
+func (m *AuthorizationMiddleware) Handle(ctx context.Context, q *query.Query, next query.NextFunc) (any, error) {
+	permission := m.permissionOf(q)
+	if permission == "" {
+		if m.denyUnmapped {
+			return nil, errors.NewCode(errors.Forbidden, "query permission is not declared").
+				WithContext("query", q.Name)
+		}
+		return next(ctx, q)
+	}
+	var targets []any
+	if m.targets != nil {
+		targets = m.targets(q)
+	}
+	if err := m.authorizer.Require(ctx, permission, targets...); err != nil {
+		return nil, err
+	}
+	return next(ctx, q)
+}
+
+func (m *AuthorizationMiddleware) permissionOf(q *query.Query) string {
+	if declared, ok := q.Payload.(IPermissionedQuery); ok {
+		if permission := strings.TrimSpace(declared.QueryPermission()); permission != "" {
+			return permission
+		}
+	}
+	return m.permissions[q.Name]
+}
+
+// Name 返回中间件名称。
+func (m *AuthorizationMiddleware) Name() string { return "QueryAuthorization" }
+
+var _ query.IMiddleware = (*AuthorizationMiddleware)(nil)
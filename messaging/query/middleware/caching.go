@@ -0,0 +1,310 @@
+// Package middleware 提供查询总线的常用中间件：结果缓存、追踪与授权。
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gochen/cache"
+	"gochen/clock"
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/bus"
+	"gochen/messaging"
+	"gochen/messaging/query"
+)
+
+// CachingConfig 定义查询结果缓存配置。
+type CachingConfig struct {
+	// TTL 缓存有效期（默认 1 分钟），基于最后访问时间滑动过期。
+	TTL time.Duration
+
+	// MaxSize 最大缓存条目数（默认 1000），超出时按 LRU 驱逐。
+	MaxSize int
+
+	// Queries 可选：只缓存这些查询名；为空时缓存所有查询。
+	Queries []string
+
+	// InvalidateOn 可选：事件类型 -> 需要失效的查询名，配合 SubscribeInvalidation 使用。
+	InvalidateOn map[string][]string
+
+	// Key 可选：计算缓存键，返回 false 表示本次不缓存。默认见 DefaultCacheKey（按租户与调用主体隔离）；
+	// 结果与调用者无关的查询可使用 SharedCacheKey 在同租户内共享。
+	Key func(ctx context.Context, q *query.Query) (string, bool)
+
+	// Clock 可选：时间来源（默认真实时钟）。
+	Clock clock.IClock
+}
+
+// CachingMiddleware 缓存成功的查询结果；handler 返回错误时不缓存。
+//
+// 缓存条目按查询名索引，可通过 InvalidateQuery 或订阅事件（InvalidateOn）整体失效；
+// 与失效并发执行的查询结果不会写回缓存，避免失效后又缓存旧读模型。
+// 缓存值与 handler 返回值为同一对象，读模型结果应按不可变值使用。
+type CachingMiddleware struct {
+	cache        *cache.Cache[string, any]
+	queries      map[string]struct{}
+	invalidateOn map[string][]string
+	key          func(ctx context.Context, q *query.Query) (string, bool)
+
+	mu sync.Mutex
+	// index 为查询名 -> 缓存键集合。
+	index map[string]map[string]struct{}
+	// owners 为缓存键 -> 查询名，供驱逐回调清理索引。
+	owners map[string]string
+	// generations 在查询失效时递增，cleared 在 Clear 时递增。
+	generations map[string]uint64
+	cleared     uint64
+}
+
+// NewCachingMiddleware 创建查询缓存中间件。
+func NewCachingMiddleware(cfg CachingConfig) (*CachingMiddleware, error) {
+	if cfg.TTL < 0 {
+		return nil, errors.NewCode(errors.InvalidInput, "cache ttl cannot be negative")
+	}
+	if cfg.TTL == 0 {
+		cfg.TTL = time.Minute
+	}
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = 1000
+	}
+	if cfg.Key == nil {
+		cfg.Key = DefaultCacheKey
+	}
+	var queries map[string]struct{}
+	if len(cfg.Queries) > 0 {
+		queries = make(map[string]struct{}, len(cfg.Queries))
+		for _, name := range cfg.Queries {
+			queries[name] = struct{}{}
+		}
+	}
+	invalidateOn := make(map[string][]string, len(cfg.InvalidateOn))
+	for eventType, names := range cfg.InvalidateOn {
+		eventType = strings.TrimSpace(eventType)
+		if eventType == "" || len(names) == 0 {
+			return nil, errors.NewCode(errors.InvalidInput, "invalid cache invalidation mapping").
+				WithContext("event_type", eventType)
+		}
+		invalidateOn[eventType] = append([]string(nil), names...)
+	}
+
+	m := &CachingMiddleware{
+		queries:      queries,
+		invalidateOn: invalidateOn,
+		key:          cfg.Key,
+		index:        make(map[string]map[string]struct{}),
+		owners:       make(map[string]string),
+		generations:  make(map[string]uint64),
+	}
+	m.cache = cache.New[string, any](cache.Config{
+		Name:    "query_results",
+		MaxSize: cfg.MaxSize,
+		TTL:     cfg.TTL,
+		Clock:   cfg.Clock,
+		OnEvict: m.onEvict,
+	})
+	return m, nil
+}
+
+// DefaultCacheKey 返回 "查询名|哈希"，哈希覆盖租户、操作者、用户 ID 与参数 JSON（SHA-256）；
+// 参数无法序列化时返回 false。
+//
+// 查询结果常按调用者的权限或数据范围裁剪，因此默认不在不同主体之间共享缓存条目。
+func DefaultCacheKey(ctx context.Context, q *query.Query) (string, bool) {
+	return cacheKey(q, contextx.TenantID(ctx), contextx.Operator(ctx), strconv.FormatInt(contextx.UserID(ctx), 10))
+}
+
+// SharedCacheKey 与 DefaultCacheKey 相同，但不区分调用主体：同一租户内所有调用者共享缓存条目。
+//
+// 仅用于结果与调用者无关的查询（如公开目录、配置字典）。
+func SharedCacheKey(ctx context.Context, q *query.Query) (string, bool) {
+	return cacheKey(q, contextx.TenantID(ctx))
+}
+
+// cacheKey 对作用域字段与参数 JSON 整体求哈希；字段以 \x00 分隔，避免拼接歧义。
+func cacheKey(q *query.Query, scope ...string) (string, bool) {
+	payload, err := json.Marshal(q.Payload)
+	if err != nil {
+		return "", false
+	}
+	h := sha256.New()
+	for _, part := range scope {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	h.Write(payload)
+	return q.Name + "|" + hex.EncodeToString(h.Sum(nil)), true
+}
+
+// Handle 命中缓存时直接返回，否则执行查询并缓存成功结果。
+func (m *CachingMiddleware) Handle(ctx context.Context, q *query.Query, next query.NextFunc) (any, error) {
+	if m.queries != nil {
+		if _, ok := m.queries[q.Name]; !ok {
+			return next(ctx, q)
+		}
+	}
+	key, ok := m.key(ctx, q)
+	if !ok {
+		return next(ctx, q)
+	}
+	if result, found := m.cache.Get(key); found {
+		return result, nil
+	}
+
+	generation := m.generation(q.Name)
+	result, err := next(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	m.store(q.Name, key, result, generation)
+	return result, nil
+}
+
+// Invalidate 删除指定缓存键，返回是否存在。
+func (m *CachingMiddleware) Invalidate(key string) bool {
+	return m.cache.Delete(key)
+}
+
+// InvalidateQuery 删除指定查询名的全部缓存条目（所有参数与租户），返回删除条数。
+func (m *CachingMiddleware) InvalidateQuery(names ...string) int {
+	var keys []string
+	m.mu.Lock()
+	for _, name := range names {
+		m.generations[name]++
+		for key := range m.index[name] {
+			keys = append(keys, key)
+		}
+	}
+	m.mu.Unlock()
+
+	// 驱逐回调会再次获取 m.mu，因此在锁外删除。
+	removed := 0
+	for _, key := range keys {
+		if m.cache.Delete(key) {
+			removed++
+		}
+	}
+	return removed
+}
+
+// Clear 清空全部缓存（例如读模型重建后）。
+func (m *CachingMiddleware) Clear() {
+	m.mu.Lock()
+	m.cleared++
+	m.mu.Unlock()
+	m.cache.Clear()
+}
+
+// Stats 返回缓存统计。
+func (m *CachingMiddleware) Stats() cache.CacheStats {
+	return m.cache.Stats()
+}
+
+// SubscribeInvalidation 按 InvalidateOn 订阅事件：收到事件后失效对应查询的缓存。
+//
+// 投影通常也订阅同一事件，缓存失效与读模型更新之间没有顺序保证，TTL 仍是最终兜底。
+func (m *CachingMiddleware) SubscribeInvalidation(ctx context.Context, eventBus bus.IEventBus) (messaging.UnsubscribeFunc, error) {
+	if eventBus == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "event bus is nil")
+	}
+	if len(m.invalidateOn) == 0 {
+		return nil, errors.NewCode(errors.InvalidInput, "cache invalidation mapping is empty")
+	}
+	return eventBus.SubscribeHandler(ctx, &invalidationHandler{middleware: m})
+}
+
+// Name 返回中间件名称。
+func (m *CachingMiddleware) Name() string { return "QueryCaching" }
+
+func (m *CachingMiddleware) generation(name string) uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.generations[name] + m.cleared
+}
+
+func (m *CachingMiddleware) store(name, key string, result any, generation uint64) {
+	m.cache.Set(key, result)
+
+	m.mu.Lock()
+	stale := m.generations[name]+m.cleared != generation
+	if !stale {
+		keys, ok := m.index[name]
+		if !ok {
+			keys = make(map[string]struct{})
+			m.index[name] = keys
+		}
+		keys[key] = struct{}{}
+		m.owners[key] = name
+	}
+	m.mu.Unlock()
+
+	// 查询执行期间发生了失效：结果可能基于旧读模型，撤回写入。
+	if stale {
+		m.cache.Delete(key)
+	}
+}
+
+// onEvict 在缓存条目被删除、过期或驱逐时清理索引（由 cache 在持锁状态下回调）。
+func (m *CachingMiddleware) onEvict(key, _ any) {
+	k, ok := key.(string)
+	if !ok {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name, ok := m.owners[k]
+	if !ok {
+		return
+	}
+	delete(m.owners, k)
+	delete(m.index[name], k)
+	if len(m.index[name]) == 0 {
+		delete(m.index, name)
+	}
+}
+
+// invalidationHandler 把事件映射为查询缓存失效。
+type invalidationHandler struct {
+	middleware *CachingMiddleware
+}
+
+func (h *invalidationHandler) Handle(ctx context.Context, message messaging.IMessage) error {
+	evt, ok := message.(eventing.IEvent)
+	if !ok {
+		return errors.NewCode(errors.InvalidInput, "message is not an event").
+			WithContext("message_type", fmt.Sprintf("%T", message))
+	}
+	return h.HandleEvent(ctx, evt)
+}
+
+func (h *invalidationHandler) HandleEvent(_ context.Context, evt eventing.IEvent) error {
+	h.middleware.InvalidateQuery(h.middleware.invalidateOn[evt.GetType()]...)
+	return nil
+}
+
+func (h *invalidationHandler) EventTypes() []string {
+	types := make([]string, 0, len(h.middleware.invalidateOn))
+	for eventType := range h.middleware.invalidateOn {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func (h *invalidationHandler) HandlerName() string { return "QueryCacheInvalidation" }
+
+func (h *invalidationHandler) Type() string { return "QueryCacheInvalidation" }
+
+var (
+	_ query.IMiddleware = (*CachingMiddleware)(nil)
+	_ bus.IEventHandler = (*invalidationHandler)(nil)
+)
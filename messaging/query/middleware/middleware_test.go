@@ -0,0 +1,270 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gochen/auth"
+	"gochen/clock"
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/bus"
+	"gochen/messaging"
+	"gochen/messaging/query"
+	"gochen/messaging/transport/direct"
+	"gochen/observe"
+)
+
+type getBalance struct {
+	AccountID string
+}
+
+type getAuditLog struct{}
+
+func (getAuditLog) QueryPermission() string { return "audit.read" }
+
+func newBus(t *testing.T, calls *int) *query.QueryBus {
+	t.Helper()
+	bus := query.NewQueryBus()
+	require.NoError(t, query.RegisterFunc(bus, func(_ context.Context, q getBalance) (int, error) {
+		*calls++
+		if q.AccountID == "missing" {
+			return 0, errors.NewCode(errors.NotFound, "account not found")
+		}
+		return 100, nil
+	}))
+	require.NoError(t, query.RegisterFunc(bus, func(context.Context, getAuditLog) ([]string, error) {
+		*calls++
+		return []string{"login"}, nil
+	}))
+	return bus
+}
+
+func TestCachingMiddleware_CachesSuccessfulResults(t *testing.T) {
+	calls := 0
+	bus := newBus(t, &calls)
+	clk := clock.NewManualClock(time.Unix(1000, 0))
+	caching, err := NewCachingMiddleware(CachingConfig{TTL: time.Minute, Clock: clk})
+	require.NoError(t, err)
+	bus.Use(caching)
+
+	ctx := context.Background()
+	for range 2 {
+		balance, err := query.Ask[int](ctx, bus, getBalance{AccountID: "a-1"})
+		require.NoError(t, err)
+		assert.Equal(t, 100, balance)
+	}
+	assert.Equal(t, 1, calls)
+
+	// 不同参数使用不同缓存键；错误结果不缓存。
+	_, _ = query.Ask[int](ctx, bus, getBalance{AccountID: "missing"})
+	_, _ = query.Ask[int](ctx, bus, getBalance{AccountID: "missing"})
+	assert.Equal(t, 3, calls)
+
+	clk.Advance(2 * time.Minute)
+	_, err = query.Ask[int](ctx, bus, getBalance{AccountID: "a-1"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, calls)
+}
+
+func TestCachingMiddleware_OnlyListedQueries(t *testing.T) {
+	calls := 0
+	bus := newBus(t, &calls)
+	caching, err := NewCachingMiddleware(CachingConfig{Queries: []string{"getBalance"}})
+	require.NoError(t, err)
+	bus.Use(caching)
+
+	for range 2 {
+		_, err := query.Ask[[]string](context.Background(), bus, getAuditLog{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, calls)
+
+	caching.Clear()
+	assert.Equal(t, 0, caching.Stats().Size)
+}
+
+func TestCachingMiddleware_InvalidateQuery(t *testing.T) {
+	calls := 0
+	bus := newBus(t, &calls)
+	caching, err := NewCachingMiddleware(CachingConfig{})
+	require.NoError(t, err)
+	bus.Use(caching)
+
+	ctx := context.Background()
+	for _, id := range []string{"a-1", "a-2", "a-1", "a-2"} {
+		_, err := query.Ask[int](ctx, bus, getBalance{AccountID: id})
+		require.NoError(t, err)
+	}
+	_, err = query.Ask[[]string](ctx, bus, getAuditLog{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	assert.Equal(t, 2, caching.InvalidateQuery("getBalance"))
+	assert.Equal(t, 1, caching.Stats().Size)
+
+	_, err = query.Ask[int](ctx, bus, getBalance{AccountID: "a-1"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, calls)
+}
+
+func TestCachingMiddleware_InvalidationDuringQueryIsNotCached(t *testing.T) {
+	caching, err := NewCachingMiddleware(CachingConfig{})
+	require.NoError(t, err)
+
+	q := &query.Query{Name: "getBalance", Payload: getBalance{AccountID: "a-1"}}
+	calls := 0
+	next := func(context.Context, *query.Query) (any, error) {
+		calls++
+		// 读模型更新事件在查询执行期间到达。
+		caching.InvalidateQuery("getBalance")
+		return 100, nil
+	}
+	_, err = caching.Handle(context.Background(), q, next)
+	require.NoError(t, err)
+	_, err = caching.Handle(context.Background(), q, next)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 0, caching.Stats().Size)
+}
+
+func TestCachingMiddleware_SubscribeInvalidation(t *testing.T) {
+	calls := 0
+	qbus := newBus(t, &calls)
+	caching, err := NewCachingMiddleware(CachingConfig{
+		InvalidateOn: map[string][]string{"MoneyDeposited": {"getBalance"}},
+	})
+	require.NoError(t, err)
+	qbus.Use(caching)
+
+	ctx := context.Background()
+	transport := direct.NewSyncTransport()
+	require.NoError(t, transport.Start(ctx))
+	defer func() { _ = transport.Stop(ctx) }()
+	eventBus := bus.NewEventBus(messaging.NewMessageBus(transport))
+	unsubscribe, err := caching.SubscribeInvalidation(ctx, eventBus)
+	require.NoError(t, err)
+
+	ask := func() {
+		_, err := query.Ask[int](ctx, qbus, getBalance{AccountID: "a-1"})
+		require.NoError(t, err)
+	}
+	ask()
+	ask()
+	assert.Equal(t, 1, calls)
+
+	require.NoError(t, eventBus.PublishEvent(ctx, eventing.NewEvent[int64](1, "Account", "AccountOpened", 1, nil)))
+	ask()
+	assert.Equal(t, 1, calls)
+
+	require.NoError(t, eventBus.PublishEvent(ctx, eventing.NewEvent[int64](1, "Account", "MoneyDeposited", 2, nil)))
+	ask()
+	assert.Equal(t, 2, calls)
+
+	require.NoError(t, unsubscribe(ctx))
+	_, err = NewCachingMiddleware(CachingConfig{InvalidateOn: map[string][]string{"MoneyDeposited": nil}})
+	assert.True(t, errors.Is(err, errors.InvalidInput))
+}
+
+type recordingTracer struct {
+	observe.NoopTracer
+	spans []string
+}
+
+func (t *recordingTracer) StartSpan(ctx context.Context, name string, opts ...observe.SpanOption) (context.Context, observe.ISpan) {
+	t.spans = append(t.spans, name)
+	return t.NoopTracer.StartSpan(ctx, name, opts...)
+}
+
+func TestTracingMiddleware_StartsSpanPerQuery(t *testing.T) {
+	calls := 0
+	bus := newBus(t, &calls)
+	tracer := &recordingTracer{}
+	bus.Use(NewTracingMiddleware(tracer))
+
+	_, err := query.Ask[int](context.Background(), bus, getBalance{AccountID: "a-1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"query.getBalance"}, tracer.spans)
+}
+
+type stubAuthorizer struct {
+	granted     map[string]bool
+	permissions []string
+}
+
+func (a *stubAuthorizer) Authorize(context.Context, string, ...any) (auth.AuthzDecision, error) {
+	return auth.AuthzDecision{}, nil
+}
+
+func (a *stubAuthorizer) Require(_ context.Context, permission string, _ ...any) error {
+	a.permissions = append(a.permissions, permission)
+	if !a.granted[permission] {
+		return errors.NewCode(errors.Forbidden, "permission denied")
+	}
+	return nil
+}
+
+func TestAuthorizationMiddleware_RequiresMappedPermission(t *testing.T) {
+	calls := 0
+	bus := newBus(t, &calls)
+	authorizer := &stubAuthorizer{granted: map[string]bool{"balance.read": true}}
+	authz, err := NewAuthorizationMiddleware(AuthorizationConfig{
+		Authorizer:  authorizer,
+		Permissions: map[string]string{"getBalance": "balance.read"},
+	})
+	require.NoError(t, err)
+	bus.Use(authz)
+
+	_, err = query.Ask[int](context.Background(), bus, getBalance{AccountID: "a-1"})
+	require.NoError(t, err)
+
+	// 查询自行声明的权限优先。
+	_, err = query.Ask[[]string](context.Background(), bus, getAuditLog{})
+	assert.True(t, errors.Is(err, errors.Forbidden))
+	assert.Equal(t, []string{"balance.read", "audit.read"}, authorizer.permissions)
+	assert.Equal(t, 1, calls)
+}
+
+func TestAuthorizationMiddleware_DenyUnmapped(t *testing.T) {
+	calls := 0
+	bus := newBus(t, &calls)
+	authz, err := NewAuthorizationMiddleware(AuthorizationConfig{Authorizer: &stubAuthorizer{}, DenyUnmapped: true})
+	require.NoError(t, err)
+	bus.Use(authz)
+
+	_, err = query.Ask[int](context.Background(), bus, getBalance{AccountID: "a-1"})
+	assert.True(t, errors.Is(err, errors.Forbidden))
+	assert.Equal(t, 0, calls)
+
+	_, err = NewAuthorizationMiddleware(AuthorizationConfig{})
+	assert.True(t, errors.Is(err, errors.InvalidInput))
+}
+
+func TestCachingMiddleware_DefaultKeyIsolatesPrincipals(t *testing.T) {
+	calls := 0
+	bus := newBus(t, &calls)
+	caching, err := NewCachingMiddleware(CachingConfig{})
+	require.NoError(t, err)
+	bus.Use(caching)
+
+	alice, err := contextx.WithOperator(context.Background(), "alice")
+	require.NoError(t, err)
+	bob, err := contextx.WithOperator(context.Background(), "bob")
+	require.NoError(t, err)
+	for _, ctx := range []context.Context{alice, alice, bob} {
+		_, err := query.Ask[int](ctx, bus, getBalance{AccountID: "a-1"})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, calls, "different operators must not share cached results")
+
+	aliceKey, ok := SharedCacheKey(alice, &query.Query{Name: "getBalance", Payload: getBalance{AccountID: "a-1"}})
+	require.True(t, ok)
+	bobKey, ok := SharedCacheKey(bob, &query.Query{Name: "getBalance", Payload: getBalance{AccountID: "a-1"}})
+	require.True(t, ok)
+	assert.Equal(t, aliceKey, bobKey)
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+
+	"gochen/messaging/query"
+	"gochen/observe"
+)
+
+// TracingMiddleware 为每次查询创建 span（名称 "query.<查询名>"），并记录错误状态。
+type TracingMiddleware struct {
+	tracer observe.ITracer
+}
+
+// NewTracingMiddleware 创建查询追踪中间件；tracer 为 nil 时使用 NoopTracer。
+func NewTracingMiddleware(tracer observe.ITracer) *TracingMiddleware {
+	if tracer == nil {
+		tracer = observe.NewNoopTracer()
+	}
+	return &TracingMiddleware{tracer: tracer}
+}
+
+// Handle 在 span 内执行查询。
+func (m *TracingMiddleware) Handle(ctx context.Context, q *query.Query, next query.NextFunc) (any, error) {
+	ctx, span := m.tracer.StartSpan(ctx, "query."+q.Name,
+		observe.WithAttribute("query.name", q.Name))
+	defer span.End()
+
+	result, err := next(ctx, q)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus("error", err.Error())
+		return nil, err
+	}
+	span.SetStatus("ok", "")
+	return result, nil
+}
+
+// Name 返回中间件名称。
+func (m *TracingMiddleware) Name() string { return "QueryTracing" }
+
+var _ query.IMiddleware = (*TracingMiddleware)(nil)
@@ -0,0 +1,93 @@
+// Package query 是 CQRS 查询侧：以类型化 handler 统一访问读模型，并在调用链上提供缓存、追踪、授权等中间件。
+//
+// 与 messaging/command 的区别：
+//   - 查询在当前调用栈内同步执行并返回结果，不经过 MessageBus / transport；
+//   - handler 按查询的 Go 类型注册（IQueryHandler[Q, R]），调用方通过 Ask 获得类型化结果；
+//   - 每个查询类型有唯一的查询名（见 NameOf），用作缓存键、span 名与授权映射的稳定标识。
+//
+// 使用示例：
+//
+//	bus := query.NewQueryBus()
+//	bus.Use(middleware.NewTracingMiddleware(tracer))
+//	_ = query.RegisterFunc(bus, func(ctx context.Context, q GetOrder) (*OrderView, error) {
+//	    return views.Get(ctx, q.OrderID)
+//	})
+//
+//	view, err := query.Ask[*OrderView](ctx, bus, GetOrder{OrderID: "o-1"})
+package query
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+// IQueryHandler 是类型化查询处理器：Q 为查询参数，R 为读模型结果。
+type IQueryHandler[Q any, R any] interface {
+	Handle(ctx context.Context, query Q) (R, error)
+}
+
+// HandlerFunc 允许使用函数直接实现 IQueryHandler。
+type HandlerFunc[Q any, R any] func(ctx context.Context, query Q) (R, error)
+
+// Handle 执行查询。
+func (f HandlerFunc[Q, R]) Handle(ctx context.Context, query Q) (R, error) {
+	return f(ctx, query)
+}
+
+// INamedQuery 可选：查询类型自定义查询名（默认取 Go 类型名）。
+type INamedQuery interface {
+	QueryName() string
+}
+
+// Query 是中间件看到的一次查询调用。
+type Query struct {
+	// Name 查询名（见 NameOf）。
+	Name string
+	// Payload 查询参数（注册时的 Q 类型值）。
+	Payload any
+}
+
+// NextFunc 是中间件链中的下一个处理函数。
+type NextFunc func(ctx context.Context, q *Query) (any, error)
+
+// IMiddleware 是查询执行侧中间件。
+//
+// 中间件可以短路返回结果（例如缓存命中），也可以在调用 next 前后附加逻辑。
+type IMiddleware interface {
+	Handle(ctx context.Context, q *Query, next NextFunc) (any, error)
+	Name() string
+}
+
+// IQueryDispatcher 抽象“执行查询并返回结果”端口，HTTP handler 等调用方应依赖它而不是具体仓储。
+type IQueryDispatcher interface {
+	Dispatch(ctx context.Context, query any) (any, error)
+}
+
+// IQueryRegistrar 由读模型模块实现，用于把本模块的查询处理器注册到 QueryBus。
+type IQueryRegistrar interface {
+	RegisterQueries(bus *QueryBus) error
+}
+
+// NameOf 返回查询名：实现 INamedQuery 时取 QueryName()，否则取去掉指针后的 Go 类型名。
+func NameOf(query any) string {
+	if named, ok := query.(INamedQuery); ok {
+		if name := strings.TrimSpace(named.QueryName()); name != "" {
+			return name
+		}
+	}
+	return typeName(reflect.TypeOf(query))
+}
+
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Name() != "" {
+		return t.Name()
+	}
+	return t.String()
+}
@@ -0,0 +1,242 @@
+package messaging
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	gerrors "gochen/errors"
+	"gochen/ident/uuid"
+)
+
+const (
+	// MetadataReplyToKey 是请求消息携带的应答主题（应答消息的 Type）。
+	MetadataReplyToKey = "reply_to"
+	// MetadataInReplyToKey 是应答消息携带的请求消息 ID，用于把应答路由回等待中的请求。
+	MetadataInReplyToKey = "in_reply_to"
+	// MetadataReplyErrorCodeKey / MetadataReplyErrorKey 表示应答方处理失败时的错误码与错误信息。
+	MetadataReplyErrorCodeKey = "reply_error_code"
+	MetadataReplyErrorKey     = "reply_error"
+
+	// ReplyTopicPrefix 是自动生成的应答主题前缀（"_reply." + 随机 ID）。
+	ReplyTopicPrefix = "_reply."
+)
+
+// IRequester 抽象请求/应答能力；MessageBus 实现了该接口。
+type IRequester interface {
+	Request(ctx context.Context, message IMessage, timeout time.Duration) (IMessage, error)
+}
+
+// ReplyTo 返回请求消息的应答主题；非请求消息返回 ("", false)。
+func ReplyTo(message IMessage) (string, bool) {
+	if message == nil {
+		return "", false
+	}
+	replyTo, ok := message.GetMetadata().Get(MetadataReplyToKey)
+	if !ok || strings.TrimSpace(replyTo) == "" {
+		return "", false
+	}
+	return replyTo, true
+}
+
+// NewReply 为请求消息创建应答；应答方通过 IMessageBus.Publish 发布即可送达请求方。
+func NewReply(request IMessage, replyID string, payload any) (*Message, error) {
+	if request == nil {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "request message is nil")
+	}
+	replyTo, ok := ReplyTo(request)
+	if !ok {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "message does not expect a reply").
+			WithContext("message_id", request.GetID()).
+			WithContext("message_type", request.GetType())
+	}
+	if strings.TrimSpace(replyID) == "" {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "reply id is required")
+	}
+	reply := NewMessage(replyID, KindReply, replyTo, payload)
+	reply.SetMetadata(MetadataInReplyToKey, request.GetID())
+	return reply, nil
+}
+
+// NewErrorReply 为请求消息创建失败应答，请求方的 Request 会返回保留错误码的错误。
+func NewErrorReply(request IMessage, replyID string, err error) (*Message, error) {
+	reply, rerr := NewReply(request, replyID, nil)
+	if rerr != nil {
+		return nil, rerr
+	}
+	if err == nil {
+		err = gerrors.NewCode(gerrors.Internal, "request failed")
+	}
+	reply.SetMetadata(MetadataReplyErrorCodeKey, string(gerrors.Code(err)))
+	reply.SetMetadata(MetadataReplyErrorKey, err.Error())
+	return reply, nil
+}
+
+// ReplyError 返回应答携带的错误；成功应答返回 nil。
+func ReplyError(reply IMessage) error {
+	if reply == nil {
+		return nil
+	}
+	md := reply.GetMetadata()
+	code, ok := md.Get(MetadataReplyErrorCodeKey)
+	if !ok || code == "" {
+		return nil
+	}
+	message, _ := md.Get(MetadataReplyErrorKey)
+	inReplyTo, _ := md.Get(MetadataInReplyToKey)
+	return gerrors.NewCode(gerrors.ErrorCode(code), message).WithContext("in_reply_to", inReplyTo)
+}
+
+// SetReplyTopic 指定本总线的应答主题，必须在第一次 Request 之前调用。
+//
+// 未设置时使用 ReplyTopicPrefix + 随机 ID。应答主题即应答消息的 Type，需要能路由回本实例：
+// memory/direct 直接按 Type 分发；RabbitMQ 需绑定到实例独占的队列；SQS 传输把应答发送到该主题表示的队列 URL。
+func (bus *MessageBus) SetReplyTopic(topic string) error {
+	if bus == nil {
+		return gerrors.NewCode(gerrors.InvalidInput, "bus is nil")
+	}
+	if strings.TrimSpace(topic) == "" {
+		return gerrors.NewCode(gerrors.InvalidInput, "reply topic cannot be empty")
+	}
+	bus.replyMutex.Lock()
+	defer bus.replyMutex.Unlock()
+	if bus.replies != nil {
+		return gerrors.NewCode(gerrors.Conflict, "reply topic is already in use").
+			WithContext("reply_topic", bus.replies.topic)
+	}
+	bus.replyTopic = topic
+	return nil
+}
+
+// Request 发布请求消息并等待对应的应答。
+//
+// 请求消息的 ID 作为关联 ID：发布前写入 reply_to，应答方用 NewReply/NewErrorReply 构造应答并发布；
+// 应答按 in_reply_to 路由回本次调用。timeout <= 0 时只受 ctx 控制。
+// 超时返回 errors.Timeout；应答方返回失败应答时同时返回应答消息与 ReplyError(reply)。
+func (bus *MessageBus) Request(ctx context.Context, message IMessage, timeout time.Duration) (IMessage, error) {
+	if bus == nil {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "bus is nil")
+	}
+	if ctx == nil {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "ctx is nil")
+	}
+	if message == nil {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "message is nil")
+	}
+	requestID := strings.TrimSpace(message.GetID())
+	if requestID == "" {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "request message id is required")
+	}
+
+	router, err := bus.replyRouter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	replies, err := router.register(requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer router.release(requestID)
+
+	waitCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	message.GetMetadata().Set(MetadataReplyToKey, router.topic)
+	if err := bus.Publish(waitCtx, message); err != nil {
+		return nil, err
+	}
+
+	select {
+	case reply := <-replies:
+		return reply, ReplyError(reply)
+	case <-waitCtx.Done():
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, gerrors.NewCode(gerrors.Timeout, "request timed out waiting for reply").
+			WithContext("message_id", requestID).
+			WithContext("message_type", message.GetType()).
+			WithContext("timeout", timeout.String())
+	}
+}
+
+// replyRouter 惰性订阅本总线的应答主题。
+func (bus *MessageBus) replyRouter(ctx context.Context) (*replyRouter, error) {
+	bus.replyMutex.Lock()
+	defer bus.replyMutex.Unlock()
+	if bus.replies != nil {
+		return bus.replies, nil
+	}
+	topic := bus.replyTopic
+	if topic == "" {
+		id, err := uuid.New()
+		if err != nil {
+			return nil, err
+		}
+		topic = ReplyTopicPrefix + id
+	}
+	router := &replyRouter{topic: topic, pending: make(map[string]chan IMessage)}
+	if _, err := bus.Subscribe(ctx, topic, router); err != nil {
+		return nil, gerrors.Wrap(err, gerrors.Code(err), "subscribe reply topic failed").
+			WithContext("reply_topic", topic)
+	}
+	bus.replies = router
+	return router, nil
+}
+
+// replyRouter 按 in_reply_to 把应答投递给等待中的请求；迟到或重复的应答直接丢弃。
+type replyRouter struct {
+	topic   string
+	mutex   sync.Mutex
+	pending map[string]chan IMessage
+}
+
+func (r *replyRouter) register(requestID string) (<-chan IMessage, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, exists := r.pending[requestID]; exists {
+		return nil, gerrors.NewCode(gerrors.Conflict, "request with the same id is already waiting for a reply").
+			WithContext("message_id", requestID)
+	}
+	ch := make(chan IMessage, 1)
+	r.pending[requestID] = ch
+	return ch, nil
+}
+
+func (r *replyRouter) release(requestID string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.pending, requestID)
+}
+
+// Handle 实现 IMessageHandler。
+func (r *replyRouter) Handle(_ context.Context, message IMessage) error {
+	if message == nil {
+		return nil
+	}
+	requestID, ok := message.GetMetadata().Get(MetadataInReplyToKey)
+	if !ok {
+		return nil
+	}
+	r.mutex.Lock()
+	ch, exists := r.pending[requestID]
+	r.mutex.Unlock()
+	if !exists {
+		return nil
+	}
+	select {
+	case ch <- message:
+	default:
+	}
+	return nil
+}
+
+// Type 返回处理器类型。
+func (r *replyRouter) Type() string { return "messaging.reply_router" }
+
+var _ IRequester = (*MessageBus)(nil)
@@ -0,0 +1,124 @@
+package messaging_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/messaging"
+	synctransport "gochen/messaging/transport/direct"
+	"gochen/messaging/transport/memory"
+)
+
+// quoteHandler 按请求载荷应答报价；sku 为 "unknown" 时返回失败应答，为 "silent" 时不应答。
+type quoteHandler struct {
+	bus *messaging.MessageBus
+}
+
+func (h *quoteHandler) Handle(ctx context.Context, message messaging.IMessage) error {
+	var req struct{ SKU string }
+	if err := message.GetPayload().DecodeTo(&req); err != nil {
+		return err
+	}
+	var reply *messaging.Message
+	var err error
+	switch req.SKU {
+	case "silent":
+		return nil
+	case "unknown":
+		reply, err = messaging.NewErrorReply(message, "reply-"+message.GetID(), errors.NewCode(errors.NotFound, "sku not found"))
+	default:
+		reply, err = messaging.NewReply(message, "reply-"+message.GetID(), map[string]any{"sku": req.SKU, "price": 42})
+	}
+	if err != nil {
+		return err
+	}
+	return h.bus.Publish(ctx, reply)
+}
+
+func (h *quoteHandler) Type() string { return "quote" }
+
+func newQuoteBus(t *testing.T, transport messaging.ITransport) *messaging.MessageBus {
+	t.Helper()
+	bus := messaging.NewMessageBus(transport)
+	_, err := bus.Subscribe(context.Background(), "GetQuote", &quoteHandler{bus: bus})
+	require.NoError(t, err)
+	require.NoError(t, transport.Start(context.Background()))
+	t.Cleanup(func() { _ = messaging.StopTransport(context.Background(), transport) })
+	return bus
+}
+
+func quoteRequest(id, sku string) *messaging.Message {
+	return messaging.NewMessage(id, messaging.KindQuery, "GetQuote", map[string]any{"sku": sku})
+}
+
+func TestMessageBus_RequestReply(t *testing.T) {
+	transports := map[string]messaging.ITransport{
+		"memory": memory.NewMemoryTransport(16, 2),
+		"direct": synctransport.NewSyncTransport(),
+	}
+	for name, transport := range transports {
+		t.Run(name, func(t *testing.T) {
+			bus := newQuoteBus(t, transport)
+
+			reply, err := bus.Request(context.Background(), quoteRequest("q1", "apple"), time.Second)
+			require.NoError(t, err)
+			require.Equal(t, messaging.KindReply, reply.GetKind())
+			require.True(t, strings.HasPrefix(reply.GetType(), messaging.ReplyTopicPrefix))
+			inReplyTo, _ := reply.GetMetadata().Get(messaging.MetadataInReplyToKey)
+			require.Equal(t, "q1", inReplyTo)
+			var quote struct{ Price int }
+			require.NoError(t, reply.GetPayload().DecodeTo(&quote))
+			require.Equal(t, 42, quote.Price)
+
+			reply, err = bus.Request(context.Background(), quoteRequest("q2", "unknown"), time.Second)
+			require.True(t, errors.Is(err, errors.NotFound))
+			require.Equal(t, "reply-q2", reply.GetID())
+		})
+	}
+}
+
+func TestMessageBus_RequestConcurrentCorrelation(t *testing.T) {
+	bus := newQuoteBus(t, memory.NewMemoryTransport(64, 4))
+
+	skus := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	var wg sync.WaitGroup
+	for _, sku := range skus {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reply, err := bus.Request(context.Background(), quoteRequest("q-"+sku, sku), time.Second)
+			require.NoError(t, err)
+			var quote struct{ SKU string }
+			require.NoError(t, reply.GetPayload().DecodeTo(&quote))
+			require.Equal(t, sku, quote.SKU)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestMessageBus_RequestTimeoutAndValidation(t *testing.T) {
+	bus := newQuoteBus(t, memory.NewMemoryTransport(16, 1))
+
+	_, err := bus.Request(context.Background(), quoteRequest("q1", "silent"), 20*time.Millisecond)
+	require.True(t, errors.Is(err, errors.Timeout))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = bus.Request(ctx, quoteRequest("q2", "silent"), time.Second)
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = bus.Request(context.Background(), quoteRequest("", "apple"), time.Second)
+	require.True(t, errors.Is(err, errors.InvalidInput))
+
+	// 第一次 Request 之后应答主题已固定。
+	require.True(t, errors.Is(bus.SetReplyTopic("replies.web-1"), errors.Conflict))
+
+	_, err = messaging.NewReply(quoteRequest("q3", "apple"), "r3", nil)
+	require.True(t, errors.Is(err, errors.InvalidInput))
+}
@@ -0,0 +1,145 @@
+package schedule
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"gochen/errors"
+	"gochen/ident/uuid"
+)
+
+type memoryEntry struct {
+	entry      Entry
+	leaseUntil time.Time
+}
+
+// MemoryStore 是 IStore 的内存实现（单进程/测试使用，进程重启后计划丢失）。
+//
+// 与 SQLStore 不同，消息按原对象保存，不经过编码。
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+}
+
+// NewMemoryStore 创建内存定时消息存储。
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]*memoryEntry)}
+}
+
+// Schedule 保存定时消息。
+func (s *MemoryStore) Schedule(ctx context.Context, entry *Entry) error {
+	if entry == nil || entry.ID == "" || entry.Message == nil {
+		return errors.NewCode(errors.InvalidInput, "scheduled entry is nil or incomplete")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.entries[entry.ID]; exists {
+		return errors.NewCode(errors.Conflict, "message already scheduled").WithContext("message_id", entry.ID)
+	}
+	copied := *entry
+	copied.ClaimToken = ""
+	s.entries[entry.ID] = &memoryEntry{entry: copied}
+	return nil
+}
+
+// ClaimDue claim 到期消息。
+func (s *MemoryStore) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*Entry, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	due := make([]*memoryEntry, 0)
+	for _, item := range s.entries {
+		if !item.entry.DeliverAt.After(now) && !item.leaseUntil.After(now) {
+			due = append(due, item)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].entry.DeliverAt.Equal(due[j].entry.DeliverAt) {
+			return due[i].entry.DeliverAt.Before(due[j].entry.DeliverAt)
+		}
+		return due[i].entry.ID < due[j].entry.ID
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]*Entry, 0, len(due))
+	for _, item := range due {
+		token, err := uuid.New()
+		if err != nil {
+			return nil, errors.Wrap(err, errors.Internal, "generate claim token failed")
+		}
+		item.entry.ClaimToken = token
+		item.leaseUntil = now.Add(lease)
+		copied := item.entry
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+
+// Complete 移除已投递的消息。
+func (s *MemoryStore) Complete(ctx context.Context, entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.ownedLocked(entry); err != nil {
+		return err
+	}
+	delete(s.entries, entry.ID)
+	return nil
+}
+
+// Retry 推迟消息并释放 claim。
+func (s *MemoryStore) Retry(ctx context.Context, entry *Entry, nextAt time.Time, cause error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, err := s.ownedLocked(entry)
+	if err != nil {
+		return err
+	}
+	item.entry.Attempts++
+	item.entry.DeliverAt = nextAt
+	item.entry.ClaimToken = ""
+	item.leaseUntil = time.Time{}
+	if cause != nil {
+		item.entry.LastError = cause.Error()
+	}
+	return nil
+}
+
+// Cancel 取消尚未投递的消息。
+func (s *MemoryStore) Cancel(ctx context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.entries[id]
+	if !ok || item.entry.ClaimToken != "" {
+		return false, nil
+	}
+	delete(s.entries, id)
+	return true, nil
+}
+
+// Pending 返回计划中的消息数量（测试用）。
+func (s *MemoryStore) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+func (s *MemoryStore) ownedLocked(entry *Entry) (*memoryEntry, error) {
+	if entry == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "scheduled entry cannot be nil")
+	}
+	item, ok := s.entries[entry.ID]
+	if !ok || entry.ClaimToken == "" || item.entry.ClaimToken != entry.ClaimToken {
+		return nil, errors.NewCode(errors.Conflict, "scheduled message claim is no longer owned").
+			WithContext("message_id", entry.ID)
+	}
+	return item, nil
+}
+
+var _ IStore = (*MemoryStore)(nil)
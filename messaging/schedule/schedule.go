@@ -0,0 +1,57 @@
+// Package schedule 提供定时/延迟消息投递：PublishAt / PublishAfter 把消息暂存到 IStore，
+// 到期后由 Scheduler 发布到消息总线，供 Saga、流程管理器设置提醒与超时，无需外部 cron。
+//
+// 投递语义：
+//   - 调度键为消息 ID：同一 ID 重复调度视为幂等（保留已有计划），Cancel 按消息 ID 取消；
+//   - 到期消息先被 claim（带租约）再发布，发布失败按指数退避重试，超过 MaxAttempts 后写入死信并移除；
+//   - 进程在发布后、确认前崩溃时，租约到期后消息会被再次发布（至少一次），处理方需按消息 ID 幂等。
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"gochen/messaging"
+)
+
+// Entry 是一条待投递的定时消息。
+type Entry struct {
+	// ID 调度键，等于消息 ID。
+	ID string
+
+	// Message 到期后发布的消息。
+	Message messaging.IMessage
+
+	// DeliverAt 计划投递时间（失败重试时会被推后）。
+	DeliverAt time.Time
+
+	// Attempts 已失败的投递次数。
+	Attempts int
+
+	// LastError 最近一次投递失败原因。
+	LastError string
+
+	// CreatedAt 调度时间。
+	CreatedAt time.Time
+
+	// ClaimToken 当前 claim 的令牌，由 IStore.ClaimDue 设置。
+	ClaimToken string
+}
+
+// IStore 抽象定时消息的持久化。
+type IStore interface {
+	// Schedule 保存定时消息；相同 ID 已存在时返回 errors.Conflict。
+	Schedule(ctx context.Context, entry *Entry) error
+
+	// ClaimDue 以租约 claim 最多 limit 条 DeliverAt <= now 且未被占用（或租约已过期）的消息，按 DeliverAt 升序返回。
+	ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*Entry, error)
+
+	// Complete 移除已投递（或已放弃）的消息；claim 已失效时返回 errors.Conflict。
+	Complete(ctx context.Context, entry *Entry) error
+
+	// Retry 释放 claim 并把消息推迟到 nextAt，同时记录失败次数与原因；claim 已失效时返回 errors.Conflict。
+	Retry(ctx context.Context, entry *Entry, nextAt time.Time, cause error) error
+
+	// Cancel 取消尚未投递的消息；返回 false 表示不存在或正在投递中。
+	Cancel(ctx context.Context, id string) (bool, error)
+}
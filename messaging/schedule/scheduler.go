@@ -0,0 +1,297 @@
+package schedule
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/messaging/deadletter"
+	"gochen/policy/retry"
+)
+
+// handlerType 是写入死信记录的处理器类型。
+const handlerType = "messaging.schedule"
+
+// Config 定义定时投递调度器配置。
+type Config struct {
+	// PollInterval 到期扫描间隔（默认：1s），决定投递时间的精度。
+	PollInterval time.Duration
+
+	// BatchSize 单次扫描 claim 的最大条数（默认：100）。
+	BatchSize int
+
+	// ClaimLease claim 租约（默认：1m）；实例崩溃后租约到期的消息会被其他实例重新投递。
+	ClaimLease time.Duration
+
+	// MaxAttempts 最大投递次数（默认：10）；超过后写入死信并移除。
+	MaxAttempts int
+
+	// RetryBackoff / MaxRetryBackoff 发布失败后的指数退避起点与上限（默认：1s / 5m）。
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+
+	// DeadLetter 可选：超过 MaxAttempts 的消息写入死信；未配置时仅记录错误日志。
+	DeadLetter deadletter.ISink
+
+	Clock  clock.IClock
+	Logger logging.ILogger
+}
+
+// Scheduler 定时/延迟消息调度器：PublishAt/PublishAfter 写入 IStore，后台循环把到期消息发布到消息总线。
+type Scheduler struct {
+	store  IStore
+	bus    messaging.IMessageBus
+	config Config
+	clock  clock.IClock
+	logger logging.ILogger
+
+	dispatchMu sync.Mutex
+
+	mu        sync.Mutex
+	started   bool
+	stopped   bool
+	runCancel context.CancelFunc
+	doneCh    chan struct{}
+}
+
+// NewScheduler 创建调度器；cfg 为 nil 时使用默认配置。
+func NewScheduler(store IStore, bus messaging.IMessageBus, cfg *Config) (*Scheduler, error) {
+	if store == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "schedule store cannot be nil")
+	}
+	if bus == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "message bus cannot be nil")
+	}
+	config := Config{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.ClaimLease <= 0 {
+		config.ClaimLease = time.Minute
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 10
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = time.Second
+	}
+	if config.MaxRetryBackoff <= 0 {
+		config.MaxRetryBackoff = 5 * time.Minute
+	}
+	if config.Clock == nil {
+		config.Clock = clock.NewRealClock()
+	}
+	if config.Logger == nil {
+		config.Logger = logging.ComponentLogger("messaging.schedule")
+	}
+	return &Scheduler{
+		store:  store,
+		bus:    bus,
+		config: config,
+		clock:  config.Clock,
+		logger: config.Logger,
+		doneCh: make(chan struct{}),
+	}, nil
+}
+
+// PublishAt 计划在 at 时刻发布消息；at 早于当前时间时在下一次扫描发布。
+//
+// 消息 ID 为调度键且不能为空；同一 ID 已在计划中时保留原计划并返回 nil。
+func (s *Scheduler) PublishAt(ctx context.Context, msg messaging.IMessage, at time.Time) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	if msg == nil {
+		return errors.NewCode(errors.InvalidInput, "message cannot be nil")
+	}
+	if msg.GetID() == "" {
+		return errors.NewCode(errors.InvalidInput, "scheduled message id cannot be empty").
+			WithContext("message_type", msg.GetType())
+	}
+	err := s.store.Schedule(ctx, &Entry{
+		ID:        msg.GetID(),
+		Message:   msg,
+		DeliverAt: at,
+		CreatedAt: s.clock.Now(),
+	})
+	if errors.Is(err, errors.Conflict) {
+		s.logger.Debug(ctx, "message already scheduled",
+			logging.String("message_id", msg.GetID()),
+			logging.String("message_type", msg.GetType()))
+		return nil
+	}
+	return err
+}
+
+// PublishAfter 计划在 delay 之后发布消息。
+func (s *Scheduler) PublishAfter(ctx context.Context, msg messaging.IMessage, delay time.Duration) error {
+	return s.PublishAt(ctx, msg, s.clock.Now().Add(delay))
+}
+
+// Cancel 取消尚未投递的消息；返回 false 表示不存在、已投递或正在投递。
+func (s *Scheduler) Cancel(ctx context.Context, messageID string) (bool, error) {
+	if messageID == "" {
+		return false, errors.NewCode(errors.InvalidInput, "scheduled message id cannot be empty")
+	}
+	return s.store.Cancel(ctx, messageID)
+}
+
+// Start 启动后台扫描循环；重复调用为 no-op，Stop 之后不可再次启动。
+func (s *Scheduler) Start(ctx context.Context) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	ticker, err := s.clock.NewTicker(s.config.PollInterval)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		ticker.Stop()
+		return errors.NewCode(errors.InvalidInput, "scheduler has been stopped; create a new instance")
+	}
+	if s.started {
+		s.mu.Unlock()
+		ticker.Stop()
+		return nil
+	}
+	s.started = true
+	runCtx, cancel := context.WithCancel(ctx)
+	s.runCancel = cancel
+	s.mu.Unlock()
+
+	go s.loop(runCtx, ticker)
+	return nil
+}
+
+// Stop 停止后台循环并等待当前一轮投递收尾。
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	s.mu.Lock()
+	if !s.started || s.stopped {
+		// Stop-before-Start 与重复 Stop 均为 no-op。
+		s.mu.Unlock()
+		return nil
+	}
+	s.stopped = true
+	cancel := s.runCancel
+	s.runCancel = nil
+	s.mu.Unlock()
+
+	cancel()
+	select {
+	case <-s.doneCh:
+		return nil
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.NewCode(errors.Timeout, "scheduler stop timeout")
+		}
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, ticker clock.ITicker) {
+	defer func() {
+		ticker.Stop()
+		close(s.doneCh)
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			if _, err := s.DispatchDue(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Error(ctx, "dispatch scheduled messages failed", logging.Error(err))
+			}
+		}
+	}
+}
+
+// DispatchDue 立即投递一批到期消息，返回成功发布的条数（后台循环每个周期调用一次）。
+//
+// 单条消息发布失败不会中断本批次，而是按退避策略推迟；只有存储错误会返回。
+func (s *Scheduler) DispatchDue(ctx context.Context) (int, error) {
+	if ctx == nil {
+		return 0, errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	s.dispatchMu.Lock()
+	defer s.dispatchMu.Unlock()
+
+	entries, err := s.store.ClaimDue(ctx, s.clock.Now(), s.config.BatchSize, s.config.ClaimLease)
+	if err != nil {
+		return 0, err
+	}
+	published := 0
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return published, ctx.Err()
+		}
+		if err := s.bus.Publish(ctx, entry.Message); err != nil {
+			if storeErr := s.handlePublishFailure(ctx, entry, err); storeErr != nil {
+				if !errors.Is(storeErr, errors.Conflict) {
+					return published, storeErr
+				}
+				// claim 已被其他实例接管（租约过期），由接管方负责后续投递。
+				s.logger.Warn(ctx, "scheduled message claim lost", logging.Error(storeErr),
+					logging.String("message_id", entry.ID))
+			}
+			continue
+		}
+		published++
+		if err := s.store.Complete(ctx, entry); err != nil {
+			// 已发布但未能移除：租约到期后会再次投递，依赖处理方按消息 ID 幂等。
+			s.logger.Warn(ctx, "complete scheduled message failed", logging.Error(err),
+				logging.String("message_id", entry.ID))
+		}
+	}
+	return published, nil
+}
+
+func (s *Scheduler) handlePublishFailure(ctx context.Context, entry *Entry, cause error) error {
+	attempts := entry.Attempts + 1
+	fields := []logging.Field{
+		logging.Error(cause),
+		logging.String("message_id", entry.ID),
+		logging.String("message_type", entry.Message.GetType()),
+		logging.Int("attempts", attempts),
+	}
+	if attempts < s.config.MaxAttempts {
+		s.logger.Warn(ctx, "publish scheduled message failed, will retry", fields...)
+		delay := retry.ComputeDelay(retry.Config{
+			MaxAttempts:   s.config.MaxAttempts,
+			InitialDelay:  s.config.RetryBackoff,
+			BackoffFactor: 2,
+			MaxDelay:      s.config.MaxRetryBackoff,
+		}, attempts)
+		return s.store.Retry(ctx, entry, s.clock.Now().Add(delay), cause)
+	}
+
+	s.logger.Error(ctx, "publish scheduled message failed, giving up", fields...)
+	if s.config.DeadLetter != nil {
+		if err := s.config.DeadLetter.Write(ctx, deadletter.Entry{
+			Message:     entry.Message,
+			HandlerType: handlerType,
+			Err:         cause,
+			OccurredAt:  s.clock.Now(),
+		}); err != nil {
+			// 死信写入失败时保留消息，等待下次重试。
+			s.logger.Error(ctx, "dead-letter scheduled message failed", logging.Error(err),
+				logging.String("message_id", entry.ID))
+			return s.store.Retry(ctx, entry, s.clock.Now().Add(s.config.MaxRetryBackoff), cause)
+		}
+	}
+	return s.store.Complete(ctx, entry)
+}
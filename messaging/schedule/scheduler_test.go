@@ -0,0 +1,177 @@
+package schedule
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/messaging"
+	dlqmemory "gochen/messaging/deadletter/memory"
+)
+
+type recordingBus struct {
+	mu        sync.Mutex
+	published []messaging.IMessage
+	failures  int
+}
+
+func (b *recordingBus) Subscribe(context.Context, string, messaging.IMessageHandler) (messaging.UnsubscribeFunc, error) {
+	return func(context.Context) error { return nil }, nil
+}
+
+func (b *recordingBus) Publish(_ context.Context, message messaging.IMessage) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures > 0 {
+		b.failures--
+		return errors.NewCode(errors.ServiceUnavailable, "transport unavailable")
+	}
+	b.published = append(b.published, message)
+	return nil
+}
+
+func (b *recordingBus) PublishAll(ctx context.Context, messages []messaging.IMessage) error {
+	for _, msg := range messages {
+		if err := b.Publish(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *recordingBus) Use(messaging.IMiddleware) {}
+
+func (b *recordingBus) ids() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ids := make([]string, 0, len(b.published))
+	for _, msg := range b.published {
+		ids = append(ids, msg.GetID())
+	}
+	return ids
+}
+
+func newTestScheduler(t *testing.T, bus messaging.IMessageBus, cfg Config) (*Scheduler, *MemoryStore, *clock.ManualClock) {
+	t.Helper()
+	clk := clock.NewManualClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	cfg.Clock = clk
+	store := NewMemoryStore()
+	scheduler, err := NewScheduler(store, bus, &cfg)
+	require.NoError(t, err)
+	return scheduler, store, clk
+}
+
+// TestScheduler_DeliversDueMessagesInOrder 验证消息在到期后按计划时间顺序发布，重复调度保持幂等。
+func TestScheduler_DeliversDueMessagesInOrder(t *testing.T) {
+	ctx := context.Background()
+	bus := &recordingBus{}
+	scheduler, store, clk := newTestScheduler(t, bus, Config{})
+
+	require.NoError(t, scheduler.PublishAfter(ctx, messaging.NewMessage("late", messaging.KindEvent, "Reminder", nil), 2*time.Minute))
+	require.NoError(t, scheduler.PublishAfter(ctx, messaging.NewMessage("early", messaging.KindEvent, "Reminder", nil), time.Minute))
+	require.NoError(t, scheduler.PublishAfter(ctx, messaging.NewMessage("early", messaging.KindEvent, "Reminder", nil), time.Hour))
+	require.Equal(t, 2, store.Pending())
+
+	published, err := scheduler.DispatchDue(ctx)
+	require.NoError(t, err)
+	require.Zero(t, published)
+
+	clk.Advance(90 * time.Second)
+	published, err = scheduler.DispatchDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, published)
+
+	clk.Advance(time.Minute)
+	_, err = scheduler.DispatchDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"early", "late"}, bus.ids())
+	require.Zero(t, store.Pending())
+}
+
+// TestScheduler_Cancel 验证取消后的消息不会发布。
+func TestScheduler_Cancel(t *testing.T) {
+	ctx := context.Background()
+	bus := &recordingBus{}
+	scheduler, _, clk := newTestScheduler(t, bus, Config{})
+
+	require.NoError(t, scheduler.PublishAfter(ctx, messaging.NewMessage("timeout-1", messaging.KindCommand, "ExpireOrder", nil), time.Minute))
+	cancelled, err := scheduler.Cancel(ctx, "timeout-1")
+	require.NoError(t, err)
+	require.True(t, cancelled)
+
+	cancelled, err = scheduler.Cancel(ctx, "timeout-1")
+	require.NoError(t, err)
+	require.False(t, cancelled)
+
+	clk.Advance(time.Hour)
+	published, err := scheduler.DispatchDue(ctx)
+	require.NoError(t, err)
+	require.Zero(t, published)
+	require.Empty(t, bus.ids())
+}
+
+// TestScheduler_RetriesThenDeadLetters 验证发布失败按退避重试，超过最大次数后写入死信。
+func TestScheduler_RetriesThenDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	bus := &recordingBus{failures: 3}
+	sink := dlqmemory.NewSink()
+	scheduler, store, clk := newTestScheduler(t, bus, Config{
+		MaxAttempts:  3,
+		RetryBackoff: time.Second,
+		DeadLetter:   sink,
+	})
+
+	require.NoError(t, scheduler.PublishAt(ctx, messaging.NewMessage("m1", messaging.KindEvent, "Reminder", nil), clk.Now()))
+
+	_, err := scheduler.DispatchDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, store.Pending())
+
+	// 退避未到期时不会再次投递。
+	published, err := scheduler.DispatchDue(ctx)
+	require.NoError(t, err)
+	require.Zero(t, published)
+
+	for range 2 {
+		clk.Advance(time.Minute)
+		_, err = scheduler.DispatchDue(ctx)
+		require.NoError(t, err)
+	}
+	require.Zero(t, store.Pending())
+	require.Empty(t, bus.ids())
+	entries := sink.Entries()
+	require.Len(t, entries, 1)
+	require.Equal(t, "m1", entries[0].Message.GetID())
+	require.Equal(t, handlerType, entries[0].HandlerType)
+}
+
+// TestScheduler_StartStop 验证后台循环按扫描间隔投递到期消息。
+func TestScheduler_StartStop(t *testing.T) {
+	ctx := context.Background()
+	bus := &recordingBus{}
+	scheduler, _, clk := newTestScheduler(t, bus, Config{PollInterval: time.Second})
+
+	require.NoError(t, scheduler.Stop(ctx))
+	require.NoError(t, scheduler.PublishAfter(ctx, messaging.NewMessage("m1", messaging.KindEvent, "Reminder", nil), 500*time.Millisecond))
+	require.NoError(t, scheduler.Start(ctx))
+
+	require.Eventually(t, func() bool {
+		clk.Advance(time.Second)
+		return len(bus.ids()) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, scheduler.Stop(ctx))
+	require.Error(t, scheduler.Start(ctx))
+}
+
+// TestScheduler_PublishAtRequiresMessageID 验证消息 ID 为空时拒绝调度。
+func TestScheduler_PublishAtRequiresMessageID(t *testing.T) {
+	scheduler, _, clk := newTestScheduler(t, &recordingBus{}, Config{})
+	err := scheduler.PublishAt(context.Background(), messaging.NewMessage("", messaging.KindEvent, "Reminder", nil), clk.Now())
+	require.True(t, errors.Is(err, errors.InvalidInput))
+}
@@ -0,0 +1,389 @@
+package schedule
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"gochen/db"
+	"gochen/db/dialect"
+	"gochen/db/sql/safeident"
+	"gochen/db/sql/sqlbuilder"
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging/codec"
+)
+
+// DefaultTableName 是 SQLStore 的默认表名。
+const DefaultTableName = "scheduled_messages"
+
+// SQLStoreConfig 定义 SQL 定时消息存储配置。
+type SQLStoreConfig struct {
+	// TableName 表名（默认：scheduled_messages）。
+	TableName string
+
+	Logger logging.ILogger
+}
+
+// SQLStore 是基于共享数据库表的 IStore 实现，多实例可共享同一张表（claim 使用 FOR UPDATE SKIP LOCKED）。
+//
+// 表结构（自动创建）：
+//   - id VARCHAR(191) PRIMARY KEY：消息 ID
+//   - message TEXT：消息 JSON（见 codec.EncodeMessage）
+//   - deliver_at_ms / lease_until_ms / created_at_ms BIGINT：毫秒时间戳
+//   - attempts INTEGER、last_error TEXT、claim_token VARCHAR(64)
+//
+// 无法解码的消息在 claim 时被标记为失败（claim_token = "failed"，记录 last_error），不再被 claim，需人工排查后删除。
+type SQLStore struct {
+	db        db.IDatabase
+	dialect   dialect.Dialect
+	tableName string
+	logger    logging.ILogger
+
+	ensureMu sync.Mutex
+	ensured  bool
+}
+
+// NewSQLStore 创建 SQL 定时消息存储。
+func NewSQLStore(database db.IDatabase, cfg *SQLStoreConfig) (*SQLStore, error) {
+	if database == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "database cannot be nil")
+	}
+	config := SQLStoreConfig{}
+	if cfg != nil {
+		config = *cfg
+	}
+	config.TableName = strings.TrimSpace(config.TableName)
+	if config.TableName == "" {
+		config.TableName = DefaultTableName
+	}
+	if !safeident.IsSafeIdentifier(config.TableName) {
+		return nil, errors.NewCode(errors.InvalidInput, fmt.Sprintf("invalid table name: %s", config.TableName))
+	}
+	if config.Logger == nil {
+		config.Logger = logging.ComponentLogger("messaging.schedule.sql").WithField("table", config.TableName)
+	}
+	return &SQLStore{db: database, dialect: dialect.FromDatabase(database), tableName: config.TableName, logger: config.Logger}, nil
+}
+
+// ensureTable 首次使用时创建表与到期索引；失败不缓存，下次调用重试。
+func (s *SQLStore) ensureTable(ctx context.Context) error {
+	s.ensureMu.Lock()
+	defer s.ensureMu.Unlock()
+	if s.ensured {
+		return nil
+	}
+	var inlineIndex string
+	if name := s.dialect.Name(); name != dialect.NameSQLite && name != dialect.NamePostgres {
+		// MySQL 不支持 CREATE INDEX IF NOT EXISTS，索引随建表语句创建。
+		inlineIndex = fmt.Sprintf(",\nINDEX idx_%s_deliver_at (deliver_at_ms)", s.tableName)
+	}
+	_, err := s.db.Exec(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+id VARCHAR(191) PRIMARY KEY,
+message TEXT NOT NULL,
+deliver_at_ms BIGINT NOT NULL,
+attempts INTEGER NOT NULL DEFAULT 0,
+last_error TEXT,
+claim_token VARCHAR(64) NOT NULL DEFAULT '',
+lease_until_ms BIGINT NOT NULL DEFAULT 0,
+created_at_ms BIGINT NOT NULL%s
+)`, s.tableName, inlineIndex))
+	if err == nil && inlineIndex == "" {
+		_, err = s.db.Exec(ctx, fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_deliver_at ON %s (deliver_at_ms)", s.tableName, s.tableName))
+	}
+	if err != nil {
+		return errors.Wrap(err, errors.Database, "ensure schedule table failed").WithContext("table", s.tableName)
+	}
+	s.ensured = true
+	return nil
+}
+
+func (s *SQLStore) builder(database db.IDatabase) (sqlbuilder.ISql, error) {
+	sq, err := sqlbuilder.New(database)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+	return sq, nil
+}
+
+// Schedule 保存定时消息。
+func (s *SQLStore) Schedule(ctx context.Context, entry *Entry) error {
+	if entry == nil || entry.ID == "" || entry.Message == nil {
+		return errors.NewCode(errors.InvalidInput, "scheduled entry is nil or incomplete")
+	}
+	if err := s.ensureTable(ctx); err != nil {
+		return err
+	}
+	data, err := codec.EncodeMessage(entry.Message)
+	if err != nil {
+		return err
+	}
+	sq, err := s.builder(s.db)
+	if err != nil {
+		return err
+	}
+	_, err = sq.InsertInto(s.tableName).
+		Columns("id", "message", "deliver_at_ms", "attempts", "claim_token", "lease_until_ms", "created_at_ms").
+		Values(entry.ID, string(data), entry.DeliverAt.UnixMilli(), entry.Attempts, "", int64(0), entry.CreatedAt.UnixMilli()).
+		Exec(ctx)
+	if err == nil {
+		return nil
+	}
+	// 主键冲突的错误文本因驱动而异，回查区分“已调度”与其他数据库错误。
+	if exists, existsErr := s.exists(ctx, entry.ID); existsErr == nil && exists {
+		return errors.NewCode(errors.Conflict, "message already scheduled").WithContext("message_id", entry.ID)
+	}
+	return errors.Wrap(err, errors.Database, "insert scheduled message failed").WithContext("message_id", entry.ID)
+}
+
+func (s *SQLStore) exists(ctx context.Context, id string) (bool, error) {
+	sq, err := s.builder(s.db)
+	if err != nil {
+		return false, err
+	}
+	var count int64
+	if err := sq.Select("COUNT(1)").From(s.tableName).Where("id = ?", id).QueryRow(ctx).Scan(&count); err != nil {
+		return false, errors.Wrap(err, errors.Database, "query scheduled message failed").WithContext("message_id", id)
+	}
+	return count > 0, nil
+}
+
+// ClaimDue claim 到期消息。
+func (s *SQLStore) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*Entry, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	if err := s.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Database, "begin transaction failed")
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	sq, err := s.builder(tx)
+	if err != nil {
+		return nil, err
+	}
+	nowMs := now.UnixMilli()
+	rows, err := sq.Select("id", "message", "deliver_at_ms", "attempts", "last_error", "created_at_ms").
+		From(s.tableName).
+		Where("deliver_at_ms <= ?", nowMs).
+		Where("lease_until_ms <= ?", nowMs).
+		OrderBy(sqlbuilder.OrderAsc("deliver_at_ms"), sqlbuilder.OrderAsc("id")).
+		Limit(limit).
+		ForUpdate().
+		SkipLocked().
+		Query(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Database, "query due scheduled messages failed")
+	}
+	defer rows.Close()
+
+	var (
+		entries []*Entry
+		poison  = map[string]error{}
+	)
+	for rows.Next() {
+		var (
+			entry                    Entry
+			message                  string
+			deliverAtMs, createdAtMs int64
+			lastError                sql.NullString
+		)
+		if err := rows.Scan(&entry.ID, &message, &deliverAtMs, &entry.Attempts, &lastError, &createdAtMs); err != nil {
+			return nil, errors.Wrap(err, errors.Database, "scan scheduled message failed")
+		}
+		msg, err := codec.DecodeMessage([]byte(message))
+		if err != nil {
+			// 无法解码的行不能阻塞后续到期消息：记入 poison，稍后标记为失败并跳过。
+			poison[entry.ID] = err
+			continue
+		}
+		entry.Message = msg
+		entry.DeliverAt = time.UnixMilli(deliverAtMs)
+		entry.CreatedAt = time.UnixMilli(createdAtMs)
+		entry.LastError = lastError.String
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, errors.Database, "iterate scheduled messages failed")
+	}
+	_ = rows.Close()
+	for id, cause := range poison {
+		if err := s.markFailed(ctx, sq, id, cause); err != nil {
+			return nil, err
+		}
+	}
+	if len(entries) == 0 {
+		if err := tx.Commit(); err != nil {
+			return nil, errors.Wrap(err, errors.Database, "commit transaction failed")
+		}
+		committed = true
+		return nil, nil
+	}
+
+	token, err := newClaimToken()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]any, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	result, err := sq.Update(s.tableName).
+		Set("claim_token", token).
+		Set("lease_until_ms", now.Add(lease).UnixMilli()).
+		Where("id IN ("+placeholders+")", ids...).
+		Where("lease_until_ms <= ?", nowMs).
+		Exec(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Database, "claim scheduled messages failed")
+	}
+	if err := ensureRowsAffected(result, int64(len(entries)), "claim scheduled messages affected unexpected rows"); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, errors.Database, "commit transaction failed")
+	}
+	committed = true
+
+	for _, entry := range entries {
+		entry.ClaimToken = token
+	}
+	return entries, nil
+}
+
+// failedClaimToken 标记无法解码的消息：租约永不过期，不再被 claim，需人工排查后删除。
+const failedClaimToken = "failed"
+
+// markFailed 把无法解码的消息标记为失败，使其不再阻塞 claim。
+func (s *SQLStore) markFailed(ctx context.Context, sq sqlbuilder.ISql, id string, cause error) error {
+	s.logger.Error(ctx, "scheduled message cannot be decoded, marked as failed",
+		logging.String("message_id", id),
+		logging.Error(cause))
+	if _, err := sq.Update(s.tableName).
+		SetIncrement("attempts", 1).
+		Set("last_error", cause.Error()).
+		Set("claim_token", failedClaimToken).
+		Set("lease_until_ms", int64(math.MaxInt64)).
+		Where("id = ?", id).
+		Exec(ctx); err != nil {
+		return errors.Wrap(err, errors.Database, "mark scheduled message failed").WithContext("message_id", id)
+	}
+	return nil
+}
+
+// Complete 删除已投递的消息。
+func (s *SQLStore) Complete(ctx context.Context, entry *Entry) error {
+	if entry == nil {
+		return errors.NewCode(errors.InvalidInput, "scheduled entry cannot be nil")
+	}
+	sq, err := s.builder(s.db)
+	if err != nil {
+		return err
+	}
+	result, err := sq.DeleteFrom(s.tableName).
+		Where("id = ?", entry.ID).
+		Where("claim_token = ?", entry.ClaimToken).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, errors.Database, "complete scheduled message failed").WithContext("message_id", entry.ID)
+	}
+	if err := ensureRowsAffected(result, 1, "scheduled message claim is no longer owned"); err != nil {
+		return err.WithContext("message_id", entry.ID)
+	}
+	return nil
+}
+
+// Retry 推迟消息并释放 claim。
+func (s *SQLStore) Retry(ctx context.Context, entry *Entry, nextAt time.Time, cause error) error {
+	if entry == nil {
+		return errors.NewCode(errors.InvalidInput, "scheduled entry cannot be nil")
+	}
+	sq, err := s.builder(s.db)
+	if err != nil {
+		return err
+	}
+	var lastError any
+	if cause != nil {
+		lastError = cause.Error()
+	}
+	result, err := sq.Update(s.tableName).
+		SetIncrement("attempts", 1).
+		Set("deliver_at_ms", nextAt.UnixMilli()).
+		Set("last_error", lastError).
+		Set("claim_token", "").
+		Set("lease_until_ms", int64(0)).
+		Where("id = ?", entry.ID).
+		Where("claim_token = ?", entry.ClaimToken).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, errors.Database, "retry scheduled message failed").WithContext("message_id", entry.ID)
+	}
+	if err := ensureRowsAffected(result, 1, "scheduled message claim is no longer owned"); err != nil {
+		return err.WithContext("message_id", entry.ID)
+	}
+	return nil
+}
+
+// Cancel 取消尚未投递的消息。
+func (s *SQLStore) Cancel(ctx context.Context, id string) (bool, error) {
+	if err := s.ensureTable(ctx); err != nil {
+		return false, err
+	}
+	sq, err := s.builder(s.db)
+	if err != nil {
+		return false, err
+	}
+	result, err := sq.DeleteFrom(s.tableName).
+		Where("id = ?", id).
+		Where("claim_token = ?", "").
+		Exec(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, errors.Database, "cancel scheduled message failed").WithContext("message_id", id)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, errors.Database, "cancel scheduled message failed").WithContext("message_id", id)
+	}
+	return affected > 0, nil
+}
+
+func ensureRowsAffected(result sql.Result, expected int64, message string) *errors.AppError {
+	if result == nil {
+		return nil
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil
+	}
+	if rows != expected {
+		return errors.NewCode(errors.Conflict, message).
+			WithContext("expected_rows", expected).
+			WithContext("affected_rows", rows)
+	}
+	return nil
+}
+
+func newClaimToken() (string, error) {
+	var raw [16]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return "", errors.Wrap(err, errors.Internal, "generate schedule claim token failed")
+	}
+	return fmt.Sprintf("%x", raw[:]), nil
+}
+
+var _ IStore = (*SQLStore)(nil)
@@ -0,0 +1,110 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"gochen/db"
+	basicdb "gochen/db/sql/stdsql"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/messaging/command"
+)
+
+func newSQLiteStore(t *testing.T) *SQLStore {
+	t.Helper()
+	database, err := basicdb.New(db.DBConfig{Driver: "sqlite", Database: ":memory:", MaxOpenConns: 1, MaxIdleConns: 1})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = database.Close() })
+	store, err := NewSQLStore(database, nil)
+	require.NoError(t, err)
+	return store
+}
+
+// TestSQLStore_ClaimCompleteRetry 验证 SQL 存储的调度、claim、重试与完成流程，并还原命令/事件类型。
+func TestSQLStore_ClaimCompleteRetry(t *testing.T) {
+	ctx := context.Background()
+	store := newSQLiteStore(t)
+	now := time.UnixMilli(time.Now().UnixMilli())
+
+	cmd := command.NewCommand("cmd-1", "ExpireOrder", "42", "Order", map[string]any{"reason": "timeout"})
+	evt := eventing.NewEvent[int64](7, "Order", "OrderReminder", 1, nil)
+	require.NoError(t, store.Schedule(ctx, &Entry{ID: cmd.GetID(), Message: cmd, DeliverAt: now, CreatedAt: now}))
+	require.NoError(t, store.Schedule(ctx, &Entry{ID: evt.GetID(), Message: evt, DeliverAt: now.Add(time.Hour), CreatedAt: now}))
+
+	err := store.Schedule(ctx, &Entry{ID: cmd.GetID(), Message: cmd, DeliverAt: now, CreatedAt: now})
+	require.True(t, errors.Is(err, errors.Conflict))
+
+	claimed, err := store.ClaimDue(ctx, now, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	decoded, ok := claimed[0].Message.(*command.Command)
+	require.True(t, ok)
+	require.Equal(t, "42", decoded.AggregateID)
+	require.NotEmpty(t, claimed[0].ClaimToken)
+
+	// 租约期内不会被重复 claim，也不能取消。
+	again, err := store.ClaimDue(ctx, now, 10, time.Minute)
+	require.NoError(t, err)
+	require.Empty(t, again)
+	cancelled, err := store.Cancel(ctx, cmd.GetID())
+	require.NoError(t, err)
+	require.False(t, cancelled)
+
+	require.NoError(t, store.Retry(ctx, claimed[0], now.Add(time.Second), errors.NewCode(errors.Timeout, "boom")))
+	require.True(t, errors.Is(store.Complete(ctx, claimed[0]), errors.Conflict))
+
+	claimed, err = store.ClaimDue(ctx, now.Add(2*time.Hour), 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	require.Equal(t, cmd.GetID(), claimed[0].ID)
+	require.Equal(t, 1, claimed[0].Attempts)
+	require.Contains(t, claimed[0].LastError, "boom")
+	decodedEvt, ok := claimed[1].Message.(*eventing.Event[int64])
+	require.True(t, ok)
+	require.Equal(t, int64(7), decodedEvt.AggregateID)
+
+	for _, entry := range claimed {
+		require.NoError(t, store.Complete(ctx, entry))
+	}
+	empty, err := store.ClaimDue(ctx, now.Add(3*time.Hour), 10, time.Minute)
+	require.NoError(t, err)
+	require.Empty(t, empty)
+}
+
+// TestSQLStore_ClaimDueSkipsUndecodableRow 验证无法解码的行被标记为失败，不阻塞其后的到期消息。
+func TestSQLStore_ClaimDueSkipsUndecodableRow(t *testing.T) {
+	ctx := context.Background()
+	store := newSQLiteStore(t)
+	now := time.UnixMilli(time.Now().UnixMilli())
+
+	cmd := command.NewCommand("cmd-1", "ExpireOrder", "42", "Order", nil)
+	require.NoError(t, store.Schedule(ctx, &Entry{ID: cmd.GetID(), Message: cmd, DeliverAt: now, CreatedAt: now}))
+	sq, err := store.builder(store.db)
+	require.NoError(t, err)
+	_, err = sq.InsertInto(store.tableName).
+		Columns("id", "message", "deliver_at_ms", "attempts", "claim_token", "lease_until_ms", "created_at_ms").
+		Values("poison", "{not json", now.Add(-time.Minute).UnixMilli(), 0, "", int64(0), now.UnixMilli()).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	claimed, err := store.ClaimDue(ctx, now, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.Equal(t, cmd.GetID(), claimed[0].ID)
+	require.NoError(t, store.Complete(ctx, claimed[0]))
+
+	again, err := store.ClaimDue(ctx, now.Add(time.Hour), 10, time.Minute)
+	require.NoError(t, err)
+	require.Empty(t, again, "failed row must not be claimed again")
+
+	var token, lastError string
+	require.NoError(t, sq.Select("claim_token", "last_error").From(store.tableName).Where("id = ?", "poison").
+		QueryRow(ctx).Scan(&token, &lastError))
+	require.Equal(t, failedClaimToken, token)
+	require.NotEmpty(t, lastError)
+}
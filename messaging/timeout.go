@@ -0,0 +1,146 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strconv"
+	"time"
+
+	gerrors "gochen/errors"
+)
+
+// maxStackSample 是超时错误中调用栈采样的最大字节数。
+const maxStackSample = 16 << 10
+
+// DefaultHandlerStopGrace 是超时或上游取消后等待处理器 goroutine 退出的默认宽限期。
+const DefaultHandlerStopGrace = 5 * time.Second
+
+// RunWithTimeout 等价于 RunWithTimeoutGrace(ctx, timeout, DefaultHandlerStopGrace, fn)。
+func RunWithTimeout(ctx context.Context, timeout time.Duration, fn func(context.Context) error) error {
+	return RunWithTimeoutGrace(ctx, timeout, DefaultHandlerStopGrace, fn)
+}
+
+// RunWithTimeoutGrace 在独立 goroutine 中执行 fn；超过 timeout 时取消 fn 的 ctx，并在 grace 内等待 fn 退出后返回 Timeout 错误。
+//
+// 说明：
+//   - 返回时 fn 已经退出，调用方可以安全地重试同一条消息，不会与仍在运行的旧调用并发；
+//   - grace 内仍未退出的 fn 被放弃，返回的错误额外携带 handler_abandoned（见 IsHandlerAbandoned），
+//     调用方应把它视为致命错误：停止重试并停用对应的运行时，而不是继续投递；
+//   - 超时错误携带 handler_timeout 与 fn 所在 goroutine 的调用栈采样（stack），便于定位卡住的处理器；
+//   - fn 中的 panic 被转换为 PanicError；timeout <= 0 时直接同步调用 fn；grace <= 0 时不等待。
+func RunWithTimeoutGrace(ctx context.Context, timeout, grace time.Duration, fn func(context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	gid := make(chan uint64, 1)
+	go func() {
+		gid <- currentGoroutineID()
+		done <- CallRecovered("message handler panicked", func() error { return fn(runCtx) })
+	}()
+
+	select {
+	case err := <-done:
+		return handlerResult(ctx, runCtx, timeout, err)
+	case <-runCtx.Done():
+	}
+	cancel()
+	// 采样放在等待之前，记录的是处理器卡住时的调用栈。
+	stack := goroutineStack(<-gid)
+
+	if err, ok := awaitHandler(done, grace); ok {
+		if ctx.Err() != nil {
+			// 上游取消不属于处理器超时。
+			return ctx.Err()
+		}
+		if err == nil {
+			// 处理器恰好在截止时完成，采用其结果。
+			return nil
+		}
+		return handlerTimeoutError(timeout, err).WithContext("stack", stack)
+	}
+	return handlerTimeoutError(timeout, ctx.Err()).
+		WithContext("stack", stack).
+		WithContext("handler_abandoned", grace.String())
+}
+
+// awaitHandler 在 grace 内等待处理器返回；grace <= 0 时只检查是否已返回。
+func awaitHandler(done <-chan error, grace time.Duration) (error, bool) {
+	if grace <= 0 {
+		select {
+		case err := <-done:
+			return err, true
+		default:
+			return nil, false
+		}
+	}
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err, true
+	case <-timer.C:
+		return nil, false
+	}
+}
+
+// handlerResult 把处理器因截止时间到达而返回的错误归一为超时错误。
+func handlerResult(ctx, runCtx context.Context, timeout time.Duration, err error) error {
+	if err == nil || ctx.Err() != nil || runCtx.Err() != context.DeadlineExceeded {
+		return err
+	}
+	return handlerTimeoutError(timeout, err)
+}
+
+func handlerTimeoutError(timeout time.Duration, cause error) *gerrors.AppError {
+	return gerrors.NewCodeWithCause(gerrors.Timeout, "message handler timed out", cause).
+		WithContext("handler_timeout", timeout.String())
+}
+
+// IsHandlerTimeout 判断 err（或其错误链）是否为 RunWithTimeout 强制超时产生的错误。
+func IsHandlerTimeout(err error) bool {
+	return hasDetail(err, "handler_timeout")
+}
+
+// IsHandlerAbandoned 判断 err（或其错误链）是否表示处理器在宽限期内未响应取消、其 goroutine 已被放弃。
+func IsHandlerAbandoned(err error) bool {
+	return hasDetail(err, "handler_abandoned")
+}
+
+func currentGoroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	// 格式："goroutine 123 [running]:..."
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i > 0 {
+		id, _ := strconv.ParseUint(string(buf[:i]), 10, 64)
+		return id
+	}
+	return 0
+}
+
+// goroutineStack 返回指定 goroutine 的调用栈；找不到时返回空字符串。
+func goroutineStack(id uint64) string {
+	if id == 0 {
+		return ""
+	}
+	buf := make([]byte, 1<<20)
+	buf = buf[:runtime.Stack(buf, true)]
+	header := []byte("goroutine " + strconv.FormatUint(id, 10) + " [")
+	start := bytes.Index(buf, header)
+	if start < 0 {
+		return ""
+	}
+	block := buf[start:]
+	if end := bytes.Index(block, []byte("\n\n")); end >= 0 {
+		block = block[:end]
+	}
+	if len(block) > maxStackSample {
+		block = block[:maxStackSample]
+	}
+	return string(block)
+}
@@ -12,6 +12,9 @@ import (
 // ErrTransportAlreadyStopped 标识 transport 已处于停止态，供 shutdown 清理路径做幂等判定。
 var ErrTransportAlreadyStopped = stderrors.New("transport already stopped")
 
+// ErrQueueFull 标识 transport 的有界队列已满、消息未被接收，调用方可据此降级（重试、限流或丢弃）。
+var ErrQueueFull = stderrors.New("transport queue is full")
+
 // UnsubscribeFunc 表示一个订阅的取消函数。
 //
 // 约定：
@@ -23,6 +26,7 @@ type UnsubscribeFunc func(ctx context.Context) error
 //
 // 语义约定：
 //   - Publish/PublishAll 返回的 error 只代表“传输层本身”的错误（连接失败、队列已满、未 Start 等）；
+//     有界队列已满应返回 NewQueueFullError，调用方用 IsQueueFull 识别背压；
 //   - 对于异步实现（如 memory/redisstreams/natsjetstream），消息处理器（IMessageHandler.Handle）的错误通常不会通过返回值暴露，
 //     而是由实现自行记录日志或上报监控；
 //   - 对于同步实现（如 transport/sync），Publish/PublishAll 可能会在同一调用中直接执行所有处理器，并将其错误聚合到返回值中。
@@ -55,6 +59,19 @@ func TransportAlreadyStopped(err error) bool {
 	return errors.Is(err, ErrTransportAlreadyStopped)
 }
 
+// NewQueueFullError 创建带标准哨兵 cause 的队列已满错误（错误码 errors.Queue）。
+func NewQueueFullError(message string) *errors.AppError {
+	return errors.NewCodeWithCause(errors.Queue, message, ErrQueueFull)
+}
+
+// IsQueueFull 判断 Publish/PublishAll 返回的错误是否表示队列已满（背压）。
+func IsQueueFull(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, ErrQueueFull)
+}
+
 // StopTransport 使用统一生命周期语义停止 transport，并把明确的“已停止”视为幂等成功。
 //
 // 说明：ctx 为 nil 时按 context.Background() 处理，便于清理入口在无请求上下文时安全调用。
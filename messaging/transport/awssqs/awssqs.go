@@ -0,0 +1,141 @@
+// Package awssqs 提供基于 AWS SQS / SNS 的消息传输（messaging.ITransport）。
+//
+// 路由：命令（KindCommand）发送到 SQS 队列（按命令类型或默认队列），其他消息（事件）发布到 SNS topic，
+// 由各服务自己的 SQS 队列订阅 topic 实现扇出；本传输轮询 ConsumeQueues 并按消息类型分发给处理器。
+// 消费：长轮询接收，处理期间按 HeartbeatInterval 延长可见性超时，成功后删除，失败后按 RetryDelay 重新可见
+// （超过队列 maxReceiveCount 后由 SQS redrive policy 转入死信队列）。
+// FIFO：目标以 ".fifo" 结尾时按 MessageGroup（默认聚合）设置消息组，同一聚合内严格有序，消息 ID 作为去重 ID。
+//
+// 框架核心不依赖 AWS SDK，业务侧实现 IClient 即可（见 README）。
+package awssqs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gochen/eventing"
+	"gochen/logging"
+	"gochen/messaging"
+)
+
+const (
+	// DefaultMaxMessages 是单次接收的最大消息数（SQS 上限 10）。
+	DefaultMaxMessages = 10
+	// DefaultWaitTime 是长轮询等待时间（SQS 上限 20s）。
+	DefaultWaitTime = 20 * time.Second
+	// DefaultVisibilityTimeout 是接收后消息对其他消费者不可见的时间。
+	DefaultVisibilityTimeout = 30 * time.Second
+	// DefaultRetryDelay 是处理失败后消息重新可见的延迟。
+	DefaultRetryDelay = 5 * time.Second
+	// DefaultErrorBackoff 是接收失败后的退避时间。
+	DefaultErrorBackoff = time.Second
+
+	// AttributeMessageType / AttributeMessageKind 是随消息发送的属性，可用于 SNS 订阅过滤策略。
+	AttributeMessageType = "message_type"
+	AttributeMessageKind = "message_kind"
+
+	fifoSuffix = ".fifo"
+)
+
+// OutboundMessage 是发送到 SQS 队列或 SNS topic 的消息。
+type OutboundMessage struct {
+	Body       string
+	Attributes map[string]string
+	// GroupID / DeduplicationID 仅对 FIFO 队列/topic 设置。
+	GroupID         string
+	DeduplicationID string
+}
+
+// ReceiveRequest 是一次长轮询接收请求。
+type ReceiveRequest struct {
+	MaxMessages       int
+	WaitTime          time.Duration
+	VisibilityTimeout time.Duration
+}
+
+// InboundMessage 是从 SQS 队列接收到的消息。
+type InboundMessage struct {
+	MessageID     string
+	ReceiptHandle string
+	Body          string
+	// GroupID 是 FIFO 队列的消息组（系统属性 MessageGroupId），标准队列为空。
+	GroupID      string
+	ReceiveCount int
+}
+
+// IClient 是传输所需的最小 SQS / SNS 客户端能力。
+type IClient interface {
+	// SendMessage 发送消息到 SQS 队列。
+	SendMessage(ctx context.Context, queueURL string, message OutboundMessage) error
+	// PublishToTopic 发布消息到 SNS topic。
+	PublishToTopic(ctx context.Context, topicARN string, message OutboundMessage) error
+	// ReceiveMessages 长轮询接收消息；ctx 结束时应尽快返回。
+	ReceiveMessages(ctx context.Context, queueURL string, request ReceiveRequest) ([]InboundMessage, error)
+	// DeleteMessage 删除（确认）已处理的消息。
+	DeleteMessage(ctx context.Context, queueURL, receiptHandle string) error
+	// ChangeVisibility 修改消息的可见性超时（0 表示立即重新可见）。
+	ChangeVisibility(ctx context.Context, queueURL, receiptHandle string, timeout time.Duration) error
+}
+
+// Config 定义 SQS / SNS 传输配置。
+type Config struct {
+	// CommandQueues 把命令类型映射到 SQS 队列 URL；未配置的命令发送到 DefaultCommandQueue。
+	CommandQueues       map[string]string
+	DefaultCommandQueue string
+
+	// EventTopics 把事件（及其他非命令消息）类型映射到 SNS topic ARN；未配置的发布到 DefaultEventTopic。
+	EventTopics       map[string]string
+	DefaultEventTopic string
+
+	// ConsumeQueues 是本服务轮询的队列 URL（自身的命令队列与订阅事件 topic 的队列）；为空时只能发布。
+	ConsumeQueues []string
+
+	// PollersPerQueue 是每个队列的轮询协程数（默认 1）；同一批消息按顺序处理。
+	PollersPerQueue int
+
+	MaxMessages       int
+	WaitTime          time.Duration
+	VisibilityTimeout time.Duration
+
+	// HeartbeatInterval 是处理期间延长可见性超时的间隔（默认 VisibilityTimeout/2，<0 表示不延长）。
+	HeartbeatInterval time.Duration
+
+	// RetryDelay 是处理失败后消息重新可见的延迟（默认 DefaultRetryDelay）。
+	RetryDelay time.Duration
+
+	// ErrorBackoff 是接收失败后的退避时间（默认 DefaultErrorBackoff）。
+	ErrorBackoff time.Duration
+
+	// MessageGroup 决定 FIFO 目标的消息组（默认 DefaultMessageGroup：按聚合分组）。
+	MessageGroup func(message messaging.IMessage) string
+
+	Logger logging.ILogger
+}
+
+// DefaultMessageGroup 以“聚合类型:聚合 ID”作为消息组，使同一聚合的命令/事件在 FIFO 队列中严格有序；
+// 无法识别聚合时退化为消息类型。
+func DefaultMessageGroup(message messaging.IMessage) string {
+	var aggregateType, aggregateID string
+	switch m := message.(type) {
+	case interface {
+		GetAggregateType() string
+		GetAggregateID() string
+	}:
+		aggregateType, aggregateID = m.GetAggregateType(), m.GetAggregateID()
+	case eventing.ITypedEvent[int64]:
+		aggregateType, aggregateID = m.GetAggregateType(), fmt.Sprint(m.GetAggregateID())
+	}
+	if aggregateType == "" || aggregateID == "" {
+		return message.GetType()
+	}
+	return aggregateType + ":" + aggregateID
+}
+
+// isFIFO 判断队列 URL / topic ARN 是否为 FIFO。
+func isFIFO(destination string) bool {
+	return strings.HasSuffix(destination, fifoSuffix)
+}
+
+var _ messaging.ITransport = (*Transport)(nil)
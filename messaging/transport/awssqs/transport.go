@@ -0,0 +1,430 @@
+package awssqs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/messaging/codec"
+	"gochen/messaging/transport/internal/subscriptions"
+)
+
+// settleTimeout 是删除消息/修改可见性的超时，独立于已取消的轮询 ctx。
+const settleTimeout = 5 * time.Second
+
+// Transport 是基于 SQS / SNS 的异步消息传输。
+type Transport struct {
+	client IClient
+	config Config
+	logger logging.ILogger
+
+	mutex    sync.RWMutex
+	handlers map[string][]messaging.IMessageHandler
+	running  bool
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewTransport 创建 SQS / SNS 传输；cfg 为 nil 时使用默认配置。
+func NewTransport(client IClient, cfg *Config) (*Transport, error) {
+	if client == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "sqs client cannot be nil")
+	}
+	config := Config{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.PollersPerQueue <= 0 {
+		config.PollersPerQueue = 1
+	}
+	if config.MaxMessages <= 0 || config.MaxMessages > DefaultMaxMessages {
+		config.MaxMessages = DefaultMaxMessages
+	}
+	if config.WaitTime <= 0 || config.WaitTime > DefaultWaitTime {
+		config.WaitTime = DefaultWaitTime
+	}
+	if config.VisibilityTimeout <= 0 {
+		config.VisibilityTimeout = DefaultVisibilityTimeout
+	}
+	if config.HeartbeatInterval == 0 {
+		config.HeartbeatInterval = config.VisibilityTimeout / 2
+	}
+	if config.HeartbeatInterval >= config.VisibilityTimeout {
+		return nil, errors.NewCode(errors.InvalidInput, "heartbeat interval must be shorter than visibility timeout").
+			WithContext("visibility_timeout", config.VisibilityTimeout).
+			WithContext("heartbeat_interval", config.HeartbeatInterval)
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = DefaultRetryDelay
+	}
+	if config.ErrorBackoff <= 0 {
+		config.ErrorBackoff = DefaultErrorBackoff
+	}
+	if config.MessageGroup == nil {
+		config.MessageGroup = DefaultMessageGroup
+	}
+	logger := config.Logger
+	if logger == nil {
+		logger = logging.ComponentLogger("messaging.transport.awssqs")
+	}
+	return &Transport{
+		client:   client,
+		config:   config,
+		logger:   logger,
+		handlers: make(map[string][]messaging.IMessageHandler),
+	}, nil
+}
+
+// Publish 把命令与应答发送到 SQS 队列，把其他消息发布到 SNS topic。
+func (t *Transport) Publish(ctx context.Context, message messaging.IMessage) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	if message == nil {
+		return errors.NewCode(errors.InvalidInput, "message is nil")
+	}
+	if strings.TrimSpace(message.GetType()) == "" {
+		return errors.NewCode(errors.InvalidInput, "message type is required")
+	}
+
+	t.mutex.RLock()
+	running := t.running
+	t.mutex.RUnlock()
+	if !running {
+		return errors.NewCode(errors.Conflict, "sqs transport is not running")
+	}
+
+	// 命令与应答点对点发送到 SQS 队列；应答的 Type 即请求方的应答队列 URL（见 messaging.MessageBus.SetReplyTopic）。
+	pointToPoint := message.GetKind() == messaging.KindCommand || message.GetKind() == messaging.KindReply
+	destination := t.destination(message)
+	if destination == "" {
+		return errors.NewCode(errors.NotFound, "no sqs queue or sns topic configured for message").
+			WithContext("message_type", message.GetType()).
+			WithContext("message_kind", string(message.GetKind()))
+	}
+
+	body, err := codec.EncodeMessage(message)
+	if err != nil {
+		return err
+	}
+	outbound := OutboundMessage{
+		Body: string(body),
+		Attributes: map[string]string{
+			AttributeMessageType: message.GetType(),
+			AttributeMessageKind: string(message.GetKind()),
+		},
+	}
+	if isFIFO(destination) {
+		outbound.GroupID = t.config.MessageGroup(message)
+		outbound.DeduplicationID = messaging.ProducerKey(message)
+	}
+
+	if pointToPoint {
+		err = t.client.SendMessage(ctx, destination, outbound)
+	} else {
+		err = t.client.PublishToTopic(ctx, destination, outbound)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return errors.Wrap(err, errors.Dependency, "publish to sqs/sns failed").
+			WithContext("message_id", message.GetID()).
+			WithContext("destination", destination)
+	}
+	return nil
+}
+
+// PublishAll 按顺序发布一批消息，遇到第一个错误即返回（之前的消息已发布）。
+func (t *Transport) PublishAll(ctx context.Context, messages []messaging.IMessage) error {
+	for _, message := range messages {
+		if err := t.Publish(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// destination 返回消息对应的队列 URL（命令、应答）或 topic ARN（其他消息）。
+func (t *Transport) destination(message messaging.IMessage) string {
+	messageType := message.GetType()
+	switch message.GetKind() {
+	case messaging.KindReply:
+		return messageType
+	case messaging.KindCommand:
+		if queueURL, ok := t.config.CommandQueues[messageType]; ok {
+			return queueURL
+		}
+		return t.config.DefaultCommandQueue
+	}
+	if topicARN, ok := t.config.EventTopics[messageType]; ok {
+		return topicARN
+	}
+	return t.config.DefaultEventTopic
+}
+
+// Subscribe 注册处理器；消息来源由 ConsumeQueues 决定（SNS 到 SQS 的订阅在基础设施侧配置）。
+func (t *Transport) Subscribe(ctx context.Context, messageType string, handler messaging.IMessageHandler) (messaging.UnsubscribeFunc, error) {
+	if len(t.config.ConsumeQueues) == 0 {
+		return nil, errors.NewCode(errors.InvalidInput, "sqs transport requires consume queues to subscribe").
+			WithContext("message_type", messageType)
+	}
+	return subscriptions.Subscribe(ctx, &t.mutex, t.handlers, messageType, handler)
+}
+
+// Start 为每个消费队列启动轮询协程。
+func (t *Transport) Start(ctx context.Context) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.running {
+		return errors.NewCode(errors.Conflict, "sqs transport is already running")
+	}
+
+	// 轮询使用独立的内部 ctx，避免 Start(ctx) 的取消导致消费意外退出。
+	pollCtx, cancel := context.WithCancel(contextx.Background())
+	t.cancel = cancel
+	for _, queueURL := range t.config.ConsumeQueues {
+		for range t.config.PollersPerQueue {
+			t.wg.Add(1)
+			go t.poll(pollCtx, queueURL)
+		}
+	}
+	t.running = true
+	return nil
+}
+
+// Stop 停止轮询并等待处理中的消息完成；未删除的消息在可见性超时后重新投递。
+func (t *Transport) Stop(ctx context.Context) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	t.mutex.Lock()
+	if !t.running {
+		t.mutex.Unlock()
+		return messaging.NewTransportAlreadyStoppedError("sqs transport is not running")
+	}
+	t.running = false
+	cancel := t.cancel
+	t.cancel = nil
+	t.mutex.Unlock()
+
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats 返回运行状态与订阅概览。
+func (t *Transport) Stats() messaging.TransportStats {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	handlerCount := 0
+	messageTypes := make([]string, 0, len(t.handlers))
+	for messageType, handlers := range t.handlers {
+		messageTypes = append(messageTypes, messageType)
+		handlerCount += len(handlers)
+	}
+	return messaging.TransportStats{
+		Running:      t.running,
+		HandlerCount: handlerCount,
+		MessageTypes: messageTypes,
+		WorkerCount:  len(t.config.ConsumeQueues) * t.config.PollersPerQueue,
+	}
+}
+
+// IsSynchronous 返回 false，表明该传输是异步的。
+func (t *Transport) IsSynchronous() bool { return false }
+
+// poll 长轮询一个队列直到 ctx 结束。
+func (t *Transport) poll(ctx context.Context, queueURL string) {
+	defer t.wg.Done()
+	request := ReceiveRequest{
+		MaxMessages:       t.config.MaxMessages,
+		WaitTime:          t.config.WaitTime,
+		VisibilityTimeout: t.config.VisibilityTimeout,
+	}
+	for ctx.Err() == nil {
+		batch, err := t.client.ReceiveMessages(ctx, queueURL, request)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			t.logger.Warn(ctx, "receive sqs messages failed", logging.String("queue", queueURL), logging.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(t.config.ErrorBackoff):
+			}
+			continue
+		}
+		t.processBatch(queueURL, batch)
+	}
+}
+
+// processBatch 按顺序处理一批消息；FIFO 消息组内某条失败后，同组后续消息直接释放，保证组内顺序。
+func (t *Transport) processBatch(queueURL string, batch []InboundMessage) {
+	failedGroups := make(map[string]struct{})
+	for _, inbound := range batch {
+		if inbound.GroupID != "" {
+			if _, failed := failedGroups[inbound.GroupID]; failed {
+				t.release(queueURL, inbound, 0)
+				continue
+			}
+		}
+		if !t.process(queueURL, inbound) && inbound.GroupID != "" {
+			failedGroups[inbound.GroupID] = struct{}{}
+		}
+	}
+}
+
+// process 解码并分发一条消息，返回是否处理成功（成功即删除）。
+func (t *Transport) process(queueURL string, inbound InboundMessage) bool {
+	ctx := contextx.Background()
+	message, err := decodeBody(inbound.Body)
+	if err != nil {
+		// 无法解码的消息重投也不会成功：不立即释放，等可见性超时后重投，超过 maxReceiveCount 进入死信队列。
+		t.logger.Error(ctx, "decode sqs message failed",
+			logging.String("queue", queueURL),
+			logging.String("sqs_message_id", inbound.MessageID),
+			logging.Error(err))
+		return false
+	}
+	if derived, derr := contextx.DeriveFromMetadata(ctx, message.GetMetadata()); derr == nil && derived != nil {
+		ctx = derived
+	}
+
+	stopHeartbeat := t.heartbeat(queueURL, inbound)
+	failed := t.dispatch(ctx, message, inbound)
+	stopHeartbeat()
+
+	if failed {
+		t.release(queueURL, inbound, t.config.RetryDelay)
+		return false
+	}
+	settleCtx, cancel := context.WithTimeout(contextx.Background(), settleTimeout)
+	defer cancel()
+	if err := t.client.DeleteMessage(settleCtx, queueURL, inbound.ReceiptHandle); err != nil {
+		// 删除失败时消息会在可见性超时后重投，依赖处理方幂等。
+		t.logger.Warn(ctx, "delete sqs message failed",
+			logging.String("queue", queueURL),
+			logging.String("message_id", message.GetID()),
+			logging.Error(err))
+	}
+	return true
+}
+
+// dispatch 调用精确匹配与通配处理器，返回是否有处理器失败。
+func (t *Transport) dispatch(ctx context.Context, message messaging.IMessage, inbound InboundMessage) bool {
+	t.mutex.RLock()
+	handlers := append(append([]messaging.IMessageHandler(nil), t.handlers[message.GetType()]...), t.handlers["*"]...)
+	t.mutex.RUnlock()
+
+	failed := false
+	for _, handler := range handlers {
+		if err := t.invoke(ctx, handler, message); err != nil {
+			failed = true
+			t.logger.Warn(ctx, "message handler failed",
+				logging.String("message_type", message.GetType()),
+				logging.String("message_id", message.GetID()),
+				logging.String("handler", handler.Type()),
+				logging.Int("receive_count", inbound.ReceiveCount),
+				logging.Error(err))
+		}
+	}
+	return failed
+}
+
+func (t *Transport) invoke(ctx context.Context, handler messaging.IMessageHandler, message messaging.IMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.logger.Error(ctx, "message handler panicked",
+				logging.String("message_type", message.GetType()),
+				logging.String("message_id", message.GetID()),
+				logging.String("handler", handler.Type()),
+				logging.String("panic", fmt.Sprint(r)),
+				logging.String("stack", string(debug.Stack())))
+			err = errors.NewCode(errors.Internal, "message handler panicked").WithContext("panic", fmt.Sprint(r))
+		}
+	}()
+	return handler.Handle(ctx, message)
+}
+
+// heartbeat 在处理期间定期延长可见性超时，返回停止函数（等待心跳协程退出）。
+func (t *Transport) heartbeat(queueURL string, inbound InboundMessage) func() {
+	if t.config.HeartbeatInterval < 0 {
+		return func() {}
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(t.config.HeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(contextx.Background(), settleTimeout)
+				err := t.client.ChangeVisibility(ctx, queueURL, inbound.ReceiptHandle, t.config.VisibilityTimeout)
+				cancel()
+				if err != nil {
+					t.logger.Warn(contextx.Background(), "extend sqs visibility failed",
+						logging.String("queue", queueURL),
+						logging.String("sqs_message_id", inbound.MessageID),
+						logging.Error(err))
+				}
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+// release 把消息的可见性超时改为 delay，使其在 delay 后重新投递。
+func (t *Transport) release(queueURL string, inbound InboundMessage, delay time.Duration) {
+	ctx, cancel := context.WithTimeout(contextx.Background(), settleTimeout)
+	defer cancel()
+	if err := t.client.ChangeVisibility(ctx, queueURL, inbound.ReceiptHandle, delay); err != nil {
+		t.logger.Warn(ctx, "release sqs message failed",
+			logging.String("queue", queueURL),
+			logging.String("sqs_message_id", inbound.MessageID),
+			logging.Error(err))
+	}
+}
+
+// snsNotification 是未启用 raw message delivery 时 SNS 投递到 SQS 的外层信封。
+type snsNotification struct {
+	TopicArn string `json:"TopicArn"`
+	Message  string `json:"Message"`
+}
+
+// decodeBody 解码消息体，兼容 SNS 通知信封。
+func decodeBody(body string) (messaging.IMessage, error) {
+	var notification snsNotification
+	if err := json.Unmarshal([]byte(body), &notification); err == nil && notification.TopicArn != "" {
+		body = notification.Message
+	}
+	return codec.DecodeMessage([]byte(body))
+}
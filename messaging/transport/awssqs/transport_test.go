@@ -0,0 +1,295 @@
+package awssqs
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/messaging/command"
+)
+
+type visibilityChange struct {
+	receipt string
+	timeout time.Duration
+}
+
+// fakeAWS 在内存中模拟 SQS 队列与 SNS topic → SQS 订阅（未启用 raw delivery，消息包在 SNS 信封中）。
+type fakeAWS struct {
+	mu            sync.Mutex
+	queues        map[string][]InboundMessage
+	subscriptions map[string][]string
+	sent          map[string][]OutboundMessage
+	deleted       []string
+	visibility    []visibilityChange
+	seq           int
+}
+
+func newFakeAWS() *fakeAWS {
+	return &fakeAWS{
+		queues:        map[string][]InboundMessage{},
+		subscriptions: map[string][]string{},
+		sent:          map[string][]OutboundMessage{},
+	}
+}
+
+func (f *fakeAWS) enqueue(queueURL, body, groupID string) {
+	f.seq++
+	id := strconv.Itoa(f.seq)
+	f.queues[queueURL] = append(f.queues[queueURL], InboundMessage{
+		MessageID: "sqs-" + id, ReceiptHandle: "rh-" + id, Body: body, GroupID: groupID, ReceiveCount: 1,
+	})
+}
+
+func (f *fakeAWS) SendMessage(_ context.Context, queueURL string, message OutboundMessage) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent[queueURL] = append(f.sent[queueURL], message)
+	f.enqueue(queueURL, message.Body, message.GroupID)
+	return nil
+}
+
+func (f *fakeAWS) PublishToTopic(_ context.Context, topicARN string, message OutboundMessage) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent[topicARN] = append(f.sent[topicARN], message)
+	envelope, _ := json.Marshal(map[string]string{"Type": "Notification", "TopicArn": topicARN, "Message": message.Body})
+	for _, queueURL := range f.subscriptions[topicARN] {
+		f.enqueue(queueURL, string(envelope), message.GroupID)
+	}
+	return nil
+}
+
+func (f *fakeAWS) ReceiveMessages(ctx context.Context, queueURL string, request ReceiveRequest) ([]InboundMessage, error) {
+	f.mu.Lock()
+	pending := f.queues[queueURL]
+	n := min(len(pending), request.MaxMessages)
+	batch := append([]InboundMessage(nil), pending[:n]...)
+	f.queues[queueURL] = pending[n:]
+	f.mu.Unlock()
+	if len(batch) == 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	return batch, nil
+}
+
+func (f *fakeAWS) DeleteMessage(_ context.Context, _ string, receiptHandle string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deleted = append(f.deleted, receiptHandle)
+	return nil
+}
+
+func (f *fakeAWS) ChangeVisibility(_ context.Context, _ string, receiptHandle string, timeout time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.visibility = append(f.visibility, visibilityChange{receipt: receiptHandle, timeout: timeout})
+	return nil
+}
+
+func (f *fakeAWS) deletedCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.deleted)
+}
+
+type recordingHandler struct {
+	mu       sync.Mutex
+	received []messaging.IMessage
+	fail     func(messaging.IMessage) error
+	delay    time.Duration
+}
+
+func (h *recordingHandler) Handle(_ context.Context, message messaging.IMessage) error {
+	time.Sleep(h.delay)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.received = append(h.received, message)
+	if h.fail != nil {
+		return h.fail(message)
+	}
+	return nil
+}
+
+func (h *recordingHandler) Type() string { return "recording" }
+
+func (h *recordingHandler) ids() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ids := make([]string, 0, len(h.received))
+	for _, m := range h.received {
+		ids = append(ids, m.GetID())
+	}
+	return ids
+}
+
+const (
+	ordersQueue   = "https://sqs.eu-west-1.amazonaws.com/123/orders-commands.fifo"
+	billingQueue  = "https://sqs.eu-west-1.amazonaws.com/123/billing-events"
+	ordersTopic   = "arn:aws:sns:eu-west-1:123:order-events"
+	fallbackTopic = "arn:aws:sns:eu-west-1:123:domain-events"
+)
+
+func newTestTransport(t *testing.T, client IClient, cfg Config) *Transport {
+	t.Helper()
+	cfg.Logger = logging.NewNoopLogger()
+	transport, err := NewTransport(client, &cfg)
+	require.NoError(t, err)
+	return transport
+}
+
+// TestTransport_CommandsToSQSEventsViaSNS 验证命令进入 FIFO 队列（按聚合分组）、事件经 SNS 扇出到订阅队列并被消费删除。
+func TestTransport_CommandsToSQSEventsViaSNS(t *testing.T) {
+	ctx := context.Background()
+	aws := newFakeAWS()
+	aws.subscriptions[ordersTopic] = []string{billingQueue}
+	transport := newTestTransport(t, aws, Config{
+		DefaultCommandQueue: ordersQueue,
+		EventTopics:         map[string]string{"OrderPlaced": ordersTopic},
+		DefaultEventTopic:   fallbackTopic,
+		ConsumeQueues:       []string{ordersQueue, billingQueue},
+	})
+	handler := &recordingHandler{}
+	_, err := transport.Subscribe(ctx, "*", handler)
+	require.NoError(t, err)
+	require.NoError(t, transport.Start(ctx))
+	t.Cleanup(func() { _ = transport.Stop(context.Background()) })
+
+	require.NoError(t, transport.Publish(ctx, command.NewCommand("c1", "PlaceOrder", "42", "Order", nil)))
+	evt := eventing.NewEvent[int64](42, "Order", "OrderPlaced", 1, map[string]any{"total": 10})
+	require.NoError(t, transport.Publish(ctx, evt))
+
+	require.Eventually(t, func() bool { return aws.deletedCount() == 2 }, time.Second, 5*time.Millisecond)
+	require.ElementsMatch(t, []string{"c1", evt.GetID()}, handler.ids())
+
+	aws.mu.Lock()
+	defer aws.mu.Unlock()
+	sentCommand := aws.sent[ordersQueue][0]
+	require.Equal(t, "Order:42", sentCommand.GroupID)
+	require.Equal(t, "c1", sentCommand.DeduplicationID)
+	sentEvent := aws.sent[ordersTopic][0]
+	require.Empty(t, sentEvent.GroupID)
+	require.Equal(t, map[string]string{AttributeMessageType: "OrderPlaced", AttributeMessageKind: "event"}, sentEvent.Attributes)
+}
+
+// TestTransport_FailureKeepsFIFOGroupOrder 验证失败消息按 RetryDelay 重新可见，同组后续消息被释放而不处理。
+func TestTransport_FailureKeepsFIFOGroupOrder(t *testing.T) {
+	aws := newFakeAWS()
+	transport := newTestTransport(t, aws, Config{ConsumeQueues: []string{ordersQueue}, RetryDelay: 3 * time.Second})
+	handler := &recordingHandler{fail: func(m messaging.IMessage) error {
+		if m.GetID() == "a" {
+			return errors.NewCode(errors.Database, "db down")
+		}
+		return nil
+	}}
+	_, err := transport.Subscribe(context.Background(), "PlaceOrder", handler)
+	require.NoError(t, err)
+
+	batch := make([]InboundMessage, 0, 3)
+	for i, spec := range []struct{ id, group string }{{"a", "Order:1"}, {"b", "Order:1"}, {"c", "Order:2"}} {
+		body, err := json.Marshal(command.NewCommand(spec.id, "PlaceOrder", "1", "Order", nil))
+		require.NoError(t, err)
+		batch = append(batch, InboundMessage{ReceiptHandle: "rh-" + strconv.Itoa(i), Body: string(body), GroupID: spec.group})
+	}
+	transport.processBatch(ordersQueue, batch)
+
+	require.Equal(t, []string{"a", "c"}, handler.ids())
+	require.Equal(t, []string{"rh-2"}, aws.deleted)
+	require.Equal(t, []visibilityChange{{receipt: "rh-0", timeout: 3 * time.Second}, {receipt: "rh-1", timeout: 0}}, aws.visibility)
+}
+
+// TestTransport_HeartbeatExtendsVisibility 验证长时间处理期间定期延长可见性超时。
+func TestTransport_HeartbeatExtendsVisibility(t *testing.T) {
+	aws := newFakeAWS()
+	transport := newTestTransport(t, aws, Config{
+		ConsumeQueues:     []string{billingQueue},
+		VisibilityTimeout: 40 * time.Millisecond,
+		HeartbeatInterval: 10 * time.Millisecond,
+	})
+	_, err := transport.Subscribe(context.Background(), "*", &recordingHandler{delay: 35 * time.Millisecond})
+	require.NoError(t, err)
+
+	body, err := json.Marshal(messaging.NewMessage("m1", messaging.KindEvent, "InvoiceIssued", nil))
+	require.NoError(t, err)
+	transport.processBatch(billingQueue, []InboundMessage{{ReceiptHandle: "rh-1", Body: string(body)}})
+
+	require.Equal(t, []string{"rh-1"}, aws.deleted)
+	require.NotEmpty(t, aws.visibility)
+	for _, change := range aws.visibility {
+		require.Equal(t, visibilityChange{receipt: "rh-1", timeout: 40 * time.Millisecond}, change)
+	}
+}
+
+// TestTransport_Errors 验证配置校验、未启动与无路由的错误语义。
+func TestTransport_Errors(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewTransport(nil, nil)
+	require.True(t, errors.Is(err, errors.InvalidInput))
+	_, err = NewTransport(newFakeAWS(), &Config{VisibilityTimeout: time.Second, HeartbeatInterval: time.Second})
+	require.True(t, errors.Is(err, errors.InvalidInput))
+
+	transport := newTestTransport(t, newFakeAWS(), Config{})
+	msg := messaging.NewMessage("m1", messaging.KindEvent, "OrderPlaced", nil)
+	require.True(t, errors.Is(transport.Publish(ctx, msg), errors.Conflict))
+	_, err = transport.Subscribe(ctx, "OrderPlaced", &recordingHandler{})
+	require.True(t, errors.Is(err, errors.InvalidInput))
+
+	require.NoError(t, transport.Start(ctx))
+	require.True(t, errors.Is(transport.Publish(ctx, msg), errors.NotFound))
+	require.NoError(t, transport.Stop(ctx))
+	require.True(t, messaging.TransportAlreadyStopped(transport.Stop(ctx)))
+}
+
+type replyingHandler struct {
+	bus *messaging.MessageBus
+}
+
+func (h *replyingHandler) Handle(ctx context.Context, message messaging.IMessage) error {
+	reply, err := messaging.NewErrorReply(message, "reply-"+message.GetID(), errors.NewCode(errors.NotFound, "order not found"))
+	if err != nil {
+		return err
+	}
+	return h.bus.Publish(ctx, reply)
+}
+
+func (h *replyingHandler) Type() string { return "replying" }
+
+// TestTransport_RequestReplyToInstanceQueue 验证应答以请求方的队列 URL 为应答主题，点对点发送回请求方。
+func TestTransport_RequestReplyToInstanceQueue(t *testing.T) {
+	ctx := context.Background()
+	const replyQueue = "https://sqs.eu-west-1.amazonaws.com/123/web-1-replies"
+	aws := newFakeAWS()
+	requester := newTestTransport(t, aws, Config{DefaultCommandQueue: ordersQueue, ConsumeQueues: []string{replyQueue}})
+	replier := newTestTransport(t, aws, Config{ConsumeQueues: []string{ordersQueue}})
+
+	replierBus := messaging.NewMessageBus(replier)
+	_, err := replierBus.Subscribe(ctx, "CancelOrder", &replyingHandler{bus: replierBus})
+	require.NoError(t, err)
+	requesterBus := messaging.NewMessageBus(requester)
+	require.NoError(t, requesterBus.SetReplyTopic(replyQueue))
+	for _, transport := range []*Transport{requester, replier} {
+		require.NoError(t, transport.Start(ctx))
+		t.Cleanup(func() { _ = transport.Stop(context.Background()) })
+	}
+
+	reply, err := requesterBus.Request(ctx, command.NewCommand("c1", "CancelOrder", "42", "Order", nil), 2*time.Second)
+	require.True(t, errors.Is(err, errors.NotFound))
+	require.Equal(t, "reply-c1", reply.GetID())
+
+	aws.mu.Lock()
+	defer aws.mu.Unlock()
+	require.Len(t, aws.sent[replyQueue], 1)
+	require.Equal(t, string(messaging.KindReply), aws.sent[replyQueue][0].Attributes[AttributeMessageKind])
+}
@@ -0,0 +1,279 @@
+// Package chaos 提供故障注入的消息传输装饰器，用于在集成测试中验证重试、幂等与 Saga 补偿。
+//
+// Transport 包装任意 messaging.ITransport，在发布侧按配置注入延迟、丢弃、重复与乱序：
+//
+//	inner := memory.NewMemoryTransport(100, 4)
+//	tr, err := chaos.NewTransport(inner, &chaos.Config{DropRate: 0.1, DuplicateRate: 0.2, Seed: 42})
+//	bus := messaging.NewMessageBus(tr)
+//
+// 故障决策由 Seed 初始化的伪随机数发生器给出，固定 Seed 时同一发布序列的故障序列可复现。
+// 仅用于测试环境，不应出现在生产装配中。
+package chaos
+
+import (
+	"context"
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	mrand "math/rand"
+	"sync"
+	"time"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/messaging"
+)
+
+// Config 定义故障注入配置；各概率取值范围为 [0, 1]。
+type Config struct {
+	// MinLatency/MaxLatency 定义每次投递前注入的延迟；MaxLatency 大于 MinLatency 时在区间内均匀取值。
+	MinLatency time.Duration
+	MaxLatency time.Duration
+
+	// DropRate 消息被静默丢弃的概率（Publish 返回 nil，模拟网络丢包）。
+	DropRate float64
+
+	// DuplicateRate 消息被重复投递一次的概率（同一消息 ID，模拟至少一次语义下的重投）。
+	DuplicateRate float64
+
+	// ReorderRate 消息被暂扣、排到下一条消息之后投递的概率。
+	ReorderRate float64
+
+	// MessageTypes 非空时仅对其中的消息类型注入故障，其余消息直接透传。
+	MessageTypes []string
+
+	// Seed 伪随机数种子；0 表示随机种子。
+	Seed int64
+
+	// Clock 用于注入延迟的计时；为 nil 时使用真实时钟。
+	Clock clock.IClock
+}
+
+// FaultStats 是已注入故障的计数。
+type FaultStats struct {
+	Dropped    int64 `json:"dropped"`
+	Duplicated int64 `json:"duplicated"`
+	Reordered  int64 `json:"reordered"`
+	Delayed    int64 `json:"delayed"`
+}
+
+// Transport 是故障注入的传输装饰器；订阅、启动与统计透传到被装饰的 transport。
+type Transport struct {
+	inner messaging.ITransport
+	cfg   Config
+	types map[string]struct{}
+
+	mu    sync.Mutex
+	rng   *mrand.Rand
+	held  []messaging.IMessage
+	stats FaultStats
+}
+
+var _ messaging.ITransport = (*Transport)(nil)
+
+// NewTransport 创建故障注入传输装饰器；inner 为 nil 或概率/延迟配置非法时返回 InvalidInput。
+func NewTransport(inner messaging.ITransport, cfg *Config) (*Transport, error) {
+	if inner == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "inner transport cannot be nil")
+	}
+	config := Config{}
+	if cfg != nil {
+		config = *cfg
+	}
+	for _, rate := range []struct {
+		name  string
+		value float64
+	}{
+		{"drop_rate", config.DropRate},
+		{"duplicate_rate", config.DuplicateRate},
+		{"reorder_rate", config.ReorderRate},
+	} {
+		if rate.value < 0 || rate.value > 1 {
+			return nil, errors.NewCode(errors.InvalidInput, "chaos rate must be within [0, 1]").
+				WithContext("field", rate.name).
+				WithContext("value", rate.value)
+		}
+	}
+	if config.MinLatency < 0 || (config.MaxLatency > 0 && config.MaxLatency < config.MinLatency) {
+		return nil, errors.NewCode(errors.InvalidInput, "chaos latency range is invalid").
+			WithContext("min_latency", config.MinLatency.String()).
+			WithContext("max_latency", config.MaxLatency.String())
+	}
+	if config.Clock == nil {
+		config.Clock = clock.NewRealClock()
+	}
+	if config.Seed == 0 {
+		config.Seed = randomSeed()
+	}
+
+	t := &Transport{
+		inner: inner,
+		cfg:   config,
+		rng:   mrand.New(mrand.NewSource(config.Seed)),
+	}
+	if len(config.MessageTypes) > 0 {
+		t.types = make(map[string]struct{}, len(config.MessageTypes))
+		for _, messageType := range config.MessageTypes {
+			t.types[messageType] = struct{}{}
+		}
+	}
+	return t, nil
+}
+
+// Inner 返回被装饰的传输实现。
+func (t *Transport) Inner() messaging.ITransport {
+	if t == nil {
+		return nil
+	}
+	return t.inner
+}
+
+// Publish 按配置注入故障后把消息交给被装饰的 transport。
+//
+// 被暂扣（乱序）的消息在下一条投递的消息之后发布；Flush/Stop 会发布仍被暂扣的消息。
+func (t *Transport) Publish(ctx context.Context, message messaging.IMessage) error {
+	if message == nil {
+		return errors.NewCode(errors.InvalidInput, "message is nil")
+	}
+	return t.deliver(ctx, t.plan([]messaging.IMessage{message}))
+}
+
+// PublishAll 对批内每条消息独立注入故障，并以一次 PublishAll 交给被装饰的 transport。
+func (t *Transport) PublishAll(ctx context.Context, messages []messaging.IMessage) error {
+	for _, message := range messages {
+		if message == nil {
+			return errors.NewCode(errors.InvalidInput, "message is nil")
+		}
+	}
+	return t.deliver(ctx, t.plan(messages))
+}
+
+// Flush 发布所有仍被暂扣的消息。
+func (t *Transport) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	held := t.held
+	t.held = nil
+	t.mu.Unlock()
+	if len(held) == 0 {
+		return nil
+	}
+	return t.inner.PublishAll(ctx, held)
+}
+
+// Subscribe 透传订阅。
+func (t *Transport) Subscribe(ctx context.Context, messageType string, handler messaging.IMessageHandler) (messaging.UnsubscribeFunc, error) {
+	return t.inner.Subscribe(ctx, messageType, handler)
+}
+
+// Start 启动被装饰的 transport。
+func (t *Transport) Start(ctx context.Context) error {
+	return t.inner.Start(ctx)
+}
+
+// Stop 先发布仍被暂扣的消息，再停止被装饰的 transport。
+func (t *Transport) Stop(ctx context.Context) error {
+	flushErr := t.Flush(ctx)
+	return errors.Join(flushErr, t.inner.Stop(ctx))
+}
+
+// Stats 返回被装饰 transport 的统计信息。
+func (t *Transport) Stats() messaging.TransportStats {
+	return t.inner.Stats()
+}
+
+// FaultStats 返回已注入故障的计数快照。
+func (t *Transport) FaultStats() FaultStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stats
+}
+
+// plan 为一组消息做故障决策，返回本次应投递的消息序列及注入的延迟。
+func (t *Transport) plan(messages []messaging.IMessage) delivery {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var out delivery
+	for _, message := range messages {
+		if !t.targets(message) {
+			out.messages = append(out.messages, message)
+			continue
+		}
+		if t.hit(t.cfg.DropRate) {
+			t.stats.Dropped++
+			continue
+		}
+		if t.hit(t.cfg.ReorderRate) {
+			t.stats.Reordered++
+			t.held = append(t.held, message)
+			continue
+		}
+		out.messages = append(out.messages, message)
+		if t.hit(t.cfg.DuplicateRate) {
+			t.stats.Duplicated++
+			out.messages = append(out.messages, message)
+		}
+		out.messages = append(out.messages, t.held...)
+		t.held = nil
+		if latency := t.latency(); latency > out.latency {
+			out.latency = latency
+		}
+	}
+	if out.latency > 0 {
+		t.stats.Delayed++
+	}
+	return out
+}
+
+// deliver 等待注入的延迟后把消息交给被装饰的 transport。
+func (t *Transport) deliver(ctx context.Context, d delivery) error {
+	if d.latency > 0 {
+		timer := t.cfg.Clock.NewTimer(d.latency)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C():
+		}
+	}
+	switch len(d.messages) {
+	case 0:
+		return nil
+	case 1:
+		return t.inner.Publish(ctx, d.messages[0])
+	default:
+		return t.inner.PublishAll(ctx, d.messages)
+	}
+}
+
+func (t *Transport) targets(message messaging.IMessage) bool {
+	if t.types == nil {
+		return true
+	}
+	_, ok := t.types[message.GetType()]
+	return ok
+}
+
+func (t *Transport) hit(rate float64) bool {
+	return rate > 0 && t.rng.Float64() < rate
+}
+
+func (t *Transport) latency() time.Duration {
+	latency := t.cfg.MinLatency
+	if spread := t.cfg.MaxLatency - t.cfg.MinLatency; spread > 0 {
+		latency += time.Duration(t.rng.Int63n(int64(spread)))
+	}
+	return latency
+}
+
+type delivery struct {
+	messages []messaging.IMessage
+	latency  time.Duration
+}
+
+func randomSeed() int64 {
+	var b [8]byte
+	if _, err := cryptorand.Read(b[:]); err == nil {
+		return int64(binary.LittleEndian.Uint64(b[:]))
+	}
+	return time.Now().UnixNano()
+}
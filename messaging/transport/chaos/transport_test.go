@@ -0,0 +1,136 @@
+package chaos
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/messaging"
+	"gochen/messaging/transport/direct"
+)
+
+type recordingHandler struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+func (h *recordingHandler) Handle(_ context.Context, message messaging.IMessage) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ids = append(h.ids, message.GetID())
+	return nil
+}
+
+func (h *recordingHandler) Type() string { return "recorder" }
+
+func (h *recordingHandler) received() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.ids...)
+}
+
+func newStartedTransport(t *testing.T, cfg *Config) (*Transport, *recordingHandler) {
+	t.Helper()
+	tr, err := NewTransport(direct.NewSyncTransport(), cfg)
+	require.NoError(t, err)
+	handler := &recordingHandler{}
+	_, err = tr.Subscribe(context.Background(), "*", handler)
+	require.NoError(t, err)
+	require.NoError(t, tr.Start(context.Background()))
+	return tr, handler
+}
+
+func event(id string) messaging.IMessage {
+	return messaging.NewMessage(id, messaging.KindEvent, "OrderPlaced", nil)
+}
+
+// TestTransport_DropAndDuplicate 验证丢弃与重复注入。
+func TestTransport_DropAndDuplicate(t *testing.T) {
+	dropping, handler := newStartedTransport(t, &Config{DropRate: 1})
+	require.NoError(t, dropping.Publish(context.Background(), event("m1")))
+	require.Empty(t, handler.received())
+	require.Equal(t, int64(1), dropping.FaultStats().Dropped)
+
+	duplicating, handler := newStartedTransport(t, &Config{DuplicateRate: 1})
+	require.NoError(t, duplicating.Publish(context.Background(), event("m1")))
+	require.Equal(t, []string{"m1", "m1"}, handler.received())
+	require.Equal(t, int64(1), duplicating.FaultStats().Duplicated)
+}
+
+// TestTransport_ReorderHoldsUntilNextMessageOrStop 验证乱序：被暂扣的消息排到下一条之后，Stop 时发布剩余暂扣。
+func TestTransport_ReorderHoldsUntilNextMessageOrStop(t *testing.T) {
+	tr, handler := newStartedTransport(t, &Config{ReorderRate: 1, MessageTypes: []string{"OrderPlaced"}})
+
+	require.NoError(t, tr.Publish(context.Background(), event("m1")))
+	require.Empty(t, handler.received())
+
+	// 非目标类型直接透传，不释放暂扣的消息。
+	require.NoError(t, tr.Publish(context.Background(), messaging.NewMessage("c1", messaging.KindCommand, "ShipOrder", nil)))
+	require.Equal(t, []string{"c1"}, handler.received())
+
+	require.NoError(t, tr.Stop(context.Background()))
+	require.Equal(t, []string{"c1", "m1"}, handler.received())
+	require.Equal(t, int64(1), tr.FaultStats().Reordered)
+}
+
+// TestTransport_SeededFaultsAreReproducible 验证固定 Seed 时故障序列可复现。
+func TestTransport_SeededFaultsAreReproducible(t *testing.T) {
+	run := func() []string {
+		tr, handler := newStartedTransport(t, &Config{DropRate: 0.2, DuplicateRate: 0.2, ReorderRate: 0.2, Seed: 42})
+		batch := make([]messaging.IMessage, 0, 10)
+		for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+			batch = append(batch, event(id))
+		}
+		require.NoError(t, tr.PublishAll(context.Background(), batch))
+		require.NoError(t, tr.Stop(context.Background()))
+		return handler.received()
+	}
+	first := run()
+	require.Equal(t, first, run())
+	require.NotEqual(t, []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}, first)
+}
+
+// TestTransport_LatencyUsesInjectedClock 验证延迟由注入时钟驱动，且可被 ctx 取消。
+func TestTransport_LatencyUsesInjectedClock(t *testing.T) {
+	clk := clock.NewManualClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	tr, handler := newStartedTransport(t, &Config{MinLatency: time.Second, Clock: clk})
+
+	done := make(chan error, 1)
+	go func() { done <- tr.Publish(context.Background(), event("m1")) }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		clk.Advance(time.Second)
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+			require.Equal(t, []string{"m1"}, handler.received())
+			require.Equal(t, int64(1), tr.FaultStats().Delayed)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			require.ErrorIs(t, tr.Publish(ctx, event("m2")), context.Canceled)
+			return
+		default:
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("delayed publish did not complete")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestNewTransport_ValidatesConfig 验证配置校验。
+func TestNewTransport_ValidatesConfig(t *testing.T) {
+	_, err := NewTransport(nil, nil)
+	require.True(t, errors.Is(err, errors.InvalidInput))
+	_, err = NewTransport(direct.NewSyncTransport(), &Config{DropRate: -0.1})
+	require.True(t, errors.Is(err, errors.InvalidInput))
+	_, err = NewTransport(direct.NewSyncTransport(), &Config{MinLatency: time.Second, MaxLatency: time.Millisecond})
+	require.True(t, errors.Is(err, errors.InvalidInput))
+}
@@ -90,6 +90,7 @@ func (t *SyncTransport) Publish(ctx context.Context, message messaging.IMessage)
 		}
 		_ = contextx.InjectTenantID(derived, md)
 		_ = contextx.InjectOperator(derived, md)
+		_ = contextx.InjectCorrelationID(derived, md)
 	}
 
 	var errs []error
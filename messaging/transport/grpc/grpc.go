@@ -0,0 +1,95 @@
+// Package grpc 提供基于 gRPC Dispatch RPC 的同步命令传输。
+//
+// 发布方通过 Transport 把命令发送到远端服务，并在同一调用中拿到远端 handler 的执行结果（带错误码）；
+// 接收方用 Server 把收到的请求交给本地 command.ICommandExecutor 执行。
+// 框架核心不依赖 google.golang.org/grpc：业务侧按 README 中的 proto 生成代码，
+// 实现 IClient 并把 Server.Dispatch 注册为 gRPC 服务方法。
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gochen/errors"
+)
+
+// DefaultTimeout 是单次 Dispatch 调用的默认超时。
+const DefaultTimeout = 10 * time.Second
+
+// DispatchRequest 是 Dispatch RPC 的请求。
+type DispatchRequest struct {
+	// MessageID / MessageType 冗余自 Body，便于服务端在解码前记录日志或做路由。
+	MessageID   string
+	MessageType string
+	// Body 为 messaging/codec.EncodeMessage 编码的消息 JSON。
+	Body []byte
+}
+
+// DispatchResponse 是 Dispatch RPC 的响应；ErrorCode 为空表示命令执行成功。
+type DispatchResponse struct {
+	ErrorCode    string
+	ErrorMessage string
+	ErrorDetails map[string]string
+}
+
+// Err 把响应还原为错误：成功返回 nil，失败返回保留远端错误码的 *errors.AppError。
+func (r *DispatchResponse) Err() error {
+	if r == nil || r.ErrorCode == "" {
+		return nil
+	}
+	appErr := errors.NewCode(errors.ErrorCode(r.ErrorCode), r.ErrorMessage)
+	if len(r.ErrorDetails) > 0 {
+		details := make(map[string]any, len(r.ErrorDetails))
+		for k, v := range r.ErrorDetails {
+			details[k] = v
+		}
+		appErr = appErr.WithDetails(details)
+	}
+	return appErr
+}
+
+// NewDispatchResponse 把命令执行结果编码为响应。
+//
+// 错误码取 errors.Code(err)（ctx 超时映射为 errors.Timeout），详情转为字符串；
+// 堆栈只保留在服务端日志中，不随响应返回。
+func NewDispatchResponse(err error) *DispatchResponse {
+	if err == nil {
+		return &DispatchResponse{}
+	}
+	code := errors.Code(err)
+	if code == errors.Internal && errors.Is(err, context.DeadlineExceeded) {
+		code = errors.Timeout
+	}
+	resp := &DispatchResponse{ErrorCode: string(code), ErrorMessage: err.Error()}
+	if appErr, ok := errors.AsType[*errors.AppError](err); ok && appErr != nil {
+		for k, v := range appErr.Details() {
+			if k == "stack" {
+				continue
+			}
+			if resp.ErrorDetails == nil {
+				resp.ErrorDetails = make(map[string]string)
+			}
+			resp.ErrorDetails[k] = fmt.Sprint(v)
+		}
+	}
+	return resp
+}
+
+// IClient 抽象 Dispatch RPC 的客户端。
+//
+// target 为 Config 中配置的目标服务名，由实现映射到对应的 gRPC 连接；
+// 返回 error 仅表示 RPC 本身失败（连接、超时、服务端未实现等），命令执行失败通过 DispatchResponse 表达。
+type IClient interface {
+	Dispatch(ctx context.Context, target string, req *DispatchRequest) (*DispatchResponse, error)
+}
+
+// Config 配置 gRPC 命令传输。
+type Config struct {
+	// Targets 把命令类型映射到目标服务名。
+	Targets map[string]string
+	// DefaultTarget 是未在 Targets 中配置的命令类型的目标服务；为空时这类命令返回 NotFound。
+	DefaultTarget string
+	// Timeout 是单次调用的超时（与调用方 ctx 的 deadline 取较早者），默认 DefaultTimeout；小于 0 表示只使用 ctx。
+	Timeout time.Duration
+}
@@ -0,0 +1,244 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"gochen/errors"
+	"gochen/messaging"
+	"gochen/messaging/codec"
+	"gochen/messaging/command"
+)
+
+// Transport 是通过 Dispatch RPC 同步投递命令的传输。
+//
+// Publish 会等待远端执行完成：返回 nil 表示远端 handler 成功，远端失败时返回保留其错误码的 *errors.AppError。
+// 该传输只负责发送，不承载本地订阅；接收方使用 Server。
+type Transport struct {
+	client IClient
+	config Config
+
+	mutex   sync.RWMutex
+	running bool
+}
+
+// NewTransport 创建 gRPC 命令传输；cfg 为 nil 时使用默认配置。
+func NewTransport(client IClient, cfg *Config) (*Transport, error) {
+	if client == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "grpc client cannot be nil")
+	}
+	config := Config{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	targets := make(map[string]string, len(config.Targets))
+	for messageType, target := range config.Targets {
+		targets[messageType] = target
+	}
+	config.Targets = targets
+	return &Transport{client: client, config: config}, nil
+}
+
+// Publish 把命令发送到目标服务并等待执行结果。
+func (t *Transport) Publish(ctx context.Context, message messaging.IMessage) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	if message == nil {
+		return errors.NewCode(errors.InvalidInput, "message is nil")
+	}
+	messageType := message.GetType()
+	if strings.TrimSpace(messageType) == "" {
+		return errors.NewCode(errors.InvalidInput, "message type is required")
+	}
+	if message.GetKind() != messaging.KindCommand {
+		return errors.NewCode(errors.Unsupported, "grpc transport only dispatches commands").
+			WithContext("message_kind", string(message.GetKind())).
+			WithContext("message_type", messageType)
+	}
+
+	t.mutex.RLock()
+	running := t.running
+	t.mutex.RUnlock()
+	if !running {
+		return errors.NewCode(errors.Conflict, "grpc transport is not running")
+	}
+
+	target := t.target(messageType)
+	if target == "" {
+		return errors.NewCode(errors.NotFound, "no grpc target configured for command").
+			WithContext("message_type", messageType)
+	}
+	body, err := codec.EncodeMessage(message)
+	if err != nil {
+		return err
+	}
+
+	callCtx := ctx
+	if t.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, t.config.Timeout)
+		defer cancel()
+	}
+	resp, err := t.client.Dispatch(callCtx, target, &DispatchRequest{
+		MessageID:   message.GetID(),
+		MessageType: messageType,
+		Body:        body,
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		code := errors.Dependency
+		if callCtx.Err() != nil {
+			code = errors.Timeout
+		}
+		return errors.Wrap(err, code, "dispatch over grpc failed").
+			WithContext("message_id", message.GetID()).
+			WithContext("target", target)
+	}
+	if resp == nil {
+		return errors.NewCode(errors.Dependency, "grpc dispatch returned no response").
+			WithContext("message_id", message.GetID()).
+			WithContext("target", target)
+	}
+	if err := resp.Err(); err != nil {
+		var appErr *errors.AppError
+		if errors.As(err, &appErr) && appErr != nil {
+			return appErr.WithContext("target", target)
+		}
+		return err
+	}
+	return nil
+}
+
+// PublishAll 按顺序同步投递一批命令，遇错立即停止（之前的命令已执行）。
+func (t *Transport) PublishAll(ctx context.Context, messages []messaging.IMessage) error {
+	for i, message := range messages {
+		if err := t.Publish(ctx, message); err != nil {
+			var appErr *errors.AppError
+			if errors.As(err, &appErr) && appErr != nil {
+				return appErr.WithContext("index", i)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Subscribe 不受支持：命令处理器应注册在接收方 Server 使用的 command.ICommandExecutor 上。
+func (t *Transport) Subscribe(ctx context.Context, messageType string, handler messaging.IMessageHandler) (messaging.UnsubscribeFunc, error) {
+	return nil, errors.NewCode(errors.Unsupported, "grpc transport does not accept local subscriptions").
+		WithContext("message_type", messageType)
+}
+
+// Start 将传输层切换到可发布状态。
+func (t *Transport) Start(ctx context.Context) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.running {
+		return errors.NewCode(errors.Conflict, "grpc transport is already running")
+	}
+	t.running = true
+	return nil
+}
+
+// Stop 关闭传输层，之后不再接受新的发布请求；进行中的调用由各自的 ctx 控制。
+func (t *Transport) Stop(ctx context.Context) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if !t.running {
+		return messaging.NewTransportAlreadyStoppedError("grpc transport is not running")
+	}
+	t.running = false
+	return nil
+}
+
+// Stats 返回传输统计信息。
+func (t *Transport) Stats() messaging.TransportStats {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return messaging.TransportStats{Running: t.running, MessageTypes: []string{}}
+}
+
+// IsSynchronous 标记 Publish 的返回值即远端 handler 的执行结果。
+func (t *Transport) IsSynchronous() bool { return true }
+
+func (t *Transport) target(messageType string) string {
+	if target, ok := t.config.Targets[messageType]; ok && target != "" {
+		return target
+	}
+	return t.config.DefaultTarget
+}
+
+// Server 是 Dispatch RPC 的服务端实现，把收到的命令交给本地执行器执行。
+type Server struct {
+	executor command.ICommandExecutor
+}
+
+// NewServer 创建 Dispatch 服务端。
+func NewServer(executor command.ICommandExecutor) (*Server, error) {
+	if executor == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "command executor cannot be nil")
+	}
+	return &Server{executor: executor}, nil
+}
+
+// Dispatch 解码并执行一条命令，执行结果（包括解码失败）编码在响应中返回。
+//
+// 链路信息（tenant/trace/operator）随命令元数据传递，由执行器派生到 ctx；
+// 调用方的 deadline 由 gRPC 透传到 ctx。
+func (s *Server) Dispatch(ctx context.Context, req *DispatchRequest) *DispatchResponse {
+	if req == nil || len(req.Body) == 0 {
+		return NewDispatchResponse(errors.NewCode(errors.InvalidInput, "dispatch request body is empty"))
+	}
+	message, err := codec.DecodeMessage(req.Body)
+	if err != nil {
+		return NewDispatchResponse(err)
+	}
+	cmd, ok := message.(*command.Command)
+	if !ok {
+		return NewDispatchResponse(errors.NewCode(errors.InvalidInput, "dispatch request is not a command").
+			WithContext("message_kind", string(message.GetKind())).
+			WithContext("message_type", message.GetType()))
+	}
+	return NewDispatchResponse(s.executor.Execute(ctx, cmd))
+}
+
+// RemoteExecutor 把基于 Transport 的消息总线适配为 command.ICommandExecutor，
+// 供 process/saga 等需要依赖执行结果推进流程的编排层调用远端命令。
+type RemoteExecutor struct {
+	bus messaging.IMessageBus
+}
+
+// NewRemoteExecutor 创建远端命令执行器；bus 必须以本包的 Transport 为传输，发布侧中间件照常生效。
+func NewRemoteExecutor(bus messaging.IMessageBus) (*RemoteExecutor, error) {
+	if bus == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "message bus cannot be nil")
+	}
+	return &RemoteExecutor{bus: bus}, nil
+}
+
+// Execute 同步执行远端命令并返回其执行结果。
+func (e *RemoteExecutor) Execute(ctx context.Context, cmd *command.Command) error {
+	if cmd == nil {
+		return errors.NewCode(errors.InvalidInput, "command cannot be nil")
+	}
+	return e.bus.Publish(ctx, cmd)
+}
+
+var (
+	_ messaging.ITransport            = (*Transport)(nil)
+	_ messaging.ISynchronousTransport = (*Transport)(nil)
+	_ command.ICommandExecutor        = (*RemoteExecutor)(nil)
+)
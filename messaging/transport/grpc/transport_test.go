@@ -0,0 +1,175 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/messaging"
+	"gochen/messaging/command"
+)
+
+// loopback 把 Dispatch 直接转给同进程的 Server，模拟一次 gRPC 往返。
+type loopback struct {
+	mu      sync.Mutex
+	servers map[string]*Server
+	targets []string
+	err     error
+	delay   time.Duration
+}
+
+func (l *loopback) Dispatch(ctx context.Context, target string, req *DispatchRequest) (*DispatchResponse, error) {
+	l.mu.Lock()
+	l.targets = append(l.targets, target)
+	server := l.servers[target]
+	l.mu.Unlock()
+	if l.delay > 0 {
+		select {
+		case <-time.After(l.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if l.err != nil {
+		return nil, l.err
+	}
+	return server.Dispatch(ctx, req), nil
+}
+
+func newOrdersServer(t *testing.T, handler command.CommandHandlerFunc) *Server {
+	t.Helper()
+	executor := command.NewCommandExecutor()
+	require.NoError(t, executor.RegisterHandler("CreateOrder", handler))
+	server, err := NewServer(executor)
+	require.NoError(t, err)
+	return server
+}
+
+func startTransport(t *testing.T, client IClient, cfg *Config) *Transport {
+	t.Helper()
+	transport, err := NewTransport(client, cfg)
+	require.NoError(t, err)
+	require.NoError(t, transport.Start(context.Background()))
+	t.Cleanup(func() { _ = messaging.StopTransport(context.Background(), transport) })
+	return transport
+}
+
+func TestTransport_DispatchReturnsRemoteResult(t *testing.T) {
+	var gotTenant string
+	var gotPayload map[string]any
+	server := newOrdersServer(t, func(ctx context.Context, cmd *command.Command) error {
+		gotTenant = contextx.TenantID(ctx)
+		require.NoError(t, cmd.GetPayload().DecodeTo(&gotPayload))
+		if gotPayload["sku"] == "missing" {
+			return errors.NewCode(errors.NotFound, "sku not found").WithContext("sku", "missing")
+		}
+		return nil
+	})
+	client := &loopback{servers: map[string]*Server{"orders": server}}
+	transport := startTransport(t, client, &Config{Targets: map[string]string{"CreateOrder": "orders"}})
+	bus := messaging.NewMessageBus(transport)
+
+	ctx, err := contextx.WithTenantID(context.Background(), "tenant-a")
+	require.NoError(t, err)
+	require.NoError(t, bus.Publish(ctx, command.NewCommand("c1", "CreateOrder", "o1", "Order", map[string]any{"sku": "a"})))
+	require.Equal(t, "tenant-a", gotTenant)
+	require.Equal(t, "a", gotPayload["sku"])
+
+	err = bus.Publish(ctx, command.NewCommand("c2", "CreateOrder", "o2", "Order", map[string]any{"sku": "missing"}))
+	require.Error(t, err)
+	require.True(t, errors.Is(err, errors.NotFound))
+	appErr, ok := errors.AsType[*errors.AppError](err)
+	require.True(t, ok)
+	require.Equal(t, "missing", appErr.Details()["sku"])
+	require.Equal(t, "orders", appErr.Details()["target"])
+	require.Equal(t, []string{"orders", "orders"}, client.targets)
+}
+
+func TestTransport_RoutingAndValidation(t *testing.T) {
+	server := newOrdersServer(t, func(context.Context, *command.Command) error { return nil })
+	client := &loopback{servers: map[string]*Server{"orders": server, "default": server}}
+
+	routed := startTransport(t, client, &Config{Targets: map[string]string{"CreateOrder": "orders"}})
+	err := routed.Publish(context.Background(), command.NewCommand("c1", "CancelOrder", "o1", "Order", nil))
+	require.True(t, errors.Is(err, errors.NotFound))
+
+	err = routed.Publish(context.Background(), eventing.NewEvent[int64](1, "Order", "OrderCreated", 1, nil))
+	require.True(t, errors.Is(err, errors.Unsupported))
+
+	fallback := startTransport(t, client, &Config{DefaultTarget: "default"})
+	// 远端没有 CancelOrder 处理器，执行器的 NotFound 原样返回。
+	err = fallback.Publish(context.Background(), command.NewCommand("c2", "CancelOrder", "o1", "Order", nil))
+	require.True(t, errors.Is(err, errors.NotFound))
+	require.Equal(t, []string{"default"}, client.targets)
+
+	_, err = routed.Subscribe(context.Background(), "CreateOrder", nil)
+	require.True(t, errors.Is(err, errors.Unsupported))
+}
+
+func TestTransport_RPCFailures(t *testing.T) {
+	client := &loopback{err: errors.New("connection refused")}
+	transport := startTransport(t, client, &Config{DefaultTarget: "orders"})
+	err := transport.Publish(context.Background(), command.NewCommand("c1", "CreateOrder", "o1", "Order", nil))
+	require.True(t, errors.Is(err, errors.Dependency))
+
+	slow := startTransport(t, &loopback{delay: time.Second}, &Config{DefaultTarget: "orders", Timeout: 20 * time.Millisecond})
+	err = slow.Publish(context.Background(), command.NewCommand("c2", "CreateOrder", "o1", "Order", nil))
+	require.True(t, errors.Is(err, errors.Timeout))
+}
+
+func TestTransport_Lifecycle(t *testing.T) {
+	transport, err := NewTransport(&loopback{}, &Config{DefaultTarget: "orders"})
+	require.NoError(t, err)
+	err = transport.Publish(context.Background(), command.NewCommand("c1", "CreateOrder", "o1", "Order", nil))
+	require.True(t, errors.Is(err, errors.Conflict))
+
+	require.NoError(t, transport.Start(context.Background()))
+	require.True(t, transport.Stats().Running)
+	require.True(t, transport.IsSynchronous())
+	require.NoError(t, transport.Stop(context.Background()))
+	require.True(t, messaging.TransportAlreadyStopped(transport.Stop(context.Background())))
+}
+
+func TestServer_RejectsInvalidRequests(t *testing.T) {
+	server := newOrdersServer(t, func(context.Context, *command.Command) error { return nil })
+
+	resp := server.Dispatch(context.Background(), &DispatchRequest{})
+	require.Equal(t, string(errors.InvalidInput), resp.ErrorCode)
+
+	resp = server.Dispatch(context.Background(), &DispatchRequest{Body: []byte(`{"kind":"event","type":"OrderCreated","aggregate_id":1}`)})
+	require.True(t, errors.Is(resp.Err(), errors.InvalidInput))
+
+	resp = server.Dispatch(context.Background(), &DispatchRequest{Body: []byte(`not json`)})
+	require.True(t, errors.Is(resp.Err(), errors.InvalidInput))
+}
+
+func TestNewDispatchResponse_DropsStack(t *testing.T) {
+	resp := NewDispatchResponse(errors.NewCode(errors.Internal, "boom").WithContext("order_id", 7))
+	require.Equal(t, string(errors.Internal), resp.ErrorCode)
+	require.Equal(t, map[string]string{"order_id": "7"}, resp.ErrorDetails)
+
+	resp = NewDispatchResponse(context.DeadlineExceeded)
+	require.Equal(t, string(errors.Timeout), resp.ErrorCode)
+	require.Nil(t, NewDispatchResponse(nil).Err())
+}
+
+func TestRemoteExecutor_ExecutesThroughBus(t *testing.T) {
+	var executed []string
+	server := newOrdersServer(t, func(_ context.Context, cmd *command.Command) error {
+		executed = append(executed, cmd.GetID())
+		return nil
+	})
+	transport := startTransport(t, &loopback{servers: map[string]*Server{"orders": server}}, &Config{DefaultTarget: "orders"})
+	executor, err := NewRemoteExecutor(messaging.NewMessageBus(transport))
+	require.NoError(t, err)
+
+	require.NoError(t, executor.Execute(context.Background(), command.NewCommand("c1", "CreateOrder", "o1", "Order", nil)))
+	require.Equal(t, []string{"c1"}, executed)
+	require.True(t, errors.Is(executor.Execute(context.Background(), nil), errors.InvalidInput))
+}
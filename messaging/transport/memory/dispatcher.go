@@ -9,7 +9,6 @@ import (
 	"time"
 
 	"gochen/contextx"
-	"gochen/errors"
 	"gochen/logging"
 	"gochen/messaging"
 	"gochen/messaging/deadletter"
@@ -68,6 +67,7 @@ func (t *MemoryTransport) dispatch(ctx context.Context, message messaging.IMessa
 	}
 
 	t.mutex.RLock()
+	t.reportDepth()
 	// 收集精确匹配和通配符("*")的处理器
 	exact := t.handlers[messageType]
 	wildcard := t.handlers["*"]
@@ -101,8 +101,7 @@ func (t *MemoryTransport) dispatch(ctx context.Context, message messaging.IMessa
 						logging.String("panic", fmt.Sprint(r)),
 						logging.String("stack", string(debug.Stack())),
 					)
-					err = errors.NewCode(errors.Internal, "message handler panicked").
-						WithContext("panic", fmt.Sprint(r))
+					err = messaging.PanicError("message handler panicked", r)
 				}
 			}()
 			return handler.Handle(derived, message)
@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/messaging/deadletter"
+	"gochen/observe"
+)
+
+const (
+	// MetricQueueDepth 是内存传输当前排队消息数（所有队列之和），标签 transport=memory。
+	MetricQueueDepth = "messaging_transport_queue_depth"
+	// MetricOverflow 是队列满时触发溢出策略的次数，标签 policy 为策略、outcome 为 rejected/timeout/dropped。
+	MetricOverflow = "messaging_transport_overflow_total"
+
+	// DefaultBlockTimeout 是 OverflowBlock 策略的默认最长等待时间。
+	DefaultBlockTimeout = time.Second
+
+	// OverflowHandlerType 是 OverflowDropOldest 丢弃的消息写入死信时使用的 HandlerType。
+	OverflowHandlerType = "memory-transport:overflow"
+)
+
+// OverflowPolicy 定义队列满时 Publish 的行为。
+type OverflowPolicy string
+
+const (
+	// OverflowReject 立即返回队列已满错误（默认）。
+	OverflowReject OverflowPolicy = "reject"
+	// OverflowBlock 等待队列出现空位，最多 BlockTimeout（或 ctx 结束），超时返回队列已满错误。
+	OverflowBlock OverflowPolicy = "block"
+	// OverflowDropOldest 丢弃同一队列中最早的消息为新消息腾出空间；被丢弃的消息写入死信（若已配置）。
+	OverflowDropOldest OverflowPolicy = "drop_oldest"
+)
+
+// OverflowConfig 定义内存传输的背压配置。
+type OverflowConfig struct {
+	// Policy 为空时使用 OverflowReject。
+	Policy OverflowPolicy
+
+	// BlockTimeout 是 OverflowBlock 的最长等待时间（<=0 时使用 DefaultBlockTimeout）。
+	// 必须有上限：等待期间持有传输读锁，Stop 会在等待结束后才开始。
+	BlockTimeout time.Duration
+}
+
+// SetOverflowConfig 配置队列满时的溢出策略；应在 Start 前装配。
+func (t *MemoryTransport) SetOverflowConfig(cfg OverflowConfig) {
+	if cfg.Policy == "" {
+		cfg.Policy = OverflowReject
+	}
+	if cfg.BlockTimeout <= 0 {
+		cfg.BlockTimeout = DefaultBlockTimeout
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.overflow = cfg
+}
+
+// SetMetrics 配置队列深度与溢出指标（可选）。
+func (t *MemoryTransport) SetMetrics(metrics observe.IMetrics) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.metrics = metrics
+}
+
+// enqueue 按溢出策略把消息写入队列；调用方需持有读锁且已确认传输处于运行态。
+func (t *MemoryTransport) enqueue(ctx context.Context, message messaging.IMessage) error {
+	queue := t.queueFor(message)
+	select {
+	case queue <- message:
+		t.reportDepth()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	switch t.overflow.Policy {
+	case OverflowBlock:
+		return t.enqueueBlocking(ctx, queue, message)
+	case OverflowDropOldest:
+		return t.enqueueDropOldest(ctx, queue, message)
+	default:
+		t.reportOverflow("rejected")
+		return t.queueFullError(message)
+	}
+}
+
+func (t *MemoryTransport) enqueueBlocking(ctx context.Context, queue chan messaging.IMessage, message messaging.IMessage) error {
+	timer := time.NewTimer(t.overflow.BlockTimeout)
+	defer timer.Stop()
+	select {
+	case queue <- message:
+		t.reportDepth()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		t.reportOverflow("timeout")
+		return t.queueFullError(message).WithContext("block_timeout", t.overflow.BlockTimeout.String())
+	}
+}
+
+// enqueueDropOldest 反复“取出最早一条 → 尝试写入”直到写入成功；并发发布者或 worker 可能抢先占用空位。
+func (t *MemoryTransport) enqueueDropOldest(ctx context.Context, queue chan messaging.IMessage, message messaging.IMessage) error {
+	for {
+		select {
+		case dropped := <-queue:
+			t.reportOverflow("dropped")
+			t.recordDropped(ctx, dropped)
+		default:
+		}
+		select {
+		case queue <- message:
+			t.reportDepth()
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+	}
+}
+
+// recordDropped 记录被溢出策略丢弃的消息：告警日志 + 死信（若已配置）。
+func (t *MemoryTransport) recordDropped(ctx context.Context, dropped messaging.IMessage) {
+	t.logger.Warn(ctx, "message dropped by overflow policy",
+		logging.String("message_type", dropped.GetType()),
+		logging.String("message_id", dropped.GetID()),
+		logging.String("policy", string(t.overflow.Policy)))
+	if t.deadLetterSink == nil {
+		return
+	}
+	if err := t.deadLetterSink.Write(ctx, deadletter.Entry{
+		Message:     dropped,
+		HandlerType: OverflowHandlerType,
+		Err:         t.queueFullError(dropped),
+		OccurredAt:  time.Now(),
+	}); err != nil {
+		t.logger.Error(ctx, "write dead letter entry failed",
+			logging.String("message_type", dropped.GetType()),
+			logging.String("message_id", dropped.GetID()),
+			logging.String("handler", OverflowHandlerType),
+			logging.Error(err))
+	}
+}
+
+func (t *MemoryTransport) queueFullError(message messaging.IMessage) *errors.AppError {
+	return messaging.NewQueueFullError("message queue is full").
+		WithContext("message_type", message.GetType()).
+		WithContext("queue_size", t.queueSize).
+		WithContext("policy", string(t.overflow.Policy))
+}
+
+// reportDepth 上报队列深度；调用方需持有读锁。
+func (t *MemoryTransport) reportDepth() {
+	if t.metrics == nil {
+		return
+	}
+	t.metrics.Gauge(MetricQueueDepth, float64(queueDepth(t.queues)), map[string]string{"transport": "memory"})
+}
+
+func (t *MemoryTransport) reportOverflow(outcome string) {
+	if t.metrics == nil {
+		return
+	}
+	t.metrics.Counter(MetricOverflow, 1, map[string]string{
+		"transport": "memory",
+		"policy":    string(t.overflow.Policy),
+		"outcome":   outcome,
+	})
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	msg "gochen/messaging"
+	dlqmem "gochen/messaging/deadletter/memory"
+	"gochen/observe"
+)
+
+// TestMemoryTransport_OverflowReject 验证默认策略立即返回可识别的队列已满错误并上报指标。
+func TestMemoryTransport_OverflowReject(t *testing.T) {
+	ctx := context.Background()
+	tpt := NewMemoryTransportForTest(1)
+	metrics := observe.NewInMemoryMetrics()
+	tpt.SetMetrics(metrics)
+	require.NoError(t, tpt.Start(ctx))
+
+	require.NoError(t, tpt.Publish(ctx, &msg.Message{ID: "m1", Type: "test"}))
+	err := tpt.Publish(ctx, &msg.Message{ID: "m2", Type: "test"})
+	require.True(t, msg.IsQueueFull(err))
+	require.True(t, errors.Is(err, errors.Queue))
+	require.Equal(t, float64(1), metrics.GaugeValue(MetricQueueDepth, map[string]string{"transport": "memory"}))
+	require.Equal(t, int64(1), metrics.CounterValue(MetricOverflow, map[string]string{
+		"transport": "memory", "policy": "reject", "outcome": "rejected",
+	}))
+
+	err = tpt.PublishAll(ctx, []msg.IMessage{&msg.Message{ID: "m3", Type: "test"}})
+	require.True(t, msg.IsQueueFull(err))
+
+	_, err = tpt.StopWithSnapshot(ctx)
+	require.NoError(t, err)
+}
+
+// TestMemoryTransport_OverflowBlock 验证阻塞策略在出现空位时写入成功，超时后返回队列已满错误。
+func TestMemoryTransport_OverflowBlock(t *testing.T) {
+	ctx := context.Background()
+	tpt := NewMemoryTransportForTest(1)
+	tpt.SetOverflowConfig(OverflowConfig{Policy: OverflowBlock, BlockTimeout: 50 * time.Millisecond})
+	require.NoError(t, tpt.Start(ctx))
+	require.NoError(t, tpt.Publish(ctx, &msg.Message{ID: "m1", Type: "test"}))
+
+	err := tpt.Publish(ctx, &msg.Message{ID: "m2", Type: "test"})
+	require.True(t, msg.IsQueueFull(err))
+
+	queue := tpt.queues[0]
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-queue
+	}()
+	require.NoError(t, tpt.Publish(ctx, &msg.Message{ID: "m3", Type: "test"}))
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	require.ErrorIs(t, tpt.Publish(cancelled, &msg.Message{ID: "m4", Type: "test"}), context.Canceled)
+
+	pending, err := tpt.StopWithSnapshot(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, "m3", pending[0].GetID())
+}
+
+// TestMemoryTransport_OverflowDropOldest 验证丢弃最早消息为新消息腾出空间，被丢弃的消息写入死信。
+func TestMemoryTransport_OverflowDropOldest(t *testing.T) {
+	ctx := context.Background()
+	tpt := NewMemoryTransportForTest(2)
+	sink := dlqmem.NewSink()
+	tpt.SetDeadLetterSink(sink)
+	tpt.SetOverflowConfig(OverflowConfig{Policy: OverflowDropOldest})
+	require.NoError(t, tpt.Start(ctx))
+
+	for _, id := range []string{"m1", "m2", "m3"} {
+		require.NoError(t, tpt.Publish(ctx, &msg.Message{ID: id, Type: "test"}))
+	}
+
+	entries := sink.Entries()
+	require.Len(t, entries, 1)
+	require.Equal(t, "m1", entries[0].Message.GetID())
+	require.Equal(t, OverflowHandlerType, entries[0].HandlerType)
+	require.True(t, msg.IsQueueFull(entries[0].Err))
+
+	pending, err := tpt.StopWithSnapshot(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"m2", "m3"}, []string{pending[0].GetID(), pending[1].GetID()})
+}
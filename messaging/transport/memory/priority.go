@@ -0,0 +1,224 @@
+package memory
+
+import (
+	"context"
+
+	"gochen/messaging"
+)
+
+// priorityLevels 是优先级模式的队列数（messaging.PriorityLow..PriorityHigh）。
+const priorityLevels = int(messaging.PriorityHigh) + 1
+
+// DefaultPriorityWeights 是优先级模式的默认调度权重：各队列都有积压时，
+// 每 13 条消息中高/普通/低优先级分别约取 8/4/1 条。
+var DefaultPriorityWeights = map[messaging.Priority]int{
+	messaging.PriorityHigh:   8,
+	messaging.PriorityNormal: 4,
+	messaging.PriorityLow:    1,
+}
+
+// PriorityConfig 定义内存传输的优先级模式。
+type PriorityConfig struct {
+	// Classify 决定消息优先级（默认 messaging.PriorityOf，即读取元数据 priority；未设置为 normal）。
+	Classify func(message messaging.IMessage) messaging.Priority
+
+	// Weights 是各优先级的调度权重（默认 DefaultPriorityWeights；缺失或 <=0 的级别按 1 处理）。
+	// 权重只在多个队列同时有积压时生效；首选队列为空时总是取当前最高优先级的消息。
+	Weights map[messaging.Priority]int
+
+	// Strict 为 true 时严格按优先级出队（高优先级队列清空前不处理低优先级），低优先级可能长时间饥饿。
+	Strict bool
+}
+
+// PriorityByKind 是按消息类别分级的 Classify：显式设置的优先级优先，否则命令为 high、其余为 normal。
+//
+// 适用于“命令不应被投影/重放流量阻塞”的场景；重放等批量发布方可再显式标记为 low。
+func PriorityByKind(message messaging.IMessage) messaging.Priority {
+	if priority, ok := messaging.LookupPriority(message); ok {
+		return priority
+	}
+	if message.GetKind() == messaging.KindCommand {
+		return messaging.PriorityHigh
+	}
+	return messaging.PriorityNormal
+}
+
+// NewPriorityMemoryTransport 创建优先级模式的内存传输：每个优先级一个容量为 queueSize 的队列，
+// worker 按加权轮询（或严格优先级）出队，使高优先级命令在投影重放等批量流量期间仍能及时处理。
+//
+// 同一优先级内保持 FIFO；不同优先级之间不保证发布顺序。cfg 为 nil 时使用默认配置。
+func NewPriorityMemoryTransport(queueSize, workerCount int, cfg *PriorityConfig) *MemoryTransport {
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	if workerCount <= 0 {
+		workerCount = defaultWorkerCount
+	}
+	t := newMemoryTransport(queueSize, workerCount)
+	t.priority = newPriorityScheduler(cfg)
+	t.queues = t.newQueues()
+	return t
+}
+
+// QueueDepthByPriority 返回各优先级队列当前深度；FIFO 模式返回 nil。
+func (t *MemoryTransport) QueueDepthByPriority() map[messaging.Priority]int {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	if t.priority == nil || len(t.queues) == 0 {
+		return nil
+	}
+	depths := make(map[messaging.Priority]int, len(t.queues))
+	for i, queue := range t.queues {
+		depths[messaging.Priority(i)] = len(queue)
+	}
+	return depths
+}
+
+// priorityScheduler 保存分级函数与加权轮询序列。
+type priorityScheduler struct {
+	classify func(message messaging.IMessage) messaging.Priority
+	// sequence 是按平滑加权轮询展开的首选队列下标序列；严格模式下为空。
+	sequence []int
+}
+
+func newPriorityScheduler(cfg *PriorityConfig) *priorityScheduler {
+	config := PriorityConfig{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.Classify == nil {
+		config.Classify = messaging.PriorityOf
+	}
+	if config.Weights == nil {
+		config.Weights = DefaultPriorityWeights
+	}
+	s := &priorityScheduler{classify: config.Classify}
+	if !config.Strict {
+		s.sequence = weightedSequence(config.Weights)
+	}
+	return s
+}
+
+// level 返回消息所在队列下标；超出范围的优先级被截断到最近的级别。
+func (s *priorityScheduler) level(message messaging.IMessage) int {
+	return min(max(int(s.classify(message)), 0), priorityLevels-1)
+}
+
+// weightedSequence 用平滑加权轮询展开一个调度周期，使高权重队列的机会均匀分布而非连续成段。
+func weightedSequence(weights map[messaging.Priority]int) []int {
+	effective := make([]int, priorityLevels)
+	total := 0
+	for i := range effective {
+		effective[i] = max(weights[messaging.Priority(i)], 1)
+		total += effective[i]
+	}
+	current := make([]int, priorityLevels)
+	sequence := make([]int, 0, total)
+	for range total {
+		best := -1
+		for i := priorityLevels - 1; i >= 0; i-- {
+			current[i] += effective[i]
+			if best < 0 || current[i] > current[best] {
+				best = i
+			}
+		}
+		current[best] -= total
+		sequence = append(sequence, best)
+	}
+	return sequence
+}
+
+// priorityWorker 按调度序列选择首选队列；首选队列为空时从高到低取任一非空队列，全部为空时阻塞等待。
+func (t *MemoryTransport) priorityWorker(ctx context.Context, queues []chan messaging.IMessage, workerID int, stopCh chan struct{}) {
+	open := make([]<-chan messaging.IMessage, len(queues))
+	for i, queue := range queues {
+		open[i] = queue
+	}
+	sequence := t.priority.sequence
+	// 错开各 worker 在序列中的起点，避免所有 worker 同时偏好同一队列。
+	pos := 0
+	if len(sequence) > 0 {
+		pos = workerID % len(sequence)
+	}
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+		}
+
+		preferred := len(open) - 1
+		if len(sequence) > 0 {
+			preferred = sequence[pos]
+			pos = (pos + 1) % len(sequence)
+		}
+		if message, ok := receiveReady(open, preferred); ok {
+			t.dispatch(ctx, message)
+			continue
+		}
+		if allClosed(open) {
+			return
+		}
+
+		select {
+		case message, ok := <-open[messaging.PriorityHigh]:
+			t.handleReceived(ctx, open, int(messaging.PriorityHigh), message, ok)
+		case message, ok := <-open[messaging.PriorityNormal]:
+			t.handleReceived(ctx, open, int(messaging.PriorityNormal), message, ok)
+		case message, ok := <-open[messaging.PriorityLow]:
+			t.handleReceived(ctx, open, int(messaging.PriorityLow), message, ok)
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+func (t *MemoryTransport) handleReceived(ctx context.Context, open []<-chan messaging.IMessage, level int, message messaging.IMessage, ok bool) {
+	if !ok {
+		open[level] = nil
+		return
+	}
+	t.dispatch(ctx, message)
+}
+
+// receiveReady 非阻塞地先尝试 preferred 队列，再按优先级从高到低尝试其余队列；已关闭的队列会被置为 nil。
+func receiveReady(open []<-chan messaging.IMessage, preferred int) (messaging.IMessage, bool) {
+	if message, ok := tryReceive(open, preferred); ok {
+		return message, true
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		if i == preferred {
+			continue
+		}
+		if message, ok := tryReceive(open, i); ok {
+			return message, true
+		}
+	}
+	return nil, false
+}
+
+func tryReceive(open []<-chan messaging.IMessage, level int) (messaging.IMessage, bool) {
+	if open[level] == nil {
+		return nil, false
+	}
+	select {
+	case message, ok := <-open[level]:
+		if !ok {
+			open[level] = nil
+			return nil, false
+		}
+		return message, true
+	default:
+		return nil, false
+	}
+}
+
+func allClosed(open []<-chan messaging.IMessage) bool {
+	for _, queue := range open {
+		if queue != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	msg "gochen/messaging"
+)
+
+// orderRecorder 记录处理顺序；第一条消息会阻塞在 gate 上，便于在 worker 忙碌时堆积各优先级消息。
+type orderRecorder struct {
+	gate chan struct{}
+	once sync.Once
+
+	mu    sync.Mutex
+	order []string
+	done  chan struct{}
+	want  int
+}
+
+func (h *orderRecorder) Handle(_ context.Context, m msg.IMessage) error {
+	h.once.Do(func() { <-h.gate })
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.order = append(h.order, m.GetID())
+	if len(h.order) == h.want {
+		close(h.done)
+	}
+	return nil
+}
+
+func (h *orderRecorder) Type() string { return "orderRecorder" }
+
+// runPriorityScenario 在单 worker 被阻塞期间发布 6 条 low 投影事件与 6 条命令，返回处理顺序（不含首条阻塞消息）。
+func runPriorityScenario(t *testing.T, cfg *PriorityConfig) []string {
+	t.Helper()
+	ctx := context.Background()
+	tpt := NewPriorityMemoryTransport(16, 1, cfg)
+	require.NoError(t, tpt.Start(ctx))
+	t.Cleanup(func() { _ = tpt.Stop(context.Background()) })
+
+	handler := &orderRecorder{gate: make(chan struct{}), done: make(chan struct{}), want: 13}
+	_, err := tpt.Subscribe(ctx, "*", handler)
+	require.NoError(t, err)
+
+	require.NoError(t, tpt.Publish(ctx, msg.NewMessage("warmup", msg.KindEvent, "Warmup", nil)))
+	require.Eventually(t, func() bool { return tpt.Stats().QueueDepth == 0 }, time.Second, time.Millisecond)
+
+	for i := range 6 {
+		replayed := msg.NewMessage("replay-"+string(rune('a'+i)), msg.KindEvent, "OrderProjected", nil)
+		msg.SetPriority(replayed, msg.PriorityLow)
+		require.NoError(t, tpt.Publish(ctx, replayed))
+	}
+	for i := range 6 {
+		require.NoError(t, tpt.Publish(ctx, msg.NewMessage("cmd-"+string(rune('a'+i)), msg.KindCommand, "PlaceOrder", nil)))
+	}
+	require.Equal(t, map[msg.Priority]int{msg.PriorityLow: 6, msg.PriorityNormal: 0, msg.PriorityHigh: 6}, tpt.QueueDepthByPriority())
+
+	close(handler.gate)
+	select {
+	case <-handler.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("messages not processed in time")
+	}
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	return append([]string(nil), handler.order[1:]...)
+}
+
+func lastIndexWithPrefix(order []string, prefix string) int {
+	last := -1
+	for i, id := range order {
+		if len(id) >= len(prefix) && id[:len(prefix)] == prefix {
+			last = i
+		}
+	}
+	return last
+}
+
+// TestPriorityMemoryTransport_StrictPreemptsBulkTraffic 验证严格模式下命令全部先于积压的重放事件处理。
+func TestPriorityMemoryTransport_StrictPreemptsBulkTraffic(t *testing.T) {
+	order := runPriorityScenario(t, &PriorityConfig{Classify: PriorityByKind, Strict: true})
+	require.Equal(t, []string{"cmd-a", "cmd-b", "cmd-c", "cmd-d", "cmd-e", "cmd-f"}, order[:6])
+}
+
+// TestPriorityMemoryTransport_WeightedDispatch 验证加权模式下命令优先，同时低优先级不会被完全饿死。
+func TestPriorityMemoryTransport_WeightedDispatch(t *testing.T) {
+	order := runPriorityScenario(t, &PriorityConfig{Classify: PriorityByKind})
+	// 一个调度周期（8/4/1）内低优先级最多插入 1 条。
+	require.LessOrEqual(t, lastIndexWithPrefix(order, "cmd-"), 6)
+	require.Len(t, order, 12)
+
+	order = runPriorityScenario(t, &PriorityConfig{
+		Classify: PriorityByKind,
+		Weights:  map[msg.Priority]int{msg.PriorityHigh: 1, msg.PriorityLow: 1},
+	})
+	require.Less(t, lastIndexWithPrefix(order[:4], "replay-"), 4)
+	require.GreaterOrEqual(t, lastIndexWithPrefix(order[:4], "replay-"), 0)
+}
+
+// TestPriorityByKind 验证显式优先级优先于按类别推断。
+func TestPriorityByKind(t *testing.T) {
+	command := msg.NewMessage("c1", msg.KindCommand, "PlaceOrder", nil)
+	require.Equal(t, msg.PriorityHigh, PriorityByKind(command))
+	msg.SetPriority(command, msg.PriorityLow)
+	require.Equal(t, msg.PriorityLow, PriorityByKind(command))
+	require.Equal(t, msg.PriorityNormal, PriorityByKind(msg.NewMessage("e1", msg.KindEvent, "OrderPlaced", nil)))
+
+	priority, ok := msg.ParsePriority(" HIGH ")
+	require.True(t, ok)
+	require.Equal(t, msg.PriorityHigh, priority)
+}
+
+// TestWeightedSequence 验证平滑加权轮询展开的调度周期。
+func TestWeightedSequence(t *testing.T) {
+	sequence := weightedSequence(map[msg.Priority]int{msg.PriorityHigh: 2, msg.PriorityNormal: 1})
+	require.Equal(t, []int{2, 1, 0, 2}, sequence)
+}
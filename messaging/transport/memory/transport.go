@@ -11,6 +11,7 @@ import (
 	"gochen/logging"
 	"gochen/messaging"
 	"gochen/messaging/deadletter"
+	"gochen/observe"
 )
 
 const (
@@ -21,7 +22,7 @@ const (
 // MemoryTransport 使用内存队列和 worker 池实现异步消息传输。
 type MemoryTransport struct {
 	handlers    map[string][]messaging.IMessageHandler
-	queue       chan messaging.IMessage
+	queues      []chan messaging.IMessage
 	queueSize   int
 	workerCount int
 	workers     []chan struct{}
@@ -36,6 +37,13 @@ type MemoryTransport struct {
 
 	// workerCancel 用于在 StopWithSnapshot 超时/取消时，尽力取消正在执行的 handler（若 handler 尊重 ctx）。
 	workerCancel context.CancelFunc
+
+	// overflow 定义队列满时的行为（默认 OverflowReject）。
+	overflow OverflowConfig
+	metrics  observe.IMetrics
+
+	// priority 非 nil 时为优先级模式：每个 Priority 一个队列（下标即优先级），worker 按加权轮询取消息。
+	priority *priorityScheduler
 }
 
 // NewMemoryTransport 创建一个用于运行环境的内存传输实现。
@@ -60,14 +68,37 @@ func NewMemoryTransportForTest(queueSize int) *MemoryTransport {
 
 // newMemoryTransport 复用初始化逻辑构造内存传输实例。
 func newMemoryTransport(queueSize, workerCount int) *MemoryTransport {
-	return &MemoryTransport{
+	t := &MemoryTransport{
 		handlers:    make(map[string][]messaging.IMessageHandler),
-		queue:       make(chan messaging.IMessage, queueSize),
 		queueSize:   queueSize,
 		workerCount: workerCount,
 		workers:     make([]chan struct{}, workerCount),
 		logger:      logging.ComponentLogger("messaging.transport.memory"),
+		overflow:    OverflowConfig{Policy: OverflowReject, BlockTimeout: DefaultBlockTimeout},
+	}
+	t.queues = t.newQueues()
+	return t
+}
+
+// newQueues 按模式创建队列：FIFO 模式 1 个，优先级模式每个 Priority 1 个（容量均为 queueSize）。
+func (t *MemoryTransport) newQueues() []chan messaging.IMessage {
+	count := 1
+	if t.priority != nil {
+		count = priorityLevels
+	}
+	queues := make([]chan messaging.IMessage, count)
+	for i := range queues {
+		queues[i] = make(chan messaging.IMessage, t.queueSize)
+	}
+	return queues
+}
+
+// queueFor 返回消息应进入的队列。
+func (t *MemoryTransport) queueFor(message messaging.IMessage) chan messaging.IMessage {
+	if t.priority == nil {
+		return t.queues[0]
 	}
+	return t.queues[t.priority.level(message)]
 }
 
 // Publish 把一条消息投递到内存队列，等待 worker 异步处理。
@@ -89,14 +120,7 @@ func (t *MemoryTransport) Publish(ctx context.Context, message messaging.IMessag
 		return errors.NewCode(errors.Conflict, "memory transport is not running")
 	}
 
-	select {
-	case t.queue <- message:
-		return nil
-	case <-ctx.Done():
-		return ctx.Err()
-	default:
-		return errors.NewCodeWithCause(errors.Queue, "message queue is full", nil)
-	}
+	return t.enqueue(ctx, message)
 }
 
 // PublishAll 按顺序把一批消息写入内存队列。
@@ -123,12 +147,8 @@ func (t *MemoryTransport) PublishAll(ctx context.Context, messages []messaging.I
 			return errors.NewCode(errors.InvalidInput, "message type is required")
 		}
 
-		select {
-		case t.queue <- message:
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			return errors.NewCodeWithCause(errors.Queue, "message queue is full", nil)
+		if err := t.enqueue(ctx, message); err != nil {
+			return err
 		}
 	}
 
@@ -152,8 +172,8 @@ func (t *MemoryTransport) Stats() messaging.TransportStats {
 		Running:      t.running,
 		HandlerCount: handlerCount,
 		MessageTypes: messageTypes,
-		QueueSize:    t.queueSize,
-		QueueDepth:   len(t.queue),
+		QueueSize:    t.queueSize * len(t.queues),
+		QueueDepth:   queueDepth(t.queues),
 		WorkerCount:  t.workerCount,
 	}
 }
@@ -167,3 +187,11 @@ func (t *MemoryTransport) SetDeadLetterSink(sink deadletter.ISink) {
 	defer t.mutex.Unlock()
 	t.deadLetterSink = sink
 }
+
+func queueDepth(queues []chan messaging.IMessage) int {
+	depth := 0
+	for _, queue := range queues {
+		depth += len(queue)
+	}
+	return depth
+}
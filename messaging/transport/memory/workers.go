@@ -30,7 +30,7 @@ func (t *MemoryTransport) Start(ctx context.Context) error {
 	}
 
 	// 允许 Close 后重启：每次 Start 都重建队列与 worker stop channel。
-	t.queue = make(chan messaging.IMessage, t.queueSize)
+	t.queues = t.newQueues()
 	t.workers = make([]chan struct{}, t.workerCount)
 
 	t.running = true
@@ -51,7 +51,7 @@ func (t *MemoryTransport) Start(ctx context.Context) error {
 		t.workers[i] = stopCh
 
 		t.wg.Add(1)
-		go t.worker(workerCtx, t.queue, i, stopCh)
+		go t.worker(workerCtx, t.queues, i, stopCh)
 	}
 
 	t.mutex.Unlock()
@@ -78,13 +78,15 @@ func (t *MemoryTransport) StopWithSnapshot(ctx context.Context) ([]messaging.IMe
 	// 标记为已停止，并复制 queue 引用，避免在持锁状态下阻塞等待
 	t.running = false
 	t.closing = true
-	queue := t.queue
+	queues := t.queues
 	workers := append([]chan struct{}(nil), t.workers...)
 	cancel := t.workerCancel
 	t.mutex.Unlock()
 
 	// 关闭队列，Worker 将在读取完缓冲中的消息后自然退出
-	close(queue)
+	for _, queue := range queues {
+		close(queue)
+	}
 
 	// 不主动关闭 stopCh，避免抢占队列 flush；队列关闭后 worker 会自然退出
 
@@ -105,11 +107,9 @@ func (t *MemoryTransport) StopWithSnapshot(ctx context.Context) ([]messaging.IMe
 		// 读取剩余未消费的消息：
 		// - workerCount=0（测试）时，这里会把队列中所有消息都返回给调用方；
 		// - 正常情况下 worker 会在队列关闭后 drain 完成，pending 通常为空。
-		for msg := range queue {
-			pending = append(pending, msg)
-		}
+		pending = drainQueues(queues)
 		t.mutex.Lock()
-		t.queue = nil
+		t.queues = nil
 		t.workers = nil
 		t.closing = false
 		t.workerCancel = nil
@@ -136,19 +136,13 @@ func (t *MemoryTransport) StopWithSnapshot(ctx context.Context) ([]messaging.IMe
 			}()
 		}
 
-		for {
-			msg, ok := <-queue
-			if !ok {
-				break
-			}
-			pending = append(pending, msg)
-		}
+		pending = drainQueues(queues)
 
 		// 背景回收：等待 worker 全退出后再允许 Start。
 		go func() {
 			t.wg.Wait()
 			t.mutex.Lock()
-			t.queue = nil
+			t.queues = nil
 			t.workers = nil
 			t.closing = false
 			t.workerCancel = nil
@@ -161,9 +155,15 @@ func (t *MemoryTransport) StopWithSnapshot(ctx context.Context) ([]messaging.IMe
 }
 
 // worker 持续从队列取消息并分发给已注册的处理器。
-func (t *MemoryTransport) worker(ctx context.Context, queue <-chan messaging.IMessage, workerID int, stopCh chan struct{}) {
+func (t *MemoryTransport) worker(ctx context.Context, queues []chan messaging.IMessage, workerID int, stopCh chan struct{}) {
 	defer t.wg.Done()
 
+	if t.priority != nil {
+		t.priorityWorker(ctx, queues, workerID, stopCh)
+		return
+	}
+	queue := queues[0]
+
 	for {
 		select {
 		case message, ok := <-queue:
@@ -178,3 +178,14 @@ func (t *MemoryTransport) worker(ctx context.Context, queue <-chan messaging.IMe
 		}
 	}
 }
+
+// drainQueues 读出已关闭队列中剩余的消息；优先级模式下按优先级从高到低排列。
+func drainQueues(queues []chan messaging.IMessage) []messaging.IMessage {
+	var pending []messaging.IMessage
+	for i := len(queues) - 1; i >= 0; i-- {
+		for msg := range queues[i] {
+			pending = append(pending, msg)
+		}
+	}
+	return pending
+}
@@ -0,0 +1,164 @@
+// Package rabbitmq 提供基于 RabbitMQ（AMQP 0-9-1）的消息传输（messaging.ITransport）。
+//
+// 拓扑：Start 时按配置声明 exchange / queue / binding；消息按 RoutingKey(messageType) 发布到 Exchange，
+// Subscribe 把消费队列以相同的 routing key 绑定到 Exchange（"*" 订阅绑定为 "#"）。
+// 可靠性：可选 publisher confirms（等待 broker 确认后 Publish 才返回），消费侧按 Prefetch 限制未确认消息数，
+// 处理成功 ack，失败 nack（默认不重新入队，配合队列的 x-dead-letter-exchange 进入死信）。
+//
+// 框架核心不依赖 AMQP 客户端，业务侧实现 IClient 即可（见 README）。
+package rabbitmq
+
+import (
+	"context"
+	"time"
+
+	"gochen/logging"
+	"gochen/messaging"
+)
+
+const (
+	// DefaultExchange 是默认的发布 exchange。
+	DefaultExchange = "gochen.messages"
+	// DefaultExchangeKind 是默认 exchange 类型（按 routing key 模式路由）。
+	DefaultExchangeKind = "topic"
+	// DefaultPrefetch 是消费者默认的未确认消息上限（QoS）。
+	DefaultPrefetch = 32
+	// DefaultConfirmTimeout 是等待 publisher confirm 的默认超时。
+	DefaultConfirmTimeout = 5 * time.Second
+
+	// DefaultGroupQueuePrefix 是消费组队列名的默认前缀（队列名为前缀 + 组名）。
+	DefaultGroupQueuePrefix = "gochen.group."
+
+	// HeaderDeduplication 是 rabbitmq-message-deduplication 插件识别的去重头，
+	// 消息携带幂等生产者键（messaging.MetadataProducerKey）时写入该头。
+	HeaderDeduplication = "x-deduplication-header"
+
+	// WildcardRoutingKey 是 "*" 订阅在 topic exchange 上对应的绑定键。
+	WildcardRoutingKey = "#"
+
+	contentTypeJSON = "application/json"
+)
+
+// Exchange 描述需要声明的 exchange。
+type Exchange struct {
+	Name       string
+	Kind       string // direct/topic/fanout/headers，为空时使用 DefaultExchangeKind
+	Durable    bool
+	AutoDelete bool
+	Internal   bool
+	Args       map[string]any
+}
+
+// Binding 描述队列到 exchange 的绑定。
+type Binding struct {
+	Exchange   string
+	RoutingKey string
+}
+
+// Queue 描述需要声明的队列及其静态绑定。
+type Queue struct {
+	Name       string
+	Durable    bool
+	Exclusive  bool
+	AutoDelete bool
+	// Args 透传给 broker，例如 x-dead-letter-exchange、x-message-ttl、x-queue-type=quorum。
+	Args     map[string]any
+	Bindings []Binding
+}
+
+// Topology 是 Start 时声明的附加拓扑（如死信 exchange/队列、跨服务共享队列）。
+type Topology struct {
+	Exchanges []Exchange
+	Queues    []Queue
+}
+
+// Publishing 是一次发布请求。
+type Publishing struct {
+	Exchange    string
+	RoutingKey  string
+	MessageID   string
+	Type        string
+	ContentType string
+	Timestamp   time.Time
+	Headers     map[string]string
+	Body        []byte
+	// Persistent 对应 delivery_mode=2（持久化到磁盘）。
+	Persistent bool
+}
+
+// Delivery 是一条投递到消费者的消息。
+type Delivery struct {
+	MessageID   string
+	RoutingKey  string
+	Body        []byte
+	Redelivered bool
+
+	// Ack 确认消息已处理；Nack 拒绝消息，requeue=false 时进入队列的死信 exchange（若已配置）或被丢弃。
+	Ack  func() error
+	Nack func(requeue bool) error
+}
+
+// IClient 是传输所需的最小 AMQP 客户端能力。
+type IClient interface {
+	// DeclareExchange / DeclareQueue 幂等声明拓扑（参数与已存在的定义不一致时 broker 会报错）。
+	DeclareExchange(ctx context.Context, exchange Exchange) error
+	DeclareQueue(ctx context.Context, queue Queue) error
+	BindQueue(ctx context.Context, queue string, binding Binding) error
+	UnbindQueue(ctx context.Context, queue string, binding Binding) error
+
+	// Publish 发布消息；confirm 为 true 时需等待 broker 确认，被 nack 或 ctx 结束时返回错误。
+	Publish(ctx context.Context, publishing Publishing, confirm bool) error
+
+	// Consume 以 prefetch 为 QoS 开始消费队列；返回的 channel 在 ctx 结束或连接关闭后关闭。
+	Consume(ctx context.Context, queue, consumerTag string, prefetch int) (<-chan Delivery, error)
+}
+
+// Config 定义 RabbitMQ 传输配置。
+type Config struct {
+	// Exchange 是发布与订阅绑定使用的 exchange（默认 DefaultExchange，类型 ExchangeKind，持久化）。
+	Exchange     string
+	ExchangeKind string
+
+	// Queue 是本服务的消费队列；为空时只能发布，Subscribe 返回 errors.InvalidInput。
+	// 多实例共享同一队列即竞争消费；不同服务使用不同队列即各自收到一份。
+	Queue string
+	// QueueArgs 透传给消费队列与消费组队列的声明参数（如 x-dead-letter-exchange）。
+	QueueArgs map[string]any
+
+	// GroupQueue 把消费组名映射为队列名（默认 DefaultGroupQueue）。SubscribeGroup 为每个组声明一个持久化队列，
+	// 各实例以同一组名订阅即共享该队列（竞争消费），不同组各自收到一份；不依赖 Queue 配置。
+	GroupQueue func(group string) string
+
+	// Topology 是 Start 时额外声明的拓扑，先于 Exchange / Queue 声明。
+	Topology Topology
+
+	// RoutingKey 把消息类型映射为 routing key（默认原样使用消息类型）；发布与订阅绑定使用同一映射。
+	RoutingKey func(messageType string) string
+
+	// PublisherConfirms 为 true 时 Publish 等待 broker 确认（ConfirmTimeout 内），保证返回 nil 的消息已被 broker 接收。
+	PublisherConfirms bool
+	ConfirmTimeout    time.Duration
+
+	// Prefetch 是消费者未确认消息上限（默认 DefaultPrefetch）；Concurrency 是处理协程数（默认 1）。
+	Prefetch    int
+	Concurrency int
+
+	// RequeueOnError 为 true 时处理失败的消息重新入队；默认 nack 不重新入队，避免毒消息无限循环。
+	RequeueOnError bool
+
+	// ConsumerTag 为空时由 broker 生成。
+	ConsumerTag string
+
+	Logger logging.ILogger
+}
+
+// DefaultRoutingKey 原样使用消息类型作为 routing key。
+func DefaultRoutingKey(messageType string) string { return messageType }
+
+// DefaultGroupQueue 返回 DefaultGroupQueuePrefix + group。
+func DefaultGroupQueue(group string) string { return DefaultGroupQueuePrefix + group }
+
+var (
+	_ messaging.ITransport       = (*Transport)(nil)
+	_ messaging.IGroupSubscriber = (*Transport)(nil)
+)
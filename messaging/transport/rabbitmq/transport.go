@@ -0,0 +1,515 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/messaging/codec"
+	"gochen/messaging/transport/internal/subscriptions"
+)
+
+// Transport 是基于 RabbitMQ 的异步消息传输。
+type Transport struct {
+	client IClient
+	config Config
+	logger logging.ILogger
+
+	mutex      sync.RWMutex
+	handlers   map[string][]messaging.IMessageHandler
+	groups     map[string]*groupQueue
+	running    bool
+	consumeCtx context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// groupQueue 是一个消费组对应的队列及其处理器。
+type groupQueue struct {
+	queue     string
+	handlers  map[string][]messaging.IMessageHandler
+	consuming bool
+}
+
+// NewTransport 创建 RabbitMQ 传输；cfg 为 nil 时使用默认配置。
+func NewTransport(client IClient, cfg *Config) (*Transport, error) {
+	if client == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "rabbitmq client cannot be nil")
+	}
+	config := Config{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.Exchange == "" {
+		config.Exchange = DefaultExchange
+	}
+	if config.ExchangeKind == "" {
+		config.ExchangeKind = DefaultExchangeKind
+	}
+	if config.RoutingKey == nil {
+		config.RoutingKey = DefaultRoutingKey
+	}
+	if config.ConfirmTimeout <= 0 {
+		config.ConfirmTimeout = DefaultConfirmTimeout
+	}
+	if config.Prefetch <= 0 {
+		config.Prefetch = DefaultPrefetch
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 1
+	}
+	if config.GroupQueue == nil {
+		config.GroupQueue = DefaultGroupQueue
+	}
+	logger := config.Logger
+	if logger == nil {
+		logger = logging.ComponentLogger("messaging.transport.rabbitmq")
+	}
+	return &Transport{
+		client:   client,
+		config:   config,
+		logger:   logger,
+		handlers: make(map[string][]messaging.IMessageHandler),
+		groups:   make(map[string]*groupQueue),
+	}, nil
+}
+
+// Publish 把消息发布到 Exchange；启用 publisher confirms 时等待 broker 确认。
+func (t *Transport) Publish(ctx context.Context, message messaging.IMessage) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	if message == nil {
+		return errors.NewCode(errors.InvalidInput, "message is nil")
+	}
+	if strings.TrimSpace(message.GetType()) == "" {
+		return errors.NewCode(errors.InvalidInput, "message type is required")
+	}
+
+	t.mutex.RLock()
+	running := t.running
+	t.mutex.RUnlock()
+	if !running {
+		return errors.NewCode(errors.Conflict, "rabbitmq transport is not running")
+	}
+
+	body, err := codec.EncodeMessage(message)
+	if err != nil {
+		return err
+	}
+	headers := message.GetMetadata().MapCopy()
+	if producerKey, ok := headers[messaging.MetadataProducerKey]; ok && producerKey != "" {
+		headers[HeaderDeduplication] = producerKey
+	}
+	publishing := Publishing{
+		Exchange:    t.config.Exchange,
+		RoutingKey:  t.config.RoutingKey(message.GetType()),
+		MessageID:   message.GetID(),
+		Type:        message.GetType(),
+		ContentType: contentTypeJSON,
+		Timestamp:   message.GetTimestamp(),
+		Headers:     headers,
+		Body:        body,
+		Persistent:  true,
+	}
+
+	publishCtx := ctx
+	if t.config.PublisherConfirms {
+		var cancel context.CancelFunc
+		publishCtx, cancel = context.WithTimeout(ctx, t.config.ConfirmTimeout)
+		defer cancel()
+	}
+	if err := t.client.Publish(publishCtx, publishing, t.config.PublisherConfirms); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		code := errors.Dependency
+		if publishCtx.Err() != nil {
+			code = errors.Timeout
+		}
+		return errors.Wrap(err, code, "publish to rabbitmq failed").
+			WithContext("message_id", message.GetID()).
+			WithContext("routing_key", publishing.RoutingKey)
+	}
+	return nil
+}
+
+// PublishAll 按顺序发布一批消息，遇到第一个错误即返回（之前的消息已发布）。
+func (t *Transport) PublishAll(ctx context.Context, messages []messaging.IMessage) error {
+	for _, message := range messages {
+		if err := t.Publish(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Subscribe 注册处理器；同一消息类型的第一个处理器会把消费队列绑定到对应 routing key。
+func (t *Transport) Subscribe(ctx context.Context, messageType string, handler messaging.IMessageHandler) (messaging.UnsubscribeFunc, error) {
+	if t.config.Queue == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "rabbitmq transport requires a queue to subscribe").
+			WithContext("message_type", messageType)
+	}
+	unsubscribe, err := subscriptions.Subscribe(ctx, &t.mutex, t.handlers, messageType, handler)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mutex.RLock()
+	bind := t.running && len(t.handlers[messageType]) == 1
+	t.mutex.RUnlock()
+	if bind {
+		if err := t.client.BindQueue(ctx, t.config.Queue, t.binding(messageType)); err != nil {
+			_ = unsubscribe(ctx)
+			return nil, errors.Wrap(err, errors.Dependency, "bind rabbitmq queue failed").
+				WithContext("message_type", messageType)
+		}
+	}
+
+	var once sync.Once
+	return func(unsubCtx context.Context) error {
+		var err error
+		once.Do(func() {
+			if err = unsubscribe(unsubCtx); err != nil {
+				return
+			}
+			t.mutex.RLock()
+			unbind := t.running && len(t.handlers[messageType]) == 0
+			t.mutex.RUnlock()
+			if unbind {
+				if uerr := t.client.UnbindQueue(unsubCtx, t.config.Queue, t.binding(messageType)); uerr != nil {
+					err = errors.Wrap(uerr, errors.Dependency, "unbind rabbitmq queue failed").
+						WithContext("message_type", messageType)
+				}
+			}
+		})
+		return err
+	}, nil
+}
+
+// SubscribeGroup 以消费组方式注册处理器：组对应一个共享队列（Config.GroupQueue(group)），
+// 多个实例以同一组名订阅即竞争消费，不同组各自收到一份。运行中首次订阅某组时声明队列并开始消费。
+func (t *Transport) SubscribeGroup(ctx context.Context, messageType, group string, handler messaging.IMessageHandler) (messaging.UnsubscribeFunc, error) {
+	if strings.TrimSpace(group) == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "consumer group cannot be empty")
+	}
+	t.mutex.Lock()
+	queue, exists := t.groups[group]
+	if !exists {
+		queue = &groupQueue{queue: t.config.GroupQueue(group), handlers: make(map[string][]messaging.IMessageHandler)}
+		t.groups[group] = queue
+	}
+	t.mutex.Unlock()
+
+	unsubscribe, err := subscriptions.Subscribe(ctx, &t.mutex, queue.handlers, messageType, handler)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mutex.Lock()
+	if t.running {
+		if !queue.consuming {
+			// 首次消费：声明队列时一并绑定当前全部消息类型。
+			err = t.startGroupLocked(ctx, queue)
+		} else if len(queue.handlers[messageType]) == 1 {
+			if berr := t.client.BindQueue(ctx, queue.queue, t.binding(messageType)); berr != nil {
+				err = errors.Wrap(berr, errors.Dependency, "bind rabbitmq queue failed").
+					WithContext("queue", queue.queue).
+					WithContext("message_type", messageType)
+			}
+		}
+	}
+	t.mutex.Unlock()
+	if err != nil {
+		_ = unsubscribe(ctx)
+		return nil, err
+	}
+
+	var once sync.Once
+	return func(unsubCtx context.Context) error {
+		var err error
+		once.Do(func() {
+			if err = unsubscribe(unsubCtx); err != nil {
+				return
+			}
+			t.mutex.RLock()
+			unbind := t.running && len(queue.handlers[messageType]) == 0
+			t.mutex.RUnlock()
+			if unbind {
+				if uerr := t.client.UnbindQueue(unsubCtx, queue.queue, t.binding(messageType)); uerr != nil {
+					err = errors.Wrap(uerr, errors.Dependency, "unbind rabbitmq queue failed").
+						WithContext("queue", queue.queue).
+						WithContext("message_type", messageType)
+				}
+			}
+		})
+		return err
+	}, nil
+}
+
+// startGroupLocked 声明消费组队列（绑定已订阅的消息类型）并开始消费；调用方需持有写锁。
+func (t *Transport) startGroupLocked(ctx context.Context, queue *groupQueue) error {
+	declared := Queue{Name: queue.queue, Durable: true, Args: t.config.QueueArgs}
+	for messageType, handlers := range queue.handlers {
+		if len(handlers) > 0 {
+			declared.Bindings = append(declared.Bindings, t.binding(messageType))
+		}
+	}
+	if err := t.declareQueue(ctx, declared); err != nil {
+		return err
+	}
+	if err := t.startConsumerLocked(queue.queue, queue.handlers); err != nil {
+		return err
+	}
+	queue.consuming = true
+	return nil
+}
+
+// Start 声明拓扑、绑定已订阅的消息类型并开始消费。
+func (t *Transport) Start(ctx context.Context) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.running {
+		return errors.NewCode(errors.Conflict, "rabbitmq transport is already running")
+	}
+
+	if err := t.declareTopology(ctx); err != nil {
+		return err
+	}
+
+	// 消费使用独立的内部 ctx，避免 Start(ctx) 的取消导致消费意外退出。
+	consumeCtx, cancel := context.WithCancel(contextx.Background())
+	t.consumeCtx = consumeCtx
+	t.cancel = cancel
+	if t.config.Queue != "" {
+		if err := t.startConsumerLocked(t.config.Queue, t.handlers); err != nil {
+			cancel()
+			return err
+		}
+	}
+	for _, group := range t.groups {
+		if err := t.startGroupLocked(ctx, group); err != nil {
+			cancel()
+			return err
+		}
+	}
+	t.running = true
+	return nil
+}
+
+// declareTopology 依次声明附加拓扑、发布 exchange、消费队列及其绑定；调用方需持有写锁。
+func (t *Transport) declareTopology(ctx context.Context) error {
+	for _, exchange := range t.config.Topology.Exchanges {
+		if exchange.Kind == "" {
+			exchange.Kind = DefaultExchangeKind
+		}
+		if err := t.client.DeclareExchange(ctx, exchange); err != nil {
+			return errors.Wrap(err, errors.Dependency, "declare rabbitmq exchange failed").WithContext("exchange", exchange.Name)
+		}
+	}
+	for _, queue := range t.config.Topology.Queues {
+		if err := t.declareQueue(ctx, queue); err != nil {
+			return err
+		}
+	}
+	if err := t.client.DeclareExchange(ctx, Exchange{Name: t.config.Exchange, Kind: t.config.ExchangeKind, Durable: true}); err != nil {
+		return errors.Wrap(err, errors.Dependency, "declare rabbitmq exchange failed").WithContext("exchange", t.config.Exchange)
+	}
+	if t.config.Queue == "" {
+		return nil
+	}
+	queue := Queue{Name: t.config.Queue, Durable: true, Args: t.config.QueueArgs}
+	for messageType, handlers := range t.handlers {
+		if len(handlers) > 0 {
+			queue.Bindings = append(queue.Bindings, t.binding(messageType))
+		}
+	}
+	return t.declareQueue(ctx, queue)
+}
+
+func (t *Transport) declareQueue(ctx context.Context, queue Queue) error {
+	if err := t.client.DeclareQueue(ctx, queue); err != nil {
+		return errors.Wrap(err, errors.Dependency, "declare rabbitmq queue failed").WithContext("queue", queue.Name)
+	}
+	for _, binding := range queue.Bindings {
+		if err := t.client.BindQueue(ctx, queue.Name, binding); err != nil {
+			return errors.Wrap(err, errors.Dependency, "bind rabbitmq queue failed").
+				WithContext("queue", queue.Name).
+				WithContext("routing_key", binding.RoutingKey)
+		}
+	}
+	return nil
+}
+
+// Stop 停止消费并等待处理中的消息完成；未确认的消息由 broker 重新投递。
+func (t *Transport) Stop(ctx context.Context) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	t.mutex.Lock()
+	if !t.running {
+		t.mutex.Unlock()
+		return messaging.NewTransportAlreadyStoppedError("rabbitmq transport is not running")
+	}
+	t.running = false
+	cancel := t.cancel
+	t.cancel = nil
+	t.consumeCtx = nil
+	for _, group := range t.groups {
+		group.consuming = false
+	}
+	t.mutex.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats 返回运行状态与订阅概览。
+func (t *Transport) Stats() messaging.TransportStats {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	handlerCount := 0
+	messageTypes := make([]string, 0, len(t.handlers))
+	seen := make(map[string]bool, len(t.handlers))
+	count := func(handlers map[string][]messaging.IMessageHandler) {
+		for messageType, hs := range handlers {
+			if !seen[messageType] {
+				seen[messageType] = true
+				messageTypes = append(messageTypes, messageType)
+			}
+			handlerCount += len(hs)
+		}
+	}
+	count(t.handlers)
+	for _, group := range t.groups {
+		count(group.handlers)
+	}
+	return messaging.TransportStats{
+		Running:      t.running,
+		HandlerCount: handlerCount,
+		MessageTypes: messageTypes,
+		WorkerCount:  t.config.Concurrency,
+	}
+}
+
+// IsSynchronous 返回 false，表明该传输是异步的。
+func (t *Transport) IsSynchronous() bool { return false }
+
+func (t *Transport) binding(messageType string) Binding {
+	routingKey := WildcardRoutingKey
+	if messageType != "*" {
+		routingKey = t.config.RoutingKey(messageType)
+	}
+	return Binding{Exchange: t.config.Exchange, RoutingKey: routingKey}
+}
+
+// startConsumerLocked 开始消费队列，投递交给 handlers 中匹配的处理器；调用方需持有写锁且 consumeCtx 有效。
+func (t *Transport) startConsumerLocked(queue string, handlers map[string][]messaging.IMessageHandler) error {
+	deliveries, err := t.client.Consume(t.consumeCtx, queue, t.config.ConsumerTag, t.config.Prefetch)
+	if err != nil {
+		return errors.Wrap(err, errors.Dependency, "start rabbitmq consumer failed").
+			WithContext("queue", queue)
+	}
+	for range t.config.Concurrency {
+		t.wg.Add(1)
+		go t.consume(deliveries, handlers)
+	}
+	return nil
+}
+
+// consume 处理投递直到 channel 关闭。
+func (t *Transport) consume(deliveries <-chan Delivery, handlers map[string][]messaging.IMessageHandler) {
+	defer t.wg.Done()
+	for delivery := range deliveries {
+		t.handleDelivery(delivery, handlers)
+	}
+}
+
+// handleDelivery 解码并分发一条投递：全部处理器成功才 ack，否则 nack。
+func (t *Transport) handleDelivery(delivery Delivery, registry map[string][]messaging.IMessageHandler) {
+	ctx := contextx.Background()
+	message, err := codec.DecodeMessage(delivery.Body)
+	if err != nil {
+		// 无法解码的消息重投也不会成功，直接拒绝（进入死信或丢弃）。
+		t.logger.Error(ctx, "decode rabbitmq delivery failed",
+			logging.String("message_id", delivery.MessageID),
+			logging.String("routing_key", delivery.RoutingKey),
+			logging.Error(err))
+		t.settle(ctx, delivery, false, false)
+		return
+	}
+	if derived, derr := contextx.DeriveFromMetadata(ctx, message.GetMetadata()); derr == nil && derived != nil {
+		ctx = derived
+	}
+
+	t.mutex.RLock()
+	handlers := append(append([]messaging.IMessageHandler(nil), registry[message.GetType()]...), registry["*"]...)
+	t.mutex.RUnlock()
+
+	failed := false
+	for _, handler := range handlers {
+		if err := t.invoke(ctx, handler, message); err != nil {
+			failed = true
+			t.logger.Warn(ctx, "message handler failed",
+				logging.String("message_type", message.GetType()),
+				logging.String("message_id", message.GetID()),
+				logging.String("handler", handler.Type()),
+				logging.Bool("redelivered", delivery.Redelivered),
+				logging.Error(err))
+		}
+	}
+	t.settle(ctx, delivery, !failed, t.config.RequeueOnError)
+}
+
+func (t *Transport) invoke(ctx context.Context, handler messaging.IMessageHandler, message messaging.IMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.logger.Error(ctx, "message handler panicked",
+				logging.String("message_type", message.GetType()),
+				logging.String("message_id", message.GetID()),
+				logging.String("handler", handler.Type()),
+				logging.String("panic", fmt.Sprint(r)),
+				logging.String("stack", string(debug.Stack())))
+			err = errors.NewCode(errors.Internal, "message handler panicked").WithContext("panic", fmt.Sprint(r))
+		}
+	}()
+	return handler.Handle(ctx, message)
+}
+
+func (t *Transport) settle(ctx context.Context, delivery Delivery, ack, requeue bool) {
+	var err error
+	if ack {
+		if delivery.Ack != nil {
+			err = delivery.Ack()
+		}
+	} else if delivery.Nack != nil {
+		err = delivery.Nack(requeue)
+	}
+	if err != nil {
+		t.logger.Warn(ctx, "settle rabbitmq delivery failed",
+			logging.String("message_id", delivery.MessageID),
+			logging.Bool("ack", ack),
+			logging.Error(err))
+	}
+}
@@ -0,0 +1,328 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/messaging/command"
+)
+
+type settlement struct {
+	id      string
+	ack     bool
+	requeue bool
+}
+
+// fakeBroker 在内存中模拟 topic exchange 路由（仅支持精确匹配与 "#"）。
+type fakeBroker struct {
+	mu         sync.Mutex
+	exchanges  []string
+	queues     map[string]chan Delivery
+	bindings   map[string][]Binding
+	settled    []settlement
+	publishErr error
+	prefetch   int
+}
+
+func newFakeBroker() *fakeBroker {
+	return &fakeBroker{queues: map[string]chan Delivery{}, bindings: map[string][]Binding{}}
+}
+
+func (b *fakeBroker) DeclareExchange(_ context.Context, exchange Exchange) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.exchanges = append(b.exchanges, exchange.Kind+":"+exchange.Name)
+	return nil
+}
+
+func (b *fakeBroker) DeclareQueue(_ context.Context, queue Queue) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.queues[queue.Name]; !ok {
+		b.queues[queue.Name] = make(chan Delivery, 16)
+	}
+	return nil
+}
+
+func (b *fakeBroker) BindQueue(_ context.Context, queue string, binding Binding) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.bindings[queue] = append(b.bindings[queue], binding)
+	return nil
+}
+
+func (b *fakeBroker) UnbindQueue(_ context.Context, queue string, binding Binding) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	current := b.bindings[queue]
+	for i, existing := range current {
+		if existing == binding {
+			b.bindings[queue] = append(current[:i], current[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (b *fakeBroker) Publish(_ context.Context, publishing Publishing, _ bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.publishErr != nil {
+		return b.publishErr
+	}
+	for queue, bindings := range b.bindings {
+		for _, binding := range bindings {
+			if binding.Exchange == publishing.Exchange && (binding.RoutingKey == "#" || binding.RoutingKey == publishing.RoutingKey) {
+				b.queues[queue] <- b.delivery(publishing)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (b *fakeBroker) delivery(publishing Publishing) Delivery {
+	id := publishing.MessageID
+	return Delivery{
+		MessageID:  id,
+		RoutingKey: publishing.RoutingKey,
+		Body:       publishing.Body,
+		Ack: func() error {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.settled = append(b.settled, settlement{id: id, ack: true})
+			return nil
+		},
+		Nack: func(requeue bool) error {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.settled = append(b.settled, settlement{id: id, requeue: requeue})
+			return nil
+		},
+	}
+}
+
+func (b *fakeBroker) Consume(ctx context.Context, queue, _ string, prefetch int) (<-chan Delivery, error) {
+	b.mu.Lock()
+	source := b.queues[queue]
+	b.prefetch = prefetch
+	b.mu.Unlock()
+	out := make(chan Delivery)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case delivery := <-source:
+				select {
+				case out <- delivery:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (b *fakeBroker) settlements() []settlement {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]settlement(nil), b.settled...)
+}
+
+type recordingHandler struct {
+	mu       sync.Mutex
+	received []messaging.IMessage
+	err      error
+}
+
+func (h *recordingHandler) Handle(_ context.Context, message messaging.IMessage) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.received = append(h.received, message)
+	return h.err
+}
+
+func (h *recordingHandler) Type() string { return "recording" }
+
+func newTestTransport(t *testing.T, broker *fakeBroker, cfg Config) *Transport {
+	t.Helper()
+	cfg.Logger = logging.NewNoopLogger()
+	transport, err := NewTransport(broker, &cfg)
+	require.NoError(t, err)
+	return transport
+}
+
+// TestTransport_TopologyPublishAndConsume 验证拓扑声明、按消息类型绑定、命令往返与 ack。
+func TestTransport_TopologyPublishAndConsume(t *testing.T) {
+	ctx := context.Background()
+	broker := newFakeBroker()
+	transport := newTestTransport(t, broker, Config{
+		Queue:      "orders-service",
+		RoutingKey: func(messageType string) string { return "orders." + messageType },
+		Prefetch:   8,
+		Topology: Topology{
+			Exchanges: []Exchange{{Name: "gochen.dlx", Kind: "fanout", Durable: true}},
+			Queues:    []Queue{{Name: "gochen.dead-letters", Durable: true, Bindings: []Binding{{Exchange: "gochen.dlx"}}}},
+		},
+	})
+	handler := &recordingHandler{}
+	_, err := transport.Subscribe(ctx, "PlaceOrder", handler)
+	require.NoError(t, err)
+	require.NoError(t, transport.Start(ctx))
+	t.Cleanup(func() { _ = transport.Stop(context.Background()) })
+
+	require.Equal(t, []string{"fanout:gochen.dlx", "topic:" + DefaultExchange}, broker.exchanges)
+	require.Equal(t, []Binding{{Exchange: DefaultExchange, RoutingKey: "orders.PlaceOrder"}}, broker.bindings["orders-service"])
+	require.Equal(t, 8, broker.prefetch)
+
+	cmd := command.NewCommand("c1", "PlaceOrder", "order-42", "Order", map[string]any{"qty": 2})
+	cmd.SetMetadata("tenant_id", "t1")
+	require.NoError(t, transport.Publish(ctx, cmd))
+	require.NoError(t, transport.Publish(ctx, messaging.NewMessage("m2", messaging.KindEvent, "Unrouted", nil)))
+
+	require.Eventually(t, func() bool { return len(broker.settlements()) == 1 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, []settlement{{id: "c1", ack: true}}, broker.settlements())
+	received, ok := handler.received[0].(*command.Command)
+	require.True(t, ok)
+	require.Equal(t, "order-42", received.AggregateID)
+	tenantID, _ := received.GetMetadata().Get("tenant_id")
+	require.Equal(t, "t1", tenantID)
+}
+
+// TestTransport_HandlerFailureAndUnsubscribe 验证处理失败 nack（默认不重新入队）、通配订阅与退订解绑。
+func TestTransport_HandlerFailureAndUnsubscribe(t *testing.T) {
+	ctx := context.Background()
+	broker := newFakeBroker()
+	transport := newTestTransport(t, broker, Config{Queue: "audit"})
+	require.NoError(t, transport.Start(ctx))
+	t.Cleanup(func() { _ = transport.Stop(context.Background()) })
+
+	failing := &recordingHandler{err: errors.NewCode(errors.Database, "db down")}
+	unsubscribe, err := transport.Subscribe(ctx, "*", failing)
+	require.NoError(t, err)
+	require.Equal(t, []Binding{{Exchange: DefaultExchange, RoutingKey: WildcardRoutingKey}}, broker.bindings["audit"])
+
+	require.NoError(t, transport.Publish(ctx, messaging.NewMessage("m1", messaging.KindEvent, "OrderPlaced", nil)))
+	require.Eventually(t, func() bool { return len(broker.settlements()) == 1 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, []settlement{{id: "m1"}}, broker.settlements())
+
+	require.NoError(t, unsubscribe(ctx))
+	require.Empty(t, broker.bindings["audit"])
+	require.Equal(t, 0, transport.Stats().HandlerCount)
+}
+
+// TestTransport_Errors 验证未启动、无消费队列订阅与发布失败的错误语义。
+func TestTransport_Errors(t *testing.T) {
+	ctx := context.Background()
+	broker := newFakeBroker()
+	transport := newTestTransport(t, broker, Config{PublisherConfirms: true})
+	msg := messaging.NewMessage("m1", messaging.KindEvent, "OrderPlaced", nil)
+
+	require.True(t, errors.Is(transport.Publish(ctx, msg), errors.Conflict))
+	_, err := transport.Subscribe(ctx, "OrderPlaced", &recordingHandler{})
+	require.True(t, errors.Is(err, errors.InvalidInput))
+
+	require.NoError(t, transport.Start(ctx))
+	broker.publishErr = errors.New("nack from broker")
+	err = transport.Publish(ctx, msg)
+	require.True(t, errors.Is(err, errors.Dependency))
+	require.True(t, strings.Contains(err.Error(), "nack from broker"))
+
+	require.NoError(t, transport.Stop(ctx))
+	require.True(t, messaging.TransportAlreadyStopped(transport.Stop(ctx)))
+}
+
+type replyingHandler struct {
+	bus *messaging.MessageBus
+}
+
+func (h *replyingHandler) Handle(ctx context.Context, message messaging.IMessage) error {
+	reply, err := messaging.NewReply(message, "reply-"+message.GetID(), map[string]any{"status": "paid"})
+	if err != nil {
+		return err
+	}
+	return h.bus.Publish(ctx, reply)
+}
+
+func (h *replyingHandler) Type() string { return "replying" }
+
+// TestTransport_RequestReplyOverInstanceQueue 验证应答主题绑定到请求方实例独占的队列，应答按 routing key 路由回请求方。
+func TestTransport_RequestReplyOverInstanceQueue(t *testing.T) {
+	ctx := context.Background()
+	broker := newFakeBroker()
+	requester := newTestTransport(t, broker, Config{Queue: "web-1"})
+	replier := newTestTransport(t, broker, Config{Queue: "orders-service"})
+	for _, transport := range []*Transport{requester, replier} {
+		require.NoError(t, transport.Start(ctx))
+		t.Cleanup(func() { _ = transport.Stop(context.Background()) })
+	}
+
+	replierBus := messaging.NewMessageBus(replier)
+	_, err := replierBus.Subscribe(ctx, "GetOrderStatus", &replyingHandler{bus: replierBus})
+	require.NoError(t, err)
+	requesterBus := messaging.NewMessageBus(requester)
+	require.NoError(t, requesterBus.SetReplyTopic("replies.web-1"))
+
+	reply, err := requesterBus.Request(ctx, messaging.NewMessage("q1", messaging.KindQuery, "GetOrderStatus", nil), time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "reply-q1", reply.GetID())
+	require.Contains(t, broker.bindings["web-1"], Binding{Exchange: DefaultExchange, RoutingKey: "replies.web-1"})
+}
+
+func (h *recordingHandler) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.received)
+}
+
+// TestTransport_ConsumerGroups 验证同组实例共享组队列（每条消息只处理一次），不同组各自收到一份。
+func TestTransport_ConsumerGroups(t *testing.T) {
+	ctx := context.Background()
+	broker := newFakeBroker()
+	publisher := newTestTransport(t, broker, Config{})
+	instances := []*recordingHandler{{}, {}}
+	audit := &recordingHandler{}
+	subscribe := func(group string, handler *recordingHandler, start bool) {
+		transport := newTestTransport(t, broker, Config{})
+		if start {
+			require.NoError(t, transport.Start(ctx))
+		}
+		_, err := transport.SubscribeGroup(ctx, "OrderPlaced", group, handler)
+		require.NoError(t, err)
+		if !start {
+			require.NoError(t, transport.Start(ctx))
+		}
+		t.Cleanup(func() { _ = transport.Stop(context.Background()) })
+	}
+	// 分别覆盖“先订阅后启动”和“运行中订阅”两条声明路径。
+	subscribe("projections", instances[0], false)
+	subscribe("projections", instances[1], true)
+	subscribe("audit", audit, true)
+	require.NoError(t, publisher.Start(ctx))
+	t.Cleanup(func() { _ = publisher.Stop(context.Background()) })
+
+	for i := range 6 {
+		require.NoError(t, publisher.Publish(ctx, messaging.NewMessage(fmt.Sprintf("e%d", i), messaging.KindEvent, "OrderPlaced", nil)))
+	}
+	require.Eventually(t, func() bool {
+		return instances[0].count()+instances[1].count() == 6 && audit.count() == 6
+	}, time.Second, 5*time.Millisecond)
+
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	binding := Binding{Exchange: DefaultExchange, RoutingKey: "OrderPlaced"}
+	require.Equal(t, []Binding{binding, binding}, broker.bindings[DefaultGroupQueue("projections")])
+	require.Equal(t, []Binding{binding}, broker.bindings[DefaultGroupQueue("audit")])
+}
@@ -156,3 +156,41 @@ func (b *Breaker) Call(fn func() error) error {
 	b.state = StateClosed
 	return nil
 }
+
+// String 返回状态名称（closed/open/half_open）。
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+// State 返回熔断器当前状态。
+//
+// 说明：Open -> HalfOpen 的转换发生在下一次 Call 时，ResetTimeout 到期后未有调用仍报告 Open。
+func (b *Breaker) State() State {
+	if b == nil {
+		return StateClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Reset 手动把熔断器恢复为 Closed 并清零失败计数（例如下游修复后由运维触发）。
+func (b *Breaker) Reset() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = StateClosed
+	b.failures = 0
+	b.halfOpenBusy = false
+}
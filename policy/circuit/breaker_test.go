@@ -78,3 +78,26 @@ func TestBreaker_HalfOpen_AllowsOnlyOneProbe(t *testing.T) {
 		}
 	}
 }
+
+func TestBreaker_StateAndReset(t *testing.T) {
+	b := New(Config{MaxFailures: 2, ResetTimeout: time.Hour})
+	if b.State() != StateClosed {
+		t.Fatalf("expected closed, got %s", b.State())
+	}
+	_ = b.Call(func() error { return errors.New("boom") })
+	_ = b.Call(func() error { return errors.New("boom") })
+	if b.State() != StateOpen {
+		t.Fatalf("expected open, got %s", b.State())
+	}
+	if err := b.Call(func() error { return nil }); !errors.Is(err, errors.ServiceUnavailable) {
+		t.Fatalf("expected open breaker to reject, got %v", err)
+	}
+
+	b.Reset()
+	if b.State() != StateClosed {
+		t.Fatalf("expected closed after reset, got %s", b.State())
+	}
+	if err := b.Call(func() error { return nil }); err != nil {
+		t.Fatalf("expected call to pass after reset, got %v", err)
+	}
+}
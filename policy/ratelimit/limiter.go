@@ -1,12 +1,19 @@
 package ratelimit
 
 import (
+	"context"
 	"sync"
 	"time"
 
 	"gochen/clock"
 )
 
+// ILimiter 抽象按 key 的限流判定，供 HTTP/消息中间件在本地令牌桶与分布式计数器（如 policy/ratelimit/redis）之间切换。
+type ILimiter interface {
+	// Take 尝试为 key 取走一个令牌，返回是否放行；err 非 nil 表示限流后端不可用。
+	Take(ctx context.Context, key string) (bool, error)
+}
+
 // Config 定义限流器配置。
 type Config struct {
 	// RequestsPerSecond 每秒允许请求数（<=0 表示不限流）。
@@ -148,3 +155,10 @@ func (l *Limiter) Allow(key string) bool {
 	}
 	return bucket.allow()
 }
+
+// Take 实现 ILimiter（本地令牌桶不会返回错误）。
+func (l *Limiter) Take(_ context.Context, key string) (bool, error) {
+	return l.Allow(key), nil
+}
+
+var _ ILimiter = (*Limiter)(nil)
@@ -0,0 +1,85 @@
+package redis
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/clock"
+	"gochen/errors"
+)
+
+type bucketState struct {
+	tokens float64
+	last   time.Time
+}
+
+// fakeClient 在内存中模拟 README 中 Lua 脚本的令牌桶语义。
+type fakeClient struct {
+	mu      sync.Mutex
+	buckets map[string]*bucketState
+	keys    []string
+	err     error
+}
+
+func (c *fakeClient) TakeToken(_ context.Context, key string, rate float64, burst int, now time.Time, _ time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return false, c.err
+	}
+	c.keys = append(c.keys, key)
+	state, ok := c.buckets[key]
+	if !ok {
+		state = &bucketState{tokens: float64(burst), last: now}
+		c.buckets[key] = state
+	}
+	state.tokens = math.Min(float64(burst), state.tokens+now.Sub(state.last).Seconds()*rate)
+	state.last = now
+	if state.tokens < 1 {
+		return false, nil
+	}
+	state.tokens--
+	return true, nil
+}
+
+// TestLimiter_BurstAndRefill 验证突发容量、持续速率与键前缀。
+func TestLimiter_BurstAndRefill(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewManualClock(time.Unix(0, 0).UTC())
+	client := &fakeClient{buckets: map[string]*bucketState{}}
+	limiter, err := NewLimiter(client, &Config{RequestsPerSecond: 0.5, BurstSize: 2, Clock: clk})
+	require.NoError(t, err)
+
+	for range 2 {
+		allowed, err := limiter.Take(ctx, "tenant-a")
+		require.NoError(t, err)
+		require.True(t, allowed)
+	}
+	allowed, err := limiter.Take(ctx, "tenant-a")
+	require.NoError(t, err)
+	require.False(t, allowed)
+
+	clk.Advance(2 * time.Second)
+	allowed, err = limiter.Take(ctx, "tenant-a")
+	require.NoError(t, err)
+	require.True(t, allowed)
+	require.Equal(t, DefaultKeyPrefix+"tenant-a", client.keys[0])
+}
+
+// TestLimiter_Errors 验证配置校验与后端错误映射。
+func TestLimiter_Errors(t *testing.T) {
+	_, err := NewLimiter(nil, &Config{RequestsPerSecond: 1})
+	require.True(t, errors.Is(err, errors.InvalidInput))
+	_, err = NewLimiter(&fakeClient{}, &Config{})
+	require.True(t, errors.Is(err, errors.InvalidInput))
+
+	limiter, err := NewLimiter(&fakeClient{err: errors.New("connection refused")}, &Config{RequestsPerSecond: 1})
+	require.NoError(t, err)
+	_, err = limiter.Take(context.Background(), "k")
+	require.True(t, errors.Is(err, errors.Dependency))
+}
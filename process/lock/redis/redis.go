@@ -0,0 +1,204 @@
+// Package redis 提供基于 Redis 的分布式锁（lock.ILockProvider）。
+//
+// 获取锁使用 `SET key token NX PX ttl`；持有期间后台按 RenewInterval 续期，
+// 续期与释放都校验 token，避免误删/误续其他实例在锁过期后重新获取的锁。
+// 框架核心不依赖 Redis 客户端，业务侧实现 IClient 即可（见 README）。
+package redis
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/ident/uuid"
+	"gochen/logging"
+	plock "gochen/process/lock"
+)
+
+const (
+	// DefaultKeyPrefix 是锁键的默认前缀。
+	DefaultKeyPrefix = "gochen:lock:"
+	// DefaultTTL 是锁的默认过期时间；持有期间会自动续期。
+	DefaultTTL = 30 * time.Second
+	// DefaultPollInterval 是未获取到锁时的默认轮询间隔。
+	DefaultPollInterval = 50 * time.Millisecond
+
+	releaseTimeout = 5 * time.Second
+)
+
+// IClient 是锁所需的最小 Redis 客户端能力。
+//
+// CompareAndExpire / CompareAndDelete 需以 Lua 脚本原子执行“值匹配才操作”。
+type IClient interface {
+	// SetNX 仅在键不存在时写入并设置过期时间，写入成功返回 true。
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	// CompareAndExpire 仅当键的值等于 value 时刷新过期时间，刷新成功返回 true。
+	CompareAndExpire(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	// CompareAndDelete 仅当键的值等于 value 时删除键，删除成功返回 true。
+	CompareAndDelete(ctx context.Context, key, value string) (bool, error)
+}
+
+// Config 定义 Redis 锁配置。
+type Config struct {
+	// KeyPrefix 为空时使用 DefaultKeyPrefix。
+	KeyPrefix string
+
+	// Owner 当前实例标识（必填），写入锁值便于排查持有者。
+	Owner string
+
+	// TTL 为 0 时使用 DefaultTTL。实例崩溃后锁最多保留 TTL。
+	TTL time.Duration
+
+	// RenewInterval 为续期间隔；为 0 时使用 TTL/3，小于 0 表示不续期。
+	RenewInterval time.Duration
+
+	// PollInterval 为 0 时使用 DefaultPollInterval。
+	PollInterval time.Duration
+
+	Logger logging.ILogger
+}
+
+// LockProvider 是基于 Redis 的分布式锁。
+type LockProvider struct {
+	client IClient
+	config Config
+	logger logging.ILogger
+}
+
+// NewLockProvider 创建 Redis 锁提供者。
+func NewLockProvider(client IClient, cfg *Config) (*LockProvider, error) {
+	if client == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "redis client cannot be nil")
+	}
+	config := Config{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if strings.TrimSpace(config.Owner) == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "owner cannot be empty")
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = DefaultKeyPrefix
+	}
+	if config.TTL <= 0 {
+		config.TTL = DefaultTTL
+	}
+	if config.RenewInterval == 0 {
+		config.RenewInterval = config.TTL / 3
+	}
+	if config.RenewInterval >= config.TTL {
+		return nil, errors.NewCode(errors.InvalidInput, "renew interval must be shorter than ttl").
+			WithContext("ttl", config.TTL).
+			WithContext("renew_interval", config.RenewInterval)
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+	logger := config.Logger
+	if logger == nil {
+		logger = logging.ComponentLogger("process.lock.redis")
+	}
+	return &LockProvider{client: client, config: config, logger: logger}, nil
+}
+
+// Acquire 阻塞获取锁，直到成功或 ctx 结束（超时映射为 errors.Timeout）。
+func (p *LockProvider) Acquire(ctx context.Context, key string) (func(), error) {
+	if key == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "lock key cannot be empty")
+	}
+	if ctx == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	id, err := uuid.New()
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "generate lock token failed")
+	}
+	// 每次获取使用独立 token：同一实例内的并发获取也互斥。
+	token := p.config.Owner + ":" + id
+	redisKey := p.config.KeyPrefix + key
+
+	start := time.Now()
+	timer := time.NewTimer(0)
+	<-timer.C
+	defer timer.Stop()
+	for {
+		ok, err := p.client.SetNX(ctx, redisKey, token, p.config.TTL)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.Dependency, "acquire redis lock failed").WithContext("key", key)
+		}
+		if ok {
+			return p.hold(redisKey, token), nil
+		}
+		timer.Reset(p.config.PollInterval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, errors.NewCode(errors.Timeout, "lock acquire timeout").
+					WithContext("key", key).
+					WithContext("ms", time.Since(start).Milliseconds())
+			}
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// hold 启动续期并返回幂等的释放函数。
+func (p *LockProvider) hold(redisKey, token string) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	if p.config.RenewInterval > 0 {
+		go p.renew(redisKey, token, stop, done)
+	} else {
+		close(done)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-done
+			ctx, cancel := context.WithTimeout(contextx.Background(), releaseTimeout)
+			defer cancel()
+			deleted, err := p.client.CompareAndDelete(ctx, redisKey, token)
+			if err != nil {
+				p.logger.Warn(ctx, "lock release failed", logging.Error(err), logging.String("key", redisKey))
+				return
+			}
+			if !deleted {
+				p.logger.Warn(ctx, "lock release had no effect (lock expired or taken over)", logging.String("key", redisKey))
+			}
+		})
+	}
+}
+
+// renew 按 RenewInterval 刷新过期时间；锁已丢失时停止续期并告警。
+func (p *LockProvider) renew(redisKey, token string, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(p.config.RenewInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(contextx.Background(), p.config.RenewInterval)
+			renewed, err := p.client.CompareAndExpire(ctx, redisKey, token, p.config.TTL)
+			cancel()
+			if err != nil {
+				// 暂时性错误：下一周期重试，锁在 TTL 内仍有效。
+				p.logger.Warn(contextx.Background(), "lock renewal failed", logging.Error(err), logging.String("key", redisKey))
+				continue
+			}
+			if !renewed {
+				p.logger.Error(contextx.Background(), "lock lost before release", logging.String("key", redisKey))
+				return
+			}
+		}
+	}
+}
+
+var _ plock.ILockProvider = (*LockProvider)(nil)
@@ -0,0 +1,145 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"gochen/errors"
+)
+
+// memoryClient 是带过期时间的内存 IClient，实现与 Lua 脚本等价的原子语义。
+type memoryClient struct {
+	mu      sync.Mutex
+	values  map[string]string
+	expires map[string]time.Time
+	renews  int
+}
+
+func newMemoryClient() *memoryClient {
+	return &memoryClient{values: make(map[string]string), expires: make(map[string]time.Time)}
+}
+
+func (c *memoryClient) live(key string) (string, bool) {
+	value, ok := c.values[key]
+	if ok && time.Now().After(c.expires[key]) {
+		delete(c.values, key)
+		delete(c.expires, key)
+		return "", false
+	}
+	return value, ok
+}
+
+func (c *memoryClient) SetNX(_ context.Context, key, value string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.live(key); ok {
+		return false, nil
+	}
+	c.values[key] = value
+	c.expires[key] = time.Now().Add(ttl)
+	return true, nil
+}
+
+func (c *memoryClient) CompareAndExpire(_ context.Context, key, value string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if current, ok := c.live(key); !ok || current != value {
+		return false, nil
+	}
+	c.expires[key] = time.Now().Add(ttl)
+	c.renews++
+	return true, nil
+}
+
+func (c *memoryClient) CompareAndDelete(_ context.Context, key, value string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if current, ok := c.live(key); !ok || current != value {
+		return false, nil
+	}
+	delete(c.values, key)
+	delete(c.expires, key)
+	return true, nil
+}
+
+func TestNewLockProvider_Validation(t *testing.T) {
+	if _, err := NewLockProvider(nil, &Config{Owner: "a"}); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected InvalidInput for nil client, got %v", err)
+	}
+	if _, err := NewLockProvider(newMemoryClient(), nil); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected InvalidInput for empty owner, got %v", err)
+	}
+	if _, err := NewLockProvider(newMemoryClient(), &Config{Owner: "a", TTL: time.Second, RenewInterval: time.Second}); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("expected InvalidInput for renew interval >= ttl, got %v", err)
+	}
+}
+
+// TestLockProvider_MutualExclusionAndRenewal 验证锁互斥、持有期间续期不过期、释放后可再次获取。
+func TestLockProvider_MutualExclusionAndRenewal(t *testing.T) {
+	client := newMemoryClient()
+	p1, err := NewLockProvider(client, &Config{Owner: "node-1", TTL: 60 * time.Millisecond, PollInterval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewLockProvider: %v", err)
+	}
+	p2, err := NewLockProvider(client, &Config{Owner: "node-2", TTL: 60 * time.Millisecond, PollInterval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewLockProvider: %v", err)
+	}
+
+	release, err := p1.Acquire(context.Background(), "saga-1")
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+
+	// 持有时间超过 TTL，续期保证其他实例仍无法获取。
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	if _, err := p2.Acquire(ctx, "saga-1"); !errors.Is(err, errors.Timeout) {
+		t.Fatalf("expected Timeout while lock is held, got %v", err)
+	}
+	client.mu.Lock()
+	renews := client.renews
+	client.mu.Unlock()
+	if renews == 0 {
+		t.Fatalf("expected lock to be renewed")
+	}
+
+	release()
+	release() // 幂等
+
+	release2, err := p2.Acquire(context.Background(), "saga-1")
+	if err != nil {
+		t.Fatalf("Acquire after release: %v", err)
+	}
+	release2()
+}
+
+// TestLockProvider_ReleaseDoesNotDeleteForeignLock 验证锁过期被他人获取后，原持有者释放不会删除新锁。
+func TestLockProvider_ReleaseDoesNotDeleteForeignLock(t *testing.T) {
+	client := newMemoryClient()
+	p1, err := NewLockProvider(client, &Config{Owner: "node-1", TTL: 20 * time.Millisecond, RenewInterval: -1})
+	if err != nil {
+		t.Fatalf("NewLockProvider: %v", err)
+	}
+	p2, err := NewLockProvider(client, &Config{Owner: "node-2", TTL: time.Minute, PollInterval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewLockProvider: %v", err)
+	}
+
+	release1, err := p1.Acquire(context.Background(), "k")
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	release2, err := p2.Acquire(context.Background(), "k")
+	if err != nil {
+		t.Fatalf("Acquire after expiry: %v", err)
+	}
+	defer release2()
+
+	release1()
+	if ok, _ := client.SetNX(context.Background(), DefaultKeyPrefix+"k", "x", time.Minute); ok {
+		t.Fatalf("stale release must not delete the new holder's lock")
+	}
+}
@@ -0,0 +1,180 @@
+package sql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"gochen/contextx"
+	"gochen/db"
+	"gochen/db/dialect"
+	gerrors "gochen/errors"
+	"gochen/logging"
+	plock "gochen/process/lock"
+)
+
+// mysqlLockNameMaxLen 是 MySQL GET_LOCK 锁名的最大长度。
+const mysqlLockNameMaxLen = 64
+
+// AdvisoryLockProviderConfig 数据库咨询锁配置。
+type AdvisoryLockProviderConfig struct {
+	// KeyPrefix 锁键前缀（默认：gochen:），用于与其他使用咨询锁的应用隔离。
+	KeyPrefix string
+
+	// PollInterval 未获取到锁时的轮询等待时间（默认：50ms）。
+	PollInterval time.Duration
+
+	Logger logging.ILogger
+}
+
+// AdvisoryLockProvider 基于数据库原生咨询锁的分布式锁实现（PostgreSQL / MySQL）。
+//
+// 与 SQLLockProvider（锁表 + TTL）相比：
+//   - 不需要锁表，也没有 TTL：锁绑定在持有它的数据库会话上，进程崩溃或连接断开时由数据库自动释放；
+//   - 每个持有中的锁独占一个连接（通过事务固定连接），连接池大小需覆盖最大并发持锁数。
+//
+// 方言实现：
+//   - PostgreSQL：`pg_try_advisory_xact_lock(bigint)`，键经 SHA-256 取前 8 字节映射为 bigint，事务结束即释放；
+//   - MySQL：`GET_LOCK(name, 0)` / `RELEASE_LOCK(name)`，超过 64 字符的键名以哈希替代。
+type AdvisoryLockProvider struct {
+	db           db.IDatabase
+	dialect      dialect.Name
+	keyPrefix    string
+	pollInterval time.Duration
+	logger       logging.ILogger
+}
+
+// NewAdvisoryLockProvider 创建数据库咨询锁提供者；仅支持 PostgreSQL 与 MySQL。
+func NewAdvisoryLockProvider(database db.IDatabase, cfg *AdvisoryLockProviderConfig) (*AdvisoryLockProvider, error) {
+	if database == nil {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "database cannot be nil")
+	}
+	name := dialect.FromDatabase(database).Name()
+	if name != dialect.NamePostgres && name != dialect.NameMySQL {
+		return nil, gerrors.NewCode(gerrors.Unsupported, "advisory locks require postgres or mysql").
+			WithContext("dialect", string(name))
+	}
+	config := AdvisoryLockProviderConfig{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = "gochen:"
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 50 * time.Millisecond
+	}
+	if config.Logger == nil {
+		config.Logger = logging.ComponentLogger("process.lock.sql.advisory").WithField("dialect", string(name))
+	}
+	return &AdvisoryLockProvider{
+		db:           database,
+		dialect:      name,
+		keyPrefix:    config.KeyPrefix,
+		pollInterval: config.PollInterval,
+		logger:       config.Logger,
+	}, nil
+}
+
+func (p *AdvisoryLockProvider) Acquire(ctx context.Context, key string) (func(), error) {
+	if key == "" {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "lock key cannot be empty")
+	}
+	if ctx == nil {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "ctx is nil")
+	}
+	name := p.keyPrefix + key
+
+	start := time.Now()
+	pollTimer := time.NewTimer(0)
+	if !pollTimer.Stop() {
+		<-pollTimer.C
+	}
+	defer pollTimer.Stop()
+
+	for {
+		release, err := p.tryAcquire(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if release != nil {
+			return release, nil
+		}
+
+		pollTimer.Reset(p.pollInterval)
+		select {
+		case <-pollTimer.C:
+		case <-ctx.Done():
+			if gerrors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, gerrors.NewCode(gerrors.Timeout, "lock acquire timeout").WithContext("key", key).WithContext("ms", time.Since(start).Milliseconds())
+			}
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// tryAcquire 尝试一次获取；未获取到时返回 (nil, nil)。
+func (p *AdvisoryLockProvider) tryAcquire(ctx context.Context, name string) (func(), error) {
+	// 锁生命周期独立于获取时的请求上下文，事务以后台上下文开启，释放时结束。
+	tx, err := p.db.BeginTx(contextx.Background(), nil)
+	if err != nil {
+		return nil, gerrors.NewCodeWithCause(gerrors.Database, "begin advisory lock session failed", err)
+	}
+
+	var acquired bool
+	switch p.dialect {
+	case dialect.NamePostgres:
+		err = tx.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock(?)", advisoryLockID(name)).Scan(&acquired)
+	default:
+		var result *int64
+		err = tx.QueryRow(ctx, "SELECT GET_LOCK(?, 0)", mysqlLockName(name)).Scan(&result)
+		acquired = result != nil && *result == 1
+	}
+	if err != nil || !acquired {
+		_ = tx.Rollback()
+		if err != nil {
+			return nil, gerrors.NewCodeWithCause(gerrors.Database, "acquire advisory lock failed", err).WithContext("key", name)
+		}
+		return nil, nil
+	}
+	return p.releaseFunc(tx, name), nil
+}
+
+func (p *AdvisoryLockProvider) releaseFunc(tx db.ITransaction, name string) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ctx, cancel := context.WithTimeout(contextx.Background(), 5*time.Second)
+			defer cancel()
+			if p.dialect == dialect.NameMySQL {
+				// GET_LOCK 绑定会话而非事务，归还连接前必须显式释放。
+				if _, err := tx.Exec(ctx, "SELECT RELEASE_LOCK(?)", mysqlLockName(name)); err != nil {
+					p.logger.Warn(ctx, "advisory lock release failed", logging.Error(err), logging.String("key", name))
+				}
+			}
+			if err := tx.Rollback(); err != nil {
+				p.logger.Warn(ctx, "advisory lock session close failed", logging.Error(err), logging.String("key", name))
+			}
+		})
+	}
+}
+
+// advisoryLockID 把锁名映射为 PostgreSQL 咨询锁使用的 bigint。
+func advisoryLockID(name string) int64 {
+	sum := sha256.Sum256([]byte(name))
+	return int64(binary.BigEndian.Uint64(sum[:8]))
+}
+
+// mysqlLockName 返回不超过 MySQL 长度限制的锁名。
+func mysqlLockName(name string) string {
+	if len(name) <= mysqlLockNameMaxLen {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:mysqlLockNameMaxLen/2])
+}
+
+var _ plock.ILockProvider = (*AdvisoryLockProvider)(nil)
@@ -0,0 +1,115 @@
+package sql
+
+import (
+	"context"
+	stdsql "database/sql"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gochen/db"
+	gerrors "gochen/errors"
+)
+
+// advisoryFakeDB 模拟 PostgreSQL 咨询锁语义：同一锁 ID 只能被一个未结束的事务持有。
+type advisoryFakeDB struct {
+	db.IDatabase
+	mu      sync.Mutex
+	held    map[int64]*advisoryFakeTx
+	queries []string
+}
+
+func (d *advisoryFakeDB) DialectName() string { return "postgres" }
+
+func (d *advisoryFakeDB) BeginTx(context.Context, *stdsql.TxOptions) (db.ITransaction, error) {
+	return &advisoryFakeTx{db: d}, nil
+}
+
+type advisoryFakeTx struct {
+	db.ITransaction
+	db *advisoryFakeDB
+}
+
+func (t *advisoryFakeTx) QueryRow(_ context.Context, query string, args ...any) db.IRow {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+	t.db.queries = append(t.db.queries, query)
+	id := args[0].(int64)
+	if holder, ok := t.db.held[id]; ok && holder != t {
+		return boolRow(false)
+	}
+	t.db.held[id] = t
+	return boolRow(true)
+}
+
+func (t *advisoryFakeTx) Rollback() error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+	for id, holder := range t.db.held {
+		if holder == t {
+			delete(t.db.held, id)
+		}
+	}
+	return nil
+}
+
+type boolRow bool
+
+func (r boolRow) Scan(dest ...any) error {
+	*dest[0].(*bool) = bool(r)
+	return nil
+}
+
+func (r boolRow) Err() error { return nil }
+
+func TestNewAdvisoryLockProvider_RequiresSupportedDialect(t *testing.T) {
+	_, err := NewAdvisoryLockProvider(setupTestDB(t), nil)
+	if !gerrors.Is(err, gerrors.Unsupported) {
+		t.Fatalf("expected Unsupported for sqlite, got %v", err)
+	}
+}
+
+// TestAdvisoryLockProvider_Postgres 验证锁互斥、释放后可再次获取，且锁在事务（会话）结束时释放。
+func TestAdvisoryLockProvider_Postgres(t *testing.T) {
+	fake := &advisoryFakeDB{held: make(map[int64]*advisoryFakeTx)}
+	p, err := NewAdvisoryLockProvider(fake, &AdvisoryLockProviderConfig{PollInterval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewAdvisoryLockProvider: %v", err)
+	}
+
+	release, err := p.Acquire(context.Background(), "saga-1")
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if _, err := p.Acquire(ctx, "saga-1"); !gerrors.Is(err, gerrors.Timeout) {
+		t.Fatalf("expected Timeout while lock is held, got %v", err)
+	}
+
+	release()
+	release()
+	release2, err := p.Acquire(context.Background(), "saga-1")
+	if err != nil {
+		t.Fatalf("Acquire after release: %v", err)
+	}
+	release2()
+
+	if !strings.Contains(fake.queries[0], "pg_try_advisory_xact_lock") {
+		t.Fatalf("unexpected lock query: %s", fake.queries[0])
+	}
+}
+
+func TestMySQLLockName_Length(t *testing.T) {
+	if got := mysqlLockName("gochen:short"); got != "gochen:short" {
+		t.Fatalf("short names must be kept, got %q", got)
+	}
+	long := mysqlLockName("gochen:" + strings.Repeat("x", 100))
+	if len(long) != mysqlLockNameMaxLen {
+		t.Fatalf("expected hashed name of %d chars, got %d", mysqlLockNameMaxLen, len(long))
+	}
+	if advisoryLockID("a") != advisoryLockID("a") || advisoryLockID("a") == advisoryLockID("b") {
+		t.Fatalf("advisory lock id must be deterministic and key-specific")
+	}
+}
@@ -0,0 +1,270 @@
+package processmanager
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"gochen/clock"
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/bus"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/messaging/command"
+	"gochen/process/lock"
+)
+
+const (
+	// DefaultHandledEventLimit 是每个实例保留的已处理事件 ID 数量（去重窗口）。
+	DefaultHandledEventLimit = 64
+
+	// MetadataProcessType / MetadataProcessCorrelationID 是写入所发命令元数据的键，
+	// 便于命令处理方回溯到发出它的流程实例（流程类型 + 实例关联键）。
+	//
+	// 命令同时补齐 contextx.MetadataCorrelationKey（沿用事件的流程根关联 ID，缺失时为事件 ID）
+	// 与 contextx.MetadataCausationKey（触发事件 ID），与命令总线、Saga 使用同一套追踪键。
+	MetadataProcessType          = "process_type"
+	MetadataProcessCorrelationID = "process_correlation_id"
+)
+
+// Manager 把 IProcess 接到事件总线上：按关联 ID 加载实例状态、调用决策、执行命令并持久化状态。
+type Manager[S any] struct {
+	process         IProcess[S]
+	stateStore      IStateStore
+	commandExecutor command.ICommandExecutor
+	lock            lock.ILockProvider
+	clock           clock.IClock
+	logger          logging.ILogger
+	handledLimit    int
+
+	mu           sync.Mutex
+	unsubscribes []messaging.UnsubscribeFunc
+}
+
+// NewManager 创建流程管理器。
+func NewManager[S any](process IProcess[S], stateStore IStateStore, commandExecutor command.ICommandExecutor) (*Manager[S], error) {
+	if process == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "process cannot be nil")
+	}
+	if process.ProcessType() == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "process type cannot be empty")
+	}
+	if stateStore == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "process state store cannot be nil")
+	}
+	if commandExecutor == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "command executor cannot be nil")
+	}
+	return &Manager[S]{
+		process:         process,
+		stateStore:      stateStore,
+		commandExecutor: commandExecutor,
+		clock:           clock.NewRealClock(),
+		logger:          logging.ComponentLogger("process.manager").WithField("process_type", process.ProcessType()),
+		handledLimit:    DefaultHandledEventLimit,
+	}, nil
+}
+
+// WithLockProvider 设置实例级锁，保证同一关联 ID 的事件串行处理（未设置时依赖乐观版本冲突）。
+func (m *Manager[S]) WithLockProvider(provider lock.ILockProvider) *Manager[S] {
+	if provider != nil {
+		m.lock = provider
+	}
+	return m
+}
+
+// WithClock 设置时钟。
+func (m *Manager[S]) WithClock(clk clock.IClock) *Manager[S] {
+	if clk != nil {
+		m.clock = clk
+	}
+	return m
+}
+
+// WithHandledEventLimit 设置每个实例保留的已处理事件 ID 数量。
+func (m *Manager[S]) WithHandledEventLimit(limit int) *Manager[S] {
+	if limit > 0 {
+		m.handledLimit = limit
+	}
+	return m
+}
+
+// Subscribe 在事件总线上订阅 IProcess.EventTypes；重复调用返回 Conflict。
+func (m *Manager[S]) Subscribe(ctx context.Context, eventBus bus.IEventBus) error {
+	if eventBus == nil {
+		return errors.NewCode(errors.InvalidInput, "event bus cannot be nil")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.unsubscribes) > 0 {
+		return errors.NewCode(errors.Conflict, "process manager already subscribed").
+			WithContext("process_type", m.process.ProcessType())
+	}
+	handler := bus.EventHandlerFunc(m.HandleEvent)
+	for _, eventType := range m.process.EventTypes() {
+		unsubscribe, err := eventBus.SubscribeEvent(ctx, eventType, handler)
+		if err != nil {
+			m.unsubscribeLocked(ctx)
+			return errors.Wrap(err, errors.Internal, "subscribe process manager failed").
+				WithContext("process_type", m.process.ProcessType()).
+				WithContext("event_type", eventType)
+		}
+		m.unsubscribes = append(m.unsubscribes, unsubscribe)
+	}
+	return nil
+}
+
+// Unsubscribe 取消全部事件订阅。
+func (m *Manager[S]) Unsubscribe(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.unsubscribeLocked(ctx)
+}
+
+func (m *Manager[S]) unsubscribeLocked(ctx context.Context) {
+	for _, unsubscribe := range m.unsubscribes {
+		if err := unsubscribe(ctx); err != nil {
+			m.logger.Warn(ctx, "unsubscribe process manager failed", logging.Error(err))
+		}
+	}
+	m.unsubscribes = nil
+}
+
+// HandleEvent 处理单个事件；可直接作为事件处理器使用（例如接在投影/Outbox 中继之后）。
+//
+// 无法关联、孤立（实例不存在且 CanStart=false）、已结束实例或重复的事件返回 nil。
+func (m *Manager[S]) HandleEvent(ctx context.Context, evt eventing.IEvent) error {
+	if evt == nil {
+		return errors.NewCode(errors.InvalidInput, "event cannot be nil")
+	}
+	correlationID, ok := m.process.CorrelationID(evt)
+	if !ok || correlationID == "" {
+		return nil
+	}
+	processType := m.process.ProcessType()
+
+	if m.lock != nil {
+		release, err := m.lock.Acquire(ctx, processType+":"+correlationID)
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+
+	state, err := m.stateStore.Load(ctx, processType, correlationID)
+	switch {
+	case err == nil:
+	case errors.Is(err, errors.NotFound):
+		if !m.process.CanStart(evt) {
+			m.logger.Debug(ctx, "orphan event ignored",
+				logging.String("correlation_id", correlationID),
+				logging.String("event_type", evt.GetType()))
+			return nil
+		}
+		now := m.clock.Now()
+		state = &State{
+			ProcessType:   processType,
+			CorrelationID: correlationID,
+			Status:        StatusActive,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+	default:
+		return err
+	}
+	if state.Status == StatusCompleted || state.handled(evt.GetID()) {
+		return nil
+	}
+
+	var data S
+	if len(state.Data) > 0 {
+		if err := json.Unmarshal(state.Data, &data); err != nil {
+			return errors.Wrap(err, errors.Internal, "decode process state failed").
+				WithContext("process_type", processType).
+				WithContext("correlation_id", correlationID)
+		}
+	}
+	decision, err := m.process.Handle(ctx, &data, evt)
+	if err != nil {
+		return err
+	}
+
+	for i, cmd := range decision.Commands {
+		if cmd == nil {
+			return errors.NewCode(errors.InvalidInput, "process decision command cannot be nil").
+				WithContext("process_type", processType).
+				WithContext("index", i)
+		}
+		m.annotate(cmd, correlationID, evt)
+		if err := m.commandExecutor.Execute(ctx, cmd); err != nil {
+			return err
+		}
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, errors.Internal, "encode process state failed").
+			WithContext("process_type", processType).
+			WithContext("correlation_id", correlationID)
+	}
+	expectedVersion := state.Version
+	state.Data = encoded
+	state.Version++
+	state.UpdatedAt = m.clock.Now()
+	state.markHandled(evt.GetID(), m.handledLimit)
+	if decision.Complete {
+		completedAt := state.UpdatedAt
+		state.Status = StatusCompleted
+		state.CompletedAt = &completedAt
+	}
+	if err := m.stateStore.Save(ctx, state, expectedVersion); err != nil {
+		return err
+	}
+
+	m.logger.Debug(ctx, "process event handled",
+		logging.String("correlation_id", correlationID),
+		logging.String("event_type", evt.GetType()),
+		logging.Int("commands", len(decision.Commands)),
+		logging.Bool("completed", decision.Complete))
+	return nil
+}
+
+// Load 读取实例状态与解码后的业务状态。
+func (m *Manager[S]) Load(ctx context.Context, correlationID string) (*State, *S, error) {
+	state, err := m.stateStore.Load(ctx, m.process.ProcessType(), correlationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var data S
+	if len(state.Data) > 0 {
+		if err := json.Unmarshal(state.Data, &data); err != nil {
+			return nil, nil, errors.Wrap(err, errors.Internal, "decode process state failed").
+				WithContext("process_type", state.ProcessType).
+				WithContext("correlation_id", correlationID)
+		}
+	}
+	return state, &data, nil
+}
+
+// annotate 为命令补齐流程实例与追踪元数据（不覆盖调用方已设置的值）。
+func (m *Manager[S]) annotate(cmd *command.Command, correlationID string, evt eventing.IEvent) {
+	if cmd.Metadata == nil {
+		cmd.Metadata = messaging.NewMetadata()
+	}
+	setIfAbsent := func(key, value string) {
+		if v, ok := cmd.Metadata.Get(key); (!ok || v == "") && value != "" {
+			cmd.Metadata.Set(key, value)
+		}
+	}
+	flowID := evt.GetID()
+	if v, ok := evt.GetMetadata().Get(contextx.MetadataCorrelationKey); ok && strings.TrimSpace(v) != "" {
+		flowID = v
+	}
+	setIfAbsent(MetadataProcessType, m.process.ProcessType())
+	setIfAbsent(MetadataProcessCorrelationID, correlationID)
+	setIfAbsent(contextx.MetadataCorrelationKey, flowID)
+	setIfAbsent(contextx.MetadataCausationKey, evt.GetID())
+}
@@ -0,0 +1,181 @@
+package processmanager
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/bus"
+	"gochen/messaging"
+	"gochen/messaging/command"
+	"gochen/messaging/transport/direct"
+)
+
+type fulfilmentState struct {
+	Paid    bool `json:"paid"`
+	Shipped bool `json:"shipped"`
+}
+
+// fulfilmentProcess：下单 → 收款 → 发货，收款与下单事件的到达顺序不固定。
+type fulfilmentProcess struct{}
+
+func (fulfilmentProcess) ProcessType() string { return "OrderFulfilment" }
+
+func (fulfilmentProcess) EventTypes() []string {
+	return []string{"OrderPlaced", "PaymentCaptured", "OrderShipped"}
+}
+
+func (fulfilmentProcess) CorrelationID(evt eventing.IEvent) (string, bool) {
+	orderID, ok := evt.GetMetadata().Get("order_id")
+	return orderID, ok
+}
+
+func (fulfilmentProcess) CanStart(evt eventing.IEvent) bool { return evt.GetType() == "OrderPlaced" }
+
+func (fulfilmentProcess) Handle(ctx context.Context, state *fulfilmentState, evt eventing.IEvent) (Decision, error) {
+	orderID, _ := evt.GetMetadata().Get("order_id")
+	switch evt.GetType() {
+	case "OrderPlaced":
+		return Decision{Commands: []*command.Command{command.NewCommand("cmd-pay-"+orderID, "CapturePayment", orderID, "Payment", nil)}}, nil
+	case "PaymentCaptured":
+		state.Paid = true
+		return Decision{Commands: []*command.Command{command.NewCommand("cmd-ship-"+orderID, "ShipOrder", orderID, "Order", nil)}}, nil
+	case "OrderShipped":
+		state.Shipped = true
+		return Decision{Complete: state.Paid}, nil
+	}
+	return Decision{}, nil
+}
+
+type recordingExecutor struct {
+	mu       sync.Mutex
+	commands []*command.Command
+	fail     error
+}
+
+func (e *recordingExecutor) Execute(ctx context.Context, cmd *command.Command) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.fail != nil {
+		return e.fail
+	}
+	e.commands = append(e.commands, cmd)
+	return nil
+}
+
+func orderEvent(id, eventType, orderID string) *eventing.Event[int64] {
+	evt := eventing.NewEvent[int64](1, "Order", eventType, 1, nil)
+	evt.ID = id
+	evt.Metadata.Set("order_id", orderID)
+	return evt
+}
+
+func TestManager_HandleEvent_DrivesProcessByEvents(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStateStore()
+	executor := &recordingExecutor{}
+	m, err := NewManager[fulfilmentState](fulfilmentProcess{}, store, executor)
+	require.NoError(t, err)
+
+	// 孤立事件：实例尚不存在且不能启动实例。
+	require.NoError(t, m.HandleEvent(ctx, orderEvent("e0", "PaymentCaptured", "o1")))
+	_, err = store.Load(ctx, "OrderFulfilment", "o1")
+	require.True(t, errors.Is(err, errors.NotFound))
+
+	require.NoError(t, m.HandleEvent(ctx, orderEvent("e1", "OrderPlaced", "o1")))
+	captured := orderEvent("e2", "PaymentCaptured", "o1")
+	captured.Metadata.Set(contextx.MetadataCorrelationKey, "checkout-1")
+	require.NoError(t, m.HandleEvent(ctx, captured))
+	// 重复投递被去重。
+	require.NoError(t, m.HandleEvent(ctx, orderEvent("e2", "PaymentCaptured", "o1")))
+	require.NoError(t, m.HandleEvent(ctx, orderEvent("e3", "OrderShipped", "o1")))
+	// 已结束实例忽略后续事件。
+	require.NoError(t, m.HandleEvent(ctx, orderEvent("e4", "PaymentCaptured", "o1")))
+
+	require.Len(t, executor.commands, 2)
+	require.Equal(t, "CapturePayment", executor.commands[0].Type)
+	require.Equal(t, "ShipOrder", executor.commands[1].Type)
+	processID, _ := executor.commands[1].Metadata.Get(MetadataProcessCorrelationID)
+	require.Equal(t, "o1", processID)
+	// correlation_id 沿用事件的流程根 ID，causation_id 为触发事件。
+	correlationID, _ := executor.commands[1].Metadata.Get(contextx.MetadataCorrelationKey)
+	require.Equal(t, "checkout-1", correlationID)
+	causation, _ := executor.commands[1].Metadata.Get(contextx.MetadataCausationKey)
+	require.Equal(t, "e2", causation)
+	rootCorrelation, _ := executor.commands[0].Metadata.Get(contextx.MetadataCorrelationKey)
+	require.Equal(t, "e1", rootCorrelation, "an event without correlation starts a new flow")
+
+	state, data, err := m.Load(ctx, "o1")
+	require.NoError(t, err)
+	require.Equal(t, StatusCompleted, state.Status)
+	require.Equal(t, uint64(3), state.Version)
+	require.NotNil(t, state.CompletedAt)
+	require.Equal(t, fulfilmentState{Paid: true, Shipped: true}, *data)
+}
+
+func TestManager_HandleEvent_CommandFailureKeepsState(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStateStore()
+	executor := &recordingExecutor{fail: errors.NewCode(errors.Dependency, "payment service down")}
+	m, err := NewManager[fulfilmentState](fulfilmentProcess{}, store, executor)
+	require.NoError(t, err)
+
+	err = m.HandleEvent(ctx, orderEvent("e1", "OrderPlaced", "o1"))
+	require.True(t, errors.Is(err, errors.Dependency))
+	_, err = store.Load(ctx, "OrderFulfilment", "o1")
+	require.True(t, errors.Is(err, errors.NotFound))
+
+	// 重试成功后实例被创建。
+	executor.fail = nil
+	require.NoError(t, m.HandleEvent(ctx, orderEvent("e1", "OrderPlaced", "o1")))
+	state, _, err := m.Load(ctx, "o1")
+	require.NoError(t, err)
+	require.Equal(t, StatusActive, state.Status)
+	require.Equal(t, []string{"e1"}, state.HandledEventIDs)
+}
+
+func TestManager_Subscribe(t *testing.T) {
+	ctx := context.Background()
+	tpt := direct.NewSyncTransport()
+	require.NoError(t, tpt.Start(ctx))
+	t.Cleanup(func() { _ = tpt.Stop(ctx) })
+	eventBus := bus.NewEventBus(messaging.NewMessageBus(tpt))
+
+	executor := &recordingExecutor{}
+	m, err := NewManager[fulfilmentState](fulfilmentProcess{}, NewMemoryStateStore(), executor)
+	require.NoError(t, err)
+	require.NoError(t, m.Subscribe(ctx, eventBus))
+	require.True(t, errors.Is(m.Subscribe(ctx, eventBus), errors.Conflict))
+
+	require.NoError(t, eventBus.PublishEvent(ctx, orderEvent("e1", "OrderPlaced", "o1")))
+	require.Len(t, executor.commands, 1)
+
+	m.Unsubscribe(ctx)
+	require.NoError(t, eventBus.PublishEvent(ctx, orderEvent("e2", "PaymentCaptured", "o1")))
+	require.Len(t, executor.commands, 1)
+}
+
+func TestMemoryStateStore_OptimisticConcurrency(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStateStore()
+	state := &State{ProcessType: "P", CorrelationID: "c1", Status: StatusActive, Version: 1}
+	require.NoError(t, store.Save(ctx, state, 0))
+	require.True(t, errors.Is(store.Save(ctx, state, 0), errors.Concurrency))
+
+	state.Version = 2
+	require.NoError(t, store.Save(ctx, state, 1))
+	require.True(t, errors.Is(store.Save(ctx, state, 1), errors.Concurrency))
+
+	list, err := store.List(ctx, "P", StatusActive)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.NoError(t, store.Delete(ctx, "P", "c1"))
+	list, err = store.List(ctx, "P", "")
+	require.NoError(t, err)
+	require.Empty(t, list)
+}
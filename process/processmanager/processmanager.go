@@ -0,0 +1,50 @@
+// Package processmanager 提供事件驱动的流程管理器（Process Manager / 编舞型 Saga）。
+//
+// 与 process/saga 的 SagaOrchestrator（预定义线性步骤 + 逆序补偿）不同，流程管理器没有固定步骤表：
+//   - 订阅一组事件类型，按事件计算关联 ID（correlation ID），把事件路由到对应的流程实例；
+//   - 每个实例持有按关联 ID 持久化的业务状态，事件到达时由 IProcess.Handle 基于“当前状态 + 事件”决定下一步；
+//   - 决策产出要发送的命令，以及实例是否已结束。
+//
+// 投递语义：
+//   - 事件至少一次投递；实例记录最近处理过的事件 ID，重复事件被忽略；
+//   - 命令先于状态持久化执行，状态保存失败时事件重试会再次发出命令，命令处理方应按命令 ID 幂等；
+//   - 同一实例的并发事件通过乐观版本（errors.Concurrency）或可选 lock.ILockProvider 串行化。
+package processmanager
+
+import (
+	"context"
+
+	"gochen/eventing"
+	"gochen/messaging/command"
+)
+
+// IProcess 定义一种流程管理器：如何关联事件、何时创建实例以及如何对事件作出决策。
+//
+// 类型参数 S 为实例业务状态，需可 JSON 序列化。
+type IProcess[S any] interface {
+	// ProcessType 返回流程类型（状态存储的分区键，需全局唯一）。
+	ProcessType() string
+
+	// EventTypes 返回需要订阅的事件类型。
+	EventTypes() []string
+
+	// CorrelationID 返回事件所属实例的关联 ID；返回 false 表示忽略该事件。
+	CorrelationID(evt eventing.IEvent) (string, bool)
+
+	// CanStart 判断事件能否为尚不存在的实例创建新状态；返回 false 时孤立事件被忽略。
+	CanStart(evt eventing.IEvent) bool
+
+	// Handle 基于当前状态处理事件，可直接修改 state。
+	//
+	// 返回错误时不执行命令、不保存状态，错误向事件投递方传播（由其决定重试）。
+	Handle(ctx context.Context, state *S, evt eventing.IEvent) (Decision, error)
+}
+
+// Decision 是流程管理器对单个事件的决策结果。
+type Decision struct {
+	// Commands 按顺序执行的命令。
+	Commands []*command.Command
+
+	// Complete 为 true 时实例结束，之后到达的事件被忽略。
+	Complete bool
+}
@@ -0,0 +1,90 @@
+package processmanager
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Status 表示流程实例所处的阶段。
+type Status string
+
+const (
+	// StatusActive 运行中（仍在等待后续事件）
+	StatusActive Status = "active"
+
+	// StatusCompleted 已结束
+	StatusCompleted Status = "completed"
+)
+
+// State 是持久化的流程实例状态。
+type State struct {
+	// ProcessType 流程类型
+	ProcessType string `json:"process_type" db:"process_type"`
+
+	// CorrelationID 关联 ID（同一流程类型内唯一）
+	CorrelationID string `json:"correlation_id" db:"correlation_id"`
+
+	// Status 实例状态
+	Status Status `json:"status" db:"status"`
+
+	// Data 业务状态（IProcess 的 S 序列化结果）
+	Data json.RawMessage `json:"data,omitempty" db:"data"`
+
+	// Version 乐观锁版本，每次保存递增（新实例保存后为 1）
+	Version uint64 `json:"version" db:"version"`
+
+	// HandledEventIDs 最近处理过的事件 ID（用于至少一次投递下的去重，长度受 Manager 配置限制）
+	HandledEventIDs []string `json:"handled_event_ids,omitempty" db:"handled_event_ids"`
+
+	// CreatedAt 创建时间
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// UpdatedAt 更新时间
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	// CompletedAt 结束时间
+	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+}
+
+// Clone 深拷贝状态。
+func (s *State) Clone() *State {
+	if s == nil {
+		return nil
+	}
+	cp := *s
+	if s.Data != nil {
+		cp.Data = append(json.RawMessage(nil), s.Data...)
+	}
+	if s.HandledEventIDs != nil {
+		cp.HandledEventIDs = append([]string(nil), s.HandledEventIDs...)
+	}
+	if s.CompletedAt != nil {
+		completedAt := *s.CompletedAt
+		cp.CompletedAt = &completedAt
+	}
+	return &cp
+}
+
+// handled 判断事件是否已处理。
+func (s *State) handled(eventID string) bool {
+	if eventID == "" {
+		return false
+	}
+	for _, id := range s.HandledEventIDs {
+		if id == eventID {
+			return true
+		}
+	}
+	return false
+}
+
+// markHandled 记录已处理事件，仅保留最近 limit 个。
+func (s *State) markHandled(eventID string, limit int) {
+	if eventID == "" || limit <= 0 {
+		return
+	}
+	s.HandledEventIDs = append(s.HandledEventIDs, eventID)
+	if overflow := len(s.HandledEventIDs) - limit; overflow > 0 {
+		s.HandledEventIDs = append([]string(nil), s.HandledEventIDs[overflow:]...)
+	}
+}
@@ -0,0 +1,23 @@
+package processmanager
+
+import "context"
+
+// IStateStore 抽象流程实例状态存储能力接口。
+type IStateStore interface {
+	// Load 加载实例状态；不存在时返回 errors.NotFound。
+	Load(ctx context.Context, processType, correlationID string) (*State, error)
+
+	// Save 以乐观锁保存实例状态。
+	//
+	// 语义约定：
+	//   - expectedVersion=0 表示创建新实例；实例已存在时返回 errors.Concurrency；
+	//   - expectedVersion>0 时存储中的版本必须等于 expectedVersion，否则返回 errors.Concurrency；
+	//   - 保存成功后 state.Version 已由调用方设置为 expectedVersion+1。
+	Save(ctx context.Context, state *State, expectedVersion uint64) error
+
+	// Delete 删除实例状态（用于清理已结束实例）。
+	Delete(ctx context.Context, processType, correlationID string) error
+
+	// List 列出指定流程类型的实例（可选按状态过滤，用于监控）。
+	List(ctx context.Context, processType string, status Status) ([]*State, error)
+}
@@ -0,0 +1,90 @@
+package processmanager
+
+import (
+	"context"
+	"sync"
+
+	"gochen/errors"
+)
+
+// MemoryStateStore 定义内存流程实例状态存储。
+type MemoryStateStore struct {
+	states map[string]*State
+	mutex  sync.RWMutex
+}
+
+// NewMemoryStateStore 创建内存流程实例状态存储。
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{
+		states: make(map[string]*State),
+	}
+}
+
+func stateKey(processType, correlationID string) string {
+	return processType + "\x00" + correlationID
+}
+
+// Load 加载数据。
+func (s *MemoryStateStore) Load(ctx context.Context, processType, correlationID string) (*State, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	state, exists := s.states[stateKey(processType, correlationID)]
+	if !exists {
+		return nil, errors.NewCode(errors.NotFound, "process instance not found").
+			WithContext("process_type", processType).
+			WithContext("correlation_id", correlationID)
+	}
+	return state.Clone(), nil
+}
+
+// Save 以乐观锁保存状态。
+func (s *MemoryStateStore) Save(ctx context.Context, state *State, expectedVersion uint64) error {
+	if state == nil || state.ProcessType == "" || state.CorrelationID == "" {
+		return errors.NewCode(errors.InvalidInput, "process state is nil or empty")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := stateKey(state.ProcessType, state.CorrelationID)
+	var actual uint64
+	if existing, ok := s.states[key]; ok {
+		actual = existing.Version
+	}
+	if actual != expectedVersion {
+		return errors.NewCode(errors.Concurrency, "process instance version conflict").
+			WithContext("process_type", state.ProcessType).
+			WithContext("correlation_id", state.CorrelationID).
+			WithContext("expected_version", expectedVersion).
+			WithContext("actual_version", actual)
+	}
+	s.states[key] = state.Clone()
+	return nil
+}
+
+// Delete 删除状态。
+func (s *MemoryStateStore) Delete(ctx context.Context, processType, correlationID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.states, stateKey(processType, correlationID))
+	return nil
+}
+
+// List 列出状态。
+func (s *MemoryStateStore) List(ctx context.Context, processType string, status Status) ([]*State, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var result []*State
+	for _, state := range s.states {
+		if state.ProcessType == processType && (status == "" || state.Status == status) {
+			result = append(result, state.Clone())
+		}
+	}
+	return result, nil
+}
+
+// Ensure MemoryStateStore implements IStateStore
+var _ IStateStore = (*MemoryStateStore)(nil)
@@ -0,0 +1,82 @@
+// Package reactions 提供编舞（choreography）辅助：声明“事件 X 发生时，根据载荷发出命令 Y”。
+//
+// 与 process/processmanager 不同，反应是无状态的一对一映射，不需要关联 ID 与实例状态：
+//   - Registry 在事件总线上按事件类型订阅，把事件交给已注册的反应构造命令，再通过 command.ICommandExecutor 执行；
+//   - 命令 ID 由“反应名 + 事件 ID”确定性派生，事件重复投递时得到相同命令 ID，
+//     配合 messaging/command/middleware.IdempotencyMiddleware（或处理方按命令 ID 去重）即可幂等；
+//   - 失败处理按反应配置：向投递方返回错误（重试）、记录后跳过，或写入死信。
+package reactions
+
+import (
+	"context"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/messaging/command"
+)
+
+// MetadataReaction 是写入所发命令元数据的反应名键。
+//
+// 命令同时补齐 contextx.MetadataCorrelationKey（沿用事件的流程根关联 ID，缺失时为事件 ID）
+// 与 contextx.MetadataCausationKey（触发事件 ID），与命令总线、Saga 使用同一套追踪键。
+const MetadataReaction = "reaction"
+
+// ErrorPolicy 定义反应失败（构造或执行命令出错）时的处理方式。
+type ErrorPolicy int
+
+const (
+	// ErrorPolicyFail 把错误返回给事件投递方，由其决定重试（默认）。
+	ErrorPolicyFail ErrorPolicy = iota
+	// ErrorPolicySkip 记录告警后跳过，不影响事件确认。
+	ErrorPolicySkip
+	// ErrorPolicyDeadLetter 写入死信后跳过；未配置死信或写入失败时按 ErrorPolicyFail 处理。
+	ErrorPolicyDeadLetter
+)
+
+// BuildFunc 根据事件构造命令；返回 (nil, nil) 表示该事件不需要反应。
+//
+// 命令 ID 为空时由 Registry 按“反应名 + 事件 ID”派生。
+type BuildFunc func(ctx context.Context, evt eventing.IEvent) (*command.Command, error)
+
+// Reaction 声明一条“事件 -> 命令”反应。
+type Reaction struct {
+	// Name 反应名称（全局唯一），参与命令 ID 派生，上线后不应修改。
+	Name string
+
+	// EventType 触发反应的事件类型。
+	EventType string
+
+	// Build 命令构造函数。
+	Build BuildFunc
+
+	// OnError 失败处理方式（默认 ErrorPolicyFail）。
+	OnError ErrorPolicy
+}
+
+// On 声明一条反应，把事件载荷解码为 P 后交给 build 构造命令。
+func On[P any](name, eventType string, build func(ctx context.Context, evt eventing.IEvent, payload P) (*command.Command, error)) Reaction {
+	return Reaction{
+		Name:      name,
+		EventType: eventType,
+		Build: func(ctx context.Context, evt eventing.IEvent) (*command.Command, error) {
+			var payload P
+			if err := evt.GetPayload().DecodeTo(&payload); err != nil {
+				return nil, errors.Wrap(err, errors.InvalidInput, "decode event payload failed").
+					WithContext("reaction", name).
+					WithContext("event_type", evt.GetType())
+			}
+			return build(ctx, evt, payload)
+		},
+	}
+}
+
+// WithErrorPolicy 返回设置了失败处理方式的反应副本。
+func (r Reaction) WithErrorPolicy(policy ErrorPolicy) Reaction {
+	r.OnError = policy
+	return r
+}
+
+// CommandID 返回反应针对事件派生的确定性命令 ID。
+func CommandID(reactionName, eventID string) string {
+	return "reaction:" + reactionName + ":" + eventID
+}
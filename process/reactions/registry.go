@@ -0,0 +1,234 @@
+package reactions
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"gochen/clock"
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/bus"
+	"gochen/logging"
+	"gochen/messaging"
+	"gochen/messaging/command"
+	"gochen/messaging/deadletter"
+)
+
+// handlerType 是写入死信记录的处理器类型前缀。
+const handlerType = "reactions"
+
+// Registry 保存反应声明，并把它们接到事件总线与命令执行端口之间。
+type Registry struct {
+	commandExecutor command.ICommandExecutor
+	deadLetter      deadletter.ISink
+	clock           clock.IClock
+	logger          logging.ILogger
+
+	mu           sync.RWMutex
+	byEventType  map[string][]Reaction
+	names        map[string]struct{}
+	unsubscribes []messaging.UnsubscribeFunc
+}
+
+// NewRegistry 创建反应注册表。
+func NewRegistry(commandExecutor command.ICommandExecutor) (*Registry, error) {
+	if commandExecutor == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "command executor cannot be nil")
+	}
+	return &Registry{
+		commandExecutor: commandExecutor,
+		clock:           clock.NewRealClock(),
+		logger:          logging.ComponentLogger("process.reactions"),
+		byEventType:     make(map[string][]Reaction),
+		names:           make(map[string]struct{}),
+	}, nil
+}
+
+// WithDeadLetterSink 设置 ErrorPolicyDeadLetter 使用的死信写入端。
+func (r *Registry) WithDeadLetterSink(sink deadletter.ISink) *Registry {
+	if sink != nil {
+		r.deadLetter = sink
+	}
+	return r
+}
+
+// WithClock 设置时钟（死信记录时间）。
+func (r *Registry) WithClock(clk clock.IClock) *Registry {
+	if clk != nil {
+		r.clock = clk
+	}
+	return r
+}
+
+// Register 注册反应；需在 Subscribe 之前调用，名称重复返回 Conflict。
+func (r *Registry) Register(reactions ...Reaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.unsubscribes) > 0 {
+		return errors.NewCode(errors.Conflict, "reactions must be registered before subscribe")
+	}
+	for i, reaction := range reactions {
+		if reaction.Name == "" {
+			return errors.NewCode(errors.InvalidInput, "reaction name cannot be empty").WithContext("index", i)
+		}
+		if reaction.EventType == "" {
+			return errors.NewCode(errors.InvalidInput, "reaction event type cannot be empty").WithContext("reaction", reaction.Name)
+		}
+		if reaction.Build == nil {
+			return errors.NewCode(errors.InvalidInput, "reaction build func cannot be nil").WithContext("reaction", reaction.Name)
+		}
+		if _, exists := r.names[reaction.Name]; exists {
+			return errors.NewCode(errors.Conflict, "reaction already registered").WithContext("reaction", reaction.Name)
+		}
+		r.names[reaction.Name] = struct{}{}
+		r.byEventType[reaction.EventType] = append(r.byEventType[reaction.EventType], reaction)
+	}
+	return nil
+}
+
+// EventTypes 返回已注册反应涉及的事件类型。
+func (r *Registry) EventTypes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]string, 0, len(r.byEventType))
+	for eventType := range r.byEventType {
+		types = append(types, eventType)
+	}
+	return types
+}
+
+// Subscribe 在事件总线上订阅全部已注册的事件类型；重复调用返回 Conflict。
+func (r *Registry) Subscribe(ctx context.Context, eventBus bus.IEventBus) error {
+	if eventBus == nil {
+		return errors.NewCode(errors.InvalidInput, "event bus cannot be nil")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.unsubscribes) > 0 {
+		return errors.NewCode(errors.Conflict, "reactions already subscribed")
+	}
+	handler := bus.EventHandlerFunc(r.HandleEvent)
+	for eventType := range r.byEventType {
+		unsubscribe, err := eventBus.SubscribeEvent(ctx, eventType, handler)
+		if err != nil {
+			r.unsubscribeLocked(ctx)
+			return errors.Wrap(err, errors.Internal, "subscribe reactions failed").
+				WithContext("event_type", eventType)
+		}
+		r.unsubscribes = append(r.unsubscribes, unsubscribe)
+	}
+	return nil
+}
+
+// Unsubscribe 取消全部事件订阅。
+func (r *Registry) Unsubscribe(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.unsubscribeLocked(ctx)
+}
+
+func (r *Registry) unsubscribeLocked(ctx context.Context) {
+	for _, unsubscribe := range r.unsubscribes {
+		if err := unsubscribe(ctx); err != nil {
+			r.logger.Warn(ctx, "unsubscribe reactions failed", logging.Error(err))
+		}
+	}
+	r.unsubscribes = nil
+}
+
+// HandleEvent 依次执行该事件类型的全部反应；可直接作为事件处理器使用。
+//
+// 按 ErrorPolicyFail 失败的反应错误合并返回；其余反应不受影响，重试时依赖确定性命令 ID 去重。
+func (r *Registry) HandleEvent(ctx context.Context, evt eventing.IEvent) error {
+	if evt == nil {
+		return errors.NewCode(errors.InvalidInput, "event cannot be nil")
+	}
+	r.mu.RLock()
+	reactions := r.byEventType[evt.GetType()]
+	r.mu.RUnlock()
+
+	var errs []error
+	for _, reaction := range reactions {
+		err := r.react(ctx, reaction, evt)
+		if err == nil {
+			continue
+		}
+		if err = r.handleFailure(ctx, reaction, evt, err); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Registry) react(ctx context.Context, reaction Reaction, evt eventing.IEvent) error {
+	cmd, err := reaction.Build(ctx, evt)
+	if err != nil {
+		return err
+	}
+	if cmd == nil {
+		return nil
+	}
+	if cmd.ID == "" {
+		cmd.ID = CommandID(reaction.Name, evt.GetID())
+	}
+	if cmd.Metadata == nil {
+		cmd.Metadata = messaging.NewMetadata()
+	}
+	if _, ok := cmd.Metadata.Get(MetadataReaction); !ok {
+		cmd.Metadata.Set(MetadataReaction, reaction.Name)
+	}
+	stampCausation(cmd.Metadata, evt)
+	return r.commandExecutor.Execute(ctx, cmd)
+}
+
+// handleFailure 按反应的失败策略处理错误；返回 nil 表示错误已被吸收。
+func (r *Registry) handleFailure(ctx context.Context, reaction Reaction, evt eventing.IEvent, err error) error {
+	fields := []logging.Field{
+		logging.Error(err),
+		logging.String("reaction", reaction.Name),
+		logging.String("event_type", evt.GetType()),
+		logging.String("event_id", evt.GetID()),
+	}
+	switch reaction.OnError {
+	case ErrorPolicySkip:
+		r.logger.Warn(ctx, "reaction failed, skipped", fields...)
+		return nil
+	case ErrorPolicyDeadLetter:
+		if r.deadLetter != nil {
+			dlqErr := r.deadLetter.Write(ctx, deadletter.Entry{
+				Message:     evt,
+				HandlerType: handlerType + ":" + reaction.Name,
+				Err:         err,
+				OccurredAt:  r.clock.Now(),
+			})
+			if dlqErr == nil {
+				r.logger.Warn(ctx, "reaction failed, dead-lettered", fields...)
+				return nil
+			}
+			r.logger.Error(ctx, "reaction dead-letter write failed", append(fields, logging.String("dlq_error", dlqErr.Error()))...)
+		}
+	}
+	return errors.Wrap(err, errors.Internal, "reaction failed").
+		WithContext("reaction", reaction.Name).
+		WithContext("event_type", evt.GetType()).
+		WithContext("event_id", evt.GetID())
+}
+
+// stampCausation 为命令补齐 correlation_id 与 causation_id（不覆盖已有值）。
+//
+// correlation_id 标识整条业务流程：沿用事件携带的关联 ID，事件没有时以事件 ID 作为流程根；
+// causation_id 为直接引起命令的事件 ID。
+func stampCausation(md *messaging.Metadata, evt eventing.IEvent) {
+	correlationID := evt.GetID()
+	if v, ok := evt.GetMetadata().Get(contextx.MetadataCorrelationKey); ok && strings.TrimSpace(v) != "" {
+		correlationID = v
+	}
+	if v, ok := md.Get(contextx.MetadataCorrelationKey); (!ok || v == "") && correlationID != "" {
+		md.Set(contextx.MetadataCorrelationKey, correlationID)
+	}
+	if v, ok := md.Get(contextx.MetadataCausationKey); (!ok || v == "") && evt.GetID() != "" {
+		md.Set(contextx.MetadataCausationKey, evt.GetID())
+	}
+}
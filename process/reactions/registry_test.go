@@ -0,0 +1,124 @@
+package reactions
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/contextx"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/bus"
+	"gochen/messaging"
+	"gochen/messaging/command"
+	dlqmemory "gochen/messaging/deadletter/memory"
+	"gochen/messaging/transport/direct"
+)
+
+type orderPlaced struct {
+	OrderID string `json:"order_id"`
+	SKU     string `json:"sku"`
+}
+
+type recordingExecutor struct {
+	mu       sync.Mutex
+	commands []*command.Command
+	fail     map[string]error
+}
+
+func (e *recordingExecutor) Execute(ctx context.Context, cmd *command.Command) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.fail[cmd.GetType()]; err != nil {
+		return err
+	}
+	e.commands = append(e.commands, cmd)
+	return nil
+}
+
+func orderPlacedEvent(id string) *eventing.Event[int64] {
+	evt := eventing.NewEvent[int64](1, "Order", "OrderPlaced", 1, orderPlaced{OrderID: "o1", SKU: "sku-1"})
+	evt.ID = id
+	return evt
+}
+
+func reserveInventory() Reaction {
+	return On("reserve-inventory-on-order-placed", "OrderPlaced",
+		func(ctx context.Context, evt eventing.IEvent, p orderPlaced) (*command.Command, error) {
+			return command.NewCommand("", "ReserveInventory", p.SKU, "Inventory", map[string]string{"order_id": p.OrderID}), nil
+		})
+}
+
+func TestRegistry_DispatchesCommandWithDeterministicID(t *testing.T) {
+	ctx := context.Background()
+	tpt := direct.NewSyncTransport()
+	require.NoError(t, tpt.Start(ctx))
+	t.Cleanup(func() { _ = tpt.Stop(ctx) })
+	eventBus := bus.NewEventBus(messaging.NewMessageBus(tpt))
+
+	executor := &recordingExecutor{}
+	registry, err := NewRegistry(executor)
+	require.NoError(t, err)
+	require.NoError(t, registry.Register(reserveInventory()))
+	require.True(t, errors.Is(registry.Register(reserveInventory()), errors.Conflict))
+	require.NoError(t, registry.Subscribe(ctx, eventBus))
+	require.True(t, errors.Is(registry.Register(On("late", "OrderPlaced",
+		func(context.Context, eventing.IEvent, orderPlaced) (*command.Command, error) { return nil, nil })), errors.Conflict))
+
+	// 重复投递同一事件得到相同命令 ID，由命令侧幂等去重
+	require.NoError(t, eventBus.PublishEvent(ctx, orderPlacedEvent("e1")))
+	require.NoError(t, eventBus.PublishEvent(ctx, orderPlacedEvent("e1")))
+	require.Len(t, executor.commands, 2)
+	cmd := executor.commands[0]
+	require.Equal(t, CommandID("reserve-inventory-on-order-placed", "e1"), cmd.ID)
+	require.Equal(t, executor.commands[1].ID, cmd.ID)
+	require.Equal(t, "sku-1", cmd.AggregateID)
+	causation, _ := cmd.Metadata.Get(contextx.MetadataCausationKey)
+	require.Equal(t, "e1", causation)
+	correlation, _ := cmd.Metadata.Get(contextx.MetadataCorrelationKey)
+	require.Equal(t, "e1", correlation, "an event without correlation is the flow root")
+
+	registry.Unsubscribe(ctx)
+	require.NoError(t, eventBus.PublishEvent(ctx, orderPlacedEvent("e2")))
+	require.Len(t, executor.commands, 2)
+}
+
+func TestRegistry_ErrorPolicies(t *testing.T) {
+	ctx := context.Background()
+	unavailable := errors.NewCode(errors.ServiceUnavailable, "inventory unavailable")
+	executor := &recordingExecutor{fail: map[string]error{"ReserveInventory": unavailable}}
+	sink := dlqmemory.NewSink()
+
+	registry, err := NewRegistry(executor)
+	require.NoError(t, err)
+	registry.WithDeadLetterSink(sink)
+	require.NoError(t, registry.Register(
+		reserveInventory(),
+		On("notify-customer", "OrderPlaced", func(ctx context.Context, evt eventing.IEvent, p orderPlaced) (*command.Command, error) {
+			return command.NewCommand("", "NotifyCustomer", p.OrderID, "Order", nil), nil
+		}),
+	))
+
+	// 默认策略：错误返回给投递方，其他反应照常执行
+	err = registry.HandleEvent(ctx, orderPlacedEvent("e1"))
+	require.True(t, errors.Is(err, errors.ServiceUnavailable))
+	require.Len(t, executor.commands, 1)
+	require.Equal(t, "NotifyCustomer", executor.commands[0].GetType())
+
+	skipping, err := NewRegistry(executor)
+	require.NoError(t, err)
+	require.NoError(t, skipping.Register(reserveInventory().WithErrorPolicy(ErrorPolicySkip)))
+	require.NoError(t, skipping.HandleEvent(ctx, orderPlacedEvent("e2")))
+
+	deadLettering, err := NewRegistry(executor)
+	require.NoError(t, err)
+	deadLettering.WithDeadLetterSink(sink)
+	require.NoError(t, deadLettering.Register(reserveInventory().WithErrorPolicy(ErrorPolicyDeadLetter)))
+	require.NoError(t, deadLettering.HandleEvent(ctx, orderPlacedEvent("e3")))
+	entries := sink.Entries()
+	require.Len(t, entries, 1)
+	require.Equal(t, "e3", entries[0].Message.GetID())
+	require.True(t, errors.Is(entries[0].Err, errors.ServiceUnavailable))
+}
@@ -12,6 +12,10 @@ const (
 	EventSagaStepCompleted SagaEventType = "SagaStepCompleted"
 	// EventSagaStepFailed 是常量。
 	EventSagaStepFailed SagaEventType = "SagaStepFailed"
+	// EventSagaStepSkipped 是常量。
+	EventSagaStepSkipped SagaEventType = "SagaStepSkipped"
+	// EventSagaStepRetrying 是常量。
+	EventSagaStepRetrying SagaEventType = "SagaStepRetrying"
 	// EventSagaCompensationStarted 是常量。
 	EventSagaCompensationStarted SagaEventType = "SagaCompensationStarted"
 	// EventSagaCompensationStepCompleted 是常量。
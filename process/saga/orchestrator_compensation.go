@@ -5,6 +5,7 @@ import (
 
 	gerrors "gochen/errors"
 	"gochen/logging"
+	"gochen/messaging"
 )
 
 type compensationStatePersistError struct {
@@ -25,8 +26,70 @@ func (e *compensationStatePersistError) Unwrap() error {
 	return e.cause
 }
 
-// compensate 执行补偿。
-func (o *SagaOrchestrator) compensate(ctx context.Context, saga ISaga, state *SagaState, failedStepIndex int) error {
+// Compensate 人工触发补偿：对状态中已完成的步骤倒序执行补偿，面向运维处理卡住或补偿失败的 Saga。
+//
+// 允许的状态：running（进程中断后未恢复）、failed、compensating（上次补偿中断或失败）。
+// 补偿会从头重新执行，已成功的补偿可能再次执行，因此补偿命令必须幂等。
+// 失败并行组中已成功的分支不在 CompletedSteps 中，需要由补偿命令的幂等语义或人工处理兜底。
+func (o *SagaOrchestrator) Compensate(ctx context.Context, saga ISaga, state *SagaState) error {
+	if ctx == nil {
+		return gerrors.NewCode(gerrors.InvalidInput, "ctx is nil")
+	}
+	if saga == nil {
+		return gerrors.NewCode(gerrors.InvalidInput, "saga is nil")
+	}
+	if state == nil {
+		return gerrors.NewCode(gerrors.InvalidInput, "saga state is nil")
+	}
+
+	sagaID := saga.ID()
+	state = state.WithClock(o.clock)
+	steps := saga.Steps()
+
+	if err := validateSagaSteps(steps); err != nil {
+		return gerrors.Wrap(err, gerrors.InvalidInput, "invalid saga steps").
+			WithContext("saga_id", sagaID)
+	}
+
+	if o.lock != nil {
+		release, err := o.lock.Acquire(ctx, sagaID)
+		if err != nil {
+			return gerrors.NewCodeWithCause(gerrors.Timeout, "failed to acquire saga lock", err).WithContext("saga_id", sagaID)
+		}
+		defer release()
+	}
+
+	if state.SagaID != sagaID {
+		return gerrors.NewCode(gerrors.InvalidInput, "saga state id does not match saga").
+			WithContext("saga_id", sagaID).
+			WithContext("state_saga_id", state.SagaID)
+	}
+	if state.IsCompleted() || state.IsCompensated() {
+		return gerrors.NewCode(gerrors.Conflict, "saga is already finished and cannot be compensated").
+			WithContext("saga_id", sagaID).
+			WithContext("status", string(state.Status))
+	}
+	if err := validateStateProgress(sagaID, state, steps); err != nil {
+		return err
+	}
+
+	o.logger.Warn(ctx, "manual saga compensation requested",
+		logging.String("saga_id", sagaID),
+		logging.String("status", string(state.Status)),
+		logging.Int("current_step", state.CurrentStep))
+
+	if err := o.compensate(ctx, saga, state, state.CurrentStep, nil); err != nil {
+		return err
+	}
+
+	o.publishEvent(ctx, EventSagaCompensationCompleted, sagaID, map[string]any{
+		"manual": true,
+	})
+	return nil
+}
+
+// compensate 执行补偿：先补偿失败并行组中已成功的分支，再倒序补偿已完成的步骤。
+func (o *SagaOrchestrator) compensate(ctx context.Context, saga ISaga, state *SagaState, failedStepIndex int, succeededBranches []*SagaStep) error {
 	sagaID := saga.ID()
 	steps := saga.Steps()
 
@@ -43,74 +106,118 @@ func (o *SagaOrchestrator) compensate(ctx context.Context, saga ISaga, state *Sa
 	// 发布补偿开始事件
 	o.publishEvent(ctx, EventSagaCompensationStarted, sagaID, nil)
 
+	for i := len(succeededBranches) - 1; i >= 0; i-- {
+		if err := o.compensateStep(ctx, sagaID, succeededBranches[i], failedStepIndex); err != nil {
+			return err
+		}
+	}
+
 	// 倒序执行补偿（从失败步骤的前一个开始）
 	for i := failedStepIndex - 1; i >= 0; i-- {
 		step := steps[i]
 
-		// 如果没有补偿命令，跳过
-		if !step.HasCompensation() {
-			o.logger.Info(ctx, "step has no compensation, skipping",
-				logging.String("saga_id", sagaID),
-				logging.String("step", step.Name))
+		// 跳过的步骤从未执行，无需补偿
+		if state.IsStepSkipped(step.Name) {
 			continue
 		}
 
-		o.logger.Info(ctx, "executing compensation",
-			logging.String("saga_id", sagaID),
-			logging.Int("step_index", i),
-			logging.String("step", step.Name))
-
-		// 生成补偿命令
-		compCmd, err := step.Compensation(ctx)
-		if err != nil {
-			o.logger.Error(ctx, "failed to generate compensation command", logging.Error(err),
-				logging.String("saga_id", sagaID),
-				logging.String("step", step.Name))
-			return gerrors.NewCodeWithCause(gerrors.Internal, "failed to generate compensation command", err).WithContext("saga_id", sagaID).WithContext("step", step.Name)
+		if !step.IsParallel() {
+			if err := o.compensateStep(ctx, sagaID, step, i); err != nil {
+				return err
+			}
+			continue
 		}
-
-		if compCmd == nil {
-			o.logger.Error(ctx, "compensation command is nil",
-				logging.String("saga_id", sagaID),
-				logging.String("step", step.Name))
-			return gerrors.NewCode(gerrors.Internal, "compensation command is nil").WithContext("saga_id", sagaID).WithContext("step", step.Name)
+		for j := len(step.Parallel) - 1; j >= 0; j-- {
+			branch := step.Parallel[j]
+			if state.IsStepSkipped(branch.Name) {
+				continue
+			}
+			if err := o.compensateStep(ctx, sagaID, branch, i); err != nil {
+				return err
+			}
 		}
+	}
+
+	// 标记补偿完成
+	state.MarkCompensated()
+	if updateErr := o.updateState(ctx, state); updateErr != nil {
+		return &compensationStatePersistError{cause: updateErr}
+	}
+
+	o.logger.Info(ctx, "saga compensation completed",
+		logging.String("saga_id", sagaID))
+
+	return nil
+}
 
-		if o.commandExecutor == nil {
-			return gerrors.NewCode(gerrors.InvalidInput, "command executor is nil").
+// compensateStep 执行单个普通步骤（或并行分支）的补偿命令；补偿中的 panic 转换为补偿失败。
+func (o *SagaOrchestrator) compensateStep(ctx context.Context, sagaID string, step *SagaStep, stepIndex int) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = messaging.PanicError("saga compensation panicked", r).
 				WithContext("saga_id", sagaID).
 				WithContext("step", step.Name)
 		}
+	}()
 
-		// 执行补偿命令
-		if err := o.commandExecutor.Execute(ctx, compCmd); err != nil {
-			o.logger.Error(ctx, "failed to execute compensation command", logging.Error(err),
-				logging.String("saga_id", sagaID),
-				logging.String("step", step.Name))
+	// 如果没有补偿命令，跳过
+	if !step.HasCompensation() {
+		o.logger.Info(ctx, "step has no compensation, skipping",
+			logging.String("saga_id", sagaID),
+			logging.String("step", step.Name))
+		return nil
+	}
 
-			// 发布补偿失败事件
-			o.publishEvent(ctx, EventSagaCompensationStepFailed, sagaID, map[string]any{
-				"step":  step.Name,
-				"error": err.Error(),
-			})
+	o.logger.Info(ctx, "executing compensation",
+		logging.String("saga_id", sagaID),
+		logging.Int("step_index", stepIndex),
+		logging.String("step", step.Name))
 
-			return gerrors.NewCodeWithCause(gerrors.Internal, "compensation execution failed", err).WithContext("saga_id", sagaID).WithContext("step", step.Name)
-		}
+	// 生成补偿命令
+	compCmd, err := step.Compensation(ctx)
+	if err != nil {
+		o.logger.Error(ctx, "failed to generate compensation command", logging.Error(err),
+			logging.String("saga_id", sagaID),
+			logging.String("step", step.Name))
+		return gerrors.NewCodeWithCause(gerrors.Internal, "failed to generate compensation command", err).WithContext("saga_id", sagaID).WithContext("step", step.Name)
+	}
 
-		// 发布补偿步骤完成事件
-		o.publishEvent(ctx, EventSagaCompensationStepCompleted, sagaID, map[string]any{
-			"step": step.Name,
-		})
+	if compCmd == nil {
+		o.logger.Error(ctx, "compensation command is nil",
+			logging.String("saga_id", sagaID),
+			logging.String("step", step.Name))
+		return gerrors.NewCode(gerrors.Internal, "compensation command is nil").WithContext("saga_id", sagaID).WithContext("step", step.Name)
 	}
 
-	// 标记补偿完成
-	state.MarkCompensated()
-	if updateErr := o.updateState(ctx, state); updateErr != nil {
-		return &compensationStatePersistError{cause: updateErr}
+	if o.commandExecutor == nil {
+		return gerrors.NewCode(gerrors.InvalidInput, "command executor is nil").
+			WithContext("saga_id", sagaID).
+			WithContext("step", step.Name)
 	}
 
-	o.logger.Info(ctx, "saga compensation completed",
-		logging.String("saga_id", sagaID))
+	cmdCtx, err := stampCommand(ctx, sagaID, compCmd)
+	if err != nil {
+		return err
+	}
+
+	// 执行补偿命令
+	if err := o.commandExecutor.Execute(cmdCtx, compCmd); err != nil {
+		o.logger.Error(ctx, "failed to execute compensation command", logging.Error(err),
+			logging.String("saga_id", sagaID),
+			logging.String("step", step.Name))
+
+		// 发布补偿失败事件
+		o.publishEvent(ctx, EventSagaCompensationStepFailed, sagaID, map[string]any{
+			"step":  step.Name,
+			"error": err.Error(),
+		})
+
+		return gerrors.NewCodeWithCause(gerrors.Internal, "compensation execution failed", err).WithContext("saga_id", sagaID).WithContext("step", step.Name)
+	}
 
+	// 发布补偿步骤完成事件
+	o.publishEvent(ctx, EventSagaCompensationStepCompleted, sagaID, map[string]any{
+		"step": step.Name,
+	})
 	return nil
 }
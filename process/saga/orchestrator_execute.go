@@ -2,10 +2,13 @@ package saga
 
 import (
 	"context"
-	"fmt"
 
+	"gochen/contextx"
 	gerrors "gochen/errors"
 	"gochen/logging"
+	"gochen/messaging"
+	"gochen/messaging/command"
+	"gochen/policy/retry"
 )
 
 func (o *SagaOrchestrator) Execute(ctx context.Context, saga ISaga) error {
@@ -17,6 +20,7 @@ func (o *SagaOrchestrator) Execute(ctx context.Context, saga ISaga) error {
 	}
 
 	sagaID := saga.ID()
+	ctx = withSagaContext(ctx, sagaID)
 	steps := saga.Steps()
 
 	if len(steps) == 0 {
@@ -40,7 +44,7 @@ func (o *SagaOrchestrator) Execute(ctx context.Context, saga ISaga) error {
 		logging.Int("steps", len(steps)))
 
 	// 创建初始状态
-	state := NewSagaState(sagaID, fmt.Sprintf("%T", saga)).WithClock(o.clock)
+	state := NewSagaState(sagaID, TypeName(saga)).WithClock(o.clock)
 	state.Status = SagaStatusRunning
 
 	// Save initial state
@@ -71,12 +75,7 @@ func (o *SagaOrchestrator) Execute(ctx context.Context, saga ISaga) error {
 			logging.String("step_name", step.Name))
 
 		// 执行步骤
-		if err := o.executeStep(ctx, step); err != nil {
-			return o.handleStepFailure(ctx, saga, state, step, i, err)
-		}
-
-		// 标记步骤完成
-		if err := o.markStepCompleted(ctx, state, sagaID, step.Name); err != nil {
+		if err := o.runStep(ctx, saga, state, step, i); err != nil {
 			return err
 		}
 	}
@@ -92,6 +91,44 @@ func (o *SagaOrchestrator) Execute(ctx context.Context, saga ISaga) error {
 	return nil
 }
 
+// runStep 按执行条件运行第 stepIndex 个步骤并推进状态；失败时进入补偿流程。
+func (o *SagaOrchestrator) runStep(ctx context.Context, saga ISaga, state *SagaState, step *SagaStep, stepIndex int) error {
+	sagaID := saga.ID()
+
+	run, err := o.evaluateCondition(ctx, sagaID, state, step)
+	if err != nil {
+		return o.handleStepFailure(ctx, saga, state, step, stepIndex, err, nil)
+	}
+	if !run {
+		return o.markStepSkipped(ctx, state, sagaID, step.Name)
+	}
+
+	if step.IsParallel() {
+		if succeeded, err := o.executeParallelStep(ctx, sagaID, state, step); err != nil {
+			return o.handleStepFailure(ctx, saga, state, step, stepIndex, err, succeeded)
+		}
+	} else if err := o.executeStep(ctx, sagaID, step); err != nil {
+		return o.handleStepFailure(ctx, saga, state, step, stepIndex, err, nil)
+	}
+
+	// 标记步骤完成
+	return o.markStepCompleted(ctx, state, sagaID, step.Name)
+}
+
+// markStepSkipped 记录顶层步骤被跳过，并像完成一样推进进度（保持 Resume 校验语义）。
+func (o *SagaOrchestrator) markStepSkipped(ctx context.Context, state *SagaState, sagaID string, stepName string) error {
+	state.MarkStepSkipped(stepName)
+	state.MarkStepCompleted(stepName)
+	if updateErr := o.updateState(ctx, state); updateErr != nil {
+		return updateErr
+	}
+
+	o.publishEvent(ctx, EventSagaStepSkipped, sagaID, map[string]any{
+		"step": stepName,
+	})
+	return nil
+}
+
 func (o *SagaOrchestrator) markStepCompleted(ctx context.Context, state *SagaState, sagaID string, stepName string) error {
 	state.MarkStepCompleted(stepName)
 	if updateErr := o.updateState(ctx, state); updateErr != nil {
@@ -105,7 +142,8 @@ func (o *SagaOrchestrator) markStepCompleted(ctx context.Context, state *SagaSta
 	return nil
 }
 
-func (o *SagaOrchestrator) handleStepFailure(ctx context.Context, saga ISaga, state *SagaState, step *SagaStep, stepIndex int, stepErr error) error {
+// handleStepFailure 处理步骤失败；succeededBranches 为失败并行组中已成功、需要先行补偿的分支。
+func (o *SagaOrchestrator) handleStepFailure(ctx context.Context, saga ISaga, state *SagaState, step *SagaStep, stepIndex int, stepErr error, succeededBranches []*SagaStep) error {
 	sagaID := saga.ID()
 
 	o.logger.Error(ctx, "saga step failed", logging.Error(stepErr),
@@ -125,7 +163,7 @@ func (o *SagaOrchestrator) handleStepFailure(ctx context.Context, saga ISaga, st
 	})
 
 	// 执行补偿
-	if compErr := o.compensate(ctx, saga, state, stepIndex); compErr != nil {
+	if compErr := o.compensate(ctx, saga, state, stepIndex, succeededBranches); compErr != nil {
 		var persistErr *compensationStatePersistError
 		if gerrors.As(compErr, &persistErr) {
 			o.logger.Error(ctx, "saga compensation completed but failed to persist compensated state", logging.Error(compErr),
@@ -193,24 +231,57 @@ func (o *SagaOrchestrator) completeSaga(ctx context.Context, saga ISaga, state *
 	return nil
 }
 
-// executeStep 执行单个步骤。
-func (o *SagaOrchestrator) executeStep(ctx context.Context, step *SagaStep) error {
-	// 生成命令
-	cmd, err := step.Command(ctx)
-	if err != nil {
-		return gerrors.Wrap(err, gerrors.Internal, "failed to generate command")
-	}
-
-	if cmd == nil {
-		return gerrors.NewCode(gerrors.InvalidInput, "command is nil")
+// executeStep 执行单个步骤；配置了 RetryPolicy 时按策略重试正向命令。
+func (o *SagaOrchestrator) executeStep(ctx context.Context, sagaID string, step *SagaStep) error {
+	// 命令生成失败属于步骤定义错误：不重试，也不触发步骤失败回调。
+	var buildErr error
+	run := func(ctx context.Context, attempt int) (err error) {
+		// 命令生成或执行中的 panic 转换为步骤失败，按重试策略与补偿流程处理。
+		defer func() {
+			if r := recover(); r != nil {
+				err = messaging.PanicError("saga step panicked", r).
+					WithContext("saga_id", sagaID).
+					WithContext("step", step.Name)
+			}
+		}()
+		if attempt > 1 {
+			o.logger.Warn(ctx, "retrying saga step",
+				logging.String("saga_id", sagaID),
+				logging.String("step_name", step.Name),
+				logging.Int("attempt", attempt))
+			o.publishEvent(ctx, EventSagaStepRetrying, sagaID, map[string]any{
+				"step":    step.Name,
+				"attempt": attempt,
+			})
+		}
+		cmd, err := o.buildStepCommand(ctx, step)
+		if err != nil {
+			buildErr = err
+			return err
+		}
+		cmdCtx, err := stampCommand(ctx, sagaID, cmd)
+		if err != nil {
+			buildErr = err
+			return err
+		}
+		// 使用显式命令执行端口执行业务步骤。
+		return o.commandExecutor.Execute(cmdCtx, cmd)
 	}
 
-	if o.commandExecutor == nil {
-		return gerrors.NewCode(gerrors.InvalidInput, "command executor is nil")
+	var err error
+	if step.RetryPolicy == nil {
+		err = run(ctx, 1)
+	} else {
+		policy := *step.RetryPolicy
+		cfg := policy.retryConfig()
+		cfg.Clock = o.clock
+		cfg.RetryIf = func(err error) bool { return buildErr == nil && policy.IsRetryable(err) }
+		err = retry.DoWithInfo(ctx, run, cfg)
 	}
-
-	// 使用显式命令执行端口执行业务步骤。
-	if err := o.commandExecutor.Execute(ctx, cmd); err != nil {
+	if err != nil {
+		if buildErr != nil {
+			return buildErr
+		}
 		// 调用失败回调
 		if step.OnFailure != nil {
 			if callbackErr := step.OnFailure(ctx, step.Name, err); callbackErr != nil {
@@ -233,6 +304,23 @@ func (o *SagaOrchestrator) executeStep(ctx context.Context, step *SagaStep) erro
 	return nil
 }
 
+// buildStepCommand 生成步骤正向命令。
+func (o *SagaOrchestrator) buildStepCommand(ctx context.Context, step *SagaStep) (*command.Command, error) {
+	cmd, err := step.Command(ctx)
+	if err != nil {
+		return nil, gerrors.Wrap(err, gerrors.Internal, "failed to generate command")
+	}
+
+	if cmd == nil {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "command is nil")
+	}
+
+	if o.commandExecutor == nil {
+		return nil, gerrors.NewCode(gerrors.InvalidInput, "command executor is nil")
+	}
+	return cmd, nil
+}
+
 func (o *SagaOrchestrator) notifySagaFailed(ctx context.Context, saga ISaga, err error) {
 	if saga == nil {
 		return
@@ -242,3 +330,31 @@ func (o *SagaOrchestrator) notifySagaFailed(ctx context.Context, saga ISaga, err
 			logging.String("saga_id", saga.ID()))
 	}
 }
+
+// withSagaContext 把 sagaID 写入 ctx，使步骤命令、补偿与状态存储中的日志自动携带 saga_id。
+func withSagaContext(ctx context.Context, sagaID string) context.Context {
+	if derived, err := contextx.WithSagaID(ctx, sagaID); err == nil {
+		return derived
+	}
+	return ctx
+}
+
+// stampCommand 为步骤/补偿命令补齐 correlation_id 与 causation_id，并返回以命令 ID 为 causationID 的执行 ctx。
+//
+// 命令 metadata 已设置的字段保持不变；ctx 未携带 correlation_id 时以 sagaID 作为流程根 ID，
+// 未携带 causation_id 时以 sagaID 作为命令的直接原因。执行端口即使不经消息总线，
+// 命令产生并持久化的事件也能按 correlation_id 与 causation_id 追溯到 Saga 与命令。
+func stampCommand(ctx context.Context, sagaID string, cmd *command.Command) (context.Context, error) {
+	md := cmd.GetMetadata()
+	ctx, err := contextx.EnsureCorrelationID(ctx, md, sagaID)
+	if err != nil {
+		return nil, err
+	}
+	if err := contextx.InjectCausationID(ctx, md); err != nil {
+		return nil, err
+	}
+	if v, ok := md.Get(contextx.MetadataCausationKey); !ok || v == "" {
+		md.Set(contextx.MetadataCausationKey, sagaID)
+	}
+	return contextx.WithCausationID(ctx, cmd.GetID())
+}
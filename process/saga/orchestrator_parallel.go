@@ -0,0 +1,79 @@
+package saga
+
+import (
+	"context"
+	"sync"
+
+	gerrors "gochen/errors"
+	"gochen/logging"
+)
+
+// evaluateCondition 判断步骤是否需要执行；未设置条件时总是执行。
+func (o *SagaOrchestrator) evaluateCondition(ctx context.Context, sagaID string, state *SagaState, step *SagaStep) (bool, error) {
+	if step.Condition == nil {
+		return true, nil
+	}
+	run, err := step.Condition(ctx, state.Clone())
+	if err != nil {
+		return false, gerrors.Wrap(err, gerrors.Internal, "failed to evaluate step condition").
+			WithContext("saga_id", sagaID).
+			WithContext("step", step.Name)
+	}
+	if !run {
+		o.logger.Info(ctx, "saga step condition not met, skipping",
+			logging.String("saga_id", sagaID),
+			logging.String("step_name", step.Name))
+	}
+	return run, nil
+}
+
+// executeParallelStep 并发执行并行组的分支。
+//
+// 失败分支不会取消其他分支（取消执行中的命令会留下不确定状态），等待全部结束后
+// 返回已成功的分支（供补偿）与合并后的错误。
+func (o *SagaOrchestrator) executeParallelStep(ctx context.Context, sagaID string, state *SagaState, group *SagaStep) ([]*SagaStep, error) {
+	branches := make([]*SagaStep, 0, len(group.Parallel))
+	for _, branch := range group.Parallel {
+		run, err := o.evaluateCondition(ctx, sagaID, state, branch)
+		if err != nil {
+			return nil, err
+		}
+		if !run {
+			state.MarkStepSkipped(branch.Name)
+			o.publishEvent(ctx, EventSagaStepSkipped, sagaID, map[string]any{
+				"step":  branch.Name,
+				"group": group.Name,
+			})
+			continue
+		}
+		branches = append(branches, branch)
+	}
+
+	errs := make([]error, len(branches))
+	var wg sync.WaitGroup
+	for i, branch := range branches {
+		wg.Go(func() {
+			errs[i] = o.executeStep(ctx, sagaID, branch)
+		})
+	}
+	wg.Wait()
+
+	succeeded := make([]*SagaStep, 0, len(branches))
+	var failures []error
+	for i, branch := range branches {
+		if errs[i] != nil {
+			failures = append(failures, gerrors.Wrap(errs[i], gerrors.Internal, "parallel branch failed").
+				WithContext("step", branch.Name))
+			continue
+		}
+		succeeded = append(succeeded, branch)
+		o.publishEvent(ctx, EventSagaStepCompleted, sagaID, map[string]any{
+			"step":  branch.Name,
+			"group": group.Name,
+		})
+	}
+	if len(failures) == 0 {
+		return nil, nil
+	}
+	return succeeded, gerrors.Join(failures...)
+}
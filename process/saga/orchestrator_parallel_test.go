@@ -0,0 +1,119 @@
+package saga
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/messaging/command"
+)
+
+// recordingExecutor 记录已执行命令类型，并按类型注入失败。
+type recordingExecutor struct {
+	mu       sync.Mutex
+	executed []string
+	fail     map[string]error
+}
+
+func (e *recordingExecutor) Execute(ctx context.Context, cmd *command.Command) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.executed = append(e.executed, cmd.GetType())
+	return e.fail[cmd.GetType()]
+}
+
+func (e *recordingExecutor) has(cmdType string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, executed := range e.executed {
+		if executed == cmdType {
+			return true
+		}
+	}
+	return false
+}
+
+func testStep(name string) *SagaStep {
+	return NewSagaStep(name, func(ctx context.Context) (*command.Command, error) {
+		return command.NewCommand(name, name, "1", "Order", nil), nil
+	}).WithCompensation(func(ctx context.Context) (*command.Command, error) {
+		return command.NewCommand("undo-"+name, "Undo"+name, "1", "Order", nil), nil
+	})
+}
+
+// TestSagaOrchestrator_ParallelStep_CompensatesSucceededBranches 验证并行组任一分支失败时补偿已成功分支与此前步骤。
+func TestSagaOrchestrator_ParallelStep_CompensatesSucceededBranches(t *testing.T) {
+	executor := &recordingExecutor{fail: map[string]error{
+		"AuthorizePayment": errors.NewCode(errors.Validation, "card declined"),
+	}}
+	stateStore := NewMemorySagaStateStore()
+	orchestrator := NewSagaOrchestrator(executor, &mockSagaEventBus{}, stateStore)
+
+	saga := &resumeSaga{id: "parallel-fail"}
+	saga.steps = []*SagaStep{
+		testStep("CreateOrder"),
+		NewParallelStep("Prepare", testStep("ReserveInventory"), testStep("AuthorizePayment")),
+		testStep("ShipOrder"),
+	}
+
+	err := orchestrator.Execute(context.Background(), saga)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, errors.Validation))
+	require.True(t, executor.has("UndoReserveInventory"))
+	require.True(t, executor.has("UndoCreateOrder"))
+	require.False(t, executor.has("UndoAuthorizePayment"))
+	require.False(t, executor.has("ShipOrder"))
+	require.Equal(t, 1, saga.failedCall)
+
+	state, err := stateStore.Load(context.Background(), "parallel-fail")
+	require.NoError(t, err)
+	require.Equal(t, SagaStatusCompensated, state.Status)
+	require.Equal(t, "Prepare", state.FailedStep)
+}
+
+// TestSagaOrchestrator_ConditionalSteps 验证条件不满足的步骤/分支被跳过，且不参与补偿。
+func TestSagaOrchestrator_ConditionalSteps(t *testing.T) {
+	executor := &recordingExecutor{fail: map[string]error{
+		"ShipOrder": errors.NewCode(errors.ServiceUnavailable, "carrier unavailable"),
+	}}
+	stateStore := NewMemorySagaStateStore()
+	orchestrator := NewSagaOrchestrator(executor, &mockSagaEventBus{}, stateStore)
+
+	isVIP := func(vip bool) StepCondition {
+		return func(ctx context.Context, state *SagaState) (bool, error) {
+			return vip, nil
+		}
+	}
+	saga := &resumeSaga{id: "conditional"}
+	saga.steps = []*SagaStep{
+		testStep("ApplyVIPDiscount").WithCondition(isVIP(false)),
+		NewParallelStep("Prepare",
+			testStep("ReserveInventory"),
+			testStep("ReserveGift").WithCondition(isVIP(false)),
+		),
+		testStep("ShipOrder"),
+	}
+
+	require.Error(t, orchestrator.Execute(context.Background(), saga))
+	require.False(t, executor.has("ApplyVIPDiscount"))
+	require.False(t, executor.has("ReserveGift"))
+	require.True(t, executor.has("UndoReserveInventory"))
+	require.False(t, executor.has("UndoReserveGift"))
+	require.False(t, executor.has("UndoApplyVIPDiscount"))
+
+	state, err := stateStore.Load(context.Background(), "conditional")
+	require.NoError(t, err)
+	require.Equal(t, []string{"ApplyVIPDiscount", "Prepare"}, state.CompletedSteps)
+	require.Equal(t, []string{"ApplyVIPDiscount", "ReserveGift"}, state.SkippedSteps)
+}
+
+// TestValidateSagaSteps_ParallelGroups 验证并行组的定义约束。
+func TestValidateSagaSteps_ParallelGroups(t *testing.T) {
+	require.NoError(t, validateSagaSteps([]*SagaStep{NewParallelStep("group", testStep("a"), testStep("b"))}))
+	require.Error(t, validateSagaSteps([]*SagaStep{testStep("a"), NewParallelStep("group", testStep("a"))}))
+	require.Error(t, validateSagaSteps([]*SagaStep{NewParallelStep("outer", NewParallelStep("inner", testStep("a")))}))
+	require.Error(t, validateSagaSteps([]*SagaStep{NewParallelStep("group", testStep("a")).WithCompensation(testStep("b").Command)}))
+}
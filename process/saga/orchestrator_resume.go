@@ -16,6 +16,7 @@ func (o *SagaOrchestrator) Resume(ctx context.Context, saga ISaga, state *SagaSt
 	}
 
 	sagaID := saga.ID()
+	ctx = withSagaContext(ctx, sagaID)
 	state = state.WithClock(o.clock)
 	steps := saga.Steps()
 
@@ -50,11 +51,7 @@ func (o *SagaOrchestrator) Resume(ctx context.Context, saga ISaga, state *SagaSt
 	for i := state.CurrentStep; i < len(steps); i++ {
 		step := steps[i]
 
-		if err := o.executeStep(ctx, step); err != nil {
-			return o.handleStepFailure(ctx, saga, state, step, i, err)
-		}
-
-		if err := o.markStepCompleted(ctx, state, sagaID, step.Name); err != nil {
+		if err := o.runStep(ctx, saga, state, step, i); err != nil {
 			return err
 		}
 	}
@@ -93,6 +90,11 @@ func validateResumeState(sagaID string, state *SagaState, steps []*SagaStep) err
 		return gerrors.NewCode(gerrors.Conflict, "saga is compensating and cannot be resumed directly").
 			WithContext("saga_id", sagaID)
 	}
+	return validateStateProgress(sagaID, state, steps)
+}
+
+// validateStateProgress 校验持久化进度（CurrentStep/CompletedSteps）与当前 Saga 定义一致。
+func validateStateProgress(sagaID string, state *SagaState, steps []*SagaStep) error {
 	if state.CurrentStep < 0 || state.CurrentStep > len(steps) {
 		return gerrors.NewCode(gerrors.InvalidInput, "saga current step is out of range").
 			WithContext("saga_id", sagaID).
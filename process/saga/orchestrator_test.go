@@ -3,11 +3,13 @@ package saga
 import (
 	"context"
 	stdErrors "errors"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"gochen/contextx"
 	"gochen/errors"
 	"gochen/eventing"
 	"gochen/eventing/bus"
@@ -17,6 +19,7 @@ import (
 )
 
 type mockSagaEventBus struct {
+	mu     sync.Mutex
 	events []eventing.IEvent
 }
 
@@ -37,6 +40,8 @@ func countSagaEventTypes(events []eventing.IEvent) map[string]int {
 // 返回：
 // - err：错误信息（nil 表示成功）
 func (m *mockSagaEventBus) PublishEvent(ctx context.Context, evt eventing.IEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.events = append(m.events, evt)
 	return nil
 }
@@ -804,6 +809,106 @@ func TestSagaOrchestrator_StepFailure_WithSuccessfulCompensation(t *testing.T) {
 	assert.Equal(t, 1, stepCompletedCount)
 }
 
+// TestSagaOrchestrator_StampsCorrelationAndCausation 验证步骤与补偿命令携带关联与因果 ID，执行 ctx 以命令 ID 为 causation。
+func TestSagaOrchestrator_StampsCorrelationAndCausation(t *testing.T) {
+	type observed struct{ correlation, causation string }
+	newSaga := func() *failingSaga {
+		saga := &failingSaga{}
+		saga.steps = []*SagaStep{
+			NewSagaStep("step1", func(ctx context.Context) (*command.Command, error) {
+				return command.NewCommand("cmd-step1", "CmdStep1", "1", "Step1", nil), nil
+			}).WithCompensation(func(ctx context.Context) (*command.Command, error) {
+				return command.NewCommand("cmd-step1-comp", "CmdStep1Comp", "1", "Step1Comp", nil), nil
+			}),
+			NewSagaStep("step2", func(ctx context.Context) (*command.Command, error) {
+				return command.NewCommand("cmd-step2", "CmdStep2", "1", "Step2", nil), nil
+			}),
+		}
+		return saga
+	}
+	run := func(t *testing.T, ctx context.Context) (map[string]observed, map[string]*command.Command) {
+		seen := make(map[string]observed)
+		cmds := make(map[string]*command.Command)
+		cmdExecutor := newTestCommandExecutor()
+		record := func(fail bool) command.CommandHandlerFunc {
+			return func(ctx context.Context, cmd *command.Command) error {
+				seen[cmd.GetID()] = observed{contextx.CorrelationID(ctx), contextx.CausationID(ctx)}
+				cmds[cmd.GetID()] = cmd
+				if fail {
+					return assert.AnError
+				}
+				return nil
+			}
+		}
+		require.NoError(t, cmdExecutor.RegisterHandler("CmdStep1", record(false)))
+		require.NoError(t, cmdExecutor.RegisterHandler("CmdStep1Comp", record(false)))
+		require.NoError(t, cmdExecutor.RegisterHandler("CmdStep2", record(true)))
+		err := NewSagaOrchestrator(cmdExecutor, &mockSagaEventBus{}, NewMemorySagaStateStore()).Execute(ctx, newSaga())
+		require.Error(t, err)
+		return seen, cmds
+	}
+	metadata := func(cmd *command.Command, key string) string {
+		v, _ := cmd.GetMetadata().Get(key)
+		return v
+	}
+
+	t.Run("Saga 为流程起点", func(t *testing.T) {
+		seen, cmds := run(t, context.Background())
+		for _, id := range []string{"cmd-step1", "cmd-step2", "cmd-step1-comp"} {
+			assert.Equal(t, observed{"saga-fail-1", id}, seen[id], id)
+			assert.Equal(t, "saga-fail-1", metadata(cmds[id], contextx.MetadataCorrelationKey), id)
+			assert.Equal(t, "saga-fail-1", metadata(cmds[id], contextx.MetadataCausationKey), id)
+		}
+	})
+
+	t.Run("沿用上游流程", func(t *testing.T) {
+		ctx, err := contextx.WithCorrelationID(context.Background(), "flow-1")
+		require.NoError(t, err)
+		ctx, err = contextx.WithCausationID(ctx, "evt-1")
+		require.NoError(t, err)
+		seen, cmds := run(t, ctx)
+		assert.Equal(t, observed{"flow-1", "cmd-step1"}, seen["cmd-step1"])
+		assert.Equal(t, "flow-1", metadata(cmds["cmd-step2"], contextx.MetadataCorrelationKey))
+		assert.Equal(t, "evt-1", metadata(cmds["cmd-step2"], contextx.MetadataCausationKey))
+	})
+}
+
+// TestSagaOrchestrator_StepPanic_IsCompensated 验证步骤命令生成中的 panic 被转换为步骤失败并触发补偿。
+func TestSagaOrchestrator_StepPanic_IsCompensated(t *testing.T) {
+	ctx := context.Background()
+
+	cmdExecutor := newTestCommandExecutor()
+	var step1Compensated int
+	require.NoError(t, cmdExecutor.RegisterHandler("CmdStep1", func(ctx context.Context, cmd *command.Command) error { return nil }))
+	require.NoError(t, cmdExecutor.RegisterHandler("CmdStep1Comp", func(ctx context.Context, cmd *command.Command) error {
+		step1Compensated++
+		return nil
+	}))
+
+	saga := &failingSaga{}
+	saga.steps = []*SagaStep{
+		NewSagaStep("step1", func(ctx context.Context) (*command.Command, error) {
+			return command.NewCommand("cmd-step1", "CmdStep1", "1", "Step1", nil), nil
+		}).WithCompensation(func(ctx context.Context) (*command.Command, error) {
+			return command.NewCommand("cmd-step1-comp", "CmdStep1Comp", "1", "Step1Comp", nil), nil
+		}),
+		NewSagaStep("step2", func(ctx context.Context) (*command.Command, error) {
+			panic("missing order reference")
+		}),
+	}
+
+	stateStore := NewMemorySagaStateStore()
+	err := NewSagaOrchestrator(cmdExecutor, &mockSagaEventBus{}, stateStore).Execute(ctx, saga)
+	require.Error(t, err)
+	assert.True(t, messaging.IsPanicError(err))
+	assert.Equal(t, 1, step1Compensated)
+	assert.True(t, saga.failedCalled)
+
+	state, stateErr := stateStore.Load(ctx, saga.ID())
+	require.NoError(t, stateErr)
+	assert.True(t, state.IsCompensated())
+}
+
 // TestSagaOrchestrator_CompensationFailure_EmitsSagaFailed 验证 SagaOrchestrator CompensationFailure EmitsSagaFailed。
 func TestSagaOrchestrator_CompensationFailure_EmitsSagaFailed(t *testing.T) {
 	ctx := context.Background()
@@ -7,22 +7,47 @@ func validateSagaSteps(steps []*SagaStep) error {
 		return fmt.Errorf("saga has no steps")
 	}
 
+	// 步骤与并行分支共用一个名称空间：状态中的 CompletedSteps/SkippedSteps 按名称记录。
 	seenNames := make(map[string]struct{}, len(steps))
 	for i, step := range steps {
-		if step == nil {
-			return fmt.Errorf("step %d is nil", i)
+		if err := validateSagaStep(step, fmt.Sprintf("step %d", i), seenNames); err != nil {
+			return err
 		}
-		if step.Name == "" {
-			return fmt.Errorf("step %d name is empty", i)
+		if !step.IsParallel() {
+			continue
 		}
-		if _, exists := seenNames[step.Name]; exists {
-			return fmt.Errorf("step %q is duplicated", step.Name)
+		if step.Command != nil || step.Compensation != nil {
+			return fmt.Errorf("parallel step %q must not define command or compensation", step.Name)
 		}
-		seenNames[step.Name] = struct{}{}
-		if step.Command == nil {
-			return fmt.Errorf("step %q command is nil", step.Name)
+		if step.RetryPolicy != nil {
+			return fmt.Errorf("parallel step %q must not define retry policy; set it on branches", step.Name)
+		}
+		for j, branch := range step.Parallel {
+			if err := validateSagaStep(branch, fmt.Sprintf("step %q branch %d", step.Name, j), seenNames); err != nil {
+				return err
+			}
+			if branch.IsParallel() {
+				return fmt.Errorf("step %q branch %q: nested parallel steps are not supported", step.Name, branch.Name)
+			}
 		}
 	}
 
 	return nil
 }
+
+func validateSagaStep(step *SagaStep, label string, seenNames map[string]struct{}) error {
+	if step == nil {
+		return fmt.Errorf("%s is nil", label)
+	}
+	if step.Name == "" {
+		return fmt.Errorf("%s name is empty", label)
+	}
+	if _, exists := seenNames[step.Name]; exists {
+		return fmt.Errorf("step %q is duplicated", step.Name)
+	}
+	seenNames[step.Name] = struct{}{}
+	if step.Command == nil && !step.IsParallel() {
+		return fmt.Errorf("step %q command is nil", step.Name)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package saga
+
+import (
+	"slices"
+	"time"
+
+	gerrors "gochen/errors"
+	"gochen/policy/retry"
+)
+
+// Backoff 定义步骤重试的指数退避参数。
+type Backoff struct {
+	// Initial 首次重试前的等待时间。
+	Initial time.Duration
+	// Max 单次等待上限；为 0 时等于 Initial。
+	Max time.Duration
+	// Multiplier 退避倍数；<=0 时使用 2.0。
+	Multiplier float64
+	// JitterRatio 抖动比例（0~1），用于避免多实例同时重试。
+	JitterRatio float64
+}
+
+// RetryPolicy 定义步骤正向命令失败时的重试策略。
+//
+// 重试耗尽（或错误不可重试）后才进入补偿流程。
+// 每次尝试都会重新调用 SagaStep.Command 生成命令，因此命令需要幂等。
+type RetryPolicy struct {
+	// MaxAttempts 最大尝试次数（包括首次）；<=1 表示不重试。
+	MaxAttempts int
+
+	// Backoff 重试间的退避策略。
+	Backoff Backoff
+
+	// RetryableErrors 可重试的错误码；为空时使用 retry.IsRetryable 的默认判断
+	// （context 取消/超时不重试，其余错误均重试）。
+	RetryableErrors []gerrors.ErrorCode
+}
+
+// DefaultRetryPolicy 返回适用于瞬时故障（网络抖动、依赖暂不可用）的默认重试策略。
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		Backoff: Backoff{
+			Initial:    100 * time.Millisecond,
+			Max:        2 * time.Second,
+			Multiplier: 2.0,
+		},
+		RetryableErrors: []gerrors.ErrorCode{gerrors.Timeout, gerrors.ServiceUnavailable, gerrors.Dependency},
+	}
+}
+
+// IsRetryable 判断错误是否允许按该策略重试。
+func (p RetryPolicy) IsRetryable(err error) bool {
+	if !retry.IsRetryable(err) {
+		return false
+	}
+	if len(p.RetryableErrors) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(p.RetryableErrors, func(code gerrors.ErrorCode) bool {
+		return gerrors.Is(err, code)
+	})
+}
+
+func (p RetryPolicy) retryConfig() retry.Config {
+	return retry.Config{
+		MaxAttempts:   p.MaxAttempts,
+		InitialDelay:  p.Backoff.Initial,
+		BackoffFactor: p.Backoff.Multiplier,
+		MaxDelay:      p.Backoff.Max,
+		JitterRatio:   p.Backoff.JitterRatio,
+		RetryIf:       p.IsRetryable,
+	}
+}
@@ -0,0 +1,98 @@
+package saga
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+	"gochen/messaging/command"
+)
+
+func newRetrySaga(id string, policy RetryPolicy) *resumeSaga {
+	saga := &resumeSaga{id: id}
+	saga.steps = []*SagaStep{
+		NewSagaStep("reserve", func(ctx context.Context) (*command.Command, error) {
+			return command.NewCommand("reserve-"+id, "Reserve", "1", "Order", nil), nil
+		}).WithCompensation(func(ctx context.Context) (*command.Command, error) {
+			return command.NewCommand("release-"+id, "Release", "1", "Order", nil), nil
+		}),
+		NewSagaStep("charge", func(ctx context.Context) (*command.Command, error) {
+			return command.NewCommand("charge-"+id, "Charge", "1", "Order", nil), nil
+		}).WithRetryPolicy(policy),
+	}
+	return saga
+}
+
+// TestSagaOrchestrator_StepRetry_RecoversFromTransientFailure 验证瞬时失败在重试后成功，不触发补偿。
+func TestSagaOrchestrator_StepRetry_RecoversFromTransientFailure(t *testing.T) {
+	ctx := context.Background()
+	cmdExecutor := newTestCommandExecutor()
+	var charges, releases int
+	require.NoError(t, cmdExecutor.RegisterHandler("Reserve", func(ctx context.Context, cmd *command.Command) error { return nil }))
+	require.NoError(t, cmdExecutor.RegisterHandler("Release", func(ctx context.Context, cmd *command.Command) error {
+		releases++
+		return nil
+	}))
+	require.NoError(t, cmdExecutor.RegisterHandler("Charge", func(ctx context.Context, cmd *command.Command) error {
+		charges++
+		if charges < 3 {
+			return errors.NewCode(errors.ServiceUnavailable, "payment gateway unavailable")
+		}
+		return nil
+	}))
+
+	mockBus := &mockSagaEventBus{}
+	orchestrator := NewSagaOrchestrator(cmdExecutor, mockBus, NewMemorySagaStateStore())
+	saga := newRetrySaga("retry-ok", RetryPolicy{
+		MaxAttempts:     3,
+		RetryableErrors: []errors.ErrorCode{errors.ServiceUnavailable},
+	})
+
+	require.NoError(t, orchestrator.Execute(ctx, saga))
+	require.Equal(t, 3, charges)
+	require.Zero(t, releases)
+	require.Equal(t, 1, saga.completedCall)
+	require.Equal(t, 2, countSagaEventTypes(mockBus.events)[EventSagaStepRetrying.String()])
+}
+
+// TestSagaOrchestrator_StepRetry_CompensatesWhenExhaustedOrNotRetryable 验证重试耗尽或错误不可重试时进入补偿。
+func TestSagaOrchestrator_StepRetry_CompensatesWhenExhaustedOrNotRetryable(t *testing.T) {
+	cases := []struct {
+		name        string
+		err         error
+		wantCharges int
+	}{
+		{name: "exhausted", err: errors.NewCode(errors.Timeout, "gateway timeout"), wantCharges: 2},
+		{name: "not retryable", err: errors.NewCode(errors.Validation, "card declined"), wantCharges: 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			cmdExecutor := newTestCommandExecutor()
+			var charges, releases int
+			require.NoError(t, cmdExecutor.RegisterHandler("Reserve", func(ctx context.Context, cmd *command.Command) error { return nil }))
+			require.NoError(t, cmdExecutor.RegisterHandler("Release", func(ctx context.Context, cmd *command.Command) error {
+				releases++
+				return nil
+			}))
+			require.NoError(t, cmdExecutor.RegisterHandler("Charge", func(ctx context.Context, cmd *command.Command) error {
+				charges++
+				return tc.err
+			}))
+
+			orchestrator := NewSagaOrchestrator(cmdExecutor, &mockSagaEventBus{}, NewMemorySagaStateStore())
+			saga := newRetrySaga("retry-"+tc.name, RetryPolicy{
+				MaxAttempts:     2,
+				RetryableErrors: []errors.ErrorCode{errors.Timeout},
+			})
+
+			err := orchestrator.Execute(ctx, saga)
+			require.Error(t, err)
+			require.Equal(t, tc.wantCharges, charges)
+			require.Equal(t, 1, releases)
+			require.Equal(t, 1, saga.failedCall)
+		})
+	}
+}
@@ -12,6 +12,7 @@ package saga
 
 import (
 	"context"
+	"fmt"
 
 	"gochen/messaging/command"
 )
@@ -54,16 +55,40 @@ type SagaStep struct {
 
 	// OnFailure 步骤失败时的回调（可选）
 	//
-	// 可用于记录错误、发送告警等。
+	// 可用于记录错误、发送告警等。配置了重试时仅在最终失败后调用一次。
 	OnFailure StepCallback
+
+	// RetryPolicy 正向命令失败时的重试策略（可选）
+	//
+	// 为 nil 时失败立即触发补偿；补偿命令不参与重试。
+	RetryPolicy *RetryPolicy
+
+	// Condition 执行条件（可选）
+	//
+	// 返回 false 时步骤被跳过：不执行命令，后续失败时也不补偿。
+	Condition StepCondition
+
+	// Parallel 并行分支（可选）
+	//
+	// 非空时该步骤为并行组（通过 NewParallelStep 创建）：分支并发执行，全部成功才算步骤完成；
+	// 任一分支失败时先补偿已成功的分支，再按步骤失败处理。并行组自身不定义 Command/Compensation。
+	Parallel []*SagaStep
 }
 
 // CommandFunc 根据当前上下文构造要执行的命令。
 type CommandFunc func(ctx context.Context) (*command.Command, error)
 
+// StepCondition 根据 Saga 当前状态（只读副本）判断是否执行步骤。
+type StepCondition func(ctx context.Context, state *SagaState) (bool, error)
+
 // StepCallback 定义步骤成功或失败时触发的回调。
 type StepCallback func(ctx context.Context, stepName string, err error) error
 
+// TypeName 返回 Saga 实例的类型名，即编排器写入 SagaState.SagaType 的值。
+func TypeName(saga ISaga) string {
+	return fmt.Sprintf("%T", saga)
+}
+
 // BaseSaga 为可选回调提供默认空实现。
 type BaseSaga struct{}
 
@@ -85,6 +110,16 @@ func NewSagaStep(name string, commandFunc CommandFunc) *SagaStep {
 	}
 }
 
+// NewParallelStep 创建并行步骤组；分支必须是普通步骤（不支持嵌套并行组）。
+//
+// 分支的回调可能被并发调用，需要自行保证并发安全。
+func NewParallelStep(name string, branches ...*SagaStep) *SagaStep {
+	return &SagaStep{
+		Name:     name,
+		Parallel: branches,
+	}
+}
+
 // WithCompensation 为步骤补充补偿命令生成函数。
 func (s *SagaStep) WithCompensation(compensationFunc CommandFunc) *SagaStep {
 	s.Compensation = compensationFunc
@@ -103,7 +138,32 @@ func (s *SagaStep) WithOnFailure(callback StepCallback) *SagaStep {
 	return s
 }
 
-// HasCompensation 判断当前步骤是否定义了补偿逻辑。
+// WithRetryPolicy 为步骤补充正向命令重试策略。
+func (s *SagaStep) WithRetryPolicy(policy RetryPolicy) *SagaStep {
+	s.RetryPolicy = &policy
+	return s
+}
+
+// WithCondition 为步骤补充执行条件。
+func (s *SagaStep) WithCondition(condition StepCondition) *SagaStep {
+	s.Condition = condition
+	return s
+}
+
+// IsParallel 判断当前步骤是否为并行组。
+func (s *SagaStep) IsParallel() bool {
+	return len(s.Parallel) > 0
+}
+
+// HasCompensation 判断当前步骤是否定义了补偿逻辑（并行组看任一分支）。
 func (s *SagaStep) HasCompensation() bool {
-	return s.Compensation != nil
+	if s.Compensation != nil {
+		return true
+	}
+	for _, branch := range s.Parallel {
+		if branch != nil && branch.Compensation != nil {
+			return true
+		}
+	}
+	return false
 }
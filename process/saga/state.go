@@ -2,6 +2,7 @@ package saga
 
 import (
 	"encoding/json"
+	"slices"
 	"time"
 
 	"gochen/clock"
@@ -49,6 +50,9 @@ type SagaState struct {
 	// CompletedSteps 已完成的步骤名称列表
 	CompletedSteps []string `json:"completed_steps" db:"completed_steps"`
 
+	// SkippedSteps 因执行条件不满足而跳过的步骤/分支名称（跳过的步骤不参与补偿）
+	SkippedSteps []string `json:"skipped_steps,omitempty" db:"skipped_steps"`
+
 	// FailedStep 失败的步骤名称
 	FailedStep string `json:"failed_step,omitempty" db:"failed_step"`
 
@@ -110,6 +114,17 @@ func (s *SagaState) MarkStepCompleted(stepName string) {
 	s.UpdatedAt = s.now()
 }
 
+// MarkStepSkipped 记录某个步骤或并行分支因条件不满足被跳过；不推进当前步骤索引。
+func (s *SagaState) MarkStepSkipped(stepName string) {
+	s.SkippedSteps = append(s.SkippedSteps, stepName)
+	s.UpdatedAt = s.now()
+}
+
+// IsStepSkipped 判断某个步骤或并行分支是否被跳过。
+func (s *SagaState) IsStepSkipped(stepName string) bool {
+	return slices.Contains(s.SkippedSteps, stepName)
+}
+
 // MarkStepFailed 记录某个步骤失败，并把 Saga 标记为 failed。
 func (s *SagaState) MarkStepFailed(stepName string, err error) {
 	s.FailedStep = stepName
@@ -195,6 +210,7 @@ func (s *SagaState) Clone() *SagaState {
 	// 克隆 CompletedSteps
 	clone.CompletedSteps = make([]string, len(s.CompletedSteps))
 	copy(clone.CompletedSteps, s.CompletedSteps)
+	clone.SkippedSteps = slices.Clone(s.SkippedSteps)
 
 	// 克隆 Data
 	if s.Data != nil {
@@ -0,0 +1,281 @@
+package retention
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/scheduling"
+)
+
+// DefaultInterval 是 Config.Interval 的默认值。
+const DefaultInterval = time.Hour
+
+// Config 保留引擎配置。
+type Config struct {
+	// Interval 后台执行间隔（默认：DefaultInterval）。
+	Interval time.Duration
+
+	// Elector 可选：只有领导者实例执行清理；为空表示单实例部署，总是执行。
+	Elector scheduling.ILeaderElector
+
+	Clock  clock.IClock
+	Logger logging.ILogger
+}
+
+type registeredPolicy struct {
+	policy Policy
+	target ITarget
+}
+
+// Engine 数据保留引擎：按注册的策略周期清理各表。
+type Engine struct {
+	config Config
+	clock  clock.IClock
+	logger logging.ILogger
+
+	policiesMu sync.RWMutex
+	policies   map[string]registeredPolicy
+
+	// runMu 串行化 RunOnce，避免后台周期与手动触发并发清理同一张表。
+	runMu sync.Mutex
+
+	mu        sync.Mutex
+	started   bool
+	stopped   bool
+	runCancel context.CancelFunc
+	doneCh    chan struct{}
+}
+
+// NewEngine 创建保留引擎；cfg 为 nil 时使用默认配置。
+func NewEngine(cfg *Config) *Engine {
+	config := Config{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.Clock == nil {
+		config.Clock = clock.NewRealClock()
+	}
+	if config.Logger == nil {
+		config.Logger = logging.ComponentLogger("retention")
+	}
+	return &Engine{
+		config:   config,
+		clock:    config.Clock,
+		logger:   config.Logger,
+		policies: make(map[string]registeredPolicy),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// Register 注册策略及其目标；策略名重复时返回 errors.Conflict，
+// 设置 MaxCount 但目标未实现 ICountTarget 时返回 errors.Unsupported。
+func (e *Engine) Register(policy Policy, target ITarget) error {
+	if policy.Name == "" {
+		return errors.NewCode(errors.InvalidInput, "retention policy name cannot be empty")
+	}
+	if target == nil {
+		return errors.NewCode(errors.InvalidInput, "retention target cannot be nil").WithContext("policy", policy.Name)
+	}
+	if policy.MaxAge < 0 || policy.MaxCount < 0 {
+		return errors.NewCode(errors.InvalidInput, "retention limits cannot be negative").WithContext("policy", policy.Name)
+	}
+	if policy.MaxAge == 0 && policy.MaxCount == 0 {
+		return errors.NewCode(errors.InvalidInput, "retention policy requires MaxAge or MaxCount").WithContext("policy", policy.Name)
+	}
+	if policy.MaxCount > 0 {
+		if _, ok := target.(ICountTarget); !ok {
+			return errors.NewCode(errors.Unsupported, "retention target does not support MaxCount").WithContext("policy", policy.Name)
+		}
+	}
+
+	e.policiesMu.Lock()
+	defer e.policiesMu.Unlock()
+	if _, exists := e.policies[policy.Name]; exists {
+		return errors.NewCode(errors.Conflict, "retention policy already registered").WithContext("policy", policy.Name)
+	}
+	e.policies[policy.Name] = registeredPolicy{policy: policy, target: target}
+	return nil
+}
+
+// Policies 返回已注册的策略（按名称排序）。
+func (e *Engine) Policies() []Policy {
+	e.policiesMu.RLock()
+	defer e.policiesMu.RUnlock()
+	policies := make([]Policy, 0, len(e.policies))
+	for _, rp := range e.policies {
+		policies = append(policies, rp.policy)
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].Name < policies[j].Name })
+	return policies
+}
+
+// RunOnce 立即按所有策略执行一轮清理（后台循环每个周期调用一次）。
+//
+// 配置 Elector 且当前实例不是领导者时不执行任何策略，返回空报告。
+// 单个策略失败不影响其他策略，所有错误合并返回。
+func (e *Engine) RunOnce(ctx context.Context) ([]Report, error) {
+	if ctx == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	e.runMu.Lock()
+	defer e.runMu.Unlock()
+
+	if e.config.Elector != nil {
+		leader, err := e.config.Elector.TryLead(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.Dependency, "retention leader election failed")
+		}
+		if !leader {
+			return nil, nil
+		}
+	}
+
+	e.policiesMu.RLock()
+	policies := make([]registeredPolicy, 0, len(e.policies))
+	for _, rp := range e.policies {
+		policies = append(policies, rp)
+	}
+	e.policiesMu.RUnlock()
+	sort.Slice(policies, func(i, j int) bool { return policies[i].policy.Name < policies[j].policy.Name })
+
+	reports := make([]Report, 0, len(policies))
+	var errs []error
+	for _, rp := range policies {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+		report := e.apply(ctx, rp)
+		reports = append(reports, report)
+		if report.Err != nil {
+			errs = append(errs, report.Err)
+		}
+	}
+	return reports, errors.Join(errs...)
+}
+
+// apply 执行单个策略：先按时间、再按条数清理。
+func (e *Engine) apply(ctx context.Context, rp registeredPolicy) Report {
+	started := e.clock.Now()
+	report := Report{Policy: rp.policy.Name}
+	if rp.policy.MaxAge > 0 {
+		report.Cutoff = started.Add(-rp.policy.MaxAge)
+		pruned, err := rp.target.PruneBefore(ctx, report.Cutoff)
+		report.Pruned += pruned
+		if err != nil {
+			report.Err = errors.Wrap(err, errors.Code(err), "retention prune by age failed").
+				WithContext("policy", rp.policy.Name).
+				WithContext("cutoff", report.Cutoff)
+		}
+	}
+	if report.Err == nil && rp.policy.MaxCount > 0 {
+		pruned, err := rp.target.(ICountTarget).PruneKeepLast(ctx, rp.policy.MaxCount)
+		report.Pruned += pruned
+		if err != nil {
+			report.Err = errors.Wrap(err, errors.Code(err), "retention prune by count failed").
+				WithContext("policy", rp.policy.Name).
+				WithContext("max_count", rp.policy.MaxCount)
+		}
+	}
+	report.Duration = e.clock.Now().Sub(started)
+
+	if report.Err != nil {
+		e.logger.Error(ctx, "retention policy failed",
+			logging.String("policy", report.Policy),
+			logging.Int64("pruned_count", report.Pruned),
+			logging.Error(report.Err))
+	} else if report.Pruned > 0 {
+		e.logger.Info(ctx, "retention policy applied",
+			logging.String("policy", report.Policy),
+			logging.Int64("pruned_count", report.Pruned),
+			logging.Duration("duration", report.Duration))
+	}
+	return report
+}
+
+// Start 启动后台循环，按 Config.Interval 周期执行 RunOnce；重复调用为空操作。
+func (e *Engine) Start(ctx context.Context) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	ticker, err := e.clock.NewTicker(e.config.Interval)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	if e.stopped {
+		e.mu.Unlock()
+		ticker.Stop()
+		return errors.NewCode(errors.InvalidInput, "retention engine has been stopped; create a new instance")
+	}
+	if e.started {
+		e.mu.Unlock()
+		ticker.Stop()
+		return nil
+	}
+	e.started = true
+	runCtx, cancel := context.WithCancel(ctx)
+	e.runCancel = cancel
+	e.mu.Unlock()
+
+	go e.loop(runCtx, ticker)
+	return nil
+}
+
+// Stop 停止后台循环，等待当前一轮清理收尾，并在持有领导权时主动放弃。
+func (e *Engine) Stop(ctx context.Context) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	e.mu.Lock()
+	if !e.started || e.stopped {
+		e.mu.Unlock()
+		return nil
+	}
+	e.stopped = true
+	cancel := e.runCancel
+	e.runCancel = nil
+	e.mu.Unlock()
+
+	cancel()
+	select {
+	case <-e.doneCh:
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.NewCode(errors.Timeout, "retention engine stop timeout")
+		}
+		return ctx.Err()
+	}
+
+	if e.config.Elector != nil {
+		if err := e.config.Elector.Resign(ctx); err != nil {
+			return errors.Wrap(err, errors.Dependency, "resign retention leadership failed")
+		}
+	}
+	return nil
+}
+
+func (e *Engine) loop(ctx context.Context, ticker clock.ITicker) {
+	defer func() {
+		ticker.Stop()
+		close(e.doneCh)
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			if _, err := e.RunOnce(ctx); err != nil && ctx.Err() == nil {
+				e.logger.Error(ctx, "retention run failed", logging.Error(err))
+			}
+		}
+	}
+}
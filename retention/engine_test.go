@@ -0,0 +1,143 @@
+package retention
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/audit"
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/store"
+	"gochen/logging"
+)
+
+var testStart = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type stubElector struct{ leader bool }
+
+func (e *stubElector) TryLead(context.Context) (bool, error) { return e.leader, nil }
+func (e *stubElector) Resign(context.Context) error          { return nil }
+
+func newTestEngine(clk clock.IClock, elector *stubElector) *Engine {
+	cfg := &Config{Interval: time.Minute, Clock: clk, Logger: logging.NewNoopLogger()}
+	if elector != nil {
+		cfg.Elector = elector
+	}
+	return NewEngine(cfg)
+}
+
+func recordAt(t *testing.T, s *audit.MemoryStore, id string, at time.Time) {
+	t.Helper()
+	require.NoError(t, s.Record(context.Background(), audit.Record{ID: id, CommandName: "Cmd", StartedAt: at}))
+}
+
+func TestEngine_RunOnce_AppliesAgeAndCountPolicies(t *testing.T) {
+	ctx := context.Background()
+	// 内存事件存储以真实时间为墓碑事件打时间戳，时钟从当前时间开始。
+	start := time.Now()
+	clk := clock.NewManualClock(start)
+	engine := newTestEngine(clk, nil)
+
+	audits := audit.NewMemoryStore(0)
+	for i := range 5 {
+		recordAt(t, audits, string(rune('a'+i)), start.Add(time.Duration(i-4)*24*time.Hour))
+	}
+	events := store.NewMemoryEventStore()
+	require.NoError(t, events.AppendEvents(ctx, 1, []eventing.IStorableEvent[int64]{eventing.NewEvent[int64](1, "Order", "Created", 1, nil)}, 0))
+	require.NoError(t, events.DeleteAggregate(ctx, 1, store.DeleteOptions{AggregateType: "Order", ExpectedVersion: 1}))
+
+	require.NoError(t, engine.Register(Policy{Name: "audit_records", MaxAge: 72 * time.Hour, MaxCount: 2}, audits))
+	require.NoError(t, engine.Register(Policy{Name: "event_store", MaxAge: time.Hour}, Tombstones(events)))
+
+	reports, err := engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	require.Equal(t, "audit_records", reports[0].Policy)
+	require.Equal(t, start.Add(-72*time.Hour), reports[0].Cutoff)
+	require.Equal(t, int64(3), reports[0].Pruned, "one record is older than MaxAge, two more exceed MaxCount")
+	require.Zero(t, reports[1].Pruned, "tombstones newer than MaxAge are kept")
+
+	remaining, err := audits.Query(ctx, audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, remaining, 2)
+	require.Equal(t, "e", remaining[0].ID)
+
+	clk.Advance(2 * time.Hour)
+	reports, err = engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Zero(t, reports[0].Pruned)
+	require.Equal(t, int64(2), reports[1].Pruned, "the tombstoned stream is purged together with its marker")
+	count, err := events.CountEvents(ctx, 1)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func TestEngine_Register_Validation(t *testing.T) {
+	engine := newTestEngine(clock.NewManualClock(testStart), nil)
+	noop := PruneFunc(func(context.Context, time.Time) (int64, error) { return 0, nil })
+
+	require.True(t, errors.Is(engine.Register(Policy{MaxAge: time.Hour}, noop), errors.InvalidInput))
+	require.True(t, errors.Is(engine.Register(Policy{Name: "t"}, noop), errors.InvalidInput))
+	require.True(t, errors.Is(engine.Register(Policy{Name: "t", MaxAge: time.Hour}, nil), errors.InvalidInput))
+	require.True(t, errors.Is(engine.Register(Policy{Name: "t", MaxCount: 10}, noop), errors.Unsupported))
+	require.NoError(t, engine.Register(Policy{Name: "t", MaxAge: time.Hour}, noop))
+	require.True(t, errors.Is(engine.Register(Policy{Name: "t", MaxAge: time.Hour}, noop), errors.Conflict))
+	require.Equal(t, []Policy{{Name: "t", MaxAge: time.Hour}}, engine.Policies())
+}
+
+func TestEngine_RunOnce_IsolatesFailuresAndRespectsLeadership(t *testing.T) {
+	ctx := context.Background()
+	elector := &stubElector{leader: false}
+	engine := newTestEngine(clock.NewManualClock(testStart), elector)
+
+	var calls int
+	require.NoError(t, engine.Register(Policy{Name: "a_broken", MaxAge: time.Hour}, PruneFunc(func(context.Context, time.Time) (int64, error) {
+		return 0, errors.NewCode(errors.Database, "table locked")
+	})))
+	require.NoError(t, engine.Register(Policy{Name: "b_healthy", MaxAge: time.Hour}, PruneFunc(func(context.Context, time.Time) (int64, error) {
+		calls++
+		return 4, nil
+	})))
+
+	reports, err := engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Empty(t, reports, "followers do not prune")
+
+	elector.leader = true
+	reports, err = engine.RunOnce(ctx)
+	require.True(t, errors.Is(err, errors.Database))
+	require.Len(t, reports, 2)
+	require.Error(t, reports[0].Err)
+	require.Equal(t, int64(4), reports[1].Pruned)
+	require.Equal(t, 1, calls)
+}
+
+func TestEngine_StartStop_RunsOnInterval(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewManualClock(testStart)
+	engine := newTestEngine(clk, nil)
+
+	ran := make(chan time.Time, 4)
+	require.NoError(t, engine.Register(Policy{Name: "outbox", MaxAge: time.Hour}, PruneFunc(func(_ context.Context, cutoff time.Time) (int64, error) {
+		ran <- cutoff
+		return 0, nil
+	})))
+	require.NoError(t, engine.Start(ctx))
+
+	clk.Advance(time.Minute)
+	select {
+	case cutoff := <-ran:
+		require.Equal(t, testStart.Add(time.Minute-time.Hour), cutoff)
+	case <-time.After(5 * time.Second):
+		t.Fatal("retention loop did not run")
+	}
+
+	stopCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	require.NoError(t, engine.Stop(stopCtx))
+	require.True(t, errors.Is(engine.Start(ctx), errors.InvalidInput), "a stopped engine cannot be restarted")
+}
@@ -0,0 +1,61 @@
+// Package retention 提供数据保留策略引擎：按表（或一类数据）声明保留期与保留条数，由后台任务统一清理或归档。
+//
+// 组成：
+//   - Policy：单张表的保留策略（MaxAge 按时间、MaxCount 按条数）；
+//   - ITarget / ICountTarget：被清理的数据源，按时间清理为基础能力，按条数清理为可选能力；
+//   - Engine：注册策略并周期执行（RunOnce / Start / Stop），可选领导者选举保证多实例只有一个执行；
+//   - 内置目标：Outbox（已发布记录）、Tombstones（已软删除的事件流），
+//     outbox.CleanupService、audit.MemoryStore 与 audit/sqlstore.Store 直接实现 ITarget。
+//
+// 是删除还是归档由目标决定（如 outbox.CleanupService 在 ArchiveEnabled 时迁移到归档表），
+// 引擎只负责计算截止条件与调度。
+package retention
+
+import (
+	"context"
+	"time"
+)
+
+// Policy 单张表（或一类数据）的保留策略；MaxAge 与 MaxCount 至少设置一项，同时设置时依次执行。
+type Policy struct {
+	// Name 策略名（通常为表名），同一 Engine 内唯一，用于日志与执行报告。
+	Name string
+
+	// MaxAge 大于 0 时清理早于 now-MaxAge 的记录。
+	MaxAge time.Duration
+
+	// MaxCount 大于 0 时只保留最近 MaxCount 条记录；目标需实现 ICountTarget。
+	MaxCount int64
+}
+
+// ITarget 可按时间清理的数据源。
+type ITarget interface {
+	// PruneBefore 删除（或归档）早于 cutoff 的记录，返回处理的记录数；无法统计时返回 0。
+	PruneBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// ICountTarget 额外支持按条数清理的数据源。
+type ICountTarget interface {
+	ITarget
+	// PruneKeepLast 只保留最近 keep 条记录，返回处理的记录数。
+	PruneKeepLast(ctx context.Context, keep int64) (int64, error)
+}
+
+// PruneFunc 是 ITarget 的函数适配器。
+type PruneFunc func(ctx context.Context, cutoff time.Time) (int64, error)
+
+// PruneBefore 调用函数本身。
+func (f PruneFunc) PruneBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return f(ctx, cutoff)
+}
+
+// Report 单个策略一次执行的结果。
+type Report struct {
+	Policy string
+	// Cutoff 按时间清理的截止时间；未设置 MaxAge 时为零值。
+	Cutoff time.Time
+	// Pruned 删除或归档的记录数（按时间与按条数之和）。
+	Pruned   int64
+	Duration time.Duration
+	Err      error
+}
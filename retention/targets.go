@@ -0,0 +1,36 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	"gochen/audit"
+	auditstore "gochen/audit/sqlstore"
+	"gochen/eventing/outbox"
+	"gochen/eventing/store"
+)
+
+// Outbox 把 Outbox 仓储的 DeletePublished 适配为 ITarget（仓储不返回删除条数，报告中计为 0）。
+//
+// 需要归档或分批删除时改用 outbox.CleanupService（直接实现 ITarget）。
+// 交给引擎清理时应设置 OutboxConfig.DisableCleanup，避免发布器按自身的保留期重复清理。
+func Outbox[ID comparable](repo outbox.IOutboxRepository[ID]) ITarget {
+	return PruneFunc(func(ctx context.Context, cutoff time.Time) (int64, error) {
+		return 0, repo.DeletePublished(ctx, cutoff)
+	})
+}
+
+// Tombstones 把事件存储的 PurgeTombstoned 适配为 ITarget：软删除时间早于截止时间的事件流被物理删除。
+//
+// 未软删除的事件流不受保留策略影响（事件溯源的事件流是聚合状态的唯一来源）；
+// 冷数据归档请使用 eventing/archive。
+func Tombstones(purger store.ITombstonePurger) ITarget {
+	return PruneFunc(purger.PurgeTombstoned)
+}
+
+// 编译期断言：内置清理服务与审计存储直接实现 ITarget。
+var (
+	_ ITarget      = (*outbox.CleanupService)(nil)
+	_ ICountTarget = (*audit.MemoryStore)(nil)
+	_ ITarget      = (*auditstore.Store)(nil)
+)
@@ -0,0 +1,22 @@
+package scheduling
+
+import (
+	"context"
+
+	"gochen/messaging"
+	"gochen/messaging/command"
+)
+
+// CommandBuilder 返回发布固定命令的 BuildFunc，命令 ID 为 Run.ID。
+func CommandBuilder(commandType, aggregateID, aggregateType string, payload any) BuildFunc {
+	return func(ctx context.Context, run Run) (messaging.IMessage, error) {
+		return command.NewCommand(run.ID, commandType, aggregateID, aggregateType, payload), nil
+	}
+}
+
+// MessageBuilder 返回发布固定消息的 BuildFunc，消息 ID 为 Run.ID。
+func MessageBuilder(kind messaging.MessageKind, messageType string, payload any) BuildFunc {
+	return func(ctx context.Context, run Run) (messaging.IMessage, error) {
+		return messaging.NewMessage(run.ID, kind, messageType, payload), nil
+	}
+}
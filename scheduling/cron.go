@@ -0,0 +1,233 @@
+package scheduling
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"gochen/errors"
+)
+
+// ISchedule 计算周期任务的触发时间。
+type ISchedule interface {
+	// Next 返回严格晚于 after 的下一次触发时间；不会再触发时返回零值。
+	Next(after time.Time) time.Time
+}
+
+// cronSearchYears 是搜索下一次触发时间的年份上限（如 "0 0 30 2 *" 永远不会触发）。
+const cronSearchYears = 5
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	monthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	weekdayNames = map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}
+)
+
+type cronField struct {
+	name   string
+	min    int
+	max    int
+	names  map[string]int
+	bitset uint64
+	star   bool
+}
+
+// cronSchedule 是标准 5 字段 cron 表达式（分 时 日 月 周）。
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar / dowStar：日与周字段均受限时按“或”匹配（与 crontab 一致），否则按“与”匹配。
+	domStar, dowStar bool
+	location         *time.Location
+}
+
+// everySchedule 是 "@every <duration>" 固定间隔调度，触发点对齐 Unix 纪元，多实例计算结果一致。
+type everySchedule struct {
+	interval time.Duration
+}
+
+// ParseSpec 解析调度表达式；loc 为 nil 时按 UTC 计算。
+//
+// 支持：
+//   - 标准 5 字段 cron：`分 时 日 月 周`，字段支持 `*`、`a`、`a-b`、`*/n`、`a-b/n`、逗号列表，
+//     月份与星期支持英文缩写（JAN..DEC、SUN..SAT），星期 7 等同于 0（周日）；
+//   - 预定义：@yearly、@annually、@monthly、@weekly、@daily、@midnight、@hourly；
+//   - 固定间隔：`@every 30s`（最小 1s，按 Unix 纪元对齐）。
+func ParseSpec(spec string, loc *time.Location) (ISchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "schedule spec cannot be empty")
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, errors.Wrap(err, errors.InvalidInput, "invalid @every interval").WithContext("spec", spec)
+		}
+		interval = interval.Truncate(time.Second)
+		if interval < time.Second {
+			return nil, errors.NewCode(errors.InvalidInput, "@every interval must be at least 1s").WithContext("spec", spec)
+		}
+		return everySchedule{interval: interval}, nil
+	}
+	if expanded, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = expanded
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != 5 {
+		return nil, errors.NewCode(errors.InvalidInput, "cron spec must have 5 fields").
+			WithContext("spec", spec).
+			WithContext("fields", len(parts))
+	}
+	fields := []*cronField{
+		{name: "minute", min: 0, max: 59},
+		{name: "hour", min: 0, max: 23},
+		{name: "day_of_month", min: 1, max: 31},
+		{name: "month", min: 1, max: 12, names: monthNames},
+		{name: "day_of_week", min: 0, max: 7, names: weekdayNames},
+	}
+	for i, field := range fields {
+		if err := field.parse(parts[i]); err != nil {
+			return nil, errors.Wrap(err, errors.InvalidInput, "invalid cron field").
+				WithContext("spec", spec).
+				WithContext("field", field.name)
+		}
+	}
+	dow := fields[4].bitset
+	if dow&(1<<7) != 0 {
+		dow = dow&^(1<<7) | 1
+	}
+	return &cronSchedule{
+		minute:   fields[0].bitset,
+		hour:     fields[1].bitset,
+		dom:      fields[2].bitset,
+		month:    fields[3].bitset,
+		dow:      dow,
+		domStar:  fields[2].star,
+		dowStar:  fields[4].star,
+		location: loc,
+	}, nil
+}
+
+func (f *cronField) parse(expr string) error {
+	for part := range strings.SplitSeq(expr, ",") {
+		if err := f.parsePart(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *cronField) parsePart(part string) error {
+	rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+	step := 1
+	if hasStep {
+		n, err := strconv.Atoi(stepExpr)
+		if err != nil || n <= 0 {
+			return errors.NewCode(errors.InvalidInput, "invalid step").WithContext("expr", part)
+		}
+		step = n
+	}
+
+	lo, hi := f.min, f.max
+	switch {
+	case rangeExpr == "*":
+		if !hasStep {
+			f.star = true
+		}
+	default:
+		startExpr, endExpr, hasRange := strings.Cut(rangeExpr, "-")
+		start, err := f.value(startExpr)
+		if err != nil {
+			return err
+		}
+		lo, hi = start, start
+		if hasRange {
+			if hi, err = f.value(endExpr); err != nil {
+				return err
+			}
+		} else if hasStep {
+			// "a/n" 等价于 "a-max/n"。
+			hi = f.max
+		}
+		if lo > hi {
+			return errors.NewCode(errors.InvalidInput, "range start exceeds end").WithContext("expr", part)
+		}
+	}
+	for v := lo; v <= hi; v += step {
+		f.bitset |= 1 << uint(v)
+	}
+	return nil
+}
+
+func (f *cronField) value(expr string) (int, error) {
+	if v, ok := f.names[strings.ToUpper(expr)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(expr)
+	if err != nil || v < f.min || v > f.max {
+		return 0, errors.NewCode(errors.InvalidInput, "value out of range").
+			WithContext("expr", expr).
+			WithContext("min", f.min).
+			WithContext("max", f.max)
+	}
+	return v, nil
+}
+
+// Next 返回 after 之后下一个匹配的整分钟。
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	t := after.In(s.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + cronSearchYears
+	for t.Year() <= limit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.location)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.location)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.location)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Next 返回 after 之后下一个 interval 整数倍的时间点。
+func (s everySchedule) Next(after time.Time) time.Time {
+	step := s.interval.Nanoseconds()
+	n := after.UnixNano()/step + 1
+	return time.Unix(0, n*step).In(after.Location())
+}
@@ -0,0 +1,62 @@
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/errors"
+)
+
+func mustParse(t *testing.T, spec string, loc *time.Location) ISchedule {
+	t.Helper()
+	schedule, err := ParseSpec(spec, loc)
+	require.NoError(t, err)
+	return schedule
+}
+
+// TestParseSpec_Next 验证常见 cron 表达式的下一次触发时间。
+func TestParseSpec_Next(t *testing.T) {
+	base := time.Date(2026, 3, 14, 10, 17, 30, 0, time.UTC) // 周六
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 14, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 14, 10, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2026, 3, 14, 13, 0, 0, 0, time.UTC)},
+		{"30 2 * * MON-FRI", time.Date(2026, 3, 16, 2, 30, 0, 0, time.UTC)},
+		{"0 0 1 JAN,JUL *", time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 5", time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)}, // 日与周均受限时按“或”匹配
+		{"@daily", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 3, 14, 11, 0, 0, 0, time.UTC)},
+		{"@every 45s", time.Date(2026, 3, 14, 10, 18, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.spec, func(t *testing.T) {
+			require.Equal(t, tc.want, mustParse(t, tc.spec, nil).Next(base).UTC())
+		})
+	}
+}
+
+// TestParseSpec_Location 验证按任务时区计算触发时间。
+func TestParseSpec_Location(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	next := mustParse(t, "0 9 * * *", loc).Next(time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC))
+	require.Equal(t, time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC), next.UTC())
+}
+
+// TestParseSpec_NeverFires 验证不可能的日期返回零值。
+func TestParseSpec_NeverFires(t *testing.T) {
+	require.True(t, mustParse(t, "0 0 30 2 *", nil).Next(time.Now()).IsZero())
+}
+
+// TestParseSpec_Invalid 验证非法表达式返回 InvalidInput。
+func TestParseSpec_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * * 13 *", "*/0 * * * *", "5-1 * * * *", "@every 10ms", "@every soon"} {
+		_, err := ParseSpec(spec, nil)
+		require.True(t, errors.Is(err, errors.InvalidInput), spec)
+	}
+}
@@ -0,0 +1,153 @@
+package scheduling
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gochen/clock"
+	"gochen/db"
+	"gochen/db/sql/safeident"
+	"gochen/errors"
+)
+
+// DefaultLeaseTableName 是 SQLLeaderElector 的默认表名。
+const DefaultLeaseTableName = "scheduling_leases"
+
+// SQLLeaderElectorConfig 定义基于数据库租约的领导者选举配置。
+type SQLLeaderElectorConfig struct {
+	// TableName 租约表名（默认：scheduling_leases）。
+	TableName string
+
+	// LeaseName 租约名（默认：scheduling），同一租约名下只有一个领导者；不同调度器集群应使用不同租约名。
+	LeaseName string
+
+	// Owner 当前实例标识（必填，集群内唯一）。
+	Owner string
+
+	// TTL 租约时长（默认：15s），应明显大于调度器 PollInterval；领导者失联后最多 TTL 后被接管。
+	TTL time.Duration
+
+	Clock clock.IClock
+}
+
+// SQLLeaderElector 基于共享数据库表的租约式领导者选举。
+//
+// 每次 TryLead 在租约未过期且属于其他实例时失败，否则写入（续期）当前实例的租约。
+// 表结构（自动创建）：lease_name VARCHAR(191) PRIMARY KEY、owner VARCHAR(191)、expires_at_ms BIGINT。
+type SQLLeaderElector struct {
+	db        db.IDatabase
+	tableName string
+	leaseName string
+	owner     string
+	ttl       time.Duration
+	clock     clock.IClock
+
+	ensureMu sync.Mutex
+	ensured  bool
+}
+
+// NewSQLLeaderElector 创建基于数据库租约的领导者选举。
+func NewSQLLeaderElector(database db.IDatabase, cfg *SQLLeaderElectorConfig) (*SQLLeaderElector, error) {
+	if database == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "database cannot be nil")
+	}
+	config := SQLLeaderElectorConfig{}
+	if cfg != nil {
+		config = *cfg
+	}
+	config.TableName = strings.TrimSpace(config.TableName)
+	if config.TableName == "" {
+		config.TableName = DefaultLeaseTableName
+	}
+	if !safeident.IsSafeIdentifier(config.TableName) {
+		return nil, errors.NewCode(errors.InvalidInput, fmt.Sprintf("invalid table name: %s", config.TableName))
+	}
+	if config.LeaseName == "" {
+		config.LeaseName = "scheduling"
+	}
+	if strings.TrimSpace(config.Owner) == "" {
+		return nil, errors.NewCode(errors.InvalidInput, "owner cannot be empty")
+	}
+	if config.TTL <= 0 {
+		config.TTL = 15 * time.Second
+	}
+	if config.Clock == nil {
+		config.Clock = clock.NewRealClock()
+	}
+	return &SQLLeaderElector{
+		db:        database,
+		tableName: config.TableName,
+		leaseName: config.LeaseName,
+		owner:     config.Owner,
+		ttl:       config.TTL,
+		clock:     config.Clock,
+	}, nil
+}
+
+// ensureTable 首次使用时创建租约表；失败不缓存，下次调用重试。
+func (e *SQLLeaderElector) ensureTable(ctx context.Context) error {
+	e.ensureMu.Lock()
+	defer e.ensureMu.Unlock()
+	if e.ensured {
+		return nil
+	}
+	_, err := e.db.Exec(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+lease_name VARCHAR(191) PRIMARY KEY,
+owner VARCHAR(191) NOT NULL,
+expires_at_ms BIGINT NOT NULL
+)`, e.tableName))
+	if err != nil {
+		return errors.Wrap(err, errors.Database, "ensure lease table failed").WithContext("table", e.tableName)
+	}
+	e.ensured = true
+	return nil
+}
+
+// TryLead 获取或续期租约。
+func (e *SQLLeaderElector) TryLead(ctx context.Context) (bool, error) {
+	if err := e.ensureTable(ctx); err != nil {
+		return false, err
+	}
+	now := e.clock.Now().UnixMilli()
+	expires := now + e.ttl.Milliseconds()
+
+	// 续期自己的租约或接管已过期的租约。
+	res, err := e.db.Exec(ctx,
+		fmt.Sprintf("UPDATE %s SET owner = ?, expires_at_ms = ? WHERE lease_name = ? AND (owner = ? OR expires_at_ms <= ?)", e.tableName),
+		e.owner, expires, e.leaseName, e.owner, now,
+	)
+	if err != nil {
+		return false, errors.Wrap(err, errors.Database, "renew scheduling lease failed").WithContext("lease", e.leaseName)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected > 0 {
+		return true, nil
+	}
+
+	// 租约不存在时尝试创建；并发创建失败说明已被其他实例持有。
+	if _, err := e.db.Exec(ctx,
+		fmt.Sprintf("INSERT INTO %s (lease_name, owner, expires_at_ms) VALUES (?, ?, ?)", e.tableName),
+		e.leaseName, e.owner, expires,
+	); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+// Resign 释放当前实例持有的租约。
+func (e *SQLLeaderElector) Resign(ctx context.Context) error {
+	if err := e.ensureTable(ctx); err != nil {
+		return err
+	}
+	if _, err := e.db.Exec(ctx,
+		fmt.Sprintf("DELETE FROM %s WHERE lease_name = ? AND owner = ?", e.tableName),
+		e.leaseName, e.owner,
+	); err != nil {
+		return errors.Wrap(err, errors.Database, "release scheduling lease failed").WithContext("lease", e.leaseName)
+	}
+	return nil
+}
+
+var _ ILeaderElector = (*SQLLeaderElector)(nil)
@@ -0,0 +1,89 @@
+package scheduling
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"gochen/errors"
+)
+
+// MemoryHistoryStore 是 IHistoryStore 的内存实现（单进程/测试使用，进程重启后历史丢失）。
+type MemoryHistoryStore struct {
+	mu   sync.Mutex
+	runs map[string][]*Run
+}
+
+// NewMemoryHistoryStore 创建内存运行历史存储。
+func NewMemoryHistoryStore() *MemoryHistoryStore {
+	return &MemoryHistoryStore{runs: make(map[string][]*Run)}
+}
+
+// Record 保存运行记录。
+func (s *MemoryHistoryStore) Record(ctx context.Context, run *Run) error {
+	if run == nil || run.JobName == "" {
+		return errors.NewCode(errors.InvalidInput, "run is nil or has no job name")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.runs[run.JobName] {
+		if existing.ScheduledAt.Equal(run.ScheduledAt) {
+			return errors.NewCode(errors.Conflict, "scheduled run already recorded").
+				WithContext("job", run.JobName).
+				WithContext("run_id", run.ID)
+		}
+	}
+	copied := *run
+	runs := append(s.runs[run.JobName], &copied)
+	sort.Slice(runs, func(i, j int) bool { return runs[i].ScheduledAt.Before(runs[j].ScheduledAt) })
+	s.runs[run.JobName] = runs
+	return nil
+}
+
+// Finish 更新运行记录。
+func (s *MemoryHistoryStore) Finish(ctx context.Context, run *Run) error {
+	if run == nil {
+		return errors.NewCode(errors.InvalidInput, "run cannot be nil")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.runs[run.JobName] {
+		if existing.ScheduledAt.Equal(run.ScheduledAt) {
+			*existing = *run
+			return nil
+		}
+	}
+	return errors.NewCode(errors.NotFound, "scheduled run not found").
+		WithContext("job", run.JobName).
+		WithContext("run_id", run.ID)
+}
+
+// Last 返回计划时间最晚的运行记录。
+func (s *MemoryHistoryStore) Last(ctx context.Context, jobName string) (*Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	runs := s.runs[jobName]
+	if len(runs) == 0 {
+		return nil, nil
+	}
+	copied := *runs[len(runs)-1]
+	return &copied, nil
+}
+
+// List 按计划时间倒序返回最近的运行记录。
+func (s *MemoryHistoryStore) List(ctx context.Context, jobName string, limit int) ([]*Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	runs := s.runs[jobName]
+	if limit <= 0 || limit > len(runs) {
+		limit = len(runs)
+	}
+	result := make([]*Run, 0, limit)
+	for i := len(runs) - 1; i >= 0 && len(result) < limit; i-- {
+		copied := *runs[i]
+		result = append(result, &copied)
+	}
+	return result, nil
+}
+
+var _ IHistoryStore = (*MemoryHistoryStore)(nil)
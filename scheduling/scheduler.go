@@ -0,0 +1,433 @@
+package scheduling
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/logging"
+	"gochen/messaging"
+)
+
+// maxDueScan 是单个任务单次扫描计算的计划时间上限，防止长时间停机后的高频任务一次展开过多。
+const maxDueScan = 10000
+
+// Config 定义周期任务调度器配置。
+type Config struct {
+	// Instance 当前实例标识，写入运行历史便于排查。
+	Instance string
+
+	// PollInterval 扫描间隔（默认：1s）。
+	PollInterval time.Duration
+
+	// MisfireThreshold 计划时间早于当前时间超过该值即视为错过（默认：1m），按任务的 MisfirePolicy 处理。
+	MisfireThreshold time.Duration
+
+	// MaxCatchUp MisfireFireAll 单次最多补触发的次数（默认：100）。
+	MaxCatchUp int
+
+	// Elector 可选：只有领导者实例触发任务；为空表示单实例部署，总是触发。
+	Elector ILeaderElector
+
+	Clock  clock.IClock
+	Logger logging.ILogger
+}
+
+type registeredJob struct {
+	job      Job
+	schedule ISchedule
+	// baseline 没有运行历史时的起始游标（注册时间），避免新任务补触发注册前的计划时间。
+	baseline time.Time
+}
+
+// Scheduler 周期任务调度器。
+type Scheduler struct {
+	bus     messaging.IMessageBus
+	history IHistoryStore
+	config  Config
+	clock   clock.IClock
+	logger  logging.ILogger
+
+	jobsMu sync.RWMutex
+	jobs   map[string]*registeredJob
+
+	tickMu sync.Mutex
+	leader bool
+
+	mu        sync.Mutex
+	started   bool
+	stopped   bool
+	runCancel context.CancelFunc
+	doneCh    chan struct{}
+}
+
+// NewScheduler 创建周期任务调度器；cfg 为 nil 时使用默认配置。
+func NewScheduler(bus messaging.IMessageBus, history IHistoryStore, cfg *Config) (*Scheduler, error) {
+	if bus == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "message bus cannot be nil")
+	}
+	if history == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "history store cannot be nil")
+	}
+	config := Config{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	if config.MisfireThreshold <= 0 {
+		config.MisfireThreshold = time.Minute
+	}
+	if config.MaxCatchUp <= 0 {
+		config.MaxCatchUp = 100
+	}
+	if config.Clock == nil {
+		config.Clock = clock.NewRealClock()
+	}
+	if config.Logger == nil {
+		config.Logger = logging.ComponentLogger("scheduling")
+	}
+	if config.Instance != "" {
+		config.Logger = config.Logger.WithField("instance", config.Instance)
+	}
+	return &Scheduler{
+		bus:     bus,
+		history: history,
+		config:  config,
+		clock:   config.Clock,
+		logger:  config.Logger,
+		jobs:    make(map[string]*registeredJob),
+		doneCh:  make(chan struct{}),
+	}, nil
+}
+
+// Register 注册周期任务；任务名重复时返回 errors.Conflict。
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" {
+		return errors.NewCode(errors.InvalidInput, "job name cannot be empty")
+	}
+	if job.Build == nil {
+		return errors.NewCode(errors.InvalidInput, "job build func cannot be nil").WithContext("job", job.Name)
+	}
+	if job.Misfire < MisfireSkip || job.Misfire > MisfireFireAll {
+		return errors.NewCode(errors.InvalidInput, "unknown misfire policy").
+			WithContext("job", job.Name).
+			WithContext("misfire", int(job.Misfire))
+	}
+	schedule, err := ParseSpec(job.Spec, job.Location)
+	if err != nil {
+		return errors.Wrap(err, errors.InvalidInput, "invalid job spec").WithContext("job", job.Name)
+	}
+
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+	if _, exists := s.jobs[job.Name]; exists {
+		return errors.NewCode(errors.Conflict, "job already registered").WithContext("job", job.Name)
+	}
+	s.jobs[job.Name] = &registeredJob{job: job, schedule: schedule, baseline: s.clock.Now()}
+	return nil
+}
+
+// Jobs 返回已注册的任务名（按名称排序）。
+func (s *Scheduler) Jobs() []string {
+	s.jobsMu.RLock()
+	defer s.jobsMu.RUnlock()
+	names := make([]string, 0, len(s.jobs))
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NextRun 返回任务在 after 之后的下一次计划时间。
+func (s *Scheduler) NextRun(name string, after time.Time) (time.Time, error) {
+	s.jobsMu.RLock()
+	rj, ok := s.jobs[name]
+	s.jobsMu.RUnlock()
+	if !ok {
+		return time.Time{}, errors.NewCode(errors.NotFound, "job not found").WithContext("job", name)
+	}
+	return rj.schedule.Next(after), nil
+}
+
+// History 按计划时间倒序返回任务最近的运行记录。
+func (s *Scheduler) History(ctx context.Context, name string, limit int) ([]*Run, error) {
+	return s.history.List(ctx, name, limit)
+}
+
+// IsLeader 返回最近一次扫描时当前实例是否为领导者。
+func (s *Scheduler) IsLeader() bool {
+	s.tickMu.Lock()
+	defer s.tickMu.Unlock()
+	return s.leader
+}
+
+// Start 启动后台扫描循环；重复调用为 no-op，Stop 之后不可再次启动。
+func (s *Scheduler) Start(ctx context.Context) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	ticker, err := s.clock.NewTicker(s.config.PollInterval)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		ticker.Stop()
+		return errors.NewCode(errors.InvalidInput, "scheduler has been stopped; create a new instance")
+	}
+	if s.started {
+		s.mu.Unlock()
+		ticker.Stop()
+		return nil
+	}
+	s.started = true
+	runCtx, cancel := context.WithCancel(ctx)
+	s.runCancel = cancel
+	s.mu.Unlock()
+
+	go s.loop(runCtx, ticker)
+	return nil
+}
+
+// Stop 停止后台循环，等待当前一轮扫描收尾，并在持有领导权时主动放弃。
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if ctx == nil {
+		return errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	s.mu.Lock()
+	if !s.started || s.stopped {
+		s.mu.Unlock()
+		return nil
+	}
+	s.stopped = true
+	cancel := s.runCancel
+	s.runCancel = nil
+	s.mu.Unlock()
+
+	cancel()
+	select {
+	case <-s.doneCh:
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.NewCode(errors.Timeout, "scheduler stop timeout")
+		}
+		return ctx.Err()
+	}
+
+	s.tickMu.Lock()
+	defer s.tickMu.Unlock()
+	if s.leader && s.config.Elector != nil {
+		s.leader = false
+		if err := s.config.Elector.Resign(ctx); err != nil {
+			return errors.Wrap(err, errors.Dependency, "resign scheduler leadership failed")
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) loop(ctx context.Context, ticker clock.ITicker) {
+	defer func() {
+		ticker.Stop()
+		close(s.doneCh)
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			if _, err := s.Tick(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Error(ctx, "scheduling tick failed", logging.Error(err))
+			}
+		}
+	}
+}
+
+// Tick 执行一轮扫描：确认领导权后触发所有到期任务，返回成功发布的消息数（后台循环每个周期调用一次）。
+//
+// 单个任务的存储错误不影响其他任务，所有错误合并返回。
+func (s *Scheduler) Tick(ctx context.Context) (int, error) {
+	if ctx == nil {
+		return 0, errors.NewCode(errors.InvalidInput, "ctx is nil")
+	}
+	s.tickMu.Lock()
+	defer s.tickMu.Unlock()
+
+	if s.config.Elector != nil {
+		leader, err := s.config.Elector.TryLead(ctx)
+		if err != nil {
+			// 无法确认领导权时按非领导者处理，避免多实例同时触发。
+			s.leader = false
+			return 0, errors.Wrap(err, errors.Dependency, "scheduler leader election failed")
+		}
+		if leader != s.leader {
+			s.logger.Info(ctx, "scheduler leadership changed", logging.Bool("leader", leader))
+		}
+		s.leader = leader
+		if !leader {
+			return 0, nil
+		}
+	} else {
+		s.leader = true
+	}
+
+	s.jobsMu.RLock()
+	jobs := make([]*registeredJob, 0, len(s.jobs))
+	for _, rj := range s.jobs {
+		jobs = append(jobs, rj)
+	}
+	s.jobsMu.RUnlock()
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].job.Name < jobs[j].job.Name })
+
+	now := s.clock.Now()
+	published := 0
+	var errs []error
+	for _, rj := range jobs {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+		n, err := s.runJob(ctx, rj, now)
+		published += n
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return published, errors.Join(errs...)
+}
+
+func (s *Scheduler) runJob(ctx context.Context, rj *registeredJob, now time.Time) (int, error) {
+	name := rj.job.Name
+	last, err := s.history.Last(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	cursor := rj.baseline
+	if last != nil {
+		cursor = last.ScheduledAt
+	}
+
+	var missed, onTime []time.Time
+	for next := rj.schedule.Next(cursor); !next.IsZero() && !next.After(now); next = rj.schedule.Next(next) {
+		if now.Sub(next) > s.config.MisfireThreshold {
+			missed = append(missed, next)
+		} else {
+			onTime = append(onTime, next)
+		}
+		if len(missed)+len(onTime) >= maxDueScan {
+			break
+		}
+	}
+
+	var due []time.Time
+	if len(missed) > 0 {
+		switch rj.job.Misfire {
+		case MisfireFireOnce:
+			due = append(due, missed[len(missed)-1])
+		case MisfireFireAll:
+			due = append(due, missed[max(0, len(missed)-s.config.MaxCatchUp):]...)
+		}
+		if skipped := len(missed) - len(due); skipped > 0 {
+			s.logger.Warn(ctx, "scheduled job misfired",
+				logging.String("job", name),
+				logging.Int("skipped", skipped),
+				logging.Any("last_missed_at", missed[len(missed)-1]))
+			if len(due) == 0 {
+				if err := s.recordSkipped(ctx, name, missed[len(missed)-1], skipped); err != nil {
+					return 0, err
+				}
+			}
+		}
+	}
+	due = append(due, onTime...)
+
+	published := 0
+	for _, scheduledAt := range due {
+		ok, err := s.fire(ctx, rj, scheduledAt)
+		if err != nil {
+			return published, err
+		}
+		if ok {
+			published++
+		}
+	}
+	return published, nil
+}
+
+// fire 触发一次计划时间；计划时间已被（其他实例）记录时返回 (false, nil)。
+func (s *Scheduler) fire(ctx context.Context, rj *registeredJob, scheduledAt time.Time) (bool, error) {
+	name := rj.job.Name
+	run := &Run{
+		ID:          RunID(name, scheduledAt),
+		JobName:     name,
+		ScheduledAt: scheduledAt,
+		StartedAt:   s.clock.Now(),
+		Status:      RunStatusRunning,
+		Instance:    s.config.Instance,
+	}
+	if err := s.history.Record(ctx, run); err != nil {
+		if errors.Is(err, errors.Conflict) {
+			s.logger.Debug(ctx, "scheduled run already recorded", logging.String("run_id", run.ID))
+			return false, nil
+		}
+		return false, err
+	}
+
+	published := false
+	msg, err := rj.job.Build(ctx, *run)
+	switch {
+	case err != nil:
+		run.Status = RunStatusFailed
+		run.Error = err.Error()
+	case msg == nil:
+		run.Status = RunStatusSkipped
+	default:
+		run.MessageID = msg.GetID()
+		msg.GetMetadata().Set(MetadataJob, name)
+		msg.GetMetadata().Set(MetadataScheduledAt, scheduledAt.UTC().Format(time.RFC3339))
+		if err = s.bus.Publish(ctx, msg); err != nil {
+			run.Status = RunStatusFailed
+			run.Error = err.Error()
+		} else {
+			run.Status = RunStatusSucceeded
+			published = true
+		}
+	}
+	if err != nil {
+		s.logger.Error(ctx, "scheduled job failed", logging.Error(err),
+			logging.String("job", name),
+			logging.String("run_id", run.ID))
+	}
+
+	finishedAt := s.clock.Now()
+	run.FinishedAt = &finishedAt
+	if err := s.history.Finish(ctx, run); err != nil {
+		return published, err
+	}
+	return published, nil
+}
+
+func (s *Scheduler) recordSkipped(ctx context.Context, name string, scheduledAt time.Time, count int) error {
+	now := s.clock.Now()
+	err := s.history.Record(ctx, &Run{
+		ID:          RunID(name, scheduledAt),
+		JobName:     name,
+		ScheduledAt: scheduledAt,
+		StartedAt:   now,
+		FinishedAt:  &now,
+		Status:      RunStatusSkipped,
+		Error:       fmt.Sprintf("%d missed runs skipped", count),
+		Instance:    s.config.Instance,
+	})
+	if errors.Is(err, errors.Conflict) {
+		return nil
+	}
+	return err
+}
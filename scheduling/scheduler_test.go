@@ -0,0 +1,234 @@
+package scheduling
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/messaging"
+	"gochen/messaging/command"
+)
+
+type recordingBus struct {
+	mu        sync.Mutex
+	published []messaging.IMessage
+}
+
+func (b *recordingBus) Subscribe(context.Context, string, messaging.IMessageHandler) (messaging.UnsubscribeFunc, error) {
+	return func(context.Context) error { return nil }, nil
+}
+
+func (b *recordingBus) Publish(_ context.Context, message messaging.IMessage) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.published = append(b.published, message)
+	return nil
+}
+
+func (b *recordingBus) PublishAll(ctx context.Context, messages []messaging.IMessage) error {
+	for _, msg := range messages {
+		if err := b.Publish(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *recordingBus) Use(messaging.IMiddleware) {}
+
+func (b *recordingBus) messages() []messaging.IMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]messaging.IMessage(nil), b.published...)
+}
+
+type switchElector struct {
+	leader   atomic.Bool
+	resigned atomic.Bool
+}
+
+func (e *switchElector) TryLead(context.Context) (bool, error) { return e.leader.Load(), nil }
+
+func (e *switchElector) Resign(context.Context) error {
+	e.resigned.Store(true)
+	return nil
+}
+
+var testStart = time.Date(2026, 1, 1, 0, 0, 30, 0, time.UTC)
+
+func newTestScheduler(t *testing.T, clk *clock.ManualClock, history IHistoryStore, cfg Config) (*Scheduler, *recordingBus) {
+	t.Helper()
+	bus := &recordingBus{}
+	cfg.Clock = clk
+	scheduler, err := NewScheduler(bus, history, &cfg)
+	require.NoError(t, err)
+	return scheduler, bus
+}
+
+// TestScheduler_FiresOnSchedule 验证任务到点发布命令，消息 ID 与元数据来自运行记录，并写入运行历史。
+func TestScheduler_FiresOnSchedule(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewManualClock(testStart)
+	history := NewMemoryHistoryStore()
+	scheduler, bus := newTestScheduler(t, clk, history, Config{Instance: "node-1"})
+
+	require.NoError(t, scheduler.Register(Job{
+		Name:  "close-stale-orders",
+		Spec:  "* * * * *",
+		Build: CommandBuilder("CloseStaleOrders", "", "Order", nil),
+	}))
+	require.True(t, errors.Is(scheduler.Register(Job{Name: "close-stale-orders", Spec: "@hourly", Build: CommandBuilder("X", "", "", nil)}), errors.Conflict))
+
+	published, err := scheduler.Tick(ctx)
+	require.NoError(t, err)
+	require.Zero(t, published)
+
+	clk.Advance(30 * time.Second)
+	published, err = scheduler.Tick(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, published)
+
+	// 同一计划时间不会重复触发。
+	published, err = scheduler.Tick(ctx)
+	require.NoError(t, err)
+	require.Zero(t, published)
+
+	scheduledAt := time.Date(2026, 1, 1, 0, 1, 0, 0, time.UTC)
+	msgs := bus.messages()
+	require.Len(t, msgs, 1)
+	cmd, ok := msgs[0].(*command.Command)
+	require.True(t, ok)
+	require.Equal(t, RunID("close-stale-orders", scheduledAt), cmd.GetID())
+	job, _ := cmd.GetMetadata().Get(MetadataJob)
+	require.Equal(t, "close-stale-orders", job)
+
+	runs, err := scheduler.History(ctx, "close-stale-orders", 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	require.Equal(t, RunStatusSucceeded, runs[0].Status)
+	require.Equal(t, cmd.GetID(), runs[0].MessageID)
+	require.Equal(t, "node-1", runs[0].Instance)
+	require.True(t, runs[0].ScheduledAt.Equal(scheduledAt))
+}
+
+// TestScheduler_MisfirePolicies 验证停机期间错过的触发按策略跳过或补触发。
+func TestScheduler_MisfirePolicies(t *testing.T) {
+	cases := []struct {
+		name       string
+		policy     MisfirePolicy
+		maxCatchUp int
+		published  int
+		skipped    bool
+	}{
+		{name: "skip", policy: MisfireSkip, published: 1, skipped: true},
+		{name: "fire_once", policy: MisfireFireOnce, published: 2},
+		{name: "fire_all", policy: MisfireFireAll, published: 10},
+		{name: "fire_all_capped", policy: MisfireFireAll, maxCatchUp: 5, published: 6},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			clk := clock.NewManualClock(testStart)
+			history := NewMemoryHistoryStore()
+			scheduler, bus := newTestScheduler(t, clk, history, Config{MaxCatchUp: tc.maxCatchUp})
+			require.NoError(t, scheduler.Register(Job{
+				Name:    "report",
+				Spec:    "* * * * *",
+				Misfire: tc.policy,
+				Build:   MessageBuilder(messaging.KindEvent, "ReportDue", nil),
+			}))
+
+			// 00:01..00:10 共 10 个计划时间，其中 00:10 在阈值内，其余 9 个视为错过。
+			clk.Advance(10 * time.Minute)
+			published, err := scheduler.Tick(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tc.published, published)
+			require.Len(t, bus.messages(), tc.published)
+
+			runs, err := history.List(ctx, "report", 0)
+			require.NoError(t, err)
+			require.True(t, runs[0].ScheduledAt.Equal(time.Date(2026, 1, 1, 0, 10, 0, 0, time.UTC)))
+			if tc.skipped {
+				require.Len(t, runs, 2)
+				require.Equal(t, RunStatusSkipped, runs[1].Status)
+				require.Contains(t, runs[1].Error, "9 missed runs skipped")
+			}
+		})
+	}
+}
+
+// TestScheduler_LeaderElection 验证只有领导者触发任务，接管后不会重复触发已记录的计划时间。
+func TestScheduler_LeaderElection(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewManualClock(testStart)
+	history := NewMemoryHistoryStore()
+	electorA, electorB := &switchElector{}, &switchElector{}
+	electorA.leader.Store(true)
+	schedulerA, busA := newTestScheduler(t, clk, history, Config{Instance: "a", Elector: electorA})
+	schedulerB, busB := newTestScheduler(t, clk, history, Config{Instance: "b", Elector: electorB})
+	for _, s := range []*Scheduler{schedulerA, schedulerB} {
+		require.NoError(t, s.Register(Job{Name: "sync", Spec: "* * * * *", Build: MessageBuilder(messaging.KindCommand, "Sync", nil)}))
+	}
+
+	clk.Advance(time.Minute)
+	_, err := schedulerA.Tick(ctx)
+	require.NoError(t, err)
+	_, err = schedulerB.Tick(ctx)
+	require.NoError(t, err)
+	require.Len(t, busA.messages(), 1)
+	require.Empty(t, busB.messages())
+	require.True(t, schedulerA.IsLeader())
+	require.False(t, schedulerB.IsLeader())
+
+	// A 失去领导权，B 接管：已由 A 触发的 00:01 不会重复，B 从 00:02 开始。
+	electorA.leader.Store(false)
+	electorB.leader.Store(true)
+	_, err = schedulerB.Tick(ctx)
+	require.NoError(t, err)
+	require.Empty(t, busB.messages())
+
+	clk.Advance(time.Minute)
+	_, err = schedulerA.Tick(ctx)
+	require.NoError(t, err)
+	_, err = schedulerB.Tick(ctx)
+	require.NoError(t, err)
+	require.Len(t, busA.messages(), 1)
+	require.Len(t, busB.messages(), 1)
+
+	require.NoError(t, schedulerB.Start(ctx))
+	require.NoError(t, schedulerB.Stop(ctx))
+	require.True(t, electorB.resigned.Load())
+}
+
+// TestScheduler_BuildFailureRecorded 验证构造失败记为 failed 且不发布。
+func TestScheduler_BuildFailureRecorded(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewManualClock(testStart)
+	history := NewMemoryHistoryStore()
+	scheduler, bus := newTestScheduler(t, clk, history, Config{})
+	require.NoError(t, scheduler.Register(Job{
+		Name: "broken",
+		Spec: "@every 30s",
+		Build: func(context.Context, Run) (messaging.IMessage, error) {
+			return nil, errors.NewCode(errors.Internal, "boom")
+		},
+	}))
+
+	clk.Advance(30 * time.Second)
+	published, err := scheduler.Tick(ctx)
+	require.NoError(t, err)
+	require.Zero(t, published)
+	require.Empty(t, bus.messages())
+
+	last, err := history.Last(ctx, "broken")
+	require.NoError(t, err)
+	require.Equal(t, RunStatusFailed, last.Status)
+	require.Contains(t, last.Error, "boom")
+	require.NotNil(t, last.FinishedAt)
+}
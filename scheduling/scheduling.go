@@ -0,0 +1,111 @@
+// Package scheduling 提供 cron 风格的周期任务：按调度表达式定时构造命令/事件并发布到消息总线。
+//
+// 与 messaging/schedule（一次性延迟投递）互补：
+//   - Job 以名称注册，Spec 为 cron 表达式（见 ParseSpec），到点时由 Build 构造消息并发布到 messaging.IMessageBus；
+//   - 每次触发写入运行历史（IHistoryStore，内存/SQL），历史同时作为“上次触发到哪里”的游标，
+//     同一 (任务, 计划时间) 只会被记录一次，多实例竞争时以记录成功者为准；
+//   - 停机或失去领导权期间错过的触发按 MisfirePolicy 处理（跳过/补触发一次/全部补触发）；
+//   - 配置 ILeaderElector 后只有领导者实例触发任务，其他实例待命，领导者失联后由其他实例接管。
+//
+// 触发语义为“每个计划时间至多一次”：发布失败记为 failed，不会重试，等待下一个计划时间。
+package scheduling
+
+import (
+	"context"
+	"time"
+
+	"gochen/messaging"
+)
+
+// MisfirePolicy 定义错过的触发（计划时间早于当前时间超过 Config.MisfireThreshold）的处理方式。
+type MisfirePolicy int
+
+const (
+	// MisfireSkip 跳过所有错过的触发，只记录一条 skipped 历史（默认）。
+	MisfireSkip MisfirePolicy = iota
+	// MisfireFireOnce 只补触发最近一次错过的计划时间，更早的视为跳过。
+	MisfireFireOnce
+	// MisfireFireAll 按时间顺序补触发错过的计划时间（最多 Config.MaxCatchUp 次，超出部分视为跳过）。
+	MisfireFireAll
+)
+
+// RunStatus 是一次触发的状态。
+type RunStatus string
+
+const (
+	RunStatusRunning   RunStatus = "running"
+	RunStatusSucceeded RunStatus = "succeeded"
+	RunStatusFailed    RunStatus = "failed"
+	RunStatusSkipped   RunStatus = "skipped"
+)
+
+const (
+	// MetadataJob / MetadataScheduledAt 是写入所发消息元数据的键（计划时间为 RFC3339）。
+	MetadataJob         = "scheduled_job"
+	MetadataScheduledAt = "scheduled_at"
+)
+
+// Run 是一次触发的运行记录。
+type Run struct {
+	// ID 由任务名与计划时间确定性派生（见 RunID），也是 CommandBuilder / MessageBuilder 所发消息的 ID。
+	ID          string     `json:"id"`
+	JobName     string     `json:"job_name"`
+	ScheduledAt time.Time  `json:"scheduled_at"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	Status      RunStatus  `json:"status"`
+	MessageID   string     `json:"message_id,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	// Instance 执行触发的实例标识（Config.Instance）。
+	Instance string `json:"instance,omitempty"`
+}
+
+// BuildFunc 为一次触发构造要发布的消息；返回 (nil, nil) 表示本次不发布（记为 skipped）。
+type BuildFunc func(ctx context.Context, run Run) (messaging.IMessage, error)
+
+// Job 声明一个周期任务。
+type Job struct {
+	// Name 任务名称（全局唯一），作为运行历史的键，上线后不应修改。
+	Name string
+
+	// Spec 调度表达式，见 ParseSpec。
+	Spec string
+
+	// Location 计算 cron 表达式使用的时区（默认 UTC），集群内各实例应保持一致。
+	Location *time.Location
+
+	// Misfire 错过触发时的处理方式（默认 MisfireSkip）。
+	Misfire MisfirePolicy
+
+	// Build 消息构造函数。
+	Build BuildFunc
+}
+
+// IHistoryStore 持久化任务运行历史。
+type IHistoryStore interface {
+	// Record 保存一条运行记录；同一 (JobName, ScheduledAt) 已存在时返回 errors.Conflict。
+	Record(ctx context.Context, run *Run) error
+
+	// Finish 更新运行记录的状态、结束时间、消息 ID 与错误。
+	Finish(ctx context.Context, run *Run) error
+
+	// Last 返回任务计划时间最晚的运行记录；没有记录时返回 (nil, nil)。
+	Last(ctx context.Context, jobName string) (*Run, error)
+
+	// List 按计划时间倒序返回任务最近的运行记录。
+	List(ctx context.Context, jobName string, limit int) ([]*Run, error)
+}
+
+// ILeaderElector 决定当前实例是否负责触发任务。
+type ILeaderElector interface {
+	// TryLead 尝试获取或续期领导权，返回当前实例是否为领导者；调度器每个扫描周期调用一次。
+	TryLead(ctx context.Context) (bool, error)
+
+	// Resign 主动放弃领导权（调度器停止时调用）。
+	Resign(ctx context.Context) error
+}
+
+// RunID 返回任务在计划时间的确定性运行 ID。
+func RunID(jobName string, scheduledAt time.Time) string {
+	return "cron:" + jobName + ":" + scheduledAt.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,222 @@
+package scheduling
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gochen/db"
+	"gochen/db/dialect"
+	"gochen/db/sql/safeident"
+	"gochen/db/sql/sqlbuilder"
+	"gochen/errors"
+)
+
+// DefaultHistoryTableName 是 SQLHistoryStore 的默认表名。
+const DefaultHistoryTableName = "scheduled_job_runs"
+
+// SQLHistoryStoreConfig 定义 SQL 运行历史存储配置。
+type SQLHistoryStoreConfig struct {
+	// TableName 表名（默认：scheduled_job_runs）。
+	TableName string
+}
+
+// SQLHistoryStore 是基于数据库表的 IHistoryStore 实现，多实例共享同一张表。
+//
+// 表结构（自动创建）：
+//   - id VARCHAR(191) PRIMARY KEY：运行 ID（RunID，由任务名与计划时间派生，主键冲突即重复触发）
+//   - job_name VARCHAR(191)、scheduled_at_ms BIGINT：任务与计划时间（毫秒）
+//   - started_at_ms / finished_at_ms BIGINT、status、message_id、error、instance
+type SQLHistoryStore struct {
+	db        db.IDatabase
+	dialect   dialect.Dialect
+	tableName string
+
+	ensureMu sync.Mutex
+	ensured  bool
+}
+
+// NewSQLHistoryStore 创建 SQL 运行历史存储。
+func NewSQLHistoryStore(database db.IDatabase, cfg *SQLHistoryStoreConfig) (*SQLHistoryStore, error) {
+	if database == nil {
+		return nil, errors.NewCode(errors.InvalidInput, "database cannot be nil")
+	}
+	tableName := DefaultHistoryTableName
+	if cfg != nil && strings.TrimSpace(cfg.TableName) != "" {
+		tableName = strings.TrimSpace(cfg.TableName)
+	}
+	if !safeident.IsSafeIdentifier(tableName) {
+		return nil, errors.NewCode(errors.InvalidInput, fmt.Sprintf("invalid table name: %s", tableName))
+	}
+	return &SQLHistoryStore{db: database, dialect: dialect.FromDatabase(database), tableName: tableName}, nil
+}
+
+// ensureTable 首次使用时创建表与索引；失败不缓存，下次调用重试。
+func (s *SQLHistoryStore) ensureTable(ctx context.Context) error {
+	s.ensureMu.Lock()
+	defer s.ensureMu.Unlock()
+	if s.ensured {
+		return nil
+	}
+	var inlineIndex string
+	if name := s.dialect.Name(); name != dialect.NameSQLite && name != dialect.NamePostgres {
+		// MySQL 不支持 CREATE INDEX IF NOT EXISTS，索引随建表语句创建。
+		inlineIndex = fmt.Sprintf(",\nINDEX idx_%s_job (job_name, scheduled_at_ms)", s.tableName)
+	}
+	_, err := s.db.Exec(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+id VARCHAR(191) PRIMARY KEY,
+job_name VARCHAR(191) NOT NULL,
+scheduled_at_ms BIGINT NOT NULL,
+started_at_ms BIGINT NOT NULL,
+finished_at_ms BIGINT,
+status VARCHAR(32) NOT NULL,
+message_id VARCHAR(191),
+error TEXT,
+instance VARCHAR(191)%s
+)`, s.tableName, inlineIndex))
+	if err == nil && inlineIndex == "" {
+		_, err = s.db.Exec(ctx, fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_job ON %s (job_name, scheduled_at_ms)", s.tableName, s.tableName))
+	}
+	if err != nil {
+		return errors.Wrap(err, errors.Database, "ensure job history table failed").WithContext("table", s.tableName)
+	}
+	s.ensured = true
+	return nil
+}
+
+func (s *SQLHistoryStore) builder() (sqlbuilder.ISql, error) {
+	sq, err := sqlbuilder.New(s.db)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "failed to create sql builder")
+	}
+	return sq, nil
+}
+
+// Record 保存运行记录。
+func (s *SQLHistoryStore) Record(ctx context.Context, run *Run) error {
+	if run == nil || run.JobName == "" || run.ID == "" {
+		return errors.NewCode(errors.InvalidInput, "run is nil or incomplete")
+	}
+	if err := s.ensureTable(ctx); err != nil {
+		return err
+	}
+	sq, err := s.builder()
+	if err != nil {
+		return err
+	}
+	_, err = sq.InsertInto(s.tableName).
+		Columns("id", "job_name", "scheduled_at_ms", "started_at_ms", "finished_at_ms", "status", "message_id", "error", "instance").
+		Values(run.ID, run.JobName, run.ScheduledAt.UnixMilli(), run.StartedAt.UnixMilli(), finishedAtMillis(run),
+			string(run.Status), run.MessageID, run.Error, run.Instance).
+		Exec(ctx)
+	if err == nil {
+		return nil
+	}
+	// 主键冲突的错误文本因驱动而异，回查区分“已记录”与其他数据库错误。
+	var count int64
+	if qerr := sq.Select("COUNT(1)").From(s.tableName).Where("id = ?", run.ID).QueryRow(ctx).Scan(&count); qerr == nil && count > 0 {
+		return errors.NewCode(errors.Conflict, "scheduled run already recorded").
+			WithContext("job", run.JobName).
+			WithContext("run_id", run.ID)
+	}
+	return errors.Wrap(err, errors.Database, "insert job run failed").WithContext("run_id", run.ID)
+}
+
+// Finish 更新运行记录。
+func (s *SQLHistoryStore) Finish(ctx context.Context, run *Run) error {
+	if run == nil || run.ID == "" {
+		return errors.NewCode(errors.InvalidInput, "run is nil or has no id")
+	}
+	sq, err := s.builder()
+	if err != nil {
+		return err
+	}
+	result, err := sq.Update(s.tableName).
+		Set("finished_at_ms", finishedAtMillis(run)).
+		Set("status", string(run.Status)).
+		Set("message_id", run.MessageID).
+		Set("error", run.Error).
+		Where("id = ?", run.ID).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, errors.Database, "update job run failed").WithContext("run_id", run.ID)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return errors.NewCode(errors.NotFound, "scheduled run not found").WithContext("run_id", run.ID)
+	}
+	return nil
+}
+
+// Last 返回计划时间最晚的运行记录。
+func (s *SQLHistoryStore) Last(ctx context.Context, jobName string) (*Run, error) {
+	runs, err := s.List(ctx, jobName, 1)
+	if err != nil || len(runs) == 0 {
+		return nil, err
+	}
+	return runs[0], nil
+}
+
+// List 按计划时间倒序返回最近的运行记录。
+func (s *SQLHistoryStore) List(ctx context.Context, jobName string, limit int) ([]*Run, error) {
+	if err := s.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	sq, err := s.builder()
+	if err != nil {
+		return nil, err
+	}
+	query := sq.Select("id", "job_name", "scheduled_at_ms", "started_at_ms", "finished_at_ms", "status", "message_id", "error", "instance").
+		From(s.tableName).
+		Where("job_name = ?", jobName).
+		OrderBy(sqlbuilder.OrderDesc("scheduled_at_ms"))
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	rows, err := query.Query(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Database, "query job runs failed").WithContext("job", jobName)
+	}
+	defer rows.Close()
+
+	var runs []*Run
+	for rows.Next() {
+		var (
+			run                                 Run
+			status                              string
+			scheduledAtMs, startedAtMs          int64
+			finishedAtMs                        sql.NullInt64
+			messageID, errorText, instanceValue sql.NullString
+		)
+		if err := rows.Scan(&run.ID, &run.JobName, &scheduledAtMs, &startedAtMs, &finishedAtMs,
+			&status, &messageID, &errorText, &instanceValue); err != nil {
+			return nil, errors.Wrap(err, errors.Database, "scan job run failed")
+		}
+		run.ScheduledAt = time.UnixMilli(scheduledAtMs)
+		run.StartedAt = time.UnixMilli(startedAtMs)
+		if finishedAtMs.Valid {
+			finishedAt := time.UnixMilli(finishedAtMs.Int64)
+			run.FinishedAt = &finishedAt
+		}
+		run.Status = RunStatus(status)
+		run.MessageID = messageID.String
+		run.Error = errorText.String
+		run.Instance = instanceValue.String
+		runs = append(runs, &run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, errors.Database, "iterate job runs failed")
+	}
+	return runs, nil
+}
+
+func finishedAtMillis(run *Run) any {
+	if run.FinishedAt == nil {
+		return nil
+	}
+	return run.FinishedAt.UnixMilli()
+}
+
+var _ IHistoryStore = (*SQLHistoryStore)(nil)
@@ -0,0 +1,95 @@
+package scheduling
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"gochen/clock"
+	"gochen/db"
+	basicdb "gochen/db/sql/stdsql"
+	"gochen/errors"
+)
+
+func newSQLiteDB(t *testing.T) db.IDatabase {
+	t.Helper()
+	database, err := basicdb.New(db.DBConfig{Driver: "sqlite", Database: ":memory:", MaxOpenConns: 1, MaxIdleConns: 1})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = database.Close() })
+	return database
+}
+
+// TestSQLHistoryStore_RecordFinishList 验证运行历史的去重、更新与倒序查询。
+func TestSQLHistoryStore_RecordFinishList(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewSQLHistoryStore(newSQLiteDB(t), nil)
+	require.NoError(t, err)
+
+	last, err := store.Last(ctx, "job")
+	require.NoError(t, err)
+	require.Nil(t, last)
+
+	base := time.UnixMilli(time.Now().UnixMilli())
+	for i := range 3 {
+		scheduledAt := base.Add(time.Duration(i) * time.Minute)
+		run := &Run{ID: RunID("job", scheduledAt), JobName: "job", ScheduledAt: scheduledAt, StartedAt: scheduledAt, Status: RunStatusRunning}
+		require.NoError(t, store.Record(ctx, run))
+		finishedAt := scheduledAt.Add(time.Second)
+		run.FinishedAt = &finishedAt
+		run.Status = RunStatusSucceeded
+		run.MessageID = run.ID
+		require.NoError(t, store.Finish(ctx, run))
+	}
+	dup := &Run{ID: RunID("job", base), JobName: "job", ScheduledAt: base, StartedAt: base, Status: RunStatusRunning}
+	require.True(t, errors.Is(store.Record(ctx, dup), errors.Conflict))
+
+	runs, err := store.List(ctx, "job", 2)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	require.True(t, runs[0].ScheduledAt.Equal(base.Add(2*time.Minute)))
+	require.Equal(t, RunStatusSucceeded, runs[0].Status)
+	require.NotNil(t, runs[0].FinishedAt)
+	require.Equal(t, runs[0].ID, runs[0].MessageID)
+}
+
+// TestSQLLeaderElector_Takeover 验证租约续期、互斥与过期接管。
+func TestSQLLeaderElector_Takeover(t *testing.T) {
+	ctx := context.Background()
+	database := newSQLiteDB(t)
+	clk := clock.NewManualClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	newElector := func(owner string) *SQLLeaderElector {
+		elector, err := NewSQLLeaderElector(database, &SQLLeaderElectorConfig{Owner: owner, TTL: 10 * time.Second, Clock: clk})
+		require.NoError(t, err)
+		return elector
+	}
+	a, b := newElector("a"), newElector("b")
+
+	leader, err := a.TryLead(ctx)
+	require.NoError(t, err)
+	require.True(t, leader)
+	leader, err = b.TryLead(ctx)
+	require.NoError(t, err)
+	require.False(t, leader)
+
+	clk.Advance(5 * time.Second)
+	leader, err = a.TryLead(ctx)
+	require.NoError(t, err)
+	require.True(t, leader)
+
+	// a 停止续期，租约过期后 b 接管。
+	clk.Advance(11 * time.Second)
+	leader, err = b.TryLead(ctx)
+	require.NoError(t, err)
+	require.True(t, leader)
+	leader, err = a.TryLead(ctx)
+	require.NoError(t, err)
+	require.False(t, leader)
+
+	require.NoError(t, b.Resign(ctx))
+	leader, err = a.TryLead(ctx)
+	require.NoError(t, err)
+	require.True(t, leader)
+}
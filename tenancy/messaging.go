@@ -0,0 +1,54 @@
+package tenancy
+
+import (
+	"context"
+
+	"gochen/errors"
+	"gochen/messaging"
+)
+
+// MessageMiddleware 是在消息 metadata 与上下文之间双向传播租户的 messaging 中间件。
+//
+// 行为：
+//   - 上下文有租户、metadata 缺失时写入 metadata（投递侧）；
+//   - metadata 有租户、上下文缺失时恢复到上下文（消费侧）；
+//   - 两者都存在且不一致时拒绝处理并返回 Forbidden，防止跨租户串用。
+type MessageMiddleware struct{}
+
+var _ messaging.IMiddleware = (*MessageMiddleware)(nil)
+
+// NewMessageMiddleware 创建租户传播中间件。
+func NewMessageMiddleware() *MessageMiddleware {
+	return &MessageMiddleware{}
+}
+
+// Handle 传播租户后执行后续链路。
+func (m *MessageMiddleware) Handle(ctx context.Context, message messaging.IMessage, next messaging.HandlerFunc) error {
+	if message == nil {
+		return next(ctx, message)
+	}
+	md := message.GetMetadata()
+	ctxTenant := FromContext(ctx)
+	mdTenant := FromMetadata(md)
+	switch {
+	case ctxTenant != "" && mdTenant == "":
+		md.Set(MetadataKey, ctxTenant)
+	case ctxTenant == "" && mdTenant != "":
+		derived, err := WithTenant(ctx, mdTenant)
+		if err != nil {
+			return err
+		}
+		ctx = derived
+	case ctxTenant != mdTenant:
+		return errors.NewCode(errors.Forbidden, "message tenant does not match context tenant").
+			WithContext("message_id", message.GetID()).
+			WithContext("message_tenant", mdTenant).
+			WithContext("context_tenant", ctxTenant)
+	}
+	return next(ctx, message)
+}
+
+// Name 返回中间件名称。
+func (m *MessageMiddleware) Name() string {
+	return "TenantPropagation"
+}
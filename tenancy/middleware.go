@@ -0,0 +1,87 @@
+package tenancy
+
+import (
+	"strconv"
+
+	auth "gochen/auth"
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+// Options 定义 HTTP 租户中间件配置。
+type Options struct {
+	// Resolvers 按顺序尝试解析租户；为空时依次使用 FromHeader("") 与 FromDataScope()。
+	Resolvers []IResolver
+
+	// Required 为 true 时请求必须携带租户，否则返回 InvalidInput。
+	Required bool
+
+	// AllowCrossTenant 为 true 时不校验解析结果是否落在上下文 DataScope.TenantIDs 内。
+	//
+	// 默认（false）下，上下文绑定了非全局且带 TenantIDs 的 DataScope 时，只能访问其中的租户，
+	// 否则返回 Forbidden，防止通过伪造 Header/子域名越权访问其他租户。
+	AllowCrossTenant bool
+
+	// ExposeHeader 为 true 时把生效的租户写回响应头 httpx.HeaderTenantID。
+	ExposeHeader bool
+}
+
+// Middleware 构造 HTTP 租户中间件：解析租户并通过 contextx 写入请求上下文。
+//
+// 需挂在认证中间件之后，才能使用 FromDataScope 与 DataScope 租户校验。
+func Middleware(opts *Options) httpx.Middleware {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	resolver := FirstOf(o.Resolvers...)
+	if len(o.Resolvers) == 0 {
+		resolver = FirstOf(FromHeader(""), FromDataScope())
+	}
+	return func(ctx httpx.IContext, next func() error) error {
+		reqCtx := ctx.RequestContext()
+		if reqCtx == nil {
+			return errors.NewCode(errors.Internal, "request context is nil")
+		}
+		tenantID, err := resolver.ResolveTenant(ctx)
+		if err != nil {
+			return err
+		}
+		if tenantID == "" {
+			if o.Required {
+				return errors.NewCode(errors.InvalidInput, "tenant is required")
+			}
+			return next()
+		}
+		if !o.AllowCrossTenant {
+			if err := checkScopeTenant(reqCtx, tenantID); err != nil {
+				return err
+			}
+		}
+		bound, err := WithTenant(reqCtx, tenantID)
+		if err != nil {
+			return err
+		}
+		ctx.SetContext(reqCtx.WithContext(bound))
+		if o.ExposeHeader {
+			ctx.SetHeader(httpx.HeaderTenantID, tenantID)
+		}
+		return next()
+	}
+}
+
+// checkScopeTenant 校验目标租户是否在上下文 DataScope 可见租户内。
+func checkScopeTenant(reqCtx httpx.IRequestContext, tenantID string) error {
+	scope, ok := auth.DataScopeFromContext(reqCtx)
+	if !ok || scope.Mode == auth.ScopeModeGlobal || len(scope.TenantIDs) == 0 {
+		return nil
+	}
+	for _, visible := range scope.TenantIDs {
+		if strconv.FormatInt(visible, 10) == tenantID {
+			return nil
+		}
+	}
+	return errors.NewCode(errors.Forbidden, "tenant access denied").
+		WithContext("tenant_id", tenantID).
+		WithContext("visible_tenant_ids", scope.TenantIDs)
+}
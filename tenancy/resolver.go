@@ -0,0 +1,104 @@
+package tenancy
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	auth "gochen/auth"
+	"gochen/errors"
+	"gochen/httpx"
+)
+
+// maxTenantIDLength 是从请求中接受的租户 ID 最大长度。
+const maxTenantIDLength = 128
+
+// IResolver 从 HTTP 请求解析租户 ID。
+//
+// 未携带租户时返回空字符串与 nil；携带了但格式非法时返回 InvalidInput 错误。
+type IResolver interface {
+	ResolveTenant(ctx httpx.IContext) (string, error)
+}
+
+// ResolverFunc 是 IResolver 的函数适配器。
+type ResolverFunc func(ctx httpx.IContext) (string, error)
+
+// ResolveTenant 调用函数本身。
+func (f ResolverFunc) ResolveTenant(ctx httpx.IContext) (string, error) { return f(ctx) }
+
+// FromHeader 从指定 Header 解析租户；name 为空时使用 httpx.HeaderTenantID。
+func FromHeader(name string) ResolverFunc {
+	if name = strings.TrimSpace(name); name == "" {
+		name = httpx.HeaderTenantID
+	}
+	return func(ctx httpx.IContext) (string, error) {
+		raw := strings.TrimSpace(ctx.Header(name))
+		if raw == "" {
+			return "", nil
+		}
+		tenantID := httpx.SanitizeIdentifierFromHeader(raw, maxTenantIDLength)
+		if tenantID == "" {
+			return "", errors.NewCode(errors.InvalidInput, "invalid tenant header").WithContext("header", name)
+		}
+		return tenantID, nil
+	}
+}
+
+// FromSubdomain 从 Host 的子域名解析租户，例如 baseDomain 为 "example.com" 时 "acme.example.com" 解析为 "acme"。
+//
+// 只接受恰好一级子域名；Host 不属于 baseDomain 或为 baseDomain 本身时视为未携带租户。
+func FromSubdomain(baseDomain string) ResolverFunc {
+	suffix := "." + strings.ToLower(strings.Trim(strings.TrimSpace(baseDomain), "."))
+	return func(ctx httpx.IContext) (string, error) {
+		req := ctx.Request()
+		if req == nil || suffix == "." {
+			return "", nil
+		}
+		host := strings.ToLower(req.Host)
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		label, ok := strings.CutSuffix(host, suffix)
+		if !ok || label == "" || strings.Contains(label, ".") {
+			return "", nil
+		}
+		tenantID := httpx.SanitizeIdentifierFromHeader(label, maxTenantIDLength)
+		if tenantID == "" {
+			return "", errors.NewCode(errors.InvalidInput, "invalid tenant subdomain").WithContext("host", req.Host)
+		}
+		return tenantID, nil
+	}
+}
+
+// FromDataScope 使用上下文 DataScope 中唯一的 TenantIDs 作为租户；需挂在绑定 DataScope 的认证链路之后。
+//
+// 可见多个租户时无法确定当前租户，视为未携带（由 Header/子域名显式指定）。
+func FromDataScope() ResolverFunc {
+	return func(ctx httpx.IContext) (string, error) {
+		reqCtx := ctx.RequestContext()
+		if reqCtx == nil {
+			return "", nil
+		}
+		scope, ok := auth.DataScopeFromContext(reqCtx)
+		if !ok || len(scope.TenantIDs) != 1 {
+			return "", nil
+		}
+		return strconv.FormatInt(scope.TenantIDs[0], 10), nil
+	}
+}
+
+// FirstOf 依次尝试多个解析器，返回第一个非空结果；任一解析器出错立即返回。
+func FirstOf(resolvers ...IResolver) ResolverFunc {
+	return func(ctx httpx.IContext) (string, error) {
+		for _, resolver := range resolvers {
+			if resolver == nil {
+				continue
+			}
+			tenantID, err := resolver.ResolveTenant(ctx)
+			if err != nil || tenantID != "" {
+				return tenantID, err
+			}
+		}
+		return "", nil
+	}
+}
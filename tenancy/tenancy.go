@@ -0,0 +1,62 @@
+// Package tenancy 提供多租户的上下文传播与数据隔离入口。
+//
+// 租户 ID 的流转路径：
+//   - HTTP 入口：Middleware 通过 IResolver（Header、子域名、DataScope 唯一租户）解析租户并写入请求上下文；
+//   - 进程内：统一保存在 contextx 租户字段，FromContext / Require 读取；
+//   - 跨传输：InjectMetadata 写入消息/事件 metadata["tenant_id"]，MessageMiddleware 在消费侧恢复到上下文；
+//   - 存储：sqlstore.WithTenantColumn、snapshot.SQLStore.WithTenantColumn、OutboxConfig.TenantColumn
+//     按上下文租户写入并过滤 Column 列；ORM 仓储通过 app/crud.TenantAwareWrapper 按租户过滤。
+//
+// 上下文中没有租户时，存储层不追加过滤条件，便于后台投影与运维任务跨租户执行；
+// 面向租户的入口应使用 Options.Required 或 Require 保证租户存在。
+package tenancy
+
+import (
+	"context"
+	"strings"
+
+	"gochen/contextx"
+	"gochen/errors"
+)
+
+// Column 是存储层默认的租户列名。
+const Column = "tenant_id"
+
+// MetadataKey 是消息/事件 metadata 中的租户键名。
+const MetadataKey = contextx.MetadataTenantKey
+
+// WithTenant 将租户 ID 写入上下文。
+func WithTenant(ctx context.Context, tenantID string) (context.Context, error) {
+	return contextx.WithTenantID(ctx, strings.TrimSpace(tenantID))
+}
+
+// FromContext 读取上下文中的租户 ID；不存在时返回空字符串。
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	return strings.TrimSpace(contextx.TenantID(ctx))
+}
+
+// Require 读取上下文中的租户 ID；不存在时返回 InvalidInput 错误。
+func Require(ctx context.Context) (string, error) {
+	tenantID := FromContext(ctx)
+	if tenantID == "" {
+		return "", errors.NewCode(errors.InvalidInput, "tenant ID is required in context")
+	}
+	return tenantID, nil
+}
+
+// InjectMetadata 将上下文租户写入 metadata（metadata 已有租户时保持不变）。
+func InjectMetadata(ctx context.Context, metadata contextx.IMetadata) error {
+	return contextx.InjectTenantID(ctx, metadata)
+}
+
+// FromMetadata 读取 metadata 中的租户 ID。
+func FromMetadata(metadata contextx.IMetadata) string {
+	if metadata == nil {
+		return ""
+	}
+	v, _ := metadata.Get(MetadataKey)
+	return strings.TrimSpace(v)
+}
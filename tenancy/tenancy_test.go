@@ -0,0 +1,137 @@
+package tenancy
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	auth "gochen/auth"
+	"gochen/errors"
+	"gochen/httpx"
+	"gochen/httpx/nethttp"
+	"gochen/messaging"
+)
+
+func newTestContext(t *testing.T, host string, headers map[string]string, scope *auth.DataScope) *nethttp.Context {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/orders", nil)
+	if host != "" {
+		req.Host = host
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	ctx, err := nethttp.NewBaseContext(httptest.NewRecorder(), req)
+	require.NoError(t, err)
+	if scope != nil {
+		bound, err := auth.WithPrincipal(ctx.RequestContext(), auth.Principal{SubjectID: 7})
+		require.NoError(t, err)
+		bound, err = auth.WithDataScope(bound, *scope)
+		require.NoError(t, err)
+		ctx.SetContext(ctx.RequestContext().WithContext(bound))
+	}
+	return ctx
+}
+
+func runMiddleware(mw httpx.Middleware, ctx httpx.IContext) (string, bool, error) {
+	var (
+		tenantID string
+		called   bool
+	)
+	err := mw(ctx, func() error {
+		called = true
+		tenantID = FromContext(ctx.RequestContext())
+		return nil
+	})
+	return tenantID, called, err
+}
+
+// TestMiddleware_ResolvesHeaderAndDataScope 验证默认解析顺序与必填校验。
+func TestMiddleware_ResolvesHeaderAndDataScope(t *testing.T) {
+	mw := Middleware(&Options{Required: true, ExposeHeader: true})
+
+	tenantID, called, err := runMiddleware(mw, newTestContext(t, "", map[string]string{httpx.HeaderTenantID: "acme"}, nil))
+	require.NoError(t, err)
+	require.True(t, called)
+	require.Equal(t, "acme", tenantID)
+
+	tenantID, _, err = runMiddleware(mw, newTestContext(t, "", nil, &auth.DataScope{ActiveScopeID: 1, TenantIDs: []int64{42}}))
+	require.NoError(t, err)
+	require.Equal(t, "42", tenantID)
+
+	_, _, err = runMiddleware(mw, newTestContext(t, "", nil, &auth.DataScope{ActiveScopeID: 1, TenantIDs: []int64{42, 43}}))
+	require.True(t, errors.Is(err, errors.InvalidInput), "ambiguous scope requires an explicit tenant")
+
+	_, called, err = runMiddleware(mw, newTestContext(t, "", nil, nil))
+	require.True(t, errors.Is(err, errors.InvalidInput))
+	require.False(t, called)
+
+	_, _, err = runMiddleware(mw, newTestContext(t, "", map[string]string{httpx.HeaderTenantID: "bad tenant!"}, nil))
+	require.True(t, errors.Is(err, errors.InvalidInput))
+
+	tenantID, called, err = runMiddleware(Middleware(nil), newTestContext(t, "", nil, nil))
+	require.NoError(t, err)
+	require.True(t, called)
+	require.Empty(t, tenantID)
+}
+
+// TestMiddleware_RejectsTenantOutsideDataScope 验证只能访问 DataScope 可见的租户。
+func TestMiddleware_RejectsTenantOutsideDataScope(t *testing.T) {
+	headers := map[string]string{httpx.HeaderTenantID: "43"}
+	scoped := &auth.DataScope{ActiveScopeID: 1, TenantIDs: []int64{42}}
+
+	_, called, err := runMiddleware(Middleware(nil), newTestContext(t, "", headers, scoped))
+	require.True(t, errors.Is(err, errors.Forbidden))
+	require.False(t, called)
+
+	tenantID, _, err := runMiddleware(Middleware(nil), newTestContext(t, "", headers, &auth.DataScope{Mode: auth.ScopeModeGlobal}))
+	require.NoError(t, err)
+	require.Equal(t, "43", tenantID)
+
+	tenantID, _, err = runMiddleware(Middleware(&Options{AllowCrossTenant: true}), newTestContext(t, "", headers, scoped))
+	require.NoError(t, err)
+	require.Equal(t, "43", tenantID)
+}
+
+// TestFromSubdomain 验证子域名解析。
+func TestFromSubdomain(t *testing.T) {
+	resolver := FromSubdomain("example.com")
+	cases := map[string]string{
+		"acme.example.com":      "acme",
+		"ACME.example.com:8443": "acme",
+		"example.com":           "",
+		"a.b.example.com":       "",
+		"acme.other.com":        "",
+	}
+	for host, want := range cases {
+		got, err := resolver.ResolveTenant(newTestContext(t, host, nil, nil))
+		require.NoError(t, err, host)
+		require.Equal(t, want, got, host)
+	}
+}
+
+// TestMessageMiddleware_PropagatesTenant 验证 metadata 与上下文之间的双向传播与冲突拒绝。
+func TestMessageMiddleware_PropagatesTenant(t *testing.T) {
+	mw := NewMessageMiddleware()
+	var seen string
+	next := func(ctx context.Context, msg messaging.IMessage) error {
+		seen = FromContext(ctx)
+		return nil
+	}
+
+	outgoing := messaging.NewMessage("m1", messaging.KindCommand, "CreateOrder", nil)
+	ctx, err := WithTenant(context.Background(), "acme")
+	require.NoError(t, err)
+	require.NoError(t, mw.Handle(ctx, outgoing, next))
+	require.Equal(t, "acme", FromMetadata(outgoing.GetMetadata()))
+
+	incoming := messaging.NewMessage("m2", messaging.KindEvent, "OrderCreated", nil)
+	incoming.GetMetadata().Set(MetadataKey, "globex")
+	require.NoError(t, mw.Handle(context.Background(), incoming, next))
+	require.Equal(t, "globex", seen)
+
+	err = mw.Handle(ctx, incoming, next)
+	require.True(t, errors.Is(err, errors.Forbidden))
+}
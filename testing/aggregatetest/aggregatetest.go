@@ -0,0 +1,315 @@
+// Package aggregatetest 提供事件溯源聚合的 Given/When/Then 测试夹具。
+//
+// 每个场景基于内存事件存储与 app/eventsourced.EventSourcedService 装配独立的仓储与命令服务：
+//
+//	fixture := aggregatetest.New(t, aggregatetest.Config[*Order, int64]{
+//	    AggregateType: "Order",
+//	    Sample:        &Order{},
+//	    Factory:       eventsourced.AdaptAggregateFactory(NewOrder),
+//	    Register: func(s *eventsourced.EventSourcedService[*Order, int64]) error {
+//	        return s.RegisterCommandHandler(&ShipOrder{}, handleShipOrder)
+//	    },
+//	})
+//
+//	fixture.Given(&OrderPlaced{ID: 1}).
+//	    When(&ShipOrder{OrderID: 1}).
+//	    Then(&OrderShipped{ID: 1})
+package aggregatetest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"gochen/app/eventsourced"
+	"gochen/domain"
+	deventsourced "gochen/domain/eventsourced"
+	"gochen/errors"
+	"gochen/eventing/registry"
+	"gochen/eventing/store"
+	"gochen/eventing/upcast"
+)
+
+// Config 定义测试夹具的聚合与命令处理器装配。
+type Config[T deventsourced.IEventSourcedAggregate[ID], ID comparable] struct {
+	// AggregateType 聚合类型（必填）。
+	AggregateType string
+
+	// Sample 用于预编译聚合 metadata 的样例实例（必填）。
+	Sample T
+
+	// Factory 聚合回放工厂（必填）；可用 eventsourced.AdaptAggregateFactory 适配 func(id) T。
+	Factory func(id ID) (T, error)
+
+	// Register 在每个场景的命令服务上注册命令处理器（必填）。
+	Register func(service *eventsourced.EventSourcedService[T, ID]) error
+
+	// MetadataRegistry 可选；为 nil 时使用独立的新注册表。
+	MetadataRegistry *deventsourced.MetadataRegistry
+
+	// ServiceOptions 可选：命令钩子、授权器、并发重试等。
+	ServiceOptions *eventsourced.EventSourcedServiceOptions[T, ID]
+
+	// EventStore 可选：为每个场景创建新的事件存储；为 nil 时使用内存存储（仅支持 int64 聚合 ID）。
+	EventStore func() store.IEventStreamStore[ID]
+}
+
+// Fixture 是某个聚合类型的测试夹具，可创建多个互不影响的场景（并发安全）。
+type Fixture[T deventsourced.IEventSourcedAggregate[ID], ID comparable] struct {
+	t   testing.TB
+	cfg Config[T, ID]
+
+	once     sync.Once
+	metadata *deventsourced.MetadataRegistry
+}
+
+// New 创建测试夹具；配置缺失必填项时立即使测试失败。
+func New[T deventsourced.IEventSourcedAggregate[ID], ID comparable](t testing.TB, cfg Config[T, ID]) *Fixture[T, ID] {
+	t.Helper()
+	switch {
+	case strings.TrimSpace(cfg.AggregateType) == "":
+		t.Fatalf("aggregatetest: AggregateType is required")
+	case cfg.Factory == nil:
+		t.Fatalf("aggregatetest: Factory is required")
+	case cfg.Register == nil:
+		t.Fatalf("aggregatetest: Register is required")
+	}
+	return &Fixture[T, ID]{t: t, cfg: cfg}
+}
+
+// Given 以 events 作为目标聚合的既有历史开始一个场景（可为空，表示新聚合）。
+func (f *Fixture[T, ID]) Given(events ...domain.IDomainEvent) *Scenario[T, ID] {
+	return &Scenario[T, ID]{fixture: f, given: events}
+}
+
+// Scenario 是一个 Given/When/Then 场景。
+type Scenario[T deventsourced.IEventSourcedAggregate[ID], ID comparable] struct {
+	fixture *Fixture[T, ID]
+	given   []domain.IDomainEvent
+}
+
+// When 把 Given 事件写入命令目标聚合的事件流，再执行 cmd。
+func (s *Scenario[T, ID]) When(cmd eventsourced.IEventSourcedCommand[ID]) *Result[T, ID] {
+	return s.WhenContext(context.Background(), cmd)
+}
+
+// WhenContext 与 When 相同，但使用调用方提供的 ctx（例如携带租户或操作人）。
+func (s *Scenario[T, ID]) WhenContext(ctx context.Context, cmd eventsourced.IEventSourcedCommand[ID]) *Result[T, ID] {
+	t := s.fixture.t
+	t.Helper()
+	if cmd == nil {
+		t.Fatalf("aggregatetest: command is nil")
+	}
+
+	env, err := s.fixture.newEnvironment()
+	if err != nil {
+		t.Fatalf("aggregatetest: setup failed: %v", err)
+	}
+	aggregateID := cmd.AggregateID()
+	if len(s.given) > 0 {
+		if err := env.store.AppendEvents(ctx, aggregateID, s.given, 0); err != nil {
+			t.Fatalf("aggregatetest: append given events: %v", err)
+		}
+	}
+	env.store.recording = true
+
+	err = env.service.ExecuteCommand(ctx, cmd)
+	return &Result[T, ID]{t: t, env: env, aggregateID: aggregateID, err: err, events: env.store.recorded}
+}
+
+// Result 是命令执行结果，提供 Then 系列断言；断言失败通过 t.Errorf 报告，可链式调用。
+type Result[T deventsourced.IEventSourcedAggregate[ID], ID comparable] struct {
+	t           testing.TB
+	env         *environment[T, ID]
+	aggregateID ID
+	err         error
+	events      []domain.IDomainEvent
+}
+
+// Then 断言命令成功，且产生的事件与 expected 按顺序逐一相等（reflect.DeepEqual）。
+func (r *Result[T, ID]) Then(expected ...domain.IDomainEvent) *Result[T, ID] {
+	r.t.Helper()
+	if r.err != nil {
+		r.t.Errorf("aggregatetest: expected command to succeed, got error: %v", r.err)
+		return r
+	}
+	if !reflect.DeepEqual(normalizeEvents(r.events), normalizeEvents(expected)) {
+		r.t.Errorf("aggregatetest: unexpected events\n expected: %s\n   actual: %s", describeEvents(expected), describeEvents(r.events))
+	}
+	return r
+}
+
+// ThenNoEvents 断言命令成功且未产生事件。
+func (r *Result[T, ID]) ThenNoEvents() *Result[T, ID] {
+	r.t.Helper()
+	return r.Then()
+}
+
+// ThenError 断言命令失败且错误满足 errors.Is(err, target)（target 可为错误码，如 errors.InvalidInput），并且未持久化任何事件。
+func (r *Result[T, ID]) ThenError(target error) *Result[T, ID] {
+	r.t.Helper()
+	if r.err == nil {
+		r.t.Errorf("aggregatetest: expected error %v, command succeeded with events: %s", target, describeEvents(r.events))
+		return r
+	}
+	if target != nil && !errors.Is(r.err, target) {
+		r.t.Errorf("aggregatetest: expected error %v, got: %v", target, r.err)
+	}
+	if len(r.events) > 0 {
+		r.t.Errorf("aggregatetest: expected no events on error, got: %s", describeEvents(r.events))
+	}
+	return r
+}
+
+// ThenState 重新加载聚合并交给 assert 检查其状态（Given 与命令产生的事件均已回放）。
+func (r *Result[T, ID]) ThenState(assert func(t testing.TB, aggregate T)) *Result[T, ID] {
+	r.t.Helper()
+	aggregate, err := r.env.repository.GetOrCreate(context.Background(), r.aggregateID)
+	if err != nil {
+		r.t.Errorf("aggregatetest: reload aggregate: %v", err)
+		return r
+	}
+	assert(r.t, aggregate)
+	return r
+}
+
+// Err 返回命令执行错误。
+func (r *Result[T, ID]) Err() error { return r.err }
+
+// Events 返回命令产生并持久化的事件。
+func (r *Result[T, ID]) Events() []domain.IDomainEvent {
+	return append([]domain.IDomainEvent(nil), r.events...)
+}
+
+type environment[T deventsourced.IEventSourcedAggregate[ID], ID comparable] struct {
+	store      *recordingStore[ID]
+	repository *eventsourced.EventSourcedRepository[T, ID]
+	service    *eventsourced.EventSourcedService[T, ID]
+}
+
+func (f *Fixture[T, ID]) newEnvironment() (*environment[T, ID], error) {
+	f.once.Do(func() {
+		f.metadata = f.cfg.MetadataRegistry
+		if f.metadata == nil {
+			f.metadata = deventsourced.NewMetadataRegistry()
+		}
+	})
+
+	eventStore, err := f.newEventStore()
+	if err != nil {
+		return nil, err
+	}
+	events := registry.NewRegistry()
+	domainStore, err := eventsourced.NewDomainEventStore(eventsourced.DomainEventStoreOptions[T, ID]{
+		AggregateType:    f.cfg.AggregateType,
+		EventStore:       eventStore,
+		EventRegistry:    events,
+		UpgraderRegistry: upcast.NewUpgraderRegistry(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	recording := &recordingStore[ID]{IDomainEventStore: domainStore, events: events}
+	repository, err := eventsourced.NewEventSourcedRepository(eventsourced.RepositoryOptions[T, ID]{
+		AggregateType:    f.cfg.AggregateType,
+		Sample:           f.cfg.Sample,
+		Factory:          f.cfg.Factory,
+		Store:            recording,
+		MetadataRegistry: f.metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+	service, err := eventsourced.NewEventSourcedService[T, ID](repository, f.cfg.ServiceOptions)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.cfg.Register(service); err != nil {
+		return nil, err
+	}
+	return &environment[T, ID]{store: recording, repository: repository, service: service}, nil
+}
+
+func (f *Fixture[T, ID]) newEventStore() (store.IEventStreamStore[ID], error) {
+	if f.cfg.EventStore != nil {
+		if eventStore := f.cfg.EventStore(); eventStore != nil {
+			return eventStore, nil
+		}
+		return nil, errors.NewCode(errors.InvalidInput, "EventStore returned nil")
+	}
+	if eventStore, ok := any(store.NewMemoryEventStreamStore()).(store.IEventStreamStore[ID]); ok {
+		return eventStore, nil
+	}
+	var zero ID
+	return nil, errors.NewCode(errors.Unsupported, "memory event store only supports int64 aggregate IDs; set Config.EventStore").
+		WithContext("id_type", fmt.Sprintf("%T", zero))
+}
+
+// recordingStore 按需注册事件载荷类型，并记录命令执行期间成功追加的事件。
+type recordingStore[ID comparable] struct {
+	deventsourced.IDomainEventStore[ID]
+	events    *registry.Registry
+	recording bool
+	recorded  []domain.IDomainEvent
+}
+
+func (s *recordingStore[ID]) AppendEvents(ctx context.Context, aggregateID ID, events []domain.IDomainEvent, expectedVersion uint64) error {
+	if err := s.registerEventTypes(events); err != nil {
+		return err
+	}
+	if err := s.IDomainEventStore.AppendEvents(ctx, aggregateID, events, expectedVersion); err != nil {
+		return err
+	}
+	if s.recording {
+		s.recorded = append(s.recorded, events...)
+	}
+	return nil
+}
+
+// registerEventTypes 为尚未注册的事件类型注册载荷工厂，测试无需预先声明事件类型。
+func (s *recordingStore[ID]) registerEventTypes(events []domain.IDomainEvent) error {
+	for _, evt := range events {
+		if evt == nil {
+			return errors.NewCode(errors.InvalidInput, "event is nil")
+		}
+		eventType := evt.EventType()
+		if s.events.HasEvent(eventType) {
+			continue
+		}
+		typ := reflect.TypeOf(evt)
+		if err := s.events.Register(eventType, func() any {
+			if typ.Kind() == reflect.Pointer {
+				return reflect.New(typ.Elem()).Interface()
+			}
+			return reflect.New(typ).Interface()
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func normalizeEvents(events []domain.IDomainEvent) []domain.IDomainEvent {
+	if len(events) == 0 {
+		return nil
+	}
+	return events
+}
+
+func describeEvents(events []domain.IDomainEvent) string {
+	if len(events) == 0 {
+		return "[]"
+	}
+	parts := make([]string, 0, len(events))
+	for _, evt := range events {
+		if evt == nil {
+			parts = append(parts, "<nil>")
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s%+v", evt.EventType(), reflect.Indirect(reflect.ValueOf(evt)).Interface()))
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
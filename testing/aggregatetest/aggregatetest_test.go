@@ -0,0 +1,120 @@
+package aggregatetest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gochen/app/eventsourced"
+	deventsourced "gochen/domain/eventsourced"
+	"gochen/errors"
+)
+
+var testMetadata = deventsourced.NewMetadataRegistry()
+
+type accountOpened struct{ Owner string }
+
+func (e *accountOpened) EventType() string { return "AccountOpened" }
+
+type moneyDeposited struct{ Amount int }
+
+func (e *moneyDeposited) EventType() string { return "MoneyDeposited" }
+
+type account struct {
+	*deventsourced.EventSourcedAggregate[int64]
+	Opened  bool
+	Balance int
+}
+
+func newAccount(id int64) *account {
+	a := &account{}
+	agg, err := deventsourced.InitAggregate[int64](testMetadata, a, id, "Account")
+	if err != nil {
+		panic(err)
+	}
+	a.EventSourcedAggregate = agg
+	return a
+}
+
+func (a *account) ApplyAccountOpened(*accountOpened) { a.Opened = true }
+
+func (a *account) ApplyMoneyDeposited(evt *moneyDeposited) { a.Balance += evt.Amount }
+
+type deposit struct {
+	ID     int64
+	Amount int
+}
+
+func (c *deposit) AggregateID() int64 { return c.ID }
+
+func newAccountFixture(t testing.TB) *Fixture[*account, int64] {
+	return New(t, Config[*account, int64]{
+		AggregateType:    "Account",
+		Sample:           &account{},
+		Factory:          eventsourced.AdaptAggregateFactory(newAccount),
+		MetadataRegistry: testMetadata,
+		Register: func(s *eventsourced.EventSourcedService[*account, int64]) error {
+			return s.RegisterCommandHandler(&deposit{}, func(_ context.Context, cmd eventsourced.IEventSourcedCommand[int64], a *account) error {
+				c := cmd.(*deposit)
+				if !a.Opened {
+					return errors.NewCode(errors.NotFound, "account not opened")
+				}
+				if c.Amount <= 0 {
+					return errors.NewCode(errors.InvalidInput, "amount must be positive")
+				}
+				return a.ApplyAndRecord(&moneyDeposited{Amount: c.Amount})
+			})
+		},
+	})
+}
+
+func TestFixture_GivenWhenThen(t *testing.T) {
+	fixture := newAccountFixture(t)
+
+	fixture.Given(&accountOpened{Owner: "alice"}, &moneyDeposited{Amount: 10}).
+		When(&deposit{ID: 1, Amount: 5}).
+		Then(&moneyDeposited{Amount: 5}).
+		ThenState(func(t testing.TB, a *account) {
+			if a.Balance != 15 {
+				t.Errorf("expected balance 15, got %d", a.Balance)
+			}
+		})
+
+	fixture.Given(&accountOpened{Owner: "alice"}).
+		When(&deposit{ID: 1, Amount: 0}).
+		ThenError(errors.InvalidInput)
+
+	// 每个场景使用独立的事件存储：前一个场景的历史不可见。
+	fixture.Given().
+		When(&deposit{ID: 1, Amount: 5}).
+		ThenError(errors.NotFound)
+}
+
+// recordingTB 记录断言失败而不终止测试，用于验证夹具的失败报告。
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestFixture_ReportsMismatches(t *testing.T) {
+	rec := &recordingTB{TB: t}
+	fixture := newAccountFixture(rec)
+
+	fixture.Given(&accountOpened{}).When(&deposit{ID: 1, Amount: 5}).Then(&moneyDeposited{Amount: 6})
+	fixture.Given(&accountOpened{}).When(&deposit{ID: 1, Amount: 5}).ThenError(errors.InvalidInput)
+	fixture.Given(&accountOpened{}).When(&deposit{ID: 1, Amount: -1}).ThenNoEvents()
+
+	if len(rec.failures) != 3 {
+		t.Fatalf("expected 3 reported failures, got %d: %v", len(rec.failures), rec.failures)
+	}
+	if !strings.Contains(rec.failures[0], "MoneyDeposited{Amount:6}") || !strings.Contains(rec.failures[0], "MoneyDeposited{Amount:5}") {
+		t.Fatalf("expected event diff in failure message, got: %s", rec.failures[0])
+	}
+}
@@ -0,0 +1,46 @@
+package projectiontest
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// UpdateGoldenEnv 为非空时，ExpectGolden 用当前读模型覆盖 golden 文件而不是比对。
+//
+//	GOCHEN_UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "GOCHEN_UPDATE_GOLDEN"
+
+// ExpectGolden 把 state 序列化为缩进 JSON，并与 testdata/<name>.golden.json 比对；不一致时报告差异。
+//
+// state 通常是读模型的快照（map 键按字典序输出，结果稳定）；golden 文件不存在时需先以 UpdateGoldenEnv 生成。
+func ExpectGolden(t testing.TB, name string, state any) {
+	t.Helper()
+	actual, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		t.Fatalf("projectiontest: marshal read model %q: %v", name, err)
+	}
+	actual = append(actual, '\n')
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("projectiontest: create golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatalf("projectiontest: write golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("projectiontest: read golden file %s (run with %s=1 to create it): %v", path, UpdateGoldenEnv, err)
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("projectiontest: read model %q does not match %s (run with %s=1 to update)\n expected:\n%s\n   actual:\n%s",
+			name, path, UpdateGoldenEnv, expected, actual)
+	}
+}
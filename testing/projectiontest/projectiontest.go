@@ -0,0 +1,208 @@
+// Package projectiontest 提供投影的单元测试工具：按顺序同步投喂事件夹具，断言 checkpoint，并以 golden 文件比对读模型。
+//
+// 无需事件总线或基于 sleep 的同步：
+//
+//	events := projectiontest.NewStream[int64]("Order").
+//	    Append(1, "OrderPlaced", &OrderPlaced{Total: 10}).
+//	    Append(1, "OrderShipped", &OrderShipped{})
+//
+//	projectiontest.Feed(t, proj, events.Events()...).
+//	    ExpectCheckpoint(2, events.LastID())
+//	projectiontest.ExpectGolden(t, "order_summary", proj.Snapshot())
+//
+// 投喂语义与 ProjectionManager 一致：仅投递 SupportedEventTypes 声明的事件类型；实现 ICheckpointingProjection 的投影
+// 走 HandleWithCheckpoint，并把 checkpoint 写入内存存储；处理器 panic 转换为错误。
+package projectiontest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/projection"
+	"gochen/messaging"
+)
+
+// Result 记录投喂进度，可继续投喂并断言 checkpoint；断言失败通过 t.Errorf 报告，可链式调用。
+type Result[ID comparable] struct {
+	t          testing.TB
+	projection projection.IProjection[ID]
+	store      *projection.MemoryCheckpointStore
+	checkpoint projection.Checkpoint
+	handled    []string
+	err        error
+}
+
+// Feed 按顺序把 events 同步投喂给 p；处理失败时立即终止测试。
+func Feed[ID comparable](t testing.TB, p projection.IProjection[ID], events ...eventing.IEvent) *Result[ID] {
+	t.Helper()
+	if p == nil {
+		t.Fatalf("projectiontest: projection is nil")
+	}
+	return newResult(t, p).Feed(events...)
+}
+
+// TryFeed 与 Feed 相同，但处理失败时不终止测试，而是停在失败事件之前并通过 Err 返回错误，用于验证错误路径。
+func TryFeed[ID comparable](t testing.TB, p projection.IProjection[ID], events ...eventing.IEvent) *Result[ID] {
+	t.Helper()
+	if p == nil {
+		t.Fatalf("projectiontest: projection is nil")
+	}
+	r := newResult(t, p)
+	r.err = r.feed(events)
+	return r
+}
+
+func newResult[ID comparable](t testing.TB, p projection.IProjection[ID]) *Result[ID] {
+	return &Result[ID]{
+		t:          t,
+		projection: p,
+		store:      projection.NewMemoryCheckpointStore(),
+		checkpoint: projection.Checkpoint{ProjectionName: p.Name()},
+	}
+}
+
+// Feed 在已有进度上继续投喂 events；处理失败时立即终止测试。
+func (r *Result[ID]) Feed(events ...eventing.IEvent) *Result[ID] {
+	r.t.Helper()
+	if err := r.feed(events); err != nil {
+		r.t.Fatalf("projectiontest: %v", err)
+	}
+	return r
+}
+
+func (r *Result[ID]) feed(events []eventing.IEvent) error {
+	if r.err != nil {
+		return r.err
+	}
+	supported := r.projection.SupportedEventTypes()
+	for i, evt := range events {
+		if evt == nil {
+			return errors.NewCode(errors.InvalidInput, "event is nil").WithContext("index", i)
+		}
+		if !slices.Contains(supported, evt.GetType()) {
+			continue
+		}
+		next := projection.NewCheckpoint(r.projection.Name(), r.checkpoint.Position+1, evt.GetID(), evt.GetTimestamp())
+		if err := r.handle(evt, next); err != nil {
+			return errors.Wrap(err, errors.Internal, "projection failed to handle event").
+				WithContext("projection", r.projection.Name()).
+				WithContext("index", i).
+				WithContext("event_id", evt.GetID()).
+				WithContext("event_type", evt.GetType())
+		}
+		r.checkpoint = *next
+		r.handled = append(r.handled, evt.GetID())
+	}
+	return nil
+}
+
+func (r *Result[ID]) handle(evt eventing.IEvent, next *projection.Checkpoint) error {
+	return messaging.CallRecovered("projection handler panicked", func() error {
+		if cp, ok := r.projection.(projection.ICheckpointingProjection[ID]); ok {
+			return cp.HandleWithCheckpoint(context.Background(), evt, r.store, next)
+		}
+		return r.projection.Handle(context.Background(), evt)
+	})
+}
+
+// Err 返回 TryFeed 遇到的处理错误。
+func (r *Result[ID]) Err() error { return r.err }
+
+// Handled 返回投影实际处理的事件 ID（已过滤不支持的事件类型）。
+func (r *Result[ID]) Handled() []string { return slices.Clone(r.handled) }
+
+// Checkpoint 返回按投喂进度推进的 checkpoint（Position 为已处理事件数）。
+func (r *Result[ID]) Checkpoint() projection.Checkpoint { return r.checkpoint }
+
+// CheckpointStore 返回 checkpoint 模式投影写入的内存存储。
+func (r *Result[ID]) CheckpointStore() *projection.MemoryCheckpointStore { return r.store }
+
+// ExpectCheckpoint 断言 checkpoint 位置与最后处理的事件 ID；checkpoint 模式投影还会核对其写入存储的 checkpoint。
+func (r *Result[ID]) ExpectCheckpoint(position int64, lastEventID string) *Result[ID] {
+	r.t.Helper()
+	if r.checkpoint.Position != position || r.checkpoint.LastEventID != lastEventID {
+		r.t.Errorf("projectiontest: expected checkpoint (position=%d, last_event_id=%q), got (position=%d, last_event_id=%q)",
+			position, lastEventID, r.checkpoint.Position, r.checkpoint.LastEventID)
+		return r
+	}
+	if _, ok := r.projection.(projection.ICheckpointingProjection[ID]); !ok || position == 0 {
+		return r
+	}
+	saved, err := r.store.Load(context.Background(), r.projection.Name())
+	if err != nil {
+		r.t.Errorf("projectiontest: load saved checkpoint: %v", err)
+		return r
+	}
+	if saved.Position != position || saved.LastEventID != lastEventID {
+		r.t.Errorf("projectiontest: projection saved checkpoint (position=%d, last_event_id=%q), expected (position=%d, last_event_id=%q)",
+			saved.Position, saved.LastEventID, position, lastEventID)
+	}
+	return r
+}
+
+// ExpectHandled 断言投影按顺序处理的事件 ID。
+func (r *Result[ID]) ExpectHandled(eventIDs ...string) *Result[ID] {
+	r.t.Helper()
+	if !slices.Equal(r.handled, eventIDs) {
+		r.t.Errorf("projectiontest: unexpected handled events\n expected: %v\n   actual: %v", eventIDs, r.handled)
+	}
+	return r
+}
+
+// ExpectStatus 断言投影自报告的状态（IProjection.Status）中已处理与失败事件计数。
+func (r *Result[ID]) ExpectStatus(processed, failed int64) *Result[ID] {
+	r.t.Helper()
+	status := r.projection.Status()
+	if status.ProcessedEvents != processed || status.FailedEvents != failed {
+		r.t.Errorf("projectiontest: expected status (processed=%d, failed=%d), got (processed=%d, failed=%d)",
+			processed, failed, status.ProcessedEvents, status.FailedEvents)
+	}
+	return r
+}
+
+// Stream 按顺序构造确定性的事件夹具：事件 ID 为 "evt-<n>"，版本按聚合递增，
+// 时间戳从 2025-01-01 UTC 起每条递增 1 秒。
+type Stream[ID comparable] struct {
+	aggregateType string
+	events        []eventing.IEvent
+	versions      map[ID]uint64
+	start         time.Time
+}
+
+// NewStream 创建聚合类型为 aggregateType 的事件夹具。
+func NewStream[ID comparable](aggregateType string) *Stream[ID] {
+	return &Stream[ID]{
+		aggregateType: aggregateType,
+		versions:      make(map[ID]uint64),
+		start:         time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// Append 追加一条事件。
+func (s *Stream[ID]) Append(aggregateID ID, eventType string, payload any) *Stream[ID] {
+	s.versions[aggregateID]++
+	n := len(s.events) + 1
+	evt := eventing.NewEvent(aggregateID, s.aggregateType, eventType, s.versions[aggregateID], payload)
+	evt.ID = fmt.Sprintf("evt-%d", n)
+	evt.Timestamp = s.start.Add(time.Duration(n) * time.Second)
+	s.events = append(s.events, evt)
+	return s
+}
+
+// Events 返回已追加的事件。
+func (s *Stream[ID]) Events() []eventing.IEvent {
+	return slices.Clone(s.events)
+}
+
+// LastID 返回最后一条事件的 ID；无事件时返回空字符串。
+func (s *Stream[ID]) LastID() string {
+	if len(s.events) == 0 {
+		return ""
+	}
+	return s.events[len(s.events)-1].GetID()
+}
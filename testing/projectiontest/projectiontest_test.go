@@ -0,0 +1,145 @@
+package projectiontest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/projection"
+	"gochen/messaging"
+)
+
+type orderPlaced struct{ Total int }
+
+// orderTotals 按聚合累计订单金额的读模型投影。
+type orderTotals struct {
+	totals    map[int64]int
+	processed int64
+}
+
+func newOrderTotals() *orderTotals { return &orderTotals{totals: make(map[int64]int)} }
+
+func (p *orderTotals) Name() string { return "order_totals" }
+
+func (p *orderTotals) Handle(_ context.Context, evt eventing.IEvent) error {
+	payload, ok := messaging.PayloadAs[*orderPlaced](evt.GetPayload())
+	if !ok {
+		return errors.NewCode(errors.InvalidInput, "unexpected payload")
+	}
+	if payload.Total < 0 {
+		panic("negative total")
+	}
+	p.totals[evt.(*eventing.Event[int64]).GetAggregateID()] += payload.Total
+	p.processed++
+	return nil
+}
+
+func (p *orderTotals) SupportedEventTypes() []string { return []string{"OrderPlaced"} }
+
+func (p *orderTotals) Rebuild(context.Context, []eventing.Event[int64]) error { return nil }
+
+func (p *orderTotals) Status() projection.ProjectionStatus {
+	return projection.ProjectionStatus{Name: p.Name(), ProcessedEvents: p.processed}
+}
+
+func (p *orderTotals) snapshot() map[string]int {
+	out := make(map[string]int, len(p.totals))
+	for id, total := range p.totals {
+		out[fmt.Sprint(id)] = total
+	}
+	return out
+}
+
+// checkpointingTotals 在 checkpoint 模式下自行保存 checkpoint。
+type checkpointingTotals struct{ *orderTotals }
+
+func (p checkpointingTotals) HandleWithCheckpoint(ctx context.Context, evt eventing.IEvent, store projection.ICheckpointStore, cp *projection.Checkpoint) error {
+	if err := p.Handle(ctx, evt); err != nil {
+		return err
+	}
+	return store.Save(ctx, cp)
+}
+
+func orderEvents() *Stream[int64] {
+	return NewStream[int64]("Order").
+		Append(1, "OrderPlaced", &orderPlaced{Total: 10}).
+		Append(2, "OrderPlaced", &orderPlaced{Total: 5}).
+		Append(1, "OrderShipped", nil).
+		Append(1, "OrderPlaced", &orderPlaced{Total: 7})
+}
+
+func TestFeed_FiltersUnsupportedEventsAndTracksCheckpoint(t *testing.T) {
+	proj := newOrderTotals()
+	events := orderEvents()
+
+	Feed[int64](t, proj, events.Events()...).
+		ExpectHandled("evt-1", "evt-2", "evt-4").
+		ExpectCheckpoint(3, "evt-4").
+		ExpectStatus(3, 0)
+
+	ExpectGolden(t, "order_totals", proj.snapshot())
+}
+
+func TestFeed_CheckpointingProjectionSavesCheckpoint(t *testing.T) {
+	proj := checkpointingTotals{newOrderTotals()}
+	events := orderEvents()
+
+	result := Feed[int64](t, proj, events.Events()[:2]...).ExpectCheckpoint(2, "evt-2")
+	result.Feed(events.Events()[2:]...).ExpectCheckpoint(3, "evt-4")
+
+	saved, err := result.CheckpointStore().Load(context.Background(), "order_totals")
+	if err != nil {
+		t.Fatalf("load checkpoint: %v", err)
+	}
+	if !saved.LastEventTime.Equal(events.Events()[3].GetTimestamp()) {
+		t.Fatalf("expected checkpoint time from last event, got %v", saved.LastEventTime)
+	}
+}
+
+func TestTryFeed_StopsAtFailingEventAndRecoversPanics(t *testing.T) {
+	events := NewStream[int64]("Order").
+		Append(1, "OrderPlaced", &orderPlaced{Total: 10}).
+		Append(1, "OrderPlaced", &orderPlaced{Total: -1}).
+		Append(1, "OrderPlaced", &orderPlaced{Total: 3})
+
+	result := TryFeed[int64](t, newOrderTotals(), events.Events()...).
+		ExpectHandled("evt-1").
+		ExpectCheckpoint(1, "evt-1")
+	if !messaging.IsPanicError(result.Err()) {
+		t.Fatalf("expected panic to surface as error, got: %v", result.Err())
+	}
+}
+
+// recordingTB 记录断言失败而不终止测试，用于验证断言的失败报告。
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestExpectations_ReportMismatches(t *testing.T) {
+	rec := &recordingTB{TB: t}
+	proj := newOrderTotals()
+
+	Feed[int64](rec, proj, orderEvents().Events()...).
+		ExpectHandled("evt-1").
+		ExpectCheckpoint(4, "evt-4").
+		ExpectStatus(1, 0)
+	proj.totals[3] = 1
+	ExpectGolden(rec, "order_totals", proj.snapshot())
+
+	if len(rec.failures) != 4 {
+		t.Fatalf("expected 4 reported failures, got %d: %v", len(rec.failures), rec.failures)
+	}
+	if !strings.Contains(rec.failures[3], `"3": 1`) {
+		t.Fatalf("expected golden diff to show actual read model, got: %s", rec.failures[3])
+	}
+}
@@ -0,0 +1,295 @@
+package sagatest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/eventing"
+	"gochen/eventing/bus"
+	"gochen/messaging"
+	"gochen/messaging/command"
+	"gochen/process/saga"
+)
+
+// CommandBus 是按命令类型编排结果的命令执行器，并记录所有执行过的命令（并发安全）。
+//
+// 未编排的命令类型默认执行成功。
+type CommandBus struct {
+	mu       sync.Mutex
+	scripts  map[string]*commandScript
+	executed []*command.Command
+}
+
+type commandScript struct {
+	failures int
+	always   bool
+	err      error
+	handler  func(ctx context.Context, cmd *command.Command) error
+}
+
+var _ command.ICommandExecutor = (*CommandBus)(nil)
+
+// NewCommandBus 创建编排式命令执行器。
+func NewCommandBus() *CommandBus {
+	return &CommandBus{scripts: make(map[string]*commandScript)}
+}
+
+// Succeed 使 commandType 的命令始终成功（覆盖此前的编排）。
+func (b *CommandBus) Succeed(commandType string) *CommandBus {
+	return b.script(commandType, &commandScript{})
+}
+
+// Fail 使 commandType 的命令始终返回 err；err 为 nil 时返回 errors.Internal。
+func (b *CommandBus) Fail(commandType string, err error) *CommandBus {
+	return b.script(commandType, &commandScript{always: true, err: err})
+}
+
+// FailTimes 使 commandType 的前 n 次执行返回 err、之后成功，用于验证步骤重试。
+func (b *CommandBus) FailTimes(commandType string, n int, err error) *CommandBus {
+	return b.script(commandType, &commandScript{failures: n, err: err})
+}
+
+// Handle 使用 handler 执行 commandType 的命令，用于按载荷决定结果或模拟副作用。
+func (b *CommandBus) Handle(commandType string, handler func(ctx context.Context, cmd *command.Command) error) *CommandBus {
+	return b.script(commandType, &commandScript{handler: handler})
+}
+
+func (b *CommandBus) script(commandType string, s *commandScript) *CommandBus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.scripts[commandType] = s
+	return b
+}
+
+// Execute 记录命令并按编排返回结果。
+func (b *CommandBus) Execute(ctx context.Context, cmd *command.Command) error {
+	if cmd == nil {
+		return errors.NewCode(errors.InvalidInput, "command is nil")
+	}
+	b.mu.Lock()
+	b.executed = append(b.executed, cmd)
+	s := b.scripts[cmd.GetType()]
+	var err error
+	switch {
+	case s == nil:
+	case s.handler != nil:
+	case s.always:
+		err = scriptedError(cmd, s.err)
+	case s.failures > 0:
+		s.failures--
+		err = scriptedError(cmd, s.err)
+	}
+	b.mu.Unlock()
+
+	if s != nil && s.handler != nil {
+		return s.handler(ctx, cmd)
+	}
+	return err
+}
+
+// Executed 返回按执行顺序记录的命令。
+func (b *CommandBus) Executed() []*command.Command {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*command.Command(nil), b.executed...)
+}
+
+// ExecutedTypes 返回按执行顺序记录的命令类型。
+func (b *CommandBus) ExecutedTypes() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	types := make([]string, 0, len(b.executed))
+	for _, cmd := range b.executed {
+		types = append(types, cmd.GetType())
+	}
+	return types
+}
+
+// Calls 返回 commandType 的执行次数（含失败）。
+func (b *CommandBus) Calls(commandType string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := 0
+	for _, cmd := range b.executed {
+		if cmd.GetType() == commandType {
+			n++
+		}
+	}
+	return n
+}
+
+func scriptedError(cmd *command.Command, err error) error {
+	if err != nil {
+		return err
+	}
+	return errors.NewCode(errors.Internal, "scripted command failure").
+		WithContext("command_type", cmd.GetType())
+}
+
+// EventRecorder 是记录 Saga 生命周期事件的事件总线（并发安全）；订阅为空操作。
+type EventRecorder struct {
+	mu     sync.Mutex
+	events []eventing.IEvent
+}
+
+var _ bus.IEventBus = (*EventRecorder)(nil)
+
+// NewEventRecorder 创建事件记录器。
+func NewEventRecorder() *EventRecorder {
+	return &EventRecorder{}
+}
+
+// PublishEvent 记录事件。
+func (r *EventRecorder) PublishEvent(_ context.Context, evt eventing.IEvent) error {
+	if evt == nil {
+		return errors.NewCode(errors.InvalidInput, "event is nil")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, evt)
+	return nil
+}
+
+// PublishEvents 按顺序记录事件。
+func (r *EventRecorder) PublishEvents(ctx context.Context, events []eventing.IEvent) error {
+	for _, evt := range events {
+		if err := r.PublishEvent(ctx, evt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Publish 记录事件消息，非事件消息被忽略。
+func (r *EventRecorder) Publish(ctx context.Context, message messaging.IMessage) error {
+	if evt, ok := message.(eventing.IEvent); ok {
+		return r.PublishEvent(ctx, evt)
+	}
+	return nil
+}
+
+// PublishAll 按顺序记录事件消息。
+func (r *EventRecorder) PublishAll(ctx context.Context, messages []messaging.IMessage) error {
+	for _, message := range messages {
+		if err := r.Publish(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Subscribe 为空操作。
+func (r *EventRecorder) Subscribe(context.Context, string, messaging.IMessageHandler) (messaging.UnsubscribeFunc, error) {
+	return func(context.Context) error { return nil }, nil
+}
+
+// SubscribeEvent 为空操作。
+func (r *EventRecorder) SubscribeEvent(context.Context, string, bus.IEventHandler) (messaging.UnsubscribeFunc, error) {
+	return func(context.Context) error { return nil }, nil
+}
+
+// SubscribeHandler 为空操作。
+func (r *EventRecorder) SubscribeHandler(context.Context, bus.IEventHandler) (messaging.UnsubscribeFunc, error) {
+	return func(context.Context) error { return nil }, nil
+}
+
+// Use 为空操作。
+func (r *EventRecorder) Use(messaging.IMiddleware) {}
+
+// Events 返回按发布顺序记录的事件。
+func (r *EventRecorder) Events() []eventing.IEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]eventing.IEvent(nil), r.events...)
+}
+
+// Types 返回按发布顺序记录的 Saga 事件类型。
+func (r *EventRecorder) Types() []saga.SagaEventType {
+	return eventTypes(r.Events())
+}
+
+func eventTypes(events []eventing.IEvent) []saga.SagaEventType {
+	types := make([]saga.SagaEventType, 0, len(events))
+	for _, evt := range events {
+		types = append(types, saga.SagaEventType(evt.GetType()))
+	}
+	return types
+}
+
+// StateStore 是内存 Saga 状态存储，并按顺序记录每次 Save/Update 写入的状态快照。
+type StateStore struct {
+	*saga.MemorySagaStateStore
+
+	mu      sync.Mutex
+	history map[string][]*saga.SagaState
+}
+
+var _ saga.ISagaStateStore = (*StateStore)(nil)
+
+// NewStateStore 创建内存 Saga 状态存储。
+func NewStateStore() *StateStore {
+	return &StateStore{
+		MemorySagaStateStore: saga.NewMemorySagaStateStore(),
+		history:              make(map[string][]*saga.SagaState),
+	}
+}
+
+// Save 保存初始状态并记录快照。
+func (s *StateStore) Save(ctx context.Context, state *saga.SagaState) error {
+	if err := s.MemorySagaStateStore.Save(ctx, state); err != nil {
+		return err
+	}
+	s.record(state)
+	return nil
+}
+
+// Update 更新状态并记录快照。
+func (s *StateStore) Update(ctx context.Context, state *saga.SagaState) error {
+	if err := s.MemorySagaStateStore.Update(ctx, state); err != nil {
+		return err
+	}
+	s.record(state)
+	return nil
+}
+
+func (s *StateStore) record(state *saga.SagaState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history[state.SagaID] = append(s.history[state.SagaID], state.Clone())
+}
+
+// History 返回 sagaID 依次写入的状态快照。
+func (s *StateStore) History(sagaID string) []*saga.SagaState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	history := make([]*saga.SagaState, 0, len(s.history[sagaID]))
+	for _, state := range s.history[sagaID] {
+		history = append(history, state.Clone())
+	}
+	return history
+}
+
+// Statuses 返回 sagaID 依次写入的状态值（相邻重复值合并）。
+func (s *StateStore) Statuses(sagaID string) []saga.SagaStatus {
+	var statuses []saga.SagaStatus
+	for _, state := range s.History(sagaID) {
+		if n := len(statuses); n == 0 || statuses[n-1] != state.Status {
+			statuses = append(statuses, state.Status)
+		}
+	}
+	return statuses
+}
+
+// instantClock 在创建 timer 时立即推进手动时钟，使重试退避无需真实等待且时间可预期。
+type instantClock struct {
+	*clock.ManualClock
+}
+
+func (c instantClock) NewTimer(d time.Duration) clock.ITimer {
+	timer := c.ManualClock.NewTimer(d)
+	c.ManualClock.Advance(d)
+	return timer
+}
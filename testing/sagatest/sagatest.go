@@ -0,0 +1,192 @@
+// Package sagatest 提供 Saga 编排器的确定性测试工具，无需装配真实的命令与事件总线。
+//
+// Harness 把编排器接到内存状态存储（StateStore）、按命令类型编排结果的命令执行器（CommandBus）、
+// 记录生命周期事件的事件总线（EventRecorder）与手动时钟上；重试退避立即推进时钟，不产生真实等待：
+//
+//	h := sagatest.New(t)
+//	h.Commands.Fail("ChargePayment", errors.NewCode(errors.Dependency, "gateway down"))
+//
+//	h.Execute(&OrderSaga{ID: "order-1"}).
+//	    ExpectError(errors.Internal).
+//	    ExpectCommands("ReserveStock", "ChargePayment", "ReleaseStock").
+//	    ExpectEventsInclude(saga.EventSagaStepFailed, saga.EventSagaCompensationCompleted).
+//	    ExpectStatus(saga.SagaStatusCompensated)
+package sagatest
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"gochen/clock"
+	"gochen/errors"
+	"gochen/process/saga"
+)
+
+// Harness 是单个测试内的 Saga 编排环境；各组件可在执行前直接编排或检查。
+type Harness struct {
+	t testing.TB
+
+	// Commands 编排式命令执行器。
+	Commands *CommandBus
+	// Events 记录编排器发布的 Saga 事件。
+	Events *EventRecorder
+	// States 内存状态存储，记录每次写入的快照。
+	States *StateStore
+	// Clock 编排器使用的手动时钟（起点 2025-01-01 UTC）。
+	Clock *clock.ManualClock
+	// Orchestrator 已装配的编排器；可继续调用 WithLockProvider 等选项。
+	Orchestrator *saga.SagaOrchestrator
+}
+
+// New 创建测试环境。
+func New(t testing.TB) *Harness {
+	t.Helper()
+	h := &Harness{
+		t:        t,
+		Commands: NewCommandBus(),
+		Events:   NewEventRecorder(),
+		States:   NewStateStore(),
+		Clock:    clock.NewManualClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+	}
+	h.Orchestrator = saga.NewSagaOrchestrator(h.Commands, h.Events, h.States).
+		WithClock(instantClock{ManualClock: h.Clock})
+	return h
+}
+
+// Execute 执行 Saga，返回本次执行的结果。
+func (h *Harness) Execute(s saga.ISaga) *Result {
+	h.t.Helper()
+	return h.run(s, func(ctx context.Context) error { return h.Orchestrator.Execute(ctx, s) })
+}
+
+// Resume 从 States 中已保存的状态恢复执行 Saga。
+func (h *Harness) Resume(s saga.ISaga) *Result {
+	h.t.Helper()
+	return h.run(s, func(ctx context.Context) error {
+		state, err := h.States.Load(ctx, s.ID())
+		if err != nil {
+			return err
+		}
+		return h.Orchestrator.Resume(ctx, s, state)
+	})
+}
+
+func (h *Harness) run(s saga.ISaga, fn func(ctx context.Context) error) *Result {
+	h.t.Helper()
+	if s == nil {
+		h.t.Fatalf("sagatest: saga is nil")
+	}
+	commandOffset := len(h.Commands.Executed())
+	eventOffset := len(h.Events.Events())
+	err := fn(context.Background())
+	return &Result{
+		t:        h.t,
+		harness:  h,
+		sagaID:   s.ID(),
+		err:      err,
+		commands: h.Commands.ExecutedTypes()[commandOffset:],
+		events:   eventTypes(h.Events.Events()[eventOffset:]),
+	}
+}
+
+// Result 是一次 Execute/Resume 的结果，仅包含该次调用产生的命令与事件；断言失败通过 t.Errorf 报告，可链式调用。
+type Result struct {
+	t        testing.TB
+	harness  *Harness
+	sagaID   string
+	err      error
+	commands []string
+	events   []saga.SagaEventType
+}
+
+// ExpectSuccess 断言 Saga 执行成功。
+func (r *Result) ExpectSuccess() *Result {
+	r.t.Helper()
+	if r.err != nil {
+		r.t.Errorf("sagatest: expected saga %q to succeed, got error: %v", r.sagaID, r.err)
+	}
+	return r
+}
+
+// ExpectError 断言 Saga 执行失败且错误满足 errors.Is(err, target)（target 可为错误码）；target 为 nil 时只断言失败。
+func (r *Result) ExpectError(target error) *Result {
+	r.t.Helper()
+	if r.err == nil {
+		r.t.Errorf("sagatest: expected saga %q to fail with %v, it succeeded", r.sagaID, target)
+		return r
+	}
+	if target != nil && !errors.Is(r.err, target) {
+		r.t.Errorf("sagatest: expected saga %q error %v, got: %v", r.sagaID, target, r.err)
+	}
+	return r
+}
+
+// ExpectCommands 断言执行过的命令类型与 expected 按顺序完全一致（含重试与补偿命令）。
+func (r *Result) ExpectCommands(expected ...string) *Result {
+	r.t.Helper()
+	if !slices.Equal(r.commands, expected) {
+		r.t.Errorf("sagatest: unexpected commands\n expected: %v\n   actual: %v", expected, r.commands)
+	}
+	return r
+}
+
+// ExpectEvents 断言发布的 Saga 事件类型与 expected 按顺序完全一致。
+func (r *Result) ExpectEvents(expected ...saga.SagaEventType) *Result {
+	r.t.Helper()
+	if !slices.Equal(r.events, expected) {
+		r.t.Errorf("sagatest: unexpected saga events\n expected: %v\n   actual: %v", expected, r.events)
+	}
+	return r
+}
+
+// ExpectEventsInclude 断言 expected 按顺序出现在发布的事件中（允许中间夹杂其他事件），适用于并行步骤等顺序不完全确定的场景。
+func (r *Result) ExpectEventsInclude(expected ...saga.SagaEventType) *Result {
+	r.t.Helper()
+	i := 0
+	for _, eventType := range r.events {
+		if i < len(expected) && eventType == expected[i] {
+			i++
+		}
+	}
+	if i < len(expected) {
+		r.t.Errorf("sagatest: saga events do not include %v in order\n   actual: %v", expected, r.events)
+	}
+	return r
+}
+
+// ExpectStatus 断言状态存储中 Saga 的最终状态。
+func (r *Result) ExpectStatus(expected saga.SagaStatus) *Result {
+	r.t.Helper()
+	state, err := r.harness.States.Load(context.Background(), r.sagaID)
+	if err != nil {
+		r.t.Errorf("sagatest: load saga %q state: %v", r.sagaID, err)
+		return r
+	}
+	if state.Status != expected {
+		r.t.Errorf("sagatest: expected saga %q status %q, got %q", r.sagaID, expected, state.Status)
+	}
+	return r
+}
+
+// ExpectState 加载 Saga 最终状态并交给 assert 检查（例如已完成步骤、失败步骤与自定义数据）。
+func (r *Result) ExpectState(assert func(t testing.TB, state *saga.SagaState)) *Result {
+	r.t.Helper()
+	state, err := r.harness.States.Load(context.Background(), r.sagaID)
+	if err != nil {
+		r.t.Errorf("sagatest: load saga %q state: %v", r.sagaID, err)
+		return r
+	}
+	assert(r.t, state)
+	return r
+}
+
+// Err 返回编排器返回的错误。
+func (r *Result) Err() error { return r.err }
+
+// Commands 返回本次执行过的命令类型。
+func (r *Result) Commands() []string { return slices.Clone(r.commands) }
+
+// Events 返回本次发布的 Saga 事件类型。
+func (r *Result) Events() []saga.SagaEventType { return slices.Clone(r.events) }
@@ -0,0 +1,130 @@
+package sagatest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"gochen/errors"
+	"gochen/messaging/command"
+	"gochen/process/saga"
+)
+
+type orderSaga struct {
+	saga.BaseSaga
+	id    string
+	retry *saga.RetryPolicy
+}
+
+func (s *orderSaga) ID() string { return s.id }
+
+func (s *orderSaga) Steps() []*saga.SagaStep {
+	charge := saga.NewSagaStep("charge", commandOf("ChargePayment")).
+		WithCompensation(commandOf("RefundPayment"))
+	if s.retry != nil {
+		charge = charge.WithRetryPolicy(*s.retry)
+	}
+	return []*saga.SagaStep{
+		saga.NewSagaStep("reserve", commandOf("ReserveStock")).WithCompensation(commandOf("ReleaseStock")),
+		charge,
+		saga.NewSagaStep("ship", commandOf("ShipOrder")),
+	}
+}
+
+func commandOf(commandType string) saga.CommandFunc {
+	return func(context.Context) (*command.Command, error) {
+		return command.NewCommand("cmd-"+commandType, commandType, "order-1", "Order", nil), nil
+	}
+}
+
+func TestHarness_SuccessPath(t *testing.T) {
+	h := New(t)
+	h.Execute(&orderSaga{id: "order-1"}).
+		ExpectSuccess().
+		ExpectCommands("ReserveStock", "ChargePayment", "ShipOrder").
+		ExpectEvents(saga.EventSagaStarted,
+			saga.EventSagaStepCompleted, saga.EventSagaStepCompleted, saga.EventSagaStepCompleted,
+			saga.EventSagaCompleted).
+		ExpectStatus(saga.SagaStatusCompleted)
+
+	if got := h.States.Statuses("order-1"); got[0] != saga.SagaStatusRunning || got[len(got)-1] != saga.SagaStatusCompleted {
+		t.Fatalf("expected running -> completed status history, got %v", got)
+	}
+}
+
+func TestHarness_FailureIsCompensated(t *testing.T) {
+	h := New(t)
+	h.Commands.Fail("ShipOrder", errors.NewCode(errors.Dependency, "carrier down"))
+
+	h.Execute(&orderSaga{id: "order-1"}).
+		ExpectError(errors.Internal).
+		ExpectCommands("ReserveStock", "ChargePayment", "ShipOrder", "RefundPayment", "ReleaseStock").
+		ExpectEventsInclude(saga.EventSagaStepFailed, saga.EventSagaCompensationStarted, saga.EventSagaCompensationCompleted).
+		ExpectStatus(saga.SagaStatusCompensated).
+		ExpectState(func(t testing.TB, state *saga.SagaState) {
+			if state.FailedStep != "ship" {
+				t.Errorf("expected failed step ship, got %q", state.FailedStep)
+			}
+		})
+}
+
+func TestHarness_RetryDoesNotWaitOnRealTime(t *testing.T) {
+	h := New(t)
+	h.Commands.FailTimes("ChargePayment", 2, errors.NewCode(errors.ServiceUnavailable, "gateway busy"))
+	policy := saga.RetryPolicy{MaxAttempts: 3, Backoff: saga.Backoff{Initial: time.Hour}}
+	start := h.Clock.Now()
+
+	h.Execute(&orderSaga{id: "order-1", retry: &policy}).
+		ExpectSuccess().
+		ExpectCommands("ReserveStock", "ChargePayment", "ChargePayment", "ChargePayment", "ShipOrder").
+		ExpectEventsInclude(saga.EventSagaStepRetrying, saga.EventSagaStepRetrying, saga.EventSagaCompleted)
+
+	if h.Commands.Calls("ChargePayment") != 3 {
+		t.Fatalf("expected 3 charge attempts, got %d", h.Commands.Calls("ChargePayment"))
+	}
+	if elapsed := h.Clock.Now().Sub(start); elapsed < 2*time.Hour {
+		t.Fatalf("expected manual clock to advance through backoff, advanced %v", elapsed)
+	}
+}
+
+func TestHarness_ResumeFromStoredState(t *testing.T) {
+	h := New(t)
+	state := saga.NewSagaState("order-1", saga.TypeName(&orderSaga{}))
+	state.MarkStepCompleted("reserve")
+	if err := h.States.Save(context.Background(), state); err != nil {
+		t.Fatalf("save state: %v", err)
+	}
+
+	h.Resume(&orderSaga{id: "order-1"}).
+		ExpectSuccess().
+		ExpectCommands("ChargePayment", "ShipOrder").
+		ExpectStatus(saga.SagaStatusCompleted)
+}
+
+// recordingTB 记录断言失败而不终止测试，用于验证断言的失败报告。
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestResult_ReportsMismatches(t *testing.T) {
+	rec := &recordingTB{TB: t}
+	h := New(rec)
+
+	h.Execute(&orderSaga{id: "order-1"}).
+		ExpectError(nil).
+		ExpectCommands("ReserveStock").
+		ExpectEventsInclude(saga.EventSagaCompleted, saga.EventSagaStarted).
+		ExpectStatus(saga.SagaStatusFailed)
+
+	if len(rec.failures) != 4 {
+		t.Fatalf("expected 4 reported failures, got %d: %v", len(rec.failures), rec.failures)
+	}
+}
@@ -0,0 +1,262 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gochen/errors"
+)
+
+// RuleContext 是一次规则检查的输入。
+type RuleContext struct {
+	// Value 字段值（已解引用；nil 指针为无效 Value，规则应视为“未填写”）。
+	Value reflect.Value
+	// Param 规则参数（如 min=1 中的 "1"）。
+	Param string
+	// Struct 字段所在的结构体；dive 元素规则中为元素所属切片字段所在的结构体。
+	Struct reflect.Value
+}
+
+// Field 按 Go 字段名返回同一结构体中的另一个字段（已解引用），用于跨字段规则。
+func (c RuleContext) Field(name string) (reflect.Value, bool) {
+	if !c.Struct.IsValid() || c.Struct.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	if _, ok := c.Struct.Type().FieldByName(name); !ok {
+		return reflect.Value{}, false
+	}
+	return indirect(c.Struct.FieldByName(name)), true
+}
+
+// RuleFunc 检查一条规则：返回非空 message 表示校验失败（写入 FieldError.Message）；
+// 返回 err 表示规则用法错误（如参数格式不对），Struct 会以 errors.Internal 返回。
+type RuleFunc func(ctx RuleContext) (message string, err error)
+
+var builtinRules = map[string]RuleFunc{
+	"required":         ruleRequired,
+	"min":              boundRule("min"),
+	"max":              boundRule("max"),
+	"len":              boundRule("len"),
+	"oneof":            ruleOneOf,
+	"email":            ruleEmail,
+	"eqfield":          compareFieldRule("eqfield"),
+	"nefield":          compareFieldRule("nefield"),
+	"gtfield":          compareFieldRule("gtfield"),
+	"gtefield":         compareFieldRule("gtefield"),
+	"ltfield":          compareFieldRule("ltfield"),
+	"ltefield":         compareFieldRule("ltefield"),
+	"required_with":    ruleRequiredWith,
+	"required_without": ruleRequiredWithout,
+	"startswith":       stringRule("startswith"),
+	"notcontains":      stringRule("notcontains"),
+	"nospace":          stringRule("nospace"),
+}
+
+// reservedRules 是由引擎直接处理、不能注册的规则名。
+var reservedRules = map[string]bool{"omitempty": true, "dive": true}
+
+func ruleRequired(ctx RuleContext) (string, error) {
+	if isZero(ctx.Value) {
+		return "is required", nil
+	}
+	return "", nil
+}
+
+func boundRule(name string) RuleFunc {
+	return func(ctx RuleContext) (string, error) {
+		limit, err := strconv.ParseFloat(ctx.Param, 64)
+		if err != nil {
+			return "", fmt.Errorf("rule %s requires a numeric parameter, got %q", name, ctx.Param)
+		}
+		if !ctx.Value.IsValid() {
+			return "", nil
+		}
+		actual, isLength, ok := measure(ctx.Value)
+		if !ok {
+			return "", fmt.Errorf("rule %s does not support %s fields", name, ctx.Value.Kind())
+		}
+		subject := "value"
+		if isLength {
+			subject = "length"
+		}
+		switch {
+		case name == "min" && actual < limit:
+			return fmt.Sprintf("%s must be at least %s", subject, ctx.Param), nil
+		case name == "max" && actual > limit:
+			return fmt.Sprintf("%s must be at most %s", subject, ctx.Param), nil
+		case name == "len" && actual != limit:
+			return fmt.Sprintf("length must be %s", ctx.Param), nil
+		}
+		return "", nil
+	}
+}
+
+func ruleOneOf(ctx RuleContext) (string, error) {
+	if !ctx.Value.IsValid() {
+		return "", nil
+	}
+	options := strings.Fields(ctx.Param)
+	actual := fmt.Sprint(ctx.Value.Interface())
+	for _, option := range options {
+		if actual == option {
+			return "", nil
+		}
+	}
+	return fmt.Sprintf("must be one of %v", options), nil
+}
+
+func ruleEmail(ctx RuleContext) (string, error) {
+	if !ctx.Value.IsValid() {
+		return "", nil
+	}
+	if ctx.Value.Kind() != reflect.String {
+		return "", fmt.Errorf("rule email only supports string fields")
+	}
+	if !emailRegex.MatchString(ctx.Value.String()) {
+		return "must be a valid email address", nil
+	}
+	return "", nil
+}
+
+// stringRule 构造只作用于字符串字段的规则：startswith、notcontains 与 nospace。
+func stringRule(name string) RuleFunc {
+	return func(ctx RuleContext) (string, error) {
+		if !ctx.Value.IsValid() {
+			return "", nil
+		}
+		if ctx.Value.Kind() != reflect.String {
+			return "", fmt.Errorf("rule %s only supports string fields", name)
+		}
+		value := ctx.Value.String()
+		switch {
+		case name == "startswith" && !strings.HasPrefix(value, ctx.Param):
+			return fmt.Sprintf("must start with %q", ctx.Param), nil
+		case name == "notcontains" && strings.Contains(value, ctx.Param):
+			return fmt.Sprintf("must not contain %q", ctx.Param), nil
+		case name == "nospace" && strings.Contains(value, " "):
+			return "must not contain spaces", nil
+		}
+		return "", nil
+	}
+}
+
+// compareFieldRule 比较当前字段与同结构体的另一字段：数值按大小、time.Time 按先后、字符串按字典序。
+func compareFieldRule(name string) RuleFunc {
+	return func(ctx RuleContext) (string, error) {
+		other, ok := ctx.Field(ctx.Param)
+		if !ok {
+			return "", fmt.Errorf("rule %s references unknown field %q", name, ctx.Param)
+		}
+		if !ctx.Value.IsValid() || !other.IsValid() {
+			return "", nil
+		}
+		cmp, ok := compareValues(ctx.Value, other)
+		if !ok {
+			return "", fmt.Errorf("rule %s cannot compare %s with %s", name, ctx.Value.Type(), other.Type())
+		}
+		var pass bool
+		var relation string
+		switch name {
+		case "eqfield":
+			pass, relation = cmp == 0, "equal"
+		case "nefield":
+			pass, relation = cmp != 0, "not equal"
+		case "gtfield":
+			pass, relation = cmp > 0, "be greater than"
+		case "gtefield":
+			pass, relation = cmp >= 0, "be greater than or equal to"
+		case "ltfield":
+			pass, relation = cmp < 0, "be less than"
+		case "ltefield":
+			pass, relation = cmp <= 0, "be less than or equal to"
+		}
+		if pass {
+			return "", nil
+		}
+		return fmt.Sprintf("must %s %s", relation, ctx.Param), nil
+	}
+}
+
+func ruleRequiredWith(ctx RuleContext) (string, error) {
+	other, ok := ctx.Field(ctx.Param)
+	if !ok {
+		return "", fmt.Errorf("rule required_with references unknown field %q", ctx.Param)
+	}
+	if !isZero(other) && isZero(ctx.Value) {
+		return fmt.Sprintf("is required when %s is present", ctx.Param), nil
+	}
+	return "", nil
+}
+
+func ruleRequiredWithout(ctx RuleContext) (string, error) {
+	other, ok := ctx.Field(ctx.Param)
+	if !ok {
+		return "", fmt.Errorf("rule required_without references unknown field %q", ctx.Param)
+	}
+	if isZero(other) && isZero(ctx.Value) {
+		return fmt.Sprintf("is required when %s is absent", ctx.Param), nil
+	}
+	return "", nil
+}
+
+// compareValues 比较两个同类值，返回 -1/0/1。
+func compareValues(a, b reflect.Value) (int, bool) {
+	timeType := reflect.TypeFor[time.Time]()
+	if a.Type() == timeType && b.Type() == timeType {
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time)), true
+	}
+	if a.Kind() == reflect.String && b.Kind() == reflect.String {
+		return strings.Compare(a.String(), b.String()), true
+	}
+	x, xLen, okA := measure(a)
+	y, yLen, okB := measure(b)
+	if !okA || !okB || xLen || yLen {
+		return 0, false
+	}
+	switch {
+	case x < y:
+		return -1, true
+	case x > y:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+// measure 返回数值本身，或字符串/切片/映射的长度（isLength=true）。
+func measure(value reflect.Value) (actual float64, isLength bool, ok bool) {
+	switch value.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), true, true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), true, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), false, true
+	default:
+		return 0, false, false
+	}
+}
+
+func validateRuleRegistration(name string, fn RuleFunc) error {
+	if strings.TrimSpace(name) == "" || strings.ContainsAny(name, ",= ") {
+		return errors.NewCode(errors.InvalidInput, "invalid validate rule name").WithContext("rule", name)
+	}
+	if fn == nil {
+		return errors.NewCode(errors.InvalidInput, "validate rule func cannot be nil").WithContext("rule", name)
+	}
+	if reservedRules[name] {
+		return errors.NewCode(errors.InvalidInput, "validate rule name is reserved").WithContext("rule", name)
+	}
+	if _, exists := builtinRules[name]; exists {
+		return errors.NewCode(errors.Conflict, "validate rule already registered").WithContext("rule", name)
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"gochen/errors"
+)
+
+// FieldError 描述单个字段的校验失败。
+type FieldError struct {
+	// Field 字段路径（优先取 json tag 名，嵌套以 "." 连接，切片元素为 "items[0]"）。
+	Field string `json:"field"`
+	// Rule 失败的规则名（如 required、min）。
+	Rule string `json:"rule"`
+	// Param 规则参数（如 min=1 中的 "1"）。
+	Param string `json:"param,omitempty"`
+	// Message 可读说明。
+	Message string `json:"message"`
+}
+
+// FieldErrors 是一次结构体校验的全部字段错误。
+type FieldErrors []FieldError
+
+// Error 实现 error 接口。
+func (e FieldErrors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, fe := range e {
+		parts = append(parts, fe.Field+": "+fe.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// FieldErrorsOf 从错误链中提取字段错误。
+func FieldErrorsOf(err error) (FieldErrors, bool) {
+	var fields FieldErrors
+	if errors.As(err, &fields) {
+		return fields, true
+	}
+	return nil, false
+}
+
+// TagValidator 按 `validate` struct tag 校验结构体，实现 IValidator；零值可直接使用内置规则。
+//
+// 内置规则（以逗号分隔）：
+//   - required：非零值（字符串去除首尾空白后非空）
+//   - omitempty：零值时跳过其余规则
+//   - min=n / max=n：数值比较大小；字符串按字符数、切片/映射按长度比较
+//   - len=n：字符串字符数或切片/映射长度等于 n
+//   - oneof=a b c：取值属于给定集合
+//   - email：邮箱格式
+//   - eqfield/nefield/gtfield/gtefield/ltfield/ltefield=Field：与同结构体的另一字段（Go 字段名）比较，
+//     支持数值、字符串与 time.Time
+//   - required_with=Field / required_without=Field：另一字段非零/为零时本字段必填
+//   - startswith=prefix / notcontains=value / nospace：字符串前缀、禁止子串与禁止空格
+//   - dive：其后的规则作用于切片/数组的每个元素（如 `validate:"max=3,dive,required"`）
+//
+// 嵌套结构体与结构体切片会递归校验。失败时返回 errors.Validation，错误链中包含 FieldErrors，
+// 并以 "fields" 上下文输出；tag 写法错误（未知规则、参数不是数字）返回 errors.Internal。
+type TagValidator struct {
+	// FieldName 自定义字段路径段的命名（如按 yaml tag）；返回 "-" 跳过该字段，返回 "" 表示展开到父级。
+	// 为 nil 时优先取 json tag 名，缺省为 Go 字段名。
+	FieldName func(field reflect.StructField) string
+	// IgnoreUnknownRules 为 true 时跳过未注册的规则，而不是返回 errors.Internal。
+	IgnoreUnknownRules bool
+
+	rules map[string]RuleFunc
+}
+
+var _ IValidator = TagValidator{}
+
+// NewTagValidator 创建可注册自定义规则的 struct tag 校验器。
+func NewTagValidator() *TagValidator {
+	return &TagValidator{rules: make(map[string]RuleFunc)}
+}
+
+// RegisterRule 注册自定义规则；名称不能与内置规则或 omitempty/dive 重复。
+//
+// 应在启动阶段完成注册，Validate 期间不可并发注册。
+func (v *TagValidator) RegisterRule(name string, fn RuleFunc) error {
+	if err := validateRuleRegistration(name, fn); err != nil {
+		return err
+	}
+	if _, exists := v.rules[name]; exists {
+		return errors.NewCode(errors.Conflict, "validate rule already registered").WithContext("rule", name)
+	}
+	if v.rules == nil {
+		v.rules = make(map[string]RuleFunc)
+	}
+	v.rules[name] = fn
+	return nil
+}
+
+// Validate 校验 value；非结构体（或其指针）直接通过。
+func (v TagValidator) Validate(value any) error {
+	var fields FieldErrors
+	if err := v.validateValue(reflect.ValueOf(value), "", &fields); err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return errors.NewCodeWithCause(errors.Validation, "validation failed", fields).
+		WithContext("fields", []FieldError(fields))
+}
+
+// Struct 使用内置规则按 `validate` tag 校验结构体，语义见 TagValidator。
+func Struct(value any) error {
+	return TagValidator{}.Validate(value)
+}
+
+type fieldRule struct {
+	name  string
+	param string
+}
+
+type structField struct {
+	index     int
+	name      string
+	embedded  bool
+	omitEmpty bool
+	rules     []fieldRule
+	// elemRules 为 dive 之后作用于每个元素的规则。
+	elemRules     []fieldRule
+	elemOmitEmpty bool
+}
+
+var structCache sync.Map // reflect.Type -> []structField
+
+func (v TagValidator) validateValue(value reflect.Value, path string, fields *FieldErrors) error {
+	value = indirect(value)
+	switch value.Kind() {
+	case reflect.Struct:
+		return v.validateStruct(value, path, fields)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := v.validateValue(value.Index(i), fmt.Sprintf("%s[%d]", path, i), fields); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v TagValidator) validateStruct(value reflect.Value, path string, fields *FieldErrors) error {
+	if value.Type() == reflect.TypeFor[time.Time]() {
+		return nil
+	}
+	for _, sf := range structFields(value.Type()) {
+		name, embedded := sf.name, sf.embedded
+		if v.FieldName != nil {
+			name = v.FieldName(value.Type().Field(sf.index))
+			embedded = name == ""
+		}
+		if name == "-" {
+			continue
+		}
+		fieldValue := value.Field(sf.index)
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+		if embedded {
+			fieldPath = path
+		}
+
+		if err := v.checkRules(fieldValue, value, sf.rules, sf.omitEmpty, fieldPath, fields); err != nil {
+			return err
+		}
+		if len(sf.elemRules) > 0 {
+			elems := indirect(fieldValue)
+			if elems.Kind() != reflect.Slice && elems.Kind() != reflect.Array {
+				if elems.IsValid() {
+					return errors.NewCode(errors.Internal, "invalid validate tag").
+						WithContext("field", fieldPath).
+						WithContext("rule", "dive")
+				}
+			} else {
+				for i := 0; i < elems.Len(); i++ {
+					elemPath := fmt.Sprintf("%s[%d]", fieldPath, i)
+					if err := v.checkRules(elems.Index(i), value, sf.elemRules, sf.elemOmitEmpty, elemPath, fields); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if err := v.validateValue(fieldValue, fieldPath, fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRules 依次执行规则；同一字段只报告第一条失败规则。
+func (v TagValidator) checkRules(value, parent reflect.Value, rules []fieldRule, omitEmpty bool, path string, fields *FieldErrors) error {
+	if omitEmpty && isZero(value) {
+		return nil
+	}
+	for _, rule := range rules {
+		fn, ok := v.rule(rule.name)
+		if !ok && v.IgnoreUnknownRules {
+			continue
+		}
+		if !ok {
+			return errors.NewCode(errors.Internal, "invalid validate tag: unsupported rule").
+				WithContext("field", path).
+				WithContext("rule", rule.name)
+		}
+		message, err := fn(RuleContext{Value: indirect(value), Param: rule.param, Struct: parent})
+		if err != nil {
+			return errors.Wrap(err, errors.Internal, "invalid validate tag").
+				WithContext("field", path).
+				WithContext("rule", rule.name)
+		}
+		if message != "" {
+			*fields = append(*fields, FieldError{Field: path, Rule: rule.name, Param: rule.param, Message: message})
+			return nil
+		}
+	}
+	return nil
+}
+
+func (v TagValidator) rule(name string) (RuleFunc, bool) {
+	if fn, ok := builtinRules[name]; ok {
+		return fn, true
+	}
+	fn, ok := v.rules[name]
+	return fn, ok
+}
+
+func structFields(t reflect.Type) []structField {
+	if cached, ok := structCache.Load(t); ok {
+		return cached.([]structField)
+	}
+	result := make([]structField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		sf := structField{index: i, name: jsonFieldName(f), embedded: f.Anonymous && f.Tag.Get("json") == ""}
+		dive := false
+		for _, raw := range strings.Split(f.Tag.Get("validate"), ",") {
+			raw = strings.TrimSpace(raw)
+			switch {
+			case raw == "":
+			case raw == "dive":
+				dive = true
+			case raw == "omitempty" && dive:
+				sf.elemOmitEmpty = true
+			case raw == "omitempty":
+				sf.omitEmpty = true
+			default:
+				name, param, _ := strings.Cut(raw, "=")
+				if dive {
+					sf.elemRules = append(sf.elemRules, fieldRule{name: name, param: param})
+				} else {
+					sf.rules = append(sf.rules, fieldRule{name: name, param: param})
+				}
+			}
+		}
+		result = append(result, sf)
+	}
+	structCache.Store(t, result)
+	return result
+}
+
+func jsonFieldName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+func indirect(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+func isZero(value reflect.Value) bool {
+	value = indirect(value)
+	if !value.IsValid() {
+		return true
+	}
+	if value.Kind() == reflect.String {
+		return strings.TrimSpace(value.String()) == ""
+	}
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Map {
+		return value.Len() == 0
+	}
+	return value.IsZero()
+}
@@ -0,0 +1,219 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gochen/errors"
+)
+
+type lineItem struct {
+	SKU string `json:"sku" validate:"required"`
+	Qty int    `json:"qty" validate:"min=1,max=99"`
+}
+
+type placeOrder struct {
+	CustomerEmail string     `json:"customer_email" validate:"required,email"`
+	Channel       string     `json:"channel" validate:"omitempty,oneof=web app"`
+	Note          *string    `json:"note" validate:"omitempty,max=5"`
+	Items         []lineItem `json:"items" validate:"min=1"`
+	Tags          []string   `validate:"max=2"`
+}
+
+// TestStruct_Valid 验证合法结构体通过校验。
+func TestStruct_Valid(t *testing.T) {
+	cmd := &placeOrder{
+		CustomerEmail: "a@example.com",
+		Items:         []lineItem{{SKU: "A-1", Qty: 2}},
+	}
+	if err := Struct(cmd); err != nil {
+		t.Fatalf("期望校验通过，实际: %v", err)
+	}
+	if err := (TagValidator{}).Validate(map[string]any{"x": 1}); err != nil {
+		t.Fatalf("非结构体应直接通过，实际: %v", err)
+	}
+}
+
+// TestStruct_FieldErrors 验证逐字段错误的路径、规则与错误码。
+func TestStruct_FieldErrors(t *testing.T) {
+	note := "too long note"
+	cmd := placeOrder{
+		CustomerEmail: "not-an-email",
+		Channel:       "fax",
+		Note:          &note,
+		Items:         []lineItem{{SKU: " ", Qty: 1}, {SKU: "B", Qty: 100}},
+		Tags:          []string{"a", "b", "c"},
+	}
+	err := Struct(cmd)
+	if !errors.Is(err, errors.Validation) {
+		t.Fatalf("期望 VALIDATION_ERROR，实际: %v", err)
+	}
+	fields, ok := FieldErrorsOf(err)
+	if !ok {
+		t.Fatal("期望错误链中包含 FieldErrors")
+	}
+
+	want := []FieldError{
+		{Field: "customer_email", Rule: "email"},
+		{Field: "channel", Rule: "oneof", Param: "web app"},
+		{Field: "note", Rule: "max", Param: "5"},
+		{Field: "items[0].sku", Rule: "required"},
+		{Field: "items[1].qty", Rule: "max", Param: "99"},
+		{Field: "Tags", Rule: "max", Param: "2"},
+	}
+	if len(fields) != len(want) {
+		t.Fatalf("期望 %d 个字段错误，实际 %d: %v", len(want), len(fields), fields)
+	}
+	for i, w := range want {
+		if fields[i].Field != w.Field || fields[i].Rule != w.Rule || fields[i].Param != w.Param {
+			t.Errorf("字段错误 %d = %+v，期望 %+v", i, fields[i], w)
+		}
+	}
+
+	var appErr *errors.AppError
+	if !errors.As(err, &appErr) || appErr.Details()["fields"] == nil {
+		t.Error("期望以 fields 上下文输出字段错误")
+	}
+}
+
+// TestStruct_InvalidTag 验证 tag 写法错误返回 INTERNAL_ERROR。
+func TestStruct_InvalidTag(t *testing.T) {
+	type badTag struct {
+		Name string `validate:"uppercase"`
+	}
+	if err := Struct(badTag{Name: "x"}); !errors.Is(err, errors.Internal) {
+		t.Fatalf("期望 INTERNAL_ERROR，实际: %v", err)
+	}
+
+	type badParam struct {
+		Age int `validate:"min=abc"`
+	}
+	if err := Struct(badParam{Age: 1}); !errors.Is(err, errors.Internal) {
+		t.Fatalf("期望 INTERNAL_ERROR，实际: %v", err)
+	}
+}
+
+type address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type booking struct {
+	Start    time.Time `json:"start" validate:"required"`
+	End      time.Time `json:"end" validate:"required,gtfield=Start"`
+	Password string    `json:"password" validate:"min=6"`
+	Confirm  string    `json:"confirm" validate:"eqfield=Password"`
+	Phone    string    `json:"phone" validate:"required_without=Email"`
+	Email    string    `json:"email" validate:"omitempty,email"`
+	Guests   []string  `json:"guests" validate:"max=3,dive,required,max=4"`
+	Address  *address  `json:"address"`
+}
+
+// TestStruct_CrossFieldAndDive 验证跨字段规则、dive 元素规则与嵌套指针结构体。
+func TestStruct_CrossFieldAndDive(t *testing.T) {
+	start := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	valid := booking{
+		Start:    start,
+		End:      start.Add(time.Hour),
+		Password: "secret",
+		Confirm:  "secret",
+		Phone:    "123",
+		Guests:   []string{"ann", "bob"},
+		Address:  &address{City: "Hangzhou"},
+	}
+	if err := Struct(valid); err != nil {
+		t.Fatalf("期望校验通过，实际: %v", err)
+	}
+
+	invalid := valid
+	invalid.End = start
+	invalid.Confirm = "other"
+	invalid.Phone = ""
+	invalid.Guests = []string{"ann", " ", "robert"}
+	invalid.Address = &address{}
+	fields, ok := FieldErrorsOf(Struct(invalid))
+	if !ok {
+		t.Fatal("期望返回 FieldErrors")
+	}
+	want := []string{"end:gtfield", "confirm:eqfield", "phone:required_without", "guests[1]:required", "guests[2]:max", "address.city:required"}
+	got := make([]string, 0, len(fields))
+	for _, fe := range fields {
+		got = append(got, fe.Field+":"+fe.Rule)
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("字段错误不符\n 期望: %v\n 实际: %v", want, got)
+	}
+}
+
+// TestTagValidator_RegisterRule 验证自定义规则注册与名称冲突检查。
+func TestTagValidator_RegisterRule(t *testing.T) {
+	v := NewTagValidator()
+	err := v.RegisterRule("sku", func(ctx RuleContext) (string, error) {
+		if ctx.Value.Kind() != reflect.String {
+			return "", fmt.Errorf("sku only supports strings")
+		}
+		if !strings.HasPrefix(ctx.Value.String(), ctx.Param) {
+			return "must start with " + ctx.Param, nil
+		}
+		return "", nil
+	})
+	if err != nil {
+		t.Fatalf("注册规则失败: %v", err)
+	}
+	if err := v.RegisterRule("sku", func(RuleContext) (string, error) { return "", nil }); !errors.Is(err, errors.Conflict) {
+		t.Fatalf("重复注册应返回 CONFLICT，实际: %v", err)
+	}
+	if err := v.RegisterRule("min", func(RuleContext) (string, error) { return "", nil }); !errors.Is(err, errors.Conflict) {
+		t.Fatalf("覆盖内置规则应返回 CONFLICT，实际: %v", err)
+	}
+	if err := v.RegisterRule("dive", func(RuleContext) (string, error) { return "", nil }); !errors.Is(err, errors.InvalidInput) {
+		t.Fatalf("保留规则名应返回 INVALID_INPUT，实际: %v", err)
+	}
+
+	type product struct {
+		SKU string `json:"sku" validate:"required,sku=P-"`
+	}
+	if err := v.Validate(&product{SKU: "P-1"}); err != nil {
+		t.Fatalf("期望校验通过，实际: %v", err)
+	}
+	fields, ok := FieldErrorsOf(v.Validate(&product{SKU: "X-1"}))
+	if !ok || len(fields) != 1 || fields[0].Rule != "sku" || fields[0].Message != "must start with P-" {
+		t.Fatalf("自定义规则错误不符: %v", fields)
+	}
+
+	// 未注册该规则的校验器视为 tag 写法错误。
+	if err := Struct(&product{SKU: "P-1"}); !errors.Is(err, errors.Internal) {
+		t.Fatalf("期望 INTERNAL_ERROR，实际: %v", err)
+	}
+}
+
+// TestTagValidator_FieldNameAndUnknownRules 验证自定义字段命名、字符串规则与忽略未知规则。
+func TestTagValidator_FieldNameAndUnknownRules(t *testing.T) {
+	type server struct {
+		BasePath string `yaml:"base_path" validate:"required,startswith=/,nospace"`
+		Secret   string `yaml:"-" validate:"required"`
+		Region   string `yaml:"region" validate:"notcontains=_,custom"`
+	}
+	v := TagValidator{
+		FieldName: func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			return name
+		},
+		IgnoreUnknownRules: true,
+	}
+	fields, ok := FieldErrorsOf(v.Validate(&server{BasePath: "api v1", Region: "cn_north"}))
+	if !ok || len(fields) != 2 {
+		t.Fatalf("期望 2 个字段错误，实际: %v", fields)
+	}
+	if fields[0].Field != "base_path" || fields[0].Rule != "startswith" {
+		t.Fatalf("base_path 错误不符: %+v", fields[0])
+	}
+	if fields[1].Field != "region" || fields[1].Rule != "notcontains" {
+		t.Fatalf("region 错误不符: %+v", fields[1])
+	}
+	if err := v.Validate(&server{BasePath: "/api", Region: "cn"}); err != nil {
+		t.Fatalf("期望校验通过，实际: %v", err)
+	}
+}